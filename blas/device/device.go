@@ -0,0 +1,162 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// Buffer is a region of device memory holding float64 values.
+type Buffer interface {
+	// Len returns the number of values
+	// held by the buffer.
+	Len() int
+}
+
+// Backend is a BLAS implementation operating on device memory. Matrices held
+// in buffers are stored in row-major order with the given leading dimension,
+// as for the routines of blas.Float64.
+//
+// Methods return a non-nil error if the device operation fails. Operations
+// must have completed when a method returns.
+type Backend interface {
+	// Name returns the name of the backend.
+	Name() string
+
+	// Alloc allocates a buffer of n values on the device.
+	Alloc(n int) (Buffer, error)
+	// Free releases the device memory held by b.
+	Free(b Buffer) error
+
+	// ToDevice copies the values of src into the
+	// start of dst. ToHost copies the first len(dst)
+	// values of src into dst.
+	ToDevice(dst Buffer, src []float64) error
+	ToHost(dst []float64, src Buffer) error
+
+	// Dgemm performs one of the matrix-matrix operations
+	//  C = alpha * A * B + beta * C
+	//  C = alpha * Aᵀ * B + beta * C
+	//  C = alpha * A * Bᵀ + beta * C
+	//  C = alpha * Aᵀ * Bᵀ + beta * C
+	// where A is an m×k or k×m matrix, B is a k×n or n×k matrix, C is
+	// an m×n matrix, and alpha and beta are scalars. The operands are
+	// held in device buffers.
+	Dgemm(tA, tB blas.Transpose, m, n, k int, alpha float64, a Buffer, lda int, b Buffer, ldb int, beta float64, c Buffer, ldc int) error
+}
+
+// backend holds the backendValue in use.
+var backend atomic.Value
+
+// backendValue wraps a Backend so that a nil
+// Backend may be held by an atomic.Value.
+type backendValue struct {
+	b Backend
+}
+
+// Use sets the device backend to be used by operations that offload work to
+// a device. If b is nil, no work is offloaded. There is no backend in use by
+// default. Use may be called concurrently with operations that offload work,
+// which use either the previous or the new backend.
+func Use(b Backend) {
+	backend.Store(backendValue{b})
+}
+
+// Implementation returns the device backend in use, or nil if there is none.
+func Implementation() Backend {
+	v, _ := backend.Load().(backendValue)
+	return v.b
+}
+
+// Gemm computes
+//
+//	C = alpha * A * B + beta * C
+//	C = alpha * Aᵀ * B + beta * C
+//	C = alpha * A * Bᵀ + beta * C
+//	C = alpha * Aᵀ * Bᵀ + beta * C,
+//
+// where A, B and C are host matrices and alpha and beta are scalars, using
+// the device backend b. The operands are copied to device memory and the
+// result is copied back into c. The elements of the host matrices' backing
+// data outside the matrices are not read or written. If an error is
+// returned, c is not modified.
+//
+// Gemm panics if the operand dimensions do not agree.
+func Gemm(b Backend, tA, tB blas.Transpose, alpha float64, a, bm blas64.General, beta float64, c blas64.General) error {
+	m, k := a.Rows, a.Cols
+	if tA != blas.NoTrans {
+		m, k = k, m
+	}
+	kb, n := bm.Rows, bm.Cols
+	if tB != blas.NoTrans {
+		kb, n = n, kb
+	}
+	if m != c.Rows || n != c.Cols || k != kb {
+		panic("device: dimension mismatch")
+	}
+
+	// Each operand is held packed on the device.
+	var bufs []Buffer
+	defer func() {
+		for _, buf := range bufs {
+			b.Free(buf)
+		}
+	}()
+	upload := func(g blas64.General, copyData bool) (Buffer, error) {
+		buf, err := b.Alloc(g.Rows * g.Cols)
+		if err != nil {
+			return nil, err
+		}
+		bufs = append(bufs, buf)
+		if !copyData {
+			return buf, nil
+		}
+		return buf, b.ToDevice(buf, packed(g))
+	}
+	da, err := upload(a, true)
+	if err != nil {
+		return err
+	}
+	db, err := upload(bm, true)
+	if err != nil {
+		return err
+	}
+	dc, err := upload(c, beta != 0)
+	if err != nil {
+		return err
+	}
+	err = b.Dgemm(tA, tB, m, n, k, alpha, da, a.Cols, db, bm.Cols, beta, dc, c.Cols)
+	if err != nil {
+		return err
+	}
+
+	if c.Stride == c.Cols {
+		return b.ToHost(c.Data[:c.Rows*c.Cols], dc)
+	}
+	res := make([]float64, c.Rows*c.Cols)
+	err = b.ToHost(res, dc)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < c.Rows; i++ {
+		copy(c.Data[i*c.Stride:i*c.Stride+c.Cols], res[i*c.Cols:(i+1)*c.Cols])
+	}
+	return nil
+}
+
+// packed returns the elements of g in row-major order without padding.
+func packed(g blas64.General) []float64 {
+	if g.Stride == g.Cols {
+		return g.Data[:g.Rows*g.Cols]
+	}
+	p := make([]float64, g.Rows*g.Cols)
+	for i := 0; i < g.Rows; i++ {
+		copy(p[i*g.Cols:(i+1)*g.Cols], g.Data[i*g.Stride:i*g.Stride+g.Cols])
+	}
+	return p
+}
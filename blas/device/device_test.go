@@ -0,0 +1,113 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+)
+
+// randGeneral returns an r×c matrix with the given stride filled with
+// random values. The padding elements are set to pad.
+func randGeneral(r, c, stride int, pad float64, rnd *rand.Rand) blas64.General {
+	g := blas64.General{Rows: r, Cols: c, Stride: stride, Data: make([]float64, r*stride)}
+	for i := range g.Data {
+		if i%stride < c {
+			g.Data[i] = rnd.NormFloat64()
+		} else {
+			g.Data[i] = pad
+		}
+	}
+	return g
+}
+
+func cloneGeneral(g blas64.General) blas64.General {
+	g.Data = append([]float64(nil), g.Data...)
+	return g
+}
+
+func TestGemm(t *testing.T) {
+	const pad = 42
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+		pad     int
+	}{
+		{m: 1, n: 1, k: 1},
+		{m: 3, n: 4, k: 5},
+		{m: 7, n: 2, k: 6, pad: 3},
+		{m: 10, n: 10, k: 10, pad: 1},
+	} {
+		for _, tA := range []blas.Transpose{blas.NoTrans, blas.Trans} {
+			for _, tB := range []blas.Transpose{blas.NoTrans, blas.Trans} {
+				for _, beta := range []float64{0, 0.5} {
+					ar, ac := test.m, test.k
+					if tA != blas.NoTrans {
+						ar, ac = ac, ar
+					}
+					br, bc := test.k, test.n
+					if tB != blas.NoTrans {
+						br, bc = bc, br
+					}
+					a := randGeneral(ar, ac, ac+test.pad, pad, rnd)
+					b := randGeneral(br, bc, bc+test.pad, pad, rnd)
+					c := randGeneral(test.m, test.n, test.n+test.pad, pad, rnd)
+					want := cloneGeneral(c)
+					blas64.Gemm(tA, tB, 2, a, b, beta, want)
+
+					err := Gemm(Host{}, tA, tB, 2, a, b, beta, c)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					if !floats.EqualApprox(c.Data, want.Data, 1e-12) {
+						t.Errorf("unexpected result for m=%d n=%d k=%d tA=%c tB=%c beta=%v:\ngot: %v\nwant:%v",
+							test.m, test.n, test.k, tA, tB, beta, c.Data, want.Data)
+					}
+				}
+			}
+		}
+	}
+}
+
+// failing is a Backend whose Dgemm always fails.
+type failing struct{ Host }
+
+func (failing) Dgemm(tA, tB blas.Transpose, m, n, k int, alpha float64, a Buffer, lda int, b Buffer, ldb int, beta float64, c Buffer, ldc int) error {
+	return errors.New("device failure")
+}
+
+func TestGemmFailure(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	a := randGeneral(3, 3, 3, 0, rnd)
+	c := randGeneral(3, 3, 3, 0, rnd)
+	want := cloneGeneral(c)
+	err := Gemm(failing{}, blas.NoTrans, blas.NoTrans, 1, a, a, 0, c)
+	if err == nil {
+		t.Errorf("expected error from failing backend")
+	}
+	if !floats.Equal(c.Data, want.Data) {
+		t.Errorf("destination modified by failed operation")
+	}
+}
+
+func TestUse(t *testing.T) {
+	if Implementation() != nil {
+		t.Fatalf("unexpected default backend: %v", Implementation().Name())
+	}
+	Use(Host{})
+	if b := Implementation(); b == nil || b.Name() != "host" {
+		t.Errorf("backend not set")
+	}
+	Use(nil)
+	if Implementation() != nil {
+		t.Errorf("backend not cleared")
+	}
+}
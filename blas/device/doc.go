@@ -0,0 +1,19 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package device defines an interface to BLAS implementations that operate
+// on the memory of an accelerator device such as a GPU, and provides helpers
+// that transfer operands between host and device memory.
+//
+// Host and device memory are distinct. Values are allocated on the device
+// as a Buffer and are copied explicitly between host slices and buffers by
+// the ToDevice and ToHost methods of a Backend. Level 3 routines are then
+// performed on device buffers.
+//
+// No device backend is used by default, so Gonum remains pure Go. Gonum
+// does not provide bindings to device BLAS libraries. A Backend implemented
+// outside Gonum is selected by passing it to Use, after which packages such
+// as mat offload large operations to it. The Host backend implements
+// Backend in host memory and may be used to test code that uses a backend.
+package device // import "gonum.org/v1/gonum/blas/device"
@@ -0,0 +1,82 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package device
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/gonum"
+)
+
+var errForeignBuffer = errors.New("device: buffer not allocated by backend")
+
+// Host is a Backend that holds buffers in host memory and performs
+// operations with gonum.Implementation. It is intended for testing code
+// that uses a Backend without a device.
+type Host struct{}
+
+// hostBuffer is a Buffer held in host memory.
+type hostBuffer []float64
+
+// Len returns the number of values held by the buffer.
+func (b hostBuffer) Len() int { return len(b) }
+
+// Name returns "host".
+func (Host) Name() string { return "host" }
+
+// Alloc allocates a buffer of n values.
+func (Host) Alloc(n int) (Buffer, error) {
+	if n < 0 {
+		return nil, errors.New("device: negative buffer length")
+	}
+	return make(hostBuffer, n), nil
+}
+
+// Free releases b. Free is a no-op for buffers allocated by Host.
+func (Host) Free(b Buffer) error {
+	if _, ok := b.(hostBuffer); !ok {
+		return errForeignBuffer
+	}
+	return nil
+}
+
+// ToDevice copies the values of src into the start of dst.
+func (Host) ToDevice(dst Buffer, src []float64) error {
+	d, ok := dst.(hostBuffer)
+	if !ok {
+		return errForeignBuffer
+	}
+	if len(src) > len(d) {
+		return errors.New("device: source longer than buffer")
+	}
+	copy(d, src)
+	return nil
+}
+
+// ToHost copies the first len(dst) values of src into dst.
+func (Host) ToHost(dst []float64, src Buffer) error {
+	s, ok := src.(hostBuffer)
+	if !ok {
+		return errForeignBuffer
+	}
+	if len(dst) > len(s) {
+		return errors.New("device: destination longer than buffer")
+	}
+	copy(dst, s)
+	return nil
+}
+
+// Dgemm performs a general matrix multiplication on the buffers a, b and c.
+func (Host) Dgemm(tA, tB blas.Transpose, m, n, k int, alpha float64, a Buffer, lda int, b Buffer, ldb int, beta float64, c Buffer, ldc int) error {
+	da, okA := a.(hostBuffer)
+	db, okB := b.(hostBuffer)
+	dc, okC := c.(hostBuffer)
+	if !okA || !okB || !okC {
+		return errForeignBuffer
+	}
+	gonum.Implementation{}.Dgemm(tA, tB, m, n, k, alpha, da, lda, db, ldb, beta, dc, ldc)
+	return nil
+}
@@ -252,16 +252,8 @@ func dgemmSerial(aTrans, bTrans bool, m, n, k int, a []float64, lda int, b []flo
 
 // dgemmSerial where neither a nor b are transposed
 func dgemmSerialNotNot(m, n, k int, a []float64, lda int, b []float64, ldb int, c []float64, ldc int, alpha float64) {
-	// This style is used instead of the literal [i*stride +j]) is used because
-	// approximately 5 times faster as of go 1.3.
 	for i := 0; i < m; i++ {
-		ctmp := c[i*ldc : i*ldc+n]
-		for l, v := range a[i*lda : i*lda+k] {
-			tmp := alpha * v
-			if tmp != 0 {
-				f64.AxpyUnitary(tmp, b[l*ldb:l*ldb+n], ctmp)
-			}
-		}
+		f64.GemmRow(alpha, a[i*lda:i*lda+k], b, uintptr(ldb), c[i*ldc:i*ldc+n])
 	}
 }
 
@@ -478,9 +478,7 @@ func Mul(dst, s []float64) {
 	if len(dst) != len(s) {
 		panic("floats: slice lengths do not match")
 	}
-	for i, val := range s {
-		dst[i] *= val
-	}
+	f64.Mul(dst, s)
 }
 
 // MulTo performs element-wise multiplication between s
@@ -1,6 +1,6 @@
 module gonum.org/v1/gonum
 
-go 1.12
+go 1.18
 
 require (
 	golang.org/x/exp v0.0.0-20190125153040-c74c464bbbf2
@@ -8,3 +8,12 @@ require (
 	gonum.org/v1/netlib v0.0.0-20190313105609-8cb42192e0e0
 	gonum.org/v1/plot v0.0.0-20190515093506-e2840ee46a6b
 )
+
+require (
+	github.com/ajstarks/svgo v0.0.0-20180226025133-644b8db467af // indirect
+	github.com/fogleman/gg v1.2.1-0.20190220221249-0403632d5b90 // indirect
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	github.com/jung-kurt/gofpdf v1.0.3-0.20190309125859-24315acbbda5 // indirect
+	golang.org/x/image v0.0.0-20180708004352-c73c2afc3b81 // indirect
+	rsc.io/pdf v0.1.1 // indirect
+)
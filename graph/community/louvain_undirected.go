@@ -424,6 +424,26 @@ type undirectedLocalMover struct {
 	// in doi:10.1103/PhysRevE.74.016110.
 	resolution float64
 
+	// quality is the quality function
+	// optimized by the mover. If quality
+	// is nil, modularity at resolution
+	// is optimized.
+	quality Quality
+	// size is the number of original
+	// nodes held by each node indexed
+	// by ID, and n is their sum.
+	size []float64
+	n    float64
+	// internalWeight and internalPairs
+	// are the total edge weight and the
+	// number of node pairs within the
+	// communities.
+	internalWeight float64
+	internalPairs  float64
+	// best is the last move chosen by
+	// deltaQuality.
+	best Move
+
 	// moved indicates that a call to
 	// move has been made since the last
 	// call to shuffle.
@@ -517,6 +537,11 @@ func (l *undirectedLocalMover) move(dst int, src commIdx) {
 	n := srcComm[src.node]
 
 	l.memberships[n.ID()] = dst
+	if l.quality != nil {
+		m := l.best
+		l.internalWeight += m.To.Links - m.From.Links
+		l.internalPairs += m.Size * (m.To.Size - m.From.Size)
+	}
 
 	l.communities[dst] = append(l.communities[dst], n)
 	srcComm[src.node], srcComm[len(srcComm)-1] = srcComm[len(srcComm)-1], nil
@@ -529,6 +554,10 @@ func (l *undirectedLocalMover) move(dst int, src commIdx) {
 // undirectedLocalMover's communities field is returned in src if n
 // is in communities.
 func (l *undirectedLocalMover) deltaQ(n graph.Node) (deltaQ float64, dst int, src commIdx) {
+	if l.quality != nil {
+		return l.deltaQuality(n)
+	}
+
 	id := n.ID()
 	a_aa := l.weight(id, id)
 	k_a := l.edgeWeightOf[id]
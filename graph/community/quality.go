@@ -0,0 +1,348 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/set"
+)
+
+// Quality is a partition quality function that can be optimized by the
+// Louvain algorithm.
+type Quality interface {
+	// Score returns the quality of the division of
+	// the undirected graph g into the given communities.
+	Score(g graph.Undirected, communities [][]graph.Node) float64
+
+	// Delta returns the change in quality resulting
+	// from the move described by m.
+	Delta(m Move) float64
+}
+
+// Move describes the move of a node between two communities during
+// optimization of a Quality. All weights count each edge once, and
+// sizes are the number of nodes of the original graph held by a node
+// or community of the reduced graph being optimized.
+type Move struct {
+	// TotalWeight is the total edge
+	// weight of the graph and TotalSize
+	// is the total number of nodes in
+	// the graph.
+	TotalWeight, TotalSize float64
+
+	// InternalWeight is the sum of the
+	// weights of edges within communities
+	// and InternalPairs is the number of
+	// pairs of nodes within communities,
+	// before the move.
+	InternalWeight, InternalPairs float64
+
+	// Degree is the weighted degree of
+	// the moved node and Size is its size.
+	Degree, Size float64
+
+	// From and To describe the source and
+	// destination communities of the move,
+	// excluding the moved node.
+	From, To MoveCommunity
+}
+
+// MoveCommunity describes a community involved in a Move.
+type MoveCommunity struct {
+	// Links is the weight of the edges
+	// between the moved node and the
+	// community.
+	Links float64
+
+	// Degree is the sum of the weighted
+	// degrees of the community's nodes
+	// and Size is its size.
+	Degree, Size float64
+}
+
+// Modularity is the modularity quality function at the resolution γ as
+// defined in Reichardt and Bornholdt doi:10.1103/PhysRevE.74.016110.
+//
+//  Q = 1/2m \sum_{ij} [ A_{ij} - (\gamma k_i k_j)/2m ] \delta(c_i,c_j)
+type Modularity struct {
+	Resolution float64
+}
+
+// Score returns the modularity of the communities of g. Score will panic
+// if g has any edge with negative edge weight.
+func (q Modularity) Score(g graph.Undirected, communities [][]graph.Node) float64 {
+	return qUndirected(g, communities, q.Resolution)
+}
+
+// Delta returns the change in modularity due to the move m.
+func (q Modularity) Delta(m Move) float64 {
+	// See louvain.tex for a derivation of this equation.
+	m2 := 2 * m.TotalWeight
+	return 2 * ((m.To.Links - m.From.Links) - q.Resolution*m.Degree*(m.To.Degree-m.From.Degree)/m2) / m2
+}
+
+// CPM is the constant Potts model quality function at the resolution γ
+// as defined in Traag, Van Dooren and Nesterov doi:10.1103/PhysRevE.84.016114.
+// Unlike modularity, CPM is free of the resolution limit, so communities
+// much smaller than the square root of the graph's size can be resolved.
+//
+//  H = \sum_c [ e_c - \gamma n_c(n_c-1)/2 ]
+//
+// where e_c is the weight of edges within community c and n_c is the
+// number of nodes in c. Self edges are ignored.
+type CPM struct {
+	Resolution float64
+}
+
+// Score returns the constant Potts model quality of the communities of g.
+// Score will panic if g has any edge with negative edge weight.
+func (q CPM) Score(g graph.Undirected, communities [][]graph.Node) float64 {
+	weight := positiveWeightFuncFor(g)
+	var h float64
+	for _, c := range communities {
+		h += internalWeight(c, weight)
+		n := float64(len(c))
+		h -= q.Resolution * n * (n - 1) / 2
+	}
+	return h
+}
+
+// Delta returns the change in constant Potts model quality due to the
+// move m.
+func (q CPM) Delta(m Move) float64 {
+	return (m.To.Links - m.From.Links) - q.Resolution*m.Size*(m.To.Size-m.From.Size)
+}
+
+// Surprise is the asymptotic surprise quality function defined in Traag,
+// Aldecoa and Delvenne doi:10.1103/PhysRevE.92.022816.
+//
+//  S = m D(q \| \langle q \rangle)
+//
+// where m is the total edge weight, q is the fraction of edge weight within
+// communities, ⟨q⟩ is the fraction of node pairs within communities and D is
+// the binary Kullback-Leibler divergence. Surprise has no resolution parameter
+// and does not suffer from the resolution limit. Self edges are ignored.
+type Surprise struct{}
+
+// Score returns the asymptotic surprise of the communities of g. Score will
+// panic if g has any edge with negative edge weight.
+func (Surprise) Score(g graph.Undirected, communities [][]graph.Node) float64 {
+	weight := positiveWeightFuncFor(g)
+	nodes := graph.NodesOf(g.Nodes())
+	var m float64
+	for _, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid != uid {
+				m += weight(uid, vid)
+			}
+		}
+	}
+	m /= 2
+	var in, pairs float64
+	for _, c := range communities {
+		in += internalWeight(c, weight)
+		n := float64(len(c))
+		pairs += n * (n - 1) / 2
+	}
+	n := float64(len(nodes))
+	return surprise(m, in, n*(n-1)/2, pairs)
+}
+
+// Delta returns the change in asymptotic surprise due to the move m.
+func (Surprise) Delta(m Move) float64 {
+	n := m.TotalSize
+	total := n * (n - 1) / 2
+	s := m.Size
+	in := m.InternalWeight + m.To.Links - m.From.Links
+	pairs := m.InternalPairs + s*(m.To.Size-m.From.Size)
+	return surprise(m.TotalWeight, in, total, pairs) - surprise(m.TotalWeight, m.InternalWeight, total, m.InternalPairs)
+}
+
+// surprise returns the asymptotic surprise of a graph with total edge
+// weight m, internal edge weight in, total pairs of nodes and internal
+// pairs of nodes.
+func surprise(m, in, total, pairs float64) float64 {
+	if m == 0 || total == 0 {
+		return 0
+	}
+	q := in / m
+	p := pairs / total
+	var d float64
+	if q > 0 {
+		d += q * math.Log(q/p)
+	}
+	if q < 1 {
+		d += (1 - q) * math.Log((1-q)/(1-p))
+	}
+	return m * d
+}
+
+// internalWeight returns the sum of the weights of non-self edges between
+// the nodes of c.
+func internalWeight(c []graph.Node, weight func(xid, yid int64) float64) float64 {
+	var w float64
+	for i, u := range c {
+		uid := u.ID()
+		for _, v := range c[i+1:] {
+			w += weight(uid, v.ID())
+		}
+	}
+	return w
+}
+
+// Optimize returns the hierarchical division of g that optimizes the quality
+// function q using the Louvain algorithm. If src is nil, rand.Intn is used as
+// the random generator. Optimize will panic if g has any edge with negative
+// edge weight.
+//
+// The concrete type of the ReducedGraph will be a pointer to a
+// ReducedUndirected. Optimize with the Modularity quality function is
+// equivalent to Modularize for undirected graphs.
+//
+// graph.Undirect may be used as a shim to allow optimization of directed
+// graphs.
+func Optimize(g graph.Undirected, q Quality, src rand.Source) ReducedGraph {
+	if q == nil {
+		panic("community: nil quality function")
+	}
+	c := reduceUndirected(g, nil)
+	rnd := rand.Intn
+	if src != nil {
+		rnd = rand.New(src).Intn
+	}
+	for {
+		l := newUndirectedLocalMover(c, c.communities, 1)
+		if l == nil {
+			return c
+		}
+		l.setQuality(q)
+		if done := l.localMovingHeuristic(rnd); done {
+			return c
+		}
+		c = reduceUndirected(c, l.communities)
+	}
+}
+
+// sizes returns the number of nodes of the original graph held by each
+// node of g, indexed by ID.
+func (g *ReducedUndirected) sizes() []float64 {
+	sizes := make([]float64, len(g.nodes))
+	if g.parent == nil {
+		for i, c := range g.nodes {
+			sizes[i] = float64(len(c.nodes))
+		}
+		return sizes
+	}
+	sub := g.parent.sizes()
+	for i, c := range g.nodes {
+		for _, n := range c.nodes {
+			sizes[i] += sub[n.ID()]
+		}
+	}
+	return sizes
+}
+
+// setQuality sets the quality function optimized by l and initializes
+// the totals needed to describe moves.
+func (l *undirectedLocalMover) setQuality(q Quality) {
+	l.quality = q
+	l.size = l.g.sizes()
+	l.n = 0
+	for _, s := range l.size {
+		l.n += s
+	}
+	l.internalWeight = 0
+	l.internalPairs = 0
+	for _, c := range l.communities {
+		var size float64
+		for i, u := range c {
+			uid := u.ID()
+			size += l.size[uid]
+			// Node weights in a reduced graph count
+			// internal edges twice.
+			l.internalWeight += l.weight(uid, uid) / 2
+			for _, v := range c[i+1:] {
+				l.internalWeight += l.weight(uid, v.ID())
+			}
+		}
+		l.internalPairs += size * (size - 1) / 2
+	}
+}
+
+// deltaQuality returns the highest gain in the quality function of l
+// attainable by moving n from its current community to another connected
+// community and the index of the chosen destination. The index into the
+// undirectedLocalMover's communities field is returned in src if n is in
+// communities. The chosen move is retained in l.best for use by move.
+func (l *undirectedLocalMover) deltaQuality(n graph.Node) (delta float64, dst int, src commIdx) {
+	id := n.ID()
+
+	connected := make(set.Ints)
+	for _, vid := range l.g.edges[id] {
+		connected.Add(l.memberships[vid])
+	}
+	connected.Add(l.memberships[id])
+
+	candidates := make([]int, 0, len(connected))
+	for i := range connected {
+		candidates = append(candidates, i)
+	}
+	sort.Ints(candidates)
+
+	m := Move{
+		TotalWeight:    l.m2 / 2,
+		TotalSize:      l.n,
+		InternalWeight: l.internalWeight,
+		InternalPairs:  l.internalPairs,
+		Degree:         l.edgeWeightOf[id],
+		Size:           l.size[id],
+	}
+
+	stats := make([]MoveCommunity, len(candidates))
+	src = commIdx{-1, -1}
+	for k, i := range candidates {
+		var s MoveCommunity
+		for j, u := range l.communities[i] {
+			uid := u.ID()
+			if uid == id {
+				if src.community != -1 {
+					panic("community: multiple sources")
+				}
+				src = commIdx{i, j}
+				continue
+			}
+			s.Links += l.weight(id, uid)
+			s.Degree += l.edgeWeightOf[uid]
+			s.Size += l.size[uid]
+		}
+		stats[k] = s
+		if i == src.community {
+			m.From = s
+		}
+	}
+
+	delta, dst = math.Inf(-1), -1
+	for k, i := range candidates {
+		if i == src.community {
+			continue
+		}
+		m.To = stats[k]
+		d := l.quality.Delta(m)
+		if d > delta {
+			delta = d
+			dst = i
+			l.best = m
+		}
+	}
+	return delta, dst, src
+}
@@ -0,0 +1,191 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package community
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var qualityTests = []Quality{
+	Modularity{Resolution: 1},
+	Modularity{Resolution: 0.5},
+	CPM{Resolution: 0.1},
+	CPM{Resolution: 0.5},
+	Surprise{},
+}
+
+func TestQualityDelta(t *testing.T) {
+	const tol = 1e-10
+	for _, q := range qualityTests {
+		for _, test := range communityUndirectedQTests {
+			g := simple.NewUndirectedGraph()
+			for u, e := range test.g {
+				if g.Node(int64(u)) == nil {
+					g.AddNode(simple.Node(u))
+				}
+				for v := range e {
+					g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+				}
+			}
+
+			for _, structure := range test.structures {
+				communityOf := make(map[int64]int)
+				communities := make([][]graph.Node, len(structure.memberships))
+				for i, c := range structure.memberships {
+					for n := range c {
+						n := int64(n)
+						communityOf[n] = i
+						communities[i] = append(communities[i], simple.Node(n))
+					}
+					sort.Sort(ordered.ByID(communities[i]))
+				}
+
+				l := newUndirectedLocalMover(reduceUndirected(g, nil), communities, 1)
+				if l == nil {
+					continue
+				}
+				l.setQuality(q)
+				sort.Sort(ordered.ByID(l.nodes))
+
+				before := q.Score(g, communities)
+				for _, target := range l.nodes {
+					got, gotDst, _ := l.deltaQ(target)
+
+					want, wantDst := math.Inf(-1), -1
+					migrated := make([][]graph.Node, len(communities))
+					for i, c := range communities {
+						for _, n := range c {
+							if n.ID() != target.ID() {
+								migrated[i] = append(migrated[i], n)
+							}
+						}
+					}
+					for i, c := range communities {
+						if i == communityOf[target.ID()] {
+							continue
+						}
+						connected := false
+						for _, n := range c {
+							if g.HasEdgeBetween(n.ID(), target.ID()) {
+								connected = true
+								break
+							}
+						}
+						if !connected {
+							continue
+						}
+						migrated[i] = append(migrated[i], target)
+						after := q.Score(g, migrated)
+						migrated[i] = migrated[i][:len(migrated[i])-1]
+						if after-before > want+tol {
+							want = after - before
+							wantDst = i
+						}
+					}
+
+					if !floats.EqualWithinAbsOrRel(got, want, tol, tol) || gotDst != wantDst {
+						t.Errorf("unexpected result moving n=%d in c=%d of %s with %#v: got: %.4v,%d want: %.4v,%d",
+							target.ID(), communityOf[target.ID()], test.name, q, got, gotDst, want, wantDst)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestOptimizeModularity(t *testing.T) {
+	for _, test := range communityUndirectedQTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		for seed := uint64(1); seed <= 5; seed++ {
+			want := Q(g, Modularize(g, 1, rand.NewSource(seed)).Communities(), 1)
+			got := Q(g, Optimize(g, Modularity{Resolution: 1}, rand.NewSource(seed)).Communities(), 1)
+			if math.IsNaN(want) && math.IsNaN(got) {
+				continue
+			}
+			if !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+				t.Errorf("unexpected modularity for %s seed %d: got:%v want:%v", test.name, seed, got, want)
+			}
+		}
+	}
+}
+
+// ringOfCliques returns a ring of n cliques of size k, with adjacent
+// cliques joined by a single edge. Modularity optimization merges
+// neighbouring cliques of this graph when n is large enough.
+//
+// See doi:10.1073/pnas.0605965104 for details.
+func ringOfCliques(n, k int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for c := 0; c < n; c++ {
+		for i := 0; i < k; i++ {
+			for j := 0; j < i; j++ {
+				g.SetEdge(simple.Edge{F: simple.Node(c*k + i), T: simple.Node(c*k + j)})
+			}
+		}
+		g.SetEdge(simple.Edge{F: simple.Node(c * k), T: simple.Node(((c+1)%n)*k + k - 1)})
+	}
+	return g
+}
+
+func TestOptimizeResolutionLimit(t *testing.T) {
+	const n, k = 30, 5
+	g := ringOfCliques(n, k)
+
+	modularity := Modularize(g, 1, rand.NewSource(1)).Communities()
+	if len(modularity) >= n {
+		t.Errorf("expected modularity to merge cliques: got %d communities", len(modularity))
+	}
+
+	for _, q := range []Quality{CPM{Resolution: 0.5}, Surprise{}} {
+		for seed := uint64(1); seed <= 3; seed++ {
+			communities := Optimize(g, q, rand.NewSource(seed)).Communities()
+			if len(communities) != n {
+				t.Errorf("unexpected number of communities for %#v seed %d: got:%d want:%d", q, seed, len(communities), n)
+				continue
+			}
+			for _, c := range communities {
+				sort.Sort(ordered.ByID(c))
+				if len(c) != k || c[0].ID()%k != 0 || c[k-1].ID() != c[0].ID()+k-1 {
+					t.Errorf("community is not a clique for %#v seed %d: %v", q, seed, c)
+				}
+			}
+		}
+	}
+}
+
+func ExampleOptimize() {
+	// A ring of 30 five-cliques is beyond the resolution
+	// limit of modularity, but the constant Potts model
+	// and surprise quality functions recover the cliques.
+	g := ringOfCliques(30, 5)
+
+	src := rand.NewSource(1)
+	fmt.Println("modularity:", len(Modularize(g, 1, src).Communities()))
+	fmt.Println("CPM:", len(Optimize(g, CPM{Resolution: 0.5}, src).Communities()))
+	fmt.Println("surprise:", len(Optimize(g, Surprise{}, src).Communities()))
+
+	// Output:
+	// modularity: 17
+	// CPM: 30
+	// surprise: 30
+}
@@ -0,0 +1,225 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/testgraph"
+)
+
+type builderGraph interface {
+	graph.Graph
+	graph.Builder
+}
+
+// builder returns a testgraph.Builder that constructs graphs using
+// the graph returned by newGraph.
+func builder(newGraph func() builderGraph) testgraph.Builder {
+	return func(nodes []graph.Node, edges []testgraph.WeightedLine, _, _ float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+		g := newGraph()
+		seen := make(map[int64]graph.Node)
+		for _, n := range nodes {
+			seen[n.ID()] = n
+			g.AddNode(n)
+		}
+		var e []testgraph.Edge
+		for _, edge := range edges {
+			if edge.From().ID() == edge.To().ID() {
+				continue
+			}
+			f := g.Node(edge.From().ID())
+			if f == nil {
+				f = edge.From()
+			}
+			t := g.Node(edge.To().ID())
+			if t == nil {
+				t = edge.To()
+			}
+			ce := simple.Edge{F: f, T: t}
+			seen[f.ID()] = f
+			seen[t.ID()] = t
+			e = append(e, ce)
+			g.SetEdge(ce)
+		}
+		if len(e) == 0 && len(edges) != 0 {
+			return nil, nil, nil, math.NaN(), math.NaN(), false
+		}
+		var n []graph.Node
+		for _, sn := range seen {
+			n = append(n, sn)
+		}
+		return g, n, e, math.NaN(), math.NaN(), true
+	}
+}
+
+var graphTypes = []struct {
+	name     string
+	newGraph func() builderGraph
+}{
+	{name: "Directed", newGraph: func() builderGraph { return NewDirected(nil) }},
+	{name: "Undirected", newGraph: func() builderGraph { return NewUndirected(nil) }},
+	{name: "ShardedDirected", newGraph: func() builderGraph { return NewShardedDirected(3) }},
+	{name: "ShardedUndirected", newGraph: func() builderGraph { return NewShardedUndirected(3) }},
+}
+
+func TestGraphs(t *testing.T) {
+	for _, typ := range graphTypes {
+		b := builder(typ.newGraph)
+		t.Run(typ.name, func(t *testing.T) {
+			t.Run("EdgeExistence", func(t *testing.T) {
+				testgraph.EdgeExistence(t, b)
+			})
+			t.Run("NodeExistence", func(t *testing.T) {
+				testgraph.NodeExistence(t, b)
+			})
+			t.Run("ReturnAdjacentNodes", func(t *testing.T) {
+				testgraph.ReturnAdjacentNodes(t, b, true)
+			})
+			t.Run("ReturnAllEdges", func(t *testing.T) {
+				testgraph.ReturnAllEdges(t, b, true)
+			})
+			t.Run("ReturnAllNodes", func(t *testing.T) {
+				testgraph.ReturnAllNodes(t, b, true)
+			})
+			t.Run("AddNodes", func(t *testing.T) {
+				testgraph.AddNodes(t, typ.newGraph().(testgraph.NodeAdder), 100)
+			})
+			t.Run("NoLoopAddEdges", func(t *testing.T) {
+				testgraph.NoLoopAddEdges(t, 100,
+					typ.newGraph().(testgraph.EdgeAdder),
+					func(id int64) graph.Node { return simple.Node(id) },
+				)
+			})
+		})
+	}
+}
+
+func TestConcurrentIngest(t *testing.T) {
+	const (
+		workers = 8
+		n       = 100
+	)
+	for _, typ := range graphTypes {
+		g := typ.newGraph()
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func(w int) {
+				defer wg.Done()
+				for i := w; i < n; i += workers {
+					// Each worker adds the edges from node i to
+					// all higher numbered nodes, concurrently
+					// reading the graph while doing so.
+					for j := i + 1; j < n; j++ {
+						g.SetEdge(g.NewEdge(simple.Node(i), simple.Node(j)))
+						g.From(int64(i)).Len()
+						g.HasEdgeBetween(int64(j), int64(i))
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+
+		if got := g.Nodes().Len(); got != n {
+			t.Errorf("unexpected number of nodes for %s: got:%d want:%d", typ.name, got, n)
+		}
+		edges := g.(interface{ Edges() graph.Edges }).Edges()
+		if got, want := edges.Len(), n*(n-1)/2; got != want {
+			t.Errorf("unexpected number of edges for %s: got:%d want:%d", typ.name, got, want)
+		}
+	}
+}
+
+func TestShardedRemoveNode(t *testing.T) {
+	for _, shards := range []int{1, 2, 5} {
+		g := NewShardedDirected(shards)
+		for i := int64(0); i < 10; i++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node((i + 1) % 10)})
+		}
+		g.RemoveNode(3)
+		if g.Node(3) != nil {
+			t.Errorf("node not removed with %d shards", shards)
+		}
+		if g.HasEdgeFromTo(2, 3) || g.HasEdgeFromTo(3, 4) {
+			t.Errorf("edges not removed with %d shards", shards)
+		}
+		if got := g.To(4).Len(); got != 0 {
+			t.Errorf("unexpected in degree of 4 with %d shards: got:%d want:0", shards, got)
+		}
+		if got := g.Edges().Len(); got != 8 {
+			t.Errorf("unexpected number of edges with %d shards: got:%d want:8", shards, got)
+		}
+	}
+}
+
+func TestShardedNewNode(t *testing.T) {
+	g := NewShardedUndirected(4)
+	const n = 100
+	nodes := make(chan graph.Node, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u := g.NewNode()
+			g.AddNode(u)
+			nodes <- u
+		}()
+	}
+	wg.Wait()
+	close(nodes)
+	seen := make(map[int64]bool)
+	for u := range nodes {
+		if seen[u.ID()] {
+			t.Errorf("duplicate node ID: %d", u.ID())
+		}
+		seen[u.ID()] = true
+	}
+	if got := g.Nodes().Len(); got != n {
+		t.Errorf("unexpected number of nodes: got:%d want:%d", got, n)
+	}
+}
+
+func BenchmarkConcurrentSetEdge(b *testing.B) {
+	for _, typ := range graphTypes {
+		b.Run(typ.name, func(b *testing.B) {
+			g := typ.newGraph()
+			var mu sync.Mutex
+			var next int64
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					mu.Lock()
+					i := next
+					next++
+					mu.Unlock()
+					g.SetEdge(g.NewEdge(simple.Node(2*i), simple.Node(2*i+1)))
+				}
+			})
+		})
+	}
+}
+
+func ExampleShardedUndirected() {
+	g := NewShardedUndirected(16)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+			g.SetEdge(g.NewEdge(simple.Node(i), simple.Node(i+1)))
+		}(int64(i))
+	}
+	wg.Wait()
+	fmt.Println(g.Nodes().Len(), g.Edges().Len())
+
+	// Output:
+	// 5 4
+}
@@ -0,0 +1,14 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package concurrent provides graphs that are safe for concurrent use.
+//
+// The Directed and Undirected types wrap the graphs of the simple package
+// with a read-write mutex. The ShardedDirected and ShardedUndirected types
+// partition their nodes and edges over independently locked shards, reducing
+// lock contention when many goroutines mutate the graph at once.
+//
+// Node and edge iterators returned by the graphs in this package are
+// snapshots and are not affected by later mutation of the graph.
+package concurrent // import "gonum.org/v1/gonum/graph/concurrent"
@@ -0,0 +1,302 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"sync"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var (
+	dg *Directed
+
+	_ graph.Graph           = dg
+	_ graph.Directed        = dg
+	_ graph.DirectedBuilder = dg
+	_ graph.NodeRemover     = dg
+	_ graph.EdgeRemover     = dg
+
+	ug *Undirected
+
+	_ graph.Graph             = ug
+	_ graph.Undirected        = ug
+	_ graph.UndirectedBuilder = ug
+	_ graph.NodeRemover       = ug
+	_ graph.EdgeRemover       = ug
+)
+
+// Directed is a simple.DirectedGraph guarded by a read-write mutex.
+type Directed struct {
+	mu sync.RWMutex
+	g  *simple.DirectedGraph
+}
+
+// NewDirected returns a Directed wrapping g. If g is nil a new empty
+// simple.DirectedGraph is used. The wrapped graph must not be accessed
+// other than through the returned Directed.
+func NewDirected(g *simple.DirectedGraph) *Directed {
+	if g == nil {
+		g = simple.NewDirectedGraph()
+	}
+	return &Directed{g: g}
+}
+
+// View calls fn with the wrapped graph while holding a read lock. The graph
+// must not be mutated or retained by fn.
+func (g *Directed) View(fn func(*simple.DirectedGraph)) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	fn(g.g)
+}
+
+// Update calls fn with the wrapped graph while holding the write lock,
+// allowing a sequence of operations, such as a call to NewNode followed
+// by AddNode, to be performed atomically. The graph must not be retained
+// by fn.
+func (g *Directed) Update(fn func(*simple.DirectedGraph)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fn(g.g)
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (g *Directed) AddNode(n graph.Node) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.AddNode(n)
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *Directed) Edge(uid, vid int64) graph.Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Edge(uid, vid)
+}
+
+// Edges returns all the edges in the graph.
+func (g *Directed) Edges() graph.Edges {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Edges()
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *Directed) From(id int64) graph.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.From(id)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (g *Directed) HasEdgeBetween(xid, yid int64) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.HasEdgeBetween(xid, yid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *Directed) HasEdgeFromTo(uid, vid int64) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.HasEdgeFromTo(uid, vid)
+}
+
+// NewEdge returns a new Edge from the source to the destination node.
+func (g *Directed) NewEdge(from, to graph.Node) graph.Edge {
+	return g.g.NewEdge(from, to)
+}
+
+// NewNode returns a new unique Node to be added to g. The Node's ID does
+// not become valid in g until the Node is added to g. Concurrent callers
+// of NewNode may be returned the same node; Update should be used to
+// allocate and add nodes atomically.
+func (g *Directed) NewNode() graph.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.NewNode()
+}
+
+// Node returns the node with the given ID if it exists in the graph,
+// and nil otherwise.
+func (g *Directed) Node(id int64) graph.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Node(id)
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *Directed) Nodes() graph.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Nodes()
+}
+
+// RemoveEdge removes the edge with the given end point IDs from the graph, leaving the terminal
+// nodes. If the edge does not exist it is a no-op.
+func (g *Directed) RemoveEdge(fid, tid int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.RemoveEdge(fid, tid)
+}
+
+// RemoveNode removes the node with the given ID from the graph, as well as any edges attached
+// to it. If the node is not in the graph it is a no-op.
+func (g *Directed) RemoveNode(id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.RemoveNode(id)
+}
+
+// SetEdge adds e, an edge from one node to another. If the nodes do not exist, they are added
+// and are set to the nodes of the edge otherwise.
+// It will panic if the IDs of the e.From and e.To are equal.
+func (g *Directed) SetEdge(e graph.Edge) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.SetEdge(e)
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (g *Directed) To(id int64) graph.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.To(id)
+}
+
+// Undirected is a simple.UndirectedGraph guarded by a read-write mutex.
+type Undirected struct {
+	mu sync.RWMutex
+	g  *simple.UndirectedGraph
+}
+
+// NewUndirected returns an Undirected wrapping g. If g is nil a new empty
+// simple.UndirectedGraph is used. The wrapped graph must not be accessed
+// other than through the returned Undirected.
+func NewUndirected(g *simple.UndirectedGraph) *Undirected {
+	if g == nil {
+		g = simple.NewUndirectedGraph()
+	}
+	return &Undirected{g: g}
+}
+
+// View calls fn with the wrapped graph while holding a read lock. The graph
+// must not be mutated or retained by fn.
+func (g *Undirected) View(fn func(*simple.UndirectedGraph)) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	fn(g.g)
+}
+
+// Update calls fn with the wrapped graph while holding the write lock,
+// allowing a sequence of operations, such as a call to NewNode followed
+// by AddNode, to be performed atomically. The graph must not be retained
+// by fn.
+func (g *Undirected) Update(fn func(*simple.UndirectedGraph)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	fn(g.g)
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (g *Undirected) AddNode(n graph.Node) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.AddNode(n)
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *Undirected) Edge(uid, vid int64) graph.Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Edge(uid, vid)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (g *Undirected) EdgeBetween(xid, yid int64) graph.Edge {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.EdgeBetween(xid, yid)
+}
+
+// Edges returns all the edges in the graph.
+func (g *Undirected) Edges() graph.Edges {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Edges()
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *Undirected) From(id int64) graph.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.From(id)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y.
+func (g *Undirected) HasEdgeBetween(xid, yid int64) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.HasEdgeBetween(xid, yid)
+}
+
+// NewEdge returns a new Edge from the source to the destination node.
+func (g *Undirected) NewEdge(from, to graph.Node) graph.Edge {
+	return g.g.NewEdge(from, to)
+}
+
+// NewNode returns a new unique Node to be added to g. The Node's ID does
+// not become valid in g until the Node is added to g. Concurrent callers
+// of NewNode may be returned the same node; Update should be used to
+// allocate and add nodes atomically.
+func (g *Undirected) NewNode() graph.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.NewNode()
+}
+
+// Node returns the node with the given ID if it exists in the graph,
+// and nil otherwise.
+func (g *Undirected) Node(id int64) graph.Node {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Node(id)
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *Undirected) Nodes() graph.Nodes {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.g.Nodes()
+}
+
+// RemoveEdge removes the edge with the given end IDs from the graph, leaving the terminal nodes.
+// If the edge does not exist it is a no-op.
+func (g *Undirected) RemoveEdge(fid, tid int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.RemoveEdge(fid, tid)
+}
+
+// RemoveNode removes the node with the given ID from the graph, as well as any edges attached
+// to it. If the node is not in the graph it is a no-op.
+func (g *Undirected) RemoveNode(id int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.RemoveNode(id)
+}
+
+// SetEdge adds e, an edge from one node to another. If the nodes do not exist, they are added
+// and are set to the nodes of the edge otherwise.
+// It will panic if the IDs of the e.From and e.To are equal.
+func (g *Undirected) SetEdge(e graph.Edge) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.g.SetEdge(e)
+}
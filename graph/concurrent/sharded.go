@@ -0,0 +1,425 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"fmt"
+	"sync"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/uid"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var (
+	sdg *ShardedDirected
+
+	_ graph.Graph           = sdg
+	_ graph.Directed        = sdg
+	_ graph.DirectedBuilder = sdg
+	_ graph.NodeRemover     = sdg
+	_ graph.EdgeRemover     = sdg
+
+	sug *ShardedUndirected
+
+	_ graph.Graph             = sug
+	_ graph.Undirected        = sug
+	_ graph.UndirectedBuilder = sug
+	_ graph.NodeRemover       = sug
+	_ graph.EdgeRemover       = sug
+)
+
+// ShardedDirected is a directed graph safe for concurrent use with nodes
+// and edges partitioned over independently locked shards. An edge is held
+// by the shards of both of its end points.
+type ShardedDirected struct {
+	sharded
+}
+
+// NewShardedDirected returns a ShardedDirected with the given number of
+// shards. NewShardedDirected will panic if shards is less than one.
+func NewShardedDirected(shards int) *ShardedDirected {
+	g := &ShardedDirected{}
+	g.init(shards, true)
+	return g
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *ShardedDirected) Edge(uid, vid int64) graph.Edge {
+	s := g.shardOf(uid)
+	s.RLock()
+	defer s.RUnlock()
+	e, ok := s.from[uid][vid]
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y without
+// considering direction.
+func (g *ShardedDirected) HasEdgeBetween(xid, yid int64) bool {
+	return g.HasEdgeFromTo(xid, yid) || g.HasEdgeFromTo(yid, xid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *ShardedDirected) HasEdgeFromTo(uid, vid int64) bool {
+	return g.Edge(uid, vid) != nil
+}
+
+// To returns all nodes in g that can reach directly to n.
+func (g *ShardedDirected) To(id int64) graph.Nodes {
+	return g.adjacent(id, func(s *shard) map[int64]graph.Edge { return s.to[id] })
+}
+
+// ShardedUndirected is an undirected graph safe for concurrent use with
+// nodes and edges partitioned over independently locked shards. An edge
+// is held by the shards of both of its end points.
+type ShardedUndirected struct {
+	sharded
+}
+
+// NewShardedUndirected returns a ShardedUndirected with the given number of
+// shards. NewShardedUndirected will panic if shards is less than one.
+func NewShardedUndirected(shards int) *ShardedUndirected {
+	g := &ShardedUndirected{}
+	g.init(shards, false)
+	return g
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *ShardedUndirected) Edge(uid, vid int64) graph.Edge {
+	return g.EdgeBetween(uid, vid)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (g *ShardedUndirected) EdgeBetween(xid, yid int64) graph.Edge {
+	s := g.shardOf(xid)
+	s.RLock()
+	defer s.RUnlock()
+	e, ok := s.from[xid][yid]
+	if !ok {
+		return nil
+	}
+	if e.From().ID() == xid {
+		return e
+	}
+	return e.ReversedEdge()
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes x and y.
+func (g *ShardedUndirected) HasEdgeBetween(xid, yid int64) bool {
+	s := g.shardOf(xid)
+	s.RLock()
+	defer s.RUnlock()
+	_, ok := s.from[xid][yid]
+	return ok
+}
+
+// shard is an independently locked part of a sharded graph.
+type shard struct {
+	sync.RWMutex
+
+	nodes map[int64]graph.Node
+
+	// from holds the edges leaving each node
+	// in the shard. For undirected graphs it
+	// holds all edges incident to the node.
+	from map[int64]map[int64]graph.Edge
+
+	// to holds the edges entering each node
+	// in the shard of a directed graph. It
+	// is nil for undirected graphs.
+	to map[int64]map[int64]graph.Edge
+}
+
+// sharded holds the behavior common to the sharded graph types.
+type sharded struct {
+	shards   []shard
+	directed bool
+
+	idMu    sync.Mutex
+	nodeIDs uid.Set
+}
+
+// init initializes g with n shards.
+func (g *sharded) init(n int, directed bool) {
+	if n < 1 {
+		panic("concurrent: non-positive shard count")
+	}
+	g.shards = make([]shard, n)
+	g.directed = directed
+	g.nodeIDs = uid.NewSet()
+	for i := range g.shards {
+		g.shards[i].nodes = make(map[int64]graph.Node)
+		g.shards[i].from = make(map[int64]map[int64]graph.Edge)
+		if directed {
+			g.shards[i].to = make(map[int64]map[int64]graph.Edge)
+		}
+	}
+}
+
+// index returns the index of the shard holding the node with the given ID.
+func (g *sharded) index(id int64) int {
+	return int(uint64(id) % uint64(len(g.shards)))
+}
+
+// shardOf returns the shard holding the node with the given ID.
+func (g *sharded) shardOf(id int64) *shard {
+	return &g.shards[g.index(id)]
+}
+
+// lockPair write locks the shards holding the nodes with IDs
+// uid and vid in a consistent order and returns the shards and
+// a function to unlock them.
+func (g *sharded) lockPair(uid, vid int64) (su, sv *shard, unlock func()) {
+	i, j := g.index(uid), g.index(vid)
+	su, sv = &g.shards[i], &g.shards[j]
+	switch {
+	case i == j:
+		su.Lock()
+		return su, sv, su.Unlock
+	case i < j:
+		su.Lock()
+		sv.Lock()
+	default:
+		sv.Lock()
+		su.Lock()
+	}
+	return su, sv, func() {
+		su.Unlock()
+		sv.Unlock()
+	}
+}
+
+// lockAll locks all the shards in order for reading or writing and
+// returns a function to unlock them.
+func (g *sharded) lockAll(write bool) (unlock func()) {
+	for i := range g.shards {
+		if write {
+			g.shards[i].Lock()
+		} else {
+			g.shards[i].RLock()
+		}
+	}
+	return func() {
+		for i := range g.shards {
+			if write {
+				g.shards[i].Unlock()
+			} else {
+				g.shards[i].RUnlock()
+			}
+		}
+	}
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an existing node ID.
+func (g *sharded) AddNode(n graph.Node) {
+	id := n.ID()
+	s := g.shardOf(id)
+	s.Lock()
+	defer s.Unlock()
+	if _, exists := s.nodes[id]; exists {
+		panic(fmt.Sprintf("concurrent: node ID collision: %d", id))
+	}
+	s.nodes[id] = n
+	g.useID(id)
+}
+
+// useID marks id as used.
+func (g *sharded) useID(id int64) {
+	g.idMu.Lock()
+	g.nodeIDs.Use(id)
+	g.idMu.Unlock()
+}
+
+// Edges returns all the edges in the graph.
+func (g *sharded) Edges() graph.Edges {
+	unlock := g.lockAll(false)
+	defer unlock()
+	var edges []graph.Edge
+	for i := range g.shards {
+		for uid, from := range g.shards[i].from {
+			for vid, e := range from {
+				if !g.directed && vid < uid {
+					continue
+				}
+				edges = append(edges, e)
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return graph.Empty
+	}
+	return iterator.NewOrderedEdges(edges)
+}
+
+// From returns all nodes in g that can be reached directly from n.
+func (g *sharded) From(id int64) graph.Nodes {
+	return g.adjacent(id, func(s *shard) map[int64]graph.Edge { return s.from[id] })
+}
+
+// adjacent returns a snapshot of the nodes held by the edges returned by
+// edges for the shard holding the node with the given ID.
+func (g *sharded) adjacent(id int64, edges func(*shard) map[int64]graph.Edge) graph.Nodes {
+	s := g.shardOf(id)
+	s.RLock()
+	ids := make([]int64, 0, len(edges(s)))
+	for vid := range edges(s) {
+		ids = append(ids, vid)
+	}
+	s.RUnlock()
+
+	var nodes []graph.Node
+	for _, vid := range ids {
+		// The neighbor may have been removed since
+		// the shard holding id was unlocked.
+		if n := g.Node(vid); n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+	if len(nodes) == 0 {
+		return graph.Empty
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// NewEdge returns a new Edge from the source to the destination node.
+func (g *sharded) NewEdge(from, to graph.Node) graph.Edge {
+	return simple.Edge{F: from, T: to}
+}
+
+// NewNode returns a new unique Node to be added to g. The Node's ID does
+// not become valid in g until the Node is added to g. The returned ID is
+// reserved, so concurrent callers of NewNode receive distinct nodes.
+func (g *sharded) NewNode() graph.Node {
+	g.idMu.Lock()
+	defer g.idMu.Unlock()
+	id := g.nodeIDs.NewID()
+	g.nodeIDs.Use(id)
+	return simple.Node(id)
+}
+
+// Node returns the node with the given ID if it exists in the graph,
+// and nil otherwise.
+func (g *sharded) Node(id int64) graph.Node {
+	s := g.shardOf(id)
+	s.RLock()
+	defer s.RUnlock()
+	return s.nodes[id]
+}
+
+// Nodes returns all the nodes in the graph.
+func (g *sharded) Nodes() graph.Nodes {
+	unlock := g.lockAll(false)
+	defer unlock()
+	var nodes []graph.Node
+	for i := range g.shards {
+		for _, n := range g.shards[i].nodes {
+			nodes = append(nodes, n)
+		}
+	}
+	if len(nodes) == 0 {
+		return graph.Empty
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// RemoveEdge removes the edge with the given end point IDs from the graph, leaving the terminal
+// nodes. If the edge does not exist it is a no-op.
+func (g *sharded) RemoveEdge(fid, tid int64) {
+	sf, st, unlock := g.lockPair(fid, tid)
+	defer unlock()
+	if _, ok := sf.nodes[fid]; !ok {
+		return
+	}
+	if _, ok := st.nodes[tid]; !ok {
+		return
+	}
+	delete(sf.from[fid], tid)
+	if g.directed {
+		delete(st.to[tid], fid)
+	} else {
+		delete(st.from[tid], fid)
+	}
+}
+
+// RemoveNode removes the node with the given ID from the graph, as well as any edges attached
+// to it. If the node is not in the graph it is a no-op.
+func (g *sharded) RemoveNode(id int64) {
+	unlock := g.lockAll(true)
+	defer unlock()
+	s := g.shardOf(id)
+	if _, ok := s.nodes[id]; !ok {
+		return
+	}
+	delete(s.nodes, id)
+
+	for vid := range s.from[id] {
+		sv := g.shardOf(vid)
+		if g.directed {
+			delete(sv.to[vid], id)
+		} else {
+			delete(sv.from[vid], id)
+		}
+	}
+	delete(s.from, id)
+	if g.directed {
+		for uid := range s.to[id] {
+			delete(g.shardOf(uid).from[uid], id)
+		}
+		delete(s.to, id)
+	}
+
+	g.idMu.Lock()
+	g.nodeIDs.Release(id)
+	g.idMu.Unlock()
+}
+
+// SetEdge adds e, an edge from one node to another. If the nodes do not exist, they are added
+// and are set to the nodes of the edge otherwise.
+// It will panic if the IDs of the e.From and e.To are equal.
+func (g *sharded) SetEdge(e graph.Edge) {
+	var (
+		from = e.From()
+		fid  = from.ID()
+		to   = e.To()
+		tid  = to.ID()
+	)
+
+	if fid == tid {
+		panic("concurrent: adding self edge")
+	}
+
+	sf, st, unlock := g.lockPair(fid, tid)
+	defer unlock()
+
+	if _, ok := sf.nodes[fid]; !ok {
+		g.useID(fid)
+	}
+	sf.nodes[fid] = from
+	if _, ok := st.nodes[tid]; !ok {
+		g.useID(tid)
+	}
+	st.nodes[tid] = to
+
+	setEdge(sf.from, fid, tid, e)
+	if g.directed {
+		setEdge(st.to, tid, fid, e)
+	} else {
+		setEdge(st.from, tid, fid, e)
+	}
+}
+
+func setEdge(m map[int64]map[int64]graph.Edge, uid, vid int64, e graph.Edge) {
+	if em, ok := m[uid]; ok {
+		em[vid] = e
+	} else {
+		m[uid] = map[int64]graph.Edge{vid: e}
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csr
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Options specifies how a CSR graph is constructed.
+type Options struct {
+	// Index32 specifies that 32-bit indices
+	// are used to store the graph structure,
+	// halving its memory use. Constructors will
+	// panic if the graph is too large for 32-bit
+	// indices.
+	Index32 bool
+
+	// Self and Absent are the weights returned
+	// by Weight for self edges and absent edges.
+	Self, Absent float64
+}
+
+// defaultOptions are the options used when nil
+// options are passed to a constructor.
+var defaultOptions = Options{Absent: math.Inf(1)}
+
+// adjacency is a compressed sparse row adjacency structure.
+type adjacency struct {
+	// Only one of the 32-bit or 64-bit
+	// index pairs is non-nil. The neighbors
+	// of node i are held in targets[offsets[i]:offsets[i+1]]
+	// in ascending order.
+	offsets32, targets32 []int32
+	offsets64, targets64 []int64
+
+	// weights holds the edge weights in the
+	// same order as the targets. If weights is
+	// nil all edges have a weight of 1.
+	weights []float64
+}
+
+// degree returns the number of neighbors of the node at index i.
+func (a *adjacency) degree(i int) int {
+	lo, hi := a.row(i)
+	return hi - lo
+}
+
+// row returns the range of indices into the targets for index i.
+func (a *adjacency) row(i int) (lo, hi int) {
+	if a.offsets32 != nil {
+		return int(a.offsets32[i]), int(a.offsets32[i+1])
+	}
+	return int(a.offsets64[i]), int(a.offsets64[i+1])
+}
+
+// target returns the node index held at k.
+func (a *adjacency) target(k int) int {
+	if a.targets32 != nil {
+		return int(a.targets32[k])
+	}
+	return int(a.targets64[k])
+}
+
+// weight returns the weight of the edge held at k.
+func (a *adjacency) weight(k int) float64 {
+	if a.weights == nil {
+		return 1
+	}
+	return a.weights[k]
+}
+
+// find returns the position of the edge from index i to index j
+// and whether it exists.
+func (a *adjacency) find(i, j int) (int, bool) {
+	lo, hi := a.row(i)
+	k := lo + sort.Search(hi-lo, func(k int) bool { return a.target(lo+k) >= j })
+	return k, k < hi && a.target(k) == j
+}
+
+// store holds the node set shared by the CSR graph types.
+type store struct {
+	// nodes holds the nodes of the graph
+	// sorted by ID.
+	nodes []graph.Node
+
+	// index maps node IDs to node indices.
+	// If index is nil the node IDs are 0
+	// through len(nodes)-1.
+	index map[int64]int
+
+	self, absent float64
+}
+
+func newStore(g graph.Graph, opts *Options) (store, Options) {
+	if opts == nil {
+		opts = &defaultOptions
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	if opts.Index32 && int64(len(nodes)) > math.MaxInt32 {
+		panic("csr: too many nodes for 32-bit indices")
+	}
+	s := store{nodes: nodes, self: opts.Self, absent: opts.Absent}
+	for i, n := range nodes {
+		if n.ID() != int64(i) {
+			s.index = make(map[int64]int, len(nodes))
+			for i, n := range nodes {
+				s.index[n.ID()] = i
+			}
+			break
+		}
+	}
+	return s, *opts
+}
+
+// indexOf returns the index of the node with the given ID and
+// whether it exists in the graph.
+func (s *store) indexOf(id int64) (int, bool) {
+	if s.index == nil {
+		if id < 0 || id >= int64(len(s.nodes)) {
+			return -1, false
+		}
+		return int(id), true
+	}
+	i, ok := s.index[id]
+	return i, ok
+}
+
+// Node returns the node with the given ID if it exists in the graph,
+// and nil otherwise.
+func (s *store) Node(id int64) graph.Node {
+	i, ok := s.indexOf(id)
+	if !ok {
+		return nil
+	}
+	return s.nodes[i]
+}
+
+// Nodes returns all the nodes in the graph in ascending order of ID.
+func (s *store) Nodes() graph.Nodes {
+	if len(s.nodes) == 0 {
+		return graph.Empty
+	}
+	return &nodes{nodes: s.nodes, curr: -1}
+}
+
+// neighbors returns an iterator over the neighbors of the node with
+// the given ID held in a.
+func (s *store) neighbors(a *adjacency, id int64) graph.Nodes {
+	i, ok := s.indexOf(id)
+	if !ok {
+		return graph.Empty
+	}
+	lo, hi := a.row(i)
+	if lo == hi {
+		return graph.Empty
+	}
+	return &neighbors{nodes: s.nodes, adj: a, lo: lo, hi: hi, curr: lo - 1}
+}
+
+// edge returns the weighted edge from u to v held in a and whether
+// it exists.
+func (s *store) edge(a *adjacency, uid, vid int64) (graph.WeightedEdge, bool) {
+	i, ok := s.indexOf(uid)
+	if !ok {
+		return nil, false
+	}
+	j, ok := s.indexOf(vid)
+	if !ok {
+		return nil, false
+	}
+	k, ok := a.find(i, j)
+	if !ok {
+		return nil, false
+	}
+	return edge{F: s.nodes[i], T: s.nodes[j], W: a.weight(k)}, true
+}
+
+// weight returns the weight of the edge from x to y held in a.
+func (s *store) weight(a *adjacency, xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		return s.self, true
+	}
+	e, ok := s.edge(a, xid, yid)
+	if !ok {
+		return s.absent, false
+	}
+	return e.Weight(), true
+}
+
+// build returns a CSR adjacency of the nodes in s, with the neighbors
+// of each node given by to. If weight is not nil, edge weights are
+// obtained from it.
+func (s *store) build(to func(id int64) graph.Nodes, weight func(uid, vid int64) float64, index32 bool) *adjacency {
+	n := len(s.nodes)
+	offsets := make([]int64, n+1)
+	var targets []int64
+	var weights []float64
+	var row []int
+	for i, u := range s.nodes {
+		uid := u.ID()
+		row = row[:0]
+		it := to(uid)
+		for it.Next() {
+			j, ok := s.indexOf(it.Node().ID())
+			if !ok {
+				panic("csr: neighbor not in graph")
+			}
+			row = append(row, j)
+		}
+		sort.Ints(row)
+		for _, j := range row {
+			targets = append(targets, int64(j))
+			if weight != nil {
+				weights = append(weights, weight(uid, s.nodes[j].ID()))
+			}
+		}
+		offsets[i+1] = int64(len(targets))
+	}
+
+	a := &adjacency{weights: weights}
+	if !index32 {
+		a.offsets64 = offsets
+		a.targets64 = targets
+		return a
+	}
+	if int64(len(targets)) > math.MaxInt32 {
+		panic("csr: too many edges for 32-bit indices")
+	}
+	a.offsets32 = make([]int32, len(offsets))
+	for i, o := range offsets {
+		a.offsets32[i] = int32(o)
+	}
+	a.targets32 = make([]int32, len(targets))
+	for i, t := range targets {
+		a.targets32[i] = int32(t)
+	}
+	return a
+}
+
+// edge is a weighted edge in a CSR graph.
+type edge struct {
+	F, T graph.Node
+	W    float64
+}
+
+func (e edge) From() graph.Node         { return e.F }
+func (e edge) To() graph.Node           { return e.T }
+func (e edge) ReversedEdge() graph.Edge { return edge{F: e.T, T: e.F, W: e.W} }
+func (e edge) Weight() float64          { return e.W }
+
+// nodes is an iterator over all the nodes of a CSR graph.
+type nodes struct {
+	nodes []graph.Node
+	curr  int
+}
+
+func (it *nodes) Len() int {
+	return len(it.nodes) - it.curr - 1
+}
+
+func (it *nodes) Next() bool {
+	if it.curr >= len(it.nodes)-1 {
+		it.curr = len(it.nodes)
+		return false
+	}
+	it.curr++
+	return true
+}
+
+func (it *nodes) Node() graph.Node {
+	if it.curr < 0 || it.curr >= len(it.nodes) {
+		return nil
+	}
+	return it.nodes[it.curr]
+}
+
+// NodeSlice returns a copy of the remaining nodes in the iterator.
+func (it *nodes) NodeSlice() []graph.Node {
+	if it.curr >= len(it.nodes)-1 {
+		it.curr = len(it.nodes)
+		return nil
+	}
+	n := make([]graph.Node, len(it.nodes)-it.curr-1)
+	copy(n, it.nodes[it.curr+1:])
+	it.curr = len(it.nodes)
+	return n
+}
+
+func (it *nodes) Reset() { it.curr = -1 }
+
+// neighbors is an iterator over the neighbors of a node in a CSR graph.
+type neighbors struct {
+	nodes  []graph.Node
+	adj    *adjacency
+	lo, hi int
+	curr   int
+}
+
+func (it *neighbors) Len() int {
+	return it.hi - it.curr - 1
+}
+
+func (it *neighbors) Next() bool {
+	if it.curr >= it.hi-1 {
+		it.curr = it.hi
+		return false
+	}
+	it.curr++
+	return true
+}
+
+func (it *neighbors) Node() graph.Node {
+	if it.curr < it.lo || it.curr >= it.hi {
+		return nil
+	}
+	return it.nodes[it.adj.target(it.curr)]
+}
+
+// NodeSlice returns the remaining nodes in the iterator.
+func (it *neighbors) NodeSlice() []graph.Node {
+	if it.curr >= it.hi-1 {
+		it.curr = it.hi
+		return nil
+	}
+	n := make([]graph.Node, 0, it.hi-it.curr-1)
+	for k := it.curr + 1; k < it.hi; k++ {
+		n = append(n, it.nodes[it.adj.target(k)])
+	}
+	it.curr = it.hi
+	return n
+}
+
+func (it *neighbors) Reset() { it.curr = it.lo - 1 }
@@ -0,0 +1,157 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csr
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/testgraph"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+// builder returns a testgraph.Builder that constructs CSR graphs from
+// simple weighted graphs.
+func builder(directed, index32 bool) testgraph.Builder {
+	return func(nodes []graph.Node, edges []testgraph.WeightedLine, self, absent float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+		var g interface {
+			graph.Weighted
+			graph.NodeAdder
+			graph.WeightedEdgeAdder
+		}
+		if directed {
+			g = simple.NewWeightedDirectedGraph(self, absent)
+		} else {
+			g = simple.NewWeightedUndirectedGraph(self, absent)
+		}
+		seen := make(map[int64]graph.Node)
+		for _, n := range nodes {
+			seen[n.ID()] = n
+			g.AddNode(n)
+		}
+		var e []testgraph.Edge
+		for _, edge := range edges {
+			if edge.From().ID() == edge.To().ID() {
+				continue
+			}
+			f := g.Node(edge.From().ID())
+			if f == nil {
+				f = edge.From()
+			}
+			t := g.Node(edge.To().ID())
+			if t == nil {
+				t = edge.To()
+			}
+			ce := simple.WeightedEdge{F: f, T: t, W: edge.Weight()}
+			seen[f.ID()] = f
+			seen[t.ID()] = t
+			e = append(e, ce)
+			g.SetWeightedEdge(ce)
+		}
+		if len(e) == 0 && len(edges) != 0 {
+			return nil, nil, nil, math.NaN(), math.NaN(), false
+		}
+		var n []graph.Node
+		for _, sn := range seen {
+			n = append(n, sn)
+		}
+		opts := &Options{Index32: index32, Self: self, Absent: absent}
+		if directed {
+			return NewDirected(g.(graph.Directed), opts), n, e, self, absent, true
+		}
+		return NewUndirected(g.(graph.Undirected), opts), n, e, self, absent, true
+	}
+}
+
+func TestCSR(t *testing.T) {
+	for _, directed := range []bool{false, true} {
+		for _, index32 := range []bool{false, true} {
+			b := builder(directed, index32)
+			t.Run(fmt.Sprintf("directed=%t/index32=%t", directed, index32), func(t *testing.T) {
+				t.Run("EdgeExistence", func(t *testing.T) {
+					testgraph.EdgeExistence(t, b)
+				})
+				t.Run("NodeExistence", func(t *testing.T) {
+					testgraph.NodeExistence(t, b)
+				})
+				t.Run("ReturnAdjacentNodes", func(t *testing.T) {
+					testgraph.ReturnAdjacentNodes(t, b, true)
+				})
+				t.Run("ReturnAllNodes", func(t *testing.T) {
+					testgraph.ReturnAllNodes(t, b, true)
+				})
+				t.Run("ReturnNodeSlice", func(t *testing.T) {
+					testgraph.ReturnNodeSlice(t, b, true)
+				})
+				t.Run("Weight", func(t *testing.T) {
+					testgraph.Weight(t, b)
+				})
+			})
+		}
+	}
+}
+
+func TestDegree(t *testing.T) {
+	src := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{10, 20}, {10, 30}, {20, 30}, {30, 10}} {
+		src.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	src.AddNode(simple.Node(40))
+	g := NewDirected(src, nil)
+	for _, test := range []struct {
+		id      int64
+		out, in int
+	}{
+		{id: 10, out: 2, in: 1},
+		{id: 20, out: 1, in: 1},
+		{id: 30, out: 1, in: 2},
+		{id: 40, out: 0, in: 0},
+		{id: 50, out: 0, in: 0},
+	} {
+		if got := g.OutDegree(test.id); got != test.out {
+			t.Errorf("unexpected out degree for node %d: got:%d want:%d", test.id, got, test.out)
+		}
+		if got := g.InDegree(test.id); got != test.in {
+			t.Errorf("unexpected in degree for node %d: got:%d want:%d", test.id, got, test.in)
+		}
+	}
+	if w, ok := g.Weight(10, 20); !ok || w != 1 {
+		t.Errorf("unexpected weight for unweighted source: got:(%v, %t) want:(1, true)", w, ok)
+	}
+
+	u := NewUndirected(simple.NewUndirectedGraph(), nil)
+	if u.Nodes().Len() != 0 || u.Degree(0) != 0 {
+		t.Error("unexpected nodes in empty graph")
+	}
+}
+
+func BenchmarkBreadthFirst(b *testing.B) {
+	benchGraph := simple.NewUndirectedGraph()
+	err := gen.Gnm(benchGraph, 10000, 100000, rand.NewSource(1))
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		name string
+		g    graph.Undirected
+	}{
+		{name: "simple", g: benchGraph},
+		{name: "csr64", g: NewUndirected(benchGraph, nil)},
+		{name: "csr32", g: NewUndirected(benchGraph, &Options{Index32: true})},
+	} {
+		b.Run(test.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var bf traverse.BreadthFirst
+				bf.Walk(test.g, test.g.Node(0), nil)
+			}
+		})
+	}
+}
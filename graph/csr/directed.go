@@ -0,0 +1,110 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csr
+
+import "gonum.org/v1/gonum/graph"
+
+var (
+	dg *Directed
+
+	_ graph.Graph            = dg
+	_ graph.Directed         = dg
+	_ graph.Weighted         = dg
+	_ graph.WeightedDirected = dg
+)
+
+// Directed is an immutable directed graph stored in compressed sparse
+// row form.
+type Directed struct {
+	store
+	from, to *adjacency
+}
+
+// NewDirected returns a CSR representation of g. If g is a graph.Weighted,
+// its edge weights are retained, otherwise edges are given a weight of 1.
+// If opts is nil, 64-bit indices are used, and self and absent edge weights
+// are zero and +Inf respectively.
+func NewDirected(g graph.Directed, opts *Options) *Directed {
+	s, o := newStore(g, opts)
+	var weight func(uid, vid int64) float64
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			return wg.WeightedEdge(uid, vid).Weight()
+		}
+	}
+	var reverse func(uid, vid int64) float64
+	if weight != nil {
+		reverse = func(vid, uid int64) float64 { return weight(uid, vid) }
+	}
+	return &Directed{
+		store: s,
+		from:  s.build(g.From, weight, o.Index32),
+		to:    s.build(g.To, reverse, o.Index32),
+	}
+}
+
+// OutDegree returns the number of edges leaving the node with the given ID.
+func (g *Directed) OutDegree(id int64) int {
+	i, ok := g.indexOf(id)
+	if !ok {
+		return 0
+	}
+	return g.from.degree(i)
+}
+
+// InDegree returns the number of edges entering the node with the given ID.
+func (g *Directed) InDegree(id int64) int {
+	i, ok := g.indexOf(id)
+	if !ok {
+		return 0
+	}
+	return g.to.degree(i)
+}
+
+// From returns all nodes in g that can be reached directly from the node
+// with the given ID, in ascending order of ID.
+func (g *Directed) From(id int64) graph.Nodes { return g.neighbors(g.from, id) }
+
+// To returns all nodes in g that can reach directly to the node with the
+// given ID, in ascending order of ID.
+func (g *Directed) To(id int64) graph.Nodes { return g.neighbors(g.to, id) }
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid without considering direction.
+func (g *Directed) HasEdgeBetween(xid, yid int64) bool {
+	return g.HasEdgeFromTo(xid, yid) || g.HasEdgeFromTo(yid, xid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *Directed) HasEdgeFromTo(uid, vid int64) bool {
+	_, ok := g.edge(g.from, uid, vid)
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *Directed) Edge(uid, vid int64) graph.Edge {
+	return g.WeightedEdge(uid, vid)
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// and nil otherwise. The node v must be directly reachable from u as defined
+// by the From method.
+func (g *Directed) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	e, ok := g.edge(g.from, uid, vid)
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y)
+// returns a non-nil Edge. If x and y are the same node or there is no
+// joining edge between the two nodes the weight value returned is either
+// the graph's absent or self value. Weight returns true if an edge exists
+// between x and y or if x and y have the same ID, false otherwise.
+func (g *Directed) Weight(xid, yid int64) (w float64, ok bool) {
+	return g.weight(g.from, xid, yid)
+}
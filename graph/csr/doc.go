@@ -0,0 +1,11 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package csr provides immutable graphs stored in compressed sparse row form.
+//
+// The graphs in this package are constructed once from an existing graph and
+// may not be altered. In exchange they use a compact, contiguous layout that
+// gives constant time degree queries and fast neighbor iteration, which
+// benefits traversal heavy algorithms.
+package csr // import "gonum.org/v1/gonum/graph/csr"
@@ -0,0 +1,227 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csr
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// The CSR file format is a little-endian encoding of a header
+// followed by a sequence of 8-byte aligned sections.
+//
+// The 64 byte header holds, in order:
+//  magic    [8]byte "gonumcsr"
+//  version  uint32
+//  flags    uint32
+//  nodes    uint64
+//  out      uint64 number of stored out edges
+//  in       uint64 number of stored in edges
+//  self     float64
+//  absent   float64
+//  reserved uint64
+//
+// The sections are:
+//  ids         [nodes]int64  node IDs in ascending order
+//  out offsets [nodes+1]int64
+//  out targets [out]int64    node indices
+//  out weights [out]float64  if weighted
+//  in offsets  [nodes+1]int64 if directed
+//  in targets  [in]int64      if directed
+//  in weights  [in]float64    if directed and weighted
+//
+// Undirected graphs store each edge in both directions in the
+// out sections.
+const (
+	fileMagic   = "gonumcsr"
+	fileVersion = 1
+	headerLen   = 64
+)
+
+const (
+	flagDirected = 1 << iota
+	flagWeighted
+)
+
+var errInvalidFile = errors.New("csr: invalid file")
+
+// WriteGraph writes g to w in the CSR file format used by OpenDirected and
+// OpenUndirected. If g is a graph.Directed, a directed graph is written,
+// otherwise g is written as an undirected graph. If g is a graph.Weighted,
+// its edge weights are retained. Only the Self and Absent fields of opts
+// are used; if opts is nil, self and absent edge weights are zero and +Inf
+// respectively.
+//
+// WriteGraph holds only the node IDs of g in memory and makes multiple
+// passes over the edges of g.
+func WriteGraph(w io.Writer, g graph.Graph, opts *Options) error {
+	if opts == nil {
+		opts = &defaultOptions
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	ids := make([]int64, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	nodes = nil
+
+	var flags uint32
+	directed, isDirected := g.(graph.Directed)
+	if isDirected {
+		flags |= flagDirected
+	}
+	weighted, isWeighted := g.(graph.Weighted)
+	if isWeighted {
+		flags |= flagWeighted
+	}
+
+	fw := &fileWriter{w: bufio.NewWriter(w)}
+	out := rowsOf(ids, g.From)
+	var in *rows
+	if isDirected {
+		in = rowsOf(ids, directed.To)
+	}
+
+	fw.write([]byte(fileMagic))
+	fw.uint32(fileVersion)
+	fw.uint32(flags)
+	fw.uint64(uint64(len(ids)))
+	fw.uint64(uint64(out.edges))
+	if in != nil {
+		fw.uint64(uint64(in.edges))
+	} else {
+		fw.uint64(0)
+	}
+	fw.uint64(math.Float64bits(opts.Self))
+	fw.uint64(math.Float64bits(opts.Absent))
+	fw.uint64(0)
+
+	for _, id := range ids {
+		fw.uint64(uint64(id))
+	}
+	var weight func(uid, vid int64) float64
+	if isWeighted {
+		weight = func(uid, vid int64) float64 {
+			return weighted.WeightedEdge(uid, vid).Weight()
+		}
+	}
+	out.write(fw, weight)
+	if in != nil {
+		var reverse func(vid, uid int64) float64
+		if weight != nil {
+			reverse = func(vid, uid int64) float64 { return weight(uid, vid) }
+		}
+		in.write(fw, reverse)
+	}
+	if fw.err != nil {
+		return fw.err
+	}
+	return fw.w.Flush()
+}
+
+// rows describes the adjacency of a graph for writing.
+type rows struct {
+	ids   []int64
+	to    func(id int64) graph.Nodes
+	edges int
+}
+
+func rowsOf(ids []int64, to func(id int64) graph.Nodes) *rows {
+	r := &rows{ids: ids, to: to}
+	for _, id := range ids {
+		r.edges += to(id).Len()
+	}
+	return r
+}
+
+// row returns the sorted neighbor indices of the node at index i.
+func (r *rows) row(dst []int, i int) []int {
+	dst = dst[:0]
+	it := r.to(r.ids[i])
+	for it.Next() {
+		dst = append(dst, search(r.ids, it.Node().ID()))
+	}
+	sort.Ints(dst)
+	return dst
+}
+
+// write writes the offsets, targets and, if weight is not nil,
+// the weights of r to w.
+func (r *rows) write(w *fileWriter, weight func(uid, vid int64) float64) {
+	var off int
+	w.uint64(0)
+	for _, id := range r.ids {
+		off += r.to(id).Len()
+		w.uint64(uint64(off))
+	}
+	var row []int
+	for i := range r.ids {
+		row = r.row(row, i)
+		for _, j := range row {
+			if j < 0 {
+				w.fail(errors.New("csr: neighbor not in graph"))
+				return
+			}
+			w.uint64(uint64(j))
+		}
+	}
+	if weight == nil {
+		return
+	}
+	for i, uid := range r.ids {
+		row = r.row(row, i)
+		for _, j := range row {
+			w.uint64(math.Float64bits(weight(uid, r.ids[j])))
+		}
+	}
+}
+
+// search returns the index of id in the sorted ids or -1 if it
+// is not present.
+func search(ids []int64, id int64) int {
+	i := sort.Search(len(ids), func(i int) bool { return ids[i] >= id })
+	if i < len(ids) && ids[i] == id {
+		return i
+	}
+	return -1
+}
+
+// fileWriter is a sticky error little-endian writer.
+type fileWriter struct {
+	w   *bufio.Writer
+	buf [8]byte
+	err error
+}
+
+func (w *fileWriter) fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+func (w *fileWriter) write(b []byte) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = w.w.Write(b)
+}
+
+func (w *fileWriter) uint32(v uint32) {
+	binary.LittleEndian.PutUint32(w.buf[:4], v)
+	w.write(w.buf[:4])
+}
+
+func (w *fileWriter) uint64(v uint64) {
+	binary.LittleEndian.PutUint64(w.buf[:], v)
+	w.write(w.buf[:])
+}
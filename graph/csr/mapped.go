@@ -0,0 +1,442 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csr
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var (
+	mdg *MappedDirected
+
+	_ graph.Graph            = mdg
+	_ graph.Directed         = mdg
+	_ graph.Weighted         = mdg
+	_ graph.WeightedDirected = mdg
+
+	mug *MappedUndirected
+
+	_ graph.Graph              = mug
+	_ graph.Undirected         = mug
+	_ graph.Weighted           = mug
+	_ graph.WeightedUndirected = mug
+)
+
+// MappedDirected is a read-only directed graph backed by a memory mapped
+// CSR file written by WriteGraph. Nodes of a MappedDirected are simple.Node.
+type MappedDirected struct {
+	mapped
+	from, to fileAdjacency
+}
+
+// OpenDirected opens the CSR file at path for use as a directed graph. The
+// file must have been written from a graph.Directed. The returned graph must
+// be closed with Close when it is no longer needed.
+func OpenDirected(path string) (*MappedDirected, error) {
+	m, err := openMapped(path)
+	if err != nil {
+		return nil, err
+	}
+	if m.flags&flagDirected == 0 {
+		m.Close()
+		return nil, errors.New("csr: file does not hold a directed graph")
+	}
+	g := &MappedDirected{mapped: m}
+	g.from, g.to = m.out, m.in
+	return g, nil
+}
+
+// OutDegree returns the number of edges leaving the node with the given ID.
+func (g *MappedDirected) OutDegree(id int64) int {
+	i := g.indexOf(id)
+	if i < 0 {
+		return 0
+	}
+	return g.from.degree(i)
+}
+
+// InDegree returns the number of edges entering the node with the given ID.
+func (g *MappedDirected) InDegree(id int64) int {
+	i := g.indexOf(id)
+	if i < 0 {
+		return 0
+	}
+	return g.to.degree(i)
+}
+
+// From returns all nodes in g that can be reached directly from the node
+// with the given ID, in ascending order of ID.
+func (g *MappedDirected) From(id int64) graph.Nodes { return g.neighbors(&g.from, id) }
+
+// To returns all nodes in g that can reach directly to the node with the
+// given ID, in ascending order of ID.
+func (g *MappedDirected) To(id int64) graph.Nodes { return g.neighbors(&g.to, id) }
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid without considering direction.
+func (g *MappedDirected) HasEdgeBetween(xid, yid int64) bool {
+	return g.HasEdgeFromTo(xid, yid) || g.HasEdgeFromTo(yid, xid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v.
+func (g *MappedDirected) HasEdgeFromTo(uid, vid int64) bool {
+	_, ok := g.edge(&g.from, uid, vid)
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *MappedDirected) Edge(uid, vid int64) graph.Edge {
+	return g.WeightedEdge(uid, vid)
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// and nil otherwise. The node v must be directly reachable from u as defined
+// by the From method.
+func (g *MappedDirected) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	e, ok := g.edge(&g.from, uid, vid)
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y)
+// returns a non-nil Edge. If x and y are the same node or there is no
+// joining edge between the two nodes the weight value returned is either
+// the graph's absent or self value. Weight returns true if an edge exists
+// between x and y or if x and y have the same ID, false otherwise.
+func (g *MappedDirected) Weight(xid, yid int64) (w float64, ok bool) {
+	return g.weight(&g.from, xid, yid)
+}
+
+// MappedUndirected is a read-only undirected graph backed by a memory mapped
+// CSR file written by WriteGraph. Nodes of a MappedUndirected are simple.Node.
+type MappedUndirected struct {
+	mapped
+}
+
+// OpenUndirected opens the CSR file at path for use as an undirected graph.
+// The file must have been written from a graph that is not a graph.Directed.
+// The returned graph must be closed with Close when it is no longer needed.
+func OpenUndirected(path string) (*MappedUndirected, error) {
+	m, err := openMapped(path)
+	if err != nil {
+		return nil, err
+	}
+	if m.flags&flagDirected != 0 {
+		m.Close()
+		return nil, errors.New("csr: file does not hold an undirected graph")
+	}
+	return &MappedUndirected{mapped: m}, nil
+}
+
+// Degree returns the number of edges incident to the node with the given ID.
+func (g *MappedUndirected) Degree(id int64) int {
+	i := g.indexOf(id)
+	if i < 0 {
+		return 0
+	}
+	return g.out.degree(i)
+}
+
+// From returns all nodes in g that can be reached directly from the node
+// with the given ID, in ascending order of ID.
+func (g *MappedUndirected) From(id int64) graph.Nodes { return g.neighbors(&g.out, id) }
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid.
+func (g *MappedUndirected) HasEdgeBetween(xid, yid int64) bool {
+	_, ok := g.edge(&g.out, xid, yid)
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *MappedUndirected) Edge(uid, vid int64) graph.Edge {
+	return g.WeightedEdgeBetween(uid, vid)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (g *MappedUndirected) EdgeBetween(xid, yid int64) graph.Edge {
+	return g.WeightedEdgeBetween(xid, yid)
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// and nil otherwise. The node v must be directly reachable from u as defined
+// by the From method.
+func (g *MappedUndirected) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	return g.WeightedEdgeBetween(uid, vid)
+}
+
+// WeightedEdgeBetween returns the weighted edge between nodes x and y.
+func (g *MappedUndirected) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	e, ok := g.edge(&g.out, xid, yid)
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y)
+// returns a non-nil Edge. If x and y are the same node or there is no
+// joining edge between the two nodes the weight value returned is either
+// the graph's absent or self value. Weight returns true if an edge exists
+// between x and y or if x and y have the same ID, false otherwise.
+func (g *MappedUndirected) Weight(xid, yid int64) (w float64, ok bool) {
+	return g.weight(&g.out, xid, yid)
+}
+
+// mapped holds the state common to memory mapped CSR graphs.
+type mapped struct {
+	data   []byte
+	unmap  func([]byte) error
+	flags  uint32
+	n      int
+	ids    section
+	out    fileAdjacency
+	in     fileAdjacency
+	self   float64
+	absent float64
+}
+
+// openMapped maps the file at path and validates its structure.
+func openMapped(path string) (mapped, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return mapped{}, err
+	}
+	m, err := parseMapped(data)
+	if err != nil {
+		unmap(data)
+		return mapped{}, err
+	}
+	m.unmap = unmap
+	return m, nil
+}
+
+// parseMapped returns the graph held in data. Only the header and the
+// section lengths are validated; the contents of the sections are
+// trusted.
+func parseMapped(data []byte) (mapped, error) {
+	if len(data) < headerLen || string(data[:8]) != fileMagic {
+		return mapped{}, errInvalidFile
+	}
+	le := binary.LittleEndian
+	if le.Uint32(data[8:]) != fileVersion {
+		return mapped{}, errors.New("csr: unsupported file version")
+	}
+	m := mapped{
+		data:   data,
+		flags:  le.Uint32(data[12:]),
+		self:   math.Float64frombits(le.Uint64(data[40:])),
+		absent: math.Float64frombits(le.Uint64(data[48:])),
+	}
+	n := le.Uint64(data[16:])
+	nOut := le.Uint64(data[24:])
+	nIn := le.Uint64(data[32:])
+	weighted := m.flags&flagWeighted != 0
+	if max := uint64(len(data)) / 8; n >= max || nOut > max || nIn > max {
+		return mapped{}, errInvalidFile
+	}
+
+	// Check the sections fit in the data before slicing.
+	words := n + n + 1 + nOut
+	if weighted {
+		words += nOut
+	}
+	if m.flags&flagDirected != 0 {
+		words += n + 1 + nIn
+		if weighted {
+			words += nIn
+		}
+	} else if nIn != 0 {
+		return mapped{}, errInvalidFile
+	}
+	if words != uint64(len(data)-headerLen)/8 || (len(data)-headerLen)%8 != 0 {
+		return mapped{}, errInvalidFile
+	}
+	m.n = int(n)
+
+	off := headerLen
+	next := func(words uint64) section {
+		s := section(data[off : off+8*int(words)])
+		off += 8 * int(words)
+		return s
+	}
+	m.ids = next(n)
+	m.out = fileAdjacency{offsets: next(n + 1), targets: next(nOut)}
+	if weighted {
+		m.out.weights = next(nOut)
+	}
+	if m.flags&flagDirected != 0 {
+		m.in = fileAdjacency{offsets: next(n + 1), targets: next(nIn)}
+		if weighted {
+			m.in.weights = next(nIn)
+		}
+	}
+	return m, nil
+}
+
+// Close releases the resources held by the graph. The graph must not be
+// used after Close has been called.
+func (m *mapped) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	err := m.unmap(m.data)
+	*m = mapped{}
+	return err
+}
+
+// indexOf returns the index of the node with the given ID or -1
+// if it does not exist.
+func (m *mapped) indexOf(id int64) int {
+	i := sort.Search(m.n, func(i int) bool { return m.ids.int(i) >= id })
+	if i < m.n && m.ids.int(i) == id {
+		return i
+	}
+	return -1
+}
+
+// Node returns the node with the given ID if it exists in the graph,
+// and nil otherwise.
+func (m *mapped) Node(id int64) graph.Node {
+	if m.indexOf(id) < 0 {
+		return nil
+	}
+	return simple.Node(id)
+}
+
+// Nodes returns all the nodes in the graph in ascending order of ID.
+func (m *mapped) Nodes() graph.Nodes {
+	if m.n == 0 {
+		return graph.Empty
+	}
+	return &fileNodes{ids: m.ids, lo: 0, hi: m.n, curr: -1}
+}
+
+// neighbors returns an iterator over the neighbors of the node with
+// the given ID held in a.
+func (m *mapped) neighbors(a *fileAdjacency, id int64) graph.Nodes {
+	i := m.indexOf(id)
+	if i < 0 {
+		return graph.Empty
+	}
+	lo, hi := a.row(i)
+	if lo == hi {
+		return graph.Empty
+	}
+	return &fileNodes{ids: m.ids, targets: a.targets, lo: lo, hi: hi, curr: lo - 1}
+}
+
+// edge returns the weighted edge from u to v held in a and whether
+// it exists.
+func (m *mapped) edge(a *fileAdjacency, uid, vid int64) (graph.WeightedEdge, bool) {
+	i := m.indexOf(uid)
+	if i < 0 {
+		return nil, false
+	}
+	j := m.indexOf(vid)
+	if j < 0 {
+		return nil, false
+	}
+	k, ok := a.find(i, j)
+	if !ok {
+		return nil, false
+	}
+	return edge{F: simple.Node(uid), T: simple.Node(vid), W: a.weight(k)}, true
+}
+
+// weight returns the weight of the edge from x to y held in a.
+func (m *mapped) weight(a *fileAdjacency, xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		return m.self, true
+	}
+	e, ok := m.edge(a, xid, yid)
+	if !ok {
+		return m.absent, false
+	}
+	return e.Weight(), true
+}
+
+// section is a little-endian encoded sequence of 8 byte values.
+type section []byte
+
+func (s section) int(i int) int64 {
+	return int64(binary.LittleEndian.Uint64(s[8*i:]))
+}
+
+func (s section) float(i int) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(s[8*i:]))
+}
+
+// fileAdjacency is a compressed sparse row adjacency structure
+// held in a CSR file.
+type fileAdjacency struct {
+	offsets, targets, weights section
+}
+
+func (a *fileAdjacency) degree(i int) int {
+	lo, hi := a.row(i)
+	return hi - lo
+}
+
+func (a *fileAdjacency) row(i int) (lo, hi int) {
+	return int(a.offsets.int(i)), int(a.offsets.int(i + 1))
+}
+
+func (a *fileAdjacency) weight(k int) float64 {
+	if a.weights == nil {
+		return 1
+	}
+	return a.weights.float(k)
+}
+
+func (a *fileAdjacency) find(i, j int) (int, bool) {
+	lo, hi := a.row(i)
+	k := lo + sort.Search(hi-lo, func(k int) bool { return a.targets.int(lo+k) >= int64(j) })
+	return k, k < hi && a.targets.int(k) == int64(j)
+}
+
+// fileNodes is an iterator over nodes held in a CSR file. If targets
+// is nil the iterator returns the nodes with IDs in ids[lo:hi],
+// otherwise it returns the nodes indexed by targets[lo:hi].
+type fileNodes struct {
+	ids, targets section
+	lo, hi       int
+	curr         int
+}
+
+func (it *fileNodes) Len() int {
+	return it.hi - it.curr - 1
+}
+
+func (it *fileNodes) Next() bool {
+	if it.curr >= it.hi-1 {
+		it.curr = it.hi
+		return false
+	}
+	it.curr++
+	return true
+}
+
+func (it *fileNodes) Node() graph.Node {
+	if it.curr < it.lo || it.curr >= it.hi {
+		return nil
+	}
+	if it.targets == nil {
+		return simple.Node(it.ids.int(it.curr))
+	}
+	return simple.Node(it.ids.int(int(it.targets.int(it.curr))))
+}
+
+func (it *fileNodes) Reset() { it.curr = it.lo - 1 }
@@ -0,0 +1,182 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csr
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/network"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/testgraph"
+)
+
+// mappedBuilder returns a testgraph.Builder that constructs memory mapped
+// graphs in dir from the graphs constructed by the CSR builder.
+func mappedBuilder(t *testing.T, dir string, directed bool) testgraph.Builder {
+	var files int
+	b := builder(directed, false)
+	return func(nodes []graph.Node, edges []testgraph.WeightedLine, self, absent float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+		g, n, e, s, a, ok := b(nodes, edges, self, absent)
+		if !ok {
+			return nil, nil, nil, math.NaN(), math.NaN(), false
+		}
+		files++
+		name := filepath.Join(dir, fmt.Sprintf("graph-%d.csr", files))
+		m := writeAndOpen(t, name, g, &Options{Self: self, Absent: absent}, directed)
+		return m, n, e, s, a, true
+	}
+}
+
+func writeAndOpen(t *testing.T, name string, g graph.Graph, opts *Options, directed bool) graph.Graph {
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatalf("failed to create file: %v", err)
+	}
+	err = WriteGraph(f, g, opts)
+	if err != nil {
+		t.Fatalf("failed to write graph: %v", err)
+	}
+	err = f.Close()
+	if err != nil {
+		t.Fatalf("failed to close file: %v", err)
+	}
+	if directed {
+		m, err := OpenDirected(name)
+		if err != nil {
+			t.Fatalf("failed to open graph: %v", err)
+		}
+		return m
+	}
+	m, err := OpenUndirected(name)
+	if err != nil {
+		t.Fatalf("failed to open graph: %v", err)
+	}
+	return m
+}
+
+func TestMapped(t *testing.T) {
+	dir, err := ioutil.TempDir("", "csr")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, directed := range []bool{false, true} {
+		b := mappedBuilder(t, dir, directed)
+		t.Run(fmt.Sprintf("directed=%t", directed), func(t *testing.T) {
+			t.Run("EdgeExistence", func(t *testing.T) {
+				testgraph.EdgeExistence(t, b)
+			})
+			t.Run("NodeExistence", func(t *testing.T) {
+				testgraph.NodeExistence(t, b)
+			})
+			t.Run("ReturnAdjacentNodes", func(t *testing.T) {
+				testgraph.ReturnAdjacentNodes(t, b, true)
+			})
+			// ReturnAllNodes is not tested since the
+			// node values of the source graph are not
+			// retained by the file format.
+			t.Run("Weight", func(t *testing.T) {
+				testgraph.Weight(t, b)
+			})
+		})
+	}
+}
+
+func TestMappedAlgorithms(t *testing.T) {
+	dir, err := ioutil.TempDir("", "csr")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	gnm := simple.NewDirectedGraph()
+	err = gen.Gnm(gnm, 100, 500, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	src := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	rnd := rand.New(rand.NewSource(1))
+	edges := gnm.Edges()
+	for edges.Next() {
+		e := edges.Edge()
+		src.SetWeightedEdge(simple.WeightedEdge{F: e.From(), T: e.To(), W: rnd.Float64()})
+	}
+	m := writeAndOpen(t, filepath.Join(dir, "gnm.csr"), src, nil, true).(*MappedDirected)
+	defer m.Close()
+
+	want := path.DijkstraFrom(simple.Node(0), src)
+	got := path.DijkstraFrom(simple.Node(0), m)
+	for id := int64(0); id < 100; id++ {
+		if w, g := want.WeightTo(id), got.WeightTo(id); math.Abs(w-g) > 1e-12 {
+			t.Errorf("unexpected path weight to %d: got:%v want:%v", id, g, w)
+		}
+	}
+
+	wantRank := network.PageRank(src, 0.85, 1e-10)
+	gotRank := network.PageRank(m, 0.85, 1e-10)
+	for id, w := range wantRank {
+		if math.Abs(gotRank[id]-w) > 1e-8 {
+			t.Errorf("unexpected page rank for %d: got:%v want:%v", id, gotRank[id], w)
+		}
+	}
+
+	if err := m.Close(); err != nil {
+		t.Errorf("unexpected error closing graph: %v", err)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "csr")
+	if err != nil {
+		t.Fatalf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	var buf bytes.Buffer
+	err = WriteGraph(&buf, g, nil)
+	if err != nil {
+		t.Fatalf("failed to write graph: %v", err)
+	}
+	valid := buf.Bytes()
+
+	for _, test := range []struct {
+		name     string
+		data     []byte
+		directed bool
+	}{
+		{name: "short", data: valid[:headerLen-1]},
+		{name: "truncated", data: valid[:len(valid)-8]},
+		{name: "magic", data: append([]byte("notcsr!!"), valid[8:]...)},
+		{name: "directedness", data: valid, directed: true},
+	} {
+		name := filepath.Join(dir, test.name)
+		err := ioutil.WriteFile(name, test.data, 0644)
+		if err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		if test.directed {
+			_, err = OpenDirected(name)
+		} else {
+			_, err = OpenUndirected(name)
+		}
+		if err == nil {
+			t.Errorf("expected error for %s file", test.name)
+		}
+	}
+}
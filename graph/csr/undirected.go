@@ -0,0 +1,94 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package csr
+
+import "gonum.org/v1/gonum/graph"
+
+var (
+	ug *Undirected
+
+	_ graph.Graph              = ug
+	_ graph.Undirected         = ug
+	_ graph.Weighted           = ug
+	_ graph.WeightedUndirected = ug
+)
+
+// Undirected is an immutable undirected graph stored in compressed sparse
+// row form.
+type Undirected struct {
+	store
+	adj *adjacency
+}
+
+// NewUndirected returns a CSR representation of g. If g is a graph.Weighted,
+// its edge weights are retained, otherwise edges are given a weight of 1.
+// If opts is nil, 64-bit indices are used, and self and absent edge weights
+// are zero and +Inf respectively.
+func NewUndirected(g graph.Undirected, opts *Options) *Undirected {
+	s, o := newStore(g, opts)
+	var weight func(uid, vid int64) float64
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			return wg.WeightedEdge(uid, vid).Weight()
+		}
+	}
+	return &Undirected{store: s, adj: s.build(g.From, weight, o.Index32)}
+}
+
+// Degree returns the number of edges incident to the node with the given ID.
+func (g *Undirected) Degree(id int64) int {
+	i, ok := g.indexOf(id)
+	if !ok {
+		return 0
+	}
+	return g.adj.degree(i)
+}
+
+// From returns all nodes in g that can be reached directly from the node
+// with the given ID, in ascending order of ID.
+func (g *Undirected) From(id int64) graph.Nodes { return g.neighbors(g.adj, id) }
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid.
+func (g *Undirected) HasEdgeBetween(xid, yid int64) bool {
+	_, ok := g.edge(g.adj, xid, yid)
+	return ok
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil otherwise.
+// The node v must be directly reachable from u as defined by the From method.
+func (g *Undirected) Edge(uid, vid int64) graph.Edge {
+	return g.WeightedEdgeBetween(uid, vid)
+}
+
+// EdgeBetween returns the edge between nodes x and y.
+func (g *Undirected) EdgeBetween(xid, yid int64) graph.Edge {
+	return g.WeightedEdgeBetween(xid, yid)
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// and nil otherwise. The node v must be directly reachable from u as defined
+// by the From method.
+func (g *Undirected) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	return g.WeightedEdgeBetween(uid, vid)
+}
+
+// WeightedEdgeBetween returns the weighted edge between nodes x and y.
+func (g *Undirected) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	e, ok := g.edge(g.adj, xid, yid)
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y)
+// returns a non-nil Edge. If x and y are the same node or there is no
+// joining edge between the two nodes the weight value returned is either
+// the graph's absent or self value. Weight returns true if an edge exists
+// between x and y or if x and y have the same ID, false otherwise.
+func (g *Undirected) Weight(xid, yid int64) (w float64, ok bool) {
+	return g.weight(g.adj, xid, yid)
+}
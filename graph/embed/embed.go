@@ -0,0 +1,37 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Embedding is a set of node vectors.
+type Embedding struct {
+	// Vectors holds the node vectors
+	// with one row for each node.
+	Vectors *mat.Dense
+
+	// Nodes holds the input graph nodes.
+	Nodes []graph.Node
+
+	// Index is a mapping from the graph
+	// node IDs to row indices.
+	Index map[int64]int
+}
+
+// Vector returns the embedding vector for the node with the given ID.
+// If the node is not in the embedding, Vector returns nil.
+func (e Embedding) Vector(id int64) *mat.VecDense {
+	i, ok := e.Index[id]
+	if !ok {
+		return nil
+	}
+	_, c := e.Vectors.Dims()
+	v := mat.NewVecDense(c, nil)
+	v.CopyVec(e.Vectors.RowView(i))
+	return v
+}
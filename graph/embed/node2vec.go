@@ -0,0 +1,338 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Node2Vec holds the parameters for node2vec embedding as described in
+// Grover and Leskovec "node2vec: Scalable Feature Learning for Networks"
+// doi:10.1145/2939672.2939754.
+//
+// Zero values for fields other than Src are replaced by the default values
+// described for each field.
+type Node2Vec struct {
+	// P is the return parameter. It controls the
+	// likelihood of immediately revisiting a node
+	// in a walk. The default is 1.
+	P float64
+	// Q is the in-out parameter. It controls
+	// whether walks are biased toward nodes close
+	// to or far from the previous node. The
+	// default is 1.
+	Q float64
+
+	// WalkLength is the number of nodes in each
+	// walk. The default is 80.
+	WalkLength int
+	// WalksPerNode is the number of walks started
+	// from each node. The default is 10.
+	WalksPerNode int
+
+	// Dimensions is the number of dimensions of
+	// the node vectors. The default is 128.
+	Dimensions int
+	// Window is the skip-gram context window size.
+	// The default is 10.
+	Window int
+	// Negative is the number of negative samples
+	// used for each positive sample. The default
+	// is 5.
+	Negative int
+	// Epochs is the number of training passes over
+	// the walks. The default is 1.
+	Epochs int
+	// Rate is the initial learning rate. The rate
+	// decays linearly to zero during training. The
+	// default is 0.025.
+	Rate float64
+
+	// Src is the random source used for walks,
+	// initialization and sampling. If Src is nil
+	// the global rand source is used.
+	Src rand.Source
+}
+
+func (n Node2Vec) withDefaults() Node2Vec {
+	if n.P == 0 {
+		n.P = 1
+	}
+	if n.Q == 0 {
+		n.Q = 1
+	}
+	if n.WalkLength == 0 {
+		n.WalkLength = 80
+	}
+	if n.WalksPerNode == 0 {
+		n.WalksPerNode = 10
+	}
+	if n.Dimensions == 0 {
+		n.Dimensions = 128
+	}
+	if n.Window == 0 {
+		n.Window = 10
+	}
+	if n.Negative == 0 {
+		n.Negative = 5
+	}
+	if n.Epochs == 0 {
+		n.Epochs = 1
+	}
+	if n.Rate == 0 {
+		n.Rate = 0.025
+	}
+	return n
+}
+
+// Embed returns the node2vec embedding of the nodes in g. If g is a
+// graph.Weighted, edge weights are used to bias the random walks and must
+// be positive. Embed will panic if P or Q is negative.
+func (n Node2Vec) Embed(g graph.Graph) Embedding {
+	n = n.withDefaults()
+	nodes, walks := n.walks(g)
+	indexOf := make(map[int64]int, len(nodes))
+	for i, u := range nodes {
+		indexOf[u.ID()] = i
+	}
+	vectors := skipGram(walks, len(nodes), n)
+	return Embedding{Vectors: vectors, Nodes: nodes, Index: indexOf}
+}
+
+// Walks returns the biased second-order random walks that would be used
+// by Embed for the graph g. Each walk is a sequence of node IDs.
+func (n Node2Vec) Walks(g graph.Graph) [][]int64 {
+	n = n.withDefaults()
+	nodes, walks := n.walks(g)
+	ids := make([][]int64, len(walks))
+	for i, w := range walks {
+		ids[i] = make([]int64, len(w))
+		for j, k := range w {
+			ids[i][j] = nodes[k].ID()
+		}
+	}
+	return ids
+}
+
+// walks returns the graph nodes in ID order and the walks on g
+// expressed as indices into the returned nodes.
+func (n Node2Vec) walks(g graph.Graph) ([]graph.Node, [][]int) {
+	if n.P < 0 || n.Q < 0 {
+		panic("embed: negative node2vec parameter")
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, u := range nodes {
+		indexOf[u.ID()] = i
+	}
+
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+	adj := make([][]int, len(nodes))
+	wts := make([][]float64, len(nodes))
+	for i, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			adj[i] = append(adj[i], indexOf[vid])
+			wts[i] = append(wts[i], weight(uid, vid))
+		}
+	}
+
+	var (
+		rnd  func() float64
+		perm func(int) []int
+	)
+	if n.Src == nil {
+		rnd = rand.Float64
+		perm = rand.Perm
+	} else {
+		r := rand.New(n.Src)
+		rnd = r.Float64
+		perm = r.Perm
+	}
+
+	var buf []float64
+	walks := make([][]int, 0, n.WalksPerNode*len(nodes))
+	for i := 0; i < n.WalksPerNode; i++ {
+		for _, start := range perm(len(nodes)) {
+			walk := make([]int, 1, n.WalkLength)
+			walk[0] = start
+			for len(walk) < n.WalkLength {
+				cur := walk[len(walk)-1]
+				if len(adj[cur]) == 0 {
+					break
+				}
+				buf = buf[:0]
+				if len(walk) == 1 {
+					buf = append(buf, wts[cur]...)
+				} else {
+					prev := walk[len(walk)-2]
+					for k, x := range adj[cur] {
+						w := wts[cur][k]
+						switch {
+						case x == prev:
+							w /= n.P
+						case !isAdjacent(adj[prev], x):
+							w /= n.Q
+						}
+						buf = append(buf, w)
+					}
+				}
+				walk = append(walk, adj[cur][sample(buf, rnd)])
+			}
+			walks = append(walks, walk)
+		}
+	}
+	return nodes, walks
+}
+
+// isAdjacent returns whether x is in the sorted neighbor list adj.
+func isAdjacent(adj []int, x int) bool {
+	i := sort.SearchInts(adj, x)
+	return i < len(adj) && adj[i] == x
+}
+
+// sample returns an index into w sampled with probability proportional
+// to the values in w. The contents of w are altered.
+func sample(w []float64, rnd func() float64) int {
+	floats.CumSum(w, w)
+	r := rnd() * w[len(w)-1]
+	i := sort.SearchFloat64s(w, r)
+	if i == len(w) {
+		i--
+	}
+	return i
+}
+
+// skipGram returns node vectors trained by skip-gram with negative
+// sampling over walks as described in Mikolov et al. "Distributed
+// Representations of Words and Phrases and their Compositionality"
+// arXiv:1310.4546.
+func skipGram(walks [][]int, nodes int, p Node2Vec) *mat.Dense {
+	var (
+		rnd  func() float64
+		intn func(int) int
+	)
+	if p.Src == nil {
+		rnd = rand.Float64
+		intn = rand.Intn
+	} else {
+		r := rand.New(p.Src)
+		rnd = r.Float64
+		intn = r.Intn
+	}
+
+	dim := p.Dimensions
+	in := make([]float64, nodes*dim)
+	for i := range in {
+		in[i] = (rnd() - 0.5) / float64(dim)
+	}
+	out := make([]float64, nodes*dim)
+
+	// Negative samples are drawn from the unigram
+	// distribution raised to the 3/4 power.
+	noise := make([]float64, nodes)
+	for _, w := range walks {
+		for _, u := range w {
+			noise[u]++
+		}
+	}
+	for i, c := range noise {
+		noise[i] = math.Pow(c, 0.75)
+	}
+	floats.CumSum(noise, noise)
+	total := noise[len(noise)-1]
+	negative := func() int {
+		i := sort.SearchFloat64s(noise, rnd()*total)
+		if i == len(noise) {
+			i--
+		}
+		return i
+	}
+
+	var steps int
+	for _, w := range walks {
+		steps += len(w)
+	}
+	steps *= p.Epochs
+
+	grad := make([]float64, dim)
+	var step int
+	for e := 0; e < p.Epochs; e++ {
+		for _, w := range walks {
+			for i, u := range w {
+				rate := p.Rate * (1 - float64(step)/float64(steps+1))
+				if rate < p.Rate*1e-4 {
+					rate = p.Rate * 1e-4
+				}
+				step++
+
+				// Use a reduced window as done by word2vec.
+				b := intn(p.Window)
+				lo := i - p.Window + b
+				if lo < 0 {
+					lo = 0
+				}
+				hi := i + p.Window - b
+				if hi >= len(w) {
+					hi = len(w) - 1
+				}
+				for j := lo; j <= hi; j++ {
+					if j == i {
+						continue
+					}
+					vu := in[w[j]*dim : (w[j]+1)*dim]
+					for k := range grad {
+						grad[k] = 0
+					}
+					for s := 0; s <= p.Negative; s++ {
+						target := u
+						label := 1.0
+						if s != 0 {
+							target = negative()
+							if target == u {
+								continue
+							}
+							label = 0
+						}
+						vt := out[target*dim : (target+1)*dim]
+						g := (label - sigmoid(floats.Dot(vu, vt))) * rate
+						floats.AddScaled(grad, g, vt)
+						floats.AddScaled(vt, g, vu)
+					}
+					floats.Add(vu, grad)
+				}
+			}
+		}
+	}
+	return mat.NewDense(nodes, dim, in)
+}
+
+func sigmoid(x float64) float64 {
+	switch {
+	case x > 6:
+		return 1
+	case x < -6:
+		return 0
+	}
+	return 1 / (1 + math.Exp(-x))
+}
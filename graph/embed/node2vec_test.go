@@ -0,0 +1,113 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+// barbell returns a graph of two complete graphs of order n
+// joined by a single edge.
+func barbell(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for off := 0; off <= n; off += n {
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				g.SetEdge(simple.Edge{F: simple.Node(off + i), T: simple.Node(off + j)})
+			}
+		}
+	}
+	g.SetEdge(simple.Edge{F: simple.Node(n - 1), T: simple.Node(n)})
+	return g
+}
+
+func TestNode2VecWalks(t *testing.T) {
+	g := barbell(5)
+	for _, test := range []struct{ p, q float64 }{
+		{p: 1, q: 1},
+		{p: 0.25, q: 4},
+		{p: 4, q: 0.25},
+	} {
+		n := Node2Vec{P: test.p, Q: test.q, WalkLength: 20, WalksPerNode: 3, Src: rand.NewSource(1)}
+		walks := n.Walks(g)
+		if len(walks) != 3*g.Nodes().Len() {
+			t.Errorf("unexpected number of walks for p=%v q=%v: got:%d want:%d",
+				test.p, test.q, len(walks), 3*g.Nodes().Len())
+		}
+		for _, w := range walks {
+			if len(w) != n.WalkLength {
+				t.Errorf("unexpected walk length for p=%v q=%v: got:%d want:%d",
+					test.p, test.q, len(w), n.WalkLength)
+			}
+			for i := 1; i < len(w); i++ {
+				if !g.HasEdgeBetween(w[i-1], w[i]) {
+					t.Errorf("invalid step in walk for p=%v q=%v: %d -> %d", test.p, test.q, w[i-1], w[i])
+				}
+			}
+		}
+
+		n.Src = rand.NewSource(1)
+		if again := n.Walks(g); !reflect.DeepEqual(walks, again) {
+			t.Errorf("walks not reproducible for p=%v q=%v", test.p, test.q)
+		}
+	}
+}
+
+func TestNode2VecEmbed(t *testing.T) {
+	const n = 6
+	g := barbell(n)
+	e := Node2Vec{
+		WalkLength:   20,
+		WalksPerNode: 20,
+		Dimensions:   8,
+		Window:       3,
+		Epochs:       2,
+		Src:          rand.NewSource(1),
+	}.Embed(g)
+
+	r, c := e.Vectors.Dims()
+	if r != 2*n || c != 8 {
+		t.Fatalf("unexpected embedding dimensions: got:%d×%d want:%d×8", r, c, 2*n)
+	}
+
+	// Nodes within a clique should be more similar than
+	// nodes in different cliques.
+	var within, between float64
+	var nWithin, nBetween int
+	for i := 0; i < 2*n; i++ {
+		for j := i + 1; j < 2*n; j++ {
+			s := cosine(e.Vector(int64(i)), e.Vector(int64(j)))
+			if (i < n) == (j < n) {
+				within += s
+				nWithin++
+			} else {
+				between += s
+				nBetween++
+			}
+		}
+	}
+	within /= float64(nWithin)
+	between /= float64(nBetween)
+	if within <= between {
+		t.Errorf("unexpected clique similarity: within:%v between:%v", within, between)
+	}
+
+	if e.Vector(100) != nil {
+		t.Error("unexpected vector for missing node")
+	}
+}
+
+func cosine(a, b *mat.VecDense) float64 {
+	x := a.RawVector().Data
+	y := b.RawVector().Data
+	return floats.Dot(x, y) / (floats.Norm(x, 2) * floats.Norm(y, 2))
+}
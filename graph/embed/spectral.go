@@ -0,0 +1,220 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LaplacianEigenmap returns the Laplacian eigenmap embedding of the nodes
+// of the undirected graph g into dims dimensions as described in Belkin and
+// Niyogi "Laplacian Eigenmaps for Dimensionality Reduction and Data
+// Representation" doi:10.1162/089976603321780317.
+//
+// The embedding is given by the solutions y of the generalized eigenproblem
+//  L y = λ D y
+// for the dims smallest non-trivial eigenvalues, where L is the graph
+// Laplacian and D is the diagonal matrix of node degrees. If g is a
+// graph.Weighted, edge weights are used in the construction of L and D and
+// must not be negative.
+//
+// The eigenvectors are computed using mat.PartialEigenSym on a sparse
+// representation of the normalized Laplacian, so LaplacianEigenmap is
+// suitable for large graphs. The result is deterministic. The graph should
+// be connected, since the trivial eigenvector is only removed once and
+// repeated eigenvalues may not be fully resolved. Isolated nodes are
+// placed at the origin.
+//
+// LaplacianEigenmap will panic if dims is not less than the number of nodes
+// in g.
+func LaplacianEigenmap(g graph.Undirected, dims int) Embedding {
+	s := newNormAdjacency(g)
+	_, vecs := s.eigenvectors(dims + 1)
+
+	r, _ := vecs.Dims()
+	vectors := mat.NewDense(r, dims, nil)
+	for i := 0; i < r; i++ {
+		if s.invSqrtDeg[i] == 0 {
+			continue
+		}
+		for j := 0; j < dims; j++ {
+			vectors.Set(i, j, vecs.At(i, j+1)*s.invSqrtDeg[i])
+		}
+	}
+	return Embedding{Vectors: vectors, Nodes: s.nodes, Index: s.indexOf}
+}
+
+// Spectral returns the normalized spectral embedding of the nodes of the
+// undirected graph g into dims dimensions as described in Ng, Jordan and
+// Weiss "On Spectral Clustering: Analysis and an algorithm" NIPS 2001.
+//
+// The embedding is given by the eigenvectors of the symmetric normalized
+// Laplacian
+//  I - D^(-1/2) A D^(-1/2)
+// corresponding to the dims smallest eigenvalues, with each row normalized
+// to unit length. The resulting vectors are suitable for direct input to
+// k-means clustering. If g is a graph.Weighted, edge weights are used in
+// the construction of the Laplacian and must not be negative.
+//
+// The eigenvectors are computed using mat.PartialEigenSym on a sparse
+// representation of the normalized Laplacian and the result is
+// deterministic. Isolated nodes are placed at the origin.
+//
+// Spectral will panic if dims is greater than the number of nodes in g.
+func Spectral(g graph.Undirected, dims int) Embedding {
+	s := newNormAdjacency(g)
+	_, vectors := s.eigenvectors(dims)
+
+	r, _ := vectors.Dims()
+	for i := 0; i < r; i++ {
+		row := vectors.RawRowView(i)
+		norm := floats.Norm(row, 2)
+		if norm == 0 || s.invSqrtDeg[i] == 0 {
+			for j := range row {
+				row[j] = 0
+			}
+			continue
+		}
+		floats.Scale(1/norm, row)
+	}
+	return Embedding{Vectors: vectors, Nodes: s.nodes, Index: s.indexOf}
+}
+
+// normAdjacency is the sparse shifted normalized adjacency matrix
+//  I + D^(-1/2) A D^(-1/2)
+// of a graph. The largest eigenvalues of this matrix correspond
+// to the smallest eigenvalues of the normalized Laplacian, and
+// all its eigenvalues are non-negative. normAdjacency is a
+// mat.Operator.
+type normAdjacency struct {
+	nodes   []graph.Node
+	indexOf map[int64]int
+
+	adj        [][]int
+	weights    [][]float64
+	invSqrtDeg []float64
+}
+
+func newNormAdjacency(g graph.Undirected) normAdjacency {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+
+	s := normAdjacency{
+		nodes:      nodes,
+		indexOf:    indexOf,
+		adj:        make([][]int, len(nodes)),
+		weights:    make([][]float64, len(nodes)),
+		invSqrtDeg: make([]float64, len(nodes)),
+	}
+	for i, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		var deg float64
+		for _, v := range to {
+			vid := v.ID()
+			if vid == uid {
+				continue
+			}
+			w := weight(uid, vid)
+			if w < 0 {
+				panic("embed: negative edge weight")
+			}
+			s.adj[i] = append(s.adj[i], indexOf[vid])
+			s.weights[i] = append(s.weights[i], w)
+			deg += w
+		}
+		if deg > 0 {
+			s.invSqrtDeg[i] = 1 / math.Sqrt(deg)
+		}
+	}
+	return s
+}
+
+// Dims returns the dimensions of the matrix.
+func (s normAdjacency) Dims() (r, c int) { return len(s.nodes), len(s.nodes) }
+
+// MulVecTo stores the product of the matrix and x into dst.
+// Since the matrix is symmetric, trans is ignored.
+func (s normAdjacency) MulVecTo(dst *mat.VecDense, _ bool, x mat.Vector) {
+	for i, adj := range s.adj {
+		v := x.AtVec(i)
+		for k, j := range adj {
+			v += s.invSqrtDeg[i] * s.weights[i][k] * s.invSqrtDeg[j] * x.AtVec(j)
+		}
+		dst.SetVec(i, v)
+	}
+}
+
+// eigenvectors returns the k eigenvectors of the normalized
+// Laplacian with the smallest eigenvalues as the columns of
+// the returned matrix, and the corresponding eigenvalues of
+// the Laplacian in ascending order.
+func (s normAdjacency) eigenvectors(k int) ([]float64, *mat.Dense) {
+	n, _ := s.Dims()
+	if k > n {
+		panic("embed: too many dimensions")
+	}
+	m := 2*k + 20
+	if m > n {
+		m = n
+	}
+	var ed mat.PartialEigenSym
+	ok := ed.FactorizeOperator(s, k, mat.EigenLargest, true, &mat.KrylovOptions{
+		SubspaceDim: m,
+		Tolerance:   1e-10,
+		MaxRestarts: 10000,
+		Src:         rand.NewSource(1),
+	})
+	if !ok {
+		panic("embed: eigendecomposition failed")
+	}
+	vals := ed.Values(nil)
+	vecs := ed.VectorsTo(nil)
+	for i, v := range vals {
+		// Undo the shift and convert
+		// to Laplacian eigenvalues.
+		vals[i] = 2 - v
+	}
+
+	// Choose a deterministic sign for each vector.
+	for j := 0; j < k; j++ {
+		var max float64
+		sign := 1.0
+		for i := 0; i < n; i++ {
+			v := vecs.At(i, j)
+			if math.Abs(v) > max+1e-12 {
+				max = math.Abs(v)
+				sign = math.Copysign(1, v)
+			}
+		}
+		if sign < 0 {
+			for i := 0; i < n; i++ {
+				vecs.Set(i, j, -vecs.At(i, j))
+			}
+		}
+	}
+	return vals, vecs
+}
@@ -0,0 +1,89 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package embed
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestNormAdjacencyEigenvectors(t *testing.T) {
+	for _, n := range []int{5, 20, 100} {
+		// The eigenvalues of the normalized Laplacian of
+		// the path graph P_n are 1 - cos(πk/(n-1)).
+		g := simple.NewUndirectedGraph()
+		for i := 1; i < n; i++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i - 1), T: simple.Node(i)})
+		}
+		s := newNormAdjacency(g)
+		const k = 3
+		vals, vecs := s.eigenvectors(k)
+		for i, v := range vals {
+			want := 1 - math.Cos(math.Pi*float64(i)/float64(n-1))
+			if math.Abs(v-want) > 1e-8 {
+				t.Errorf("unexpected eigenvalue %d for P_%d: got:%v want:%v", i, n, v, want)
+			}
+		}
+
+		// Check the eigenvector residuals.
+		y := mat.NewVecDense(n, nil)
+		for j := 0; j < k; j++ {
+			x := vecs.ColView(j)
+			s.MulVecTo(y, false, x)
+			y.AddScaledVec(y, -(2 - vals[j]), x)
+			if r := mat.Norm(y, 2); r > 1e-6 {
+				t.Errorf("unexpected residual for eigenvector %d of P_%d: %v", j, n, r)
+			}
+		}
+	}
+}
+
+func TestLaplacianEigenmap(t *testing.T) {
+	const n = 8
+	g := barbell(n)
+	e := LaplacianEigenmap(g, 2)
+	r, c := e.Vectors.Dims()
+	if r != 2*n || c != 2 {
+		t.Fatalf("unexpected embedding dimensions: got:%d×%d want:%d×2", r, c, 2*n)
+	}
+	// The Fiedler vector separates the two cliques.
+	for i := 0; i < 2*n; i++ {
+		v := e.Vector(int64(i)).AtVec(0)
+		if (i < n) == (e.Vector(0).AtVec(0) < 0) != (v < 0) {
+			t.Errorf("node %d not separated by Fiedler vector: %v", i, v)
+		}
+	}
+
+	again := LaplacianEigenmap(g, 2)
+	if !mat.Equal(e.Vectors, again.Vectors) {
+		t.Error("Laplacian eigenmap is not deterministic")
+	}
+}
+
+func TestSpectral(t *testing.T) {
+	const n = 8
+	g := barbell(n)
+	e := Spectral(g, 2)
+	for i := 0; i < 2*n; i++ {
+		if norm := mat.Norm(e.Vector(int64(i)), 2); math.Abs(norm-1) > 1e-12 {
+			t.Errorf("unexpected norm for node %d: got:%v want:1", i, norm)
+		}
+	}
+	// Nodes in the same clique are close in the embedding.
+	for i := 0; i < 2*n; i++ {
+		for j := i + 1; j < 2*n; j++ {
+			d := mat.Dot(e.Vector(int64(i)), e.Vector(int64(j)))
+			if (i < n) == (j < n) && d < 0.9 {
+				t.Errorf("unexpected similarity within clique for nodes %d and %d: %v", i, j, d)
+			}
+			if (i < n) != (j < n) && d > 0.9 {
+				t.Errorf("unexpected similarity between cliques for nodes %d and %d: %v", i, j, d)
+			}
+		}
+	}
+}
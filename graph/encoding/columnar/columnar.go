@@ -0,0 +1,345 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package columnar
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Strings is a column of strings in the Arrow Utf8 layout.
+type Strings struct {
+	// Offsets holds the start of each string
+	// in Data followed by the end of the last
+	// string. The length of the column is
+	// len(Offsets)-1.
+	Offsets []int32
+
+	// Data holds the bytes of the strings.
+	Data []byte
+
+	// Valid is the validity bitmap of the
+	// column, holding a set bit in least
+	// significant bit order for each non-null
+	// string. If Valid is nil, all strings
+	// are non-null.
+	Valid []byte
+}
+
+// Len returns the number of strings in the column.
+func (c Strings) Len() int {
+	if len(c.Offsets) == 0 {
+		return 0
+	}
+	return len(c.Offsets) - 1
+}
+
+// Value returns the i-th string of the column and whether it is non-null.
+func (c Strings) Value(i int) (s string, ok bool) {
+	if c.Valid != nil && c.Valid[i/8]&(1<<uint(i%8)) == 0 {
+		return "", false
+	}
+	return string(c.Data[c.Offsets[i]:c.Offsets[i+1]]), true
+}
+
+// append adds s to the column, as a null if ok is false.
+func (c *Strings) append(s string, ok bool) {
+	i := c.Len()
+	if len(c.Offsets) == 0 {
+		c.Offsets = append(c.Offsets, 0)
+	}
+	if i%8 == 0 {
+		c.Valid = append(c.Valid, 0)
+	}
+	if ok {
+		c.Data = append(c.Data, s...)
+		c.Valid[i/8] |= 1 << uint(i%8)
+	}
+	c.Offsets = append(c.Offsets, int32(len(c.Data)))
+}
+
+// validate returns an error if the column is not a valid
+// column of n strings.
+func (c Strings) validate(n int) error {
+	if c.Len() != n {
+		return fmt.Errorf("column length mismatch: got:%d want:%d", c.Len(), n)
+	}
+	if c.Valid != nil && len(c.Valid) < (n+7)/8 {
+		return errors.New("short validity bitmap")
+	}
+	for i := 0; i < n; i++ {
+		if c.Offsets[i] < 0 || c.Offsets[i] > c.Offsets[i+1] || int(c.Offsets[i+1]) > len(c.Data) {
+			return fmt.Errorf("invalid offset at %d", i)
+		}
+	}
+	return nil
+}
+
+// Nodes is a node table.
+type Nodes struct {
+	// ID holds the IDs of the nodes.
+	ID []int64
+
+	// Attributes holds the node attribute
+	// columns, keyed by attribute key.
+	Attributes map[string]Strings
+}
+
+// Edges is an edge table.
+type Edges struct {
+	// Directed indicates that the edges
+	// are directed.
+	Directed bool
+
+	// From and To hold the IDs of the end
+	// points of the edges.
+	From, To []int64
+
+	// Weight holds the weights of the edges.
+	// Weight is nil for unweighted edges.
+	Weight []float64
+
+	// Attributes holds the edge attribute
+	// columns, keyed by attribute key.
+	Attributes map[string]Strings
+}
+
+// Tables returns the node and edge tables of g. Nodes are held in order
+// of ID and edges in order of from and then to node ID. The edges of
+// undirected graphs are held once, from the end point with the lower ID.
+// Edge weights are held for graphs that implement graph.Weighted, and
+// attribute columns for the keys of nodes and edges that implement
+// encoding.Attributer. Missing attributes are held as nulls.
+func Tables(g graph.Graph) (Nodes, Edges) {
+	gNodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(gNodes))
+	_, directed := g.(graph.Directed)
+	wg, weighted := g.(graph.Weighted)
+
+	nodes := Nodes{ID: make([]int64, len(gNodes))}
+	var nodeAttrs [][]encoding.Attribute
+	edges := Edges{Directed: directed}
+	var edgeAttrs [][]encoding.Attribute
+	if weighted {
+		edges.Weight = []float64{}
+	}
+	for i, u := range gNodes {
+		uid := u.ID()
+		nodes.ID[i] = uid
+		nodeAttrs = append(nodeAttrs, attributesOf(u))
+
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !directed && vid < uid {
+				continue
+			}
+			edges.From = append(edges.From, uid)
+			edges.To = append(edges.To, vid)
+			if weighted {
+				w, _ := wg.Weight(uid, vid)
+				edges.Weight = append(edges.Weight, w)
+			}
+			edgeAttrs = append(edgeAttrs, attributesOf(g.Edge(uid, vid)))
+		}
+	}
+	nodes.Attributes = columnsOf(nodeAttrs)
+	edges.Attributes = columnsOf(edgeAttrs)
+	return nodes, edges
+}
+
+// attributesOf returns the attributes of v if it is an
+// encoding.Attributer.
+func attributesOf(v interface{}) []encoding.Attribute {
+	a, ok := v.(encoding.Attributer)
+	if !ok {
+		return nil
+	}
+	return a.Attributes()
+}
+
+// columnsOf returns the attribute columns for the rows of attributes
+// in attrs.
+func columnsOf(attrs [][]encoding.Attribute) map[string]Strings {
+	var cols map[string]*Strings
+	for _, row := range attrs {
+		for _, a := range row {
+			if cols == nil {
+				cols = make(map[string]*Strings)
+			}
+			if cols[a.Key] == nil {
+				cols[a.Key] = &Strings{}
+			}
+		}
+	}
+	if cols == nil {
+		return nil
+	}
+	for _, row := range attrs {
+		for k, c := range cols {
+			val, ok := lookup(row, k)
+			c.append(val, ok)
+		}
+	}
+	m := make(map[string]Strings, len(cols))
+	for k, c := range cols {
+		m[k] = *c
+	}
+	return m
+}
+
+// lookup returns the value of the last attribute in attrs with the
+// given key and whether it exists.
+func lookup(attrs []encoding.Attribute, key string) (string, bool) {
+	for i := len(attrs) - 1; i >= 0; i-- {
+		if attrs[i].Key == key {
+			return attrs[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Build adds the nodes and edges of the tables to dst. Nodes are created
+// with dst's MakeNode method if dst implements encoding.NodeMaker, and as
+// simple.Node values otherwise. dst must not hold nodes with IDs that are
+// in the node table. Edge end points must be in the node table.
+//
+// Edges are created with dst's NewWeightedEdge method if dst is a
+// graph.WeightedEdgeAdder and the edges are weighted or dst is not a
+// graph.EdgeAdder, and with NewEdge otherwise. Unweighted edges are given
+// a weight of 1 by NewWeightedEdge. Undirected edges are added in both
+// directions to directed destinations.
+//
+// Non-null attributes are set on nodes and edges that implement
+// encoding.AttributeSetter, in order of attribute key.
+func Build(dst graph.NodeAdder, nodes Nodes, edges Edges) error {
+	nodeKeys, err := keysOf(nodes.Attributes, len(nodes.ID))
+	if err != nil {
+		return fmt.Errorf("columnar: node %v", err)
+	}
+	if len(edges.To) != len(edges.From) {
+		return errors.New("columnar: edge end point length mismatch")
+	}
+	if edges.Weight != nil && len(edges.Weight) != len(edges.From) {
+		return errors.New("columnar: edge weight length mismatch")
+	}
+	edgeKeys, err := keysOf(edges.Attributes, len(edges.From))
+	if err != nil {
+		return fmt.Errorf("columnar: edge %v", err)
+	}
+
+	wdst, isWeighted := dst.(graph.WeightedEdgeAdder)
+	udst, isUnweighted := dst.(graph.EdgeAdder)
+	if len(edges.From) != 0 && !isWeighted && !isUnweighted {
+		return errors.New("columnar: destination cannot add edges")
+	}
+
+	byID := make(map[int64]graph.Node, len(nodes.ID))
+	maker, hasMaker := dst.(encoding.NodeMaker)
+	for i, id := range nodes.ID {
+		if _, exists := byID[id]; exists {
+			return fmt.Errorf("columnar: duplicate node ID: %d", id)
+		}
+		var n graph.Node
+		if hasMaker {
+			n = maker.MakeNode(id)
+		} else {
+			n = simple.Node(id)
+		}
+		err := setAttributes(n, nodeKeys, nodes.Attributes, i)
+		if err != nil {
+			return err
+		}
+		dst.AddNode(n)
+		byID[id] = n
+	}
+
+	useWeighted := isWeighted && (edges.Weight != nil || !isUnweighted)
+	_, reverse := dst.(graph.Directed)
+	reverse = reverse && !edges.Directed
+	setEdge := func(u, v graph.Node, w float64, i int) error {
+		if useWeighted {
+			e := wdst.NewWeightedEdge(u, v, w)
+			err := setAttributes(e, edgeKeys, edges.Attributes, i)
+			if err != nil {
+				return err
+			}
+			wdst.SetWeightedEdge(e)
+			return nil
+		}
+		e := udst.NewEdge(u, v)
+		err := setAttributes(e, edgeKeys, edges.Attributes, i)
+		if err != nil {
+			return err
+		}
+		udst.SetEdge(e)
+		return nil
+	}
+	for i, fid := range edges.From {
+		u, ok := byID[fid]
+		if !ok {
+			return fmt.Errorf("columnar: edge from node not in node table: %d", fid)
+		}
+		v, ok := byID[edges.To[i]]
+		if !ok {
+			return fmt.Errorf("columnar: edge to node not in node table: %d", edges.To[i])
+		}
+		w := 1.0
+		if edges.Weight != nil {
+			w = edges.Weight[i]
+		}
+		err := setEdge(u, v, w, i)
+		if err != nil {
+			return err
+		}
+		if reverse && u.ID() != v.ID() {
+			err = setEdge(v, u, w, i)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keysOf returns the sorted keys of cols after checking that each
+// column is a valid column of n strings.
+func keysOf(cols map[string]Strings, n int) ([]string, error) {
+	keys := make([]string, 0, len(cols))
+	for k, c := range cols {
+		if err := c.validate(n); err != nil {
+			return nil, fmt.Errorf("attribute %q: %v", k, err)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// setAttributes sets the non-null attributes in row i of cols on dst if
+// dst is an encoding.AttributeSetter.
+func setAttributes(dst interface{}, keys []string, cols map[string]Strings, i int) error {
+	s, ok := dst.(encoding.AttributeSetter)
+	if !ok {
+		return nil
+	}
+	for _, k := range keys {
+		v, ok := cols[k].Value(i)
+		if !ok {
+			continue
+		}
+		if err := s.SetAttribute(encoding.Attribute{Key: k, Value: v}); err != nil {
+			return fmt.Errorf("columnar: unable to set attribute %q: %v", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package columnar
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph/internal/attrgraph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestTables(t *testing.T) {
+	g := attrgraph.NewUndirected()
+	a := &attrgraph.Node{NodeID: 5, Attr: attrgraph.Attrs{{Key: "name", Value: "a"}}}
+	b := &attrgraph.Node{NodeID: 2}
+	c := &attrgraph.Node{NodeID: 9, Attr: attrgraph.Attrs{{Key: "colour", Value: "red"}, {Key: "name", Value: "c"}}}
+	g.SetWeightedEdge(&attrgraph.Edge{WeightedEdge: simple.WeightedEdge{F: a, T: b, W: 2}, Attrs: attrgraph.Attrs{{Key: "kind", Value: "x"}}})
+	g.SetWeightedEdge(&attrgraph.Edge{WeightedEdge: simple.WeightedEdge{F: a, T: c, W: 0.5}})
+
+	nodes, edges := Tables(g)
+	if want := []int64{2, 5, 9}; !reflect.DeepEqual(nodes.ID, want) {
+		t.Errorf("unexpected node IDs: got:%v want:%v", nodes.ID, want)
+	}
+	wantName := Strings{Offsets: []int32{0, 0, 1, 2}, Data: []byte("ac"), Valid: []byte{0x6}}
+	if got := nodes.Attributes["name"]; !reflect.DeepEqual(got, wantName) {
+		t.Errorf("unexpected name column: got:%+v want:%+v", got, wantName)
+	}
+	if len(nodes.Attributes) != 2 {
+		t.Errorf("unexpected number of node attribute columns: got:%d want:2", len(nodes.Attributes))
+	}
+	if edges.Directed {
+		t.Error("unexpected directed edge table for undirected graph")
+	}
+	if want := []int64{2, 5}; !reflect.DeepEqual(edges.From, want) {
+		t.Errorf("unexpected from IDs: got:%v want:%v", edges.From, want)
+	}
+	if want := []int64{5, 9}; !reflect.DeepEqual(edges.To, want) {
+		t.Errorf("unexpected to IDs: got:%v want:%v", edges.To, want)
+	}
+	if want := []float64{2, 0.5}; !reflect.DeepEqual(edges.Weight, want) {
+		t.Errorf("unexpected weights: got:%v want:%v", edges.Weight, want)
+	}
+	kind := edges.Attributes["kind"]
+	if v, ok := kind.Value(0); !ok || v != "x" {
+		t.Errorf("unexpected kind for first edge: got:%q,%t want:%q,true", v, ok, "x")
+	}
+	if _, ok := kind.Value(1); ok {
+		t.Error("unexpected kind for second edge")
+	}
+
+	got := attrgraph.NewUndirected()
+	err := Build(got, nodes, edges)
+	if err != nil {
+		t.Fatalf("unexpected error building graph: %v", err)
+	}
+	for _, want := range []*attrgraph.Node{a, b, c} {
+		n, ok := got.Node(want.NodeID).(*attrgraph.Node)
+		if !ok {
+			t.Errorf("missing node %d", want.NodeID)
+			continue
+		}
+		if !reflect.DeepEqual(n.Attr, want.Attr) {
+			t.Errorf("unexpected attributes for node %d: got:%v want:%v", want.NodeID, n.Attr, want.Attr)
+		}
+	}
+	if w, ok := got.Weight(9, 5); !ok || w != 0.5 {
+		t.Errorf("unexpected weight for edge 9--5: got:%v want:0.5", w)
+	}
+	e := got.WeightedEdge(2, 5).(*attrgraph.Edge)
+	want := attrgraph.Attrs{{Key: "kind", Value: "x"}}
+	if !reflect.DeepEqual(e.Attrs, want) {
+		t.Errorf("unexpected edge attributes: got:%v want:%v", e.Attrs, want)
+	}
+
+	d := simple.NewDirectedGraph()
+	err = Build(d, nodes, edges)
+	if err != nil {
+		t.Fatalf("unexpected error building directed graph: %v", err)
+	}
+	if d.Edges().Len() != 4 || !d.HasEdgeFromTo(9, 5) || !d.HasEdgeFromTo(5, 9) {
+		t.Error("undirected edges not added in both directions")
+	}
+}
+
+func TestBuildError(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		nodes Nodes
+		edges Edges
+	}{
+		{
+			name:  "duplicate node",
+			nodes: Nodes{ID: []int64{1, 1}},
+		},
+		{
+			name:  "missing node",
+			nodes: Nodes{ID: []int64{1}},
+			edges: Edges{From: []int64{1}, To: []int64{2}},
+		},
+		{
+			name:  "end point length",
+			nodes: Nodes{ID: []int64{1, 2}},
+			edges: Edges{From: []int64{1}, To: []int64{2, 1}},
+		},
+		{
+			name:  "weight length",
+			nodes: Nodes{ID: []int64{1, 2}},
+			edges: Edges{From: []int64{1}, To: []int64{2}, Weight: []float64{1, 2}},
+		},
+		{
+			name:  "column length",
+			nodes: Nodes{ID: []int64{1, 2}, Attributes: map[string]Strings{"a": {Offsets: []int32{0, 1}, Data: []byte("x")}}},
+		},
+		{
+			name:  "bad offset",
+			nodes: Nodes{ID: []int64{1}, Attributes: map[string]Strings{"a": {Offsets: []int32{0, 2}, Data: []byte("x")}}},
+		},
+	} {
+		err := Build(simple.NewDirectedGraph(), test.nodes, test.edges)
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
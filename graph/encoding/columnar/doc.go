@@ -0,0 +1,18 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package columnar implements conversion between graphs and columnar node
+// and edge tables for interchange with columnar data systems such as
+// Apache Arrow.
+//
+// The columns of the tables use the Arrow columnar memory layout: integer
+// and float columns are slices of the Arrow Int64 and Float64 types and
+// string columns hold the offsets, data and validity bitmap buffers of the
+// Arrow Utf8 type. The column buffers can be wrapped by Arrow array
+// implementations without copying, so that the tables can be shared with
+// Arrow record batches without this package depending on an Arrow library.
+//
+// See https://arrow.apache.org/docs/format/Columnar.html for the Arrow
+// columnar format.
+package columnar // import "gonum.org/v1/gonum/graph/encoding/columnar"
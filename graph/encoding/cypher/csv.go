@@ -0,0 +1,138 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cypher
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// WriteCSV writes the nodes and edges of g to nodes and relationships as
+// CSV files for the neo4j-admin import tool. The node file has an ID
+// column named for the exporter's ID property, a column for each node
+// attribute key and a label column. The relationship file has start, end
+// and type columns, a weight column for weighted graphs and a column for
+// each edge attribute key. Missing attributes are written as empty fields.
+// The edges of undirected graphs are written once as relationships from
+// the end point with the lower ID.
+func (e *Exporter) WriteCSV(nodes, relationships io.Writer, g graph.Graph) error {
+	n, rels, weighted, err := e.export(g)
+	if err != nil {
+		return err
+	}
+
+	var attrs [][]encoding.Attribute
+	for _, v := range n {
+		attrs = append(attrs, v.attrs)
+	}
+	keys, err := keysOf(attrs)
+	if err != nil {
+		return err
+	}
+	header := []string{e.ID + ":ID"}
+	header = append(header, keys...)
+	header = append(header, ":LABEL")
+	w := csv.NewWriter(nodes)
+	err = w.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, v := range n {
+		record := []string{strconv.FormatInt(v.id, 10)}
+		record = append(record, valuesOf(keys, v.attrs)...)
+		record = append(record, strings.Join(append([]string{e.NodeLabel}, v.labels...), ";"))
+		err = w.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	err = w.Error()
+	if err != nil {
+		return err
+	}
+
+	attrs = attrs[:0]
+	for _, r := range rels {
+		attrs = append(attrs, r.attrs)
+	}
+	keys, err = keysOf(attrs)
+	if err != nil {
+		return err
+	}
+	header = []string{":START_ID", ":END_ID", ":TYPE"}
+	if weighted {
+		header = append(header, e.Weight+":double")
+	}
+	header = append(header, keys...)
+	w = csv.NewWriter(relationships)
+	err = w.Write(header)
+	if err != nil {
+		return err
+	}
+	for _, r := range rels {
+		record := []string{strconv.FormatInt(r.from, 10), strconv.FormatInt(r.to, 10), r.typ}
+		if weighted {
+			record = append(record, formatDouble(r.weight))
+		}
+		record = append(record, valuesOf(keys, r.attrs)...)
+		err = w.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// keysOf returns the sorted set of attribute keys in attrs.
+func keysOf(attrs [][]encoding.Attribute) ([]string, error) {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, a := range attrs {
+		for _, attr := range a {
+			if seen[attr.Key] {
+				continue
+			}
+			if strings.Contains(attr.Key, ":") {
+				return nil, fmt.Errorf("cypher: invalid CSV property key: %q", attr.Key)
+			}
+			seen[attr.Key] = true
+			keys = append(keys, attr.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// valuesOf returns the values of attrs for each of keys.
+func valuesOf(keys []string, attrs []encoding.Attribute) []string {
+	values := make([]string, len(keys))
+	for _, a := range attrs {
+		values[sort.SearchStrings(keys, a.Key)] = a.Value
+	}
+	return values
+}
+
+// formatDouble returns v formatted for a neo4j-admin double field.
+func formatDouble(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "Infinity"
+	case math.IsInf(v, -1):
+		return "-Infinity"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,380 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cypher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Labeler is a graph.Node that has Cypher node labels in addition to
+// the label of the exporter.
+type Labeler interface {
+	CypherLabels() []string
+}
+
+// Typer is a graph.Edge that has a Cypher relationship type.
+type Typer interface {
+	CypherType() string
+}
+
+// Exporter exports graphs to Neo4j.
+type Exporter struct {
+	// NodeLabel is the label given to all
+	// exported nodes. It is used to find the
+	// end points of relationships.
+	NodeLabel string
+
+	// ID is the name of the node property
+	// holding the node ID. Node attributes
+	// with the same key are not exported.
+	ID string
+
+	// RelationshipType is the type of
+	// relationships for edges that do not
+	// implement Typer.
+	RelationshipType string
+
+	// Weight is the name of the relationship
+	// property holding the weight of edges of
+	// graph.Weighted graphs. If empty, weights
+	// are not exported. Edge attributes with
+	// the same key are not exported.
+	Weight string
+
+	// BatchSize is the maximum number of
+	// nodes or relationships created by a
+	// single Cypher statement.
+	BatchSize int
+
+	// Merge indicates that Cypher statements
+	// use MERGE rather than CREATE, so that
+	// existing nodes and relationships are
+	// updated.
+	Merge bool
+
+	// Index indicates that a Cypher statement
+	// creating an index on the node ID property
+	// is written before the nodes.
+	Index bool
+}
+
+// NewExporter returns a new Exporter for nodes labeled "Node" with
+// IDs held in the "id" property, relationships of type "EDGE" and weights
+// held in the "weight" property, in batches of 1000.
+func NewExporter() *Exporter {
+	return &Exporter{
+		NodeLabel:        "Node",
+		ID:               "id",
+		RelationshipType: "EDGE",
+		Weight:           "weight",
+		BatchSize:        1000,
+	}
+}
+
+// node is an exported node.
+type node struct {
+	id     int64
+	labels []string
+	attrs  []encoding.Attribute
+}
+
+// relationship is an exported relationship.
+type relationship struct {
+	from, to int64
+	typ      string
+	weight   float64
+	attrs    []encoding.Attribute
+}
+
+// WriteCypher writes Cypher statements creating the nodes and edges of g
+// to w. Statements are terminated by a semicolon. Nodes are created in
+// batches of nodes with the same labels, in order of ID within each batch,
+// followed by the relationships in batches of the same type. The edges of
+// undirected graphs are created once as relationships from the end point
+// with the lower ID.
+func (e *Exporter) WriteCypher(w io.Writer, g graph.Graph) error {
+	if e.BatchSize < 1 {
+		return errors.New("cypher: invalid batch size")
+	}
+	nodes, rels, weighted, err := e.export(g)
+	if err != nil {
+		return err
+	}
+
+	verb := "CREATE"
+	if e.Merge {
+		verb = "MERGE"
+	}
+	label := quoteName(e.NodeLabel)
+	id := quoteName(e.ID)
+
+	bw := bufio.NewWriter(w)
+	if e.Index {
+		fmt.Fprintf(bw, "CREATE INDEX IF NOT EXISTS FOR (n:%s) ON (n.%s);\n", label, id)
+	}
+
+	for _, batch := range batches(len(nodes), e.BatchSize, func(i, j int) bool {
+		return sameStrings(nodes[i].labels, nodes[j].labels)
+	}) {
+		bw.WriteString("UNWIND [\n")
+		for k, n := range nodes[batch[0]:batch[1]] {
+			if k != 0 {
+				bw.WriteString(",\n")
+			}
+			fmt.Fprintf(bw, "\t{id: %d, props: ", n.id)
+			writeMap(bw, n.attrs, "", 0)
+			bw.WriteByte('}')
+		}
+		labels := label
+		for _, l := range nodes[batch[0]].labels {
+			labels += ":" + quoteName(l)
+		}
+		fmt.Fprintf(bw, "\n] AS row\n%s (n:%s {%s: row.id})\nSET n += row.props;\n", verb, labels, id)
+	}
+
+	for _, batch := range batches(len(rels), e.BatchSize, func(i, j int) bool {
+		return rels[i].typ == rels[j].typ
+	}) {
+		bw.WriteString("UNWIND [\n")
+		for k, r := range rels[batch[0]:batch[1]] {
+			if k != 0 {
+				bw.WriteString(",\n")
+			}
+			fmt.Fprintf(bw, "\t{from: %d, to: %d, props: ", r.from, r.to)
+			weight := ""
+			if weighted {
+				weight = e.Weight
+			}
+			writeMap(bw, r.attrs, weight, r.weight)
+			bw.WriteByte('}')
+		}
+		fmt.Fprintf(bw, "\n] AS row\nMATCH (a:%[1]s {%[2]s: row.from}), (b:%[1]s {%[2]s: row.to})\n%[3]s (a)-[r:%[4]s]->(b)\nSET r += row.props;\n",
+			label, id, verb, quoteName(rels[batch[0]].typ))
+	}
+	return bw.Flush()
+}
+
+// export returns the nodes and relationships of g in export order and
+// whether relationships have weights.
+func (e *Exporter) export(g graph.Graph) (nodes []node, rels []relationship, weighted bool, err error) {
+	if e.NodeLabel == "" || e.ID == "" || e.RelationshipType == "" {
+		return nil, nil, false, errors.New("cypher: empty node label, ID or relationship type")
+	}
+
+	gNodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(gNodes))
+	_, directed := g.(graph.Directed)
+	wg, weighted := g.(graph.Weighted)
+	weighted = weighted && e.Weight != ""
+
+	for _, n := range gNodes {
+		exp := node{id: n.ID()}
+		if l, ok := n.(Labeler); ok {
+			exp.labels = l.CypherLabels()
+		}
+		if a, ok := n.(encoding.Attributer); ok {
+			exp.attrs = without(a.Attributes(), e.ID)
+		}
+		nodes = append(nodes, exp)
+	}
+
+	for _, u := range gNodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !directed && vid < uid {
+				continue
+			}
+			edge := g.Edge(uid, vid)
+			r := relationship{from: uid, to: vid, typ: e.RelationshipType}
+			if t, ok := edge.(Typer); ok {
+				r.typ = t.CypherType()
+				if r.typ == "" {
+					return nil, nil, false, errors.New("cypher: empty relationship type")
+				}
+			}
+			if weighted {
+				r.weight, _ = wg.Weight(uid, vid)
+			}
+			if a, ok := edge.(encoding.Attributer); ok {
+				attrs := a.Attributes()
+				if weighted {
+					attrs = without(attrs, e.Weight)
+				}
+				r.attrs = attrs
+			}
+			rels = append(rels, r)
+		}
+	}
+
+	// Group nodes with the same labels and relationships
+	// of the same type so they can be batched, retaining
+	// the order within each group.
+	groups := make(map[string]int)
+	nodeGroup := func(n node) int {
+		key := strings.Join(n.labels, "\x00")
+		i, ok := groups[key]
+		if !ok {
+			i = len(groups)
+			groups[key] = i
+		}
+		return i
+	}
+	for _, n := range nodes {
+		nodeGroup(n)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodeGroup(nodes[i]) < nodeGroup(nodes[j])
+	})
+	first := make(map[string]int)
+	for i, r := range rels {
+		if _, ok := first[r.typ]; !ok {
+			first[r.typ] = i
+		}
+	}
+	sort.SliceStable(rels, func(i, j int) bool {
+		return first[rels[i].typ] < first[rels[j].typ]
+	})
+
+	return nodes, rels, weighted, nil
+}
+
+// without returns attrs without attributes with the given key.
+func without(attrs []encoding.Attribute, key string) []encoding.Attribute {
+	var n []encoding.Attribute
+	for _, a := range attrs {
+		if a.Key != key {
+			n = append(n, a)
+		}
+	}
+	return n
+}
+
+// batches returns the half-open index ranges of runs of at most size
+// consecutive items that are the same according to same.
+func batches(n, size int, same func(i, j int) bool) [][2]int {
+	var b [][2]int
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && j-i < size && same(i, j) {
+			j++
+		}
+		b = append(b, [2]int{i, j})
+		i = j
+	}
+	return b
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeMap writes a Cypher map literal holding attrs as string
+// properties to bw. If weight is not empty, a float property with
+// the value w is added with that key.
+func writeMap(bw *bufio.Writer, attrs []encoding.Attribute, weight string, w float64) {
+	bw.WriteByte('{')
+	for i, a := range attrs {
+		if i != 0 {
+			bw.WriteString(", ")
+		}
+		bw.WriteString(quoteName(a.Key))
+		bw.WriteString(": ")
+		bw.WriteString(quoteString(a.Value))
+	}
+	if weight != "" {
+		if len(attrs) != 0 {
+			bw.WriteString(", ")
+		}
+		bw.WriteString(quoteName(weight))
+		bw.WriteString(": ")
+		bw.WriteString(formatFloat(w))
+	}
+	bw.WriteByte('}')
+}
+
+// quoteName returns the Cypher symbolic name for s, quoting it with
+// backticks if it is not a valid identifier.
+func quoteName(s string) string {
+	valid := s != ""
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			valid = false
+			break
+		}
+	}
+	if valid {
+		return s
+	}
+	return "`" + strings.Replace(s, "`", "``", -1) + "`"
+}
+
+// quoteString returns s as a Cypher string literal.
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if unicode.IsControl(r) {
+				fmt.Fprintf(&b, `\u%04X`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// formatFloat returns v as a Cypher float expression.
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "toFloat('Infinity')"
+	case math.IsInf(v, -1):
+		return "toFloat('-Infinity')"
+	case math.IsNaN(v):
+		return "toFloat('NaN')"
+	}
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	s = strings.Replace(s, "e+", "e", 1)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
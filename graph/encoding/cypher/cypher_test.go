@@ -0,0 +1,186 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cypher
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+type labeledNode struct {
+	id     int64
+	labels []string
+	attrs  []encoding.Attribute
+}
+
+func (n labeledNode) ID() int64                        { return n.id }
+func (n labeledNode) CypherLabels() []string           { return n.labels }
+func (n labeledNode) Attributes() []encoding.Attribute { return n.attrs }
+
+type typedEdge struct {
+	simple.WeightedEdge
+	typ   string
+	attrs []encoding.Attribute
+}
+
+func (e typedEdge) CypherType() string               { return e.typ }
+func (e typedEdge) Attributes() []encoding.Attribute { return e.attrs }
+
+func propertyGraph() *simple.WeightedDirectedGraph {
+	g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	alice := labeledNode{id: 0, labels: []string{"Person"}, attrs: []encoding.Attribute{{Key: "name", Value: "Alice"}, {Key: "id", Value: "ignored"}}}
+	acme := labeledNode{id: 1, labels: []string{"Company"}, attrs: []encoding.Attribute{{Key: "name", Value: "Acme's \"Widgets\""}}}
+	bob := labeledNode{id: 2, labels: []string{"Person"}, attrs: []encoding.Attribute{{Key: "name", Value: "Bob"}, {Key: "start date", Value: "2019"}}}
+	g.SetWeightedEdge(typedEdge{WeightedEdge: simple.WeightedEdge{F: alice, T: acme, W: 3}, typ: "WORKS_AT"})
+	g.SetWeightedEdge(typedEdge{WeightedEdge: simple.WeightedEdge{F: bob, T: acme, W: 0.5}, typ: "WORKS_AT", attrs: []encoding.Attribute{{Key: "role", Value: "line\nbreak"}}})
+	g.SetWeightedEdge(simple.WeightedEdge{F: alice, T: bob, W: 1e21})
+	return g
+}
+
+const wantCypher = `UNWIND [
+	{id: 0, props: {name: 'Alice'}},
+	{id: 2, props: {name: 'Bob', ` + "`start date`" + `: '2019'}}
+] AS row
+CREATE (n:Node:Person {id: row.id})
+SET n += row.props;
+UNWIND [
+	{id: 1, props: {name: 'Acme\'s "Widgets"'}}
+] AS row
+CREATE (n:Node:Company {id: row.id})
+SET n += row.props;
+UNWIND [
+	{from: 0, to: 1, props: {weight: 3.0}},
+	{from: 2, to: 1, props: {role: 'line\nbreak', weight: 0.5}}
+] AS row
+MATCH (a:Node {id: row.from}), (b:Node {id: row.to})
+CREATE (a)-[r:WORKS_AT]->(b)
+SET r += row.props;
+UNWIND [
+	{from: 0, to: 2, props: {weight: 1e21}}
+] AS row
+MATCH (a:Node {id: row.from}), (b:Node {id: row.to})
+CREATE (a)-[r:EDGE]->(b)
+SET r += row.props;
+`
+
+func TestWriteCypher(t *testing.T) {
+	e := NewExporter()
+	e.BatchSize = 2
+	var buf bytes.Buffer
+	err := e.WriteCypher(&buf, propertyGraph())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != wantCypher {
+		t.Errorf("unexpected Cypher:\ngot:\n%s\nwant:\n%s", &buf, wantCypher)
+	}
+}
+
+const wantMergeCypher = `CREATE INDEX IF NOT EXISTS FOR (n:` + "`Graph Node`" + `) ON (n.uid);
+UNWIND [
+	{id: 0, props: {}},
+	{id: 1, props: {}},
+	{id: 2, props: {}}
+] AS row
+MERGE (n:` + "`Graph Node`" + ` {uid: row.id})
+SET n += row.props;
+UNWIND [
+	{from: 0, to: 1, props: {}},
+	{from: 0, to: 2, props: {}}
+] AS row
+MATCH (a:` + "`Graph Node`" + ` {uid: row.from}), (b:` + "`Graph Node`" + ` {uid: row.to})
+MERGE (a)-[r:LINK]->(b)
+SET r += row.props;
+`
+
+func TestWriteCypherMerge(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2)})
+
+	e := NewExporter()
+	e.NodeLabel = "Graph Node"
+	e.ID = "uid"
+	e.RelationshipType = "LINK"
+	e.Merge = true
+	e.Index = true
+	var buf bytes.Buffer
+	err := e.WriteCypher(&buf, g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != wantMergeCypher {
+		t.Errorf("unexpected Cypher:\ngot:\n%s\nwant:\n%s", &buf, wantMergeCypher)
+	}
+}
+
+const (
+	wantNodesCSV = `id:ID,name,start date,:LABEL
+0,Alice,,Node;Person
+2,Bob,2019,Node;Person
+1,"Acme's ""Widgets""",,Node;Company
+`
+	wantRelationshipsCSV = `:START_ID,:END_ID,:TYPE,weight:double,role
+0,1,WORKS_AT,3,
+2,1,WORKS_AT,0.5,"line
+break"
+0,2,EDGE,1e+21,
+`
+)
+
+func TestWriteCSV(t *testing.T) {
+	var nodes, rels bytes.Buffer
+	err := NewExporter().WriteCSV(&nodes, &rels, propertyGraph())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nodes.String() != wantNodesCSV {
+		t.Errorf("unexpected node CSV:\ngot:\n%s\nwant:\n%s", &nodes, wantNodesCSV)
+	}
+	if rels.String() != wantRelationshipsCSV {
+		t.Errorf("unexpected relationship CSV:\ngot:\n%s\nwant:\n%s", &rels, wantRelationshipsCSV)
+	}
+}
+
+func TestExportError(t *testing.T) {
+	g := propertyGraph()
+	e := NewExporter()
+	e.BatchSize = 0
+	var buf bytes.Buffer
+	if err := e.WriteCypher(&buf, g); err == nil {
+		t.Error("expected error for zero batch size")
+	}
+	e = NewExporter()
+	e.NodeLabel = ""
+	if err := e.WriteCypher(&buf, g); err == nil {
+		t.Error("expected error for empty node label")
+	}
+	g.AddNode(labeledNode{id: 3, attrs: []encoding.Attribute{{Key: "a:b", Value: "c"}}})
+	if err := NewExporter().WriteCSV(&buf, &buf, g); err == nil {
+		t.Error("expected error for CSV property key with colon")
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	for _, test := range []struct {
+		v    float64
+		want string
+	}{
+		{v: 0, want: "0.0"},
+		{v: -2, want: "-2.0"},
+		{v: 1.25, want: "1.25"},
+		{v: 1e-7, want: "1e-07"},
+		{v: math.Inf(1), want: "toFloat('Infinity')"},
+		{v: math.NaN(), want: "toFloat('NaN')"},
+	} {
+		if got := formatFloat(test.v); got != test.want {
+			t.Errorf("unexpected formatting of %v: got:%s want:%s", test.v, got, test.want)
+		}
+	}
+}
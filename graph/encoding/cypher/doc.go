@@ -0,0 +1,17 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cypher implements export of graphs to the Neo4j graph database,
+// either as batched Cypher statements or as CSV files for the neo4j-admin
+// import tool.
+//
+// Nodes are exported with their ID held in a node property and with the
+// labels of the exporter and of the node. Edges are exported as
+// relationships between the nodes with matching ID properties. Node and
+// edge attributes are exported as string properties and edge weights of
+// weighted graphs as float properties.
+//
+// See https://neo4j.com/docs/cypher-manual/current/ for the Cypher query
+// language.
+package cypher // import "gonum.org/v1/gonum/graph/encoding/cypher"
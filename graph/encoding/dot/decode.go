@@ -6,7 +6,6 @@ package dot
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"gonum.org/v1/gonum/graph"
@@ -40,11 +39,55 @@ type PortSetter interface {
 	SetToPort(port, compass string) error
 }
 
+// StructureBuilder is implemented by graph values that can retain the
+// subgraph structure of a DOT graph during unmarshaling.
+type StructureBuilder interface {
+	// NewSubgraph returns a new subgraph of the
+	// receiver to hold the nodes and edges of a
+	// DOT subgraph.
+	NewSubgraph() SubgraphBuilder
+}
+
+// SubgraphBuilder is a DOT subgraph being unmarshaled. The nodes and edges
+// of a subgraph are created by the destination graph and are added to the
+// subgraph and to each of its enclosing subgraphs. A SubgraphBuilder may
+// implement DOTIDSetter, AttributeSetters and StructureBuilder to retain
+// the ID, attributes and nested subgraphs of the DOT subgraph.
+type SubgraphBuilder interface {
+	AddNode(graph.Node)
+	SetEdge(graph.Edge)
+}
+
+// MultiStructureBuilder is implemented by multigraph values that can retain
+// the subgraph structure of a DOT graph during unmarshaling.
+type MultiStructureBuilder interface {
+	// NewSubgraph returns a new subgraph of the
+	// receiver to hold the nodes and lines of a
+	// DOT subgraph.
+	NewSubgraph() MultiSubgraphBuilder
+}
+
+// MultiSubgraphBuilder is a DOT subgraph being unmarshaled as a multigraph.
+// The nodes and lines of a subgraph are created by the destination graph
+// and are added to the subgraph and to each of its enclosing subgraphs.
+// A MultiSubgraphBuilder may implement DOTIDSetter, AttributeSetters and
+// MultiStructureBuilder to retain the ID, attributes and nested subgraphs
+// of the DOT subgraph.
+type MultiSubgraphBuilder interface {
+	AddNode(graph.Node)
+	SetLine(graph.Line)
+}
+
 // Unmarshal parses the Graphviz DOT-encoded data and stores the result in dst.
 // If the number of graphs encoded in data is not one, an error is returned and
 // dst will hold the first graph in data.
 //
 // Attributes and IDs are unquoted during unmarshalling if appropriate.
+//
+// If dst implements StructureBuilder, the subgraphs of the DOT graph are
+// retained as subgraphs of dst, and subgraph attribute statements are
+// applied to the subgraph holding them. Otherwise subgraphs are flattened
+// into dst.
 func Unmarshal(data []byte, dst encoding.Builder) error {
 	file, err := dot.ParseBytes(data)
 	if err != nil {
@@ -63,6 +106,11 @@ func Unmarshal(data []byte, dst encoding.Builder) error {
 // dst will hold the first graph in data.
 //
 // Attributes and IDs are unquoted during unmarshalling if appropriate.
+//
+// If dst implements MultiStructureBuilder, the subgraphs of the DOT graph
+// are retained as subgraphs of dst, and subgraph attribute statements are
+// applied to the subgraph holding them. Otherwise subgraphs are flattened
+// into dst.
 func UnmarshalMulti(data []byte, dst encoding.MultiBuilder) error {
 	file, err := dot.ParseBytes(data)
 	if err != nil {
@@ -89,8 +137,9 @@ func copyGraph(dst encoding.Builder, src *ast.Graph) (err error) {
 	}()
 	gen := &simpleGraph{
 		generator: generator{
-			directed: src.Directed,
-			ids:      make(map[string]graph.Node),
+			directed:    src.Directed,
+			ids:         make(map[string]graph.Node),
+			newSubgraph: structureOf(dst),
 		},
 	}
 	if dst, ok := dst.(DOTIDSetter); ok {
@@ -119,8 +168,9 @@ func copyMultigraph(dst encoding.MultiBuilder, src *ast.Graph) (err error) {
 	}()
 	gen := &multiGraph{
 		generator: generator{
-			directed: src.Directed,
-			ids:      make(map[string]graph.Node),
+			directed:    src.Directed,
+			ids:         make(map[string]graph.Node),
+			newSubgraph: multiStructureOf(dst),
 		},
 	}
 	if dst, ok := dst.(DOTIDSetter); ok {
@@ -150,20 +200,29 @@ type generator struct {
 	subStart []int
 	// graphAttr, nodeAttr and edgeAttr are global graph attributes.
 	graphAttr, nodeAttr, edgeAttr encoding.AttributeSetter
+	// newSubgraph returns a new retained subgraph of the destination
+	// graph, or is nil if the destination does not retain subgraphs.
+	newSubgraph func(id string) *subgraph
+	// Stack of the DOT subgraphs enclosing the active context. Elements
+	// are nil for subgraphs that are not retained.
+	subgraphs []*subgraph
 }
 
 // node returns the gonum node corresponding to the given dot AST node ID,
 // generating a new such node if none exist.
 func (gen *generator) node(dst graph.NodeAdder, id string) graph.Node {
-	if n, ok := gen.ids[id]; ok {
+	n, ok := gen.ids[id]
+	if ok {
+		gen.addSubgraphNode(n)
 		return n
 	}
-	n := dst.NewNode()
+	n = dst.NewNode()
 	if n, ok := n.(DOTIDSetter); ok {
 		n.SetDOTID(unquoteID(id))
 	}
 	dst.AddNode(n)
 	gen.ids[id] = n
+	gen.addSubgraphNode(n)
 	// Check if within the context of a subgraph, that is to be used as a vertex
 	// of an edge.
 	if gen.isInSubgraph() {
@@ -174,6 +233,163 @@ func (gen *generator) node(dst graph.NodeAdder, id string) graph.Node {
 	return n
 }
 
+// subgraph is a retained DOT subgraph.
+type subgraph struct {
+	addNode func(graph.Node)
+	setEdge func(basicEdge)
+	nodes   set.Int64s
+
+	// newSubgraph returns a new nested subgraph, or is
+	// nil if the subgraph does not retain subgraphs.
+	newSubgraph func(id string) *subgraph
+
+	graphAttr, nodeAttr, edgeAttr encoding.AttributeSetter
+}
+
+// structureOf returns a function that returns new subgraphs of g, or nil
+// if g is not a StructureBuilder.
+func structureOf(g interface{}) func(id string) *subgraph {
+	b, ok := g.(StructureBuilder)
+	if !ok {
+		return nil
+	}
+	return func(id string) *subgraph {
+		s := b.NewSubgraph()
+		return newSubgraph(s, id,
+			func(n graph.Node) { s.AddNode(n) },
+			func(e basicEdge) { s.SetEdge(e.(graph.Edge)) },
+			structureOf(s),
+		)
+	}
+}
+
+// multiStructureOf returns a function that returns new subgraphs of g, or
+// nil if g is not a MultiStructureBuilder.
+func multiStructureOf(g interface{}) func(id string) *subgraph {
+	b, ok := g.(MultiStructureBuilder)
+	if !ok {
+		return nil
+	}
+	return func(id string) *subgraph {
+		s := b.NewSubgraph()
+		return newSubgraph(s, id,
+			func(n graph.Node) { s.AddNode(n) },
+			func(e basicEdge) { s.SetLine(e.(graph.Line)) },
+			multiStructureOf(s),
+		)
+	}
+}
+
+// newSubgraph returns a retained subgraph for the subgraph builder b with the
+// given DOT ID.
+func newSubgraph(b interface{}, id string, addNode func(graph.Node), setEdge func(basicEdge), sub func(string) *subgraph) *subgraph {
+	if s, ok := b.(DOTIDSetter); ok && id != "" {
+		s.SetDOTID(unquoteID(id))
+	}
+	s := &subgraph{
+		addNode:     addNode,
+		setEdge:     setEdge,
+		nodes:       make(set.Int64s),
+		newSubgraph: sub,
+	}
+	if a, ok := b.(AttributeSetters); ok {
+		s.graphAttr, s.nodeAttr, s.edgeAttr = a.DOTAttributeSetters()
+	}
+	return s
+}
+
+// openSubgraph opens the DOT subgraph with the given ID within the active
+// context.
+func (gen *generator) openSubgraph(id string) {
+	newSubgraph := gen.newSubgraph
+	if n := len(gen.subgraphs); n != 0 {
+		newSubgraph = nil
+		if parent := gen.subgraphs[n-1]; parent != nil {
+			newSubgraph = parent.newSubgraph
+		}
+	}
+	var s *subgraph
+	if newSubgraph != nil {
+		s = newSubgraph(id)
+	}
+	gen.subgraphs = append(gen.subgraphs, s)
+}
+
+// closeSubgraph closes the active DOT subgraph.
+func (gen *generator) closeSubgraph() {
+	gen.subgraphs = gen.subgraphs[:len(gen.subgraphs)-1]
+}
+
+// addSubgraphNode adds n to each retained subgraph enclosing the active
+// context.
+func (gen *generator) addSubgraphNode(n graph.Node) {
+	for _, s := range gen.subgraphs {
+		if s != nil && !s.nodes.Has(n.ID()) {
+			s.nodes.Add(n.ID())
+			s.addNode(n)
+		}
+	}
+}
+
+// setSubgraphEdge sets e in each retained subgraph enclosing the active
+// context.
+func (gen *generator) setSubgraphEdge(e basicEdge) {
+	if len(gen.subgraphs) == 0 {
+		return
+	}
+	gen.addSubgraphNode(e.From())
+	gen.addSubgraphNode(e.To())
+	for _, s := range gen.subgraphs {
+		if s != nil {
+			s.setEdge(e)
+		}
+	}
+}
+
+// setAttributes applies the given global attributes for the component kind,
+// "graph", "node" or "edge", to the innermost retained subgraph enclosing the
+// active context, or to the destination graph if there is none.
+func (gen *generator) setAttributes(kind string, attrs []encoding.Attribute) {
+	graphAttr, nodeAttr, edgeAttr := gen.graphAttr, gen.nodeAttr, gen.edgeAttr
+	for i := len(gen.subgraphs) - 1; i >= 0; i-- {
+		if s := gen.subgraphs[i]; s != nil {
+			graphAttr, nodeAttr, edgeAttr = s.graphAttr, s.nodeAttr, s.edgeAttr
+			break
+		}
+	}
+	var n encoding.AttributeSetter
+	switch kind {
+	case "graph":
+		n = graphAttr
+	case "node":
+		n = nodeAttr
+	case "edge":
+		n = edgeAttr
+	default:
+		panic("unreachable")
+	}
+	if n == nil {
+		return
+	}
+	for _, a := range attrs {
+		if err := n.SetAttribute(a); err != nil {
+			panic(fmt.Errorf("unable to unmarshal global %s DOT attribute (%s=%s): %v", kind, a.Key, a.Value, err))
+		}
+	}
+}
+
+// attrsOf returns the unquoted attributes of the given AST attributes.
+func attrsOf(attrs []*ast.Attr) []encoding.Attribute {
+	a := make([]encoding.Attribute, len(attrs))
+	for i, attr := range attrs {
+		a[i] = encoding.Attribute{
+			Key:   unquoteID(attr.Key),
+			Value: unquoteID(attr.Val),
+		}
+	}
+	return a
+}
+
 type simpleGraph struct{ generator }
 
 // addStmt adds the given statement to the graph.
@@ -196,45 +412,15 @@ func (gen *simpleGraph) addStmt(dst encoding.Builder, stmt ast.Stmt) {
 	case *ast.EdgeStmt:
 		gen.addEdgeStmt(dst, stmt)
 	case *ast.AttrStmt:
-		var n encoding.AttributeSetter
-		var dst string
-		switch stmt.Kind {
-		case ast.GraphKind:
-			if gen.graphAttr == nil {
-				return
-			}
-			n = gen.graphAttr
-			dst = "graph"
-		case ast.NodeKind:
-			if gen.nodeAttr == nil {
-				return
-			}
-			n = gen.nodeAttr
-			dst = "node"
-		case ast.EdgeKind:
-			if gen.edgeAttr == nil {
-				return
-			}
-			n = gen.edgeAttr
-			dst = "edge"
-		default:
-			panic("unreachable")
-		}
-		for _, attr := range stmt.Attrs {
-			a := encoding.Attribute{
-				Key:   unquoteID(attr.Key),
-				Value: unquoteID(attr.Val),
-			}
-			if err := n.SetAttribute(a); err != nil {
-				panic(fmt.Errorf("unable to unmarshal global %s DOT attribute (%s=%s): %v", dst, a.Key, a.Value, err))
-			}
-		}
+		gen.setAttributes(stmt.Kind.String(), attrsOf(stmt.Attrs))
 	case *ast.Attr:
-		// ignore.
+		gen.setAttributes("graph", attrsOf([]*ast.Attr{stmt}))
 	case *ast.Subgraph:
+		gen.openSubgraph(stmt.ID)
 		for _, stmt := range stmt.Stmts {
 			gen.addStmt(dst, stmt)
 		}
+		gen.closeSubgraph()
 	default:
 		panic(fmt.Sprintf("unknown statement type %T", stmt))
 	}
@@ -281,6 +467,7 @@ func (gen *simpleGraph) addEdgeStmt(dst encoding.Builder, stmt *ast.EdgeStmt) {
 			dst.SetEdge(edge)
 			applyPortsToEdge(stmt.From, stmt.To, edge)
 			addEdgeAttrs(edge, stmt.Attrs)
+			gen.setSubgraphEdge(edge)
 		}
 	}
 }
@@ -293,9 +480,11 @@ func (gen *simpleGraph) addVertex(dst encoding.Builder, v ast.Vertex) []graph.No
 		return []graph.Node{n}
 	case *ast.Subgraph:
 		gen.pushSubgraph()
+		gen.openSubgraph(v.ID)
 		for _, stmt := range v.Stmts {
 			gen.addStmt(dst, stmt)
 		}
+		gen.closeSubgraph()
 		return gen.popSubgraph()
 	default:
 		panic(fmt.Sprintf("unknown vertex type %T", v))
@@ -316,6 +505,7 @@ func (gen *simpleGraph) addEdge(dst encoding.Builder, to *ast.Edge, attrs []*ast
 				dst.SetEdge(edge)
 				applyPortsToEdge(to.Vertex, to.To, edge)
 				addEdgeAttrs(edge, attrs)
+				gen.setSubgraphEdge(edge)
 			}
 		}
 	}
@@ -395,45 +585,15 @@ func (gen *multiGraph) addStmt(dst encoding.MultiBuilder, stmt ast.Stmt) {
 	case *ast.EdgeStmt:
 		gen.addEdgeStmt(dst, stmt)
 	case *ast.AttrStmt:
-		var n encoding.AttributeSetter
-		var dst string
-		switch stmt.Kind {
-		case ast.GraphKind:
-			if gen.graphAttr == nil {
-				return
-			}
-			n = gen.graphAttr
-			dst = "graph"
-		case ast.NodeKind:
-			if gen.nodeAttr == nil {
-				return
-			}
-			n = gen.nodeAttr
-			dst = "node"
-		case ast.EdgeKind:
-			if gen.edgeAttr == nil {
-				return
-			}
-			n = gen.edgeAttr
-			dst = "edge"
-		default:
-			panic("unreachable")
-		}
-		for _, attr := range stmt.Attrs {
-			a := encoding.Attribute{
-				Key:   unquoteID(attr.Key),
-				Value: unquoteID(attr.Val),
-			}
-			if err := n.SetAttribute(a); err != nil {
-				panic(fmt.Errorf("unable to unmarshal global %s DOT attribute (%s=%s): %v", dst, a.Key, a.Value, err))
-			}
-		}
+		gen.setAttributes(stmt.Kind.String(), attrsOf(stmt.Attrs))
 	case *ast.Attr:
-		// ignore.
+		gen.setAttributes("graph", attrsOf([]*ast.Attr{stmt}))
 	case *ast.Subgraph:
+		gen.openSubgraph(stmt.ID)
 		for _, stmt := range stmt.Stmts {
 			gen.addStmt(dst, stmt)
 		}
+		gen.closeSubgraph()
 	default:
 		panic(fmt.Sprintf("unknown statement type %T", stmt))
 	}
@@ -449,6 +609,7 @@ func (gen *multiGraph) addEdgeStmt(dst encoding.MultiBuilder, stmt *ast.EdgeStmt
 			dst.SetLine(edge)
 			applyPortsToEdge(stmt.From, stmt.To, edge)
 			addEdgeAttrs(edge, stmt.Attrs)
+			gen.setSubgraphEdge(edge)
 		}
 	}
 }
@@ -461,9 +622,11 @@ func (gen *multiGraph) addVertex(dst encoding.MultiBuilder, v ast.Vertex) []grap
 		return []graph.Node{n}
 	case *ast.Subgraph:
 		gen.pushSubgraph()
+		gen.openSubgraph(v.ID)
 		for _, stmt := range v.Stmts {
 			gen.addStmt(dst, stmt)
 		}
+		gen.closeSubgraph()
 		return gen.popSubgraph()
 	default:
 		panic(fmt.Sprintf("unknown vertex type %T", v))
@@ -484,6 +647,7 @@ func (gen *multiGraph) addLine(dst encoding.MultiBuilder, to *ast.Edge, attrs []
 				dst.SetLine(edge)
 				applyPortsToEdge(to.Vertex, to.To, edge)
 				addEdgeAttrs(edge, attrs)
+				gen.setSubgraphEdge(edge)
 			}
 		}
 	}
@@ -509,6 +673,10 @@ func addEdgeAttrs(edge basicEdge, attrs []*ast.Attr) {
 
 // unquoteID unquotes the given string if needed in the context of an ID. If s
 // is not already quoted the original string is returned.
+//
+// In DOT quoted strings the escaped double quote and backslash are unescaped.
+// All other escape sequences, including those used by Graphviz escape strings
+// and record labels, are retained.
 func unquoteID(s string) string {
 	// To make round-trips idempotent, don't unquote quoted HTML-like strings
 	//
@@ -516,12 +684,21 @@ func unquoteID(s string) string {
 	if len(s) >= 4 && strings.HasPrefix(s, `"<`) && strings.HasSuffix(s, `>"`) {
 		return s
 	}
-	// Unquote quoted string if possible.
-	if t, err := strconv.Unquote(s); err == nil {
-		return t
+	if !isQuotedID(s) {
+		return s
 	}
-	// On error, either s is not quoted or s is quoted but contains invalid
-	// characters, in both cases we return the original string rather than
-	// panicking.
-	return s
+	s = s[1 : len(s)-1]
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && (s[i+1] == '\\' || s[i+1] == '"') {
+			i++
+			c = s[i]
+		}
+		buf.WriteByte(c)
+	}
+	return buf.String()
 }
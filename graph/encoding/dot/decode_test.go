@@ -6,6 +6,7 @@ package dot
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"gonum.org/v1/gonum/graph"
@@ -51,6 +52,10 @@ func TestRoundTrip(t *testing.T) {
 			want:     undirectedAttrs,
 			directed: false,
 		},
+		{
+			want:     directedRecordsAndHTML,
+			directed: true,
+		},
 	}
 	for i, g := range golden {
 		var dst encoding.Builder
@@ -213,6 +218,56 @@ const undirectedAttrs = `strict graph {
 	A -- B [label="hello world"];
 }`
 
+const directedRecordsAndHTML = `strict digraph {
+	// Node definitions.
+	A [label="{<f0> left|<f1> mid\ dle|<f2> right\l}"];
+	B [label=<<table><tr><td port="p1">x</td></tr></table>>];
+	C [label="line\nbreak \"quoted\""];
+	D [label="<a>b<c>"];
+
+	// Edge definitions.
+	A:f0:n -> B:p1:s;
+	A:"n" -> C:sw;
+	B -> D:"e";
+}`
+
+func TestRecordsAndHTMLLabels(t *testing.T) {
+	dst := newDotDirectedGraph()
+	err := Unmarshal([]byte(directedRecordsAndHTML), dst)
+	if err != nil {
+		t.Fatalf("unable to unmarshal DOT graph: %v", err)
+	}
+	wantLabels := map[string]string{
+		"A": `{<f0> left|<f1> mid\ dle|<f2> right\l}`,
+		"B": `<<table><tr><td port="p1">x</td></tr></table>>`,
+		"C": `line\nbreak "quoted"`,
+		"D": `"<a>b<c>"`,
+	}
+	ids := make(map[string]int64)
+	for _, n := range graph.NodesOf(dst.Nodes()) {
+		n := n.(*dotNode)
+		ids[n.dotID] = n.ID()
+		if n.Label != wantLabels[n.dotID] {
+			t.Errorf("unexpected label for %s: got:%q want:%q", n.dotID, n.Label, wantLabels[n.dotID])
+		}
+	}
+	wantPorts := []struct {
+		from, to   string
+		fromP, toP dotPortLabels
+	}{
+		{from: "A", to: "B", fromP: dotPortLabels{"f0", "n"}, toP: dotPortLabels{"p1", "s"}},
+		{from: "A", to: "C", fromP: dotPortLabels{"n", ""}, toP: dotPortLabels{"", "sw"}},
+		{from: "B", to: "D", toP: dotPortLabels{"e", ""}},
+	}
+	for _, w := range wantPorts {
+		e := dst.Edge(ids[w.from], ids[w.to]).(*dotEdge)
+		if e.FromPortLabels != w.fromP || e.ToPortLabels != w.toP {
+			t.Errorf("unexpected ports for %s->%s: got:%+v %+v want:%+v %+v",
+				w.from, w.to, e.FromPortLabels, e.ToPortLabels, w.fromP, w.toP)
+		}
+	}
+}
+
 func TestChainedEdgeAttributes(t *testing.T) {
 	golden := []struct {
 		in, want string
@@ -644,3 +699,206 @@ func (a *attributes) SetAttribute(attr encoding.Attribute) error {
 	*a = append(*a, attr)
 	return nil
 }
+
+// dotStructuredGraph is a dotDirectedGraph that retains its
+// subgraph structure.
+type dotStructuredGraph struct {
+	*dotDirectedGraph
+	sub []*dotStructuredGraph
+}
+
+func newDotStructuredGraph() *dotStructuredGraph {
+	return &dotStructuredGraph{dotDirectedGraph: newDotDirectedGraph()}
+}
+
+// NewSubgraph implements the dot.StructureBuilder interface.
+func (g *dotStructuredGraph) NewSubgraph() SubgraphBuilder {
+	s := newDotStructuredGraph()
+	g.sub = append(g.sub, s)
+	return s
+}
+
+// Structure implements the dot.Structurer interface.
+func (g *dotStructuredGraph) Structure() []Graph {
+	s := make([]Graph, len(g.sub))
+	for i, sub := range g.sub {
+		s[i] = sub
+	}
+	return s
+}
+
+// dotStructuredMultigraph is a multi.DirectedGraph that retains
+// its subgraph structure.
+type dotStructuredMultigraph struct {
+	*multi.DirectedGraph
+	id  string
+	sub []*dotStructuredMultigraph
+}
+
+func newDotStructuredMultigraph() *dotStructuredMultigraph {
+	return &dotStructuredMultigraph{DirectedGraph: multi.NewDirectedGraph()}
+}
+
+// NewSubgraph implements the dot.MultiStructureBuilder interface.
+func (g *dotStructuredMultigraph) NewSubgraph() MultiSubgraphBuilder {
+	s := newDotStructuredMultigraph()
+	g.sub = append(g.sub, s)
+	return s
+}
+
+// Structure implements the dot.MultiStructurer interface.
+func (g *dotStructuredMultigraph) Structure() []Multigraph {
+	s := make([]Multigraph, len(g.sub))
+	for i, sub := range g.sub {
+		s[i] = sub
+	}
+	return s
+}
+
+func (g *dotStructuredMultigraph) SetDOTID(id string) { g.id = id }
+func (g *dotStructuredMultigraph) DOTID() string      { return g.id }
+
+const nestedClusters = `strict digraph {
+	graph [
+		label=root
+	];
+
+	subgraph cluster_outer {
+		graph [
+			label=outer
+			color=blue
+		];
+		node [
+			shape=box
+		];
+
+		subgraph cluster_inner {
+			graph [
+				label=inner
+			];
+
+			// Node definitions.
+			A [label="first node"];
+			B;
+
+			// Edge definitions.
+			A -> B;
+		}
+		// Node definitions.
+		A [label="first node"];
+		B;
+		C;
+
+		// Edge definitions.
+		B -> C;
+	}
+	// Node definitions.
+	A [label="first node"];
+	B;
+	C;
+	D;
+
+	// Edge definitions.
+	C -> D;
+}`
+
+func TestNestedClusterRoundTrip(t *testing.T) {
+	decoders := []struct {
+		name   string
+		decode func(data string, dst encoding.Builder) error
+	}{
+		{name: "Unmarshal", decode: func(data string, dst encoding.Builder) error {
+			return Unmarshal([]byte(data), dst)
+		}},
+		{name: "Decoder", decode: func(data string, dst encoding.Builder) error {
+			return NewDecoder(strings.NewReader(data)).Decode(dst)
+		}},
+	}
+	for _, d := range decoders {
+		dst := newDotStructuredGraph()
+		err := d.decode(nestedClusters, dst)
+		if err != nil {
+			t.Errorf("%s: unable to unmarshal DOT graph: %v", d.name, err)
+			continue
+		}
+
+		if len(dst.sub) != 1 {
+			t.Fatalf("%s: unexpected number of subgraphs: got:%d want:1", d.name, len(dst.sub))
+		}
+		outer := dst.sub[0]
+		if outer.id != "cluster_outer" || len(outer.sub) != 1 {
+			t.Fatalf("%s: unexpected outer subgraph: id=%q with %d subgraphs", d.name, outer.id, len(outer.sub))
+		}
+		inner := outer.sub[0]
+		if inner.id != "cluster_inner" {
+			t.Errorf("%s: unexpected inner subgraph ID: got:%q want:%q", d.name, inner.id, "cluster_inner")
+		}
+		for _, test := range []struct {
+			g            *dotStructuredGraph
+			nodes, edges int
+		}{
+			{g: dst, nodes: 4, edges: 3},
+			{g: outer, nodes: 3, edges: 2},
+			{g: inner, nodes: 2, edges: 1},
+		} {
+			if n := test.g.Nodes().Len(); n != test.nodes {
+				t.Errorf("%s: unexpected number of nodes in %q: got:%d want:%d", d.name, test.g.id, n, test.nodes)
+			}
+			if n := test.g.Edges().Len(); n != test.edges {
+				t.Errorf("%s: unexpected number of edges in %q: got:%d want:%d", d.name, test.g.id, n, test.edges)
+			}
+		}
+		if dst.graph.Attributes()[0].Value != "root" || inner.graph.Attributes()[0].Value != "inner" {
+			t.Errorf("%s: subgraph attributes not retained in their subgraphs", d.name)
+		}
+
+		buf, err := Marshal(dst, "", "", "\t")
+		if err != nil {
+			t.Errorf("%s: unable to marshal graph: %v", d.name, err)
+			continue
+		}
+		if string(buf) != nestedClusters {
+			t.Errorf("%s: graph content mismatch; want:\n%s\n\ngot:\n%s", d.name, nestedClusters, buf)
+		}
+	}
+}
+
+const multigraphCluster = `digraph {
+	subgraph S {
+		// Node definitions.
+		0;
+		1;
+
+		// Edge definitions.
+		0 -> 1;
+		0 -> 1;
+	}
+	// Node definitions.
+	0;
+	1;
+	2;
+
+	// Edge definitions.
+	1 -> 2;
+}`
+
+func TestMultigraphClusterRoundTrip(t *testing.T) {
+	dst := newDotStructuredMultigraph()
+	err := UnmarshalMulti([]byte(multigraphCluster), dst)
+	if err != nil {
+		t.Fatalf("unable to unmarshal DOT graph: %v", err)
+	}
+	if len(dst.sub) != 1 || dst.sub[0].id != "S" {
+		t.Fatalf("unexpected subgraph structure: %v", dst.sub)
+	}
+	if n := dst.sub[0].Lines(0, 1).Len(); n != 2 {
+		t.Errorf("unexpected number of lines in subgraph: got:%d want:2", n)
+	}
+	buf, err := MarshalMulti(dst, "", "", "\t")
+	if err != nil {
+		t.Fatalf("unable to marshal graph: %v", err)
+	}
+	if string(buf) != multigraphCluster {
+		t.Errorf("graph content mismatch; want:\n%s\n\ngot:\n%s", multigraphCluster, buf)
+	}
+}
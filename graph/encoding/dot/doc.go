@@ -18,4 +18,18 @@
 // so the data is kept in raw form. As an exception, quoted text with a leading
 // `"<` and a trailing `>"` is not unquoted to ensure preservation of the string
 // during a round-trip.
+//
+// In quoted strings, double quotes are escaped, as are backslashes that
+// precede a backslash or a double quote or that end the string. Other
+// backslash escapes, such as the Graphviz escape strings \n, \l and \N, and
+// the escaped field separators of record labels, are retained unaltered.
+// HTML-like labels (label=<...>) are written without quoting when their angle
+// brackets are balanced.
+//
+// Edge ports
+//
+// Edge end point ports and compass points are marshaled from graph.Edge
+// values that implement Porter and unmarshaled into values that implement
+// PortSetter. Ports that have the name of a compass point are quoted so that
+// they are not read as a compass point.
 package dot // import "gonum.org/v1/gonum/graph/encoding/dot"
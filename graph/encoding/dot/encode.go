@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 
 	"gonum.org/v1/gonum/graph"
@@ -53,11 +52,15 @@ type Porter interface {
 }
 
 // Structurer represents a graph.Graph that can define subgraphs.
+// Subgraphs may themselves be Structurers, and edges held by a subgraph
+// are not repeated in the enclosing graph.
 type Structurer interface {
 	Structure() []Graph
 }
 
 // MultiStructurer represents a graph.Multigraph that can define subgraphs.
+// Subgraphs may themselves be MultiStructurers, and lines held by a
+// subgraph are not repeated in the enclosing graph.
 type MultiStructurer interface {
 	Structure() []Multigraph
 }
@@ -157,6 +160,9 @@ func (p *simpleGraphPrinter) print(g graph.Graph, name string, needsIndent, isSu
 	if a, ok := g.(Attributers); ok {
 		p.writeAttributeComplex(a)
 	}
+	// Edges declared in the subgraph structure
+	// are not repeated in g.
+	var structure []graph.Graph
 	if s, ok := g.(Structurer); ok {
 		for _, g := range s.Structure() {
 			_, subIsDirected := g.(graph.Directed)
@@ -164,7 +170,11 @@ func (p *simpleGraphPrinter) print(g graph.Graph, name string, needsIndent, isSu
 				return errors.New("dot: mismatched graph type")
 			}
 			p.buf.WriteByte('\n')
-			p.print(g, g.DOTID(), true, true)
+			err := p.print(g, g.DOTID(), true, true)
+			if err != nil {
+				return err
+			}
+			structure = append(structure, g)
 		}
 	}
 
@@ -212,6 +222,9 @@ func (p *simpleGraphPrinter) print(g graph.Graph, name string, needsIndent, isSu
 		sort.Sort(ordered.ByID(to))
 		for _, t := range to {
 			tid := t.ID()
+			if hasEdge(structure, nid, tid) {
+				continue
+			}
 			if isDirected {
 				if p.visited[edge{inGraph: name, from: nid, to: tid}] {
 					continue
@@ -248,9 +261,13 @@ func (p *simpleGraphPrinter) print(g graph.Graph, name string, needsIndent, isSu
 			porter, edgeIsPorter := e.(Porter)
 			if edgeIsPorter {
 				if e.From().ID() == nid {
-					p.writePorts(porter.FromPort())
+					if err := p.writePorts(porter.FromPort()); err != nil {
+						return err
+					}
 				} else {
-					p.writePorts(porter.ToPort())
+					if err := p.writePorts(porter.ToPort()); err != nil {
+						return err
+					}
 				}
 			}
 
@@ -272,9 +289,13 @@ func (p *simpleGraphPrinter) print(g graph.Graph, name string, needsIndent, isSu
 			}
 			if edgeIsPorter {
 				if e.From().ID() == nid {
-					p.writePorts(porter.ToPort())
+					if err := p.writePorts(porter.ToPort()); err != nil {
+						return err
+					}
 				} else {
-					p.writePorts(porter.FromPort())
+					if err := p.writePorts(porter.FromPort()); err != nil {
+						return err
+					}
 				}
 			}
 
@@ -291,6 +312,31 @@ func (p *simpleGraphPrinter) print(g graph.Graph, name string, needsIndent, isSu
 	return nil
 }
 
+// hasEdge returns whether any of the graphs in structure has the edge
+// from uid to vid.
+func hasEdge(structure []graph.Graph, uid, vid int64) bool {
+	for _, g := range structure {
+		if g.Edge(uid, vid) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLine returns whether any of the multigraphs in structure has the
+// line with the given ID from uid to vid.
+func hasLine(structure []graph.Multigraph, uid, vid, id int64) bool {
+	for _, g := range structure {
+		lines := g.Lines(uid, vid)
+		for lines.Next() {
+			if lines.Line().ID() == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (p *printer) printFrontMatter(name string, needsIndent, isSubgraph, isDirected, isStrict bool) error {
 	p.buf.WriteString(p.prefix)
 	if needsIndent {
@@ -323,15 +369,25 @@ func (p *printer) writeNode(n graph.Node) {
 	p.buf.WriteString(quoteID(nodeID(n)))
 }
 
-func (p *printer) writePorts(port, cp string) {
+func (p *printer) writePorts(port, cp string) error {
+	if cp != "" && !isCompassPoint(cp) {
+		return fmt.Errorf("dot: invalid compass point: %q", cp)
+	}
 	if port != "" {
 		p.buf.WriteByte(':')
-		p.buf.WriteString(quoteID(port))
+		if isCompassPoint(port) {
+			// Quote ports named as compass points so
+			// they are not read back as a compass point.
+			p.buf.WriteString(quote(port))
+		} else {
+			p.buf.WriteString(quoteID(port))
+		}
 	}
 	if cp != "" {
 		p.buf.WriteByte(':')
 		p.buf.WriteString(cp)
 	}
+	return nil
 }
 
 func nodeID(n graph.Node) string {
@@ -451,6 +507,9 @@ func (p *multiGraphPrinter) print(g graph.Multigraph, name string, needsIndent,
 	if a, ok := g.(Attributers); ok {
 		p.writeAttributeComplex(a)
 	}
+	// Lines declared in the subgraph structure
+	// are not repeated in g.
+	var structure []graph.Multigraph
 	if s, ok := g.(MultiStructurer); ok {
 		for _, g := range s.Structure() {
 			_, subIsDirected := g.(graph.Directed)
@@ -458,7 +517,11 @@ func (p *multiGraphPrinter) print(g graph.Multigraph, name string, needsIndent,
 				return errors.New("dot: mismatched graph type")
 			}
 			p.buf.WriteByte('\n')
-			p.print(g, g.DOTID(), true, true)
+			err := p.print(g, g.DOTID(), true, true)
+			if err != nil {
+				return err
+			}
+			structure = append(structure, g)
 		}
 	}
 
@@ -513,6 +576,9 @@ func (p *multiGraphPrinter) print(g graph.Multigraph, name string, needsIndent,
 
 			for _, l := range lines {
 				lid := l.ID()
+				if hasLine(structure, nid, tid, lid) {
+					continue
+				}
 				if p.visited[line{inGraph: name, id: lid}] {
 					continue
 				}
@@ -541,9 +607,13 @@ func (p *multiGraphPrinter) print(g graph.Multigraph, name string, needsIndent,
 				porter, edgeIsPorter := l.(Porter)
 				if edgeIsPorter {
 					if l.From().ID() == nid {
-						p.writePorts(porter.FromPort())
+						if err := p.writePorts(porter.FromPort()); err != nil {
+							return err
+						}
 					} else {
-						p.writePorts(porter.ToPort())
+						if err := p.writePorts(porter.ToPort()); err != nil {
+							return err
+						}
 					}
 				}
 
@@ -565,9 +635,13 @@ func (p *multiGraphPrinter) print(g graph.Multigraph, name string, needsIndent,
 				}
 				if edgeIsPorter {
 					if l.From().ID() == nid {
-						p.writePorts(porter.ToPort())
+						if err := p.writePorts(porter.ToPort()); err != nil {
+							return err
+						}
 					} else {
-						p.writePorts(porter.FromPort())
+						if err := p.writePorts(porter.FromPort()); err != nil {
+							return err
+						}
 					}
 				}
 
@@ -591,16 +665,51 @@ func (p *multiGraphPrinter) print(g graph.Multigraph, name string, needsIndent,
 func quoteID(s string) string {
 	// To use a keyword as an ID, it must be quoted.
 	if isKeyword(s) {
-		return strconv.Quote(s)
+		return quote(s)
 	}
 	// Quote if s is not an ID. This includes strings containing spaces, except
 	// if those spaces are used within HTML string IDs (e.g. <foo >).
 	if !isID(s) {
-		return strconv.Quote(s)
+		return quote(s)
 	}
 	return s
 }
 
+// quote returns s as a DOT double-quoted string. Double quotes are escaped,
+// as are backslashes that are followed by a backslash or a double quote or
+// that end s. All other backslashes, including those used in Graphviz escape
+// strings and record labels, are written unaltered. quote is the inverse of
+// unquoteID.
+func quote(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			if i+1 == len(s) || s[i+1] == '\\' || s[i+1] == '"' {
+				buf.WriteString(`\\`)
+				continue
+			}
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// isCompassPoint reports whether the given string is a DOT compass point.
+func isCompassPoint(s string) bool {
+	switch s {
+	case "n", "ne", "e", "se", "s", "sw", "w", "nw", "c", "_":
+		return true
+	}
+	return false
+}
+
 // isKeyword reports whether the given string is a keyword in the DOT language.
 func isKeyword(s string) bool {
 	// ref: https://www.graphviz.org/doc/info/lang.html
@@ -640,18 +749,48 @@ func isID(s string) bool {
 		return true
 	}
 	// 3. double-quote string ID.
-	if len(s) >= 2 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
-		// Check that escape sequences within the double-quotes are valid.
-		if _, err := strconv.Unquote(s); err == nil {
-			return true
-		}
+	if isQuotedID(s) {
+		return true
 	}
 	// 4. HTML ID.
 	return isHTMLID(s)
 }
 
+// isQuotedID reports whether the given string is a double-quoted string ID.
+func isQuotedID(s string) bool {
+	if len(s) < 2 || s[0] != '"' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			// Skip the escaped character.
+			i++
+		case '"':
+			return i == len(s)-1
+		}
+	}
+	return false
+}
+
 // isHTMLID reports whether the given string an HTML ID.
 func isHTMLID(s string) bool {
-	// HTML IDs have the format /^<.*>$/
-	return len(s) >= 2 && strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">")
+	// HTML IDs have the format /^<.*>$/ where the
+	// angle brackets of the content are balanced.
+	if len(s) < 2 || s[0] != '<' || s[len(s)-1] != '>' {
+		return false
+	}
+	var depth int
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+			if depth == 0 {
+				return i == len(s)-1
+			}
+		}
+	}
+	return false
 }
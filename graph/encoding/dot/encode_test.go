@@ -1487,6 +1487,58 @@ func TestEncode(t *testing.T) {
 	}
 }
 
+var quoteTests = []string{
+	"a b",
+	`{<f0> left|<f1> mid\ dle|<f2> right\l}`,
+	`line\nbreak`,
+	"line\nbreak",
+	`say "hello"`,
+	`C:\dir\file`,
+	`abc\`,
+	`a\"b`,
+	`a\\b`,
+	"graph",
+	"<a>b<c>",
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	for _, s := range quoteTests {
+		q := quoteID(s)
+		if !isID(q) {
+			t.Errorf("quoted %q is not a valid ID: %s", s, q)
+		}
+		want := s
+		if s == "<a>b<c>" {
+			// HTML-like quoted strings are not unquoted.
+			want = q
+		}
+		got := unquoteID(q)
+		if got != want {
+			t.Errorf("unexpected round trip of %q via %s: got:%q", s, q, got)
+		}
+
+		dst := newDotUndirectedGraph()
+		err := Unmarshal([]byte("graph { a [label="+q+"] }"), dst)
+		if err != nil {
+			t.Errorf("unable to unmarshal label %s: %v", q, err)
+			continue
+		}
+		got = dst.Node(0).(*dotNode).Label
+		if got != want {
+			t.Errorf("unexpected unmarshaled label for %q via %s: got:%q", s, q, got)
+		}
+	}
+}
+
+func TestEncodeInvalidCompass(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	g.SetEdge(portedEdge{from: simple.Node(0), to: simple.Node(1), toCompass: "north"})
+	_, err := Marshal(g, "", "", "\t")
+	if err == nil {
+		t.Error("expected error for invalid compass point")
+	}
+}
+
 type intlist []int64
 
 func createMultigraph(g []intlist) graph.Multigraph {
@@ -0,0 +1,640 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// Decoder reads and decodes Graphviz DOT-encoded graphs from an input
+// stream. Unlike Unmarshal, a Decoder does not construct a syntax tree;
+// statements are added to the destination graph as they are read, so the
+// memory required for decoding is bounded by the size of the destination
+// and the largest subgraph used as an edge end point rather than by the
+// size of the input.
+type Decoder struct {
+	s *scanner
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{s: newScanner(r)}
+}
+
+// Decode reads the next DOT-encoded graph from the input and stores the
+// result in dst. If there are no more graphs in the input, Decode returns
+// io.EOF. The semantics of decoding follow those of Unmarshal.
+//
+// If an error is returned, dst will hold the statements of the graph that
+// were decoded before the error was encountered.
+func (d *Decoder) Decode(dst encoding.Builder) error {
+	p := &streamParser{
+		generator: generator{newSubgraph: structureOf(dst)},
+		s:         d.s,
+		dst:       dst,
+		newEdge: func(from, to graph.Node) basicEdge {
+			return dst.NewEdge(from, to)
+		},
+		setEdge: func(e basicEdge) {
+			dst.SetEdge(e.(graph.Edge))
+		},
+	}
+	return p.decode()
+}
+
+// DecodeMulti reads the next DOT-encoded graph from the input as a multigraph
+// and stores the result in dst. If there are no more graphs in the input,
+// DecodeMulti returns io.EOF. The semantics of decoding follow those of
+// UnmarshalMulti.
+//
+// If an error is returned, dst will hold the statements of the graph that
+// were decoded before the error was encountered.
+func (d *Decoder) DecodeMulti(dst encoding.MultiBuilder) error {
+	p := &streamParser{
+		generator: generator{newSubgraph: multiStructureOf(dst)},
+		s:         d.s,
+		dst:       dst,
+		newEdge: func(from, to graph.Node) basicEdge {
+			return dst.NewLine(from, to)
+		},
+		setEdge: func(e basicEdge) {
+			dst.SetLine(e.(graph.Line))
+		},
+	}
+	return p.decode()
+}
+
+// streamParser is a recursive descent DOT parser that adds statements
+// to a destination graph as they are parsed.
+type streamParser struct {
+	generator
+
+	s *scanner
+
+	dst     graph.NodeAdder
+	newEdge func(from, to graph.Node) basicEdge
+	setEdge func(basicEdge)
+}
+
+// decode parses a single graph from the input.
+func (p *streamParser) decode() (err error) {
+	defer func() {
+		switch e := recover().(type) {
+		case nil:
+		case error:
+			err = e
+		default:
+			panic(e)
+		}
+	}()
+	p.ids = make(map[string]graph.Node)
+	if a, ok := p.dst.(AttributeSetters); ok {
+		p.graphAttr, p.nodeAttr, p.edgeAttr = a.DOTAttributeSetters()
+	}
+
+	t := p.s.next()
+	if t.kind == eofToken {
+		return io.EOF
+	}
+	if t.isKeyword("strict") {
+		t = p.s.next()
+	}
+	switch {
+	case t.isKeyword("graph"):
+		p.directed = false
+	case t.isKeyword("digraph"):
+		p.directed = true
+	default:
+		p.s.errorf(t.line, "expected graph or digraph, got %s", t)
+	}
+	t = p.s.next()
+	if t.kind == idToken {
+		if dst, ok := p.dst.(DOTIDSetter); ok {
+			dst.SetDOTID(unquoteID(p.id(t)))
+		} else {
+			p.id(t)
+		}
+		t = p.s.next()
+	}
+	p.expect(t, '{')
+	p.stmts()
+	return nil
+}
+
+// stmts parses statements up to and including the closing brace
+// of the enclosing graph or subgraph.
+func (p *streamParser) stmts() {
+	for {
+		t := p.s.next()
+		switch t.kind {
+		case '}':
+			return
+		case ';':
+			continue
+		case eofToken:
+			p.s.errorf(t.line, "unexpected end of input")
+		}
+		p.stmt(t)
+	}
+}
+
+// stmt parses the statement starting with t.
+func (p *streamParser) stmt(t token) {
+	switch {
+	case t.isKeyword("graph"), t.isKeyword("node"), t.isKeyword("edge"):
+		p.attrStmt(t)
+	case t.kind == '{', t.isKeyword("subgraph"):
+		nodes := p.subgraph(t)
+		if p.s.peek().kind == edgeToken {
+			p.edgeStmt(vertex{nodes: nodes})
+		}
+	case t.kind == idToken:
+		if p.s.peek().kind == '=' {
+			key := p.id(t)
+			p.s.next()
+			val := p.id(p.s.next())
+			p.setAttributes("graph", []encoding.Attribute{{
+				Key:   unquoteID(key),
+				Value: unquoteID(val),
+			}})
+			return
+		}
+		v := p.node(t)
+		if p.s.peek().kind == edgeToken {
+			p.edgeStmt(v)
+			return
+		}
+		attrs := p.attrList()
+		n, ok := v.nodes[0].(encoding.AttributeSetter)
+		if !ok {
+			return
+		}
+		for _, a := range attrs {
+			if err := n.SetAttribute(a); err != nil {
+				panic(fmt.Errorf("unable to unmarshal node DOT attribute (%s=%s): %v", a.Key, a.Value, err))
+			}
+		}
+	default:
+		p.s.errorf(t.line, "unexpected %s", t)
+	}
+}
+
+// attrStmt parses the global attribute statement starting with t.
+func (p *streamParser) attrStmt(t token) {
+	if p.s.peek().kind != '[' {
+		p.s.errorf(t.line, "missing attribute list for %s statement", t.text)
+	}
+	p.setAttributes(strings.ToLower(t.text), p.attrList())
+}
+
+// vertex is an edge end point.
+type vertex struct {
+	// nodes is the set of nodes
+	// of the end point.
+	nodes []graph.Node
+
+	// port is the port of a node
+	// end point.
+	port *port
+}
+
+// port is a DOT node port.
+type port struct {
+	id, compass string
+}
+
+// edgeStmt parses the edge statement starting with the vertex from, and
+// adds the edges between each pair of consecutive vertices to the graph.
+func (p *streamParser) edgeStmt(from vertex) {
+	chain := []vertex{from}
+	for p.s.peek().kind == edgeToken {
+		op := p.s.next()
+		if op.text == "->" && !p.directed {
+			p.s.errorf(op.line, "directed edge in undirected graph")
+		}
+		chain = append(chain, p.vertex(p.s.next()))
+	}
+	attrs := p.attrList()
+	for i, to := range chain[1:] {
+		from := chain[i]
+		for _, f := range from.nodes {
+			for _, t := range to.nodes {
+				e := p.newEdge(f, t)
+				applyPorts(from.port, to.port, e)
+				setEdgeAttrs(e, attrs)
+				p.setEdge(e)
+				p.setSubgraphEdge(e)
+			}
+		}
+	}
+}
+
+// vertex parses the edge end point starting with t.
+func (p *streamParser) vertex(t token) vertex {
+	switch {
+	case t.kind == '{', t.isKeyword("subgraph"):
+		return vertex{nodes: p.subgraph(t)}
+	case t.kind == idToken:
+		return p.node(t)
+	default:
+		p.s.errorf(t.line, "expected node or subgraph, got %s", t)
+		panic("unreachable")
+	}
+}
+
+// node parses the node ID and optional port starting with t, returning
+// the node as an edge end point. The node is added to the graph if it
+// does not already exist.
+func (p *streamParser) node(t token) vertex {
+	id := p.id(t)
+	n, ok := p.ids[id]
+	if !ok {
+		n = p.dst.NewNode()
+		if n, ok := n.(DOTIDSetter); ok {
+			n.SetDOTID(unquoteID(id))
+		}
+		p.dst.AddNode(n)
+		p.ids[id] = n
+	}
+	p.addSubgraphNode(n)
+	if p.isInSubgraph() {
+		p.appendSubgraphNode(n)
+	}
+
+	v := vertex{nodes: []graph.Node{n}}
+	if p.s.peek().kind != ':' {
+		return v
+	}
+	p.s.next()
+	v.port = &port{id: p.id(p.s.next())}
+	if p.s.peek().kind == ':' {
+		p.s.next()
+		v.port.compass = p.id(p.s.next())
+		if !isCompassPoint(v.port.compass) {
+			v.port.compass = ""
+		}
+	} else if isCompassPoint(v.port.id) {
+		v.port.id, v.port.compass = "", v.port.id
+	}
+	return v
+}
+
+// subgraph parses the subgraph starting with t, returning
+// the set of nodes referred to within the subgraph.
+func (p *streamParser) subgraph(t token) []graph.Node {
+	var id string
+	if t.isKeyword("subgraph") {
+		t = p.s.next()
+		if t.kind == idToken {
+			id = p.id(t)
+			t = p.s.next()
+		}
+	}
+	p.expect(t, '{')
+	p.pushSubgraph()
+	p.openSubgraph(id)
+	p.stmts()
+	p.closeSubgraph()
+	return p.popSubgraph()
+}
+
+// attrList parses an optional sequence of attribute lists.
+func (p *streamParser) attrList() []encoding.Attribute {
+	var attrs []encoding.Attribute
+	for p.s.peek().kind == '[' {
+		p.s.next()
+		for {
+			t := p.s.next()
+			switch t.kind {
+			case ']':
+			case ',', ';':
+				continue
+			case idToken:
+				key := p.id(t)
+				p.expect(p.s.next(), '=')
+				val := p.id(p.s.next())
+				attrs = append(attrs, encoding.Attribute{
+					Key:   unquoteID(key),
+					Value: unquoteID(val),
+				})
+				continue
+			default:
+				p.s.errorf(t.line, "unexpected %s in attribute list", t)
+			}
+			break
+		}
+	}
+	return attrs
+}
+
+// id returns the ID starting with t, concatenating quoted
+// strings joined with '+'.
+func (p *streamParser) id(t token) string {
+	if t.kind != idToken {
+		p.s.errorf(t.line, "expected ID, got %s", t)
+	}
+	id := t.text
+	for isQuotedID(id) && p.s.peek().kind == '+' {
+		p.s.next()
+		t = p.s.next()
+		if t.kind != idToken || !isQuotedID(t.text) {
+			p.s.errorf(t.line, "expected quoted string after '+', got %s", t)
+		}
+		id = id[:len(id)-1] + t.text[1:]
+	}
+	return id
+}
+
+// expect panics with an error if t is not of the given kind.
+func (p *streamParser) expect(t token, kind rune) {
+	if t.kind != kind {
+		p.s.errorf(t.line, "expected '%c', got %s", kind, t)
+	}
+}
+
+// applyPorts applies the ports of edge end points to e if it is a PortSetter.
+func applyPorts(from, to *port, e basicEdge) {
+	ps, ok := e.(PortSetter)
+	if !ok {
+		return
+	}
+	if from != nil {
+		err := ps.SetFromPort(unquoteID(from.id), from.compass)
+		if err != nil {
+			panic(fmt.Errorf("unable to unmarshal edge port (:%s:%s)", from.id, from.compass))
+		}
+	}
+	if to != nil {
+		err := ps.SetToPort(unquoteID(to.id), to.compass)
+		if err != nil {
+			panic(fmt.Errorf("unable to unmarshal edge DOT port (:%s:%s)", to.id, to.compass))
+		}
+	}
+}
+
+// setEdgeAttrs sets the attributes on e if it is an encoding.AttributeSetter.
+func setEdgeAttrs(e basicEdge, attrs []encoding.Attribute) {
+	s, ok := e.(encoding.AttributeSetter)
+	if !ok {
+		return
+	}
+	for _, a := range attrs {
+		if err := s.SetAttribute(a); err != nil {
+			panic(fmt.Errorf("unable to unmarshal edge DOT attribute (%s=%s): %v", a.Key, a.Value, err))
+		}
+	}
+}
+
+// Token kinds other than punctuation, which is
+// represented by the punctuation character.
+const (
+	eofToken  = -1
+	idToken   = 'a'
+	edgeToken = '-'
+)
+
+// token is a DOT lexical token.
+type token struct {
+	kind rune
+	text string
+	line int
+}
+
+// isKeyword returns whether t is the given case-independent keyword.
+func (t token) isKeyword(kw string) bool {
+	return t.kind == idToken && strings.EqualFold(t.text, kw)
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case eofToken:
+		return "end of input"
+	case idToken, edgeToken:
+		return fmt.Sprintf("%q", t.text)
+	default:
+		return fmt.Sprintf("'%c'", t.kind)
+	}
+}
+
+// scanner is a DOT lexical scanner with a single token of look-ahead.
+type scanner struct {
+	r    *bufio.Reader
+	line int
+
+	peeked bool
+	tok    token
+}
+
+func newScanner(r io.Reader) *scanner {
+	return &scanner{r: bufio.NewReader(r), line: 1}
+}
+
+// next returns the next token in the input.
+func (s *scanner) next() token {
+	if s.peeked {
+		s.peeked = false
+		return s.tok
+	}
+	return s.scan()
+}
+
+// peek returns the next token in the input without consuming it.
+func (s *scanner) peek() token {
+	if !s.peeked {
+		s.tok = s.scan()
+		s.peeked = true
+	}
+	return s.tok
+}
+
+// scan reads the next token from the input.
+func (s *scanner) scan() token {
+	for {
+		c := s.read()
+		line := s.line
+		switch {
+		case c == eofToken:
+			return token{kind: eofToken, line: line}
+		case c == ' ', c == '\t', c == '\r', c == '\n':
+		case c == '#':
+			s.skipLine()
+		case c == '/':
+			switch s.read() {
+			case '/':
+				s.skipLine()
+			case '*':
+				s.skipBlock(line)
+			default:
+				s.errorf(line, "unexpected character '/'")
+			}
+		case strings.ContainsRune("{}[];,:=+", c):
+			return token{kind: c, line: line}
+		case c == '-':
+			d := s.read()
+			if d == '-' || d == '>' {
+				return token{kind: edgeToken, text: string([]rune{c, d}), line: line}
+			}
+			s.unread(d)
+			return s.numeral("-", line)
+		case c == '.', isDigit(c):
+			s.unread(c)
+			return s.numeral("", line)
+		case c == '"':
+			return s.quoted(line)
+		case c == '<':
+			return s.html(line)
+		case isLetter(c):
+			var b strings.Builder
+			for ; isLetter(c) || isDigit(c); c = s.read() {
+				b.WriteRune(c)
+			}
+			s.unread(c)
+			return token{kind: idToken, text: b.String(), line: line}
+		default:
+			s.errorf(line, "unexpected character %q", c)
+		}
+	}
+}
+
+// numeral reads a numeral ID with the given sign prefix.
+func (s *scanner) numeral(sign string, line int) token {
+	var b strings.Builder
+	b.WriteString(sign)
+	var digits int
+	c := s.read()
+	for ; isDigit(c); c = s.read() {
+		b.WriteRune(c)
+		digits++
+	}
+	if c == '.' {
+		b.WriteRune(c)
+		for c = s.read(); isDigit(c); c = s.read() {
+			b.WriteRune(c)
+			digits++
+		}
+	}
+	s.unread(c)
+	if digits == 0 {
+		s.errorf(line, "invalid numeral %q", b.String())
+	}
+	return token{kind: idToken, text: b.String(), line: line}
+}
+
+// quoted reads a double-quoted string ID following the opening quote.
+// The returned text includes the quotes and escaped line breaks are
+// removed.
+func (s *scanner) quoted(line int) token {
+	var b strings.Builder
+	b.WriteByte('"')
+	for {
+		c := s.read()
+		switch c {
+		case eofToken:
+			s.errorf(line, "unterminated string")
+		case '"':
+			b.WriteByte('"')
+			return token{kind: idToken, text: b.String(), line: line}
+		case '\\':
+			d := s.read()
+			switch d {
+			case eofToken:
+				s.errorf(line, "unterminated string")
+			case '\n':
+				continue
+			}
+			b.WriteRune(c)
+			b.WriteRune(d)
+		default:
+			b.WriteRune(c)
+		}
+	}
+}
+
+// html reads an HTML string ID following the opening angle bracket.
+// The returned text includes the enclosing angle brackets.
+func (s *scanner) html(line int) token {
+	var b strings.Builder
+	b.WriteByte('<')
+	for depth := 1; depth > 0; {
+		c := s.read()
+		switch c {
+		case eofToken:
+			s.errorf(line, "unterminated HTML string")
+		case '<':
+			depth++
+		case '>':
+			depth--
+		}
+		b.WriteRune(c)
+	}
+	return token{kind: idToken, text: b.String(), line: line}
+}
+
+// skipLine discards the input up to and including the next line break.
+func (s *scanner) skipLine() {
+	for c := s.read(); c != '\n' && c != eofToken; c = s.read() {
+	}
+}
+
+// skipBlock discards the input up to and including the end of a block
+// comment.
+func (s *scanner) skipBlock(line int) {
+	var last rune
+	for {
+		c := s.read()
+		switch {
+		case c == eofToken:
+			s.errorf(line, "unterminated comment")
+		case last == '*' && c == '/':
+			return
+		}
+		last = c
+	}
+}
+
+// read returns the next rune in the input, or eofToken at the end of
+// the input.
+func (s *scanner) read() rune {
+	c, _, err := s.r.ReadRune()
+	if err == io.EOF {
+		return eofToken
+	}
+	if err != nil {
+		panic(err)
+	}
+	if c == '\n' {
+		s.line++
+	}
+	return c
+}
+
+// unread unreads the last rune read from the input.
+func (s *scanner) unread(c rune) {
+	if c == eofToken {
+		return
+	}
+	s.r.UnreadRune()
+	if c == '\n' {
+		s.line--
+	}
+}
+
+func (s *scanner) errorf(line int, format string, args ...interface{}) {
+	panic(fmt.Errorf("dot: line %d: %s", line, fmt.Sprintf(format, args...)))
+}
+
+func isDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isLetter(c rune) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c >= 0x80
+}
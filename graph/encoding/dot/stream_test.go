@@ -0,0 +1,192 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dot
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/multi"
+)
+
+var decoderTests = []struct {
+	in       string
+	directed bool
+}{
+	{in: directed, directed: true},
+	{in: undirected, directed: false},
+	{in: directedID, directed: true},
+	{in: undirectedID, directed: false},
+	{in: directedWithPorts, directed: true},
+	{in: undirectedWithPorts, directed: false},
+	{in: directedAttrs, directed: true},
+	{in: undirectedAttrs, directed: false},
+	{in: directedChained, directed: true},
+	{in: undirectedChained, directed: false},
+	{in: directedRecordsAndHTML, directed: true},
+}
+
+func TestDecoder(t *testing.T) {
+	for i, test := range decoderTests {
+		var want, got encoding.Builder
+		if test.directed {
+			want, got = newDotDirectedGraph(), newDotDirectedGraph()
+		} else {
+			want, got = newDotUndirectedGraph(), newDotUndirectedGraph()
+		}
+		err := Unmarshal([]byte(test.in), want)
+		if err != nil {
+			t.Fatalf("i=%d: unable to unmarshal DOT graph; %v", i, err)
+		}
+		dec := NewDecoder(strings.NewReader(test.in))
+		err = dec.Decode(got)
+		if err != nil {
+			t.Errorf("i=%d: unable to decode DOT graph; %v", i, err)
+			continue
+		}
+		err = dec.Decode(got)
+		if err != io.EOF {
+			t.Errorf("i=%d: unexpected error at end of input: got:%v want:%v", i, err, io.EOF)
+		}
+		wantBuf, err := Marshal(want, "", "", "\t")
+		if err != nil {
+			t.Fatalf("i=%d: unable to marshal graph; %v", i, err)
+		}
+		gotBuf, err := Marshal(got, "", "", "\t")
+		if err != nil {
+			t.Errorf("i=%d: unable to marshal graph; %v", i, err)
+			continue
+		}
+		if string(gotBuf) != string(wantBuf) {
+			t.Errorf("i=%d: graph content mismatch; want:\n%s\n\ngot:\n%s", i, wantBuf, gotBuf)
+		}
+	}
+}
+
+func TestDecoderMulti(t *testing.T) {
+	for i, test := range []struct {
+		in       string
+		directed bool
+	}{
+		{in: directedMultigraph, directed: true},
+		{in: undirectedMultigraph, directed: false},
+		{in: directedSelfLoopMultigraph, directed: true},
+		{in: undirectedSelfLoopMultigraph, directed: false},
+	} {
+		var dst encoding.MultiBuilder
+		if test.directed {
+			dst = multi.NewDirectedGraph()
+		} else {
+			dst = multi.NewUndirectedGraph()
+		}
+		err := NewDecoder(strings.NewReader(test.in)).DecodeMulti(dst)
+		if err != nil {
+			t.Errorf("i=%d: unable to decode DOT graph; %v", i, err)
+			continue
+		}
+		buf, err := MarshalMulti(dst, "", "", "\t")
+		if err != nil {
+			t.Errorf("i=%d: unable to marshal graph; %v", i, err)
+			continue
+		}
+		if string(buf) != test.in {
+			t.Errorf("i=%d: graph content mismatch; want:\n%s\n\ngot:\n%s", i, test.in, buf)
+		}
+	}
+}
+
+const graphStream = `# 1 "stream.gv"
+digraph first {
+	/* A subgraph end point
+	   fans out to each node. */
+	a -> {b; c} -> d
+	subgraph s { e:x:ne -> "f" + "g" [label=<<b>h</b>>] }
+}
+graph second { x -- y; y -- z; z -- x }
+`
+
+func TestDecoderStream(t *testing.T) {
+	dec := NewDecoder(strings.NewReader(graphStream))
+
+	first := newDotDirectedGraph()
+	err := dec.Decode(first)
+	if err != nil {
+		t.Fatalf("unable to decode first graph: %v", err)
+	}
+	if first.id != "first" {
+		t.Errorf("unexpected graph ID: got:%q want:%q", first.id, "first")
+	}
+	ids := make(map[string]int64)
+	for _, n := range graph.NodesOf(first.Nodes()) {
+		ids[n.(*dotNode).dotID] = n.ID()
+	}
+	for _, e := range [][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "d"}, {"e", "fg"}} {
+		u, ok := ids[e[0]]
+		if !ok {
+			t.Errorf("missing node %s", e[0])
+			continue
+		}
+		v, ok := ids[e[1]]
+		if !ok {
+			t.Errorf("missing node %s", e[1])
+			continue
+		}
+		if !first.HasEdgeFromTo(u, v) {
+			t.Errorf("missing edge %s->%s", e[0], e[1])
+		}
+	}
+	if n := first.Edges().Len(); n != 5 {
+		t.Errorf("unexpected number of edges: got:%d want:5", n)
+	}
+	e := first.Edge(ids["e"], ids["fg"]).(*dotEdge)
+	if e.FromPortLabels != (dotPortLabels{Port: "x", Compass: "ne"}) {
+		t.Errorf("unexpected from port: got:%+v", e.FromPortLabels)
+	}
+	if e.Label != "<<b>h</b>>" {
+		t.Errorf("unexpected edge label: got:%q want:%q", e.Label, "<<b>h</b>>")
+	}
+
+	second := newDotUndirectedGraph()
+	err = dec.Decode(second)
+	if err != nil {
+		t.Fatalf("unable to decode second graph: %v", err)
+	}
+	if second.Nodes().Len() != 3 || second.Edges().Len() != 3 {
+		t.Errorf("unexpected second graph: got %d nodes and %d edges", second.Nodes().Len(), second.Edges().Len())
+	}
+
+	err = dec.Decode(newDotDirectedGraph())
+	if err != io.EOF {
+		t.Errorf("unexpected error at end of stream: got:%v want:%v", err, io.EOF)
+	}
+}
+
+var decoderErrorTests = []struct {
+	name string
+	in   string
+}{
+	{name: "not a graph", in: "node a"},
+	{name: "unclosed graph", in: "digraph { a -> b"},
+	{name: "directed edge in undirected graph", in: "graph { a -> b }"},
+	{name: "unterminated string", in: `digraph { "a }`},
+	{name: "unterminated comment", in: "digraph { /* a }"},
+	{name: "unterminated HTML", in: "digraph { a [label=<b] }"},
+	{name: "missing attribute value", in: "digraph { a [label] }"},
+	{name: "bad numeral", in: "digraph { -x }"},
+	{name: "missing attribute list", in: "digraph { node }"},
+	{name: "bad concatenation", in: `digraph { "a" + b }`},
+}
+
+func TestDecoderError(t *testing.T) {
+	for _, test := range decoderErrorTests {
+		err := NewDecoder(strings.NewReader(test.in)).Decode(newDotDirectedGraph())
+		if err == nil || err == io.EOF {
+			t.Errorf("expected error for %s: got:%v", test.name, err)
+		}
+	}
+}
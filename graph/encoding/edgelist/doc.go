@@ -0,0 +1,9 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package edgelist implements reading and writing of graphs as delimited
+// edge lists such as CSV and TSV files. Columns of the edge list are mapped
+// to the source and target nodes, weight and attributes of edges by name,
+// either from a header record or from names provided by the user.
+package edgelist // import "gonum.org/v1/gonum/graph/encoding/edgelist"
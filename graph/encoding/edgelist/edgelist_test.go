@@ -0,0 +1,247 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edgelist
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// edgeListGraph is a weighted directed graph of named nodes and
+// attributed edges.
+type edgeListGraph struct {
+	*simple.WeightedDirectedGraph
+}
+
+func newEdgeListGraph() edgeListGraph {
+	return edgeListGraph{WeightedDirectedGraph: simple.NewWeightedDirectedGraph(0, 0)}
+}
+
+func (g edgeListGraph) NewNode() graph.Node {
+	return &namedNode{id: g.WeightedDirectedGraph.NewNode().ID()}
+}
+
+func (g edgeListGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return g.NewWeightedEdge(from, to, 1)
+}
+
+func (g edgeListGraph) NewWeightedEdge(from, to graph.Node, weight float64) graph.WeightedEdge {
+	return &attrEdge{WeightedEdge: simple.WeightedEdge{F: from, T: to, W: weight}}
+}
+
+func (g edgeListGraph) SetEdge(e graph.Edge) {
+	g.SetWeightedEdge(e.(graph.WeightedEdge))
+}
+
+// undirectedGraph is a weighted undirected graph that
+// satisfies graph.Builder.
+type undirectedGraph struct {
+	*simple.WeightedUndirectedGraph
+}
+
+func (g undirectedGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return g.NewWeightedEdge(from, to, 1)
+}
+
+func (g undirectedGraph) SetEdge(e graph.Edge) {
+	g.SetWeightedEdge(e.(graph.WeightedEdge))
+}
+
+type namedNode struct {
+	id   int64
+	name string
+}
+
+func (n *namedNode) ID() int64                        { return n.id }
+func (n *namedNode) StringID() string                 { return n.name }
+func (n *namedNode) SetIDFromString(uid string) error { n.name = uid; return nil }
+
+type attrEdge struct {
+	simple.WeightedEdge
+	attrs []encoding.Attribute
+}
+
+func (e *attrEdge) Attributes() []encoding.Attribute { return e.attrs }
+func (e *attrEdge) SetAttribute(attr encoding.Attribute) error {
+	e.attrs = append(e.attrs, attr)
+	return nil
+}
+
+const flights = `# Flights between airports.
+origin;distance;dest;carrier;delay
+SFO;2586;JFK;UA;12
+JFK;2586;SFO;AA;-3
+SFO;1846;ORD;UA;0
+`
+
+func TestReadHeader(t *testing.T) {
+	g := newEdgeListGraph()
+	r := NewReader(strings.NewReader(flights))
+	r.Comma = ';'
+	r.Comment = '#'
+	r.Header = true
+	r.Source = "origin"
+	r.Target = "dest"
+	r.Weight = "distance"
+	r.Attributes = []string{"carrier"}
+	err := r.Read(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := make(map[string]int64)
+	for _, n := range graph.NodesOf(g.Nodes()) {
+		names[n.(*namedNode).name] = n.ID()
+	}
+	if len(names) != 3 {
+		t.Fatalf("unexpected number of nodes: got:%d want:3", len(names))
+	}
+	want := []struct {
+		from, to string
+		weight   float64
+		carrier  string
+	}{
+		{from: "SFO", to: "JFK", weight: 2586, carrier: "UA"},
+		{from: "JFK", to: "SFO", weight: 2586, carrier: "AA"},
+		{from: "SFO", to: "ORD", weight: 1846, carrier: "UA"},
+	}
+	if n := g.Edges().Len(); n != len(want) {
+		t.Errorf("unexpected number of edges: got:%d want:%d", n, len(want))
+	}
+	for _, w := range want {
+		e, ok := g.WeightedEdge(names[w.from], names[w.to]).(*attrEdge)
+		if !ok {
+			t.Errorf("missing edge %s->%s", w.from, w.to)
+			continue
+		}
+		if e.Weight() != w.weight {
+			t.Errorf("unexpected weight for %s->%s: got:%v want:%v", w.from, w.to, e.Weight(), w.weight)
+		}
+		wantAttrs := []encoding.Attribute{{Key: "carrier", Value: w.carrier}}
+		if !reflect.DeepEqual(e.attrs, wantAttrs) {
+			t.Errorf("unexpected attributes for %s->%s: got:%v want:%v", w.from, w.to, e.attrs, wantAttrs)
+		}
+	}
+
+	var buf bytes.Buffer
+	wr := NewWriter(&buf)
+	wr.Comma = '\t'
+	wr.Header = true
+	wr.Weight = "distance"
+	wr.Attributes = []string{"carrier", "delay"}
+	err = wr.Write(g)
+	if err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	const wantEncoding = "source\ttarget\tdistance\tcarrier\tdelay\n" +
+		"SFO\tJFK\t2586\tUA\t\n" +
+		"SFO\tORD\t1846\tUA\t\n" +
+		"JFK\tSFO\t2586\tAA\t\n"
+	if buf.String() != wantEncoding {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", &buf, wantEncoding)
+	}
+}
+
+func TestReadAllAttributes(t *testing.T) {
+	g := newEdgeListGraph()
+	r := NewReader(strings.NewReader("a,b,x,y\n\"c,d\",e,1,2\n"))
+	r.Header = true
+	err := r.Read(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	edges := graph.EdgesOf(g.Edges())
+	if len(edges) != 1 {
+		t.Fatalf("unexpected number of edges: got:%d want:1", len(edges))
+	}
+	e := edges[0].(*attrEdge)
+	if got := e.From().(*namedNode).name; got != "c,d" {
+		t.Errorf("unexpected source: got:%q want:%q", got, "c,d")
+	}
+	want := []encoding.Attribute{{Key: "x", Value: "1"}, {Key: "y", Value: "2"}}
+	if !reflect.DeepEqual(e.attrs, want) {
+		t.Errorf("unexpected attributes: got:%v want:%v", e.attrs, want)
+	}
+}
+
+func TestReadWhitespace(t *testing.T) {
+	const data = `% Zachary-style edge list.
+1   2  0.5
+2	3  1.5
+
+3 1 2
+`
+	g := undirectedGraph{simple.NewWeightedUndirectedGraph(0, 0)}
+	r := NewReader(strings.NewReader(data))
+	r.Comma = ' '
+	r.Comment = '%'
+	r.Weight = "2"
+	err := r.Read(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.Nodes().Len() != 3 {
+		t.Errorf("unexpected number of nodes: got:%d want:3", g.Nodes().Len())
+	}
+	var weights []float64
+	for _, e := range graph.WeightedEdgesOf(g.WeightedEdges()) {
+		weights = append(weights, e.Weight())
+	}
+	sort.Float64s(weights)
+	if want := []float64{0.5, 1.5, 2}; !reflect.DeepEqual(weights, want) {
+		t.Errorf("unexpected weights: got:%v want:%v", weights, want)
+	}
+
+	var buf bytes.Buffer
+	err = NewWriter(&buf).Write(g)
+	if err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	const want = "0,1\n0,2\n1,2\n"
+	if buf.String() != want {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", &buf, want)
+	}
+}
+
+var readErrorTests = []struct {
+	name   string
+	data   string
+	reader func(*Reader)
+}{
+	{name: "missing header", data: "", reader: func(r *Reader) { r.Header = true }},
+	{name: "unknown column", data: "a,b\n1,2\n", reader: func(r *Reader) { r.Header = true; r.Source = "c" }},
+	{name: "same column", data: "a,b\n1,2\n", reader: func(r *Reader) { r.Header = true; r.Source = "b" }},
+	{name: "too few columns", data: "1\n"},
+	{name: "ragged", data: "1,2\n3,4,5\n"},
+	{name: "bad weight", data: "1,2,heavy\n", reader: func(r *Reader) { r.Weight = "2" }},
+	{name: "bad quote", data: "\"1,2\n"},
+}
+
+func TestReadError(t *testing.T) {
+	for _, test := range readErrorTests {
+		r := NewReader(strings.NewReader(test.data))
+		if test.reader != nil {
+			test.reader(r)
+		}
+		err := r.Read(newEdgeListGraph())
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+
+	r := NewReader(strings.NewReader("1,2,3\n"))
+	r.Weight = "2"
+	err := r.Read(simple.NewDirectedGraph())
+	if err == nil {
+		t.Error("expected error for weight column with unweighted destination")
+	}
+}
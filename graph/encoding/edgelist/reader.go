@@ -0,0 +1,293 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edgelist
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// StringIDSetter is a graph node that can set its ID based on the given
+// node column value.
+type StringIDSetter interface {
+	SetIDFromString(uid string) error
+}
+
+// Reader reads edges from a delimited edge list.
+type Reader struct {
+	// Comma is the field delimiter. If Comma
+	// is ' ', fields are separated by runs of
+	// white space and quoting is not supported.
+	Comma rune
+
+	// Comment, if not zero, is the comment
+	// character. Lines beginning with Comment
+	// are ignored.
+	Comment rune
+
+	// Header indicates that the first record
+	// holds the names of the columns.
+	Header bool
+
+	// Names holds the column names when Header
+	// is false. If Names is nil and Header is
+	// false, columns are named by their decimal
+	// index from zero.
+	Names []string
+
+	// Source and Target are the names of the
+	// columns holding the source and target
+	// nodes of each edge. If empty, the first
+	// and second columns are used.
+	Source, Target string
+
+	// Weight is the name of the column holding
+	// the weight of each edge. If empty, edges
+	// are not read with weights.
+	Weight string
+
+	// Attributes holds the names of the columns
+	// to set as edge attributes. If Attributes
+	// is nil, all columns that are not mapped
+	// to a source, target or weight are used.
+	Attributes []string
+
+	r io.Reader
+}
+
+// NewReader returns a new comma-delimited Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{Comma: ',', r: r}
+}
+
+// Read reads all the records from the receiver, adding an edge for each
+// record to dst. Nodes are created by dst the first time their column value
+// is seen and have their ID set from the value if they implement
+// StringIDSetter. If a Weight column is named, dst must be a
+// graph.WeightedBuilder. Attribute columns are set on edges that implement
+// encoding.AttributeSetter, keyed by the column name.
+func (r *Reader) Read(dst graph.Builder) error {
+	next := r.records()
+
+	var (
+		names []string
+		line  int
+		err   error
+	)
+	if r.Header {
+		names, line, err = next()
+		if err == io.EOF {
+			return errors.New("edgelist: missing header")
+		}
+		if err != nil {
+			return err
+		}
+	} else {
+		names = r.Names
+	}
+
+	var m *mapping
+	if names != nil {
+		m, err = r.mapping(names)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		wb       graph.WeightedBuilder
+		weighted bool
+	)
+	if r.Weight != "" {
+		wb, weighted = dst.(graph.WeightedBuilder)
+		if !weighted {
+			return errors.New("edgelist: weight column for unweighted destination")
+		}
+	}
+
+	nodes := make(map[string]graph.Node)
+	node := func(uid string) (graph.Node, error) {
+		if n, ok := nodes[uid]; ok {
+			return n, nil
+		}
+		n := dst.NewNode()
+		if s, ok := n.(StringIDSetter); ok {
+			err := s.SetIDFromString(uid)
+			if err != nil {
+				return nil, err
+			}
+		}
+		dst.AddNode(n)
+		nodes[uid] = n
+		return n, nil
+	}
+
+	for {
+		var record []string
+		record, line, err = next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if m == nil {
+			// Name the columns by index from
+			// the first record.
+			names = make([]string, len(record))
+			for i := range names {
+				names[i] = strconv.Itoa(i)
+			}
+			m, err = r.mapping(names)
+			if err != nil {
+				return err
+			}
+		}
+		if len(record) != len(names) {
+			return fmt.Errorf("edgelist: line %d: wrong number of fields: got %d want %d", line, len(record), len(names))
+		}
+
+		u, err := node(record[m.source])
+		if err != nil {
+			return fmt.Errorf("edgelist: line %d: %v", line, err)
+		}
+		v, err := node(record[m.target])
+		if err != nil {
+			return fmt.Errorf("edgelist: line %d: %v", line, err)
+		}
+		var e graph.Edge
+		if weighted {
+			w, err := strconv.ParseFloat(record[m.weight], 64)
+			if err != nil {
+				return fmt.Errorf("edgelist: line %d: invalid weight: %q", line, record[m.weight])
+			}
+			e = wb.NewWeightedEdge(u, v, w)
+		} else {
+			e = dst.NewEdge(u, v)
+		}
+		if s, ok := e.(encoding.AttributeSetter); ok {
+			for _, i := range m.attrs {
+				err = s.SetAttribute(encoding.Attribute{Key: names[i], Value: record[i]})
+				if err != nil {
+					return fmt.Errorf("edgelist: line %d: unable to set attribute %s: %v", line, names[i], err)
+				}
+			}
+		}
+		if weighted {
+			wb.SetWeightedEdge(e.(graph.WeightedEdge))
+		} else {
+			dst.SetEdge(e)
+		}
+	}
+}
+
+// mapping is the mapping between column indices and edge fields.
+type mapping struct {
+	source, target, weight int
+	attrs                  []int
+}
+
+// mapping returns the mapping for the given column names.
+func (r *Reader) mapping(names []string) (*mapping, error) {
+	index := func(name string, def int) (int, error) {
+		if name == "" {
+			if def >= len(names) {
+				return -1, errors.New("edgelist: too few columns")
+			}
+			return def, nil
+		}
+		for i, n := range names {
+			if n == name {
+				return i, nil
+			}
+		}
+		return -1, fmt.Errorf("edgelist: no column named %q", name)
+	}
+
+	m := &mapping{weight: -1}
+	var err error
+	m.source, err = index(r.Source, 0)
+	if err != nil {
+		return nil, err
+	}
+	m.target, err = index(r.Target, 1)
+	if err != nil {
+		return nil, err
+	}
+	if m.source == m.target {
+		return nil, errors.New("edgelist: source and target columns are the same")
+	}
+	if r.Weight != "" {
+		m.weight, err = index(r.Weight, -1)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.Attributes == nil {
+		for i := range names {
+			if i != m.source && i != m.target && i != m.weight {
+				m.attrs = append(m.attrs, i)
+			}
+		}
+		return m, nil
+	}
+	for _, name := range r.Attributes {
+		i, err := index(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		m.attrs = append(m.attrs, i)
+	}
+	return m, nil
+}
+
+// records returns a function that returns successive records from the
+// receiver's input and their line numbers.
+func (r *Reader) records() func() ([]string, int, error) {
+	if r.Comma == ' ' {
+		sc := bufio.NewScanner(r.r)
+		var line int
+		return func() ([]string, int, error) {
+			for sc.Scan() {
+				line++
+				text := sc.Text()
+				if r.Comment != 0 && strings.HasPrefix(text, string(r.Comment)) {
+					continue
+				}
+				fields := strings.Fields(text)
+				if len(fields) == 0 {
+					continue
+				}
+				return fields, line, nil
+			}
+			err := sc.Err()
+			if err == nil {
+				err = io.EOF
+			}
+			return nil, line, err
+		}
+	}
+
+	cr := csv.NewReader(r.r)
+	cr.Comma = r.Comma
+	cr.Comment = r.Comment
+	cr.FieldsPerRecord = -1
+	return func() ([]string, int, error) {
+		record, err := cr.Read()
+		if err != nil {
+			return nil, 0, err
+		}
+		line, _ := cr.FieldPos(0)
+		return record, line, nil
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package edgelist
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// StringIDer is a graph node that has a string identifier for its
+// node column value.
+type StringIDer interface {
+	StringID() string
+}
+
+// Writer writes edges as a delimited edge list.
+type Writer struct {
+	// Comma is the field delimiter.
+	Comma rune
+
+	// Header indicates that a header record
+	// holding the column names is written
+	// before the edges.
+	Header bool
+
+	// Source and Target are the names of the
+	// columns holding the source and target
+	// nodes of each edge.
+	Source, Target string
+
+	// Weight is the name of the column holding
+	// the weight of each edge. If empty, no
+	// weight column is written.
+	Weight string
+
+	// Attributes holds the names of the edge
+	// attributes that are written as columns
+	// after the source, target and weight
+	// columns.
+	Attributes []string
+
+	w io.Writer
+}
+
+// NewWriter returns a new comma-delimited Writer that writes to w with
+// the source and target columns named "source" and "target".
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{Comma: ',', Source: "source", Target: "target", w: w}
+}
+
+// Write writes the edges of g to the receiver's output, one record per edge,
+// in order of increasing source and then target node ID. The edges of
+// undirected graphs are written once. Nodes are written as their decimal ID
+// unless they implement StringIDer. Weights are written from graph.Weighted
+// graphs if the Weight column is named, and attribute columns are written
+// from edges that implement encoding.Attributer. Missing attributes are
+// written as empty fields.
+func (w *Writer) Write(g graph.Graph) error {
+	cw := csv.NewWriter(w.w)
+	cw.Comma = w.Comma
+
+	if w.Header {
+		header := []string{w.Source, w.Target}
+		if w.Weight != "" {
+			header = append(header, w.Weight)
+		}
+		header = append(header, w.Attributes...)
+		err := cw.Write(header)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, isDirected := g.(graph.Directed)
+	wg, isWeighted := g.(graph.Weighted)
+	record := make([]string, 0, 3+len(w.Attributes))
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			record = append(record[:0], nodeID(u), nodeID(v))
+			if w.Weight != "" {
+				weight := 1.0
+				if isWeighted {
+					weight, _ = wg.Weight(uid, vid)
+				}
+				record = append(record, strconv.FormatFloat(weight, 'g', -1, 64))
+			}
+			if len(w.Attributes) != 0 {
+				var attrs map[string]string
+				if a, ok := g.Edge(uid, vid).(encoding.Attributer); ok {
+					attrs = make(map[string]string)
+					for _, attr := range a.Attributes() {
+						attrs[attr.Key] = attr.Value
+					}
+				}
+				for _, key := range w.Attributes {
+					record = append(record, attrs[key])
+				}
+			}
+			err := cw.Write(record)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// nodeID returns the node column value for n.
+func nodeID(n graph.Node) string {
+	if s, ok := n.(StringIDer); ok {
+		return s.StringID()
+	}
+	return strconv.FormatInt(n.ID(), 10)
+}
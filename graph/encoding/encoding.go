@@ -18,6 +18,14 @@ type MultiBuilder interface {
 	graph.MultigraphBuilder
 }
 
+// NodeMaker is implemented by destination graphs that make their own
+// node values for node IDs specified by an encoding.
+type NodeMaker interface {
+	// MakeNode returns a new node with the given ID. The node is
+	// not added to the graph by MakeNode.
+	MakeNode(id int64) graph.Node
+}
+
 // AttributeSetter is implemented by types that can set an encoded graph
 // attribute.
 type AttributeSetter interface {
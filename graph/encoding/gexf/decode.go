@@ -0,0 +1,188 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gexf
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/formats/gexf12"
+)
+
+// GEXFIDSetter is implemented by types that can set a GEXF ID.
+type GEXFIDSetter interface {
+	SetGEXFID(id string)
+}
+
+// LabelSetter is implemented by graph.Node and graph.Edge values
+// that can set a GEXF label.
+type LabelSetter interface {
+	SetGEXFLabel(label string)
+}
+
+// NodeVizSetter is implemented by graph.Node values that can set
+// GEXF viz extension properties.
+type NodeVizSetter interface {
+	SetGEXFNodeViz(NodeViz)
+}
+
+// EdgeVizSetter is implemented by graph.Edge values that can set
+// GEXF viz extension properties.
+type EdgeVizSetter interface {
+	SetGEXFEdgeViz(EdgeViz)
+}
+
+// Unmarshal parses the GEXF-encoded data and stores the result in dst.
+// Nodes are created by dst and have their GEXF ID, label, attributes and
+// viz properties set if they implement GEXFIDSetter, LabelSetter,
+// encoding.AttributeSetter and NodeVizSetter. Edges are treated similarly
+// with EdgeVizSetter. If dst is a graph.WeightedBuilder, edges are created
+// with their GEXF weight, which defaults to one.
+//
+// Attribute keys are the titles of declared GEXF attributes, and declared
+// default values are set for nodes and edges that do not hold a value for
+// the attribute. Dynamic properties are ignored and hierarchical graphs
+// are not supported.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	var c gexf12.Content
+	err := xml.Unmarshal(data, &c)
+	if err != nil {
+		return err
+	}
+
+	var nodeAttrs, edgeAttrs []gexf12.Attribute
+	for _, a := range c.Graph.Attributes {
+		switch a.Class {
+		case "node":
+			nodeAttrs = append(nodeAttrs, a.Attributes...)
+		case "edge":
+			edgeAttrs = append(edgeAttrs, a.Attributes...)
+		default:
+			return fmt.Errorf("gexf: invalid attribute class %q", a.Class)
+		}
+	}
+
+	ids := make(map[string]graph.Node, len(c.Graph.Nodes.Nodes))
+	for _, gn := range c.Graph.Nodes.Nodes {
+		if gn.Nodes != nil || gn.Edges != nil || gn.ParentID != "" || gn.Parents != nil {
+			return fmt.Errorf("gexf: hierarchical graphs not supported: node %q", gn.ID)
+		}
+		if _, exists := ids[gn.ID]; exists {
+			return fmt.Errorf("gexf: duplicate node ID %q", gn.ID)
+		}
+		n := dst.NewNode()
+		if s, ok := n.(GEXFIDSetter); ok {
+			s.SetGEXFID(gn.ID)
+		}
+		if s, ok := n.(LabelSetter); ok && gn.Label != "" {
+			s.SetGEXFLabel(gn.Label)
+		}
+		if s, ok := n.(encoding.AttributeSetter); ok {
+			err = setAttributes(s, nodeAttrs, gn.AttValues)
+			if err != nil {
+				return fmt.Errorf("gexf: unable to unmarshal node %q attribute: %v", gn.ID, err)
+			}
+		}
+		if s, ok := n.(NodeVizSetter); ok {
+			var viz NodeViz
+			if gn.Position != nil {
+				viz.Position = &Position{X: gn.Position.X, Y: gn.Position.Y, Z: gn.Position.Z}
+			}
+			viz.Color = toColor(gn.Color)
+			if gn.Size != nil {
+				viz.Size = gn.Size.Value
+			}
+			if gn.Shape != nil {
+				viz.Shape = gn.Shape.Shape
+				viz.Image = gn.Shape.URI
+			}
+			s.SetGEXFNodeViz(viz)
+		}
+		dst.AddNode(n)
+		ids[gn.ID] = n
+	}
+
+	wb, isWeighted := dst.(graph.WeightedBuilder)
+	for _, ge := range c.Graph.Edges.Edges {
+		from, ok := ids[ge.Source]
+		if !ok {
+			return fmt.Errorf("gexf: edge source %q not found", ge.Source)
+		}
+		to, ok := ids[ge.Target]
+		if !ok {
+			return fmt.Errorf("gexf: edge target %q not found", ge.Target)
+		}
+		var e graph.Edge
+		if isWeighted {
+			w := ge.Weight
+			if w == 0 {
+				w = 1
+			}
+			e = wb.NewWeightedEdge(from, to, w)
+		} else {
+			e = dst.NewEdge(from, to)
+		}
+		if s, ok := e.(LabelSetter); ok && ge.Label != "" {
+			s.SetGEXFLabel(ge.Label)
+		}
+		if s, ok := e.(encoding.AttributeSetter); ok {
+			err = setAttributes(s, edgeAttrs, ge.AttValues)
+			if err != nil {
+				return fmt.Errorf("gexf: unable to unmarshal edge %q attribute: %v", ge.ID, err)
+			}
+		}
+		if s, ok := e.(EdgeVizSetter); ok {
+			var viz EdgeViz
+			viz.Color = toColor(ge.Color)
+			if ge.Thickness != nil {
+				viz.Thickness = ge.Thickness.Value
+			}
+			if ge.Shape != nil {
+				viz.Shape = ge.Shape.Shape
+			}
+			s.SetGEXFEdgeViz(viz)
+		}
+		if isWeighted {
+			wb.SetWeightedEdge(e.(graph.WeightedEdge))
+		} else {
+			dst.SetEdge(e)
+		}
+	}
+	return nil
+}
+
+// setAttributes sets the attribute values in vals on dst, using the
+// declarations in decl to obtain attribute keys and default values.
+func setAttributes(dst encoding.AttributeSetter, decl []gexf12.Attribute, vals *gexf12.AttValues) error {
+	set := make(map[string]bool)
+	if vals != nil {
+		for _, v := range vals.AttValues {
+			key := v.For
+			for _, d := range decl {
+				if d.ID == v.For {
+					key = d.Title
+					break
+				}
+			}
+			set[v.For] = true
+			err := dst.SetAttribute(encoding.Attribute{Key: key, Value: v.Value})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	for _, d := range decl {
+		if set[d.ID] || d.Default == "" {
+			continue
+		}
+		err := dst.SetAttribute(encoding.Attribute{Key: d.Title, Value: d.Default})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
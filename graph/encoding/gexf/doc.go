@@ -0,0 +1,10 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gexf implements GEXF 1.2 marshaling and unmarshaling of graphs,
+// including the viz extension for node position, color, size and shape and
+// edge color, thickness and shape. GEXF is the native format of Gephi.
+//
+// For details of GEXF see https://gephi.org/gexf/format/.
+package gexf // import "gonum.org/v1/gonum/graph/encoding/gexf"
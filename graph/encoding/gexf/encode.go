@@ -0,0 +1,191 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gexf
+
+import (
+	"encoding/xml"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/formats/gexf12"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Node is a GEXF graph node.
+type Node interface {
+	// GEXFID returns a GEXF node ID.
+	GEXFID() string
+}
+
+// Labeler is implemented by graph.Node and graph.Edge values
+// that have a GEXF label.
+type Labeler interface {
+	GEXFLabel() string
+}
+
+// NodeVisualizer is implemented by graph.Node values that have
+// GEXF viz extension properties.
+type NodeVisualizer interface {
+	GEXFNodeViz() NodeViz
+}
+
+// EdgeVisualizer is implemented by graph.Edge values that have
+// GEXF viz extension properties.
+type EdgeVisualizer interface {
+	GEXFEdgeViz() EdgeViz
+}
+
+// Marshal returns the GEXF encoding for the graph g, applying the prefix
+// and indent to the encoding. Node IDs are the decimal node ID values
+// unless the node implements Node, and edge weights are encoded for
+// graph.WeightedEdge values. Values implementing Labeler, NodeVisualizer
+// and EdgeVisualizer have their labels and viz properties encoded.
+//
+// Attributes of nodes and edges implementing encoding.Attributer are
+// encoded as string-typed GEXF attributes titled by the attribute key.
+// Edges with a zero weight are encoded without a weight and so decode
+// with the GEXF default weight of one.
+func Marshal(g graph.Graph, prefix, indent string) ([]byte, error) {
+	_, isDirected := g.(graph.Directed)
+	c := gexf12.Content{
+		Version: "1.2",
+		Graph: gexf12.Graph{
+			DefaultEdgeType: "undirected",
+			IDType:          "string",
+			Mode:            "static",
+		},
+	}
+	if isDirected {
+		c.Graph.DefaultEdgeType = "directed"
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+
+	nodeAttrs := newAttributes("node")
+	for _, n := range nodes {
+		gn := gexf12.Node{ID: nodeID(n)}
+		if l, ok := n.(Labeler); ok {
+			gn.Label = l.GEXFLabel()
+		}
+		if a, ok := n.(encoding.Attributer); ok {
+			gn.AttValues = nodeAttrs.values(a.Attributes())
+		}
+		if v, ok := n.(NodeVisualizer); ok {
+			viz := v.GEXFNodeViz()
+			if viz.Position != nil {
+				gn.Position = &gexf12.Position{X: viz.Position.X, Y: viz.Position.Y, Z: viz.Position.Z}
+			}
+			gn.Color = fromColor(viz.Color)
+			if viz.Size != 0 {
+				gn.Size = &gexf12.Size{Value: viz.Size}
+			}
+			if viz.Shape != "" {
+				gn.Shape = &gexf12.NodeShape{Shape: viz.Shape, URI: viz.Image}
+			}
+		}
+		c.Graph.Nodes.Nodes = append(c.Graph.Nodes.Nodes, gn)
+	}
+	c.Graph.Nodes.Count = len(nodes)
+
+	edgeAttrs := newAttributes("edge")
+	for _, n := range nodes {
+		uid := n.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			vid := t.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			e := g.Edge(uid, vid)
+			ge := gexf12.Edge{
+				ID:     strconv.Itoa(len(c.Graph.Edges.Edges)),
+				Source: nodeID(n),
+				Target: nodeID(t),
+			}
+			if w, ok := e.(graph.WeightedEdge); ok {
+				ge.Weight = w.Weight()
+			}
+			if l, ok := e.(Labeler); ok {
+				ge.Label = l.GEXFLabel()
+			}
+			if a, ok := e.(encoding.Attributer); ok {
+				ge.AttValues = edgeAttrs.values(a.Attributes())
+			}
+			if v, ok := e.(EdgeVisualizer); ok {
+				viz := v.GEXFEdgeViz()
+				ge.Color = fromColor(viz.Color)
+				if viz.Thickness != 0 {
+					ge.Thickness = &gexf12.Thickness{Value: viz.Thickness}
+				}
+				if viz.Shape != "" {
+					ge.Shape = &gexf12.Edgeshape{Shape: viz.Shape}
+				}
+			}
+			c.Graph.Edges.Edges = append(c.Graph.Edges.Edges, ge)
+		}
+	}
+	c.Graph.Edges.Count = len(c.Graph.Edges.Edges)
+
+	for _, a := range []*attributes{nodeAttrs, edgeAttrs} {
+		if len(a.decl.Attributes) != 0 {
+			c.Graph.Attributes = append(c.Graph.Attributes, a.decl)
+		}
+	}
+
+	b, err := xml.MarshalIndent(c, prefix, indent)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(prefix+xml.Header), b...), nil
+}
+
+// nodeID returns the GEXF node ID of n.
+func nodeID(n graph.Node) string {
+	if n, ok := n.(Node); ok {
+		return n.GEXFID()
+	}
+	return strconv.FormatInt(n.ID(), 10)
+}
+
+// attributes holds the attribute declarations
+// of a GEXF attribute class.
+type attributes struct {
+	decl gexf12.Attributes
+	ids  map[string]string
+}
+
+func newAttributes(class string) *attributes {
+	return &attributes{
+		decl: gexf12.Attributes{Class: class},
+		ids:  make(map[string]string),
+	}
+}
+
+// values returns the GEXF attribute values for attrs, declaring any
+// attribute keys that have not been seen before.
+func (a *attributes) values(attrs []encoding.Attribute) *gexf12.AttValues {
+	if len(attrs) == 0 {
+		return nil
+	}
+	var v gexf12.AttValues
+	for _, attr := range attrs {
+		id, ok := a.ids[attr.Key]
+		if !ok {
+			id = strconv.Itoa(len(a.ids))
+			a.ids[attr.Key] = id
+			a.decl.Attributes = append(a.decl.Attributes, gexf12.Attribute{
+				ID:    id,
+				Title: attr.Key,
+				Type:  "string",
+			})
+		}
+		v.AttValues = append(v.AttValues, gexf12.AttValue{For: id, Value: attr.Value})
+	}
+	return &v
+}
@@ -0,0 +1,85 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gexf
+
+import (
+	"image/color"
+	"math"
+
+	"gonum.org/v1/gonum/graph/formats/gexf12"
+)
+
+// NodeViz holds the GEXF viz extension properties of a node.
+type NodeViz struct {
+	// Position is the position of the node.
+	// A nil Position is not encoded.
+	Position *Position
+
+	// Color is the color of the node.
+	// A nil Color is not encoded.
+	Color color.Color
+
+	// Size is the size of the node.
+	// A zero Size is not encoded.
+	Size float64
+
+	// Shape is the shape of the node, one
+	// of "disc", "square", "triangle",
+	// "diamond" or "image". An empty Shape
+	// is not encoded.
+	Shape string
+
+	// Image is the URI of the image
+	// used when Shape is "image".
+	Image string
+}
+
+// Position is a node position.
+type Position struct {
+	X, Y, Z float64
+}
+
+// EdgeViz holds the GEXF viz extension properties of an edge.
+type EdgeViz struct {
+	// Color is the color of the edge.
+	// A nil Color is not encoded.
+	Color color.Color
+
+	// Thickness is the thickness of the
+	// edge. A zero Thickness is not encoded.
+	Thickness float64
+
+	// Shape is the shape of the edge, one
+	// of "solid", "dotted", "dashed" or
+	// "double". An empty Shape is not
+	// encoded.
+	Shape string
+}
+
+// fromColor returns the GEXF color corresponding to c.
+func fromColor(c color.Color) *gexf12.Color {
+	if c == nil {
+		return nil
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	gc := gexf12.Color{R: n.R, G: n.G, B: n.B}
+	if n.A != math.MaxUint8 {
+		gc.A = float64(n.A) / math.MaxUint8
+	}
+	return &gc
+}
+
+// toColor returns the color corresponding to the GEXF color c.
+// An absent alpha value is opaque.
+func toColor(c *gexf12.Color) color.Color {
+	if c == nil {
+		return nil
+	}
+	a := uint8(math.MaxUint8)
+	if c.A != 0 {
+		a = uint8(math.Round(math.Min(c.A, 1) * math.MaxUint8))
+	}
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: a}
+}
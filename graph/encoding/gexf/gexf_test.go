@@ -0,0 +1,316 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gexf
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// vizGraph is a directed graph of vizNodes and vizEdges.
+type vizGraph struct {
+	*simple.WeightedDirectedGraph
+}
+
+func newVizGraph() vizGraph {
+	return vizGraph{simple.NewWeightedDirectedGraph(0, 0)}
+}
+
+func (g vizGraph) NewNode() graph.Node {
+	return &vizNode{id: g.WeightedDirectedGraph.NewNode().ID()}
+}
+
+func (g vizGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return &vizEdge{from: from, to: to, weight: 1}
+}
+
+func (g vizGraph) SetEdge(e graph.Edge) {
+	g.SetWeightedEdge(e.(*vizEdge))
+}
+
+func (g vizGraph) NewWeightedEdge(from, to graph.Node, weight float64) graph.WeightedEdge {
+	return &vizEdge{from: from, to: to, weight: weight}
+}
+
+type vizNode struct {
+	id    int64
+	gexf  string
+	label string
+	attrs []encoding.Attribute
+	viz   NodeViz
+}
+
+func (n *vizNode) ID() int64                  { return n.id }
+func (n *vizNode) GEXFID() string             { return n.gexf }
+func (n *vizNode) SetGEXFID(id string)        { n.gexf = id }
+func (n *vizNode) GEXFLabel() string          { return n.label }
+func (n *vizNode) SetGEXFLabel(l string)      { n.label = l }
+func (n *vizNode) GEXFNodeViz() NodeViz       { return n.viz }
+func (n *vizNode) SetGEXFNodeViz(viz NodeViz) { n.viz = viz }
+func (n *vizNode) Attributes() []encoding.Attribute {
+	return n.attrs
+}
+func (n *vizNode) SetAttribute(a encoding.Attribute) error {
+	n.attrs = append(n.attrs, a)
+	return nil
+}
+
+type vizEdge struct {
+	from, to graph.Node
+	weight   float64
+	label    string
+	attrs    []encoding.Attribute
+	viz      EdgeViz
+}
+
+func (e *vizEdge) From() graph.Node           { return e.from }
+func (e *vizEdge) To() graph.Node             { return e.to }
+func (e *vizEdge) Weight() float64            { return e.weight }
+func (e *vizEdge) GEXFLabel() string          { return e.label }
+func (e *vizEdge) SetGEXFLabel(l string)      { e.label = l }
+func (e *vizEdge) GEXFEdgeViz() EdgeViz       { return e.viz }
+func (e *vizEdge) SetGEXFEdgeViz(viz EdgeViz) { e.viz = viz }
+func (e *vizEdge) ReversedEdge() graph.Edge {
+	r := *e
+	r.from, r.to = e.to, e.from
+	return &r
+}
+func (e *vizEdge) Attributes() []encoding.Attribute {
+	return e.attrs
+}
+func (e *vizEdge) SetAttribute(a encoding.Attribute) error {
+	e.attrs = append(e.attrs, a)
+	return nil
+}
+
+const vizEncoding = `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+	<graph defaultedgetype="directed" idtype="string" mode="static">
+		<attributes class="node">
+			<attribute id="0" title="kind" type="string"></attribute>
+		</attributes>
+		<attributes class="edge">
+			<attribute id="0" title="since" type="string"></attribute>
+		</attributes>
+		<nodes count="3">
+			<node id="alpha" label="Alpha">
+				<attvalues>
+					<attvalue for="0" value="hub"></attvalue>
+				</attvalues>
+				<color xmlns="http://www.gexf.net/1.2draft/viz" r="239" g="173" b="66" a="0.6"></color>
+				<position xmlns="http://www.gexf.net/1.2draft/viz" x="15.783598" y="-40.109245" z="0"></position>
+				<size xmlns="http://www.gexf.net/1.2draft/viz" value="2.5"></size>
+				<shape xmlns="http://www.gexf.net/1.2draft/viz" value="square"></shape>
+			</node>
+			<node id="beta" label="Beta">
+				<position xmlns="http://www.gexf.net/1.2draft/viz" x="0" y="0" z="0"></position>
+			</node>
+			<node id="gamma"></node>
+		</nodes>
+		<edges count="2">
+			<edge id="0" label="knows" source="alpha" target="beta" weight="0.5">
+				<attvalues>
+					<attvalue for="0" value="2010"></attvalue>
+				</attvalues>
+				<color xmlns="http://www.gexf.net/1.2draft/viz" r="0" g="0" b="255"></color>
+				<thickness xmlns="http://www.gexf.net/1.2draft/viz" value="3"></thickness>
+				<shape xmlns="http://www.gexf.net/1.2draft/viz" value="dashed"></shape>
+			</edge>
+			<edge id="1" source="beta" target="gamma" weight="1"></edge>
+		</edges>
+	</graph>
+</gexf>`
+
+func TestRoundTrip(t *testing.T) {
+	g := newVizGraph()
+	alpha := &vizNode{
+		id:    0,
+		gexf:  "alpha",
+		label: "Alpha",
+		attrs: []encoding.Attribute{{Key: "kind", Value: "hub"}},
+		viz: NodeViz{
+			Position: &Position{X: 15.783598, Y: -40.109245},
+			Color:    color.NRGBA{R: 239, G: 173, B: 66, A: 153},
+			Size:     2.5,
+			Shape:    "square",
+		},
+	}
+	beta := &vizNode{id: 1, gexf: "beta", label: "Beta", viz: NodeViz{Position: &Position{}}}
+	gamma := &vizNode{id: 2, gexf: "gamma"}
+	g.SetWeightedEdge(&vizEdge{
+		from: alpha, to: beta, weight: 0.5, label: "knows",
+		attrs: []encoding.Attribute{{Key: "since", Value: "2010"}},
+		viz:   EdgeViz{Color: color.RGBA{B: 255, A: 255}, Thickness: 3, Shape: "dashed"},
+	})
+	g.SetWeightedEdge(&vizEdge{from: beta, to: gamma, weight: 1})
+
+	got, err := Marshal(g, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(got) != vizEncoding {
+		t.Fatalf("unexpected encoding:\ngot:\n%s\nwant:\n%s", got, vizEncoding)
+	}
+
+	dst := newVizGraph()
+	err = Unmarshal(got, dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	for _, want := range []*vizNode{alpha, beta, gamma} {
+		n := dst.Node(want.id).(*vizNode)
+		if n.gexf != want.gexf || n.label != want.label || !reflect.DeepEqual(n.attrs, want.attrs) {
+			t.Errorf("unexpected node: got:%+v want:%+v", n, want)
+		}
+	}
+	n := dst.Node(0).(*vizNode)
+	if !reflect.DeepEqual(n.viz.Position, alpha.viz.Position) || n.viz.Size != 2.5 || n.viz.Shape != "square" {
+		t.Errorf("unexpected node viz: got:%+v want:%+v", n.viz, alpha.viz)
+	}
+	if n.viz.Color != alpha.viz.Color {
+		t.Errorf("unexpected node color: got:%v want:%v", n.viz.Color, alpha.viz.Color)
+	}
+	e := dst.WeightedEdge(0, 1).(*vizEdge)
+	if e.weight != 0.5 || e.label != "knows" || e.viz.Thickness != 3 || e.viz.Shape != "dashed" {
+		t.Errorf("unexpected edge: got:%+v", e)
+	}
+	if e.viz.Color != (color.NRGBA{B: 255, A: 255}) {
+		t.Errorf("unexpected edge color: got:%v", e.viz.Color)
+	}
+
+	again, err := Marshal(dst, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error remarshaling: %v", err)
+	}
+	if string(again) != vizEncoding {
+		t.Errorf("unexpected round trip encoding:\ngot:\n%s\nwant:\n%s", again, vizEncoding)
+	}
+}
+
+func TestUndirected(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	g.AddNode(simple.Node(3))
+
+	const want = `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+ <graph defaultedgetype="undirected" idtype="string" mode="static">
+  <nodes count="4">
+   <node id="0"></node>
+   <node id="1"></node>
+   <node id="2"></node>
+   <node id="3"></node>
+  </nodes>
+  <edges count="2">
+   <edge id="0" source="0" target="1"></edge>
+   <edge id="1" source="1" target="2"></edge>
+  </edges>
+ </graph>
+</gexf>`
+	got, err := Marshal(g, "", " ")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	dst := simple.NewUndirectedGraph()
+	err = Unmarshal(got, dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if dst.Nodes().Len() != 4 || dst.Edges().Len() != 2 {
+		t.Errorf("unexpected graph size: got %d nodes and %d edges", dst.Nodes().Len(), dst.Edges().Len())
+	}
+	if !dst.HasEdgeBetween(0, 1) || !dst.HasEdgeBetween(1, 2) {
+		t.Error("missing edge after round trip")
+	}
+}
+
+// gephi is a GEXF document in the form written by Gephi.
+const gephi = `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" xmlns:viz="http://www.gexf.net/1.2draft/viz" version="1.2">
+    <graph defaultedgetype="undirected">
+        <attributes class="node">
+            <attribute id="0" title="url" type="string"/>
+            <attribute id="1" title="frog" type="boolean">
+                <default>true</default>
+            </attribute>
+        </attributes>
+        <nodes>
+            <node id="a" label="glossy">
+                <attvalues>
+                    <attvalue for="0" value="https://gephi.org"/>
+                </attvalues>
+                <viz:color r="239" g="173" b="66" a="0.6"/>
+                <viz:position x="15.783598" y="40.109245" z="0.0"/>
+                <viz:size value="2.0375757"/>
+            </node>
+            <node id="b" label="matte"/>
+        </nodes>
+        <edges>
+            <edge id="0" source="a" target="b"/>
+        </edges>
+    </graph>
+</gexf>`
+
+func TestUnmarshalGephi(t *testing.T) {
+	dst := newVizGraph()
+	err := Unmarshal([]byte(gephi), dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	n := dst.Node(0).(*vizNode)
+	wantAttrs := []encoding.Attribute{{Key: "url", Value: "https://gephi.org"}, {Key: "frog", Value: "true"}}
+	if n.gexf != "a" || n.label != "glossy" || !reflect.DeepEqual(n.attrs, wantAttrs) {
+		t.Errorf("unexpected node: got:%+v", n)
+	}
+	wantViz := NodeViz{
+		Position: &Position{X: 15.783598, Y: 40.109245},
+		Color:    color.NRGBA{R: 239, G: 173, B: 66, A: 153},
+		Size:     2.0375757,
+	}
+	if !reflect.DeepEqual(n.viz, wantViz) {
+		t.Errorf("unexpected node viz: got:%+v want:%+v", n.viz, wantViz)
+	}
+	e := dst.WeightedEdge(0, 1)
+	if e == nil || e.Weight() != 1 {
+		t.Errorf("unexpected edge: got:%v", e)
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{
+		name: "missing node",
+		data: `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2"><graph><nodes><node id="a"/></nodes><edges><edge source="a" target="b"/></edges></graph></gexf>`,
+	},
+	{
+		name: "duplicate node",
+		data: `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2"><graph><nodes><node id="a"/><node id="a"/></nodes></graph></gexf>`,
+	},
+	{
+		name: "hierarchy",
+		data: `<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2"><graph><nodes><node id="a"><nodes><node id="b"/></nodes></node></nodes></graph></gexf>`,
+	},
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := Unmarshal([]byte(test.data), simple.NewDirectedGraph())
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Unmarshal parses the GML encoded data and stores the first graph of the
+// document in dst. Nodes keep the IDs given by their id keys and are created
+// with dst's MakeNode method if dst implements encoding.NodeMaker, and as
+// simple.Node values otherwise. dst must not hold nodes with IDs that are in
+// the document.
+//
+// Edges are created with dst's NewWeightedEdge method if dst is a
+// graph.WeightedEdgeAdder, using the value of the edge's weight key or 1 if
+// it has none, and with dst's NewEdge method otherwise. Edges of undirected
+// graphs, which are graphs without a non-zero directed key, are added in
+// both directions to directed destinations.
+//
+// The remaining keys of the graph, node and edge lists are set on dst,
+// nodes and edges that implement KeyValueSetter or encoding.AttributeSetter.
+func Unmarshal(data []byte, dst graph.NodeAdder) error {
+	doc, err := parse(data)
+	if err != nil {
+		return err
+	}
+	v, ok := doc.Get("graph")
+	if !ok {
+		return errors.New("gml: no graph")
+	}
+	g, ok := v.(List)
+	if !ok {
+		return errors.New("gml: graph is not a list")
+	}
+
+	wdst, isWeighted := dst.(graph.WeightedEdgeAdder)
+	udst, isUnweighted := dst.(graph.EdgeAdder)
+
+	var (
+		directed bool
+		edges    []List
+	)
+	nodes := make(map[int64]graph.Node)
+	maker, hasMaker := dst.(encoding.NodeMaker)
+	for _, kv := range g {
+		switch kv.Key {
+		case "directed":
+			d, ok := kv.Value.(int64)
+			if !ok {
+				return errors.New("gml: directed is not an integer")
+			}
+			directed = d != 0
+		case "node":
+			l, ok := kv.Value.(List)
+			if !ok {
+				return errors.New("gml: node is not a list")
+			}
+			id, err := intValue(l, "id", "node")
+			if err != nil {
+				return err
+			}
+			if _, exists := nodes[id]; exists {
+				return fmt.Errorf("gml: duplicate node id: %d", id)
+			}
+			var n graph.Node
+			if hasMaker {
+				n = maker.MakeNode(id)
+			} else {
+				n = simple.Node(id)
+			}
+			err = setKeyValues(n, l, "id")
+			if err != nil {
+				return err
+			}
+			dst.AddNode(n)
+			nodes[id] = n
+		case "edge":
+			l, ok := kv.Value.(List)
+			if !ok {
+				return errors.New("gml: edge is not a list")
+			}
+			edges = append(edges, l)
+		default:
+			err := setKeyValue(dst, kv)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(edges) != 0 && !isWeighted && !isUnweighted {
+		return errors.New("gml: destination cannot add edges")
+	}
+
+	_, reverse := dst.(graph.Directed)
+	reverse = reverse && !directed
+	for _, l := range edges {
+		var end [2]graph.Node
+		for i, key := range [2]string{"source", "target"} {
+			id, err := intValue(l, key, "edge")
+			if err != nil {
+				return err
+			}
+			n, ok := nodes[id]
+			if !ok {
+				return fmt.Errorf("gml: edge %s is not a node: %d", key, id)
+			}
+			end[i] = n
+		}
+
+		weight := 1.0
+		if isWeighted {
+			if v, ok := l.Get("weight"); ok {
+				switch v := v.(type) {
+				case int64:
+					weight = float64(v)
+				case float64:
+					weight = v
+				default:
+					return errors.New("gml: edge weight is not a number")
+				}
+			}
+		}
+
+		for _, uv := range [][2]graph.Node{end, {end[1], end[0]}} {
+			if isWeighted {
+				e := wdst.NewWeightedEdge(uv[0], uv[1], weight)
+				err := setKeyValues(e, l, "source", "target", "weight")
+				if err != nil {
+					return err
+				}
+				wdst.SetWeightedEdge(e)
+			} else {
+				e := udst.NewEdge(uv[0], uv[1])
+				err := setKeyValues(e, l, "source", "target")
+				if err != nil {
+					return err
+				}
+				udst.SetEdge(e)
+			}
+			if !reverse || end[0].ID() == end[1].ID() {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// intValue returns the integer value of key in the list l of the named kind.
+func intValue(l List, key, kind string) (int64, error) {
+	v, ok := l.Get(key)
+	if !ok {
+		return 0, fmt.Errorf("gml: %s without %s", kind, key)
+	}
+	i, ok := v.(int64)
+	if !ok {
+		return 0, fmt.Errorf("gml: %s %s is not an integer", kind, key)
+	}
+	return i, nil
+}
+
+// setKeyValues sets the key-value pairs of l on dst, skipping the pairs
+// with the reserved keys.
+func setKeyValues(dst interface{}, l List, reserved ...string) error {
+outer:
+	for _, kv := range l {
+		for _, r := range reserved {
+			if kv.Key == r {
+				continue outer
+			}
+		}
+		err := setKeyValue(dst, kv)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setKeyValue sets kv on dst if dst is a KeyValueSetter, or the
+// flattened attributes of kv if dst is an encoding.AttributeSetter.
+func setKeyValue(dst interface{}, kv KeyValue) error {
+	switch dst := dst.(type) {
+	case KeyValueSetter:
+		err := dst.SetGMLKeyValue(kv)
+		if err != nil {
+			return fmt.Errorf("gml: unable to set key %q: %v", kv.Key, err)
+		}
+	case encoding.AttributeSetter:
+		for _, attr := range flatten(nil, kv.Key, kv.Value) {
+			err := dst.SetAttribute(attr)
+			if err != nil {
+				return fmt.Errorf("gml: unable to set attribute %q: %v", attr.Key, err)
+			}
+		}
+	}
+	return nil
+}
+
+// flatten appends the attributes for the key-value pair with the given
+// key and value to dst, joining the keys of nested lists with dots.
+func flatten(dst []encoding.Attribute, key string, value interface{}) []encoding.Attribute {
+	switch v := value.(type) {
+	case List:
+		for _, kv := range v {
+			dst = flatten(dst, key+"."+kv.Key, kv.Value)
+		}
+		return dst
+	case int64:
+		return append(dst, encoding.Attribute{Key: key, Value: strconv.FormatInt(v, 10)})
+	case float64:
+		return append(dst, encoding.Attribute{Key: key, Value: formatReal(v)})
+	default:
+		return append(dst, encoding.Attribute{Key: key, Value: v.(string)})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gml implements marshaling and unmarshaling of graphs in the
+// Graph Modelling Language.
+//
+// A GML document is a list of key-value pairs where a value is an integer,
+// a real, a string or a nested list of key-value pairs. Graphs are held in
+// the list of the graph key, with each node given by a node list holding
+// an integer id and each edge by an edge list holding the ids of its
+// source and target nodes. Other keys of the graph, node and edge lists,
+// including nested lists such as graphics blocks, are read and written as
+// attributes.
+//
+// Attributes are unmarshaled into values that implement KeyValueSetter,
+// or encoding.AttributeSetter with nested lists flattened to keys joined
+// by dots, so that the x coordinate of a node's graphics block has the key
+// "graphics.x". They are marshaled from values that implement KeyValuer,
+// or encoding.Attributer with keys holding dots written as nested lists.
+//
+// See http://www.fim.uni-passau.de/fileadmin/files/lehrstuhl/brandenburg/projekte/gml/gml-technical-report.pdf
+// for the GML specification.
+package gml // import "gonum.org/v1/gonum/graph/encoding/gml"
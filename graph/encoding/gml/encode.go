@@ -0,0 +1,145 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Marshal returns the GML encoding for the graph g. Nodes are written in
+// order of ID and edges in order of source and then target node ID. The
+// edges of undirected graphs are written once, from the end point with the
+// lower ID. Edge weights are written as the weight key of edges of graphs
+// that implement graph.Weighted.
+//
+// Key-value pairs of g, its nodes and its edges are written from values
+// that implement KeyValuer or encoding.Attributer. Attribute values that
+// are valid GML integers or reals are written as numbers, and other values
+// as strings.
+func Marshal(g graph.Graph) ([]byte, error) {
+	_, directed := g.(graph.Directed)
+	wg, weighted := g.(graph.Weighted)
+
+	var l List
+	if directed {
+		l = append(l, KeyValue{Key: "directed", Value: int64(1)})
+	} else {
+		l = append(l, KeyValue{Key: "directed", Value: int64(0)})
+	}
+	l = append(l, keyValuesOf(g, "directed", "node", "edge")...)
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		nl := List{{Key: "id", Value: n.ID()}}
+		nl = append(nl, keyValuesOf(n, "id")...)
+		l = append(l, KeyValue{Key: "node", Value: nl})
+	}
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !directed && vid < uid {
+				continue
+			}
+			el := List{{Key: "source", Value: uid}, {Key: "target", Value: vid}}
+			reserved := []string{"source", "target"}
+			if weighted {
+				w, _ := wg.Weight(uid, vid)
+				el = append(el, KeyValue{Key: "weight", Value: w})
+				reserved = append(reserved, "weight")
+			}
+			el = append(el, keyValuesOf(g.Edge(uid, vid), reserved...)...)
+			l = append(l, KeyValue{Key: "edge", Value: el})
+		}
+	}
+
+	var w writer
+	err := w.keyValue(KeyValue{Key: "graph", Value: l})
+	if err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+// keyValuesOf returns the key-value pairs of v if it is a KeyValuer
+// or an encoding.Attributer, omitting pairs with the reserved keys.
+func keyValuesOf(v interface{}, reserved ...string) List {
+	var l List
+	switch v := v.(type) {
+	case KeyValuer:
+		l = v.GMLKeyValues()
+	case encoding.Attributer:
+		l = nest(v.Attributes())
+	default:
+		return nil
+	}
+	var kept List
+outer:
+	for _, kv := range l {
+		for _, r := range reserved {
+			if kv.Key == r {
+				continue outer
+			}
+		}
+		kept = append(kept, kv)
+	}
+	return kept
+}
+
+// nest returns the list of key-value pairs for attrs, with attributes
+// with keys that have a common prefix before a dot held in nested lists.
+func nest(attrs []encoding.Attribute) List {
+	var (
+		l List
+
+		// nested holds the index in l of the
+		// nested list for each prefix and inner
+		// holds the attributes of each nested
+		// list with their prefixes removed.
+		nested = make(map[string]int)
+		inner  = make(map[int][]encoding.Attribute)
+	)
+	for _, a := range attrs {
+		dot := strings.IndexByte(a.Key, '.')
+		if dot < 0 {
+			l = append(l, KeyValue{Key: a.Key, Value: valueOf(a.Value)})
+			continue
+		}
+		prefix := a.Key[:dot]
+		i, ok := nested[prefix]
+		if !ok {
+			i = len(l)
+			nested[prefix] = i
+			l = append(l, KeyValue{Key: prefix})
+		}
+		inner[i] = append(inner[i], encoding.Attribute{Key: a.Key[dot+1:], Value: a.Value})
+	}
+	for i, a := range inner {
+		l[i].Value = nest(a)
+	}
+	return l
+}
+
+// valueOf returns the GML value for the attribute value s.
+func valueOf(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	if strings.Contains(s, ".") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
@@ -0,0 +1,271 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// KeyValue is a GML key-value pair. The Value is an int64, a float64,
+// a string or a List.
+type KeyValue struct {
+	Key   string
+	Value interface{}
+}
+
+// List is a GML list of key-value pairs. Keys may be repeated.
+type List []KeyValue
+
+// Get returns the value of the first pair in l with the given key and
+// whether it exists.
+func (l List) Get(key string) (v interface{}, ok bool) {
+	for _, kv := range l {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return nil, false
+}
+
+// KeyValueSetter is implemented by graphs, nodes and edges that can set
+// GML key-value pairs, including nested lists.
+type KeyValueSetter interface {
+	SetGMLKeyValue(KeyValue) error
+}
+
+// KeyValuer is implemented by graphs, nodes and edges that have GML
+// key-value pairs.
+type KeyValuer interface {
+	GMLKeyValues() List
+}
+
+// parse returns the list of key-value pairs held in data.
+func parse(data []byte) (List, error) {
+	p := parser{src: string(data), line: 1}
+	l, err := p.list(false)
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// parser is a GML recursive descent parser.
+type parser struct {
+	src  string
+	pos  int
+	line int
+}
+
+// list parses key-value pairs up to the end of the input, or up to and
+// including a closing bracket if nested is true.
+func (p *parser) list(nested bool) (List, error) {
+	var l List
+	for {
+		p.skip()
+		if p.pos == len(p.src) {
+			if nested {
+				return nil, p.errorf("unexpected end of input in list")
+			}
+			return l, nil
+		}
+		if p.src[p.pos] == ']' {
+			if !nested {
+				return nil, p.errorf("unexpected ]")
+			}
+			p.pos++
+			return l, nil
+		}
+		key := p.word()
+		if !isKey(key) {
+			return nil, p.errorf("invalid key: %q", key)
+		}
+		p.skip()
+		if p.pos == len(p.src) {
+			return nil, p.errorf("missing value for key %q", key)
+		}
+		v, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		l = append(l, KeyValue{Key: key, Value: v})
+	}
+}
+
+// value parses a GML value.
+func (p *parser) value() (interface{}, error) {
+	switch c := p.src[p.pos]; c {
+	case '[':
+		p.pos++
+		return p.list(true)
+	case '"':
+		end := strings.IndexByte(p.src[p.pos+1:], '"')
+		if end < 0 {
+			return nil, p.errorf("unterminated string")
+		}
+		s := p.src[p.pos+1 : p.pos+1+end]
+		p.line += strings.Count(s, "\n")
+		p.pos += end + 2
+		return html.UnescapeString(s), nil
+	}
+	w := p.word()
+	switch strings.ToUpper(strings.TrimLeft(w, "+-")) {
+	case "INF":
+		if strings.HasPrefix(w, "-") {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "NAN":
+		return math.NaN(), nil
+	}
+	if !strings.ContainsAny(w, ".eE") {
+		i, err := strconv.ParseInt(w, 10, 64)
+		if err == nil {
+			return i, nil
+		}
+	}
+	f, err := strconv.ParseFloat(w, 64)
+	if err != nil {
+		return nil, p.errorf("invalid value: %q", w)
+	}
+	return f, nil
+}
+
+// skip skips white space and comment lines.
+func (p *parser) skip() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#':
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+			} else {
+				p.pos += end
+			}
+		default:
+			return
+		}
+	}
+}
+
+// word returns the run of characters up to the next white space or
+// bracket.
+func (p *parser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n[]\"", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("gml: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// isKey returns whether s is a valid GML key.
+func isKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '_':
+		case '0' <= c && c <= '9':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// writer writes GML lists.
+type writer struct {
+	buf    []byte
+	indent int
+}
+
+// list writes the key-value pairs of l.
+func (w *writer) list(l List) error {
+	for _, kv := range l {
+		err := w.keyValue(kv)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyValue writes the key-value pair kv.
+func (w *writer) keyValue(kv KeyValue) error {
+	if !isKey(kv.Key) {
+		return fmt.Errorf("gml: invalid key: %q", kv.Key)
+	}
+	for i := 0; i < w.indent; i++ {
+		w.buf = append(w.buf, "  "...)
+	}
+	w.buf = append(w.buf, kv.Key...)
+	w.buf = append(w.buf, ' ')
+	switch v := kv.Value.(type) {
+	case int64:
+		w.buf = strconv.AppendInt(w.buf, v, 10)
+	case float64:
+		w.buf = append(w.buf, formatReal(v)...)
+	case string:
+		w.buf = append(w.buf, quote(v)...)
+	case List:
+		w.buf = append(w.buf, "[\n"...)
+		w.indent++
+		err := w.list(v)
+		if err != nil {
+			return err
+		}
+		w.indent--
+		for i := 0; i < w.indent; i++ {
+			w.buf = append(w.buf, "  "...)
+		}
+		w.buf = append(w.buf, ']')
+	default:
+		return fmt.Errorf("gml: invalid value type for key %q: %T", kv.Key, kv.Value)
+	}
+	w.buf = append(w.buf, '\n')
+	return nil
+}
+
+// formatReal returns v as a GML real.
+func formatReal(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "INF"
+	case math.IsInf(v, -1):
+		return "-INF"
+	case math.IsNaN(v):
+		return "NAN"
+	}
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	if strings.Contains(s, ".") {
+		return s
+	}
+	if i := strings.IndexByte(s, 'e'); i >= 0 {
+		return s[:i] + ".0" + s[i:]
+	}
+	return s + ".0"
+}
+
+// quote returns s as a GML string, escaping double quotes and ampersands
+// as HTML character entities.
+func quote(s string) string {
+	return `"` + strings.NewReplacer("&", "&amp;", `"`, "&quot;").Replace(s) + `"`
+}
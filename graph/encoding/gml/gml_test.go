@@ -0,0 +1,257 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gml
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/attrgraph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// listGraph is an undirected graph of nodes that retain their
+// GML key-value pairs.
+type listGraph struct {
+	*simple.UndirectedGraph
+}
+
+func (g listGraph) MakeNode(id int64) graph.Node { return &listNode{id: id} }
+
+type listNode struct {
+	id int64
+	l  List
+}
+
+func (n *listNode) ID() int64          { return n.id }
+func (n *listNode) GMLKeyValues() List { return n.l }
+func (n *listNode) SetGMLKeyValue(kv KeyValue) error {
+	n.l = append(n.l, kv)
+	return nil
+}
+
+const cytoscape = `Creator "Cytoscape"
+Version 1.0
+# A directed graph with graphics blocks.
+graph	[
+	directed	1
+	label "Pathway &quot;A&quot; &amp; B"
+	node	[
+		id	-10
+		label	"TP53"
+		graphics	[
+			x	-37.5
+			y	12
+			w	35.0
+			fill	"#ff9999"
+			type	"ellipse"
+		]
+	]
+	node	[ id 4 label "MDM2" ]
+	edge	[
+		source	-10
+		target	4
+		weight	2.5
+		label	"inhibits"
+		graphics	[ width 1 Line [ point [ x 0 y 0 ] ] ]
+	]
+	edge	[ source 4 target -10 ]
+]
+`
+
+const wantCytoscape = `graph [
+  directed 1
+  label "Pathway &quot;A&quot; &amp; B"
+  node [
+    id -10
+    label "TP53"
+    graphics [
+      x -37.5
+      y 12
+      w 35.0
+      fill "#ff9999"
+      type "ellipse"
+    ]
+  ]
+  node [
+    id 4
+    label "MDM2"
+  ]
+  edge [
+    source -10
+    target 4
+    weight 2.5
+    label "inhibits"
+    graphics [
+      width 1
+      Line [
+        point [
+          x 0
+          y 0
+        ]
+      ]
+    ]
+  ]
+  edge [
+    source 4
+    target -10
+    weight 1.0
+  ]
+]
+`
+
+func TestAttributeRoundTrip(t *testing.T) {
+	g := attrgraph.NewDirected()
+	err := Unmarshal([]byte(cytoscape), g)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	want := attrgraph.Attrs{{Key: "label", Value: `Pathway "A" & B`}}
+	if !reflect.DeepEqual(*g.Attrs, want) {
+		t.Errorf("unexpected graph attributes: got:%v want:%v", *g.Attrs, want)
+	}
+	n, ok := g.Node(-10).(*attrgraph.Node)
+	if !ok {
+		t.Fatal("missing node -10")
+	}
+	wantNode := attrgraph.Attrs{
+		{Key: "label", Value: "TP53"},
+		{Key: "graphics.x", Value: "-37.5"},
+		{Key: "graphics.y", Value: "12"},
+		{Key: "graphics.w", Value: "35.0"},
+		{Key: "graphics.fill", Value: "#ff9999"},
+		{Key: "graphics.type", Value: "ellipse"},
+	}
+	if !reflect.DeepEqual(n.Attr, wantNode) {
+		t.Errorf("unexpected node attributes:\ngot: %v\nwant:%v", n.Attr, wantNode)
+	}
+	e, ok := g.WeightedEdge(-10, 4).(*attrgraph.Edge)
+	if !ok {
+		t.Fatal("missing edge -10->4")
+	}
+	if e.Weight() != 2.5 {
+		t.Errorf("unexpected edge weight: got:%v want:2.5", e.Weight())
+	}
+	if w, _ := g.Weight(4, -10); w != 1 {
+		t.Errorf("unexpected default edge weight: got:%v want:1", w)
+	}
+
+	buf, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(buf) != wantCytoscape {
+		t.Errorf("unexpected GML:\ngot:\n%s\nwant:\n%s", buf, wantCytoscape)
+	}
+}
+
+func TestKeyValues(t *testing.T) {
+	const in = `graph [
+  node [ id 1 graphics [ x 1.5 y -2 ] label "a" ]
+  node [ id 2 ]
+  node [ id 3 ]
+  edge [ source 1 target 2 ]
+  edge [ source 3 target 2 ]
+]`
+	g := listGraph{simple.NewUndirectedGraph()}
+	err := Unmarshal([]byte(in), g)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	want := List{
+		{Key: "graphics", Value: List{{Key: "x", Value: 1.5}, {Key: "y", Value: int64(-2)}}},
+		{Key: "label", Value: "a"},
+	}
+	if got := g.Node(1).(*listNode).l; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected key-value pairs: got:%v want:%v", got, want)
+	}
+	if !g.HasEdgeBetween(2, 3) || g.Edges().Len() != 2 {
+		t.Error("unexpected edges")
+	}
+
+	d := simple.NewDirectedGraph()
+	err = Unmarshal([]byte(in), d)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if d.Edges().Len() != 4 {
+		t.Errorf("undirected edges not added in both directions: got %d edges", d.Edges().Len())
+	}
+
+	buf, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	got := listGraph{simple.NewUndirectedGraph()}
+	err = Unmarshal(buf, got)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling marshaled graph: %v", err)
+	}
+	if l := got.Node(1).(*listNode).l; !reflect.DeepEqual(l, want) {
+		t.Errorf("unexpected key-value pairs after round trip: got:%v want:%v", l, want)
+	}
+}
+
+func TestValues(t *testing.T) {
+	l, err := parse([]byte(`a 1 b -2.5e3 c +INF d NAN e "multi
+line" f [ ]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(l) != 6 {
+		t.Fatalf("unexpected number of pairs: got:%d want:6", len(l))
+	}
+	if l[0].Value != int64(1) || l[1].Value != -2500.0 || l[2].Value != math.Inf(1) || l[4].Value != "multi\nline" {
+		t.Errorf("unexpected values: %v", l)
+	}
+	if f, ok := l[3].Value.(float64); !ok || !math.IsNaN(f) {
+		t.Errorf("unexpected NaN value: %v", l[3].Value)
+	}
+	if v, ok := l[5].Value.(List); !ok || len(v) != 0 {
+		t.Errorf("unexpected empty list value: %#v", l[5].Value)
+	}
+
+	for _, test := range []struct {
+		v    float64
+		want string
+	}{
+		{v: 2, want: "2.0"},
+		{v: 1e21, want: "1.0e+21"},
+		{v: -0.25, want: "-0.25"},
+		{v: math.Inf(-1), want: "-INF"},
+	} {
+		if got := formatReal(test.v); got != test.want {
+			t.Errorf("unexpected formatting of %v: got:%s want:%s", test.v, got, test.want)
+		}
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+	}{
+		{name: "no graph", in: `Creator "x"`},
+		{name: "unclosed list", in: `graph [ node [ id 1 ]`},
+		{name: "unexpected close", in: `graph [ ] ]`},
+		{name: "bad key", in: `graph [ 1node [ id 1 ] ]`},
+		{name: "missing value", in: `graph [ directed`},
+		{name: "bad value", in: `graph [ directed yes ]`},
+		{name: "unterminated string", in: `graph [ label "a ]`},
+		{name: "node without id", in: `graph [ node [ label "a" ] ]`},
+		{name: "real id", in: `graph [ node [ id 1.0 ] ]`},
+		{name: "duplicate id", in: `graph [ node [ id 1 ] node [ id 1 ] ]`},
+		{name: "missing target", in: `graph [ node [ id 1 ] edge [ source 1 ] ]`},
+		{name: "unknown target", in: `graph [ node [ id 1 ] edge [ source 1 target 2 ] ]`},
+		{name: "bad weight", in: `graph [ node [ id 1 ] node [ id 2 ] edge [ source 1 target 2 weight "heavy" ] ]`},
+	} {
+		err := Unmarshal([]byte(test.in), simple.NewWeightedDirectedGraph(0, 0))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package jsongraph implements JSON marshaling and unmarshaling of graphs
+// in the node-link form used by D3 and by the NetworkX node_link_data and
+// node_link_graph functions, and in the JSON Graph Format.
+//
+// For details of the JSON Graph Format see https://jsongraphformat.info/.
+package jsongraph // import "gonum.org/v1/gonum/graph/encoding/jsongraph"
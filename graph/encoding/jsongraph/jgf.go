@@ -0,0 +1,311 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsongraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// jgfGraph is the JSON Graph Format representation of a graph.
+type jgfGraph struct {
+	ID       json.RawMessage            `json:"id,omitempty"`
+	Type     json.RawMessage            `json:"type,omitempty"`
+	Label    json.RawMessage            `json:"label,omitempty"`
+	Directed bool                       `json:"directed"`
+	Metadata map[string]json.RawMessage `json:"metadata,omitempty"`
+	Nodes    jgfNodes                   `json:"nodes"`
+	Edges    []jgfEdge                  `json:"edges"`
+}
+
+// jgfNodes is a JSON Graph Format node object that retains the order
+// of its nodes.
+type jgfNodes []jgfNode
+
+type jgfNode struct {
+	id       string
+	Label    json.RawMessage            `json:"label,omitempty"`
+	Metadata map[string]json.RawMessage `json:"metadata,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (n jgfNodes) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, node := range n {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		b, err := json.Marshal(node.id)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteByte(':')
+		b, err = json.Marshal(node)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type jgfEdge struct {
+	Source   string                     `json:"source"`
+	Target   string                     `json:"target"`
+	Relation json.RawMessage            `json:"relation,omitempty"`
+	Label    json.RawMessage            `json:"label,omitempty"`
+	Metadata map[string]json.RawMessage `json:"metadata,omitempty"`
+}
+
+// MarshalJGF returns the JSON Graph Format encoding for the graph g,
+// applying the prefix and indent to the encoding.
+//
+// Nodes are identified by the decimal form of their numeric node ID
+// unless they implement Node. Graph, node and edge attributes are
+// obtained from values that implement Attributer or encoding.Attributer.
+// The label attribute of graphs, nodes and edges, the id and type
+// attributes of graphs and the relation attribute of edges are encoded
+// as the corresponding JSON Graph Format fields, and all other
+// attributes are encoded as metadata. The weights of graph.WeightedEdge
+// values are encoded as the weight metadata value.
+func MarshalJGF(g graph.Graph, prefix, indent string) ([]byte, error) {
+	_, isDirected := g.(graph.Directed)
+	jg := jgfGraph{
+		Directed: isDirected,
+		Nodes:    jgfNodes{},
+		Edges:    []jgfEdge{},
+	}
+	attrs, err := attributesOf(g)
+	if err != nil {
+		return nil, err
+	}
+	jg.ID, attrs = take(attrs, "id")
+	jg.Type, attrs = take(attrs, "type")
+	jg.Label, jg.Metadata = take(attrs, "label")
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		attrs, err := attributesOf(n)
+		if err != nil {
+			return nil, err
+		}
+		node := jgfNode{id: nodeIDString(n)}
+		node.Label, node.Metadata = take(attrs, "label")
+		jg.Nodes = append(jg.Nodes, node)
+	}
+
+	for _, n := range nodes {
+		uid := n.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			vid := t.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			attrs, err := edgeAttributes(g.Edge(uid, vid))
+			if err != nil {
+				return nil, err
+			}
+			e := jgfEdge{Source: nodeIDString(n), Target: nodeIDString(t)}
+			e.Relation, attrs = take(attrs, "relation")
+			e.Label, e.Metadata = take(attrs, "label")
+			jg.Edges = append(jg.Edges, e)
+		}
+	}
+
+	return json.MarshalIndent(struct {
+		Graph jgfGraph `json:"graph"`
+	}{jg}, prefix, indent)
+}
+
+// take returns the value of key in attrs and a copy of attrs without key.
+func take(attrs map[string]json.RawMessage, key string) (json.RawMessage, map[string]json.RawMessage) {
+	v, ok := attrs[key]
+	if !ok {
+		return nil, attrs
+	}
+	if len(attrs) == 1 {
+		return v, nil
+	}
+	m := make(map[string]json.RawMessage, len(attrs)-1)
+	for k, a := range attrs {
+		if k != key {
+			m[k] = a
+		}
+	}
+	return v, m
+}
+
+// UnmarshalJGF parses the JSON Graph Format-encoded data and stores the
+// result in dst. Both the object and the array forms of the list of nodes
+// are accepted. If the number of graphs encoded in data is not one, an
+// error is returned and dst will hold the first graph in data.
+//
+// Nodes are created by dst and have their JSON ID set if they implement
+// JSONIDSetter. JSON Graph Format label, id, type and relation fields and
+// metadata values are set as attributes on the graph, nodes and edges if
+// they implement AttributeSetter or encoding.AttributeSetter. If dst is a
+// graph.WeightedBuilder, edges are created with the value of their weight
+// metadata, or one if it is absent.
+func UnmarshalJGF(data []byte, dst encoding.Builder) error {
+	var raw struct {
+		Graph  *json.RawMessage  `json:"graph"`
+		Graphs []json.RawMessage `json:"graphs"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	switch {
+	case raw.Graph != nil && raw.Graphs == nil:
+		return unmarshalJGFGraph(*raw.Graph, dst)
+	case raw.Graph == nil && len(raw.Graphs) != 0:
+		err = unmarshalJGFGraph(raw.Graphs[0], dst)
+		if err == nil && len(raw.Graphs) != 1 {
+			err = fmt.Errorf("jsongraph: invalid number of graphs; expected 1, got %d", len(raw.Graphs))
+		}
+		return err
+	default:
+		return errors.New("jsongraph: invalid number of graphs")
+	}
+}
+
+func unmarshalJGFGraph(data []byte, dst encoding.Builder) error {
+	var g object
+	err := json.Unmarshal(data, &g)
+	if err != nil {
+		return err
+	}
+	var edges []object
+	if raw, ok := g.values["edges"]; ok {
+		err = json.Unmarshal(raw, &edges)
+		if err != nil {
+			return err
+		}
+	}
+
+	graphKeys, graphAttrs, err := flatten(g, "nodes", "edges", "directed")
+	if err != nil {
+		return err
+	}
+	for _, k := range graphKeys {
+		err = setAttribute(dst, k, graphAttrs[k])
+		if err != nil {
+			return fmt.Errorf("jsongraph: unable to unmarshal graph attribute %s: %v", k, err)
+		}
+	}
+
+	b := newBuilder(dst)
+	nodes := bytes.TrimSpace(g.values["nodes"])
+	switch {
+	case len(nodes) == 0 || bytes.Equal(nodes, []byte("null")):
+	case nodes[0] == '{':
+		var o object
+		err = json.Unmarshal(nodes, &o)
+		if err != nil {
+			return err
+		}
+		for _, id := range o.keys {
+			var n object
+			err = json.Unmarshal(o.values[id], &n)
+			if err != nil {
+				return err
+			}
+			keys, attrs, err := flatten(n)
+			if err != nil {
+				return err
+			}
+			err = b.addNode(id, attrs, keys)
+			if err != nil {
+				return err
+			}
+		}
+	case nodes[0] == '[':
+		var list []object
+		err = json.Unmarshal(nodes, &list)
+		if err != nil {
+			return err
+		}
+		for _, n := range list {
+			idRaw, ok := n.values["id"]
+			if !ok {
+				return errors.New("jsongraph: node without id")
+			}
+			id, err := text(idRaw)
+			if err != nil {
+				return err
+			}
+			keys, attrs, err := flatten(n, "id")
+			if err != nil {
+				return err
+			}
+			err = b.addNode(id, attrs, keys)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("jsongraph: invalid nodes: %s", nodes)
+	}
+
+	for _, e := range edges {
+		from, err := endpoint(e, "source")
+		if err != nil {
+			return err
+		}
+		to, err := endpoint(e, "target")
+		if err != nil {
+			return err
+		}
+		keys, attrs, err := flatten(e, "source", "target")
+		if err != nil {
+			return err
+		}
+		err = b.addEdge(from, to, attrs, keys)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flatten returns the keys and values of the fields of o other than those
+// in exclude, with the fields of any metadata object lifted into the
+// returned values.
+func flatten(o object, exclude ...string) ([]string, map[string]json.RawMessage, error) {
+	var keys []string
+	attrs := make(map[string]json.RawMessage)
+	for _, k := range without(o.keys, exclude...) {
+		if k != "metadata" {
+			keys = append(keys, k)
+			attrs[k] = o.values[k]
+			continue
+		}
+		var meta object
+		err := json.Unmarshal(o.values[k], &meta)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, mk := range meta.keys {
+			if _, exists := attrs[mk]; !exists {
+				keys = append(keys, mk)
+			}
+			attrs[mk] = meta.values[mk]
+		}
+	}
+	return keys, attrs, nil
+}
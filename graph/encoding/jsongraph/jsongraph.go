@@ -0,0 +1,267 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsongraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// Node is a JSON graph node with a string ID.
+type Node interface {
+	// JSONID returns the JSON ID of the node.
+	JSONID() string
+}
+
+// JSONIDSetter is implemented by types that can set a JSON node ID.
+type JSONIDSetter interface {
+	// SetJSONID sets the JSON ID of the receiver.
+	// String IDs are passed unquoted and other
+	// IDs are passed as their JSON text.
+	SetJSONID(id string)
+}
+
+// Attributer is implemented by graphs, nodes and edges that hold
+// JSON-valued attributes.
+type Attributer interface {
+	JSONAttributes() map[string]json.RawMessage
+}
+
+// AttributeSetter is implemented by graphs, nodes and edges that can
+// set JSON-valued attributes.
+type AttributeSetter interface {
+	SetJSONAttribute(key string, value json.RawMessage) error
+}
+
+// attributesOf returns the JSON attributes of v. If v implements
+// Attributer, its attributes are returned. Otherwise if v implements
+// encoding.Attributer, its attributes are returned as JSON strings.
+func attributesOf(v interface{}) (map[string]json.RawMessage, error) {
+	switch v := v.(type) {
+	case Attributer:
+		return v.JSONAttributes(), nil
+	case encoding.Attributer:
+		attrs := v.Attributes()
+		if len(attrs) == 0 {
+			return nil, nil
+		}
+		m := make(map[string]json.RawMessage, len(attrs))
+		for _, a := range attrs {
+			b, err := json.Marshal(a.Value)
+			if err != nil {
+				return nil, err
+			}
+			m[a.Key] = b
+		}
+		return m, nil
+	default:
+		return nil, nil
+	}
+}
+
+// setAttribute sets the JSON attribute key to value on dst. If dst
+// implements AttributeSetter, the raw value is set. Otherwise if dst
+// implements encoding.AttributeSetter, string values are set unquoted
+// and other values are set as their compacted JSON text.
+func setAttribute(dst interface{}, key string, value json.RawMessage) error {
+	switch dst := dst.(type) {
+	case AttributeSetter:
+		return dst.SetJSONAttribute(key, value)
+	case encoding.AttributeSetter:
+		s, err := text(value)
+		if err != nil {
+			return err
+		}
+		return dst.SetAttribute(encoding.Attribute{Key: key, Value: s})
+	default:
+		return nil
+	}
+}
+
+// text returns the unquoted string held by a JSON string value or the
+// compacted JSON text of any other value.
+func text(value json.RawMessage) (string, error) {
+	value = bytes.TrimSpace(value)
+	if len(value) != 0 && value[0] == '"' {
+		var s string
+		err := json.Unmarshal(value, &s)
+		return s, err
+	}
+	var buf bytes.Buffer
+	err := json.Compact(&buf, value)
+	return buf.String(), err
+}
+
+// nodeID returns the JSON ID of n. Nodes implementing Node have string
+// IDs and all others have their numeric graph node ID.
+func nodeID(n graph.Node) json.RawMessage {
+	if n, ok := n.(Node); ok {
+		b, _ := json.Marshal(n.JSONID())
+		return b
+	}
+	return json.RawMessage(strconv.FormatInt(n.ID(), 10))
+}
+
+// nodeIDString returns the string form of the JSON ID of n used
+// for keys in JSON Graph Format node objects.
+func nodeIDString(n graph.Node) string {
+	if n, ok := n.(Node); ok {
+		return n.JSONID()
+	}
+	return strconv.FormatInt(n.ID(), 10)
+}
+
+// builder holds the state for unmarshaling a graph.
+type builder struct {
+	dst encoding.Builder
+	ids map[string]graph.Node
+
+	wb       graph.WeightedBuilder
+	weighted bool
+}
+
+func newBuilder(dst encoding.Builder) *builder {
+	wb, weighted := dst.(graph.WeightedBuilder)
+	return &builder{
+		dst:      dst,
+		ids:      make(map[string]graph.Node),
+		wb:       wb,
+		weighted: weighted,
+	}
+}
+
+// addNode adds a new node with the given ID and attributes to dst.
+func (b *builder) addNode(id string, attrs map[string]json.RawMessage, keys []string) error {
+	if _, exists := b.ids[id]; exists {
+		return fmt.Errorf("jsongraph: duplicate node ID %q", id)
+	}
+	n := b.dst.NewNode()
+	if s, ok := n.(JSONIDSetter); ok {
+		s.SetJSONID(id)
+	}
+	for _, k := range keys {
+		err := setAttribute(n, k, attrs[k])
+		if err != nil {
+			return fmt.Errorf("jsongraph: unable to unmarshal node %q attribute %s: %v", id, k, err)
+		}
+	}
+	b.dst.AddNode(n)
+	b.ids[id] = n
+	return nil
+}
+
+// addEdge adds a new edge between the nodes with the given IDs to dst.
+// If dst is a graph.WeightedBuilder, the edge is given the weight held
+// by the weight attribute if it exists, or one otherwise, and the weight
+// attribute is not set on the edge.
+func (b *builder) addEdge(from, to string, attrs map[string]json.RawMessage, keys []string) error {
+	u, ok := b.ids[from]
+	if !ok {
+		return fmt.Errorf("jsongraph: edge source %q not found", from)
+	}
+	v, ok := b.ids[to]
+	if !ok {
+		return fmt.Errorf("jsongraph: edge target %q not found", to)
+	}
+	var e graph.Edge
+	if b.weighted {
+		w := 1.0
+		if raw, ok := attrs[weightKey]; ok {
+			err := json.Unmarshal(raw, &w)
+			if err != nil {
+				return fmt.Errorf("jsongraph: invalid weight for edge %q--%q: %v", from, to, err)
+			}
+		}
+		e = b.wb.NewWeightedEdge(u, v, w)
+	} else {
+		e = b.dst.NewEdge(u, v)
+	}
+	for _, k := range keys {
+		if b.weighted && k == weightKey {
+			continue
+		}
+		err := setAttribute(e, k, attrs[k])
+		if err != nil {
+			return fmt.Errorf("jsongraph: unable to unmarshal edge %q--%q attribute %s: %v", from, to, k, err)
+		}
+	}
+	if b.weighted {
+		b.wb.SetWeightedEdge(e.(graph.WeightedEdge))
+	} else {
+		b.dst.SetEdge(e)
+	}
+	return nil
+}
+
+// weightKey is the attribute key used for edge weights.
+const weightKey = "weight"
+
+// edgeAttributes returns the attributes of e including its weight if it
+// is a graph.WeightedEdge.
+func edgeAttributes(e graph.Edge) (map[string]json.RawMessage, error) {
+	attrs, err := attributesOf(e)
+	if err != nil {
+		return nil, err
+	}
+	if w, ok := e.(graph.WeightedEdge); ok {
+		b, err := json.Marshal(w.Weight())
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]json.RawMessage, len(attrs)+1)
+		for k, v := range attrs {
+			m[k] = v
+		}
+		m[weightKey] = b
+		attrs = m
+	}
+	return attrs, nil
+}
+
+// object is a JSON object that retains the order of its keys.
+type object struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (o *object) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		return fmt.Errorf("jsongraph: expected object, got %s", data)
+	}
+	o.keys = o.keys[:0]
+	o.values = make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var v json.RawMessage
+		err = dec.Decode(&v)
+		if err != nil {
+			return err
+		}
+		if _, exists := o.values[key]; !exists {
+			o.keys = append(o.keys, key)
+		}
+		o.values[key] = v
+	}
+	_, err = dec.Token()
+	return err
+}
@@ -0,0 +1,322 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsongraph
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/attrgraph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// rawNode is a node with a string JSON ID and JSON-valued attributes.
+type rawNode struct {
+	id     int64
+	jsonID string
+	attrs  map[string]json.RawMessage
+}
+
+func (n *rawNode) ID() int64           { return n.id }
+func (n *rawNode) JSONID() string      { return n.jsonID }
+func (n *rawNode) SetJSONID(id string) { n.jsonID = id }
+func (n *rawNode) JSONAttributes() map[string]json.RawMessage {
+	return n.attrs
+}
+func (n *rawNode) SetJSONAttribute(key string, value json.RawMessage) error {
+	if n.attrs == nil {
+		n.attrs = make(map[string]json.RawMessage)
+	}
+	n.attrs[key] = value
+	return nil
+}
+
+// rawGraph is a directed graph of rawNodes.
+type rawGraph struct {
+	*simple.DirectedGraph
+}
+
+func (g rawGraph) NewNode() graph.Node {
+	return &rawNode{id: g.DirectedGraph.NewNode().ID()}
+}
+
+// networkx is the output of json.dumps(nx.node_link_data(g)) for a small
+// weighted graph with graph, node and edge attributes.
+const networkx = `{"directed": false, "multigraph": false, "graph": {"name": "toy"}, "nodes": [{"club": "Mr. Hi", "id": 0}, {"club": "Officer", "id": 1}, {"club": "Officer", "id": 2}], "links": [{"weight": 4, "kind": "friend", "source": 0, "target": 1}, {"weight": 0.5, "source": 1, "target": 2}]}`
+
+func TestUnmarshalNetworkX(t *testing.T) {
+	g := attrgraph.NewUndirected()
+	err := UnmarshalNodeLink([]byte(networkx), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(*g.Attrs, attrgraph.Attrs{{Key: "name", Value: "toy"}}) {
+		t.Errorf("unexpected graph attributes: %v", *g.Attrs)
+	}
+	wantClub := []string{"Mr. Hi", "Officer", "Officer"}
+	for id, club := range wantClub {
+		n := g.Node(int64(id)).(*attrgraph.Node)
+		want := attrgraph.Attrs{{Key: "club", Value: club}}
+		if !reflect.DeepEqual(n.Attr, want) {
+			t.Errorf("unexpected attributes for node %d: got:%v want:%v", id, n.Attr, want)
+		}
+	}
+	e := g.WeightedEdge(0, 1).(*attrgraph.Edge)
+	if e.W != 4 || !reflect.DeepEqual(e.Attrs, attrgraph.Attrs{{Key: "kind", Value: "friend"}}) {
+		t.Errorf("unexpected edge 0--1: weight=%v attrs=%v", e.W, e.Attrs)
+	}
+	if w := g.WeightedEdge(1, 2).Weight(); w != 0.5 {
+		t.Errorf("unexpected edge 1--2 weight: got:%v want:0.5", w)
+	}
+
+	const wantNodeLink = `{
+	"directed": false,
+	"multigraph": false,
+	"graph": {
+		"name": "toy"
+	},
+	"nodes": [
+		{
+			"club": "Mr. Hi",
+			"id": 0
+		},
+		{
+			"club": "Officer",
+			"id": 1
+		},
+		{
+			"club": "Officer",
+			"id": 2
+		}
+	],
+	"links": [
+		{
+			"kind": "friend",
+			"source": 0,
+			"target": 1,
+			"weight": 4
+		},
+		{
+			"source": 1,
+			"target": 2,
+			"weight": 0.5
+		}
+	]
+}`
+	got, err := MarshalNodeLink(g, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(got) != wantNodeLink {
+		t.Errorf("unexpected node-link encoding:\ngot:\n%s\nwant:\n%s", got, wantNodeLink)
+	}
+
+	const wantJGF = `{
+	"graph": {
+		"directed": false,
+		"metadata": {
+			"name": "toy"
+		},
+		"nodes": {
+			"0": {
+				"metadata": {
+					"club": "Mr. Hi"
+				}
+			},
+			"1": {
+				"metadata": {
+					"club": "Officer"
+				}
+			},
+			"2": {
+				"metadata": {
+					"club": "Officer"
+				}
+			}
+		},
+		"edges": [
+			{
+				"source": "0",
+				"target": "1",
+				"metadata": {
+					"kind": "friend",
+					"weight": 4
+				}
+			},
+			{
+				"source": "1",
+				"target": "2",
+				"metadata": {
+					"weight": 0.5
+				}
+			}
+		]
+	}
+}`
+	got, err = MarshalJGF(g, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(got) != wantJGF {
+		t.Errorf("unexpected JGF encoding:\ngot:\n%s\nwant:\n%s", got, wantJGF)
+	}
+
+	h := attrgraph.NewUndirected()
+	err = UnmarshalJGF(got, h)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling JGF: %v", err)
+	}
+	again, err := MarshalNodeLink(h, "", "\t")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(again) != wantNodeLink {
+		t.Errorf("unexpected round trip encoding:\ngot:\n%s\nwant:\n%s", again, wantNodeLink)
+	}
+}
+
+// jgfSpec is derived from the examples in the JSON Graph Format
+// specification.
+var jgfSpec = []struct {
+	name string
+	data string
+}{
+	{
+		name: "v2",
+		data: `{
+  "graph": {
+    "id": "car-manufacturer-relationships",
+    "type": "car",
+    "label": "Car Manufacturer Relationships",
+    "directed": true,
+    "nodes": {
+      "nissan": {"label": "Nissan", "metadata": {"country": "Japan", "founded": 1933}},
+      "infiniti": {"label": "Infiniti"},
+      "toyota": {"label": "Toyota", "metadata": {"country": "Japan"}}
+    },
+    "edges": [
+      {"source": "nissan", "target": "infiniti", "relation": "has_luxury_division", "metadata": {"since": [1989]}},
+      {"source": "toyota", "target": "nissan", "relation": "competes_with"}
+    ]
+  }
+}`,
+	},
+	{
+		name: "v1",
+		data: `{
+  "graphs": [{
+    "id": "car-manufacturer-relationships",
+    "type": "car",
+    "label": "Car Manufacturer Relationships",
+    "directed": true,
+    "nodes": [
+      {"id": "nissan", "label": "Nissan", "metadata": {"country": "Japan", "founded": 1933}},
+      {"id": "infiniti", "label": "Infiniti"},
+      {"id": "toyota", "label": "Toyota", "metadata": {"country": "Japan"}}
+    ],
+    "edges": [
+      {"source": "nissan", "target": "infiniti", "relation": "has_luxury_division", "metadata": {"since": [1989]}},
+      {"source": "toyota", "target": "nissan", "relation": "competes_with"}
+    ]
+  }]
+}`,
+	},
+}
+
+func TestUnmarshalJGFSpec(t *testing.T) {
+	const want = `{
+  "graph": {
+    "nodes": {
+      "nissan": {
+        "label": "Nissan",
+        "metadata": {
+          "country": "Japan",
+          "founded": 1933
+        }
+      },
+      "infiniti": {
+        "label": "Infiniti"
+      },
+      "toyota": {
+        "label": "Toyota",
+        "metadata": {
+          "country": "Japan"
+        }
+      }
+    },
+    "edges": [
+      {
+        "source": "nissan",
+        "target": "infiniti"
+      },
+      {
+        "source": "toyota",
+        "target": "nissan"
+      }
+    ],
+    "directed": true
+  }
+}`
+	for _, test := range jgfSpec {
+		g := rawGraph{simple.NewDirectedGraph()}
+		err := UnmarshalJGF([]byte(test.data), g)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		if g.Nodes().Len() != 3 || g.Edges().Len() != 2 {
+			t.Errorf("unexpected graph size for %s: %d nodes and %d edges", test.name, g.Nodes().Len(), g.Edges().Len())
+		}
+		n := g.Node(0).(*rawNode)
+		if n.jsonID != "nissan" || string(n.attrs["founded"]) != "1933" || string(n.attrs["label"]) != `"Nissan"` {
+			t.Errorf("unexpected first node for %s: %+v", test.name, n)
+		}
+		if !g.HasEdgeFromTo(2, 0) {
+			t.Errorf("missing edge toyota->nissan for %s", test.name)
+		}
+
+		got, err := MarshalJGF(g, "", "  ")
+		if err != nil {
+			t.Errorf("unexpected error marshaling %s: %v", test.name, err)
+			continue
+		}
+		var gotJSON, wantJSON interface{}
+		json.Unmarshal(got, &gotJSON)
+		json.Unmarshal([]byte(want), &wantJSON)
+		if !reflect.DeepEqual(gotJSON, wantJSON) {
+			t.Errorf("unexpected encoding for %s:\ngot:\n%s\nwant:\n%s", test.name, got, want)
+		}
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+	jgf  bool
+}{
+	{name: "missing node", data: `{"nodes": [{"id": 0}], "links": [{"source": 0, "target": 1}]}`},
+	{name: "duplicate node", data: `{"nodes": [{"id": 0}, {"id": 0}], "links": []}`},
+	{name: "missing id", data: `{"nodes": [{"name": "a"}], "links": []}`},
+	{name: "multigraph", data: `{"multigraph": true, "nodes": [], "links": []}`},
+	{name: "no graph", data: `{}`, jgf: true},
+	{name: "two graphs", data: `{"graphs": [{"nodes": {}}, {"nodes": {}}]}`, jgf: true},
+	{name: "bad nodes", data: `{"graph": {"nodes": 1}}`, jgf: true},
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		var err error
+		if test.jgf {
+			err = UnmarshalJGF([]byte(test.data), simple.NewDirectedGraph())
+		} else {
+			err = UnmarshalNodeLink([]byte(test.data), simple.NewDirectedGraph())
+		}
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jsongraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// nodeLink is the node-link JSON representation of a graph.
+type nodeLink struct {
+	Directed   bool                         `json:"directed"`
+	Multigraph bool                         `json:"multigraph"`
+	Graph      map[string]json.RawMessage   `json:"graph"`
+	Nodes      []map[string]json.RawMessage `json:"nodes"`
+	Links      []map[string]json.RawMessage `json:"links"`
+}
+
+// MarshalNodeLink returns the node-link JSON encoding for the graph g,
+// applying the prefix and indent to the encoding. The encoding is
+// compatible with the NetworkX node_link_graph function and D3 force
+// layouts.
+//
+// Nodes are identified by their numeric node ID unless they implement
+// Node. Graph, node and edge attributes are obtained from values that
+// implement Attributer or encoding.Attributer, and the weights of
+// graph.WeightedEdge values are encoded as the weight attribute. The
+// id attribute of nodes and the source and target attributes of edges
+// are reserved.
+func MarshalNodeLink(g graph.Graph, prefix, indent string) ([]byte, error) {
+	_, isDirected := g.(graph.Directed)
+	nl := nodeLink{
+		Directed: isDirected,
+		Graph:    make(map[string]json.RawMessage),
+		Nodes:    []map[string]json.RawMessage{},
+		Links:    []map[string]json.RawMessage{},
+	}
+	attrs, err := attributesOf(g)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range attrs {
+		nl.Graph[k] = v
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		attrs, err := attributesOf(n)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]json.RawMessage, len(attrs)+1)
+		for k, v := range attrs {
+			m[k] = v
+		}
+		m["id"] = nodeID(n)
+		nl.Nodes = append(nl.Nodes, m)
+	}
+
+	for _, n := range nodes {
+		uid := n.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			vid := t.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			attrs, err := edgeAttributes(g.Edge(uid, vid))
+			if err != nil {
+				return nil, err
+			}
+			m := make(map[string]json.RawMessage, len(attrs)+2)
+			for k, v := range attrs {
+				m[k] = v
+			}
+			m["source"] = nodeID(n)
+			m["target"] = nodeID(t)
+			nl.Links = append(nl.Links, m)
+		}
+	}
+
+	return json.MarshalIndent(nl, prefix, indent)
+}
+
+// UnmarshalNodeLink parses the node-link JSON-encoded data and stores the
+// result in dst. Both the links and edges keys used by NetworkX for the
+// list of edges are accepted. Multigraph encodings are not supported.
+//
+// Nodes are created by dst and have their JSON ID set if they implement
+// JSONIDSetter. Attributes other than the reserved id, source and target
+// attributes are set on the graph, nodes and edges if they implement
+// AttributeSetter or encoding.AttributeSetter. If dst is a
+// graph.WeightedBuilder, edges are created with the value of their weight
+// attribute, or one if it is absent.
+func UnmarshalNodeLink(data []byte, dst encoding.Builder) error {
+	var raw struct {
+		Multigraph bool     `json:"multigraph"`
+		Graph      object   `json:"graph"`
+		Nodes      []object `json:"nodes"`
+		Links      []object `json:"links"`
+		Edges      []object `json:"edges"`
+	}
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	if raw.Multigraph {
+		return errors.New("jsongraph: multigraph encodings not supported")
+	}
+	if raw.Links != nil && raw.Edges != nil {
+		return errors.New("jsongraph: both links and edges present")
+	}
+
+	for _, k := range raw.Graph.keys {
+		err = setAttribute(dst, k, raw.Graph.values[k])
+		if err != nil {
+			return fmt.Errorf("jsongraph: unable to unmarshal graph attribute %s: %v", k, err)
+		}
+	}
+
+	b := newBuilder(dst)
+	for _, n := range raw.Nodes {
+		idRaw, ok := n.values["id"]
+		if !ok {
+			return errors.New("jsongraph: node without id")
+		}
+		id, err := text(idRaw)
+		if err != nil {
+			return err
+		}
+		err = b.addNode(id, n.values, without(n.keys, "id"))
+		if err != nil {
+			return err
+		}
+	}
+
+	links := raw.Links
+	if links == nil {
+		links = raw.Edges
+	}
+	for _, e := range links {
+		from, err := endpoint(e, "source")
+		if err != nil {
+			return err
+		}
+		to, err := endpoint(e, "target")
+		if err != nil {
+			return err
+		}
+		err = b.addEdge(from, to, e.values, without(e.keys, "source", "target"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endpoint returns the node ID held in the key field of e.
+func endpoint(e object, key string) (string, error) {
+	raw, ok := e.values[key]
+	if !ok {
+		return "", fmt.Errorf("jsongraph: edge without %s", key)
+	}
+	return text(raw)
+}
+
+// without returns the keys excluding those in exclude.
+func without(keys []string, exclude ...string) []string {
+	var r []string
+outer:
+	for _, k := range keys {
+		for _, x := range exclude {
+			if k == x {
+				continue outer
+			}
+		}
+		r = append(r, k)
+	}
+	return r
+}
@@ -0,0 +1,21 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package matrixmarket implements reading and writing of graphs as
+// adjacency matrices in the Matrix Market coordinate and array formats,
+// the formats of the SuiteSparse Matrix Collection.
+//
+// The rows and columns of the adjacency matrix of a graph correspond to
+// the nodes of the graph, with a non-zero entry in row i and column j for
+// each edge from the node of row i to the node of column j. Symmetric
+// matrices correspond to undirected graphs and general matrices to
+// directed graphs. Pattern matrices hold no values and correspond to
+// unweighted graphs.
+//
+// Matrices may be read and written as mat matrices with the
+// gonum.org/v1/gonum/mat/encoding/matrixmarket package.
+//
+// See https://math.nist.gov/MatrixMarket/formats.html for a description
+// of the format.
+package matrixmarket // import "gonum.org/v1/gonum/graph/encoding/matrixmarket"
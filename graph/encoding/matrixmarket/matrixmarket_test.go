@@ -0,0 +1,217 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrixmarket
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+const symmetricPattern = `%%MatrixMarket matrix coordinate pattern symmetric
+% A path with a diagonal entry.
+%
+4 4 4
+1 1
+2 1
+3 2
+
+4 3
+`
+
+const skewReal = `%%MatrixMarket matrix coordinate real skew-symmetric
+3 3 2
+2 1 1.5
+3 1 -2
+`
+
+const generalInteger = `%%MatrixMarket matrix coordinate integer general
+3 3 3
+1 2 4
+2 3 5
+3 1 6
+`
+
+func TestReadGraph(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	err := NewReader(strings.NewReader(symmetricPattern)).ReadGraph(g)
+	if err != nil {
+		t.Fatalf("unexpected error reading symmetric pattern matrix: %v", err)
+	}
+	if g.Nodes().Len() != 4 || g.Edges().Len() != 3 {
+		t.Errorf("unexpected graph size: got %d nodes and %d edges", g.Nodes().Len(), g.Edges().Len())
+	}
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {2, 3}} {
+		if !g.HasEdgeBetween(e[0], e[1]) {
+			t.Errorf("missing edge %d--%d", e[0], e[1])
+		}
+	}
+
+	d := simple.NewDirectedGraph()
+	err = NewReader(strings.NewReader(symmetricPattern)).ReadGraph(d)
+	if err != nil {
+		t.Fatalf("unexpected error reading symmetric pattern matrix: %v", err)
+	}
+	if d.Edges().Len() != 6 {
+		t.Errorf("symmetric entries not added in both directions: got %d edges", d.Edges().Len())
+	}
+
+	w := simple.NewWeightedDirectedGraph(0, 0)
+	err = NewReader(strings.NewReader(skewReal)).ReadGraph(w)
+	if err != nil {
+		t.Fatalf("unexpected error reading skew-symmetric matrix: %v", err)
+	}
+	for _, test := range []struct {
+		from, to int64
+		weight   float64
+	}{
+		{from: 1, to: 0, weight: 1.5},
+		{from: 0, to: 1, weight: -1.5},
+		{from: 2, to: 0, weight: -2},
+		{from: 0, to: 2, weight: 2},
+	} {
+		got, ok := w.Weight(test.from, test.to)
+		if !ok || got != test.weight {
+			t.Errorf("unexpected weight for %d->%d: got:%v want:%v", test.from, test.to, got, test.weight)
+		}
+	}
+}
+
+func TestReadGraphSelfLoops(t *testing.T) {
+	var n int
+	g := selfCounter{UndirectedGraph: simple.NewUndirectedGraph(), n: &n}
+	r := NewReader(strings.NewReader(symmetricPattern))
+	r.SelfLoops = true
+	err := r.ReadGraph(g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("unexpected number of self edges: got:%d want:1", n)
+	}
+}
+
+// selfCounter is an undirected graph that counts and
+// discards self edges.
+type selfCounter struct {
+	*simple.UndirectedGraph
+	n *int
+}
+
+func (g selfCounter) SetEdge(e graph.Edge) {
+	if e.From().ID() == e.To().ID() {
+		*g.n++
+		return
+	}
+	g.UndirectedGraph.SetEdge(e)
+}
+
+func TestGraphRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		dst  graph.NodeAdder
+		want string
+	}{
+		{
+			name: "pattern",
+			in:   symmetricPattern,
+			dst:  simple.NewUndirectedGraph(),
+			want: `%%MatrixMarket matrix coordinate pattern symmetric
+4 4 3
+2 1
+3 2
+4 3
+`,
+		},
+		{
+			name: "weighted",
+			in:   generalInteger,
+			dst:  simple.NewWeightedDirectedGraph(0, 0),
+			want: `%%MatrixMarket matrix coordinate real general
+3 3 3
+3 1 6
+1 2 4
+2 3 5
+`,
+		},
+	} {
+		err := NewReader(strings.NewReader(test.in)).ReadGraph(test.dst)
+		if err != nil {
+			t.Errorf("unexpected error reading %s graph: %v", test.name, err)
+			continue
+		}
+		var buf bytes.Buffer
+		err = NewWriter(&buf).WriteGraph(test.dst.(graph.Graph))
+		if err != nil {
+			t.Errorf("unexpected error writing %s graph: %v", test.name, err)
+			continue
+		}
+		if buf.String() != test.want {
+			t.Errorf("unexpected %s graph encoding:\ngot:\n%s\nwant:\n%s", test.name, &buf, test.want)
+		}
+	}
+}
+
+var readErrorTests = []struct {
+	name string
+	in   string
+}{
+	{name: "empty", in: ""},
+	{name: "bad banner", in: "%%MatrixMarket tensor coordinate real general\n1 1 0\n"},
+	{name: "pattern array", in: "%%MatrixMarket matrix array pattern general\n1 1\n"},
+	{name: "array too few entries", in: "%%MatrixMarket matrix array real symmetric\n2 2\n1\n2\n"},
+	{name: "array coordinate entry", in: "%%MatrixMarket matrix array real general\n1 1\n1 1 1\n"},
+	{name: "complex", in: "%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 1\n"},
+	{name: "hermitian", in: "%%MatrixMarket matrix coordinate real hermitian\n1 1 0\n"},
+	{name: "non-square symmetric", in: "%%MatrixMarket matrix coordinate real symmetric\n2 3 0\n"},
+	{name: "missing size", in: "%%MatrixMarket matrix coordinate real general\n"},
+	{name: "bad size", in: "%%MatrixMarket matrix coordinate real general\n2 2 x\n"},
+	{name: "non-square", in: "%%MatrixMarket matrix coordinate real general\n2 3 0\n"},
+	{name: "too few entries", in: "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 2 1\n"},
+	{name: "too many entries", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 1\n2 1 1\n"},
+	{name: "index out of range", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n"},
+	{name: "missing value", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2\n"},
+	{name: "bad value", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 one\n"},
+	{name: "skew diagonal", in: "%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n1 1 1\n"},
+}
+
+func TestReadError(t *testing.T) {
+	for _, test := range readErrorTests {
+		err := NewReader(strings.NewReader(test.in)).ReadGraph(simple.NewWeightedDirectedGraph(0, 0))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+const symmetricArray = `%%MatrixMarket matrix array real symmetric
+% The lower triangle in column-major order.
+3 3
+1
+2
+0
+4
+5
+6
+`
+
+func TestReadArray(t *testing.T) {
+	// Zero elements of arrays are not edges.
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	err := NewReader(strings.NewReader(symmetricArray)).ReadGraph(g)
+	if err != nil {
+		t.Fatalf("unexpected error reading symmetric array graph: %v", err)
+	}
+	if g.Edges().Len() != 2 {
+		t.Errorf("unexpected number of edges: got %d want 2", g.Edges().Len())
+	}
+	if w, _ := g.Weight(2, 1); w != 5 {
+		t.Errorf("unexpected weight: got %v want 5", w)
+	}
+}
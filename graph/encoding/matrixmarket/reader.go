@@ -0,0 +1,103 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrixmarket
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+	mm "gonum.org/v1/gonum/mat/encoding/matrixmarket"
+)
+
+// Reader reads graphs from Matrix Market coordinate and array files.
+type Reader struct {
+	// SelfLoops indicates that diagonal entries
+	// are added to graphs as self edges. If false,
+	// diagonal entries are ignored when reading
+	// graphs.
+	SelfLoops bool
+
+	r io.Reader
+}
+
+// NewReader returns a new Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadGraph reads the square matrix held by the receiver's input into dst.
+// The node for row and column i of the matrix has ID i-1 and is created with
+// dst's MakeNode method if dst implements encoding.NodeMaker, and as a
+// simple.Node otherwise. dst must not hold nodes with IDs that are in the
+// matrix.
+//
+// Edges are created with dst's NewWeightedEdge method if dst is a
+// graph.WeightedEdgeAdder and the matrix is not a pattern matrix or dst is
+// not a graph.EdgeAdder, and with NewEdge otherwise. Edges of pattern
+// matrices are given a weight of 1 by NewWeightedEdge. Entries of symmetric
+// matrices are added in both directions to directed destinations, and
+// entries of skew-symmetric matrices are added in the reverse direction
+// with a negated weight. Zero elements of array files do not correspond
+// to edges. Complex matrices cannot be read as graphs.
+func (r *Reader) ReadGraph(dst graph.NodeAdder) error {
+	mr := mm.NewReader(r.r)
+	h, err := mr.ReadHeader()
+	if err != nil {
+		return err
+	}
+	if h.Field == mm.Complex {
+		return errors.New("matrixmarket: complex matrix cannot be read as a graph")
+	}
+	if h.Rows != h.Cols {
+		return fmt.Errorf("matrixmarket: non-square matrix: %d×%d", h.Rows, h.Cols)
+	}
+
+	wdst, isWeighted := dst.(graph.WeightedEdgeAdder)
+	udst, isUnweighted := dst.(graph.EdgeAdder)
+	if !isWeighted && !isUnweighted {
+		return errors.New("matrixmarket: destination cannot add edges")
+	}
+	useWeighted := isWeighted && (h.Field != mm.Pattern || !isUnweighted)
+	_, reverse := dst.(graph.Directed)
+	reverse = reverse && h.Symmetry != mm.General
+
+	nodes := make([]graph.Node, h.Rows)
+	maker, hasMaker := dst.(encoding.NodeMaker)
+	for i := range nodes {
+		if hasMaker {
+			nodes[i] = maker.MakeNode(int64(i))
+		} else {
+			nodes[i] = simple.Node(i)
+		}
+		dst.AddNode(nodes[i])
+	}
+
+	setEdge := func(i, j int, w float64) {
+		if useWeighted {
+			wdst.SetWeightedEdge(wdst.NewWeightedEdge(nodes[i], nodes[j], w))
+		} else {
+			udst.SetEdge(udst.NewEdge(nodes[i], nodes[j]))
+		}
+	}
+	for mr.Next() {
+		i, j, c := mr.Entry()
+		v := real(c)
+		if (i == j && !r.SelfLoops) || (h.Format == mm.Array && v == 0) {
+			continue
+		}
+		setEdge(i, j, v)
+		if reverse && i != j {
+			if h.Symmetry == mm.SkewSymmetric {
+				v = -v
+			}
+			setEdge(j, i, v)
+		}
+	}
+	return mr.Err()
+}
@@ -0,0 +1,97 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrixmarket
+
+import (
+	"errors"
+	"io"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	mm "gonum.org/v1/gonum/mat/encoding/matrixmarket"
+)
+
+// Writer writes graphs as Matrix Market coordinate files.
+type Writer struct {
+	// Comments holds comment lines that are
+	// written after the header. Each comment
+	// must be a single line.
+	Comments []string
+
+	w io.Writer
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteGraph writes the adjacency matrix of g to the receiver's output.
+// Row and column i of the matrix correspond to the node of g with the
+// i-th smallest ID. Undirected graphs are written as symmetric matrices
+// holding the lower triangle, and directed graphs as general matrices.
+// Graphs that implement graph.Weighted are written as real matrices of
+// the edge weights, and other graphs as pattern matrices.
+func (w *Writer) WriteGraph(g graph.Graph) error {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	index := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+
+	_, directed := g.(graph.Directed)
+	wg, weighted := g.(graph.Weighted)
+
+	var entries []mm.Entry
+	for j, u := range nodes {
+		uid := u.ID()
+		for _, v := range graph.NodesOf(g.From(uid)) {
+			i, ok := index[v.ID()]
+			if !ok {
+				return errors.New("matrixmarket: edge to node not in graph")
+			}
+			if !directed && i < j {
+				continue
+			}
+			e := mm.Entry{Row: i, Col: j, Value: 1}
+			if directed {
+				// Directed edges are held in the
+				// row of their from node.
+				e.Row, e.Col = j, i
+			}
+			if weighted {
+				weight, _ := wg.Weight(uid, v.ID())
+				e.Value = complex(weight, 0)
+			}
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		if entries[a].Col != entries[b].Col {
+			return entries[a].Col < entries[b].Col
+		}
+		return entries[a].Row < entries[b].Row
+	})
+
+	h := mm.Header{
+		Format:   mm.Coordinate,
+		Field:    mm.Pattern,
+		Symmetry: mm.Symmetric,
+		Rows:     len(nodes),
+		Cols:     len(nodes),
+		Entries:  len(entries),
+	}
+	if weighted {
+		h.Field = mm.Real
+	}
+	if directed {
+		h.Symmetry = mm.General
+	}
+	mw := mm.NewWriter(w.w)
+	mw.Comments = w.Comments
+	return mw.WriteEntries(h, entries)
+}
@@ -0,0 +1,317 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pajek
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// NameSetter is implemented by graphs that can set a Pajek network name.
+type NameSetter interface {
+	SetPajekName(name string)
+}
+
+// LabelSetter is implemented by graph.Node values that can set a Pajek
+// vertex label.
+type LabelSetter interface {
+	SetPajekLabel(label string)
+}
+
+// CoordinateSetter is implemented by graph.Node values that can set
+// Pajek vertex coordinates.
+type CoordinateSetter interface {
+	SetPajekCoordinates(coords []float64)
+}
+
+// Unmarshal parses the Pajek .net-encoded data and stores the result in dst.
+// Nodes are created by dst in vertex number order and have their labels and
+// coordinates set if they implement LabelSetter and CoordinateSetter.
+// Vertex parameters following the coordinates are ignored.
+//
+// Arcs and edges are read from *Arcs, *Edges, *Arcslist and *Edgeslist
+// sections. If dst is a graph.Directed, each edge is added as a pair of
+// arcs in opposite directions. If dst is a graph.WeightedBuilder, arcs
+// and edges are created with their weight, or one if the weight is absent.
+// Matrix, partition and vector sections are not supported.
+func Unmarshal(data []byte, dst encoding.Builder) error {
+	d := decoder{
+		dst:      dst,
+		section:  none,
+		directed: isDirected(dst),
+	}
+	d.wb, d.weighted = dst.(graph.WeightedBuilder)
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		d.line++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '%' {
+			continue
+		}
+		var err error
+		if line[0] == '*' {
+			err = d.header(line)
+		} else {
+			err = d.record(line)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if d.nodes == nil {
+		if d.vertices == nil {
+			return errors.New("pajek: no vertices")
+		}
+		d.addNodes()
+	}
+	return nil
+}
+
+func isDirected(g graph.Graph) bool {
+	_, ok := g.(graph.Directed)
+	return ok
+}
+
+const (
+	none = iota
+	vertices
+	arcs
+	edges
+	arcsList
+	edgesList
+)
+
+// decoder holds the state of a Pajek decoding.
+type decoder struct {
+	dst      encoding.Builder
+	wb       graph.WeightedBuilder
+	weighted bool
+	directed bool
+
+	line    int
+	section int
+
+	// vertices holds the vertex records until the
+	// nodes are added at the end of the vertex
+	// section.
+	vertices []vertex
+	nodes    []graph.Node
+}
+
+// vertex is a Pajek vertex record.
+type vertex struct {
+	label  string
+	coords []float64
+}
+
+// header handles a section header line.
+func (d *decoder) header(line string) error {
+	fields := strings.Fields(line)
+	keyword := strings.ToLower(fields[0])
+	if keyword == "*network" {
+		if s, ok := d.dst.(NameSetter); ok {
+			s.SetPajekName(strings.TrimSpace(line[len(fields[0]):]))
+		}
+		return nil
+	}
+	if d.section == vertices {
+		d.addNodes()
+	}
+	switch keyword {
+	case "*vertices":
+		if d.vertices != nil {
+			return d.errorf("multiple vertex sections")
+		}
+		if len(fields) < 2 {
+			return d.errorf("missing vertex count")
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 0 {
+			return d.errorf("invalid vertex count: %q", fields[1])
+		}
+		d.vertices = make([]vertex, n)
+		d.section = vertices
+		return nil
+	case "*arcs":
+		d.section = arcs
+	case "*edges":
+		d.section = edges
+	case "*arcslist":
+		d.section = arcsList
+	case "*edgeslist":
+		d.section = edgesList
+	default:
+		return d.errorf("unsupported section: %s", fields[0])
+	}
+	if d.nodes == nil {
+		return d.errorf("%s section before vertices", fields[0])
+	}
+	return nil
+}
+
+// record handles a line within a section.
+func (d *decoder) record(line string) error {
+	fields, err := split(line)
+	if err != nil {
+		return d.errorf("%v", err)
+	}
+	switch d.section {
+	case vertices:
+		return d.vertex(fields)
+	case arcs, edges:
+		if len(fields) < 2 {
+			return d.errorf("missing end point")
+		}
+		u, err := d.node(fields[0])
+		if err != nil {
+			return err
+		}
+		v, err := d.node(fields[1])
+		if err != nil {
+			return err
+		}
+		w := 1.0
+		if len(fields) > 2 {
+			w, err = strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return d.errorf("invalid weight: %q", fields[2])
+			}
+		}
+		d.setEdge(u, v, w, d.section == edges)
+	case arcsList, edgesList:
+		u, err := d.node(fields[0])
+		if err != nil {
+			return err
+		}
+		for _, f := range fields[1:] {
+			v, err := d.node(f)
+			if err != nil {
+				return err
+			}
+			d.setEdge(u, v, 1, d.section == edgesList)
+		}
+	default:
+		return d.errorf("record outside section")
+	}
+	return nil
+}
+
+// vertex handles a vertex record.
+func (d *decoder) vertex(fields []string) error {
+	i, err := strconv.Atoi(fields[0])
+	if err != nil || i < 1 || len(d.vertices) < i {
+		return d.errorf("invalid vertex number: %q", fields[0])
+	}
+	var v vertex
+	if len(fields) > 1 {
+		v.label = fields[1]
+	}
+	for _, f := range fields[min(2, len(fields)):] {
+		if len(v.coords) == 3 {
+			break
+		}
+		x, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			break
+		}
+		v.coords = append(v.coords, x)
+	}
+	if len(v.coords) == 1 {
+		return d.errorf("invalid coordinates for vertex %d", i)
+	}
+	d.vertices[i-1] = v
+	return nil
+}
+
+// addNodes adds the nodes described by the vertex section to dst.
+func (d *decoder) addNodes() {
+	d.nodes = make([]graph.Node, len(d.vertices))
+	for i, v := range d.vertices {
+		n := d.dst.NewNode()
+		if s, ok := n.(LabelSetter); ok && v.label != "" {
+			s.SetPajekLabel(v.label)
+		}
+		if s, ok := n.(CoordinateSetter); ok && v.coords != nil {
+			s.SetPajekCoordinates(v.coords)
+		}
+		d.dst.AddNode(n)
+		d.nodes[i] = n
+	}
+}
+
+// node returns the node with the given vertex number.
+func (d *decoder) node(field string) (graph.Node, error) {
+	i, err := strconv.Atoi(field)
+	if err != nil || i < 1 || len(d.nodes) < i {
+		return nil, d.errorf("invalid vertex number: %q", field)
+	}
+	return d.nodes[i-1], nil
+}
+
+// setEdge adds an edge from u to v with the weight w to the destination.
+// If undirected is true and the destination is directed, the reverse edge
+// is also added.
+func (d *decoder) setEdge(u, v graph.Node, w float64, undirected bool) {
+	set := func(u, v graph.Node) {
+		if d.weighted {
+			d.wb.SetWeightedEdge(d.wb.NewWeightedEdge(u, v, w))
+		} else {
+			d.dst.SetEdge(d.dst.NewEdge(u, v))
+		}
+	}
+	set(u, v)
+	if undirected && d.directed && u.ID() != v.ID() {
+		set(v, u)
+	}
+}
+
+func (d *decoder) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("pajek: line %d: %s", d.line, fmt.Sprintf(format, args...))
+}
+
+// split splits line into white space separated fields, treating double
+// quoted text as a single field.
+func split(line string) ([]string, error) {
+	var fields []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return fields, nil
+		}
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return nil, errors.New("unterminated quote")
+			}
+			fields = append(fields, line[1:end+1])
+			line = line[end+2:]
+			continue
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,10 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pajek implements encoding and decoding of graphs in the Pajek
+// .net network format.
+//
+// For details of the format see the Pajek manual at
+// http://mrvar.fdv.uni-lj.si/pajek/.
+package pajek // import "gonum.org/v1/gonum/graph/encoding/pajek"
@@ -0,0 +1,98 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pajek
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Labeler is implemented by graph.Node values that have a Pajek
+// vertex label.
+type Labeler interface {
+	PajekLabel() string
+}
+
+// Coordinater is implemented by graph.Node values that have Pajek
+// vertex coordinates. The returned slice must have length 0, 2 or 3.
+type Coordinater interface {
+	PajekCoordinates() []float64
+}
+
+// Marshal returns the Pajek .net encoding for the graph g. If name is not
+// empty, a *Network line holding name is written first. Vertices are
+// numbered from one in order of increasing node ID and are labeled with
+// the decimal node ID unless the node implements Labeler. Vertex
+// coordinates are written for nodes implementing Coordinater.
+//
+// The edges of directed graphs are written as *Arcs and the edges of
+// undirected graphs are written as *Edges. The weights of graph.WeightedEdge
+// values are written with each arc or edge.
+func Marshal(g graph.Graph, name string) ([]byte, error) {
+	var buf bytes.Buffer
+	if name != "" {
+		fmt.Fprintf(&buf, "*Network %s\n", name)
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	fmt.Fprintf(&buf, "*Vertices %d\n", len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i + 1
+		label := strconv.FormatInt(n.ID(), 10)
+		if l, ok := n.(Labeler); ok {
+			label = l.PajekLabel()
+		}
+		if strings.ContainsAny(label, "\"\n") {
+			return nil, fmt.Errorf("pajek: invalid label for node %d: %q", n.ID(), label)
+		}
+		fmt.Fprintf(&buf, "%d \"%s\"", i+1, label)
+		if c, ok := n.(Coordinater); ok {
+			coords := c.PajekCoordinates()
+			switch len(coords) {
+			case 0, 2, 3:
+			default:
+				return nil, fmt.Errorf("pajek: invalid number of coordinates for node %d: %d", n.ID(), len(coords))
+			}
+			for _, x := range coords {
+				buf.WriteByte(' ')
+				buf.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+			}
+		}
+		buf.WriteByte('\n')
+	}
+
+	_, isDirected := g.(graph.Directed)
+	if isDirected {
+		buf.WriteString("*Arcs\n")
+	} else {
+		buf.WriteString("*Edges\n")
+	}
+	for _, n := range nodes {
+		uid := n.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, t := range to {
+			vid := t.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			fmt.Fprintf(&buf, "%d %d", indexOf[uid], indexOf[vid])
+			if e, ok := g.Edge(uid, vid).(graph.WeightedEdge); ok {
+				buf.WriteByte(' ')
+				buf.WriteString(strconv.FormatFloat(e.Weight(), 'g', -1, 64))
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes(), nil
+}
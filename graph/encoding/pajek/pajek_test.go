@@ -0,0 +1,198 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pajek
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// pajekGraph is a weighted directed graph of pajekNodes.
+type pajekGraph struct {
+	*simple.WeightedDirectedGraph
+	name string
+}
+
+func newPajekGraph() *pajekGraph {
+	return &pajekGraph{WeightedDirectedGraph: simple.NewWeightedDirectedGraph(0, 0)}
+}
+
+func (g *pajekGraph) NewNode() graph.Node {
+	return &pajekNode{id: g.WeightedDirectedGraph.NewNode().ID()}
+}
+
+func (g *pajekGraph) NewEdge(from, to graph.Node) graph.Edge {
+	return g.NewWeightedEdge(from, to, 1)
+}
+
+func (g *pajekGraph) SetEdge(e graph.Edge) {
+	g.SetWeightedEdge(e.(graph.WeightedEdge))
+}
+
+func (g *pajekGraph) SetPajekName(name string) { g.name = name }
+
+type pajekNode struct {
+	id     int64
+	label  string
+	coords []float64
+}
+
+func (n *pajekNode) ID() int64                       { return n.id }
+func (n *pajekNode) PajekLabel() string              { return n.label }
+func (n *pajekNode) SetPajekLabel(l string)          { n.label = l }
+func (n *pajekNode) PajekCoordinates() []float64     { return n.coords }
+func (n *pajekNode) SetPajekCoordinates(c []float64) { n.coords = c }
+
+const mixed = `% A mixed network with coordinates.
+*Network Example
+*Vertices 4
+1 "first vertex" 0.1 0.2 0.5 ic Red
+2 second 0.3 0.4
+3 "third"
+*Arcs
+1 2 2.5
+2 3
+*Edges
+3 4 0.5
+*Arcslist
+4 1
+`
+
+func TestUnmarshal(t *testing.T) {
+	g := newPajekGraph()
+	err := Unmarshal([]byte(mixed), g)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.name != "Example" {
+		t.Errorf("unexpected network name: %q", g.name)
+	}
+	want := []pajekNode{
+		{id: 0, label: "first vertex", coords: []float64{0.1, 0.2, 0.5}},
+		{id: 1, label: "second", coords: []float64{0.3, 0.4}},
+		{id: 2, label: "third"},
+		{id: 3},
+	}
+	for _, w := range want {
+		n := g.Node(w.id).(*pajekNode)
+		if !reflect.DeepEqual(*n, w) {
+			t.Errorf("unexpected node: got:%+v want:%+v", *n, w)
+		}
+	}
+	wantEdges := map[[2]int64]float64{
+		{0, 1}: 2.5,
+		{1, 2}: 1,
+		{2, 3}: 0.5,
+		{3, 2}: 0.5,
+		{3, 0}: 1,
+	}
+	if n := g.Edges().Len(); n != len(wantEdges) {
+		t.Errorf("unexpected number of edges: got:%d want:%d", n, len(wantEdges))
+	}
+	for e, w := range wantEdges {
+		got := g.WeightedEdge(e[0], e[1])
+		if got == nil || got.Weight() != w {
+			t.Errorf("unexpected edge %d->%d: got:%v want weight:%v", e[0], e[1], got, w)
+		}
+	}
+
+	const wantEncoding = `*Network Example
+*Vertices 4
+1 "first vertex" 0.1 0.2 0.5
+2 "second" 0.3 0.4
+3 "third"
+4 ""
+*Arcs
+1 2 2.5
+2 3 1
+3 4 0.5
+4 1 1
+4 3 0.5
+`
+	b, err := Marshal(g, g.name)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(b) != wantEncoding {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", b, wantEncoding)
+	}
+}
+
+func TestUndirected(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(5)})
+	g.SetEdge(simple.Edge{F: simple.Node(5), T: simple.Node(7)})
+
+	const want = `*Vertices 3
+1 "2"
+2 "5"
+3 "7"
+*Edges
+1 2
+2 3
+`
+	b, err := Marshal(g, "")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	if string(b) != want {
+		t.Errorf("unexpected encoding:\ngot:\n%s\nwant:\n%s", b, want)
+	}
+
+	dst := simple.NewUndirectedGraph()
+	err = Unmarshal(b, dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if dst.Nodes().Len() != 3 || !dst.HasEdgeBetween(0, 1) || !dst.HasEdgeBetween(1, 2) || dst.HasEdgeBetween(0, 2) {
+		t.Errorf("unexpected graph after round trip")
+	}
+}
+
+func TestEdgesList(t *testing.T) {
+	const data = `*Vertices 4
+*Edgeslist
+1 2 3 4
+`
+	dst := simple.NewDirectedGraph()
+	err := Unmarshal([]byte(data), dst)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dst.Edges().Len() != 6 {
+		t.Errorf("unexpected number of arcs: got:%d want:6", dst.Edges().Len())
+	}
+	for v := int64(1); v < 4; v++ {
+		if !dst.HasEdgeFromTo(0, v) || !dst.HasEdgeFromTo(v, 0) {
+			t.Errorf("missing arcs between 0 and %d", v)
+		}
+	}
+}
+
+var unmarshalErrorTests = []struct {
+	name string
+	data string
+}{
+	{name: "no vertices", data: "*Arcs\n1 2\n"},
+	{name: "empty", data: ""},
+	{name: "bad vertex", data: "*Vertices 2\n3 \"c\"\n"},
+	{name: "bad arc", data: "*Vertices 2\n*Arcs\n1 3\n"},
+	{name: "bad weight", data: "*Vertices 2\n*Arcs\n1 2 heavy\n"},
+	{name: "unterminated", data: "*Vertices 2\n1 \"a\n"},
+	{name: "matrix", data: "*Vertices 2\n*Matrix\n0 1\n1 0\n"},
+	{name: "one coordinate", data: "*Vertices 1\n1 a 0.5\n"},
+}
+
+func TestUnmarshalError(t *testing.T) {
+	for _, test := range unmarshalErrorTests {
+		err := Unmarshal([]byte(test.data), simple.NewDirectedGraph())
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Unmarshal decodes the binary snapshot in data into dst, retaining the
+// node IDs held in the snapshot. Nodes are created with dst's MakeNode
+// method if dst implements encoding.NodeMaker, and as simple.Node values
+// otherwise. dst must not hold nodes with IDs that are in the snapshot.
+//
+// Edges are created with dst's NewWeightedEdge method if dst is a
+// graph.WeightedEdgeAdder and the snapshot is weighted or dst is not a
+// graph.EdgeAdder, and with NewEdge otherwise. Edges of snapshots without
+// weights are given a weight of 1 by NewWeightedEdge. Undirected edges are
+// added in both directions to directed destinations.
+//
+// Node and edge attributes are set on nodes and edges that implement
+// encoding.AttributeSetter.
+func Unmarshal(data []byte, dst graph.NodeAdder) error {
+	if len(data) < len(magic)+crc32.Size || string(data[:len(magic)]) != magic {
+		return errors.New("snapshot: not a graph snapshot")
+	}
+	body := data[:len(data)-crc32.Size]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(data[len(body):]) {
+		return errors.New("snapshot: checksum mismatch")
+	}
+	r := reader{buf: body, off: len(magic)}
+
+	version := r.uvarint()
+	if r.err == nil && version != Version {
+		return fmt.Errorf("snapshot: unsupported version: %d", version)
+	}
+	flags := r.uvarint()
+	directed := flags&directedFlag != 0
+	weighted := flags&weightedFlag != 0
+
+	n := r.count()
+	ids := make([]int64, n)
+	for i := range ids {
+		if i == 0 {
+			ids[i] = r.varint()
+			continue
+		}
+		d := r.uvarint()
+		if d == 0 || d > math.MaxInt64 || ids[i-1] > math.MaxInt64-int64(d) {
+			r.fail("invalid node ID")
+		}
+		ids[i] = ids[i-1] + int64(d)
+	}
+
+	type edge struct{ u, v int }
+	var edges []edge
+	for i := 0; i < n && r.err == nil; i++ {
+		last := 0
+		for k, deg := 0, r.count(); k < deg; k++ {
+			d := r.uvarint()
+			if k > 0 && d == 0 {
+				r.fail("unordered adjacency")
+			}
+			j := uint64(last) + d
+			if j >= uint64(n) || (!directed && j < uint64(i)) {
+				r.fail("invalid node index")
+				break
+			}
+			last = int(j)
+			edges = append(edges, edge{u: i, v: last})
+		}
+	}
+
+	var weights []float64
+	if weighted {
+		weights = make([]float64, len(edges))
+		for i := range weights {
+			weights[i] = r.float64()
+		}
+	}
+
+	strings := make([]string, r.count())
+	for i := range strings {
+		strings[i] = r.string()
+	}
+	nodeAttrs := r.attributes(strings, n)
+	edgeAttrs := r.attributes(strings, len(edges))
+	if r.err != nil {
+		return r.err
+	}
+	if r.off != len(r.buf) {
+		return errors.New("snapshot: unexpected data after attribute table")
+	}
+
+	nodes := make([]graph.Node, n)
+	maker, hasMaker := dst.(encoding.NodeMaker)
+	for i, id := range ids {
+		var u graph.Node
+		if hasMaker {
+			u = maker.MakeNode(id)
+		} else {
+			u = simple.Node(id)
+		}
+		if err := setAttributes(u, nodeAttrs[i]); err != nil {
+			return err
+		}
+		dst.AddNode(u)
+		nodes[i] = u
+	}
+
+	wdst, isWeighted := dst.(graph.WeightedEdgeAdder)
+	udst, isUnweighted := dst.(graph.EdgeAdder)
+	if !isWeighted && !isUnweighted {
+		return errors.New("snapshot: destination cannot add edges")
+	}
+	useWeighted := isWeighted && (weighted || !isUnweighted)
+	_, reverse := dst.(graph.Directed)
+	reverse = reverse && !directed
+	for i, e := range edges {
+		w := 1.0
+		if weighted {
+			w = weights[i]
+		}
+		for _, uv := range [2]edge{e, {u: e.v, v: e.u}} {
+			u, v := nodes[uv.u], nodes[uv.v]
+			if useWeighted {
+				we := wdst.NewWeightedEdge(u, v, w)
+				if err := setAttributes(we, edgeAttrs[i]); err != nil {
+					return err
+				}
+				wdst.SetWeightedEdge(we)
+			} else {
+				ue := udst.NewEdge(u, v)
+				if err := setAttributes(ue, edgeAttrs[i]); err != nil {
+					return err
+				}
+				udst.SetEdge(ue)
+			}
+			if !reverse || e.u == e.v {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// setAttributes sets attrs on dst if dst is an encoding.AttributeSetter.
+func setAttributes(dst interface{}, attrs []encoding.Attribute) error {
+	if len(attrs) == 0 {
+		return nil
+	}
+	s, ok := dst.(encoding.AttributeSetter)
+	if !ok {
+		return nil
+	}
+	for _, a := range attrs {
+		if err := s.SetAttribute(a); err != nil {
+			return fmt.Errorf("snapshot: unable to set attribute %q: %v", a.Key, err)
+		}
+	}
+	return nil
+}
+
+// reader decodes snapshot encoded values. After the first error
+// all reads return zero values and the error is held in err.
+type reader struct {
+	buf []byte
+	off int
+	err error
+}
+
+func (r *reader) fail(msg string) {
+	if r.err == nil {
+		r.err = fmt.Errorf("snapshot: offset %d: %s", r.off, msg)
+	}
+}
+
+func (r *reader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf[r.off:])
+	if n <= 0 {
+		r.fail("invalid integer")
+		return 0
+	}
+	r.off += n
+	return v
+}
+
+func (r *reader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf[r.off:])
+	if n <= 0 {
+		r.fail("invalid integer")
+		return 0
+	}
+	r.off += n
+	return v
+}
+
+// count returns a length that is no longer than the remaining data.
+// Every counted item occupies at least one byte.
+func (r *reader) count() int {
+	v := r.uvarint()
+	if v > uint64(len(r.buf)-r.off) {
+		r.fail("count exceeds snapshot length")
+		return 0
+	}
+	return int(v)
+}
+
+func (r *reader) float64() float64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf)-r.off < 8 {
+		r.fail("truncated weight")
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(r.buf[r.off:]))
+	r.off += 8
+	return v
+}
+
+func (r *reader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.buf[r.off : r.off+n])
+	r.off += n
+	return s
+}
+
+// attributes reads an attribute table for n items, resolving keys
+// and values in strings.
+func (r *reader) attributes(strings []string, n int) [][]encoding.Attribute {
+	attrs := make([][]encoding.Attribute, n)
+	last := -1
+	for k, m := 0, r.count(); k < m && r.err == nil; k++ {
+		i := r.uvarint()
+		if i >= uint64(n) || int(i) <= last {
+			r.fail("invalid attribute index")
+			break
+		}
+		last = int(i)
+		l := r.count()
+		a := make([]encoding.Attribute, l)
+		for j := range a {
+			key, val := r.uvarint(), r.uvarint()
+			if key >= uint64(len(strings)) || val >= uint64(len(strings)) {
+				r.fail("invalid string index")
+				break
+			}
+			a[j] = encoding.Attribute{Key: strings[key], Value: strings[val]}
+		}
+		attrs[i] = a
+	}
+	return attrs
+}
@@ -0,0 +1,12 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package snapshot implements a compact binary serialization of graphs for
+// persisting and reloading large graphs.
+//
+// A snapshot holds the node IDs of a graph, its adjacency in a compressed
+// sparse row layout of variable length integers, the edge weights of
+// weighted graphs and a table of the node and edge attributes. Snapshots
+// begin with a versioned header and end with a checksum of their content.
+package snapshot // import "gonum.org/v1/gonum/graph/encoding/snapshot"
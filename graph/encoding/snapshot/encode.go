@@ -0,0 +1,226 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// A snapshot is laid out as follows. All integers are unsigned variable
+// length integers unless otherwise noted.
+//
+//	magic     4 bytes, "GSNP"
+//	version   the snapshot format version
+//	flags     bit 0 set for directed graphs, bit 1 set for weighted graphs
+//	n         the number of nodes
+//	ids       n node IDs in ascending order, the first as a signed variable
+//	          length integer and the remainder as differences from the
+//	          preceding ID
+//	adjacency for each node in ID order, the number of neighbours followed
+//	          by the node indices of the neighbours in ascending order, the
+//	          first as an index and the remainder as differences from the
+//	          preceding index; undirected edges are held only by the end
+//	          point with the lower index
+//	weights   for weighted graphs, the weight of each edge in adjacency
+//	          order as a little-endian IEEE 754 float64
+//	strings   the number of strings followed by each string's length and
+//	          bytes
+//	node attributes
+//	          the number of attributed nodes followed by, for each, the node
+//	          index, the number of attributes and the key and value of each
+//	          attribute as string table indices
+//	edge attributes
+//	          as for node attributes, with the edge's index in adjacency
+//	          order in place of the node index
+//	checksum  4 bytes, the little-endian IEEE CRC-32 of the preceding bytes
+const (
+	magic = "GSNP"
+
+	// Version is the snapshot format version written by Marshal.
+	Version = 1
+)
+
+const (
+	directedFlag = 1 << iota
+	weightedFlag
+)
+
+// Marshal returns a binary snapshot of the graph g. If g implements
+// graph.Weighted, the edge weights are included in the snapshot. Attributes
+// of nodes and edges that implement encoding.Attributer are held in the
+// snapshot's attribute table.
+func Marshal(g graph.Graph) ([]byte, error) {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	index := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+
+	_, directed := g.(graph.Directed)
+	_, weighted := g.(graph.Weighted)
+
+	var w writer
+	w.buf = append(w.buf, magic...)
+	w.uvarint(Version)
+	var flags uint64
+	if directed {
+		flags |= directedFlag
+	}
+	if weighted {
+		flags |= weightedFlag
+	}
+	w.uvarint(flags)
+
+	w.uvarint(uint64(len(nodes)))
+	var last int64
+	for i, n := range nodes {
+		id := n.ID()
+		if i == 0 {
+			w.varint(id)
+		} else {
+			w.uvarint(uint64(id - last))
+		}
+		last = id
+	}
+
+	var (
+		edges     []graph.Edge
+		neighbors []int
+	)
+	for i, u := range nodes {
+		neighbors = neighbors[:0]
+		for _, v := range graph.NodesOf(g.From(u.ID())) {
+			j, ok := index[v.ID()]
+			if !ok {
+				return nil, errors.New("snapshot: edge to node not in graph")
+			}
+			if !directed && j < i {
+				continue
+			}
+			neighbors = append(neighbors, j)
+		}
+		sort.Ints(neighbors)
+		w.uvarint(uint64(len(neighbors)))
+		for k, j := range neighbors {
+			if k == 0 {
+				w.uvarint(uint64(j))
+			} else {
+				w.uvarint(uint64(j - neighbors[k-1]))
+			}
+			edges = append(edges, g.Edge(u.ID(), nodes[j].ID()))
+		}
+	}
+
+	if weighted {
+		for _, e := range edges {
+			we, ok := e.(graph.WeightedEdge)
+			if !ok {
+				return nil, errors.New("snapshot: weighted graph edge does not implement graph.WeightedEdge")
+			}
+			w.float64(we.Weight())
+		}
+	}
+
+	var (
+		table   stringTable
+		attrBuf writer
+	)
+	attributed := 0
+	for i, n := range nodes {
+		if a, ok := n.(encoding.Attributer); ok && table.attributes(&attrBuf, i, a.Attributes()) {
+			attributed++
+		}
+	}
+	nodeAttrs := attrBuf.buf
+	attrBuf = writer{}
+	edgeAttributed := 0
+	for i, e := range edges {
+		if a, ok := e.(encoding.Attributer); ok && table.attributes(&attrBuf, i, a.Attributes()) {
+			edgeAttributed++
+		}
+	}
+
+	w.uvarint(uint64(len(table.strings)))
+	for _, s := range table.strings {
+		w.string(s)
+	}
+	w.uvarint(uint64(attributed))
+	w.buf = append(w.buf, nodeAttrs...)
+	w.uvarint(uint64(edgeAttributed))
+	w.buf = append(w.buf, attrBuf.buf...)
+
+	var sum [4]byte
+	binary.LittleEndian.PutUint32(sum[:], crc32.ChecksumIEEE(w.buf))
+	return append(w.buf, sum[:]...), nil
+}
+
+// writer accumulates snapshot encoded values.
+type writer struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *writer) uvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf = append(w.buf, w.scratch[:n]...)
+}
+
+func (w *writer) varint(v int64) {
+	n := binary.PutVarint(w.scratch[:], v)
+	w.buf = append(w.buf, w.scratch[:n]...)
+}
+
+func (w *writer) float64(v float64) {
+	binary.LittleEndian.PutUint64(w.scratch[:8], math.Float64bits(v))
+	w.buf = append(w.buf, w.scratch[:8]...)
+}
+
+func (w *writer) string(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// stringTable interns attribute keys and values.
+type stringTable struct {
+	index   map[string]uint64
+	strings []string
+}
+
+func (t *stringTable) add(s string) uint64 {
+	if t.index == nil {
+		t.index = make(map[string]uint64)
+	}
+	i, ok := t.index[s]
+	if !ok {
+		i = uint64(len(t.strings))
+		t.index[s] = i
+		t.strings = append(t.strings, s)
+	}
+	return i
+}
+
+// attributes writes the attribute table entry for the item at index i
+// to w, returning whether attrs is not empty.
+func (t *stringTable) attributes(w *writer, i int, attrs []encoding.Attribute) bool {
+	if len(attrs) == 0 {
+		return false
+	}
+	w.uvarint(uint64(i))
+	w.uvarint(uint64(len(attrs)))
+	for _, a := range attrs {
+		w.uvarint(t.add(a.Key))
+		w.uvarint(t.add(a.Value))
+	}
+	return true
+}
@@ -0,0 +1,282 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding/dot"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/internal/attrgraph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// edgesOf returns a sorted description of the edges in g
+// with their weights.
+func edgesOf(g graph.Graph) [][3]float64 {
+	var edges [][3]float64
+	for _, u := range graph.NodesOf(g.Nodes()) {
+		for _, v := range graph.NodesOf(g.From(u.ID())) {
+			w := 1.0
+			if wg, ok := g.(graph.Weighted); ok {
+				w, _ = wg.Weight(u.ID(), v.ID())
+			}
+			edges = append(edges, [3]float64{float64(u.ID()), float64(v.ID()), w})
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		for k := range edges[i] {
+			if edges[i][k] != edges[j][k] {
+				return edges[i][k] < edges[j][k]
+			}
+		}
+		return false
+	})
+	return edges
+}
+
+func idsOf(g graph.Graph) []int64 {
+	var ids []int64
+	for _, n := range graph.NodesOf(g.Nodes()) {
+		ids = append(ids, n.ID())
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestRoundTrip(t *testing.T) {
+	directed := simple.NewDirectedGraph()
+	undirected := simple.NewUndirectedGraph()
+	weightedDirected := simple.NewWeightedDirectedGraph(0, 0)
+	weightedUndirected := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []struct {
+		from, to int64
+		weight   float64
+	}{
+		{from: -3, to: 10, weight: 0.5},
+		{from: 10, to: -3, weight: 2},
+		{from: 10, to: 1 << 40, weight: -1},
+		{from: 4, to: 10, weight: 3},
+		{from: 4, to: 5, weight: 1},
+	} {
+		directed.SetEdge(directed.NewEdge(simple.Node(e.from), simple.Node(e.to)))
+		undirected.SetEdge(undirected.NewEdge(simple.Node(e.from), simple.Node(e.to)))
+		weightedDirected.SetWeightedEdge(weightedDirected.NewWeightedEdge(simple.Node(e.from), simple.Node(e.to), e.weight))
+		weightedUndirected.SetWeightedEdge(weightedUndirected.NewWeightedEdge(simple.Node(e.from), simple.Node(e.to), e.weight))
+	}
+	directed.AddNode(simple.Node(7))
+	weightedUndirected.AddNode(simple.Node(-7))
+
+	for _, test := range []struct {
+		name string
+		g    graph.Graph
+		dst  func() graph.NodeAdder
+	}{
+		{name: "directed", g: directed, dst: func() graph.NodeAdder { return simple.NewDirectedGraph() }},
+		{name: "undirected", g: undirected, dst: func() graph.NodeAdder { return simple.NewUndirectedGraph() }},
+		{name: "weighted directed", g: weightedDirected, dst: func() graph.NodeAdder { return simple.NewWeightedDirectedGraph(0, 0) }},
+		{name: "weighted undirected", g: weightedUndirected, dst: func() graph.NodeAdder { return simple.NewWeightedUndirectedGraph(0, 0) }},
+		{name: "empty", g: simple.NewDirectedGraph(), dst: func() graph.NodeAdder { return simple.NewDirectedGraph() }},
+	} {
+		data, err := Marshal(test.g)
+		if err != nil {
+			t.Errorf("unexpected error marshaling %s graph: %v", test.name, err)
+			continue
+		}
+		dst := test.dst()
+		err = Unmarshal(data, dst)
+		if err != nil {
+			t.Errorf("unexpected error unmarshaling %s graph: %v", test.name, err)
+			continue
+		}
+		got := dst.(graph.Graph)
+		if !reflect.DeepEqual(idsOf(got), idsOf(test.g)) {
+			t.Errorf("unexpected nodes for %s graph: got:%v want:%v", test.name, idsOf(got), idsOf(test.g))
+		}
+		if !reflect.DeepEqual(edgesOf(got), edgesOf(test.g)) {
+			t.Errorf("unexpected edges for %s graph: got:%v want:%v", test.name, edgesOf(got), edgesOf(test.g))
+		}
+		again, err := Marshal(got)
+		if err != nil {
+			t.Errorf("unexpected error remarshaling %s graph: %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(again, data) {
+			t.Errorf("snapshot of %s graph not stable", test.name)
+		}
+	}
+}
+
+func TestUnmarshalConversion(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	g.SetWeightedEdge(g.NewWeightedEdge(simple.Node(1), simple.Node(2), 5))
+	data, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	d := simple.NewDirectedGraph()
+	err = Unmarshal(data, d)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if !d.HasEdgeFromTo(1, 2) || !d.HasEdgeFromTo(2, 1) || d.Edges().Len() != 2 {
+		t.Errorf("undirected edge not added in both directions")
+	}
+
+	data, err = Marshal(d)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	w := simple.NewWeightedUndirectedGraph(0, 0)
+	err = Unmarshal(data, w)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	if weight, ok := w.Weight(1, 2); !ok || weight != 1 {
+		t.Errorf("unexpected weight for unweighted edge: got:%v want:1", weight)
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	g := attrgraph.NewDirected()
+	a := &attrgraph.Node{NodeID: 1, Attr: attrgraph.Attrs{{Key: "color", Value: "red"}, {Key: "label", Value: "a"}}}
+	b := &attrgraph.Node{NodeID: 2}
+	c := &attrgraph.Node{NodeID: 3, Attr: attrgraph.Attrs{{Key: "color", Value: "red"}}}
+	g.SetWeightedEdge(&attrgraph.Edge{
+		WeightedEdge: simple.WeightedEdge{F: a, T: b, W: 2},
+		Attrs:        attrgraph.Attrs{{Key: "label", Value: "red"}},
+	})
+	g.SetWeightedEdge(&attrgraph.Edge{WeightedEdge: simple.WeightedEdge{F: b, T: c, W: 3}})
+
+	data, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+	got := attrgraph.NewDirected()
+	err = Unmarshal(data, got)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v", err)
+	}
+	for _, want := range []*attrgraph.Node{a, b, c} {
+		n, ok := got.Node(want.NodeID).(*attrgraph.Node)
+		if !ok {
+			t.Errorf("missing node %d", want.NodeID)
+			continue
+		}
+		if !reflect.DeepEqual(n.Attr, want.Attr) {
+			t.Errorf("unexpected attributes for node %d: got:%v want:%v", want.NodeID, n.Attr, want.Attr)
+		}
+	}
+	e, ok := got.WeightedEdge(1, 2).(*attrgraph.Edge)
+	if !ok {
+		t.Fatal("missing edge 1->2")
+	}
+	want := attrgraph.Attrs{{Key: "label", Value: "red"}}
+	if !reflect.DeepEqual(e.Attrs, want) {
+		t.Errorf("unexpected edge attributes: got:%v want:%v", e.Attrs, want)
+	}
+	if e.Weight() != 2 {
+		t.Errorf("unexpected edge weight: got:%v want:2", e.Weight())
+	}
+}
+
+func TestUnmarshalError(t *testing.T) {
+	g := simple.NewWeightedDirectedGraph(0, 0)
+	g.SetWeightedEdge(g.NewWeightedEdge(simple.Node(1), simple.Node(2), 5))
+	data, err := Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(magic)+2]++
+
+	future := append([]byte(nil), data[:len(data)-4]...)
+	future[len(magic)] = Version + 1
+	future = withChecksum(future)
+
+	truncated := withChecksum(append([]byte(nil), data[:len(data)-9]...))
+
+	for _, test := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "not a snapshot", data: []byte("digraph { a -> b }")},
+		{name: "checksum mismatch", data: corrupt},
+		{name: "future version", data: future},
+		{name: "truncated", data: truncated},
+	} {
+		err := Unmarshal(test.data, simple.NewWeightedDirectedGraph(0, 0))
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+// withChecksum returns b with its snapshot checksum appended.
+func withChecksum(b []byte) []byte {
+	var sum [4]byte
+	binary.LittleEndian.PutUint32(sum[:], crc32.ChecksumIEEE(b))
+	return append(b, sum[:]...)
+}
+
+func benchmarkGraph(b *testing.B) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	err := gen.Gnp(g, 1000, 0.05, rand.NewSource(1))
+	if err != nil {
+		b.Fatalf("unexpected error generating graph: %v", err)
+	}
+	return g
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	g := benchmarkGraph(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := Marshal(g)
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	data, err := Marshal(benchmarkGraph(b))
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := Unmarshal(data, simple.NewDirectedGraph())
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalDOT(b *testing.B) {
+	g := benchmarkGraph(b)
+	data, err := dot.Marshal(g, "", "", "")
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := dot.Unmarshal(data, simple.NewDirectedGraph())
+		if err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
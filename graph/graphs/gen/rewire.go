@@ -0,0 +1,83 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"errors"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// DegreePreservingRewire randomizes the undirected graph dst by performing
+// the given number of double edge swaps. Each swap replaces a randomly chosen
+// pair of edges {a,b} and {c,d} with either {a,d} and {c,b} or {a,c} and
+// {b,d}, preserving the degree of every node. Swaps that would introduce a
+// self edge or a duplicate edge are rejected and retried. If src is not nil
+// it is used as the random source, otherwise rand.Intn is used.
+//
+// DegreePreservingRewire returns an error if dst has fewer than two edges or
+// if the requested number of swaps cannot be completed within 100 attempts
+// per swap. In the latter case, the successful swaps are retained in dst.
+func DegreePreservingRewire(dst UndirectedMutator, swaps int, src rand.Source) error {
+	var rndN func(int) int
+	if src == nil {
+		rndN = rand.Intn
+	} else {
+		rndN = rand.New(src).Intn
+	}
+
+	var edges [][2]graph.Node
+	nodes := graph.NodesOf(dst.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(dst.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			if uid < v.ID() {
+				edges = append(edges, [2]graph.Node{u, v})
+			}
+		}
+	}
+	if len(edges) < 2 {
+		return errors.New("gen: too few edges to rewire")
+	}
+
+	for done, tries := 0, 0; done < swaps; tries++ {
+		if tries >= 100*swaps {
+			return errors.New("gen: failed to complete rewiring")
+		}
+		i := rndN(len(edges))
+		j := rndN(len(edges) - 1)
+		if j >= i {
+			j++
+		}
+		a, b := edges[i][0], edges[i][1]
+		c, d := edges[j][0], edges[j][1]
+		if rndN(2) == 0 {
+			c, d = d, c
+		}
+		// Replace {a,b} and {c,d} with {a,d} and {c,b}.
+		aid, bid, cid, did := a.ID(), b.ID(), c.ID(), d.ID()
+		if aid == did || cid == bid {
+			continue
+		}
+		if dst.HasEdgeBetween(aid, did) || dst.HasEdgeBetween(cid, bid) {
+			continue
+		}
+		dst.RemoveEdge(aid, bid)
+		dst.RemoveEdge(cid, did)
+		dst.SetEdge(dst.NewEdge(a, d))
+		dst.SetEdge(dst.NewEdge(c, b))
+		edges[i] = [2]graph.Node{a, d}
+		edges[j] = [2]graph.Node{c, b}
+		done++
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gen
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestDegreePreservingRewire(t *testing.T) {
+	const n = 50
+	g := simple.NewUndirectedGraph()
+	err := PreferentialAttachment(g, n, 3, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error generating graph: %v", err)
+	}
+	wantDeg := make(map[int64]int)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		wantDeg[id] = g.From(id).Len()
+	}
+	edges := g.Edges().Len()
+	before := simple.NewUndirectedGraph()
+	graph.Copy(before, g)
+
+	err = DegreePreservingRewire(g, 200, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error rewiring graph: %v", err)
+	}
+	if got := g.Edges().Len(); got != edges {
+		t.Errorf("unexpected number of edges after rewiring: got:%d want:%d", got, edges)
+	}
+	var changed bool
+	for _, e := range graph.EdgesOf(g.Edges()) {
+		if !before.HasEdgeBetween(e.From().ID(), e.To().ID()) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		t.Error("graph not changed by rewiring")
+	}
+	for id, want := range wantDeg {
+		if got := g.From(id).Len(); got != want {
+			t.Errorf("unexpected degree for node %d after rewiring: got:%d want:%d", id, got, want)
+		}
+	}
+
+	if DegreePreservingRewire(simple.NewUndirectedGraph(), 1, nil) == nil {
+		t.Error("expected error rewiring empty graph")
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypergraph
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// ConnectedComponents returns the connected components of h. Two nodes are
+// connected if they are linked by a sequence of hyperedges where consecutive
+// hyperedges share a member. Each component is ordered by node ID and the
+// components are ordered by their lowest node ID.
+func ConnectedComponents(h *Hypergraph) [][]graph.Node {
+	parent := make(map[int64]int64, len(h.nodes))
+	for id := range h.nodes {
+		parent[id] = id
+	}
+	var find func(int64) int64
+	find = func(id int64) int64 {
+		for parent[id] != id {
+			parent[id] = parent[parent[id]]
+			id = parent[id]
+		}
+		return id
+	}
+	for _, m := range h.members {
+		var root int64
+		first := true
+		for id := range m {
+			if first {
+				root = find(id)
+				first = false
+				continue
+			}
+			r := find(id)
+			if r != root {
+				parent[r] = root
+			}
+		}
+	}
+
+	comps := make(map[int64][]graph.Node)
+	for id, n := range h.nodes {
+		r := find(id)
+		comps[r] = append(comps[r], n)
+	}
+	cc := make([][]graph.Node, 0, len(comps))
+	for _, c := range comps {
+		sort.Sort(ordered.ByID(c))
+		cc = append(cc, c)
+	}
+	sort.Slice(cc, func(i, j int) bool { return cc[i][0].ID() < cc[j][0].ID() })
+	return cc
+}
+
+// Cut returns the total weight of the hyperedges of h that span more than
+// one part of the given partition. The partition maps node IDs to part
+// labels. Nodes absent from the partition are considered to be in part 0.
+func Cut(h *Hypergraph, part map[int64]int) float64 {
+	var cut float64
+	for eid, m := range h.members {
+		first := true
+		var p int
+		for id := range m {
+			if first {
+				p = part[id]
+				first = false
+				continue
+			}
+			if part[id] != p {
+				cut += h.weights[eid]
+				break
+			}
+		}
+	}
+	return cut
+}
+
+// Bisection is a two-way partition of a hypergraph.
+type Bisection struct {
+	// Parts holds the nodes of each
+	// part ordered by ID.
+	Parts [2][]graph.Node
+
+	// Cut is the total weight of the
+	// hyperedges spanning both parts.
+	Cut float64
+}
+
+// Bisect returns a balanced two-way partition of the nodes of h that
+// heuristically minimizes the weight of hyperedges spanning both parts,
+// using the Fiduccia–Mattheyses refinement algorithm starting from a
+// random partition.
+//
+// The imbalance parameter specifies the permitted fractional deviation
+// of part sizes from an even split; each part holds at most
+// ⌈(1+imbalance)n/2⌉ of the n nodes, and when n > 1 each part holds at
+// least one node. If src is nil, rand.Intn from
+// golang.org/x/exp/rand is used to generate the initial partition.
+// Bisect will panic if imbalance is negative.
+//
+// See https://doi.org/10.1145/800263.809204 for details.
+func Bisect(h *Hypergraph, imbalance float64, src rand.Source) Bisection {
+	if imbalance < 0 {
+		panic("hypergraph: negative imbalance")
+	}
+
+	nodes := make([]graph.Node, 0, len(h.nodes))
+	for _, n := range h.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Sort(ordered.ByID(nodes))
+	n := len(nodes)
+	if n == 0 {
+		return Bisection{}
+	}
+
+	var perm func(int) []int
+	if src == nil {
+		perm = rand.Perm
+	} else {
+		perm = rand.New(src).Perm
+	}
+	maxSize := int(math.Ceil((1 + imbalance) * float64(n) / 2))
+	if maxSize >= n && n > 1 {
+		maxSize = n - 1
+	}
+
+	side := make(map[int64]int, n)
+	var size [2]int
+	for i, j := range perm(n) {
+		s := 0
+		if i >= (n+1)/2 {
+			s = 1
+		}
+		side[nodes[j].ID()] = s
+		size[s]++
+	}
+
+	// count holds the number of members
+	// of each hyperedge on each side.
+	count := make(map[int64]*[2]int, len(h.members))
+	for eid, m := range h.members {
+		var c [2]int
+		for id := range m {
+			c[side[id]]++
+		}
+		count[eid] = &c
+	}
+
+	gain := func(id int64) float64 {
+		s := side[id]
+		var g float64
+		for eid := range h.incident[id] {
+			c := count[eid]
+			before := c[0] > 0 && c[1] > 0
+			after := c[s] > 1
+			switch {
+			case before && !after:
+				g += h.weights[eid]
+			case !before && after:
+				g -= h.weights[eid]
+			}
+		}
+		return g
+	}
+	move := func(id int64) {
+		s := side[id]
+		for eid := range h.incident[id] {
+			c := count[eid]
+			c[s]--
+			c[1-s]++
+		}
+		side[id] = 1 - s
+		size[s]--
+		size[1-s]++
+	}
+
+	for {
+		locked := make(map[int64]bool, n)
+		var (
+			moves     []int64
+			total     float64
+			best      float64
+			bestMoves int
+		)
+		for len(moves) < n {
+			var (
+				next    int64
+				maxGain = math.Inf(-1)
+				found   bool
+			)
+			for _, u := range nodes {
+				id := u.ID()
+				// Moves may transiently exceed the size limit
+				// by one node, but only balanced prefixes of
+				// the pass are retained.
+				if locked[id] || size[1-side[id]] >= maxSize+1 {
+					continue
+				}
+				if g := gain(id); g > maxGain {
+					next, maxGain, found = id, g, true
+				}
+			}
+			if !found {
+				break
+			}
+			move(next)
+			locked[next] = true
+			moves = append(moves, next)
+			total += maxGain
+			if total > best && size[0] <= maxSize && size[1] <= maxSize {
+				best = total
+				bestMoves = len(moves)
+			}
+		}
+		for i := len(moves) - 1; i >= bestMoves; i-- {
+			move(moves[i])
+		}
+		if best <= 0 {
+			break
+		}
+	}
+
+	var b Bisection
+	for _, u := range nodes {
+		s := side[u.ID()]
+		b.Parts[s] = append(b.Parts[s], u)
+	}
+	b.Cut = Cut(h, side)
+	return b
+}
@@ -0,0 +1,87 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypergraph
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestConnectedComponents(t *testing.T) {
+	h := New()
+	h.AddHyperedge(nodes(4, 1, 2)...)
+	h.AddHyperedge(nodes(2, 5)...)
+	h.AddHyperedge(nodes(3, 0)...)
+	h.AddNode(nodes(6)[0])
+
+	var got [][]int64
+	for _, c := range ConnectedComponents(h) {
+		got = append(got, ids(c))
+	}
+	want := [][]int64{{0, 3}, {1, 2, 4, 5}, {6}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected components: got:%v want:%v", got, want)
+	}
+}
+
+func TestCut(t *testing.T) {
+	h := New()
+	h.AddHyperedge(nodes(0, 1, 2)...)
+	h.AddWeightedHyperedge(3, nodes(2, 3)...)
+	h.AddWeightedHyperedge(5, nodes(0, 1)...)
+	if got := Cut(h, map[int64]int{2: 1, 3: 1}); got != 1 {
+		t.Errorf("unexpected cut: got:%v want:1", got)
+	}
+	if got := Cut(h, map[int64]int{3: 1}); got != 3 {
+		t.Errorf("unexpected cut: got:%v want:3", got)
+	}
+}
+
+// clusteredHypergraph returns a hypergraph with two densely connected
+// clusters of five nodes joined by a single hyperedge.
+func clusteredHypergraph() *Hypergraph {
+	h := New()
+	for _, c := range [][]int64{{0, 2, 4, 6, 8}, {1, 3, 5, 7, 9}} {
+		for i := range c {
+			for j := i + 1; j < len(c); j++ {
+				h.AddHyperedge(nodes(c[i], c[j])...)
+			}
+			h.AddHyperedge(nodes(c[i], c[(i+1)%len(c)], c[(i+2)%len(c)])...)
+		}
+	}
+	h.AddHyperedge(nodes(8, 9)...)
+	return h
+}
+
+func TestBisect(t *testing.T) {
+	h := clusteredHypergraph()
+	for seed := uint64(1); seed <= 10; seed++ {
+		b := Bisect(h, 0, rand.NewSource(seed))
+		if len(b.Parts[0]) != 5 || len(b.Parts[1]) != 5 {
+			t.Errorf("unexpected part sizes for seed %d: got:%d,%d want:5,5", seed, len(b.Parts[0]), len(b.Parts[1]))
+		}
+		part := make(map[int64]int)
+		for _, n := range b.Parts[1] {
+			part[n.ID()] = 1
+		}
+		if got := Cut(h, part); got != b.Cut {
+			t.Errorf("inconsistent cut for seed %d: got:%v want:%v", seed, b.Cut, got)
+		}
+		if b.Cut != 1 {
+			t.Errorf("unexpected cut for seed %d: got:%v want:1", seed, b.Cut)
+		}
+	}
+
+	b := Bisect(h, 1, rand.NewSource(1))
+	if len(b.Parts[0]) == 0 || len(b.Parts[1]) == 0 {
+		t.Errorf("unexpected empty part with unbounded imbalance: got:%d,%d", len(b.Parts[0]), len(b.Parts[1]))
+	}
+
+	if b := Bisect(New(), 0, nil); b.Cut != 0 || b.Parts[0] != nil || b.Parts[1] != nil {
+		t.Errorf("unexpected bisection of empty hypergraph: %+v", b)
+	}
+}
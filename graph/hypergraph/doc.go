@@ -0,0 +1,11 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hypergraph provides a hypergraph implementation, conversions
+// from hypergraphs to graphs and hypergraph analysis functions.
+//
+// A hypergraph generalizes a graph by allowing an edge, a hyperedge, to
+// join any number of nodes. Hypergraphs model nets in VLSI circuits and
+// co-authorship of papers, amongst other relations that are not pairwise.
+package hypergraph // import "gonum.org/v1/gonum/graph/hypergraph"
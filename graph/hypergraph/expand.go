@@ -0,0 +1,87 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypergraph
+
+import "gonum.org/v1/gonum/graph"
+
+// CliqueExpansion adds the clique expansion of h to dst. Each node of h is
+// added to dst and every pair of nodes sharing a hyperedge is joined by an
+// edge. Hyperedges with a single member add no edges. CliqueExpansion will
+// panic if a node ID in h matches a node ID in dst.
+func CliqueExpansion(dst graph.Builder, h *Hypergraph) {
+	addNodes(dst, h)
+	seen := make(map[[2]int64]bool)
+	for _, eid := range h.Hyperedges() {
+		ids := h.nodesOf(eid)
+		for i, uid := range ids {
+			for _, vid := range ids[i+1:] {
+				if seen[[2]int64{uid, vid}] {
+					continue
+				}
+				seen[[2]int64{uid, vid}] = true
+				dst.SetEdge(dst.NewEdge(h.nodes[uid], h.nodes[vid]))
+			}
+		}
+	}
+}
+
+// WeightedCliqueExpansion adds the weighted clique expansion of h to dst.
+// Each node of h is added to dst and every pair of nodes sharing a hyperedge
+// is joined by an edge. The weight of an edge is the sum of w/(k-1) over the
+// hyperedges with weight w and k members that include both its end points,
+// so the total weight incident to a node from a hyperedge equals the weight
+// of the hyperedge. WeightedCliqueExpansion will panic if a node ID in h
+// matches a node ID in dst.
+func WeightedCliqueExpansion(dst graph.WeightedBuilder, h *Hypergraph) {
+	addNodes(dst, h)
+	var pairs [][2]int64
+	weight := make(map[[2]int64]float64)
+	for _, eid := range h.Hyperedges() {
+		ids := h.nodesOf(eid)
+		if len(ids) < 2 {
+			continue
+		}
+		w := h.weights[eid] / float64(len(ids)-1)
+		for i, uid := range ids {
+			for _, vid := range ids[i+1:] {
+				p := [2]int64{uid, vid}
+				if _, ok := weight[p]; !ok {
+					pairs = append(pairs, p)
+				}
+				weight[p] += w
+			}
+		}
+	}
+	for _, p := range pairs {
+		dst.SetWeightedEdge(dst.NewWeightedEdge(h.nodes[p[0]], h.nodes[p[1]], weight[p]))
+	}
+}
+
+// StarExpansion adds the star expansion of h to dst. Each node of h is
+// added to dst, a new node is added to dst for each hyperedge and each
+// hyperedge node is joined by an edge to the nodes of its members. The
+// returned map holds the ID of the dst node representing each hyperedge,
+// keyed by hyperedge ID. StarExpansion will panic if a node ID in h matches
+// a node ID in dst.
+func StarExpansion(dst graph.Builder, h *Hypergraph) map[int64]int64 {
+	addNodes(dst, h)
+	star := make(map[int64]int64, len(h.members))
+	for _, eid := range h.Hyperedges() {
+		s := dst.NewNode()
+		dst.AddNode(s)
+		star[eid] = s.ID()
+		for _, id := range h.nodesOf(eid) {
+			dst.SetEdge(dst.NewEdge(s, h.nodes[id]))
+		}
+	}
+	return star
+}
+
+func addNodes(dst graph.NodeAdder, h *Hypergraph) {
+	nodes := h.Nodes()
+	for nodes.Next() {
+		dst.AddNode(nodes.Node())
+	}
+}
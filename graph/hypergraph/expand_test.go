@@ -0,0 +1,87 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypergraph
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func expansionHypergraph() *Hypergraph {
+	h := New()
+	h.AddHyperedge(nodes(0, 1, 2)...)
+	h.AddWeightedHyperedge(4, nodes(1, 2)...)
+	h.AddHyperedge(nodes(3)...)
+	return h
+}
+
+func TestCliqueExpansion(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	CliqueExpansion(g, expansionHypergraph())
+	if g.Nodes().Len() != 4 {
+		t.Errorf("unexpected number of nodes: got:%d want:4", g.Nodes().Len())
+	}
+	if g.Edges().Len() != 3 {
+		t.Errorf("unexpected number of edges: got:%d want:3", g.Edges().Len())
+	}
+	for _, e := range [][2]int64{{0, 1}, {0, 2}, {1, 2}} {
+		if !g.HasEdgeBetween(e[0], e[1]) {
+			t.Errorf("missing edge %v", e)
+		}
+	}
+}
+
+func TestWeightedCliqueExpansion(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	WeightedCliqueExpansion(g, expansionHypergraph())
+	for _, test := range []struct {
+		u, v int64
+		w    float64
+	}{
+		{u: 0, v: 1, w: 0.5},
+		{u: 0, v: 2, w: 0.5},
+		{u: 1, v: 2, w: 4.5},
+	} {
+		w, ok := g.Weight(test.u, test.v)
+		if !ok || !floats.EqualWithinAbsOrRel(w, test.w, 1e-12, 1e-12) {
+			t.Errorf("unexpected weight for %d--%d: got:%v ok=%t want:%v", test.u, test.v, w, ok, test.w)
+		}
+	}
+	if g.Edges().Len() != 3 {
+		t.Errorf("unexpected number of edges: got:%d want:3", g.Edges().Len())
+	}
+}
+
+func TestStarExpansion(t *testing.T) {
+	h := expansionHypergraph()
+	g := simple.NewUndirectedGraph()
+	star := StarExpansion(g, h)
+	if g.Nodes().Len() != 7 {
+		t.Errorf("unexpected number of nodes: got:%d want:7", g.Nodes().Len())
+	}
+	if g.Edges().Len() != 6 {
+		t.Errorf("unexpected number of edges: got:%d want:6", g.Edges().Len())
+	}
+	for _, eid := range h.Hyperedges() {
+		sid, ok := star[eid]
+		if !ok {
+			t.Fatalf("missing star node for hyperedge %d", eid)
+		}
+		if h.Node(sid) != nil {
+			t.Errorf("star node %d collides with hypergraph node", sid)
+		}
+		members := h.Members(eid)
+		if g.From(sid).Len() != members.Len() {
+			t.Errorf("unexpected star degree for hyperedge %d: got:%d want:%d", eid, g.From(sid).Len(), members.Len())
+		}
+		for members.Next() {
+			if !g.HasEdgeBetween(sid, members.Node().ID()) {
+				t.Errorf("missing star edge %d--%d", sid, members.Node().ID())
+			}
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypergraph
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/internal/set"
+	"gonum.org/v1/gonum/graph/internal/uid"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Hypergraph implements a weighted hypergraph.
+type Hypergraph struct {
+	nodes map[int64]graph.Node
+
+	// members holds the IDs of the
+	// nodes in each hyperedge and
+	// incident holds the IDs of the
+	// hyperedges incident to each node.
+	members  map[int64]set.Int64s
+	incident map[int64]set.Int64s
+	weights  map[int64]float64
+
+	nodeIDs uid.Set
+	edgeIDs uid.Set
+}
+
+// New returns an empty hypergraph.
+func New() *Hypergraph {
+	return &Hypergraph{
+		nodes:    make(map[int64]graph.Node),
+		members:  make(map[int64]set.Int64s),
+		incident: make(map[int64]set.Int64s),
+		weights:  make(map[int64]float64),
+
+		nodeIDs: uid.NewSet(),
+		edgeIDs: uid.NewSet(),
+	}
+}
+
+// NewNode returns a new unique Node to be added to h. The Node's ID does
+// not become valid in h until the Node is added to h.
+func (h *Hypergraph) NewNode() graph.Node {
+	if len(h.nodes) == 0 {
+		return simple.Node(0)
+	}
+	return simple.Node(h.nodeIDs.NewID())
+}
+
+// AddNode adds n to the hypergraph. It panics if the added node ID matches
+// an existing node ID.
+func (h *Hypergraph) AddNode(n graph.Node) {
+	if _, exists := h.nodes[n.ID()]; exists {
+		panic(fmt.Sprintf("hypergraph: node ID collision: %d", n.ID()))
+	}
+	h.nodes[n.ID()] = n
+	h.incident[n.ID()] = make(set.Int64s)
+	h.nodeIDs.Use(n.ID())
+}
+
+// RemoveNode removes the node with the given ID from the hypergraph,
+// removing it from all hyperedges. Hyperedges left without members are
+// removed. If the node is not in the hypergraph it is a no-op.
+func (h *Hypergraph) RemoveNode(id int64) {
+	if _, ok := h.nodes[id]; !ok {
+		return
+	}
+	for eid := range h.incident[id] {
+		h.members[eid].Remove(id)
+		if h.members[eid].Count() == 0 {
+			h.RemoveHyperedge(eid)
+		}
+	}
+	delete(h.nodes, id)
+	delete(h.incident, id)
+	h.nodeIDs.Release(id)
+}
+
+// Node returns the node with the given ID if it exists in the hypergraph,
+// and nil otherwise.
+func (h *Hypergraph) Node(id int64) graph.Node {
+	return h.nodes[id]
+}
+
+// Nodes returns all the nodes in the hypergraph ordered by ID.
+func (h *Hypergraph) Nodes() graph.Nodes {
+	if len(h.nodes) == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, 0, len(h.nodes))
+	for _, n := range h.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// AddHyperedge adds a hyperedge of weight 1 joining the given nodes and
+// returns its ID. Nodes that do not exist in the hypergraph are added.
+// Repeated nodes are included once. AddHyperedge will panic if no nodes
+// are given.
+func (h *Hypergraph) AddHyperedge(nodes ...graph.Node) int64 {
+	return h.AddWeightedHyperedge(1, nodes...)
+}
+
+// AddWeightedHyperedge adds a hyperedge with weight w joining the given
+// nodes and returns its ID. Nodes that do not exist in the hypergraph are
+// added. Repeated nodes are included once. AddWeightedHyperedge will panic
+// if no nodes are given.
+func (h *Hypergraph) AddWeightedHyperedge(w float64, nodes ...graph.Node) int64 {
+	if len(nodes) == 0 {
+		panic("hypergraph: empty hyperedge")
+	}
+	eid := h.edgeIDs.NewID()
+	h.edgeIDs.Use(eid)
+	m := make(set.Int64s, len(nodes))
+	for _, n := range nodes {
+		id := n.ID()
+		if _, ok := h.nodes[id]; !ok {
+			h.AddNode(n)
+		}
+		m.Add(id)
+		h.incident[id].Add(eid)
+	}
+	h.members[eid] = m
+	h.weights[eid] = w
+	return eid
+}
+
+// RemoveHyperedge removes the hyperedge with the given ID, leaving its
+// member nodes. If the hyperedge does not exist it is a no-op.
+func (h *Hypergraph) RemoveHyperedge(eid int64) {
+	m, ok := h.members[eid]
+	if !ok {
+		return
+	}
+	for id := range m {
+		h.incident[id].Remove(eid)
+	}
+	delete(h.members, eid)
+	delete(h.weights, eid)
+	h.edgeIDs.Release(eid)
+}
+
+// Hyperedges returns the IDs of all the hyperedges in the hypergraph in
+// ascending order.
+func (h *Hypergraph) Hyperedges() []int64 {
+	ids := make([]int64, 0, len(h.members))
+	for eid := range h.members {
+		ids = append(ids, eid)
+	}
+	sort.Sort(ordered.Int64s(ids))
+	return ids
+}
+
+// HasHyperedge returns whether the hyperedge with the given ID exists in
+// the hypergraph.
+func (h *Hypergraph) HasHyperedge(eid int64) bool {
+	_, ok := h.members[eid]
+	return ok
+}
+
+// Members returns the nodes joined by the hyperedge with the given ID
+// ordered by ID. If the hyperedge does not exist, Members returns
+// graph.Empty.
+func (h *Hypergraph) Members(eid int64) graph.Nodes {
+	m := h.members[eid]
+	if len(m) == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, 0, len(m))
+	for id := range m {
+		nodes = append(nodes, h.nodes[id])
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// Incident returns the IDs of the hyperedges incident to the node with the
+// given ID in ascending order.
+func (h *Hypergraph) Incident(id int64) []int64 {
+	inc := h.incident[id]
+	if len(inc) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(inc))
+	for eid := range inc {
+		ids = append(ids, eid)
+	}
+	sort.Sort(ordered.Int64s(ids))
+	return ids
+}
+
+// Weight returns the weight of the hyperedge with the given ID and whether
+// the hyperedge exists.
+func (h *Hypergraph) Weight(eid int64) (w float64, ok bool) {
+	w, ok = h.weights[eid]
+	return w, ok
+}
+
+// Degree returns the number of hyperedges incident to the node with the
+// given ID.
+func (h *Hypergraph) Degree(id int64) int {
+	return len(h.incident[id])
+}
+
+// Size returns the number of nodes joined by the hyperedge with the given
+// ID.
+func (h *Hypergraph) Size(eid int64) int {
+	return len(h.members[eid])
+}
+
+// nodesOf returns the IDs of the members of the hyperedge with the given ID
+// in ascending order.
+func (h *Hypergraph) nodesOf(eid int64) []int64 {
+	m := h.members[eid]
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Sort(ordered.Int64s(ids))
+	return ids
+}
@@ -0,0 +1,92 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypergraph
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func nodes(ids ...int64) []graph.Node {
+	n := make([]graph.Node, len(ids))
+	for i, id := range ids {
+		n[i] = simple.Node(id)
+	}
+	return n
+}
+
+func ids(nodes []graph.Node) []int64 {
+	if nodes == nil {
+		return nil
+	}
+	id := make([]int64, len(nodes))
+	for i, n := range nodes {
+		id[i] = n.ID()
+	}
+	return id
+}
+
+func TestHypergraph(t *testing.T) {
+	h := New()
+	if n := h.NewNode(); n.ID() != 0 {
+		t.Errorf("unexpected first new node ID: got:%d want:0", n.ID())
+	}
+	e0 := h.AddHyperedge(nodes(0, 1, 2, 1)...)
+	e1 := h.AddWeightedHyperedge(2, nodes(2, 3)...)
+	h.AddNode(simple.Node(5))
+
+	if got := ids(graph.NodesOf(h.Nodes())); !reflect.DeepEqual(got, []int64{0, 1, 2, 3, 5}) {
+		t.Errorf("unexpected nodes: got:%v", got)
+	}
+	if n := h.NewNode(); h.Node(n.ID()) != nil {
+		t.Errorf("new node ID %d exists in hypergraph", n.ID())
+	}
+	if got := h.Hyperedges(); !reflect.DeepEqual(got, []int64{e0, e1}) {
+		t.Errorf("unexpected hyperedges: got:%v want:%v", got, []int64{e0, e1})
+	}
+	if got := ids(graph.NodesOf(h.Members(e0))); !reflect.DeepEqual(got, []int64{0, 1, 2}) {
+		t.Errorf("unexpected members: got:%v", got)
+	}
+	if got := h.Incident(2); !reflect.DeepEqual(got, []int64{e0, e1}) {
+		t.Errorf("unexpected incident hyperedges: got:%v", got)
+	}
+	if h.Degree(2) != 2 || h.Degree(5) != 0 || h.Size(e0) != 3 || h.Size(e1) != 2 {
+		t.Error("unexpected degree or size")
+	}
+	if w, ok := h.Weight(e1); !ok || w != 2 {
+		t.Errorf("unexpected weight: got:%v ok=%t", w, ok)
+	}
+
+	h.RemoveNode(2)
+	if got := h.Incident(2); got != nil {
+		t.Errorf("unexpected incident hyperedges for removed node: got:%v", got)
+	}
+	if got := ids(graph.NodesOf(h.Members(e0))); !reflect.DeepEqual(got, []int64{0, 1}) {
+		t.Errorf("unexpected members after node removal: got:%v", got)
+	}
+	h.RemoveNode(3)
+	if h.HasHyperedge(e1) {
+		t.Error("expected empty hyperedge to be removed")
+	}
+	h.RemoveHyperedge(e0)
+	if h.HasHyperedge(e0) || h.Degree(0) != 0 {
+		t.Error("hyperedge not removed")
+	}
+	if h.Members(e0) != graph.Empty {
+		t.Error("expected empty members for removed hyperedge")
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		h.AddHyperedge()
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for empty hyperedge")
+	}
+}
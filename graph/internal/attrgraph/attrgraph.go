@@ -0,0 +1,105 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package attrgraph provides weighted graphs with nodes, edges and graph
+// attributes held as ordered attribute lists, for testing the graph
+// encoding packages.
+package attrgraph
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Attrs is an ordered list of attributes.
+type Attrs []encoding.Attribute
+
+// Attributes returns the attributes in a.
+func (a Attrs) Attributes() []encoding.Attribute { return a }
+
+// SetAttribute appends attr to a.
+func (a *Attrs) SetAttribute(attr encoding.Attribute) error {
+	*a = append(*a, attr)
+	return nil
+}
+
+// Node is a node holding an ordered attribute list.
+type Node = simple.AttrNode[Attrs]
+
+// Edge is a weighted edge holding an ordered attribute list.
+type Edge struct {
+	simple.WeightedEdge
+	Attrs
+}
+
+// ReversedEdge returns a new Edge with the F and T fields swapped.
+// The attributes of the new edge are shared with the receiver.
+func (e *Edge) ReversedEdge() graph.Edge {
+	return &Edge{WeightedEdge: simple.WeightedEdge{F: e.T, T: e.F, W: e.W}, Attrs: e.Attrs}
+}
+
+// Directed is a weighted directed graph of *Node nodes and *Edge edges
+// with graph attributes.
+type Directed struct {
+	*simple.WeightedDirectedGraph
+	*Attrs
+}
+
+// NewDirected returns an empty Directed graph.
+func NewDirected() Directed {
+	return Directed{WeightedDirectedGraph: simple.NewWeightedDirectedGraph(0, 0), Attrs: &Attrs{}}
+}
+
+// MakeNode returns a *Node with the given ID.
+func (g Directed) MakeNode(id int64) graph.Node { return &Node{NodeID: id} }
+
+// NewNode returns a new unique *Node to be added to g.
+func (g Directed) NewNode() graph.Node {
+	return &Node{NodeID: g.WeightedDirectedGraph.NewNode().ID()}
+}
+
+// NewEdge returns a new *Edge from the source to the destination node
+// with a weight of 1.
+func (g Directed) NewEdge(from, to graph.Node) graph.Edge { return g.NewWeightedEdge(from, to, 1) }
+
+// NewWeightedEdge returns a new *Edge from the source to the destination
+// node.
+func (g Directed) NewWeightedEdge(from, to graph.Node, weight float64) graph.WeightedEdge {
+	return &Edge{WeightedEdge: simple.WeightedEdge{F: from, T: to, W: weight}}
+}
+
+// SetEdge adds e, which must be an *Edge, to g.
+func (g Directed) SetEdge(e graph.Edge) { g.SetWeightedEdge(e.(*Edge)) }
+
+// Undirected is a weighted undirected graph of *Node nodes and *Edge edges
+// with graph attributes.
+type Undirected struct {
+	*simple.WeightedUndirectedGraph
+	*Attrs
+}
+
+// NewUndirected returns an empty Undirected graph.
+func NewUndirected() Undirected {
+	return Undirected{WeightedUndirectedGraph: simple.NewWeightedUndirectedGraph(0, 0), Attrs: &Attrs{}}
+}
+
+// MakeNode returns a *Node with the given ID.
+func (g Undirected) MakeNode(id int64) graph.Node { return &Node{NodeID: id} }
+
+// NewNode returns a new unique *Node to be added to g.
+func (g Undirected) NewNode() graph.Node {
+	return &Node{NodeID: g.WeightedUndirectedGraph.NewNode().ID()}
+}
+
+// NewEdge returns a new *Edge between the given nodes with a weight of 1.
+func (g Undirected) NewEdge(from, to graph.Node) graph.Edge { return g.NewWeightedEdge(from, to, 1) }
+
+// NewWeightedEdge returns a new *Edge between the given nodes.
+func (g Undirected) NewWeightedEdge(from, to graph.Node, weight float64) graph.WeightedEdge {
+	return &Edge{WeightedEdge: simple.WeightedEdge{F: from, T: to, W: weight}}
+}
+
+// SetEdge adds e, which must be an *Edge, to g.
+func (g Undirected) SetEdge(e graph.Edge) { g.SetWeightedEdge(e.(*Edge)) }
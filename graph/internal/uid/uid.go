@@ -21,6 +21,12 @@ func NewSet() Set {
 	return Set{maxID: -1, used: make(set.Int64s), free: make(set.Int64s)}
 }
 
+// NewSetSize returns a new Set with capacity for n used IDs. The returned
+// value should not be passed except by pointer.
+func NewSetSize(n int) Set {
+	return Set{maxID: -1, used: make(set.Int64s, n), free: make(set.Int64s)}
+}
+
 // NewID returns a new unique ID. The ID returned is not considered used
 // until passed in a call to use.
 func (s *Set) NewID() int64 {
@@ -0,0 +1,7 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package matrix provides conversions between graphs and their adjacency,
+// Laplacian and incidence matrices.
+package matrix // import "gonum.org/v1/gonum/graph/matrix"
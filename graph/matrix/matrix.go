@@ -0,0 +1,344 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrix
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Matrix is a matrix representation of a graph.
+type Matrix struct {
+	// Matrix holds the matrix.
+	mat.Matrix
+
+	// Nodes holds the graph nodes in order
+	// of their row index.
+	Nodes []graph.Node
+
+	// Index is a mapping from the graph
+	// node IDs to row indices.
+	Index map[int64]int
+
+	// Edges holds the graph edges in order
+	// of their column index for incidence
+	// matrices. It is nil otherwise.
+	Edges []graph.Edge
+}
+
+// Weighting returns the weight of the edge from the node with ID uid to
+// the node with ID vid. It is only called for edges that exist.
+type Weighting func(uid, vid int64) float64
+
+// Unit is a Weighting that gives each edge a weight of 1.
+func Unit(uid, vid int64) float64 { return 1 }
+
+// Normalization specifies the normalization of an adjacency or
+// Laplacian matrix.
+type Normalization int
+
+const (
+	// None specifies no normalization.
+	None Normalization = iota
+
+	// Symmetric specifies normalization by
+	// D^(-1/2) on the left and right, where D
+	// is the diagonal degree matrix.
+	Symmetric
+
+	// RandomWalk specifies normalization by
+	// D^(-1) on the left, so that each row of
+	// the normalized adjacency matrix sums to
+	// one.
+	RandomWalk
+)
+
+// Options specifies the construction of a matrix from a graph.
+type Options struct {
+	// Weight returns the weights of edges.
+	// If Weight is nil, the weights of
+	// graph.Weighted graphs are used and
+	// edges of other graphs have a weight
+	// of 1.
+	Weight Weighting
+
+	// Normalization specifies the
+	// normalization of adjacency and
+	// Laplacian matrices.
+	Normalization Normalization
+}
+
+// weighting returns the Weighting for g specified by opts.
+func (opts *Options) weighting(g graph.Graph) Weighting {
+	if opts != nil && opts.Weight != nil {
+		return opts.Weight
+	}
+	if wg, ok := g.(graph.Weighted); ok {
+		return func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+	return Unit
+}
+
+func (opts *Options) normalization() Normalization {
+	if opts == nil {
+		return None
+	}
+	return opts.Normalization
+}
+
+// nodesOf returns the nodes of g in order of ID and a mapping from
+// node IDs to their index.
+func nodesOf(g graph.Graph) ([]graph.Node, map[int64]int) {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	index := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID()] = i
+	}
+	return nodes, index
+}
+
+// Adjacency returns the adjacency matrix A of g, with the element in row i
+// and column j holding the weight of the edge from the i-th to the j-th
+// node of g in order of ID. The matrix is a *mat.SymDense for undirected
+// graphs and a *mat.Dense otherwise, except for RandomWalk normalization,
+// which always gives a *mat.Dense. If opts is nil, the default options are
+// used.
+//
+// With Symmetric normalization, Adjacency returns D^(-1/2) A D^(-1/2), and
+// with RandomWalk normalization D^(-1) A, where D is the diagonal matrix of
+// the row sums of A. Rows with a zero sum are left unnormalized.
+//
+// Adjacency panics if g has no nodes.
+func Adjacency(g graph.Graph, opts *Options) Matrix {
+	nodes, index := nodesOf(g)
+	a := adjacency(g, nodes, index, opts.weighting(g))
+	norm := opts.normalization()
+	normalize(a, degrees(a), norm)
+	return Matrix{Matrix: result(g, a, norm), Nodes: nodes, Index: index}
+}
+
+// adjacency returns the unnormalized adjacency matrix of g.
+func adjacency(g graph.Graph, nodes []graph.Node, index map[int64]int, weight Weighting) *mat.Dense {
+	n := len(nodes)
+	a := mat.NewDense(n, n, nil)
+	for i, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			a.Set(i, index[vid], weight(uid, vid))
+		}
+	}
+	return a
+}
+
+// degrees returns the row sums of a.
+func degrees(a *mat.Dense) []float64 {
+	r, _ := a.Dims()
+	d := make([]float64, r)
+	for i := range d {
+		d[i] = floats.Sum(a.RawRowView(i))
+	}
+	return d
+}
+
+// normalize normalizes the adjacency matrix a with the degrees d in place.
+func normalize(a *mat.Dense, d []float64, norm Normalization) {
+	n := len(d)
+	switch norm {
+	case None:
+	case Symmetric:
+		s := make([]float64, n)
+		for i, v := range d {
+			s[i] = 1
+			if v != 0 {
+				s[i] = 1 / math.Sqrt(v)
+			}
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, s[i]*a.At(i, j)*s[j])
+			}
+		}
+	case RandomWalk:
+		for i, v := range d {
+			if v != 0 {
+				floats.Scale(1/v, a.RawRowView(i))
+			}
+		}
+	default:
+		panic("matrix: invalid normalization")
+	}
+}
+
+// result returns a as a *mat.SymDense if g is undirected and the
+// normalization preserves symmetry, and as a *mat.Dense otherwise.
+func result(g graph.Graph, a *mat.Dense, norm Normalization) mat.Matrix {
+	if _, directed := g.(graph.Directed); directed || norm == RandomWalk {
+		return a
+	}
+	n, _ := a.Dims()
+	return mat.NewSymDense(n, a.RawMatrix().Data)
+}
+
+// Laplacian returns the Laplacian matrix L = D - A of g, where A is the
+// adjacency matrix of g and D the diagonal matrix of the row sums of A. For
+// directed graphs, the row sums are the weighted out degrees of the nodes.
+// The matrix is a *mat.SymDense for undirected graphs and a *mat.Dense
+// otherwise, except for RandomWalk normalization, which always gives a
+// *mat.Dense. If opts is nil, the default options are used.
+//
+// With Symmetric normalization, Laplacian returns I - D^(-1/2) A D^(-1/2),
+// and with RandomWalk normalization I - D^(-1) A. The diagonal element of
+// a node with a zero degree is zero in normalized Laplacians.
+//
+// Laplacian panics if g has no nodes.
+func Laplacian(g graph.Graph, opts *Options) Matrix {
+	nodes, index := nodesOf(g)
+	a := adjacency(g, nodes, index, opts.weighting(g))
+	d := degrees(a)
+	norm := opts.normalization()
+	normalize(a, d, norm)
+
+	a.Scale(-1, a)
+	for i, v := range d {
+		switch {
+		case norm == None:
+			a.Set(i, i, a.At(i, i)+v)
+		case v != 0:
+			a.Set(i, i, a.At(i, i)+1)
+		}
+	}
+	return Matrix{Matrix: result(g, a, norm), Nodes: nodes, Index: index}
+}
+
+// Incidence returns the incidence matrix of g, with a row for each node of
+// g in order of ID and a column for each edge in order of from and then to
+// node ID. The edges of undirected graphs are held once, in the column of
+// the edge from the end point with the lower ID. If oriented is true, the
+// column of an edge holds -1 in the row of its from node and 1 in the row
+// of its to node, and the column of a self edge is zero. Otherwise the
+// column of an edge holds 1 in the rows of both its end points, and the
+// column of a self edge holds 2 in the row of its node.
+//
+// Incidence panics if g has no nodes or no edges.
+func Incidence(g graph.Graph, oriented bool) Matrix {
+	nodes, index := nodesOf(g)
+	_, directed := g.(graph.Directed)
+
+	var edges []graph.Edge
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !directed && vid < uid {
+				continue
+			}
+			edges = append(edges, g.Edge(uid, vid))
+		}
+	}
+
+	b := mat.NewDense(len(nodes), len(edges), nil)
+	for j, e := range edges {
+		u, v := index[e.From().ID()], index[e.To().ID()]
+		if oriented {
+			if u != v {
+				b.Set(u, j, -1)
+				b.Set(v, j, 1)
+			}
+			continue
+		}
+		b.Set(u, j, b.At(u, j)+1)
+		b.Set(v, j, b.At(v, j)+1)
+	}
+	return Matrix{Matrix: b, Nodes: nodes, Index: index, Edges: edges}
+}
+
+// FromAdjacency adds the graph with the adjacency matrix a to dst. The
+// i-th row and column of a correspond to nodes[i], or if nodes is nil, to
+// a node with ID i that is created with dst's MakeNode method if dst
+// implements encoding.NodeMaker, and as a simple.Node otherwise. The nodes
+// must not be held by dst.
+//
+// An edge is added from the i-th to the j-th node for each non-zero
+// off-diagonal element of a. Diagonal elements are ignored. Only the upper
+// triangle of symmetric matrices is used, and its edges are added in both
+// directions if dst is a graph.Directed. Edges are created with dst's
+// NewWeightedEdge method, with the element as the weight, if dst is a
+// graph.WeightedEdgeAdder, and with NewEdge otherwise.
+func FromAdjacency(dst graph.NodeAdder, a mat.Matrix, nodes []graph.Node) error {
+	r, c := a.Dims()
+	if r != c {
+		return errors.New("matrix: adjacency matrix is not square")
+	}
+	if nodes != nil && len(nodes) != r {
+		return errors.New("matrix: number of nodes does not match adjacency matrix")
+	}
+	wdst, isWeighted := dst.(graph.WeightedEdgeAdder)
+	udst, isUnweighted := dst.(graph.EdgeAdder)
+	if !isWeighted && !isUnweighted {
+		return errors.New("matrix: destination cannot add edges")
+	}
+
+	if nodes == nil {
+		nodes = make([]graph.Node, r)
+		maker, hasMaker := dst.(encoding.NodeMaker)
+		for i := range nodes {
+			if hasMaker {
+				nodes[i] = maker.MakeNode(int64(i))
+			} else {
+				nodes[i] = simple.Node(i)
+			}
+		}
+	}
+	for _, n := range nodes {
+		dst.AddNode(n)
+	}
+
+	// Symmetric matrices are walked once and
+	// added in both directions to directed
+	// destinations.
+	_, isSym := a.(mat.Symmetric)
+	_, reverse := dst.(graph.Directed)
+	reverse = reverse && isSym
+	setEdge := func(u, v graph.Node, w float64) {
+		if isWeighted {
+			wdst.SetWeightedEdge(wdst.NewWeightedEdge(u, v, w))
+		} else {
+			udst.SetEdge(udst.NewEdge(u, v))
+		}
+	}
+	for i, u := range nodes {
+		j := 0
+		if isSym {
+			j = i + 1
+		}
+		for ; j < c; j++ {
+			w := a.At(i, j)
+			if i == j || w == 0 {
+				continue
+			}
+			setEdge(u, nodes[j], w)
+			if reverse {
+				setEdge(nodes[j], u, w)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrix
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/mat"
+)
+
+// path is the weighted undirected path 0--1--2 with an isolated node 3.
+func path() *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 2})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 4})
+	g.AddNode(simple.Node(3))
+	return g
+}
+
+var matrixTests = []struct {
+	name string
+	g    graph.Graph
+	fn   func(graph.Graph, *Options) Matrix
+	opts *Options
+
+	want    *mat.Dense
+	wantSym bool
+}{
+	{
+		name: "adjacency",
+		g:    path(),
+		fn:   Adjacency,
+		want: mat.NewDense(4, 4, []float64{
+			0, 2, 0, 0,
+			2, 0, 4, 0,
+			0, 4, 0, 0,
+			0, 0, 0, 0,
+		}),
+		wantSym: true,
+	},
+	{
+		name: "unit adjacency",
+		g:    path(),
+		fn:   Adjacency,
+		opts: &Options{Weight: Unit},
+		want: mat.NewDense(4, 4, []float64{
+			0, 1, 0, 0,
+			1, 0, 1, 0,
+			0, 1, 0, 0,
+			0, 0, 0, 0,
+		}),
+		wantSym: true,
+	},
+	{
+		name: "symmetric adjacency",
+		g:    path(),
+		fn:   Adjacency,
+		opts: &Options{Normalization: Symmetric},
+		want: mat.NewDense(4, 4, []float64{
+			0, 2 / math.Sqrt(2*6), 0, 0,
+			2 / math.Sqrt(2*6), 0, 4 / math.Sqrt(6*4), 0,
+			0, 4 / math.Sqrt(6*4), 0, 0,
+			0, 0, 0, 0,
+		}),
+		wantSym: true,
+	},
+	{
+		name: "random walk adjacency",
+		g:    path(),
+		fn:   Adjacency,
+		opts: &Options{Normalization: RandomWalk},
+		want: mat.NewDense(4, 4, []float64{
+			0, 1, 0, 0,
+			1.0 / 3, 0, 2.0 / 3, 0,
+			0, 1, 0, 0,
+			0, 0, 0, 0,
+		}),
+	},
+	{
+		name: "laplacian",
+		g:    path(),
+		fn:   Laplacian,
+		want: mat.NewDense(4, 4, []float64{
+			2, -2, 0, 0,
+			-2, 6, -4, 0,
+			0, -4, 4, 0,
+			0, 0, 0, 0,
+		}),
+		wantSym: true,
+	},
+	{
+		name: "symmetric laplacian",
+		g:    path(),
+		fn:   Laplacian,
+		opts: &Options{Normalization: Symmetric},
+		want: mat.NewDense(4, 4, []float64{
+			1, -2 / math.Sqrt(2*6), 0, 0,
+			-2 / math.Sqrt(2*6), 1, -4 / math.Sqrt(6*4), 0,
+			0, -4 / math.Sqrt(6*4), 1, 0,
+			0, 0, 0, 0,
+		}),
+		wantSym: true,
+	},
+	{
+		name: "random walk laplacian",
+		g:    path(),
+		fn:   Laplacian,
+		opts: &Options{Normalization: RandomWalk},
+		want: mat.NewDense(4, 4, []float64{
+			1, -1, 0, 0,
+			-1.0 / 3, 1, -2.0 / 3, 0,
+			0, -1, 1, 0,
+			0, 0, 0, 0,
+		}),
+	},
+	{
+		name: "directed laplacian",
+		g: func() graph.Graph {
+			g := simple.NewDirectedGraph()
+			g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0)})
+			g.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(1)})
+			g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+			return g
+		}(),
+		fn: Laplacian,
+		want: mat.NewDense(3, 3, []float64{
+			1, -1, 0,
+			0, 0, 0,
+			-1, -1, 2,
+		}),
+	},
+}
+
+func TestMatrix(t *testing.T) {
+	for _, test := range matrixTests {
+		got := test.fn(test.g, test.opts)
+		if _, isSym := got.Matrix.(*mat.SymDense); isSym != test.wantSym {
+			t.Errorf("unexpected matrix type for %s: %T", test.name, got.Matrix)
+		}
+		if !mat.EqualApprox(got, test.want, 1e-14) {
+			t.Errorf("unexpected matrix for %s:\ngot:\n%v\nwant:\n%v",
+				test.name, mat.Formatted(got), mat.Formatted(test.want))
+		}
+		for i, n := range got.Nodes {
+			if n.ID() != int64(i) || got.Index[n.ID()] != i {
+				t.Errorf("unexpected node order for %s: %v", test.name, got.Nodes)
+				break
+			}
+		}
+	}
+}
+
+func TestIncidence(t *testing.T) {
+	g := multi.NewUndirectedGraph()
+	for _, uv := range [][2]int64{{2, 0}, {1, 2}, {1, 1}} {
+		g.SetLine(g.NewLine(multi.Node(uv[0]), multi.Node(uv[1])))
+	}
+
+	for _, test := range []struct {
+		oriented bool
+		want     *mat.Dense
+	}{
+		{
+			oriented: false,
+			want: mat.NewDense(3, 3, []float64{
+				1, 0, 0,
+				0, 2, 1,
+				1, 0, 1,
+			}),
+		},
+		{
+			oriented: true,
+			want: mat.NewDense(3, 3, []float64{
+				-1, 0, 0,
+				0, 0, -1,
+				1, 0, 1,
+			}),
+		},
+	} {
+		got := Incidence(g, test.oriented)
+		if !mat.Equal(got, test.want) {
+			t.Errorf("unexpected incidence matrix for oriented=%t:\ngot:\n%v\nwant:\n%v",
+				test.oriented, mat.Formatted(got), mat.Formatted(test.want))
+		}
+		wantEdges := [][2]int64{{0, 2}, {1, 1}, {1, 2}}
+		for j, e := range got.Edges {
+			if uv := [2]int64{e.From().ID(), e.To().ID()}; uv != wantEdges[j] {
+				t.Errorf("unexpected edge for column %d: got:%v want:%v", j, uv, wantEdges[j])
+			}
+		}
+
+		// The oriented incidence matrix gives the
+		// Laplacian of the unweighted graph without
+		// its self edge.
+		if test.oriented {
+			var l mat.Dense
+			l.Mul(got, got.T())
+			want := mat.NewDense(3, 3, []float64{
+				1, 0, -1,
+				0, 1, -1,
+				-1, -1, 2,
+			})
+			if !mat.Equal(&l, want) {
+				t.Errorf("unexpected B B^T:\ngot:\n%v\nwant:\n%v", mat.Formatted(&l), mat.Formatted(want))
+			}
+		}
+	}
+}
+
+func TestFromAdjacency(t *testing.T) {
+	a := Adjacency(path(), nil)
+
+	u := simple.NewWeightedUndirectedGraph(0, 0)
+	err := FromAdjacency(u, a, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Nodes().Len() != 4 || u.Edges().Len() != 2 {
+		t.Errorf("unexpected graph size: got %d nodes and %d edges", u.Nodes().Len(), u.Edges().Len())
+	}
+	if w, ok := u.Weight(2, 1); !ok || w != 4 {
+		t.Errorf("unexpected weight: got:%v want:4", w)
+	}
+	if got := Adjacency(u, nil); !mat.Equal(got, a) {
+		t.Errorf("adjacency not preserved:\ngot:\n%v\nwant:\n%v", mat.Formatted(got), mat.Formatted(a))
+	}
+
+	d := simple.NewDirectedGraph()
+	err = FromAdjacency(d, a, a.Nodes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Edges().Len() != 4 || !d.HasEdgeFromTo(2, 1) || !d.HasEdgeFromTo(1, 2) {
+		t.Errorf("symmetric matrix not added in both directions: got %d edges", d.Edges().Len())
+	}
+
+	err = FromAdjacency(simple.NewDirectedGraph(), mat.NewDense(2, 3, nil), nil)
+	if err == nil {
+		t.Error("expected error for non-square matrix")
+	}
+	err = FromAdjacency(simple.NewDirectedGraph(), a, a.Nodes[:2])
+	if err == nil {
+		t.Error("expected error for mismatched node count")
+	}
+}
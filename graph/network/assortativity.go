@@ -0,0 +1,159 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Degree specifies the degree of a node in a directed graph.
+type Degree int
+
+const (
+	// OutDegree is the number of edges
+	// leaving a node.
+	OutDegree Degree = iota
+	// InDegree is the number of edges
+	// entering a node.
+	InDegree
+)
+
+// DegreeAssortativity returns the degree assortativity coefficient of the
+// graph g as described in Newman "Assortative mixing in networks"
+// doi:10.1103/PhysRevLett.89.208701. The coefficient is the Pearson
+// correlation coefficient of the degrees of the nodes at either end of
+// the edges of g. For undirected graphs each edge is considered in both
+// directions. If g is directed, the out-degree of the source and the
+// in-degree of the target of each edge are used, corresponding to the
+// out-in assortativity of Foster et al. doi:10.1073/pnas.0912671107;
+// DirectedDegreeAssortativity may be used to calculate the other
+// directed assortativity variants.
+//
+// If the degrees have no variance, DegreeAssortativity returns NaN.
+func DegreeAssortativity(g graph.Graph) float64 {
+	if d, ok := g.(graph.Directed); ok {
+		if _, ok := g.(graph.Undirected); !ok {
+			return DirectedDegreeAssortativity(d, OutDegree, InDegree)
+		}
+	}
+	deg := func(n graph.Node) float64 { return float64(g.From(n.ID()).Len()) }
+	return NumericAssortativity(g, deg)
+}
+
+// DirectedDegreeAssortativity returns the directed degree assortativity
+// coefficient of the directed graph g for the given source and target
+// degree kinds as described in Foster et al. "Edge direction and the
+// structure of networks" doi:10.1073/pnas.0912671107. The coefficient is
+// the Pearson correlation coefficient of the src degree of the source node
+// and the dst degree of the target node of each edge in g.
+//
+// If the degrees have no variance, DirectedDegreeAssortativity returns NaN.
+func DirectedDegreeAssortativity(g graph.Directed, src, dst Degree) float64 {
+	degree := func(kind Degree) func(graph.Node) float64 {
+		switch kind {
+		case OutDegree:
+			return func(n graph.Node) float64 { return float64(g.From(n.ID()).Len()) }
+		case InDegree:
+			return func(n graph.Node) float64 { return float64(g.To(n.ID()).Len()) }
+		default:
+			panic("network: invalid degree kind")
+		}
+	}
+	return correlate(g, degree(src), degree(dst))
+}
+
+// NumericAssortativity returns the assortativity coefficient of the graph g
+// with respect to the scalar node attribute returned by value, as described
+// in Newman "Mixing patterns in networks" doi:10.1103/PhysRevE.67.026126.
+// The coefficient is the Pearson correlation coefficient of the values of
+// the nodes at either end of the edges of g. Each edge is visited in the
+// directions returned by the From method, so for undirected graphs each
+// edge is considered in both directions.
+//
+// If the values have no variance, NumericAssortativity returns NaN.
+func NumericAssortativity(g graph.Graph, value func(graph.Node) float64) float64 {
+	return correlate(g, value, value)
+}
+
+// correlate returns the Pearson correlation coefficient of x(u) and y(v)
+// over all edges (u, v) in g.
+func correlate(g graph.Graph, x, y func(graph.Node) float64) float64 {
+	var n, sx, sy, sxx, syy, sxy float64
+	nodes := g.Nodes()
+	for nodes.Next() {
+		u := nodes.Node()
+		xu := x(u)
+		to := g.From(u.ID())
+		for to.Next() {
+			yv := y(to.Node())
+			n++
+			sx += xu
+			sy += yv
+			sxx += xu * xu
+			syy += yv * yv
+			sxy += xu * yv
+		}
+	}
+	if n == 0 {
+		return math.NaN()
+	}
+	cov := sxy/n - (sx/n)*(sy/n)
+	vx := sxx/n - (sx/n)*(sx/n)
+	vy := syy/n - (sy/n)*(sy/n)
+	if vx <= 0 || vy <= 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(vx*vy)
+}
+
+// CategoricalAssortativity returns the assortativity coefficient of the
+// graph g with respect to the categorical node attribute returned by class,
+// as described in Newman "Mixing patterns in networks"
+// doi:10.1103/PhysRevE.67.026126,
+//  r = (\sum_i e_ii - \sum_i a_i b_i) / (1 - \sum_i a_i b_i)
+// where e_ij is the fraction of edges from a node in class i to a node in
+// class j, and a_i and b_i are the fractions of edge sources and targets
+// in class i. Each edge is visited in the directions returned by the From
+// method, so for undirected graphs each edge is considered in both
+// directions.
+//
+// If all edges join nodes of a single class, CategoricalAssortativity
+// returns NaN.
+func CategoricalAssortativity(g graph.Graph, class func(graph.Node) int) float64 {
+	var (
+		m     float64
+		same  float64
+		src   = make(map[int]float64)
+		dst   = make(map[int]float64)
+		nodes = g.Nodes()
+	)
+	for nodes.Next() {
+		u := nodes.Node()
+		cu := class(u)
+		to := g.From(u.ID())
+		for to.Next() {
+			cv := class(to.Node())
+			m++
+			if cu == cv {
+				same++
+			}
+			src[cu]++
+			dst[cv]++
+		}
+	}
+	if m == 0 {
+		return math.NaN()
+	}
+	var ab float64
+	for c, a := range src {
+		ab += (a / m) * (dst[c] / m)
+	}
+	if ab == 1 {
+		return math.NaN()
+	}
+	return (same/m - ab) / (1 - ab)
+}
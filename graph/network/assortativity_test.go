@@ -0,0 +1,150 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var degreeAssortativityTests = []struct {
+	name     string
+	g        []set
+	directed bool
+	want     float64
+}{
+	{
+		name: "star",
+		g: []set{
+			A: linksTo(B, C, D),
+		},
+		want: -1,
+	},
+	{
+		name: "path",
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(C),
+			C: linksTo(D),
+		},
+		want: -0.5,
+	},
+	{
+		name: "regular",
+		g: []set{
+			A: linksTo(B),
+			B: linksTo(C),
+			C: linksTo(A),
+		},
+		want: math.NaN(),
+	},
+	{
+		name: "directed",
+		g: []set{
+			A: linksTo(B, C),
+			B: linksTo(C),
+		},
+		directed: true,
+		want:     -0.5,
+	},
+}
+
+func TestDegreeAssortativity(t *testing.T) {
+	for _, test := range degreeAssortativityTests {
+		var g graph.Graph
+		if test.directed {
+			dg := simple.NewDirectedGraph()
+			for u, e := range test.g {
+				for v := range e {
+					dg.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+				}
+			}
+			g = dg
+		} else {
+			ug := simple.NewUndirectedGraph()
+			for u, e := range test.g {
+				for v := range e {
+					ug.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+				}
+			}
+			g = ug
+		}
+		got := DegreeAssortativity(g)
+		if !same(got, test.want) {
+			t.Errorf("unexpected degree assortativity for %s: got:%v want:%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDirectedDegreeAssortativity(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	for u, e := range []set{
+		A: linksTo(B, C),
+		B: linksTo(C),
+	} {
+		for v := range e {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	for _, test := range []struct {
+		src, dst Degree
+		want     float64
+	}{
+		{src: OutDegree, dst: InDegree, want: -0.5},
+		{src: InDegree, dst: OutDegree, want: -0.5},
+		{src: OutDegree, dst: OutDegree, want: 0.5},
+		{src: InDegree, dst: InDegree, want: 0.5},
+	} {
+		got := DirectedDegreeAssortativity(g, test.src, test.dst)
+		if !same(got, test.want) {
+			t.Errorf("unexpected directed degree assortativity for %v-%v: got:%v want:%v",
+				test.src, test.dst, got, test.want)
+		}
+	}
+}
+
+func TestAttributeAssortativity(t *testing.T) {
+	// Two triangles joined by a single edge.
+	g := simple.NewUndirectedGraph()
+	for u, e := range []set{
+		A: linksTo(B, C),
+		B: linksTo(C),
+		C: linksTo(D),
+		D: linksTo(E, F),
+		E: linksTo(F),
+	} {
+		for v := range e {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+
+	byTriangle := func(n graph.Node) int { return int(n.ID() / 3) }
+	// The fraction of edge ends in the same class is 6/7 and
+	// each class has half the edge ends, so r = (6/7-1/2)/(1/2).
+	if got, want := CategoricalAssortativity(g, byTriangle), 5.0/7; !same(got, want) {
+		t.Errorf("unexpected categorical assortativity by triangle: got:%v want:%v", got, want)
+	}
+	byParity := func(n graph.Node) int { return int(n.ID() % 2) }
+	if got := CategoricalAssortativity(g, byParity); got >= 0 {
+		t.Errorf("unexpected categorical assortativity by parity: got:%v want negative", got)
+	}
+	if got := CategoricalAssortativity(g, func(graph.Node) int { return 0 }); !math.IsNaN(got) {
+		t.Errorf("unexpected categorical assortativity for single class: got:%v want:NaN", got)
+	}
+
+	// A numeric attribute equal to the class gives the same
+	// result as the categorical assortativity for two classes.
+	value := func(n graph.Node) float64 { return float64(byTriangle(n)) }
+	if got, want := NumericAssortativity(g, value), 5.0/7; !same(got, want) {
+		t.Errorf("unexpected numeric assortativity by triangle: got:%v want:%v", got, want)
+	}
+}
+
+func same(a, b float64) bool {
+	return math.Abs(a-b) < 1e-12 || (math.IsNaN(a) && math.IsNaN(b))
+}
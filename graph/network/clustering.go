@@ -0,0 +1,318 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// ClusteringWeighting specifies how edge weights are used in the
+// calculation of clustering coefficients.
+type ClusteringWeighting int
+
+const (
+	// Unweighted ignores edge weights.
+	Unweighted ClusteringWeighting = iota
+
+	// Barrat uses the weighted clustering coefficient described
+	// in Barrat et al. "The architecture of complex weighted
+	// networks" doi:10.1073/pnas.0400087101,
+	//  C(i) = 1/(s_i (k_i-1)) \sum_{j,h} (w_ij+w_ih)/2 a_ij a_ih a_jh
+	// where s_i is the strength of node i. Barrat weighting is only
+	// defined for undirected clustering.
+	Barrat
+
+	// Onnela uses the geometric mean weighted clustering coefficient
+	// described in Onnela et al. "Intensity and coherence of motifs
+	// in weighted complex networks" doi:10.1103/PhysRevE.71.065103,
+	//  C(i) = 1/(k_i (k_i-1)) \sum_{j,h} (ŵ_ij ŵ_ih ŵ_jh)^(1/3)
+	// where ŵ are the edge weights normalized by the maximum weight
+	// in the graph.
+	Onnela
+)
+
+// ClusteringOptions specifies the calculation of clustering coefficients.
+type ClusteringOptions struct {
+	// Weighting specifies how edge weights are
+	// used. If the graph is not a graph.Weighted,
+	// all edges have unit weight.
+	Weighting ClusteringWeighting
+
+	// Directed specifies that Fagiolo's directed
+	// clustering coefficient is calculated as
+	// described in Fagiolo "Clustering in complex
+	// directed networks" doi:10.1103/PhysRevE.76.026107,
+	//  C(i) = (A+Aᵀ)³_ii / (2 [d_i (d_i-1) - 2 d↔_i])
+	// where d_i is the total degree of i and d↔_i
+	// is the number of reciprocated edges of i. If
+	// Weighting is Onnela, A is replaced by the
+	// element-wise cube root of the normalized
+	// weights.
+	//
+	// If Directed is false, the direction of edges
+	// in directed graphs is ignored.
+	Directed bool
+}
+
+// LocalClustering returns the local clustering coefficients of the nodes of
+// the graph g calculated according to opts. If opts is nil, the unweighted
+// coefficient described in Watts and Strogatz "Collective dynamics of
+// 'small-world' networks" doi:10.1038/30918 is calculated,
+//  C(i) = 2 T_i / (k_i (k_i-1))
+// where T_i is the number of triangles through node i and k_i is its
+// degree. Self edges are ignored and nodes with fewer than two neighbors
+// have a coefficient of zero.
+//
+// LocalClustering will panic if opts.Directed is true and g is not a
+// graph.Directed, or if Barrat weighting is requested for directed
+// clustering.
+//
+// The returned map is keyed on the graph node IDs.
+func LocalClustering(g graph.Graph, opts *ClusteringOptions) map[int64]float64 {
+	if opts == nil {
+		opts = &ClusteringOptions{}
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	cc := make(map[int64]float64, len(nodes))
+	if ug, ok := g.(graph.Undirected); ok && opts.Weighting == Unweighted && !opts.Directed {
+		_, t := Triangles(ug, nil)
+		for _, u := range nodes {
+			uid := u.ID()
+			k := float64(len(neighborIDs(g, uid)))
+			if k < 2 {
+				cc[uid] = 0
+				continue
+			}
+			cc[uid] = 2 * float64(t[uid]) / (k * (k - 1))
+		}
+		return cc
+	}
+	c := newClustering(g, opts)
+	for _, u := range nodes {
+		cc[u.ID()] = c.local(u.ID())
+	}
+	return cc
+}
+
+// AverageClustering returns the mean of the local clustering coefficients
+// of the nodes of g calculated according to opts as described for
+// LocalClustering. If g has no nodes, AverageClustering returns NaN.
+func AverageClustering(g graph.Graph, opts *ClusteringOptions) float64 {
+	cc := LocalClustering(g, opts)
+	if len(cc) == 0 {
+		return math.NaN()
+	}
+	var sum float64
+	for _, v := range cc {
+		sum += v
+	}
+	return sum / float64(len(cc))
+}
+
+// GlobalClustering returns the global clustering coefficient, or
+// transitivity, of the undirected graph g,
+//  C = 3 × triangles / connected triples.
+// Self edges are ignored. If g has no connected triples, GlobalClustering
+// returns NaN.
+func GlobalClustering(g graph.Undirected) float64 {
+	total, _ := Triangles(g, nil)
+	var triples float64
+	nodes := g.Nodes()
+	for nodes.Next() {
+		k := float64(len(neighborIDs(g, nodes.Node().ID())))
+		triples += k * (k - 1) / 2
+	}
+	if triples == 0 {
+		return math.NaN()
+	}
+	return 3 * float64(total) / triples
+}
+
+// neighborIDs returns the IDs of the nodes adjacent to uid in g,
+// excluding uid.
+func neighborIDs(g graph.Graph, uid int64) []int64 {
+	var ids []int64
+	to := g.From(uid)
+	for to.Next() {
+		if vid := to.Node().ID(); vid != uid {
+			ids = append(ids, vid)
+		}
+	}
+	return ids
+}
+
+// clustering holds the state for clustering coefficient calculation.
+type clustering struct {
+	g    graph.Graph
+	d    graph.Directed
+	opts *ClusteringOptions
+
+	weight func(xid, yid int64) float64
+	max    float64
+}
+
+func newClustering(g graph.Graph, opts *ClusteringOptions) clustering {
+	c := clustering{g: g, opts: opts, weight: func(xid, yid int64) float64 { return 1 }, max: 1}
+	if opts.Directed {
+		d, ok := g.(graph.Directed)
+		if !ok {
+			panic("network: directed clustering requires a directed graph")
+		}
+		if opts.Weighting == Barrat {
+			panic("network: Barrat weighting not defined for directed clustering")
+		}
+		c.d = d
+	}
+	if opts.Weighting == Unweighted {
+		return c
+	}
+	wg, ok := g.(graph.Weighted)
+	if !ok {
+		return c
+	}
+	c.weight = func(xid, yid int64) float64 {
+		w, _ := wg.Weight(xid, yid)
+		return w
+	}
+	if opts.Weighting == Onnela {
+		c.max = 0
+		nodes := g.Nodes()
+		for nodes.Next() {
+			uid := nodes.Node().ID()
+			to := g.From(uid)
+			for to.Next() {
+				vid := to.Node().ID()
+				if vid == uid {
+					continue
+				}
+				if w := c.weight(uid, vid); w > c.max {
+					c.max = w
+				}
+			}
+		}
+	}
+	return c
+}
+
+// edge returns the weight of the edge between x and y, zero if
+// there is no such edge. If the clustering is directed, only the
+// edge from x to y is considered.
+func (c clustering) edge(xid, yid int64) float64 {
+	if c.d != nil {
+		if !c.d.HasEdgeFromTo(xid, yid) {
+			return 0
+		}
+	} else if !c.g.HasEdgeBetween(xid, yid) {
+		return 0
+	}
+	w := c.weight(xid, yid)
+	if c.opts.Weighting == Onnela {
+		return math.Cbrt(w / c.max)
+	}
+	return w
+}
+
+func (c clustering) local(uid int64) float64 {
+	if c.d != nil {
+		return c.directed(uid)
+	}
+	nbrs := neighborIDs(c.g, uid)
+	k := float64(len(nbrs))
+	if k < 2 {
+		return 0
+	}
+	switch c.opts.Weighting {
+	case Barrat:
+		var s, sum float64
+		for i, v := range nbrs {
+			wv := c.weight(uid, v)
+			s += wv
+			for _, w := range nbrs[i+1:] {
+				if c.g.HasEdgeBetween(v, w) {
+					sum += wv + c.weight(uid, w)
+				}
+			}
+		}
+		// Each unordered pair contributes (w_ij+w_ih)/2 twice.
+		return sum / (s * (k - 1))
+	case Onnela:
+		var sum float64
+		for i, v := range nbrs {
+			for _, w := range nbrs[i+1:] {
+				sum += c.edge(uid, v) * c.edge(uid, w) * c.edge(v, w)
+			}
+		}
+		return 2 * sum / (k * (k - 1))
+	default:
+		var t float64
+		for i, v := range nbrs {
+			for _, w := range nbrs[i+1:] {
+				if c.g.HasEdgeBetween(v, w) {
+					t++
+				}
+			}
+		}
+		return 2 * t / (k * (k - 1))
+	}
+}
+
+func (c clustering) directed(uid int64) float64 {
+	seen := make(map[int64]bool)
+	var (
+		nbrs        []int64
+		in, out, bi float64
+	)
+	to := c.d.From(uid)
+	for to.Next() {
+		vid := to.Node().ID()
+		if vid == uid {
+			continue
+		}
+		out++
+		if !seen[vid] {
+			seen[vid] = true
+			nbrs = append(nbrs, vid)
+		}
+		if c.d.HasEdgeFromTo(vid, uid) {
+			bi++
+		}
+	}
+	from := c.d.To(uid)
+	for from.Next() {
+		vid := from.Node().ID()
+		if vid == uid {
+			continue
+		}
+		in++
+		if !seen[vid] {
+			seen[vid] = true
+			nbrs = append(nbrs, vid)
+		}
+	}
+	tot := in + out
+	denom := 2 * (tot*(tot-1) - 2*bi)
+	if denom == 0 {
+		return 0
+	}
+
+	// (A+Aᵀ)³_ii = \sum_{j,h} (a_ij+a_ji)(a_jh+a_hj)(a_hi+a_ih)
+	var sum float64
+	for _, j := range nbrs {
+		aij := c.edge(uid, j) + c.edge(j, uid)
+		for _, h := range nbrs {
+			if h == j {
+				continue
+			}
+			ajh := c.edge(j, h) + c.edge(h, j)
+			if ajh == 0 {
+				continue
+			}
+			sum += aij * ajh * (c.edge(h, uid) + c.edge(uid, h))
+		}
+	}
+	return sum / denom
+}
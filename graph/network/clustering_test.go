@@ -0,0 +1,194 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var clusteringTests = []struct {
+	name string
+	g    []set
+
+	wantLocal  map[int64]float64
+	wantGlobal float64
+}{
+	{
+		name: "complete",
+		g: []set{
+			A: linksTo(B, C, D),
+			B: linksTo(C, D),
+			C: linksTo(D),
+		},
+		wantLocal:  map[int64]float64{A: 1, B: 1, C: 1, D: 1},
+		wantGlobal: 1,
+	},
+	{
+		name: "star",
+		g: []set{
+			A: linksTo(B, C, D),
+		},
+		wantLocal:  map[int64]float64{A: 0, B: 0, C: 0, D: 0},
+		wantGlobal: 0,
+	},
+	{
+		name: "paw",
+		g: []set{
+			A: linksTo(B, C),
+			B: linksTo(C),
+			C: linksTo(D),
+		},
+		wantLocal:  map[int64]float64{A: 1, B: 1, C: 1.0 / 3, D: 0},
+		wantGlobal: 3.0 / 5,
+	},
+	{
+		name: "path",
+		g: []set{
+			A: linksTo(B),
+		},
+		wantLocal:  map[int64]float64{A: 0, B: 0},
+		wantGlobal: math.NaN(),
+	},
+}
+
+func TestClustering(t *testing.T) {
+	for _, test := range clusteringTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		for _, opts := range []*ClusteringOptions{
+			nil,
+			{Weighting: Barrat},
+			{Weighting: Onnela},
+		} {
+			got := LocalClustering(g, opts)
+			for id, want := range test.wantLocal {
+				if !same(got[id], want) {
+					t.Errorf("unexpected local clustering for %s node %d with %+v: got:%v want:%v",
+						test.name, id, opts, got[id], want)
+				}
+			}
+		}
+		if got := GlobalClustering(g); !same(got, test.wantGlobal) {
+			t.Errorf("unexpected global clustering for %s: got:%v want:%v", test.name, got, test.wantGlobal)
+		}
+	}
+}
+
+func TestWeightedClustering(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(A), T: simple.Node(B), W: 1},
+		{F: simple.Node(B), T: simple.Node(C), W: 2},
+		{F: simple.Node(C), T: simple.Node(A), W: 3},
+		{F: simple.Node(C), T: simple.Node(D), W: 4},
+	} {
+		g.SetWeightedEdge(e)
+	}
+
+	for _, test := range []struct {
+		weighting ClusteringWeighting
+		want      map[int64]float64
+	}{
+		{weighting: Unweighted, want: map[int64]float64{A: 1, B: 1, C: 1.0 / 3, D: 0}},
+		{weighting: Barrat, want: map[int64]float64{A: 1, B: 1, C: 5.0 / 18, D: 0}},
+		{
+			weighting: Onnela,
+			want: map[int64]float64{
+				A: math.Cbrt(0.75 * 0.5 * 0.25),
+				B: math.Cbrt(0.75 * 0.5 * 0.25),
+				C: math.Cbrt(0.75*0.5*0.25) / 3,
+				D: 0,
+			},
+		},
+	} {
+		got := LocalClustering(g, &ClusteringOptions{Weighting: test.weighting})
+		for id, want := range test.want {
+			if !same(got[id], want) {
+				t.Errorf("unexpected local clustering for node %d with weighting %d: got:%v want:%v",
+					id, test.weighting, got[id], want)
+			}
+		}
+	}
+
+	want := (2 + 1.0/3) / 4
+	if got := AverageClustering(g, nil); !same(got, want) {
+		t.Errorf("unexpected average clustering: got:%v want:%v", got, want)
+	}
+}
+
+func TestDirectedClustering(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		edges []set
+		want  map[int64]float64
+	}{
+		{
+			name: "cycle",
+			edges: []set{
+				A: linksTo(B),
+				B: linksTo(C),
+				C: linksTo(A),
+			},
+			want: map[int64]float64{A: 0.5, B: 0.5, C: 0.5},
+		},
+		{
+			name: "reciprocal",
+			edges: []set{
+				A: linksTo(B, C),
+				B: linksTo(A, C),
+				C: linksTo(A, B),
+			},
+			want: map[int64]float64{A: 1, B: 1, C: 1},
+		},
+		{
+			name: "open",
+			edges: []set{
+				A: linksTo(B),
+				B: linksTo(C),
+			},
+			want: map[int64]float64{A: 0, B: 0, C: 0},
+		},
+	} {
+		g := simple.NewDirectedGraph()
+		for u, e := range test.edges {
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		for _, weighting := range []ClusteringWeighting{Unweighted, Onnela} {
+			got := LocalClustering(g, &ClusteringOptions{Weighting: weighting, Directed: true})
+			for id, want := range test.want {
+				if !same(got[id], want) {
+					t.Errorf("unexpected directed clustering for %s node %d: got:%v want:%v",
+						test.name, id, got[id], want)
+				}
+			}
+		}
+	}
+
+	panics := func(fn func()) (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		fn()
+		return
+	}
+	var g graph.Graph = simple.NewUndirectedGraph()
+	if !panics(func() { LocalClustering(g, &ClusteringOptions{Directed: true}) }) {
+		t.Error("expected panic for directed clustering of undirected graph")
+	}
+	g = simple.NewDirectedGraph()
+	if !panics(func() { LocalClustering(g, &ClusteringOptions{Weighting: Barrat, Directed: true}) }) {
+		t.Error("expected panic for directed Barrat clustering")
+	}
+}
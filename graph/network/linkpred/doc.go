@@ -0,0 +1,11 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package linkpred provides neighborhood-based link prediction scores.
+//
+// The scores provided are described in Liben-Nowell and Kleinberg
+// "The link-prediction problem for social networks" doi:10.1002/asi.20591
+// and Zhou, Lü and Zhang "Predicting missing links via local information"
+// doi:10.1140/epjb/e2009-00335-8.
+package linkpred // import "gonum.org/v1/gonum/graph/network/linkpred"
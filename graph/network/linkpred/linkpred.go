@@ -0,0 +1,199 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linkpred
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Neighbors is the neighborhood of a node. Each neighbor's ID is
+// mapped to the number of neighbors it has.
+type Neighbors map[int64]int
+
+// Score is a link prediction score function. A Score returns the
+// predicted strength of a link between nodes x and y given their
+// neighborhoods, nx and ny.
+type Score func(nx, ny Neighbors) float64
+
+// CommonNeighbors is the common neighbors score,
+//  |Γ(x) ∩ Γ(y)|
+func CommonNeighbors(nx, ny Neighbors) float64 {
+	if len(ny) < len(nx) {
+		nx, ny = ny, nx
+	}
+	var n int
+	for z := range nx {
+		if _, ok := ny[z]; ok {
+			n++
+		}
+	}
+	return float64(n)
+}
+
+// Jaccard is the Jaccard coefficient score,
+//  |Γ(x) ∩ Γ(y)| / |Γ(x) ∪ Γ(y)|
+// If both neighborhoods are empty, Jaccard returns zero.
+func Jaccard(nx, ny Neighbors) float64 {
+	n := CommonNeighbors(nx, ny)
+	u := float64(len(nx)+len(ny)) - n
+	if u == 0 {
+		return 0
+	}
+	return n / u
+}
+
+// AdamicAdar is the Adamic-Adar score,
+//  \sum_{z ∈ Γ(x) ∩ Γ(y)} 1 / log(|Γ(z)|)
+// Common neighbors with a degree less than two are not considered.
+func AdamicAdar(nx, ny Neighbors) float64 {
+	if len(ny) < len(nx) {
+		nx, ny = ny, nx
+	}
+	var s float64
+	for z, d := range nx {
+		if _, ok := ny[z]; !ok || d < 2 {
+			continue
+		}
+		s += 1 / math.Log(float64(d))
+	}
+	return s
+}
+
+// ResourceAllocation is the resource allocation score,
+//  \sum_{z ∈ Γ(x) ∩ Γ(y)} 1 / |Γ(z)|
+func ResourceAllocation(nx, ny Neighbors) float64 {
+	if len(ny) < len(nx) {
+		nx, ny = ny, nx
+	}
+	var s float64
+	for z, d := range nx {
+		if _, ok := ny[z]; !ok {
+			continue
+		}
+		s += 1 / float64(d)
+	}
+	return s
+}
+
+// PreferentialAttachment is the preferential attachment score,
+//  |Γ(x)| × |Γ(y)|
+func PreferentialAttachment(nx, ny Neighbors) float64 {
+	return float64(len(nx) * len(ny))
+}
+
+// Prediction is a scored candidate link between two nodes.
+type Prediction struct {
+	X, Y  graph.Node
+	Score float64
+}
+
+// Predictor evaluates link prediction scores over a graph. The
+// neighborhood of each node is determined by the From method of the
+// graph, so for directed graphs only out-neighbors are considered.
+// Self loops are ignored.
+type Predictor struct {
+	g graph.Graph
+
+	nodes     []graph.Node
+	neighbors map[int64]Neighbors
+}
+
+// NewPredictor returns a new Predictor for the graph g. The neighborhoods
+// of the nodes in g are determined once during construction, so g must
+// not be changed while the Predictor is in use.
+func NewPredictor(g graph.Graph) *Predictor {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID() < nodes[j].ID() })
+
+	adj := make(map[int64][]int64, len(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		ids := make([]int64, 0, to.Len())
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			ids = append(ids, vid)
+		}
+		adj[uid] = ids
+	}
+	neighbors := make(map[int64]Neighbors, len(nodes))
+	for uid, ids := range adj {
+		n := make(Neighbors, len(ids))
+		for _, vid := range ids {
+			n[vid] = len(adj[vid])
+		}
+		neighbors[uid] = n
+	}
+	return &Predictor{g: g, nodes: nodes, neighbors: neighbors}
+}
+
+// Neighbors returns the neighborhood of the node with the given ID. The
+// returned value must not be altered.
+func (p *Predictor) Neighbors(id int64) Neighbors {
+	return p.neighbors[id]
+}
+
+// Score returns the link prediction score for the nodes with IDs xid and
+// yid using the score function fn. If either node is not in the graph,
+// Score returns NaN.
+func (p *Predictor) Score(xid, yid int64, fn Score) float64 {
+	nx, ok := p.neighbors[xid]
+	if !ok {
+		return math.NaN()
+	}
+	ny, ok := p.neighbors[yid]
+	if !ok {
+		return math.NaN()
+	}
+	return fn(nx, ny)
+}
+
+// Pairs returns the link prediction scores for the node ID pairs in pairs
+// using the score function fn. The returned predictions are in the order
+// of pairs. Pairs panics if a node in pairs does not exist in the graph.
+func (p *Predictor) Pairs(pairs [][2]int64, fn Score) []Prediction {
+	preds := make([]Prediction, len(pairs))
+	for i, pair := range pairs {
+		x := p.g.Node(pair[0])
+		y := p.g.Node(pair[1])
+		if x == nil || y == nil {
+			panic("linkpred: node not in graph")
+		}
+		preds[i] = Prediction{X: x, Y: y, Score: fn(p.neighbors[pair[0]], p.neighbors[pair[1]])}
+	}
+	return preds
+}
+
+// NonEdges returns the link prediction scores for all unordered pairs of
+// distinct nodes that are not joined by an edge using the score function
+// fn. Only predictions with a score greater than min are returned. The
+// returned predictions are sorted by descending score, with ties ordered
+// by ascending node IDs. The X node of each prediction has the lower ID.
+func (p *Predictor) NonEdges(fn Score, min float64) []Prediction {
+	var preds []Prediction
+	for i, x := range p.nodes {
+		xid := x.ID()
+		nx := p.neighbors[xid]
+		for _, y := range p.nodes[i+1:] {
+			yid := y.ID()
+			if p.g.HasEdgeBetween(xid, yid) {
+				continue
+			}
+			s := fn(nx, p.neighbors[yid])
+			if !(s > min) {
+				continue
+			}
+			preds = append(preds, Prediction{X: x, Y: y, Score: s})
+		}
+	}
+	sort.SliceStable(preds, func(i, j int) bool { return preds[i].Score > preds[j].Score })
+	return preds
+}
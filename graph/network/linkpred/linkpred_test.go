@@ -0,0 +1,106 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linkpred
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// linkpredGraph is the graph:
+//
+//  0 -- 1 -- 2
+//  |  / |    |
+//  | /  |    |
+//  3 -- 4    5
+var linkpredGraph = [][2]int64{
+	{0, 1}, {1, 2}, {0, 3}, {1, 3}, {1, 4}, {3, 4}, {2, 5},
+}
+
+var scoreTests = []struct {
+	name string
+	fn   Score
+	x, y int64
+	want float64
+}{
+	{name: "common", fn: CommonNeighbors, x: 0, y: 4, want: 2},
+	{name: "common", fn: CommonNeighbors, x: 2, y: 3, want: 1},
+	{name: "common", fn: CommonNeighbors, x: 0, y: 5, want: 0},
+	{name: "jaccard", fn: Jaccard, x: 0, y: 4, want: 1},
+	{name: "jaccard", fn: Jaccard, x: 2, y: 3, want: 1.0 / 4},
+	{name: "adamic-adar", fn: AdamicAdar, x: 0, y: 4, want: 1/math.Log(4) + 1/math.Log(3)},
+	{name: "adamic-adar", fn: AdamicAdar, x: 1, y: 5, want: 1 / math.Log(2)},
+	{name: "resource", fn: ResourceAllocation, x: 0, y: 4, want: 1.0/4 + 1.0/3},
+	{name: "resource", fn: ResourceAllocation, x: 2, y: 3, want: 1.0 / 4},
+	{name: "preferential", fn: PreferentialAttachment, x: 0, y: 4, want: 4},
+	{name: "preferential", fn: PreferentialAttachment, x: 1, y: 5, want: 4},
+}
+
+func TestScore(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	for _, e := range linkpredGraph {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	p := NewPredictor(g)
+	for _, test := range scoreTests {
+		got := p.Score(test.x, test.y, test.fn)
+		if !floats.EqualWithinAbsOrRel(got, test.want, 1e-12, 1e-12) {
+			t.Errorf("unexpected %s score for (%d,%d): got:%v want:%v", test.name, test.x, test.y, got, test.want)
+		}
+		// Scores are symmetric.
+		rev := p.Score(test.y, test.x, test.fn)
+		if got != rev {
+			t.Errorf("asymmetric %s score for (%d,%d): %v != %v", test.name, test.x, test.y, got, rev)
+		}
+	}
+	if got := p.Score(0, 10, CommonNeighbors); !math.IsNaN(got) {
+		t.Errorf("unexpected score for missing node: got:%v want:NaN", got)
+	}
+}
+
+func TestNonEdges(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	for _, e := range linkpredGraph {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	p := NewPredictor(g)
+
+	got := p.NonEdges(CommonNeighbors, 0)
+	want := []struct {
+		x, y  int64
+		score float64
+	}{
+		{0, 4, 2},
+		{0, 2, 1},
+		{1, 5, 1},
+		{2, 3, 1},
+		{2, 4, 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected number of predictions: got:%d want:%d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].X.ID() != w.x || got[i].Y.ID() != w.y || got[i].Score != w.score {
+			t.Errorf("unexpected prediction %d: got:{%d %d %v} want:%v",
+				i, got[i].X.ID(), got[i].Y.ID(), got[i].Score, w)
+		}
+	}
+
+	all := p.NonEdges(PreferentialAttachment, math.Inf(-1))
+	n := g.Nodes().Len()
+	if wantLen := n*(n-1)/2 - len(linkpredGraph); len(all) != wantLen {
+		t.Errorf("unexpected number of non-edges: got:%d want:%d", len(all), wantLen)
+	}
+
+	pairs := p.Pairs([][2]int64{{0, 4}, {5, 1}}, ResourceAllocation)
+	for _, pred := range pairs {
+		if want := p.Score(pred.X.ID(), pred.Y.ID(), ResourceAllocation); pred.Score != want {
+			t.Errorf("unexpected pair score for (%d,%d): got:%v want:%v", pred.X.ID(), pred.Y.ID(), pred.Score, want)
+		}
+	}
+}
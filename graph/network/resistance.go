@@ -0,0 +1,363 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/topo"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ResistanceDistance is an effective resistance oracle for a graph.
+type ResistanceDistance interface {
+	// Resistance returns the effective resistance
+	// between the nodes with IDs uid and vid.
+	Resistance(uid, vid int64) float64
+}
+
+// EdgeResistances returns the effective resistance between the end points
+// of each edge in the undirected graph g using the resistance oracle r.
+// The returned map is keyed on pairs of node IDs with the lower ID first.
+func EdgeResistances(g graph.Undirected, r ResistanceDistance) map[[2]int64]float64 {
+	res := make(map[[2]int64]float64)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uid := nodes.Node().ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid <= uid {
+				continue
+			}
+			res[[2]int64{uid, vid}] = r.Resistance(uid, vid)
+		}
+	}
+	return res
+}
+
+// resistanceNetwork is the Laplacian of an undirected graph
+// held as adjacency lists, partitioned into its connected
+// components.
+type resistanceNetwork struct {
+	indexOf   map[int64]int
+	component []int
+	members   [][]int
+
+	adj     [][]int
+	weights [][]float64
+	degree  []float64
+}
+
+func newResistanceNetwork(g graph.Undirected) resistanceNetwork {
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+
+	cc := topo.ConnectedComponents(g)
+	for _, c := range cc {
+		sort.Sort(ordered.ByID(c))
+	}
+	sort.Sort(ordered.BySliceIDs(cc))
+
+	var n int
+	for _, c := range cc {
+		n += len(c)
+	}
+	r := resistanceNetwork{
+		indexOf:   make(map[int64]int, n),
+		component: make([]int, n),
+		members:   make([][]int, len(cc)),
+		adj:       make([][]int, n),
+		weights:   make([][]float64, n),
+		degree:    make([]float64, n),
+	}
+	for k, c := range cc {
+		for _, u := range c {
+			i := len(r.indexOf)
+			r.indexOf[u.ID()] = i
+			r.component[i] = k
+			r.members[k] = append(r.members[k], i)
+		}
+	}
+	for _, c := range cc {
+		for _, u := range c {
+			uid := u.ID()
+			i := r.indexOf[uid]
+			to := graph.NodesOf(g.From(uid))
+			sort.Sort(ordered.ByID(to))
+			for _, v := range to {
+				vid := v.ID()
+				if vid == uid {
+					continue
+				}
+				w := weight(uid, vid)
+				if w <= 0 {
+					panic("network: non-positive edge conductance")
+				}
+				r.adj[i] = append(r.adj[i], r.indexOf[vid])
+				r.weights[i] = append(r.weights[i], w)
+				r.degree[i] += w
+			}
+		}
+	}
+	return r
+}
+
+// mulVecTo computes dst = L×x.
+func (r resistanceNetwork) mulVecTo(dst, x []float64) {
+	for i, adj := range r.adj {
+		v := r.degree[i] * x[i]
+		for k, j := range adj {
+			v -= r.weights[i][k] * x[j]
+		}
+		dst[i] = v
+	}
+}
+
+// DenseResistance is an effective resistance oracle based on the
+// Moore-Penrose pseudo-inverse of the graph Laplacian.
+type DenseResistance struct {
+	network resistanceNetwork
+	// pinv holds the pseudo-inverse of the
+	// Laplacian of each connected component.
+	pinv []*mat.SymDense
+	// offset holds the index of each node
+	// within its component.
+	offset []int
+}
+
+// NewDenseResistance returns an effective resistance oracle for the
+// undirected graph g. If g is a graph.Weighted, edge weights are treated as
+// conductances and must be positive, otherwise each edge has unit
+// conductance. The effective resistance between u and v is
+//  R(u,v) = L⁺(u,u) + L⁺(v,v) - 2 L⁺(u,v)
+// where L⁺ is the pseudo-inverse of the Laplacian of g. The pseudo-inverse
+// is computed densely for each connected component, so the space required
+// is quadratic in the size of the largest component.
+func NewDenseResistance(g graph.Undirected) DenseResistance {
+	r := DenseResistance{network: newResistanceNetwork(g)}
+	r.offset = make([]int, len(r.network.component))
+	r.pinv = make([]*mat.SymDense, len(r.network.members))
+	for k, members := range r.network.members {
+		n := len(members)
+		for o, i := range members {
+			r.offset[i] = o
+		}
+		// L⁺ = (L + J/n)⁻¹ - J/n for a connected
+		// graph, where J is the matrix of ones.
+		j := 1 / float64(n)
+		l := mat.NewSymDense(n, nil)
+		for o := 0; o < n; o++ {
+			for q := o; q < n; q++ {
+				l.SetSym(o, q, j)
+			}
+		}
+		for o, i := range members {
+			l.SetSym(o, o, r.network.degree[i]+j)
+			for p, v := range r.network.adj[i] {
+				if q := r.offset[v]; q > o {
+					l.SetSym(o, q, j-r.network.weights[i][p])
+				}
+			}
+		}
+		var chol mat.Cholesky
+		if !chol.Factorize(l) {
+			panic("network: Laplacian factorization failed")
+		}
+		var inv mat.SymDense
+		err := chol.InverseTo(&inv)
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				panic(err)
+			}
+		}
+		for o := 0; o < n; o++ {
+			for q := o; q < n; q++ {
+				inv.SetSym(o, q, inv.At(o, q)-j)
+			}
+		}
+		r.pinv[k] = &inv
+	}
+	return r
+}
+
+// Resistance returns the effective resistance between the nodes with IDs
+// uid and vid. If the nodes are in different connected components, the
+// resistance is infinite. Resistance will panic if either node is not in
+// the graph.
+func (r DenseResistance) Resistance(uid, vid int64) float64 {
+	i, ok := r.network.indexOf[uid]
+	if !ok {
+		panic("network: node not in graph")
+	}
+	j, ok := r.network.indexOf[vid]
+	if !ok {
+		panic("network: node not in graph")
+	}
+	if i == j {
+		return 0
+	}
+	k := r.network.component[i]
+	if k != r.network.component[j] {
+		return math.Inf(1)
+	}
+	p := r.pinv[k]
+	u, v := r.offset[i], r.offset[j]
+	return p.At(u, u) + p.At(v, v) - 2*p.At(u, v)
+}
+
+// SketchedResistance is an approximate effective resistance oracle based on
+// a Johnson-Lindenstrauss projection of the graph's edge-weighted incidence
+// matrix.
+type SketchedResistance struct {
+	network resistanceNetwork
+	// z holds the k×n sketch.
+	z *mat.Dense
+}
+
+// NewSketchedResistance returns an approximate effective resistance oracle
+// for the undirected graph g using a random projection into k dimensions
+// as described in Spielman and Srivastava "Graph Sparsification by
+// Effective Resistances" doi:10.1137/080734029. If g is a graph.Weighted,
+// edge weights are treated as conductances and must be positive, otherwise
+// each edge has unit conductance.
+//
+// The sketch is computed using k Laplacian solves by the conjugate gradient
+// method, each terminating when the relative residual norm is below tol,
+// so construction takes time roughly proportional to k times the number of
+// edges and the space required is k times the number of nodes. With
+// k = O(log(n)/ε²), all resistances are within a factor of 1±ε with high
+// probability.
+//
+// If src is not nil it is used as the random source, otherwise rand.Intn is
+// used. If g has no nodes, the returned oracle holds an empty sketch.
+func NewSketchedResistance(g graph.Undirected, k int, tol float64, src rand.Source) SketchedResistance {
+	var intn func(int) int
+	if src == nil {
+		intn = rand.Intn
+	} else {
+		intn = rand.New(src).Intn
+	}
+
+	net := newResistanceNetwork(g)
+	n := len(net.component)
+	if n == 0 {
+		return SketchedResistance{network: net}
+	}
+	z := mat.NewDense(k, n, nil)
+	y := make([]float64, n)
+	scale := 1 / math.Sqrt(float64(k))
+	for row := 0; row < k; row++ {
+		// y = (Q W^(1/2) B)ᵀ for the current row of Q, where
+		// B is the edge-node incidence matrix and W holds
+		// the edge conductances.
+		for i := range y {
+			y[i] = 0
+		}
+		for i, adj := range net.adj {
+			for p, j := range adj {
+				if j <= i {
+					continue
+				}
+				q := scale * math.Sqrt(net.weights[i][p])
+				if intn(2) == 0 {
+					q = -q
+				}
+				y[i] += q
+				y[j] -= q
+			}
+		}
+		net.solve(z.RawRowView(row), y, tol)
+	}
+	return SketchedResistance{network: net, z: z}
+}
+
+// Resistance returns the approximate effective resistance between the nodes
+// with IDs uid and vid. If the nodes are in different connected components,
+// the resistance is infinite. Resistance will panic if either node is not
+// in the graph.
+func (r SketchedResistance) Resistance(uid, vid int64) float64 {
+	i, ok := r.network.indexOf[uid]
+	if !ok {
+		panic("network: node not in graph")
+	}
+	j, ok := r.network.indexOf[vid]
+	if !ok {
+		panic("network: node not in graph")
+	}
+	if i == j {
+		return 0
+	}
+	if r.network.component[i] != r.network.component[j] {
+		return math.Inf(1)
+	}
+	rows, _ := r.z.Dims()
+	var sum float64
+	for k := 0; k < rows; k++ {
+		d := r.z.At(k, i) - r.z.At(k, j)
+		sum += d * d
+	}
+	return sum
+}
+
+// solve solves L x = b for x using the conjugate gradient method, where
+// b sums to zero over each connected component. The solution is placed in
+// dst and has zero mean over each connected component.
+func (r resistanceNetwork) solve(dst, b []float64, tol float64) {
+	n := len(b)
+	for i := range dst {
+		dst[i] = 0
+	}
+	res := make([]float64, n)
+	copy(res, b)
+	r.center(res)
+	p := make([]float64, n)
+	copy(p, res)
+	ap := make([]float64, n)
+
+	bnorm := floats.Norm(res, 2)
+	if bnorm == 0 {
+		return
+	}
+	rr := floats.Dot(res, res)
+	for iter := 0; iter < 10*n; iter++ {
+		r.mulVecTo(ap, p)
+		alpha := rr / floats.Dot(p, ap)
+		floats.AddScaled(dst, alpha, p)
+		floats.AddScaled(res, -alpha, ap)
+		next := floats.Dot(res, res)
+		if math.Sqrt(next) < tol*bnorm {
+			break
+		}
+		floats.AddScaledTo(p, res, next/rr, p)
+		rr = next
+	}
+	r.center(dst)
+}
+
+// center subtracts the mean of x over each connected component.
+func (r resistanceNetwork) center(x []float64) {
+	for _, members := range r.members {
+		var mean float64
+		for _, i := range members {
+			mean += x[i]
+		}
+		mean /= float64(len(members))
+		for _, i := range members {
+			x[i] -= mean
+		}
+	}
+}
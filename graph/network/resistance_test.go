@@ -0,0 +1,121 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestDenseResistance(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	// A path of three unit resistors in series from A to D.
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(A), T: simple.Node(B), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(B), T: simple.Node(C), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(C), T: simple.Node(D), W: 1})
+	// A 1 ohm resistor between E and F in parallel with
+	// a 1/2 ohm and a 1/4 ohm resistor in series via G.
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(E), T: simple.Node(F), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(E), T: simple.Node(G), W: 2})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(G), T: simple.Node(F), W: 4})
+	g.AddNode(simple.Node(H))
+
+	r := NewDenseResistance(g)
+	for _, test := range []struct {
+		u, v int64
+		want float64
+	}{
+		{u: A, v: A, want: 0},
+		{u: A, v: B, want: 1},
+		{u: A, v: D, want: 3},
+		{u: D, v: B, want: 2},
+		{u: E, v: F, want: 3.0 / 7},
+		{u: E, v: G, want: 5.0 / 14},
+		{u: A, v: E, want: math.Inf(1)},
+		{u: H, v: A, want: math.Inf(1)},
+	} {
+		got := r.Resistance(test.u, test.v)
+		if math.Abs(got-test.want) > 1e-9 && got != test.want {
+			t.Errorf("unexpected resistance between %d and %d: got:%v want:%v", test.u, test.v, got, test.want)
+		}
+	}
+}
+
+func TestCompleteGraphResistance(t *testing.T) {
+	// The effective resistance between any two nodes
+	// of the complete graph K_n is 2/n.
+	const n = 10
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+		}
+	}
+	dense := NewDenseResistance(g)
+	sketch := NewSketchedResistance(g, 4000, 1e-10, rand.NewSource(1))
+	for e, got := range EdgeResistances(g, dense) {
+		if math.Abs(got-2.0/n) > 1e-12 {
+			t.Errorf("unexpected dense resistance for %v: got:%v want:%v", e, got, 2.0/n)
+		}
+	}
+	for e, got := range EdgeResistances(g, sketch) {
+		if math.Abs(got-2.0/n) > 0.1*2/n {
+			t.Errorf("unexpected sketched resistance for %v: got:%v want:%v", e, got, 2.0/n)
+		}
+	}
+}
+
+func TestSketchedResistance(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	for u, e := range grid(6) {
+		for v := range e {
+			g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	g.AddNode(simple.Node(100))
+
+	dense := NewDenseResistance(g)
+	sketch := NewSketchedResistance(g, 2000, 1e-10, rand.NewSource(1))
+	nodes := g.Nodes()
+	for nodes.Next() {
+		u := nodes.Node().ID()
+		for v := int64(0); v < 36; v += 7 {
+			want := dense.Resistance(u, v)
+			got := sketch.Resistance(u, v)
+			if math.IsInf(want, 1) {
+				if !math.IsInf(got, 1) {
+					t.Errorf("unexpected sketched resistance between disconnected %d and %d: got:%v", u, v, got)
+				}
+				continue
+			}
+			if math.Abs(got-want) > 0.15*want {
+				t.Errorf("unexpected sketched resistance between %d and %d: got:%v want:%v", u, v, got, want)
+			}
+		}
+	}
+}
+
+func TestEmptyGraphResistance(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	for _, r := range []interface{ Resistance(uid, vid int64) float64 }{
+		NewDenseResistance(g),
+		NewSketchedResistance(g, 10, 1e-10, rand.NewSource(1)),
+	} {
+		panicked := func() (panicked bool) {
+			defer func() {
+				panicked = recover() != nil
+			}()
+			r.Resistance(0, 1)
+			return false
+		}()
+		if !panicked {
+			t.Errorf("expected panic for query of empty %T", r)
+		}
+	}
+}
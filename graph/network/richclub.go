@@ -0,0 +1,137 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// RichClub returns the rich-club coefficients of the undirected graph g as
+// described in Colizza et al. "Detecting rich-club ordering in complex
+// networks" doi:10.1038/nphys209,
+//  φ(k) = 2 E_{>k} / (N_{>k} (N_{>k} - 1))
+// where N_{>k} is the number of nodes with degree greater than k and E_{>k}
+// is the number of edges between them. The returned slice is indexed by k
+// and has length equal to the maximum degree of g. Coefficients for degrees
+// where fewer than two nodes have a greater degree are NaN.
+//
+// Self edges are not considered.
+func RichClub(g graph.Undirected) []float64 {
+	deg := make(map[int64]int)
+	var maxDeg int
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uid := nodes.Node().ID()
+		var d int
+		to := g.From(uid)
+		for to.Next() {
+			if to.Node().ID() != uid {
+				d++
+			}
+		}
+		deg[uid] = d
+		if d > maxDeg {
+			maxDeg = d
+		}
+	}
+
+	// nodesWith[d] and edgesWith[d] hold the number of
+	// nodes with degree d and the number of edges with
+	// a minimum end point degree of d.
+	nodesWith := make([]float64, maxDeg+1)
+	edgesWith := make([]float64, maxDeg+1)
+	for uid, d := range deg {
+		nodesWith[d]++
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid <= uid {
+				continue
+			}
+			m := d
+			if dv := deg[vid]; dv < m {
+				m = dv
+			}
+			edgesWith[m]++
+		}
+	}
+
+	phi := make([]float64, maxDeg)
+	var n, e float64
+	for k := maxDeg; k > 0; k-- {
+		n += nodesWith[k]
+		e += edgesWith[k]
+		// n and e now hold the number of nodes and edges
+		// with degree greater than k-1.
+		if n < 2 {
+			phi[k-1] = math.NaN()
+			continue
+		}
+		phi[k-1] = 2 * e / (n * (n - 1))
+	}
+	return phi
+}
+
+// NormalizedRichClub returns the rich-club coefficients of the undirected
+// graph g normalized by the mean rich-club coefficients of samples
+// degree-preserving randomizations of g,
+//  ρ(k) = φ(k) / φ_rand(k).
+// Each randomization is made by performing swaps double edge swaps on a
+// copy of g using gen.DegreePreservingRewire. A swaps value of zero
+// indicates ten swaps per edge of g. Coefficients where φ_rand(k) is zero
+// or undefined are NaN.
+//
+// If src is not nil it is used as the random source, otherwise the global
+// rand source is used. NormalizedRichClub returns an error if g cannot be
+// rewired, and will panic if g has a self edge.
+func NormalizedRichClub(g graph.Undirected, samples, swaps int, src rand.Source) ([]float64, error) {
+	phi := RichClub(g)
+	if swaps == 0 {
+		var degSum int
+		nodes := g.Nodes()
+		for nodes.Next() {
+			degSum += g.From(nodes.Node().ID()).Len()
+		}
+		swaps = 10 * degSum / 2
+	}
+
+	var rnd *rand.Rand
+	if src != nil {
+		rnd = rand.New(src)
+	}
+	random := make([]float64, len(phi))
+	for i := 0; i < samples; i++ {
+		r := simple.NewUndirectedGraph()
+		graph.Copy(r, g)
+		var s rand.Source
+		if rnd != nil {
+			s = rand.NewSource(rnd.Uint64())
+		}
+		err := gen.DegreePreservingRewire(r, swaps, s)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range RichClub(r) {
+			random[k] += v
+		}
+	}
+
+	rho := make([]float64, len(phi))
+	for k, v := range phi {
+		mean := random[k] / float64(samples)
+		if mean == 0 || math.IsNaN(mean) {
+			rho[k] = math.NaN()
+			continue
+		}
+		rho[k] = v / mean
+	}
+	return rho, nil
+}
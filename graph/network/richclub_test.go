@@ -0,0 +1,115 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var richClubTests = []struct {
+	name string
+	g    []set
+	want []float64
+}{
+	{
+		name: "star",
+		g: []set{
+			A: linksTo(B, C, D, E),
+		},
+		want: []float64{0.4, math.NaN(), math.NaN(), math.NaN()},
+	},
+	{
+		name: "complete",
+		g: []set{
+			A: linksTo(B, C, D),
+			B: linksTo(C, D),
+			C: linksTo(D),
+		},
+		want: []float64{1, 1, 1},
+	},
+	{
+		// A triangle with a pendant node on each vertex.
+		name: "net",
+		g: []set{
+			A: linksTo(B, C, D),
+			B: linksTo(C, E),
+			C: linksTo(F),
+		},
+		want: []float64{0.4, 1, 1},
+	},
+}
+
+func TestRichClub(t *testing.T) {
+	for _, test := range richClubTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		got := RichClub(g)
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected rich-club curve length for %s: got:%d want:%d", test.name, len(got), len(test.want))
+			continue
+		}
+		for k, v := range got {
+			if !same(v, test.want[k]) {
+				t.Errorf("unexpected rich-club coefficient for %s at k=%d: got:%v want:%v", test.name, k, v, test.want[k])
+			}
+		}
+	}
+}
+
+func TestNormalizedRichClub(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	err := gen.PreferentialAttachment(g, 100, 3, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error generating graph: %v", err)
+	}
+	phi := RichClub(g)
+	rho, err := NormalizedRichClub(g, 10, 0, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rho) != len(phi) {
+		t.Fatalf("unexpected normalized curve length: got:%d want:%d", len(rho), len(phi))
+	}
+	// All nodes have degree at least three, so the rich
+	// club for k < 3 is the whole graph, which has the
+	// same density as all its degree-preserving
+	// randomizations.
+	for k := 0; k < 3; k++ {
+		if math.Abs(rho[k]-1) > 1e-12 {
+			t.Errorf("unexpected normalized rich-club coefficient at k=%d: got:%v want:1", k, rho[k])
+		}
+	}
+
+	again, err := NormalizedRichClub(g, 10, 0, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for k := range rho {
+		if !same(rho[k], again[k]) {
+			t.Errorf("normalized rich-club coefficient not reproducible at k=%d: %v != %v", k, rho[k], again[k])
+		}
+	}
+
+	// A complete graph cannot be rewired.
+	k4 := simple.NewUndirectedGraph()
+	for u, e := range richClubTests[1].g {
+		for v := range e {
+			k4.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+		}
+	}
+	if _, err := NormalizedRichClub(k4, 1, 10, nil); err == nil {
+		t.Error("expected error for complete graph")
+	}
+}
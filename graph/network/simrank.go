@@ -0,0 +1,273 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// SimRank returns the SimRank similarity scores for all pairs of nodes in
+// the graph g using the decay factor c. SimRank is described in Jeh and
+// Widom "SimRank: a measure of structural-context similarity"
+// doi:10.1145/775047.775126,
+//  s(a, b) = c / (|I(a)| |I(b)|) \sum_{i ∈ I(a)} \sum_{j ∈ I(b)} s(i, j)
+// with s(a, a) = 1, where I(v) is the set of in-neighbors of v. If g is
+// not directed, the neighbors returned by From are used.
+//
+// SimRank terminates when the maximum absolute change in any score between
+// iterations is below tol or after iters iterations. Each iteration takes
+// O(n²d) time where d is the mean in-degree. For bipartite interaction
+// graphs, the returned scores hold the similarities within each partition,
+// and scores between nodes in different partitions are zero.
+//
+// The returned map is keyed on the graph node IDs. SimRank will panic if
+// c is not in (0, 1).
+func SimRank(g graph.Graph, c float64, iters int, tol float64) map[int64]map[int64]float64 {
+	if c <= 0 || 1 <= c {
+		panic("network: SimRank decay factor out of range")
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	in := inNeighborIndices(g, nodes)
+	n := len(nodes)
+
+	s := make([]float64, n*n)
+	next := make([]float64, n*n)
+	partial := make([]float64, n)
+	for i := 0; i < n; i++ {
+		s[i*n+i] = 1
+	}
+	for ; iters > 0; iters-- {
+		var delta float64
+		for a := 0; a < n; a++ {
+			next[a*n+a] = 1
+			if len(in[a]) == 0 {
+				for b := 0; b < n; b++ {
+					if b != a {
+						next[a*n+b] = 0
+					}
+				}
+				continue
+			}
+			// Partial sums as described in Lizorkin et al.
+			// doi:10.1007/s00778-009-0168-8.
+			for j := range partial {
+				var sum float64
+				for _, i := range in[a] {
+					sum += s[i*n+j]
+				}
+				partial[j] = sum
+			}
+			for b := 0; b < n; b++ {
+				if b == a {
+					continue
+				}
+				if len(in[b]) == 0 {
+					next[a*n+b] = 0
+					continue
+				}
+				var sum float64
+				for _, j := range in[b] {
+					sum += partial[j]
+				}
+				next[a*n+b] = c * sum / float64(len(in[a])*len(in[b]))
+			}
+		}
+		for i, v := range next {
+			delta = math.Max(delta, math.Abs(v-s[i]))
+		}
+		s, next = next, s
+		if delta < tol {
+			break
+		}
+	}
+
+	sim := make(map[int64]map[int64]float64, n)
+	for a, u := range nodes {
+		row := make(map[int64]float64, n)
+		for b, v := range nodes {
+			row[v.ID()] = s[a*n+b]
+		}
+		sim[u.ID()] = row
+	}
+	return sim
+}
+
+// SimRankPair returns a Monte Carlo estimate of the SimRank similarity score
+// between the nodes with IDs aid and bid in g using the decay factor c. The
+// estimate is the mean of c^τ over the given number of pairs of reverse
+// random walks of at most steps steps starting from a and b, where τ is the
+// first step at which the walks meet, as described in Fogaras and Rácz
+// "Scaling link-based similarity search" doi:10.1145/1060745.1060828.
+// Walks that do not meet contribute zero.
+//
+// If src is not nil it is used as the random source, otherwise rand.Intn is
+// used. SimRankPair will panic if either node is not in g or c is not in
+// (0, 1).
+func SimRankPair(g graph.Graph, aid, bid int64, c float64, walks, steps int, src rand.Source) float64 {
+	if c <= 0 || 1 <= c {
+		panic("network: SimRank decay factor out of range")
+	}
+	if g.Node(aid) == nil || g.Node(bid) == nil {
+		panic("network: node not in graph")
+	}
+	if aid == bid {
+		return 1
+	}
+	w := newReverseWalker(g, src)
+	var sum float64
+	for i := 0; i < walks; i++ {
+		a, b := aid, bid
+		f := 1.0
+		for t := 0; t < steps; t++ {
+			var ok bool
+			a, ok = w.step(a)
+			if !ok {
+				break
+			}
+			b, ok = w.step(b)
+			if !ok {
+				break
+			}
+			f *= c
+			if a == b {
+				sum += f
+				break
+			}
+		}
+	}
+	return sum / float64(walks)
+}
+
+// SimRankFrom returns Monte Carlo estimates of the SimRank similarity scores
+// between the node with ID id and all other nodes in g using the decay factor
+// c. The estimates are made as described for SimRankPair, with a single set
+// of reverse random walks from the source node shared between all estimates.
+// This makes SimRankFrom suitable for single-source queries in
+// recommendation workloads.
+//
+// The returned map is keyed on the graph node IDs and only holds non-zero
+// estimates. If src is not nil it is used as the random source, otherwise
+// rand.Intn is used. SimRankFrom will panic if the node is not in g or c
+// is not in (0, 1).
+func SimRankFrom(g graph.Graph, id int64, c float64, walks, steps int, src rand.Source) map[int64]float64 {
+	if c <= 0 || 1 <= c {
+		panic("network: SimRank decay factor out of range")
+	}
+	if g.Node(id) == nil {
+		panic("network: node not in graph")
+	}
+	w := newReverseWalker(g, src)
+
+	// Generate the source walks once.
+	paths := make([][]int64, walks)
+	for i := range paths {
+		path := make([]int64, 0, steps)
+		a := id
+		for t := 0; t < steps; t++ {
+			var ok bool
+			a, ok = w.step(a)
+			if !ok {
+				break
+			}
+			path = append(path, a)
+		}
+		paths[i] = path
+	}
+
+	sim := map[int64]float64{id: 1}
+	nodes := g.Nodes()
+	for nodes.Next() {
+		bid := nodes.Node().ID()
+		if bid == id {
+			continue
+		}
+		var sum float64
+		for _, path := range paths {
+			b := bid
+			f := 1.0
+			for _, a := range path {
+				var ok bool
+				b, ok = w.step(b)
+				if !ok {
+					break
+				}
+				f *= c
+				if a == b {
+					sum += f
+					break
+				}
+			}
+		}
+		if sum != 0 {
+			sim[bid] = sum / float64(walks)
+		}
+	}
+	return sim
+}
+
+// inNeighborIndices returns the indices into nodes of the in-neighbors
+// of each node in g, or the out-neighbors if g is not directed.
+func inNeighborIndices(g graph.Graph, nodes []graph.Node) [][]int {
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	to := g.From
+	if d, ok := g.(graph.Directed); ok {
+		to = d.To
+	}
+	in := make([][]int, len(nodes))
+	for i, n := range nodes {
+		it := to(n.ID())
+		for it.Next() {
+			in[i] = append(in[i], indexOf[it.Node().ID()])
+		}
+	}
+	return in
+}
+
+// reverseWalker performs uniform reverse random walks on a graph.
+type reverseWalker struct {
+	to    func(int64) graph.Nodes
+	cache map[int64][]int64
+	intn  func(int) int
+}
+
+func newReverseWalker(g graph.Graph, src rand.Source) *reverseWalker {
+	w := reverseWalker{to: g.From, cache: make(map[int64][]int64)}
+	if d, ok := g.(graph.Directed); ok {
+		w.to = d.To
+	}
+	if src == nil {
+		w.intn = rand.Intn
+	} else {
+		w.intn = rand.New(src).Intn
+	}
+	return &w
+}
+
+// step returns a uniformly chosen in-neighbor of the node with the
+// given ID and whether such a neighbor exists.
+func (w *reverseWalker) step(id int64) (int64, bool) {
+	in, ok := w.cache[id]
+	if !ok {
+		it := w.to(id)
+		for it.Next() {
+			in = append(in, it.Node().ID())
+		}
+		sort.Sort(ordered.Int64s(in))
+		w.cache[id] = in
+	}
+	if len(in) == 0 {
+		return 0, false
+	}
+	return in[w.intn(len(in))], true
+}
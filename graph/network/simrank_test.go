@@ -0,0 +1,111 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+const (
+	univ = iota
+	profA
+	profB
+	studentA
+	studentB
+)
+
+// simRankTests holds the example graph from Jeh and Widom doi:10.1145/775047.775126
+// figure 1.
+var simRankTests = []struct {
+	g []set
+	c float64
+
+	want map[[2]int64]float64
+}{
+	{
+		g: []set{
+			univ:     linksTo(profA, profB),
+			profA:    linksTo(studentA),
+			profB:    linksTo(studentB),
+			studentA: linksTo(univ),
+			studentB: linksTo(profB),
+		},
+		c: 0.8,
+
+		want: map[[2]int64]float64{
+			{univ, profB}:        0.132,
+			{profA, profB}:       0.414,
+			{profA, studentB}:    0.106,
+			{studentA, studentB}: 0.331,
+			{univ, studentB}:     0.034,
+			{profB, studentB}:    0.088,
+			{univ, profA}:        0,
+			{profA, studentA}:    0,
+			{studentA, univ}:     0,
+			{studentA, profB}:    0.042,
+		},
+	},
+}
+
+func TestSimRank(t *testing.T) {
+	for i, test := range simRankTests {
+		g := simple.NewDirectedGraph()
+		for u, e := range test.g {
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		got := SimRank(g, test.c, 100, 1e-10)
+		for pair, want := range test.want {
+			if math.Abs(got[pair[0]][pair[1]]-want) > 1e-3 {
+				t.Errorf("unexpected SimRank score for test %d pair %v: got:%.4f want:%.3f",
+					i, pair, got[pair[0]][pair[1]], want)
+			}
+			if got[pair[0]][pair[1]] != got[pair[1]][pair[0]] {
+				t.Errorf("asymmetric SimRank score for test %d pair %v", i, pair)
+			}
+		}
+		for u := range test.g {
+			if got[int64(u)][int64(u)] != 1 {
+				t.Errorf("unexpected self similarity for test %d node %d: got:%v want:1", i, u, got[int64(u)][int64(u)])
+			}
+		}
+
+		const walks = 20000
+		for pair, want := range test.want {
+			est := SimRankPair(g, pair[0], pair[1], test.c, walks, 50, rand.NewSource(1))
+			if math.Abs(est-want) > 0.02 {
+				t.Errorf("unexpected SimRankPair estimate for test %d pair %v: got:%.4f want:%.3f",
+					i, pair, est, want)
+			}
+		}
+		for _, src := range []int64{profA, studentB} {
+			est := SimRankFrom(g, src, test.c, walks, 50, rand.NewSource(1))
+			for pair, want := range test.want {
+				var id int64
+				switch src {
+				case pair[0]:
+					id = pair[1]
+				case pair[1]:
+					id = pair[0]
+				default:
+					continue
+				}
+				if math.Abs(est[id]-want) > 0.02 {
+					t.Errorf("unexpected SimRankFrom estimate for test %d pair %v: got:%.4f want:%.3f",
+						i, pair, est[id], want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestTriangles(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		g    []set
+
+		want        int
+		wantPerNode map[int64]int
+	}{
+		{
+			name: "complete",
+			g: []set{
+				A: linksTo(B, C, D),
+				B: linksTo(C, D),
+				C: linksTo(D),
+			},
+			want:        4,
+			wantPerNode: map[int64]int{A: 3, B: 3, C: 3, D: 3},
+		},
+		{
+			name: "paw",
+			g: []set{
+				A: linksTo(B, C),
+				B: linksTo(C),
+				C: linksTo(D),
+			},
+			want:        1,
+			wantPerNode: map[int64]int{A: 1, B: 1, C: 1, D: 0},
+		},
+		{
+			name: "star",
+			g: []set{
+				A: linksTo(B, C, D),
+			},
+			want:        0,
+			wantPerNode: map[int64]int{A: 0, B: 0, C: 0, D: 0},
+		},
+	} {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		for _, workers := range []int{0, 1, 3} {
+			got, perNode := Triangles(g, &TriangleOptions{Workers: workers})
+			if got != test.want {
+				t.Errorf("unexpected triangle count for %s with %d workers: got:%d want:%d",
+					test.name, workers, got, test.want)
+			}
+			for id, want := range test.wantPerNode {
+				if perNode[id] != want {
+					t.Errorf("unexpected triangle count for %s node %d with %d workers: got:%d want:%d",
+						test.name, id, workers, perNode[id], want)
+				}
+			}
+		}
+	}
+}
+
+func TestTrianglesRandom(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	err := gen.Gnp(g, 200, 0.1, rand.NewSource(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Count triangles naively.
+	want := make(map[int64]int)
+	var wantTotal int
+	nodes := g.Nodes()
+	for nodes.Next() {
+		u := nodes.Node().ID()
+		nbrs := neighborIDs(g, u)
+		for i, v := range nbrs {
+			for _, w := range nbrs[i+1:] {
+				if g.HasEdgeBetween(v, w) {
+					want[u]++
+					wantTotal++
+				}
+			}
+		}
+	}
+	wantTotal /= 3
+
+	got, perNode := Triangles(g, &TriangleOptions{Workers: 4})
+	if got != wantTotal {
+		t.Errorf("unexpected triangle count: got:%d want:%d", got, wantTotal)
+	}
+	for id, w := range want {
+		if perNode[id] != w {
+			t.Errorf("unexpected triangle count for node %d: got:%d want:%d", id, perNode[id], w)
+		}
+	}
+}
+
+func BenchmarkTriangles(b *testing.B) {
+	g := simple.NewUndirectedGraph()
+	err := gen.Gnp(g, 1000, 0.05, rand.NewSource(1))
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	for _, workers := range []int{1, 4} {
+		opts := &TriangleOptions{Workers: workers}
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Triangles(g, opts)
+			}
+		})
+	}
+}
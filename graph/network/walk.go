@@ -0,0 +1,320 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/mat"
+)
+
+// RandomWalk is a random walk iterator over the nodes of a graph.
+type RandomWalk struct {
+	g       graph.Graph
+	weight  func(uid, vid int64) float64
+	start   graph.Node
+	restart float64
+
+	rnd func() float64
+
+	cur   graph.Node
+	began bool
+
+	// cache holds the out-neighbors and the
+	// cumulative transition weights of nodes
+	// visited by the walk.
+	cache map[int64]transitions
+}
+
+type transitions struct {
+	to  []graph.Node
+	cum []float64
+}
+
+// total returns the total out-going weight.
+func (t transitions) total() float64 {
+	if len(t.cum) == 0 {
+		return 0
+	}
+	return t.cum[len(t.cum)-1]
+}
+
+// NewRandomWalk returns a random walk on g starting from the node with
+// ID start. At each step the walk returns to the start node with probability
+// restart, and otherwise moves to a node reachable from the current node as
+// defined by the From method of g. If g is a graph.Weighted, the next node
+// is chosen with probability proportional to the weight of the edge to it,
+// otherwise it is chosen uniformly. If the current node has no out-neighbors
+// with positive weight, the walk returns to the start node.
+//
+// A restart probability of zero gives a simple random walk. A restart
+// probability in (0, 1) gives the random walk with restart that underlies
+// personalized PageRank, where the long-run visit frequencies of the walk
+// are the personalized PageRank scores for the start node.
+//
+// If src is not nil it is used as the random source, otherwise rand.Float64
+// is used. NewRandomWalk will panic if the start node is not in g or restart
+// is not in [0, 1].
+func NewRandomWalk(g graph.Graph, start int64, restart float64, src rand.Source) *RandomWalk {
+	s := g.Node(start)
+	if s == nil {
+		panic("network: start node not in graph")
+	}
+	if restart < 0 || 1 < restart {
+		panic("network: restart probability out of range")
+	}
+	w := &RandomWalk{
+		g:       g,
+		weight:  func(uid, vid int64) float64 { return 1 },
+		start:   s,
+		restart: restart,
+		cache:   make(map[int64]transitions),
+	}
+	if wg, ok := g.(graph.Weighted); ok {
+		w.weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+	if src == nil {
+		w.rnd = rand.Float64
+	} else {
+		w.rnd = rand.New(src).Float64
+	}
+	return w
+}
+
+// Next advances the walk by one step. The first call to Next positions the
+// walk at the start node. Next returns false if the start node has no
+// out-neighbors with positive weight, since the walk cannot leave it.
+func (w *RandomWalk) Next() bool {
+	if !w.began {
+		w.began = true
+		w.cur = w.start
+		return true
+	}
+	if t := w.transitions(w.start); t.total() == 0 {
+		return false
+	}
+	if w.restart != 0 && w.rnd() < w.restart {
+		w.cur = w.start
+		return true
+	}
+	next := w.step(w.cur)
+	if next == nil {
+		next = w.start
+	}
+	w.cur = next
+	return true
+}
+
+// Node returns the current node of the walk.
+func (w *RandomWalk) Node() graph.Node {
+	return w.cur
+}
+
+// Reset returns the walk to its initial state.
+func (w *RandomWalk) Reset() {
+	w.began = false
+	w.cur = nil
+}
+
+// step returns a randomly chosen out-neighbor of u, or nil
+// if u has no out-neighbors with positive weight.
+func (w *RandomWalk) step(u graph.Node) graph.Node {
+	t := w.transitions(u)
+	total := t.total()
+	if total == 0 {
+		return nil
+	}
+	r := w.rnd() * total
+	i := sort.Search(len(t.cum), func(i int) bool { return t.cum[i] > r })
+	if i == len(t.cum) {
+		i--
+	}
+	return t.to[i]
+}
+
+// transitions returns the out-neighbors of u and their cumulative
+// transition weights.
+func (w *RandomWalk) transitions(u graph.Node) transitions {
+	uid := u.ID()
+	t, ok := w.cache[uid]
+	if !ok {
+		to := graph.NodesOf(w.g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		var sum float64
+		for _, v := range to {
+			wt := w.weight(uid, v.ID())
+			if wt < 0 {
+				panic("network: negative edge weight")
+			}
+			sum += wt
+			t.cum = append(t.cum, sum)
+		}
+		t.to = to
+		w.cache[uid] = t
+	}
+	return t
+}
+
+// HittingTimes returns the expected number of steps for a simple random
+// walk on g to first reach the node with ID target from each node in g.
+// If g is a graph.Weighted, transition probabilities are proportional to the
+// weights of out-going edges as described for NewRandomWalk. The hitting
+// times are found by solving the linear system
+//  h(u) = 1 + \sum_v P(u,v) h(v), h(target) = 0.
+// Nodes that cannot reach the target have an infinite hitting time.
+//
+// The returned map is keyed on the graph node IDs. HittingTimes will panic
+// if the target is not in g or g has a negative edge weight.
+func HittingTimes(g graph.Graph, target int64) map[int64]float64 {
+	if g.Node(target) == nil {
+		panic("network: target node not in graph")
+	}
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	out := make(map[int64][]graph.Node, len(nodes))
+	wts := make(map[int64][]float64, len(nodes))
+	into := make(map[int64][]int64, len(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		for _, v := range graph.NodesOf(g.From(uid)) {
+			vid := v.ID()
+			wt := weight(uid, vid)
+			if wt < 0 {
+				panic("network: negative edge weight")
+			}
+			if wt == 0 {
+				continue
+			}
+			out[uid] = append(out[uid], v)
+			wts[uid] = append(wts[uid], wt)
+			into[vid] = append(into[vid], uid)
+		}
+	}
+
+	// Find the nodes that can reach the target by a reverse
+	// breadth first search.
+	reach := map[int64]bool{target: true}
+	queue := []int64{target}
+	for len(queue) != 0 {
+		vid := queue[0]
+		queue = queue[1:]
+		for _, uid := range into[vid] {
+			if !reach[uid] {
+				reach[uid] = true
+				queue = append(queue, uid)
+			}
+		}
+	}
+
+	// Nodes that can reach a node that cannot reach the
+	// target without passing through the target have a
+	// positive probability of never reaching the target,
+	// and so have infinite hitting times.
+	inf := make(map[int64]bool)
+	for _, u := range nodes {
+		uid := u.ID()
+		if !reach[uid] {
+			inf[uid] = true
+			queue = append(queue, uid)
+		}
+	}
+	for len(queue) != 0 {
+		vid := queue[0]
+		queue = queue[1:]
+		for _, uid := range into[vid] {
+			if uid != target && !inf[uid] {
+				inf[uid] = true
+				queue = append(queue, uid)
+			}
+		}
+	}
+
+	h := make(map[int64]float64, len(nodes))
+	indexOf := make(map[int64]int)
+	var solve []graph.Node
+	for _, u := range nodes {
+		uid := u.ID()
+		switch {
+		case uid == target:
+			h[uid] = 0
+		case inf[uid]:
+			h[uid] = math.Inf(1)
+		default:
+			indexOf[uid] = len(solve)
+			solve = append(solve, u)
+		}
+	}
+	if len(solve) == 0 {
+		return h
+	}
+
+	// Construct and solve (I-P')h = 1 where P' is the
+	// transition matrix restricted to the nodes with
+	// finite hitting times.
+	n := len(solve)
+	a := mat.NewDense(n, n, nil)
+	b := mat.NewVecDense(n, nil)
+	for i, u := range solve {
+		uid := u.ID()
+		var sum float64
+		for _, wt := range wts[uid] {
+			sum += wt
+		}
+		a.Set(i, i, 1)
+		b.SetVec(i, 1)
+		for k, v := range out[uid] {
+			vid := v.ID()
+			if vid == target {
+				continue
+			}
+			j := indexOf[vid]
+			a.Set(i, j, a.At(i, j)-wts[uid][k]/sum)
+		}
+	}
+	var x mat.VecDense
+	err := x.SolveVec(a, b)
+	if err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			panic(err)
+		}
+	}
+	for i, u := range solve {
+		h[u.ID()] = x.AtVec(i)
+	}
+	return h
+}
+
+// CommuteTime returns the expected number of steps for a simple random walk
+// on g to travel from the node with ID uid to the node with ID vid and back
+// again, the sum of the hitting times from u to v and from v to u.
+// Transition probabilities are determined as described for HittingTimes.
+//
+// For connected undirected graphs, the commute time is proportional to the
+// effective resistance between u and v.
+func CommuteTime(g graph.Graph, uid, vid int64) float64 {
+	if uid == vid {
+		if g.Node(uid) == nil {
+			panic("network: node not in graph")
+		}
+		return 0
+	}
+	return HittingTimes(g, vid)[uid] + HittingTimes(g, uid)[vid]
+}
@@ -0,0 +1,115 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package network
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestRandomWalk(t *testing.T) {
+	g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(A), T: simple.Node(B), W: 3})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(A), T: simple.Node(C), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(B), T: simple.Node(A), W: 1})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(C), T: simple.Node(A), W: 1})
+	g.AddNode(simple.Node(D))
+
+	const steps = 100000
+	w := NewRandomWalk(g, A, 0, rand.NewSource(1))
+	counts := make(map[int64]int)
+	prev := int64(-1)
+	for i := 0; i < steps && w.Next(); i++ {
+		id := w.Node().ID()
+		if prev >= 0 && !g.HasEdgeFromTo(prev, id) {
+			t.Fatalf("invalid step in walk: %d -> %d", prev, id)
+		}
+		counts[id]++
+		prev = id
+	}
+	if got := float64(counts[B]) / float64(counts[C]); math.Abs(got-3) > 0.1 {
+		t.Errorf("unexpected weighted visit ratio: got:%v want:3", got)
+	}
+	if counts[D] != 0 {
+		t.Errorf("unexpected visits to unreachable node: %d", counts[D])
+	}
+
+	w.Reset()
+	if !w.Next() || w.Node().ID() != A {
+		t.Error("walk did not reset to start node")
+	}
+
+	// A walk with certain restart stays at the start.
+	w = NewRandomWalk(g, B, 1, rand.NewSource(1))
+	for i := 0; i < 10 && w.Next(); i++ {
+		if w.Node().ID() != B {
+			t.Fatalf("unexpected node for walk with restart: got:%d want:%d", w.Node().ID(), B)
+		}
+	}
+
+	// A walk from an isolated node cannot proceed.
+	w = NewRandomWalk(g, D, 0.5, rand.NewSource(1))
+	if !w.Next() {
+		t.Fatal("walk did not start")
+	}
+	if w.Next() {
+		t.Error("walk from isolated node proceeded")
+	}
+}
+
+func TestHittingTimes(t *testing.T) {
+	// The hitting time from node i to node n on
+	// the path graph P_{n+1} is n²-i².
+	const n = 6
+	path := simple.NewUndirectedGraph()
+	for i := 1; i <= n; i++ {
+		path.SetEdge(simple.Edge{F: simple.Node(i - 1), T: simple.Node(i)})
+	}
+	h := HittingTimes(path, n)
+	for i := 0; i <= n; i++ {
+		want := float64(n*n - i*i)
+		if math.Abs(h[int64(i)]-want) > 1e-10 {
+			t.Errorf("unexpected hitting time from %d on path: got:%v want:%v", i, h[int64(i)], want)
+		}
+	}
+	// The commute time is 2mR where m is the number of
+	// edges and R is the effective resistance.
+	if got, want := CommuteTime(path, 0, n), float64(2*n*n); math.Abs(got-want) > 1e-10 {
+		t.Errorf("unexpected commute time on path: got:%v want:%v", got, want)
+	}
+
+	// The hitting time between distinct nodes of
+	// the complete graph K_n is n-1.
+	complete := simple.NewUndirectedGraph()
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			complete.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+		}
+	}
+	h = HittingTimes(complete, 0)
+	for i := 1; i < n; i++ {
+		if math.Abs(h[int64(i)]-(n-1)) > 1e-10 {
+			t.Errorf("unexpected hitting time from %d on complete graph: got:%v want:%v", i, h[int64(i)], n-1)
+		}
+	}
+
+	// Nodes that may be trapped have infinite hitting times.
+	g := simple.NewDirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(A), T: simple.Node(B)})
+	g.SetEdge(simple.Edge{F: simple.Node(A), T: simple.Node(C)})
+	g.SetEdge(simple.Edge{F: simple.Node(B), T: simple.Node(D)})
+	g.SetEdge(simple.Edge{F: simple.Node(E), T: simple.Node(B)})
+	h = HittingTimes(g, D)
+	want := map[int64]float64{A: math.Inf(1), B: 1, C: math.Inf(1), D: 0, E: 2}
+	for id, w := range want {
+		if h[id] != w {
+			t.Errorf("unexpected hitting time from %d on directed graph: got:%v want:%v", id, h[id], w)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package partition provides balanced graph partitioning functions.
+//
+// Partitions are found by the multilevel scheme: the graph is coarsened
+// by repeatedly contracting a matching of heavy edges, the coarsest graph
+// is partitioned directly, and the partition is projected back through
+// the levels, being refined at each level by Fiduccia–Mattheyses moves.
+package partition // import "gonum.org/v1/gonum/graph/partition"
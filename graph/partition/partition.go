@@ -0,0 +1,707 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package partition
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Partition is a k-way partition of the nodes of a graph.
+type Partition struct {
+	// Parts holds the nodes of each
+	// part ordered by ID.
+	Parts [][]graph.Node
+
+	// Cut is the total weight of the
+	// edges between nodes in different
+	// parts.
+	Cut float64
+
+	part map[int64]int
+}
+
+// Part returns the index into Parts of the part holding the node with
+// the given ID. If the node is not in the partition, Part returns -1.
+func (p Partition) Part(id int64) int {
+	i, ok := p.part[id]
+	if !ok {
+		return -1
+	}
+	return i
+}
+
+// KWay returns a balanced k-way partition of the nodes of the undirected
+// graph g that heuristically minimizes the weight of edges between parts.
+// If g is a graph.Weighted, edge weights are obtained from its Weight
+// method and must be non-negative, otherwise each edge has unit weight.
+// Self edges do not contribute to the cut.
+//
+// The imbalance parameter specifies the permitted fractional deviation
+// of part sizes from an even split; each part holds at most
+// ⌈(1+imbalance)⌈n/k⌉⌉ of the n nodes. The constraint is met whenever the
+// heuristic can find a move that restores it. If src is nil, rand.Perm
+// from golang.org/x/exp/rand is used to order matching and seed initial
+// partitions. KWay will panic if k is less than one or imbalance is
+// negative.
+//
+// The partition is found using multilevel coarsening by heavy edge
+// matching, greedy region growing on the coarsest graph and k-way
+// Fiduccia–Mattheyses refinement during uncoarsening as described by
+// Karypis and Kumar.
+//
+// See https://doi.org/10.1137/S1064827595287997 for details.
+func KWay(g graph.Undirected, k int, imbalance float64, src rand.Source) Partition {
+	if k < 1 {
+		panic("partition: k must be positive")
+	}
+	if imbalance < 0 {
+		panic("partition: negative imbalance")
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	n := len(nodes)
+	p := Partition{Parts: make([][]graph.Node, k), part: make(map[int64]int, n)}
+	if n == 0 {
+		return p
+	}
+
+	var perm func(int) []int
+	if src == nil {
+		perm = rand.Perm
+	} else {
+		perm = rand.New(src).Perm
+	}
+
+	fine := newLevel(g, nodes)
+	maxWeight := math.Ceil((1 + imbalance) * math.Ceil(float64(n)/float64(k)))
+
+	// Coarsen until the graph is small enough to partition
+	// directly or matching no longer reduces it usefully.
+	coarsest := 15 * k
+	if coarsest < 20 {
+		coarsest = 20
+	}
+	levels := []*level{fine}
+	for {
+		l := levels[len(levels)-1]
+		if len(l.weight) <= coarsest {
+			break
+		}
+		c := l.coarsen(perm, 1.5*float64(n)/float64(coarsest))
+		if float64(len(c.weight)) > 0.95*float64(len(l.weight)) {
+			break
+		}
+		levels = append(levels, c)
+	}
+
+	// Partition the coarsest graph by recursive bisection,
+	// distributing the permitted imbalance over the levels
+	// of recursion, and refine the k-way partition.
+	depth := math.Ceil(math.Log2(float64(k)))
+	tolerance := 1.0
+	if depth > 0 {
+		tolerance = math.Pow(1+imbalance, 1/depth)
+	}
+	c := levels[len(levels)-1]
+	part := c.refine(c.initial(k, tolerance, perm), k, maxWeight)
+
+	// Project the partition back through the levels,
+	// refining at each level.
+	for i := len(levels) - 2; i >= 0; i-- {
+		l := levels[i]
+		fpart := make([]int, len(l.weight))
+		for u, cu := range l.coarse {
+			fpart[u] = part[cu]
+		}
+		part = l.refine(fpart, k, maxWeight)
+	}
+
+	for i, u := range nodes {
+		p.Parts[part[i]] = append(p.Parts[part[i]], u)
+		p.part[u.ID()] = part[i]
+	}
+	p.Cut = newRefiner(fine, part, make([]float64, k)).cut()
+	return p
+}
+
+// refine returns the k-way partition part of l after balancing and
+// Fiduccia–Mattheyses refinement with the given maximum part weight.
+// Coarse vertices may be too heavy to meet the maximum exactly, so
+// parts may exceed it by less than the weight of the heaviest vertex.
+func (l *level) refine(part []int, k int, maxWeight float64) []int {
+	heaviest := 1.0
+	for _, w := range l.weight {
+		heaviest = math.Max(heaviest, w)
+	}
+	max := make([]float64, k)
+	for i := range max {
+		max[i] = maxWeight + heaviest - 1
+	}
+	r := newRefiner(l, part, max)
+	r.balance()
+	r.refine()
+	return r.part
+}
+
+// level is a graph at one level of coarsening.
+type level struct {
+	// weight holds the vertex weights.
+	weight []float64
+
+	// adj and edge hold the neighbours of
+	// each vertex and the weight of the
+	// edge to each neighbour.
+	adj  [][]int
+	edge [][]float64
+
+	// coarse maps the vertices of the
+	// level to the vertices of the next
+	// coarser level.
+	coarse []int
+}
+
+// newLevel returns the finest level representing g.
+func newLevel(g graph.Undirected, nodes []graph.Node) *level {
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			w, _ := wg.Weight(uid, vid)
+			return w
+		}
+	}
+	l := &level{
+		weight: make([]float64, len(nodes)),
+		adj:    make([][]int, len(nodes)),
+		edge:   make([][]float64, len(nodes)),
+	}
+	for i, u := range nodes {
+		l.weight[i] = 1
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if vid == uid {
+				continue
+			}
+			w := weight(uid, vid)
+			if w < 0 {
+				panic("partition: negative edge weight")
+			}
+			l.adj[i] = append(l.adj[i], indexOf[vid])
+			l.edge[i] = append(l.edge[i], w)
+		}
+	}
+	return l
+}
+
+// coarsen returns the next coarser level obtained by contracting a
+// heavy edge matching of l. Vertices are visited in an order given by
+// perm and are matched to the unmatched neighbour joined by the
+// heaviest edge such that the combined vertex weight is at most max.
+func (l *level) coarsen(perm func(int) []int, max float64) *level {
+	n := len(l.weight)
+	match := make([]int, n)
+	for i := range match {
+		match[i] = -1
+	}
+	l.coarse = make([]int, n)
+	var cn int
+	for _, u := range perm(n) {
+		if match[u] >= 0 {
+			continue
+		}
+		best, bestWeight := u, -1.0
+		for i, v := range l.adj[u] {
+			if match[v] >= 0 || l.weight[u]+l.weight[v] > max {
+				continue
+			}
+			if w := l.edge[u][i]; w > bestWeight {
+				best, bestWeight = v, w
+			}
+		}
+		match[u] = best
+		match[best] = u
+		l.coarse[u] = cn
+		l.coarse[best] = cn
+		cn++
+	}
+
+	c := &level{
+		weight: make([]float64, cn),
+		adj:    make([][]int, cn),
+		edge:   make([][]float64, cn),
+	}
+	slot := make([]int, cn)
+	for i := range slot {
+		slot[i] = -1
+	}
+	for u := 0; u < n; u++ {
+		if match[u] < u {
+			// Each coarse vertex is constructed
+			// from its lowest index fine vertex.
+			continue
+		}
+		cu := l.coarse[u]
+		members := []int{u}
+		if match[u] != u {
+			members = append(members, match[u])
+		}
+		for _, x := range members {
+			c.weight[cu] += l.weight[x]
+			for i, v := range l.adj[x] {
+				cv := l.coarse[v]
+				if cv == cu {
+					continue
+				}
+				if s := slot[cv]; s >= 0 {
+					c.edge[cu][s] += l.edge[x][i]
+					continue
+				}
+				slot[cv] = len(c.adj[cu])
+				c.adj[cu] = append(c.adj[cu], cv)
+				c.edge[cu] = append(c.edge[cu], l.edge[x][i])
+			}
+		}
+		for _, cv := range c.adj[cu] {
+			slot[cv] = -1
+		}
+	}
+	return c
+}
+
+// initial returns a k-way partition of l found by recursive bisection.
+// Each bisection is the best of several greedy growths refined by
+// Fiduccia–Mattheyses moves, with the weight of each side limited to
+// tolerance times its share of the vertex weight of l.
+func (l *level) initial(k int, tolerance float64, perm func(int) []int) []int {
+	part := make([]int, len(l.weight))
+	if k == 1 || len(part) == 0 {
+		return part
+	}
+	var total, heaviest float64
+	for _, w := range l.weight {
+		total += w
+		heaviest = math.Max(heaviest, w)
+	}
+	k0 := k / 2
+	share := float64(k0) / float64(k)
+
+	// Coarse vertices may be too heavy to meet the
+	// tolerance, so allow each side to exceed it by
+	// up to the weight of the heaviest vertex and
+	// leave balancing to the finer levels.
+	max := []float64{
+		tolerance*share*total + heaviest,
+		tolerance*(1-share)*total + heaviest,
+	}
+
+	const trials = 4
+	var (
+		best    []int
+		bestCut = math.Inf(1)
+		bestOff = math.Inf(1)
+	)
+	for i := 0; i < trials; i++ {
+		r := newRefiner(l, l.grow(share*total, perm), max)
+		r.balance()
+		r.refine()
+		cut := r.cut()
+		off := r.overweight()
+		if off < bestOff || (off == bestOff && cut < bestCut) {
+			best = r.part
+			bestCut = cut
+			bestOff = off
+		}
+	}
+
+	for side, sk := range [2]int{k0, k - k0} {
+		sub, vertices := l.induced(best, side)
+		offset := side * k0
+		for i, p := range sub.initial(sk, tolerance, perm) {
+			part[vertices[i]] = offset + p
+		}
+	}
+	return part
+}
+
+// grow returns a bisection of l constructed by greedily growing side
+// zero from a random seed vertex until it holds approximately the given
+// target weight. At each step the vertex on the boundary of the grown
+// region whose addition most reduces the cut is added.
+func (l *level) grow(target float64, perm func(int) []int) []int {
+	n := len(l.weight)
+	part := make([]int, n)
+	for i := range part {
+		part[i] = 1
+	}
+	degree := make([]float64, n)
+	for u, w := range l.edge {
+		for _, e := range w {
+			degree[u] += e
+		}
+	}
+
+	// conn holds the weight of edges from each
+	// vertex to the grown region, and frontier
+	// holds the vertices on its boundary.
+	conn := make([]float64, n)
+	onFrontier := make([]bool, n)
+	var frontier []int
+	add := func(u int) {
+		part[u] = 0
+		for i, v := range l.adj[u] {
+			if part[v] == 0 {
+				continue
+			}
+			conn[v] += l.edge[u][i]
+			if !onFrontier[v] {
+				onFrontier[v] = true
+				frontier = append(frontier, v)
+			}
+		}
+	}
+
+	seeds := perm(n)
+	var (
+		next   int
+		weight float64
+	)
+	for weight < target {
+		if len(frontier) == 0 {
+			for next < n && part[seeds[next]] == 0 {
+				next++
+			}
+			if next == n {
+				break
+			}
+			u := seeds[next]
+			if weight+l.weight[u]/2 > target {
+				break
+			}
+			weight += l.weight[u]
+			add(u)
+			continue
+		}
+		best, bestGain := 0, math.Inf(-1)
+		for i, v := range frontier {
+			if gain := 2*conn[v] - degree[v]; gain > bestGain {
+				best, bestGain = i, gain
+			}
+		}
+		u := frontier[best]
+		if weight+l.weight[u]/2 > target {
+			break
+		}
+		frontier[best] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		weight += l.weight[u]
+		add(u)
+	}
+	return part
+}
+
+// induced returns the subgraph of l induced by the vertices in the given
+// side of part, and the vertices of l corresponding to the vertices of the
+// subgraph.
+func (l *level) induced(part []int, side int) (*level, []int) {
+	var vertices []int
+	index := make([]int, len(part))
+	for u, p := range part {
+		if p == side {
+			index[u] = len(vertices)
+			vertices = append(vertices, u)
+		}
+	}
+	sub := &level{
+		weight: make([]float64, len(vertices)),
+		adj:    make([][]int, len(vertices)),
+		edge:   make([][]float64, len(vertices)),
+	}
+	for i, u := range vertices {
+		sub.weight[i] = l.weight[u]
+		for j, v := range l.adj[u] {
+			if part[v] != side {
+				continue
+			}
+			sub.adj[i] = append(sub.adj[i], index[v])
+			sub.edge[i] = append(sub.edge[i], l.edge[u][j])
+		}
+	}
+	return sub, vertices
+}
+
+// refiner performs k-way Fiduccia–Mattheyses refinement of a partition
+// of a level.
+type refiner struct {
+	l    *level
+	part []int
+
+	// weight and max hold the weight
+	// of each part and its maximum.
+	weight []float64
+	max    []float64
+
+	// conn and touched are workspace for
+	// calculating the connectivity of a
+	// vertex to each part.
+	conn    []float64
+	touched []int
+}
+
+func newRefiner(l *level, part []int, max []float64) *refiner {
+	r := &refiner{
+		l:      l,
+		part:   part,
+		weight: make([]float64, len(max)),
+		max:    max,
+		conn:   make([]float64, len(max)),
+	}
+	for u, p := range part {
+		r.weight[p] += l.weight[u]
+	}
+	return r
+}
+
+// cut returns the weight of edges between parts.
+func (r *refiner) cut() float64 {
+	var cut float64
+	for u, adj := range r.l.adj {
+		for i, v := range adj {
+			if u < v && r.part[u] != r.part[v] {
+				cut += r.l.edge[u][i]
+			}
+		}
+	}
+	return cut
+}
+
+// overweight returns the total weight by which parts exceed the
+// maximum part weight.
+func (r *refiner) overweight() float64 {
+	var off float64
+	for p, w := range r.weight {
+		if w > r.max[p] {
+			off += w - r.max[p]
+		}
+	}
+	return off
+}
+
+// connect fills conn with the weight of edges from u to each part and
+// records the parts that were touched. The caller must call clear once
+// conn is no longer needed.
+func (r *refiner) connect(u int) {
+	for i, v := range r.l.adj[u] {
+		p := r.part[v]
+		if r.conn[p] == 0 {
+			r.touched = append(r.touched, p)
+		}
+		r.conn[p] += r.l.edge[u][i]
+	}
+}
+
+func (r *refiner) clear() {
+	for _, p := range r.touched {
+		r.conn[p] = 0
+	}
+	r.touched = r.touched[:0]
+}
+
+// bestMove returns the part adjacent to u that u can be moved to without
+// exceeding the maximum part weight with the greatest reduction in cut,
+// and the reduction.
+func (r *refiner) bestMove(u int) (to int, gain float64, ok bool) {
+	r.connect(u)
+	defer r.clear()
+	from := r.part[u]
+	to = -1
+	best := math.Inf(-1)
+	for _, p := range r.touched {
+		if p == from || r.weight[p]+r.l.weight[u] > r.max[p] {
+			continue
+		}
+		if c := r.conn[p]; c > best || (c == best && p < to) {
+			to, best = p, c
+		}
+	}
+	if to < 0 {
+		return -1, 0, false
+	}
+	return to, best - r.conn[from], true
+}
+
+func (r *refiner) move(u, to int) {
+	w := r.l.weight[u]
+	r.weight[r.part[u]] -= w
+	r.weight[to] += w
+	r.part[u] = to
+}
+
+// balance moves vertices out of the part that most exceeds its maximum
+// weight, choosing the vertex and destination part whose move least
+// increases the cut, until all parts are within their maximum or no
+// move reduces the excess.
+func (r *refiner) balance() {
+	for range r.part {
+		heavy := 0
+		for p, w := range r.weight {
+			if w-r.max[p] > r.weight[heavy]-r.max[heavy] {
+				heavy = p
+			}
+		}
+		over := r.weight[heavy] - r.max[heavy]
+		if over <= 0 {
+			return
+		}
+		best, to, bestGain := -1, -1, math.Inf(-1)
+		for u, p := range r.part {
+			if p != heavy {
+				continue
+			}
+			r.connect(u)
+			for q, w := range r.weight {
+				// Reject moves that leave the
+				// destination further over its
+				// maximum than the heavy part was.
+				if q == heavy || w+r.l.weight[u]-r.max[q] >= over {
+					continue
+				}
+				if gain := r.conn[q] - r.conn[heavy]; gain > bestGain {
+					best, to, bestGain = u, q, gain
+				}
+			}
+			r.clear()
+		}
+		if best < 0 {
+			return
+		}
+		r.move(best, to)
+	}
+}
+
+// refine performs passes of Fiduccia–Mattheyses refinement until a
+// pass fails to reduce the cut.
+func (r *refiner) refine() {
+	const maxPasses = 10
+	for i := 0; i < maxPasses; i++ {
+		if !r.pass() {
+			return
+		}
+	}
+}
+
+// pass performs a single Fiduccia–Mattheyses pass, moving each vertex
+// at most once and retaining the sequence of moves that gives the least
+// cut. It returns whether the cut was reduced.
+func (r *refiner) pass() bool {
+	n := len(r.part)
+	locked := make([]bool, n)
+	version := make([]int, n)
+	var h gainQueue
+	for u := range r.part {
+		if _, gain, ok := r.bestMove(u); ok {
+			h = append(h, gainItem{vertex: u, gain: gain})
+		}
+	}
+	heap.Init(&h)
+
+	// Stop searching after a run of moves
+	// that fail to improve on the best cut.
+	limit := n / 4
+	if limit < 50 {
+		limit = 50
+	}
+
+	type move struct{ vertex, from int }
+	var (
+		moves     []move
+		gain      float64
+		bestGain  float64
+		bestMoves int
+		since     int
+	)
+	for h.Len() != 0 && since < limit {
+		it := heap.Pop(&h).(gainItem)
+		u := it.vertex
+		if locked[u] || it.version != version[u] {
+			continue
+		}
+		to, g, ok := r.bestMove(u)
+		if !ok {
+			continue
+		}
+		if g != it.gain {
+			version[u]++
+			heap.Push(&h, gainItem{vertex: u, gain: g, version: version[u]})
+			continue
+		}
+		moves = append(moves, move{vertex: u, from: r.part[u]})
+		r.move(u, to)
+		locked[u] = true
+		gain += g
+		if gain > bestGain {
+			bestGain = gain
+			bestMoves = len(moves)
+			since = 0
+		} else {
+			since++
+		}
+		for _, v := range r.l.adj[u] {
+			if locked[v] {
+				continue
+			}
+			version[v]++
+			if _, g, ok := r.bestMove(v); ok {
+				heap.Push(&h, gainItem{vertex: v, gain: g, version: version[v]})
+			}
+		}
+	}
+	for i := len(moves) - 1; i >= bestMoves; i-- {
+		r.move(moves[i].vertex, moves[i].from)
+	}
+	return bestMoves != 0
+}
+
+// gainItem is a vertex and the reduction in cut obtained by its best
+// move at insertion into a gainQueue.
+type gainItem struct {
+	vertex  int
+	gain    float64
+	version int
+}
+
+// gainQueue is a max-priority queue of vertices ordered by gain and
+// then by lowest vertex index.
+type gainQueue []gainItem
+
+func (q gainQueue) Len() int { return len(q) }
+func (q gainQueue) Less(i, j int) bool {
+	if q[i].gain != q[j].gain {
+		return q[i].gain > q[j].gain
+	}
+	return q[i].vertex < q[j].vertex
+}
+func (q gainQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *gainQueue) Push(x interface{}) { *q = append(*q, x.(gainItem)) }
+func (q *gainQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	x := old[n]
+	*q = old[:n]
+	return x
+}
@@ -0,0 +1,171 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package partition
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// grid returns an undirected r×c grid graph.
+func grid(r, c int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			u := simple.Node(i*c + j)
+			if g.Node(int64(u)) == nil {
+				g.AddNode(u)
+			}
+			if j+1 < c {
+				g.SetEdge(simple.Edge{F: u, T: simple.Node(i*c + j + 1)})
+			}
+			if i+1 < r {
+				g.SetEdge(simple.Edge{F: u, T: simple.Node((i+1)*c + j)})
+			}
+		}
+	}
+	return g
+}
+
+// cliques returns n cliques of size s, with clique i joined to
+// clique i+1 by a single edge of weight w.
+func cliques(n, s int, w float64) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, 0)
+	for c := 0; c < n; c++ {
+		for i := 0; i < s; i++ {
+			for j := i + 1; j < s; j++ {
+				g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(c*s + i), T: simple.Node(c*s + j), W: 1})
+			}
+		}
+		if c+1 < n {
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(c*s + s - 1), T: simple.Node((c + 1) * s), W: w})
+		}
+	}
+	return g
+}
+
+var kWayTests = []struct {
+	name      string
+	g         graph.Undirected
+	k         int
+	imbalance float64
+	maxCut    float64
+}{
+	{name: "two cliques", g: cliques(2, 10, 1), k: 2, imbalance: 0, maxCut: 1},
+	{name: "four cliques", g: cliques(4, 8, 0.5), k: 4, imbalance: 0, maxCut: 1.5},
+	{name: "four cliques weighted", g: cliques(4, 8, 0.5), k: 2, imbalance: 0, maxCut: 0.5},
+	{name: "grid bisection", g: grid(16, 16), k: 2, imbalance: 0.03, maxCut: 24},
+	{name: "grid 4-way", g: grid(16, 16), k: 4, imbalance: 0.03, maxCut: 48},
+	{name: "single part", g: grid(4, 4), k: 1, imbalance: 0, maxCut: 0},
+}
+
+func TestKWay(t *testing.T) {
+	for _, test := range kWayTests {
+		p := KWay(test.g, test.k, test.imbalance, rand.NewSource(1))
+		checkPartition(t, test.name, test.g, p, test.k, test.imbalance)
+		if p.Cut > test.maxCut {
+			t.Errorf("unexpectedly large cut for %s: got:%v want<=%v", test.name, p.Cut, test.maxCut)
+		}
+	}
+}
+
+func TestKWayGnp(t *testing.T) {
+	for seed := uint64(1); seed <= 3; seed++ {
+		g := simple.NewUndirectedGraph()
+		gen.Gnp(g, 500, 0.01, rand.NewSource(seed))
+		for _, k := range []int{2, 3, 8} {
+			p := KWay(g, k, 0.05, rand.NewSource(seed))
+			checkPartition(t, "gnp", g, p, k, 0.05)
+		}
+	}
+}
+
+func TestKWayEmpty(t *testing.T) {
+	p := KWay(simple.NewUndirectedGraph(), 3, 0, nil)
+	if len(p.Parts) != 3 || p.Cut != 0 || p.Part(0) != -1 {
+		t.Errorf("unexpected partition of empty graph: %+v", p)
+	}
+}
+
+func TestKWayPanics(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		k         int
+		imbalance float64
+	}{
+		{name: "zero k", k: 0},
+		{name: "negative imbalance", k: 2, imbalance: -0.1},
+	} {
+		panicked := func() (panicked bool) {
+			defer func() { panicked = recover() != nil }()
+			KWay(grid(2, 2), test.k, test.imbalance, nil)
+			return false
+		}()
+		if !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+// checkPartition checks that p is a balanced partition of g into k
+// parts and that its cut and part assignments are consistent.
+func checkPartition(t *testing.T, name string, g graph.Undirected, p Partition, k int, imbalance float64) {
+	t.Helper()
+	if len(p.Parts) != k {
+		t.Errorf("unexpected number of parts for %s: got:%d want:%d", name, len(p.Parts), k)
+		return
+	}
+	n := g.Nodes().Len()
+	max := math.Ceil((1 + imbalance) * math.Ceil(float64(n)/float64(k)))
+	seen := make(map[int64]bool)
+	for i, part := range p.Parts {
+		if float64(len(part)) > max {
+			t.Errorf("part %d too large for %s: got:%d max:%v", i, name, len(part), max)
+		}
+		for j, u := range part {
+			if j > 0 && part[j-1].ID() >= u.ID() {
+				t.Errorf("part %d not ordered by ID for %s", i, name)
+			}
+			if seen[u.ID()] {
+				t.Errorf("node %d in more than one part for %s", u.ID(), name)
+			}
+			seen[u.ID()] = true
+			if got := p.Part(u.ID()); got != i {
+				t.Errorf("unexpected part for node %d for %s: got:%d want:%d", u.ID(), name, got, i)
+			}
+		}
+	}
+	if len(seen) != n {
+		t.Errorf("partition does not cover graph for %s: got:%d nodes want:%d", name, len(seen), n)
+	}
+
+	var cut float64
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uid := nodes.Node().ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid < uid || p.Part(uid) == p.Part(vid) {
+				continue
+			}
+			if wg, ok := g.(graph.Weighted); ok {
+				w, _ := wg.Weight(uid, vid)
+				cut += w
+			} else {
+				cut++
+			}
+		}
+	}
+	if math.Abs(cut-p.Cut) > 1e-12 {
+		t.Errorf("unexpected cut for %s: got:%v want:%v", name, p.Cut, cut)
+	}
+}
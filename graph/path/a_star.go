@@ -22,17 +22,14 @@ import (
 //
 // If h is nil, AStar will use the g.HeuristicCost method if g implements HeuristicCoster,
 // falling back to NullHeuristic otherwise. If the graph does not implement Weighted,
-// UniformCost is used. AStar will panic if g has an A*-reachable negative edge weight.
+// UniformCost is used. Weighted multigraphs that do not implement Weighted use
+// the least weight line between nodes. AStar will panic if g has an A*-reachable
+// negative edge weight.
 func AStar(s, t graph.Node, g graph.Graph, h Heuristic) (path Shortest, expanded int) {
 	if g.Node(s.ID()) == nil || g.Node(t.ID()) == nil {
 		return Shortest{from: s}, 0
 	}
-	var weight Weighting
-	if wg, ok := g.(Weighted); ok {
-		weight = wg.Weight
-	} else {
-		weight = UniformCost(g)
-	}
+	weight := weightOf(g)
 	if h == nil {
 		if g, ok := g.(HeuristicCoster); ok {
 			h = g.HeuristicCost
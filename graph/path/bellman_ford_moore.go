@@ -11,19 +11,15 @@ import (
 
 // BellmanFordFrom returns a shortest-path tree for a shortest path from u to all nodes in
 // the graph g, or false indicating that a negative cycle exists in the graph. If the graph
-// does not implement Weighted, UniformCost is used.
+// does not implement Weighted, UniformCost is used. Weighted multigraphs that do not
+// implement Weighted use the least weight line between nodes.
 //
 // The time complexity of BellmanFordFrom is O(|V|.|E|).
 func BellmanFordFrom(u graph.Node, g graph.Graph) (path Shortest, ok bool) {
 	if g.Node(u.ID()) == nil {
 		return Shortest{from: u}, true
 	}
-	var weight Weighting
-	if wg, ok := g.(Weighted); ok {
-		weight = wg.Weight
-	} else {
-		weight = UniformCost(g)
-	}
+	weight := weightOf(g)
 
 	nodes := graph.NodesOf(g.Nodes())
 
@@ -13,6 +13,8 @@ import (
 
 // DijkstraFrom returns a shortest-path tree for a shortest path from u to all nodes in
 // the graph g. If the graph does not implement Weighted, UniformCost is used.
+// Weighted multigraphs that do not implement Weighted use the least weight line
+// between nodes.
 // DijkstraFrom will panic if g has a u-reachable negative edge weight.
 //
 // If g is a graph.Graph, all nodes of the graph will be stored in the shortest-path
@@ -33,12 +35,7 @@ func DijkstraFrom(u graph.Node, g traverse.Graph) Shortest {
 		path = newShortestFrom(u, []graph.Node{u})
 	}
 
-	var weight Weighting
-	if wg, ok := g.(Weighted); ok {
-		weight = wg.Weight
-	} else {
-		weight = UniformCost(g)
-	}
+	weight := weightOf(g)
 
 	// Dijkstra's algorithm here is implemented essentially as
 	// described in Function B.2 in figure 6 of UTCS Technical
@@ -98,12 +95,7 @@ func DijkstraAllPaths(g graph.Graph) (paths AllShortest) {
 // of the nodes slice and the indexOf map. It returns nothing, but stores the
 // result of the work in the paths parameter which is a reference type.
 func dijkstraAllPaths(g graph.Graph, paths AllShortest) {
-	var weight Weighting
-	if wg, ok := g.(graph.Weighted); ok {
-		weight = wg.Weight
-	} else {
-		weight = UniformCost(g)
-	}
+	weight := weightOf(g)
 
 	var Q priorityQueue
 	for i, u := range paths.nodes {
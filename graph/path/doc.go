@@ -3,4 +3,10 @@
 // license that can be found in the LICENSE file.
 
 // Package path provides graph path finding functions.
+//
+// Graphs that implement graph.WeightedMultigraph but not Weighted are searched
+// using the least weight line between each pair of nodes. The Multigraph and
+// DirectedMultigraph types allow multigraphs to be searched using a different
+// LineSelector, and allow multigraphs that do not implement graph.Graph to be
+// searched and traversed.
 package path // import "gonum.org/v1/gonum/graph/path"
@@ -24,11 +24,7 @@ func JohnsonAllPaths(g graph.Graph) (paths AllShortest, ok bool) {
 		from:   g.From,
 		edgeTo: g.Edge,
 	}
-	if wg, ok := g.(Weighted); ok {
-		jg.weight = wg.Weight
-	} else {
-		jg.weight = UniformCost(g)
-	}
+	jg.weight = weightOf(g)
 
 	paths = newAllShortest(graph.NodesOf(g.Nodes()), false)
 
@@ -0,0 +1,181 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+// LineSelector returns the line used to join a pair of nodes from the
+// parallel lines between them. A LineSelector is only called with
+// non-empty lines and must not retain lines.
+type LineSelector func(lines graph.WeightedLines) graph.WeightedLine
+
+// MinWeightLine is a LineSelector that returns the line with the least
+// weight. Ties are broken by returning the first line found.
+func MinWeightLine(lines graph.WeightedLines) graph.WeightedLine {
+	var min graph.WeightedLine
+	for lines.Next() {
+		l := lines.WeightedLine()
+		if min == nil || l.Weight() < min.Weight() {
+			min = l
+		}
+	}
+	lines.Reset()
+	return min
+}
+
+// MultigraphWeight returns a Weighting for the multigraph g that returns
+// the weight of the line selected by sel from the lines between a pair of
+// nodes. If sel is nil, MinWeightLine is used. Node identity without a
+// joining line has zero weight and other absent edges have infinite weight.
+func MultigraphWeight(g graph.WeightedMultigraph, sel LineSelector) Weighting {
+	if sel == nil {
+		sel = MinWeightLine
+	}
+	return func(xid, yid int64) (w float64, ok bool) {
+		lines := g.WeightedLines(xid, yid)
+		if lines.Len() != 0 {
+			return sel(lines).Weight(), true
+		}
+		if xid == yid {
+			return 0, true
+		}
+		return math.Inf(1), false
+	}
+}
+
+// Multigraph is a graph.Graph view of a multigraph for use with the
+// shortest path and traversal functions. Each pair of nodes joined by
+// lines in the multigraph is joined by a single edge in the view.
+//
+// If the multigraph is a graph.WeightedMultigraph, the edge is the line
+// selected by Select, or the least weight line if Select is nil. Otherwise
+// the edge is the first line between the nodes and has unit weight.
+type Multigraph struct {
+	graph.Multigraph
+	Select LineSelector
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil
+// otherwise. The node v must be directly reachable from u as defined by
+// the From method.
+func (g Multigraph) Edge(uid, vid int64) graph.Edge {
+	return selectedEdge(g.Multigraph, g.Select, uid, vid)
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// and nil otherwise. The node v must be directly reachable from u as defined
+// by the From method.
+func (g Multigraph) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	return selectedEdge(g.Multigraph, g.Select, uid, vid)
+}
+
+// Weight returns the weight for the edge between x and y if Edge(xid, yid)
+// returns a non-nil Edge. If x and y are the same node and are not joined
+// by a line, the weight returned is zero, otherwise if there is no joining
+// line, the weight returned is +Inf. Weight returns true if an edge exists
+// between x and y or if x and y have the same ID, false otherwise.
+func (g Multigraph) Weight(xid, yid int64) (w float64, ok bool) {
+	return selectedWeight(g.Multigraph, g.Select, xid, yid)
+}
+
+// DirectedMultigraph is a graph.Directed view of a directed multigraph for
+// use with the shortest path and traversal functions. Edges are selected
+// as described for Multigraph.
+type DirectedMultigraph struct {
+	graph.DirectedMultigraph
+	Select LineSelector
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil
+// otherwise. The node v must be directly reachable from u as defined by
+// the From method.
+func (g DirectedMultigraph) Edge(uid, vid int64) graph.Edge {
+	return selectedEdge(g.DirectedMultigraph, g.Select, uid, vid)
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// and nil otherwise. The node v must be directly reachable from u as defined
+// by the From method.
+func (g DirectedMultigraph) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	return selectedEdge(g.DirectedMultigraph, g.Select, uid, vid)
+}
+
+// Weight returns the weight for the edge between x and y if Edge(xid, yid)
+// returns a non-nil Edge. If x and y are the same node and are not joined
+// by a line, the weight returned is zero, otherwise if there is no joining
+// line, the weight returned is +Inf. Weight returns true if an edge exists
+// between x and y or if x and y have the same ID, false otherwise.
+func (g DirectedMultigraph) Weight(xid, yid int64) (w float64, ok bool) {
+	return selectedWeight(g.DirectedMultigraph, g.Select, xid, yid)
+}
+
+// selectedEdge returns the edge representing the lines from u to v in g
+// or nil if there are no such lines.
+func selectedEdge(g graph.Multigraph, sel LineSelector, uid, vid int64) graph.WeightedEdge {
+	if wg, ok := g.(graph.WeightedMultigraph); ok {
+		lines := wg.WeightedLines(uid, vid)
+		if lines.Len() == 0 {
+			return nil
+		}
+		if sel == nil {
+			sel = MinWeightLine
+		}
+		l := sel(lines)
+		return lineEdge{Line: l, weight: l.Weight()}
+	}
+	lines := g.Lines(uid, vid)
+	if !lines.Next() {
+		return nil
+	}
+	l := lines.Line()
+	lines.Reset()
+	return lineEdge{Line: l, weight: 1}
+}
+
+// selectedWeight returns the weight of the edge representing the lines
+// between x and y in g.
+func selectedWeight(g graph.Multigraph, sel LineSelector, xid, yid int64) (w float64, ok bool) {
+	if wg, ok := g.(graph.WeightedMultigraph); ok {
+		return MultigraphWeight(wg, sel)(xid, yid)
+	}
+	if g.Lines(xid, yid).Len() != 0 {
+		return 1, true
+	}
+	if xid == yid {
+		return 0, true
+	}
+	return math.Inf(1), false
+}
+
+// lineEdge is a graph.WeightedEdge holding a multigraph line.
+type lineEdge struct {
+	graph.Line
+	weight float64
+}
+
+func (e lineEdge) ReversedEdge() graph.Edge {
+	return lineEdge{Line: e.Line.ReversedLine(), weight: e.weight}
+}
+func (e lineEdge) Weight() float64 { return e.weight }
+
+// weightOf returns the Weighting used for shortest path searches in g.
+// Weighted graphs use their Weight method, weighted multigraphs without
+// a Weight method use the least weight line between nodes and unweighted
+// graphs use UniformCost.
+func weightOf(g traverse.Graph) Weighting {
+	switch g := g.(type) {
+	case Weighted:
+		return g.Weight
+	case graph.WeightedMultigraph:
+		return MultigraphWeight(g, nil)
+	default:
+		return UniformCost(g)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/traverse"
+)
+
+var (
+	_ graph.Weighted         = Multigraph{}
+	_ graph.WeightedDirected = DirectedMultigraph{}
+)
+
+// parallelLines returns a multigraph where the least weight path from 0
+// to 2 uses the lighter of two parallel lines from 0 to 1.
+func parallelLines() *multi.WeightedDirectedGraph {
+	g := multi.NewWeightedDirectedGraph()
+	for _, l := range []struct {
+		f, t int64
+		w    float64
+	}{
+		{f: 0, t: 1, w: 5},
+		{f: 0, t: 1, w: 1},
+		{f: 1, t: 2, w: 1},
+		{f: 0, t: 2, w: 3},
+		{f: 2, t: 3, w: 1},
+	} {
+		g.SetWeightedLine(g.NewWeightedLine(multi.Node(l.f), multi.Node(l.t), l.w))
+	}
+	return g
+}
+
+// maxWeightLine is a LineSelector that returns the heaviest line.
+func maxWeightLine(lines graph.WeightedLines) graph.WeightedLine {
+	var max graph.WeightedLine
+	for lines.Next() {
+		l := lines.WeightedLine()
+		if max == nil || l.Weight() > max.Weight() {
+			max = l
+		}
+	}
+	lines.Reset()
+	return max
+}
+
+func TestMultigraphShortest(t *testing.T) {
+	g := parallelLines()
+	for _, test := range []struct {
+		name string
+		g    graph.Graph
+		path []int64
+		w    float64
+	}{
+		{name: "multigraph", g: g, path: []int64{0, 2, 3}, w: 4},
+		{name: "min view", g: DirectedMultigraph{DirectedMultigraph: g}, path: []int64{0, 1, 2, 3}, w: 3},
+		{name: "max view", g: DirectedMultigraph{DirectedMultigraph: g, Select: maxWeightLine}, path: []int64{0, 2, 3}, w: 4},
+		{name: "undirected view", g: Multigraph{Multigraph: g, Select: maxWeightLine}, path: []int64{0, 2, 3}, w: 4},
+	} {
+		for _, search := range []struct {
+			name string
+			fn   func(graph.Graph) Shortest
+		}{
+			{name: "Dijkstra", fn: func(g graph.Graph) Shortest { return DijkstraFrom(multi.Node(0), g) }},
+			{name: "BellmanFord", fn: func(g graph.Graph) Shortest { p, _ := BellmanFordFrom(multi.Node(0), g); return p }},
+			{name: "AStar", fn: func(g graph.Graph) Shortest { p, _ := AStar(multi.Node(0), multi.Node(3), g, nil); return p }},
+		} {
+			p, w := search.fn(test.g).To(3)
+			if got := nodeIDs(p); !reflect.DeepEqual(got, test.path) {
+				t.Errorf("unexpected %s path for %s: got:%v want:%v", search.name, test.name, got, test.path)
+			}
+			if w != test.w {
+				t.Errorf("unexpected %s weight for %s: got:%v want:%v", search.name, test.name, w, test.w)
+			}
+		}
+	}
+}
+
+func TestMultigraphEdgeWeightFunc(t *testing.T) {
+	// The weight of an edge is the number of parallel lines, so
+	// the search must use the graph's Weight method rather than
+	// the weights of the lines.
+	g := parallelLines()
+	g.EdgeWeightFunc = func(lines graph.WeightedLines) float64 {
+		if lines == nil {
+			return 0
+		}
+		return float64(lines.Len())
+	}
+	want := []int64{0, 2, 3}
+	const wantWeight = 2
+
+	for _, search := range []struct {
+		name string
+		fn   func(graph.Graph) Shortest
+	}{
+		{name: "Dijkstra", fn: func(g graph.Graph) Shortest { return DijkstraFrom(multi.Node(0), g) }},
+		{name: "BellmanFord", fn: func(g graph.Graph) Shortest { p, _ := BellmanFordFrom(multi.Node(0), g); return p }},
+		{name: "AStar", fn: func(g graph.Graph) Shortest { p, _ := AStar(multi.Node(0), multi.Node(3), g, nil); return p }},
+	} {
+		p, w := search.fn(g).To(3)
+		if got := nodeIDs(p); !reflect.DeepEqual(got, want) || w != wantWeight {
+			t.Errorf("unexpected %s path: got:%v weight:%v want:%v weight:%v", search.name, got, w, want, wantWeight)
+		}
+	}
+
+	for _, search := range []struct {
+		name string
+		fn   func(graph.Graph) (AllShortest, bool)
+	}{
+		{name: "FloydWarshall", fn: FloydWarshall},
+		{name: "JohnsonAllPaths", fn: JohnsonAllPaths},
+	} {
+		paths, _ := search.fn(g)
+		p, w, _ := paths.Between(0, 3)
+		if got := nodeIDs(p); !reflect.DeepEqual(got, want) || w != wantWeight {
+			t.Errorf("unexpected %s path: got:%v weight:%v want:%v weight:%v", search.name, got, w, want, wantWeight)
+		}
+	}
+
+	paths := YenKShortestPaths(g, 1, multi.Node(0), multi.Node(3))
+	if len(paths) != 1 || !reflect.DeepEqual(nodeIDs(paths[0]), want) {
+		t.Errorf("unexpected YenKShortestPaths paths: got:%v want:[%v]", paths, want)
+	}
+}
+
+func TestMultigraphWeight(t *testing.T) {
+	g := parallelLines()
+	weight := MultigraphWeight(g, nil)
+	for _, test := range []struct {
+		x, y int64
+		w    float64
+		ok   bool
+	}{
+		{x: 0, y: 1, w: 1, ok: true},
+		{x: 1, y: 1, w: 0, ok: true},
+		{x: 1, y: 0, w: math.Inf(1), ok: false},
+	} {
+		w, ok := weight(test.x, test.y)
+		if w != test.w || ok != test.ok {
+			t.Errorf("unexpected weight for %d->%d: got:(%v, %t) want:(%v, %t)", test.x, test.y, w, ok, test.w, test.ok)
+		}
+	}
+	e := DirectedMultigraph{DirectedMultigraph: g, Select: maxWeightLine}.WeightedEdge(0, 1)
+	if e == nil || e.Weight() != 5 {
+		t.Errorf("unexpected selected edge: got:%v", e)
+	}
+	if r := e.ReversedEdge(); r.From().ID() != 1 || r.To().ID() != 0 || r.(graph.WeightedEdge).Weight() != 5 {
+		t.Errorf("unexpected reversed edge: got:%v", r)
+	}
+}
+
+// multigraphOnly hides the graph.Graph methods of a multigraph.
+type multigraphOnly struct {
+	graph.Multigraph
+}
+
+func TestMultigraphTraversal(t *testing.T) {
+	dg := multi.NewDirectedGraph()
+	for _, l := range [][2]int64{{0, 1}, {0, 1}, {1, 2}, {3, 2}} {
+		dg.SetLine(dg.NewLine(multi.Node(l[0]), multi.Node(l[1])))
+	}
+	g := Multigraph{Multigraph: multigraphOnly{dg}}
+
+	var visited []int64
+	var traversed int
+	bf := traverse.BreadthFirst{
+		Visit: func(n graph.Node) { visited = append(visited, n.ID()) },
+		Traverse: func(e graph.Edge) bool {
+			traversed++
+			return true
+		},
+	}
+	bf.Walk(g, multi.Node(0), nil)
+	if !reflect.DeepEqual(visited, []int64{0, 1, 2}) {
+		t.Errorf("unexpected visited nodes: got:%v want:[0 1 2]", visited)
+	}
+	if traversed != 2 {
+		t.Errorf("unexpected number of traversed edges: got:%d want:2", traversed)
+	}
+
+	p, w := DijkstraFrom(multi.Node(0), g).To(2)
+	if got := nodeIDs(p); !reflect.DeepEqual(got, []int64{0, 1, 2}) || w != 2 {
+		t.Errorf("unexpected path: got:%v weight:%v want:[0 1 2] weight:2", got, w)
+	}
+	if w, ok := g.Weight(3, 0); ok || !math.IsInf(w, 1) {
+		t.Errorf("unexpected weight for absent edge: got:(%v, %t)", w, ok)
+	}
+}
+
+func nodeIDs(nodes []graph.Node) []int64 {
+	if nodes == nil {
+		return nil
+	}
+	ids := make([]int64, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
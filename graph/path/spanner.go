@@ -0,0 +1,156 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// GreedySpanner generates a t-spanner of g by greedy edge insertion, placing
+// the result in the destination, dst. In a t-spanner, the weight of the
+// shortest path between any pair of nodes is at most t times the weight of
+// the shortest path between them in g. The destination is not cleared first.
+// The total weight of the edges of the spanner is returned.
+//
+// Edges of g are considered in order of increasing weight, and an edge is
+// added to the spanner only when the spanner constructed so far has no path
+// between its end points within t times the edge's weight. The greedy
+// spanner of a graph with n nodes has girth greater than t+1, and so for
+// t = 2k-1 it holds O(n^(1+1/k)) edges. When t is infinite, the spanner
+// is a minimum spanning forest of g.
+//
+// Nodes and Edges from g are used to construct dst, so if the Node and Edge
+// types used in g are pointer or reference-like, then the values will be shared
+// between the graphs.
+//
+// If dst has nodes that exist in g, GreedySpanner will panic. GreedySpanner will
+// also panic if t is less than one or g has a negative edge weight.
+//
+// See doi:10.1007/BF02189308 for details.
+func GreedySpanner(dst WeightedBuilder, g UndirectedWeightLister, t float64) float64 {
+	if !(t >= 1) {
+		panic("path: spanner stretch less than one")
+	}
+
+	nodes := graph.NodesOf(g.Nodes())
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		dst.AddNode(n)
+		indexOf[n.ID()] = i
+	}
+
+	edges := graph.WeightedEdgesOf(g.WeightedEdges())
+	sort.Stable(byWeight(edges))
+
+	s := spanner{
+		adj:  make([][]spannerEdge, len(nodes)),
+		dist: make([]float64, len(nodes)),
+	}
+	for i := range s.dist {
+		s.dist[i] = math.Inf(1)
+	}
+	var w float64
+	for _, e := range edges {
+		uid := e.From().ID()
+		vid := e.To().ID()
+		if uid == vid {
+			continue
+		}
+		ew := e.Weight()
+		if ew < 0 {
+			panic("path: negative edge weight")
+		}
+		bound := t * ew
+		if math.IsInf(t, 1) {
+			// Avoid NaN bounds for zero weight edges.
+			bound = t
+		}
+		u := indexOf[uid]
+		v := indexOf[vid]
+		if s.within(u, v, bound) {
+			continue
+		}
+		s.adj[u] = append(s.adj[u], spannerEdge{to: v, weight: ew})
+		s.adj[v] = append(s.adj[v], spannerEdge{to: u, weight: ew})
+		dst.SetWeightedEdge(g.WeightedEdge(uid, vid))
+		w += ew
+	}
+	return w
+}
+
+// spanner is a spanner under construction.
+type spanner struct {
+	adj [][]spannerEdge
+
+	// dist and touched are workspace
+	// for bounded searches.
+	dist    []float64
+	touched []int
+}
+
+type spannerEdge struct {
+	to     int
+	weight float64
+}
+
+// within returns whether there is a path from u to v in the spanner
+// with weight no more than max. The search is a Dijkstra search that
+// does not proceed beyond max.
+func (s *spanner) within(u, v int, max float64) bool {
+	defer func() {
+		for _, i := range s.touched {
+			s.dist[i] = math.Inf(1)
+		}
+		s.touched = s.touched[:0]
+	}()
+
+	s.dist[u] = 0
+	s.touched = append(s.touched, u)
+	q := spannerQueue{{node: u}}
+	for q.Len() != 0 {
+		mid := heap.Pop(&q).(spannerDistance)
+		if mid.node == v {
+			return true
+		}
+		if mid.dist > s.dist[mid.node] {
+			continue
+		}
+		for _, e := range s.adj[mid.node] {
+			d := mid.dist + e.weight
+			if d > max || d >= s.dist[e.to] {
+				continue
+			}
+			if math.IsInf(s.dist[e.to], 1) {
+				s.touched = append(s.touched, e.to)
+			}
+			s.dist[e.to] = d
+			heap.Push(&q, spannerDistance{node: e.to, dist: d})
+		}
+	}
+	return false
+}
+
+type spannerDistance struct {
+	node int
+	dist float64
+}
+
+// spannerQueue implements a no-dec priority queue.
+type spannerQueue []spannerDistance
+
+func (q spannerQueue) Len() int            { return len(q) }
+func (q spannerQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q spannerQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *spannerQueue) Push(n interface{}) { *q = append(*q, n.(spannerDistance)) }
+func (q *spannerQueue) Pop() interface{} {
+	t := *q
+	var n interface{}
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
@@ -0,0 +1,103 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package path
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// euclideanComplete returns a complete graph on n random points in the
+// unit square with edges weighted by Euclidean distance.
+func euclideanComplete(n int, src rand.Source) *simple.WeightedUndirectedGraph {
+	rnd := rand.New(src)
+	x := make([]float64, n)
+	y := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.Float64()
+		y[i] = rnd.Float64()
+	}
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			w := math.Hypot(x[i]-x[j], y[i]-y[j])
+			g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(j), W: w})
+		}
+	}
+	return g
+}
+
+func TestGreedySpanner(t *testing.T) {
+	g := euclideanComplete(40, rand.NewSource(1))
+	all := DijkstraAllPaths(g)
+	m := g.Edges().Len()
+
+	var lastEdges int
+	for _, stretch := range []float64{1, 1.5, 2, 3} {
+		dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		w := GreedySpanner(dst, g, stretch)
+
+		var sum float64
+		edges := dst.WeightedEdges()
+		for edges.Next() {
+			sum += edges.WeightedEdge().Weight()
+		}
+		if math.Abs(sum-w) > 1e-9 {
+			t.Errorf("unexpected spanner weight for t=%v: got:%v want:%v", stretch, w, sum)
+		}
+		n := dst.Edges().Len()
+		// Random points are in general position, so
+		// only spanners with t > 1 may drop edges.
+		if stretch > 1 && n >= m {
+			t.Errorf("spanner for t=%v is not sparser than graph: %d edges of %d", stretch, n, m)
+		}
+		if lastEdges != 0 && n > lastEdges {
+			t.Errorf("spanner for t=%v has more edges than for smaller t: %d > %d", stretch, n, lastEdges)
+		}
+		lastEdges = n
+
+		span := DijkstraAllPaths(dst)
+		for i := int64(0); i < 40; i++ {
+			for j := i + 1; j < 40; j++ {
+				want := all.Weight(i, j)
+				got := span.Weight(i, j)
+				if got > stretch*want*(1+1e-12) {
+					t.Errorf("stretch exceeded for t=%v between %d and %d: got:%v shortest:%v", stretch, i, j, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestGreedySpannerMST(t *testing.T) {
+	for _, test := range spanningTreeTests {
+		g := test.graph()
+		for _, e := range test.edges {
+			g.SetWeightedEdge(e)
+		}
+		dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		w := GreedySpanner(dst, g, math.Inf(1))
+		if w != test.want {
+			t.Errorf("unexpected minimum spanning forest weight for %q: got:%v want:%v", test.name, w, test.want)
+		}
+	}
+}
+
+func TestGreedySpannerPanics(t *testing.T) {
+	for _, stretch := range []float64{0.5, math.NaN()} {
+		panicked := func() (panicked bool) {
+			defer func() { panicked = recover() != nil }()
+			GreedySpanner(simple.NewWeightedUndirectedGraph(0, math.Inf(1)), simple.NewWeightedUndirectedGraph(0, math.Inf(1)), stretch)
+			return false
+		}()
+		if !panicked {
+			t.Errorf("expected panic for t=%v", stretch)
+		}
+	}
+}
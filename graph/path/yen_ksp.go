@@ -20,11 +20,7 @@ func YenKShortestPaths(g graph.Graph, k int, s, t graph.Node) [][]graph.Node {
 		isDirected: isDirected,
 	}
 
-	if wg, ok := g.(Weighted); ok {
-		yk.weight = wg.Weight
-	} else {
-		yk.weight = UniformCost(g)
-	}
+	yk.weight = weightOf(g)
 
 	shortest, _ := DijkstraFrom(s, yk).To(t.ID())
 	switch len(shortest) {
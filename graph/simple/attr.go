@@ -0,0 +1,322 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+)
+
+// AttrNode is a graph node carrying an attribute payload.
+//
+// AttrNode implements encoding.Attributer and encoding.AttributeSetter so
+// payloads are surfaced by attribute aware encoders and decoders such as the
+// graph/encoding/dot package. If the payload or a pointer to it implements
+// encoding.Attributer or encoding.AttributeSetter, those methods are used.
+// Otherwise struct payloads are encoded as one attribute per exported field,
+// keyed by the field's name or its attr struct tag; a tag of "-" omits the
+// field. Payloads of type map[string]string are encoded as one attribute
+// per key. Other payload types have no attributes.
+type AttrNode[N any] struct {
+	NodeID int64
+	Attr   N
+}
+
+// ID returns the ID number of the node.
+func (n *AttrNode[N]) ID() int64 { return n.NodeID }
+
+// Attributes returns the encoding attributes of the node's payload.
+func (n *AttrNode[N]) Attributes() []encoding.Attribute { return attributesOf(&n.Attr) }
+
+// SetAttribute sets the attribute of the node's payload described by attr.
+func (n *AttrNode[N]) SetAttribute(attr encoding.Attribute) error {
+	return setAttributeOf(&n.Attr, attr)
+}
+
+// AttrEdge is a graph edge carrying an attribute payload. Attributes of the
+// payload are handled as described for AttrNode.
+type AttrEdge[E any] struct {
+	F, T graph.Node
+	Attr E
+}
+
+// From returns the from-node of the edge.
+func (e *AttrEdge[E]) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e *AttrEdge[E]) To() graph.Node { return e.T }
+
+// ReversedEdge returns a new AttrEdge with the F and T fields swapped.
+// The payload of the new edge is a copy of the receiver's payload.
+func (e *AttrEdge[E]) ReversedEdge() graph.Edge { return &AttrEdge[E]{F: e.T, T: e.F, Attr: e.Attr} }
+
+// Attributes returns the encoding attributes of the edge's payload.
+func (e *AttrEdge[E]) Attributes() []encoding.Attribute { return attributesOf(&e.Attr) }
+
+// SetAttribute sets the attribute of the edge's payload described by attr.
+func (e *AttrEdge[E]) SetAttribute(attr encoding.Attribute) error {
+	return setAttributeOf(&e.Attr, attr)
+}
+
+// AttrGraph is an undirected graph with nodes and edges carrying typed
+// attribute payloads. The nodes and edges created by NewNode and NewEdge
+// are *AttrNode[N] and *AttrEdge[E].
+type AttrGraph[N, E any] struct {
+	*UndirectedGraph
+}
+
+// NewAttrGraph returns an AttrGraph.
+func NewAttrGraph[N, E any]() AttrGraph[N, E] {
+	return AttrGraph[N, E]{NewUndirectedGraph()}
+}
+
+// NewNode returns a new unique *AttrNode[N] with a zero payload to be added
+// to g. The node's ID does not become valid in g until the node is added to g.
+func (g AttrGraph[N, E]) NewNode() graph.Node {
+	return &AttrNode[N]{NodeID: g.UndirectedGraph.NewNode().ID()}
+}
+
+// NewEdge returns a new *AttrEdge[E] with a zero payload from the source to
+// the destination node.
+func (g AttrGraph[N, E]) NewEdge(from, to graph.Node) graph.Edge {
+	return &AttrEdge[E]{F: from, T: to}
+}
+
+// AddAttrNode adds a new node with the given payload to g and returns it.
+func (g AttrGraph[N, E]) AddAttrNode(attr N) *AttrNode[N] {
+	n := g.NewNode().(*AttrNode[N])
+	n.Attr = attr
+	g.AddNode(n)
+	return n
+}
+
+// SetAttrEdge sets an edge with the given payload between the nodes with
+// IDs uid and vid, adding *AttrNode[N] nodes with zero payloads for IDs
+// not in g. It will panic if uid and vid are equal.
+func (g AttrGraph[N, E]) SetAttrEdge(uid, vid int64, attr E) {
+	g.SetEdge(&AttrEdge[E]{F: nodeFor[N](g, uid), T: nodeFor[N](g, vid), Attr: attr})
+}
+
+// NodeAttr returns the payload of the node with the given ID and whether
+// the node exists in g with a payload of type N.
+func (g AttrGraph[N, E]) NodeAttr(id int64) (attr N, ok bool) {
+	return nodeAttr[N](g.Node(id))
+}
+
+// EdgeAttr returns the payload of the edge between the nodes with IDs xid
+// and yid, and whether the edge exists in g with a payload of type E.
+func (g AttrGraph[N, E]) EdgeAttr(xid, yid int64) (attr E, ok bool) {
+	return edgeAttr[E](g.Edge(xid, yid))
+}
+
+// AttrDirectedGraph is a directed graph with nodes and edges carrying typed
+// attribute payloads. The nodes and edges created by NewNode and NewEdge
+// are *AttrNode[N] and *AttrEdge[E].
+type AttrDirectedGraph[N, E any] struct {
+	*DirectedGraph
+}
+
+// NewAttrDirectedGraph returns an AttrDirectedGraph.
+func NewAttrDirectedGraph[N, E any]() AttrDirectedGraph[N, E] {
+	return AttrDirectedGraph[N, E]{NewDirectedGraph()}
+}
+
+// NewNode returns a new unique *AttrNode[N] with a zero payload to be added
+// to g. The node's ID does not become valid in g until the node is added to g.
+func (g AttrDirectedGraph[N, E]) NewNode() graph.Node {
+	return &AttrNode[N]{NodeID: g.DirectedGraph.NewNode().ID()}
+}
+
+// NewEdge returns a new *AttrEdge[E] with a zero payload from the source to
+// the destination node.
+func (g AttrDirectedGraph[N, E]) NewEdge(from, to graph.Node) graph.Edge {
+	return &AttrEdge[E]{F: from, T: to}
+}
+
+// AddAttrNode adds a new node with the given payload to g and returns it.
+func (g AttrDirectedGraph[N, E]) AddAttrNode(attr N) *AttrNode[N] {
+	n := g.NewNode().(*AttrNode[N])
+	n.Attr = attr
+	g.AddNode(n)
+	return n
+}
+
+// SetAttrEdge sets an edge with the given payload from the node with ID uid
+// to the node with ID vid, adding *AttrNode[N] nodes with zero payloads for
+// IDs not in g. It will panic if uid and vid are equal.
+func (g AttrDirectedGraph[N, E]) SetAttrEdge(uid, vid int64, attr E) {
+	g.SetEdge(&AttrEdge[E]{F: nodeFor[N](g, uid), T: nodeFor[N](g, vid), Attr: attr})
+}
+
+// NodeAttr returns the payload of the node with the given ID and whether
+// the node exists in g with a payload of type N.
+func (g AttrDirectedGraph[N, E]) NodeAttr(id int64) (attr N, ok bool) {
+	return nodeAttr[N](g.Node(id))
+}
+
+// EdgeAttr returns the payload of the edge from the node with ID uid to the
+// node with ID vid, and whether the edge exists in g with a payload of type E.
+func (g AttrDirectedGraph[N, E]) EdgeAttr(uid, vid int64) (attr E, ok bool) {
+	return edgeAttr[E](g.Edge(uid, vid))
+}
+
+// nodeFor returns the node in g with the given ID or a new
+// *AttrNode[N] if it does not exist.
+func nodeFor[N any](g graph.Graph, id int64) graph.Node {
+	if n := g.Node(id); n != nil {
+		return n
+	}
+	return &AttrNode[N]{NodeID: id}
+}
+
+func nodeAttr[N any](n graph.Node) (attr N, ok bool) {
+	an, ok := n.(*AttrNode[N])
+	if !ok {
+		return attr, false
+	}
+	return an.Attr, true
+}
+
+func edgeAttr[E any](e graph.Edge) (attr E, ok bool) {
+	ae, ok := e.(*AttrEdge[E])
+	if !ok {
+		return attr, false
+	}
+	return ae.Attr, true
+}
+
+// attributesOf returns the encoding attributes of the payload pointed to by p.
+func attributesOf(p interface{}) []encoding.Attribute {
+	if a, ok := reflect.ValueOf(p).Elem().Interface().(encoding.Attributer); ok {
+		return a.Attributes()
+	}
+	if a, ok := p.(encoding.Attributer); ok {
+		return a.Attributes()
+	}
+	v := reflect.ValueOf(p).Elem()
+	switch v.Kind() {
+	case reflect.Struct:
+		var attrs []encoding.Attribute
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			key, ok := fieldKey(t.Field(i))
+			if !ok {
+				continue
+			}
+			attrs = append(attrs, encoding.Attribute{Key: key, Value: fmt.Sprint(v.Field(i).Interface())})
+		}
+		return attrs
+	case reflect.Map:
+		m, ok := v.Interface().(map[string]string)
+		if !ok {
+			return nil
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]encoding.Attribute, len(keys))
+		for i, k := range keys {
+			attrs[i] = encoding.Attribute{Key: k, Value: m[k]}
+		}
+		return attrs
+	default:
+		return nil
+	}
+}
+
+// setAttributeOf sets the attribute of the payload pointed to by p
+// described by attr.
+func setAttributeOf(p interface{}, attr encoding.Attribute) error {
+	if s, ok := p.(encoding.AttributeSetter); ok {
+		return s.SetAttribute(attr)
+	}
+	v := reflect.ValueOf(p).Elem()
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			key, ok := fieldKey(t.Field(i))
+			if !ok || key != attr.Key {
+				continue
+			}
+			return setValue(v.Field(i), attr)
+		}
+		return fmt.Errorf("simple: unknown attribute %q", attr.Key)
+	case reflect.Map:
+		if v.Type() != reflect.TypeOf(map[string]string(nil)) {
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		v.SetMapIndex(reflect.ValueOf(attr.Key), reflect.ValueOf(attr.Value))
+		return nil
+	}
+	return fmt.Errorf("simple: cannot set attribute %q on %T payload", attr.Key, v.Interface())
+}
+
+// fieldKey returns the attribute key for the struct field f and whether
+// the field is encoded.
+func fieldKey(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" {
+		return "", false
+	}
+	key := f.Tag.Get("attr")
+	switch key {
+	case "-":
+		return "", false
+	case "":
+		return f.Name, true
+	default:
+		return key, true
+	}
+}
+
+// setValue sets v to the value held in attr.
+func setValue(v reflect.Value, attr encoding.Attribute) error {
+	var err error
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(attr.Value)
+		return nil
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(attr.Value)
+		if err == nil {
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		i, err = strconv.ParseInt(attr.Value, 0, v.Type().Bits())
+		if err == nil {
+			v.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		u, err = strconv.ParseUint(attr.Value, 0, v.Type().Bits())
+		if err == nil {
+			v.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(attr.Value, v.Type().Bits())
+		if err == nil {
+			v.SetFloat(f)
+		}
+	default:
+		return fmt.Errorf("simple: cannot set attribute %q of kind %v", attr.Key, v.Kind())
+	}
+	if err != nil {
+		return fmt.Errorf("simple: invalid value for attribute %q: %v", attr.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple_test
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/encoding/dot"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+type city struct {
+	Name       string  `attr:"name"`
+	Population int     `attr:"pop"`
+	Capital    bool    `attr:"capital"`
+	Area       float64 `attr:"area"`
+	Note       string  `attr:"-"`
+	hidden     int
+}
+
+type road struct {
+	Lanes uint8
+	Toll  bool `attr:"toll"`
+}
+
+func TestAttrNodeAttributes(t *testing.T) {
+	n := &simple.AttrNode[city]{NodeID: 1, Attr: city{Name: "A", Population: 10, Capital: true, Area: 1.5, Note: "x", hidden: 1}}
+	got := n.Attributes()
+	want := []encoding.Attribute{
+		{Key: "name", Value: "A"},
+		{Key: "pop", Value: "10"},
+		{Key: "capital", Value: "true"},
+		{Key: "area", Value: "1.5"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected attributes: got:%v want:%v", got, want)
+	}
+
+	var m simple.AttrNode[city]
+	for _, a := range want {
+		err := m.SetAttribute(a)
+		if err != nil {
+			t.Fatalf("unexpected error setting %v: %v", a, err)
+		}
+	}
+	if m.Attr != (city{Name: "A", Population: 10, Capital: true, Area: 1.5}) {
+		t.Errorf("unexpected payload after setting attributes: %+v", m.Attr)
+	}
+
+	for _, a := range []encoding.Attribute{
+		{Key: "pop", Value: "many"},
+		{Key: "Note", Value: "x"},
+		{Key: "unknown", Value: "x"},
+	} {
+		if err := m.SetAttribute(a); err == nil {
+			t.Errorf("expected error setting %v", a)
+		}
+	}
+}
+
+func TestAttrMapPayload(t *testing.T) {
+	var n simple.AttrNode[map[string]string]
+	for _, a := range []encoding.Attribute{{Key: "b", Value: "2"}, {Key: "a", Value: "1"}} {
+		err := n.SetAttribute(a)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	got := n.Attributes()
+	want := []encoding.Attribute{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected attributes: got:%v want:%v", got, want)
+	}
+
+	var i simple.AttrNode[int]
+	if len(i.Attributes()) != 0 {
+		t.Errorf("unexpected attributes for scalar payload: %v", i.Attributes())
+	}
+	if err := i.SetAttribute(encoding.Attribute{Key: "a", Value: "1"}); err == nil {
+		t.Error("expected error setting attribute on scalar payload")
+	}
+}
+
+func TestAttrGraph(t *testing.T) {
+	g := simple.NewAttrGraph[city, road]()
+	a := g.AddAttrNode(city{Name: "A"})
+	b := g.AddAttrNode(city{Name: "B"})
+	g.SetAttrEdge(a.ID(), b.ID(), road{Lanes: 2})
+	g.SetAttrEdge(b.ID(), 10, road{Lanes: 1, Toll: true})
+
+	if c, ok := g.NodeAttr(b.ID()); !ok || c.Name != "B" {
+		t.Errorf("unexpected node attribute: got:%+v ok=%t", c, ok)
+	}
+	if _, ok := g.NodeAttr(10); !ok {
+		t.Error("expected edge end point to be added as an attribute node")
+	}
+	if _, ok := g.NodeAttr(11); ok {
+		t.Error("unexpected attribute for absent node")
+	}
+	if r, ok := g.EdgeAttr(10, b.ID()); !ok || r != (road{Lanes: 1, Toll: true}) {
+		t.Errorf("unexpected edge attribute: got:%+v ok=%t", r, ok)
+	}
+	if _, ok := g.EdgeAttr(a.ID(), 10); ok {
+		t.Error("unexpected attribute for absent edge")
+	}
+}
+
+func TestAttrDirectedGraph(t *testing.T) {
+	g := simple.NewAttrDirectedGraph[city, road]()
+	g.SetAttrEdge(0, 1, road{Lanes: 3})
+	if r, ok := g.EdgeAttr(0, 1); !ok || r.Lanes != 3 {
+		t.Errorf("unexpected edge attribute: got:%+v ok=%t", r, ok)
+	}
+	if _, ok := g.EdgeAttr(1, 0); ok {
+		t.Error("unexpected attribute for reversed edge")
+	}
+}
+
+func TestAttrGraphDOTRoundTrip(t *testing.T) {
+	g := simple.NewAttrDirectedGraph[city, road]()
+	a := g.AddAttrNode(city{Name: "A", Population: 100, Capital: true})
+	b := g.AddAttrNode(city{Name: "B", Population: 20, Area: 2.25})
+	g.SetAttrEdge(a.ID(), b.ID(), road{Lanes: 4, Toll: true})
+
+	buf, err := dot.Marshal(g, "", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error marshaling: %v", err)
+	}
+
+	dst := simple.NewAttrDirectedGraph[city, road]()
+	err = dot.Unmarshal(buf, dst)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling: %v\n%s", err, buf)
+	}
+	if dst.Nodes().Len() != 2 {
+		t.Fatalf("unexpected number of nodes: got:%d want:2", dst.Nodes().Len())
+	}
+	for _, want := range []city{g.Node(a.ID()).(*simple.AttrNode[city]).Attr, g.Node(b.ID()).(*simple.AttrNode[city]).Attr} {
+		var found bool
+		nodes := dst.Nodes()
+		for nodes.Next() {
+			n := nodes.Node().(*simple.AttrNode[city])
+			if n.Attr == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("missing node with payload %+v after round trip:\n%s", want, buf)
+		}
+	}
+	edges := dst.Edges()
+	if edges.Len() != 1 {
+		t.Fatalf("unexpected number of edges: got:%d want:1", edges.Len())
+	}
+	edges.Next()
+	if got := edges.Edge().(*simple.AttrEdge[road]).Attr; got != (road{Lanes: 4, Toll: true}) {
+		t.Errorf("unexpected edge payload after round trip: got:%+v", got)
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple
+
+import (
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/uid"
+)
+
+// NewDirectedGraphFrom returns a DirectedGraph holding the given edges and
+// their end points. The internal storage of the graph is sized to hold
+// the edges before they are inserted. It will panic if the IDs of the
+// From and To of any edge are equal.
+func NewDirectedGraphFrom(edges []graph.Edge) *DirectedGraph {
+	nodes, out, in := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, true)
+	g := &DirectedGraph{
+		nodes: nodes,
+		from:  make(map[int64]map[int64]graph.Edge, len(out)),
+		to:    make(map[int64]map[int64]graph.Edge, len(in)),
+
+		nodeIDs: newNodes(nodes),
+	}
+	g.addEdges(edges, out, in)
+	return g
+}
+
+// AddEdges adds the given edges to g as if by calling SetEdge on each edge
+// in order. Storage for the edges of each node first seen in the call is
+// sized to hold the node's edges before they are inserted. It will panic
+// before modifying g if the IDs of the From and To of any edge are equal.
+func (g *DirectedGraph) AddEdges(edges []graph.Edge) {
+	nodes, out, in := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, true)
+	mergeNodes(g.nodes, &g.nodeIDs, nodes)
+	g.addEdges(edges, out, in)
+}
+
+func (g *DirectedGraph) addEdges(edges []graph.Edge, out, in map[int64]int) {
+	for _, e := range edges {
+		fid := e.From().ID()
+		tid := e.To().ID()
+
+		fm, ok := g.from[fid]
+		if !ok {
+			fm = make(map[int64]graph.Edge, out[fid])
+			g.from[fid] = fm
+		}
+		fm[tid] = e
+		tm, ok := g.to[tid]
+		if !ok {
+			tm = make(map[int64]graph.Edge, in[tid])
+			g.to[tid] = tm
+		}
+		tm[fid] = e
+	}
+}
+
+// NewUndirectedGraphFrom returns an UndirectedGraph holding the given edges
+// and their end points. The internal storage of the graph is sized to hold
+// the edges before they are inserted. It will panic if the IDs of the From
+// and To of any edge are equal.
+func NewUndirectedGraphFrom(edges []graph.Edge) *UndirectedGraph {
+	nodes, deg, _ := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, false)
+	g := &UndirectedGraph{
+		nodes: nodes,
+		edges: make(map[int64]map[int64]graph.Edge, len(nodes)),
+
+		nodeIDs: newNodes(nodes),
+	}
+	g.addEdges(edges, deg)
+	return g
+}
+
+// AddEdges adds the given edges to g as if by calling SetEdge on each edge
+// in order. Storage for the edges of each node first seen in the call is
+// sized to hold the node's edges before they are inserted. It will panic
+// before modifying g if the IDs of the From and To of any edge are equal.
+func (g *UndirectedGraph) AddEdges(edges []graph.Edge) {
+	nodes, deg, _ := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, false)
+	mergeNodes(g.nodes, &g.nodeIDs, nodes)
+	g.addEdges(edges, deg)
+}
+
+func (g *UndirectedGraph) addEdges(edges []graph.Edge, deg map[int64]int) {
+	for _, e := range edges {
+		fid := e.From().ID()
+		tid := e.To().ID()
+
+		fm, ok := g.edges[fid]
+		if !ok {
+			fm = make(map[int64]graph.Edge, deg[fid])
+			g.edges[fid] = fm
+		}
+		fm[tid] = e
+		tm, ok := g.edges[tid]
+		if !ok {
+			tm = make(map[int64]graph.Edge, deg[tid])
+			g.edges[tid] = tm
+		}
+		tm[fid] = e
+	}
+}
+
+// NewWeightedDirectedGraphFrom returns a WeightedDirectedGraph with the
+// specified self and absent edge weight values holding the given edges and
+// their end points. The internal storage of the graph is sized to hold the
+// edges before they are inserted. It will panic if the IDs of the From and
+// To of any edge are equal.
+func NewWeightedDirectedGraphFrom(self, absent float64, edges []graph.WeightedEdge) *WeightedDirectedGraph {
+	nodes, out, in := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, true)
+	g := &WeightedDirectedGraph{
+		nodes: nodes,
+		from:  make(map[int64]map[int64]graph.WeightedEdge, len(out)),
+		to:    make(map[int64]map[int64]graph.WeightedEdge, len(in)),
+
+		self:   self,
+		absent: absent,
+
+		nodeIDs: newNodes(nodes),
+	}
+	g.addWeightedEdges(edges, out, in)
+	return g
+}
+
+// AddWeightedEdges adds the given edges to g as if by calling
+// SetWeightedEdge on each edge in order. Storage for the edges of each node
+// first seen in the call is sized to hold the node's edges before they are
+// inserted. It will panic before modifying g if the IDs of the From and To
+// of any edge are equal.
+func (g *WeightedDirectedGraph) AddWeightedEdges(edges []graph.WeightedEdge) {
+	nodes, out, in := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, true)
+	mergeNodes(g.nodes, &g.nodeIDs, nodes)
+	g.addWeightedEdges(edges, out, in)
+}
+
+func (g *WeightedDirectedGraph) addWeightedEdges(edges []graph.WeightedEdge, out, in map[int64]int) {
+	for _, e := range edges {
+		fid := e.From().ID()
+		tid := e.To().ID()
+
+		fm, ok := g.from[fid]
+		if !ok {
+			fm = make(map[int64]graph.WeightedEdge, out[fid])
+			g.from[fid] = fm
+		}
+		fm[tid] = e
+		tm, ok := g.to[tid]
+		if !ok {
+			tm = make(map[int64]graph.WeightedEdge, in[tid])
+			g.to[tid] = tm
+		}
+		tm[fid] = e
+	}
+}
+
+// NewWeightedUndirectedGraphFrom returns a WeightedUndirectedGraph with the
+// specified self and absent edge weight values holding the given edges and
+// their end points. The internal storage of the graph is sized to hold the
+// edges before they are inserted. It will panic if the IDs of the From and
+// To of any edge are equal.
+func NewWeightedUndirectedGraphFrom(self, absent float64, edges []graph.WeightedEdge) *WeightedUndirectedGraph {
+	nodes, deg, _ := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, false)
+	g := &WeightedUndirectedGraph{
+		nodes: nodes,
+		edges: make(map[int64]map[int64]graph.WeightedEdge, len(nodes)),
+
+		self:   self,
+		absent: absent,
+
+		nodeIDs: newNodes(nodes),
+	}
+	g.addWeightedEdges(edges, deg)
+	return g
+}
+
+// AddWeightedEdges adds the given edges to g as if by calling
+// SetWeightedEdge on each edge in order. Storage for the edges of each node
+// first seen in the call is sized to hold the node's edges before they are
+// inserted. It will panic before modifying g if the IDs of the From and To
+// of any edge are equal.
+func (g *WeightedUndirectedGraph) AddWeightedEdges(edges []graph.WeightedEdge) {
+	nodes, deg, _ := endPoints(len(edges), func(i int) graph.Edge { return edges[i] }, false)
+	mergeNodes(g.nodes, &g.nodeIDs, nodes)
+	g.addWeightedEdges(edges, deg)
+}
+
+func (g *WeightedUndirectedGraph) addWeightedEdges(edges []graph.WeightedEdge, deg map[int64]int) {
+	for _, e := range edges {
+		fid := e.From().ID()
+		tid := e.To().ID()
+
+		fm, ok := g.edges[fid]
+		if !ok {
+			fm = make(map[int64]graph.WeightedEdge, deg[fid])
+			g.edges[fid] = fm
+		}
+		fm[tid] = e
+		tm, ok := g.edges[tid]
+		if !ok {
+			tm = make(map[int64]graph.WeightedEdge, deg[tid])
+			g.edges[tid] = tm
+		}
+		tm[fid] = e
+	}
+}
+
+// endPoints returns the last seen node for each ID in the n edges
+// returned by edge, and the number of edges leaving and arriving at each
+// node. If directed is false the out-degrees hold the total degree of each
+// node and the in-degrees are nil. Repeated edges are counted each time they
+// appear. It will panic if the IDs of the From and To of any edge are equal.
+func endPoints(n int, edge func(int) graph.Edge, directed bool) (nodes map[int64]graph.Node, out, in map[int64]int) {
+	nodes = make(map[int64]graph.Node)
+	out = make(map[int64]int)
+	if directed {
+		in = make(map[int64]int)
+	}
+	for i := 0; i < n; i++ {
+		e := edge(i)
+		from := e.From()
+		fid := from.ID()
+		to := e.To()
+		tid := to.ID()
+		if fid == tid {
+			panic("simple: adding self edge")
+		}
+		nodes[fid] = from
+		nodes[tid] = to
+		out[fid]++
+		if directed {
+			in[tid]++
+		} else {
+			out[tid]++
+		}
+	}
+	return nodes, out, in
+}
+
+// mergeNodes sets the nodes of dst to the given nodes, marking the IDs
+// of nodes not already in dst as used.
+func mergeNodes(dst map[int64]graph.Node, ids *uid.Set, nodes map[int64]graph.Node) {
+	for id, n := range nodes {
+		if _, ok := dst[id]; !ok {
+			ids.Use(id)
+		}
+		dst[id] = n
+	}
+}
+
+// newNodes returns the ID set for the given nodes.
+func newNodes(nodes map[int64]graph.Node) uid.Set {
+	ids := uid.NewSetSize(len(nodes))
+	for id := range nodes {
+		ids.Use(id)
+	}
+	return ids
+}
@@ -0,0 +1,158 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package simple_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func randomEdges(n, m int, seed uint64) []graph.Edge {
+	rnd := rand.New(rand.NewSource(seed))
+	edges := make([]graph.Edge, 0, m)
+	for len(edges) < m {
+		u := rnd.Int63n(int64(n))
+		v := rnd.Int63n(int64(n))
+		if u == v {
+			continue
+		}
+		edges = append(edges, simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+	}
+	return edges
+}
+
+func randomWeightedEdges(n, m int, seed uint64) []graph.WeightedEdge {
+	rnd := rand.New(rand.NewSource(seed))
+	edges := make([]graph.WeightedEdge, 0, m)
+	for len(edges) < m {
+		u := rnd.Int63n(int64(n))
+		v := rnd.Int63n(int64(n))
+		if u == v {
+			continue
+		}
+		edges = append(edges, simple.WeightedEdge{F: simple.Node(u), T: simple.Node(v), W: rnd.Float64()})
+	}
+	return edges
+}
+
+// edgeSet returns the directed end point pairs of the edges in g mapped
+// to their weights.
+func edgeSet(g graph.Graph) map[[2]int64]float64 {
+	s := make(map[[2]int64]float64)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uid := nodes.Node().ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			var w float64
+			if wg, ok := g.(graph.Weighted); ok {
+				w, _ = wg.Weight(uid, vid)
+			}
+			s[[2]int64{uid, vid}] = w
+		}
+	}
+	return s
+}
+
+func sameGraph(t *testing.T, name string, got, want graph.Graph) {
+	t.Helper()
+	if got.Nodes().Len() != want.Nodes().Len() {
+		t.Errorf("unexpected number of nodes for %s: got:%d want:%d", name, got.Nodes().Len(), want.Nodes().Len())
+	}
+	g, w := edgeSet(got), edgeSet(want)
+	if len(g) != len(w) {
+		t.Errorf("unexpected number of edges for %s: got:%d want:%d", name, len(g), len(w))
+	}
+	for e, wt := range w {
+		if gt, ok := g[e]; !ok || gt != wt {
+			t.Errorf("unexpected edge %v for %s: got:(%v, %t) want:%v", e, name, gt, ok, wt)
+		}
+	}
+}
+
+func TestBulkEdges(t *testing.T) {
+	edges := randomEdges(50, 400, 1)
+	weighted := randomWeightedEdges(50, 400, 1)
+
+	dg := simple.NewDirectedGraph()
+	ug := simple.NewUndirectedGraph()
+	for _, e := range edges {
+		dg.SetEdge(e)
+		ug.SetEdge(e)
+	}
+	wdg := simple.NewWeightedDirectedGraph(0, 0)
+	wug := simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range weighted {
+		wdg.SetWeightedEdge(e)
+		wug.SetWeightedEdge(e)
+	}
+
+	sameGraph(t, "NewDirectedGraphFrom", simple.NewDirectedGraphFrom(edges), dg)
+	sameGraph(t, "NewUndirectedGraphFrom", simple.NewUndirectedGraphFrom(edges), ug)
+	sameGraph(t, "NewWeightedDirectedGraphFrom", simple.NewWeightedDirectedGraphFrom(0, 0, weighted), wdg)
+	sameGraph(t, "NewWeightedUndirectedGraphFrom", simple.NewWeightedUndirectedGraphFrom(0, 0, weighted), wug)
+
+	bdg := simple.NewDirectedGraph()
+	bdg.AddEdges(edges[:200])
+	bdg.AddEdges(edges[200:])
+	sameGraph(t, "DirectedGraph.AddEdges", bdg, dg)
+	bug := simple.NewUndirectedGraph()
+	bug.AddEdges(edges[:200])
+	bug.AddEdges(edges[200:])
+	sameGraph(t, "UndirectedGraph.AddEdges", bug, ug)
+	bwdg := simple.NewWeightedDirectedGraph(0, 0)
+	bwdg.AddWeightedEdges(weighted[:200])
+	bwdg.AddWeightedEdges(weighted[200:])
+	sameGraph(t, "WeightedDirectedGraph.AddWeightedEdges", bwdg, wdg)
+	bwug := simple.NewWeightedUndirectedGraph(0, 0)
+	bwug.AddWeightedEdges(weighted[:200])
+	bwug.AddWeightedEdges(weighted[200:])
+	sameGraph(t, "WeightedUndirectedGraph.AddWeightedEdges", bwug, wug)
+
+	if n := bdg.NewNode(); bdg.Node(n.ID()) != nil {
+		t.Errorf("new node ID %d exists in graph", n.ID())
+	}
+}
+
+func TestBulkSelfEdge(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	edges := []graph.Edge{
+		simple.Edge{F: simple.Node(0), T: simple.Node(1)},
+		simple.Edge{F: simple.Node(2), T: simple.Node(2)},
+	}
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		g.AddEdges(edges)
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for self edge")
+	}
+	if g.Nodes().Len() != 0 {
+		t.Errorf("graph modified before panic: got %d nodes", g.Nodes().Len())
+	}
+}
+
+var bulkBenchEdges = randomEdges(1e4, 1e5, 1)
+
+func BenchmarkSetEdge(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		g := simple.NewDirectedGraph()
+		for _, e := range bulkBenchEdges {
+			g.SetEdge(e)
+		}
+	}
+}
+
+func BenchmarkNewDirectedGraphFrom(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		simple.NewDirectedGraphFrom(bulkBenchEdges)
+	}
+}
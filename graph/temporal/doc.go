@@ -0,0 +1,16 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package temporal provides temporal graphs, graphs whose edges are only
+// present over an interval of time, and analysis functions that respect the
+// ordering of edges in time.
+//
+// A temporal edge departs its from node at its start time and arrives at its
+// to node at its end time. A journey is a sequence of temporal edges where
+// each edge departs no earlier than the previous edge arrives. Reachability
+// by journeys is not transitive; a node may reach a second node which reaches
+// a third, while no journey exists from the first to the third.
+//
+// Undirected contacts can be represented by a pair of opposed temporal edges.
+package temporal // import "gonum.org/v1/gonum/graph/temporal"
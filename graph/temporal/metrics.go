@@ -0,0 +1,60 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+// Degrees returns the temporal degree of each node in g over the closed
+// interval [from, to]. The temporal degree of a node is the number of
+// temporal edges incident to the node that are present during the
+// interval.
+func Degrees(g *Graph, from, to float64) map[int64]int {
+	d := make(map[int64]int, len(g.nodes))
+	for id := range g.nodes {
+		d[id] = 0
+	}
+	for _, e := range g.edges {
+		if !e.overlaps(from, to) {
+			continue
+		}
+		d[e.F.ID()]++
+		d[e.T.ID()]++
+	}
+	return d
+}
+
+// Reach returns the number of nodes that can be reached by a journey from
+// each node in g, departing no earlier than start and arriving no later
+// than end. The starting node is not counted.
+func Reach(g *Graph, start, end float64) map[int64]int {
+	r := make(map[int64]int, len(g.nodes))
+	for id, u := range g.nodes {
+		f := ForemostFrom(g, u, start, end)
+		r[id] = len(f.arrival) - 1
+	}
+	return r
+}
+
+// Closeness returns the temporal harmonic closeness centrality for nodes
+// in g, considering journeys that depart no earlier than start and arrive
+// no later than end.
+//
+//  C(v) = \sum_{u ≠ v} 1 / h(v,u)
+//
+// where h(v,u) is the number of edges in the shortest journey from v to u.
+// Unreachable nodes are not considered.
+func Closeness(g *Graph, start, end float64) map[int64]float64 {
+	c := make(map[int64]float64, len(g.nodes))
+	for id, u := range g.nodes {
+		s := ShortestFrom(g, u, start, end)
+		var sum float64
+		for vid, h := range s.hops {
+			if vid == id {
+				continue
+			}
+			sum += 1 / float64(h)
+		}
+		c[id] = sum
+	}
+	return c
+}
@@ -0,0 +1,49 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestDegrees(t *testing.T) {
+	g := newGraph(journeyGraph)
+	got := Degrees(g, 4, 5)
+	want := map[int64]int{0: 1, 1: 1, 2: 2, 3: 3, 4: 0, 5: 2, 6: 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected degrees: got:%v want:%v", got, want)
+	}
+}
+
+func TestReach(t *testing.T) {
+	g := newGraph(journeyGraph)
+	got := Reach(g, 0, 10)
+	want := map[int64]int{0: 5, 1: 5, 2: 3, 3: 2, 4: 0, 5: 1, 6: 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected reach: got:%v want:%v", got, want)
+	}
+}
+
+func TestCloseness(t *testing.T) {
+	g := newGraph(journeyGraph)
+	got := Closeness(g, 0, 10)
+	want := map[int64]float64{
+		0: 1 + 1.0/2 + 1 + 1.0/4 + 1.0/5,
+		1: 1 + 1 + 1.0/2 + 1.0/3 + 1.0/4,
+		2: 1 + 1.0/2 + 1.0/3,
+		3: 1 + 1.0/2,
+		4: 0,
+		5: 1,
+		6: 0,
+	}
+	for id, w := range want {
+		if !floats.EqualWithinAbsOrRel(got[id], w, 1e-12, 1e-12) {
+			t.Errorf("unexpected closeness for %d: got:%v want:%v", id, got[id], w)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Foremost is a tree of earliest arrival journeys created by ForemostFrom.
+type Foremost struct {
+	from graph.Node
+
+	start, end float64
+
+	arrival map[int64]float64
+	via     map[int64]Edge
+}
+
+// ForemostFrom returns the earliest arrival journeys from u that depart no
+// earlier than start and arrive no later than end. The time complexity of
+// ForemostFrom is O(|E|.log|E|).
+func ForemostFrom(g *Graph, u graph.Node, start, end float64) Foremost {
+	f := Foremost{
+		from:    u,
+		start:   start,
+		end:     end,
+		arrival: make(map[int64]float64),
+		via:     make(map[int64]Edge),
+	}
+	if g.Node(u.ID()) == nil || end < start {
+		return f
+	}
+
+	f.arrival[u.ID()] = start
+	done := make(map[int64]bool)
+	q := arrivalQueue{{id: u.ID(), time: start}}
+	for q.Len() != 0 {
+		mid := heap.Pop(&q).(arrival)
+		if done[mid.id] {
+			continue
+		}
+		done[mid.id] = true
+		for _, e := range g.departing(mid.id, mid.time, end) {
+			if e.End > end {
+				continue
+			}
+			tid := e.T.ID()
+			if t, ok := f.arrival[tid]; ok && t <= e.End {
+				continue
+			}
+			f.arrival[tid] = e.End
+			f.via[tid] = e
+			heap.Push(&q, arrival{id: tid, time: e.End})
+		}
+	}
+	return f
+}
+
+// From returns the starting node of the journeys held by the Foremost.
+func (f Foremost) From() graph.Node { return f.from }
+
+// ArrivalTime returns the earliest arrival time at the node with ID vid.
+// If the node is not reachable, ArrivalTime returns +Inf.
+func (f Foremost) ArrivalTime(vid int64) float64 {
+	t, ok := f.arrival[vid]
+	if !ok {
+		return math.Inf(1)
+	}
+	return t
+}
+
+// To returns an earliest arrival journey to the node with ID vid and the
+// time of arrival. If the node is not reachable, To returns a nil journey
+// and +Inf. The journey to the starting node is empty.
+func (f Foremost) To(vid int64) (journey []Edge, arrival float64) {
+	arrival, ok := f.arrival[vid]
+	if !ok {
+		return nil, math.Inf(1)
+	}
+	for vid != f.from.ID() {
+		e := f.via[vid]
+		journey = append(journey, e)
+		vid = e.F.ID()
+	}
+	reverse(journey)
+	if journey == nil {
+		journey = []Edge{}
+	}
+	return journey, arrival
+}
+
+// Reachable returns the nodes reachable from the starting node, including
+// the starting node, ordered by ID.
+func (f Foremost) Reachable() []graph.Node {
+	var nodes []graph.Node
+	for id := range f.arrival {
+		if id == f.from.ID() {
+			nodes = append(nodes, f.from)
+			continue
+		}
+		nodes = append(nodes, f.via[id].T)
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return nodes
+}
+
+// arrival is a node arrival time.
+type arrival struct {
+	id   int64
+	time float64
+}
+
+// arrivalQueue is a priority queue of node arrivals.
+type arrivalQueue []arrival
+
+func (q arrivalQueue) Len() int            { return len(q) }
+func (q arrivalQueue) Less(i, j int) bool  { return q[i].time < q[j].time }
+func (q arrivalQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *arrivalQueue) Push(n interface{}) { *q = append(*q, n.(arrival)) }
+func (q *arrivalQueue) Pop() interface{} {
+	t := *q
+	var n arrival
+	n, *q = t[len(t)-1], t[:len(t)-1]
+	return n
+}
+
+// Shortest is a tree of fewest hop journeys created by ShortestFrom.
+type Shortest struct {
+	from graph.Node
+
+	start, end float64
+
+	// hops holds the number of edges in the
+	// shortest journeys to each reachable node.
+	hops map[int64]int
+
+	// levels holds the earliest arrival time
+	// and the arriving edge for the nodes
+	// improved with each journey length.
+	levels []map[int64]arrivalEdge
+}
+
+// arrivalEdge is an edge arrival time.
+type arrivalEdge struct {
+	edge Edge
+	time float64
+}
+
+// ShortestFrom returns the journeys from u with the fewest edges that
+// depart no earlier than start and arrive no later than end. Among the
+// journeys with the fewest edges to a node, the journey with the earliest
+// arrival is retained.
+func ShortestFrom(g *Graph, u graph.Node, start, end float64) Shortest {
+	s := Shortest{
+		from:  u,
+		start: start,
+		end:   end,
+		hops:  make(map[int64]int),
+	}
+	if g.Node(u.ID()) == nil || end < start {
+		return s
+	}
+
+	uid := u.ID()
+	s.hops[uid] = 0
+	best := map[int64]float64{uid: start}
+	frontier := map[int64]arrivalEdge{uid: {time: start}}
+	s.levels = append(s.levels, frontier)
+	for len(frontier) != 0 {
+		next := make(map[int64]arrivalEdge)
+		for id, a := range frontier {
+			for _, e := range g.departing(id, a.time, end) {
+				if e.End > end {
+					continue
+				}
+				tid := e.T.ID()
+				if t, ok := best[tid]; ok && t <= e.End {
+					continue
+				}
+				if n, ok := next[tid]; ok && n.time <= e.End {
+					continue
+				}
+				next[tid] = arrivalEdge{edge: e, time: e.End}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		for id, a := range next {
+			best[id] = a.time
+			if _, ok := s.hops[id]; !ok {
+				s.hops[id] = len(s.levels)
+			}
+		}
+		s.levels = append(s.levels, next)
+		frontier = next
+	}
+	return s
+}
+
+// From returns the starting node of the journeys held by the Shortest.
+func (s Shortest) From() graph.Node { return s.from }
+
+// Hops returns the number of edges in the shortest journey to the node
+// with ID vid. If the node is not reachable, Hops returns -1.
+func (s Shortest) Hops(vid int64) int {
+	n, ok := s.hops[vid]
+	if !ok {
+		return -1
+	}
+	return n
+}
+
+// To returns a journey to the node with ID vid with the fewest edges and
+// its time of arrival. If the node is not reachable, To returns a nil
+// journey and +Inf. The journey to the starting node is empty.
+func (s Shortest) To(vid int64) (journey []Edge, arrival float64) {
+	n, ok := s.hops[vid]
+	if !ok {
+		return nil, math.Inf(1)
+	}
+	arrival = s.levels[n][vid].time
+	journey = make([]Edge, n)
+	for i := n; i > 0; i-- {
+		e := s.levels[i][vid].edge
+		journey[i-1] = e
+		vid = e.F.ID()
+	}
+	return journey, arrival
+}
+
+func reverse(e []Edge) {
+	for i, j := 0, len(e)-1; i < j; i, j = i+1, j-1 {
+		e[i], e[j] = e[j], e[i]
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// journeyGraph has a direct slow journey 0->3 and faster multi-hop
+// journeys. The edge 1->2 departs before 0->1 arrives at 1 and so
+// can not be used on journeys from 0.
+var journeyGraph = []edge{
+	{f: 0, t: 1, start: 1, end: 2},
+	{f: 1, t: 2, start: 1, end: 1.5},
+	{f: 1, t: 2, start: 3, end: 4},
+	{f: 2, t: 3, start: 4, end: 5},
+	{f: 0, t: 3, start: 0, end: 9},
+	{f: 1, t: 4, start: 0, end: 1},
+	{f: 3, t: 5, start: 5, end: 5},
+	{f: 5, t: 6, start: 5, end: 5},
+}
+
+func journeyIDs(journey []Edge) [][2]int64 {
+	if journey == nil {
+		return nil
+	}
+	ids := make([][2]int64, len(journey))
+	for i, e := range journey {
+		ids[i] = [2]int64{e.F.ID(), e.T.ID()}
+	}
+	return ids
+}
+
+var foremostTests = []struct {
+	start, end float64
+	want       map[int64]float64
+}{
+	{
+		start: 0, end: 10,
+		want: map[int64]float64{0: 0, 1: 2, 2: 4, 3: 5, 5: 5, 6: 5},
+	},
+	{
+		start: 0, end: 4.5,
+		want: map[int64]float64{0: 0, 1: 2, 2: 4},
+	},
+	{
+		start: 0.5, end: 10,
+		want: map[int64]float64{0: 0.5, 1: 2, 2: 4, 3: 5, 5: 5, 6: 5},
+	},
+	{
+		start: 1.5, end: 10,
+		want: map[int64]float64{0: 1.5},
+	},
+}
+
+func TestForemostFrom(t *testing.T) {
+	g := newGraph(journeyGraph)
+	for _, test := range foremostTests {
+		f := ForemostFrom(g, simple.Node(0), test.start, test.end)
+		var reached []int64
+		for id := int64(0); id < 7; id++ {
+			want, ok := test.want[id]
+			if !ok {
+				want = math.Inf(1)
+			} else {
+				reached = append(reached, id)
+			}
+			if got := f.ArrivalTime(id); got != want {
+				t.Errorf("unexpected arrival time at %d in [%v, %v]: got:%v want:%v", id, test.start, test.end, got, want)
+			}
+			journey, arrival := f.To(id)
+			if arrival != want {
+				t.Errorf("unexpected journey arrival at %d in [%v, %v]: got:%v want:%v", id, test.start, test.end, arrival, want)
+			}
+			checkJourney(t, journey, 0, id, test.start, test.end, ok)
+		}
+		if got := nodeIDs(f.Reachable()); !reflect.DeepEqual(got, reached) {
+			t.Errorf("unexpected reachable nodes in [%v, %v]: got:%v want:%v", test.start, test.end, got, reached)
+		}
+	}
+}
+
+func TestShortestFrom(t *testing.T) {
+	g := newGraph(journeyGraph)
+	s := ShortestFrom(g, simple.Node(0), 0, 10)
+	for _, test := range []struct {
+		id      int64
+		hops    int
+		journey [][2]int64
+		arrival float64
+	}{
+		{id: 0, hops: 0, journey: [][2]int64{}, arrival: 0},
+		{id: 1, hops: 1, journey: [][2]int64{{0, 1}}, arrival: 2},
+		{id: 2, hops: 2, journey: [][2]int64{{0, 1}, {1, 2}}, arrival: 4},
+		{id: 3, hops: 1, journey: [][2]int64{{0, 3}}, arrival: 9},
+		{id: 4, hops: -1, arrival: math.Inf(1)},
+		{id: 5, hops: 4, journey: [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 5}}, arrival: 5},
+		{id: 6, hops: 5, journey: [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 5}, {5, 6}}, arrival: 5},
+	} {
+		if got := s.Hops(test.id); got != test.hops {
+			t.Errorf("unexpected hops to %d: got:%d want:%d", test.id, got, test.hops)
+		}
+		journey, arrival := s.To(test.id)
+		if got := journeyIDs(journey); !reflect.DeepEqual(got, test.journey) {
+			t.Errorf("unexpected journey to %d: got:%v want:%v", test.id, got, test.journey)
+		}
+		if arrival != test.arrival {
+			t.Errorf("unexpected arrival at %d: got:%v want:%v", test.id, arrival, test.arrival)
+		}
+		checkJourney(t, journey, 0, test.id, 0, 10, test.hops >= 0)
+	}
+}
+
+// checkJourney checks that journey is a time respecting journey from
+// uid to vid within [start, end].
+func checkJourney(t *testing.T, journey []Edge, uid, vid int64, start, end float64, reachable bool) {
+	t.Helper()
+	if !reachable {
+		if journey != nil {
+			t.Errorf("unexpected journey to unreachable node %d: %v", vid, journey)
+		}
+		return
+	}
+	at := uid
+	time := start
+	for _, e := range journey {
+		if e.F.ID() != at {
+			t.Errorf("journey to %d is not connected: %v", vid, journey)
+			return
+		}
+		if e.Start < time || e.End > end {
+			t.Errorf("journey to %d is not time respecting: %v", vid, journey)
+			return
+		}
+		at = e.T.ID()
+		time = e.End
+	}
+	if at != vid {
+		t.Errorf("journey does not end at %d: %v", vid, journey)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/iterator"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Snapshot is a static view of a temporal graph over a window of time.
+// Two nodes are joined in the snapshot if a temporal edge between them
+// is present at any time in the window. The weight of an edge is the
+// number of temporal edges it aggregates.
+//
+// Snapshot implements graph.WeightedDirected. All nodes of the temporal
+// graph are present in the snapshot.
+type Snapshot struct {
+	g *Graph
+
+	from, to float64
+
+	out map[int64]map[int64]int
+	in  map[int64]map[int64]int
+}
+
+// Snapshot returns a snapshot of g over the closed interval [from, to].
+// Nodes added to g after the snapshot is taken are visible in the
+// snapshot, but edges are not.
+func (g *Graph) Snapshot(from, to float64) *Snapshot {
+	s := &Snapshot{
+		g:    g,
+		from: from,
+		to:   to,
+		out:  make(map[int64]map[int64]int),
+		in:   make(map[int64]map[int64]int),
+	}
+	for _, e := range g.edges {
+		if !e.overlaps(from, to) {
+			continue
+		}
+		fid := e.F.ID()
+		tid := e.T.ID()
+		if s.out[fid] == nil {
+			s.out[fid] = make(map[int64]int)
+		}
+		s.out[fid][tid]++
+		if s.in[tid] == nil {
+			s.in[tid] = make(map[int64]int)
+		}
+		s.in[tid][fid]++
+	}
+	return s
+}
+
+// Window returns the closed interval of time covered by the snapshot.
+func (s *Snapshot) Window() (from, to float64) {
+	return s.from, s.to
+}
+
+// Node returns the node with the given ID if it exists in the graph,
+// and nil otherwise.
+func (s *Snapshot) Node(id int64) graph.Node {
+	return s.g.Node(id)
+}
+
+// Nodes returns all the nodes in the graph.
+func (s *Snapshot) Nodes() graph.Nodes {
+	return s.g.Nodes()
+}
+
+// From returns all nodes in s that can be reached directly from the node
+// with the given ID.
+func (s *Snapshot) From(id int64) graph.Nodes {
+	return s.nodesOf(s.out[id])
+}
+
+// To returns all nodes in s that can reach directly to the node with the
+// given ID.
+func (s *Snapshot) To(id int64) graph.Nodes {
+	return s.nodesOf(s.in[id])
+}
+
+func (s *Snapshot) nodesOf(ids map[int64]int) graph.Nodes {
+	if len(ids) == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, 0, len(ids))
+	for id := range ids {
+		nodes = append(nodes, s.g.Node(id))
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid without considering direction.
+func (s *Snapshot) HasEdgeBetween(xid, yid int64) bool {
+	return s.HasEdgeFromTo(xid, yid) || s.HasEdgeFromTo(yid, xid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the graph from u to v
+// with IDs uid and vid.
+func (s *Snapshot) HasEdgeFromTo(uid, vid int64) bool {
+	return s.out[uid][vid] != 0
+}
+
+// Edge returns the edge from u to v if such an edge exists and nil
+// otherwise. The node v must be directly reachable from u as defined
+// by the From method.
+func (s *Snapshot) Edge(uid, vid int64) graph.Edge {
+	return s.WeightedEdge(uid, vid)
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// and nil otherwise. The node v must be directly reachable from u as defined
+// by the From method. The weight of the edge is the number of temporal edges
+// from u to v present in the snapshot's window.
+func (s *Snapshot) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	n := s.out[uid][vid]
+	if n == 0 {
+		return nil
+	}
+	return simple.WeightedEdge{F: s.g.Node(uid), T: s.g.Node(vid), W: float64(n)}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(xid, yid)
+// returns a non-nil Edge. If x and y are the same node or there is no
+// joining edge between the two nodes the weight value returned is zero.
+// Weight returns true if an edge exists between x and y or if x and y have
+// the same ID, false otherwise.
+func (s *Snapshot) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		return 0, true
+	}
+	n := s.out[xid][yid]
+	return float64(n), n != 0
+}
@@ -0,0 +1,193 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/iterator"
+)
+
+// Edge is a temporal graph edge. The edge departs F at Start and arrives
+// at T at End. The edge is present in the graph over the closed interval
+// [Start, End].
+type Edge struct {
+	F, T       graph.Node
+	Start, End float64
+}
+
+// From returns the from-node of the edge.
+func (e Edge) From() graph.Node { return e.F }
+
+// To returns the to-node of the edge.
+func (e Edge) To() graph.Node { return e.T }
+
+// ReversedEdge returns a new Edge with the F and T fields swapped.
+// The interval of the new Edge is the same as the interval of the
+// receiver.
+func (e Edge) ReversedEdge() graph.Edge { return Edge{F: e.T, T: e.F, Start: e.Start, End: e.End} }
+
+// overlaps returns whether the edge is present during the closed
+// interval [from, to].
+func (e Edge) overlaps(from, to float64) bool {
+	return e.Start <= to && from <= e.End
+}
+
+// Graph is a directed temporal graph. Multiple temporal edges may
+// join the same pair of nodes.
+type Graph struct {
+	nodes map[int64]graph.Node
+	from  map[int64][]Edge
+	to    map[int64][]Edge
+	edges []Edge
+
+	// sorted indicates whether the
+	// edge lists are ordered by start
+	// time.
+	sorted bool
+}
+
+// NewGraph returns an empty temporal graph.
+func NewGraph() *Graph {
+	return &Graph{
+		nodes:  make(map[int64]graph.Node),
+		from:   make(map[int64][]Edge),
+		to:     make(map[int64][]Edge),
+		sorted: true,
+	}
+}
+
+// AddNode adds n to the graph. It panics if the added node ID matches an
+// existing node ID.
+func (g *Graph) AddNode(n graph.Node) {
+	if _, exists := g.nodes[n.ID()]; exists {
+		panic(fmt.Sprintf("temporal: node ID collision: %d", n.ID()))
+	}
+	g.nodes[n.ID()] = n
+}
+
+// AddEdge adds e to the graph, adding the end points of e if they do not
+// exist. It will panic if the IDs of e.F and e.T are equal, or if e.End
+// is before e.Start or either is NaN.
+func (g *Graph) AddEdge(e Edge) {
+	fid := e.F.ID()
+	tid := e.T.ID()
+	if fid == tid {
+		panic("temporal: adding self edge")
+	}
+	if !(e.Start <= e.End) {
+		panic("temporal: edge ends before it starts")
+	}
+	if _, ok := g.nodes[fid]; !ok {
+		g.AddNode(e.F)
+	} else {
+		e.F = g.nodes[fid]
+	}
+	if _, ok := g.nodes[tid]; !ok {
+		g.AddNode(e.T)
+	} else {
+		e.T = g.nodes[tid]
+	}
+
+	g.from[fid] = append(g.from[fid], e)
+	g.to[tid] = append(g.to[tid], e)
+	g.edges = append(g.edges, e)
+	g.sorted = false
+}
+
+// Node returns the node with the given ID if it exists in the graph,
+// and nil otherwise.
+func (g *Graph) Node(id int64) graph.Node {
+	return g.nodes[id]
+}
+
+// Nodes returns all the nodes in the graph ordered by ID.
+func (g *Graph) Nodes() graph.Nodes {
+	if len(g.nodes) == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, 0, len(g.nodes))
+	for _, n := range g.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// Edges returns all the temporal edges in the graph ordered by start
+// time and then end time.
+func (g *Graph) Edges() []Edge {
+	g.sort()
+	return append([]Edge(nil), g.edges...)
+}
+
+// EdgesFrom returns the temporal edges leaving the node with the
+// given ID ordered by start time and then end time.
+func (g *Graph) EdgesFrom(id int64) []Edge {
+	g.sort()
+	return append([]Edge(nil), g.from[id]...)
+}
+
+// EdgesTo returns the temporal edges arriving at the node with the
+// given ID ordered by start time and then end time.
+func (g *Graph) EdgesTo(id int64) []Edge {
+	g.sort()
+	return append([]Edge(nil), g.to[id]...)
+}
+
+// Span returns the earliest start time and the latest end time of
+// the temporal edges in the graph. If the graph has no edges, Span
+// returns +Inf and -Inf.
+func (g *Graph) Span() (start, end float64) {
+	start = math.Inf(1)
+	end = math.Inf(-1)
+	for _, e := range g.edges {
+		start = math.Min(start, e.Start)
+		end = math.Max(end, e.End)
+	}
+	return start, end
+}
+
+// sort orders the edge lists of g by start time and then end time.
+func (g *Graph) sort() {
+	if g.sorted {
+		return
+	}
+	sortEdges(g.edges)
+	for _, edges := range g.from {
+		sortEdges(edges)
+	}
+	for _, edges := range g.to {
+		sortEdges(edges)
+	}
+	g.sorted = true
+}
+
+func sortEdges(edges []Edge) {
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].Start != edges[j].Start {
+			return edges[i].Start < edges[j].Start
+		}
+		return edges[i].End < edges[j].End
+	})
+}
+
+// departing returns the temporal edges leaving the node with the given
+// ID that depart within the closed interval [from, to]. The returned
+// slice must not be modified.
+func (g *Graph) departing(id int64, from, to float64) []Edge {
+	g.sort()
+	edges := g.from[id]
+	lo := sort.Search(len(edges), func(i int) bool { return edges[i].Start >= from })
+	hi := sort.Search(len(edges), func(i int) bool { return edges[i].Start > to })
+	if hi < lo {
+		return nil
+	}
+	return edges[lo:hi]
+}
@@ -0,0 +1,136 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package temporal
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// edge is a temporal edge specification for tests.
+type edge struct {
+	f, t       int64
+	start, end float64
+}
+
+func newGraph(edges []edge) *Graph {
+	g := NewGraph()
+	for _, e := range edges {
+		g.AddEdge(Edge{F: simple.Node(e.f), T: simple.Node(e.t), Start: e.start, End: e.end})
+	}
+	return g
+}
+
+func nodeIDs(nodes []graph.Node) []int64 {
+	ids := make([]int64, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.ID()
+	}
+	return ids
+}
+
+func TestGraph(t *testing.T) {
+	g := newGraph([]edge{
+		{f: 0, t: 1, start: 3, end: 4},
+		{f: 0, t: 1, start: 1, end: 2},
+		{f: 1, t: 2, start: 2, end: 5},
+	})
+	g.AddNode(simple.Node(3))
+
+	if got := nodeIDs(graph.NodesOf(g.Nodes())); !reflect.DeepEqual(got, []int64{0, 1, 2, 3}) {
+		t.Errorf("unexpected nodes: got:%v", got)
+	}
+	edges := g.EdgesFrom(0)
+	if len(edges) != 2 || edges[0].Start != 1 || edges[1].Start != 3 {
+		t.Errorf("unexpected edges from 0: got:%v", edges)
+	}
+	if edges := g.EdgesTo(2); len(edges) != 1 || edges[0].F.ID() != 1 {
+		t.Errorf("unexpected edges to 2: got:%v", edges)
+	}
+	if start, end := g.Span(); start != 1 || end != 5 {
+		t.Errorf("unexpected span: got:[%v, %v] want:[1, 5]", start, end)
+	}
+
+	for _, test := range []struct {
+		name string
+		e    Edge
+	}{
+		{name: "self", e: Edge{F: simple.Node(0), T: simple.Node(0)}},
+		{name: "reversed", e: Edge{F: simple.Node(0), T: simple.Node(1), Start: 2, End: 1}},
+		{name: "NaN", e: Edge{F: simple.Node(0), T: simple.Node(1), Start: math.NaN(), End: 1}},
+	} {
+		panicked := func() (panicked bool) {
+			defer func() { panicked = recover() != nil }()
+			g.AddEdge(test.e)
+			return false
+		}()
+		if !panicked {
+			t.Errorf("expected panic adding %s edge", test.name)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	g := newGraph([]edge{
+		{f: 0, t: 1, start: 0, end: 1},
+		{f: 0, t: 1, start: 2, end: 3},
+		{f: 1, t: 2, start: 4, end: 6},
+		{f: 2, t: 0, start: 7, end: 8},
+	})
+
+	for _, test := range []struct {
+		from, to float64
+		want     map[[2]int64]float64
+	}{
+		{from: 0, to: 10, want: map[[2]int64]float64{{0, 1}: 2, {1, 2}: 1, {2, 0}: 1}},
+		{from: 1, to: 2, want: map[[2]int64]float64{{0, 1}: 2}},
+		{from: 5, to: 5, want: map[[2]int64]float64{{1, 2}: 1}},
+		{from: 9, to: 10, want: map[[2]int64]float64{}},
+	} {
+		s := g.Snapshot(test.from, test.to)
+		if s.Nodes().Len() != 3 {
+			t.Errorf("unexpected number of nodes in [%v, %v]: got:%d want:3", test.from, test.to, s.Nodes().Len())
+		}
+		got := make(map[[2]int64]float64)
+		nodes := s.Nodes()
+		for nodes.Next() {
+			uid := nodes.Node().ID()
+			to := s.From(uid)
+			for to.Next() {
+				vid := to.Node().ID()
+				w, ok := s.Weight(uid, vid)
+				if !ok {
+					t.Errorf("missing weight for edge %d->%d", uid, vid)
+				}
+				if !s.HasEdgeFromTo(uid, vid) || !s.HasEdgeBetween(vid, uid) {
+					t.Errorf("missing edge %d->%d", uid, vid)
+				}
+				if s.Edge(uid, vid) == nil {
+					t.Errorf("nil edge %d->%d", uid, vid)
+				}
+				got[[2]int64{uid, vid}] = w
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected snapshot edges in [%v, %v]: got:%v want:%v", test.from, test.to, got, test.want)
+		}
+		for e := range test.want {
+			var found bool
+			from := s.To(e[1])
+			for from.Next() {
+				if from.Node().ID() == e[0] {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("missing reverse adjacency for %d->%d", e[0], e[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// VisitMaximalCliques calls fn with each maximal clique of the undirected
+// graph g. The nodes of each clique are ordered by ID in a newly allocated
+// slice that fn may retain. If fn returns false, enumeration stops and no
+// further cliques are visited.
+//
+// The cliques are enumerated by up to workers goroutines. If workers is less
+// than one, runtime.GOMAXPROCS(0) goroutines are used. Calls to fn are
+// serialized, so fn does not need to be safe for concurrent use, but the
+// order in which cliques are visited is not specified.
+//
+// The algorithm used is the degeneracy ordered Bron–Kerbosch enumeration of
+// Eppstein, Löffler and Strash with Tomita pivoting, where each vertex in the
+// degeneracy ordering roots an independent subproblem. For a graph with
+// degeneracy d, each subproblem has at most d candidate vertices, which makes
+// the enumeration efficient for sparse graphs with dense local structure such
+// as social networks.
+func VisitMaximalCliques(g graph.Undirected, workers int, fn func(clique []graph.Node) bool) {
+	nodes := graph.NodesOf(g.Nodes())
+	if len(nodes) == 0 {
+		return
+	}
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	adj := make([][]int, len(nodes))
+	for i, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				continue
+			}
+			adj[i] = append(adj[i], indexOf[vid])
+		}
+		sort.Ints(adj[i])
+	}
+
+	// Each vertex has at most d neighbours that are
+	// later in the degeneracy ordering, so these form
+	// the candidate set of the vertex's subproblem.
+	order, _ := degeneracyOrdering(g)
+	rank := make([]int, len(nodes))
+	for i, n := range order {
+		rank[indexOf[n.ID()]] = i
+	}
+
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+
+	v := &cliqueVisitor{nodes: nodes, adj: adj, fn: fn}
+	work := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for u := range work {
+				var p, x []int
+				for _, w := range adj[u] {
+					if rank[w] > rank[u] {
+						p = append(p, w)
+					} else {
+						x = append(x, w)
+					}
+				}
+				v.expand([]int{u}, p, x)
+			}
+		}()
+	}
+	for _, n := range order {
+		if v.stopped() {
+			break
+		}
+		work <- indexOf[n.ID()]
+	}
+	close(work)
+	wg.Wait()
+}
+
+// cliqueVisitor holds the shared state of a parallel maximal
+// clique enumeration.
+type cliqueVisitor struct {
+	nodes []graph.Node
+	adj   [][]int
+
+	mu   sync.Mutex
+	fn   func([]graph.Node) bool
+	stop int32
+}
+
+func (v *cliqueVisitor) stopped() bool {
+	return atomic.LoadInt32(&v.stop) != 0
+}
+
+// expand enumerates the maximal cliques that extend r with vertices
+// in the candidate set p and none in the excluded set x. The sets p
+// and x are held as sorted slices of vertex indices.
+func (v *cliqueVisitor) expand(r, p, x []int) {
+	if v.stopped() {
+		return
+	}
+	if len(p) == 0 {
+		if len(x) == 0 {
+			v.visit(r)
+		}
+		return
+	}
+
+	// Choose the pivot u in p ⋃ x that maximises
+	// |p ⋂ N(u)| and branch only on p \ N(u).
+	pivot, max := -1, -1
+	for _, s := range [2][]int{p, x} {
+		for _, u := range s {
+			if c := intersectionLenInts(p, v.adj[u]); c > max {
+				pivot, max = u, c
+			}
+		}
+	}
+	branch := differenceInts(p, v.adj[pivot])
+
+	p = append([]int(nil), p...)
+	x = append([]int(nil), x...)
+	for _, u := range branch {
+		nu := v.adj[u]
+		v.expand(append(r[:len(r):len(r)], u), intersectionInts(p, nu), intersectionInts(x, nu))
+		if v.stopped() {
+			return
+		}
+		p = removeInt(p, u)
+		x = insertInt(x, u)
+	}
+}
+
+// visit calls the user function with the clique r unless the
+// enumeration has been stopped.
+func (v *cliqueVisitor) visit(r []int) {
+	clique := make([]graph.Node, len(r))
+	for i, u := range r {
+		clique[i] = v.nodes[u]
+	}
+	sort.Sort(ordered.ByID(clique))
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.stopped() {
+		return
+	}
+	if !v.fn(clique) {
+		atomic.StoreInt32(&v.stop, 1)
+	}
+}
+
+// intersectionLenInts returns the number of elements common to
+// the sorted slices a and b.
+func intersectionLenInts(a, b []int) int {
+	var n int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			n++
+			i++
+			j++
+		}
+	}
+	return n
+}
+
+// intersectionInts returns the elements common to the sorted slices
+// a and b.
+func intersectionInts(a, b []int) []int {
+	var c []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			c = append(c, a[i])
+			i++
+			j++
+		}
+	}
+	return c
+}
+
+// differenceInts returns the elements of the sorted slice a that are
+// not in the sorted slice b.
+func differenceInts(a, b []int) []int {
+	var c []int
+	j := 0
+	for _, e := range a {
+		for j < len(b) && b[j] < e {
+			j++
+		}
+		if j < len(b) && b[j] == e {
+			continue
+		}
+		c = append(c, e)
+	}
+	return c
+}
+
+// removeInt returns the sorted slice s with e removed.
+func removeInt(s []int, e int) []int {
+	i := sort.SearchInts(s, e)
+	if i < len(s) && s[i] == e {
+		s = append(s[:i], s[i+1:]...)
+	}
+	return s
+}
+
+// insertInt returns the sorted slice s with e inserted.
+func insertInt(s []int, e int) []int {
+	i := sort.SearchInts(s, e)
+	s = append(s, 0)
+	copy(s[i+1:], s[i:])
+	s[i] = e
+	return s
+}
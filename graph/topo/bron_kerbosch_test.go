@@ -5,11 +5,15 @@
 package topo
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 	"testing"
 
+	"golang.org/x/exp/rand"
+
 	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
 	"gonum.org/v1/gonum/graph/internal/ordered"
 	"gonum.org/v1/gonum/graph/simple"
 )
@@ -227,3 +231,95 @@ func BenchmarkBronKerbosch(b *testing.B) {
 		})
 	}
 }
+
+func TestVisitMaximalCliques(t *testing.T) {
+	for _, test := range bronKerboschTests {
+		g := simple.NewUndirectedGraph()
+		for u, e := range test.g {
+			// Add nodes that are not defined by an edge.
+			if g.Node(int64(u)) == nil {
+				g.AddNode(simple.Node(u))
+			}
+			for v := range e {
+				g.SetEdge(simple.Edge{F: simple.Node(u), T: simple.Node(v)})
+			}
+		}
+		for _, workers := range []int{0, 1, 4} {
+			var got [][]int64
+			VisitMaximalCliques(g, workers, func(c []graph.Node) bool {
+				ids := make([]int64, len(c))
+				for k, n := range c {
+					ids[k] = n.ID()
+				}
+				if !sort.IsSorted(ordered.Int64s(ids)) {
+					t.Errorf("clique not ordered by ID for test %q: %v", test.name, ids)
+				}
+				got = append(got, ids)
+				return true
+			})
+			sort.Sort(ordered.BySliceValues(got))
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected cliques for test %q with %d workers:\ngot: %v\nwant:%v", test.name, workers, got, test.want)
+			}
+
+			var n int
+			VisitMaximalCliques(g, workers, func([]graph.Node) bool {
+				n++
+				return n < 2
+			})
+			want := 2
+			if len(test.want) < want {
+				want = len(test.want)
+			}
+			if n != want {
+				t.Errorf("unexpected number of visits for test %q with %d workers after stopping: got:%d want:%d", test.name, workers, n, want)
+			}
+		}
+	}
+}
+
+func TestVisitMaximalCliquesGnp(t *testing.T) {
+	g := simple.NewUndirectedGraph()
+	gen.Gnp(g, 100, 0.3, rand.NewSource(1))
+
+	normalize := func(cliques [][]graph.Node) [][]int64 {
+		ids := make([][]int64, len(cliques))
+		for i, c := range cliques {
+			ids[i] = make([]int64, len(c))
+			for j, n := range c {
+				ids[i][j] = n.ID()
+			}
+			sort.Sort(ordered.Int64s(ids[i]))
+		}
+		sort.Sort(ordered.BySliceValues(ids))
+		return ids
+	}
+
+	var got [][]graph.Node
+	VisitMaximalCliques(g, 0, func(c []graph.Node) bool {
+		got = append(got, c)
+		return true
+	})
+	if want := normalize(BronKerbosch(g)); !reflect.DeepEqual(normalize(got), want) {
+		t.Errorf("unexpected cliques: got %d want %d", len(got), len(want))
+	}
+}
+
+func BenchmarkVisitMaximalCliques(b *testing.B) {
+	for _, n := range []int{100, 200} {
+		g := simple.NewUndirectedGraph()
+		gen.Gnp(g, n, 0.2, rand.NewSource(1))
+		for _, workers := range []int{1, 0} {
+			b.Run(fmt.Sprintf("n=%d/workers=%d", n, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					VisitMaximalCliques(g, workers, func([]graph.Node) bool { return true })
+				}
+			})
+		}
+		b.Run(fmt.Sprintf("n=%d/BronKerbosch", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				BronKerbosch(g)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/internal/set"
+	"gonum.org/v1/gonum/graph/iterator"
+)
+
+// Closure is an incrementally maintained transitive closure of a directed
+// graph. It answers reachability queries in constant time and supports the
+// insertion of nodes and edges without recomputing the closure.
+//
+// The closure is maintained using Italiano's approach: when an edge u→v is
+// inserted, the search for newly reachable nodes from each node that reaches
+// u is pruned at nodes that are already known to be reachable. The total
+// cost of building the closure of a graph with n nodes and m edges by any
+// sequence of insertions is O(nm), and the closure uses O(n²) space.
+type Closure struct {
+	nodes map[int64]graph.Node
+
+	// from holds the direct successors of each node.
+	from map[int64]set.Int64s
+
+	// desc and anc hold the nodes reachable from
+	// and reaching each node by paths of at least
+	// one edge.
+	desc map[int64]set.Int64s
+	anc  map[int64]set.Int64s
+}
+
+// NewClosure returns the transitive closure of g. If g is nil, the returned
+// closure is empty.
+func NewClosure(g graph.Directed) *Closure {
+	c := &Closure{
+		nodes: make(map[int64]graph.Node),
+		from:  make(map[int64]set.Int64s),
+		desc:  make(map[int64]set.Int64s),
+		anc:   make(map[int64]set.Int64s),
+	}
+	if g == nil {
+		return c
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		c.AddNode(u)
+	}
+	for _, u := range nodes {
+		to := graph.NodesOf(g.From(u.ID()))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			c.addEdge(u.ID(), v.ID())
+		}
+	}
+	return c
+}
+
+// AddNode adds n to the closure. It panics if the added node ID matches an
+// existing node ID.
+func (c *Closure) AddNode(n graph.Node) {
+	id := n.ID()
+	if _, exists := c.nodes[id]; exists {
+		panic("topo: node ID collision")
+	}
+	c.nodes[id] = n
+	c.from[id] = make(set.Int64s)
+	c.desc[id] = make(set.Int64s)
+	c.anc[id] = make(set.Int64s)
+}
+
+// Node returns the node with the given ID if it exists in the closure,
+// and nil otherwise.
+func (c *Closure) Node(id int64) graph.Node {
+	return c.nodes[id]
+}
+
+// Nodes returns all the nodes in the closure, ordered by ID.
+func (c *Closure) Nodes() graph.Nodes {
+	if len(c.nodes) == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, 0, len(c.nodes))
+	for _, n := range c.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// SetEdge inserts the edge e into the closure, adding the end points of
+// e if they are not already present, and updates the reachability of all
+// nodes affected by the insertion.
+func (c *Closure) SetEdge(e graph.Edge) {
+	from := e.From()
+	if c.nodes[from.ID()] == nil {
+		c.AddNode(from)
+	}
+	to := e.To()
+	if c.nodes[to.ID()] == nil {
+		c.AddNode(to)
+	}
+	c.addEdge(from.ID(), to.ID())
+}
+
+// addEdge inserts the edge uid→vid into the closure. Both nodes must
+// already be present.
+func (c *Closure) addEdge(uid, vid int64) {
+	if c.from[uid].Has(vid) {
+		return
+	}
+	c.from[uid].Add(vid)
+	if c.desc[uid].Has(vid) {
+		return
+	}
+
+	// The set of nodes reaching u may grow during
+	// the update when the edge completes a cycle,
+	// so take a copy before updating.
+	sources := make([]int64, 0, len(c.anc[uid])+1)
+	sources = append(sources, uid)
+	for xid := range c.anc[uid] {
+		if xid != uid {
+			sources = append(sources, xid)
+		}
+	}
+	var stack []int64
+	for _, xid := range sources {
+		dx := c.desc[xid]
+		if dx.Has(vid) {
+			continue
+		}
+		stack = append(stack[:0], vid)
+		for len(stack) != 0 {
+			wid := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if dx.Has(wid) {
+				continue
+			}
+			dx.Add(wid)
+			c.anc[wid].Add(xid)
+			for yid := range c.from[wid] {
+				if !dx.Has(yid) {
+					stack = append(stack, yid)
+				}
+			}
+		}
+	}
+}
+
+// Reachable returns whether there is a path from the node with ID uid to
+// the node with ID vid. As a special case, a node is reachable from itself
+// if it exists in the closure.
+func (c *Closure) Reachable(uid, vid int64) bool {
+	if uid == vid {
+		return c.nodes[uid] != nil
+	}
+	return c.desc[uid].Has(vid)
+}
+
+// Descendants returns the nodes reachable from the node with the given ID
+// by a path of at least one edge, ordered by ID. The node itself is included
+// only if it is on a cycle.
+func (c *Closure) Descendants(id int64) graph.Nodes {
+	return c.nodesOf(c.desc[id])
+}
+
+// Ancestors returns the nodes from which the node with the given ID is
+// reachable by a path of at least one edge, ordered by ID. The node itself
+// is included only if it is on a cycle.
+func (c *Closure) Ancestors(id int64) graph.Nodes {
+	return c.nodesOf(c.anc[id])
+}
+
+func (c *Closure) nodesOf(ids set.Int64s) graph.Nodes {
+	if len(ids) == 0 {
+		return graph.Empty
+	}
+	nodes := make([]graph.Node, 0, len(ids))
+	for id := range ids {
+		nodes = append(nodes, c.nodes[id])
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return iterator.NewOrderedNodes(nodes)
+}
@@ -0,0 +1,119 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestClosureIncremental(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		n     int
+		edges int
+		dag   bool
+	}{
+		{name: "dag", n: 20, edges: 60, dag: true},
+		{name: "cyclic", n: 20, edges: 40},
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		g := simple.NewDirectedGraph()
+		for i := 0; i < test.n; i++ {
+			g.AddNode(simple.Node(i))
+		}
+		c := NewClosure(g)
+		for k := 0; k < test.edges; k++ {
+			u := rnd.Intn(test.n)
+			v := rnd.Intn(test.n)
+			if u == v {
+				continue
+			}
+			if test.dag && u > v {
+				u, v = v, u
+			}
+			e := simple.Edge{F: simple.Node(u), T: simple.Node(v)}
+			g.SetEdge(e)
+			c.SetEdge(e)
+
+			for i := 0; i < test.n; i++ {
+				for j := 0; j < test.n; j++ {
+					want := PathExistsIn(g, simple.Node(i), simple.Node(j))
+					if got := c.Reachable(int64(i), int64(j)); got != want {
+						t.Fatalf("unexpected reachability for %s after inserting %d→%d: %d→%d got:%t want:%t",
+							test.name, u, v, i, j, got, want)
+					}
+				}
+			}
+		}
+
+		built := NewClosure(g)
+		for i := 0; i < test.n; i++ {
+			if got, want := ids(built.Descendants(int64(i))), ids(c.Descendants(int64(i))); !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected descendants of %d for %s: got:%v want:%v", i, test.name, got, want)
+			}
+			if got, want := ids(built.Ancestors(int64(i))), ids(c.Ancestors(int64(i))); !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected ancestors of %d for %s: got:%v want:%v", i, test.name, got, want)
+			}
+		}
+	}
+}
+
+func TestClosure(t *testing.T) {
+	c := NewClosure(nil)
+	c.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	c.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	c.AddNode(simple.Node(3))
+
+	if got, want := ids(c.Nodes()), []int64{0, 1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes: got:%v want:%v", got, want)
+	}
+	if got, want := ids(c.Descendants(0)), []int64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected descendants of 0: got:%v want:%v", got, want)
+	}
+	if got := ids(c.Descendants(3)); got != nil {
+		t.Errorf("unexpected descendants of 3: got:%v", got)
+	}
+	if !c.Reachable(3, 3) || c.Reachable(4, 4) {
+		t.Error("unexpected reflexive reachability")
+	}
+	if c.Reachable(2, 0) {
+		t.Error("unexpected reachability of 0 from 2")
+	}
+
+	// Closing the cycle makes every node on
+	// it reachable from every other.
+	c.SetEdge(simple.Edge{F: simple.Node(2), T: simple.Node(0)})
+	for _, id := range []int64{0, 1, 2} {
+		if got, want := ids(c.Descendants(id)), []int64{0, 1, 2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected descendants of %d: got:%v want:%v", id, got, want)
+		}
+		if got, want := ids(c.Ancestors(id)), []int64{0, 1, 2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected ancestors of %d: got:%v want:%v", id, got, want)
+		}
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		c.AddNode(simple.Node(1))
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for node ID collision")
+	}
+}
+
+func ids(it graph.Nodes) []int64 {
+	var ids []int64
+	for it.Next() {
+		ids = append(ids, it.Node().ID())
+	}
+	return ids
+}
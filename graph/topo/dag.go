@@ -0,0 +1,262 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Levels returns the longest-path layering of the directed acyclic graph g.
+// Nodes without predecessors are in the first level, and every other node is
+// in the level one after the deepest of its predecessors. Each level is an
+// antichain, so the nodes within a level can be processed in parallel once
+// all earlier levels are complete. Nodes within a level are ordered by ID.
+//
+// If g is not acyclic, Levels returns an Unorderable error as described for
+// Sort.
+func Levels(g graph.Directed) ([][]graph.Node, error) {
+	sorted, err := Sort(g)
+	if err != nil {
+		return nil, err
+	}
+	level := make(map[int64]int, len(sorted))
+	var levels [][]graph.Node
+	for _, u := range sorted {
+		uid := u.ID()
+		var l int
+		from := g.To(uid)
+		for from.Next() {
+			if pl := level[from.Node().ID()] + 1; pl > l {
+				l = pl
+			}
+		}
+		level[uid] = l
+		if l == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[l] = append(levels[l], u)
+	}
+	for _, l := range levels {
+		sort.Sort(ordered.ByID(l))
+	}
+	return levels, nil
+}
+
+// Schedule is a critical path schedule of tasks in a directed acyclic graph.
+type Schedule struct {
+	// Earliest and Latest hold the earliest
+	// and latest start times of each task,
+	// keyed by node ID, such that the
+	// schedule completes in the critical
+	// path length.
+	Earliest, Latest map[int64]float64
+
+	// Path is a critical path, a longest
+	// path through the graph weighted by
+	// the task durations.
+	Path []graph.Node
+
+	// Length is the total duration of the
+	// critical path.
+	Length float64
+}
+
+// Slack returns the amount of time the start of the task with the given ID
+// may be delayed without delaying completion of the schedule. Tasks on a
+// critical path have zero slack. If the task is not in the schedule Slack
+// returns NaN.
+func (s Schedule) Slack(id int64) float64 {
+	e, ok := s.Earliest[id]
+	if !ok {
+		return math.NaN()
+	}
+	return s.Latest[id] - e
+}
+
+// CriticalPath returns the critical path schedule of the tasks represented
+// by the nodes of the directed acyclic graph g, where an edge from u to v
+// indicates that u must complete before v starts. The duration of each task
+// is given by the duration function, which must return non-negative
+// values. If duration is nil, all tasks have unit duration.
+//
+// If g is not acyclic, CriticalPath returns an Unorderable error as
+// described for Sort.
+func CriticalPath(g graph.Directed, duration func(graph.Node) float64) (Schedule, error) {
+	sorted, err := Sort(g)
+	if err != nil {
+		return Schedule{}, err
+	}
+	if duration == nil {
+		duration = func(graph.Node) float64 { return 1 }
+	}
+
+	s := Schedule{
+		Earliest: make(map[int64]float64, len(sorted)),
+		Latest:   make(map[int64]float64, len(sorted)),
+	}
+	d := make(map[int64]float64, len(sorted))
+	prev := make(map[int64]graph.Node)
+	var last graph.Node
+	for _, u := range sorted {
+		uid := u.ID()
+		d[uid] = duration(u)
+		var start float64
+		from := g.To(uid)
+		for from.Next() {
+			p := from.Node()
+			pid := p.ID()
+			// Ties are broken by lowest node ID to
+			// make the critical path deterministic.
+			f := s.Earliest[pid] + d[pid]
+			if q := prev[uid]; q == nil || f > start || (f == start && pid < q.ID()) {
+				start = f
+				prev[uid] = p
+			}
+		}
+		s.Earliest[uid] = start
+		if f := start + d[uid]; last == nil || f > s.Length {
+			s.Length = f
+			last = u
+		}
+	}
+	for i := len(sorted) - 1; i >= 0; i-- {
+		u := sorted[i]
+		uid := u.ID()
+		finish := s.Length
+		to := g.From(uid)
+		for to.Next() {
+			if l := s.Latest[to.Node().ID()]; l < finish {
+				finish = l
+			}
+		}
+		s.Latest[uid] = finish - d[uid]
+	}
+
+	for n := last; n != nil; n = prev[n.ID()] {
+		s.Path = append(s.Path, n)
+	}
+	for i, j := 0, len(s.Path)-1; i < j; i, j = i+1, j-1 {
+		s.Path[i], s.Path[j] = s.Path[j], s.Path[i]
+	}
+	return s, nil
+}
+
+// MaximumAntichain returns a largest set of nodes of the directed acyclic
+// graph g where no node can reach another. The size of the antichain is the
+// maximum number of tasks that can be executed in parallel when edges
+// represent precedence constraints. The returned nodes are ordered by ID.
+//
+// MaximumAntichain uses Dilworth's theorem, finding a maximum matching in
+// the bipartite graph of the reachability relation of g. The time
+// complexity of MaximumAntichain is O(|V|.|E| + |V|^3).
+//
+// If g is not acyclic, MaximumAntichain returns an Unorderable error as
+// described for Sort.
+func MaximumAntichain(g graph.Directed) ([]graph.Node, error) {
+	sorted, err := Sort(g)
+	if err != nil {
+		return nil, err
+	}
+	n := len(sorted)
+	index := make(map[int64]int, n)
+	for i, u := range sorted {
+		index[u.ID()] = i
+	}
+
+	// reach[i] holds the nodes reachable from
+	// node i, excluding i, as indices into sorted.
+	reach := make([][]int, n)
+	seen := make([]int, n)
+	for i := range seen {
+		seen[i] = -1
+	}
+	for i := n - 1; i >= 0; i-- {
+		to := g.From(sorted[i].ID())
+		for to.Next() {
+			j := index[to.Node().ID()]
+			if seen[j] != i {
+				seen[j] = i
+				reach[i] = append(reach[i], j)
+			}
+			for _, k := range reach[j] {
+				if seen[k] != i {
+					seen[k] = i
+					reach[i] = append(reach[i], k)
+				}
+			}
+		}
+	}
+
+	// Find a maximum matching between the left
+	// and right copies of the nodes using
+	// augmenting paths.
+	matchL := make([]int, n)
+	matchR := make([]int, n)
+	for i := range matchL {
+		matchL[i] = -1
+		matchR[i] = -1
+	}
+	visited := make([]bool, n)
+	var augment func(int) bool
+	augment = func(u int) bool {
+		for _, v := range reach[u] {
+			if visited[v] {
+				continue
+			}
+			visited[v] = true
+			if matchR[v] < 0 || augment(matchR[v]) {
+				matchL[u] = v
+				matchR[v] = u
+				return true
+			}
+		}
+		return false
+	}
+	for u := range reach {
+		for i := range visited {
+			visited[i] = false
+		}
+		augment(u)
+	}
+
+	// By König's theorem a minimum vertex cover
+	// is given by the unvisited left nodes and
+	// the visited right nodes of alternating
+	// paths from unmatched left nodes. The nodes
+	// with neither copy in the cover form a
+	// maximum antichain.
+	visitedL := make([]bool, n)
+	visitedR := make([]bool, n)
+	var alternate func(int)
+	alternate = func(u int) {
+		visitedL[u] = true
+		for _, v := range reach[u] {
+			if visitedR[v] || matchL[u] == v {
+				continue
+			}
+			visitedR[v] = true
+			if w := matchR[v]; w >= 0 && !visitedL[w] {
+				alternate(w)
+			}
+		}
+	}
+	for u := range reach {
+		if matchL[u] < 0 {
+			alternate(u)
+		}
+	}
+	var antichain []graph.Node
+	for i, u := range sorted {
+		if visitedL[i] && !visitedR[i] {
+			antichain = append(antichain, u)
+		}
+	}
+	sort.Sort(ordered.ByID(antichain))
+	return antichain, nil
+}
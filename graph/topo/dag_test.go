@@ -0,0 +1,157 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// buildDAG is a task dependency graph with an isolated task.
+func buildDAG() *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{0, 2}, {1, 2}, {2, 3}, {2, 4}, {3, 5}, {4, 5}, {1, 7}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	g.AddNode(simple.Node(6))
+	return g
+}
+
+var buildDurations = map[int64]float64{0: 3, 1: 1, 2: 2, 3: 4, 4: 1, 5: 1, 6: 2, 7: 5}
+
+func cyclicDAG() *simple.DirectedGraph {
+	g := buildDAG()
+	g.SetEdge(simple.Edge{F: simple.Node(5), T: simple.Node(0)})
+	return g
+}
+
+func idsOfLevels(levels [][]graph.Node) [][]int64 {
+	var ids [][]int64
+	for _, l := range levels {
+		var lids []int64
+		for _, n := range l {
+			lids = append(lids, n.ID())
+		}
+		ids = append(ids, lids)
+	}
+	return ids
+}
+
+func TestLevels(t *testing.T) {
+	levels, err := Levels(buildDAG())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := [][]int64{{0, 1, 6}, {2, 7}, {3, 4}, {5}}
+	if got := idsOfLevels(levels); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected levels: got:%v want:%v", got, want)
+	}
+	if _, err := Levels(cyclicDAG()); err == nil {
+		t.Error("expected error for cyclic graph")
+	}
+	levels, err = Levels(simple.NewDirectedGraph())
+	if err != nil || levels != nil {
+		t.Errorf("unexpected levels for empty graph: got:%v err:%v", levels, err)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	s, err := CriticalPath(buildDAG(), func(n graph.Node) float64 { return buildDurations[n.ID()] })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Length != 10 {
+		t.Errorf("unexpected critical path length: got:%v want:10", s.Length)
+	}
+	var path []int64
+	for _, n := range s.Path {
+		path = append(path, n.ID())
+	}
+	if want := []int64{0, 2, 3, 5}; !reflect.DeepEqual(path, want) {
+		t.Errorf("unexpected critical path: got:%v want:%v", path, want)
+	}
+	wantEarliest := map[int64]float64{0: 0, 1: 0, 2: 3, 3: 5, 4: 5, 5: 9, 6: 0, 7: 1}
+	if !reflect.DeepEqual(s.Earliest, wantEarliest) {
+		t.Errorf("unexpected earliest start times: got:%v want:%v", s.Earliest, wantEarliest)
+	}
+	wantLatest := map[int64]float64{0: 0, 1: 2, 2: 3, 3: 5, 4: 8, 5: 9, 6: 8, 7: 5}
+	if !reflect.DeepEqual(s.Latest, wantLatest) {
+		t.Errorf("unexpected latest start times: got:%v want:%v", s.Latest, wantLatest)
+	}
+	for _, n := range s.Path {
+		if slack := s.Slack(n.ID()); slack != 0 {
+			t.Errorf("unexpected slack for critical task %d: got:%v", n.ID(), slack)
+		}
+	}
+	if slack := s.Slack(4); slack != 3 {
+		t.Errorf("unexpected slack for task 4: got:%v want:3", slack)
+	}
+	if !math.IsNaN(s.Slack(10)) {
+		t.Error("expected NaN slack for absent task")
+	}
+
+	s, err = CriticalPath(buildDAG(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Length != 4 {
+		t.Errorf("unexpected unit duration critical path length: got:%v want:4", s.Length)
+	}
+	if _, err := CriticalPath(cyclicDAG(), nil); err == nil {
+		t.Error("expected error for cyclic graph")
+	}
+}
+
+func TestMaximumAntichain(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		g    *simple.DirectedGraph
+		want int
+	}{
+		{name: "build", g: buildDAG(), want: 4},
+		{name: "empty", g: simple.NewDirectedGraph(), want: 0},
+		{name: "chain", g: chain(5), want: 1},
+		{name: "transitive", g: func() *simple.DirectedGraph {
+			// Chains may pass through nodes shared
+			// with other chains, so paths 0-2-3 and
+			// 1-2-4 cover the graph with two chains.
+			g := simple.NewDirectedGraph()
+			for _, e := range [][2]int64{{0, 2}, {1, 2}, {2, 3}, {2, 4}} {
+				g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+			}
+			return g
+		}(), want: 2},
+	} {
+		a, err := MaximumAntichain(test.g)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", test.name, err)
+		}
+		if len(a) != test.want {
+			t.Errorf("unexpected antichain size for %s: got:%d want:%d", test.name, len(a), test.want)
+		}
+		for i, u := range a {
+			for _, v := range a[i+1:] {
+				if PathExistsIn(test.g, u, v) || PathExistsIn(test.g, v, u) {
+					t.Errorf("antichain for %s has comparable nodes %d and %d", test.name, u.ID(), v.ID())
+				}
+			}
+		}
+	}
+	if _, err := MaximumAntichain(cyclicDAG()); err == nil {
+		t.Error("expected error for cyclic graph")
+	}
+}
+
+func chain(n int) *simple.DirectedGraph {
+	g := simple.NewDirectedGraph()
+	for i := 1; i < n; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i - 1), T: simple.Node(i)})
+	}
+	return g
+}
@@ -0,0 +1,293 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"container/heap"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// FeedbackArcSet is a feedback arc set of a directed graph. Removing
+// the arcs of the set from the graph leaves it acyclic.
+type FeedbackArcSet struct {
+	// Order is a linear ordering of the nodes
+	// of the graph that is a topological
+	// ordering of the graph with the arcs
+	// removed.
+	Order []graph.Node
+
+	// Arcs are the edges of the graph that
+	// go against Order, including any self
+	// edges, ordered by the IDs of their
+	// From and then To nodes.
+	Arcs []graph.Edge
+}
+
+// GreedyFeedbackArcSet returns a feedback arc set of the directed graph g
+// found using the greedy heuristic of Eades, Lin and Smyth. Sinks are placed
+// at the end of the ordering and sources at its start, and when neither
+// remain, the node whose out-degree most exceeds its in-degree is placed at
+// the start. Ties are broken by lowest node ID.
+//
+// See doi:10.1016/0020-0190(93)90079-O for details.
+func GreedyFeedbackArcSet(g graph.Directed) FeedbackArcSet {
+	f := newFeedback(g)
+	return f.result(g, f.eadesLinSmyth())
+}
+
+// SortFeedbackArcSet returns a feedback arc set of the directed graph g
+// found by refining the ordering of GreedyFeedbackArcSet with the insertion
+// sorting heuristic of Brandenburg and Hanauer. Each node in turn is moved
+// to the earlier position in the ordering that most reduces the number of
+// arcs against the ordering, and passes are repeated until no move reduces
+// the number of arcs. The returned set is never larger than the set returned
+// by GreedyFeedbackArcSet.
+//
+// The heuristic is described in Brandenburg and Hanauer, "Sorting Heuristics
+// for the Feedback Arc Set Problem", Technical Report MIP-1104, University of
+// Passau (2011).
+func SortFeedbackArcSet(g graph.Directed) FeedbackArcSet {
+	f := newFeedback(g)
+	order := f.eadesLinSmyth()
+	f.sort(order)
+	return f.result(g, order)
+}
+
+// feedback holds an indexed representation of a directed graph
+// for feedback arc set heuristics.
+type feedback struct {
+	nodes []graph.Node
+	from  [][]int
+	to    [][]int
+
+	// edges holds the set of edges
+	// between distinct nodes.
+	edges map[[2]int]bool
+}
+
+func newFeedback(g graph.Directed) *feedback {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	f := &feedback{
+		nodes: nodes,
+		from:  make([][]int, len(nodes)),
+		to:    make([][]int, len(nodes)),
+		edges: make(map[[2]int]bool),
+	}
+	for i, u := range nodes {
+		uid := u.ID()
+		it := g.From(uid)
+		for it.Next() {
+			vid := it.Node().ID()
+			if vid == uid {
+				continue
+			}
+			j := indexOf[vid]
+			f.from[i] = append(f.from[i], j)
+			f.to[j] = append(f.to[j], i)
+			f.edges[[2]int{i, j}] = true
+		}
+	}
+	for i := range nodes {
+		sort.Ints(f.from[i])
+		sort.Ints(f.to[i])
+	}
+	return f
+}
+
+// eadesLinSmyth returns the node ordering found by the Eades, Lin
+// and Smyth heuristic.
+func (f *feedback) eadesLinSmyth() []int {
+	n := len(f.nodes)
+	in := make([]int, n)
+	out := make([]int, n)
+	for i := range f.nodes {
+		in[i] = len(f.to[i])
+		out[i] = len(f.from[i])
+	}
+	removed := make([]bool, n)
+
+	// sinks and sources are min-priority queues of node
+	// indices, and delta is a max-priority queue of the
+	// remaining nodes by out-degree less in-degree. Stale
+	// entries are discarded when they are popped.
+	var sinks, sources intQueue
+	delta := make(deltaQueue, 0, n)
+	for i := range f.nodes {
+		switch {
+		case out[i] == 0:
+			heap.Push(&sinks, i)
+		case in[i] == 0:
+			heap.Push(&sources, i)
+		default:
+			delta = append(delta, deltaItem{node: i, delta: out[i] - in[i]})
+		}
+	}
+	heap.Init(&delta)
+
+	order := make([]int, 0, n)
+	var tail []int
+	remove := func(u int) {
+		removed[u] = true
+		for _, v := range f.from[u] {
+			if removed[v] {
+				continue
+			}
+			in[v]--
+			if in[v] == 0 {
+				heap.Push(&sources, v)
+			} else {
+				heap.Push(&delta, deltaItem{node: v, delta: out[v] - in[v]})
+			}
+		}
+		for _, v := range f.to[u] {
+			if removed[v] {
+				continue
+			}
+			out[v]--
+			if out[v] == 0 {
+				heap.Push(&sinks, v)
+			} else {
+				heap.Push(&delta, deltaItem{node: v, delta: out[v] - in[v]})
+			}
+		}
+	}
+	for len(order)+len(tail) < n {
+		if sinks.Len() != 0 {
+			u := heap.Pop(&sinks).(int)
+			if removed[u] {
+				continue
+			}
+			tail = append(tail, u)
+			remove(u)
+			continue
+		}
+		if sources.Len() != 0 {
+			u := heap.Pop(&sources).(int)
+			if removed[u] || out[u] == 0 {
+				// Nodes that have become isolated
+				// are also queued as sinks.
+				continue
+			}
+			order = append(order, u)
+			remove(u)
+			continue
+		}
+		it := heap.Pop(&delta).(deltaItem)
+		u := it.node
+		if removed[u] || it.delta != out[u]-in[u] || in[u] == 0 || out[u] == 0 {
+			continue
+		}
+		order = append(order, u)
+		remove(u)
+	}
+	for i := len(tail) - 1; i >= 0; i-- {
+		order = append(order, tail[i])
+	}
+	return order
+}
+
+// sort refines order in place by insertion sorting.
+func (f *feedback) sort(order []int) {
+	for improved := true; improved; {
+		improved = false
+		for i := 1; i < len(order); i++ {
+			v := order[i]
+			var val, best int
+			pos := i
+			for j := i - 1; j >= 0; j-- {
+				w := order[j]
+				if f.edges[[2]int{v, w}] {
+					val--
+				}
+				if f.edges[[2]int{w, v}] {
+					val++
+				}
+				if val < best {
+					best = val
+					pos = j
+				}
+			}
+			if pos < i {
+				copy(order[pos+1:i+1], order[pos:i])
+				order[pos] = v
+				improved = true
+			}
+		}
+	}
+}
+
+// result returns the feedback arc set of g corresponding to order.
+func (f *feedback) result(g graph.Directed, order []int) FeedbackArcSet {
+	pos := make([]int, len(f.nodes))
+	nodes := make([]graph.Node, len(order))
+	for i, u := range order {
+		pos[u] = i
+		nodes[i] = f.nodes[u]
+	}
+	var arcs []graph.Edge
+	for u, n := range f.nodes {
+		uid := n.ID()
+		if g.HasEdgeFromTo(uid, uid) {
+			arcs = append(arcs, g.Edge(uid, uid))
+		}
+		for _, v := range f.from[u] {
+			if pos[v] < pos[u] {
+				arcs = append(arcs, g.Edge(uid, f.nodes[v].ID()))
+			}
+		}
+	}
+	return FeedbackArcSet{Order: nodes, Arcs: arcs}
+}
+
+// intQueue is a min-priority queue of ints.
+type intQueue []int
+
+func (q intQueue) Len() int            { return len(q) }
+func (q intQueue) Less(i, j int) bool  { return q[i] < q[j] }
+func (q intQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *intQueue) Push(x interface{}) { *q = append(*q, x.(int)) }
+func (q *intQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	x := old[n]
+	*q = old[:n]
+	return x
+}
+
+// deltaItem is a node and the difference between its out-degree and
+// in-degree at insertion into a deltaQueue.
+type deltaItem struct {
+	node  int
+	delta int
+}
+
+// deltaQueue is a max-priority queue of nodes ordered by degree
+// difference and then by lowest node index.
+type deltaQueue []deltaItem
+
+func (q deltaQueue) Len() int { return len(q) }
+func (q deltaQueue) Less(i, j int) bool {
+	if q[i].delta != q[j].delta {
+		return q[i].delta > q[j].delta
+	}
+	return q[i].node < q[j].node
+}
+func (q deltaQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *deltaQueue) Push(x interface{}) { *q = append(*q, x.(deltaItem)) }
+func (q *deltaQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	x := old[n]
+	*q = old[:n]
+	return x
+}
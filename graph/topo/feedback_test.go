@@ -0,0 +1,141 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+var feedbackArcSetTests = []struct {
+	name  string
+	edges [][2]int64
+	want  int
+}{
+	{name: "dag", edges: [][2]int64{{0, 1}, {0, 2}, {1, 3}, {2, 3}}, want: 0},
+	{name: "triangle", edges: [][2]int64{{0, 1}, {1, 2}, {2, 0}}, want: 1},
+	{name: "two cycle", edges: [][2]int64{{0, 1}, {1, 0}}, want: 1},
+	{
+		// Two triangles sharing the edge 1→2.
+		name:  "shared",
+		edges: [][2]int64{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 1}},
+		want:  1,
+	},
+	{
+		// Disjoint cycles each need one arc.
+		name:  "disjoint",
+		edges: [][2]int64{{0, 1}, {1, 0}, {2, 3}, {3, 4}, {4, 2}, {5, 6}},
+		want:  2,
+	},
+}
+
+func TestFeedbackArcSet(t *testing.T) {
+	for _, test := range feedbackArcSetTests {
+		g := simple.NewDirectedGraph()
+		for _, e := range test.edges {
+			g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+		}
+		for _, fn := range []struct {
+			name string
+			fas  func(graph.Directed) FeedbackArcSet
+		}{
+			{name: "greedy", fas: GreedyFeedbackArcSet},
+			{name: "sort", fas: SortFeedbackArcSet},
+		} {
+			f := fn.fas(g)
+			if len(f.Arcs) != test.want {
+				t.Errorf("unexpected number of %s arcs for %s: got:%d want:%d", fn.name, test.name, len(f.Arcs), test.want)
+			}
+			checkFeedbackArcSet(t, test.name+" "+fn.name, g, f)
+		}
+	}
+}
+
+func TestFeedbackArcSetSelfEdge(t *testing.T) {
+	g := multi.NewDirectedGraph()
+	g.SetLine(g.NewLine(multi.Node(0), multi.Node(1)))
+	g.SetLine(g.NewLine(multi.Node(1), multi.Node(1)))
+	f := SortFeedbackArcSet(g)
+	var got [][2]int64
+	for _, e := range f.Arcs {
+		got = append(got, [2]int64{e.From().ID(), e.To().ID()})
+	}
+	if want := [][2]int64{{1, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected arcs: got:%v want:%v", got, want)
+	}
+}
+
+func TestFeedbackArcSetGnp(t *testing.T) {
+	for seed := uint64(1); seed <= 5; seed++ {
+		g := simple.NewDirectedGraph()
+		gen.Gnp(g, 50, 0.1, rand.NewSource(seed))
+		greedy := GreedyFeedbackArcSet(g)
+		checkFeedbackArcSet(t, "greedy gnp", g, greedy)
+		sorted := SortFeedbackArcSet(g)
+		checkFeedbackArcSet(t, "sort gnp", g, sorted)
+		if len(sorted.Arcs) > len(greedy.Arcs) {
+			t.Errorf("sorting heuristic worse than greedy for seed %d: got:%d greedy:%d", seed, len(sorted.Arcs), len(greedy.Arcs))
+		}
+	}
+}
+
+// checkFeedbackArcSet checks that f.Order holds all the nodes of g, that
+// f.Arcs are exactly the edges against f.Order and that removing them
+// leaves g acyclic.
+func checkFeedbackArcSet(t *testing.T, name string, g graph.Directed, f FeedbackArcSet) {
+	t.Helper()
+	if len(f.Order) != g.Nodes().Len() {
+		t.Errorf("unexpected order length for %s: got:%d want:%d", name, len(f.Order), g.Nodes().Len())
+	}
+	pos := make(map[int64]int)
+	for i, n := range f.Order {
+		pos[n.ID()] = i
+	}
+	arcs := make(map[[2]int64]bool)
+	for i, e := range f.Arcs {
+		uid, vid := e.From().ID(), e.To().ID()
+		if i > 0 {
+			pid, qid := f.Arcs[i-1].From().ID(), f.Arcs[i-1].To().ID()
+			if pid > uid || (pid == uid && qid >= vid) {
+				t.Errorf("arcs not ordered for %s", name)
+			}
+		}
+		if pos[uid] < pos[vid] {
+			t.Errorf("arc %d→%d does not go against order for %s", uid, vid, name)
+		}
+		arcs[[2]int64{uid, vid}] = true
+	}
+
+	acyclic := simple.NewDirectedGraph()
+	nodes := graph.NodesOf(g.Nodes())
+	for _, u := range nodes {
+		acyclic.AddNode(simple.Node(u.ID()))
+	}
+	for _, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if arcs[[2]int64{uid, vid}] {
+				continue
+			}
+			if pos[uid] >= pos[vid] {
+				t.Errorf("edge %d→%d against order missing from arcs for %s", uid, vid, name)
+				continue
+			}
+			acyclic.SetEdge(simple.Edge{F: simple.Node(uid), T: simple.Node(vid)})
+		}
+	}
+	if _, err := Sort(acyclic); err != nil {
+		t.Errorf("graph not acyclic after removing arcs for %s: %v", name, err)
+	}
+}
@@ -0,0 +1,449 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"container/heap"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// IndependentSet is an independent set of an undirected graph.
+type IndependentSet struct {
+	// Nodes holds the nodes of the
+	// independent set ordered by ID.
+	Nodes []graph.Node
+
+	// Bound is an upper bound on the
+	// size of a maximum independent set
+	// of the graph.
+	Bound int
+}
+
+// Gap returns the difference between the upper bound on the size of a
+// maximum independent set and the size of the independent set. A zero
+// gap shows that the independent set is maximum.
+func (s IndependentSet) Gap() int { return s.Bound - len(s.Nodes) }
+
+// VertexCover is a vertex cover of an undirected graph.
+type VertexCover struct {
+	// Nodes holds the nodes of the
+	// vertex cover ordered by ID.
+	Nodes []graph.Node
+
+	// Bound is a lower bound on the
+	// size of a minimum vertex cover
+	// of the graph.
+	Bound int
+}
+
+// Gap returns the difference between the size of the vertex cover and the
+// lower bound on the size of a minimum vertex cover. A zero gap shows that
+// the vertex cover is minimum.
+func (c VertexCover) Gap() int { return len(c.Nodes) - c.Bound }
+
+// MaximalIndependentSet returns a maximal independent set of the undirected
+// graph g, ordered by ID. The set is constructed greedily by repeatedly
+// adding a node of minimum degree in the graph remaining after removal of
+// the nodes already added and their neighbours. Nodes with self edges are
+// never included in the set.
+func MaximalIndependentSet(g graph.Undirected) []graph.Node {
+	s := newIndependence(g)
+	s.greedy()
+	return s.set(s.in)
+}
+
+// MaximumIndependentSet returns an approximation to a maximum independent
+// set of the undirected graph g. The search starts from the greedy set
+// found by MaximalIndependentSet and improves it by iterated local search
+// as described by Andrade, Resende and Werneck, performing the given number
+// of perturbation iterations. If src is nil, the global random source is
+// used for the perturbations.
+//
+// The returned Bound is the number of cliques in a greedy clique cover of
+// g, which no independent set can exceed.
+//
+// See doi:10.1007/s10732-012-9196-4 for details of the local search.
+func MaximumIndependentSet(g graph.Undirected, iterations int, src rand.Source) IndependentSet {
+	s := newIndependence(g)
+	s.greedy()
+	s.localSearch()
+	if iterations > 0 && len(s.adj) != 0 {
+		var (
+			intn    func(int) int
+			uniform func() float64
+		)
+		if src == nil {
+			intn = rand.Intn
+			uniform = rand.Float64
+		} else {
+			rnd := rand.New(src)
+			intn = rnd.Intn
+			uniform = rnd.Float64
+		}
+		s.iterate(iterations, intn, uniform)
+	}
+	return IndependentSet{Nodes: s.set(s.best), Bound: s.cliqueCoverBound()}
+}
+
+// MinimumVertexCover returns an approximation to a minimum vertex cover of
+// the undirected graph g. The cover is the complement of the independent
+// set returned by MaximumIndependentSet with the same parameters.
+//
+// The returned Bound is the larger of the size of a maximal matching of g
+// and the number of nodes in g less the upper bound on the size of a
+// maximum independent set.
+func MinimumVertexCover(g graph.Undirected, iterations int, src rand.Source) VertexCover {
+	is := MaximumIndependentSet(g, iterations, src)
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	cover := make([]graph.Node, 0, len(nodes)-len(is.Nodes))
+	i := 0
+	for _, n := range nodes {
+		if i < len(is.Nodes) && is.Nodes[i].ID() == n.ID() {
+			i++
+			continue
+		}
+		cover = append(cover, n)
+	}
+	bound := len(nodes) - is.Bound
+	if m := maximalMatchingSize(g, nodes); m > bound {
+		bound = m
+	}
+	return VertexCover{Nodes: cover, Bound: bound}
+}
+
+// maximalMatchingSize returns the size of a greedy maximal matching of g.
+func maximalMatchingSize(g graph.Undirected, nodes []graph.Node) int {
+	matched := make(map[int64]bool)
+	var n int
+	for _, u := range nodes {
+		uid := u.ID()
+		if matched[uid] {
+			continue
+		}
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if vid == uid || matched[vid] {
+				continue
+			}
+			matched[uid] = true
+			matched[vid] = true
+			n++
+			break
+		}
+	}
+	return n
+}
+
+// independence holds the state of an independent set search.
+type independence struct {
+	nodes []graph.Node
+	adj   [][]int
+
+	// blocked marks nodes with self edges.
+	blocked []bool
+
+	// in marks the nodes of the current solution
+	// and size is the number of nodes in it.
+	in   []bool
+	size int
+
+	// tight holds the number of neighbours of
+	// each node that are in the solution.
+	tight []int
+
+	best     []bool
+	bestSize int
+}
+
+func newIndependence(g graph.Undirected) *independence {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	indexOf := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		indexOf[n.ID()] = i
+	}
+	s := &independence{
+		nodes:   nodes,
+		adj:     make([][]int, len(nodes)),
+		blocked: make([]bool, len(nodes)),
+		in:      make([]bool, len(nodes)),
+		tight:   make([]int, len(nodes)),
+	}
+	for i, u := range nodes {
+		uid := u.ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid == uid {
+				s.blocked[i] = true
+				continue
+			}
+			s.adj[i] = append(s.adj[i], indexOf[vid])
+		}
+		sort.Ints(s.adj[i])
+	}
+	return s
+}
+
+// set returns the nodes marked by in.
+func (s *independence) set(in []bool) []graph.Node {
+	var nodes []graph.Node
+	for i, ok := range in {
+		if ok {
+			nodes = append(nodes, s.nodes[i])
+		}
+	}
+	return nodes
+}
+
+func (s *independence) insert(v int) {
+	s.in[v] = true
+	s.size++
+	for _, w := range s.adj[v] {
+		s.tight[w]++
+	}
+}
+
+func (s *independence) remove(v int) {
+	s.in[v] = false
+	s.size--
+	for _, w := range s.adj[v] {
+		s.tight[w]--
+	}
+}
+
+func (s *independence) isFree(v int) bool {
+	return !s.in[v] && !s.blocked[v] && s.tight[v] == 0
+}
+
+func (s *independence) adjacent(u, v int) bool {
+	i := sort.SearchInts(s.adj[u], v)
+	return i < len(s.adj[u]) && s.adj[u][i] == v
+}
+
+// greedy constructs a maximal independent set by repeatedly adding
+// a free node of minimum residual degree.
+func (s *independence) greedy() {
+	deg := make([]int, len(s.nodes))
+	h := make(degreeQueue, 0, len(s.nodes))
+	for v, a := range s.adj {
+		if s.blocked[v] {
+			continue
+		}
+		deg[v] = len(a)
+		h = append(h, degreeItem{node: v, degree: deg[v]})
+	}
+	heap.Init(&h)
+	removed := make([]bool, len(s.nodes))
+	for h.Len() != 0 {
+		it := heap.Pop(&h).(degreeItem)
+		v := it.node
+		if removed[v] || it.degree != deg[v] {
+			continue
+		}
+		s.insert(v)
+		removed[v] = true
+		for _, w := range s.adj[v] {
+			if removed[w] {
+				continue
+			}
+			removed[w] = true
+			for _, x := range s.adj[w] {
+				if removed[x] || s.blocked[x] {
+					continue
+				}
+				deg[x]--
+				heap.Push(&h, degreeItem{node: x, degree: deg[x]})
+			}
+		}
+	}
+	s.keepBest()
+}
+
+// localSearch improves the current solution by adding free nodes and
+// applying (1,2)-swaps that replace one node of the solution with two
+// of its neighbours until no improvement is possible.
+func (s *independence) localSearch() {
+	for improved := true; improved; {
+		improved = false
+		for v := range s.nodes {
+			if s.isFree(v) {
+				s.insert(v)
+			}
+		}
+		for x := range s.nodes {
+			if !s.in[x] {
+				continue
+			}
+			if s.twoImprove(x) {
+				improved = true
+			}
+		}
+	}
+	if s.size > s.bestSize {
+		s.keepBest()
+	}
+}
+
+// twoImprove attempts to replace the solution node x with two
+// non-adjacent neighbours of x that have no other neighbours in
+// the solution, reporting whether it succeeded.
+func (s *independence) twoImprove(x int) bool {
+	var cand []int
+	for _, v := range s.adj[x] {
+		if !s.blocked[v] && s.tight[v] == 1 {
+			cand = append(cand, v)
+		}
+	}
+	if len(cand) < 2 {
+		return false
+	}
+	for i, v := range cand {
+		for _, w := range cand[i+1:] {
+			if s.adjacent(v, w) {
+				continue
+			}
+			s.remove(x)
+			s.insert(v)
+			s.insert(w)
+			return true
+		}
+	}
+	return false
+}
+
+// iterate performs the given number of perturbation and local search
+// iterations. A perturbation forces a random node into the solution,
+// removing its neighbours. Worse solutions are accepted with a
+// probability that decreases with their distance from the current and
+// best solutions.
+func (s *independence) iterate(iterations int, intn func(int) int, uniform func() float64) {
+	var candidates []int
+	for v := range s.nodes {
+		if !s.blocked[v] {
+			candidates = append(candidates, v)
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	current := append([]bool(nil), s.in...)
+	currentSize := s.size
+	for i := 0; i < iterations; i++ {
+		k := 1
+		if intn(2*(s.size+1)) == 0 {
+			k++
+		}
+		for j := 0; j < k; j++ {
+			v := candidates[intn(len(candidates))]
+			if s.in[v] {
+				continue
+			}
+			for _, w := range s.adj[v] {
+				if s.in[w] {
+					s.remove(w)
+				}
+			}
+			s.insert(v)
+		}
+		s.localSearch()
+
+		if s.size >= currentSize {
+			copy(current, s.in)
+			currentSize = s.size
+			continue
+		}
+		delta := currentSize - s.size
+		deltaBest := s.bestSize - s.size
+		if uniform() < 1/float64(1+delta*deltaBest) {
+			copy(current, s.in)
+			currentSize = s.size
+			continue
+		}
+		s.reset(current)
+	}
+}
+
+// reset sets the current solution to in.
+func (s *independence) reset(in []bool) {
+	for v := range s.in {
+		s.in[v] = false
+		s.tight[v] = 0
+	}
+	s.size = 0
+	for v, ok := range in {
+		if ok {
+			s.insert(v)
+		}
+	}
+}
+
+func (s *independence) keepBest() {
+	s.best = append(s.best[:0], s.in...)
+	s.bestSize = s.size
+}
+
+// cliqueCoverBound returns the number of cliques in a greedy clique
+// cover of the graph, excluding nodes with self edges.
+func (s *independence) cliqueCoverBound() int {
+	var cliques [][]int
+	clique := make([]int, len(s.nodes))
+	for v := range s.nodes {
+		if s.blocked[v] {
+			continue
+		}
+		clique[v] = -1
+	outer:
+		for c, members := range cliques {
+			for _, u := range members {
+				if !s.adjacent(u, v) {
+					continue outer
+				}
+			}
+			clique[v] = c
+			cliques[c] = append(members, v)
+			break
+		}
+		if clique[v] < 0 {
+			cliques = append(cliques, []int{v})
+		}
+	}
+	return len(cliques)
+}
+
+// degreeItem is a node and its degree at insertion into a degreeQueue.
+type degreeItem struct {
+	node   int
+	degree int
+}
+
+// degreeQueue is a min-priority queue of nodes ordered by degree and
+// then by node index.
+type degreeQueue []degreeItem
+
+func (q degreeQueue) Len() int { return len(q) }
+func (q degreeQueue) Less(i, j int) bool {
+	if q[i].degree != q[j].degree {
+		return q[i].degree < q[j].degree
+	}
+	return q[i].node < q[j].node
+}
+func (q degreeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *degreeQueue) Push(x interface{}) { *q = append(*q, x.(degreeItem)) }
+func (q *degreeQueue) Pop() interface{} {
+	old := *q
+	n := len(old) - 1
+	x := old[n]
+	*q = old[:n]
+	return x
+}
@@ -0,0 +1,174 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package topo
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/graphs/gen"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func undirectedFrom(n int, edges [][2]int64) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < n; i++ {
+		g.AddNode(simple.Node(i))
+	}
+	for _, e := range edges {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	return g
+}
+
+var independentSetTests = []struct {
+	name  string
+	g     *simple.UndirectedGraph
+	alpha int
+}{
+	{name: "empty", g: simple.NewUndirectedGraph(), alpha: 0},
+	{name: "isolated", g: undirectedFrom(3, nil), alpha: 3},
+	{name: "C5", g: undirectedFrom(5, [][2]int64{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}}), alpha: 2},
+	{name: "star", g: undirectedFrom(6, [][2]int64{{0, 1}, {0, 2}, {0, 3}, {0, 4}, {0, 5}}), alpha: 5},
+	{name: "K4", g: undirectedFrom(4, [][2]int64{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}}), alpha: 1},
+	{name: "K3,4", g: undirectedFrom(7, [][2]int64{
+		{0, 3}, {0, 4}, {0, 5}, {0, 6},
+		{1, 3}, {1, 4}, {1, 5}, {1, 6},
+		{2, 3}, {2, 4}, {2, 5}, {2, 6},
+	}), alpha: 4},
+	{name: "petersen", g: undirectedFrom(10, [][2]int64{
+		{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0},
+		{0, 5}, {1, 6}, {2, 7}, {3, 8}, {4, 9},
+		{5, 7}, {7, 9}, {9, 6}, {6, 8}, {8, 5},
+	}), alpha: 4},
+}
+
+func TestMaximumIndependentSet(t *testing.T) {
+	for _, test := range independentSetTests {
+		greedy := MaximalIndependentSet(test.g)
+		checkIndependent(t, test.name, test.g, greedy)
+
+		s := MaximumIndependentSet(test.g, 100, rand.NewSource(1))
+		checkIndependent(t, test.name, test.g, s.Nodes)
+		if len(s.Nodes) != test.alpha {
+			t.Errorf("unexpected independent set size for %s: got:%d want:%d", test.name, len(s.Nodes), test.alpha)
+		}
+		if s.Bound < test.alpha || s.Gap() < 0 {
+			t.Errorf("invalid bound for %s: got:%d alpha:%d", test.name, s.Bound, test.alpha)
+		}
+
+		c := MinimumVertexCover(test.g, 100, rand.NewSource(1))
+		n := test.g.Nodes().Len()
+		if len(c.Nodes) != n-test.alpha {
+			t.Errorf("unexpected vertex cover size for %s: got:%d want:%d", test.name, len(c.Nodes), n-test.alpha)
+		}
+		if c.Bound > n-test.alpha || c.Gap() < 0 {
+			t.Errorf("invalid bound for %s: got:%d tau:%d", test.name, c.Bound, n-test.alpha)
+		}
+		cover := make(map[int64]bool)
+		for _, n := range c.Nodes {
+			cover[n.ID()] = true
+		}
+		edges := test.g.Edges()
+		for edges.Next() {
+			e := edges.Edge()
+			if !cover[e.From().ID()] && !cover[e.To().ID()] {
+				t.Errorf("edge %d--%d not covered for %s", e.From().ID(), e.To().ID(), test.name)
+			}
+		}
+	}
+}
+
+func TestMaximumIndependentSetSelfEdge(t *testing.T) {
+	g := multi.NewUndirectedGraph()
+	g.SetLine(g.NewLine(multi.Node(0), multi.Node(1)))
+	g.SetLine(g.NewLine(multi.Node(2), multi.Node(2)))
+	s := MaximumIndependentSet(g, 10, rand.NewSource(1))
+	if len(s.Nodes) != 1 || s.Nodes[0].ID() == 2 {
+		t.Errorf("unexpected independent set: got:%v", s.Nodes)
+	}
+}
+
+func TestMaximumIndependentSetGnp(t *testing.T) {
+	for seed := uint64(1); seed <= 5; seed++ {
+		g := simple.NewUndirectedGraph()
+		gen.Gnp(g, 40, 0.2, rand.NewSource(seed))
+		for i := 0; i < 40; i++ {
+			if g.Node(int64(i)) == nil {
+				g.AddNode(simple.Node(i))
+			}
+		}
+
+		// The maximum independent sets of g are the
+		// maximum cliques of its complement.
+		comp := simple.NewUndirectedGraph()
+		for i := 0; i < 40; i++ {
+			comp.AddNode(simple.Node(i))
+			for j := 0; j < i; j++ {
+				if !g.HasEdgeBetween(int64(i), int64(j)) {
+					comp.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(j)})
+				}
+			}
+		}
+		var alpha int
+		VisitMaximalCliques(comp, 1, func(c []graph.Node) bool {
+			if len(c) > alpha {
+				alpha = len(c)
+			}
+			return true
+		})
+
+		greedy := MaximalIndependentSet(g)
+		s := MaximumIndependentSet(g, 200, rand.NewSource(seed))
+		checkIndependent(t, "gnp", g, s.Nodes)
+		if len(s.Nodes) < len(greedy) {
+			t.Errorf("local search worse than greedy for seed %d: got:%d greedy:%d", seed, len(s.Nodes), len(greedy))
+		}
+		if len(s.Nodes) != alpha {
+			t.Errorf("unexpected independent set size for seed %d: got:%d want:%d", seed, len(s.Nodes), alpha)
+		}
+		if s.Bound < alpha {
+			t.Errorf("invalid bound for seed %d: got:%d alpha:%d", seed, s.Bound, alpha)
+		}
+	}
+}
+
+func checkIndependent(t *testing.T, name string, g graph.Undirected, nodes []graph.Node) {
+	t.Helper()
+	in := make(map[int64]bool)
+	for i, u := range nodes {
+		if i > 0 && nodes[i-1].ID() >= u.ID() {
+			t.Errorf("nodes not strictly ordered by ID for %s: %v", name, nodes)
+		}
+		in[u.ID()] = true
+	}
+	for _, u := range nodes {
+		for _, v := range nodes {
+			if g.HasEdgeBetween(u.ID(), v.ID()) {
+				t.Errorf("set not independent for %s: %d--%d", name, u.ID(), v.ID())
+			}
+		}
+	}
+	it := g.Nodes()
+	for it.Next() {
+		u := it.Node()
+		if in[u.ID()] || g.HasEdgeBetween(u.ID(), u.ID()) {
+			continue
+		}
+		free := true
+		for _, v := range nodes {
+			if g.HasEdgeBetween(u.ID(), v.ID()) {
+				free = false
+				break
+			}
+		}
+		if free {
+			t.Errorf("set not maximal for %s: %d can be added", name, u.ID())
+		}
+	}
+}
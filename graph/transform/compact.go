@@ -0,0 +1,183 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Compaction is a bijection between the node IDs of a graph and the
+// contiguous range of IDs [0, n) where n is the number of nodes in the
+// graph. Compacted IDs are ordered by the original node IDs.
+//
+// Graphs with contiguous node IDs can be stored in index-backed graph
+// types such as the matrix graphs in graph/simple and the graphs in
+// graph/csr, allowing algorithms to use slices in place of maps keyed
+// by node ID.
+type Compaction struct {
+	nodes []graph.Node
+	index map[int64]int
+}
+
+// NewCompaction returns a Compaction for the nodes of g.
+func NewCompaction(g graph.Graph) Compaction {
+	c := Compaction{nodes: graph.NodesOf(g.Nodes())}
+	sort.Sort(ordered.ByID(c.nodes))
+	c.index = indexOf(c.nodes)
+	return c
+}
+
+// Len returns the number of nodes in the Compaction.
+func (c Compaction) Len() int { return len(c.nodes) }
+
+// ID returns the compacted ID for the original node ID id, and whether the
+// node is held by the Compaction.
+func (c Compaction) ID(id int64) (compact int64, ok bool) {
+	i, ok := c.index[id]
+	return int64(i), ok
+}
+
+// Original returns the original node for the compacted ID compact, or nil
+// if compact is outside the range of the Compaction.
+func (c Compaction) Original(compact int64) graph.Node {
+	if compact < 0 || int64(len(c.nodes)) <= compact {
+		return nil
+	}
+	return c.nodes[compact]
+}
+
+// OriginalNodes returns the original nodes for the given nodes with
+// compacted IDs, such as a path found in a compacted graph. It will panic
+// if any node ID is outside the range of the Compaction.
+func (c Compaction) OriginalNodes(nodes []graph.Node) []graph.Node {
+	if nodes == nil {
+		return nil
+	}
+	orig := make([]graph.Node, len(nodes))
+	for i, n := range nodes {
+		orig[i] = c.original(n.ID())
+	}
+	return orig
+}
+
+// OriginalValues returns the values keyed by compacted node ID rekeyed by
+// the original node IDs, such as centrality scores of a compacted graph. It
+// will panic if any key is outside the range of the Compaction.
+func (c Compaction) OriginalValues(values map[int64]float64) map[int64]float64 {
+	orig := make(map[int64]float64, len(values))
+	for id, v := range values {
+		orig[c.original(id).ID()] = v
+	}
+	return orig
+}
+
+// OriginalVector returns the values indexed by compacted node ID keyed by the
+// original node IDs. It will panic if the length of x is not the length of
+// the Compaction.
+func (c Compaction) OriginalVector(x []float64) map[int64]float64 {
+	if len(x) != len(c.nodes) {
+		panic("transform: vector length mismatch")
+	}
+	orig := make(map[int64]float64, len(x))
+	for i, v := range x {
+		orig[c.nodes[i].ID()] = v
+	}
+	return orig
+}
+
+func (c Compaction) original(compact int64) graph.Node {
+	n := c.Original(compact)
+	if n == nil {
+		panic("transform: compacted ID out of range")
+	}
+	return n
+}
+
+// Compact adds a copy of g to dst where the nodes of g are relabeled to
+// contiguous IDs starting from zero. The nodes added to dst are simple.Node
+// values. Compact will panic if a compacted node ID matches a node ID in dst.
+func Compact(dst graph.Builder, g graph.Graph) Compaction {
+	c := NewCompaction(g)
+	nodes := c.addNodes(dst)
+	eachEdge(g, func(e graph.Edge) {
+		u, v := c.edgeNodes(nodes, e)
+		dst.SetEdge(dst.NewEdge(u, v))
+	})
+	return c
+}
+
+// WeightedCompact adds a copy of g to dst where the nodes of g are relabeled
+// to contiguous IDs starting from zero, retaining edge weights. The nodes added
+// to dst are simple.Node values. WeightedCompact will panic if a compacted node
+// ID matches a node ID in dst.
+func WeightedCompact(dst graph.WeightedBuilder, g graph.Weighted) Compaction {
+	c := NewCompaction(g)
+	nodes := c.addNodes(dst)
+	eachEdge(g, func(e graph.Edge) {
+		u, v := c.edgeNodes(nodes, e)
+		w := g.WeightedEdge(e.From().ID(), e.To().ID()).Weight()
+		dst.SetWeightedEdge(dst.NewWeightedEdge(u, v, w))
+	})
+	return c
+}
+
+// CompactDirectedMatrix returns a dense matrix representation of g with nodes
+// relabeled to contiguous IDs starting from zero, and the Compaction used.
+// Edge weights are taken from g if it is a graph.Weighted, and are one
+// otherwise. The self and absent parameters specify the weight of self
+// connection and absent edges in the returned graph.
+func CompactDirectedMatrix(g graph.Directed, self, absent float64) (*simple.DirectedMatrix, Compaction) {
+	c := NewCompaction(g)
+	m := simple.NewDirectedMatrix(c.Len(), absent, self, absent)
+	c.setMatrix(m, g)
+	return m, c
+}
+
+// CompactUndirectedMatrix returns a dense matrix representation of g with
+// nodes relabeled to contiguous IDs starting from zero, and the Compaction
+// used. Edge weights are taken from g if it is a graph.Weighted, and are one
+// otherwise. The self and absent parameters specify the weight of self
+// connection and absent edges in the returned graph.
+func CompactUndirectedMatrix(g graph.Undirected, self, absent float64) (*simple.UndirectedMatrix, Compaction) {
+	c := NewCompaction(g)
+	m := simple.NewUndirectedMatrix(c.Len(), absent, self, absent)
+	c.setMatrix(m, g)
+	return m, c
+}
+
+func (c Compaction) addNodes(dst graph.NodeAdder) []graph.Node {
+	nodes := make([]graph.Node, len(c.nodes))
+	for i := range c.nodes {
+		nodes[i] = simple.Node(i)
+		dst.AddNode(nodes[i])
+	}
+	return nodes
+}
+
+func (c Compaction) edgeNodes(nodes []graph.Node, e graph.Edge) (u, v graph.Node) {
+	return nodes[c.index[e.From().ID()]], nodes[c.index[e.To().ID()]]
+}
+
+func (c Compaction) setMatrix(m interface {
+	SetWeightedEdge(graph.WeightedEdge)
+}, g graph.Graph) {
+	wg, weighted := g.(graph.Weighted)
+	eachEdge(g, func(e graph.Edge) {
+		uid, vid := e.From().ID(), e.To().ID()
+		w := 1.0
+		if weighted {
+			w = wg.WeightedEdge(uid, vid).Weight()
+		}
+		m.SetWeightedEdge(simple.WeightedEdge{
+			F: simple.Node(c.index[uid]),
+			T: simple.Node(c.index[vid]),
+			W: w,
+		})
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// sparseWeighted returns a weighted directed graph with non-contiguous
+// node IDs.
+func sparseWeighted() *simple.WeightedDirectedGraph {
+	g := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(42), T: simple.Node(3), W: 1},
+		{F: simple.Node(3), T: simple.Node(10), W: 2},
+		{F: simple.Node(42), T: simple.Node(10), W: 5},
+		{F: simple.Node(10), T: simple.Node(7), W: 1},
+	} {
+		g.SetWeightedEdge(e)
+	}
+	g.AddNode(simple.Node(100))
+	return g
+}
+
+func TestCompaction(t *testing.T) {
+	g := sparseWeighted()
+	c := NewCompaction(g)
+	if c.Len() != 5 {
+		t.Errorf("unexpected length: got:%d want:5", c.Len())
+	}
+	for i, id := range []int64{3, 7, 10, 42, 100} {
+		got, ok := c.ID(id)
+		if !ok || got != int64(i) {
+			t.Errorf("unexpected compacted ID for %d: got:%d ok=%t want:%d", id, got, ok, i)
+		}
+		if n := c.Original(int64(i)); n == nil || n.ID() != id {
+			t.Errorf("unexpected original node for %d: got:%v want:%d", i, n, id)
+		}
+	}
+	if _, ok := c.ID(4); ok {
+		t.Error("unexpected compacted ID for absent node")
+	}
+	if c.Original(-1) != nil || c.Original(5) != nil {
+		t.Error("unexpected original node for out of range ID")
+	}
+
+	got := c.OriginalValues(map[int64]float64{0: 1, 4: 2})
+	if want := map[int64]float64{3: 1, 100: 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected original values: got:%v want:%v", got, want)
+	}
+	got = c.OriginalVector([]float64{0, 1, 2, 3, 4})
+	if want := map[int64]float64{3: 0, 7: 1, 10: 2, 42: 3, 100: 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected original vector: got:%v want:%v", got, want)
+	}
+
+	panicked := func() (panicked bool) {
+		defer func() { panicked = recover() != nil }()
+		c.OriginalNodes([]graph.Node{simple.Node(5)})
+		return false
+	}()
+	if !panicked {
+		t.Error("expected panic for out of range node")
+	}
+}
+
+func TestCompact(t *testing.T) {
+	g := sparseWeighted()
+
+	dst := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	c := WeightedCompact(dst, g)
+	if got := nodeIDs(dst); !reflect.DeepEqual(got, []int64{0, 1, 2, 3, 4}) {
+		t.Errorf("unexpected compacted nodes: got:%v", got)
+	}
+	want := map[[2]int64]float64{{3, 0}: 1, {0, 2}: 2, {3, 2}: 5, {2, 1}: 1}
+	if got := weightedEdges(dst); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected compacted edges: got:%v want:%v", got, want)
+	}
+
+	u := simple.NewDirectedGraph()
+	Compact(u, g)
+	if u.Edges().Len() != 4 || !u.HasEdgeFromTo(3, 0) || !u.HasEdgeFromTo(2, 1) {
+		t.Error("unexpected unweighted compacted edges")
+	}
+
+	m, c := CompactDirectedMatrix(g, 0, math.Inf(1))
+	if got := weightedEdges(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected compacted matrix edges: got:%v want:%v", got, want)
+	}
+	from, _ := c.ID(42)
+	p, w := path.DijkstraFrom(simple.Node(from), m).To(1)
+	var ids []int64
+	for _, n := range c.OriginalNodes(p) {
+		ids = append(ids, n.ID())
+	}
+	if !reflect.DeepEqual(ids, []int64{42, 3, 10, 7}) || w != 4 {
+		t.Errorf("unexpected path in compacted matrix: got:%v weight:%v want:[42 3 10 7] weight:4", ids, w)
+	}
+
+	ug := simple.NewUndirectedGraph()
+	ug.SetEdge(simple.Edge{F: simple.Node(-5), T: simple.Node(8)})
+	um, _ := CompactUndirectedMatrix(ug, 0, 0)
+	if got := weightedEdges(um); !reflect.DeepEqual(got, map[[2]int64]float64{{0, 1}: 1}) {
+		t.Errorf("unexpected compacted undirected matrix edges: got:%v", got)
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// Delta is a structural difference between two graphs.
+type Delta struct {
+	// Directed indicates whether the
+	// compared graphs are directed.
+	Directed bool
+
+	// AddedNodes and RemovedNodes hold
+	// the nodes added and removed, ordered
+	// by ID.
+	AddedNodes, RemovedNodes []graph.Node
+
+	// AddedEdges and RemovedEdges hold the
+	// edges added and removed, ordered by
+	// from and then to node ID. Edges of
+	// undirected graphs are held once with
+	// the lower node ID as the from node.
+	// Edges incident to removed nodes are
+	// included in RemovedEdges.
+	AddedEdges, RemovedEdges []graph.WeightedEdge
+
+	// Reweighted holds the edges present
+	// in both graphs with differing weights,
+	// ordered as for AddedEdges.
+	Reweighted []Reweighting
+}
+
+// Reweighting is a change in the weight of an edge.
+type Reweighting struct {
+	F, T     graph.Node
+	Old, New float64
+}
+
+// Diff returns the structural difference from the graph a to the graph b.
+// Nodes and edges are identified by node ID. Added and removed edges hold
+// their weight in the graph they are present in if that graph is a
+// graph.Weighted, and unit weight otherwise. Reweightings are reported only
+// when both a and b are graph.Weighted. Diff will panic if only one of a
+// and b is a graph.Directed.
+func Diff(a, b graph.Graph) Delta {
+	_, aDirected := a.(graph.Directed)
+	_, bDirected := b.(graph.Directed)
+	if aDirected != bDirected {
+		panic("transform: mismatched graph directedness")
+	}
+	d := Delta{Directed: aDirected}
+
+	d.RemovedNodes = missingNodes(a, b)
+	d.AddedNodes = missingNodes(b, a)
+
+	d.RemovedEdges = missingEdges(a, b, d.Directed)
+	d.AddedEdges = missingEdges(b, a, d.Directed)
+	aw, aWeighted := a.(graph.Weighted)
+	bw, bWeighted := b.(graph.Weighted)
+	if aWeighted && bWeighted {
+		eachOrderedEdge(a, d.Directed, func(uid, vid int64) {
+			be := bw.WeightedEdge(uid, vid)
+			if be == nil {
+				return
+			}
+			x := aw.WeightedEdge(uid, vid).Weight()
+			y := be.Weight()
+			if x != y {
+				d.Reweighted = append(d.Reweighted, Reweighting{F: b.Node(uid), T: b.Node(vid), Old: x, New: y})
+			}
+		})
+	}
+	return d
+}
+
+// IsEmpty returns whether the Delta describes no changes.
+func (d Delta) IsEmpty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 &&
+		len(d.Reweighted) == 0
+}
+
+// Reverse returns the Delta that undoes the changes described by d.
+func (d Delta) Reverse() Delta {
+	r := Delta{
+		Directed:     d.Directed,
+		AddedNodes:   d.RemovedNodes,
+		RemovedNodes: d.AddedNodes,
+		AddedEdges:   d.RemovedEdges,
+		RemovedEdges: d.AddedEdges,
+	}
+	if d.Reweighted != nil {
+		r.Reweighted = make([]Reweighting, len(d.Reweighted))
+		for i, c := range d.Reweighted {
+			r.Reweighted[i] = Reweighting{F: c.F, T: c.T, Old: c.New, New: c.Old}
+		}
+	}
+	return r
+}
+
+// Patchable is a graph that can have a Delta applied to it by Patch.
+type Patchable interface {
+	graph.Graph
+	graph.NodeAdder
+	graph.NodeRemover
+	graph.EdgeRemover
+}
+
+// Patch applies the changes described by d to dst. If dst is a
+// graph.WeightedEdgeAdder, added edges are set with their weights,
+// otherwise dst must be a graph.EdgeAdder and reweightings are not
+// permitted.
+//
+// Patch checks that d can be applied before making any changes. An error
+// is returned and dst is left unaltered if a removed node or edge does not
+// exist in dst, an added node or edge already exists, or a reweighting
+// does not match the weight of an edge in dst.
+func Patch(dst Patchable, d Delta) error {
+	wdst, weighted := dst.(graph.WeightedEdgeAdder)
+	edst, unweighted := dst.(graph.EdgeAdder)
+	if !weighted && !unweighted {
+		return errors.New("transform: destination cannot add edges")
+	}
+	if !weighted && len(d.Reweighted) != 0 {
+		return errors.New("transform: destination cannot reweight edges")
+	}
+	err := check(dst, d)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range d.RemovedEdges {
+		dst.RemoveEdge(e.From().ID(), e.To().ID())
+	}
+	for _, n := range d.RemovedNodes {
+		dst.RemoveNode(n.ID())
+	}
+	for _, n := range d.AddedNodes {
+		dst.AddNode(n)
+	}
+	for _, e := range d.AddedEdges {
+		if weighted {
+			wdst.SetWeightedEdge(wdst.NewWeightedEdge(e.From(), e.To(), e.Weight()))
+		} else {
+			edst.SetEdge(edst.NewEdge(e.From(), e.To()))
+		}
+	}
+	for _, c := range d.Reweighted {
+		wdst.SetWeightedEdge(wdst.NewWeightedEdge(c.F, c.T, c.New))
+	}
+	return nil
+}
+
+// check returns an error if d can not be applied to g.
+func check(g graph.Graph, d Delta) error {
+	removed := make(map[int64]bool, len(d.RemovedNodes))
+	for _, n := range d.RemovedNodes {
+		if g.Node(n.ID()) == nil {
+			return fmt.Errorf("transform: removed node %d not in graph", n.ID())
+		}
+		removed[n.ID()] = true
+	}
+	added := make(map[int64]bool, len(d.AddedNodes))
+	for _, n := range d.AddedNodes {
+		if g.Node(n.ID()) != nil && !removed[n.ID()] {
+			return fmt.Errorf("transform: added node %d already in graph", n.ID())
+		}
+		added[n.ID()] = true
+	}
+	removedEdge := make(map[[2]int64]bool, len(d.RemovedEdges))
+	for _, e := range d.RemovedEdges {
+		uid, vid := e.From().ID(), e.To().ID()
+		if g.Edge(uid, vid) == nil {
+			return fmt.Errorf("transform: removed edge %d--%d not in graph", uid, vid)
+		}
+		removedEdge[edgeKey(uid, vid, d.Directed)] = true
+	}
+	// Edges of removed nodes are implicitly removed.
+	for id := range removed {
+		to := g.From(id)
+		for to.Next() {
+			removedEdge[edgeKey(id, to.Node().ID(), d.Directed)] = true
+		}
+		if dg, ok := g.(graph.Directed); ok {
+			from := dg.To(id)
+			for from.Next() {
+				removedEdge[edgeKey(from.Node().ID(), id, d.Directed)] = true
+			}
+		}
+	}
+	for _, e := range d.AddedEdges {
+		uid, vid := e.From().ID(), e.To().ID()
+		if g.Edge(uid, vid) != nil && !removedEdge[edgeKey(uid, vid, d.Directed)] {
+			return fmt.Errorf("transform: added edge %d--%d already in graph", uid, vid)
+		}
+		for _, id := range []int64{uid, vid} {
+			if (g.Node(id) == nil || removed[id]) && !added[id] {
+				return fmt.Errorf("transform: added edge %d--%d has missing end point %d", uid, vid, id)
+			}
+		}
+	}
+	if len(d.Reweighted) == 0 {
+		return nil
+	}
+	wg, ok := g.(graph.Weighted)
+	if !ok {
+		return errors.New("transform: destination cannot reweight edges")
+	}
+	for _, c := range d.Reweighted {
+		uid, vid := c.F.ID(), c.T.ID()
+		e := wg.WeightedEdge(uid, vid)
+		if e == nil || removedEdge[edgeKey(uid, vid, d.Directed)] {
+			return fmt.Errorf("transform: reweighted edge %d--%d not in graph", uid, vid)
+		}
+		if w := e.Weight(); w != c.Old {
+			return fmt.Errorf("transform: reweighted edge %d--%d has weight %v, expected %v", uid, vid, w, c.Old)
+		}
+	}
+	return nil
+}
+
+func edgeKey(uid, vid int64, directed bool) [2]int64 {
+	if !directed && vid < uid {
+		uid, vid = vid, uid
+	}
+	return [2]int64{uid, vid}
+}
+
+// missingNodes returns the nodes of a that are not in b, ordered by ID.
+func missingNodes(a, b graph.Graph) []graph.Node {
+	var nodes []graph.Node
+	it := a.Nodes()
+	for it.Next() {
+		n := it.Node()
+		if b.Node(n.ID()) == nil {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Sort(ordered.ByID(nodes))
+	return nodes
+}
+
+// missingEdges returns the edges of a that are not in b.
+func missingEdges(a, b graph.Graph, directed bool) []graph.WeightedEdge {
+	wa, weighted := a.(graph.Weighted)
+	var edges []graph.WeightedEdge
+	eachOrderedEdge(a, directed, func(uid, vid int64) {
+		if b.Edge(uid, vid) != nil {
+			return
+		}
+		w := 1.0
+		if weighted {
+			w = wa.WeightedEdge(uid, vid).Weight()
+		}
+		edges = append(edges, simple.WeightedEdge{F: a.Node(uid), T: a.Node(vid), W: w})
+	})
+	return edges
+}
+
+// eachOrderedEdge calls fn for each edge in g ordered by from and then to
+// node ID. Edges of undirected graphs are visited once with uid <= vid.
+func eachOrderedEdge(g graph.Graph, directed bool, fn func(uid, vid int64)) {
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !directed && vid < uid {
+				continue
+			}
+			fn(uid, vid)
+		}
+	}
+}
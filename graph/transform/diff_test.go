@@ -0,0 +1,147 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func edgeList(edges []graph.WeightedEdge) [][3]float64 {
+	var l [][3]float64
+	for _, e := range edges {
+		l = append(l, [3]float64{float64(e.From().ID()), float64(e.To().ID()), e.Weight()})
+	}
+	return l
+}
+
+func diffPair() (a, b *simple.WeightedUndirectedGraph) {
+	a = simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(0), T: simple.Node(1), W: 1},
+		{F: simple.Node(1), T: simple.Node(2), W: 2},
+		{F: simple.Node(2), T: simple.Node(3), W: 3},
+		{F: simple.Node(3), T: simple.Node(4), W: 4},
+	} {
+		a.SetWeightedEdge(e)
+	}
+	b = simple.NewWeightedUndirectedGraph(0, 0)
+	for _, e := range []simple.WeightedEdge{
+		{F: simple.Node(1), T: simple.Node(0), W: 1},
+		{F: simple.Node(2), T: simple.Node(1), W: 5},
+		{F: simple.Node(2), T: simple.Node(3), W: 3},
+		{F: simple.Node(0), T: simple.Node(5), W: 6},
+		{F: simple.Node(2), T: simple.Node(0), W: 7},
+	} {
+		b.SetWeightedEdge(e)
+	}
+	b.AddNode(simple.Node(6))
+	return a, b
+}
+
+func TestDiff(t *testing.T) {
+	a, b := diffPair()
+	d := Diff(a, b)
+	if d.Directed {
+		t.Error("unexpected directed delta")
+	}
+	if got := idsOf(d.AddedNodes); !reflect.DeepEqual(got, []int64{5, 6}) {
+		t.Errorf("unexpected added nodes: got:%v want:[5 6]", got)
+	}
+	if got := idsOf(d.RemovedNodes); !reflect.DeepEqual(got, []int64{4}) {
+		t.Errorf("unexpected removed nodes: got:%v want:[4]", got)
+	}
+	if got, want := edgeList(d.AddedEdges), [][3]float64{{0, 2, 7}, {0, 5, 6}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected added edges: got:%v want:%v", got, want)
+	}
+	if got, want := edgeList(d.RemovedEdges), [][3]float64{{3, 4, 4}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected removed edges: got:%v want:%v", got, want)
+	}
+	if len(d.Reweighted) != 1 {
+		t.Fatalf("unexpected number of reweightings: got:%d want:1", len(d.Reweighted))
+	}
+	if c := d.Reweighted[0]; c.F.ID() != 1 || c.T.ID() != 2 || c.Old != 2 || c.New != 5 {
+		t.Errorf("unexpected reweighting: got:%+v", c)
+	}
+	if d.IsEmpty() {
+		t.Error("unexpected empty delta")
+	}
+	if !Diff(a, a).IsEmpty() {
+		t.Error("expected empty delta for identical graphs")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	a, b := diffPair()
+	d := Diff(a, b)
+
+	err := Patch(a, d)
+	if err != nil {
+		t.Fatalf("unexpected error patching: %v", err)
+	}
+	if r := Diff(a, b); !r.IsEmpty() {
+		t.Errorf("patched graph differs from target: %+v", r)
+	}
+
+	// a now equals b, so the delta can not be applied again.
+	if err := Patch(a, d); err == nil {
+		t.Error("expected error reapplying delta")
+	}
+	if r := Diff(a, b); !r.IsEmpty() {
+		t.Error("failed patch modified graph")
+	}
+
+	err = Patch(a, d.Reverse())
+	if err != nil {
+		t.Fatalf("unexpected error reverting: %v", err)
+	}
+	orig, _ := diffPair()
+	if r := Diff(a, orig); !r.IsEmpty() {
+		t.Errorf("reverted graph differs from original: %+v", r)
+	}
+}
+
+func TestPatchDirected(t *testing.T) {
+	a := simple.NewDirectedGraph()
+	a.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	a.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	b := simple.NewDirectedGraph()
+	b.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	b.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+
+	d := Diff(a, b)
+	if !d.Directed {
+		t.Error("expected directed delta")
+	}
+	if got, want := edgeList(d.AddedEdges), [][3]float64{{1, 0, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected added edges: got:%v want:%v", got, want)
+	}
+	if got, want := edgeList(d.RemovedEdges), [][3]float64{{0, 1, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected removed edges: got:%v want:%v", got, want)
+	}
+	err := Patch(a, d)
+	if err != nil {
+		t.Fatalf("unexpected error patching: %v", err)
+	}
+	if !Diff(a, b).IsEmpty() {
+		t.Error("patched graph differs from target")
+	}
+
+	reweight := Delta{Directed: true, Reweighted: []Reweighting{{F: simple.Node(1), T: simple.Node(2), Old: 1, New: 2}}}
+	if err := Patch(a, reweight); err == nil {
+		t.Error("expected error reweighting unweighted graph")
+	}
+}
+
+func idsOf(nodes []graph.Node) []int64 {
+	var ids []int64
+	for _, n := range nodes {
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
@@ -0,0 +1,11 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package transform provides functions that construct new graphs
+// from existing graphs.
+//
+// Most functions in this package write their results into a destination
+// graph provided by the caller. Lazily evaluated equivalents of some of
+// the transforms are provided by the graph/view package.
+package transform // import "gonum.org/v1/gonum/graph/transform"
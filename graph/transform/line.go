@@ -0,0 +1,127 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// LineGraph writes the line graph of g into dst and returns a mapping from
+// the IDs of the nodes created in dst to the edges of g they represent.
+// The nodes of the line graph are obtained from dst. If g is a graph.Weighted,
+// the returned edges are graph.WeightedEdge, allowing original edge weights
+// to be recovered.
+//
+// If g is a graph.Directed, the directed line graph is constructed; there is
+// an edge from the node representing u→v to the node representing v→w for
+// every pair of such edges in g. Otherwise two nodes of the line graph are
+// adjacent when the edges of g they represent share an end point. Self edges
+// in g are not considered adjacent to themselves.
+func LineGraph(dst graph.Builder, g graph.Graph) map[int64]graph.Edge {
+	edges, incident := lineNodes(dst, g)
+	connectLine(g, incident, func(x, y lineEdge) {
+		dst.SetEdge(dst.NewEdge(x.node, y.node))
+	})
+	return edges
+}
+
+// WeightedLineGraph writes the weighted line graph of g into dst and returns
+// a mapping from the IDs of the nodes created in dst to the edges of g they
+// represent. Adjacency in the line graph is as described for LineGraph. The
+// weight of an edge of the line graph joining the nodes representing edges x
+// and y of g is weight(x, y). If weight is nil, the mean of the weights of x
+// and y is used.
+func WeightedLineGraph(dst graph.WeightedBuilder, g graph.Weighted, weight func(x, y graph.WeightedEdge) float64) map[int64]graph.WeightedEdge {
+	if weight == nil {
+		weight = func(x, y graph.WeightedEdge) float64 {
+			return (x.Weight() + y.Weight()) / 2
+		}
+	}
+	edges, incident := lineNodes(dst, g)
+	connectLine(g, incident, func(x, y lineEdge) {
+		w := weight(x.edge.(graph.WeightedEdge), y.edge.(graph.WeightedEdge))
+		dst.SetWeightedEdge(dst.NewWeightedEdge(x.node, y.node, w))
+	})
+	weighted := make(map[int64]graph.WeightedEdge, len(edges))
+	for id, e := range edges {
+		weighted[id] = e.(graph.WeightedEdge)
+	}
+	return weighted
+}
+
+// lineEdge is an edge of the original graph and the
+// node representing it in the line graph.
+type lineEdge struct {
+	edge graph.Edge
+	node graph.Node
+}
+
+// lineNodes adds a node to dst for each edge in g in a deterministic order.
+// It returns the mapping from new node IDs to edges and, for each node of g,
+// the edges leaving it for directed graphs or incident to it for undirected
+// graphs.
+func lineNodes(dst graph.NodeAdder, g graph.Graph) (map[int64]graph.Edge, map[int64][]lineEdge) {
+	_, isDirected := g.(graph.Directed)
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	edges := make(map[int64]graph.Edge)
+	incident := make(map[int64][]lineEdge, len(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			n := dst.NewNode()
+			dst.AddNode(n)
+			e := lineEdge{edge: edgeOf(g, uid, vid), node: n}
+			edges[n.ID()] = e.edge
+			incident[uid] = append(incident[uid], e)
+			if !isDirected && uid != vid {
+				incident[vid] = append(incident[vid], e)
+			}
+		}
+	}
+	return edges, incident
+}
+
+// edgeOf returns the edge from u to v in g, as a graph.WeightedEdge
+// if g is a graph.Weighted.
+func edgeOf(g graph.Graph, uid, vid int64) graph.Edge {
+	if wg, ok := g.(graph.Weighted); ok {
+		return wg.WeightedEdge(uid, vid)
+	}
+	return g.Edge(uid, vid)
+}
+
+// connectLine calls set for each pair of adjacent nodes of the line
+// graph described by incident.
+func connectLine(g graph.Graph, incident map[int64][]lineEdge, set func(x, y lineEdge)) {
+	if _, isDirected := g.(graph.Directed); isDirected {
+		for _, out := range incident {
+			for _, x := range out {
+				for _, y := range incident[x.edge.To().ID()] {
+					if x.node.ID() != y.node.ID() {
+						set(x, y)
+					}
+				}
+			}
+		}
+		return
+	}
+	for _, edges := range incident {
+		for i, x := range edges {
+			for _, y := range edges[i+1:] {
+				set(x, y)
+			}
+		}
+	}
+}
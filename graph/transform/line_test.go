@@ -0,0 +1,126 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// lineAdjacency returns the adjacency of the line graph g with
+// nodes labeled by the end point IDs of the edges they represent.
+func lineAdjacency(g graph.Graph, edges map[int64]graph.Edge) map[[2]int64][][2]int64 {
+	label := func(id int64) [2]int64 {
+		e := edges[id]
+		u, v := e.From().ID(), e.To().ID()
+		if _, ok := g.(graph.Undirected); ok && v < u {
+			u, v = v, u
+		}
+		return [2]int64{u, v}
+	}
+	adj := make(map[[2]int64][][2]int64)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		l := label(id)
+		adj[l] = adj[l]
+		to := g.From(id)
+		for to.Next() {
+			adj[l] = append(adj[l], label(to.Node().ID()))
+		}
+		sort.Slice(adj[l], func(i, j int) bool {
+			a, b := adj[l][i], adj[l][j]
+			return a[0] < b[0] || (a[0] == b[0] && a[1] < b[1])
+		})
+	}
+	return adj
+}
+
+func TestLineGraphUndirected(t *testing.T) {
+	// Star with a pendant path: 0--1, 0--2, 0--3, 3--4.
+	g := simple.NewUndirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {0, 2}, {0, 3}, {3, 4}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	dst := simple.NewUndirectedGraph()
+	edges := LineGraph(dst, g)
+	if len(edges) != 4 {
+		t.Fatalf("unexpected number of line graph nodes: got:%d want:4", len(edges))
+	}
+	want := map[[2]int64][][2]int64{
+		{0, 1}: {{0, 2}, {0, 3}},
+		{0, 2}: {{0, 1}, {0, 3}},
+		{0, 3}: {{0, 1}, {0, 2}, {3, 4}},
+		{3, 4}: {{0, 3}},
+	}
+	got := lineAdjacency(dst, edges)
+	if !equalAdjacency(got, want) {
+		t.Errorf("unexpected line graph:\ngot: %v\nwant:%v", got, want)
+	}
+}
+
+func TestLineGraphDirected(t *testing.T) {
+	// Directed 2-cycle with a tail: 0->1, 1->0, 1->2.
+	g := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {1, 0}, {1, 2}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	dst := simple.NewDirectedGraph()
+	edges := LineGraph(dst, g)
+	want := map[[2]int64][][2]int64{
+		{0, 1}: {{1, 0}, {1, 2}},
+		{1, 0}: {{0, 1}},
+		{1, 2}: nil,
+	}
+	got := lineAdjacency(dst, edges)
+	if !equalAdjacency(got, want) {
+		t.Errorf("unexpected line graph:\ngot: %v\nwant:%v", got, want)
+	}
+}
+
+func TestWeightedLineGraph(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 2})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 4})
+	dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	edges := WeightedLineGraph(dst, g, nil)
+
+	var x, y int64 = -1, -1
+	for id, e := range edges {
+		switch e.Weight() {
+		case 2:
+			x = id
+		case 4:
+			y = id
+		default:
+			t.Errorf("unexpected edge weight: %v", e.Weight())
+		}
+	}
+	if w, ok := dst.Weight(x, y); !ok || w != 3 {
+		t.Errorf("unexpected line graph edge weight: got:(%v, %t) want:(3, true)", w, ok)
+	}
+}
+
+func equalAdjacency(a, b map[[2]int64][][2]int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, va := range a {
+		vb, ok := b[k]
+		if !ok || len(va) != len(vb) {
+			return false
+		}
+		for i := range va {
+			if va[i] != vb[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// ProductNode is a node in a graph product.
+type ProductNode struct {
+	UID int64
+
+	// A and B are the nodes in the
+	// operands that the product node
+	// represents.
+	A, B graph.Node
+}
+
+// ID implements the graph.Node interface.
+func (n ProductNode) ID() int64 { return n.UID }
+
+// Pairing is a bijection between pairs of node IDs in two graphs and
+// the node IDs of their product. Product node IDs are contiguous from
+// zero and are ordered lexically by the operand node IDs.
+type Pairing struct {
+	a, b   []graph.Node
+	aIndex map[int64]int
+	bIndex map[int64]int
+}
+
+// NewPairing returns a Pairing for the nodes of a and b.
+func NewPairing(a, b graph.Graph) Pairing {
+	p := Pairing{
+		a: graph.NodesOf(a.Nodes()),
+		b: graph.NodesOf(b.Nodes()),
+	}
+	sort.Sort(ordered.ByID(p.a))
+	sort.Sort(ordered.ByID(p.b))
+	p.aIndex = indexOf(p.a)
+	p.bIndex = indexOf(p.b)
+	return p
+}
+
+func indexOf(nodes []graph.Node) map[int64]int {
+	idx := make(map[int64]int, len(nodes))
+	for i, n := range nodes {
+		idx[n.ID()] = i
+	}
+	return idx
+}
+
+// Len returns the number of nodes in the product.
+func (p Pairing) Len() int { return len(p.a) * len(p.b) }
+
+// ID returns the product node ID for the pair of operand node IDs aid and
+// bid. If either node is not in its operand, ID returns -1 and false.
+func (p Pairing) ID(aid, bid int64) (id int64, ok bool) {
+	i, ok := p.aIndex[aid]
+	if !ok {
+		return -1, false
+	}
+	j, ok := p.bIndex[bid]
+	if !ok {
+		return -1, false
+	}
+	return int64(i*len(p.b) + j), true
+}
+
+// Node returns the product node for the given product node ID. If id is
+// not a valid product node ID, Node returns nil.
+func (p Pairing) Node(id int64) graph.Node {
+	if id < 0 || id >= int64(p.Len()) {
+		return nil
+	}
+	i := int(id) / len(p.b)
+	j := int(id) % len(p.b)
+	return ProductNode{UID: id, A: p.a[i], B: p.b[j]}
+}
+
+// node returns the product node for the operand nodes at
+// indices i and j.
+func (p Pairing) node(i, j int) ProductNode {
+	return ProductNode{UID: int64(i*len(p.b) + j), A: p.a[i], B: p.b[j]}
+}
+
+// CartesianProduct writes the Cartesian product of a and b into dst. The
+// nodes of the product are ProductNode values with IDs given by the returned
+// Pairing. There is an edge from (u, v) to (u', v') when u = u' and v→v' is
+// an edge of b, or when v = v' and u→u' is an edge of a. CartesianProduct
+// does not clear dst before writing.
+func CartesianProduct(dst graph.Builder, a, b graph.Graph) Pairing {
+	return product(dst, a, b, true, false)
+}
+
+// TensorProduct writes the tensor product of a and b into dst. The nodes of
+// the product are ProductNode values with IDs given by the returned Pairing.
+// There is an edge from (u, v) to (u', v') when u→u' is an edge of a and v→v'
+// is an edge of b. TensorProduct does not clear dst before writing.
+func TensorProduct(dst graph.Builder, a, b graph.Graph) Pairing {
+	return product(dst, a, b, false, true)
+}
+
+// StrongProduct writes the strong product of a and b into dst. The nodes of
+// the product are ProductNode values with IDs given by the returned Pairing.
+// The edges of the strong product are the union of the edges of the Cartesian
+// and tensor products. StrongProduct does not clear dst before writing.
+func StrongProduct(dst graph.Builder, a, b graph.Graph) Pairing {
+	return product(dst, a, b, true, true)
+}
+
+// product writes the product of a and b into dst, including Cartesian
+// and tensor edges as specified.
+func product(dst graph.Builder, a, b graph.Graph, cartesian, tensor bool) Pairing {
+	p := NewPairing(a, b)
+	for i := range p.a {
+		for j := range p.b {
+			dst.AddNode(p.node(i, j))
+		}
+	}
+	aTo := adjacentIndices(a, p.a, p.aIndex)
+	bTo := adjacentIndices(b, p.b, p.bIndex)
+	for i := range p.a {
+		for j := range p.b {
+			u := p.node(i, j)
+			if cartesian {
+				for _, k := range aTo[i] {
+					dst.SetEdge(dst.NewEdge(u, p.node(k, j)))
+				}
+				for _, l := range bTo[j] {
+					dst.SetEdge(dst.NewEdge(u, p.node(i, l)))
+				}
+			}
+			if tensor {
+				for _, k := range aTo[i] {
+					for _, l := range bTo[j] {
+						dst.SetEdge(dst.NewEdge(u, p.node(k, l)))
+					}
+				}
+			}
+		}
+	}
+	return p
+}
+
+// adjacentIndices returns the indices of the nodes reachable from each
+// node of g, excluding self edges.
+func adjacentIndices(g graph.Graph, nodes []graph.Node, index map[int64]int) [][]int {
+	to := make([][]int, len(nodes))
+	for i, u := range nodes {
+		uid := u.ID()
+		it := g.From(uid)
+		for it.Next() {
+			vid := it.Node().ID()
+			if vid == uid {
+				continue
+			}
+			to[i] = append(to[i], index[vid])
+		}
+		sort.Ints(to[i])
+	}
+	return to
+}
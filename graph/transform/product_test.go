@@ -0,0 +1,107 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func pathGraph(n int) *simple.UndirectedGraph {
+	g := simple.NewUndirectedGraph()
+	for i := 0; i < n-1; i++ {
+		g.SetEdge(simple.Edge{F: simple.Node(i), T: simple.Node(i + 1)})
+	}
+	return g
+}
+
+func TestProducts(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		product func(dst graph.Builder, a, b graph.Graph) Pairing
+		n, m    int
+		edges   int
+	}{
+		// The Cartesian product of P_n and P_m is the n×m grid.
+		{name: "cartesian", product: CartesianProduct, n: 3, m: 4, edges: 3*3 + 4*2},
+		// The tensor product of P_n and P_m has 2(n-1)(m-1) edges.
+		{name: "tensor", product: TensorProduct, n: 3, m: 4, edges: 2 * 2 * 3},
+		// The strong product is the king's graph.
+		{name: "strong", product: StrongProduct, n: 3, m: 4, edges: 3*3 + 4*2 + 2*2*3},
+	} {
+		a := pathGraph(test.n)
+		b := pathGraph(test.m)
+		dst := simple.NewUndirectedGraph()
+		p := test.product(dst, a, b)
+		if got, want := dst.Nodes().Len(), test.n*test.m; got != want || p.Len() != want {
+			t.Errorf("unexpected number of nodes for %s: got:%d pairing:%d want:%d", test.name, got, p.Len(), want)
+		}
+		if got := dst.Edges().Len(); got != test.edges {
+			t.Errorf("unexpected number of edges for %s: got:%d want:%d", test.name, got, test.edges)
+		}
+	}
+}
+
+func TestProductAdjacency(t *testing.T) {
+	a := pathGraph(2)
+	b := pathGraph(3)
+	b.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(2)})
+
+	dst := simple.NewUndirectedGraph()
+	p := CartesianProduct(dst, a, b)
+	id := func(u, v int64) int64 {
+		id, ok := p.ID(u, v)
+		if !ok {
+			t.Fatalf("missing pairing for (%d, %d)", u, v)
+		}
+		return id
+	}
+	if !dst.HasEdgeBetween(id(0, 0), id(1, 0)) {
+		t.Error("missing Cartesian edge along first operand")
+	}
+	if !dst.HasEdgeBetween(id(1, 0), id(1, 2)) {
+		t.Error("missing Cartesian edge along second operand")
+	}
+	if dst.HasEdgeBetween(id(0, 0), id(1, 1)) {
+		t.Error("unexpected diagonal edge in Cartesian product")
+	}
+	n, ok := p.Node(id(1, 2)).(ProductNode)
+	if !ok || n.A.ID() != 1 || n.B.ID() != 2 {
+		t.Errorf("unexpected product node: got:%#v", p.Node(id(1, 2)))
+	}
+	if _, ok := p.ID(2, 0); ok {
+		t.Error("unexpected pairing for absent node")
+	}
+	if p.Node(-1) != nil || p.Node(int64(p.Len())) != nil {
+		t.Error("unexpected product node for invalid ID")
+	}
+}
+
+func TestDirectedTensorProduct(t *testing.T) {
+	// The tensor product of two directed 2-cycles is two disjoint 2-cycles.
+	cycle := func() *simple.DirectedGraph {
+		g := simple.NewDirectedGraph()
+		g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+		g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+		return g
+	}
+	dst := simple.NewDirectedGraph()
+	p := TensorProduct(dst, cycle(), cycle())
+	id := func(u, v int64) int64 {
+		id, _ := p.ID(u, v)
+		return id
+	}
+	if !dst.HasEdgeFromTo(id(0, 0), id(1, 1)) || !dst.HasEdgeFromTo(id(1, 1), id(0, 0)) {
+		t.Error("missing tensor product cycle")
+	}
+	if dst.HasEdgeBetween(id(0, 0), id(0, 1)) {
+		t.Error("unexpected edge between product components")
+	}
+	if got := dst.Edges().Len(); got != 4 {
+		t.Errorf("unexpected number of edges: got:%d want:4", got)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"fmt"
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Quotient describes the relationship between a graph and a quotient
+// graph constructed from it.
+type Quotient struct {
+	// Nodes holds the nodes of the quotient
+	// graph representing each part of the
+	// partition.
+	Nodes []graph.Node
+
+	// Part is a mapping from the node IDs of
+	// the original graph to the index of the
+	// node's part.
+	Part map[int64]int
+
+	// Weights holds the aggregated node
+	// weight of each part.
+	Weights []float64
+
+	// Internal holds the aggregated weight of
+	// the edges with both end points in each
+	// part. For undirected graphs each edge
+	// is counted once.
+	Internal []float64
+}
+
+// WeightedQuotient writes the quotient graph of g with respect to the
+// given partition of its nodes into dst. Each part is represented by a
+// new node obtained from dst, and the parts are joined by an edge with
+// weight equal to the sum of the weights of the edges of g between them.
+// Edges of g are given a weight of 1 if g is not a graph.Weighted. If g is
+// a graph.Directed edges are aggregated separately in each direction.
+//
+// The weight of each part is the sum of nodeWeight for the nodes in the part.
+// If nodeWeight is nil, each node is given a weight of 1. Edges within a part
+// are not written to dst, but their aggregated weight is recorded in the
+// returned Quotient.
+//
+// WeightedQuotient will panic if parts is not a partition of the nodes of g.
+func WeightedQuotient(dst graph.WeightedBuilder, g graph.Graph, parts [][]graph.Node, nodeWeight func(graph.Node) float64) Quotient {
+	q := newQuotient(dst, g, parts, nodeWeight)
+	_, isDirected := g.(graph.Directed)
+	between := make(map[[2]int]float64)
+	eachPartEdge(g, q, func(pu, pv int, w float64) {
+		if pu == pv {
+			return
+		}
+		if !isDirected && pv < pu {
+			pu, pv = pv, pu
+		}
+		between[[2]int{pu, pv}] += w
+	})
+	keys := make([][2]int, 0, len(between))
+	for k := range between {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i][0] < keys[j][0] || (keys[i][0] == keys[j][0] && keys[i][1] < keys[j][1])
+	})
+	for _, k := range keys {
+		dst.SetWeightedEdge(dst.NewWeightedEdge(q.Nodes[k[0]], q.Nodes[k[1]], between[k]))
+	}
+	return q
+}
+
+// MultiQuotient writes the quotient multigraph of g with respect to the
+// given partition of its nodes into dst. Each part is represented by a new
+// node obtained from dst, and each edge of g is represented by a line
+// between the nodes of the parts holding its end points. Edges within a
+// part are represented by self lines. Node weights and internal edge weights
+// are aggregated as described for WeightedQuotient.
+//
+// MultiQuotient will panic if parts is not a partition of the nodes of g.
+func MultiQuotient(dst graph.MultigraphBuilder, g graph.Graph, parts [][]graph.Node, nodeWeight func(graph.Node) float64) Quotient {
+	q := newQuotient(dst, g, parts, nodeWeight)
+	eachPartEdge(g, q, func(pu, pv int, _ float64) {
+		dst.SetLine(dst.NewLine(q.Nodes[pu], q.Nodes[pv]))
+	})
+	return q
+}
+
+// WeightedMultiQuotient writes the quotient multigraph of g with respect to
+// the given partition of its nodes into dst as described for MultiQuotient.
+// Each line carries the weight of the edge of g it represents, or 1 if g is
+// not a graph.Weighted.
+//
+// WeightedMultiQuotient will panic if parts is not a partition of the nodes
+// of g.
+func WeightedMultiQuotient(dst graph.WeightedMultigraphBuilder, g graph.Graph, parts [][]graph.Node, nodeWeight func(graph.Node) float64) Quotient {
+	q := newQuotient(dst, g, parts, nodeWeight)
+	eachPartEdge(g, q, func(pu, pv int, w float64) {
+		dst.SetWeightedLine(dst.NewWeightedLine(q.Nodes[pu], q.Nodes[pv], w))
+	})
+	return q
+}
+
+// newQuotient adds a node to dst for each part and returns the
+// Quotient describing the partition.
+func newQuotient(dst graph.NodeAdder, g graph.Graph, parts [][]graph.Node, nodeWeight func(graph.Node) float64) Quotient {
+	if nodeWeight == nil {
+		nodeWeight = func(graph.Node) float64 { return 1 }
+	}
+	q := Quotient{
+		Nodes:    make([]graph.Node, len(parts)),
+		Part:     make(map[int64]int),
+		Weights:  make([]float64, len(parts)),
+		Internal: make([]float64, len(parts)),
+	}
+	for i, p := range parts {
+		n := dst.NewNode()
+		dst.AddNode(n)
+		q.Nodes[i] = n
+		for _, u := range p {
+			id := u.ID()
+			if g.Node(id) == nil {
+				panic(fmt.Sprintf("transform: partition node %d not in graph", id))
+			}
+			if _, exists := q.Part[id]; exists {
+				panic(fmt.Sprintf("transform: node %d in more than one part", id))
+			}
+			q.Part[id] = i
+			q.Weights[i] += nodeWeight(u)
+		}
+	}
+	if len(q.Part) != g.Nodes().Len() {
+		panic("transform: partition does not cover graph")
+	}
+	return q
+}
+
+// eachPartEdge calls fn with the parts of the end points and the weight
+// of each edge of g in a deterministic order, recording the weights of
+// edges within parts in q. Undirected edges are visited once.
+func eachPartEdge(g graph.Graph, q Quotient, fn func(pu, pv int, w float64)) {
+	_, isDirected := g.(graph.Directed)
+	weight := func(uid, vid int64) float64 { return 1 }
+	if wg, ok := g.(graph.Weighted); ok {
+		weight = func(uid, vid int64) float64 {
+			return wg.WeightedEdge(uid, vid).Weight()
+		}
+	}
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			w := weight(uid, vid)
+			pu, pv := q.Part[uid], q.Part[vid]
+			if pu == pv {
+				q.Internal[pu] += w
+			}
+			fn(pu, pv, w)
+		}
+	}
+}
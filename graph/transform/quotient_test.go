@@ -0,0 +1,112 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/multi"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// quotientGraph is two triangles {0,1,2} and {3,4,5} joined
+// by the edges 2--3 and 1--4, with a pendant node 6 on 5.
+var quotientGraph = map[[2]int64]float64{
+	{0, 1}: 1, {0, 2}: 1, {1, 2}: 1,
+	{3, 4}: 2, {3, 5}: 2, {4, 5}: 2,
+	{2, 3}: 5, {1, 4}: 7,
+	{5, 6}: 3,
+}
+
+func quotientParts() [][]graph.Node {
+	return [][]graph.Node{
+		{simple.Node(0), simple.Node(1), simple.Node(2)},
+		{simple.Node(3), simple.Node(4), simple.Node(5)},
+		{simple.Node(6)},
+	}
+}
+
+func TestWeightedQuotient(t *testing.T) {
+	g := weightedUndirected(quotientGraph)
+	dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	q := WeightedQuotient(dst, g, quotientParts(), nil)
+
+	if got, want := q.Weights, []float64{3, 3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected part weights: got:%v want:%v", got, want)
+	}
+	if got, want := q.Internal, []float64{3, 6, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected internal weights: got:%v want:%v", got, want)
+	}
+	for id, want := range map[int64]int{0: 0, 1: 0, 2: 0, 3: 1, 4: 1, 5: 1, 6: 2} {
+		if got := q.Part[id]; got != want {
+			t.Errorf("unexpected part for node %d: got:%d want:%d", id, got, want)
+		}
+	}
+	got := weightedEdges(dst)
+	a, b, c := q.Nodes[0].ID(), q.Nodes[1].ID(), q.Nodes[2].ID()
+	want := map[[2]int64]float64{{a, b}: 12, {b, c}: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected quotient edges: got:%v want:%v", got, want)
+	}
+}
+
+func TestWeightedQuotientDirected(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	for _, e := range [][2]int64{{0, 1}, {1, 2}, {2, 0}, {0, 2}} {
+		g.SetEdge(simple.Edge{F: simple.Node(e[0]), T: simple.Node(e[1])})
+	}
+	dst := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	parts := [][]graph.Node{{simple.Node(0), simple.Node(1)}, {simple.Node(2)}}
+	q := WeightedQuotient(dst, g, parts, func(n graph.Node) float64 { return float64(n.ID()) })
+
+	a, b := q.Nodes[0].ID(), q.Nodes[1].ID()
+	if w, _ := dst.Weight(a, b); w != 2 {
+		t.Errorf("unexpected forward weight: got:%v want:2", w)
+	}
+	if w, _ := dst.Weight(b, a); w != 1 {
+		t.Errorf("unexpected reverse weight: got:%v want:1", w)
+	}
+	if got, want := q.Weights, []float64{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected part weights: got:%v want:%v", got, want)
+	}
+}
+
+func TestMultiQuotient(t *testing.T) {
+	g := weightedUndirected(quotientGraph)
+	dst := multi.NewWeightedUndirectedGraph()
+	q := WeightedMultiQuotient(dst, g, quotientParts(), nil)
+
+	a, b := q.Nodes[0].ID(), q.Nodes[1].ID()
+	if got := dst.WeightedLinesBetween(a, b).Len(); got != 2 {
+		t.Errorf("unexpected number of lines between parts: got:%d want:2", got)
+	}
+	if got := dst.WeightedLinesBetween(a, a).Len(); got != 3 {
+		t.Errorf("unexpected number of self lines: got:%d want:3", got)
+	}
+	if w, _ := dst.Weight(a, b); w != 12 {
+		t.Errorf("unexpected aggregate weight between parts: got:%v want:12", w)
+	}
+}
+
+func TestQuotientPanics(t *testing.T) {
+	g := weightedUndirected(quotientGraph)
+	for _, parts := range [][][]graph.Node{
+		{{simple.Node(0)}},
+		{{simple.Node(0), simple.Node(1), simple.Node(2), simple.Node(3)}, {simple.Node(3), simple.Node(4), simple.Node(5), simple.Node(6)}},
+		{{simple.Node(0), simple.Node(1), simple.Node(2), simple.Node(3), simple.Node(4), simple.Node(5), simple.Node(6), simple.Node(7)}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for invalid partition: %v", parts)
+				}
+			}()
+			WeightedQuotient(simple.NewWeightedUndirectedGraph(0, math.Inf(1)), g, parts, nil)
+		}()
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Merge returns the weight of an edge present in both operands of a set
+// operation given the weights of the edge in the first and second operand.
+type Merge func(x, y float64) float64
+
+// First returns x.
+func First(x, _ float64) float64 { return x }
+
+// Sum returns x+y.
+func Sum(x, y float64) float64 { return x + y }
+
+// Min returns the lesser of x and y.
+func Min(x, y float64) float64 { return math.Min(x, y) }
+
+// Max returns the greater of x and y.
+func Max(x, y float64) float64 { return math.Max(x, y) }
+
+// Union writes the union of a and b into dst. Nodes and edges are
+// identified by node ID. The union contains every node and edge that is
+// present in either a or b. When a node is present in both a and b, the
+// node from a is used. Union does not clear dst before writing.
+func Union(dst graph.Builder, a, b graph.Graph) {
+	addNodes(dst, a, b, func(inA, inB bool) bool { return inA || inB })
+	addEdges(dst, a, b, func(inA, inB bool) bool { return inA || inB })
+}
+
+// WeightedUnion writes the union of a and b into dst. Nodes and edges are
+// identified by node ID. The union contains every node and edge that is
+// present in either a or b. When a node is present in both a and b, the
+// node from a is used. When an edge is present in both a and b its weight
+// is the result of merge applied to the weights of the edge in a and b.
+// If merge is nil, First is used. WeightedUnion does not clear dst before
+// writing.
+func WeightedUnion(dst graph.WeightedBuilder, a, b graph.Weighted, merge Merge) {
+	addNodes(dst, a, b, func(inA, inB bool) bool { return inA || inB })
+	addWeightedEdges(dst, a, b, merge, func(inA, inB bool) bool { return inA || inB })
+}
+
+// Intersection writes the intersection of a and b into dst. Nodes and edges
+// are identified by node ID. The intersection contains the nodes and edges
+// that are present in both a and b, with nodes taken from a. Intersection
+// does not clear dst before writing.
+func Intersection(dst graph.Builder, a, b graph.Graph) {
+	addNodes(dst, a, b, func(inA, inB bool) bool { return inA && inB })
+	addEdges(dst, a, b, func(inA, inB bool) bool { return inA && inB })
+}
+
+// WeightedIntersection writes the intersection of a and b into dst. Nodes
+// and edges are identified by node ID. The intersection contains the nodes
+// and edges that are present in both a and b, with nodes taken from a. The
+// weight of each edge is the result of merge applied to the weights of the
+// edge in a and b. If merge is nil, First is used. WeightedIntersection does
+// not clear dst before writing.
+func WeightedIntersection(dst graph.WeightedBuilder, a, b graph.Weighted, merge Merge) {
+	addNodes(dst, a, b, func(inA, inB bool) bool { return inA && inB })
+	addWeightedEdges(dst, a, b, merge, func(inA, inB bool) bool { return inA && inB })
+}
+
+// Difference writes the difference of a and b into dst. Nodes and edges are
+// identified by node ID. The difference contains all the nodes of a and the
+// edges of a that are not present in b. Difference does not clear dst before
+// writing.
+func Difference(dst graph.Builder, a, b graph.Graph) {
+	addNodes(dst, a, b, func(inA, _ bool) bool { return inA })
+	addEdges(dst, a, b, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// WeightedDifference writes the difference of a and b into dst. Nodes and
+// edges are identified by node ID. The difference contains all the nodes of
+// a and the edges of a, with their weights in a, that are not present in b.
+// WeightedDifference does not clear dst before writing.
+func WeightedDifference(dst graph.WeightedBuilder, a, b graph.Weighted) {
+	addNodes(dst, a, b, func(inA, _ bool) bool { return inA })
+	addWeightedEdges(dst, a, b, nil, func(inA, inB bool) bool { return inA && !inB })
+}
+
+// SymmetricDifference writes the symmetric difference of a and b into dst.
+// Nodes and edges are identified by node ID. The symmetric difference
+// contains every node present in either a or b, and the edges that are
+// present in exactly one of a and b. When a node is present in both a and b,
+// the node from a is used. SymmetricDifference does not clear dst before
+// writing.
+func SymmetricDifference(dst graph.Builder, a, b graph.Graph) {
+	addNodes(dst, a, b, func(inA, inB bool) bool { return inA || inB })
+	addEdges(dst, a, b, func(inA, inB bool) bool { return inA != inB })
+}
+
+// WeightedSymmetricDifference writes the symmetric difference of a and b
+// into dst. Nodes and edges are identified by node ID. The symmetric
+// difference contains every node present in either a or b, and the edges,
+// with their original weights, that are present in exactly one of a and b.
+// When a node is present in both a and b, the node from a is used.
+// WeightedSymmetricDifference does not clear dst before writing.
+func WeightedSymmetricDifference(dst graph.WeightedBuilder, a, b graph.Weighted) {
+	addNodes(dst, a, b, func(inA, inB bool) bool { return inA || inB })
+	addWeightedEdges(dst, a, b, nil, func(inA, inB bool) bool { return inA != inB })
+}
+
+// DisjointUnion writes the disjoint union of a and b into dst. The nodes
+// and edges of a are copied into dst unaltered. Each node of b is
+// represented by a new node obtained from dst, and its edges are copied
+// with end points mapped to the new nodes. DisjointUnion returns the
+// mapping from node IDs in b to node IDs in dst. DisjointUnion does not
+// clear dst before writing.
+func DisjointUnion(dst graph.Builder, a, b graph.Graph) map[int64]int64 {
+	graph.Copy(dst, a)
+	nodes := addRelabeledNodes(dst, b)
+	eachEdge(b, func(e graph.Edge) {
+		dst.SetEdge(dst.NewEdge(nodes[e.From().ID()], nodes[e.To().ID()]))
+	})
+	return relabeling(nodes)
+}
+
+// WeightedDisjointUnion writes the disjoint union of a and b into dst. The
+// nodes and edges of a are copied into dst unaltered. Each node of b is
+// represented by a new node obtained from dst, and its edges are copied
+// with their weights and with end points mapped to the new nodes.
+// WeightedDisjointUnion returns the mapping from node IDs in b to node IDs
+// in dst. WeightedDisjointUnion does not clear dst before writing.
+func WeightedDisjointUnion(dst graph.WeightedBuilder, a, b graph.Weighted) map[int64]int64 {
+	graph.CopyWeighted(dst, a)
+	nodes := addRelabeledNodes(dst, b)
+	eachEdge(b, func(e graph.Edge) {
+		w := b.WeightedEdge(e.From().ID(), e.To().ID()).Weight()
+		dst.SetWeightedEdge(dst.NewWeightedEdge(nodes[e.From().ID()], nodes[e.To().ID()], w))
+	})
+	return relabeling(nodes)
+}
+
+// addRelabeledNodes adds a new node to dst for each node in g and
+// returns the mapping from g node IDs to the new nodes.
+func addRelabeledNodes(dst graph.NodeAdder, g graph.Graph) map[int64]graph.Node {
+	it := g.Nodes()
+	nodes := make(map[int64]graph.Node)
+	for it.Next() {
+		n := dst.NewNode()
+		dst.AddNode(n)
+		nodes[it.Node().ID()] = n
+	}
+	return nodes
+}
+
+// relabeling returns the node ID mapping described by nodes.
+func relabeling(nodes map[int64]graph.Node) map[int64]int64 {
+	ids := make(map[int64]int64, len(nodes))
+	for id, n := range nodes {
+		ids[id] = n.ID()
+	}
+	return ids
+}
+
+// endPoints returns the end points of e, using the nodes of a
+// in preference to those held by e.
+func endPoints(a graph.Graph, e graph.Edge) (u, v graph.Node) {
+	u, v = e.From(), e.To()
+	if n := a.Node(u.ID()); n != nil {
+		u = n
+	}
+	if n := a.Node(v.ID()); n != nil {
+		v = n
+	}
+	return u, v
+}
+
+// addNodes adds the nodes of a and b to dst when keep returns true
+// for the node's membership of a and b.
+func addNodes(dst graph.NodeAdder, a, b graph.Graph, keep func(inA, inB bool) bool) {
+	nodes := a.Nodes()
+	for nodes.Next() {
+		n := nodes.Node()
+		if keep(true, b.Node(n.ID()) != nil) {
+			dst.AddNode(n)
+		}
+	}
+	nodes = b.Nodes()
+	for nodes.Next() {
+		n := nodes.Node()
+		if a.Node(n.ID()) == nil && keep(false, true) {
+			dst.AddNode(n)
+		}
+	}
+}
+
+// addEdges sets the edges of a and b in dst when keep returns true
+// for the edge's membership of a and b.
+func addEdges(dst graph.Builder, a, b graph.Graph, keep func(inA, inB bool) bool) {
+	eachEdge(a, func(e graph.Edge) {
+		if keep(true, b.Edge(e.From().ID(), e.To().ID()) != nil) {
+			dst.SetEdge(dst.NewEdge(e.From(), e.To()))
+		}
+	})
+	eachEdge(b, func(e graph.Edge) {
+		if a.Edge(e.From().ID(), e.To().ID()) == nil && keep(false, true) {
+			dst.SetEdge(dst.NewEdge(endPoints(a, e)))
+		}
+	})
+}
+
+// addWeightedEdges sets the edges of a and b in dst when keep returns
+// true for the edge's membership of a and b, merging the weights of
+// edges present in both using merge.
+func addWeightedEdges(dst graph.WeightedBuilder, a, b graph.Weighted, merge Merge, keep func(inA, inB bool) bool) {
+	if merge == nil {
+		merge = First
+	}
+	eachEdge(a, func(e graph.Edge) {
+		uid, vid := e.From().ID(), e.To().ID()
+		w := a.WeightedEdge(uid, vid).Weight()
+		be := b.WeightedEdge(uid, vid)
+		if !keep(true, be != nil) {
+			return
+		}
+		if be != nil {
+			w = merge(w, be.Weight())
+		}
+		dst.SetWeightedEdge(dst.NewWeightedEdge(e.From(), e.To(), w))
+	})
+	eachEdge(b, func(e graph.Edge) {
+		uid, vid := e.From().ID(), e.To().ID()
+		if a.Edge(uid, vid) != nil || !keep(false, true) {
+			return
+		}
+		w := b.WeightedEdge(uid, vid).Weight()
+		u, v := endPoints(a, e)
+		dst.SetWeightedEdge(dst.NewWeightedEdge(u, v, w))
+	})
+}
+
+// eachEdge calls fn for each edge in g. Edges of undirected graphs
+// are visited once in each direction.
+func eachEdge(g graph.Graph, fn func(graph.Edge)) {
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uid := nodes.Node().ID()
+		to := g.From(uid)
+		for to.Next() {
+			fn(g.Edge(uid, to.Node().ID()))
+		}
+	}
+}
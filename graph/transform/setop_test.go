@@ -0,0 +1,182 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// weightedEdges returns the weighted edges of g as a map keyed by
+// ordered node ID pairs.
+func weightedEdges(g graph.Weighted) map[[2]int64]float64 {
+	edges := make(map[[2]int64]float64)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uid := nodes.Node().ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if _, ok := g.(graph.Undirected); ok && vid < uid {
+				continue
+			}
+			edges[[2]int64{uid, vid}] = g.WeightedEdge(uid, vid).Weight()
+		}
+	}
+	return edges
+}
+
+func nodeIDs(g graph.Graph) []int64 {
+	var ids []int64
+	nodes := g.Nodes()
+	for nodes.Next() {
+		ids = append(ids, nodes.Node().ID())
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func weightedUndirected(edges map[[2]int64]float64, nodes ...int64) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for _, id := range nodes {
+		g.AddNode(simple.Node(id))
+	}
+	for e, w := range edges {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: w})
+	}
+	return g
+}
+
+var (
+	// setA is the graph 0--1--2--3 with an isolated node 5.
+	setA = map[[2]int64]float64{{0, 1}: 1, {1, 2}: 2, {2, 3}: 3}
+	// setB is the graph 1--2--4 and 0--4.
+	setB = map[[2]int64]float64{{1, 2}: 10, {2, 4}: 20, {0, 4}: 30}
+)
+
+var setOpTests = []struct {
+	name  string
+	op    func(dst graph.WeightedBuilder, a, b graph.Weighted)
+	nodes []int64
+	edges map[[2]int64]float64
+}{
+	{
+		name: "union sum",
+		op: func(dst graph.WeightedBuilder, a, b graph.Weighted) {
+			WeightedUnion(dst, a, b, Sum)
+		},
+		nodes: []int64{0, 1, 2, 3, 4, 5},
+		edges: map[[2]int64]float64{{0, 1}: 1, {1, 2}: 12, {2, 3}: 3, {2, 4}: 20, {0, 4}: 30},
+	},
+	{
+		name: "union default",
+		op: func(dst graph.WeightedBuilder, a, b graph.Weighted) {
+			WeightedUnion(dst, a, b, nil)
+		},
+		nodes: []int64{0, 1, 2, 3, 4, 5},
+		edges: map[[2]int64]float64{{0, 1}: 1, {1, 2}: 2, {2, 3}: 3, {2, 4}: 20, {0, 4}: 30},
+	},
+	{
+		name: "intersection max",
+		op: func(dst graph.WeightedBuilder, a, b graph.Weighted) {
+			WeightedIntersection(dst, a, b, Max)
+		},
+		nodes: []int64{0, 1, 2},
+		edges: map[[2]int64]float64{{1, 2}: 10},
+	},
+	{
+		name:  "difference",
+		op:    WeightedDifference,
+		nodes: []int64{0, 1, 2, 3, 5},
+		edges: map[[2]int64]float64{{0, 1}: 1, {2, 3}: 3},
+	},
+	{
+		name:  "symmetric difference",
+		op:    WeightedSymmetricDifference,
+		nodes: []int64{0, 1, 2, 3, 4, 5},
+		edges: map[[2]int64]float64{{0, 1}: 1, {2, 3}: 3, {2, 4}: 20, {0, 4}: 30},
+	},
+}
+
+func TestWeightedSetOps(t *testing.T) {
+	for _, test := range setOpTests {
+		a := weightedUndirected(setA, 5)
+		b := weightedUndirected(setB)
+		dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		test.op(dst, a, b)
+		if got := nodeIDs(dst); !reflect.DeepEqual(got, test.nodes) {
+			t.Errorf("unexpected nodes for %s: got:%v want:%v", test.name, got, test.nodes)
+		}
+		if got := weightedEdges(dst); !reflect.DeepEqual(got, test.edges) {
+			t.Errorf("unexpected edges for %s: got:%v want:%v", test.name, got, test.edges)
+		}
+	}
+}
+
+func TestSetOpsDirected(t *testing.T) {
+	a := simple.NewDirectedGraph()
+	a.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	a.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	b := simple.NewDirectedGraph()
+	b.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	b.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+
+	u := simple.NewDirectedGraph()
+	Union(u, a, b)
+	if got := u.Edges().Len(); got != 3 {
+		t.Errorf("unexpected number of union edges: got:%d want:3", got)
+	}
+	i := simple.NewDirectedGraph()
+	Intersection(i, a, b)
+	if !i.HasEdgeFromTo(1, 2) || i.HasEdgeBetween(0, 1) {
+		t.Error("unexpected intersection edges")
+	}
+	d := simple.NewDirectedGraph()
+	Difference(d, a, b)
+	if !d.HasEdgeFromTo(0, 1) || d.HasEdgeFromTo(1, 0) || d.HasEdgeFromTo(1, 2) {
+		t.Error("unexpected difference edges")
+	}
+	s := simple.NewDirectedGraph()
+	SymmetricDifference(s, a, b)
+	if !s.HasEdgeFromTo(0, 1) || !s.HasEdgeFromTo(1, 0) || s.HasEdgeFromTo(1, 2) {
+		t.Error("unexpected symmetric difference edges")
+	}
+}
+
+func TestDisjointUnion(t *testing.T) {
+	a := weightedUndirected(setA, 5)
+	b := weightedUndirected(setB)
+	dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	ids := WeightedDisjointUnion(dst, a, b)
+
+	if got, want := dst.Nodes().Len(), a.Nodes().Len()+b.Nodes().Len(); got != want {
+		t.Errorf("unexpected number of nodes: got:%d want:%d", got, want)
+	}
+	if got, want := dst.Edges().Len(), len(setA)+len(setB); got != want {
+		t.Errorf("unexpected number of edges: got:%d want:%d", got, want)
+	}
+	if len(ids) != b.Nodes().Len() {
+		t.Errorf("unexpected number of relabeled nodes: got:%d want:%d", len(ids), b.Nodes().Len())
+	}
+	for e, w := range setA {
+		if got, ok := dst.Weight(e[0], e[1]); !ok || got != w {
+			t.Errorf("unexpected weight for edge %v of a: got:%v want:%v", e, got, w)
+		}
+	}
+	for e, w := range setB {
+		uid, vid := ids[e[0]], ids[e[1]]
+		if a.Node(uid) != nil || a.Node(vid) != nil {
+			t.Errorf("relabeled edge %v collides with node of a: (%d, %d)", e, uid, vid)
+		}
+		if got, ok := dst.Weight(uid, vid); !ok || got != w {
+			t.Errorf("unexpected weight for edge %v of b: got:%v want:%v", e, got, w)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+)
+
+// Subdivide writes the graph obtained by subdividing each edge of g k times
+// into dst. The nodes of g are copied into dst, and each edge u–v of g is
+// replaced by a path from u to v through k new nodes obtained from dst.
+// Subdivide returns the IDs of the new nodes on the path replacing each edge,
+// keyed by the IDs of the edge's from and to nodes. For undirected graphs
+// each edge is keyed once, with the lower ID first. Subdivide will panic if
+// k is negative.
+func Subdivide(dst graph.Builder, g graph.Graph, k int) map[[2]int64][]int64 {
+	return subdivide(dst, g, k, func(u, v graph.Node, _ graph.Edge) {
+		dst.SetEdge(dst.NewEdge(u, v))
+	})
+}
+
+// WeightedSubdivide writes the graph obtained by subdividing each edge of g
+// k times into dst as described for Subdivide. The weight of each edge of g
+// is divided equally among the k+1 edges of the path replacing it, so path
+// lengths are preserved. WeightedSubdivide will panic if k is negative.
+func WeightedSubdivide(dst graph.WeightedBuilder, g graph.Weighted, k int) map[[2]int64][]int64 {
+	return subdivide(dst, g, k, func(u, v graph.Node, e graph.Edge) {
+		w := e.(graph.WeightedEdge).Weight() / float64(k+1)
+		dst.SetWeightedEdge(dst.NewWeightedEdge(u, v, w))
+	})
+}
+
+func subdivide(dst graph.NodeAdder, g graph.Graph, k int, set func(u, v graph.Node, e graph.Edge)) map[[2]int64][]int64 {
+	if k < 0 {
+		panic("transform: negative subdivision count")
+	}
+	_, isDirected := g.(graph.Directed)
+	nodes := graph.NodesOf(g.Nodes())
+	sort.Sort(ordered.ByID(nodes))
+	for _, n := range nodes {
+		dst.AddNode(n)
+	}
+	paths := make(map[[2]int64][]int64)
+	for _, u := range nodes {
+		uid := u.ID()
+		to := graph.NodesOf(g.From(uid))
+		sort.Sort(ordered.ByID(to))
+		for _, v := range to {
+			vid := v.ID()
+			if !isDirected && vid < uid {
+				continue
+			}
+			e := edgeOf(g, uid, vid)
+			path := make([]int64, k)
+			last := u
+			for i := range path {
+				n := dst.NewNode()
+				dst.AddNode(n)
+				path[i] = n.ID()
+				set(last, n, e)
+				last = n
+			}
+			set(last, v, e)
+			paths[[2]int64{uid, vid}] = path
+		}
+	}
+	return paths
+}
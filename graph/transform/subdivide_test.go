@@ -0,0 +1,74 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func TestWeightedSubdivide(t *testing.T) {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(1), W: 3})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(1), T: simple.Node(2), W: 6})
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(0), T: simple.Node(2), W: 12})
+
+	for k := 0; k <= 3; k++ {
+		dst := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+		paths := WeightedSubdivide(dst, g, k)
+		if len(paths) != 3 {
+			t.Errorf("unexpected number of subdivided edges for k=%d: got:%d want:3", k, len(paths))
+		}
+		if got, want := dst.Nodes().Len(), 3+3*k; got != want {
+			t.Errorf("unexpected number of nodes for k=%d: got:%d want:%d", k, got, want)
+		}
+		if got, want := dst.Edges().Len(), 3*(k+1); got != want {
+			t.Errorf("unexpected number of edges for k=%d: got:%d want:%d", k, got, want)
+		}
+		for e, p := range paths {
+			if len(p) != k {
+				t.Errorf("unexpected path length for edge %v with k=%d: got:%d", e, k, len(p))
+			}
+		}
+		pt := path.DijkstraAllPaths(dst)
+		for _, test := range []struct {
+			u, v int64
+			want float64
+		}{
+			{u: 0, v: 1, want: 3},
+			{u: 1, v: 2, want: 6},
+			{u: 0, v: 2, want: 9},
+		} {
+			if got := pt.Weight(test.u, test.v); math.Abs(got-test.want) > 1e-12 {
+				t.Errorf("unexpected distance between %d and %d for k=%d: got:%v want:%v", test.u, test.v, k, got, test.want)
+			}
+		}
+	}
+}
+
+func TestSubdivideDirected(t *testing.T) {
+	g := simple.NewDirectedGraph()
+	g.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	g.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	dst := simple.NewDirectedGraph()
+	paths := Subdivide(dst, g, 1)
+	if len(paths) != 2 {
+		t.Fatalf("unexpected number of subdivided edges: got:%d want:2", len(paths))
+	}
+	for e, p := range paths {
+		if !dst.HasEdgeFromTo(e[0], p[0]) || !dst.HasEdgeFromTo(p[0], e[1]) {
+			t.Errorf("missing directed path for edge %v through %v", e, p)
+		}
+		if dst.HasEdgeFromTo(p[0], e[0]) {
+			t.Errorf("unexpected reversed edge for edge %v through %v", e, p)
+		}
+	}
+	if dst.HasEdgeBetween(0, 1) {
+		t.Error("unexpected original edge in subdivided graph")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import "gonum.org/v1/gonum/graph"
+
+var (
+	_ graph.Graph            = Directed{}
+	_ graph.Directed         = Directed{}
+	_ graph.Graph            = WeightedDirected{}
+	_ graph.Directed         = WeightedDirected{}
+	_ graph.Weighted         = WeightedDirected{}
+	_ graph.WeightedDirected = WeightedDirected{}
+)
+
+// Directed is a filtered view of a directed graph.
+type Directed struct {
+	g graph.Directed
+	filter
+}
+
+// NewDirected returns a view of g including only the nodes accepted by
+// nodes and the edges accepted by edges that join included nodes. A nil
+// filter includes all nodes or edges.
+func NewDirected(g graph.Directed, nodes NodeFilter, edges EdgeFilter) Directed {
+	return Directed{g: g, filter: newFilter(nodes, edges)}
+}
+
+// InducedDirected returns a view of the subgraph of g induced by the nodes
+// with the given IDs. Node iteration over the returned view takes time
+// proportional to the number of IDs rather than the order of g.
+func InducedDirected(g graph.Directed, ids []int64) Directed {
+	return Directed{g: g, filter: newInducedFilter(ids)}
+}
+
+// Node returns the node with the given ID if it exists in the view,
+// and nil otherwise.
+func (g Directed) Node(id int64) graph.Node { return g.node(g.g, id) }
+
+// Nodes returns all the nodes in the view.
+func (g Directed) Nodes() graph.Nodes { return g.allNodes(g.g) }
+
+// From returns all nodes in the view that can be reached directly from
+// the node with the given ID.
+func (g Directed) From(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	return newNodes(g.g.From(id), func(v graph.Node) bool {
+		return g.Edge(id, v.ID()) != nil
+	})
+}
+
+// To returns all nodes in the view that can reach directly to the node with
+// the given ID.
+func (g Directed) To(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	return newNodes(g.g.To(id), func(u graph.Node) bool {
+		return g.Edge(u.ID(), id) != nil
+	})
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid in the view without considering direction.
+func (g Directed) HasEdgeBetween(xid, yid int64) bool {
+	return g.HasEdgeFromTo(xid, yid) || g.HasEdgeFromTo(yid, xid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the view from u to v.
+func (g Directed) HasEdgeFromTo(uid, vid int64) bool {
+	return g.Edge(uid, vid) != nil
+}
+
+// Edge returns the edge from u to v if such an edge exists in the view and
+// nil otherwise.
+func (g Directed) Edge(uid, vid int64) graph.Edge {
+	return g.edge(g.g.Edge(uid, vid))
+}
+
+// WeightedDirected is a filtered view of a weighted directed graph.
+type WeightedDirected struct {
+	g graph.WeightedDirected
+	filter
+}
+
+// NewWeightedDirected returns a view of g including only the nodes accepted
+// by nodes and the edges accepted by edges that join included nodes. A nil
+// filter includes all nodes or edges.
+func NewWeightedDirected(g graph.WeightedDirected, nodes NodeFilter, edges EdgeFilter) WeightedDirected {
+	return WeightedDirected{g: g, filter: newFilter(nodes, edges)}
+}
+
+// InducedWeightedDirected returns a view of the subgraph of g induced by the
+// nodes with the given IDs. Node iteration over the returned view takes time
+// proportional to the number of IDs rather than the order of g.
+func InducedWeightedDirected(g graph.WeightedDirected, ids []int64) WeightedDirected {
+	return WeightedDirected{g: g, filter: newInducedFilter(ids)}
+}
+
+// Node returns the node with the given ID if it exists in the view,
+// and nil otherwise.
+func (g WeightedDirected) Node(id int64) graph.Node { return g.node(g.g, id) }
+
+// Nodes returns all the nodes in the view.
+func (g WeightedDirected) Nodes() graph.Nodes { return g.allNodes(g.g) }
+
+// From returns all nodes in the view that can be reached directly from
+// the node with the given ID.
+func (g WeightedDirected) From(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	return newNodes(g.g.From(id), func(v graph.Node) bool {
+		return g.Edge(id, v.ID()) != nil
+	})
+}
+
+// To returns all nodes in the view that can reach directly to the node with
+// the given ID.
+func (g WeightedDirected) To(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	return newNodes(g.g.To(id), func(u graph.Node) bool {
+		return g.Edge(u.ID(), id) != nil
+	})
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid in the view without considering direction.
+func (g WeightedDirected) HasEdgeBetween(xid, yid int64) bool {
+	return g.HasEdgeFromTo(xid, yid) || g.HasEdgeFromTo(yid, xid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the view from u to v.
+func (g WeightedDirected) HasEdgeFromTo(uid, vid int64) bool {
+	return g.Edge(uid, vid) != nil
+}
+
+// Edge returns the edge from u to v if such an edge exists in the view and
+// nil otherwise.
+func (g WeightedDirected) Edge(uid, vid int64) graph.Edge {
+	return g.edge(g.g.Edge(uid, vid))
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// in the view and nil otherwise.
+func (g WeightedDirected) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	e := g.g.WeightedEdge(uid, vid)
+	if e == nil || g.edge(e) == nil {
+		return nil
+	}
+	return e
+}
+
+// Weight returns the weight for the edge from x to y if Edge(x, y) returns
+// a non-nil Edge. If x and y are the same node included in the view, the
+// underlying graph's weight is returned. If there is no joining edge in the
+// view between the two nodes, the weight value returned is zero. Weight
+// returns true if an edge exists from x to y in the view or if x and y have
+// the same ID and are included in the view, false otherwise.
+func (g WeightedDirected) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		if g.Node(xid) == nil {
+			return 0, false
+		}
+		return g.g.Weight(xid, yid)
+	}
+	if g.Edge(xid, yid) == nil {
+		return 0, false
+	}
+	return g.g.Weight(xid, yid)
+}
@@ -0,0 +1,15 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package view provides lazily evaluated filtered views of graphs.
+//
+// The view types in this package implement the graph interfaces by
+// filtering the nodes and edges of an underlying graph on demand, so
+// algorithms can be run on subgraphs of large graphs without copying.
+// Changes to the underlying graph are reflected in its views.
+//
+// Views of the union, intersection, difference and symmetric difference of
+// pairs of graphs are also provided. Materialized equivalents are provided
+// by the graph/transform package.
+package view // import "gonum.org/v1/gonum/graph/view"
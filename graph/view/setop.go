@@ -0,0 +1,339 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import "gonum.org/v1/gonum/graph"
+
+// SetOp is a set operation over the nodes and edges of two graphs.
+// Nodes and edges are identified by node ID.
+type SetOp int
+
+const (
+	// Union includes the nodes and edges present in either graph.
+	Union SetOp = iota
+	// Intersection includes the nodes and edges present in both graphs.
+	Intersection
+	// Difference includes the nodes of the first graph and the edges
+	// of the first graph that are not present in the second.
+	Difference
+	// SymmetricDifference includes the nodes present in either graph
+	// and the edges present in exactly one of the graphs.
+	SymmetricDifference
+)
+
+// nodes returns whether a node is included by the operation.
+func (op SetOp) nodes(inA, inB bool) bool {
+	switch op {
+	case Union, SymmetricDifference:
+		return inA || inB
+	case Intersection:
+		return inA && inB
+	case Difference:
+		return inA
+	default:
+		panic("view: invalid set operation")
+	}
+}
+
+// edges returns whether an edge is included by the operation.
+func (op SetOp) edges(inA, inB bool) bool {
+	switch op {
+	case Union:
+		return inA || inB
+	case Intersection:
+		return inA && inB
+	case Difference:
+		return inA && !inB
+	case SymmetricDifference:
+		return inA != inB
+	default:
+		panic("view: invalid set operation")
+	}
+}
+
+var (
+	_ graph.Graph              = SetUndirected{}
+	_ graph.Undirected         = SetUndirected{}
+	_ graph.WeightedUndirected = SetUndirected{}
+	_ graph.Graph              = SetDirected{}
+	_ graph.Directed           = SetDirected{}
+	_ graph.WeightedDirected   = SetDirected{}
+)
+
+// SetUndirected is a view of the result of a set operation on two
+// undirected graphs.
+type SetUndirected struct {
+	setOp
+}
+
+// NewSetUndirected returns a view of the result of applying op to a and b.
+// When a node is present in both a and b, the node from a is used.
+//
+// Edge weights are taken from the Weight method of a graph operand if it is a
+// graph.Weighted, and are otherwise 1. The weight of an edge present in both
+// a and b is the result of merge applied to the weights of the edge in a and
+// b. If merge is nil, the weight from a is used.
+func NewSetUndirected(op SetOp, a, b graph.Undirected, merge func(x, y float64) float64) SetUndirected {
+	return SetUndirected{newSetOp(op, a, b, merge)}
+}
+
+// EdgeBetween returns the edge between nodes x and y if it exists in the
+// view and nil otherwise.
+func (g SetUndirected) EdgeBetween(xid, yid int64) graph.Edge { return g.Edge(xid, yid) }
+
+// WeightedEdgeBetween returns the weighted edge between nodes x and y if it
+// exists in the view and nil otherwise.
+func (g SetUndirected) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	return g.WeightedEdge(xid, yid)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid in the view.
+func (g SetUndirected) HasEdgeBetween(xid, yid int64) bool {
+	return g.included(xid, yid)
+}
+
+// SetDirected is a view of the result of a set operation on two
+// directed graphs.
+type SetDirected struct {
+	setOp
+}
+
+// NewSetDirected returns a view of the result of applying op to a and b.
+// When a node is present in both a and b, the node from a is used.
+//
+// Edge weights are taken from the Weight method of a graph operand if it is a
+// graph.Weighted, and are otherwise 1. The weight of an edge present in both
+// a and b is the result of merge applied to the weights of the edge in a and
+// b. If merge is nil, the weight from a is used.
+func NewSetDirected(op SetOp, a, b graph.Directed, merge func(x, y float64) float64) SetDirected {
+	return SetDirected{newSetOp(op, a, b, merge)}
+}
+
+// To returns all nodes in the view that can reach directly to the node with
+// the given ID.
+func (g SetDirected) To(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	a := g.a.(graph.Directed)
+	b := g.b.(graph.Directed)
+	return chain(
+		newNodes(a.To(id), func(u graph.Node) bool {
+			return g.included(u.ID(), id)
+		}),
+		newNodes(b.To(id), func(u graph.Node) bool {
+			return !a.HasEdgeFromTo(u.ID(), id) && g.included(u.ID(), id)
+		}),
+	)
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid in the view without considering direction.
+func (g SetDirected) HasEdgeBetween(xid, yid int64) bool {
+	return g.included(xid, yid) || g.included(yid, xid)
+}
+
+// HasEdgeFromTo returns whether an edge exists in the view from u to v.
+func (g SetDirected) HasEdgeFromTo(uid, vid int64) bool {
+	return g.included(uid, vid)
+}
+
+// setOp holds the behavior common to undirected and directed set
+// operation views.
+type setOp struct {
+	op    SetOp
+	a, b  graph.Graph
+	merge func(x, y float64) float64
+}
+
+func newSetOp(op SetOp, a, b graph.Graph, merge func(x, y float64) float64) setOp {
+	op.nodes(false, false) // Check op is valid.
+	if merge == nil {
+		merge = func(x, _ float64) float64 { return x }
+	}
+	return setOp{op: op, a: a, b: b, merge: merge}
+}
+
+// Node returns the node with the given ID if it exists in the view,
+// and nil otherwise.
+func (g setOp) Node(id int64) graph.Node {
+	na := g.a.Node(id)
+	nb := g.b.Node(id)
+	if !g.op.nodes(na != nil, nb != nil) {
+		return nil
+	}
+	if na != nil {
+		return na
+	}
+	return nb
+}
+
+// Nodes returns all the nodes in the view.
+func (g setOp) Nodes() graph.Nodes {
+	return chain(
+		newNodes(g.a.Nodes(), func(n graph.Node) bool {
+			return g.op.nodes(true, g.b.Node(n.ID()) != nil)
+		}),
+		newNodes(g.b.Nodes(), func(n graph.Node) bool {
+			return g.a.Node(n.ID()) == nil && g.op.nodes(false, true)
+		}),
+	)
+}
+
+// From returns all nodes in the view that can be reached directly from
+// the node with the given ID.
+func (g setOp) From(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	return chain(
+		newNodes(g.a.From(id), func(v graph.Node) bool {
+			return g.included(id, v.ID())
+		}),
+		newNodes(g.b.From(id), func(v graph.Node) bool {
+			return g.a.Edge(id, v.ID()) == nil && g.included(id, v.ID())
+		}),
+	)
+}
+
+// Edge returns the edge from u to v if such an edge exists in the view and
+// nil otherwise.
+func (g setOp) Edge(uid, vid int64) graph.Edge {
+	e := g.WeightedEdge(uid, vid)
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// in the view and nil otherwise. The returned edge's end points are the
+// nodes of the view.
+func (g setOp) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	w, ok := g.edgeWeight(uid, vid)
+	if !ok {
+		return nil
+	}
+	return weightedEdge{F: g.Node(uid), T: g.Node(vid), W: w}
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y)
+// returns a non-nil Edge. If x and y are the same node included in the
+// view, the weight returned is the self weight of the graph operand that
+// provides the node, or zero if that operand is not weighted. If there
+// is no joining edge in the view between the two nodes, the weight value
+// returned is zero. Weight returns true if an edge exists between x and y
+// in the view or if x and y have the same ID and are included in the view,
+// false otherwise.
+func (g setOp) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		if g.Node(xid) == nil {
+			return 0, false
+		}
+		src := g.a
+		if src.Node(xid) == nil {
+			src = g.b
+		}
+		if wg, isWeighted := src.(graph.Weighted); isWeighted {
+			return wg.Weight(xid, yid)
+		}
+		return 0, true
+	}
+	return g.edgeWeight(xid, yid)
+}
+
+// included returns whether the edge from u to v is in the view.
+func (g setOp) included(uid, vid int64) bool {
+	return g.op.edges(g.a.Edge(uid, vid) != nil, g.b.Edge(uid, vid) != nil)
+}
+
+// edgeWeight returns the weight of the edge from u to v in the view
+// and whether the edge exists.
+func (g setOp) edgeWeight(uid, vid int64) (w float64, ok bool) {
+	inA := g.a.Edge(uid, vid) != nil
+	inB := g.b.Edge(uid, vid) != nil
+	if !g.op.edges(inA, inB) {
+		return 0, false
+	}
+	switch {
+	case inA && inB:
+		return g.merge(weightOf(g.a, uid, vid), weightOf(g.b, uid, vid)), true
+	case inA:
+		return weightOf(g.a, uid, vid), true
+	default:
+		return weightOf(g.b, uid, vid), true
+	}
+}
+
+// weightOf returns the weight of the edge from u to v in g, or
+// 1 if g is not a graph.Weighted.
+func weightOf(g graph.Graph, uid, vid int64) float64 {
+	wg, ok := g.(graph.Weighted)
+	if !ok {
+		return 1
+	}
+	w, _ := wg.Weight(uid, vid)
+	return w
+}
+
+// weightedEdge is a weighted edge returned by set operation views.
+type weightedEdge struct {
+	F, T graph.Node
+	W    float64
+}
+
+func (e weightedEdge) From() graph.Node         { return e.F }
+func (e weightedEdge) To() graph.Node           { return e.T }
+func (e weightedEdge) ReversedEdge() graph.Edge { return weightedEdge{F: e.T, T: e.F, W: e.W} }
+func (e weightedEdge) Weight() float64          { return e.W }
+
+// chained is a node iterator that iterates over a sequence of iterators.
+type chained struct {
+	its []graph.Nodes
+	idx int
+}
+
+// chain returns an iterator over the nodes of each of its in turn.
+func chain(its ...graph.Nodes) graph.Nodes {
+	return &chained{its: its}
+}
+
+// Len returns the number of nodes remaining in the iterator.
+func (c *chained) Len() int {
+	var n int
+	for _, it := range c.its[c.idx:] {
+		n += it.Len()
+	}
+	return n
+}
+
+// Next advances the iterator and returns whether the next call to Node
+// will return a non-nil node.
+func (c *chained) Next() bool {
+	for c.idx < len(c.its) {
+		if c.its[c.idx].Next() {
+			return true
+		}
+		c.idx++
+	}
+	return false
+}
+
+// Node returns the current node of the iterator.
+func (c *chained) Node() graph.Node {
+	if c.idx >= len(c.its) {
+		return nil
+	}
+	return c.its[c.idx].Node()
+}
+
+// Reset returns the iterator to its start position.
+func (c *chained) Reset() {
+	for _, it := range c.its {
+		it.Reset()
+	}
+	c.idx = 0
+}
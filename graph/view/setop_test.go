@@ -0,0 +1,122 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+func weightedUndirected(edges map[[2]int64]float64, nodes ...int64) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for _, id := range nodes {
+		g.AddNode(simple.Node(id))
+	}
+	for e, w := range edges {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(e[0]), T: simple.Node(e[1]), W: w})
+	}
+	return g
+}
+
+// weightedEdges returns the weighted edges of g as a map keyed by
+// ordered node ID pairs.
+func weightedEdges(g graph.WeightedUndirected) map[[2]int64]float64 {
+	edges := make(map[[2]int64]float64)
+	nodes := g.Nodes()
+	for nodes.Next() {
+		uid := nodes.Node().ID()
+		to := g.From(uid)
+		for to.Next() {
+			vid := to.Node().ID()
+			if vid < uid {
+				continue
+			}
+			edges[[2]int64{uid, vid}] = g.WeightedEdgeBetween(uid, vid).Weight()
+		}
+	}
+	return edges
+}
+
+func TestSetUndirected(t *testing.T) {
+	a := weightedUndirected(map[[2]int64]float64{{0, 1}: 1, {1, 2}: 2, {2, 3}: 3}, 5)
+	b := weightedUndirected(map[[2]int64]float64{{1, 2}: 10, {2, 4}: 20, {0, 4}: 30})
+	sum := func(x, y float64) float64 { return x + y }
+
+	for _, test := range []struct {
+		op    SetOp
+		merge func(x, y float64) float64
+		nodes []int64
+		edges map[[2]int64]float64
+	}{
+		{
+			op:    Union,
+			merge: sum,
+			nodes: []int64{0, 1, 2, 3, 4, 5},
+			edges: map[[2]int64]float64{{0, 1}: 1, {1, 2}: 12, {2, 3}: 3, {2, 4}: 20, {0, 4}: 30},
+		},
+		{
+			op:    Intersection,
+			nodes: []int64{0, 1, 2},
+			edges: map[[2]int64]float64{{1, 2}: 2},
+		},
+		{
+			op:    Difference,
+			nodes: []int64{0, 1, 2, 3, 5},
+			edges: map[[2]int64]float64{{0, 1}: 1, {2, 3}: 3},
+		},
+		{
+			op:    SymmetricDifference,
+			nodes: []int64{0, 1, 2, 3, 4, 5},
+			edges: map[[2]int64]float64{{0, 1}: 1, {2, 3}: 3, {2, 4}: 20, {0, 4}: 30},
+		},
+	} {
+		g := NewSetUndirected(test.op, a, b, test.merge)
+		it := g.Nodes()
+		if it.Len() != len(test.nodes) {
+			t.Errorf("unexpected number of nodes for op %d: got:%d want:%d", test.op, it.Len(), len(test.nodes))
+		}
+		if got := ids(it); !reflect.DeepEqual(got, test.nodes) {
+			t.Errorf("unexpected nodes for op %d: got:%v want:%v", test.op, got, test.nodes)
+		}
+		if got := weightedEdges(g); !reflect.DeepEqual(got, test.edges) {
+			t.Errorf("unexpected edges for op %d: got:%v want:%v", test.op, got, test.edges)
+		}
+		for e := range test.edges {
+			if !g.HasEdgeBetween(e[1], e[0]) {
+				t.Errorf("missing reverse edge for op %d: %v", test.op, e)
+			}
+		}
+	}
+}
+
+func TestSetDirected(t *testing.T) {
+	a := simple.NewDirectedGraph()
+	a.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	a.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	b := simple.NewDirectedGraph()
+	b.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	b.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+
+	g := NewSetDirected(SymmetricDifference, a, b, nil)
+	if got, want := ids(g.From(1)), []int64{0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes from 1: got:%v want:%v", got, want)
+	}
+	if got, want := ids(g.To(0)), []int64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes to 0: got:%v want:%v", got, want)
+	}
+	if got, want := ids(g.To(1)), []int64{0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes to 1: got:%v want:%v", got, want)
+	}
+	if w, ok := g.Weight(0, 1); !ok || w != 1 {
+		t.Errorf("unexpected weight for unweighted edge: got:(%v, %t) want:(1, true)", w, ok)
+	}
+	if g.HasEdgeFromTo(1, 2) {
+		t.Error("unexpected edge from 1 to 2")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import "gonum.org/v1/gonum/graph"
+
+var (
+	_ graph.Graph              = Undirected{}
+	_ graph.Undirected         = Undirected{}
+	_ graph.Graph              = WeightedUndirected{}
+	_ graph.Undirected         = WeightedUndirected{}
+	_ graph.Weighted           = WeightedUndirected{}
+	_ graph.WeightedUndirected = WeightedUndirected{}
+)
+
+// Undirected is a filtered view of an undirected graph.
+type Undirected struct {
+	g graph.Undirected
+	filter
+}
+
+// NewUndirected returns a view of g including only the nodes accepted by
+// nodes and the edges accepted by edges that join included nodes. A nil
+// filter includes all nodes or edges.
+func NewUndirected(g graph.Undirected, nodes NodeFilter, edges EdgeFilter) Undirected {
+	return Undirected{g: g, filter: newFilter(nodes, edges)}
+}
+
+// InducedUndirected returns a view of the subgraph of g induced by the nodes
+// with the given IDs. Node iteration over the returned view takes time
+// proportional to the number of IDs rather than the order of g.
+func InducedUndirected(g graph.Undirected, ids []int64) Undirected {
+	return Undirected{g: g, filter: newInducedFilter(ids)}
+}
+
+// Node returns the node with the given ID if it exists in the view,
+// and nil otherwise.
+func (g Undirected) Node(id int64) graph.Node { return g.node(g.g, id) }
+
+// Nodes returns all the nodes in the view.
+func (g Undirected) Nodes() graph.Nodes { return g.allNodes(g.g) }
+
+// From returns all nodes in the view that can be reached directly from
+// the node with the given ID.
+func (g Undirected) From(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	return newNodes(g.g.From(id), func(v graph.Node) bool {
+		return g.edge(g.g.EdgeBetween(id, v.ID())) != nil
+	})
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid in the view.
+func (g Undirected) HasEdgeBetween(xid, yid int64) bool {
+	return g.EdgeBetween(xid, yid) != nil
+}
+
+// Edge returns the edge from u to v if such an edge exists in the view and
+// nil otherwise.
+func (g Undirected) Edge(uid, vid int64) graph.Edge { return g.EdgeBetween(uid, vid) }
+
+// EdgeBetween returns the edge between nodes x and y if it exists in the
+// view and nil otherwise.
+func (g Undirected) EdgeBetween(xid, yid int64) graph.Edge {
+	return g.edge(g.g.EdgeBetween(xid, yid))
+}
+
+// WeightedUndirected is a filtered view of a weighted undirected graph.
+type WeightedUndirected struct {
+	g graph.WeightedUndirected
+	filter
+}
+
+// NewWeightedUndirected returns a view of g including only the nodes
+// accepted by nodes and the edges accepted by edges that join included
+// nodes. A nil filter includes all nodes or edges.
+func NewWeightedUndirected(g graph.WeightedUndirected, nodes NodeFilter, edges EdgeFilter) WeightedUndirected {
+	return WeightedUndirected{g: g, filter: newFilter(nodes, edges)}
+}
+
+// InducedWeightedUndirected returns a view of the subgraph of g induced by
+// the nodes with the given IDs. Node iteration over the returned view takes
+// time proportional to the number of IDs rather than the order of g.
+func InducedWeightedUndirected(g graph.WeightedUndirected, ids []int64) WeightedUndirected {
+	return WeightedUndirected{g: g, filter: newInducedFilter(ids)}
+}
+
+// Node returns the node with the given ID if it exists in the view,
+// and nil otherwise.
+func (g WeightedUndirected) Node(id int64) graph.Node { return g.node(g.g, id) }
+
+// Nodes returns all the nodes in the view.
+func (g WeightedUndirected) Nodes() graph.Nodes { return g.allNodes(g.g) }
+
+// From returns all nodes in the view that can be reached directly from
+// the node with the given ID.
+func (g WeightedUndirected) From(id int64) graph.Nodes {
+	if g.Node(id) == nil {
+		return graph.Empty
+	}
+	return newNodes(g.g.From(id), func(v graph.Node) bool {
+		return g.EdgeBetween(id, v.ID()) != nil
+	})
+}
+
+// HasEdgeBetween returns whether an edge exists between nodes with IDs xid
+// and yid in the view.
+func (g WeightedUndirected) HasEdgeBetween(xid, yid int64) bool {
+	return g.EdgeBetween(xid, yid) != nil
+}
+
+// Edge returns the edge from u to v if such an edge exists in the view and
+// nil otherwise.
+func (g WeightedUndirected) Edge(uid, vid int64) graph.Edge { return g.EdgeBetween(uid, vid) }
+
+// EdgeBetween returns the edge between nodes x and y if it exists in the
+// view and nil otherwise.
+func (g WeightedUndirected) EdgeBetween(xid, yid int64) graph.Edge {
+	e := g.WeightedEdgeBetween(xid, yid)
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+// WeightedEdge returns the weighted edge from u to v if such an edge exists
+// in the view and nil otherwise.
+func (g WeightedUndirected) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	return g.WeightedEdgeBetween(uid, vid)
+}
+
+// WeightedEdgeBetween returns the weighted edge between nodes x and y if it
+// exists in the view and nil otherwise.
+func (g WeightedUndirected) WeightedEdgeBetween(xid, yid int64) graph.WeightedEdge {
+	e := g.g.WeightedEdgeBetween(xid, yid)
+	if e == nil || g.edge(e) == nil {
+		return nil
+	}
+	return e
+}
+
+// Weight returns the weight for the edge between x and y if Edge(x, y)
+// returns a non-nil Edge. If x and y are the same node included in the
+// view, the underlying graph's weight is returned. If there is no joining
+// edge in the view between the two nodes, the weight value returned is
+// zero. Weight returns true if an edge exists between x and y in the view
+// or if x and y have the same ID and are included in the view, false
+// otherwise.
+func (g WeightedUndirected) Weight(xid, yid int64) (w float64, ok bool) {
+	if xid == yid {
+		if g.Node(xid) == nil {
+			return 0, false
+		}
+		return g.g.Weight(xid, yid)
+	}
+	if g.EdgeBetween(xid, yid) == nil {
+		return 0, false
+	}
+	return g.g.Weight(xid, yid)
+}
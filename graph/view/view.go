@@ -0,0 +1,171 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/iterator"
+)
+
+// NodeFilter returns whether a node is included in a view.
+type NodeFilter func(graph.Node) bool
+
+// EdgeFilter returns whether an edge is included in a view. For views of
+// undirected graphs, an EdgeFilter must return the same result for an
+// edge and its reversal.
+type EdgeFilter func(graph.Edge) bool
+
+// NodeIDs returns a NodeFilter that includes nodes with IDs in ids.
+func NodeIDs(ids ...int64) NodeFilter {
+	s := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		s[id] = struct{}{}
+	}
+	return func(n graph.Node) bool {
+		_, ok := s[n.ID()]
+		return ok
+	}
+}
+
+// filter holds the node and edge filters of a view.
+type filter struct {
+	nodes NodeFilter
+	edges EdgeFilter
+
+	// ids holds the sorted IDs of the nodes
+	// in an induced subgraph view. If ids
+	// is non-nil, nodes filters on ids.
+	ids []int64
+}
+
+func newFilter(nodes NodeFilter, edges EdgeFilter) filter {
+	if nodes == nil {
+		nodes = func(graph.Node) bool { return true }
+	}
+	if edges == nil {
+		edges = func(graph.Edge) bool { return true }
+	}
+	return filter{nodes: nodes, edges: edges}
+}
+
+func newInducedFilter(ids []int64) filter {
+	s := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		s[id] = struct{}{}
+	}
+	sorted := make([]int64, 0, len(s))
+	for id := range s {
+		sorted = append(sorted, id)
+	}
+	sort.Sort(ordered.Int64s(sorted))
+	f := newFilter(func(n graph.Node) bool {
+		_, ok := s[n.ID()]
+		return ok
+	}, nil)
+	f.ids = sorted
+	return f
+}
+
+// node returns the node with the given ID in g if it exists
+// and is included by the filter, and nil otherwise.
+func (f filter) node(g graph.Graph, id int64) graph.Node {
+	n := g.Node(id)
+	if n == nil || !f.nodes(n) {
+		return nil
+	}
+	return n
+}
+
+// allNodes returns an iterator over the nodes of g included
+// by the filter.
+func (f filter) allNodes(g graph.Graph) graph.Nodes {
+	if f.ids == nil {
+		return newNodes(g.Nodes(), f.nodes)
+	}
+	var nodes []graph.Node
+	for _, id := range f.ids {
+		if n := g.Node(id); n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+	if len(nodes) == 0 {
+		return graph.Empty
+	}
+	return iterator.NewOrderedNodes(nodes)
+}
+
+// edge returns e if it is not nil and it and its end points
+// are included by the filter, and nil otherwise.
+func (f filter) edge(e graph.Edge) graph.Edge {
+	if e == nil || !f.nodes(e.From()) || !f.nodes(e.To()) || !f.edges(e) {
+		return nil
+	}
+	return e
+}
+
+// nodes is a lazily filtered node iterator.
+type nodes struct {
+	it    graph.Nodes
+	keep  func(graph.Node) bool
+	curr  graph.Node
+	pos   int // pos is the number of underlying nodes consumed.
+	taken int // taken is the number of filtered nodes consumed.
+	total int // total is the number of filtered nodes or -1 if unknown.
+}
+
+func newNodes(it graph.Nodes, keep func(graph.Node) bool) *nodes {
+	return &nodes{it: it, keep: keep, total: -1}
+}
+
+// Len returns the number of nodes remaining in the iterator. The first
+// call to Len requires a pass over the underlying iterator.
+func (n *nodes) Len() int {
+	if n.total < 0 {
+		n.it.Reset()
+		var total int
+		for n.it.Next() {
+			if n.keep(n.it.Node()) {
+				total++
+			}
+		}
+		n.it.Reset()
+		for i := 0; i < n.pos; i++ {
+			n.it.Next()
+		}
+		n.total = total
+	}
+	return n.total - n.taken
+}
+
+// Next advances the iterator and returns whether the next call to Node
+// will return a non-nil node.
+func (n *nodes) Next() bool {
+	for n.it.Next() {
+		n.pos++
+		if v := n.it.Node(); n.keep(v) {
+			n.curr = v
+			n.taken++
+			return true
+		}
+	}
+	n.curr = nil
+	return false
+}
+
+// Node returns the current node of the iterator.
+func (n *nodes) Node() graph.Node {
+	return n.curr
+}
+
+// Reset returns the iterator to its start position.
+func (n *nodes) Reset() {
+	n.it.Reset()
+	n.curr = nil
+	n.pos = 0
+	n.taken = 0
+}
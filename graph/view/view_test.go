@@ -0,0 +1,268 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package view
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/internal/ordered"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/testgraph"
+)
+
+// passThrough returns a testgraph.Builder that wraps the graphs built by
+// b in an unfiltered view.
+func passThrough(b testgraph.Builder, wrap func(graph.Graph) graph.Graph) testgraph.Builder {
+	return func(nodes []graph.Node, edges []testgraph.WeightedLine, self, absent float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+		g, n, e, s, a, ok := b(nodes, edges, self, absent)
+		if !ok {
+			return nil, nil, nil, math.NaN(), math.NaN(), false
+		}
+		return wrap(g), n, e, s, a, true
+	}
+}
+
+func undirectedBuilder(nodes []graph.Node, edges []testgraph.WeightedLine, self, absent float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+	g := simple.NewWeightedUndirectedGraph(self, absent)
+	return build(g, nodes, edges, self, absent)
+}
+
+func directedBuilder(nodes []graph.Node, edges []testgraph.WeightedLine, self, absent float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+	g := simple.NewWeightedDirectedGraph(self, absent)
+	return build(g, nodes, edges, self, absent)
+}
+
+func build(g interface {
+	graph.Graph
+	graph.NodeAdder
+	graph.WeightedEdgeAdder
+}, nodes []graph.Node, edges []testgraph.WeightedLine, self, absent float64) (graph.Graph, []graph.Node, []testgraph.Edge, float64, float64, bool) {
+	seen := make(map[int64]graph.Node)
+	for _, n := range nodes {
+		seen[n.ID()] = n
+		g.AddNode(n)
+	}
+	var e []testgraph.Edge
+	for _, edge := range edges {
+		if edge.From().ID() == edge.To().ID() {
+			continue
+		}
+		f := g.Node(edge.From().ID())
+		if f == nil {
+			f = edge.From()
+		}
+		t := g.Node(edge.To().ID())
+		if t == nil {
+			t = edge.To()
+		}
+		ce := simple.WeightedEdge{F: f, T: t, W: edge.Weight()}
+		seen[f.ID()] = f
+		seen[t.ID()] = t
+		e = append(e, ce)
+		g.SetWeightedEdge(ce)
+	}
+	if len(e) == 0 && len(edges) != 0 {
+		return nil, nil, nil, math.NaN(), math.NaN(), false
+	}
+	var n []graph.Node
+	for _, sn := range seen {
+		n = append(n, sn)
+	}
+	return g, n, e, self, absent, true
+}
+
+func TestPassThrough(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		builder testgraph.Builder
+	}{
+		{
+			name: "Undirected",
+			builder: passThrough(undirectedBuilder, func(g graph.Graph) graph.Graph {
+				return NewUndirected(g.(graph.Undirected), nil, nil)
+			}),
+		},
+		{
+			name: "WeightedUndirected",
+			builder: passThrough(undirectedBuilder, func(g graph.Graph) graph.Graph {
+				return NewWeightedUndirected(g.(graph.WeightedUndirected), nil, nil)
+			}),
+		},
+		{
+			name: "Directed",
+			builder: passThrough(directedBuilder, func(g graph.Graph) graph.Graph {
+				return NewDirected(g.(graph.Directed), nil, nil)
+			}),
+		},
+		{
+			name: "WeightedDirected",
+			builder: passThrough(directedBuilder, func(g graph.Graph) graph.Graph {
+				return NewWeightedDirected(g.(graph.WeightedDirected), nil, nil)
+			}),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("EdgeExistence", func(t *testing.T) {
+				testgraph.EdgeExistence(t, test.builder)
+			})
+			t.Run("NodeExistence", func(t *testing.T) {
+				testgraph.NodeExistence(t, test.builder)
+			})
+			t.Run("ReturnAdjacentNodes", func(t *testing.T) {
+				testgraph.ReturnAdjacentNodes(t, test.builder, false)
+			})
+			t.Run("ReturnAllNodes", func(t *testing.T) {
+				testgraph.ReturnAllNodes(t, test.builder, false)
+			})
+		})
+	}
+}
+
+// path returns a weighted undirected path graph with n nodes
+// where the edge between i and i+1 has weight i+1.
+func path(n int) *simple.WeightedUndirectedGraph {
+	g := simple.NewWeightedUndirectedGraph(0, math.Inf(1))
+	for i := 0; i < n-1; i++ {
+		g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(i), T: simple.Node(i + 1), W: float64(i + 1)})
+	}
+	return g
+}
+
+func ids(it graph.Nodes) []int64 {
+	var ids []int64
+	for it.Next() {
+		ids = append(ids, it.Node().ID())
+	}
+	sort.Sort(ordered.Int64s(ids))
+	return ids
+}
+
+func TestNodeFilter(t *testing.T) {
+	g := path(6)
+	even := func(n graph.Node) bool { return n.ID()%2 == 0 }
+	odd := func(n graph.Node) bool { return n.ID() != 3 }
+
+	v := NewWeightedUndirected(g, even, nil)
+	if got, want := ids(v.Nodes()), []int64{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes: got:%v want:%v", got, want)
+	}
+	if v.Node(1) != nil {
+		t.Error("unexpected node 1 in view")
+	}
+	if v.HasEdgeBetween(0, 1) {
+		t.Error("unexpected edge between 0 and 1 in view")
+	}
+	if w, ok := v.Weight(0, 1); w != 0 || ok {
+		t.Errorf("unexpected weight for excluded edge: got:(%v, %t) want:(0, false)", w, ok)
+	}
+	if w, ok := v.Weight(2, 2); w != 0 || !ok {
+		t.Errorf("unexpected self weight for included node: got:(%v, %t) want:(0, true)", w, ok)
+	}
+	if w, ok := v.Weight(1, 1); ok {
+		t.Errorf("unexpected self weight for excluded node: got:(%v, %t) want:(0, false)", w, ok)
+	}
+
+	v = NewWeightedUndirected(g, odd, nil)
+	if got, want := ids(v.From(2)), []int64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected neighbors of 2: got:%v want:%v", got, want)
+	}
+	if got := v.From(3); got.Len() != 0 {
+		t.Errorf("unexpected neighbors of excluded node: got:%d", got.Len())
+	}
+	if e := v.WeightedEdgeBetween(4, 5); e == nil || e.Weight() != 5 {
+		t.Errorf("unexpected edge between 4 and 5: got:%v", e)
+	}
+}
+
+func TestEdgeFilter(t *testing.T) {
+	g := path(6)
+	light := func(e graph.Edge) bool { return e.(graph.WeightedEdge).Weight() < 3 }
+	v := NewWeightedUndirected(g, nil, light)
+
+	if got, want := ids(v.Nodes()), []int64{0, 1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes: got:%v want:%v", got, want)
+	}
+	if got, want := ids(v.From(2)), []int64{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected neighbors of 2: got:%v want:%v", got, want)
+	}
+	if v.HasEdgeBetween(2, 3) || v.HasEdgeBetween(3, 2) {
+		t.Error("unexpected edge between 2 and 3 in view")
+	}
+	if !v.HasEdgeBetween(1, 2) || !v.HasEdgeBetween(2, 1) {
+		t.Error("missing edge between 1 and 2 in view")
+	}
+
+	d := simple.NewDirectedGraph()
+	d.SetEdge(simple.Edge{F: simple.Node(0), T: simple.Node(1)})
+	d.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(0)})
+	d.SetEdge(simple.Edge{F: simple.Node(1), T: simple.Node(2)})
+	forward := func(e graph.Edge) bool { return e.From().ID() < e.To().ID() }
+	dv := NewDirected(d, nil, forward)
+	if !dv.HasEdgeFromTo(0, 1) || dv.HasEdgeFromTo(1, 0) {
+		t.Error("unexpected directed edge filtering between 0 and 1")
+	}
+	if !dv.HasEdgeBetween(1, 0) {
+		t.Error("missing undirected edge between 1 and 0")
+	}
+	if got, want := ids(dv.To(0)), []int64(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes to 0: got:%v want:%v", got, want)
+	}
+	if got, want := ids(dv.From(1)), []int64{2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes from 1: got:%v want:%v", got, want)
+	}
+}
+
+func TestInduced(t *testing.T) {
+	g := path(10)
+	v := InducedWeightedUndirected(g, []int64{3, 2, 4, 7, 42, 3})
+	if got, want := ids(v.Nodes()), []int64{2, 3, 4, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected nodes: got:%v want:%v", got, want)
+	}
+	if got, want := ids(v.From(3)), []int64{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected neighbors of 3: got:%v want:%v", got, want)
+	}
+	if got := v.From(7).Len(); got != 0 {
+		t.Errorf("unexpected degree of 7: got:%d want:0", got)
+	}
+
+	// Changes to the underlying graph are visible in the view.
+	g.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(4), T: simple.Node(7), W: 1})
+	if !v.HasEdgeBetween(4, 7) {
+		t.Error("missing edge added to underlying graph")
+	}
+}
+
+func TestNodesLen(t *testing.T) {
+	g := path(10)
+	v := NewUndirected(g, func(n graph.Node) bool { return n.ID()%3 != 0 }, nil)
+	it := v.Nodes()
+	want := 6
+	for i := 0; i < 3; i++ {
+		if !it.Next() {
+			t.Fatalf("unexpected end of iteration at %d", i)
+		}
+	}
+	if got := it.Len(); got != want-3 {
+		t.Errorf("unexpected remaining length: got:%d want:%d", got, want-3)
+	}
+	n := 3
+	for it.Next() {
+		n++
+	}
+	if n != want {
+		t.Errorf("unexpected number of nodes after Len: got:%d want:%d", n, want)
+	}
+	it.Reset()
+	if got := it.Len(); got != want {
+		t.Errorf("unexpected length after reset: got:%d want:%d", got, want)
+	}
+	if got := len(graph.NodesOf(v.Nodes())); got != want {
+		t.Errorf("unexpected number of nodes from NodesOf: got:%d want:%d", got, want)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !noasm,!appengine,!safe
+
+#include "textflag.h"
+
+// The kernels in this file are the AVX-512 implementations of the unitary
+// functions. They are reached by a tail call from the SSE2 implementations
+// when useAVX512 is set and so take the same arguments. Products and sums
+// are rounded separately, rather than fused, so that the results of the
+// kernels are identical to those of the SSE2 kernels. Reductions such as
+// DotUnitary have no AVX-512 implementation since the wider accumulators
+// would change the order of summation, and so the rounding of the results,
+// depending on the processor.
+
+// func axpyUnitaryAVX512(alpha float64, x, y []float64)
+TEXT ·axpyUnitaryAVX512(SB), NOSPLIT, $0
+	MOVQ    x_base+8(FP), SI  // SI = &x
+	MOVQ    y_base+32(FP), DI // DI = &y
+	MOVQ    x_len+16(FP), CX  // CX = min( len(x), len(y) )
+	CMPQ    y_len+40(FP), CX
+	CMOVQLE y_len+40(FP), CX
+	CMPQ    CX, $0            // if CX == 0 { return }
+	JE      axpy_end
+	XORQ    AX, AX            // i = 0
+
+	VBROADCASTSD alpha+0(FP), Z0 // Z0 = { alpha, ..., alpha }
+
+	MOVQ CX, BX
+	ANDQ $31, BX         // BX = CX % 32
+	SHRQ $5, CX          // CX = floor( CX / 32 )
+	JZ   axpy_loop8_start
+
+axpy_loop32: // do {  // y[i] += alpha * x[i] unrolled 32x.
+	VMULPD  (SI)(AX*8), Z0, Z1
+	VMULPD  64(SI)(AX*8), Z0, Z2
+	VMULPD  128(SI)(AX*8), Z0, Z3
+	VMULPD  192(SI)(AX*8), Z0, Z4
+	VADDPD  (DI)(AX*8), Z1, Z1
+	VADDPD  64(DI)(AX*8), Z2, Z2
+	VADDPD  128(DI)(AX*8), Z3, Z3
+	VADDPD  192(DI)(AX*8), Z4, Z4
+	VMOVUPD Z1, (DI)(AX*8)
+	VMOVUPD Z2, 64(DI)(AX*8)
+	VMOVUPD Z3, 128(DI)(AX*8)
+	VMOVUPD Z4, 192(DI)(AX*8)
+	ADDQ    $32, AX          // i += 32
+	DECQ    CX
+	JNZ     axpy_loop32      // } while --CX > 0
+
+axpy_loop8_start:
+	MOVQ BX, CX
+	ANDQ $7, BX          // BX = CX % 8
+	SHRQ $3, CX          // CX = floor( CX / 8 )
+	JZ   axpy_tail_start
+
+axpy_loop8: // do {  // y[i] += alpha * x[i] unrolled 8x.
+	VMULPD  (SI)(AX*8), Z0, Z1
+	VADDPD  (DI)(AX*8), Z1, Z1
+	VMOVUPD Z1, (DI)(AX*8)
+	ADDQ    $8, AX             // i += 8
+	DECQ    CX
+	JNZ     axpy_loop8         // } while --CX > 0
+
+axpy_tail_start:
+	CMPQ BX, $0 // if BX == 0 { return }
+	JE   axpy_end
+
+axpy_tail: // do {
+	VMOVSD (SI)(AX*8), X1     // X1 = x[i]
+	VMULSD X0, X1, X1         // X1 *= alpha
+	VADDSD (DI)(AX*8), X1, X1 // X1 += y[i]
+	VMOVSD X1, (DI)(AX*8)     // y[i] = X1
+	INCQ   AX                 // ++i
+	DECQ   BX
+	JNZ    axpy_tail          // } while --BX > 0
+
+axpy_end:
+	VZEROUPPER
+	RET
+
+// func axpyUnitaryToAVX512(dst []float64, alpha float64, x, y []float64)
+TEXT ·axpyUnitaryToAVX512(SB), NOSPLIT, $0
+	MOVQ    dst_base+0(FP), DI // DI = &dst
+	MOVQ    x_base+32(FP), SI  // SI = &x
+	MOVQ    y_base+56(FP), DX  // DX = &y
+	MOVQ    x_len+40(FP), CX   // CX = min( len(dst), len(x), len(y) )
+	CMPQ    y_len+64(FP), CX
+	CMOVQLE y_len+64(FP), CX
+	CMPQ    dst_len+8(FP), CX
+	CMOVQLE dst_len+8(FP), CX
+	CMPQ    CX, $0             // if CX == 0 { return }
+	JE      axpyto_end
+	XORQ    AX, AX             // i = 0
+
+	VBROADCASTSD alpha+24(FP), Z0 // Z0 = { alpha, ..., alpha }
+
+	MOVQ CX, BX
+	ANDQ $31, BX           // BX = CX % 32
+	SHRQ $5, CX            // CX = floor( CX / 32 )
+	JZ   axpyto_loop8_start
+
+axpyto_loop32: // do {  // dst[i] = alpha * x[i] + y[i] unrolled 32x.
+	VMULPD  (SI)(AX*8), Z0, Z1
+	VMULPD  64(SI)(AX*8), Z0, Z2
+	VMULPD  128(SI)(AX*8), Z0, Z3
+	VMULPD  192(SI)(AX*8), Z0, Z4
+	VADDPD  (DX)(AX*8), Z1, Z1
+	VADDPD  64(DX)(AX*8), Z2, Z2
+	VADDPD  128(DX)(AX*8), Z3, Z3
+	VADDPD  192(DX)(AX*8), Z4, Z4
+	VMOVUPD Z1, (DI)(AX*8)
+	VMOVUPD Z2, 64(DI)(AX*8)
+	VMOVUPD Z3, 128(DI)(AX*8)
+	VMOVUPD Z4, 192(DI)(AX*8)
+	ADDQ    $32, AX          // i += 32
+	DECQ    CX
+	JNZ     axpyto_loop32    // } while --CX > 0
+
+axpyto_loop8_start:
+	MOVQ BX, CX
+	ANDQ $7, BX            // BX = CX % 8
+	SHRQ $3, CX            // CX = floor( CX / 8 )
+	JZ   axpyto_tail_start
+
+axpyto_loop8: // do {  // dst[i] = alpha * x[i] + y[i] unrolled 8x.
+	VMULPD  (SI)(AX*8), Z0, Z1
+	VADDPD  (DX)(AX*8), Z1, Z1
+	VMOVUPD Z1, (DI)(AX*8)
+	ADDQ    $8, AX             // i += 8
+	DECQ    CX
+	JNZ     axpyto_loop8       // } while --CX > 0
+
+axpyto_tail_start:
+	CMPQ BX, $0 // if BX == 0 { return }
+	JE   axpyto_end
+
+axpyto_tail: // do {
+	VMOVSD (SI)(AX*8), X1     // X1 = x[i]
+	VMULSD X0, X1, X1         // X1 *= alpha
+	VADDSD (DX)(AX*8), X1, X1 // X1 += y[i]
+	VMOVSD X1, (DI)(AX*8)     // dst[i] = X1
+	INCQ   AX                 // ++i
+	DECQ   BX
+	JNZ    axpyto_tail        // } while --BX > 0
+
+axpyto_end:
+	VZEROUPPER
+	RET
+
+// func scalUnitaryAVX512(alpha float64, x []float64)
+TEXT ·scalUnitaryAVX512(SB), NOSPLIT, $0
+	MOVQ x_base+8(FP), SI // SI = &x
+	MOVQ x_len+16(FP), CX // CX = len(x)
+	CMPQ CX, $0           // if CX == 0 { return }
+	JE   scal_end
+	XORQ AX, AX           // i = 0
+
+	VBROADCASTSD alpha+0(FP), Z0 // Z0 = { alpha, ..., alpha }
+
+	MOVQ CX, BX
+	ANDQ $31, BX         // BX = CX % 32
+	SHRQ $5, CX          // CX = floor( CX / 32 )
+	JZ   scal_loop8_start
+
+scal_loop32: // do {  // x[i] *= alpha unrolled 32x.
+	VMULPD  (SI)(AX*8), Z0, Z1
+	VMULPD  64(SI)(AX*8), Z0, Z2
+	VMULPD  128(SI)(AX*8), Z0, Z3
+	VMULPD  192(SI)(AX*8), Z0, Z4
+	VMOVUPD Z1, (SI)(AX*8)
+	VMOVUPD Z2, 64(SI)(AX*8)
+	VMOVUPD Z3, 128(SI)(AX*8)
+	VMOVUPD Z4, 192(SI)(AX*8)
+	ADDQ    $32, AX           // i += 32
+	DECQ    CX
+	JNZ     scal_loop32       // } while --CX > 0
+
+scal_loop8_start:
+	MOVQ BX, CX
+	ANDQ $7, BX          // BX = CX % 8
+	SHRQ $3, CX          // CX = floor( CX / 8 )
+	JZ   scal_tail_start
+
+scal_loop8: // do {  // x[i] *= alpha unrolled 8x.
+	VMULPD  (SI)(AX*8), Z0, Z1
+	VMOVUPD Z1, (SI)(AX*8)
+	ADDQ    $8, AX             // i += 8
+	DECQ    CX
+	JNZ     scal_loop8         // } while --CX > 0
+
+scal_tail_start:
+	CMPQ BX, $0 // if BX == 0 { return }
+	JE   scal_end
+
+scal_tail: // do {
+	VMOVSD (SI)(AX*8), X1 // X1 = x[i]
+	VMULSD X0, X1, X1     // X1 *= alpha
+	VMOVSD X1, (SI)(AX*8) // x[i] = X1
+	INCQ   AX             // ++i
+	DECQ   BX
+	JNZ    scal_tail      // } while --BX > 0
+
+scal_end:
+	VZEROUPPER
+	RET
+
+// func mulAVX512(dst, s []float64)
+TEXT ·mulAVX512(SB), NOSPLIT, $0
+	MOVQ    dst_base+0(FP), DI // DI = &dst
+	MOVQ    s_base+24(FP), SI  // SI = &s
+	MOVQ    dst_len+8(FP), CX  // CX = min( len(dst), len(s) )
+	CMPQ    s_len+32(FP), CX
+	CMOVQLE s_len+32(FP), CX
+	CMPQ    CX, $0             // if CX == 0 { return }
+	JE      mul_end
+	XORQ    AX, AX             // i = 0
+
+	MOVQ CX, BX
+	ANDQ $31, BX        // BX = CX % 32
+	SHRQ $5, CX         // CX = floor( CX / 32 )
+	JZ   mul_loop8_start
+
+mul_loop32: // do {  // dst[i] *= s[i] unrolled 32x.
+	VMOVUPD (DI)(AX*8), Z1
+	VMOVUPD 64(DI)(AX*8), Z2
+	VMOVUPD 128(DI)(AX*8), Z3
+	VMOVUPD 192(DI)(AX*8), Z4
+	VMULPD  (SI)(AX*8), Z1, Z1
+	VMULPD  64(SI)(AX*8), Z2, Z2
+	VMULPD  128(SI)(AX*8), Z3, Z3
+	VMULPD  192(SI)(AX*8), Z4, Z4
+	VMOVUPD Z1, (DI)(AX*8)
+	VMOVUPD Z2, 64(DI)(AX*8)
+	VMOVUPD Z3, 128(DI)(AX*8)
+	VMOVUPD Z4, 192(DI)(AX*8)
+	ADDQ    $32, AX           // i += 32
+	DECQ    CX
+	JNZ     mul_loop32        // } while --CX > 0
+
+mul_loop8_start:
+	MOVQ BX, CX
+	ANDQ $7, BX         // BX = CX % 8
+	SHRQ $3, CX         // CX = floor( CX / 8 )
+	JZ   mul_tail_start
+
+mul_loop8: // do {  // dst[i] *= s[i] unrolled 8x.
+	VMOVUPD (DI)(AX*8), Z1
+	VMULPD  (SI)(AX*8), Z1, Z1
+	VMOVUPD Z1, (DI)(AX*8)
+	ADDQ    $8, AX             // i += 8
+	DECQ    CX
+	JNZ     mul_loop8          // } while --CX > 0
+
+mul_tail_start:
+	CMPQ BX, $0 // if BX == 0 { return }
+	JE   mul_end
+
+mul_tail: // do {
+	VMOVSD (DI)(AX*8), X1     // X1 = dst[i]
+	VMULSD (SI)(AX*8), X1, X1 // X1 *= s[i]
+	VMOVSD X1, (DI)(AX*8)     // dst[i] = X1
+	INCQ   AX                 // ++i
+	DECQ   BX
+	JNZ    mul_tail           // } while --BX > 0
+
+mul_end:
+	VZEROUPPER
+	RET
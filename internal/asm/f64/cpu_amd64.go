@@ -0,0 +1,50 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !noasm,!appengine,!safe
+
+package f64
+
+// useAVX512 indicates that the unitary kernels are performed with their
+// AVX-512 implementations. It is set at initialization from the features
+// reported by the processor and enabled by the operating system.
+var useAVX512 = hasAVX512()
+
+// cpuid executes the CPUID instruction with the given EAX and ECX inputs.
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// xgetbv returns the low and high halves of the XCR0 register.
+func xgetbv() (eax, edx uint32)
+
+// hasAVX512 returns whether the processor supports the AVX-512 foundation
+// instructions and the operating system saves the AVX-512 register state.
+func hasAVX512() bool {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 7 {
+		return false
+	}
+	const osxsave = 1 << 27
+	_, _, ecx1, _ := cpuid(1, 0)
+	if ecx1&osxsave == 0 {
+		return false
+	}
+	// The SSE, AVX, opmask and upper and high
+	// ZMM register states must all be enabled.
+	const zmmState = 1<<1 | 1<<2 | 1<<5 | 1<<6 | 1<<7
+	xcr0, _ := xgetbv()
+	if xcr0&zmmState != zmmState {
+		return false
+	}
+	const avx512f = 1 << 16
+	_, ebx7, _, _ := cpuid(7, 0)
+	return ebx7&avx512f != 0
+}
+
+// The following functions are the AVX-512 implementations of the unitary
+// kernels and are only called when useAVX512 is true.
+
+func axpyUnitaryAVX512(alpha float64, x, y []float64)
+func axpyUnitaryToAVX512(dst []float64, alpha float64, x, y []float64)
+func scalUnitaryAVX512(alpha float64, x []float64)
+func mulAVX512(dst, s []float64)
@@ -0,0 +1,27 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !noasm,!appengine,!safe
+
+package f64
+
+import "testing"
+
+func TestUnitaryKernelsSSE2(t *testing.T) {
+	if !useAVX512 {
+		t.Skip("AVX-512 kernels not in use")
+	}
+	useAVX512 = false
+	defer func() { useAVX512 = true }()
+	testUnitaryKernels(t)
+}
+
+func TestGemmRowSSE2(t *testing.T) {
+	if !useAVX512 {
+		t.Skip("AVX-512 kernels not in use")
+	}
+	useAVX512 = false
+	defer func() { useAVX512 = true }()
+	testGemmRow(t)
+}
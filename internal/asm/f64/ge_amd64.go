@@ -20,3 +20,19 @@ func GemvN(m, n uintptr, alpha float64, a []float64, lda uintptr, x []float64, i
 //  y = alpha * A^T * x + beta * y
 // where A is an m×n dense matrix, x and y are vectors, and alpha and beta are scalars.
 func GemvT(m, n uintptr, alpha float64, a []float64, lda uintptr, x []float64, incX uintptr, beta float64, y []float64, incY uintptr)
+
+// GemmRow computes
+//  c += alpha * a^T * B
+// where c is a row of the matrix C of length n, a is the corresponding row
+// of the matrix A of length k, and B is a k×n dense matrix. The products
+// for zero elements of alpha * a are skipped.
+func GemmRow(alpha float64, a, b []float64, ldb uintptr, c []float64) {
+	if useAVX512 {
+		gemmRowAVX512(alpha, a, b, ldb, c)
+		return
+	}
+	gemmRow(alpha, a, b, ldb, c)
+}
+
+// gemmRowAVX512 is the AVX-512 implementation of GemmRow.
+func gemmRowAVX512(alpha float64, a, b []float64, ldb uintptr, c []float64)
@@ -116,3 +116,12 @@ func GemvT(m, n uintptr, alpha float64, a []float64, lda uintptr, x []float64, i
 		ix += incX
 	}
 }
+
+// GemmRow computes
+//  c += alpha * a^T * B
+// where c is a row of the matrix C of length n, a is the corresponding row
+// of the matrix A of length k, and B is a k×n dense matrix. The products
+// for zero elements of alpha * a are skipped.
+func GemmRow(alpha float64, a, b []float64, ldb uintptr, c []float64) {
+	gemmRow(alpha, a, b, ldb, c)
+}
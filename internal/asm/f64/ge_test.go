@@ -6,7 +6,10 @@ package f64
 
 import (
 	"fmt"
+	"math"
 	"testing"
+
+	"golang.org/x/exp/rand"
 )
 
 var gerTests = []struct {
@@ -221,3 +224,62 @@ func gerData(m, n, incX, incY int) (x, y, a []float64) {
 	}
 	return x, y, a
 }
+
+func TestGemmRow(t *testing.T) {
+	testGemmRow(t)
+}
+
+// testGemmRow checks GemmRow against a reference loop, for row lengths
+// spanning the unrolled and tail sections of the kernels, with zero and
+// NaN elements to check that the products for zero elements of a are
+// skipped.
+func testGemmRow(t *testing.T) {
+	const (
+		gdVal = 0.5
+		gdLen = 3
+	)
+	rnd := rand.New(rand.NewSource(1))
+	nan := math.NaN()
+	for _, k := range []int{0, 1, 2, 5} {
+		for n := 0; n <= 70; n++ {
+			for _, ldb := range []int{n, n + 3} {
+				if ldb == 0 {
+					continue
+				}
+				alpha := rnd.NormFloat64()
+				a := randSlice(k, 1, rnd)[:k]
+				b := randSlice(k*ldb, 1, rnd)[:k*ldb]
+				if k > 1 {
+					// Row 1 of B is not used.
+					a[1] = 0
+					for j := 0; j < n; j++ {
+						b[ldb+j] = nan
+					}
+				}
+				cg := guardVector(randSlice(n, 1, rnd)[:n], gdVal, gdLen)
+				c := cg[gdLen : gdLen+n]
+
+				want := make([]float64, n)
+				for j := range want {
+					want[j] = c[j]
+					for l, v := range a {
+						tmp := alpha * v
+						if tmp != 0 {
+							want[j] += float64(tmp * b[l*ldb+j])
+						}
+					}
+				}
+				GemmRow(alpha, a, b, uintptr(ldb), c)
+				for j := range want {
+					if !same(c[j], want[j]) {
+						t.Errorf("unexpected GemmRow result for k=%d n=%d ldb=%d at %d: got:%v want:%v", k, n, ldb, j, c[j], want[j])
+						break
+					}
+				}
+				if !isValidGuard(cg, gdVal, gdLen) {
+					t.Errorf("guard violated in c for k=%d n=%d ldb=%d", k, n, ldb)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package f64
+
+// gemmRow is the reference implementation of GemmRow.
+func gemmRow(alpha float64, a, b []float64, ldb uintptr, c []float64) {
+	n := uintptr(len(c))
+	for l, v := range a {
+		tmp := alpha * v
+		if tmp != 0 {
+			AxpyUnitary(tmp, b[uintptr(l)*ldb:uintptr(l)*ldb+n], c)
+		}
+	}
+}
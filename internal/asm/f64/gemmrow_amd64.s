@@ -0,0 +1,146 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !noasm,!appengine,!safe
+
+#include "textflag.h"
+
+// gemmRowAVX512 holds a block of up to 32 elements of the row of C in
+// registers while the corresponding elements of each row of B are added
+// to it, so each element of C is loaded and stored once rather than once
+// for each row of B. The elements of C are updated in the same order and
+// with the same separately rounded products and sums as by gemmRow, so the
+// results are identical.
+
+// func gemmRowAVX512(alpha float64, a, b []float64, ldb uintptr, c []float64)
+TEXT ·gemmRowAVX512(SB), NOSPLIT, $0
+	MOVQ a_base+8(FP), R8   // R8 = &a
+	MOVQ a_len+16(FP), R9   // R9 = k = len(a)
+	MOVQ b_base+32(FP), R10 // R10 = &b
+	MOVQ ldb+56(FP), R11
+	SHLQ $3, R11            // R11 = ldb * sizeof(float64)
+	MOVQ c_base+64(FP), DI  // DI = &c
+	MOVQ c_len+72(FP), CX   // CX = n = len(c)
+	CMPQ R9, $0             // if k == 0 || n == 0 { return }
+	JE   gemm_end
+	CMPQ CX, $0
+	JE   gemm_end
+	XORQ AX, AX             // j = 0
+
+	VMOVSD alpha+0(FP), X0 // X0 = alpha
+	VXORPD X6, X6, X6      // X6 = 0
+
+	MOVQ CX, BX
+	ANDQ $31, BX         // BX = n % 32
+	SHRQ $5, CX          // CX = floor( n / 32 )
+	JZ   gemm_loop8_start
+
+gemm_loop32: // do {  // c[j:j+32] += alpha * a[l] * b[l, j:j+32] for each l.
+	VMOVUPD (DI)(AX*8), Z1
+	VMOVUPD 64(DI)(AX*8), Z2
+	VMOVUPD 128(DI)(AX*8), Z3
+	VMOVUPD 192(DI)(AX*8), Z4
+	LEAQ    (R10)(AX*8), SI  // SI = &b[0, j]
+	MOVQ    R8, R12          // R12 = &a[0]
+	MOVQ    R9, DX           // l = k
+
+gemm_inner32: // do {
+	VMULSD (R12), X0, X5    // X5 = tmp = alpha * a[l]
+	VUCOMISD X6, X5         // if tmp == 0 { goto gemm_skip32 }
+	JNE    gemm_do32
+	JNP    gemm_skip32
+
+gemm_do32:
+	VBROADCASTSD X5, Z5
+	VMULPD       (SI), Z5, Z7
+	VMULPD       64(SI), Z5, Z8
+	VMULPD       128(SI), Z5, Z9
+	VMULPD       192(SI), Z5, Z10
+	VADDPD       Z7, Z1, Z1
+	VADDPD       Z8, Z2, Z2
+	VADDPD       Z9, Z3, Z3
+	VADDPD       Z10, Z4, Z4
+
+gemm_skip32:
+	ADDQ $8, R12        // R12 = &a[l+1]
+	ADDQ R11, SI        // SI = &b[l+1, j]
+	DECQ DX
+	JNZ  gemm_inner32   // } while --l > 0
+
+	VMOVUPD Z1, (DI)(AX*8)
+	VMOVUPD Z2, 64(DI)(AX*8)
+	VMOVUPD Z3, 128(DI)(AX*8)
+	VMOVUPD Z4, 192(DI)(AX*8)
+	ADDQ    $32, AX          // j += 32
+	DECQ    CX
+	JNZ     gemm_loop32      // } while --CX > 0
+
+gemm_loop8_start:
+	MOVQ BX, CX
+	ANDQ $7, BX          // BX = n % 8
+	SHRQ $3, CX          // CX = floor( (n % 32) / 8 )
+	JZ   gemm_tail_start
+
+gemm_loop8: // do {  // c[j:j+8] += alpha * a[l] * b[l, j:j+8] for each l.
+	VMOVUPD (DI)(AX*8), Z1
+	LEAQ    (R10)(AX*8), SI // SI = &b[0, j]
+	MOVQ    R8, R12         // R12 = &a[0]
+	MOVQ    R9, DX          // l = k
+
+gemm_inner8: // do {
+	VMULSD   (R12), X0, X5 // X5 = tmp = alpha * a[l]
+	VUCOMISD X6, X5        // if tmp == 0 { goto gemm_skip8 }
+	JNE      gemm_do8
+	JNP      gemm_skip8
+
+gemm_do8:
+	VBROADCASTSD X5, Z5
+	VMULPD       (SI), Z5, Z7
+	VADDPD       Z7, Z1, Z1
+
+gemm_skip8:
+	ADDQ $8, R12       // R12 = &a[l+1]
+	ADDQ R11, SI       // SI = &b[l+1, j]
+	DECQ DX
+	JNZ  gemm_inner8   // } while --l > 0
+
+	VMOVUPD Z1, (DI)(AX*8)
+	ADDQ    $8, AX         // j += 8
+	DECQ    CX
+	JNZ     gemm_loop8     // } while --CX > 0
+
+gemm_tail_start:
+	CMPQ BX, $0 // if n % 8 == 0 { return }
+	JE   gemm_end
+
+gemm_tail: // do {  // c[j] += alpha * a[l] * b[l, j] for each l.
+	VMOVSD (DI)(AX*8), X1 // X1 = c[j]
+	LEAQ   (R10)(AX*8), SI // SI = &b[0, j]
+	MOVQ   R8, R12         // R12 = &a[0]
+	MOVQ   R9, DX          // l = k
+
+gemm_inner1: // do {
+	VMULSD   (R12), X0, X5 // X5 = tmp = alpha * a[l]
+	VUCOMISD X6, X5        // if tmp == 0 { goto gemm_skip1 }
+	JNE      gemm_do1
+	JNP      gemm_skip1
+
+gemm_do1:
+	VMULSD (SI), X5, X7 // X7 = tmp * b[l, j]
+	VADDSD X7, X1, X1   // X1 += X7
+
+gemm_skip1:
+	ADDQ $8, R12       // R12 = &a[l+1]
+	ADDQ R11, SI       // SI = &b[l+1, j]
+	DECQ DX
+	JNZ  gemm_inner1   // } while --l > 0
+
+	VMOVSD X1, (DI)(AX*8) // c[j] = X1
+	INCQ   AX             // ++j
+	DECQ   BX
+	JNZ    gemm_tail      // } while --BX > 0
+
+gemm_end:
+	VZEROUPPER
+	RET
@@ -89,6 +89,12 @@ func CumProd(dst, s []float64) []float64
 //  }
 func Div(dst, s []float64)
 
+// Mul is
+//  for i, v := range s {
+//  	dst[i] *= v
+//  }
+func Mul(dst, s []float64)
+
 // DivTo is
 //  for i, v := range s {
 //  	dst[i] = v / t[i]
@@ -102,6 +102,16 @@ func Div(dst, s []float64) {
 	}
 }
 
+// Mul is
+//  for i, v := range s {
+//  	dst[i] *= v
+//  }
+func Mul(dst, s []float64) {
+	for i, v := range s {
+		dst[i] *= v
+	}
+}
+
 // DivTo is
 //  for i, v := range s {
 //  	dst[i] = v / t[i]
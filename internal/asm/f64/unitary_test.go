@@ -0,0 +1,105 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package f64
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestUnitaryKernels(t *testing.T) {
+	testUnitaryKernels(t)
+}
+
+// testUnitaryKernels checks the unitary kernels against reference loops
+// for lengths spanning the unrolled and tail sections of the kernels and
+// for each alignment of their arguments.
+func testUnitaryKernels(t *testing.T) {
+	const (
+		maxLen = 70
+		gdVal  = 0.5
+		gdLen  = 3
+	)
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n <= maxLen; n++ {
+		for off := 0; off < 2; off++ {
+			alpha := rnd.NormFloat64()
+			x := guardVector(randSlice(n+off, 1, rnd)[off:], gdVal, gdLen)
+			y := guardVector(randSlice(n+1-off, 1, rnd)[1-off:], gdVal, gdLen)
+			dst := guardVector(make([]float64, n), gdVal, gdLen)
+			xs, ys, ds := x[gdLen:gdLen+n], y[gdLen:gdLen+n], dst[gdLen:gdLen+n]
+
+			want := make([]float64, n)
+			for i, v := range xs {
+				want[i] = ys[i]
+				want[i] += alpha * v
+			}
+			got := append([]float64(nil), ys...)
+			AxpyUnitary(alpha, xs, got)
+			for i := range want {
+				if !same(got[i], want[i]) {
+					t.Errorf("unexpected AxpyUnitary result for n=%d off=%d at %d: got:%v want:%v", n, off, i, got[i], want[i])
+					break
+				}
+			}
+
+			AxpyUnitaryTo(ds, alpha, xs, ys)
+			for i := range want {
+				if !same(ds[i], want[i]) {
+					t.Errorf("unexpected AxpyUnitaryTo result for n=%d off=%d at %d: got:%v want:%v", n, off, i, ds[i], want[i])
+					break
+				}
+			}
+			if !isValidGuard(dst, gdVal, gdLen) {
+				t.Errorf("guard violated by AxpyUnitaryTo for n=%d off=%d", n, off)
+			}
+
+			var wantDot, scale float64
+			for i, v := range xs {
+				wantDot += v * ys[i]
+				scale += math.Abs(v * ys[i])
+			}
+			gotDot := DotUnitary(xs, ys)
+			if math.Abs(gotDot-wantDot) > 1e-14*(scale+1) {
+				t.Errorf("unexpected DotUnitary result for n=%d off=%d: got:%v want:%v", n, off, gotDot, wantDot)
+			}
+
+			copy(ds, ys)
+			for i, v := range xs {
+				want[i] = ds[i] * v
+			}
+			Mul(ds, xs)
+			for i := range want {
+				if !same(ds[i], want[i]) {
+					t.Errorf("unexpected Mul result for n=%d off=%d at %d: got:%v want:%v", n, off, i, ds[i], want[i])
+					break
+				}
+			}
+
+			for i, v := range xs {
+				want[i] = v * alpha
+			}
+			ScalUnitary(alpha, xs)
+			for i := range want {
+				if !same(xs[i], want[i]) {
+					t.Errorf("unexpected ScalUnitary result for n=%d off=%d at %d: got:%v want:%v", n, off, i, xs[i], want[i])
+					break
+				}
+			}
+
+			if !isValidGuard(x, gdVal, gdLen) {
+				t.Errorf("guard violated in x for n=%d off=%d", n, off)
+			}
+			if !isValidGuard(y, gdVal, gdLen) {
+				t.Errorf("guard violated in y for n=%d off=%d", n, off)
+			}
+			if !isValidGuard(dst, gdVal, gdLen) {
+				t.Errorf("guard violated in dst for n=%d off=%d", n, off)
+			}
+		}
+	}
+}
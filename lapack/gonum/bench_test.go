@@ -10,4 +10,6 @@ import (
 	"gonum.org/v1/gonum/lapack/testlapack"
 )
 
-func BenchmarkDgeev(b *testing.B) { testlapack.DgeevBenchmark(b, impl) }
+func BenchmarkDgeev(b *testing.B)  { testlapack.DgeevBenchmark(b, impl) }
+func BenchmarkDhseqr(b *testing.B) { testlapack.DhseqrBenchmark(b, impl) }
+func BenchmarkDlasrt(b *testing.B) { testlapack.DlasrtBenchmark(b, impl) }
@@ -146,6 +146,9 @@ func (impl Implementation) Dlahqr(wantt, wantz bool, n, ilo, ihi int, h []float6
 	// have already converged. Either l = ilo or H[l,l-1] is negligible so
 	// that the matrix splits.
 	bi := blas64.Implementation()
+	// v holds the reflection vector of the double-shift QR steps. It is
+	// declared outside the main loop so that it is allocated once per call.
+	var v [3]float64
 	i := ihi
 	for i >= ilo {
 		l := ilo
@@ -268,7 +271,6 @@ func (impl Implementation) Dlahqr(wantt, wantz bool, n, ilo, ihi int, h []float6
 
 			// Look for two consecutive small subdiagonal elements.
 			var m int
-			var v [3]float64
 			for m = i - 2; m >= l; m-- {
 				// Determine the effect of starting the
 				// double-shift QR iteration at row m, and see
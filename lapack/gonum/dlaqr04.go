@@ -186,15 +186,15 @@ func (impl Implementation) Dlaqr04(wantt, wantz bool, n, ilo, ihi int, h []float
 	// Use small bulge multi-shift QR with aggressive early deflation on
 	// larger-than-tiny matrices.
 	var jbcmpz string
-	if wantt {
-		jbcmpz = "S"
-	} else {
-		jbcmpz = "E"
-	}
-	if wantz {
-		jbcmpz += "V"
-	} else {
-		jbcmpz += "N"
+	switch {
+	case wantt && wantz:
+		jbcmpz = "SV"
+	case wantt:
+		jbcmpz = "SN"
+	case wantz:
+		jbcmpz = "EV"
+	default:
+		jbcmpz = "EN"
 	}
 
 	var fname string
@@ -31,6 +31,9 @@ func (impl Implementation) Dlasrt(s lapack.Sort, n int, d []float64) {
 	case lapack.SortIncreasing:
 		sort.Float64s(d)
 	case lapack.SortDecreasing:
-		sort.Sort(sort.Reverse(sort.Float64Slice(d)))
+		sort.Float64s(d)
+		for i, j := 0, len(d)-1; i < j; i, j = i+1, j-1 {
+			d[i], d[j] = d[j], d[i]
+		}
 	}
 }
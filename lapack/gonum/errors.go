@@ -101,6 +101,7 @@ const (
 	badLenIpiv     = "lapack: bad length of ipiv"
 	badLenJpvt     = "lapack: bad length of jpvt"
 	badLenK        = "lapack: bad length of k"
+	badLenPiv      = "lapack: bad length of piv"
 	badLenSelected = "lapack: bad length of selected"
 	badLenSi       = "lapack: bad length of si"
 	badLenSr       = "lapack: bad length of sr"
@@ -409,6 +409,16 @@ func TestDpotf2(t *testing.T) {
 	testlapack.Dpotf2Test(t, impl)
 }
 
+func TestDpstf2(t *testing.T) {
+	t.Parallel()
+	testlapack.Dpstf2Test(t, impl)
+}
+
+func TestDpstrf(t *testing.T) {
+	t.Parallel()
+	testlapack.DpstrfTest(t, impl)
+}
+
 func TestDpotrf(t *testing.T) {
 	testlapack.DpotrfTest(t, impl)
 }
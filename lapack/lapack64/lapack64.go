@@ -13,6 +13,18 @@ import (
 
 var lapack64 lapack.Float64 = gonum.Implementation{}
 
+// dgeqp3er and dpstrfer are implemented by LAPACK implementations that
+// provide routines that are not part of lapack.Float64. The Gonum
+// implementation is used for implementations that do not provide them.
+type (
+	dgeqp3er interface {
+		Dgeqp3(m, n int, a []float64, lda int, jpvt []int, tau, work []float64, lwork int)
+	}
+	dpstrfer interface {
+		Dpstrf(uplo blas.Uplo, n int, a []float64, lda int, piv []int, tol float64, work []float64) (rank int, ok bool)
+	}
+)
+
 // Use sets the LAPACK float64 implementation to be used by subsequent BLAS calls.
 // The default implementation is native.Implementation.
 func Use(l lapack.Float64) {
@@ -73,6 +85,42 @@ func Potrs(t blas64.Triangular, b blas64.General) {
 	lapack64.Dpotrs(t.Uplo, t.N, b.Cols, t.Data, max(1, t.Stride), b.Data, max(1, b.Stride))
 }
 
+// Pstrf computes the Cholesky factorization with complete pivoting of an n×n
+// symmetric positive semidefinite matrix A.
+// The factorization has the form
+//  P^T * A * P = U^T * U if a.Uplo == blas.Upper, or
+//  P^T * A * P = L * L^T if a.Uplo == blas.Lower,
+// where U is an upper triangular matrix, L is lower triangular, and P is a
+// permutation matrix stored in piv such that P[piv[k],k] = 1.
+// The triangular matrix is returned in t, and the underlying data between
+// a and t is shared. Only the leading rank rows of U or columns of L hold
+// the factor.
+//
+// tol is the tolerance used to terminate the algorithm. If tol is negative,
+// n*eps*max(A[k,k]) is used instead.
+//
+// piv must have length n and work must have length at least 2*n, otherwise
+// Pstrf will panic.
+//
+// Pstrf returns the computed rank of A and whether the factorization of the
+// full matrix could be finished.
+//
+// If the implementation set by Use does not provide Dpstrf, the Gonum
+// implementation is used.
+func Pstrf(a blas64.Symmetric, piv []int, tol float64, work []float64) (t blas64.Triangular, rank int, ok bool) {
+	impl, isDpstrfer := lapack64.(dpstrfer)
+	if !isDpstrfer {
+		impl = gonum.Implementation{}
+	}
+	rank, ok = impl.Dpstrf(a.Uplo, a.N, a.Data, max(1, a.Stride), piv, tol, work)
+	t.Uplo = a.Uplo
+	t.N = a.N
+	t.Data = a.Data
+	t.Stride = a.Stride
+	t.Diag = blas.NonUnit
+	return
+}
+
 // Gecon estimates the reciprocal of the condition number of the n×n matrix A
 // given the LU decomposition of the matrix. The condition number computed may
 // be based on the 1-norm or the ∞-norm.
@@ -147,6 +195,31 @@ func Geqrf(a blas64.General, tau, work []float64, lwork int) {
 	lapack64.Dgeqrf(a.Rows, a.Cols, a.Data, max(1, a.Stride), tau, work, lwork)
 }
 
+// Geqp3 computes the QR factorization with column pivoting of the m×n
+// matrix A, A*P = Q*R. A is modified to contain the information to construct
+// Q and R as described for Geqrf, and P is stored in jpvt such that the jth
+// column of A*P is the jpvt[j] column of A.
+//
+// On entry, if jpvt[j] is at least zero the jth column of A is permuted to
+// the front of A*P, and if jpvt[j] is -1 the jth column of A is a free column.
+// jpvt must have length n and tau must have length min(m,n), otherwise Geqp3
+// will panic.
+//
+// Work is temporary storage, and lwork specifies the usable memory length.
+// At minimum, lwork >= 3*n+1 and this function will panic otherwise.
+// If lwork == -1, instead of performing Geqp3, the optimal work length
+// will be stored into work[0].
+//
+// If the implementation set by Use does not provide Dgeqp3, the Gonum
+// implementation is used.
+func Geqp3(a blas64.General, jpvt []int, tau, work []float64, lwork int) {
+	impl, ok := lapack64.(dgeqp3er)
+	if !ok {
+		impl = gonum.Implementation{}
+	}
+	impl.Dgeqp3(a.Rows, a.Cols, a.Data, max(1, a.Stride), jpvt, tau, work, lwork)
+}
+
 // Gelqf computes the LQ factorization of the m×n matrix A using a blocked
 // algorithm. A is modified to contain the information to construct L and Q. The
 // lower triangle of a contains the matrix L. The elements above the diagonal
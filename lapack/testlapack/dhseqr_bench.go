@@ -0,0 +1,47 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testlapack
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/lapack"
+)
+
+// DhseqrBenchmark benchmarks the computation of the Schur form of random
+// upper Hessenberg matrices. Matrices of order at least 75 are reduced by
+// Dlaqr04 and smaller matrices by Dlahqr.
+func DhseqrBenchmark(b *testing.B, impl Dhseqrer) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{10, 50, 100, 200} {
+		for _, job := range []lapack.SchurJob{lapack.EigenvaluesOnly, lapack.EigenvaluesAndSchur} {
+			compz := lapack.SchurNone
+			if job == lapack.EigenvaluesAndSchur {
+				compz = lapack.SchurHess
+			}
+			hOrig := randomHessenberg(n, n, rnd)
+			h := zeros(n, n, n)
+			z := zeros(n, n, n)
+			wr := make([]float64, n)
+			wi := make([]float64, n)
+			work := make([]float64, 1)
+			impl.Dhseqr(job, compz, n, 0, n-1, h.Data, h.Stride, wr, wi, z.Data, z.Stride, work, -1)
+			work = make([]float64, int(work[0]))
+			b.Run(fmt.Sprintf("n=%d,job=%c", n, job), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					copyGeneral(h, hOrig)
+					b.StartTimer()
+					impl.Dhseqr(job, compz, n, 0, n-1, h.Data, h.Stride, wr, wi, z.Data, z.Stride, work, len(work))
+				}
+				resultGeneral = h
+			})
+		}
+	}
+}
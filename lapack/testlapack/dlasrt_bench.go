@@ -0,0 +1,38 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testlapack
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/lapack"
+)
+
+// DlasrtBenchmark benchmarks sorting random vectors in increasing and
+// decreasing order.
+func DlasrtBenchmark(b *testing.B, impl Dlasrter) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{10, 100, 1000, 10000} {
+		for _, s := range []lapack.Sort{lapack.SortIncreasing, lapack.SortDecreasing} {
+			dOrig := make([]float64, n)
+			for i := range dOrig {
+				dOrig[i] = rnd.NormFloat64()
+			}
+			d := make([]float64, n)
+			b.Run(fmt.Sprintf("n=%d,s=%c", n, s), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					copy(d, dOrig)
+					b.StartTimer()
+					impl.Dlasrt(s, n, d)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testlapack
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+)
+
+type Dpstrfer interface {
+	Dpstrf(uplo blas.Uplo, n int, a []float64, lda int, piv []int, tol float64, work []float64) (rank int, ok bool)
+}
+
+type Dpstf2er interface {
+	Dpstf2(uplo blas.Uplo, n int, a []float64, lda int, piv []int, tol float64, work []float64) (rank int, ok bool)
+}
+
+func DpstrfTest(t *testing.T, impl Dpstrfer) {
+	dpstrfTest(t, "Dpstrf", impl.Dpstrf)
+}
+
+func Dpstf2Test(t *testing.T, impl Dpstf2er) {
+	dpstrfTest(t, "Dpstf2", impl.Dpstf2)
+}
+
+func dpstrfTest(t *testing.T, name string, fn func(blas.Uplo, int, []float64, int, []int, float64, []float64) (int, bool)) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, uplo := range []blas.Uplo{blas.Upper, blas.Lower} {
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 10, 30, 63, 65, 100, 150} {
+			for _, lda := range []int{max(1, n), n + 11} {
+				for _, rank := range []int{0, 1, n / 2, n - 1, n} {
+					if rank < 0 || n < rank {
+						continue
+					}
+					dpstrfTestCase(t, name, fn, rnd, uplo, n, lda, rank)
+				}
+			}
+		}
+	}
+}
+
+func dpstrfTestCase(t *testing.T, name string, fn func(blas.Uplo, int, []float64, int, []int, float64, []float64) (int, bool), rnd *rand.Rand, uplo blas.Uplo, n, lda, rankWant int) {
+	const tol = 1e-13
+
+	// Construct a positive semidefinite matrix A = X * X^T
+	// of rank rankWant, where X is a random n×rankWant matrix.
+	a := make([]float64, max(0, (n-1)*lda+n))
+	for i := range a {
+		a[i] = rnd.NormFloat64()
+	}
+	if n > 0 && rankWant > 0 {
+		x := randomGeneral(n, rankWant, rankWant, rnd)
+		bi := blas64.Implementation()
+		bi.Dgemm(blas.NoTrans, blas.Trans, n, n, rankWant,
+			1, x.Data, x.Stride, x.Data, x.Stride,
+			0, a, lda)
+	} else {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a[i*lda+j] = 0
+			}
+		}
+	}
+	aCopy := make([]float64, len(a))
+	copy(aCopy, a)
+	anorm := 0.0
+	maxDiag := 0.0
+	for i := 0; i < n; i++ {
+		anorm = math.Max(anorm, floats.Norm(aCopy[i*lda:i*lda+n], math.Inf(1)))
+		maxDiag = math.Max(maxDiag, aCopy[i*lda+i])
+	}
+	// The default tolerance of n*eps*max(A[k,k]) is of the order of
+	// the rounding error in the trailing pivots of a rank-deficient
+	// matrix, so use a safer tolerance for those.
+	pivTol := -1.0
+	if rankWant < n {
+		pivTol = 1e-10 * maxDiag
+	}
+
+	prefix := fmt.Sprintf("%s: uplo=%c,n=%d,lda=%d,rank=%d", name, uplo, n, lda, rankWant)
+
+	piv := make([]int, n)
+	work := make([]float64, 2*n)
+	rank, ok := fn(uplo, n, a, lda, piv, pivTol, work)
+
+	if rank != rankWant {
+		t.Errorf("%v: unexpected rank: got %d want %d", prefix, rank, rankWant)
+	}
+	if ok != (rank == n) {
+		t.Errorf("%v: unexpected ok: got %t with rank %d", prefix, ok, rank)
+	}
+	if n == 0 {
+		return
+	}
+
+	// Check that piv is a permutation.
+	seen := make([]bool, n)
+	for _, p := range piv {
+		if p < 0 || n <= p || seen[p] {
+			t.Fatalf("%v: piv is not a permutation: %v", prefix, piv)
+		}
+		seen[p] = true
+	}
+
+	// Extract the computed factor F with the first rank rows of U, or
+	// columns of L, and reconstruct P^T * A * P = F^T * F.
+	f := make([]float64, rank*n)
+	for i := 0; i < rank; i++ {
+		for j := i; j < n; j++ {
+			if uplo == blas.Upper {
+				f[i*n+j] = a[i*lda+j]
+			} else {
+				f[i*n+j] = a[j*lda+i]
+			}
+		}
+	}
+	ftf := make([]float64, n*n)
+	if rank > 0 {
+		blas64.Implementation().Dgemm(blas.Trans, blas.NoTrans, n, n, rank,
+			1, f, n, f, n,
+			0, ftf, n)
+	}
+	var resid float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			resid = math.Max(resid, math.Abs(ftf[i*n+j]-aCopy[piv[i]*lda+piv[j]]))
+		}
+	}
+	if resid > tol*math.Max(1, anorm)*float64(n) {
+		t.Errorf("%v: unexpected residual |P^T*A*P - F^T*F| = %v", prefix, resid)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// BiCGSTAB implements the right-preconditioned biconjugate gradient
+// stabilized method of van der Vorst for solving systems with a general
+// matrix A.
+//
+// The residual norm used for convergence is that of the residual computed
+// by the BiCGSTAB recurrence.
+type BiCGSTAB struct {
+	r, rt, p, v, s *mat.VecDense
+
+	// phat and shat hold the preconditioned
+	// search directions.
+	phat, shat *mat.VecDense
+
+	rho, rhoPrev, alpha, omega float64
+	first                      bool
+	resume                     int
+}
+
+// Init initializes the method for solving a system with the initial
+// estimate x and the corresponding residual.
+func (b *BiCGSTAB) Init(x, residual mat.Vector) {
+	n := x.Len()
+	b.r = reuseVec(b.r, n)
+	b.r.CopyVec(residual)
+	b.rt = reuseVec(b.rt, n)
+	b.rt.CopyVec(residual)
+	b.p = reuseVec(b.p, n)
+	b.v = reuseVec(b.v, n)
+	b.s = reuseVec(b.s, n)
+	b.phat = reuseVec(b.phat, n)
+	b.shat = reuseVec(b.shat, n)
+	b.first = true
+	b.resume = 1
+}
+
+// Iterate performs a step of the method.
+func (b *BiCGSTAB) Iterate(ctx *Context) (Operation, error) {
+	switch b.resume {
+	case 1:
+		b.rho = mat.Dot(b.rt, b.r)
+		if b.rho == 0 || math.IsNaN(b.rho) {
+			return NoOperation, &BreakdownError{Method: "BiCGSTAB", Value: b.rho}
+		}
+		if b.first {
+			b.p.CopyVec(b.r)
+			b.first = false
+		} else {
+			// p = r + beta*(p - omega*v)
+			beta := (b.rho / b.rhoPrev) * (b.alpha / b.omega)
+			b.p.AddScaledVec(b.p, -b.omega, b.v)
+			b.p.AddScaledVec(b.r, beta, b.p)
+		}
+		ctx.Src.CopyVec(b.p)
+		b.resume = 2
+		return PreconSolve, nil
+	case 2:
+		b.phat.CopyVec(ctx.Dst)
+		ctx.Src.CopyVec(b.phat)
+		b.resume = 3
+		return MulVec, nil
+	case 3:
+		b.v.CopyVec(ctx.Dst)
+		rtv := mat.Dot(b.rt, b.v)
+		if rtv == 0 || math.IsNaN(rtv) {
+			return NoOperation, &BreakdownError{Method: "BiCGSTAB", Value: rtv}
+		}
+		b.alpha = b.rho / rtv
+		ctx.X.AddScaledVec(ctx.X, b.alpha, b.phat)
+		b.s.AddScaledVec(b.r, -b.alpha, b.v)
+		ctx.ResidualNorm = mat.Norm(b.s, 2)
+		b.resume = 4
+		return CheckResidualNorm, nil
+	case 4:
+		if ctx.Converged {
+			b.r.CopyVec(b.s)
+			b.resume = 1
+			return MajorIteration, nil
+		}
+		ctx.Src.CopyVec(b.s)
+		b.resume = 5
+		return PreconSolve, nil
+	case 5:
+		b.shat.CopyVec(ctx.Dst)
+		ctx.Src.CopyVec(b.shat)
+		b.resume = 6
+		return MulVec, nil
+	case 6:
+		t := ctx.Dst
+		tt := mat.Dot(t, t)
+		if tt == 0 {
+			return NoOperation, &BreakdownError{Method: "BiCGSTAB", Value: tt}
+		}
+		b.omega = mat.Dot(t, b.s) / tt
+		if b.omega == 0 || math.IsNaN(b.omega) {
+			return NoOperation, &BreakdownError{Method: "BiCGSTAB", Value: b.omega}
+		}
+		ctx.X.AddScaledVec(ctx.X, b.omega, b.shat)
+		b.r.AddScaledVec(b.s, -b.omega, t)
+		b.rhoPrev = b.rho
+		ctx.ResidualNorm = mat.Norm(b.r, 2)
+		b.resume = 7
+		return CheckResidualNorm, nil
+	case 7:
+		b.resume = 1
+		return MajorIteration, nil
+	default:
+		panic("linsolve: BiCGSTAB.Init not called")
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CG implements the preconditioned conjugate gradient method for solving
+// systems with a symmetric positive definite matrix A. The preconditioner
+// must also be symmetric positive definite.
+//
+// The residual norm used for convergence is that of the residual computed
+// by the CG recurrence.
+type CG struct {
+	r, p *mat.VecDense
+
+	rho, rhoPrev float64
+	first        bool
+	resume       int
+}
+
+// Init initializes the method for solving a system with the initial
+// estimate x and the corresponding residual.
+func (cg *CG) Init(x, residual mat.Vector) {
+	n := x.Len()
+	cg.r = reuseVec(cg.r, n)
+	cg.r.CopyVec(residual)
+	cg.p = reuseVec(cg.p, n)
+	cg.first = true
+	cg.resume = 1
+}
+
+// Iterate performs a step of the method.
+func (cg *CG) Iterate(ctx *Context) (Operation, error) {
+	switch cg.resume {
+	case 1:
+		ctx.Src.CopyVec(cg.r)
+		cg.resume = 2
+		return PreconSolve, nil
+	case 2:
+		z := ctx.Dst
+		cg.rho = mat.Dot(cg.r, z)
+		if cg.rho == 0 || math.IsNaN(cg.rho) {
+			return NoOperation, &BreakdownError{Method: "CG", Value: cg.rho}
+		}
+		if cg.first {
+			cg.p.CopyVec(z)
+			cg.first = false
+		} else {
+			cg.p.AddScaledVec(z, cg.rho/cg.rhoPrev, cg.p)
+		}
+		ctx.Src.CopyVec(cg.p)
+		cg.resume = 3
+		return MulVec, nil
+	case 3:
+		ap := ctx.Dst
+		pap := mat.Dot(cg.p, ap)
+		if !(pap > 0) {
+			// A is not positive definite.
+			return NoOperation, &BreakdownError{Method: "CG", Value: pap}
+		}
+		alpha := cg.rho / pap
+		ctx.X.AddScaledVec(ctx.X, alpha, cg.p)
+		cg.r.AddScaledVec(cg.r, -alpha, ap)
+		ctx.ResidualNorm = mat.Norm(cg.r, 2)
+		cg.resume = 4
+		return CheckResidualNorm, nil
+	case 4:
+		cg.rhoPrev = cg.rho
+		cg.resume = 1
+		return MajorIteration, nil
+	default:
+		panic("linsolve: CG.Init not called")
+	}
+}
+
+// reuseVec returns v if it has length n, and a new vector otherwise.
+func reuseVec(v *mat.VecDense, n int) *mat.VecDense {
+	if v == nil || v.Len() != n {
+		return mat.NewVecDense(n, nil)
+	}
+	v.Zero()
+	return v
+}
@@ -0,0 +1,29 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package linsolve implements iterative methods for solving systems of
+// linear equations
+//
+//	A * x = b
+//
+// where A is an n×n matrix that is only accessed through its products with
+// vectors, so it may be sparse or defined implicitly by a function.
+//
+// The methods provided are the conjugate gradient method (CG) and MINRES
+// for symmetric matrices, and BiCGSTAB and restarted GMRES for general
+// matrices. Their convergence may be accelerated by preconditioning with
+// a Jacobi, SSOR or incomplete LU preconditioner, or with any other type
+// that solves systems with an approximation to A, such as the sparse and
+// dense LU factorizations provided by package mat. MixedPrecision solves
+// dense systems to double precision accuracy by GMRES preconditioned with
+// a single precision LU factorization.
+//
+// Methods are driven by the Iterative function, which evaluates the
+// operations requested by a Method, checks convergence and reports progress
+// to an optional monitor, in the same reverse-communication style as the
+// methods of package optimize.
+//
+// See Saad, "Iterative Methods for Sparse Linear Systems", 2nd edition,
+// SIAM, 2003 for more details.
+package linsolve // import "gonum.org/v1/gonum/linsolve"
@@ -0,0 +1,65 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/linsolve"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleIterative() {
+	// Solve the one-dimensional Poisson equation
+	//  -u'' = 1 on (0, 1), u(0) = u(1) = 0
+	// by central differences on n interior points.
+	const n = 50
+	h := 1.0 / (n + 1)
+	a := mat.NewCOO(n, n, nil, nil, nil)
+	b := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		a.Append(i, i, 2/(h*h))
+		if i > 0 {
+			a.Append(i, i-1, -1/(h*h))
+			a.Append(i-1, i, -1/(h*h))
+		}
+		b.SetVec(i, 1)
+	}
+	A := a.ToCSR()
+
+	for _, test := range []struct {
+		name   string
+		precon linsolve.Preconditioner
+	}{
+		{name: "none"},
+		{name: "SSOR", precon: must(linsolve.NewSSOR(A, 1.8))},
+		{name: "ILU0", precon: must(linsolve.NewILU0(A))},
+	} {
+		res, err := linsolve.Iterative(linsolve.Matrix{Matrix: A}, b, &linsolve.CG{}, &linsolve.Settings{
+			Preconditioner: test.precon,
+			Tolerance:      1e-10,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		// The exact solution is u(x) = x(1-x)/2.
+		x := float64(n/2) * h
+		fmt.Printf("%s: %d iterations, u(%.3f) = %.6f (exact %.6f)\n",
+			test.name, res.Stats.Iterations, x, res.X.AtVec(n/2-1), x*(1-x)/2)
+	}
+
+	// Output:
+	// none: 25 iterations, u(0.490) = 0.124952 (exact 0.124952)
+	// SSOR: 10 iterations, u(0.490) = 0.124952 (exact 0.124952)
+	// ILU0: 1 iterations, u(0.490) = 0.124952 (exact 0.124952)
+}
+
+func must(p linsolve.Preconditioner, ok bool) linsolve.Preconditioner {
+	if !ok {
+		log.Fatal("preconditioner construction failed")
+	}
+	return p
+}
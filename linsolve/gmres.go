@@ -0,0 +1,194 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const defaultRestart = 30
+
+// GMRES implements the right-preconditioned restarted generalized minimum
+// residual method GMRES(m) for solving systems with a general matrix A.
+//
+// Each major iteration of GMRES is a cycle of up to Restart inner steps
+// that minimize the residual norm over a Krylov subspace, after which the
+// solution estimate is updated and the method restarts. Convergence is
+// checked after every inner step using the residual norm of the least
+// squares problem. The preconditioned basis vectors are retained, so the
+// preconditioner may vary between steps, as in flexible GMRES.
+type GMRES struct {
+	// Restart is the maximum dimension of the Krylov
+	// subspace before a restart. If Restart is zero,
+	// a default of min(n, 30) is used.
+	Restart int
+
+	m int
+
+	// v holds the orthonormal basis of the
+	// Krylov subspace and z the preconditioned
+	// basis vectors.
+	v, z []*mat.VecDense
+
+	// h holds the Hessenberg matrix reduced to
+	// upper triangular form by the Givens
+	// rotations in cs and sn, and g holds the
+	// rotated right-hand side of the least
+	// squares problem.
+	h      *mat.Dense
+	cs, sn []float64
+	g      []float64
+
+	k      int
+	resume int
+}
+
+// Init initializes the method for solving a system with the initial
+// estimate x and the corresponding residual.
+func (g *GMRES) Init(x, residual mat.Vector) {
+	n := x.Len()
+	if g.Restart < 0 {
+		panic("linsolve: negative GMRES restart")
+	}
+	m := g.Restart
+	if m == 0 {
+		m = defaultRestart
+		if n < m {
+			m = n
+		}
+	}
+	if m != g.m || g.v == nil || g.v[0].Len() != n {
+		g.m = m
+		g.v = make([]*mat.VecDense, m+1)
+		for i := range g.v {
+			g.v[i] = mat.NewVecDense(n, nil)
+		}
+		g.z = make([]*mat.VecDense, m)
+		for i := range g.z {
+			g.z[i] = mat.NewVecDense(n, nil)
+		}
+		g.h = mat.NewDense(m+1, m, nil)
+		g.cs = make([]float64, m)
+		g.sn = make([]float64, m)
+		g.g = make([]float64, m+1)
+	}
+	g.restart(residual)
+}
+
+// restart begins a new cycle from the residual r.
+func (g *GMRES) restart(r mat.Vector) {
+	beta := mat.Norm(r, 2)
+	g.v[0].ScaleVec(1/beta, r)
+	for i := range g.g {
+		g.g[i] = 0
+	}
+	g.g[0] = beta
+	g.k = 0
+	g.resume = 1
+}
+
+// Iterate performs a step of the method.
+func (g *GMRES) Iterate(ctx *Context) (Operation, error) {
+	switch g.resume {
+	case 1:
+		ctx.Src.CopyVec(g.v[g.k])
+		g.resume = 2
+		return PreconSolve, nil
+	case 2:
+		g.z[g.k].CopyVec(ctx.Dst)
+		ctx.Src.CopyVec(g.z[g.k])
+		g.resume = 3
+		return MulVec, nil
+	case 3:
+		k := g.k
+		w := ctx.Dst
+
+		// Orthogonalize w against the basis by
+		// modified Gram-Schmidt.
+		for i := 0; i <= k; i++ {
+			hik := mat.Dot(w, g.v[i])
+			g.h.Set(i, k, hik)
+			w.AddScaledVec(w, -hik, g.v[i])
+		}
+		hk := mat.Norm(w, 2)
+		if math.IsNaN(hk) {
+			return NoOperation, &BreakdownError{Method: "GMRES", Value: hk}
+		}
+		if hk != 0 {
+			g.v[k+1].ScaleVec(1/hk, w)
+		}
+
+		// Apply the previous rotations to the new
+		// column and eliminate its subdiagonal.
+		for i := 0; i < k; i++ {
+			a, b := g.h.At(i, k), g.h.At(i+1, k)
+			g.h.Set(i, k, g.cs[i]*a+g.sn[i]*b)
+			g.h.Set(i+1, k, -g.sn[i]*a+g.cs[i]*b)
+		}
+		a := g.h.At(k, k)
+		r := math.Hypot(a, hk)
+		if r == 0 {
+			return NoOperation, &BreakdownError{Method: "GMRES", Value: r}
+		}
+		g.cs[k], g.sn[k] = a/r, hk/r
+		g.h.Set(k, k, r)
+		g.h.Set(k+1, k, 0)
+		g.g[k+1] = -g.sn[k] * g.g[k]
+		g.g[k] *= g.cs[k]
+
+		g.k++
+		ctx.ResidualNorm = math.Abs(g.g[g.k])
+		g.resume = 4
+		return CheckResidualNorm, nil
+	case 4:
+		if !ctx.Converged && g.k < g.m {
+			g.resume = 1
+			return g.Iterate(ctx)
+		}
+		g.update(ctx.X)
+		if ctx.Converged {
+			g.resume = 1
+		} else {
+			g.resume = 5
+		}
+		return MajorIteration, nil
+	case 5:
+		g.resume = 6
+		return ComputeResidual, nil
+	case 6:
+		if mat.Norm(ctx.Dst, 2) == 0 {
+			// The current estimate is exact.
+			ctx.ResidualNorm = 0
+			g.resume = 7
+			return CheckResidualNorm, nil
+		}
+		g.restart(ctx.Dst)
+		return g.Iterate(ctx)
+	case 7:
+		g.resume = 1
+		return MajorIteration, nil
+	default:
+		panic("linsolve: GMRES.Init not called")
+	}
+}
+
+// update adds the solution of the least squares problem of the current
+// cycle to x.
+func (g *GMRES) update(x *mat.VecDense) {
+	k := g.k
+	y := make([]float64, k)
+	for i := k - 1; i >= 0; i-- {
+		s := g.g[i]
+		for j := i + 1; j < k; j++ {
+			s -= g.h.At(i, j) * y[j]
+		}
+		y[i] = s / g.h.At(i, i)
+	}
+	for i, yi := range y {
+		x.AddScaledVec(x, yi, g.z[i])
+	}
+}
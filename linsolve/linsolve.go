@@ -0,0 +1,134 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const defaultTolerance = 1e-8
+
+// Iterative finds an approximate solution of the system of n linear
+// equations
+//
+//	A * x = b
+//
+// using the iterative method m, where A is the n×n operator a and b is a
+// vector of length n. If settings is nil, the default settings are used.
+//
+// Iterative returns a Result holding the estimate of the solution reached
+// along with any error. If the method does not converge before the
+// iteration limit, ErrIterationLimit is returned. Iterative will panic if
+// the settings are invalid or if the method requests an invalid operation.
+func Iterative(a MulVecToer, b mat.Vector, m Method, settings *Settings) (*Result, error) {
+	start := time.Now()
+	n := b.Len()
+	if n == 0 {
+		panic("linsolve: zero length b")
+	}
+	var s Settings
+	if settings != nil {
+		s = *settings
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = defaultTolerance
+	}
+	if !(0 < s.Tolerance && s.Tolerance < 1) {
+		panic("linsolve: invalid tolerance")
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 4 * n
+	}
+	if s.MaxIterations < 0 {
+		panic("linsolve: negative iteration limit")
+	}
+
+	x := s.Dst
+	if x == nil {
+		x = &mat.VecDense{}
+	}
+	if s.InitX != nil {
+		if s.InitX.Len() != n {
+			panic("linsolve: mismatched initial estimate length")
+		}
+		x.CloneVec(s.InitX)
+	} else {
+		x.CloneVec(mat.NewVecDense(n, nil))
+	}
+
+	ctx := &Context{
+		X:   x,
+		Src: mat.NewVecDense(n, nil),
+		Dst: mat.NewVecDense(n, nil),
+	}
+	res := &Result{X: x}
+	defer func() { res.Stats.Runtime = time.Since(start) }()
+
+	computeResidual(ctx.Dst, a, b, x, &res.Stats)
+	res.ResidualNorm = mat.Norm(ctx.Dst, 2)
+	tol := s.Tolerance * mat.Norm(b, 2)
+	if res.ResidualNorm <= tol {
+		return res, nil
+	}
+	m.Init(x, ctx.Dst)
+
+	for {
+		op, err := m.Iterate(ctx)
+		if err != nil {
+			return res, err
+		}
+		switch op {
+		case NoOperation:
+		case MulVec, MulVec | Trans:
+			a.MulVecTo(ctx.Dst, op&Trans != 0, ctx.Src)
+			res.Stats.MulVec++
+		case PreconSolve, PreconSolve | Trans:
+			if s.Preconditioner == nil {
+				ctx.Dst.CopyVec(ctx.Src)
+				break
+			}
+			err := s.Preconditioner.SolveVecTo(ctx.Dst, op&Trans != 0, ctx.Src)
+			res.Stats.PreconSolve++
+			if err != nil {
+				// An ill-conditioned preconditioner
+				// may still be effective.
+				if _, ok := err.(mat.Condition); !ok {
+					return res, err
+				}
+			}
+		case ComputeResidual:
+			computeResidual(ctx.Dst, a, b, ctx.X, &res.Stats)
+		case CheckResidualNorm:
+			ctx.Converged = ctx.ResidualNorm <= tol
+			res.ResidualNorm = ctx.ResidualNorm
+		case MajorIteration:
+			res.Stats.Iterations++
+			if s.Monitor != nil {
+				res.Stats.Runtime = time.Since(start)
+				err := s.Monitor(res.Stats, res.ResidualNorm)
+				if err != nil {
+					return res, err
+				}
+			}
+			if ctx.Converged {
+				return res, nil
+			}
+			if res.Stats.Iterations >= s.MaxIterations {
+				return res, ErrIterationLimit
+			}
+		default:
+			panic("linsolve: invalid operation " + op.String())
+		}
+	}
+}
+
+// computeResidual stores b - A*x into dst.
+func computeResidual(dst *mat.VecDense, a MulVecToer, b mat.Vector, x *mat.VecDense, stats *Stats) {
+	a.MulVecTo(dst, false, x)
+	stats.MulVec++
+	dst.SubVec(b, dst)
+}
@@ -0,0 +1,281 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// convectionDiffusion returns the matrix of an upwind finite difference
+// discretization of -Δu + c·∇u on an m×m grid, shifted by s. With c zero
+// the matrix is the symmetric five-point Laplacian.
+func convectionDiffusion(m int, c, s float64) *mat.CSR {
+	n := m * m
+	a := mat.NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			k := i*m + j
+			a.Append(k, k, 4+c+s)
+			if i > 0 {
+				a.Append(k, k-m, -1-c)
+			}
+			if i < m-1 {
+				a.Append(k, k+m, -1)
+			}
+			if j > 0 {
+				a.Append(k, k-1, -1)
+			}
+			if j < m-1 {
+				a.Append(k, k+1, -1)
+			}
+		}
+	}
+	return a.ToCSR()
+}
+
+type preconditioner struct {
+	name string
+	new  func(a mat.Matrix) Preconditioner
+}
+
+var (
+	identity = preconditioner{name: "none", new: func(mat.Matrix) Preconditioner { return nil }}
+	jacobi   = preconditioner{name: "Jacobi", new: func(a mat.Matrix) Preconditioner {
+		p, ok := NewJacobi(a)
+		if !ok {
+			panic("bad test matrix")
+		}
+		return p
+	}}
+	ilu0 = preconditioner{name: "ILU0", new: func(a mat.Matrix) Preconditioner {
+		p, ok := NewILU0(a)
+		if !ok {
+			panic("bad test matrix")
+		}
+		return p
+	}}
+	ssor = preconditioner{name: "SSOR", new: func(a mat.Matrix) Preconditioner {
+		p, ok := NewSSOR(a, 1.2)
+		if !ok {
+			panic("bad test matrix")
+		}
+		return p
+	}}
+	exact = preconditioner{name: "SparseLU", new: func(a mat.Matrix) Preconditioner {
+		var lu mat.SparseLU
+		if !lu.Factorize(a) {
+			panic("bad test matrix")
+		}
+		return &lu
+	}}
+)
+
+func TestIterative(t *testing.T) {
+	t.Parallel()
+	spd := []*mat.CSR{convectionDiffusion(1, 0, 0), convectionDiffusion(8, 0, 0), convectionDiffusion(20, 0, 0.01)}
+	indefinite := []*mat.CSR{convectionDiffusion(8, 0, -1.3), convectionDiffusion(15, 0, -0.5)}
+	general := []*mat.CSR{convectionDiffusion(8, 2, 0), convectionDiffusion(20, 0.5, 0)}
+
+	for _, test := range []struct {
+		name     string
+		method   func() Method
+		matrices [][]*mat.CSR
+		precon   []preconditioner
+	}{
+		{
+			name:     "CG",
+			method:   func() Method { return &CG{} },
+			matrices: [][]*mat.CSR{spd},
+			precon:   []preconditioner{identity, jacobi, ssor},
+		},
+		{
+			name:     "MINRES",
+			method:   func() Method { return &MINRES{} },
+			matrices: [][]*mat.CSR{spd, indefinite},
+			precon:   []preconditioner{identity, jacobi, ssor},
+		},
+		{
+			name:     "BiCGSTAB",
+			method:   func() Method { return &BiCGSTAB{} },
+			matrices: [][]*mat.CSR{spd, general},
+			precon:   []preconditioner{identity, jacobi, ilu0, ssor, exact},
+		},
+		{
+			name:     "GMRES",
+			method:   func() Method { return &GMRES{} },
+			matrices: [][]*mat.CSR{spd, indefinite, general},
+			precon:   []preconditioner{identity, jacobi, ilu0, ssor, exact},
+		},
+		{
+			name:     "GMRES(5)",
+			method:   func() Method { return &GMRES{Restart: 5} },
+			matrices: [][]*mat.CSR{spd, general},
+			precon:   []preconditioner{identity, ilu0},
+		},
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		for _, set := range test.matrices {
+			for _, a := range set {
+				n, _ := a.Dims()
+				b := mat.NewVecDense(n, nil)
+				for i := 0; i < n; i++ {
+					b.SetVec(i, rnd.NormFloat64())
+				}
+				var want mat.VecDense
+				var lu mat.LU
+				lu.Factorize(a)
+				lu.SolveVecTo(&want, false, b)
+
+				for _, p := range test.precon {
+					name := fmt.Sprintf("%s n=%d %s", test.name, n, p.name)
+					var monitored int
+					var last float64
+					settings := &Settings{
+						Preconditioner: p.new(a),
+						Tolerance:      1e-10,
+						MaxIterations:  1000,
+						Monitor: func(stats Stats, residualNorm float64) error {
+							monitored++
+							if stats.Iterations != monitored {
+								t.Errorf("%s: unexpected iteration count: got %d want %d", name, stats.Iterations, monitored)
+							}
+							last = residualNorm
+							return nil
+						},
+					}
+					res, err := Iterative(Matrix{a}, b, test.method(), settings)
+					if err != nil {
+						t.Errorf("%s: unexpected error: %v", name, err)
+						continue
+					}
+					if !mat.EqualApprox(res.X, &want, 1e-7) {
+						t.Errorf("%s: unexpected solution", name)
+					}
+					if res.Stats.Iterations != monitored || res.ResidualNorm != last {
+						t.Errorf("%s: monitor not called for each iteration", name)
+					}
+					if p.name == "SparseLU" && res.Stats.Iterations > 2 {
+						t.Errorf("%s: unexpected iterations with exact preconditioner: %d", name, res.Stats.Iterations)
+					}
+					if p.name != "none" && res.Stats.PreconSolve == 0 {
+						t.Errorf("%s: preconditioner not used", name)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestIterativeInitX(t *testing.T) {
+	t.Parallel()
+	a := convectionDiffusion(5, 0, 0)
+	n, _ := a.Dims()
+	want := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		want.SetVec(i, float64(i))
+	}
+	var b mat.VecDense
+	b.MulVec(a, want)
+
+	var dst mat.VecDense
+	res, err := Iterative(Matrix{a}, &b, &CG{}, &Settings{InitX: want, Dst: &dst})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.X != &dst || res.Stats.Iterations != 0 || !mat.Equal(&dst, want) {
+		t.Errorf("unexpected result starting at the solution: %+v", res)
+	}
+
+	res, err = Iterative(Matrix{a}, mat.NewVecDense(n, nil), &CG{}, nil)
+	if err != nil || res.Stats.Iterations != 0 || mat.Norm(res.X, math.Inf(1)) != 0 {
+		t.Errorf("unexpected result for zero right-hand side: %+v %v", res, err)
+	}
+}
+
+func TestIterativeErrors(t *testing.T) {
+	t.Parallel()
+	a := convectionDiffusion(20, 1, 0)
+	n, _ := a.Dims()
+	b := mat.NewVecDense(n, nil)
+	b.SetVec(0, 1)
+
+	res, err := Iterative(Matrix{a}, b, &GMRES{Restart: 2}, &Settings{MaxIterations: 3})
+	if err != ErrIterationLimit {
+		t.Errorf("unexpected error at iteration limit: %v", err)
+	}
+	if res.Stats.Iterations != 3 {
+		t.Errorf("unexpected iteration count: got %d want 3", res.Stats.Iterations)
+	}
+
+	stop := errors.New("stop")
+	_, err = Iterative(Matrix{a}, b, &BiCGSTAB{}, &Settings{
+		Monitor: func(stats Stats, _ float64) error {
+			if stats.Iterations == 2 {
+				return stop
+			}
+			return nil
+		},
+	})
+	if err != stop {
+		t.Errorf("unexpected error from monitor: %v", err)
+	}
+
+	_, err = Iterative(Matrix{mat.NewDiagDense(2, []float64{1, -1})}, mat.NewVecDense(2, []float64{1, 1}), &CG{}, nil)
+	if _, ok := err.(*BreakdownError); !ok {
+		t.Errorf("unexpected error for indefinite CG: %v", err)
+	}
+
+	for _, settings := range []*Settings{
+		{Tolerance: 1},
+		{Tolerance: -1},
+		{MaxIterations: -1},
+		{InitX: mat.NewVecDense(n+1, nil)},
+	} {
+		settings := settings
+		if !panics(func() { Iterative(Matrix{a}, b, &CG{}, settings) }) {
+			t.Errorf("expected panic for settings %+v", settings)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+func BenchmarkIterative(b *testing.B) {
+	a := convectionDiffusion(100, 0, 0)
+	n, _ := a.Dims()
+	rhs := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		rhs.SetVec(i, 1)
+	}
+	p, _ := NewSSOR(a, 1.5)
+	for _, bench := range []struct {
+		name   string
+		method func() Method
+	}{
+		{name: "CG", method: func() Method { return &CG{} }},
+		{name: "MINRES", method: func() Method { return &MINRES{} }},
+		{name: "BiCGSTAB", method: func() Method { return &BiCGSTAB{} }},
+		{name: "GMRES", method: func() Method { return &GMRES{} }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Iterative(Matrix{a}, rhs, bench.method(), &Settings{Preconditioner: p, MaxIterations: 10000})
+			}
+		})
+	}
+}
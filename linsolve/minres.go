@@ -0,0 +1,141 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// MINRES implements the preconditioned minimum residual method of Paige and
+// Saunders for solving systems with a symmetric, possibly indefinite, matrix
+// A. The preconditioner must be symmetric positive definite.
+//
+// The residual norm used for convergence is the estimate computed by the
+// MINRES recurrence, which is the norm of the residual in the norm defined
+// by the inverse of the preconditioner. Without a preconditioner it is the
+// Euclidean norm of the residual.
+type MINRES struct {
+	r1, r2, y, v *mat.VecDense
+	w, w1, w2    *mat.VecDense
+
+	alfa, beta, oldb float64
+	dbar, epsln      float64
+	phibar, cs, sn   float64
+
+	first  bool
+	resume int
+}
+
+// eps is the machine epsilon used to guard
+// against a zero rotation in MINRES.
+const eps = 0x1p-52
+
+// Init initializes the method for solving a system with the initial
+// estimate x and the corresponding residual.
+func (m *MINRES) Init(x, residual mat.Vector) {
+	n := x.Len()
+	m.r1 = reuseVec(m.r1, n)
+	m.r1.CopyVec(residual)
+	m.r2 = reuseVec(m.r2, n)
+	m.r2.CopyVec(residual)
+	m.y = reuseVec(m.y, n)
+	m.v = reuseVec(m.v, n)
+	m.w = reuseVec(m.w, n)
+	m.w1 = reuseVec(m.w1, n)
+	m.w2 = reuseVec(m.w2, n)
+	m.first = true
+	m.resume = 1
+}
+
+// Iterate performs a step of the method.
+func (m *MINRES) Iterate(ctx *Context) (Operation, error) {
+	switch m.resume {
+	case 1:
+		ctx.Src.CopyVec(m.r1)
+		m.resume = 2
+		return PreconSolve, nil
+	case 2:
+		m.y.CopyVec(ctx.Dst)
+		beta := mat.Dot(m.r1, m.y)
+		if !(beta > 0) {
+			// The preconditioner is not
+			// positive definite.
+			return NoOperation, &BreakdownError{Method: "MINRES", Value: beta}
+		}
+		m.beta = math.Sqrt(beta)
+		m.oldb = 0
+		m.dbar = 0
+		m.epsln = 0
+		m.phibar = m.beta
+		m.cs = -1
+		m.sn = 0
+		m.resume = 3
+		fallthrough
+	case 3:
+		m.v.ScaleVec(1/m.beta, m.y)
+		ctx.Src.CopyVec(m.v)
+		m.resume = 4
+		return MulVec, nil
+	case 4:
+		m.y.CopyVec(ctx.Dst)
+		if !m.first {
+			m.y.AddScaledVec(m.y, -m.beta/m.oldb, m.r1)
+		}
+		m.first = false
+		m.alfa = mat.Dot(m.v, m.y)
+		m.y.AddScaledVec(m.y, -m.alfa/m.beta, m.r2)
+		m.r1, m.r2 = m.r2, m.r1
+		m.r2.CopyVec(m.y)
+		ctx.Src.CopyVec(m.r2)
+		m.resume = 5
+		return PreconSolve, nil
+	case 5:
+		m.y.CopyVec(ctx.Dst)
+		m.oldb = m.beta
+		beta := mat.Dot(m.r2, m.y)
+		if beta < 0 || math.IsNaN(beta) {
+			return NoOperation, &BreakdownError{Method: "MINRES", Value: beta}
+		}
+		m.beta = math.Sqrt(beta)
+
+		// Apply the previous rotation to the new
+		// column of the tridiagonal matrix and
+		// compute the next rotation.
+		oldeps := m.epsln
+		delta := m.cs*m.dbar + m.sn*m.alfa
+		gbar := m.sn*m.dbar - m.cs*m.alfa
+		m.epsln = m.sn * m.beta
+		m.dbar = -m.cs * m.beta
+		gamma := math.Max(math.Hypot(gbar, m.beta), eps)
+		m.cs = gbar / gamma
+		m.sn = m.beta / gamma
+		phi := m.cs * m.phibar
+		m.phibar *= m.sn
+
+		// Update the search direction and the
+		// solution estimate.
+		m.w1, m.w2, m.w = m.w2, m.w, m.w1
+		m.w.AddScaledVec(m.v, -oldeps, m.w1)
+		m.w.AddScaledVec(m.w, -delta, m.w2)
+		m.w.ScaleVec(1/gamma, m.w)
+		ctx.X.AddScaledVec(ctx.X, phi, m.w)
+
+		ctx.ResidualNorm = math.Abs(m.phibar)
+		m.resume = 6
+		return CheckResidualNorm, nil
+	case 6:
+		if m.beta == 0 && !ctx.Converged {
+			// The Krylov subspace is invariant
+			// but the tolerance was not reached.
+			return NoOperation, &BreakdownError{Method: "MINRES", Value: m.beta}
+		}
+		m.resume = 3
+		return MajorIteration, nil
+	default:
+		panic("linsolve: MINRES.Init not called")
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLU32(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 63, 64, 65, 150} {
+		a := mat.NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		p, ok := NewLU32(a)
+		if !ok {
+			t.Fatalf("n=%d: unexpected LU32 failure", n)
+		}
+		b := mat.NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			b.SetVec(i, rnd.NormFloat64())
+		}
+		for _, trans := range []bool{false, true} {
+			var m mat.Matrix = a
+			if trans {
+				m = a.T()
+			}
+			var x, r mat.VecDense
+			err := p.SolveVecTo(&x, trans, b)
+			if err != nil {
+				t.Errorf("n=%d trans=%t: unexpected error: %v", n, trans, err)
+			}
+			r.MulVec(m, &x)
+			r.SubVec(b, &r)
+			// The solve is accurate to single precision.
+			if rel := mat.Norm(&r, 2) / mat.Norm(b, 2); rel > 1e-3 {
+				t.Errorf("n=%d trans=%t: unexpected relative residual: %v", n, trans, rel)
+			}
+		}
+	}
+
+	if _, ok := NewLU32(mat.NewDense(2, 2, []float64{1, 2, 2, 4})); ok {
+		t.Error("expected LU32 failure for singular matrix")
+	}
+	if _, ok := NewLU32(mat.NewDense(1, 1, []float64{1e300})); ok {
+		t.Error("expected LU32 failure for single precision overflow")
+	}
+}
+
+func TestMixedPrecision(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 10, 100, 200} {
+		a := mat.NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+			a.Set(i, i, a.At(i, i)+float64(n))
+		}
+		want := mat.NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			want.SetVec(i, rnd.NormFloat64())
+		}
+		var b mat.VecDense
+		b.MulVec(a, want)
+
+		res, err := MixedPrecision(a, &b, nil)
+		if err != nil {
+			t.Errorf("n=%d: unexpected error: %v", n, err)
+			continue
+		}
+		var diff mat.VecDense
+		diff.SubVec(res.X, want)
+		if rel := mat.Norm(&diff, 2) / mat.Norm(want, 2); rel > 1e-11 {
+			t.Errorf("n=%d: unexpected relative error: %v", n, rel)
+		}
+		// Refinement from a single precision factorization
+		// should need only a few GMRES steps.
+		if res.Stats.PreconSolve > 10 {
+			t.Errorf("n=%d: unexpected number of preconditioner solves: %d", n, res.Stats.PreconSolve)
+		}
+	}
+
+	_, err := MixedPrecision(mat.NewDense(2, 2, []float64{1, 2, 2, 4}), mat.NewVecDense(2, []float64{1, 1}), nil)
+	if err != mat.ErrSingular {
+		t.Errorf("unexpected error for singular matrix: got %v want %v", err, mat.ErrSingular)
+	}
+}
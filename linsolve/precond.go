@@ -0,0 +1,284 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import "gonum.org/v1/gonum/mat"
+
+var (
+	_ Preconditioner = (*Jacobi)(nil)
+	_ Preconditioner = (*ILU0)(nil)
+	_ Preconditioner = (*SSOR)(nil)
+	_ Preconditioner = (*mat.LU)(nil)
+	_ Preconditioner = (*mat.SparseLU)(nil)
+)
+
+// Jacobi is a diagonal preconditioner holding the diagonal of A.
+type Jacobi struct {
+	diag []float64
+}
+
+// NewJacobi returns a Jacobi preconditioner for the square matrix a and
+// whether the diagonal of a has no zero elements. If ok is false the
+// preconditioner must not be used.
+func NewJacobi(a mat.Matrix) (p *Jacobi, ok bool) {
+	n := squareDim(a)
+	p = &Jacobi{diag: make([]float64, n)}
+	for i := range p.diag {
+		p.diag[i] = a.At(i, i)
+		if p.diag[i] == 0 {
+			return nil, false
+		}
+	}
+	return p, true
+}
+
+// SolveVecTo stores the solution of D*dst = b into dst, where D is the
+// diagonal of A. The transpose of D is D, so trans is ignored.
+func (p *Jacobi) SolveVecTo(dst *mat.VecDense, trans bool, b mat.Vector) error {
+	solveVecTo(dst, len(p.diag), b, func(x []float64) {
+		for i, d := range p.diag {
+			x[i] /= d
+		}
+	})
+	return nil
+}
+
+// ILU0 is an incomplete LU preconditioner without fill, holding factors L
+// and U with the sparsity pattern of A such that L*U agrees with A on that
+// pattern.
+type ILU0 struct {
+	lu *csr
+}
+
+// NewILU0 returns an incomplete LU preconditioner for the square matrix a
+// and whether the factorization succeeded. The factorization fails if a
+// diagonal element of A is not stored or a pivot is zero. If ok is false
+// the preconditioner must not be used.
+//
+// NewILU0 is efficient when a is a sparse matrix provided by package mat.
+func NewILU0(a mat.Matrix) (p *ILU0, ok bool) {
+	lu := newCSR(a)
+	n := lu.n
+	pos := make([]int, n)
+	for i := range pos {
+		pos[i] = -1
+	}
+	for i := 0; i < n; i++ {
+		if lu.diag[i] < 0 {
+			return nil, false
+		}
+		for q := lu.indptr[i]; q < lu.indptr[i+1]; q++ {
+			pos[lu.ind[q]] = q
+		}
+		for q := lu.indptr[i]; q < lu.diag[i]; q++ {
+			k := lu.ind[q]
+			lu.data[q] /= lu.data[lu.diag[k]]
+			l := lu.data[q]
+			for r := lu.diag[k] + 1; r < lu.indptr[k+1]; r++ {
+				if s := pos[lu.ind[r]]; s >= 0 {
+					lu.data[s] -= l * lu.data[r]
+				}
+			}
+		}
+		for q := lu.indptr[i]; q < lu.indptr[i+1]; q++ {
+			pos[lu.ind[q]] = -1
+		}
+		if lu.data[lu.diag[i]] == 0 {
+			return nil, false
+		}
+	}
+	return &ILU0{lu: lu}, true
+}
+
+// SolveVecTo stores the solution of L*U*dst = b into dst if trans is false,
+// and of (L*U)ᵀ*dst = b otherwise.
+func (p *ILU0) SolveVecTo(dst *mat.VecDense, trans bool, b mat.Vector) error {
+	lu := p.lu
+	solveVecTo(dst, lu.n, b, func(x []float64) {
+		if trans {
+			// Solve Uᵀ and then Lᵀ by columns.
+			for i := 0; i < lu.n; i++ {
+				x[i] /= lu.data[lu.diag[i]]
+				for q := lu.diag[i] + 1; q < lu.indptr[i+1]; q++ {
+					x[lu.ind[q]] -= lu.data[q] * x[i]
+				}
+			}
+			for i := lu.n - 1; i >= 0; i-- {
+				for q := lu.indptr[i]; q < lu.diag[i]; q++ {
+					x[lu.ind[q]] -= lu.data[q] * x[i]
+				}
+			}
+			return
+		}
+		for i := 0; i < lu.n; i++ {
+			for q := lu.indptr[i]; q < lu.diag[i]; q++ {
+				x[i] -= lu.data[q] * x[lu.ind[q]]
+			}
+		}
+		for i := lu.n - 1; i >= 0; i-- {
+			for q := lu.diag[i] + 1; q < lu.indptr[i+1]; q++ {
+				x[i] -= lu.data[q] * x[lu.ind[q]]
+			}
+			x[i] /= lu.data[lu.diag[i]]
+		}
+	})
+	return nil
+}
+
+// SSOR is a symmetric successive over-relaxation preconditioner
+//
+//	M = 1/(ω(2-ω)) * (D + ωL) * D⁻¹ * (D + ωU)
+//
+// where D, L and U are the diagonal, strictly lower and strictly upper
+// triangles of A. M is symmetric positive definite when A is, so SSOR may be
+// used with CG and MINRES. With ω = 1 it is the symmetric Gauss-Seidel
+// preconditioner.
+type SSOR struct {
+	a     *csr
+	omega float64
+}
+
+// NewSSOR returns an SSOR preconditioner for the square matrix a with the
+// relaxation parameter omega, and whether the diagonal of a has no zero
+// elements. If ok is false the preconditioner must not be used. NewSSOR
+// will panic if omega is not in the interval (0, 2).
+//
+// NewSSOR is efficient when a is a sparse matrix provided by package mat.
+func NewSSOR(a mat.Matrix, omega float64) (p *SSOR, ok bool) {
+	if !(0 < omega && omega < 2) {
+		panic("linsolve: SSOR relaxation parameter out of range")
+	}
+	s := newCSR(a)
+	for _, q := range s.diag {
+		if q < 0 || s.data[q] == 0 {
+			return nil, false
+		}
+	}
+	return &SSOR{a: s, omega: omega}, true
+}
+
+// SolveVecTo stores the solution of M*dst = b into dst if trans is false,
+// and of Mᵀ*dst = b otherwise.
+func (p *SSOR) SolveVecTo(dst *mat.VecDense, trans bool, b mat.Vector) error {
+	a, w := p.a, p.omega
+	solveVecTo(dst, a.n, b, func(x []float64) {
+		if trans {
+			// Solve (D + ωU)ᵀ and then (D + ωL)ᵀ
+			// by columns.
+			for i := 0; i < a.n; i++ {
+				x[i] /= a.data[a.diag[i]]
+				for q := a.diag[i] + 1; q < a.indptr[i+1]; q++ {
+					x[a.ind[q]] -= w * a.data[q] * x[i]
+				}
+			}
+			for i := range x {
+				x[i] *= a.data[a.diag[i]]
+			}
+			for i := a.n - 1; i >= 0; i-- {
+				x[i] /= a.data[a.diag[i]]
+				for q := a.indptr[i]; q < a.diag[i]; q++ {
+					x[a.ind[q]] -= w * a.data[q] * x[i]
+				}
+			}
+		} else {
+			for i := 0; i < a.n; i++ {
+				for q := a.indptr[i]; q < a.diag[i]; q++ {
+					x[i] -= w * a.data[q] * x[a.ind[q]]
+				}
+				x[i] /= a.data[a.diag[i]]
+			}
+			for i := range x {
+				x[i] *= a.data[a.diag[i]]
+			}
+			for i := a.n - 1; i >= 0; i-- {
+				for q := a.diag[i] + 1; q < a.indptr[i+1]; q++ {
+					x[i] -= w * a.data[q] * x[a.ind[q]]
+				}
+				x[i] /= a.data[a.diag[i]]
+			}
+		}
+		for i := range x {
+			x[i] *= w * (2 - w)
+		}
+	})
+	return nil
+}
+
+// csr is a copy of a square matrix in compressed sparse row format.
+type csr struct {
+	n           int
+	indptr, ind []int
+	data        []float64
+
+	// diag holds the index of the diagonal
+	// element of each row, or -1 if it is
+	// not stored.
+	diag []int
+}
+
+// newCSR returns a copy of the square matrix a in compressed sparse row
+// format.
+func newCSR(a mat.Matrix) *csr {
+	n := squareDim(a)
+	var s *mat.CSR
+	switch a := a.(type) {
+	case *mat.CSR:
+		s = a
+	case interface{ ToCSR() *mat.CSR }:
+		s = a.ToCSR()
+	default:
+		coo := mat.NewCOO(n, n, nil, nil, nil)
+		if nz, ok := a.(mat.NonZeroDoer); ok {
+			nz.DoNonZero(func(i, j int, v float64) { coo.Append(i, j, v) })
+		} else {
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					if v := a.At(i, j); v != 0 {
+						coo.Append(i, j, v)
+					}
+				}
+			}
+		}
+		s = coo.ToCSR()
+	}
+	indptr, ind, data := s.RawCSR()
+	c := &csr{
+		n:      n,
+		indptr: append([]int(nil), indptr...),
+		ind:    append([]int(nil), ind...),
+		data:   append([]float64(nil), data...),
+		diag:   make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		c.diag[i] = -1
+		for q := c.indptr[i]; q < c.indptr[i+1]; q++ {
+			if c.ind[q] == i {
+				c.diag[i] = q
+				break
+			}
+		}
+	}
+	return c
+}
+
+// squareDim returns the dimension of the square matrix a.
+func squareDim(a mat.Matrix) int {
+	r, c := a.Dims()
+	if r != c {
+		panic(mat.ErrSquare)
+	}
+	return r
+}
+
+// solveVecTo stores the result of applying solve in place to a copy of b
+// into dst.
+func solveVecTo(dst *mat.VecDense, n int, b mat.Vector, solve func(x []float64)) {
+	if b.Len() != n {
+		panic(mat.ErrShape)
+	}
+	x := mat.VecDenseCopyOf(b)
+	solve(x.RawVector().Data)
+	dst.CloneVec(x)
+}
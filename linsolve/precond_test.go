@@ -0,0 +1,173 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// randSparse returns a random n×n sparse matrix with a dominant diagonal.
+func randSparse(n int, density float64, rnd *rand.Rand) *mat.CSR {
+	a := mat.NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		a.Append(i, i, float64(n)+rnd.Float64())
+	}
+	for k := 0; k < int(density*float64(n*n)); k++ {
+		a.Append(rnd.Intn(n), rnd.Intn(n), rnd.NormFloat64())
+	}
+	return a.ToCSR()
+}
+
+func TestPreconditioners(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 5, 30} {
+		a := randSparse(n, 0.2, rnd)
+		var d mat.Dense
+		d.CloneFrom(a)
+		diag := mat.NewDense(n, n, nil)
+		lower := mat.NewDense(n, n, nil)
+		upper := mat.NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				switch v := d.At(i, j); {
+				case i == j:
+					diag.Set(i, j, v)
+				case i > j:
+					lower.Set(i, j, v)
+				default:
+					upper.Set(i, j, v)
+				}
+			}
+		}
+
+		// Form each preconditioner matrix M explicitly.
+		const omega = 1.3
+		var dl, du, dinv, ssorM mat.Dense
+		dl.Scale(omega, lower)
+		dl.Add(diag, &dl)
+		du.Scale(omega, upper)
+		du.Add(diag, &du)
+		dinv.Inverse(diag)
+		ssorM.Product(&dl, &dinv, &du)
+		ssorM.Scale(1/(omega*(2-omega)), &ssorM)
+
+		ilu, ok := NewILU0(a)
+		if !ok {
+			t.Fatalf("n=%d: unexpected ILU0 failure", n)
+		}
+		// Check that L*U matches A on the pattern of A.
+		iluL := mat.NewDense(n, n, nil)
+		iluU := mat.NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			iluL.Set(i, i, 1)
+			for q := ilu.lu.indptr[i]; q < ilu.lu.indptr[i+1]; q++ {
+				if j := ilu.lu.ind[q]; j < i {
+					iluL.Set(i, j, ilu.lu.data[q])
+				} else {
+					iluU.Set(i, j, ilu.lu.data[q])
+				}
+			}
+		}
+		var iluM mat.Dense
+		iluM.Mul(iluL, iluU)
+		a.DoNonZero(func(i, j int, v float64) {
+			if diff := iluM.At(i, j) - v; diff*diff > 1e-24 {
+				t.Errorf("n=%d: ILU0 product does not match A at (%d,%d)", n, i, j)
+			}
+		})
+
+		jac, _ := NewJacobi(a)
+		ss, _ := NewSSOR(a, omega)
+		for _, test := range []struct {
+			name string
+			p    Preconditioner
+			m    mat.Matrix
+		}{
+			{name: "Jacobi", p: jac, m: diag},
+			{name: "SSOR", p: ss, m: &ssorM},
+			{name: "ILU0", p: ilu, m: &iluM},
+		} {
+			for _, trans := range []bool{false, true} {
+				name := fmt.Sprintf("%s n=%d trans=%t", test.name, n, trans)
+				b := mat.NewVecDense(n, nil)
+				for i := 0; i < n; i++ {
+					b.SetVec(i, rnd.NormFloat64())
+				}
+				var got, want mat.VecDense
+				if err := test.p.SolveVecTo(&got, trans, b); err != nil {
+					t.Errorf("%s: unexpected error: %v", name, err)
+				}
+				m := test.m
+				if trans {
+					m = m.T()
+				}
+				if err := want.SolveVec(m, b); err != nil {
+					t.Fatalf("%s: unexpected error solving with M: %v", name, err)
+				}
+				if !mat.EqualApprox(&got, &want, 1e-10) {
+					t.Errorf("%s: unexpected solution:\ngot  %v\nwant %v", name, mat.Formatted(got.T()), mat.Formatted(want.T()))
+				}
+			}
+		}
+	}
+}
+
+func TestILU0Tridiagonal(t *testing.T) {
+	t.Parallel()
+	// ILU(0) of a tridiagonal matrix has
+	// no fill so it is the exact LU.
+	const n = 10
+	a := mat.NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		a.Append(i, i, 3)
+		if i > 0 {
+			a.Append(i, i-1, -1)
+			a.Append(i-1, i, -2)
+		}
+	}
+	p, ok := NewILU0(a)
+	if !ok {
+		t.Fatal("unexpected ILU0 failure")
+	}
+	b := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		b.SetVec(i, float64(i+1))
+	}
+	var got, want mat.VecDense
+	p.SolveVecTo(&got, false, b)
+	want.SolveVec(a, b)
+	if !mat.EqualApprox(&got, &want, 1e-12) {
+		t.Errorf("unexpected solution:\ngot  %v\nwant %v", mat.Formatted(got.T()), mat.Formatted(want.T()))
+	}
+}
+
+func TestPreconditionerFailure(t *testing.T) {
+	t.Parallel()
+	zeroDiag := mat.NewDense(2, 2, []float64{0, 1, 1, 1})
+	if _, ok := NewJacobi(zeroDiag); ok {
+		t.Error("unexpected Jacobi success with zero diagonal")
+	}
+	if _, ok := NewSSOR(zeroDiag, 1); ok {
+		t.Error("unexpected SSOR success with zero diagonal")
+	}
+	if _, ok := NewILU0(zeroDiag); ok {
+		t.Error("unexpected ILU0 success with zero diagonal")
+	}
+	if _, ok := NewILU0(mat.NewDense(2, 2, []float64{1, 1, 1, 1})); ok {
+		t.Error("unexpected ILU0 success with zero pivot")
+	}
+	if !panics(func() { NewSSOR(mat.NewDiagDense(2, nil), 2) }) {
+		t.Error("expected panic for SSOR relaxation parameter")
+	}
+	if !panics(func() { NewJacobi(mat.NewDense(2, 3, nil)) }) {
+		t.Error("expected panic for non-square matrix")
+	}
+}
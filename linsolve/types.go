@@ -0,0 +1,228 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrIterationLimit is returned when the maximum number of iterations is
+// reached before the method converges.
+var ErrIterationLimit = errors.New("linsolve: iteration limit reached")
+
+// BreakdownError is returned by a Method when it cannot continue because
+// a quantity that must be non-zero, or positive, is not. Value holds the
+// offending quantity.
+type BreakdownError struct {
+	Method string
+	Value  float64
+}
+
+func (e *BreakdownError) Error() string {
+	return fmt.Sprintf("linsolve: %s breakdown: value=%v", e.Method, e.Value)
+}
+
+// MulVecToer is the interface implemented by linear operators that can
+// compute their products with vectors. Every mat.Operator is a MulVecToer,
+// so matrix-free operators can be passed directly to Iterative.
+type MulVecToer interface {
+	// MulVecTo stores A*x into dst if trans is false, and Aᵀ*x
+	// into dst otherwise. The vectors dst and x have length n
+	// and do not share storage.
+	MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector)
+}
+
+var _ MulVecToer = mat.Operator(nil)
+
+// Matrix is a MulVecToer computing the products of a matrix with vectors.
+type Matrix struct {
+	mat.Matrix
+}
+
+// MulVecTo stores A*x into dst if trans is false, and Aᵀ*x into dst
+// otherwise.
+func (a Matrix) MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector) {
+	if trans {
+		dst.MulVec(a.Matrix.T(), x)
+		return
+	}
+	dst.MulVec(a.Matrix, x)
+}
+
+// Preconditioner is the interface implemented by preconditioners that solve
+// systems with an approximation M to the matrix A.
+//
+// The LU and SparseLU types of package mat implement Preconditioner as
+// exact preconditioners.
+type Preconditioner interface {
+	// SolveVecTo stores the solution of M*dst = b into dst if trans
+	// is false, and of Mᵀ*dst = b otherwise.
+	SolveVecTo(dst *mat.VecDense, trans bool, b mat.Vector) error
+}
+
+// Operation is the set of operations requested by a Method at each step.
+// MulVec and PreconSolve may be combined with Trans using the binary OR
+// operator. Other operations must not be combined.
+type Operation uint
+
+// Supported Operations.
+const (
+	// NoOperation specifies that no action should be taken.
+	NoOperation Operation = 0
+
+	// MulVec specifies that the product of A with Context.Src
+	// should be stored in Context.Dst.
+	MulVec Operation = 1 << (iota - 1)
+
+	// PreconSolve specifies that the solution of the
+	// preconditioner system M*Dst = Src should be stored
+	// in Context.Dst.
+	PreconSolve
+
+	// Trans specifies that the transpose of A or M should
+	// be used for MulVec or PreconSolve.
+	Trans
+
+	// ComputeResidual specifies that the residual b - A*X
+	// should be stored in Context.Dst.
+	ComputeResidual
+
+	// CheckResidualNorm specifies that convergence should be
+	// checked using Context.ResidualNorm. The result is
+	// stored in Context.Converged.
+	CheckResidualNorm
+
+	// MajorIteration indicates that an iteration of the method
+	// is complete and that Context.X holds the current estimate
+	// of the solution. Iterative stops after a MajorIteration
+	// if Context.Converged is true.
+	MajorIteration
+)
+
+func (op Operation) String() string {
+	var s string
+	switch op &^ Trans {
+	case NoOperation:
+		s = "NoOperation"
+	case MulVec:
+		s = "MulVec"
+	case PreconSolve:
+		s = "PreconSolve"
+	case ComputeResidual:
+		s = "ComputeResidual"
+	case CheckResidualNorm:
+		s = "CheckResidualNorm"
+	case MajorIteration:
+		s = "MajorIteration"
+	default:
+		return fmt.Sprintf("Operation(%d)", op)
+	}
+	if op&Trans != 0 {
+		s += "|Trans"
+	}
+	return s
+}
+
+// Context holds the state of an iterative solve that is shared between a
+// Method and Iterative.
+type Context struct {
+	// X is the current estimate of the solution.
+	// It is updated by the Method.
+	X *mat.VecDense
+
+	// ResidualNorm is the estimate of the norm of
+	// the residual b - A*X computed by the Method
+	// before requesting CheckResidualNorm.
+	ResidualNorm float64
+
+	// Converged is the result of the last
+	// CheckResidualNorm operation.
+	Converged bool
+
+	// Src and Dst are the operand and result of the
+	// MulVec, PreconSolve and ComputeResidual
+	// operations.
+	Src, Dst *mat.VecDense
+}
+
+// Method is an iterative method for solving systems of linear equations.
+//
+// A Method is initialized by Init, and then Iterate is called repeatedly,
+// with the caller performing the returned operation before the next call.
+// Iterate must not retain ctx between calls, though the vectors it holds
+// are the same in every call.
+type Method interface {
+	// Init initializes the method for solving a system
+	// with the initial estimate x and the corresponding
+	// residual b - A*x. Init must not retain x or
+	// residual.
+	Init(x, residual mat.Vector)
+
+	// Iterate performs a step of the method, returning
+	// the operation that the caller must perform next.
+	Iterate(ctx *Context) (Operation, error)
+}
+
+// Stats holds statistics about an iterative solve.
+type Stats struct {
+	Iterations  int           // Number of major iterations.
+	MulVec      int           // Number of products with A or Aᵀ.
+	PreconSolve int           // Number of preconditioner solves.
+	Runtime     time.Duration // Total runtime of the solve.
+}
+
+// Settings holds the settings for an iterative solve. See the field
+// comments for default values.
+type Settings struct {
+	// InitX holds the initial estimate of the solution.
+	// If InitX is nil, the zero vector is used.
+	InitX mat.Vector
+
+	// Dst, if not nil, is used to hold the solution
+	// returned in Result.
+	Dst *mat.VecDense
+
+	// Tolerance is the relative residual tolerance for
+	// convergence. The solve converges when the residual
+	// norm estimated by the Method is less than
+	// Tolerance times the norm of b. If Tolerance is
+	// zero, a default of 1e-8 is used. Tolerance must
+	// be less than 1.
+	Tolerance float64
+
+	// MaxIterations is the maximum number of major
+	// iterations. If MaxIterations is zero, a default
+	// of 4*n is used.
+	MaxIterations int
+
+	// Preconditioner is the preconditioner used by the
+	// Method. If Preconditioner is nil, the identity
+	// is used.
+	Preconditioner Preconditioner
+
+	// Monitor, if not nil, is called after each major
+	// iteration with the current statistics and
+	// estimate of the residual norm. If Monitor returns
+	// a non-nil error the solve is stopped and the
+	// error is returned by Iterative.
+	Monitor func(stats Stats, residualNorm float64) error
+}
+
+// Result holds the result of an iterative solve.
+type Result struct {
+	// X is the estimate of the solution.
+	X *mat.VecDense
+
+	// ResidualNorm is the estimate of the
+	// norm of the residual b - A*X.
+	ResidualNorm float64
+
+	// Stats holds statistics of the solve.
+	Stats Stats
+}
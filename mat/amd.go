@@ -0,0 +1,349 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "sort"
+
+// AMD returns an approximate minimum degree ordering of the rows and columns
+// of the square matrix a, based on the pattern of non-zero elements of
+// a+aᵀ. The returned perm holds the index of the row and column of a that is
+// placed at position k in perm[k]. Factorizing the symmetrically permuted
+// matrix in this order usually produces much less fill than factorizing a
+// directly. AMD will panic if a is not square.
+//
+// AMD eliminates vertices of the graph of a in order of least approximate
+// external degree using a quotient graph, with element absorption and the
+// merging of indistinguishable vertices into supervariables. See Amestoy,
+// Davis and Duff, "An approximate minimum degree ordering algorithm", SIAM
+// Journal on Matrix Analysis and Applications 17(4), 1996.
+func AMD(a Matrix) []int {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	return amd(symmetricPattern(a))
+}
+
+// symmetricPattern returns the sorted adjacency lists of the graph of the
+// pattern of a+aᵀ, excluding the diagonal.
+func symmetricPattern(a Matrix) [][]int {
+	s := csrOf(a)
+	t := s.transpose()
+	adj := make([][]int, s.rows)
+	for i := range adj {
+		x := s.ind[s.indptr[i]:s.indptr[i+1]]
+		y := t.ind[t.indptr[i]:t.indptr[i+1]]
+		row := make([]int, 0, len(x)+len(y))
+		for len(x) != 0 || len(y) != 0 {
+			var j int
+			switch {
+			case len(y) == 0 || (len(x) != 0 && x[0] < y[0]):
+				j, x = x[0], x[1:]
+			case len(x) == 0 || y[0] < x[0]:
+				j, y = y[0], y[1:]
+			default:
+				j, x, y = x[0], x[1:], y[1:]
+			}
+			if j != i {
+				row = append(row, j)
+			}
+		}
+		adj[i] = row
+	}
+	return adj
+}
+
+// Node kinds in the AMD quotient graph.
+const (
+	amdVariable = iota // An uneliminated principal variable.
+	amdElement         // An eliminated variable, now an element.
+	amdAbsorbed        // An element absorbed into another element.
+	amdMerged          // A variable merged into a supervariable.
+)
+
+// amd returns an approximate minimum degree ordering of the graph with the
+// given adjacency lists.
+func amd(adj [][]int) []int {
+	n := len(adj)
+	var (
+		kind = make([]int, n)
+
+		// nv is the number of variables
+		// in each principal supervariable.
+		nv = make([]int, n)
+
+		// vars holds the variables adjacent
+		// to each variable, and elems holds
+		// the elements adjacent to each
+		// variable. For elements, vars holds
+		// the variables of the element.
+		vars  = make([][]int, n)
+		elems = make([][]int, n)
+
+		// members holds the variables merged
+		// into each principal supervariable.
+		members = make([][]int, n)
+
+		deg = make([]int, n)
+		q   = newDegreeLists(n)
+
+		// tag marks the variables of the current
+		// pivot element, and w holds the number
+		// of variables in elements outside the
+		// current pivot element.
+		tag   = make([]int, n)
+		stamp int
+		w     = make([]int, n)
+	)
+	for i := range adj {
+		nv[i] = 1
+		vars[i] = append([]int(nil), adj[i]...)
+		deg[i] = len(adj[i])
+		q.insert(i, deg[i])
+		tag[i] = -1
+		w[i] = -1
+	}
+
+	order := make([]int, 0, n)
+	for k := 0; k < n; {
+		p := q.popMin()
+
+		// Form the variables of the new element p
+		// from the variables adjacent to p and the
+		// variables of the elements adjacent to p,
+		// which are absorbed into p.
+		stamp++
+		tag[p] = stamp
+		var lp []int
+		var degLp int
+		add := func(i int) {
+			if kind[i] == amdVariable && tag[i] != stamp {
+				tag[i] = stamp
+				lp = append(lp, i)
+				degLp += nv[i]
+			}
+		}
+		for _, i := range vars[p] {
+			add(i)
+		}
+		for _, e := range elems[p] {
+			if kind[e] != amdElement {
+				continue
+			}
+			for _, i := range vars[e] {
+				add(i)
+			}
+			kind[e] = amdAbsorbed
+			vars[e] = nil
+		}
+		kind[p] = amdElement
+		vars[p] = lp
+		elems[p] = nil
+		order = append(order, p)
+		order = append(order, members[p]...)
+		members[p] = nil
+		k += nv[p]
+
+		// Update the lists of the variables of p.
+		for _, i := range lp {
+			q.remove(i)
+			e := elems[i][:0]
+			for _, f := range elems[i] {
+				if kind[f] == amdElement {
+					e = append(e, f)
+				}
+			}
+			elems[i] = append(e, p)
+			v := vars[i][:0]
+			for _, j := range vars[i] {
+				if kind[j] == amdVariable && tag[j] != stamp {
+					v = append(v, j)
+				}
+			}
+			vars[i] = v
+		}
+
+		// Find the number of variables of each element
+		// adjacent to the variables of p that are outside
+		// p. Elements within p are absorbed into p.
+		var touched []int
+		for _, i := range lp {
+			for _, e := range elems[i] {
+				if e == p {
+					continue
+				}
+				if w[e] < 0 {
+					v := vars[e][:0]
+					var d int
+					for _, j := range vars[e] {
+						if kind[j] == amdVariable {
+							v = append(v, j)
+							d += nv[j]
+						}
+					}
+					vars[e] = v
+					w[e] = d
+					touched = append(touched, e)
+				}
+				w[e] -= nv[i]
+			}
+		}
+		for _, e := range touched {
+			if w[e] == 0 {
+				kind[e] = amdAbsorbed
+				vars[e] = nil
+			}
+		}
+
+		// Compute the approximate external degrees
+		// of the variables of p.
+		for _, i := range lp {
+			var d int
+			for _, j := range vars[i] {
+				d += nv[j]
+			}
+			e := elems[i][:0]
+			for _, f := range elems[i] {
+				if kind[f] != amdElement {
+					continue
+				}
+				e = append(e, f)
+				if f != p {
+					d += w[f]
+				}
+			}
+			elems[i] = e
+			ext := degLp - nv[i]
+			d += ext
+			d = min(d, deg[i]+ext)
+			d = min(d, n-k-nv[i])
+			deg[i] = max(d, 0)
+		}
+		for _, e := range touched {
+			w[e] = -1
+		}
+
+		// Merge indistinguishable variables of p,
+		// those with identical adjacency, into
+		// supervariables.
+		buckets := make(map[int][]int)
+		for _, i := range lp {
+			sort.Ints(vars[i])
+			sort.Ints(elems[i])
+			var h int
+			for _, j := range vars[i] {
+				h += j
+			}
+			for _, j := range elems[i] {
+				h += j
+			}
+			buckets[h] = append(buckets[h], i)
+		}
+		for _, i := range lp {
+			if kind[i] != amdVariable {
+				continue
+			}
+			h := 0
+			for _, j := range vars[i] {
+				h += j
+			}
+			for _, j := range elems[i] {
+				h += j
+			}
+			for _, j := range buckets[h] {
+				if j == i || kind[j] != amdVariable || !equalInts(vars[i], vars[j]) || !equalInts(elems[i], elems[j]) {
+					continue
+				}
+				nv[i] += nv[j]
+				deg[i] = max(deg[i]-nv[j], 0)
+				members[i] = append(members[i], j)
+				members[i] = append(members[i], members[j]...)
+				kind[j] = amdMerged
+				nv[j] = 0
+				vars[j], elems[j], members[j] = nil, nil, nil
+			}
+		}
+
+		// Return the principal variables of p to
+		// the degree lists.
+		v := lp[:0]
+		for _, i := range lp {
+			if kind[i] == amdVariable {
+				v = append(v, i)
+				q.insert(i, deg[i])
+			}
+		}
+		vars[p] = v
+	}
+	return order
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// degreeLists is a set of doubly linked lists of vertices indexed by degree.
+type degreeLists struct {
+	head, next, prev, deg []int
+	min                   int
+}
+
+func newDegreeLists(n int) *degreeLists {
+	q := &degreeLists{
+		head: make([]int, n+1),
+		next: make([]int, n),
+		prev: make([]int, n),
+		deg:  make([]int, n),
+	}
+	for i := range q.head {
+		q.head[i] = -1
+	}
+	return q
+}
+
+// insert adds vertex i with degree d to the lists.
+func (q *degreeLists) insert(i, d int) {
+	q.deg[i] = d
+	q.prev[i] = -1
+	q.next[i] = q.head[d]
+	if q.head[d] >= 0 {
+		q.prev[q.head[d]] = i
+	}
+	q.head[d] = i
+	if d < q.min {
+		q.min = d
+	}
+}
+
+// remove removes vertex i from the lists.
+func (q *degreeLists) remove(i int) {
+	if q.prev[i] >= 0 {
+		q.next[q.prev[i]] = q.next[i]
+	} else {
+		q.head[q.deg[i]] = q.next[i]
+	}
+	if q.next[i] >= 0 {
+		q.prev[q.next[i]] = q.prev[i]
+	}
+}
+
+// popMin removes and returns a vertex of least degree. There must be a
+// vertex in the lists.
+func (q *degreeLists) popMin() int {
+	for q.head[q.min] < 0 {
+		q.min++
+	}
+	i := q.head[q.min]
+	q.remove(i)
+	return i
+}
@@ -0,0 +1,116 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// gridLaplacian returns the m²×m² five-point Laplacian of an m×m grid
+// shifted by s.
+func gridLaplacian(m int, s float64) *CSR {
+	n := m * m
+	a := NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			k := i*m + j
+			a.Append(k, k, 4+s)
+			if i > 0 {
+				a.Append(k, k-m, -1)
+			}
+			if i < m-1 {
+				a.Append(k, k+m, -1)
+			}
+			if j > 0 {
+				a.Append(k, k-1, -1)
+			}
+			if j < m-1 {
+				a.Append(k, k+1, -1)
+			}
+		}
+	}
+	return a.ToCSR()
+}
+
+// choleskyFill returns the number of elements of the Cholesky factor of the
+// symmetric matrix a permuted by perm.
+func choleskyFill(a Matrix, perm []int) int {
+	c := &SparseCholesky{n: len(perm), perm: perm, pinv: make([]int, len(perm))}
+	for k, i := range perm {
+		c.pinv[i] = k
+	}
+	c.analyze(c.permutedUpper(a))
+	return c.colptr[c.n]
+}
+
+func isPerm(p []int, n int) bool {
+	if len(p) != n {
+		return false
+	}
+	seen := make([]bool, n)
+	for _, v := range p {
+		if v < 0 || n <= v || seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
+
+func TestAMD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 20, 100} {
+		for _, density := range []float64{0, 0.05, 0.2, 0.8} {
+			a, _ := randCOO(n, n, density, rnd)
+			perm := AMD(a)
+			if !isPerm(perm, n) {
+				t.Errorf("unexpected ordering for n=%d density=%v: %v", n, density, perm)
+			}
+		}
+	}
+
+	natural := func(n int) []int {
+		p := make([]int, n)
+		for i := range p {
+			p[i] = i
+		}
+		return p
+	}
+	for _, m := range []int{10, 30} {
+		a := gridLaplacian(m, 0)
+		perm := AMD(a)
+		if !isPerm(perm, m*m) {
+			t.Fatalf("unexpected ordering for %d×%d grid", m, m)
+		}
+		got := choleskyFill(a, perm)
+		want := choleskyFill(a, natural(m*m))
+		if 3*got > 2*want {
+			t.Errorf("unexpected fill for %d×%d grid: got %d natural %d", m, m, got, want)
+		}
+	}
+
+	// An arrow matrix has no fill when its dense
+	// row and column are ordered last.
+	const n = 50
+	arrow := NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		arrow.Append(i, i, 1)
+		if i != 0 {
+			arrow.Append(0, i, 1)
+			arrow.Append(i, 0, 1)
+		}
+	}
+	perm := AMD(arrow)
+	if got := choleskyFill(arrow, perm); got != 2*n-1 {
+		t.Errorf("unexpected arrow fill: got %d want %d", got, 2*n-1)
+	}
+
+	if panicked, _ := panics(func() { AMD(NewCOO(2, 3, nil, nil, nil)) }); !panicked {
+		t.Error("expected panic for non-square matrix")
+	}
+}
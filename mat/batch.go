@@ -0,0 +1,170 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// BatchError is the error returned by batched operations when the operation
+// fails for one or more elements of the batch. Each non-nil element holds the
+// error for the corresponding element of the batch.
+type BatchError []error
+
+func (e BatchError) Error() string {
+	var (
+		n     int
+		first = -1
+	)
+	for i, err := range e {
+		if err == nil {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		n++
+	}
+	if first < 0 {
+		return "mat: no batch errors"
+	}
+	return fmt.Sprintf("mat: %d of %d batch operations failed: element %d: %v", n, len(e), first, e[first])
+}
+
+// MulBatch computes dst[i] = a[i] * b[i] for each element of the batch,
+// distributing the products over GOMAXPROCS goroutines. A nil element of
+// dst is replaced with a newly allocated matrix, and each non-nil element
+// must satisfy the size restrictions of Dense.Mul.
+//
+// MulBatch will panic if the lengths of dst, a and b are not equal or if
+// any of the individual products would panic.
+func MulBatch(dst []*Dense, a, b []Matrix) {
+	if len(a) != len(dst) || len(b) != len(dst) {
+		panic(ErrSliceLengthMismatch)
+	}
+	newBatchDst(dst)
+	batch(len(dst), func(start, end int) {
+		for i := start; i < end; i++ {
+			dst[i].Mul(a[i], b[i])
+		}
+	})
+}
+
+// LUSolveBatch solves a[i] * dst[i] = b[i] for each element of the batch using
+// the LU decomposition of a[i], distributing the solutions over GOMAXPROCS
+// goroutines. A nil element of dst is replaced with a newly allocated matrix.
+// The matrices of a must be square. Each goroutine reuses its decomposition
+// storage, so the per-solve allocation cost is amortized over the batch.
+//
+// If one or more of the matrices of a is singular or near-singular, a
+// BatchError is returned holding the Condition errors of the solutions, as
+// returned by LU.SolveTo. LUSolveBatch will panic if the lengths of dst, a and
+// b are not equal or if any of the individual solutions would panic.
+func LUSolveBatch(dst []*Dense, a, b []Matrix) error {
+	if len(a) != len(dst) || len(b) != len(dst) {
+		panic(ErrSliceLengthMismatch)
+	}
+	newBatchDst(dst)
+	errs := make(BatchError, len(dst))
+	batch(len(dst), func(start, end int) {
+		var (
+			lu LU
+			ws Workspace
+		)
+		for i := start; i < end; i++ {
+			lu.FactorizeWork(a[i], &ws)
+			errs[i] = lu.SolveTo(dst[i], false, b[i])
+		}
+	})
+	return errs.errOrNil()
+}
+
+// EigBatch computes the eigenvalue decompositions of the square matrices of
+// a into the corresponding elements of dst as for Eigen.Factorize with the
+// given kind, distributing the factorizations over GOMAXPROCS goroutines.
+//
+// If one or more of the factorizations fails, a BatchError is returned holding
+// ErrFailedEigen for each failed factorization. EigBatch will panic if the
+// lengths of dst and a are not equal or if any of the factorizations would
+// panic.
+func EigBatch(dst []Eigen, a []Matrix, kind EigenKind) error {
+	if len(a) != len(dst) {
+		panic(ErrSliceLengthMismatch)
+	}
+	errs := make(BatchError, len(dst))
+	batch(len(dst), func(start, end int) {
+		var ws Workspace
+		for i := start; i < end; i++ {
+			if !dst[i].FactorizeWork(a[i], kind, &ws) {
+				errs[i] = ErrFailedEigen
+			}
+		}
+	})
+	return errs.errOrNil()
+}
+
+// errOrNil returns the receiver if any of its elements is non-nil,
+// and nil otherwise.
+func (e BatchError) errOrNil() error {
+	for _, err := range e {
+		if err != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// newBatchDst replaces nil elements of dst with zero-valued matrices.
+func newBatchDst(dst []*Dense) {
+	for i, d := range dst {
+		if d == nil {
+			dst[i] = &Dense{}
+		}
+	}
+}
+
+// batch calls fn over contiguous ranges covering [0, n) using up to
+// GOMAXPROCS goroutines. If any call to fn panics, batch panics with
+// the first recovered value after all calls have returned.
+func batch(n int, fn func(start, end int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	if workers <= 1 {
+		fn(0, n)
+		return
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		recovered interface{}
+	)
+	chunk := (n + workers - 1) / workers
+	for start := 0; start < n; start += chunk {
+		end := min(start+chunk, n)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					if recovered == nil {
+						recovered = r
+					}
+					mu.Unlock()
+				}
+			}()
+			fn(start, end)
+		}(start, end)
+	}
+	wg.Wait()
+	if recovered != nil {
+		panic(recovered)
+	}
+}
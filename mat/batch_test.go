@@ -0,0 +1,120 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"runtime"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestBatch(t *testing.T) {
+	// Ensure the concurrent path is exercised
+	// on single processor machines.
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 3, 7, 20} {
+		a := make([]Matrix, n)
+		b := make([]Matrix, n)
+		for i := range a {
+			a[i] = randNormDense(4, 4, rnd)
+			b[i] = randNormDense(4, 2, rnd)
+		}
+
+		dst := make([]*Dense, n)
+		if n > 2 {
+			dst[1] = NewDense(4, 2, nil)
+		}
+		MulBatch(dst, a, b)
+		for i := range dst {
+			var want Dense
+			want.Mul(a[i], b[i])
+			if !Equal(dst[i], &want) {
+				t.Errorf("unexpected MulBatch result for n=%d at %d:\ngot:\n%v\nwant:\n%v", n, i, Formatted(dst[i]), Formatted(&want))
+			}
+		}
+
+		dst = make([]*Dense, n)
+		err := LUSolveBatch(dst, a, b)
+		if err != nil {
+			t.Errorf("unexpected error from LUSolveBatch for n=%d: %v", n, err)
+		}
+		for i := range dst {
+			var want Dense
+			err := want.Solve(a[i], b[i])
+			if err != nil {
+				t.Fatalf("unexpected error from Solve: %v", err)
+			}
+			if !EqualApprox(dst[i], &want, 1e-12) {
+				t.Errorf("unexpected LUSolveBatch result for n=%d at %d:\ngot:\n%v\nwant:\n%v", n, i, Formatted(dst[i]), Formatted(&want))
+			}
+		}
+
+		eig := make([]Eigen, n)
+		err = EigBatch(eig, a, EigenRight)
+		if err != nil {
+			t.Errorf("unexpected error from EigBatch for n=%d: %v", n, err)
+		}
+		for i := range eig {
+			var want Eigen
+			if !want.Factorize(a[i], EigenRight) {
+				t.Fatalf("unexpected eigendecomposition failure")
+			}
+			if !CEqual(eig[i].VectorsTo(nil), want.VectorsTo(nil)) {
+				t.Errorf("unexpected EigBatch eigenvectors for n=%d at %d", n, i)
+			}
+			got, wantVals := eig[i].Values(nil), want.Values(nil)
+			for j := range wantVals {
+				if got[j] != wantVals[j] {
+					t.Errorf("unexpected EigBatch eigenvalue for n=%d at %d: got %v want %v", n, i, got[j], wantVals[j])
+				}
+			}
+		}
+	}
+}
+
+func TestLUSolveBatchSingular(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	a := []Matrix{
+		NewDense(2, 2, []float64{1, 2, 3, 4}),
+		NewDense(2, 2, []float64{1, 2, 2, 4}),
+		NewDense(2, 2, []float64{2, 0, 0, 2}),
+	}
+	b := []Matrix{
+		NewDense(2, 1, []float64{1, 1}),
+		NewDense(2, 1, []float64{1, 1}),
+		NewDense(2, 1, []float64{1, 1}),
+	}
+	dst := make([]*Dense, len(a))
+	err := LUSolveBatch(dst, a, b)
+	errs, ok := err.(BatchError)
+	if !ok {
+		t.Fatalf("unexpected error type: %T", err)
+	}
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Errorf("unexpected batch errors: %v", []error(errs))
+	}
+	if !EqualApprox(dst[2], NewDense(2, 1, []float64{0.5, 0.5}), 1e-15) {
+		t.Errorf("unexpected solution:\n%v", Formatted(dst[2]))
+	}
+}
+
+func TestMulBatchPanic(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	a := []Matrix{NewDense(2, 2, nil), NewDense(2, 3, nil), NewDense(2, 2, nil)}
+	b := []Matrix{NewDense(2, 2, nil), NewDense(2, 2, nil), NewDense(2, 2, nil)}
+	panicked, message := panics(func() { MulBatch(make([]*Dense, 3), a, b) })
+	if !panicked || message != ErrShape.Error() {
+		t.Errorf("expected panic with %q, got panicked=%t message=%q", ErrShape, panicked, message)
+	}
+	panicked, message = panics(func() { MulBatch(make([]*Dense, 2), a, b) })
+	if !panicked || message != ErrSliceLengthMismatch.Error() {
+		t.Errorf("expected panic with %q, got panicked=%t message=%q", ErrSliceLengthMismatch, panicked, message)
+	}
+}
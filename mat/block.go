@@ -0,0 +1,412 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sort"
+)
+
+var (
+	blockDense *BlockDense
+	_          Matrix      = blockDense
+	_          NonZeroDoer = blockDense
+
+	blockDiagonal *BlockDiagonal
+	_             Matrix      = blockDiagonal
+	_             NonZeroDoer = blockDiagonal
+)
+
+// BlockDense represents a matrix partitioned into a grid of blocks. Each
+// block may be any Matrix, and blocks that have not been set are zero and
+// take no storage, so a large matrix with few non-zero blocks can be used
+// without being formed explicitly.
+//
+// Dense.Mul and VecDense.MulVec only form the products of the non-zero
+// blocks when an operand is a BlockDense.
+type BlockDense struct {
+	// rowOff and colOff hold the offsets of
+	// the block rows and columns followed by
+	// the matrix dimensions.
+	rowOff, colOff []int
+
+	// blocks holds the blocks in row-major
+	// order with nil for zero blocks.
+	blocks []Matrix
+}
+
+// NewBlockDense returns a new zero block matrix with block rows of the sizes
+// in rows and block columns of the sizes in cols. The blocks are set with
+// SetBlock. NewBlockDense will panic if rows or cols is empty or holds a size
+// that is not positive.
+func NewBlockDense(rows, cols []int) *BlockDense {
+	return &BlockDense{
+		rowOff: blockOffsets(rows),
+		colOff: blockOffsets(cols),
+		blocks: make([]Matrix, len(rows)*len(cols)),
+	}
+}
+
+// blockOffsets returns the offsets of blocks of the given sizes followed by
+// their total.
+func blockOffsets(sizes []int) []int {
+	if len(sizes) == 0 {
+		panic(ErrZeroLength)
+	}
+	off := make([]int, len(sizes)+1)
+	for i, s := range sizes {
+		if s <= 0 {
+			panic(ErrZeroLength)
+		}
+		off[i+1] = off[i] + s
+	}
+	return off
+}
+
+// findBlock returns the index of the block holding the element i given the
+// block offsets.
+func findBlock(off []int, i int) int {
+	return sort.SearchInts(off, i+1) - 1
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (b *BlockDense) Dims() (r, c int) {
+	return b.rowOff[len(b.rowOff)-1], b.colOff[len(b.colOff)-1]
+}
+
+// BlockDims returns the number of block rows and block columns in the
+// matrix.
+func (b *BlockDense) BlockDims() (r, c int) {
+	return len(b.rowOff) - 1, len(b.colOff) - 1
+}
+
+// At returns the element at row i, column j. At takes O(log n) time for a
+// matrix with n block rows and columns.
+func (b *BlockDense) At(i, j int) float64 {
+	r, c := b.Dims()
+	if uint(i) >= uint(r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(c) {
+		panic(ErrColAccess)
+	}
+	bi := findBlock(b.rowOff, i)
+	bj := findBlock(b.colOff, j)
+	blk := b.blocks[bi*(len(b.colOff)-1)+bj]
+	if blk == nil {
+		return 0
+	}
+	return blk.At(i-b.rowOff[bi], j-b.colOff[bj])
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (b *BlockDense) T() Matrix {
+	return Transpose{b}
+}
+
+// Block returns the block in block row i and block column j, which is nil
+// if the block is zero.
+func (b *BlockDense) Block(i, j int) Matrix {
+	br, bc := b.BlockDims()
+	if uint(i) >= uint(br) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(bc) {
+		panic(ErrColAccess)
+	}
+	return b.blocks[i*bc+j]
+}
+
+// SetBlock sets the block in block row i and block column j to a. The block
+// is not copied, so later changes to a are seen by the receiver. A nil a
+// sets the block to zero. SetBlock will panic if a does not have the size of
+// the block.
+func (b *BlockDense) SetBlock(i, j int, a Matrix) {
+	br, bc := b.BlockDims()
+	if uint(i) >= uint(br) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(bc) {
+		panic(ErrColAccess)
+	}
+	if a != nil {
+		r, c := a.Dims()
+		if r != b.rowOff[i+1]-b.rowOff[i] || c != b.colOff[j+1]-b.colOff[j] {
+			panic(ErrShape)
+		}
+	}
+	b.blocks[i*bc+j] = a
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of the
+// receiver. The function fn takes a row/column index and the element value
+// of the receiver at (i, j). The order of calls to fn is undefined.
+func (b *BlockDense) DoNonZero(fn func(i, j int, v float64)) {
+	_, bc := b.BlockDims()
+	for k, blk := range b.blocks {
+		if blk != nil {
+			doBlockNonZero(blk, b.rowOff[k/bc], b.colOff[k%bc], fn)
+		}
+	}
+}
+
+// doBlocks calls fn for each non-zero block of the receiver with the offsets
+// of the block.
+func (b *BlockDense) doBlocks(fn func(i, j int, blk Matrix)) {
+	_, bc := b.BlockDims()
+	for k, blk := range b.blocks {
+		if blk != nil {
+			fn(b.rowOff[k/bc], b.colOff[k%bc], blk)
+		}
+	}
+}
+
+// BlockDiagonal represents a square block diagonal matrix with square
+// diagonal blocks. Each block may be any Matrix, and the elements outside
+// the diagonal blocks take no storage.
+//
+// Dense.Mul and VecDense.MulVec only form the products of the diagonal
+// blocks when an operand is a BlockDiagonal, and Dense.Solve solves with each
+// diagonal block in turn.
+type BlockDiagonal struct {
+	off    []int
+	blocks []Matrix
+}
+
+// NewBlockDiagonal returns a new block diagonal matrix with the given
+// diagonal blocks. The blocks are not copied, so later changes to them are
+// seen by the returned matrix. NewBlockDiagonal will panic if no blocks are
+// given or if a block is not square.
+func NewBlockDiagonal(blocks ...Matrix) *BlockDiagonal {
+	sizes := make([]int, len(blocks))
+	for i, blk := range blocks {
+		r, c := blk.Dims()
+		if r != c {
+			panic(ErrSquare)
+		}
+		sizes[i] = r
+	}
+	return &BlockDiagonal{
+		off:    blockOffsets(sizes),
+		blocks: blocks,
+	}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (b *BlockDiagonal) Dims() (r, c int) {
+	n := b.off[len(b.off)-1]
+	return n, n
+}
+
+// Blocks returns the number of diagonal blocks in the matrix.
+func (b *BlockDiagonal) Blocks() int {
+	return len(b.blocks)
+}
+
+// Block returns the i-th diagonal block.
+func (b *BlockDiagonal) Block(i int) Matrix {
+	if uint(i) >= uint(len(b.blocks)) {
+		panic(ErrIndexOutOfRange)
+	}
+	return b.blocks[i]
+}
+
+// At returns the element at row i, column j. At takes O(log n) time for a
+// matrix with n blocks.
+func (b *BlockDiagonal) At(i, j int) float64 {
+	n, _ := b.Dims()
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	k := findBlock(b.off, i)
+	if j < b.off[k] || b.off[k+1] <= j {
+		return 0
+	}
+	return b.blocks[k].At(i-b.off[k], j-b.off[k])
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (b *BlockDiagonal) T() Matrix {
+	return Transpose{b}
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of the
+// receiver. The function fn takes a row/column index and the element value
+// of the receiver at (i, j). The order of calls to fn is undefined.
+func (b *BlockDiagonal) DoNonZero(fn func(i, j int, v float64)) {
+	for k, blk := range b.blocks {
+		doBlockNonZero(blk, b.off[k], b.off[k], fn)
+	}
+}
+
+// doBlocks calls fn for each block of the receiver with the offsets of the
+// block.
+func (b *BlockDiagonal) doBlocks(fn func(i, j int, blk Matrix)) {
+	for k, blk := range b.blocks {
+		fn(b.off[k], b.off[k], blk)
+	}
+}
+
+// SolveTo solves a system of linear equations with the block diagonal
+// matrix A. It computes
+//
+//	A * X = B if trans == false
+//	Aᵀ * X = B if trans == true
+//
+// by solving with each diagonal block in turn, and stores the matrix X into
+// dst. If a block is singular or near-singular a Condition error for the
+// worst conditioned block is returned. See the documentation for Condition
+// for more information.
+func (b *BlockDiagonal) SolveTo(dst *Dense, trans bool, rhs Matrix) error {
+	n, _ := b.Dims()
+	br, bc := rhs.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	dst.reuseAs(n, bc)
+
+	// The right-hand side is copied so that it
+	// may share storage with dst.
+	w := DenseCopyOf(rhs)
+	var cond Condition
+	for k, blk := range b.blocks {
+		if trans {
+			blk = blk.T()
+		}
+		lo, hi := b.off[k], b.off[k+1]
+		x := dst.Slice(lo, hi, 0, bc).(*Dense)
+		err := x.Solve(blk, w.Slice(lo, hi, 0, bc))
+		if err != nil {
+			c, ok := err.(Condition)
+			if !ok {
+				return err
+			}
+			if c > cond {
+				cond = c
+			}
+		}
+	}
+	if cond > 0 {
+		return cond
+	}
+	return nil
+}
+
+// doBlockNonZero calls fn for the non-zero elements of blk offset by (i0, j0).
+func doBlockNonZero(blk Matrix, i0, j0 int, fn func(i, j int, v float64)) {
+	if nz, ok := blk.(NonZeroDoer); ok {
+		nz.DoNonZero(func(i, j int, v float64) {
+			fn(i0+i, j0+j, v)
+		})
+		return
+	}
+	r, c := blk.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if v := blk.At(i, j); v != 0 {
+				fn(i0+i, j0+j, v)
+			}
+		}
+	}
+}
+
+// blockMatrix is a matrix stored as a collection of blocks.
+type blockMatrix interface {
+	Matrix
+	doBlocks(fn func(i, j int, blk Matrix))
+}
+
+// blocksOf returns a if it is a block matrix or the transpose of a block
+// matrix, and whether it is the transpose.
+func blocksOf(a Matrix) (b blockMatrix, trans, ok bool) {
+	aU, trans := untranspose(a)
+	b, ok = aU.(blockMatrix)
+	return b, trans, ok
+}
+
+// mulBlock places the product of a and b in the receiver if either is a
+// block matrix, and returns whether it did so. The receiver must have the
+// dimensions of the product and must not be a or b.
+func (m *Dense) mulBlock(a, b Matrix) bool {
+	mr, mc := m.Dims()
+	if x, trans, ok := blocksOf(a); ok {
+		bU, _ := untranspose(b)
+		m.checkOverlapMatrix(bU)
+		bd, restore := denseOf(b)
+		defer restore()
+		m.Zero()
+		var prod Dense
+		x.doBlocks(func(i, j int, blk Matrix) {
+			r, c := blk.Dims()
+			if trans {
+				// Block (i, j) of x is block (j, i) of a.
+				blk = blk.T()
+				i, j = j, i
+				r, c = c, r
+			}
+			prod.Reset()
+			prod.Mul(blk, bd.Slice(j, j+c, 0, mc))
+			dst := m.Slice(i, i+r, 0, mc).(*Dense)
+			dst.Add(dst, &prod)
+		})
+		return true
+	}
+	if y, trans, ok := blocksOf(b); ok {
+		aU, _ := untranspose(a)
+		m.checkOverlapMatrix(aU)
+		ad, restore := denseOf(a)
+		defer restore()
+		m.Zero()
+		var prod Dense
+		y.doBlocks(func(i, j int, blk Matrix) {
+			r, c := blk.Dims()
+			if trans {
+				blk = blk.T()
+				i, j = j, i
+				r, c = c, r
+			}
+			prod.Reset()
+			prod.Mul(ad.Slice(0, mr, i, i+r), blk)
+			dst := m.Slice(0, mr, j, j+c).(*Dense)
+			dst.Add(dst, &prod)
+		})
+		return true
+	}
+	return false
+}
+
+// mulVecBlock places the product of a and the vector x in the receiver if
+// a is a block matrix, and returns whether it did so. The receiver must have
+// the length of the product and must not share storage with x.
+func (v *VecDense) mulVecBlock(a Matrix, x Vector) bool {
+	blocks, trans, ok := blocksOf(a)
+	if !ok {
+		return false
+	}
+	xd, ok := x.(*VecDense)
+	if !ok {
+		xd = NewVecDense(x.Len(), nil)
+		xd.CopyVec(x)
+	}
+	v.Zero()
+	var prod VecDense
+	blocks.doBlocks(func(i, j int, blk Matrix) {
+		r, c := blk.Dims()
+		if trans {
+			blk = blk.T()
+			i, j = j, i
+			r, c = c, r
+		}
+		prod.Reset()
+		prod.MulVec(blk, xd.SliceVec(j, j+c))
+		dst := v.SliceVec(i, i+r).(*VecDense)
+		dst.AddVec(dst, &prod)
+	})
+	return true
+}
@@ -0,0 +1,187 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randNormDense returns a random r×c matrix with normally distributed elements.
+func randNormDense(r, c int, rnd *rand.Rand) *Dense {
+	m := NewDense(r, c, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = rnd.NormFloat64()
+	}
+	return m
+}
+
+func TestBlockDense(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	b := NewBlockDense([]int{2, 3, 1}, []int{4, 2})
+	if r, c := b.Dims(); r != 6 || c != 6 {
+		t.Fatalf("unexpected dimensions %d×%d", r, c)
+	}
+	if r, c := b.BlockDims(); r != 3 || c != 2 {
+		t.Fatalf("unexpected block dimensions %d×%d", r, c)
+	}
+	b.SetBlock(0, 0, randNormDense(2, 4, rnd))
+	b.SetBlock(1, 1, randNormDense(3, 2, rnd))
+	sparse := NewCOO(1, 4, []int{0}, []int{2}, []float64{5}).ToCSR()
+	b.SetBlock(2, 0, sparse)
+	if b.Block(0, 1) != nil || b.Block(2, 0) != sparse {
+		t.Error("unexpected block")
+	}
+
+	want := NewDense(6, 6, nil)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 4; j++ {
+			want.set(i, j, b.Block(0, 0).At(i, j))
+		}
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 2; j++ {
+			want.set(2+i, 4+j, b.Block(1, 1).At(i, j))
+		}
+	}
+	want.set(5, 2, 5)
+	if !Equal(b, want) {
+		t.Errorf("unexpected elements:\ngot  %v\nwant %v", Formatted(b), Formatted(want))
+	}
+	nz := NewDense(6, 6, nil)
+	var count int
+	b.DoNonZero(func(i, j int, v float64) {
+		nz.set(i, j, v)
+		count++
+	})
+	if !Equal(nz, want) || count != 2*4+3*2+1 {
+		t.Error("unexpected non-zero elements")
+	}
+
+	testBlockMul(t, "BlockDense", b, want, rnd)
+
+	if panicked, _ := panics(func() { b.SetBlock(0, 1, NewDense(2, 3, nil)) }); !panicked {
+		t.Error("expected panic for block of wrong size")
+	}
+	if panicked, _ := panics(func() { NewBlockDense([]int{1, 0}, []int{1}) }); !panicked {
+		t.Error("expected panic for zero block size")
+	}
+}
+
+func TestBlockDiagonal(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	blocks := []Matrix{
+		randNormDense(3, 3, rnd),
+		NewDiagDense(2, []float64{2, -3}),
+		NewTriDense(2, Upper, []float64{1, 2, 0, 4}),
+		randNormDense(1, 1, rnd),
+	}
+	b := NewBlockDiagonal(blocks...)
+	if r, c := b.Dims(); r != 8 || c != 8 || b.Blocks() != 4 {
+		t.Fatalf("unexpected dimensions %d×%d with %d blocks", r, c, b.Blocks())
+	}
+	want := NewDense(8, 8, nil)
+	var off int
+	for _, blk := range blocks {
+		n, _ := blk.Dims()
+		want.Slice(off, off+n, off, off+n).(*Dense).Copy(blk)
+		off += n
+	}
+	if !Equal(b, want) {
+		t.Errorf("unexpected elements:\ngot  %v\nwant %v", Formatted(b), Formatted(want))
+	}
+
+	testBlockMul(t, "BlockDiagonal", b, want, rnd)
+
+	for _, trans := range []bool{false, true} {
+		rhs := randNormDense(8, 3, rnd)
+		var x, got Dense
+		if err := b.SolveTo(&x, trans, rhs); err != nil {
+			t.Errorf("trans=%t: unexpected error: %v", trans, err)
+		}
+		if trans {
+			got.Mul(want.T(), &x)
+		} else {
+			got.Mul(want, &x)
+		}
+		if !EqualApprox(&got, rhs, 1e-12) {
+			t.Errorf("trans=%t: A*X != B", trans)
+		}
+
+		// Dense.Solve uses the block structure.
+		var y Dense
+		var a Matrix = b
+		if trans {
+			a = b.T()
+		}
+		if err := y.Solve(a, rhs); err != nil {
+			t.Errorf("trans=%t: unexpected error: %v", trans, err)
+		}
+		if !EqualApprox(&x, &y, 1e-14) {
+			t.Errorf("trans=%t: Dense.Solve and SolveTo differ", trans)
+		}
+	}
+
+	singular := NewBlockDiagonal(eye(2), NewDense(2, 2, []float64{1, 2, 2, 4}))
+	var x Dense
+	if err := singular.SolveTo(&x, false, randNormDense(4, 1, rnd)); err == nil {
+		t.Error("expected error for singular block")
+	}
+	if panicked, _ := panics(func() { NewBlockDiagonal(NewDense(2, 3, nil)) }); !panicked {
+		t.Error("expected panic for non-square block")
+	}
+	if panicked, _ := panics(func() { NewBlockDiagonal() }); !panicked {
+		t.Error("expected panic for no blocks")
+	}
+}
+
+// testBlockMul checks products with the block matrix b against the products
+// with its dense equivalent.
+func testBlockMul(t *testing.T, name string, b Matrix, want *Dense, rnd *rand.Rand) {
+	r, c := b.Dims()
+	x := randNormDense(c, 3, rnd)
+	y := randNormDense(4, r, rnd)
+	for _, test := range []struct {
+		a, b, wantA, wantB Matrix
+	}{
+		{a: b, b: x, wantA: want, wantB: x},
+		{a: b.T(), b: y.T(), wantA: want.T(), wantB: y.T()},
+		{a: y, b: b, wantA: y, wantB: want},
+		{a: x.T(), b: b.T(), wantA: x.T(), wantB: want.T()},
+		{a: b, b: b.T(), wantA: want, wantB: want.T()},
+	} {
+		var got, prod Dense
+		got.Mul(test.a, test.b)
+		prod.Mul(test.wantA, test.wantB)
+		if !EqualApprox(&got, &prod, 1e-12) {
+			t.Errorf("%s: unexpected product", name)
+		}
+	}
+
+	v := NewVecDense(c, nil)
+	for i := 0; i < c; i++ {
+		v.SetVec(i, rnd.NormFloat64())
+	}
+	var got, prod VecDense
+	got.MulVec(b, v)
+	prod.MulVec(want, v)
+	if !EqualApprox(&got, &prod, 1e-12) {
+		t.Errorf("%s: unexpected vector product", name)
+	}
+	u := NewVecDense(r, nil)
+	for i := 0; i < r; i++ {
+		u.SetVec(i, rnd.NormFloat64())
+	}
+	got.Reset()
+	prod.Reset()
+	got.MulVec(b.T(), u)
+	prod.MulVec(want.T(), u)
+	if !EqualApprox(&got, &prod, 1e-12) {
+		t.Errorf("%s: unexpected transposed vector product", name)
+	}
+}
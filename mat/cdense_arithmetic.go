@@ -0,0 +1,165 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+// Add adds a and b element-wise, placing the result in the receiver. Add
+// will panic if the two matrices do not have the same shape.
+func (m *CDense) Add(a, b CMatrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	a = m.isolated(a)
+	b = m.isolated(b)
+	m.reuseAs(ar, ac)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			m.set(i, j, a.At(i, j)+b.At(i, j))
+		}
+	}
+}
+
+// Sub subtracts the matrix b from a, placing the result in the receiver. Sub
+// will panic if the two matrices do not have the same shape.
+func (m *CDense) Sub(a, b CMatrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	a = m.isolated(a)
+	b = m.isolated(b)
+	m.reuseAs(ar, ac)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			m.set(i, j, a.At(i, j)-b.At(i, j))
+		}
+	}
+}
+
+// Scale multiplies the elements of a by f, placing the result in the receiver.
+func (m *CDense) Scale(f complex128, a CMatrix) {
+	ar, ac := a.Dims()
+	a = m.isolated(a)
+	m.reuseAs(ar, ac)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < ac; j++ {
+			m.set(i, j, f*a.At(i, j))
+		}
+	}
+}
+
+// Mul takes the matrix product of a and b, placing the result in the receiver.
+// If the number of columns in a does not equal the number of rows in b, Mul
+// will panic.
+func (m *CDense) Mul(a, b CMatrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+
+	aU, aConj := unconjugate(a)
+	bU, bConj := unconjugate(b)
+	m.reuseAs(ar, bc)
+	if m == aU || m == bU {
+		// Compute the product into new storage
+		// when the receiver is an operand.
+		var tmp CDense
+		tmp.Mul(a, b)
+		m.Copy(&tmp)
+		return
+	}
+	m.checkOverlapMatrix(aU)
+	m.checkOverlapMatrix(bU)
+
+	if aU, ok := aU.(*CDense); ok {
+		if bU, ok := bU.(*CDense); ok {
+			tA := blas.NoTrans
+			if aConj {
+				tA = blas.ConjTrans
+			}
+			tB := blas.NoTrans
+			if bConj {
+				tB = blas.ConjTrans
+			}
+			cblas128.Gemm(tA, tB, 1, aU.mat, bU.mat, 0, m.mat)
+			return
+		}
+	}
+
+	row := make([]complex128, ac)
+	for i := 0; i < ar; i++ {
+		for l := range row {
+			row[l] = a.At(i, l)
+		}
+		for j := 0; j < bc; j++ {
+			var v complex128
+			for l, e := range row {
+				v += e * b.At(l, j)
+			}
+			m.set(i, j, v)
+		}
+	}
+}
+
+// Solve finds a minimum-norm solution to a system of linear equations defined
+// by the complex matrices A and B. If A is singular or near-singular, a
+// Condition error is returned. See the documentation for Condition for more
+// information.
+//
+// The minimization problem solved depends on the input parameters:
+//
+//	if m >= n, find X such that ||A*X - B||_2 is minimized,
+//	if m < n, find the minimum norm solution of A * X = B.
+//
+// The solution matrix, X, is stored in-place into the receiver.
+func (m *CDense) Solve(a, b CMatrix) error {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br {
+		panic(ErrShape)
+	}
+	m.reuseAs(ac, bc)
+
+	switch {
+	case ar == ac:
+		var lu CLU
+		lu.Factorize(a)
+		return lu.SolveTo(m, false, b)
+	case ar > ac:
+		var qr CQR
+		qr.Factorize(a)
+		return qr.SolveTo(m, false, b)
+	default:
+		var lq CLQ
+		lq.Factorize(a)
+		return lq.SolveTo(m, false, b)
+	}
+}
+
+// isolated returns a copy of a if a is the conjugate transpose of the
+// receiver, so that a is not modified while elements of the result are
+// written, and a otherwise.
+func (m *CDense) isolated(a CMatrix) CMatrix {
+	if aU, conj := unconjugate(a); conj && aU == m {
+		return cdenseCopyOf(a)
+	}
+	return a
+}
+
+// cdenseCopyOf returns a newly allocated copy of the elements of a.
+func cdenseCopyOf(a CMatrix) *CDense {
+	r, c := a.Dims()
+	d := NewCDense(r, c, nil)
+	d.Copy(a)
+	return d
+}
@@ -0,0 +1,101 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+const badCLQ = "mat: invalid complex LQ factorization"
+
+// CLQ is a type for creating and using the LQ factorization of a complex
+// matrix.
+type CLQ struct {
+	// qr is the QR factorization of Aᴴ. With
+	// Aᴴ = Q₁ * R, the LQ factorization is
+	// A = Rᴴ * Q₁ᴴ.
+	qr CQR
+}
+
+// Factorize computes the LQ factorization of an m×n complex matrix a where
+// m <= n. The LQ factorization always exists even if A is singular.
+//
+// The LQ decomposition is a factorization of the matrix A such that A = L * Q.
+// The matrix Q is a unitary n×n matrix, and L is an m×n lower triangular
+// matrix. L and Q can be extracted using the LTo and QTo methods.
+func (lq *CLQ) Factorize(a CMatrix) {
+	m, n := a.Dims()
+	if m > n {
+		panic(ErrShape)
+	}
+	lq.qr.Factorize(a.H())
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (lq *CLQ) isValid() bool {
+	return lq.qr.isValid()
+}
+
+// Cond returns the condition number for the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (lq *CLQ) Cond() float64 {
+	if !lq.isValid() {
+		panic(badCLQ)
+	}
+	return lq.qr.cond
+}
+
+// LTo extracts the m×n lower trapezoidal matrix from a LQ decomposition.
+// If dst is nil, a new matrix is allocated. The resulting L matrix is returned.
+// LTo will panic if the receiver does not contain a factorization.
+func (lq *CLQ) LTo(dst *CDense) *CDense {
+	if !lq.isValid() {
+		panic(badCLQ)
+	}
+	r := lq.qr.RTo(nil)
+	m, n := r.Dims()
+	if dst == nil {
+		dst = NewCDense(n, m, nil)
+	} else {
+		dst.reuseAs(n, m)
+	}
+	dst.Copy(r.H())
+	return dst
+}
+
+// QTo extracts the n×n unitary matrix Q from an LQ decomposition.
+// If dst is nil, a new matrix is allocated. The resulting Q matrix is returned.
+// QTo will panic if the receiver does not contain a factorization.
+func (lq *CLQ) QTo(dst *CDense) *CDense {
+	if !lq.isValid() {
+		panic(badCLQ)
+	}
+	q := lq.qr.QTo(nil)
+	n, _ := q.Dims()
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAs(n, n)
+	}
+	dst.Copy(q.H())
+	return dst
+}
+
+// SolveTo finds a minimum-norm solution to a system of linear equations defined
+// by the matrices A and b, where A is an m×n matrix represented in its LQ factorized
+// form. If A is singular or near-singular a Condition error is returned.
+// See the documentation for Condition for more information.
+//
+// The minimization problem solved depends on the input parameters.
+//
+//	If trans == false, find the minimum norm solution of A * X = B.
+//	If trans == true, find X such that ||Aᴴ*X - B||_2 is minimized.
+//
+// The solution matrix, X, is stored in place into dst.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (lq *CLQ) SolveTo(dst *CDense, trans bool, b CMatrix) error {
+	if !lq.isValid() {
+		panic(badCLQ)
+	}
+	// A = (Aᴴ)ᴴ, so the problems are those of the
+	// QR factorization of Aᴴ with trans inverted.
+	return lq.qr.SolveTo(dst, !trans, b)
+}
@@ -0,0 +1,286 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+const badCLU = "mat: invalid complex LU factorization"
+
+// CLU is a type for creating and using the LU factorization of a complex
+// matrix.
+type CLU struct {
+	lu    *CDense
+	pivot []int
+	cond  float64
+}
+
+// Factorize computes the LU factorization of the square complex matrix a and
+// stores the result. The LU decomposition will complete regardless of the
+// singularity of a.
+//
+// The LU factorization is computed with partial pivoting, and so really the
+// decomposition is a PLU decomposition where P is a permutation matrix. The
+// individual matrix factors can be extracted from the factorization using the
+// Permutation method on Dense with the row swaps returned by Pivot, and the
+// CLU LTo and UTo methods.
+func (lu *CLU) Factorize(a CMatrix) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	lu.lu = cdenseCopyOf(a)
+	anorm := cNormInf(lu.lu)
+	lu.pivot = make([]int, r)
+
+	m := lu.lu.mat
+	for j := 0; j < r; j++ {
+		p := j
+		pmax := cmplx.Abs(m.Data[j*m.Stride+j])
+		for i := j + 1; i < r; i++ {
+			if v := cmplx.Abs(m.Data[i*m.Stride+j]); v > pmax {
+				p, pmax = i, v
+			}
+		}
+		lu.pivot[j] = p
+		if p != j {
+			cblas128.Swap(r,
+				cblas128.Vector{Inc: 1, Data: m.Data[j*m.Stride:]},
+				cblas128.Vector{Inc: 1, Data: m.Data[p*m.Stride:]},
+			)
+		}
+		d := m.Data[j*m.Stride+j]
+		if d == 0 {
+			continue
+		}
+		row := m.Data[j*m.Stride+j+1 : j*m.Stride+r]
+		for i := j + 1; i < r; i++ {
+			l := m.Data[i*m.Stride+j] / d
+			m.Data[i*m.Stride+j] = l
+			if l == 0 {
+				continue
+			}
+			dst := m.Data[i*m.Stride+j+1 : i*m.Stride+r]
+			for k, v := range row {
+				dst[k] -= l * v
+			}
+		}
+	}
+
+	lu.cond = math.Inf(1)
+	if cNonSingular(lu.upper()) {
+		inv := NewCDense(r, r, nil)
+		for i := 0; i < r; i++ {
+			inv.set(i, i, 1)
+		}
+		lu.solve(inv, false)
+		lu.cond = anorm * cNormInf(inv)
+	}
+}
+
+// upper returns the upper triangular factor U held by the receiver.
+func (lu *CLU) upper() cblas128.Triangular {
+	return cblas128.Triangular{
+		N:      lu.lu.mat.Rows,
+		Stride: lu.lu.mat.Stride,
+		Data:   lu.lu.mat.Data,
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+	}
+}
+
+// solve computes A⁻¹*b, or A⁻ᴴ*b if trans is true, in place.
+func (lu *CLU) solve(b *CDense, trans bool) {
+	u := lu.upper()
+	l := u
+	l.Uplo = blas.Lower
+	l.Diag = blas.Unit
+	swap := func(i int) {
+		if p := lu.pivot[i]; p != i {
+			cblas128.Swap(b.mat.Cols,
+				cblas128.Vector{Inc: 1, Data: b.mat.Data[i*b.mat.Stride:]},
+				cblas128.Vector{Inc: 1, Data: b.mat.Data[p*b.mat.Stride:]},
+			)
+		}
+	}
+	if trans {
+		// Aᴴ = Uᴴ * Lᴴ * Pᵀ.
+		cblas128.Trsm(blas.Left, blas.ConjTrans, 1, u, b.mat)
+		cblas128.Trsm(blas.Left, blas.ConjTrans, 1, l, b.mat)
+		for i := len(lu.pivot) - 1; i >= 0; i-- {
+			swap(i)
+		}
+		return
+	}
+	for i := range lu.pivot {
+		swap(i)
+	}
+	cblas128.Trsm(blas.Left, blas.NoTrans, 1, l, b.mat)
+	cblas128.Trsm(blas.Left, blas.NoTrans, 1, u, b.mat)
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (lu *CLU) isValid() bool {
+	return lu.lu != nil && !lu.lu.IsZero()
+}
+
+// Cond returns the condition number for the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (lu *CLU) Cond() float64 {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	return lu.cond
+}
+
+// Det returns the determinant of the matrix that has been factorized. In many
+// expressions, using LogDet will be more numerically stable.
+// Det will panic if the receiver does not contain a factorization.
+func (lu *CLU) Det() complex128 {
+	det, phase := lu.LogDet()
+	return complex(math.Exp(det), 0) * phase
+}
+
+// LogDet returns the log of the absolute value of the determinant and the
+// phase of the determinant, a complex number of unit modulus, for the matrix
+// that has been factorized. If the determinant is zero, the returned phase is
+// zero.
+// LogDet will panic if the receiver does not contain a factorization.
+func (lu *CLU) LogDet() (det float64, phase complex128) {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	phase = 1
+	for i, p := range lu.pivot {
+		v := lu.lu.at(i, i)
+		if v == 0 {
+			return math.Inf(-1), 0
+		}
+		abs := cmplx.Abs(v)
+		phase *= v / complex(abs, 0)
+		if p != i {
+			phase = -phase
+		}
+		det += math.Log(abs)
+	}
+	return det, phase
+}
+
+// Pivot returns pivot indices that enable the construction of the permutation
+// matrix P (see Dense.Permutation). If swaps == nil, then new memory will be
+// allocated, otherwise the length of the input must be equal to the size of the
+// factorized matrix.
+// Pivot will panic if the receiver does not contain a factorization.
+func (lu *CLU) Pivot(swaps []int) []int {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	n := len(lu.pivot)
+	if swaps == nil {
+		swaps = make([]int, n)
+	}
+	if len(swaps) != n {
+		panic(badSliceLength)
+	}
+	for i := range swaps {
+		swaps[i] = i
+	}
+	for i := n - 1; i >= 0; i-- {
+		v := lu.pivot[i]
+		swaps[i], swaps[v] = swaps[v], swaps[i]
+	}
+	return swaps
+}
+
+// LTo extracts the unit lower triangular matrix from an LU factorization.
+// If dst is nil, a new matrix is allocated. The resulting L matrix is returned.
+// LTo will panic if the receiver does not contain a factorization.
+func (lu *CLU) LTo(dst *CDense) *CDense {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	n := len(lu.pivot)
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAsZeroed(n, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			dst.set(i, j, lu.lu.at(i, j))
+		}
+		dst.set(i, i, 1)
+		for j := i + 1; j < n; j++ {
+			dst.set(i, j, 0)
+		}
+	}
+	return dst
+}
+
+// UTo extracts the upper triangular matrix from an LU factorization.
+// If dst is nil, a new matrix is allocated. The resulting U matrix is returned.
+// UTo will panic if the receiver does not contain a factorization.
+func (lu *CLU) UTo(dst *CDense) *CDense {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	n := len(lu.pivot)
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAsZeroed(n, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			dst.set(i, j, 0)
+		}
+		for j := i; j < n; j++ {
+			dst.set(i, j, lu.lu.at(i, j))
+		}
+	}
+	return dst
+}
+
+// SolveTo solves a system of linear equations using the LU decomposition of a
+// complex matrix. It computes
+//
+//	A * X = B if trans == false
+//	Aᴴ * X = B if trans == true
+//
+// In both cases, A is represented in LU factorized form, and the matrix X is
+// stored into dst.
+//
+// If A is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (lu *CLU) SolveTo(dst *CDense, trans bool, b CMatrix) error {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	n := len(lu.pivot)
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	if math.IsInf(lu.cond, 1) {
+		return Condition(math.Inf(1))
+	}
+	dst.reuseAs(n, bc)
+
+	// The solution is computed in new storage so
+	// that b may alias dst.
+	w := cdenseCopyOf(b)
+	lu.solve(w, trans)
+	dst.Copy(w)
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCLU(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10, 30} {
+		a := randCDense(n, n, rnd)
+		var lu CLU
+		lu.Factorize(a)
+
+		var p Dense
+		p.Permutation(n, lu.Pivot(nil))
+		pc := NewCDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				pc.set(i, j, complex(p.At(i, j), 0))
+			}
+		}
+		var lu2, plu CDense
+		lu2.Mul(lu.LTo(nil), lu.UTo(nil))
+		plu.Mul(pc, &lu2)
+		if !CEqualApprox(&plu, a, 1e-12) {
+			t.Errorf("n=%d: P*L*U != A", n)
+		}
+
+		// The determinant is invariant under unitary similarity.
+		var eg CSchur
+		if !eg.Factorize(a, false) {
+			t.Fatalf("n=%d: unexpected Schur failure", n)
+		}
+		want := complex(1, 0)
+		for _, v := range eg.Values(nil) {
+			want *= v
+		}
+		if det := lu.Det(); cmplx.Abs(det-want) > 1e-10*cmplx.Abs(want) {
+			t.Errorf("n=%d: unexpected determinant: got %v want %v", n, det, want)
+		}
+
+		for _, trans := range []bool{false, true} {
+			b := randCDense(n, 3, rnd)
+			var x, ax CDense
+			if err := lu.SolveTo(&x, trans, b); err != nil {
+				t.Errorf("n=%d trans=%t: unexpected error: %v", n, trans, err)
+			}
+			if trans {
+				ax.Mul(a.H(), &x)
+			} else {
+				ax.Mul(a, &x)
+			}
+			if !CEqualApprox(&ax, b, 1e-10) {
+				t.Errorf("n=%d trans=%t: A*X != B", n, trans)
+			}
+		}
+	}
+
+	singular := NewCDense(2, 2, []complex128{1, 1i, 1i, -1})
+	var lu CLU
+	lu.Factorize(singular)
+	if !math.IsInf(lu.Cond(), 1) {
+		t.Errorf("unexpected condition number for singular matrix: %v", lu.Cond())
+	}
+	if err := lu.SolveTo(&CDense{}, false, NewCDense(2, 1, nil)); err == nil {
+		t.Error("expected error for singular matrix")
+	}
+	if panicked, _ := panics(func() { new(CLU).Factorize(NewCDense(2, 3, nil)) }); !panicked {
+		t.Error("expected panic for non-square matrix")
+	}
+}
+
+func TestCDenseSolve(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n int }{
+		{4, 4}, {8, 3}, {3, 8},
+	} {
+		a := randCDense(test.m, test.n, rnd)
+		b := randCDense(test.m, 2, rnd)
+		var x CDense
+		if err := x.Solve(a, b); err != nil {
+			t.Errorf("%d×%d: unexpected error: %v", test.m, test.n, err)
+		}
+		if r, c := x.Dims(); r != test.n || c != 2 {
+			t.Errorf("%d×%d: unexpected solution shape %d×%d", test.m, test.n, r, c)
+		}
+		var ax, ahr CDense
+		ax.Mul(a, &x)
+		ax.Sub(b, &ax)
+		ahr.Mul(a.H(), &ax)
+		if !CEqualApprox(&ahr, NewCDense(test.n, 2, nil), 1e-11) {
+			t.Errorf("%d×%d: solution does not satisfy the normal equations", test.m, test.n)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+const badCQR = "mat: invalid complex QR factorization"
+
+// CQR is a type for creating and using the QR factorization of a complex
+// matrix.
+type CQR struct {
+	qr   *CDense
+	tau  []complex128
+	cond float64
+}
+
+// Factorize computes the QR factorization of an m×n complex matrix a where
+// m >= n. The QR factorization always exists even if A is singular.
+//
+// The QR decomposition is a factorization of the matrix A such that A = Q * R.
+// The matrix Q is a unitary m×m matrix, and R is an m×n upper triangular
+// matrix. Q and R can be extracted using the QTo and RTo methods.
+func (qr *CQR) Factorize(a CMatrix) {
+	m, n := a.Dims()
+	if m < n {
+		panic(ErrShape)
+	}
+	qr.qr = cdenseCopyOf(a)
+	qr.tau = make([]complex128, n)
+	for j := 0; j < n; j++ {
+		qr.tau[j] = cReflector(qr.qr, j, j)
+		cReflectLeft(cmplx.Conj(qr.tau[j]), qr.reflector(j), qr.qr, j, j+1)
+	}
+	qr.cond = cTriCond(qr.r(), blas.Upper)
+}
+
+// reflector returns the vector v of the j-th elementary reflector
+// H_j = I - τ_j * v * vᴴ.
+func (qr *CQR) reflector(j int) []complex128 {
+	return columnReflector(qr.qr, j, j)
+}
+
+// r returns the n×n upper triangle R held by the receiver.
+func (qr *CQR) r() cblas128.Triangular {
+	return cblas128.Triangular{
+		N:      qr.qr.mat.Cols,
+		Stride: qr.qr.mat.Stride,
+		Data:   qr.qr.mat.Data,
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+	}
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (qr *CQR) isValid() bool {
+	return qr.qr != nil && !qr.qr.IsZero()
+}
+
+// Cond returns the condition number for the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (qr *CQR) Cond() float64 {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	return qr.cond
+}
+
+// RTo extracts the m×n upper trapezoidal matrix from a QR decomposition.
+// If dst is nil, a new matrix is allocated. The resulting dst matrix is returned.
+// RTo will panic if the receiver does not contain a factorization.
+func (qr *CQR) RTo(dst *CDense) *CDense {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	r, c := qr.qr.Dims()
+	if dst == nil {
+		dst = NewCDense(r, c, nil)
+	} else {
+		dst.reuseAsZeroed(r, c)
+	}
+	for i := 0; i < c; i++ {
+		for j := i; j < c; j++ {
+			dst.set(i, j, qr.qr.at(i, j))
+		}
+		for j := 0; j < i; j++ {
+			dst.set(i, j, 0)
+		}
+	}
+	for i := c; i < r; i++ {
+		zeroC(dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+c])
+	}
+	return dst
+}
+
+// QTo extracts the m×m unitary matrix Q from a QR decomposition.
+// If dst is nil, a new matrix is allocated. The resulting Q matrix is returned.
+// QTo will panic if the receiver does not contain a factorization.
+func (qr *CQR) QTo(dst *CDense) *CDense {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	r, _ := qr.qr.Dims()
+	if dst == nil {
+		dst = NewCDense(r, r, nil)
+	} else {
+		dst.reuseAsZeroed(r, r)
+	}
+	for i := 0; i < r; i++ {
+		dst.set(i, i, 1)
+	}
+	qr.applyQ(dst, false)
+	return dst
+}
+
+// applyQ computes Q*c, or Qᴴ*c if conj is true, in place.
+func (qr *CQR) applyQ(c *CDense, conj bool) {
+	n := len(qr.tau)
+	if conj {
+		for j := 0; j < n; j++ {
+			cReflectLeft(cmplx.Conj(qr.tau[j]), qr.reflector(j), c, j, 0)
+		}
+		return
+	}
+	for j := n - 1; j >= 0; j-- {
+		cReflectLeft(qr.tau[j], qr.reflector(j), c, j, 0)
+	}
+}
+
+// SolveTo finds a minimum-norm solution to a system of linear equations defined
+// by the matrices A and b, where A is an m×n matrix represented in its QR factorized
+// form. If A is singular or near-singular a Condition error is returned.
+// See the documentation for Condition for more information.
+//
+// The minimization problem solved depends on the input parameters.
+//
+//	If trans == false, find X such that ||A*X - B||_2 is minimized.
+//	If trans == true, find the minimum norm solution of Aᴴ * X = B.
+//
+// The solution matrix, X, is stored in place into dst.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (qr *CQR) SolveTo(dst *CDense, trans bool, b CMatrix) error {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	r, c := qr.qr.Dims()
+	br, bc := b.Dims()
+	if trans {
+		if c != br {
+			panic(ErrShape)
+		}
+		dst.reuseAs(r, bc)
+	} else {
+		if r != br {
+			panic(ErrShape)
+		}
+		dst.reuseAs(c, bc)
+	}
+
+	// The solution is computed in place in new storage
+	// large enough to hold both b and x.
+	w := NewCDense(r, bc, nil)
+	w.Copy(b)
+	t := qr.r()
+	top := cblas128.General{Rows: c, Cols: bc, Stride: w.mat.Stride, Data: w.mat.Data}
+	if trans {
+		if !cNonSingular(t) {
+			return Condition(math.Inf(1))
+		}
+		cblas128.Trsm(blas.Left, blas.ConjTrans, 1, t, top)
+		for i := c; i < r; i++ {
+			zeroC(w.mat.Data[i*w.mat.Stride : i*w.mat.Stride+bc])
+		}
+		qr.applyQ(w, false)
+	} else {
+		qr.applyQ(w, true)
+		if !cNonSingular(t) {
+			return Condition(math.Inf(1))
+		}
+		cblas128.Trsm(blas.Left, blas.NoTrans, 1, t, top)
+	}
+	dst.Copy(w)
+	if qr.cond > ConditionTolerance {
+		return Condition(qr.cond)
+	}
+	return nil
+}
+
+// cReflector computes the elementary reflector H = I - τ*v*vᴴ such that
+//
+//	Hᴴ * x = β*e_1
+//
+// with β real, where x is column j of a from row i. β is stored in place of
+// x_0 and the elements of v after v_0 = 1 are stored in place of the rest of
+// x. cReflector returns τ.
+func cReflector(a *CDense, i, j int) (tau complex128) {
+	m := a.mat.Rows
+	alpha := a.at(i, j)
+	var xnorm float64
+	for r := i + 1; r < m; r++ {
+		xnorm = math.Hypot(xnorm, cmplx.Abs(a.at(r, j)))
+	}
+	if xnorm == 0 && imag(alpha) == 0 {
+		return 0
+	}
+	beta := -math.Copysign(math.Hypot(cmplx.Abs(alpha), xnorm), real(alpha))
+	tau = complex((beta-real(alpha))/beta, -imag(alpha)/beta)
+	scale := 1 / (alpha - complex(beta, 0))
+	for r := i + 1; r < m; r++ {
+		a.set(r, j, a.at(r, j)*scale)
+	}
+	a.set(i, j, complex(beta, 0))
+	return tau
+}
+
+// columnReflector returns the vector v of the elementary reflector stored
+// in column j of a from row i by cReflector.
+func columnReflector(a *CDense, i, j int) []complex128 {
+	v := make([]complex128, a.mat.Rows-i)
+	v[0] = 1
+	for r := 1; r < len(v); r++ {
+		v[r] = a.at(i+r, j)
+	}
+	return v
+}
+
+// cReflectLeft computes C = (I - τ*v*vᴴ) * C for the rows r0:r0+len(v) and
+// the columns from c0 of c.
+func cReflectLeft(tau complex128, v []complex128, c *CDense, r0, c0 int) {
+	if tau == 0 {
+		return
+	}
+	for j := c0; j < c.mat.Cols; j++ {
+		var w complex128
+		for k, vk := range v {
+			w += cmplx.Conj(vk) * c.at(r0+k, j)
+		}
+		w *= tau
+		for k, vk := range v {
+			c.set(r0+k, j, c.at(r0+k, j)-vk*w)
+		}
+	}
+}
+
+// cReflectRight computes C = C * (I - τ*v*vᴴ) for the columns c0:c0+len(v)
+// and the rows from r0 of c.
+func cReflectRight(tau complex128, v []complex128, c *CDense, r0, c0 int) {
+	if tau == 0 {
+		return
+	}
+	for i := r0; i < c.mat.Rows; i++ {
+		var w complex128
+		for k, vk := range v {
+			w += c.at(i, c0+k) * vk
+		}
+		w *= tau
+		for k, vk := range v {
+			c.set(i, c0+k, c.at(i, c0+k)-w*cmplx.Conj(vk))
+		}
+	}
+}
+
+// cNonSingular returns whether the triangular matrix t has no zero diagonal
+// elements.
+func cNonSingular(t cblas128.Triangular) bool {
+	for i := 0; i < t.N; i++ {
+		if t.Data[i*t.Stride+i] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// cTriCond returns the condition number of the triangular matrix t in the
+// CondNorm norm, computed from its explicit inverse. The uplo argument
+// specifies the triangle of t that is used.
+func cTriCond(t cblas128.Triangular, uplo blas.Uplo) float64 {
+	t.Uplo = uplo
+	if !cNonSingular(t) {
+		return math.Inf(1)
+	}
+	n := t.N
+	inv := NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		inv.set(i, i, 1)
+	}
+	cblas128.Trsm(blas.Left, blas.NoTrans, 1, t, inv.mat)
+	var tnorm float64
+	for i := 0; i < n; i++ {
+		lo, hi := i, n
+		if uplo == blas.Lower {
+			lo, hi = 0, i+1
+		}
+		var s float64
+		for j := lo; j < hi; j++ {
+			s += cmplx.Abs(t.Data[i*t.Stride+j])
+		}
+		tnorm = math.Max(tnorm, s)
+	}
+	return tnorm * cNormInf(inv)
+}
+
+// cNormInf returns the maximum absolute row sum of a.
+func cNormInf(a *CDense) float64 {
+	var norm float64
+	for i := 0; i < a.mat.Rows; i++ {
+		var s float64
+		for _, v := range a.mat.Data[i*a.mat.Stride : i*a.mat.Stride+a.mat.Cols] {
+			s += cmplx.Abs(v)
+		}
+		norm = math.Max(norm, s)
+	}
+	return norm
+}
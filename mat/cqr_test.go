@@ -0,0 +1,148 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randCDense returns a random r×c complex matrix with normally distributed
+// real and imaginary parts.
+func randCDense(r, c int, rnd *rand.Rand) *CDense {
+	m := NewCDense(r, c, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+	}
+	return m
+}
+
+// isUnitary returns whether the square complex matrix q satisfies
+// Qᴴ*Q = I within tol.
+func isUnitary(q *CDense, tol float64) bool {
+	n, _ := q.Dims()
+	var qhq CDense
+	qhq.Mul(q.H(), q)
+	eye := NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		eye.set(i, i, 1)
+	}
+	return CEqualApprox(&qhq, eye, tol)
+}
+
+func TestCQR(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n int }{
+		{1, 1}, {3, 3}, {5, 3}, {10, 10}, {20, 7},
+	} {
+		name := fmt.Sprintf("%d×%d", test.m, test.n)
+		a := randCDense(test.m, test.n, rnd)
+		var qr CQR
+		qr.Factorize(a)
+		q := qr.QTo(nil)
+		r := qr.RTo(nil)
+		if !isUnitary(q, 1e-13) {
+			t.Errorf("%s: Q not unitary", name)
+		}
+		for i := 0; i < test.m; i++ {
+			for j := 0; j < i && j < test.n; j++ {
+				if r.At(i, j) != 0 {
+					t.Errorf("%s: R not upper triangular", name)
+				}
+			}
+		}
+		var qra CDense
+		qra.Mul(q, r)
+		if !CEqualApprox(&qra, a, 1e-13) {
+			t.Errorf("%s: Q*R != A", name)
+		}
+
+		// The least squares solution has a residual
+		// orthogonal to the range of A.
+		b := randCDense(test.m, 2, rnd)
+		var x, res, ahr CDense
+		if err := qr.SolveTo(&x, false, b); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		res.Mul(a, &x)
+		res.Sub(b, &res)
+		ahr.Mul(a.H(), &res)
+		if !CEqualApprox(&ahr, NewCDense(test.n, 2, nil), 1e-12) {
+			t.Errorf("%s: residual not orthogonal to range of A", name)
+		}
+
+		// The minimum norm solution of Aᴴ*X = B
+		// solves the system and lies in the range of A.
+		b = randCDense(test.n, 2, rnd)
+		x.Reset()
+		if err := qr.SolveTo(&x, true, b); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		var ahx CDense
+		ahx.Mul(a.H(), &x)
+		if !CEqualApprox(&ahx, b, 1e-12) {
+			t.Errorf("%s: Aᴴ*X != B", name)
+		}
+		var qhx CDense
+		qhx.Mul(q.H(), &x)
+		for i := test.n; i < test.m; i++ {
+			for j := 0; j < 2; j++ {
+				if v := qhx.At(i, j); real(v)*real(v)+imag(v)*imag(v) > 1e-24 {
+					t.Errorf("%s: minimum norm solution not in range of A", name)
+				}
+			}
+		}
+	}
+}
+
+func TestCLQ(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct{ m, n int }{
+		{1, 1}, {3, 3}, {3, 5}, {7, 20},
+	} {
+		name := fmt.Sprintf("%d×%d", test.m, test.n)
+		a := randCDense(test.m, test.n, rnd)
+		var lq CLQ
+		lq.Factorize(a)
+		l := lq.LTo(nil)
+		q := lq.QTo(nil)
+		if !isUnitary(q, 1e-13) {
+			t.Errorf("%s: Q not unitary", name)
+		}
+		for i := 0; i < test.m; i++ {
+			for j := i + 1; j < test.n; j++ {
+				if l.At(i, j) != 0 {
+					t.Errorf("%s: L not lower triangular", name)
+				}
+			}
+		}
+		var lqa CDense
+		lqa.Mul(l, q)
+		if !CEqualApprox(&lqa, a, 1e-13) {
+			t.Errorf("%s: L*Q != A", name)
+		}
+
+		b := randCDense(test.m, 3, rnd)
+		var x, ax CDense
+		if err := lq.SolveTo(&x, false, b); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		ax.Mul(a, &x)
+		if !CEqualApprox(&ax, b, 1e-12) {
+			t.Errorf("%s: A*X != B", name)
+		}
+	}
+
+	if panicked, _ := panics(func() { new(CLQ).Factorize(NewCDense(3, 2, nil)) }); !panicked {
+		t.Error("expected panic for tall matrix")
+	}
+	if panicked, _ := panics(func() { new(CQR).Factorize(NewCDense(2, 3, nil)) }); !panicked {
+		t.Error("expected panic for wide matrix")
+	}
+}
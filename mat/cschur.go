@@ -0,0 +1,262 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const badCSchur = "mat: invalid complex Schur factorization"
+
+// CSchur is a type for creating and using the Schur factorization of a
+// complex matrix.
+type CSchur struct {
+	t *CDense
+	z *CDense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (s *CSchur) succFact() bool {
+	return s.t != nil
+}
+
+// Factorize computes the Schur factorization of the square complex matrix a
+//
+//	A = Z * T * Zᴴ
+//
+// where Z is unitary and T is upper triangular with the eigenvalues of A on
+// its diagonal. The Schur vectors, the columns of Z, are only computed if
+// vectors is true.
+//
+// The factorization is computed by reduction to upper Hessenberg form
+// followed by single-shift complex QR iterations. Factorize returns whether
+// the iterations converged. If the factorization failed, routines that require
+// a successful factorization will panic.
+func (s *CSchur) Factorize(a CMatrix, vectors bool) (ok bool) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	s.t = nil
+	s.z = nil
+
+	n := r
+	t := cdenseCopyOf(a)
+	var z *CDense
+	if vectors {
+		z = NewCDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			z.set(i, i, 1)
+		}
+	}
+
+	// Reduce A to upper Hessenberg form H = Qᴴ * A * Q.
+	for j := 0; j < n-2; j++ {
+		tau := cReflector(t, j+1, j)
+		v := columnReflector(t, j+1, j)
+		cReflectLeft(cmplx.Conj(tau), v, t, j+1, j+1)
+		cReflectRight(tau, v, t, 0, j+1)
+		if vectors {
+			cReflectRight(tau, v, z, 0, j+1)
+		}
+		for i := j + 2; i < n; i++ {
+			t.set(i, j, 0)
+		}
+	}
+
+	if !cHessenbergQR(t, z) {
+		return false
+	}
+	s.t = t
+	s.z = z
+	return true
+}
+
+// cHessenbergQR reduces the upper Hessenberg matrix h to upper triangular
+// form by single-shift QR iterations. If z is not nil, the applied unitary
+// transformations are accumulated into z from the right. cHessenbergQR
+// returns whether the iterations converged.
+func cHessenbergQR(h, z *CDense) (ok bool) {
+	const maxIter = 30
+
+	n := h.mat.Rows
+	anorm := cNormInf(h)
+	iter := 0
+	for hi := n - 1; hi > 0; {
+		// Look for a single small subdiagonal element
+		// to split the active block [l, hi].
+		l := hi
+		for ; l > 0; l-- {
+			tst := cmplx.Abs(h.at(l-1, l-1)) + cmplx.Abs(h.at(l, l))
+			if tst == 0 {
+				tst = anorm
+			}
+			if cmplx.Abs(h.at(l, l-1)) <= machEps*tst {
+				h.set(l, l-1, 0)
+				break
+			}
+		}
+		if l == hi {
+			// h[hi, hi] has converged.
+			hi--
+			iter = 0
+			continue
+		}
+		iter++
+		if iter > maxIter*(hi-l+1) {
+			return false
+		}
+
+		var mu complex128
+		if iter%10 == 0 {
+			// Use an exceptional shift to break cycles.
+			mu = h.at(hi, hi) + complex(0.75*math.Abs(real(h.at(hi, hi-1))), 0)
+		} else {
+			// Use the eigenvalue of the trailing 2×2 block
+			// closest to h[hi, hi] as the Wilkinson shift.
+			a, b := h.at(hi-1, hi-1), h.at(hi-1, hi)
+			c, d := h.at(hi, hi-1), h.at(hi, hi)
+			half := (a - d) / 2
+			disc := cmplx.Sqrt(half*half + b*c)
+			mu = d + half - disc
+			if mu2 := d + half + disc; cmplx.Abs(mu2-d) < cmplx.Abs(mu-d) {
+				mu = mu2
+			}
+		}
+
+		// Chase the bulge introduced by the shift down
+		// the subdiagonal with Givens rotations.
+		x := h.at(l, l) - mu
+		y := h.at(l+1, l)
+		for k := l; k < hi; k++ {
+			if k > l {
+				x = h.at(k, k-1)
+				y = h.at(k+1, k-1)
+			}
+			c, s := cGivens(x, y)
+			c0 := k - 1
+			if k == l {
+				c0 = l
+			}
+			cGivensLeft(h, k, c0, c, s)
+			if k > l {
+				h.set(k+1, k-1, 0)
+			}
+			last := k + 2
+			if last > hi {
+				last = hi
+			}
+			cGivensRight(h, k, last+1, c, s)
+			if z != nil {
+				cGivensRight(z, k, n, c, s)
+			}
+		}
+	}
+	return true
+}
+
+// cGivens returns c and s of the complex Givens rotation
+//
+//	[  c   s ] [ x ]   [ r ]
+//	[ -s̄   c ] [ y ] = [ 0 ]
+//
+// with c real.
+func cGivens(x, y complex128) (c float64, s complex128) {
+	if y == 0 {
+		return 1, 0
+	}
+	if x == 0 {
+		return 0, 1
+	}
+	ax := cmplx.Abs(x)
+	norm := math.Hypot(ax, cmplx.Abs(y))
+	return ax / norm, x / complex(ax, 0) * cmplx.Conj(y) / complex(norm, 0)
+}
+
+// cGivensLeft applies the Givens rotation defined by c and s from the left to
+// the rows k and k+1 of x from column c0.
+func cGivensLeft(x *CDense, k, c0 int, c float64, s complex128) {
+	for j := c0; j < x.mat.Cols; j++ {
+		u, w := x.at(k, j), x.at(k+1, j)
+		x.set(k, j, complex(c, 0)*u+s*w)
+		x.set(k+1, j, -cmplx.Conj(s)*u+complex(c, 0)*w)
+	}
+}
+
+// cGivensRight applies the conjugate transpose of the Givens rotation defined
+// by c and s from the right to the columns k and k+1 of the first rows rows of
+// x.
+func cGivensRight(x *CDense, k, rows int, c float64, s complex128) {
+	for i := 0; i < rows; i++ {
+		u, w := x.at(i, k), x.at(i, k+1)
+		x.set(i, k, complex(c, 0)*u+cmplx.Conj(s)*w)
+		x.set(i, k+1, -s*u+complex(c, 0)*w)
+	}
+}
+
+// Values returns the eigenvalues of the factorized matrix, the diagonal
+// elements of T, in the order in which they appear in the Schur form.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length n, and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (s *CSchur) Values(v []complex128) []complex128 {
+	if !s.succFact() {
+		panic(badCSchur)
+	}
+	n := s.t.mat.Rows
+	if v == nil {
+		v = make([]complex128, n)
+	}
+	if len(v) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	for i := range v {
+		v[i] = s.t.at(i, i)
+	}
+	return v
+}
+
+// TTo extracts the upper triangular Schur form T from the factorization.
+// If dst is nil, a new matrix is allocated. The resulting T matrix is returned.
+// TTo will panic if the receiver does not contain a successful factorization.
+func (s *CSchur) TTo(dst *CDense) *CDense {
+	if !s.succFact() {
+		panic(badCSchur)
+	}
+	n := s.t.mat.Rows
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAs(n, n)
+	}
+	dst.Copy(s.t)
+	return dst
+}
+
+// ZTo extracts the unitary matrix of Schur vectors Z from the factorization.
+// If dst is nil, a new matrix is allocated. The resulting Z matrix is returned.
+// ZTo will panic if the receiver does not contain a successful factorization
+// or if the Schur vectors were not computed.
+func (s *CSchur) ZTo(dst *CDense) *CDense {
+	if !s.succFact() {
+		panic(badCSchur)
+	}
+	if s.z == nil {
+		panic("mat: Schur vectors not computed")
+	}
+	n := s.z.mat.Rows
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAs(n, n)
+	}
+	dst.Copy(s.z)
+	return dst
+}
@@ -0,0 +1,110 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCSchur(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	jordan := NewCDense(4, 4, []complex128{
+		2, 1, 0, 0,
+		0, 2, 1, 0,
+		0, 0, 2, 0,
+		0, 0, 0, 1i,
+	})
+	for _, test := range []struct {
+		name string
+		a    *CDense
+	}{
+		{name: "1×1", a: NewCDense(1, 1, []complex128{3 - 1i})},
+		{name: "2×2", a: randCDense(2, 2, rnd)},
+		{name: "random", a: randCDense(10, 10, rnd)},
+		{name: "large", a: randCDense(40, 40, rnd)},
+		{name: "jordan", a: jordan},
+		{name: "zero", a: NewCDense(3, 3, nil)},
+	} {
+		n, _ := test.a.Dims()
+		var s CSchur
+		if !s.Factorize(test.a, true) {
+			t.Errorf("%s: unexpected factorization failure", test.name)
+			continue
+		}
+		tm := s.TTo(nil)
+		z := s.ZTo(nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < i; j++ {
+				if tm.At(i, j) != 0 {
+					t.Errorf("%s: T not upper triangular", test.name)
+				}
+			}
+		}
+		if !isUnitary(z, 1e-12) {
+			t.Errorf("%s: Z not unitary", test.name)
+		}
+		var zt, ztz CDense
+		zt.Mul(z, tm)
+		ztz.Mul(&zt, z.H())
+		if !CEqualApprox(&ztz, test.a, 1e-11) {
+			t.Errorf("%s: Z*T*Zᴴ != A", test.name)
+		}
+
+		var noVec CSchur
+		noVec.Factorize(test.a, false)
+		got := noVec.Values(nil)
+		want := s.Values(nil)
+		if !CEqualApprox(NewCDense(1, n, got), NewCDense(1, n, want), 1e-12) {
+			t.Errorf("%s: eigenvalues depend on vectors", test.name)
+		}
+	}
+
+	// The eigenvalues of a real matrix agree with Eigen.
+	a := NewDense(12, 12, nil)
+	for i := range a.mat.Data {
+		a.mat.Data[i] = rnd.NormFloat64()
+	}
+	ac := NewCDense(12, 12, nil)
+	for i, v := range a.mat.Data {
+		ac.mat.Data[i] = complex(v, 0)
+	}
+	var eg Eigen
+	eg.Factorize(a, EigenNone)
+	var s CSchur
+	s.Factorize(ac, false)
+	want := eg.Values(nil)
+	got := s.Values(nil)
+	used := make([]bool, len(got))
+	for _, w := range want {
+		best := -1
+		for i, g := range got {
+			if !used[i] && (best < 0 || cmplx.Abs(g-w) < cmplx.Abs(got[best]-w)) {
+				best = i
+			}
+		}
+		used[best] = true
+		if cmplx.Abs(got[best]-w) > 1e-10 {
+			t.Errorf("unexpected eigenvalues of real matrix:\ngot  %v\nwant %v", got, want)
+			break
+		}
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "non-square", fn: func() { new(CSchur).Factorize(NewCDense(2, 3, nil), false) }},
+		{name: "unfactorized", fn: func() { new(CSchur).TTo(nil) }},
+		{name: "vectors", fn: func() { s.ZTo(nil) }},
+	} {
+		if panicked, _ := panics(test.fn); !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// CSVD is a type for creating and using the Singular Value Decomposition (SVD)
+// of a complex matrix.
+type CSVD struct {
+	kind SVDKind
+
+	s []float64
+	u *CDense
+	v *CDense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (svd *CSVD) succFact() bool {
+	return len(svd.s) != 0
+}
+
+// Factorize computes the singular value decomposition (SVD) of the complex
+// input matrix A. The singular values of A are computed in all cases, while
+// the singular vectors are optionally computed depending on the input kind.
+//
+// The full singular value decomposition (kind == SVDFull) is a factorization
+// of an m×n matrix A of the form
+//
+//	A = U * Σ * Vᴴ
+//
+// where Σ is an m×n diagonal matrix, U is an m×m unitary matrix, and V is an
+// n×n unitary matrix. The diagonal elements of Σ are the singular values of A.
+// The first min(m,n) columns of U and V are, respectively, the left and right
+// singular vectors of A.
+//
+// The thin SVD (kind == SVDThin) finds
+//
+//	A = U~ * Σ * V~ᴴ
+//
+// where U~ is of size m×min(m,n), Σ is a diagonal matrix of size min(m,n)×min(m,n)
+// and V~ is of size n×min(m,n).
+//
+// The decomposition is computed with one-sided Jacobi rotations, which find
+// the small singular values to high relative accuracy. Factorize returns
+// whether the decomposition succeeded. If the decomposition failed, routines
+// that require a successful factorization will panic.
+func (svd *CSVD) Factorize(a CMatrix, kind SVDKind) (ok bool) {
+	svd.s = svd.s[:0]
+	svd.kind = kind
+	svd.u = nil
+	svd.v = nil
+
+	m, n := a.Dims()
+	wantU := kind&(SVDThinU|SVDFullU) != 0
+	wantV := kind&(SVDThinV|SVDFullV) != 0
+	fullU := kind&SVDFullU != 0
+	fullV := kind&SVDFullV != 0
+
+	// The rotations are applied to the columns of a tall
+	// matrix, so a wide matrix is factorized through its
+	// conjugate transpose Aᴴ = V * Σ * Uᴴ.
+	tall := m >= n
+	var w *CDense
+	if tall {
+		w = cdenseCopyOf(a)
+	} else {
+		w = cdenseCopyOf(a.H())
+		wantU, wantV = wantV, wantU
+		fullU = fullV
+	}
+	s, left, right, ok := cJacobiSVD(w, wantU, fullU)
+	if !ok {
+		svd.kind = 0
+		return false
+	}
+	svd.s = s
+	if tall {
+		svd.u = left
+		if wantV {
+			svd.v = right
+		}
+	} else {
+		svd.v = left
+		if wantV {
+			svd.u = right
+		}
+	}
+	return true
+}
+
+// cJacobiSVD computes the singular value decomposition W = U * Σ * Vᴴ of the
+// m×n matrix w with m >= n by one-sided Jacobi rotations, overwriting w. The
+// left singular vectors are only computed if wantU is true, and U is m×m if
+// fullU is true and m×n otherwise. V is always computed.
+func cJacobiSVD(w *CDense, wantU, fullU bool) (s []float64, u, v *CDense, ok bool) {
+	const maxSweeps = 60
+
+	m, n := w.Dims()
+	v = NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		v.set(i, i, 1)
+	}
+
+	// Rotate pairs of columns of W until all are mutually
+	// orthogonal. The rotation of columns p and q zeroes the
+	// off-diagonal element of the 2×2 Hermitian matrix
+	//
+	//	[ α  γ ]   [ w_pᴴ*w_p  w_pᴴ*w_q ]
+	//	[ γ̄  β ] = [ w_qᴴ*w_p  w_qᴴ*w_q ].
+	tol := math.Sqrt(float64(m)) * machEps
+	converged := false
+	for sweep := 0; sweep < maxSweeps && !converged; sweep++ {
+		converged = true
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				var alpha, beta float64
+				var gamma complex128
+				for i := 0; i < m; i++ {
+					wp := w.at(i, p)
+					wq := w.at(i, q)
+					alpha += real(wp)*real(wp) + imag(wp)*imag(wp)
+					beta += real(wq)*real(wq) + imag(wq)*imag(wq)
+					gamma += cmplx.Conj(wp) * wq
+				}
+				g := cmplx.Abs(gamma)
+				if g == 0 || g <= tol*math.Sqrt(alpha)*math.Sqrt(beta) {
+					continue
+				}
+				converged = false
+
+				zeta := (beta - alpha) / (2 * g)
+				t := math.Copysign(1, zeta) / (math.Abs(zeta) + math.Hypot(1, zeta))
+				c := 1 / math.Sqrt(1+t*t)
+				sn := complex(c*t, 0)
+				phase := gamma / complex(g, 0)
+				cJacobiRotate(w, p, q, complex(c, 0), sn, phase)
+				cJacobiRotate(v, p, q, complex(c, 0), sn, phase)
+			}
+		}
+	}
+	if !converged {
+		return nil, nil, nil, false
+	}
+
+	// The singular values are the norms of the columns of W.
+	norms := make([]float64, n)
+	for j := range norms {
+		var nrm float64
+		for i := 0; i < m; i++ {
+			nrm = math.Hypot(nrm, cmplx.Abs(w.at(i, j)))
+		}
+		norms[j] = nrm
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return norms[order[i]] > norms[order[j]] })
+	s = make([]float64, n)
+	vs := NewCDense(n, n, nil)
+	for j, o := range order {
+		s[j] = norms[o]
+		for i := 0; i < n; i++ {
+			vs.set(i, j, v.at(i, o))
+		}
+	}
+	if !wantU {
+		return s, nil, vs, true
+	}
+
+	uc := n
+	if fullU {
+		uc = m
+	}
+	u = NewCDense(m, uc, nil)
+	var r int
+	for j, o := range order {
+		if s[j] == 0 {
+			break
+		}
+		for i := 0; i < m; i++ {
+			u.set(i, j, w.at(i, o)/complex(s[j], 0))
+		}
+		r++
+	}
+	if r < uc {
+		// Complete the basis with the trailing columns
+		// of the unitary factor of the QR factorization
+		// of the computed singular vectors.
+		q := NewCDense(m, m, nil)
+		if r == 0 {
+			for i := 0; i < m; i++ {
+				q.set(i, i, 1)
+			}
+		} else {
+			basis := NewCDense(m, r, nil)
+			for i := 0; i < m; i++ {
+				for j := 0; j < r; j++ {
+					basis.set(i, j, u.at(i, j))
+				}
+			}
+			var qr CQR
+			qr.Factorize(basis)
+			qr.QTo(q)
+		}
+		for i := 0; i < m; i++ {
+			for j := r; j < uc; j++ {
+				u.set(i, j, q.at(i, j))
+			}
+		}
+	}
+	return s, u, vs, true
+}
+
+// cJacobiRotate applies the rotation
+//
+//	x_p ← c*x_p - s*e⁻ⁱᵠ*x_q
+//	x_q ← s*eⁱᵠ*x_p + c*x_q
+//
+// to the columns p and q of x, where phase = eⁱᵠ.
+func cJacobiRotate(x *CDense, p, q int, c, s, phase complex128) {
+	sp := s * phase
+	sc := s * cmplx.Conj(phase)
+	for i := 0; i < x.mat.Rows; i++ {
+		xp := x.at(i, p)
+		xq := x.at(i, q)
+		x.set(i, p, c*xp-sc*xq)
+		x.set(i, q, sp*xp+c*xq)
+	}
+}
+
+// Kind returns the SVDKind of the decomposition. If no decomposition has been
+// computed, Kind returns -1.
+func (svd *CSVD) Kind() SVDKind {
+	if !svd.succFact() {
+		return -1
+	}
+	return svd.kind
+}
+
+// Cond returns the 2-norm condition number for the factorized matrix. Cond will
+// panic if the receiver does not contain a successful factorization.
+func (svd *CSVD) Cond() float64 {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	return svd.s[0] / svd.s[len(svd.s)-1]
+}
+
+// Values returns the singular values of the factorized matrix in descending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length min(m,n), and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (svd *CSVD) Values(s []float64) []float64 {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	if s == nil {
+		s = make([]float64, len(svd.s))
+	}
+	if len(s) != len(svd.s) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(s, svd.s)
+	return s
+}
+
+// UTo extracts the matrix U from the singular value decomposition. The first
+// min(m,n) columns are the left singular vectors and correspond to the singular
+// values as returned from CSVD.Values.
+//
+// If dst is not nil, U is stored in-place into dst, and dst must have size
+// m×m if the full U was computed, size m×min(m,n) if the thin U was computed,
+// and UTo panics otherwise. If dst is nil, a new matrix of the appropriate size
+// is allocated and returned.
+func (svd *CSVD) UTo(dst *CDense) *CDense {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	if svd.u == nil {
+		panic("svd: u not computed during factorization")
+	}
+	r, c := svd.u.Dims()
+	if dst == nil {
+		dst = NewCDense(r, c, nil)
+	} else {
+		dst.reuseAs(r, c)
+	}
+	dst.Copy(svd.u)
+	return dst
+}
+
+// VTo extracts the matrix V from the singular value decomposition. The first
+// min(m,n) columns are the right singular vectors and correspond to the singular
+// values as returned from CSVD.Values.
+//
+// If dst is not nil, V is stored in-place into dst, and dst must have size
+// n×n if the full V was computed, size n×min(m,n) if the thin V was computed,
+// and VTo panics otherwise. If dst is nil, a new matrix of the appropriate size
+// is allocated and returned.
+func (svd *CSVD) VTo(dst *CDense) *CDense {
+	if !svd.succFact() {
+		panic(badFact)
+	}
+	if svd.v == nil {
+		panic("svd: v not computed during factorization")
+	}
+	r, c := svd.v.Dims()
+	if dst == nil {
+		dst = NewCDense(r, c, nil)
+	} else {
+		dst.reuseAs(r, c)
+	}
+	dst.Copy(svd.v)
+	return dst
+}
@@ -0,0 +1,107 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	rankDeficient := NewCDense(6, 4, nil)
+	rankDeficient.Mul(randCDense(6, 2, rnd), randCDense(2, 4, rnd))
+	for _, test := range []struct {
+		name string
+		a    *CDense
+	}{
+		{name: "square", a: randCDense(6, 6, rnd)},
+		{name: "tall", a: randCDense(9, 4, rnd)},
+		{name: "wide", a: randCDense(3, 8, rnd)},
+		{name: "vector", a: randCDense(1, 5, rnd)},
+		{name: "rank deficient", a: rankDeficient},
+		{name: "zero", a: NewCDense(3, 2, nil)},
+	} {
+		m, n := test.a.Dims()
+		k := m
+		if n < k {
+			k = n
+		}
+		for _, kind := range []SVDKind{SVDThin, SVDFull, SVDThinU | SVDFullV, SVDNone} {
+			name := fmt.Sprintf("%s kind=%d", test.name, kind)
+			var svd CSVD
+			if !svd.Factorize(test.a, kind) {
+				t.Errorf("%s: unexpected factorization failure", name)
+				continue
+			}
+			s := svd.Values(nil)
+			for i := 1; i < len(s); i++ {
+				if s[i] > s[i-1] {
+					t.Errorf("%s: singular values not in descending order: %v", name, s)
+					break
+				}
+			}
+			var hs CSVD
+			hs.Factorize(test.a.H(), SVDNone)
+			for i, v := range hs.Values(nil) {
+				if math.Abs(v-s[i]) > 1e-12*s[0] {
+					t.Errorf("%s: singular values of Aᴴ differ", name)
+					break
+				}
+			}
+			if kind == SVDNone {
+				if panicked, _ := panics(func() { svd.UTo(nil) }); !panicked {
+					t.Errorf("%s: expected panic for U", name)
+				}
+				continue
+			}
+
+			u := svd.UTo(nil)
+			v := svd.VTo(nil)
+			ur, uc := u.Dims()
+			vr, vc := v.Dims()
+			wantUC, wantVC := k, k
+			if kind&SVDFullU != 0 {
+				wantUC = m
+			}
+			if kind&SVDFullV != 0 {
+				wantVC = n
+			}
+			if ur != m || uc != wantUC || vr != n || vc != wantVC {
+				t.Errorf("%s: unexpected shapes U %d×%d V %d×%d", name, ur, uc, vr, vc)
+				continue
+			}
+			var uhu, vhv CDense
+			uhu.Mul(u.H(), u)
+			vhv.Mul(v.H(), v)
+			if !CEqualApprox(&uhu, cEye(uc), 1e-12) || !CEqualApprox(&vhv, cEye(vc), 1e-12) {
+				t.Errorf("%s: singular vectors not orthonormal", name)
+			}
+			sigma := NewCDense(uc, vc, nil)
+			for i, v := range s {
+				sigma.set(i, i, complex(v, 0))
+			}
+			var us, usv CDense
+			us.Mul(u, sigma)
+			usv.Mul(&us, v.H())
+			if !CEqualApprox(&usv, test.a, 1e-12) {
+				t.Errorf("%s: U*Σ*Vᴴ != A", name)
+			}
+		}
+	}
+}
+
+// cEye returns the n×n complex identity matrix.
+func cEye(n int) *CDense {
+	m := NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.set(i, i, 1)
+	}
+	return m
+}
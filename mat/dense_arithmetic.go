@@ -268,6 +268,10 @@ func (m *Dense) Inverse(a Matrix) error {
 
 // Mul takes the matrix product of a and b, placing the result in the receiver.
 // If the number of columns in a does not equal the number of rows in b, Mul will panic.
+//
+// If a device backend has been selected with device.Use, large products of
+// Dense matrices are computed on the device. If the device operation fails,
+// the product is computed on the host.
 func (m *Dense) Mul(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
@@ -296,6 +300,13 @@ func (m *Dense) Mul(a, b Matrix) {
 		bT = blas.Trans
 	}
 
+	if m.mulBlock(a, b) {
+		return
+	}
+	if m.mulSparse(a, b) {
+		return
+	}
+
 	// Some of the cases do not have a transpose option, so create
 	// temporary memory.
 	// C = A^T * B = (B^T * A)^T
@@ -309,6 +320,9 @@ func (m *Dense) Mul(a, b Matrix) {
 			if restore == nil {
 				m.checkOverlap(bU.mat)
 			}
+			if m.mulDevice(aT, bT, aU, bU) {
+				return
+			}
 			blas64.Gemm(aT, bT, 1, aU.mat, bU.mat, 0, m.mat)
 			return
 
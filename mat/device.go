@@ -0,0 +1,40 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/device"
+)
+
+// deviceMulWork is the number of multiply-add operations above which
+// a product of Dense matrices is offloaded to the device backend in use.
+// Smaller products do not amortize the cost of the transfers. At 512³
+// the packing and copying done by device.Gemm costs less than a tenth
+// of the host product, and at 128³ it costs about a quarter; see the
+// BenchmarkMulDevice benchmarks. It is a variable so that tests may
+// force products to be offloaded.
+var deviceMulWork = 1 << 27
+
+// mulDevice attempts to compute the product of a and b into the receiver
+// with the device backend selected by device.Use. It returns whether the
+// product was computed. If the device operation returns an error, the
+// error is discarded and mulDevice returns false so that the product is
+// computed on the host by the caller.
+func (m *Dense) mulDevice(tA, tB blas.Transpose, a, b *Dense) bool {
+	dev := device.Implementation()
+	if dev == nil {
+		return false
+	}
+	r, c := m.Dims()
+	k := a.mat.Cols
+	if tA != blas.NoTrans {
+		k = a.mat.Rows
+	}
+	if float64(r)*float64(c)*float64(k) < float64(deviceMulWork) {
+		return false
+	}
+	return device.Gemm(dev, tA, tB, 1, a.mat, b.mat, 0, m.mat) == nil
+}
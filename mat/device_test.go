@@ -0,0 +1,104 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/device"
+)
+
+// countingBackend is a device backend that counts its Dgemm calls
+// and optionally fails them.
+type countingBackend struct {
+	device.Host
+	calls int
+	fail  bool
+}
+
+func (b *countingBackend) Dgemm(tA, tB blas.Transpose, m, n, k int, alpha float64, a device.Buffer, lda int, bb device.Buffer, ldb int, beta float64, c device.Buffer, ldc int) error {
+	b.calls++
+	if b.fail {
+		return errors.New("device failure")
+	}
+	return b.Host.Dgemm(tA, tB, m, n, k, alpha, a, lda, bb, ldb, beta, c, ldc)
+}
+
+func TestMulDevice(t *testing.T) {
+	defer func(w int) { deviceMulWork = w }(deviceMulWork)
+	defer device.Use(nil)
+
+	rnd := rand.New(rand.NewSource(1))
+	a := randNormDense(6, 5, rnd)
+	b := randNormDense(5, 7, rnd)
+	big := randNormDense(8, 9, rnd)
+	for _, fail := range []bool{false, true} {
+		dev := &countingBackend{fail: fail}
+		device.Use(dev)
+		for _, test := range []struct {
+			name string
+			a, b Matrix
+		}{
+			{name: "NoTrans", a: a, b: b},
+			{name: "Trans", a: b.T(), b: a.T()},
+			{name: "view", a: big.Slice(1, 7, 2, 7), b: big.Slice(0, 5, 1, 8)},
+		} {
+			var want Dense
+			device.Use(nil)
+			want.Mul(test.a, test.b)
+			device.Use(dev)
+
+			deviceMulWork = 1 << 30
+			calls := dev.calls
+			var got Dense
+			got.Mul(test.a, test.b)
+			if dev.calls != calls {
+				t.Errorf("small product offloaded for %s", test.name)
+			}
+
+			deviceMulWork = 0
+			got.Reset()
+			got.Mul(test.a, test.b)
+			if dev.calls != calls+1 {
+				t.Errorf("product not offloaded for %s", test.name)
+			}
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("unexpected result for %s with fail=%t:\ngot:\n%v\nwant:\n%v", test.name, fail, Formatted(&got), Formatted(&want))
+			}
+		}
+	}
+}
+
+// The device benchmarks offload products to the Host backend, so the
+// difference between the Host and NoDevice timings is the cost of the
+// transfers and packing performed by device.Gemm for a product of the
+// given size. deviceMulWork is 512³.
+func BenchmarkMulDevice128NoDevice(b *testing.B)  { mulDeviceBench(b, 128, false) }
+func BenchmarkMulDevice128Host(b *testing.B)      { mulDeviceBench(b, 128, true) }
+func BenchmarkMulDevice512NoDevice(b *testing.B)  { mulDeviceBench(b, 512, false) }
+func BenchmarkMulDevice512Host(b *testing.B)      { mulDeviceBench(b, 512, true) }
+func BenchmarkMulDevice1024NoDevice(b *testing.B) { mulDeviceBench(b, 1024, false) }
+func BenchmarkMulDevice1024Host(b *testing.B)     { mulDeviceBench(b, 1024, true) }
+func mulDeviceBench(b *testing.B, size int, offload bool) {
+	defer func(w int) { deviceMulWork = w }(deviceMulWork)
+	defer device.Use(nil)
+	if offload {
+		device.Use(device.Host{})
+		deviceMulWork = 0
+	}
+	rnd := rand.New(rand.NewSource(1))
+	a := randNormDense(size, size, rnd)
+	d := randNormDense(size, size, rnd)
+	var m Dense
+	m.Mul(a, d)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Mul(a, d)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+var (
+	dia *DIA
+
+	_ Matrix         = dia
+	_ Banded         = dia
+	_ NonZeroDoer    = dia
+	_ RowNonZeroDoer = dia
+	_ ColNonZeroDoer = dia
+)
+
+// DIA is a sparse matrix in diagonal format, holding a set of diagonals of
+// the matrix. DIA is suited to banded matrices with few distinct diagonals
+// that need not be contiguous.
+type DIA struct {
+	r, c    int
+	offsets []int
+	data    []float64
+}
+
+// NewDIA returns a new r×c sparse matrix in diagonal format holding the
+// diagonals with the given offsets, where the main diagonal has offset
+// zero, diagonals above it have positive offsets and those below it have
+// negative offsets. The element at row i, column j on the diagonal with
+// offset k=j-i is stored in data[d*c+j], where d is the index of k in
+// offsets, so elements of a diagonal are aligned by column. Elements of
+// data that do not correspond to an element of the matrix are never
+// accessed. If data is nil a new slice is allocated, otherwise data is used
+// as the backing slice and must have length len(offsets)*c. NewDIA panics
+// if an offset is out of range or repeated, if the length of data is
+// incorrect or if either of r or c is zero.
+//
+// For example, the matrix
+//
+//	1 0 2 0
+//	0 3 0 4
+//	5 0 6 0
+//
+// with offsets {0, 2, -2} has data
+//
+//	1 3 6 *
+//	* * 2 4
+//	5 * * *
+//
+// which is passed to NewDIA as []float64{1, 3, 6, *, *, *, 2, 4, 5, *, *, *}.
+func NewDIA(r, c int, offsets []int, data []float64) *DIA {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic("mat: negative dimension")
+	}
+	seen := make(map[int]bool, len(offsets))
+	for _, k := range offsets {
+		if k <= -r || c <= k {
+			panic("mat: diagonal offset out of range")
+		}
+		if seen[k] {
+			panic("mat: repeated diagonal offset")
+		}
+		seen[k] = true
+	}
+	if data == nil {
+		data = make([]float64, len(offsets)*c)
+	}
+	if len(data) != len(offsets)*c {
+		panic(ErrSliceLengthMismatch)
+	}
+	return &DIA{r: r, c: c, offsets: offsets, data: data}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *DIA) Dims() (r, c int) {
+	return m.r, m.c
+}
+
+// At returns the element at row i, column j. At takes time linear in the
+// number of diagonals.
+func (m *DIA) At(i, j int) float64 {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	for d, k := range m.offsets {
+		if k == j-i {
+			return m.data[d*m.c+j]
+		}
+	}
+	return 0
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *DIA) T() Matrix {
+	return Transpose{m}
+}
+
+// Bandwidth returns the lower and upper bandwidths of the matrix.
+func (m *DIA) Bandwidth() (kl, ku int) {
+	for _, k := range m.offsets {
+		kl = max(kl, -k)
+		ku = max(ku, k)
+	}
+	return kl, ku
+}
+
+// TBand performs an implicit transpose by returning the receiver inside a TransposeBand.
+func (m *DIA) TBand() Banded {
+	return TransposeBand{m}
+}
+
+// RawDIA returns the backing storage of the receiver. Changes to the
+// elements of data will be reflected in the receiver. The offsets must not
+// be altered.
+func (m *DIA) RawDIA() (offsets []int, data []float64) {
+	return m.offsets, m.data
+}
+
+// span returns the range of columns of the matrix on the diagonal with
+// offset k.
+func (m *DIA) span(k int) (lo, hi int) {
+	return max(0, k), min(m.c, m.r+k)
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *DIA) DoNonZero(fn func(i, j int, v float64)) {
+	for d, k := range m.offsets {
+		lo, hi := m.span(k)
+		for j, v := range m.data[d*m.c+lo : d*m.c+hi] {
+			if v != 0 {
+				fn(lo+j-k, lo+j, v)
+			}
+		}
+	}
+}
+
+// DoRowNonZero calls the function fn for each of the non-zero elements of row i of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *DIA) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	for d, k := range m.offsets {
+		j := i + k
+		if 0 <= j && j < m.c {
+			if v := m.data[d*m.c+j]; v != 0 {
+				fn(i, j, v)
+			}
+		}
+	}
+}
+
+// DoColNonZero calls the function fn for each of the non-zero elements of column j of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *DIA) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	for d, k := range m.offsets {
+		i := j - k
+		if 0 <= i && i < m.r {
+			if v := m.data[d*m.c+j]; v != 0 {
+				fn(i, j, v)
+			}
+		}
+	}
+}
+
+// ToCSR returns the matrix in compressed sparse row format. Elements that
+// are zero are not stored.
+func (m *DIA) ToCSR() *CSR {
+	var rows, cols []int
+	var data []float64
+	m.DoNonZero(func(i, j int, v float64) {
+		rows = append(rows, i)
+		cols = append(cols, j)
+		data = append(data, v)
+	})
+	return &CSR{mat: compress(m.r, m.c, rows, cols, data)}
+}
@@ -13,9 +13,19 @@
 // mat provides:
 //  - Interfaces for Matrix classes (Matrix, Symmetric, Triangular)
 //  - Concrete implementations (Dense, SymDense, TriDense)
+//  - Sparse matrix types (CSR, CSC, COO, DIA) and their factorizations (Cholesky, LU)
+//  - Block matrix types (BlockDense, BlockDiagonal)
+//  - Structured matrices with fast products and solves (Toeplitz, Circulant)
+//  - File backed matrices (MappedDense) and tiled operations on them (MulTiled, TiledCholesky)
 //  - Methods and functions for using matrix data (Add, Trace, SymRankOne)
 //  - Types for constructing and using matrix factorizations (QR, LU)
-//  - The complementary types for complex matrices, CMatrix, CSymDense, etc.
+//  - Rank-revealing factorizations (PivotedCholesky, PivotedQR)
+//  - Batched operations over slices of small matrices (MulBatch, LUSolveBatch, EigBatch)
+//  - Matrix-free linear operators (Operator, MatrixOperator) accepted by the
+//    Krylov eigensolvers, TruncatedSVD and VecDense.ExpMulVec
+//  - The complementary types for complex matrices, CMatrix, CSymDense, etc., and
+//    their factorizations (CQR, CLQ, CLU, CSVD, CSchur, and GSchur, the
+//    generalized Schur factorization of a pencil)
 //
 // A matrix may be constructed through the corresponding New function. If no
 // backing array is provided the matrix will be initialized to all zeros.
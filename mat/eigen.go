@@ -5,6 +5,8 @@
 package mat
 
 import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
 	"gonum.org/v1/gonum/lapack"
 	"gonum.org/v1/gonum/lapack/lapack64"
 )
@@ -34,25 +36,49 @@ type EigenSym struct {
 // Factorize returns whether the decomposition succeeded. If the decomposition
 // failed, methods that require a successful factorization will panic.
 func (e *EigenSym) Factorize(a Symmetric, vectors bool) (ok bool) {
+	return e.factorize(a, vectors, nil)
+}
+
+// FactorizeWork computes the eigenvalue decomposition of the symmetric matrix a
+// as for Factorize, using ws for scratch memory. Repeated factorizations of
+// matrices of the same size into the same receiver using the same ws do not
+// allocate. If ws is nil, FactorizeWork is equivalent to Factorize.
+func (e *EigenSym) FactorizeWork(a Symmetric, vectors bool, ws *Workspace) (ok bool) {
+	return e.factorize(a, vectors, ws)
+}
+
+func (e *EigenSym) factorize(a Symmetric, vectors bool, ws *Workspace) (ok bool) {
 	// kill previous decomposition
 	e.vectorsComputed = false
-	e.values = e.values[:]
 
 	n := a.Symmetric()
-	sd := NewSymDense(n, nil)
+	if e.vectors == nil {
+		e.vectors = &Dense{}
+	}
+	e.vectors.Reset()
+	e.vectors.reuseAs(n, n)
+	sd := SymDense{
+		mat: blas64.Symmetric{
+			N:      n,
+			Stride: n,
+			Data:   e.vectors.mat.Data,
+			Uplo:   blas.Upper,
+		},
+		cap: n,
+	}
 	sd.CopySym(a)
 
 	jobz := lapack.EVNone
 	if vectors {
 		jobz = lapack.EVCompute
 	}
-	w := make([]float64, n)
-	work := []float64{0}
+	w := use(e.values, n)
+	work := ws.getFloats(wsWork, 1, false)
 	lapack64.Syev(jobz, sd.mat, w, work, -1)
 
-	work = getFloats(int(work[0]), false)
+	work = ws.getFloats(wsWork, ws.lwork(work), false)
 	ok = lapack64.Syev(jobz, sd.mat, w, work, len(work))
-	putFloats(work)
+	ws.putFloats(work)
 	if !ok {
 		e.vectorsComputed = false
 		e.values = nil
@@ -61,7 +87,6 @@ func (e *EigenSym) Factorize(a Symmetric, vectors bool) (ok bool) {
 	}
 	e.vectorsComputed = vectors
 	e.values = w
-	e.vectors = NewDense(n, n, sd.mat.Data)
 	return true
 }
 
@@ -167,6 +192,19 @@ func (e *Eigen) succFact() bool {
 // Factorize returns whether the decomposition succeeded. If the decomposition
 // failed, methods that require a successful factorization will panic.
 func (e *Eigen) Factorize(a Matrix, kind EigenKind) (ok bool) {
+	return e.factorize(a, kind, nil)
+}
+
+// FactorizeWork computes the eigenvalues and optionally the eigenvectors of
+// the square matrix a as for Factorize, using ws for scratch memory. Repeated
+// factorizations of matrices of the same size and kind into the same receiver
+// using the same ws do not allocate. If ws is nil, FactorizeWork is equivalent
+// to Factorize.
+func (e *Eigen) FactorizeWork(a Matrix, kind EigenKind, ws *Workspace) (ok bool) {
+	return e.factorize(a, kind, ws)
+}
+
+func (e *Eigen) factorize(a Matrix, kind EigenKind, ws *Workspace) (ok bool) {
 	// kill previous factorization.
 	e.n = 0
 	e.kind = 0
@@ -175,34 +213,40 @@ func (e *Eigen) Factorize(a Matrix, kind EigenKind) (ok bool) {
 	if r != c {
 		panic(ErrShape)
 	}
-	var sd Dense
-	sd.CloneFrom(a)
+	sd := ws.copyOf(a)
 
 	left := kind&EigenLeft != 0
 	right := kind&EigenRight != 0
 
-	var vl, vr Dense
+	var vl, vr *Dense
 	jobvl := lapack.LeftEVNone
 	jobvr := lapack.RightEVNone
+	if ws == nil {
+		vl, vr = &Dense{}, &Dense{}
+	} else {
+		vl, vr = &ws.vl, &ws.vr
+		vl.Reset()
+		vr.Reset()
+	}
 	if left {
-		vl = *NewDense(r, r, nil)
+		vl.reuseAs(r, r)
 		jobvl = lapack.LeftEVCompute
 	}
 	if right {
-		vr = *NewDense(c, c, nil)
+		vr.reuseAs(c, c)
 		jobvr = lapack.RightEVCompute
 	}
 
-	wr := getFloats(c, false)
-	defer putFloats(wr)
-	wi := getFloats(c, false)
-	defer putFloats(wi)
+	wr := ws.getFloats(wsRe, c, false)
+	defer ws.putFloats(wr)
+	wi := ws.getFloats(wsIm, c, false)
+	defer ws.putFloats(wi)
 
-	work := []float64{0}
+	work := ws.getFloats(wsWork, 1, false)
 	lapack64.Geev(jobvl, jobvr, sd.mat, wr, wi, vl.mat, vr.mat, work, -1)
-	work = getFloats(int(work[0]), false)
+	work = ws.getFloats(wsWork, ws.lwork(work), false)
 	first := lapack64.Geev(jobvl, jobvr, sd.mat, wr, wi, vl.mat, vr.mat, work, len(work))
-	putFloats(work)
+	ws.putFloats(work)
 
 	if first != 0 {
 		e.values = nil
@@ -212,31 +256,40 @@ func (e *Eigen) Factorize(a Matrix, kind EigenKind) (ok bool) {
 	e.kind = kind
 
 	// Construct complex eigenvalues from float64 data.
-	values := make([]complex128, r)
+	values := useC(e.values, r)
 	for i, v := range wr {
 		values[i] = complex(v, wi[i])
 	}
 	e.values = values
 
 	// Construct complex eigenvectors from float64 data.
-	var cvl, cvr CDense
 	if left {
-		cvl = *NewCDense(r, r, nil)
-		e.complexEigenTo(&cvl, &vl)
-		e.lVectors = &cvl
+		e.lVectors = reuseCDense(e.lVectors, r, r)
+		e.complexEigenTo(e.lVectors, vl)
 	} else {
 		e.lVectors = nil
 	}
 	if right {
-		cvr = *NewCDense(c, c, nil)
-		e.complexEigenTo(&cvr, &vr)
-		e.rVectors = &cvr
+		e.rVectors = reuseCDense(e.rVectors, c, c)
+		e.complexEigenTo(e.rVectors, vr)
 	} else {
 		e.rVectors = nil
 	}
 	return true
 }
 
+// reuseCDense returns an r×c matrix using the storage of m if it is
+// not nil. The elements of the returned matrix are not zeroed.
+func reuseCDense(m *CDense, r, c int) *CDense {
+	if m == nil {
+		m = &CDense{}
+	} else {
+		m.Reset()
+	}
+	m.reuseAs(r, c)
+	return m
+}
+
 // Kind returns the EigenKind of the decomposition. If no decomposition has been
 // computed, Kind returns -1.
 func (e *Eigen) Kind() EigenKind {
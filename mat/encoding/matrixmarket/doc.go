@@ -0,0 +1,17 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package matrixmarket implements reading and writing of matrices in the
+// Matrix Market coordinate and array formats, the formats of the
+// SuiteSparse Matrix Collection.
+//
+// Real, integer, complex and pattern matrices with general, symmetric,
+// skew-symmetric and Hermitian symmetry may be read as dense or sparse
+// matrices. The entries held in a file may also be read one at a time
+// with the ReadHeader and Next methods of a Reader, and written with the
+// WriteEntries method of a Writer.
+//
+// See https://math.nist.gov/MatrixMarket/formats.html for a description
+// of the format.
+package matrixmarket // import "gonum.org/v1/gonum/mat/encoding/matrixmarket"
@@ -0,0 +1,434 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrixmarket
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const symmetricPattern = `%%MatrixMarket matrix coordinate pattern symmetric
+% A path with a diagonal entry.
+%
+4 4 4
+1 1
+2 1
+3 2
+
+4 3
+`
+
+const skewReal = `%%MatrixMarket matrix coordinate real skew-symmetric
+3 3 2
+2 1 1.5
+3 1 -2
+`
+
+func TestMatrixRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		m    mat.Matrix
+	}{
+		{name: "general", m: mat.NewDense(2, 3, []float64{0, 1, 0, -2.5, 0, 1e-10})},
+		{name: "symmetric", m: mat.NewSymDense(3, []float64{1, 2, 0, 2, 0, 3, 0, 3, 4})},
+	} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		w.Comments = []string{" " + test.name}
+		err := w.WriteMatrix(test.m)
+		if err != nil {
+			t.Errorf("unexpected error writing %s matrix: %v", test.name, err)
+			continue
+		}
+		got, err := NewReader(&buf).ReadMatrix()
+		if err != nil {
+			t.Errorf("unexpected error reading %s matrix: %v", test.name, err)
+			continue
+		}
+		if _, isSym := test.m.(mat.Symmetric); isSym {
+			if _, ok := got.(*mat.SymDense); !ok {
+				t.Errorf("unexpected type for %s matrix: %T", test.name, got)
+			}
+		}
+		if !mat.Equal(got, test.m) {
+			t.Errorf("unexpected %s matrix:\ngot:\n%v\nwant:\n%v", test.name, mat.Formatted(got), mat.Formatted(test.m))
+		}
+	}
+
+	m, err := NewReader(strings.NewReader(skewReal)).ReadMatrix()
+	if err != nil {
+		t.Fatalf("unexpected error reading skew-symmetric matrix: %v", err)
+	}
+	want := mat.NewDense(3, 3, []float64{0, -1.5, 2, 1.5, 0, 0, -2, 0, 0})
+	if !mat.Equal(m, want) {
+		t.Errorf("unexpected skew-symmetric matrix:\ngot:\n%v\nwant:\n%v", mat.Formatted(m), mat.Formatted(want))
+	}
+}
+
+var readErrorTests = []struct {
+	name string
+	in   string
+}{
+	{name: "empty", in: ""},
+	{name: "bad banner", in: "%%MatrixMarket tensor coordinate real general\n1 1 0\n"},
+	{name: "pattern array", in: "%%MatrixMarket matrix array pattern general\n1 1\n"},
+	{name: "array too few entries", in: "%%MatrixMarket matrix array real symmetric\n2 2\n1\n2\n"},
+	{name: "array coordinate entry", in: "%%MatrixMarket matrix array real general\n1 1\n1 1 1\n"},
+	{name: "complex", in: "%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 1\n"},
+	{name: "hermitian", in: "%%MatrixMarket matrix coordinate real hermitian\n1 1 0\n"},
+	{name: "non-square symmetric", in: "%%MatrixMarket matrix coordinate real symmetric\n2 3 0\n"},
+	{name: "missing size", in: "%%MatrixMarket matrix coordinate real general\n"},
+	{name: "bad size", in: "%%MatrixMarket matrix coordinate real general\n2 2 x\n"},
+	{name: "too few entries", in: "%%MatrixMarket matrix coordinate real general\n2 2 2\n1 2 1\n"},
+	{name: "too many entries", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 1\n2 1 1\n"},
+	{name: "index out of range", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n"},
+	{name: "missing value", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2\n"},
+	{name: "bad value", in: "%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 one\n"},
+	{name: "skew diagonal", in: "%%MatrixMarket matrix coordinate real skew-symmetric\n2 2 1\n1 1 1\n"},
+}
+
+func TestReadError(t *testing.T) {
+	for _, test := range readErrorTests {
+		_, err := NewReader(strings.NewReader(test.in)).ReadMatrix()
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+const symmetricArray = `%%MatrixMarket matrix array real symmetric
+% The lower triangle in column-major order.
+3 3
+1
+2
+0
+4
+5
+6
+`
+
+const skewArray = `%%MatrixMarket matrix array integer skew-symmetric
+3 3
+1
+2
+3
+`
+
+const hermitianComplex = `%%MatrixMarket matrix coordinate complex hermitian
+2 2 3
+1 1 1 0
+2 1 2 -3
+2 2 4 0
+`
+
+func TestReadArray(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		want mat.Matrix
+	}{
+		{
+			name: "general",
+			in:   "%%MatrixMarket matrix array real general\n2 3\n1\n2\n3\n4\n5\n6\n",
+			want: mat.NewDense(2, 3, []float64{1, 3, 5, 2, 4, 6}),
+		},
+		{
+			name: "symmetric",
+			in:   symmetricArray,
+			want: mat.NewSymDense(3, []float64{1, 2, 0, 2, 4, 5, 0, 5, 6}),
+		},
+		{
+			name: "skew-symmetric",
+			in:   skewArray,
+			want: mat.NewDense(3, 3, []float64{0, -1, -2, 1, 0, -3, 2, 3, 0}),
+		},
+	} {
+		got, err := NewReader(strings.NewReader(test.in)).ReadMatrix()
+		if err != nil {
+			t.Errorf("unexpected error reading %s array: %v", test.name, err)
+			continue
+		}
+		if !mat.Equal(got, test.want) {
+			t.Errorf("unexpected %s array:\ngot:\n%v\nwant:\n%v", test.name, mat.Formatted(got), mat.Formatted(test.want))
+		}
+	}
+
+}
+
+func TestReadCMatrix(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		want *mat.CDense
+	}{
+		{
+			name: "hermitian",
+			in:   hermitianComplex,
+			want: mat.NewCDense(2, 2, []complex128{1, 2 + 3i, 2 - 3i, 4}),
+		},
+		{
+			name: "general array",
+			in:   "%%MatrixMarket matrix array complex general\n1 2\n1 -1\n0 2\n",
+			want: mat.NewCDense(1, 2, []complex128{1 - 1i, 2i}),
+		},
+		{
+			name: "symmetric",
+			in:   "%%MatrixMarket matrix coordinate complex symmetric\n2 2 1\n2 1 1 1\n",
+			want: mat.NewCDense(2, 2, []complex128{0, 1 + 1i, 1 + 1i, 0}),
+		},
+		{
+			name: "real skew-symmetric",
+			in:   skewReal,
+			want: mat.NewCDense(3, 3, []complex128{0, -1.5, 2, 1.5, 0, 0, -2, 0, 0}),
+		},
+	} {
+		got, err := NewReader(strings.NewReader(test.in)).ReadCMatrix()
+		if err != nil {
+			t.Errorf("unexpected error reading %s matrix: %v", test.name, err)
+			continue
+		}
+		if !mat.CEqual(got, test.want) {
+			t.Errorf("unexpected %s matrix:\ngot:  %v\nwant: %v", test.name, got.RawCMatrix().Data, test.want.RawCMatrix().Data)
+		}
+	}
+
+	for _, in := range []string{
+		"%%MatrixMarket matrix coordinate complex hermitian\n2 2 1\n1 1 1 1\n",
+		"%%MatrixMarket matrix coordinate complex general\n2 2 1\n1 1 1\n",
+	} {
+		_, err := NewReader(strings.NewReader(in)).ReadCMatrix()
+		if err == nil {
+			t.Errorf("expected error for %q", in)
+		}
+	}
+	_, err := NewReader(strings.NewReader(hermitianComplex)).ReadMatrix()
+	if err == nil {
+		t.Errorf("expected error reading complex matrix as real")
+	}
+}
+
+func TestReadCOO(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		want *mat.Dense
+		nnz  int
+	}{
+		{
+			name: "symmetric pattern",
+			in:   symmetricPattern,
+			want: mat.NewDense(4, 4, []float64{
+				1, 1, 0, 0,
+				1, 0, 1, 0,
+				0, 1, 0, 1,
+				0, 0, 1, 0,
+			}),
+			nnz: 7,
+		},
+		{
+			name: "skew-symmetric",
+			in:   skewReal,
+			want: mat.NewDense(3, 3, []float64{0, -1.5, 2, 1.5, 0, 0, -2, 0, 0}),
+			nnz:  4,
+		},
+		{
+			name: "symmetric array",
+			in:   symmetricArray,
+			want: mat.NewDense(3, 3, []float64{1, 2, 0, 2, 4, 5, 0, 5, 6}),
+			nnz:  7,
+		},
+	} {
+		got, err := NewReader(strings.NewReader(test.in)).ReadCOO()
+		if err != nil {
+			t.Errorf("unexpected error reading %s matrix: %v", test.name, err)
+			continue
+		}
+		if got.NNZ() != test.nnz {
+			t.Errorf("unexpected number of stored elements for %s matrix: got %d want %d", test.name, got.NNZ(), test.nnz)
+		}
+		if !mat.Equal(got.ToCSR(), test.want) {
+			t.Errorf("unexpected %s matrix:\ngot:\n%v\nwant:\n%v", test.name, mat.Formatted(got), mat.Formatted(test.want))
+		}
+	}
+}
+
+func TestSparseRoundTrip(t *testing.T) {
+	coo := mat.NewCOO(3, 4, nil, nil, nil)
+	coo.Append(0, 1, 2)
+	coo.Append(2, 3, -1)
+	coo.Append(1, 0, 0.5)
+	coo.Append(0, 1, 1)
+	want := mat.NewDense(3, 4, []float64{
+		0, 3, 0, 0,
+		0.5, 0, 0, 0,
+		0, 0, 0, -1,
+	})
+	for _, test := range []struct {
+		name string
+		m    mat.Matrix
+	}{
+		{name: "COO", m: coo},
+		{name: "CSR", m: coo.ToCSR()},
+		{name: "CSC", m: coo.ToCSC()},
+	} {
+		var buf bytes.Buffer
+		err := NewWriter(&buf).WriteMatrix(test.m)
+		if err != nil {
+			t.Errorf("unexpected error writing %s matrix: %v", test.name, err)
+			continue
+		}
+		wantText := "%%MatrixMarket matrix coordinate real general\n3 4 3\n2 1 0.5\n1 2 3\n3 4 -1\n"
+		if buf.String() != wantText {
+			t.Errorf("unexpected output for %s matrix:\ngot:\n%s\nwant:\n%s", test.name, buf.String(), wantText)
+		}
+		got, err := NewReader(&buf).ReadCOO()
+		if err != nil {
+			t.Errorf("unexpected error reading %s matrix: %v", test.name, err)
+			continue
+		}
+		if !mat.Equal(got.ToCSR(), want) {
+			t.Errorf("unexpected %s matrix:\ngot:\n%v\nwant:\n%v", test.name, mat.Formatted(got), mat.Formatted(want))
+		}
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.Pattern = true
+	err := w.WriteMatrix(coo)
+	if err != nil {
+		t.Fatalf("unexpected error writing pattern matrix: %v", err)
+	}
+	wantText := "%%MatrixMarket matrix coordinate pattern general\n3 4 3\n2 1\n1 2\n3 4\n"
+	if buf.String() != wantText {
+		t.Errorf("unexpected pattern output:\ngot:\n%s\nwant:\n%s", buf.String(), wantText)
+	}
+}
+
+func TestArrayRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		m    mat.Matrix
+	}{
+		{name: "general", m: mat.NewDense(2, 3, []float64{0, 1, 0, -2.5, 0, 1e-10})},
+		{name: "symmetric", m: mat.NewSymDense(3, []float64{1, 2, 0, 2, 0, 3, 0, 3, 4})},
+	} {
+		var buf bytes.Buffer
+		err := NewWriter(&buf).WriteArray(test.m)
+		if err != nil {
+			t.Errorf("unexpected error writing %s array: %v", test.name, err)
+			continue
+		}
+		got, err := NewReader(&buf).ReadMatrix()
+		if err != nil {
+			t.Errorf("unexpected error reading %s array: %v", test.name, err)
+			continue
+		}
+		if !mat.Equal(got, test.m) {
+			t.Errorf("unexpected %s array:\ngot:\n%v\nwant:\n%v", test.name, mat.Formatted(got), mat.Formatted(test.m))
+		}
+	}
+}
+
+func TestCMatrixRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		m         *mat.CDense
+		hermitian bool
+	}{
+		{name: "general", m: mat.NewCDense(2, 3, []complex128{0, 1i, 2, -1 + 1e-10i, 0, 3})},
+		{name: "hermitian", m: mat.NewCDense(2, 2, []complex128{1, 2 + 3i, 2 - 3i, 4}), hermitian: true},
+	} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+		w.Hermitian = test.hermitian
+		err := w.WriteCMatrix(test.m)
+		if err != nil {
+			t.Errorf("unexpected error writing %s matrix: %v", test.name, err)
+			continue
+		}
+		if test.hermitian && !strings.HasPrefix(buf.String(), "%%MatrixMarket matrix coordinate complex hermitian\n") {
+			t.Errorf("unexpected header for %s matrix:\n%s", test.name, buf.String())
+		}
+		got, err := NewReader(&buf).ReadCMatrix()
+		if err != nil {
+			t.Errorf("unexpected error reading %s matrix: %v", test.name, err)
+			continue
+		}
+		if !mat.CEqual(got, test.m) {
+			t.Errorf("unexpected %s matrix:\ngot:  %v\nwant: %v", test.name, got.RawCMatrix().Data, test.m.RawCMatrix().Data)
+		}
+	}
+
+	w := NewWriter(&bytes.Buffer{})
+	w.Hermitian = true
+	err := w.WriteCMatrix(mat.NewCDense(2, 2, []complex128{1, 2i, 2i, 1}))
+	if err == nil {
+		t.Errorf("expected error writing non-hermitian matrix as hermitian")
+	}
+}
+
+func TestEntriesRoundTrip(t *testing.T) {
+	h := Header{
+		Format:   Coordinate,
+		Field:    Complex,
+		Symmetry: Hermitian,
+		Rows:     3,
+		Cols:     3,
+		Entries:  3,
+	}
+	entries := []Entry{
+		{Row: 0, Col: 0, Value: 1},
+		{Row: 2, Col: 0, Value: 2 - 1i},
+		{Row: 1, Col: 1, Value: -3},
+	}
+	var buf bytes.Buffer
+	err := NewWriter(&buf).WriteEntries(h, entries)
+	if err != nil {
+		t.Fatalf("unexpected error writing entries: %v", err)
+	}
+
+	r := NewReader(&buf)
+	got, err := r.ReadHeader()
+	if err != nil {
+		t.Fatalf("unexpected error reading header: %v", err)
+	}
+	if got != h {
+		t.Errorf("unexpected header: got:%+v want:%+v", got, h)
+	}
+	var n int
+	for r.Next() {
+		i, j, v := r.Entry()
+		if n < len(entries) && (Entry{Row: i, Col: j, Value: v}) != entries[n] {
+			t.Errorf("unexpected entry %d: got:(%d, %d, %v) want:%+v", n, i, j, v, entries[n])
+		}
+		n++
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("unexpected error reading entries: %v", err)
+	}
+	if n != len(entries) {
+		t.Errorf("unexpected number of entries: got:%d want:%d", n, len(entries))
+	}
+
+	for _, test := range []struct {
+		name    string
+		h       Header
+		entries []Entry
+	}{
+		{name: "array", h: Header{Format: Array, Field: Real, Symmetry: General, Rows: 1, Cols: 1, Entries: 1}, entries: entries[:1]},
+		{name: "count mismatch", h: Header{Format: Coordinate, Field: Real, Symmetry: General, Rows: 3, Cols: 3, Entries: 2}, entries: entries},
+		{name: "out of range", h: Header{Format: Coordinate, Field: Real, Symmetry: General, Rows: 2, Cols: 2, Entries: 3}, entries: entries},
+		{name: "upper triangle", h: Header{Format: Coordinate, Field: Real, Symmetry: Symmetric, Rows: 3, Cols: 3, Entries: 1}, entries: []Entry{{Row: 0, Col: 1}}},
+		{name: "hermitian real", h: Header{Format: Coordinate, Field: Real, Symmetry: Hermitian, Rows: 3, Cols: 3, Entries: 3}, entries: entries},
+	} {
+		err := NewWriter(&bytes.Buffer{}).WriteEntries(test.h, test.entries)
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,460 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrixmarket
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/cmplx"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Matrix Market format, field and symmetry qualifiers.
+const (
+	Coordinate = "coordinate"
+	Array      = "array"
+
+	Real    = "real"
+	Integer = "integer"
+	Complex = "complex"
+	Pattern = "pattern"
+
+	General       = "general"
+	Symmetric     = "symmetric"
+	SkewSymmetric = "skew-symmetric"
+	Hermitian     = "hermitian"
+)
+
+// Header describes the matrix held by a Matrix Market file.
+type Header struct {
+	// Format is the format of the
+	// file, Coordinate or Array.
+	Format string
+
+	// Field is the type of the matrix
+	// elements, Real, Integer, Complex
+	// or Pattern.
+	Field string
+
+	// Symmetry is the symmetry of the
+	// matrix, General, Symmetric,
+	// SkewSymmetric or Hermitian.
+	Symmetry string
+
+	// Rows and Cols are the dimensions
+	// of the matrix.
+	Rows, Cols int
+
+	// Entries is the number of entries
+	// held in the file.
+	Entries int
+}
+
+// check returns an error if the qualifiers of h are not a valid
+// combination or the dimensions of h are not valid for its symmetry.
+func (h Header) check() error {
+	switch h.Format {
+	case Coordinate, Array:
+	default:
+		return fmt.Errorf("matrixmarket: unsupported format: %s", h.Format)
+	}
+	switch h.Field {
+	case Real, Integer, Complex:
+	case Pattern:
+		if h.Format == Array {
+			return errors.New("matrixmarket: pattern array matrix")
+		}
+	default:
+		return fmt.Errorf("matrixmarket: unsupported field: %s", h.Field)
+	}
+	switch h.Symmetry {
+	case General, Symmetric:
+	case SkewSymmetric:
+		if h.Field == Pattern {
+			return errors.New("matrixmarket: skew-symmetric pattern matrix")
+		}
+	case Hermitian:
+		if h.Field != Complex {
+			return fmt.Errorf("matrixmarket: hermitian %s matrix", h.Field)
+		}
+	default:
+		return fmt.Errorf("matrixmarket: unsupported symmetry: %s", h.Symmetry)
+	}
+	if h.Rows < 0 || h.Cols < 0 || h.Entries < 0 {
+		return errors.New("matrixmarket: negative size")
+	}
+	if h.Symmetry != General && h.Rows != h.Cols {
+		return fmt.Errorf("matrixmarket: non-square %s matrix: %d×%d", h.Symmetry, h.Rows, h.Cols)
+	}
+	return nil
+}
+
+// Reader reads matrices from Matrix Market coordinate and array files.
+type Reader struct {
+	r io.Reader
+	p *parser
+}
+
+// NewReader returns a new Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadHeader reads the header and size line of the receiver's input. The
+// entries held in the file may then be read with Next. ReadHeader must not
+// be used with the ReadMatrix, ReadCOO and ReadCMatrix methods, which read
+// the complete input.
+func (r *Reader) ReadHeader() (Header, error) {
+	p, err := newParser(r.r)
+	if err != nil {
+		return Header{}, err
+	}
+	r.p = p
+	return p.Header, nil
+}
+
+// Next reads the next entry held in the file, returning false when all
+// entries have been read or an error has occurred. The entry is returned
+// by Entry and any error by Err. Next will panic if ReadHeader has not
+// been called successfully.
+func (r *Reader) Next() bool {
+	if r.p == nil {
+		panic("matrixmarket: header not read")
+	}
+	return r.p.next()
+}
+
+// Entry returns the zero-based row and column and the value of the entry
+// read by the last call to Next. Entries of pattern matrices have the value
+// 1, and entries of real and integer matrices have a zero imaginary part.
+// Only the entries held in the file are returned, so elements that are
+// implied by the symmetry of the matrix are not.
+func (r *Reader) Entry() (i, j int, v complex128) {
+	return r.p.i, r.p.j, complex(r.p.v, r.p.vi)
+}
+
+// Err returns the first error that occurred while reading entries with Next.
+func (r *Reader) Err() error {
+	if r.p == nil {
+		return nil
+	}
+	return r.p.err
+}
+
+// ReadMatrix reads the real matrix held by the receiver's input. Symmetric
+// matrices are returned as a *mat.SymDense and general and skew-symmetric
+// matrices as a *mat.Dense. Entries of pattern matrices have the value 1.
+// Complex matrices must be read with ReadCMatrix.
+func (r *Reader) ReadMatrix() (mat.Matrix, error) {
+	p, err := newRealParser(r.r)
+	if err != nil {
+		return nil, err
+	}
+	if p.Symmetry == Symmetric {
+		m := mat.NewSymDense(p.Rows, nil)
+		for p.next() {
+			m.SetSym(p.i, p.j, p.v)
+		}
+		return m, p.err
+	}
+	m := mat.NewDense(p.Rows, p.Cols, nil)
+	for p.next() {
+		m.Set(p.i, p.j, p.v)
+		if p.Symmetry == SkewSymmetric {
+			m.Set(p.j, p.i, -p.v)
+		}
+	}
+	return m, p.err
+}
+
+// ReadCOO reads the real matrix held by the receiver's input as a sparse
+// matrix. Elements of symmetric and skew-symmetric matrices are stored for
+// both triangles, and zero elements of array files are not stored. The
+// returned matrix may be converted for computation with its ToCSR or ToCSC
+// methods. Complex matrices cannot be read with ReadCOO.
+func (r *Reader) ReadCOO() (*mat.COO, error) {
+	p, err := newRealParser(r.r)
+	if err != nil {
+		return nil, err
+	}
+	m := mat.NewCOO(p.Rows, p.Cols, nil, nil, nil)
+	for p.next() {
+		if p.v == 0 {
+			continue
+		}
+		m.Append(p.i, p.j, p.v)
+		if p.i == p.j {
+			continue
+		}
+		switch p.Symmetry {
+		case Symmetric:
+			m.Append(p.j, p.i, p.v)
+		case SkewSymmetric:
+			m.Append(p.j, p.i, -p.v)
+		}
+	}
+	return m, p.err
+}
+
+// newRealParser returns a parser for a non-empty real matrix in r.
+func newRealParser(r io.Reader) (*parser, error) {
+	p, err := newParser(r)
+	if err != nil {
+		return nil, err
+	}
+	if p.Field == Complex {
+		return nil, errors.New("matrixmarket: complex matrix")
+	}
+	if p.Rows == 0 || p.Cols == 0 {
+		return nil, errors.New("matrixmarket: zero length matrix")
+	}
+	return p, nil
+}
+
+// ReadCMatrix reads the matrix held by the receiver's input as a complex
+// matrix. Real, integer and pattern matrices are read with zero imaginary
+// parts. The elements of the upper triangle of symmetric, skew-symmetric
+// and Hermitian matrices are filled from those of the lower triangle.
+func (r *Reader) ReadCMatrix() (*mat.CDense, error) {
+	p, err := newParser(r.r)
+	if err != nil {
+		return nil, err
+	}
+	if p.Rows == 0 || p.Cols == 0 {
+		return nil, errors.New("matrixmarket: zero length matrix")
+	}
+	m := mat.NewCDense(p.Rows, p.Cols, nil)
+	for p.next() {
+		v := complex(p.v, p.vi)
+		m.Set(p.i, p.j, v)
+		if p.i == p.j {
+			continue
+		}
+		switch p.Symmetry {
+		case Symmetric:
+			m.Set(p.j, p.i, v)
+		case SkewSymmetric:
+			m.Set(p.j, p.i, -v)
+		case Hermitian:
+			m.Set(p.j, p.i, cmplx.Conj(v))
+		}
+	}
+	return m, p.err
+}
+
+// parser reads the entries of a Matrix Market coordinate or array file.
+type parser struct {
+	sc   *bufio.Scanner
+	line int
+
+	Header
+
+	// read is the number of entries read.
+	read int
+
+	// i, j and v are the zero-based row and
+	// column and the value of the last entry
+	// read by next. vi is the imaginary part
+	// of the value of complex matrices.
+	i, j  int
+	v, vi float64
+
+	err error
+}
+
+// newParser returns a parser for the coordinate or array file in r after
+// reading its header and size line.
+func newParser(r io.Reader) (*parser, error) {
+	p := &parser{sc: bufio.NewScanner(r)}
+	if !p.sc.Scan() {
+		if err := p.sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("matrixmarket: missing header")
+	}
+	p.line++
+	banner := strings.Fields(strings.ToLower(p.sc.Text()))
+	if len(banner) != 5 || banner[0] != "%%matrixmarket" || banner[1] != "matrix" {
+		return nil, errors.New("matrixmarket: invalid header")
+	}
+	p.Format = banner[2]
+	p.Field = banner[3]
+	p.Symmetry = banner[4]
+	err := p.Header.check()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := p.fields()
+	if err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, errors.New("matrixmarket: missing size line")
+	}
+	want := 3
+	if p.Format == Array {
+		want = 2
+	}
+	if len(fields) != want {
+		return nil, fmt.Errorf("matrixmarket: line %d: invalid size line", p.line)
+	}
+	var size [3]int
+	for k, f := range fields {
+		size[k], err = strconv.Atoi(f)
+		if err != nil || size[k] < 0 {
+			return nil, fmt.Errorf("matrixmarket: line %d: invalid size: %q", p.line, f)
+		}
+	}
+	p.Rows, p.Cols, p.Entries = size[0], size[1], size[2]
+	if p.Format == Array {
+		// Array files hold every element in column-major
+		// order, or the lower triangle of matrices with
+		// symmetry, excluding the diagonal if skew.
+		n := p.Rows
+		switch p.Symmetry {
+		case General:
+			p.Entries = p.Rows * p.Cols
+		case SkewSymmetric:
+			p.Entries = n * (n - 1) / 2
+		default:
+			p.Entries = n * (n + 1) / 2
+		}
+		p.i, p.j = -1, 0
+		if p.Symmetry == SkewSymmetric {
+			p.i = 0
+		}
+	}
+	err = p.Header.check()
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// fields returns the fields of the next non-comment, non-blank line,
+// or nil at the end of the input.
+func (p *parser) fields() ([]string, error) {
+	for p.sc.Scan() {
+		p.line++
+		text := p.sc.Text()
+		if strings.HasPrefix(text, "%") {
+			continue
+		}
+		f := strings.Fields(text)
+		if len(f) != 0 {
+			return f, nil
+		}
+	}
+	return nil, p.sc.Err()
+}
+
+// next reads the next entry, returning false when all entries have
+// been read or an error has occurred. Errors are held in the err
+// field.
+func (p *parser) next() bool {
+	if p.err != nil {
+		return false
+	}
+	fields, err := p.fields()
+	if err != nil {
+		p.err = err
+		return false
+	}
+	if fields == nil {
+		if p.read != p.Entries {
+			p.err = fmt.Errorf("matrixmarket: unexpected number of entries: got:%d want:%d", p.read, p.Entries)
+		}
+		return false
+	}
+	p.read++
+	if p.read > p.Entries {
+		p.err = fmt.Errorf("matrixmarket: line %d: too many entries", p.line)
+		return false
+	}
+
+	var want int
+	switch p.Field {
+	case Pattern:
+		want = 0
+	case Complex:
+		want = 2
+	default:
+		want = 1
+	}
+	if p.Format == Coordinate {
+		want += 2
+	}
+	if len(fields) != want {
+		p.err = fmt.Errorf("matrixmarket: line %d: invalid entry", p.line)
+		return false
+	}
+
+	if p.Format == Array {
+		p.advance()
+	} else {
+		i, err := strconv.Atoi(fields[0])
+		if err != nil || i < 1 || i > p.Rows {
+			p.err = fmt.Errorf("matrixmarket: line %d: invalid row index: %q", p.line, fields[0])
+			return false
+		}
+		j, err := strconv.Atoi(fields[1])
+		if err != nil || j < 1 || j > p.Cols {
+			p.err = fmt.Errorf("matrixmarket: line %d: invalid column index: %q", p.line, fields[1])
+			return false
+		}
+		if p.Symmetry == SkewSymmetric && i == j {
+			p.err = fmt.Errorf("matrixmarket: line %d: diagonal entry in skew-symmetric matrix", p.line)
+			return false
+		}
+		p.i, p.j = i-1, j-1
+		fields = fields[2:]
+	}
+
+	p.v, p.vi = 1, 0
+	if p.Field == Pattern {
+		return true
+	}
+	p.v, err = strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		p.err = fmt.Errorf("matrixmarket: line %d: invalid value: %q", p.line, fields[0])
+		return false
+	}
+	if p.Field == Complex {
+		p.vi, err = strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			p.err = fmt.Errorf("matrixmarket: line %d: invalid value: %q", p.line, fields[1])
+			return false
+		}
+		if p.Symmetry == Hermitian && p.i == p.j && p.vi != 0 {
+			p.err = fmt.Errorf("matrixmarket: line %d: non-real diagonal entry in hermitian matrix", p.line)
+			return false
+		}
+	}
+	return true
+}
+
+// advance moves the position of the current array entry to the next
+// stored element in column-major order.
+func (p *parser) advance() {
+	p.i++
+	if p.i < p.Rows {
+		return
+	}
+	p.j++
+	switch p.Symmetry {
+	case General:
+		p.i = 0
+	case SkewSymmetric:
+		p.i = p.j + 1
+	default:
+		p.i = p.j
+	}
+}
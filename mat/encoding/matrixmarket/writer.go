@@ -0,0 +1,253 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matrixmarket
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/cmplx"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Writer writes matrices as Matrix Market coordinate and array files.
+type Writer struct {
+	// Comments holds comment lines that are
+	// written after the header. Each comment
+	// must be a single line.
+	Comments []string
+
+	// Pattern indicates that WriteMatrix
+	// writes pattern matrices holding only
+	// the positions of non-zero elements.
+	Pattern bool
+
+	// Hermitian indicates that WriteCMatrix
+	// writes Hermitian matrices holding the
+	// lower triangle. WriteCMatrix returns
+	// an error if the matrix is not Hermitian.
+	Hermitian bool
+
+	w io.Writer
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Entry is an entry of a coordinate file written by WriteEntries.
+type Entry struct {
+	// Row and Col are the zero-based
+	// row and column of the entry.
+	Row, Col int
+
+	// Value is the value of the entry.
+	// The imaginary part is only written
+	// for complex matrices, and the value
+	// is not written for pattern matrices.
+	Value complex128
+}
+
+// WriteEntries writes a coordinate file described by h holding entries, in
+// the order they are given, to the receiver's output. The entries of
+// symmetric, skew-symmetric and Hermitian matrices must be in the lower
+// triangle. WriteEntries returns an error if h is not a valid coordinate
+// file header, if the Entries field of h is not the length of entries or
+// if an entry is outside the matrix.
+func (w *Writer) WriteEntries(h Header, entries []Entry) error {
+	if h.Format != Coordinate {
+		return fmt.Errorf("matrixmarket: invalid format for entries: %s", h.Format)
+	}
+	err := h.check()
+	if err != nil {
+		return err
+	}
+	if h.Entries != len(entries) {
+		return fmt.Errorf("matrixmarket: entry count mismatch: %d != %d", h.Entries, len(entries))
+	}
+	for _, e := range entries {
+		if e.Row < 0 || h.Rows <= e.Row || e.Col < 0 || h.Cols <= e.Col {
+			return fmt.Errorf("matrixmarket: entry out of range: (%d, %d)", e.Row, e.Col)
+		}
+		if h.Symmetry != General && e.Row < e.Col {
+			return fmt.Errorf("matrixmarket: entry not in lower triangle: (%d, %d)", e.Row, e.Col)
+		}
+	}
+	return w.write(h.Field, h.Symmetry, h.Rows, h.Cols, entries)
+}
+
+// WriteMatrix writes the non-zero elements of m to the receiver's output
+// as a real coordinate matrix, or as a pattern matrix if the receiver's
+// Pattern field is true. Matrices that implement mat.Symmetric are written
+// as symmetric matrices holding the lower triangle, and other matrices as
+// general matrices. The elements of sparse matrices that implement
+// mat.NonZeroDoer, and of *mat.COO matrices, are visited without
+// examining their zero elements.
+func (w *Writer) WriteMatrix(m mat.Matrix) error {
+	r, c := m.Dims()
+	s, isSym := m.(mat.Symmetric)
+	symmetry := General
+	if isSym {
+		symmetry = Symmetric
+	}
+
+	var entries []Entry
+	if coo, ok := m.(*mat.COO); ok {
+		m = coo.ToCSC()
+	}
+	if nz, ok := m.(mat.NonZeroDoer); ok && !isSym {
+		nz.DoNonZero(func(i, j int, v float64) {
+			if v != 0 {
+				entries = append(entries, Entry{Row: i, Col: j, Value: complex(v, 0)})
+			}
+		})
+		sort.Slice(entries, func(a, b int) bool {
+			if entries[a].Col != entries[b].Col {
+				return entries[a].Col < entries[b].Col
+			}
+			return entries[a].Row < entries[b].Row
+		})
+	} else {
+		for j := 0; j < c; j++ {
+			i := 0
+			if isSym {
+				i = j
+			}
+			for ; i < r; i++ {
+				var v float64
+				if isSym {
+					v = s.At(i, j)
+				} else {
+					v = m.At(i, j)
+				}
+				if v != 0 {
+					entries = append(entries, Entry{Row: i, Col: j, Value: complex(v, 0)})
+				}
+			}
+		}
+	}
+	field := Real
+	if w.Pattern {
+		field = Pattern
+	}
+	return w.write(field, symmetry, r, c, entries)
+}
+
+// WriteArray writes all the elements of m to the receiver's output as a
+// real array matrix. Matrices that implement mat.Symmetric are written as
+// symmetric matrices holding the lower triangle, and other matrices as
+// general matrices.
+func (w *Writer) WriteArray(m mat.Matrix) error {
+	r, c := m.Dims()
+	_, isSym := m.(mat.Symmetric)
+	symmetry := General
+	if isSym {
+		symmetry = Symmetric
+	}
+
+	bw := bufio.NewWriter(w.w)
+	err := w.writeHeader(bw, Array, Real, symmetry)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(bw, "%d %d\n", r, c)
+	var buf []byte
+	for j := 0; j < c; j++ {
+		i := 0
+		if isSym {
+			i = j
+		}
+		for ; i < r; i++ {
+			buf = strconv.AppendFloat(buf[:0], m.At(i, j), 'g', -1, 64)
+			buf = append(buf, '\n')
+			bw.Write(buf)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteCMatrix writes the non-zero elements of m to the receiver's output
+// as a complex coordinate matrix. If the receiver's Hermitian field is true,
+// m is written as a Hermitian matrix holding the lower triangle, and as a
+// general matrix otherwise.
+func (w *Writer) WriteCMatrix(m mat.CMatrix) error {
+	r, c := m.Dims()
+	symmetry := General
+	if w.Hermitian {
+		if r != c {
+			return fmt.Errorf("matrixmarket: non-square hermitian matrix: %d×%d", r, c)
+		}
+		for i := 0; i < r; i++ {
+			for j := 0; j <= i; j++ {
+				if m.At(i, j) != cmplx.Conj(m.At(j, i)) {
+					return errors.New("matrixmarket: matrix is not hermitian")
+				}
+			}
+		}
+		symmetry = Hermitian
+	}
+
+	var entries []Entry
+	for j := 0; j < c; j++ {
+		i := 0
+		if w.Hermitian {
+			i = j
+		}
+		for ; i < r; i++ {
+			v := m.At(i, j)
+			if v != 0 {
+				entries = append(entries, Entry{Row: i, Col: j, Value: v})
+			}
+		}
+	}
+	return w.write(Complex, symmetry, r, c, entries)
+}
+
+// write writes a coordinate file of the given field and symmetry holding
+// entries.
+func (w *Writer) write(field, symmetry string, rows, cols int, entries []Entry) error {
+	bw := bufio.NewWriter(w.w)
+	err := w.writeHeader(bw, Coordinate, field, symmetry)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(bw, "%d %d %d\n", rows, cols, len(entries))
+	var buf []byte
+	for _, e := range entries {
+		buf = strconv.AppendInt(buf[:0], int64(e.Row+1), 10)
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, int64(e.Col+1), 10)
+		if field != Pattern {
+			buf = append(buf, ' ')
+			buf = strconv.AppendFloat(buf, real(e.Value), 'g', -1, 64)
+		}
+		if field == Complex {
+			buf = append(buf, ' ')
+			buf = strconv.AppendFloat(buf, imag(e.Value), 'g', -1, 64)
+		}
+		buf = append(buf, '\n')
+		bw.Write(buf)
+	}
+	return bw.Flush()
+}
+
+// writeHeader writes the banner and comment lines of a file of the given
+// format, field and symmetry to bw.
+func (w *Writer) writeHeader(bw *bufio.Writer, format, field, symmetry string) error {
+	fmt.Fprintf(bw, "%%%%MatrixMarket matrix %s %s %s\n", format, field, symmetry)
+	for _, c := range w.Comments {
+		if strings.ContainsAny(c, "\r\n") {
+			return errors.New("matrixmarket: multiple line comment")
+		}
+		fmt.Fprintf(bw, "%%%s\n", c)
+	}
+	return nil
+}
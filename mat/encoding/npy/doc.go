@@ -0,0 +1,16 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package npy implements reading and writing of matrices and vectors in
+// the NumPy .npy array format and .npz archive format.
+//
+// One-dimensional arrays correspond to vectors and two-dimensional arrays
+// to matrices. Arrays of little- or big-endian floating point and signed
+// and unsigned integer elements in C or Fortran order may be read, with
+// the element values converted to float64. Arrays are written as
+// little-endian float64 values in C order.
+//
+// See https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html
+// for a description of the format.
+package npy // import "gonum.org/v1/gonum/mat/encoding/npy"
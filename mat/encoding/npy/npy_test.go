@@ -0,0 +1,314 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package npy
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// npyFile returns a version major .npy file with the given header
+// dictionary and data.
+func npyFile(major byte, dict string, data []byte) []byte {
+	var b bytes.Buffer
+	b.WriteString(magic)
+	b.WriteByte(major)
+	b.WriteByte(0)
+	if major == 1 {
+		binary.Write(&b, binary.LittleEndian, uint16(len(dict)+1))
+	} else {
+		binary.Write(&b, binary.LittleEndian, uint32(len(dict)+1))
+	}
+	b.WriteString(dict)
+	b.WriteByte('\n')
+	b.Write(data)
+	return b.Bytes()
+}
+
+// encodeValues returns the values in vals encoded with the given byte order
+// as elements of type t.
+func encodeValues(order binary.ByteOrder, t interface{}, vals []float64) []byte {
+	var b bytes.Buffer
+	for _, v := range vals {
+		switch t.(type) {
+		case float64:
+			binary.Write(&b, order, v)
+		case float32:
+			binary.Write(&b, order, float32(v))
+		case int8:
+			binary.Write(&b, order, int8(v))
+		case int16:
+			binary.Write(&b, order, int16(v))
+		case int32:
+			binary.Write(&b, order, int32(v))
+		case int64:
+			binary.Write(&b, order, int64(v))
+		case uint8:
+			binary.Write(&b, order, uint8(v))
+		case uint16:
+			binary.Write(&b, order, uint16(v))
+		case uint32:
+			binary.Write(&b, order, uint32(v))
+		case uint64:
+			binary.Write(&b, order, uint64(v))
+		}
+	}
+	return b.Bytes()
+}
+
+func TestReadMatrix(t *testing.T) {
+	vals := []float64{1, 2, 3, 4, 5, 6}
+	dense := mat.NewDense(2, 3, vals)
+	fortran := mat.NewDense(2, 3, []float64{1, 3, 5, 2, 4, 6})
+	vec := mat.NewVecDense(6, vals)
+	for _, test := range []struct {
+		name string
+		file []byte
+		want mat.Matrix
+	}{
+		{
+			name: "f8",
+			file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }", encodeValues(binary.LittleEndian, float64(0), vals)),
+			want: dense,
+		},
+		{
+			name: "big-endian f8",
+			file: npyFile(1, "{'descr': '>f8', 'fortran_order': False, 'shape': (2, 3), }", encodeValues(binary.BigEndian, float64(0), vals)),
+			want: dense,
+		},
+		{
+			name: "f4",
+			file: npyFile(1, "{'descr': '<f4', 'fortran_order': False, 'shape': (2, 3), }", encodeValues(binary.LittleEndian, float32(0), vals)),
+			want: dense,
+		},
+		{
+			name: "fortran",
+			file: npyFile(1, "{'descr': '<f8', 'fortran_order': True, 'shape': (2, 3), }", encodeValues(binary.LittleEndian, float64(0), vals)),
+			want: fortran,
+		},
+		{
+			name: "vector",
+			file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (6,), }", encodeValues(binary.LittleEndian, float64(0), vals)),
+			want: vec,
+		},
+		{
+			name: "version 2",
+			file: npyFile(2, `{"descr": "<f8", "fortran_order": False, "shape": (6,)}`, encodeValues(binary.LittleEndian, float64(0), vals)),
+			want: vec,
+		},
+		{
+			name: "long dimensions",
+			file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (2L, 3L), }", encodeValues(binary.LittleEndian, float64(0), vals)),
+			want: dense,
+		},
+		{
+			name: "i1",
+			file: npyFile(1, "{'descr': '|i1', 'fortran_order': False, 'shape': (6,), }", encodeValues(binary.LittleEndian, int8(0), vals)),
+			want: vec,
+		},
+		{
+			name: "i2",
+			file: npyFile(1, "{'descr': '<i2', 'fortran_order': False, 'shape': (6,), }", encodeValues(binary.LittleEndian, int16(0), vals)),
+			want: vec,
+		},
+		{
+			name: "i4",
+			file: npyFile(1, "{'descr': '>i4', 'fortran_order': False, 'shape': (6,), }", encodeValues(binary.BigEndian, int32(0), vals)),
+			want: vec,
+		},
+		{
+			name: "i8",
+			file: npyFile(1, "{'descr': '<i8', 'fortran_order': False, 'shape': (2, 3), }", encodeValues(binary.LittleEndian, int64(0), vals)),
+			want: dense,
+		},
+		{
+			name: "u1",
+			file: npyFile(1, "{'descr': '|u1', 'fortran_order': False, 'shape': (6,), }", encodeValues(binary.LittleEndian, uint8(0), vals)),
+			want: vec,
+		},
+		{
+			name: "u2",
+			file: npyFile(1, "{'descr': '<u2', 'fortran_order': False, 'shape': (6,), }", encodeValues(binary.LittleEndian, uint16(0), vals)),
+			want: vec,
+		},
+		{
+			name: "u4",
+			file: npyFile(1, "{'descr': '<u4', 'fortran_order': False, 'shape': (6,), }", encodeValues(binary.LittleEndian, uint32(0), vals)),
+			want: vec,
+		},
+		{
+			name: "u8",
+			file: npyFile(1, "{'descr': '>u8', 'fortran_order': True, 'shape': (2, 3), }", encodeValues(binary.BigEndian, uint64(0), vals)),
+			want: fortran,
+		},
+	} {
+		got, err := NewReader(bytes.NewReader(test.file)).ReadMatrix()
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.name, err)
+			continue
+		}
+		var typeOK bool
+		switch test.want.(type) {
+		case *mat.Dense:
+			_, typeOK = got.(*mat.Dense)
+		case *mat.VecDense:
+			_, typeOK = got.(*mat.VecDense)
+		}
+		if !typeOK {
+			t.Errorf("unexpected type for %s: got %T want %T", test.name, got, test.want)
+		}
+		if !mat.Equal(got, test.want) {
+			t.Errorf("unexpected result for %s:\ngot:\n%v\nwant:\n%v", test.name, mat.Formatted(got), mat.Formatted(test.want))
+		}
+	}
+}
+
+func TestReadMatrixErrors(t *testing.T) {
+	data := encodeValues(binary.LittleEndian, float64(0), []float64{1, 2, 3, 4, 5, 6})
+	for _, test := range []struct {
+		name string
+		file []byte
+	}{
+		{name: "empty", file: nil},
+		{name: "bad magic", file: []byte("\x93NUMPX\x01\x00\x00\x00")},
+		{name: "bad version", file: npyFile(4, "{'descr': '<f8', 'fortran_order': False, 'shape': (6,), }", data)},
+		{name: "complex", file: npyFile(1, "{'descr': '<c16', 'fortran_order': False, 'shape': (3,), }", data)},
+		{name: "object", file: npyFile(1, "{'descr': '|O', 'fortran_order': False, 'shape': (6,), }", data)},
+		{name: "scalar", file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (), }", data)},
+		{name: "three dimensions", file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (1, 2, 3), }", data)},
+		{name: "zero length", file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (0, 3), }", data)},
+		{name: "missing shape", file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, }", data)},
+		{name: "malformed header", file: npyFile(1, "{'descr': '<f8' 'fortran_order': False, 'shape': (6,), }", data)},
+		{name: "short data", file: npyFile(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (7,), }", data)},
+	} {
+		_, err := NewReader(bytes.NewReader(test.file)).ReadMatrix()
+		if err == nil {
+			t.Errorf("expected error for %s", test.name)
+		}
+	}
+}
+
+func TestWriteMatrix(t *testing.T) {
+	for _, test := range []struct {
+		m     mat.Matrix
+		shape string
+	}{
+		{m: mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, math.Inf(-1)}), shape: "(2, 3)"},
+		{m: mat.NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}).T(), shape: "(2, 3)"},
+		{m: mat.NewVecDense(4, []float64{1, math.NaN(), 3, 4}), shape: "(4,)"},
+		{m: mat.NewDiagDense(2, []float64{7, 8}), shape: "(2, 2)"},
+	} {
+		var b bytes.Buffer
+		err := NewWriter(&b).WriteMatrix(test.m)
+		if err != nil {
+			t.Fatalf("unexpected error writing matrix: %v", err)
+		}
+		buf := b.Bytes()
+
+		// The layout must match that written by numpy.save.
+		n := int(binary.LittleEndian.Uint16(buf[8:10]))
+		if (10+n)%headerAlign != 0 {
+			t.Errorf("array data not aligned: header length %d", n)
+		}
+		dict := strings.TrimRight(string(buf[10:10+n]), " \n")
+		want := "{'descr': '<f8', 'fortran_order': False, 'shape': " + test.shape + ", }"
+		if dict != want {
+			t.Errorf("unexpected header: got %q want %q", dict, want)
+		}
+
+		got, err := NewReader(&b).ReadMatrix()
+		if err != nil {
+			t.Fatalf("unexpected error reading matrix: %v", err)
+		}
+		if !mat.Equal(got, test.m) && !sameNaN(got, test.m) {
+			t.Errorf("round trip mismatch:\ngot:\n%v\nwant:\n%v", mat.Formatted(got), mat.Formatted(test.m))
+		}
+	}
+}
+
+// sameNaN returns whether a and b are equal treating NaN values as equal.
+func sameNaN(a, b mat.Matrix) bool {
+	r, c := a.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			x, y := a.At(i, j), b.At(i, j)
+			if x != y && !(math.IsNaN(x) && math.IsNaN(y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestArchive(t *testing.T) {
+	arrays := map[string]mat.Matrix{
+		"a": mat.NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+		"b": mat.NewVecDense(3, []float64{7, 8, 9}),
+		"c": mat.NewDense(1, 1, []float64{10}),
+	}
+	var b bytes.Buffer
+	err := WriteArchive(&b, arrays)
+	if err != nil {
+		t.Fatalf("unexpected error writing archive: %v", err)
+	}
+	got, err := ReadArchive(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error reading archive: %v", err)
+	}
+	checkArrays(t, got, arrays)
+
+	// Compressed archives and non-array members
+	// must be handled.
+	b.Reset()
+	z := zip.NewWriter(&b)
+	f, err := z.Create("notes.txt")
+	if err != nil {
+		t.Fatalf("unexpected error creating archive member: %v", err)
+	}
+	f.Write([]byte("not an array"))
+	for _, name := range []string{"a", "b"} {
+		f, err = z.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: zip.Deflate})
+		if err != nil {
+			t.Fatalf("unexpected error creating archive member: %v", err)
+		}
+		err = NewWriter(f).WriteMatrix(arrays[name])
+		if err != nil {
+			t.Fatalf("unexpected error writing archive member: %v", err)
+		}
+	}
+	err = z.Close()
+	if err != nil {
+		t.Fatalf("unexpected error closing archive: %v", err)
+	}
+	got, err = ReadArchive(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error reading compressed archive: %v", err)
+	}
+	delete(arrays, "c")
+	checkArrays(t, got, arrays)
+}
+
+func checkArrays(t *testing.T, got, want map[string]mat.Matrix) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("unexpected number of arrays: got %d want %d", len(got), len(want))
+	}
+	for name, w := range want {
+		g, ok := got[name]
+		if !ok {
+			t.Errorf("missing array %q", name)
+			continue
+		}
+		if !mat.Equal(g, w) {
+			t.Errorf("unexpected array %q:\ngot:\n%v\nwant:\n%v", name, mat.Formatted(g), mat.Formatted(w))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package npy
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ReadArchive reads the arrays held in the .npz archive of the given size
+// read from r. The returned map is keyed by the names of the arrays, the
+// names of the archive members without their .npy suffix. Members without
+// a .npy suffix are ignored. Arrays are returned as for Reader.ReadMatrix.
+//
+// Both stored archives written by numpy.savez and compressed archives
+// written by numpy.savez_compressed may be read.
+func ReadArchive(r io.ReaderAt, size int64) (map[string]mat.Matrix, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	arrays := make(map[string]mat.Matrix)
+	for _, f := range z.File {
+		name := strings.TrimSuffix(f.Name, ".npy")
+		if name == f.Name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		m, err := NewReader(rc).ReadMatrix()
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("npy: reading %q: %w", f.Name, err)
+		}
+		arrays[name] = m
+	}
+	return arrays, nil
+}
+
+// WriteArchive writes arrays to w as an uncompressed .npz archive in the
+// form written by numpy.savez. Each matrix is stored as a member named by
+// its key with a .npy suffix, and is written as for Writer.WriteMatrix.
+// Members are written in sorted order of their names.
+func WriteArchive(w io.Writer, arrays map[string]mat.Matrix) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	z := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := z.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		err = NewWriter(f).WriteMatrix(arrays[name])
+		if err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
@@ -0,0 +1,354 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package npy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// magic is the prefix of every .npy file.
+const magic = "\x93NUMPY"
+
+// maxLen is the largest number of elements that may be allocated.
+const maxLen = int(^uint(0) >> 1)
+
+// Reader reads matrices and vectors from .npy files.
+type Reader struct {
+	r io.Reader
+}
+
+// NewReader returns a new Reader that reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadMatrix reads the array held by the receiver's input. One-dimensional
+// arrays are returned as a *mat.VecDense and two-dimensional arrays as a
+// *mat.Dense. Arrays with other numbers of dimensions or with no elements
+// cannot be read.
+func (r *Reader) ReadMatrix() (mat.Matrix, error) {
+	br := bufio.NewReader(r.r)
+	h, err := readHeader(br)
+	if err != nil {
+		return nil, err
+	}
+	var rows, cols int
+	switch len(h.shape) {
+	case 1:
+		rows, cols = h.shape[0], 1
+	case 2:
+		rows, cols = h.shape[0], h.shape[1]
+	default:
+		return nil, fmt.Errorf("npy: unsupported number of dimensions: %d", len(h.shape))
+	}
+	if rows == 0 || cols == 0 {
+		return nil, errors.New("npy: zero length array")
+	}
+	if rows > maxLen/cols/h.size {
+		return nil, errors.New("npy: array too big")
+	}
+
+	data := make([]float64, rows*cols)
+	err = readData(br, h, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(h.shape) == 1 {
+		return mat.NewVecDense(rows, data), nil
+	}
+	if h.fortran {
+		// The elements are in column-major order,
+		// so the data hold the transpose.
+		t := mat.NewDense(cols, rows, data)
+		m := mat.NewDense(rows, cols, nil)
+		m.Copy(t.T())
+		return m, nil
+	}
+	return mat.NewDense(rows, cols, data), nil
+}
+
+// header holds the description of the array in a .npy file.
+type header struct {
+	order   binary.ByteOrder
+	kind    byte // 'f', 'i' or 'u'
+	size    int  // bytes per element
+	fortran bool
+	shape   []int
+}
+
+// readHeader reads and parses the magic string, version and header of a
+// .npy file.
+func readHeader(r io.Reader) (header, error) {
+	var pre [8]byte
+	_, err := io.ReadFull(r, pre[:])
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return header{}, err
+	}
+	if string(pre[:len(magic)]) != magic {
+		return header{}, errors.New("npy: not a .npy file")
+	}
+	var n int
+	switch major := pre[6]; major {
+	case 1:
+		var b [2]byte
+		_, err = io.ReadFull(r, b[:])
+		n = int(binary.LittleEndian.Uint16(b[:]))
+	case 2, 3:
+		var b [4]byte
+		_, err = io.ReadFull(r, b[:])
+		n = int(binary.LittleEndian.Uint32(b[:]))
+	default:
+		return header{}, fmt.Errorf("npy: unsupported version: %d.%d", major, pre[7])
+	}
+	if err != nil {
+		return header{}, err
+	}
+	buf := make([]byte, n)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		return header{}, err
+	}
+	return parseHeader(string(buf))
+}
+
+// parseHeader parses the Python dictionary literal describing an array.
+func parseHeader(s string) (header, error) {
+	d, err := parseDict(s)
+	if err != nil {
+		return header{}, err
+	}
+
+	var h header
+	descr, ok := d["descr"].(string)
+	if !ok {
+		return header{}, errors.New("npy: missing or invalid descr")
+	}
+	if len(descr) < 3 {
+		return header{}, fmt.Errorf("npy: unsupported descr: %q", descr)
+	}
+	switch descr[0] {
+	case '<', '|':
+		h.order = binary.LittleEndian
+	case '>':
+		h.order = binary.BigEndian
+	default:
+		return header{}, fmt.Errorf("npy: unsupported byte order in descr: %q", descr)
+	}
+	h.kind = descr[1]
+	h.size, err = strconv.Atoi(descr[2:])
+	if err != nil {
+		return header{}, fmt.Errorf("npy: unsupported descr: %q", descr)
+	}
+	switch {
+	case h.kind == 'f' && (h.size == 4 || h.size == 8):
+	case (h.kind == 'i' || h.kind == 'u') && (h.size == 1 || h.size == 2 || h.size == 4 || h.size == 8):
+	default:
+		return header{}, fmt.Errorf("npy: unsupported element type: %q", descr)
+	}
+
+	h.fortran, ok = d["fortran_order"].(bool)
+	if !ok {
+		return header{}, errors.New("npy: missing or invalid fortran_order")
+	}
+	h.shape, ok = d["shape"].([]int)
+	if !ok {
+		return header{}, errors.New("npy: missing or invalid shape")
+	}
+	return h, nil
+}
+
+// parseDict parses the restricted form of Python dictionary literal used
+// in .npy headers. Keys are strings and values are strings, booleans or
+// tuples of non-negative integers.
+func parseDict(s string) (map[string]interface{}, error) {
+	p := dictParser{s: s}
+	d := make(map[string]interface{})
+	if !p.consume('{') {
+		return nil, p.errorf("expected '{'")
+	}
+	for !p.consume('}') {
+		key, err := p.str()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(':') {
+			return nil, p.errorf("expected ':'")
+		}
+		val, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		d[key] = val
+		if !p.consume(',') {
+			if !p.consume('}') {
+				return nil, p.errorf("expected ',' or '}'")
+			}
+			break
+		}
+	}
+	return d, nil
+}
+
+// dictParser is a cursor over a Python dictionary literal.
+type dictParser struct {
+	s   string
+	pos int
+}
+
+func (p *dictParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("npy: invalid header at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *dictParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume advances past c and returns true if c is the next
+// non-space character.
+func (p *dictParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *dictParser) value() (interface{}, error) {
+	p.skipSpace()
+	if p.pos == len(p.s) {
+		return nil, p.errorf("unexpected end of header")
+	}
+	switch c := p.s[p.pos]; {
+	case c == '\'' || c == '"':
+		return p.str()
+	case c == '(':
+		return p.tuple()
+	case strings.HasPrefix(p.s[p.pos:], "True"):
+		p.pos += len("True")
+		return true, nil
+	case strings.HasPrefix(p.s[p.pos:], "False"):
+		p.pos += len("False")
+		return false, nil
+	default:
+		return nil, p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *dictParser) str() (string, error) {
+	p.skipSpace()
+	if p.pos == len(p.s) || (p.s[p.pos] != '\'' && p.s[p.pos] != '"') {
+		return "", p.errorf("expected string")
+	}
+	q := p.s[p.pos]
+	end := strings.IndexByte(p.s[p.pos+1:], q)
+	if end < 0 {
+		return "", p.errorf("unterminated string")
+	}
+	s := p.s[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return s, nil
+}
+
+func (p *dictParser) tuple() ([]int, error) {
+	p.consume('(')
+	shape := []int{}
+	for !p.consume(')') {
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.s) && '0' <= p.s[p.pos] && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		v, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil {
+			return nil, p.errorf("invalid dimension")
+		}
+		// Integers may carry a Python 2 long suffix.
+		if p.pos < len(p.s) && p.s[p.pos] == 'L' {
+			p.pos++
+		}
+		shape = append(shape, v)
+		if !p.consume(',') {
+			if !p.consume(')') {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+			break
+		}
+	}
+	return shape, nil
+}
+
+// readData reads len(dst) elements described by h from r into dst.
+func readData(r io.Reader, h header, dst []float64) error {
+	const chunk = 4096
+	buf := make([]byte, chunk*h.size)
+	for len(dst) != 0 {
+		n := len(dst)
+		if n > chunk {
+			n = chunk
+		}
+		b := buf[:n*h.size]
+		_, err := io.ReadFull(r, b)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		for i := range dst[:n] {
+			dst[i] = decode(h, b[i*h.size:])
+		}
+		dst = dst[n:]
+	}
+	return nil
+}
+
+// decode returns the value of the element described by h at the start of b.
+func decode(h header, b []byte) float64 {
+	switch h.kind {
+	case 'f':
+		if h.size == 4 {
+			return float64(math.Float32frombits(h.order.Uint32(b)))
+		}
+		return math.Float64frombits(h.order.Uint64(b))
+	case 'i':
+		switch h.size {
+		case 1:
+			return float64(int8(b[0]))
+		case 2:
+			return float64(int16(h.order.Uint16(b)))
+		case 4:
+			return float64(int32(h.order.Uint32(b)))
+		default:
+			return float64(int64(h.order.Uint64(b)))
+		}
+	default:
+		switch h.size {
+		case 1:
+			return float64(b[0])
+		case 2:
+			return float64(h.order.Uint16(b))
+		case 4:
+			return float64(h.order.Uint32(b))
+		default:
+			return float64(h.order.Uint64(b))
+		}
+	}
+}
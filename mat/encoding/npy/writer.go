@@ -0,0 +1,87 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package npy
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// headerAlign is the alignment of the start of the array data,
+// as required by the format.
+const headerAlign = 64
+
+// Writer writes matrices and vectors to .npy files.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a new Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// WriteMatrix writes m to the receiver's output as an array of little-endian
+// float64 values in C order. If m is a mat.Vector it is written as a
+// one-dimensional array, and otherwise as a two-dimensional array.
+func (w *Writer) WriteMatrix(m mat.Matrix) error {
+	r, c := m.Dims()
+	v, isVec := m.(mat.Vector)
+	var shape string
+	if isVec {
+		shape = fmt.Sprintf("(%d,)", v.Len())
+	} else {
+		shape = fmt.Sprintf("(%d, %d)", r, c)
+	}
+
+	bw := bufio.NewWriter(w.w)
+	_, err := bw.WriteString(preamble(shape))
+	if err != nil {
+		return err
+	}
+	var b [8]byte
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(m.At(i, j)))
+			_, err = bw.Write(b[:])
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// preamble returns the magic string, version 1.0 and header describing a C
+// order float64 array with the given shape, padded so that the array data
+// is aligned.
+func preamble(shape string) string {
+	dict := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': %s, }", shape)
+	// The preamble is the magic string, two version bytes
+	// and a two byte header length, and the header is
+	// terminated by a newline.
+	pre := len(magic) + 4
+	pad := headerAlign - (pre+len(dict)+1)%headerAlign
+	if pad == headerAlign {
+		pad = 0
+	}
+	n := len(dict) + pad + 1
+
+	var sb strings.Builder
+	sb.WriteString(magic)
+	sb.WriteString("\x01\x00")
+	sb.WriteByte(byte(n))
+	sb.WriteByte(byte(n >> 8))
+	sb.WriteString(dict)
+	sb.WriteString(strings.Repeat(" ", pad))
+	sb.WriteByte('\n')
+	return sb.String()
+}
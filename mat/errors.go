@@ -134,6 +134,8 @@ var (
 	ErrSliceLengthMismatch = Error{"matrix: input slice length mismatch"}
 	ErrNotPSD              = Error{"matrix: input not positive symmetric definite"}
 	ErrFailedEigen         = Error{"matrix: eigendecomposition not successful"}
+	ErrNegativeEigen       = Error{"matrix: eigenvalue on the closed negative real axis"}
+	ErrFailedFunction      = Error{"matrix: matrix function evaluation not successful"}
 )
 
 // ErrorStack represents matrix handling errors that have been recovered by Maybe wrappers.
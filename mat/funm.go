@@ -0,0 +1,443 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+// Sqrtm calculates the principal square root of the matrix a, the unique
+// square root whose eigenvalues have positive real part, placing the result
+// in the receiver. Sqrtm will panic with ErrShape if a is not square.
+//
+// The principal square root is real and exists only if a has no eigenvalues
+// on the closed negative real axis. Sqrtm returns ErrNegativeEigen if this is
+// not the case, and ErrFailedEigen if the Schur factorization of a could not
+// be computed. The receiver is not modified when an error is returned.
+func (m *Dense) Sqrtm(a Matrix) error {
+	// The implementation used here is from Functions of Matrices: Theory and Computation
+	// Chapter 6, Algorithm 6.3. https://doi.org/10.1137/1.9780898717778.ch6
+	t, z, err := realSchur(a)
+	if err != nil {
+		return err
+	}
+	if onNegativeRealAxis(t) {
+		return ErrNegativeEigen
+	}
+	m.setRealSimilar(z, sqrtTri(t))
+	return nil
+}
+
+// Logm calculates the principal logarithm of the matrix a, the unique
+// logarithm whose eigenvalues have imaginary part in (-π, π), placing the
+// result in the receiver. Logm will panic with ErrShape if a is not square.
+//
+// The principal logarithm is real and exists only if a has no eigenvalues on
+// the closed negative real axis. Logm returns ErrNegativeEigen if this is not
+// the case, and ErrFailedEigen if the Schur factorization of a could not be
+// computed. The receiver is not modified when an error is returned.
+func (m *Dense) Logm(a Matrix) error {
+	// The implementation used here is the inverse scaling and squaring
+	// method from Functions of Matrices: Theory and Computation
+	// Chapter 11, Algorithm 11.10. https://doi.org/10.1137/1.9780898717778.ch11
+	t, z, err := realSchur(a)
+	if err != nil {
+		return err
+	}
+	if onNegativeRealAxis(t) {
+		return ErrNegativeEigen
+	}
+	m.setRealSimilar(z, logTri(t))
+	return nil
+}
+
+// Funm calculates f(A) for the matrix a and the scalar function f by the
+// Schur–Parlett method, placing the result in the receiver. Funm will panic
+// with ErrShape if a is not square.
+//
+// The call f(z, k) must return the k-th derivative of the scalar function at
+// z. Derivatives with k > 0 are only requested for eigenvalues of a that lie
+// within 0.1 of each other, so f may ignore k if the eigenvalues of a are known
+// to be well separated. The scalar function must be analytic on a region
+// containing the eigenvalues of a and satisfy f(z̄) = conj(f(z)) for f(A) to be
+// real; the imaginary part of the computed result is discarded.
+//
+// Funm returns ErrFailedEigen if the Schur factorization of a could not be
+// computed and ErrFailedFunction if the Taylor series of f on a cluster of
+// eigenvalues did not converge. The receiver is not modified when an error is
+// returned.
+func (m *Dense) Funm(a Matrix, f func(z complex128, k int) complex128) error {
+	// The implementation used here is from Functions of Matrices: Theory and Computation
+	// Chapter 9, Algorithm 9.6. https://doi.org/10.1137/1.9780898717778.ch9
+	t, z, err := realSchur(a)
+	if err != nil {
+		return err
+	}
+	blocks := reorderClusters(t, z, 0.1)
+	ft, ok := parlett(t, blocks, f)
+	if !ok {
+		return ErrFailedFunction
+	}
+	m.setRealSimilar(z, ft)
+	return nil
+}
+
+// realSchur returns the complex Schur factorization A = Z * T * Zᴴ of the
+// real square matrix a.
+func realSchur(a Matrix) (t, z *CDense, err error) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrShape)
+	}
+	ac := NewCDense(r, r, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			ac.set(i, j, complex(a.At(i, j), 0))
+		}
+	}
+	var s CSchur
+	if !s.Factorize(ac, true) {
+		return nil, nil, ErrFailedEigen
+	}
+	return s.t, s.z, nil
+}
+
+// setRealSimilar sets the receiver to the real part of Z * F * Zᴴ.
+func (m *Dense) setRealSimilar(z, f *CDense) {
+	n, _ := z.Dims()
+	var zf, zfz CDense
+	zf.Mul(z, f)
+	zfz.Mul(&zf, z.H())
+	m.reuseAs(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.set(i, j, real(zfz.at(i, j)))
+		}
+	}
+}
+
+// onNegativeRealAxis returns whether any diagonal element of the upper
+// triangular matrix t lies on the closed negative real axis. Imaginary parts
+// at the rounding level of the diagonal of t are ignored since the eigenvalues
+// of a real matrix are computed in complex arithmetic.
+func onNegativeRealAxis(t *CDense) bool {
+	n := t.mat.Rows
+	var norm float64
+	for i := 0; i < n; i++ {
+		norm = math.Max(norm, cmplx.Abs(t.at(i, i)))
+	}
+	tol := 100 * float64(n) * machEps * norm
+	for i := 0; i < n; i++ {
+		v := t.at(i, i)
+		if real(v) <= 0 && math.Abs(imag(v)) <= tol {
+			return true
+		}
+	}
+	return false
+}
+
+// sqrtTri returns the principal square root of the upper triangular matrix t
+// computed by the recurrence of Björck and Hammarling.
+func sqrtTri(t *CDense) *CDense {
+	n := t.mat.Rows
+	r := NewCDense(n, n, nil)
+	for j := 0; j < n; j++ {
+		r.set(j, j, cmplx.Sqrt(t.at(j, j)))
+		for i := j - 1; i >= 0; i-- {
+			s := t.at(i, j)
+			for k := i + 1; k < j; k++ {
+				s -= r.at(i, k) * r.at(k, j)
+			}
+			r.set(i, j, s/(r.at(i, i)+r.at(j, j)))
+		}
+	}
+	return r
+}
+
+// logTri returns the principal logarithm of the upper triangular matrix t.
+func logTri(t *CDense) *CDense {
+	// theta7 is the bound on ‖T - I‖₁ for which the [7/7] Padé
+	// approximant of log(I + X) is accurate to double precision.
+	const theta7 = 0.264
+
+	// Nodes and weights of the 7-point Gauss–Legendre rule
+	// on [0, 1]. The rule applied to the integral
+	//
+	//	log(I + X) = ∫₀¹ X * (I + s*X)⁻¹ ds
+	//
+	// is the [7/7] Padé approximant.
+	nodes := [...]float64{
+		0.025446043828620757, 0.12923440720030277, 0.2970774243113014, 0.5,
+		0.7029225756886986, 0.8707655927996972, 0.9745539561713792,
+	}
+	weights := [...]float64{
+		0.06474248308443485, 0.13985269574463832, 0.19091502525255946, 0.20897959183673470,
+		0.19091502525255946, 0.13985269574463832, 0.06474248308443485,
+	}
+
+	n := t.mat.Rows
+	x := cdenseCopyOf(t)
+	for i := 0; i < n; i++ {
+		x.set(i, i, x.at(i, i)-1)
+	}
+
+	// Take square roots until T^(1/2^s) is close to I.
+	const maxRoots = 64
+	var s int
+	for ; s < maxRoots && cNorm1(x) > theta7; s++ {
+		t = sqrtTri(t)
+		x = cdenseCopyOf(t)
+		for i := 0; i < n; i++ {
+			x.set(i, i, x.at(i, i)-1)
+		}
+	}
+
+	l := NewCDense(n, n, nil)
+	y := NewCDense(n, n, nil)
+	d := NewCDense(n, n, nil)
+	tri := cblas128.Triangular{
+		N:      n,
+		Stride: d.mat.Stride,
+		Data:   d.mat.Data,
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+	}
+	for k, node := range nodes {
+		// Y = (I + s_k*X)⁻¹ * X.
+		d.Scale(complex(node, 0), x)
+		for i := 0; i < n; i++ {
+			d.set(i, i, d.at(i, i)+1)
+		}
+		y.Copy(x)
+		cblas128.Trsm(blas.Left, blas.NoTrans, 1, tri, y.mat)
+		for i := range l.mat.Data {
+			l.mat.Data[i] += complex(weights[k], 0) * y.mat.Data[i]
+		}
+	}
+	l.Scale(complex(math.Ldexp(1, s), 0), l)
+	return l
+}
+
+// cNorm1 returns the maximum absolute column sum of a.
+func cNorm1(a *CDense) float64 {
+	var norm float64
+	for j := 0; j < a.mat.Cols; j++ {
+		var s float64
+		for i := 0; i < a.mat.Rows; i++ {
+			s += cmplx.Abs(a.at(i, j))
+		}
+		norm = math.Max(norm, s)
+	}
+	return norm
+}
+
+// reorderClusters partitions the eigenvalues on the diagonal of the upper
+// triangular matrix t into clusters in which each eigenvalue is within delta
+// of another, and reorders the Schur form A = Z * T * Zᴴ in place so that the
+// clusters occupy contiguous diagonal blocks. reorderClusters returns the
+// starting indices of the blocks followed by n.
+func reorderClusters(t, z *CDense, delta float64) []int {
+	n := t.mat.Rows
+
+	// Find the clusters as the connected components of the
+	// graph joining eigenvalues closer than delta.
+	cluster := make([]int, n)
+	for i := range cluster {
+		cluster[i] = -1
+	}
+	var nc int
+	for i := 0; i < n; i++ {
+		if cluster[i] >= 0 {
+			continue
+		}
+		cluster[i] = nc
+		stack := []int{i}
+		for len(stack) > 0 {
+			p := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for q := 0; q < n; q++ {
+				if cluster[q] < 0 && cmplx.Abs(t.at(p, p)-t.at(q, q)) <= delta {
+					cluster[q] = nc
+					stack = append(stack, q)
+				}
+			}
+		}
+		nc++
+	}
+
+	// Bubble the eigenvalues into cluster order with swaps
+	// of adjacent diagonal elements of the Schur form.
+	for swapped := true; swapped; {
+		swapped = false
+		for k := 0; k < n-1; k++ {
+			if cluster[k] > cluster[k+1] {
+				swapSchur(t, z, k)
+				cluster[k], cluster[k+1] = cluster[k+1], cluster[k]
+				swapped = true
+			}
+		}
+	}
+
+	blocks := []int{0}
+	for k := 1; k < n; k++ {
+		if cluster[k] != cluster[k-1] {
+			blocks = append(blocks, k)
+		}
+	}
+	return append(blocks, n)
+}
+
+// swapSchur swaps the distinct diagonal elements k and k+1 of the upper
+// triangular matrix t with a unitary similarity transformation, accumulating
+// the transformation into z.
+func swapSchur(t, z *CDense, k int) {
+	t11 := t.at(k, k)
+	t22 := t.at(k+1, k+1)
+	c, s := cGivens(t.at(k, k+1), t22-t11)
+	cGivensLeft(t, k, k+2, c, s)
+	cGivensRight(t, k, k, c, s)
+	t.set(k, k, t22)
+	t.set(k+1, k+1, t11)
+	cGivensRight(z, k, z.mat.Rows, c, s)
+}
+
+// parlett returns f(T) for the upper triangular matrix t with eigenvalue
+// clusters in the diagonal blocks given by blocks. The diagonal blocks of
+// f(T) are evaluated by Taylor series and the off-diagonal blocks by the
+// block Parlett recurrence. parlett returns whether the Taylor series
+// converged.
+func parlett(t *CDense, blocks []int, f func(z complex128, k int) complex128) (*CDense, bool) {
+	n := t.mat.Rows
+	ft := NewCDense(n, n, nil)
+	for b := 0; b < len(blocks)-1; b++ {
+		if !taylorBlock(ft, t, blocks[b], blocks[b+1], f) {
+			return nil, false
+		}
+	}
+
+	// Solve the Sylvester equations
+	//
+	//	T_ii*F_ij - F_ij*T_jj = F_ii*T_ij - T_ij*F_jj + Σ_k (F_ik*T_kj - T_ik*F_kj)
+	//
+	// for the blocks above the diagonal, column of blocks by column of blocks
+	// from the diagonal upwards.
+	for bj := 1; bj < len(blocks)-1; bj++ {
+		j0, j1 := blocks[bj], blocks[bj+1]
+		for bi := bj - 1; bi >= 0; bi-- {
+			i0, i1 := blocks[bi], blocks[bi+1]
+			rhs := NewCDense(i1-i0, j1-j0, nil)
+			for i := i0; i < i1; i++ {
+				for j := j0; j < j1; j++ {
+					var v complex128
+					for k := i0; k < i1; k++ {
+						v += ft.at(i, k) * t.at(k, j)
+					}
+					for k := j0; k < j1; k++ {
+						v -= t.at(i, k) * ft.at(k, j)
+					}
+					for k := i1; k < j0; k++ {
+						v += ft.at(i, k)*t.at(k, j) - t.at(i, k)*ft.at(k, j)
+					}
+					rhs.set(i-i0, j-j0, v)
+				}
+			}
+
+			// Solve by forward substitution over the columns of
+			// T_jj and back substitution over the rows of T_ii.
+			for j := j0; j < j1; j++ {
+				for i := i1 - 1; i >= i0; i-- {
+					v := rhs.at(i-i0, j-j0)
+					for k := j0; k < j; k++ {
+						v += ft.at(i, k) * t.at(k, j)
+					}
+					for k := i + 1; k < i1; k++ {
+						v -= t.at(i, k) * ft.at(k, j)
+					}
+					ft.set(i, j, v/(t.at(i, i)-t.at(j, j)))
+				}
+			}
+		}
+	}
+	return ft, true
+}
+
+// taylorBlock sets the diagonal block [lo, hi) of ft to f of the same block
+// of the upper triangular matrix t by a Taylor series about the mean of its
+// eigenvalues. taylorBlock returns whether the series converged.
+func taylorBlock(ft, t *CDense, lo, hi int, f func(z complex128, k int) complex128) bool {
+	const maxTerms = 250
+
+	p := hi - lo
+	if p == 1 {
+		ft.set(lo, lo, f(t.at(lo, lo), 0))
+		return true
+	}
+	var sigma complex128
+	for i := lo; i < hi; i++ {
+		sigma += t.at(i, i)
+	}
+	sigma /= complex(float64(p), 0)
+
+	// M = T_b - σI, and the terms of the series are
+	// f⁽ᵏ⁾(σ) * Mᵏ/k!, accumulated into F.
+	mb := NewCDense(p, p, nil)
+	for i := 0; i < p; i++ {
+		for j := i; j < p; j++ {
+			mb.set(i, j, t.at(lo+i, lo+j))
+		}
+		mb.set(i, i, mb.at(i, i)-sigma)
+	}
+	pow := NewCDense(p, p, nil)
+	fb := NewCDense(p, p, nil)
+	f0 := f(sigma, 0)
+	for i := 0; i < p; i++ {
+		pow.set(i, i, 1)
+		fb.set(i, i, f0)
+	}
+	var next CDense
+	var small int
+	for k := 1; k <= maxTerms; k++ {
+		next.Mul(pow, mb)
+		next.Scale(complex(1/float64(k), 0), &next)
+		pow.Copy(&next)
+		if cNormInf(pow) == 0 {
+			break
+		}
+		dk := f(sigma, k)
+		var tnorm float64
+		for i := range fb.mat.Data {
+			term := dk * pow.mat.Data[i]
+			fb.mat.Data[i] += term
+			tnorm = math.Max(tnorm, cmplx.Abs(term))
+		}
+		if math.IsInf(tnorm, 0) || math.IsNaN(tnorm) {
+			return false
+		}
+		// Stop when the terms have been negligible
+		// for as many terms as the block size, since
+		// the terms need not decrease monotonically.
+		if tnorm <= machEps*cNormInf(fb) {
+			small++
+			if small >= p {
+				break
+			}
+		} else {
+			small = 0
+		}
+		if k == maxTerms {
+			return false
+		}
+	}
+	for i := 0; i < p; i++ {
+		for j := i; j < p; j++ {
+			ft.set(lo+i, lo+j, fb.at(i, j))
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// funmTestMatrices returns matrices with no eigenvalues on the closed
+// negative real axis.
+func funmTestMatrices(rnd *rand.Rand) map[string]*Dense {
+	spd := NewDense(8, 8, nil)
+	b := NewDense(8, 8, nil)
+	for i := range b.mat.Data {
+		b.mat.Data[i] = rnd.NormFloat64()
+	}
+	spd.Mul(b, b.T())
+	for i := 0; i < 8; i++ {
+		spd.set(i, i, spd.at(i, i)+1)
+	}
+	shifted := NewDense(10, 10, nil)
+	for i := range shifted.mat.Data {
+		shifted.mat.Data[i] = rnd.NormFloat64() / 4
+	}
+	for i := 0; i < 10; i++ {
+		shifted.set(i, i, shifted.at(i, i)+2)
+	}
+	return map[string]*Dense{
+		"1×1":      NewDense(1, 1, []float64{4}),
+		"spd":      spd,
+		"shifted":  shifted,
+		"rotation": NewDense(2, 2, []float64{1, -2, 2, 1}),
+		"jordan": NewDense(4, 4, []float64{
+			3, 1, 0, 0,
+			0, 3, 1, 0,
+			0, 0, 3, 0,
+			0, 0, 0, 0.5,
+		}),
+	}
+}
+
+func TestSqrtm(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for name, a := range funmTestMatrices(rnd) {
+		var r, rr Dense
+		if err := r.Sqrtm(a); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		rr.Mul(&r, &r)
+		if !EqualApprox(&rr, a, 1e-10) {
+			t.Errorf("%s: R*R != A", name)
+		}
+		var eg Eigen
+		eg.Factorize(&r, EigenNone)
+		for _, v := range eg.Values(nil) {
+			if real(v) <= 0 {
+				t.Errorf("%s: square root is not principal: eigenvalue %v", name, v)
+			}
+		}
+	}
+
+	neg := NewDense(2, 2, []float64{-1, 0, 0, 4})
+	var r Dense
+	if err := r.Sqrtm(neg); err != ErrNegativeEigen {
+		t.Errorf("unexpected error for negative eigenvalue: got %v want %v", err, ErrNegativeEigen)
+	}
+	if err := r.Sqrtm(NewDense(2, 2, nil)); err != ErrNegativeEigen {
+		t.Errorf("unexpected error for zero eigenvalue: got %v want %v", err, ErrNegativeEigen)
+	}
+	if panicked, _ := panics(func() { r.Sqrtm(NewDense(2, 3, nil)) }); !panicked {
+		t.Error("expected panic for non-square matrix")
+	}
+}
+
+func TestLogm(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for name, a := range funmTestMatrices(rnd) {
+		var l, el Dense
+		if err := l.Logm(a); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		el.Exp(&l)
+		if !EqualApprox(&el, a, 1e-10) {
+			t.Errorf("%s: exp(log(A)) != A", name)
+		}
+	}
+
+	// log(exp(B)) = B when the eigenvalues of B have
+	// imaginary parts in (-π, π).
+	b := NewDense(6, 6, nil)
+	for i := range b.mat.Data {
+		b.mat.Data[i] = rnd.NormFloat64() / 3
+	}
+	var eb, l Dense
+	eb.Exp(b)
+	if err := l.Logm(&eb); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !EqualApprox(&l, b, 1e-10) {
+		t.Error("log(exp(B)) != B")
+	}
+
+	if err := l.Logm(NewDense(1, 1, []float64{-2})); err != ErrNegativeEigen {
+		t.Errorf("unexpected error for negative eigenvalue: got %v want %v", err, ErrNegativeEigen)
+	}
+}
+
+func TestFunm(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	exp := func(z complex128, _ int) complex128 { return cmplx.Exp(z) }
+	for name, a := range funmTestMatrices(rnd) {
+		var f, want Dense
+		if err := f.Funm(a, exp); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		want.Exp(a)
+		if !EqualApprox(&f, &want, 1e-10) {
+			t.Errorf("%s: Funm(exp) != Exp:\ngot  %v\nwant %v", name, Formatted(&f), Formatted(&want))
+		}
+	}
+
+	// The square root of a matrix with clustered
+	// eigenvalues through the Taylor series.
+	a := NewDense(5, 5, []float64{
+		4, 1, 2, 0, 1,
+		0, 4.01, 1, 3, 0,
+		0, 0, 4.05, 1, 1,
+		0, 0, 0, 9, 2,
+		0, 0, 0, 0, 9.02,
+	})
+	sqrt := func(z complex128, k int) complex128 {
+		// The k-th derivative of z^(1/2).
+		c := complex(1, 0)
+		for i := 0; i < k; i++ {
+			c *= complex(0.5-float64(i), 0)
+		}
+		return c * cmplx.Pow(z, complex(0.5-float64(k), 0))
+	}
+	var f, ff Dense
+	if err := f.Funm(a, sqrt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ff.Mul(&f, &f)
+	if !EqualApprox(&ff, a, 1e-10) {
+		t.Errorf("clustered square root: F*F != A")
+	}
+	var r Dense
+	r.Sqrtm(a)
+	if !EqualApprox(&f, &r, 1e-10) {
+		t.Errorf("clustered square root: Funm != Sqrtm")
+	}
+
+	// The Taylor series of a function with a pole closer to
+	// the mean of a cluster than its eigenvalues diverges.
+	pole := func(z complex128, k int) complex128 {
+		return complex(math.Pow(-1, float64(k))*math.Gamma(float64(k+1)), 0) / cmplx.Pow(z-1.005, complex(float64(k+1), 0))
+	}
+	jordan := NewDense(3, 3, []float64{1, 1, 0, 0, 1, 1, 0, 0, 1.01})
+	var g Dense
+	if err := g.Funm(jordan, pole); err != ErrFailedFunction {
+		t.Errorf("unexpected error for divergent series: got %v want %v", err, ErrFailedFunction)
+	}
+}
@@ -0,0 +1,632 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	badGSchur        = "mat: invalid generalized Schur factorization"
+	badGSchurVectors = "mat: generalized Schur vectors not computed"
+)
+
+// GSchur is a type for creating and using the generalized Schur factorization
+// of a pair of square complex matrices, the pencil (A, B).
+//
+// The generalized Schur factorization has the form
+//
+//	A = Q * S * Zᴴ
+//	B = Q * T * Zᴴ
+//
+// where Q and Z are unitary and S and T are upper triangular with the diagonal
+// of T real and non-negative. The generalized eigenvalues λ of the pencil,
+// the values for which A - λB is singular, are the ratios α/β of the diagonal
+// elements α of S and β of T. An eigenvalue is infinite if β is zero, which
+// is the case when B is singular.
+type GSchur struct {
+	s, t *CDense
+	q, z *CDense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (g *GSchur) succFact() bool {
+	return g.s != nil
+}
+
+// Factorize computes the generalized Schur factorization of the pencil (A, B)
+// of n×n complex matrices. The generalized Schur vectors, the columns of Q and
+// Z, are only computed if vectors is true.
+//
+// The factorization is computed by reduction of (A, B) to upper Hessenberg
+// and upper triangular form followed by single-shift complex QZ iterations.
+// Factorize returns whether the iterations converged. If the factorization
+// failed, routines that require a successful factorization will panic.
+func (g *GSchur) Factorize(a, b CMatrix, vectors bool) (ok bool) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	if br, bc := b.Dims(); br != r || bc != c {
+		panic(ErrShape)
+	}
+	g.s = nil
+	g.t = nil
+	g.q = nil
+	g.z = nil
+
+	n := r
+	s := cdenseCopyOf(a)
+	t := cdenseCopyOf(b)
+	var q, z *CDense
+	if vectors {
+		q = NewCDense(n, n, nil)
+		z = NewCDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			q.set(i, i, 1)
+			z.set(i, i, 1)
+		}
+	}
+
+	// Reduce B to upper triangular form with
+	// the QR factorization B = Q * T.
+	for j := 0; j < n-1; j++ {
+		tau := cReflector(t, j, j)
+		v := columnReflector(t, j, j)
+		cReflectLeft(cmplx.Conj(tau), v, t, j, j+1)
+		cReflectLeft(cmplx.Conj(tau), v, s, j, 0)
+		if vectors {
+			cReflectRight(tau, v, q, 0, j)
+		}
+		for i := j + 1; i < n; i++ {
+			t.set(i, j, 0)
+		}
+	}
+
+	// Reduce A to upper Hessenberg form while keeping B
+	// upper triangular, zeroing the elements below the
+	// subdiagonal of A with rotations from the left and
+	// restoring B with rotations from the right.
+	for j := 0; j < n-2; j++ {
+		for i := n - 1; i > j+1; i-- {
+			c, sn := cGivens(s.at(i-1, j), s.at(i, j))
+			cGivensLeft(s, i-1, j, c, sn)
+			s.set(i, j, 0)
+			cGivensLeft(t, i-1, i-1, c, sn)
+			if vectors {
+				cGivensRight(q, i-1, n, c, sn)
+			}
+			gRotateCols(t, s, z, i, i, i+1, n)
+		}
+	}
+
+	if !gHessenbergQZ(s, t, q, z) {
+		return false
+	}
+
+	// Make the diagonal of T real and non-negative.
+	for j := 0; j < n; j++ {
+		gRealDiag(s, t, z, j)
+	}
+
+	g.s = s
+	g.t = t
+	g.q = q
+	g.z = z
+	return true
+}
+
+// gRealDiag scales the column j of the upper triangular matrices s and t,
+// and of z if it is not nil, by a unit complex number so that t[j, j] is
+// real and non-negative.
+func gRealDiag(s, t, z *CDense, j int) {
+	tjj := t.at(j, j)
+	if imag(tjj) == 0 && real(tjj) >= 0 {
+		return
+	}
+	sgn := cmplx.Conj(tjj) / complex(cmplx.Abs(tjj), 0)
+	for i := 0; i <= j; i++ {
+		s.set(i, j, s.at(i, j)*sgn)
+		t.set(i, j, t.at(i, j)*sgn)
+	}
+	t.set(j, j, complex(real(t.at(j, j)), 0))
+	if z != nil {
+		for i := 0; i < z.mat.Rows; i++ {
+			z.set(i, j, z.at(i, j)*sgn)
+		}
+	}
+}
+
+// gRotateCols zeros the element x[r, k-1] with a rotation of the columns k-1
+// and k applied from the right to the first xRows rows of x, to the first
+// yRows rows of y and, if z is not nil, to z.
+func gRotateCols(x, y, z *CDense, r, k, xRows, yRows int) {
+	c, s := cGivens(x.at(r, k), x.at(r, k-1))
+	cGivensRight(x, k-1, xRows, c, -s)
+	x.set(r, k-1, 0)
+	cGivensRight(y, k-1, yRows, c, -s)
+	if z != nil {
+		cGivensRight(z, k-1, z.mat.Rows, c, -s)
+	}
+}
+
+// gHessenbergQZ reduces the upper Hessenberg and upper triangular pair (h, t)
+// to upper triangular form by single-shift QZ iterations. If q and z are not
+// nil, the applied unitary transformations are accumulated into q and z from
+// the right. gHessenbergQZ returns whether the iterations converged.
+func gHessenbergQZ(h, t, q, z *CDense) (ok bool) {
+	const maxIter = 30
+
+	n := h.mat.Rows
+	anorm := cNormInf(h)
+	btol := machEps * cNormInf(t)
+	small := func(l int) bool {
+		tst := cmplx.Abs(h.at(l-1, l-1)) + cmplx.Abs(h.at(l, l))
+		if tst == 0 {
+			tst = anorm
+		}
+		return cmplx.Abs(h.at(l, l-1)) <= machEps*tst
+	}
+
+	iter := 0
+outer:
+	for hi := n - 1; hi > 0; {
+		// Check for deflation at the bottom of the active block.
+		if small(hi) {
+			h.set(hi, hi-1, 0)
+			hi--
+			iter = 0
+			continue
+		}
+		if cmplx.Abs(t.at(hi, hi)) <= btol {
+			// An infinite eigenvalue has converged.
+			t.set(hi, hi, 0)
+			gRotateCols(h, t, z, hi, hi, hi+1, hi)
+			hi--
+			iter = 0
+			continue
+		}
+
+		// Look for a single small subdiagonal element of h to
+		// split the active block [l, hi], or a negligible
+		// diagonal element of t to chase to the bottom.
+		l := hi - 1
+		for ; l > 0; l-- {
+			if cmplx.Abs(t.at(l, l)) <= btol {
+				break
+			}
+			if small(l) {
+				h.set(l, l-1, 0)
+				break
+			}
+		}
+		if cmplx.Abs(t.at(l, l)) <= btol {
+			// Chase the zero diagonal element of t down
+			// to hi where it can be deflated.
+			t.set(l, l, 0)
+			for k := l; k < hi; k++ {
+				c, s := cGivens(t.at(k, k+1), t.at(k+1, k+1))
+				cGivensLeft(t, k, k+1, c, s)
+				t.set(k+1, k+1, 0)
+				cGivensLeft(h, k, max(k-1, 0), c, s)
+				if q != nil {
+					cGivensRight(q, k, n, c, s)
+				}
+				if k > 0 {
+					// Remove the fill-in at h[k+1, k-1].
+					gRotateCols(h, t, z, k+1, k, k+2, k+1)
+				}
+			}
+			continue outer
+		}
+
+		iter++
+		if iter > maxIter*(hi-l+1) {
+			return false
+		}
+
+		// Compute the shift from the eigenvalues of the trailing 2×2
+		// block of T⁻¹ * H, using the one closest to the last diagonal
+		// element as the Wilkinson shift.
+		t11, t12, t22 := t.at(hi-1, hi-1), t.at(hi-1, hi), t.at(hi, hi)
+		h11, h12 := h.at(hi-1, hi-1), h.at(hi-1, hi)
+		h21, h22 := h.at(hi, hi-1), h.at(hi, hi)
+		m21 := h21 / t22
+		m22 := h22 / t22
+		m11 := (h11 - t12*m21) / t11
+		m12 := (h12 - t12*m22) / t11
+		var mu complex128
+		if iter%10 == 0 {
+			// Use an exceptional shift to break cycles.
+			mu = m22 + complex(0.75*cmplx.Abs(m21), 0)
+		} else {
+			half := (m11 - m22) / 2
+			disc := cmplx.Sqrt(half*half + m12*m21)
+			mu = m22 + half - disc
+			if mu2 := m22 + half + disc; cmplx.Abs(mu2-m22) < cmplx.Abs(mu-m22) {
+				mu = mu2
+			}
+		}
+
+		// Chase the bulge introduced by the shift down the
+		// subdiagonal of h, restoring t to triangular form
+		// after each rotation.
+		x := h.at(l, l) - mu*t.at(l, l)
+		y := h.at(l+1, l)
+		for k := l; k < hi; k++ {
+			if k > l {
+				x = h.at(k, k-1)
+				y = h.at(k+1, k-1)
+			}
+			c, s := cGivens(x, y)
+			c0 := k - 1
+			if k == l {
+				c0 = l
+			}
+			cGivensLeft(h, k, c0, c, s)
+			if k > l {
+				h.set(k+1, k-1, 0)
+			}
+			cGivensLeft(t, k, k, c, s)
+			if q != nil {
+				cGivensRight(q, k, n, c, s)
+			}
+			gRotateCols(t, h, z, k+1, k+1, k+2, min(k+3, hi+1))
+		}
+	}
+	return true
+}
+
+// AlphaBeta returns the diagonal elements α of S and β of T of the factorized
+// pencil, in the order in which they appear in the generalized Schur form. The
+// generalized eigenvalues of the pencil are α/β.
+//
+// If the input slices are non-nil, the values will be stored in-place into
+// the slices. In this case, the slices must have length n, and AlphaBeta will
+// panic with ErrSliceLengthMismatch otherwise. If an input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// AlphaBeta will panic if the receiver does not contain a successful
+// factorization.
+func (g *GSchur) AlphaBeta(alpha []complex128, beta []float64) ([]complex128, []float64) {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	n := g.s.mat.Rows
+	if alpha == nil {
+		alpha = make([]complex128, n)
+	}
+	if beta == nil {
+		beta = make([]float64, n)
+	}
+	if len(alpha) != n || len(beta) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	for i := 0; i < n; i++ {
+		alpha[i] = g.s.at(i, i)
+		beta[i] = real(g.t.at(i, i))
+	}
+	return alpha, beta
+}
+
+// Values returns the generalized eigenvalues α/β of the factorized pencil in
+// the order in which they appear in the generalized Schur form. Infinite
+// eigenvalues are returned as cmplx.Inf() and the eigenvalues of a singular
+// pencil, for which both α and β are zero, as cmplx.NaN().
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length n, and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (g *GSchur) Values(v []complex128) []complex128 {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	n := g.s.mat.Rows
+	if v == nil {
+		v = make([]complex128, n)
+	}
+	if len(v) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	for i := range v {
+		alpha, beta := g.s.at(i, i), real(g.t.at(i, i))
+		switch {
+		case beta != 0:
+			v[i] = alpha / complex(beta, 0)
+		case alpha != 0:
+			v[i] = cmplx.Inf()
+		default:
+			v[i] = cmplx.NaN()
+		}
+	}
+	return v
+}
+
+// STo extracts the upper triangular generalized Schur form S of A from the
+// factorization. If dst is nil, a new matrix is allocated. The resulting S
+// matrix is returned.
+// STo will panic if the receiver does not contain a successful factorization.
+func (g *GSchur) STo(dst *CDense) *CDense {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	return gCopyTo(dst, g.s)
+}
+
+// TTo extracts the upper triangular generalized Schur form T of B from the
+// factorization. If dst is nil, a new matrix is allocated. The resulting T
+// matrix is returned.
+// TTo will panic if the receiver does not contain a successful factorization.
+func (g *GSchur) TTo(dst *CDense) *CDense {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	return gCopyTo(dst, g.t)
+}
+
+// QTo extracts the unitary matrix of left generalized Schur vectors Q from
+// the factorization. If dst is nil, a new matrix is allocated. The resulting
+// Q matrix is returned.
+// QTo will panic if the receiver does not contain a successful factorization
+// or if the Schur vectors were not computed.
+func (g *GSchur) QTo(dst *CDense) *CDense {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	if g.q == nil {
+		panic(badGSchurVectors)
+	}
+	return gCopyTo(dst, g.q)
+}
+
+// ZTo extracts the unitary matrix of right generalized Schur vectors Z from
+// the factorization. If dst is nil, a new matrix is allocated. The resulting
+// Z matrix is returned.
+// ZTo will panic if the receiver does not contain a successful factorization
+// or if the Schur vectors were not computed.
+func (g *GSchur) ZTo(dst *CDense) *CDense {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	if g.z == nil {
+		panic(badGSchurVectors)
+	}
+	return gCopyTo(dst, g.z)
+}
+
+// gCopyTo copies the square matrix m into dst, allocating a new matrix
+// if dst is nil, and returns the result.
+func gCopyTo(dst, m *CDense) *CDense {
+	n := m.mat.Rows
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAs(n, n)
+	}
+	dst.Copy(m)
+	return dst
+}
+
+// VectorsTo stores the right generalized eigenvectors of the factorized pencil
+// into the columns of dst. The kth column of dst is the eigenvector x satisfying
+//
+//	β_k * A * x = α_k * B * x
+//
+// for the kth pair of AlphaBeta, normalized to unit Euclidean norm. If dst is
+// nil, a new matrix is allocated. The resulting matrix is returned.
+//
+// VectorsTo will panic if the receiver does not contain a successful
+// factorization or if the Schur vectors were not computed.
+func (g *GSchur) VectorsTo(dst *CDense) *CDense {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	if g.z == nil {
+		panic(badGSchurVectors)
+	}
+	n := g.s.mat.Rows
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAsZeroed(n, n)
+	}
+	small := machEps * math.Max(cNormInf(g.s), cNormInf(g.t))
+	if small == 0 {
+		small = math.SmallestNonzeroFloat64
+	}
+	y := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		alpha, beta := g.scaledPair(k)
+
+		// Solve the upper triangular system
+		//  (β*S - α*T)[:k+1, :k+1] * y = 0
+		// with y[k] = 1 by back substitution.
+		zeroC(y)
+		y[k] = 1
+		for j := k - 1; j >= 0; j-- {
+			var sum complex128
+			for i := j + 1; i <= k; i++ {
+				sum += (beta*g.s.at(j, i) - alpha*g.t.at(j, i)) * y[i]
+			}
+			d := beta*g.s.at(j, j) - alpha*g.t.at(j, j)
+			if cmplx.Abs(d) < small {
+				d = complex(small, 0)
+			}
+			y[j] = -sum / d
+		}
+
+		// Transform back with x = Z * y.
+		var norm float64
+		for i := 0; i < n; i++ {
+			var x complex128
+			for j := 0; j <= k; j++ {
+				x += g.z.at(i, j) * y[j]
+			}
+			dst.set(i, k, x)
+			norm = math.Hypot(norm, cmplx.Abs(x))
+		}
+		for i := 0; i < n; i++ {
+			dst.set(i, k, dst.at(i, k)/complex(norm, 0))
+		}
+	}
+	return dst
+}
+
+// LeftVectorsTo stores the left generalized eigenvectors of the factorized
+// pencil into the columns of dst. The kth column of dst is the eigenvector u
+// satisfying
+//
+//	β_k * uᴴ * A = α_k * uᴴ * B
+//
+// for the kth pair of AlphaBeta, normalized to unit Euclidean norm. If dst is
+// nil, a new matrix is allocated. The resulting matrix is returned.
+//
+// LeftVectorsTo will panic if the receiver does not contain a successful
+// factorization or if the Schur vectors were not computed.
+func (g *GSchur) LeftVectorsTo(dst *CDense) *CDense {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	if g.q == nil {
+		panic(badGSchurVectors)
+	}
+	n := g.s.mat.Rows
+	if dst == nil {
+		dst = NewCDense(n, n, nil)
+	} else {
+		dst.reuseAsZeroed(n, n)
+	}
+	small := machEps * math.Max(cNormInf(g.s), cNormInf(g.t))
+	if small == 0 {
+		small = math.SmallestNonzeroFloat64
+	}
+	v := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		alpha, beta := g.scaledPair(k)
+
+		// Solve the lower triangular system
+		//  (β*S - α*T)[k:, k:]ᴴ * v = 0
+		// with v[k] = 1 by forward substitution.
+		zeroC(v)
+		v[k] = 1
+		for j := k + 1; j < n; j++ {
+			var sum complex128
+			for i := k; i < j; i++ {
+				sum += cmplx.Conj(beta*g.s.at(i, j)-alpha*g.t.at(i, j)) * v[i]
+			}
+			d := cmplx.Conj(beta*g.s.at(j, j) - alpha*g.t.at(j, j))
+			if cmplx.Abs(d) < small {
+				d = complex(small, 0)
+			}
+			v[j] = -sum / d
+		}
+
+		// Transform back with u = Q * v.
+		var norm float64
+		for i := 0; i < n; i++ {
+			var u complex128
+			for j := k; j < n; j++ {
+				u += g.q.at(i, j) * v[j]
+			}
+			dst.set(i, k, u)
+			norm = math.Hypot(norm, cmplx.Abs(u))
+		}
+		for i := 0; i < n; i++ {
+			dst.set(i, k, dst.at(i, k)/complex(norm, 0))
+		}
+	}
+	return dst
+}
+
+// scaledPair returns the kth diagonal elements of S and T scaled so that
+// the larger of their magnitudes is one.
+func (g *GSchur) scaledPair(k int) (alpha, beta complex128) {
+	alpha, beta = g.s.at(k, k), g.t.at(k, k)
+	scale := math.Max(cmplx.Abs(alpha), cmplx.Abs(beta))
+	if scale == 0 {
+		return 0, 0
+	}
+	return alpha / complex(scale, 0), beta / complex(scale, 0)
+}
+
+// Reorder reorders the generalized Schur factorization so that the eigenvalues
+// for which sel is true are moved to the leading positions of S and T,
+// preserving their relative order and the relative order of the remaining
+// eigenvalues. The elements of sel refer to the order of the eigenvalues
+// before the call, as returned by AlphaBeta and Values. The Schur vectors, if
+// computed, are updated so that the columns of Z corresponding to the selected
+// eigenvalues span the associated right deflating subspace of the pencil.
+//
+// Reorder will panic if the receiver does not contain a successful
+// factorization or if len(sel) is not n.
+func (g *GSchur) Reorder(sel []bool) {
+	if !g.succFact() {
+		panic(badGSchur)
+	}
+	n := g.s.mat.Rows
+	if len(sel) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	var ks int
+	for k, ok := range sel {
+		if !ok {
+			continue
+		}
+		// Move the kth eigenvalue to position ks by
+		// successive swaps of adjacent eigenvalues.
+		for j := k; j > ks; j-- {
+			g.swap(j - 1)
+		}
+		ks++
+	}
+}
+
+// swap exchanges the adjacent eigenvalues in positions k and k+1 of the
+// generalized Schur form with a unitary equivalence transformation.
+func (g *GSchur) swap(k int) {
+	s, t := g.s, g.t
+	n := s.mat.Rows
+	a11, a12, a22 := s.at(k, k), s.at(k, k+1), s.at(k+1, k+1)
+	b11, b12, b22 := t.at(k, k), t.at(k, k+1), t.at(k+1, k+1)
+
+	// The right eigenvector of the trailing eigenvalue of the 2×2 block,
+	// the null vector of b22*S - a22*T, becomes the first column of the
+	// rotated basis.
+	f := b22*a11 - a22*b11
+	h := b22*a12 - a22*b12
+	if f == 0 && h == 0 {
+		// The eigenvalues are equal.
+		return
+	}
+	c, sn := cGivens(h, -f)
+	cGivensRight(s, k, k+2, c, sn)
+	cGivensRight(t, k, k+2, c, sn)
+	if g.z != nil {
+		cGivensRight(g.z, k, n, c, sn)
+	}
+
+	// Restore the triangular form from the left using the larger
+	// of the rotated first columns of S and T for stability.
+	x, y := s.at(k, k), s.at(k+1, k)
+	if cmplx.Abs(t.at(k, k))+cmplx.Abs(t.at(k+1, k)) > cmplx.Abs(x)+cmplx.Abs(y) {
+		x, y = t.at(k, k), t.at(k+1, k)
+	}
+	c, sn = cGivens(x, y)
+	cGivensLeft(s, k, k, c, sn)
+	cGivensLeft(t, k, k, c, sn)
+	s.set(k+1, k, 0)
+	t.set(k+1, k, 0)
+	if g.q != nil {
+		cGivensRight(g.q, k, n, c, sn)
+	}
+
+	// Keep the diagonal of T real and non-negative.
+	gRealDiag(s, t, g.z, k)
+	gRealDiag(s, t, g.z, k+1)
+}
@@ -0,0 +1,197 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestGSchur(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// Construct a pencil with known eigenvalues 1, 2i, -3 and 4
+	// as (V*D*W, V*W).
+	v, w := randCDense(4, 4, rnd), randCDense(4, 4, rnd)
+	var vd, known, vw CDense
+	vd.Mul(v, NewCDense(4, 4, []complex128{
+		1, 0, 0, 0,
+		0, 2i, 0, 0,
+		0, 0, -3, 0,
+		0, 0, 0, 4,
+	}))
+	known.Mul(&vd, w)
+	vw.Mul(v, w)
+
+	// Construct a singular B with one infinite eigenvalue.
+	var singular CDense
+	singular.Mul(randCDense(5, 4, rnd), randCDense(4, 5, rnd))
+
+	eye := NewCDense(6, 6, nil)
+	for i := 0; i < 6; i++ {
+		eye.set(i, i, 1)
+	}
+
+	for _, test := range []struct {
+		name string
+		a, b *CDense
+		want []complex128
+		inf  int
+	}{
+		{name: "1×1", a: NewCDense(1, 1, []complex128{3 - 1i}), b: NewCDense(1, 1, []complex128{2i}), want: []complex128{-0.5 - 1.5i}},
+		{name: "2×2", a: randCDense(2, 2, rnd), b: randCDense(2, 2, rnd)},
+		{name: "random", a: randCDense(10, 10, rnd), b: randCDense(10, 10, rnd)},
+		{name: "large", a: randCDense(40, 40, rnd), b: randCDense(40, 40, rnd)},
+		{name: "known", a: &known, b: &vw, want: []complex128{-3, 2i, 1, 4}},
+		{name: "identity", a: randCDense(6, 6, rnd), b: eye},
+		{name: "singular", a: randCDense(5, 5, rnd), b: &singular, inf: 1},
+	} {
+		n, _ := test.a.Dims()
+		var g GSchur
+		if !g.Factorize(test.a, test.b, true) {
+			t.Errorf("%s: unexpected factorization failure", test.name)
+			continue
+		}
+		checkGSchur(t, test.name, &g, test.a, test.b)
+
+		alpha, beta := g.AlphaBeta(nil, nil)
+		var inf int
+		for i, b := range beta {
+			if b < 0 {
+				t.Errorf("%s: negative β[%d]: %v", test.name, i, b)
+			}
+			if b < 1e-12*cmplx.Abs(alpha[i]) {
+				inf++
+			}
+		}
+		if inf != test.inf {
+			t.Errorf("%s: unexpected number of infinite eigenvalues: got %d want %d", test.name, inf, test.inf)
+		}
+		if test.want != nil {
+			got := g.Values(nil)
+			sort.Slice(got, func(i, j int) bool { return real(got[i]) < real(got[j]) })
+			for i, v := range got {
+				if cmplx.Abs(v-test.want[i]) > 1e-10*cmplx.Abs(test.want[i]) {
+					t.Errorf("%s: unexpected eigenvalue %d: got %v want %v", test.name, i, v, test.want[i])
+				}
+			}
+		}
+		if test.b == eye {
+			var s CSchur
+			s.Factorize(test.a, false)
+			want := s.Values(nil)
+			got := g.Values(nil)
+			for _, v := range want {
+				found := false
+				for _, u := range got {
+					if cmplx.Abs(u-v) < 1e-10 {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("%s: eigenvalue %v of A not found", test.name, v)
+				}
+			}
+		}
+
+		// Check the eigenvectors of the finite eigenvalues.
+		x := g.VectorsTo(nil)
+		u := g.LeftVectorsTo(nil)
+		var ax, bx, ua, ub CDense
+		ax.Mul(test.a, x)
+		bx.Mul(test.b, x)
+		ua.Mul(u.H(), test.a)
+		ub.Mul(u.H(), test.b)
+		for k := 0; k < n; k++ {
+			a, b := g.scaledPair(k)
+			for i := 0; i < n; i++ {
+				if r := b*ax.At(i, k) - a*bx.At(i, k); cmplx.Abs(r) > 1e-10*float64(n) {
+					t.Errorf("%s: right eigenvector %d residual too large: %v", test.name, k, cmplx.Abs(r))
+					break
+				}
+			}
+			for j := 0; j < n; j++ {
+				if r := b*ua.At(k, j) - a*ub.At(k, j); cmplx.Abs(r) > 1e-10*float64(n) {
+					t.Errorf("%s: left eigenvector %d residual too large: %v", test.name, k, cmplx.Abs(r))
+					break
+				}
+			}
+		}
+
+		var noVec GSchur
+		noVec.Factorize(test.a, test.b, false)
+		if !CEqual(noVec.STo(nil), g.STo(nil)) || !CEqual(noVec.TTo(nil), g.TTo(nil)) {
+			t.Errorf("%s: unexpected Schur form without vectors", test.name)
+		}
+		if p, _ := panics(func() { noVec.ZTo(nil) }); !p {
+			t.Errorf("%s: expected panic for ZTo without vectors", test.name)
+		}
+
+		// Move every other eigenvalue to the front and
+		// check the factorization is preserved.
+		before := g.Values(nil)
+		sel := make([]bool, n)
+		var want []complex128
+		for i := range sel {
+			sel[i] = i%2 == 1
+			if sel[i] {
+				want = append(want, before[i])
+			}
+		}
+		g.Reorder(sel)
+		checkGSchur(t, test.name+" reordered", &g, test.a, test.b)
+		after := g.Values(nil)
+		for i, v := range want {
+			if cmplx.IsInf(v) {
+				if !cmplx.IsInf(after[i]) {
+					t.Errorf("%s: unexpected reordered eigenvalue %d: got %v want %v", test.name, i, after[i], v)
+				}
+				continue
+			}
+			if cmplx.Abs(after[i]-v) > 1e-8*(1+cmplx.Abs(v)) {
+				t.Errorf("%s: unexpected reordered eigenvalue %d: got %v want %v", test.name, i, after[i], v)
+			}
+		}
+
+	}
+}
+
+func checkGSchur(t *testing.T, name string, g *GSchur, a, b CMatrix) {
+	t.Helper()
+	n, _ := a.Dims()
+	s, tm := g.STo(nil), g.TTo(nil)
+	q, z := g.QTo(nil), g.ZTo(nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if s.At(i, j) != 0 || tm.At(i, j) != 0 {
+				t.Errorf("%s: S or T not upper triangular", name)
+			}
+		}
+		if imag(tm.At(i, i)) != 0 {
+			t.Errorf("%s: diagonal of T not real", name)
+		}
+	}
+	if !isUnitary(q, 1e-12) || !isUnitary(z, 1e-12) {
+		t.Errorf("%s: Q or Z not unitary", name)
+	}
+	var tmp, got CDense
+	tmp.Mul(q, s)
+	got.Mul(&tmp, z.H())
+	if !CEqualApprox(&got, a, 1e-10) {
+		t.Errorf("%s: Q*S*Zᴴ != A", name)
+	}
+	tmp.Reset()
+	tmp.Mul(q, tm)
+	got.Reset()
+	got.Mul(&tmp, z.H())
+	if !CEqualApprox(&got, b, 1e-10) {
+		t.Errorf("%s: Q*T*Zᴴ != B", name)
+	}
+}
@@ -0,0 +1,727 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// machEps is the machine epsilon, the spacing of float64 values at 1.
+const machEps = 1.0 / (1 << 52)
+
+// EigenSelect specifies which eigenvalues are computed by a partial
+// eigenvalue decomposition.
+type EigenSelect int
+
+const (
+	// EigenLargest selects the eigenvalues with the largest real part.
+	EigenLargest EigenSelect = iota
+	// EigenSmallest selects the eigenvalues with the smallest real part.
+	EigenSmallest
+	// EigenLargestMagnitude selects the eigenvalues with the largest
+	// magnitude.
+	EigenLargestMagnitude
+	// EigenSmallestMagnitude selects the eigenvalues with the smallest
+	// magnitude.
+	EigenSmallestMagnitude
+)
+
+// before returns whether the eigenvalue a is selected before b. Eigenvalues
+// that are equal under the selection are ordered by decreasing imaginary
+// part, so complex conjugate pairs are adjacent.
+func (s EigenSelect) before(a, b complex128) bool {
+	var x, y float64
+	switch s {
+	case EigenLargest:
+		x, y = -real(a), -real(b)
+	case EigenSmallest:
+		x, y = real(a), real(b)
+	case EigenLargestMagnitude:
+		x, y = -cmplx.Abs(a), -cmplx.Abs(b)
+	case EigenSmallestMagnitude:
+		x, y = cmplx.Abs(a), cmplx.Abs(b)
+	}
+	if x != y {
+		return x < y
+	}
+	return imag(a) > imag(b)
+}
+
+// KrylovOptions holds the parameters of the implicitly restarted Krylov
+// subspace methods used by PartialEigenSym and PartialEigen.
+type KrylovOptions struct {
+	// SubspaceDim is the maximum dimension of the
+	// Krylov subspace. Larger subspaces need more
+	// storage and work for each restart but fewer
+	// restarts. If SubspaceDim is zero, the
+	// dimension min(n, max(2k+1, 20)) is used.
+	SubspaceDim int
+
+	// Tolerance is the relative accuracy required
+	// of the computed eigenvalues. If Tolerance is
+	// zero, the machine precision is used.
+	// Eigenvalues that are small relative to the
+	// norm of the matrix, ‖A‖, are computed to an
+	// absolute accuracy of Tolerance*ε^(2/3)*‖A‖,
+	// and no accuracy finer than the rounding error
+	// of products with A, about ε*‖A‖, is required.
+	Tolerance float64
+
+	// MaxRestarts is the maximum number of implicit
+	// restarts. If MaxRestarts is zero, 300 restarts
+	// are allowed.
+	MaxRestarts int
+
+	// Src is the source of random numbers used for
+	// the starting vector. If Src is nil, the global
+	// source is used.
+	Src rand.Source
+}
+
+// PartialEigenSym is a type for computing and using k eigenvalues and
+// eigenvectors of a large symmetric matrix.
+type PartialEigenSym struct {
+	values  []float64
+	vectors *Dense
+}
+
+// Factorize computes k eigenvalues of the n×n symmetric matrix a selected
+// by which, and optionally the corresponding eigenvectors, using the
+// implicitly restarted Lanczos method with exact shifts and full
+// reorthogonalization. Factorize computes the eigenvalues in order of
+// selection, so with EigenLargest they are in descending order.
+//
+// The matrix a is only used to form products with vectors, so a may be a
+// sparse matrix provided by this package. The symmetry of a is not checked.
+// Convergence is fastest for well separated eigenvalues at the ends of the
+// spectrum; EigenSmallestMagnitude selects interior eigenvalues of an
+// indefinite matrix and may need many restarts. An eigenvalue of multiplicity
+// greater than one may be computed fewer times than its multiplicity, in
+// which case the following eigenvalues in order of selection are returned in
+// place of the missing copies. If opts is nil, default options are used.
+//
+// Factorize returns whether the decomposition succeeded within the allowed
+// number of restarts. If the decomposition failed, methods that require a
+// successful factorization will panic. Factorize will panic if a is not
+// square, if k is not in [1, n] or if opts holds invalid values.
+func (e *PartialEigenSym) Factorize(a Matrix, k int, which EigenSelect, vectors bool, opts *KrylovOptions) (ok bool) {
+	return e.FactorizeOperator(MatrixOperator{a}, k, which, vectors, opts)
+}
+
+// FactorizeOperator computes k eigenvalues and optionally eigenvectors of the
+// n×n symmetric operator a as for Factorize. Only the products of a with
+// vectors are used.
+func (e *PartialEigenSym) FactorizeOperator(a Operator, k int, which EigenSelect, vectors bool, opts *KrylovOptions) (ok bool) {
+	e.values = nil
+	e.vectors = nil
+	values, xr, _, ok := krylovEigen(a, k, which, true, vectors, opts)
+	if !ok {
+		return false
+	}
+	e.values = make([]float64, k)
+	for i, v := range values {
+		e.values[i] = real(v)
+	}
+	e.vectors = xr
+	return true
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *PartialEigenSym) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Values extracts the k computed eigenvalues. If dst is non-nil, the values
+// are stored in-place into dst. In this case dst must have length k,
+// otherwise Values will panic. If dst is nil, then a new slice will be
+// allocated of the proper length and filled with the eigenvalues.
+//
+// Values panics if the decomposition was not successful.
+func (e *PartialEigenSym) Values(dst []float64) []float64 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo returns the n×k matrix of eigenvectors of the decomposition,
+// with columns in the order of the eigenvalues returned by Values. VectorsTo
+// will panic if the eigenvectors were not computed during the factorization,
+// or if the factorization was not successful.
+//
+// If dst is not nil, the eigenvectors are stored in-place into dst, and dst
+// must have size n×k and panics otherwise. If dst is nil, a new matrix
+// is allocated and returned.
+func (e *PartialEigenSym) VectorsTo(dst *Dense) *Dense {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if e.vectors == nil {
+		panic(badNoVect)
+	}
+	return copyFactor(dst, e.vectors)
+}
+
+// PartialEigen is a type for computing and using k eigenvalues and right
+// eigenvectors of a large general square matrix.
+type PartialEigen struct {
+	values  []complex128
+	vectors *CDense
+}
+
+// Factorize computes k eigenvalues of the n×n matrix a selected by which,
+// and optionally the corresponding right eigenvectors, using the implicitly
+// restarted Arnoldi method with exact shifts and full reorthogonalization.
+// Factorize computes the eigenvalues in order of selection. Complex
+// conjugate pairs are adjacent with the positive imaginary part first; if
+// the k-th eigenvalue is the first of a pair, its conjugate is not included.
+//
+// The matrix a is only used to form products with vectors, so a may be a
+// sparse matrix provided by this package. Convergence is fastest for well
+// separated eigenvalues on the boundary of the spectrum; with
+// EigenSmallestMagnitude many restarts may be needed. An eigenvalue of
+// multiplicity greater than one may be computed fewer times than its
+// multiplicity. If opts is nil, default options are used.
+//
+// Factorize returns whether the decomposition succeeded within the allowed
+// number of restarts. If the decomposition failed, methods that require a
+// successful factorization will panic. Factorize will panic if a is not
+// square, if k is not in [1, n] or if opts holds invalid values.
+func (e *PartialEigen) Factorize(a Matrix, k int, which EigenSelect, vectors bool, opts *KrylovOptions) (ok bool) {
+	return e.FactorizeOperator(MatrixOperator{a}, k, which, vectors, opts)
+}
+
+// FactorizeOperator computes k eigenvalues and optionally right eigenvectors
+// of the n×n operator a as for Factorize. Only the products of a with vectors
+// are used.
+func (e *PartialEigen) FactorizeOperator(a Operator, k int, which EigenSelect, vectors bool, opts *KrylovOptions) (ok bool) {
+	e.values = nil
+	e.vectors = nil
+	values, xr, xi, ok := krylovEigen(a, k, which, false, vectors, opts)
+	if !ok {
+		return false
+	}
+	e.values = values
+	if vectors {
+		n, _ := xr.Dims()
+		e.vectors = NewCDense(n, k, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < k; j++ {
+				e.vectors.set(i, j, complex(xr.at(i, j), xi.at(i, j)))
+			}
+		}
+	}
+	return true
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *PartialEigen) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Values extracts the k computed eigenvalues. If dst is non-nil, the values
+// are stored in-place into dst. In this case dst must have length k,
+// otherwise Values will panic. If dst is nil, then a new slice will be
+// allocated of the proper length and filled with the eigenvalues.
+//
+// Values panics if the decomposition was not successful.
+func (e *PartialEigen) Values(dst []complex128) []complex128 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]complex128, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo returns the n×k matrix of right eigenvectors of the
+// decomposition, with columns in the order of the eigenvalues returned by
+// Values. VectorsTo will panic if the eigenvectors were not computed during
+// the factorization, or if the factorization was not successful.
+//
+// The computed eigenvectors are normalized to have Euclidean norm equal to 1.
+func (e *PartialEigen) VectorsTo(dst *CDense) *CDense {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if e.vectors == nil {
+		panic(badNoVect)
+	}
+	r, c := e.vectors.Dims()
+	if dst == nil {
+		dst = NewCDense(r, c, nil)
+	} else {
+		dst.reuseAs(r, c)
+	}
+	dst.Copy(e.vectors)
+	return dst
+}
+
+// krylovEigen computes k eigenvalues of a selected by which and, if vectors
+// is true, the real and imaginary parts of the corresponding eigenvectors.
+func krylovEigen(a Operator, k int, which EigenSelect, sym, vectors bool, opts *KrylovOptions) (values []complex128, xr, xi *Dense, ok bool) {
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	if k < 1 || n < k {
+		panic("mat: number of eigenvalues out of range")
+	}
+	if which < EigenLargest || EigenSmallestMagnitude < which {
+		panic("mat: invalid eigenvalue selection")
+	}
+	if opts == nil {
+		opts = &KrylovOptions{}
+	}
+	m := opts.SubspaceDim
+	if m == 0 {
+		m = 2*k + 1
+		if m < 20 {
+			m = 20
+		}
+		if m > n {
+			m = n
+		}
+	}
+	// A restart must purge at least one value, and
+	// in the general case must not split a complex
+	// conjugate pair of wanted values.
+	minDim := k + 1
+	if !sym {
+		minDim++
+	}
+	if n < m || (m < n && m < minDim) {
+		panic("mat: invalid Krylov subspace dimension")
+	}
+	tol := opts.Tolerance
+	if tol < 0 {
+		panic("mat: negative Krylov tolerance")
+	}
+	if tol == 0 {
+		tol = machEps
+	}
+	maxRestarts := opts.MaxRestarts
+	if maxRestarts < 0 {
+		panic("mat: negative Krylov restart limit")
+	}
+	if maxRestarts == 0 {
+		maxRestarts = 300
+	}
+
+	kr := newKrylov(a, m, sym, opts.Src)
+	eps23 := math.Pow(machEps, 2.0/3)
+	for restart := 0; ; restart++ {
+		kr.expand()
+		theta, y, ok := kr.ritz(which)
+		if !ok {
+			return nil, nil, nil, false
+		}
+
+		// The residual norm of the i-th Ritz pair
+		// is |f| times the last element of y_i. It
+		// is compared with tol*max(eps23, |θ_i|) as
+		// in ARPACK, with eps23 scaled by the norm
+		// of H so the test is invariant to the scale
+		// of A. Residuals below the rounding error
+		// in forming products with A, about
+		// machEps*‖H‖, cannot be computed reliably
+		// for eigenvalues at or near zero, so the
+		// bound is not allowed to fall below it.
+		// The largest Ritz value magnitude is used
+		// as ‖H‖, which it equals when H is
+		// symmetric.
+		beta := blas64.Nrm2(kr.f.mat)
+		var hnorm float64
+		for _, v := range theta {
+			hnorm = math.Max(hnorm, cmplx.Abs(v))
+		}
+		var nconv int
+		for i := 0; i < k; i++ {
+			bound := math.Max(tol*math.Max(eps23*hnorm, cmplx.Abs(theta[i])), machEps*hnorm)
+			if beta*cmplx.Abs(y.at(m-1, i)) <= bound {
+				nconv++
+			}
+		}
+		if nconv == k {
+			if vectors {
+				xr, xi = kr.ritzVectors(y, k)
+			}
+			return theta[:k], xr, xi, true
+		}
+		if restart == maxRestarts {
+			return nil, nil, nil, false
+		}
+
+		// Keep some of the converged values in
+		// addition to the wanted values to speed
+		// convergence of the rest, following ARPACK.
+		keep := k + nconv
+		if lim := k + (m-k)/2; keep > lim {
+			keep = lim
+		}
+		if !sym && imag(theta[keep-1]) != 0 && theta[keep] == cmplx.Conj(theta[keep-1]) {
+			keep++
+			if keep == m {
+				keep -= 2
+			}
+		}
+		kr.restart(keep, theta[keep:])
+	}
+}
+
+// krylov holds an Arnoldi factorization
+//
+//	A * Vᵀ = Vᵀ * H + f * e_jᵀ
+//
+// of dimension j, where the rows of v are an orthonormal basis for the
+// Krylov subspace and f is orthogonal to them. When sym is true, H is
+// symmetric tridiagonal and this is a Lanczos factorization.
+type krylov struct {
+	a    Operator
+	sym  bool
+	n, m int
+	j    int
+
+	v *Dense
+	h *Dense
+	f *VecDense
+
+	proj       []float64
+	coef, work *VecDense
+	normal     func() float64
+}
+
+// newKrylov returns an empty Arnoldi factorization of a with maximum
+// dimension m and a random starting vector.
+func newKrylov(a Operator, m int, sym bool, src rand.Source) *krylov {
+	n, _ := a.Dims()
+	normal := rand.NormFloat64
+	if src != nil {
+		normal = rand.New(src).NormFloat64
+	}
+	k := &krylov{
+		a:   a,
+		sym: sym,
+		n:   n,
+		m:   m,
+
+		v: NewDense(m, n, nil),
+		h: NewDense(m, m, nil),
+		f: NewVecDense(n, nil),
+
+		proj:   make([]float64, m),
+		coef:   NewVecDense(m, nil),
+		work:   NewVecDense(n, nil),
+		normal: normal,
+	}
+	for i := range k.f.mat.Data {
+		k.f.mat.Data[i] = normal()
+	}
+	return k
+}
+
+// expand extends the factorization to dimension m.
+func (k *krylov) expand() {
+	for ; k.j < k.m; k.j++ {
+		j := k.j
+		beta := blas64.Nrm2(k.f.mat)
+		if beta == 0 {
+			// The basis spans an invariant subspace,
+			// so continue with a random vector that is
+			// orthogonal to it.
+			for i := range k.f.mat.Data {
+				k.f.mat.Data[i] = k.normal()
+			}
+			k.orthogonalize(j, nil)
+			k.f.ScaleVec(1/blas64.Nrm2(k.f.mat), k.f)
+		} else {
+			k.f.ScaleVec(1/beta, k.f)
+			if j > 0 {
+				k.h.set(j, j-1, beta)
+				if k.sym {
+					k.h.set(j-1, j, beta)
+				}
+			}
+		}
+		copy(k.v.rawRowView(j), k.f.mat.Data)
+
+		k.a.MulVecTo(k.f, false, k.v.RowView(j))
+		norm := blas64.Nrm2(k.f.mat)
+		h := k.proj[:j+1]
+		for i := range h {
+			h[i] = 0
+		}
+		k.orthogonalize(j+1, h)
+		if k.sym {
+			k.h.set(j, j, h[j])
+		} else {
+			for i, v := range h {
+				k.h.set(i, j, v)
+			}
+		}
+		if j+1 == k.n || blas64.Nrm2(k.f.mat) <= machEps*norm {
+			k.f.Zero()
+		}
+	}
+}
+
+// orthogonalize orthogonalizes f against the first j basis vectors using
+// classical Gram-Schmidt with one step of reorthogonalization. If h is not
+// nil, the projection coefficients are added to it.
+func (k *krylov) orthogonalize(j int, h []float64) {
+	if j == 0 {
+		return
+	}
+	v := k.v.Slice(0, j, 0, k.n)
+	c := k.coef.SliceVec(0, j).(*VecDense)
+	for pass := 0; pass < 2; pass++ {
+		c.MulVec(v, k.f)
+		k.work.MulVec(v.T(), c)
+		k.f.SubVec(k.f, k.work)
+		for i := range h {
+			h[i] += c.AtVec(i)
+		}
+	}
+}
+
+// ritz returns the eigenvalues of H in order of selection and the
+// corresponding eigenvectors of H.
+func (k *krylov) ritz(which EigenSelect) (theta []complex128, y *CDense, ok bool) {
+	m := k.m
+	vals := make([]complex128, m)
+	vecs := NewCDense(m, m, nil)
+	if k.sym {
+		var es EigenSym
+		if !es.Factorize(NewSymDense(m, k.h.mat.Data), true) {
+			return nil, nil, false
+		}
+		for i, v := range es.values {
+			vals[i] = complex(v, 0)
+		}
+		for i := 0; i < m; i++ {
+			for j := 0; j < m; j++ {
+				vecs.set(i, j, complex(es.vectors.at(i, j), 0))
+			}
+		}
+	} else {
+		var eg Eigen
+		if !eg.Factorize(k.h, EigenRight) {
+			return nil, nil, false
+		}
+		copy(vals, eg.values)
+		vecs = eg.rVectors
+	}
+
+	idx := make([]int, m)
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return which.before(vals[idx[a]], vals[idx[b]]) })
+	theta = make([]complex128, m)
+	y = NewCDense(m, m, nil)
+	for j, p := range idx {
+		theta[j] = vals[p]
+		for i := 0; i < m; i++ {
+			y.set(i, j, vecs.at(i, p))
+		}
+	}
+	return theta, y, true
+}
+
+// ritzVectors returns the real and imaginary parts of the first c Ritz
+// vectors Vᵀ * y.
+func (k *krylov) ritzVectors(y *CDense, c int) (xr, xi *Dense) {
+	yr := NewDense(k.m, c, nil)
+	yi := NewDense(k.m, c, nil)
+	for i := 0; i < k.m; i++ {
+		for j := 0; j < c; j++ {
+			v := y.at(i, j)
+			yr.set(i, j, real(v))
+			yi.set(i, j, imag(v))
+		}
+	}
+	xr = NewDense(k.n, c, nil)
+	xr.Mul(k.v.T(), yr)
+	xi = NewDense(k.n, c, nil)
+	xi.Mul(k.v.T(), yi)
+	return xr, xi
+}
+
+// restart applies the shifts in mu, which are removed from the spectrum of
+// H, by implicitly shifted QR steps and truncates the factorization to
+// dimension keep. Complex shifts are applied as conjugate pairs with real
+// double shift steps.
+func (k *krylov) restart(keep int, mu []complex128) {
+	m := k.m
+	q := NewDense(m, m, nil)
+	for i := 0; i < m; i++ {
+		q.set(i, i, 1)
+	}
+	h := k.h
+	for i := 0; i < len(mu); i++ {
+		re, abs := real(mu[i]), cmplx.Abs(mu[i])
+		double := imag(mu[i]) != 0
+		if double && i+1 < len(mu) && mu[i+1] == cmplx.Conj(mu[i]) {
+			i++
+		}
+
+		// Apply the shift to each unreduced
+		// diagonal block of H.
+		for l := 0; l < m-1; {
+			u := l
+			for u < m-1 {
+				if math.Abs(h.at(u+1, u)) <= machEps*(math.Abs(h.at(u, u))+math.Abs(h.at(u+1, u+1))) {
+					h.set(u+1, u, 0)
+					if k.sym {
+						h.set(u, u+1, 0)
+					}
+					break
+				}
+				u++
+			}
+			if u == l {
+				l++
+				continue
+			}
+
+			// Form the first column of the shift
+			// polynomial H - μI, or H² - 2Re(μ)H + |μ|²I
+			// for a complex pair.
+			var v []float64
+			if double {
+				v = []float64{
+					h.at(l, l)*h.at(l, l) + h.at(l, l+1)*h.at(l+1, l) - 2*re*h.at(l, l) + abs*abs,
+					h.at(l+1, l) * (h.at(l, l) + h.at(l+1, l+1) - 2*re),
+				}
+				if l+2 <= u {
+					v = append(v, h.at(l+1, l)*h.at(l+2, l+1))
+				}
+			} else {
+				v = []float64{h.at(l, l) - re, h.at(l+1, l)}
+			}
+			k.bulgeChase(q, l, u, v)
+			l = u + 1
+		}
+
+		if k.sym {
+			// Restore the symmetry of H lost to
+			// rounding errors.
+			for r := 1; r < m; r++ {
+				v := (h.at(r, r-1) + h.at(r-1, r)) / 2
+				h.set(r, r-1, v)
+				h.set(r-1, r, v)
+				for c := r + 1; c < m; c++ {
+					h.set(r-1, c, 0)
+				}
+			}
+		}
+	}
+
+	// Update the basis and the residual, using
+	// that the last row of Q is zero before the
+	// column keep-1.
+	var v Dense
+	v.Mul(q.Slice(0, m, 0, keep+1).T(), k.v)
+	beta := h.at(keep, keep-1)
+	sigma := q.at(m-1, keep-1)
+	k.f.ScaleVec(sigma, k.f)
+	k.f.AddScaledVec(k.f, beta, v.RowView(keep))
+	for i := 0; i < m; i++ {
+		if i < keep {
+			copy(k.v.rawRowView(i), v.rawRowView(i))
+		} else {
+			zero(k.v.rawRowView(i))
+		}
+		for j := 0; j < m; j++ {
+			if i >= keep || j >= keep {
+				h.set(i, j, 0)
+			}
+		}
+	}
+	k.j = keep
+}
+
+// bulgeChase performs an implicit QR step on the unreduced diagonal block
+// l:u+1 of the upper Hessenberg matrix H, where v is the first column of
+// the shift polynomial, and accumulates the transformation into q.
+func (k *krylov) bulgeChase(q *Dense, l, u int, v []float64) {
+	h := k.h
+	m := k.m
+	for i := l; i < u; i++ {
+		r := len(v)
+		if i+r-1 > u {
+			r = u - i + 1
+		}
+		if i > l {
+			for j := 0; j < r; j++ {
+				v[j] = h.at(i+j, i-1)
+			}
+		}
+		v := v[:r]
+
+		// Find the reflector P = I - τ*w*wᵀ with
+		// P*v = α*e_1, storing w in v.
+		norm := blas64.Nrm2(blas64.Vector{N: r, Data: v, Inc: 1})
+		if norm == 0 {
+			continue
+		}
+		alpha := -math.Copysign(norm, v[0])
+		v[0] -= alpha
+		tau := -v[0] / alpha
+		for j := 1; j < r; j++ {
+			v[j] /= v[0]
+		}
+		v[0] = 1
+
+		// Apply P from the left and right to H
+		// and from the right to Q.
+		for c := 0; c < m; c++ {
+			var d float64
+			for j, w := range v {
+				d += w * h.at(i+j, c)
+			}
+			d *= tau
+			for j, w := range v {
+				h.set(i+j, c, h.at(i+j, c)-d*w)
+			}
+		}
+		for _, a := range []*Dense{h, q} {
+			for c := 0; c < m; c++ {
+				var d float64
+				for j, w := range v {
+					d += w * a.at(c, i+j)
+				}
+				d *= tau
+				for j, w := range v {
+					a.set(c, i+j, a.at(c, i+j)-d*w)
+				}
+			}
+		}
+		if i > l {
+			h.set(i, i-1, alpha)
+			for j := 1; j < r; j++ {
+				h.set(i+j, i-1, 0)
+			}
+		}
+	}
+}
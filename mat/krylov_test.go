@@ -0,0 +1,311 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPartialEigenSym(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	dense := NewSymDense(60, nil)
+	for i := 0; i < 60; i++ {
+		for j := i; j < 60; j++ {
+			dense.SetSym(i, j, rnd.NormFloat64())
+		}
+	}
+	for _, test := range []struct {
+		name string
+		a    Matrix
+		opts *KrylovOptions
+	}{
+		{name: "dense", a: dense},
+		{name: "grid", a: gridLaplacian(12, 0)},
+		{name: "shifted grid", a: gridLaplacian(10, -3.3)},
+		{name: "small", a: NewSymDense(3, []float64{2, 1, 0, 1, 2, 1, 0, 1, 2})},
+		{name: "diagonal", a: NewDiagDense(40, func() []float64 {
+			d := make([]float64, 40)
+			for i := range d {
+				d[i] = float64(i%10) + float64(i/10)/4
+			}
+			return d
+		}())},
+		{name: "small subspace", a: gridLaplacian(12, 0), opts: &KrylovOptions{SubspaceDim: 8, MaxRestarts: 1000}},
+	} {
+		n, _ := test.a.Dims()
+		var d Dense
+		d.CloneFrom(test.a)
+		var es EigenSym
+		if !es.Factorize(NewSymDense(n, d.mat.Data), false) {
+			t.Fatalf("%s: unexpected EigenSym failure", test.name)
+		}
+		all := es.Values(nil)
+		norm := math.Max(math.Abs(all[0]), math.Abs(all[n-1]))
+
+		for _, which := range []EigenSelect{EigenLargest, EigenSmallest, EigenLargestMagnitude, EigenSmallestMagnitude} {
+			for _, k := range []int{1, 3, 6} {
+				if k > n {
+					continue
+				}
+				name := fmt.Sprintf("%s which=%d k=%d", test.name, which, k)
+				want := make([]float64, n)
+				copy(want, all)
+				sort.SliceStable(want, func(i, j int) bool { return which.before(complex(want[i], 0), complex(want[j], 0)) })
+				want = want[:k]
+
+				opts := &KrylovOptions{Src: rand.NewSource(1)}
+				if test.opts != nil {
+					*opts = *test.opts
+					opts.Src = rand.NewSource(1)
+				} else if which == EigenSmallestMagnitude && n > 40 {
+					// Interior eigenvalues need a larger subspace.
+					opts.SubspaceDim = 40
+				}
+				var pe PartialEigenSym
+				if !pe.Factorize(test.a, k, which, true, opts) {
+					t.Errorf("%s: unexpected factorization failure", name)
+					continue
+				}
+				got := pe.Values(nil)
+				for i := range got {
+					if math.Abs(got[i]-want[i]) > 1e-10*norm {
+						t.Errorf("%s: unexpected eigenvalues:\ngot  %v\nwant %v", name, got, want)
+						break
+					}
+				}
+
+				x := pe.VectorsTo(nil)
+				if r, c := x.Dims(); r != n || c != k {
+					t.Errorf("%s: unexpected vectors shape %d×%d", name, r, c)
+					continue
+				}
+				var xtx, ax, xl Dense
+				xtx.Mul(x.T(), x)
+				if !EqualApprox(&xtx, eye(k), 1e-10) {
+					t.Errorf("%s: eigenvectors not orthonormal", name)
+				}
+				ax.Mul(test.a, x)
+				xl.Mul(x, NewDiagDense(k, got))
+				if !EqualApprox(&ax, &xl, 1e-8*norm) {
+					t.Errorf("%s: A*x != λ*x", name)
+				}
+			}
+		}
+	}
+}
+
+func TestPartialEigenSymRepeated(t *testing.T) {
+	t.Parallel()
+	// Copies of repeated eigenvalues may be missed, so only check
+	// that the computed pairs are eigenpairs in order of selection.
+	const n = 40
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = float64(i % 10)
+	}
+	a := NewDiagDense(n, d)
+	for _, which := range []EigenSelect{EigenLargest, EigenSmallest, EigenLargestMagnitude, EigenSmallestMagnitude} {
+		for _, k := range []int{1, 3, 6} {
+			name := fmt.Sprintf("which=%d k=%d", which, k)
+			var pe PartialEigenSym
+			if !pe.Factorize(a, k, which, true, &KrylovOptions{Src: rand.NewSource(1)}) {
+				t.Errorf("%s: unexpected factorization failure", name)
+				continue
+			}
+			got := pe.Values(nil)
+			for i, v := range got {
+				if r := math.Round(v); math.Abs(v-r) > 1e-10*9 || r < 0 || 9 < r {
+					t.Errorf("%s: unexpected eigenvalue %v", name, v)
+				}
+				if i > 0 && which.before(complex(v, 0), complex(got[i-1], 0)) {
+					t.Errorf("%s: eigenvalues not in order of selection: %v", name, got)
+				}
+			}
+			var ax, xl Dense
+			x := pe.VectorsTo(nil)
+			ax.Mul(a, x)
+			xl.Mul(x, NewDiagDense(k, got))
+			if !EqualApprox(&ax, &xl, 1e-8*9) {
+				t.Errorf("%s: A*x != λ*x", name)
+			}
+		}
+	}
+}
+
+func TestPartialEigen(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	dense := NewDense(60, 60, nil)
+	for i := range dense.mat.Data {
+		dense.mat.Data[i] = rnd.NormFloat64()
+	}
+	rotation := NewDense(4, 4, []float64{
+		0, -2, 0, 0,
+		2, 0, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, -3,
+	})
+	for _, test := range []struct {
+		name string
+		a    Matrix
+		tol  float64
+
+		// interior is true if the eigenvalues of smallest
+		// magnitude are in the interior of the spectrum.
+		interior bool
+	}{
+		{name: "dense", a: dense, tol: 1e-9, interior: true},
+		{name: "convection", a: convection(10, 2), tol: 1e-9},
+		{name: "rotation", a: rotation, tol: 1e-12},
+		{name: "symmetric", a: gridLaplacian(8, 0), tol: 1e-9},
+	} {
+		n, _ := test.a.Dims()
+		var eg Eigen
+		if !eg.Factorize(test.a, EigenNone) {
+			t.Fatalf("%s: unexpected Eigen failure", test.name)
+		}
+		all := eg.Values(nil)
+		var norm float64
+		for _, v := range all {
+			norm = math.Max(norm, cmplx.Abs(v))
+		}
+
+		for _, which := range []EigenSelect{EigenLargest, EigenSmallest, EigenLargestMagnitude, EigenSmallestMagnitude} {
+			for _, k := range []int{1, 2, 5} {
+				if k > n || (test.interior && which == EigenSmallestMagnitude) {
+					continue
+				}
+				name := fmt.Sprintf("%s which=%d k=%d", test.name, which, k)
+				want := make([]complex128, n)
+				copy(want, all)
+				sort.SliceStable(want, func(i, j int) bool { return which.before(want[i], want[j]) })
+				want = want[:k]
+
+				var pe PartialEigen
+				if !pe.Factorize(test.a, k, which, true, &KrylovOptions{Src: rand.NewSource(1)}) {
+					t.Errorf("%s: unexpected factorization failure", name)
+					continue
+				}
+				got := pe.Values(nil)
+				for i := range got {
+					if cmplx.Abs(got[i]-want[i]) > test.tol*norm {
+						t.Errorf("%s: unexpected eigenvalues:\ngot  %v\nwant %v", name, got, want)
+						break
+					}
+				}
+
+				x := pe.VectorsTo(nil)
+				if r, c := x.Dims(); r != n || c != k {
+					t.Errorf("%s: unexpected vectors shape %d×%d", name, r, c)
+					continue
+				}
+				xr := NewDense(n, k, nil)
+				xi := NewDense(n, k, nil)
+				for i := 0; i < n; i++ {
+					for j := 0; j < k; j++ {
+						xr.set(i, j, real(x.at(i, j)))
+						xi.set(i, j, imag(x.at(i, j)))
+					}
+				}
+				var axr, axi Dense
+				axr.Mul(test.a, xr)
+				axi.Mul(test.a, xi)
+				for j, l := range got {
+					var res, xnorm float64
+					for i := 0; i < n; i++ {
+						xij := complex(xr.at(i, j), xi.at(i, j))
+						r := complex(axr.at(i, j), axi.at(i, j)) - l*xij
+						res += real(r)*real(r) + imag(r)*imag(r)
+						xnorm += real(xij)*real(xij) + imag(xij)*imag(xij)
+					}
+					if math.Abs(xnorm-1) > 1e-10 {
+						t.Errorf("%s: eigenvector %d not normalized: |x|²=%v", name, j, xnorm)
+					}
+					if math.Sqrt(res) > 1e-8*norm {
+						t.Errorf("%s: A*x != λ*x for eigenvalue %d: residual %v", name, j, math.Sqrt(res))
+					}
+				}
+			}
+		}
+	}
+}
+
+// convection returns the matrix of an upwind finite difference
+// discretization of -Δu + c·∇u on an m×m grid.
+func convection(m int, c float64) *CSR {
+	n := m * m
+	a := NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			k := i*m + j
+			a.Append(k, k, 4+2*c)
+			if i > 0 {
+				a.Append(k, k-m, -1-c)
+			}
+			if i < m-1 {
+				a.Append(k, k+m, -1)
+			}
+			if j > 0 {
+				a.Append(k, k-1, -1-c)
+			}
+			if j < m-1 {
+				a.Append(k, k+1, -1)
+			}
+		}
+	}
+	return a.ToCSR()
+}
+
+func TestPartialEigenFailure(t *testing.T) {
+	t.Parallel()
+	a := gridLaplacian(10, 0)
+	var pe PartialEigenSym
+	if pe.Factorize(a, 4, EigenSmallest, false, &KrylovOptions{SubspaceDim: 6, MaxRestarts: 1}) {
+		t.Error("unexpected success with one restart")
+	}
+	if panicked, _ := panics(func() { pe.Values(nil) }); !panicked {
+		t.Error("expected panic for Values after failure")
+	}
+
+	if !pe.Factorize(a, 2, EigenLargest, false, nil) {
+		t.Fatal("unexpected factorization failure")
+	}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "vectors", fn: func() { pe.VectorsTo(nil) }},
+		{name: "Values length", fn: func() { pe.Values(make([]float64, 1)) }},
+		{name: "non-square", fn: func() { new(PartialEigen).Factorize(NewDense(3, 4, nil), 1, EigenLargest, false, nil) }},
+		{name: "zero k", fn: func() { new(PartialEigenSym).Factorize(a, 0, EigenLargest, false, nil) }},
+		{name: "large k", fn: func() { new(PartialEigenSym).Factorize(a, 101, EigenLargest, false, nil) }},
+		{name: "selection", fn: func() { new(PartialEigenSym).Factorize(a, 1, -1, false, nil) }},
+		{name: "subspace", fn: func() {
+			new(PartialEigen).Factorize(a, 4, EigenLargest, false, &KrylovOptions{SubspaceDim: 5})
+		}},
+		{name: "tolerance", fn: func() {
+			new(PartialEigen).Factorize(a, 4, EigenLargest, false, &KrylovOptions{Tolerance: -1})
+		}},
+	} {
+		if panicked, _ := panics(test.fn); !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func BenchmarkPartialEigenSymGrid(b *testing.B) {
+	a := gridLaplacian(100, 0)
+	for i := 0; i < b.N; i++ {
+		var pe PartialEigenSym
+		pe.Factorize(a, 10, EigenLargest, true, nil)
+	}
+}
@@ -28,12 +28,12 @@ type LU struct {
 
 // updateCond updates the stored condition number of the matrix. anorm is the
 // norm of the original matrix. If anorm is negative it will be estimated.
-func (lu *LU) updateCond(anorm float64, norm lapack.MatrixNorm) {
+func (lu *LU) updateCond(anorm float64, norm lapack.MatrixNorm, ws *Workspace) {
 	n := lu.lu.mat.Cols
-	work := getFloats(4*n, false)
-	defer putFloats(work)
-	iwork := getInts(n, false)
-	defer putInts(iwork)
+	work := ws.getFloats(wsNorm, 4*n, false)
+	defer ws.putFloats(work)
+	iwork := ws.getInts(n, false)
+	defer ws.putInts(iwork)
 	if anorm < 0 {
 		// This is an approximation. By the definition of a norm,
 		//  |AB| <= |A| |B|.
@@ -60,10 +60,18 @@ func (lu *LU) updateCond(anorm float64, norm lapack.MatrixNorm) {
 // factors can be extracted from the factorization using the Permutation method
 // on Dense, and the LU LTo and UTo methods.
 func (lu *LU) Factorize(a Matrix) {
-	lu.factorize(a, CondNorm)
+	lu.factorize(a, CondNorm, nil)
 }
 
-func (lu *LU) factorize(a Matrix, norm lapack.MatrixNorm) {
+// FactorizeWork computes the LU factorization of the square matrix a as for
+// Factorize, using ws for scratch memory. Repeated factorizations of matrices
+// of the same size into the same receiver using the same ws do not allocate.
+// If ws is nil, FactorizeWork is equivalent to Factorize.
+func (lu *LU) FactorizeWork(a Matrix, ws *Workspace) {
+	lu.factorize(a, CondNorm, ws)
+}
+
+func (lu *LU) factorize(a Matrix, norm lapack.MatrixNorm, ws *Workspace) {
 	r, c := a.Dims()
 	if r != c {
 		panic(ErrSquare)
@@ -79,11 +87,11 @@ func (lu *LU) factorize(a Matrix, norm lapack.MatrixNorm) {
 		lu.pivot = make([]int, r)
 	}
 	lu.pivot = lu.pivot[:r]
-	work := getFloats(r, false)
+	work := ws.getFloats(wsWork, r, false)
 	anorm := lapack64.Lange(norm, lu.lu.mat, work)
-	putFloats(work)
+	ws.putFloats(work)
 	lapack64.Getrf(lu.lu.mat, lu.pivot)
-	lu.updateCond(anorm, norm)
+	lu.updateCond(anorm, norm, ws)
 }
 
 // isValid returns whether the receiver contains a factorization.
@@ -246,7 +254,7 @@ func (lu *LU) RankOne(orig *LU, alpha float64, x, y Vector) {
 			lum.Data[j*lum.Stride+i] += gamma * tmp
 		}
 	}
-	lu.updateCond(-1, CondNorm)
+	lu.updateCond(-1, CondNorm, nil)
 }
 
 // LTo extracts the lower triangular matrix from an LU factorization.
@@ -0,0 +1,184 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+)
+
+var errBadMapping = errors.New("mat: file is not a mapped Dense")
+
+// MappedDense is a dense matrix stored in a file in the form written by
+// Dense.MarshalBinary. On platforms that support it, the file is memory
+// mapped so that the matrix may be larger than the available memory, with
+// its elements paged in and out by the operating system as they are used.
+// On other platforms the elements are read into memory and written back
+// by Sync and Close.
+//
+// Operations on the matrix returned by Dense are best performed by methods
+// that work on the matrix in tiles, such as Dense.MulTiled and
+// TiledCholesky.Factorize, so that the working set remains small.
+type MappedDense struct {
+	f     *os.File
+	flush func() error
+	unmap func() error
+
+	dense Dense
+}
+
+// CreateMapped creates the file at path, or truncates it if it already
+// exists, to hold an r×c matrix of zeros and returns a MappedDense backed
+// by the file. CreateMapped will panic if r or c is not positive.
+func CreateMapped(path string, r, c int) (*MappedDense, error) {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic("mat: negative dimension")
+	}
+	if int64(r) > maxLen/int64(c)/int64(sizeFloat64) {
+		return nil, errTooBig
+	}
+	size := int64(headerSize) + int64(r)*int64(c)*int64(sizeFloat64)
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return nil, err
+	}
+	header := storage{
+		Form: 'G', Packing: 'F', Uplo: 'A',
+		Rows: int64(r), Cols: int64(c),
+		Version: version,
+	}
+	_, err = header.marshalBinaryTo(f)
+	if err == nil {
+		err = f.Truncate(size)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return newMapped(f, r, c)
+}
+
+// OpenMapped returns a MappedDense backed by the file at path, which must
+// hold a Dense matrix written by Dense.MarshalBinary or Dense.MarshalBinaryTo,
+// or created by CreateMapped. Changes to the matrix are written to the file.
+func OpenMapped(path string) (*MappedDense, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	r, c, err := mappedDims(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return newMapped(f, r, c)
+}
+
+// mappedDims returns the dimensions of the matrix stored in f, validating
+// the header and the size of the file.
+func mappedDims(f *os.File) (r, c int, err error) {
+	buf := make([]byte, headerSize)
+	_, err = f.ReadAt(buf, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	var header storage
+	err = header.unmarshalBinary(buf)
+	if err != nil {
+		return 0, 0, err
+	}
+	if header.Form != 'G' || header.Packing != 'F' || header.Uplo != 'A' {
+		return 0, 0, errWrongType
+	}
+	if header.Rows <= 0 || header.Cols <= 0 {
+		return 0, 0, errBadSize
+	}
+	if header.Rows > maxLen/header.Cols/int64(sizeFloat64) {
+		return 0, 0, errTooBig
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+	if fi.Size() != int64(headerSize)+header.Rows*header.Cols*int64(sizeFloat64) {
+		return 0, 0, errBadMapping
+	}
+	return int(header.Rows), int(header.Cols), nil
+}
+
+// newMapped returns a MappedDense holding the r×c matrix stored in f.
+func newMapped(f *os.File, r, c int) (*MappedDense, error) {
+	data, flush, unmap, err := mapFloats(f, int64(headerSize), r*c)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	m := &MappedDense{f: f, flush: flush, unmap: unmap}
+	m.dense = *NewDense(r, c, data)
+	return m, nil
+}
+
+// Dense returns the matrix backed by the receiver's file. The returned
+// matrix must not be used after Close has been called.
+func (m *MappedDense) Dense() *Dense {
+	return &m.dense
+}
+
+// Sync writes the elements of the matrix to the backing file.
+func (m *MappedDense) Sync() error {
+	if m.f == nil {
+		return os.ErrClosed
+	}
+	err := m.flush()
+	if err != nil {
+		return err
+	}
+	return m.f.Sync()
+}
+
+// Close writes the elements of the matrix to the backing file and releases
+// the resources held by the receiver. The matrix returned by Dense is empty
+// after Close returns.
+func (m *MappedDense) Close() error {
+	if m.f == nil {
+		return os.ErrClosed
+	}
+	err := m.unmap()
+	m.dense = Dense{}
+	if cerr := m.f.Close(); err == nil {
+		err = cerr
+	}
+	m.f = nil
+	return err
+}
+
+// readFloats reads n little-endian float64 values from f starting at byte
+// offset off. It returns the values and functions that write the values back
+// to f, the second of which also marks the end of their use.
+func readFloats(f *os.File, off int64, n int) (data []float64, flush, release func() error, err error) {
+	buf := make([]byte, n*sizeFloat64)
+	_, err = f.ReadAt(buf, off)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	data = make([]float64, n)
+	for i := range data {
+		data[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[i*sizeFloat64:]))
+	}
+	flush = func() error {
+		for i, v := range data {
+			binary.LittleEndian.PutUint64(buf[i*sizeFloat64:], math.Float64bits(v))
+		}
+		_, err := f.WriteAt(buf, off)
+		return err
+	}
+	return data, flush, flush, nil
+}
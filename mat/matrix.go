@@ -378,13 +378,13 @@ func Cond(a Matrix, norm float64) float64 {
 	if m == n {
 		// Use the LU decomposition to compute the condition number.
 		var lu LU
-		lu.factorize(a, lnorm)
+		lu.factorize(a, lnorm, nil)
 		return lu.Cond()
 	}
 	if m > n {
 		// Use the QR factorization to compute the condition number.
 		var qr QR
-		qr.factorize(a, lnorm)
+		qr.factorize(a, lnorm, nil)
 		return qr.Cond()
 	}
 	// Use the LQ factorization to compute the condition number.
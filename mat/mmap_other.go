@@ -0,0 +1,16 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !darwin,!dragonfly,!freebsd,!linux,!openbsd appengine safe
+
+package mat
+
+import "os"
+
+// mapFloats returns n float64 values stored in f from byte offset off,
+// and functions to write the values back to f. On platforms without
+// memory mapping support the values are read into memory.
+func mapFloats(f *os.File, off int64, n int) (data []float64, flush, unmap func() error, err error) {
+	return readFloats(f, off, n)
+}
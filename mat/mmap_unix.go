@@ -0,0 +1,47 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build darwin dragonfly freebsd linux openbsd
+// +build !appengine,!safe
+
+package mat
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isLittleEndian is whether the host byte order matches the byte order
+// of the file layout, so that the file elements can be used in place.
+var isLittleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// mapFloats returns a shared read-write memory mapping of n float64 values
+// stored in f from byte offset off, and functions to flush and release the
+// mapping. If the host is not little-endian, the values are read into memory.
+func mapFloats(f *os.File, off int64, n int) (data []float64, flush, unmap func() error, err error) {
+	if !isLittleEndian {
+		return readFloats(f, off, n)
+	}
+	size := int(off) + n*sizeFloat64
+	b, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	data = unsafe.Slice((*float64)(unsafe.Pointer(&b[off])), n)
+	flush = func() error {
+		_, _, errno := syscall.Syscall(syscall.SYS_MSYNC, uintptr(unsafe.Pointer(&b[0])), uintptr(len(b)), syscall.MS_SYNC)
+		if errno != 0 {
+			return errno
+		}
+		return nil
+	}
+	unmap = func() error {
+		return syscall.Munmap(b)
+	}
+	return data, flush, unmap, nil
+}
@@ -0,0 +1,279 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// NMFMethod specifies the algorithm used for nonnegative matrix
+// factorization.
+type NMFMethod int
+
+const (
+	// NMFMultiplicative specifies the multiplicative update rules of
+	// Lee and Seung.
+	NMFMultiplicative NMFMethod = iota
+	// NMFHALS specifies hierarchical alternating least squares, which
+	// updates one column of W and one row of H at a time and usually
+	// converges in far fewer iterations than NMFMultiplicative.
+	NMFHALS
+)
+
+// NMFOptions holds the parameters of a nonnegative matrix factorization.
+type NMFOptions struct {
+	// Method is the algorithm used to compute
+	// the factorization.
+	Method NMFMethod
+
+	// MaxIterations is the maximum number of
+	// updates of W and H. If MaxIterations is
+	// zero, 200 iterations are allowed.
+	MaxIterations int
+
+	// Tolerance is the relative decrease of the
+	// objective below which the factorization
+	// is considered to have converged. If
+	// Tolerance is zero, 1e-4 is used.
+	Tolerance float64
+
+	// L1W and L1H are the weights of the L1
+	// penalties on the elements of W and H.
+	// Positive values give sparser factors.
+	L1W, L1H float64
+
+	// Src is the source of random numbers used
+	// for the initial factors. If Src is nil,
+	// the global source is used.
+	Src rand.Source
+}
+
+// NMF is a type for creating and using the nonnegative matrix factorization
+// of a matrix.
+type NMF struct {
+	wt, h *Dense
+
+	iterations int
+	residual   float64
+}
+
+// Factorize computes an approximate nonnegative factorization of the
+// m×n matrix A with nonnegative elements
+//
+//	A ≈ W * H
+//
+// where W is m×k and H is k×n and both have nonnegative elements. Factorize
+// minimizes the objective
+//
+//	1/2 * ‖A - W*H‖_F² + L1W * Σ W_ij + L1H * Σ H_ij
+//
+// from random initial factors, alternating updates of H and W until the
+// relative decrease of the objective falls below the tolerance. The
+// objective is not convex, so the factorization depends on the initial
+// factors; a fixed Src gives reproducible results. If opts is nil, default
+// options with multiplicative updates are used. The matrix A is only used
+// in products, so a may be a sparse matrix provided by this package.
+//
+// Factorize returns whether the iteration converged within the allowed
+// number of iterations. The factors of the last iteration are available
+// whether or not it converged. Factorize will panic if A has a negative
+// element, if k is not positive or if opts holds invalid values.
+func (f *NMF) Factorize(a Matrix, k int, opts *NMFOptions) (ok bool) {
+	m, n := a.Dims()
+	if k < 1 {
+		panic("mat: NMF rank not positive")
+	}
+	if opts == nil {
+		opts = &NMFOptions{}
+	}
+	if opts.Method != NMFMultiplicative && opts.Method != NMFHALS {
+		panic("mat: invalid NMF method")
+	}
+	if opts.MaxIterations < 0 || opts.Tolerance < 0 || opts.L1W < 0 || opts.L1H < 0 {
+		panic("mat: negative NMF option")
+	}
+	maxIter := opts.MaxIterations
+	if maxIter == 0 {
+		maxIter = 200
+	}
+	tol := opts.Tolerance
+	if tol == 0 {
+		tol = 1e-4
+	}
+
+	var sum float64
+	check := func(_, _ int, v float64) {
+		if v < 0 {
+			panic("mat: negative element in NMF input")
+		}
+		sum += v
+	}
+	if nz, ok := a.(NonZeroDoer); ok {
+		nz.DoNonZero(check)
+	} else {
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				check(i, j, a.At(i, j))
+			}
+		}
+	}
+
+	// Initialize the factors with uniform random
+	// values scaled so that W*H has the mean of A.
+	uniform := rand.Float64
+	if opts.Src != nil {
+		uniform = rand.New(opts.Src).Float64
+	}
+	scale := math.Sqrt(sum / float64(m*n*k))
+	f.wt = NewDense(k, m, nil)
+	for i := range f.wt.mat.Data {
+		f.wt.mat.Data[i] = scale * uniform()
+	}
+	f.h = NewDense(k, n, nil)
+	for i := range f.h.mat.Data {
+		f.h.mat.Data[i] = scale * uniform()
+	}
+
+	var work nmfWorkspace
+	prev := f.objective(a, opts)
+	f.iterations = 0
+	for f.iterations < maxIter {
+		f.iterations++
+
+		// Update H with W fixed, and then W with H
+		// fixed by the same rule applied to the
+		// transposed problem Aᵀ ≈ Hᵀ * Wᵀ.
+		work.update(f.h, f.wt, a, opts.L1H, opts.Method)
+		work.update(f.wt, f.h, a.T(), opts.L1W, opts.Method)
+
+		obj := f.objective(a, opts)
+		if prev-obj <= tol*prev {
+			return true
+		}
+		prev = obj
+	}
+	return false
+}
+
+// nmfWorkspace holds the workspace for updating NMF factors.
+type nmfWorkspace struct {
+	num, gram, den Dense
+}
+
+// update updates the k×n factor X of A ≈ Yᵀ * X with the k×m factor Y
+// fixed.
+func (w *nmfWorkspace) update(x, y *Dense, a Matrix, l1 float64, method NMFMethod) {
+	w.num.Reset()
+	w.num.Mul(y, a)
+	w.gram.Reset()
+	w.gram.Mul(y, y.T())
+	switch method {
+	case NMFMultiplicative:
+		w.den.Reset()
+		w.den.Mul(&w.gram, x)
+		multiplicativeUpdate(x, &w.num, &w.den, l1)
+	case NMFHALS:
+		halsUpdate(x, &w.num, &w.gram, l1)
+	}
+}
+
+// nmfEpsilon avoids division by zero in the multiplicative updates.
+const nmfEpsilon = 1e-16
+
+// multiplicativeUpdate performs the update
+//
+//	X ← X ⊙ N ⊘ (D + l1)
+//
+// for the k×n factor X, where N = Y*A and D = Y*Yᵀ*X.
+func multiplicativeUpdate(x, num, den *Dense, l1 float64) {
+	r, c := x.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			x.set(i, j, x.at(i, j)*num.at(i, j)/(den.at(i, j)+l1+nmfEpsilon))
+		}
+	}
+}
+
+// halsUpdate performs one sweep of hierarchical alternating least squares
+// updating each row of the k×n factor X in turn, where N = Y*A and
+// G = Y*Yᵀ.
+func halsUpdate(x, num, g *Dense, l1 float64) {
+	k, n := x.Dims()
+	for p := 0; p < k; p++ {
+		gpp := g.at(p, p)
+		if gpp == 0 {
+			continue
+		}
+		for j := 0; j < n; j++ {
+			// (G*X)_pj with the current X.
+			var gx float64
+			for q := 0; q < k; q++ {
+				gx += g.at(p, q) * x.at(q, j)
+			}
+			v := x.at(p, j) + (num.at(p, j)-gx-l1)/gpp
+			if v < 0 {
+				v = 0
+			}
+			x.set(p, j, v)
+		}
+	}
+}
+
+// objective returns the penalized objective of the current factors and
+// stores the Frobenius norm of the residual.
+func (f *NMF) objective(a Matrix, opts *NMFOptions) float64 {
+	var r Dense
+	r.Mul(f.wt.T(), f.h)
+	r.Sub(a, &r)
+	f.residual = Norm(&r, 2)
+	obj := f.residual * f.residual / 2
+	if opts.L1W != 0 {
+		obj += opts.L1W * Sum(f.wt)
+	}
+	if opts.L1H != 0 {
+		obj += opts.L1H * Sum(f.h)
+	}
+	return obj
+}
+
+// Iterations returns the number of iterations used to compute the
+// factorization.
+func (f *NMF) Iterations() int {
+	return f.iterations
+}
+
+// Residual returns the Frobenius norm of A - W*H for the computed factors.
+func (f *NMF) Residual() float64 {
+	if f.wt == nil {
+		panic(badFact)
+	}
+	return f.residual
+}
+
+// WTo extracts the m×k factor W from the factorization.
+//
+// If dst is not nil, W is stored in-place into dst, and dst must have size
+// m×k, and WTo panics otherwise. If dst is nil, a new matrix of the
+// appropriate size is allocated and returned.
+func (f *NMF) WTo(dst *Dense) *Dense {
+	if f.wt == nil {
+		panic(badFact)
+	}
+	return copyFactor(dst, f.wt.T())
+}
+
+// HTo extracts the k×n factor H from the factorization.
+//
+// If dst is not nil, H is stored in-place into dst, and dst must have size
+// k×n, and HTo panics otherwise. If dst is nil, a new matrix of the
+// appropriate size is allocated and returned.
+func (f *NMF) HTo(dst *Dense) *Dense {
+	if f.wt == nil {
+		panic(badFact)
+	}
+	return copyFactor(dst, f.h)
+}
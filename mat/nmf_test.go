@@ -0,0 +1,150 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randNonneg returns a random r×c matrix with elements uniform in [0, 1).
+func randNonneg(r, c int, rnd *rand.Rand) *Dense {
+	m := NewDense(r, c, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = rnd.Float64()
+	}
+	return m
+}
+
+func TestNMF(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+		method  NMFMethod
+		maxIter int
+		tol     float64
+	}{
+		{m: 20, n: 15, k: 3, method: NMFMultiplicative, maxIter: 5000, tol: 1e-2},
+		{m: 20, n: 15, k: 3, method: NMFHALS, maxIter: 1000, tol: 1e-3},
+		{m: 40, n: 60, k: 5, method: NMFHALS, maxIter: 1000, tol: 1e-3},
+		{m: 5, n: 5, k: 1, method: NMFMultiplicative, maxIter: 1000, tol: 1e-6},
+	} {
+		name := fmt.Sprintf("m=%d n=%d k=%d method=%d", test.m, test.n, test.k, test.method)
+		var a Dense
+		a.Mul(randNonneg(test.m, test.k, rnd), randNonneg(test.k, test.n, rnd))
+
+		var nmf NMF
+		opts := &NMFOptions{Method: test.method, MaxIterations: test.maxIter, Tolerance: 1e-12, Src: rand.NewSource(1)}
+		nmf.Factorize(&a, test.k, opts)
+		w := nmf.WTo(nil)
+		h := nmf.HTo(nil)
+		if r, c := w.Dims(); r != test.m || c != test.k {
+			t.Errorf("%s: unexpected W shape %d×%d", name, r, c)
+		}
+		if r, c := h.Dims(); r != test.k || c != test.n {
+			t.Errorf("%s: unexpected H shape %d×%d", name, r, c)
+		}
+		if Min(w) < 0 || Min(h) < 0 {
+			t.Errorf("%s: negative factor element", name)
+		}
+		var wh Dense
+		wh.Mul(w, h)
+		wh.Sub(&a, &wh)
+		res := Norm(&wh, 2)
+		if res != nmf.Residual() {
+			t.Errorf("%s: mismatched residual: got %v want %v", name, nmf.Residual(), res)
+		}
+		if rel := res / Norm(&a, 2); rel > test.tol {
+			t.Errorf("%s: unexpected relative residual: got %v want <= %v", name, rel, test.tol)
+		}
+	}
+}
+
+func TestNMFSparse(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := NewCOO(30, 20, nil, nil, nil)
+	for k := 0; k < 120; k++ {
+		a.Append(rnd.Intn(30), rnd.Intn(20), rnd.Float64())
+	}
+	for _, method := range []NMFMethod{NMFMultiplicative, NMFHALS} {
+		opts := &NMFOptions{Method: method, Src: rand.NewSource(1)}
+		var sparse, dense NMF
+		sparse.Factorize(a.ToCSR(), 4, opts)
+		opts.Src = rand.NewSource(1)
+		dense.Factorize(DenseCopyOf(a), 4, opts)
+		if sparse.Iterations() != dense.Iterations() || !EqualApprox(sparse.WTo(nil), dense.WTo(nil), 1e-10) {
+			t.Errorf("method=%d: sparse and dense factorizations differ", method)
+		}
+	}
+}
+
+func TestNMFOptions(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	var a Dense
+	a.Mul(randNonneg(30, 4, rnd), randNonneg(4, 30, rnd))
+
+	// A fixed source gives reproducible factors.
+	var f1, f2 NMF
+	f1.Factorize(&a, 4, &NMFOptions{Method: NMFHALS, Src: rand.NewSource(2)})
+	f2.Factorize(&a, 4, &NMFOptions{Method: NMFHALS, Src: rand.NewSource(2)})
+	if !Equal(f1.WTo(nil), f2.WTo(nil)) || !Equal(f1.HTo(nil), f2.HTo(nil)) {
+		t.Error("factorizations with the same source differ")
+	}
+
+	// The L1 penalty on H gives a sparser H.
+	zeros := func(m *Dense) int {
+		var n int
+		for _, v := range m.mat.Data {
+			if v == 0 {
+				n++
+			}
+		}
+		return n
+	}
+	for _, method := range []NMFMethod{NMFMultiplicative, NMFHALS} {
+		var plain, sparse NMF
+		plain.Factorize(&a, 4, &NMFOptions{Method: method, Src: rand.NewSource(2)})
+		sparse.Factorize(&a, 4, &NMFOptions{Method: method, L1H: 2, Src: rand.NewSource(2)})
+		if method == NMFHALS && zeros(sparse.HTo(nil)) <= zeros(plain.HTo(nil)) {
+			t.Errorf("method=%d: L1 penalty did not give exact zeros in H", method)
+		}
+		if Sum(sparse.HTo(nil)) >= Sum(plain.HTo(nil)) {
+			t.Errorf("method=%d: L1 penalty did not shrink H", method)
+		}
+	}
+
+	// Convergence is reported.
+	var f NMF
+	if f.Factorize(&a, 4, &NMFOptions{MaxIterations: 1, Src: rand.NewSource(2)}) {
+		t.Error("unexpected convergence in one iteration")
+	}
+	if f.Iterations() != 1 {
+		t.Errorf("unexpected iteration count: got %d want 1", f.Iterations())
+	}
+}
+
+func TestNMFPanics(t *testing.T) {
+	t.Parallel()
+	a := NewDense(2, 2, []float64{1, 2, 3, 4})
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "negative element", fn: func() { new(NMF).Factorize(NewDense(1, 2, []float64{1, -1}), 1, nil) }},
+		{name: "zero rank", fn: func() { new(NMF).Factorize(a, 0, nil) }},
+		{name: "method", fn: func() { new(NMF).Factorize(a, 1, &NMFOptions{Method: 2}) }},
+		{name: "penalty", fn: func() { new(NMF).Factorize(a, 1, &NMFOptions{L1W: -1}) }},
+		{name: "unfactorized", fn: func() { new(NMF).WTo(nil) }},
+	} {
+		if panicked, _ := panics(test.fn); !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+// Operator is a linear operator that is only represented through its products
+// with vectors. An Operator can be used to represent large structured matrices,
+// such as graph Laplacians or convolutions, without storing their elements.
+type Operator interface {
+	// Dims returns the dimensions of the operator.
+	Dims() (r, c int)
+
+	// MulVecTo stores A*x into dst if trans is false, and Aᵀ*x
+	// into dst otherwise. The vector dst has the length of the
+	// product, x has the corresponding length, and dst and x do
+	// not share storage.
+	MulVecTo(dst *VecDense, trans bool, x Vector)
+}
+
+// MulToer is an Operator that can compute its products with matrices more
+// efficiently than one vector at a time.
+type MulToer interface {
+	Operator
+
+	// MulTo stores A*X into dst if trans is false, and Aᵀ*X into
+	// dst otherwise. The matrix dst is either empty or has the
+	// dimensions of the product, and dst and X do not share
+	// storage.
+	MulTo(dst *Dense, trans bool, x Matrix)
+}
+
+var (
+	_ MulToer = MatrixOperator{}
+	_ MulToer = TransposeOperator{}
+)
+
+// MatrixOperator is an Operator computing the products of a Matrix with
+// vectors and matrices.
+type MatrixOperator struct {
+	Matrix Matrix
+}
+
+// Dims returns the dimensions of the matrix.
+func (a MatrixOperator) Dims() (r, c int) {
+	return a.Matrix.Dims()
+}
+
+// MulVecTo stores A*x into dst if trans is false, and Aᵀ*x into dst
+// otherwise.
+func (a MatrixOperator) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	if trans {
+		dst.MulVec(a.Matrix.T(), x)
+		return
+	}
+	dst.MulVec(a.Matrix, x)
+}
+
+// MulTo stores A*X into dst if trans is false, and Aᵀ*X into dst
+// otherwise.
+func (a MatrixOperator) MulTo(dst *Dense, trans bool, x Matrix) {
+	if trans {
+		dst.Mul(a.Matrix.T(), x)
+		return
+	}
+	dst.Mul(a.Matrix, x)
+}
+
+// TransposeOperator is an Operator representing the transpose of the
+// Operator within.
+type TransposeOperator struct {
+	Operator Operator
+}
+
+// Dims returns the dimensions of the transposed operator.
+func (t TransposeOperator) Dims() (r, c int) {
+	c, r = t.Operator.Dims()
+	return r, c
+}
+
+// MulVecTo stores Aᵀ*x into dst if trans is false, and A*x into dst
+// otherwise.
+func (t TransposeOperator) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	t.Operator.MulVecTo(dst, !trans, x)
+}
+
+// MulTo stores Aᵀ*X into dst if trans is false, and A*X into dst
+// otherwise.
+func (t TransposeOperator) MulTo(dst *Dense, trans bool, x Matrix) {
+	mulOperator(dst, t.Operator, !trans, x)
+}
+
+// mulOperator stores A*X into dst if trans is false, and Aᵀ*X into dst
+// otherwise, using the MulTo method of a if it is a MulToer, and products
+// with the columns of X otherwise.
+func mulOperator(dst *Dense, a Operator, trans bool, x Matrix) {
+	if m, ok := a.(MulToer); ok {
+		m.MulTo(dst, trans, x)
+		return
+	}
+	r, c := a.Dims()
+	if trans {
+		r, c = c, r
+	}
+	xr, xc := x.Dims()
+	if xr != c {
+		panic(ErrShape)
+	}
+	dst.reuseAs(r, xc)
+	src := NewVecDense(c, nil)
+	col := NewVecDense(r, nil)
+	for j := 0; j < xc; j++ {
+		for i := 0; i < c; i++ {
+			src.setVec(i, x.At(i, j))
+		}
+		a.MulVecTo(col, trans, src)
+		dst.SetCol(j, col.mat.Data)
+	}
+}
+
+// ExpMulVec sets the receiver to exp(t*A)*b, the action of the exponential of
+// the n×n operator t*A on b, without forming the exponential, using only the
+// products of A and Aᵀ with vectors.
+//
+// ExpMulVec scales the interval [0, t] into s steps with |t*A|/s ≤ 1 using an
+// estimate of the 1-norm of A and applies a truncated Taylor series in each
+// step, following Al-Mohy and Higham, "Computing the action of the matrix
+// exponential, with an application to exponential integrators", SIAM J. Sci.
+// Comput. 33(2), 2011, without the shifting and balancing of A which require
+// its elements.
+//
+// ExpMulVec will panic if a is not square or if the length of b is not n.
+func (v *VecDense) ExpMulVec(t float64, a Operator, b Vector) {
+	const maxTerms = 55
+
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	if b.Len() != n {
+		panic(ErrShape)
+	}
+
+	steps := int(math.Ceil(math.Abs(t) * normEst1(a)))
+	if steps < 1 {
+		steps = 1
+	}
+	h := t / float64(steps)
+
+	x := NewVecDense(n, nil)
+	x.CopyVec(b)
+	term := NewVecDense(n, nil)
+	next := NewVecDense(n, nil)
+	for step := 0; step < steps; step++ {
+		// Sum the Taylor series of exp(h*A)*x until two
+		// successive terms are negligible.
+		term.CopyVec(x)
+		prev := math.Inf(1)
+		for k := 1; k <= maxTerms; k++ {
+			a.MulVecTo(next, false, term)
+			next.ScaleVec(h/float64(k), next)
+			term, next = next, term
+			x.AddVec(x, term)
+			tnorm := Norm(term, math.Inf(1))
+			if prev+tnorm <= machEps*Norm(x, math.Inf(1)) {
+				break
+			}
+			prev = tnorm
+		}
+	}
+
+	v.reuseAs(n)
+	v.CopyVec(x)
+}
+
+// normEst1 returns an estimate of the 1-norm of the square operator a using
+// Hager's method, "Condition estimates", SIAM J. Sci. Stat. Comput. 5(2),
+// 1984. The estimate is a lower bound that is usually within a small factor
+// of the 1-norm.
+func normEst1(a Operator) float64 {
+	const maxIter = 5
+
+	n, _ := a.Dims()
+	x := NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		x.setVec(i, 1/float64(n))
+	}
+	y := NewVecDense(n, nil)
+	xi := NewVecDense(n, nil)
+	z := NewVecDense(n, nil)
+	var est float64
+	for iter := 0; iter < maxIter; iter++ {
+		a.MulVecTo(y, false, x)
+		est = math.Max(est, Norm(y, 1))
+		for i, v := range y.mat.Data {
+			if v < 0 {
+				xi.mat.Data[i] = -1
+			} else {
+				xi.mat.Data[i] = 1
+			}
+		}
+		a.MulVecTo(z, true, xi)
+		j := 0
+		for i, v := range z.mat.Data {
+			if math.Abs(v) > math.Abs(z.mat.Data[j]) {
+				j = i
+			}
+		}
+		if iter > 0 && math.Abs(z.mat.Data[j]) <= Dot(z, x) {
+			break
+		}
+		zero(x.mat.Data)
+		x.mat.Data[j] = 1
+	}
+	return est
+}
@@ -0,0 +1,172 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// pathLaplacian is the matrix-free Laplacian of a path graph with n nodes.
+type pathLaplacian int
+
+func (l pathLaplacian) Dims() (r, c int) { return int(l), int(l) }
+
+func (l pathLaplacian) MulVecTo(dst *VecDense, _ bool, x Vector) {
+	n := int(l)
+	for i := 0; i < n; i++ {
+		v := 2 * x.AtVec(i)
+		if i == 0 || i == n-1 {
+			v = x.AtVec(i)
+		}
+		if i > 0 {
+			v -= x.AtVec(i - 1)
+		}
+		if i < n-1 {
+			v -= x.AtVec(i + 1)
+		}
+		dst.SetVec(i, v)
+	}
+}
+
+// operatorDense returns the dense matrix of the operator a.
+func operatorDense(a Operator) *Dense {
+	r, c := a.Dims()
+	d := NewDense(r, c, nil)
+	e := NewVecDense(c, nil)
+	col := NewVecDense(r, nil)
+	for j := 0; j < c; j++ {
+		e.Zero()
+		e.SetVec(j, 1)
+		a.MulVecTo(col, false, e)
+		d.SetCol(j, col.RawVector().Data)
+	}
+	return d
+}
+
+// vecOnly hides the MulTo method of an Operator.
+type vecOnly struct {
+	Operator
+}
+
+func TestOperatorMul(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := randNormDense(7, 4, rnd)
+	x := randNormDense(4, 3, rnd)
+	xt := randNormDense(7, 3, rnd)
+	for _, op := range []Operator{
+		MatrixOperator{a},
+		vecOnly{MatrixOperator{a}},
+		TransposeOperator{TransposeOperator{vecOnly{MatrixOperator{a}}}},
+	} {
+		var got, want Dense
+		mulOperator(&got, op, false, x)
+		want.Mul(a, x)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected product for %T", op)
+		}
+		got.Reset()
+		want.Reset()
+		mulOperator(&got, op, true, xt)
+		want.Mul(a.T(), xt)
+		if !EqualApprox(&got, &want, 1e-14) {
+			t.Errorf("unexpected transpose product for %T", op)
+		}
+	}
+	if r, c := (TransposeOperator{MatrixOperator{a}}).Dims(); r != 4 || c != 7 {
+		t.Errorf("unexpected transpose dimensions: got %d×%d want 4×7", r, c)
+	}
+}
+
+func TestOperatorFactorize(t *testing.T) {
+	t.Parallel()
+	const n = 50
+	lap := pathLaplacian(n)
+
+	var es PartialEigenSym
+	if !es.FactorizeOperator(lap, 4, EigenLargest, false, &KrylovOptions{Src: rand.NewSource(1)}) {
+		t.Fatal("unexpected PartialEigenSym failure")
+	}
+	for i, got := range es.Values(nil) {
+		// The eigenvalues of the path Laplacian
+		// are 2 - 2*cos(π*k/n) for k in [0, n).
+		want := 2 - 2*math.Cos(math.Pi*float64(n-1-i)/n)
+		if math.Abs(got-want) > 1e-10 {
+			t.Errorf("unexpected eigenvalue %d: got %v want %v", i, got, want)
+		}
+	}
+
+	var e PartialEigen
+	if !e.FactorizeOperator(lap, 2, EigenLargest, false, &KrylovOptions{Src: rand.NewSource(1)}) {
+		t.Fatal("unexpected PartialEigen failure")
+	}
+	for i, got := range e.Values(nil) {
+		want := 2 - 2*math.Cos(math.Pi*float64(n-1-i)/n)
+		if math.Abs(real(got)-want) > 1e-10 || imag(got) != 0 {
+			t.Errorf("unexpected eigenvalue %d: got %v want %v", i, got, want)
+		}
+	}
+
+	var svd TruncatedSVD
+	opts := RandomizedSVDOptions{Oversample: n, Src: rand.NewSource(1)}
+	if !svd.FactorizeOperator(lap, 5, SVDThin, &opts) {
+		t.Fatal("unexpected TruncatedSVD failure")
+	}
+	var full SVD
+	full.Factorize(operatorDense(lap), SVDNone)
+	want := full.Values(nil)[:5]
+	got := svd.Values(nil)
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-10 {
+			t.Errorf("unexpected singular value %d: got %v want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExpMulVec(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		a    Operator
+		t    float64
+	}{
+		{name: "random", a: MatrixOperator{randNormDense(10, 10, rnd)}, t: 1},
+		{name: "random scaled", a: MatrixOperator{randNormDense(10, 10, rnd)}, t: -3.5},
+		{name: "zero time", a: MatrixOperator{randNormDense(5, 5, rnd)}, t: 0},
+		{name: "laplacian", a: pathLaplacian(30), t: -10},
+	} {
+		n, _ := test.a.Dims()
+		b := NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			b.SetVec(i, rnd.NormFloat64())
+		}
+
+		var ta, e Dense
+		ta.Scale(test.t, operatorDense(test.a))
+		e.Exp(&ta)
+		var want, got VecDense
+		want.MulVec(&e, b)
+		got.ExpMulVec(test.t, test.a, b)
+		if !EqualApprox(&got, &want, 1e-10) {
+			t.Errorf("%s: unexpected result:\ngot  %v\nwant %v", test.name, got.RawVector().Data, want.RawVector().Data)
+		}
+	}
+
+	// The heat equation on a path conserves the total mass
+	// and converges to the mean.
+	b := NewVecDense(20, nil)
+	b.SetVec(0, 20)
+	var got VecDense
+	got.ExpMulVec(-1e3, pathLaplacian(20), b)
+	for i := 0; i < 20; i++ {
+		if math.Abs(got.AtVec(i)-1) > 1e-8 {
+			t.Errorf("unexpected steady state element %d: got %v want 1", i, got.AtVec(i))
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const badPivotedCholesky = "mat: invalid pivoted Cholesky factorization"
+
+// PivotedCholesky is a type for creating and using the Cholesky factorization
+// with complete pivoting of a symmetric positive semidefinite matrix.
+//
+// The factorization has the form
+//  P^T * A * P = U^T * U,
+// where U is an upper triangular matrix with non-increasing diagonal and P is
+// a permutation matrix. If A has rank r, only the leading r rows of U are
+// non-zero, so the factorization is well defined for rank-deficient matrices
+// for which the Cholesky type fails.
+type PivotedCholesky struct {
+	chol *TriDense
+	piv  []int
+	rank int
+}
+
+// Factorize computes the Cholesky factorization with complete pivoting of the
+// symmetric positive semidefinite matrix a. The factorization terminates when
+// the largest remaining diagonal element of the Schur complement is less than
+// or equal to tol, and the number of completed steps is the rank of A. If tol is
+// negative, n*eps*max(A[k,k]) is used instead.
+//
+// Factorize returns whether the factorization was completed for the full
+// matrix, that is whether A is numerically positive definite. If Factorize
+// returns false, the factorization may still be used through its leading
+// Rank rows. Factorize does not check that A is positive semidefinite.
+func (c *PivotedCholesky) Factorize(a Symmetric, tol float64) (ok bool) {
+	n := a.Symmetric()
+	if c.chol == nil {
+		c.chol = NewTriDense(n, Upper, nil)
+	} else {
+		c.chol = NewTriDense(n, Upper, use(c.chol.mat.Data, n*n))
+	}
+	copySymIntoTriangle(c.chol, a)
+	c.piv = useInt(c.piv, n)
+
+	sym := c.chol.asSymBlas()
+	work := getFloats(2*n, false)
+	_, c.rank, ok = lapack64.Pstrf(sym, c.piv, tol, work)
+	putFloats(work)
+
+	// Zero the trailing rows of U which do not
+	// hold a part of the factor.
+	for i := c.rank; i < n; i++ {
+		zero(c.chol.mat.Data[i*c.chol.mat.Stride+i : i*c.chol.mat.Stride+n])
+	}
+	return ok
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (c *PivotedCholesky) isValid() bool {
+	return c.chol != nil && !c.chol.IsZero()
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (c *PivotedCholesky) Reset() {
+	if c.chol != nil {
+		c.chol.Reset()
+	}
+	c.piv = c.piv[:0]
+	c.rank = 0
+}
+
+// Rank returns the numerical rank of the factorized matrix determined by the
+// tolerance passed to Factorize.
+// Rank will panic if the receiver does not contain a factorization.
+func (c *PivotedCholesky) Rank() int {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	return c.rank
+}
+
+// Pivot returns the symmetric permutation applied to the factorized matrix
+// such that the jth row and column of P^T * A * P are the piv[j]th row and
+// column of A. The permutation matrix P^T can be constructed with
+// Dense.Permutation. If piv == nil, then new memory will be allocated,
+// otherwise the length of the input must be equal to the size of the
+// factorized matrix.
+// Pivot will panic if the receiver does not contain a factorization.
+func (c *PivotedCholesky) Pivot(piv []int) []int {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	n := c.chol.mat.N
+	if piv == nil {
+		piv = make([]int, n)
+	}
+	if len(piv) != n {
+		panic(badSliceLength)
+	}
+	copy(piv, c.piv)
+	return piv
+}
+
+// UTo extracts the n×n upper triangular matrix U from a pivoted Cholesky
+// decomposition into dst and returns the result. The rows of U below the
+// rank of the factorization are zero. If dst is nil a new TriDense is
+// allocated.
+//  P^T * A * P = U^T * U.
+// UTo will panic if the receiver does not contain a factorization.
+func (c *PivotedCholesky) UTo(dst *TriDense) *TriDense {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	n := c.chol.mat.N
+	if dst == nil {
+		dst = NewTriDense(n, Upper, make([]float64, n*n))
+	} else {
+		dst.reuseAs(n, Upper)
+	}
+	dst.Copy(c.chol)
+	return dst
+}
+
+// FactorTo extracts the r×n low-rank factor F of the factorized matrix into
+// dst and returns the result, where r is the rank of the factorization and
+//  A ≈ F^T * F.
+// F is the leading r rows of U with the permutation undone, so that column
+// piv[j] of F is column j of U. If dst is nil a new Dense is allocated,
+// otherwise dst must be empty or r×n.
+// FactorTo will panic if the receiver does not contain a factorization.
+func (c *PivotedCholesky) FactorTo(dst *Dense) *Dense {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	n := c.chol.mat.N
+	r := c.rank
+	if dst == nil {
+		dst = NewDense(r, n, nil)
+	} else {
+		dst.reuseAsZeroed(r, n)
+	}
+	if r == 0 {
+		return dst
+	}
+	u := c.chol.mat
+	for i := 0; i < r; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		for j := i; j < n; j++ {
+			row[c.piv[j]] = u.Data[i*u.Stride+j]
+		}
+	}
+	return dst
+}
+
+// SolveTo finds a solution of the linear system
+//  A * X = B
+// using the pivoted Cholesky factorization of A, storing the result into dst.
+// If A has full rank, X is the exact solution. Otherwise X is the basic
+// solution that is non-zero only in the Rank elements chosen as pivots, which
+// solves the system exactly if the columns of B are in the range of A, and
+// a Condition error is returned. See the documentation for Condition for more
+// information.
+// SolveTo will panic if the receiver does not contain a factorization or if
+// its rank is zero.
+func (c *PivotedCholesky) SolveTo(dst *Dense, b Matrix) error {
+	if !c.isValid() {
+		panic(badPivotedCholesky)
+	}
+	n := c.chol.mat.N
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	r := c.rank
+	if r == 0 {
+		panic(ErrZeroLength)
+	}
+
+	// Apply P^T to B, keeping the rows corresponding to the pivots.
+	w := getWorkspace(r, bc, false)
+	for i := 0; i < r; i++ {
+		for j := 0; j < bc; j++ {
+			w.mat.Data[i*w.mat.Stride+j] = b.At(c.piv[i], j)
+		}
+	}
+	u11 := blas64.Triangular{
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+		N:      r,
+		Stride: c.chol.mat.Stride,
+		Data:   c.chol.mat.Data,
+	}
+	blas64.Trsm(blas.Left, blas.Trans, 1, u11, w.mat)
+	blas64.Trsm(blas.Left, blas.NoTrans, 1, u11, w.mat)
+
+	dst.reuseAsZeroed(n, bc)
+	for i := 0; i < r; i++ {
+		copy(dst.mat.Data[c.piv[i]*dst.mat.Stride:c.piv[i]*dst.mat.Stride+bc], w.mat.Data[i*w.mat.Stride:i*w.mat.Stride+bc])
+	}
+	putWorkspace(w)
+	if r < n {
+		return Condition(math.Inf(1))
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPivotedCholesky(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, rank int
+	}{
+		{1, 1}, {3, 3}, {5, 2}, {10, 10}, {10, 1}, {40, 17}, {100, 100}, {100, 60},
+	} {
+		n, rank := test.n, test.rank
+		x := randNormDense(n, rank, rnd)
+		var a SymDense
+		a.SymOuterK(1, x)
+
+		var c PivotedCholesky
+		ok := c.Factorize(&a, 1e-10*maxDiag(&a))
+		if ok != (rank == n) {
+			t.Errorf("unexpected ok for n=%d rank=%d: %t", n, rank, ok)
+		}
+		if c.Rank() != rank {
+			t.Errorf("unexpected rank for n=%d: got %d want %d", n, c.Rank(), rank)
+		}
+
+		// Check A = F^T * F.
+		f := c.FactorTo(nil)
+		var ftf Dense
+		ftf.Mul(f.T(), f)
+		if !EqualApprox(&ftf, &a, 1e-12) {
+			t.Errorf("unexpected factor for n=%d rank=%d", n, rank)
+		}
+
+		// Check P^T * A * P = U^T * U.
+		var p, pap, utu Dense
+		p.Permutation(n, c.Pivot(nil))
+		pap.Product(&p, &a, p.T())
+		u := c.UTo(nil)
+		utu.Mul(u.T(), u)
+		if !EqualApprox(&pap, &utu, 1e-12) {
+			t.Errorf("unexpected U for n=%d rank=%d", n, rank)
+		}
+
+		// Check that the solution of a consistent system is exact.
+		b := randNormDense(n, 3, rnd)
+		var y, ax Dense
+		y.Mul(&a, b)
+		var xs Dense
+		err := c.SolveTo(&xs, &y)
+		if (err != nil) != (rank < n) {
+			t.Errorf("unexpected error for n=%d rank=%d: %v", n, rank, err)
+		}
+		ax.Mul(&a, &xs)
+		if !EqualApprox(&ax, &y, 1e-8) {
+			t.Errorf("unexpected solution for n=%d rank=%d", n, rank)
+		}
+	}
+}
+
+// maxDiag returns the largest diagonal element of a.
+func maxDiag(a *SymDense) float64 {
+	var m float64
+	for i := 0; i < a.Symmetric(); i++ {
+		if v := a.At(i, i); v > m {
+			m = v
+		}
+	}
+	return m
+}
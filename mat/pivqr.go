@@ -0,0 +1,210 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const badPivotedQR = "mat: invalid pivoted QR factorization"
+
+// PivotedQR is a type for creating and using the QR factorization with column
+// pivoting of a matrix.
+//
+// The factorization has the form
+//  A * P = Q * R,
+// where Q is an orthonormal matrix, R is upper trapezoidal with diagonal
+// elements of non-increasing magnitude and P is a permutation matrix. The
+// magnitudes of the diagonal of R reveal the numerical rank of A, so the
+// factorization can be used to find basic solutions of rank-deficient least
+// squares problems.
+type PivotedQR struct {
+	qr  *Dense
+	tau []float64
+	piv []int
+}
+
+// Factorize computes the QR factorization with column pivoting of an m×n matrix
+// a where m >= n.
+func (qr *PivotedQR) Factorize(a Matrix) {
+	m, n := a.Dims()
+	if m < n {
+		panic(ErrShape)
+	}
+	if qr.qr == nil {
+		qr.qr = &Dense{}
+	}
+	qr.qr.Reset()
+	qr.qr.reuseAs(m, n)
+	qr.qr.Copy(a)
+	qr.tau = use(qr.tau, n)
+	qr.piv = useInt(qr.piv, n)
+	for i := range qr.piv {
+		// All columns are free.
+		qr.piv[i] = -1
+	}
+	work := []float64{0}
+	lapack64.Geqp3(qr.qr.mat, qr.piv, qr.tau, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Geqp3(qr.qr.mat, qr.piv, qr.tau, work, len(work))
+	putFloats(work)
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (qr *PivotedQR) isValid() bool {
+	return qr.qr != nil && !qr.qr.IsZero()
+}
+
+// Rank returns the numerical rank of the factorized matrix, the number of
+// diagonal elements of R with magnitude greater than tol*|R[0,0]|. If tol is
+// negative, max(m,n)*eps is used instead.
+// Rank will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) Rank(tol float64) int {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, n := qr.qr.Dims()
+	if tol < 0 {
+		tol = float64(max(m, n)) * machEps
+	}
+	r := qr.qr.mat
+	thresh := tol * math.Abs(r.Data[0])
+	var rank int
+	for ; rank < n; rank++ {
+		if math.Abs(r.Data[rank*r.Stride+rank]) <= thresh {
+			break
+		}
+	}
+	return rank
+}
+
+// Pivot returns the column permutation applied to the factorized matrix such
+// that the jth column of A * P is the piv[j]th column of A. The permutation
+// matrix P^T can be constructed with Dense.Permutation. If piv == nil, then
+// new memory will be allocated, otherwise the length of the input must be
+// equal to the number of columns of the factorized matrix.
+// Pivot will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) Pivot(piv []int) []int {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	_, n := qr.qr.Dims()
+	if piv == nil {
+		piv = make([]int, n)
+	}
+	if len(piv) != n {
+		panic(badSliceLength)
+	}
+	copy(piv, qr.piv)
+	return piv
+}
+
+// RTo extracts the m×n upper trapezoidal matrix from a pivoted QR decomposition.
+// If dst is nil, a new matrix is allocated. The resulting dst matrix is returned.
+// RTo will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) RTo(dst *Dense) *Dense {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	r, c := qr.qr.Dims()
+	if dst == nil {
+		dst = NewDense(r, c, nil)
+	} else {
+		dst.reuseAsZeroed(r, c)
+	}
+	dst.Copy(qr.qr.asTriDense(c, blas.NonUnit, blas.Upper))
+	return dst
+}
+
+// QTo extracts the m×m orthonormal matrix Q from a pivoted QR decomposition.
+// If dst is nil, a new matrix is allocated. The resulting Q matrix is returned.
+// QTo will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) QTo(dst *Dense) *Dense {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	r, _ := qr.qr.Dims()
+	if dst == nil {
+		dst = NewDense(r, r, nil)
+	} else {
+		dst.reuseAsZeroed(r, r)
+	}
+
+	// Set Q = I.
+	for i := 0; i < r*r; i += r + 1 {
+		dst.mat.Data[i] = 1
+	}
+
+	// Construct Q from the elementary reflectors.
+	work := []float64{0}
+	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, len(work))
+	putFloats(work)
+
+	return dst
+}
+
+// SolveTo finds a basic solution to the least squares problem
+//  minimize ||A*X - B||_2
+// where A is an m×n matrix represented in its pivoted QR factorized form,
+// storing the result into dst. The rank k of A is determined by tol as for
+// Rank, and only the k elements of each column of X corresponding to the
+// leading columns of A * P are non-zero. If k < n, the solution is not the
+// minimum norm solution and a Condition error is returned. See the
+// documentation for Condition for more information.
+// SolveTo will panic if the receiver does not contain a factorization or if
+// the rank of A is zero.
+func (qr *PivotedQR) SolveTo(dst *Dense, b Matrix, tol float64) error {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	r, c := qr.qr.Dims()
+	br, bc := b.Dims()
+	if br != r {
+		panic(ErrShape)
+	}
+	k := qr.Rank(tol)
+	if k == 0 {
+		panic(ErrZeroLength)
+	}
+
+	// Compute Q^T * B in place.
+	w := getWorkspace(r, bc, false)
+	w.Copy(b)
+	work := []float64{0}
+	lapack64.Ormqr(blas.Left, blas.Trans, qr.qr.mat, qr.tau, w.mat, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Ormqr(blas.Left, blas.Trans, qr.qr.mat, qr.tau, w.mat, work, len(work))
+	putFloats(work)
+
+	// Solve R11 * Y = (Q^T * B)[:k].
+	r11 := blas64.Triangular{
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+		N:      k,
+		Stride: qr.qr.mat.Stride,
+		Data:   qr.qr.mat.Data,
+	}
+	y := w.mat
+	y.Rows = k
+	blas64.Trsm(blas.Left, blas.NoTrans, 1, r11, y)
+
+	// Undo the permutation, X[piv[j]] = Y[j].
+	dst.reuseAsZeroed(c, bc)
+	for j := 0; j < k; j++ {
+		p := qr.piv[j]
+		copy(dst.mat.Data[p*dst.mat.Stride:p*dst.mat.Stride+bc], y.Data[j*y.Stride:j*y.Stride+bc])
+	}
+	putWorkspace(w)
+	if k < c {
+		return Condition(math.Inf(1))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPivotedQR(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{1, 1, 1}, {5, 3, 3}, {5, 5, 2}, {10, 4, 1}, {20, 10, 10}, {50, 30, 12}, {100, 80, 80},
+	} {
+		m, n, rank := test.m, test.n, test.rank
+		var a Dense
+		a.Mul(randNormDense(m, rank, rnd), randNormDense(rank, n, rnd))
+
+		var qr PivotedQR
+		qr.Factorize(&a)
+		if got := qr.Rank(-1); got != rank {
+			t.Errorf("unexpected rank for m=%d n=%d: got %d want %d", m, n, got, rank)
+		}
+
+		// Check A * P = Q * R.
+		var p, ap, qrm Dense
+		p.Permutation(n, qr.Pivot(nil))
+		ap.Mul(&a, p.T())
+		qrm.Mul(qr.QTo(nil), qr.RTo(nil))
+		if !EqualApprox(&ap, &qrm, 1e-12) {
+			t.Errorf("unexpected factorization for m=%d n=%d rank=%d", m, n, rank)
+		}
+		r := qr.RTo(nil)
+		for i := 1; i < n; i++ {
+			if math.Abs(r.At(i, i)) > math.Abs(r.At(i-1, i-1)) {
+				t.Errorf("diagonal of R not non-increasing for m=%d n=%d rank=%d", m, n, rank)
+				break
+			}
+		}
+
+		// Check that a solution of a consistent system is exact.
+		var y, ax, x Dense
+		y.Mul(&a, randNormDense(n, 2, rnd))
+		err := qr.SolveTo(&x, &y, -1)
+		if (err != nil) != (rank < n) {
+			t.Errorf("unexpected error for m=%d n=%d rank=%d: %v", m, n, rank, err)
+		}
+		ax.Mul(&a, &x)
+		if !EqualApprox(&ax, &y, 1e-8) {
+			t.Errorf("unexpected solution for m=%d n=%d rank=%d", m, n, rank)
+		}
+	}
+}
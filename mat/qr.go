@@ -22,7 +22,7 @@ type QR struct {
 	cond float64
 }
 
-func (qr *QR) updateCond(norm lapack.MatrixNorm) {
+func (qr *QR) updateCond(norm lapack.MatrixNorm, ws *Workspace) {
 	// Since A = Q*R, and Q is orthogonal, we get for the condition number κ
 	//  κ(A) := |A| |A^-1| = |Q*R| |(Q*R)^-1| = |R| |R^-1 * Q^T|
 	//        = |R| |R^-1| = κ(R),
@@ -31,12 +31,12 @@ func (qr *QR) updateCond(norm lapack.MatrixNorm) {
 	// is not the case for CondNorm. Hopefully the error is negligible: κ
 	// is only a qualitative measure anyway.
 	n := qr.qr.mat.Cols
-	work := getFloats(3*n, false)
-	iwork := getInts(n, false)
+	work := ws.getFloats(wsNorm, 3*n, false)
+	iwork := ws.getInts(n, false)
 	r := qr.qr.asTriDense(n, blas.NonUnit, blas.Upper)
 	v := lapack64.Trcon(norm, r.mat, work, iwork)
-	putFloats(work)
-	putInts(iwork)
+	ws.putFloats(work)
+	ws.putInts(iwork)
 	qr.cond = 1 / v
 }
 
@@ -47,10 +47,18 @@ func (qr *QR) updateCond(norm lapack.MatrixNorm) {
 // The matrix Q is an orthonormal m×m matrix, and R is an m×n upper triangular matrix.
 // Q and R can be extracted using the QTo and RTo methods.
 func (qr *QR) Factorize(a Matrix) {
-	qr.factorize(a, CondNorm)
+	qr.factorize(a, CondNorm, nil)
+}
+
+// FactorizeWork computes the QR factorization of the m×n matrix a as for
+// Factorize, using ws for scratch memory. Repeated factorizations of matrices
+// of the same size into the same receiver using the same ws do not allocate.
+// If ws is nil, FactorizeWork is equivalent to Factorize.
+func (qr *QR) FactorizeWork(a Matrix, ws *Workspace) {
+	qr.factorize(a, CondNorm, ws)
 }
 
-func (qr *QR) factorize(a Matrix, norm lapack.MatrixNorm) {
+func (qr *QR) factorize(a Matrix, norm lapack.MatrixNorm, ws *Workspace) {
 	m, n := a.Dims()
 	if m < n {
 		panic(ErrShape)
@@ -59,14 +67,16 @@ func (qr *QR) factorize(a Matrix, norm lapack.MatrixNorm) {
 	if qr.qr == nil {
 		qr.qr = &Dense{}
 	}
-	qr.qr.CloneFrom(a)
-	work := []float64{0}
-	qr.tau = make([]float64, k)
+	qr.qr.Reset()
+	qr.qr.reuseAs(m, n)
+	qr.qr.Copy(a)
+	qr.tau = use(qr.tau, k)
+	work := ws.getFloats(wsWork, 1, false)
 	lapack64.Geqrf(qr.qr.mat, qr.tau, work, -1)
-	work = getFloats(int(work[0]), false)
+	work = ws.getFloats(wsWork, ws.lwork(work), false)
 	lapack64.Geqrf(qr.qr.mat, qr.tau, work, len(work))
-	putFloats(work)
-	qr.updateCond(norm)
+	ws.putFloats(work)
+	qr.updateCond(norm, ws)
 }
 
 // isValid returns whether the receiver contains a factorization.
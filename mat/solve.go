@@ -72,6 +72,8 @@ func (m *Dense) Solve(a, b Matrix) error {
 			return Condition(cond)
 		}
 		return nil
+	case *BlockDiagonal:
+		return rma.SolveTo(m, aTrans, b)
 	}
 
 	switch {
@@ -0,0 +1,493 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "sort"
+
+var (
+	csr *CSR
+
+	_ Matrix         = csr
+	_ NonZeroDoer    = csr
+	_ RowNonZeroDoer = csr
+	_ ColNonZeroDoer = csr
+
+	csc *CSC
+
+	_ Matrix         = csc
+	_ NonZeroDoer    = csc
+	_ RowNonZeroDoer = csc
+	_ ColNonZeroDoer = csc
+
+	coo *COO
+
+	_ Matrix = coo
+)
+
+// compressed is a compressed sparse row representation of a matrix. The
+// column indices of the non-zero elements of row i are held in
+// ind[indptr[i]:indptr[i+1]] in strictly increasing order, and their
+// values in the corresponding elements of data.
+type compressed struct {
+	rows, cols int
+	indptr     []int
+	ind        []int
+	data       []float64
+}
+
+// newCompressed returns a compressed matrix with the given storage after
+// checking its validity. Out of range indices in ind panic with minorErr.
+func newCompressed(rows, cols int, indptr, ind []int, data []float64, minorErr Error) compressed {
+	if rows <= 0 || cols <= 0 {
+		if rows == 0 || cols == 0 {
+			panic(ErrZeroLength)
+		}
+		panic("mat: negative dimension")
+	}
+	if len(indptr) != rows+1 || len(ind) != len(data) {
+		panic(ErrSliceLengthMismatch)
+	}
+	if indptr[0] != 0 || indptr[rows] != len(ind) {
+		panic("mat: invalid sparse index pointer")
+	}
+	for i := 0; i < rows; i++ {
+		if indptr[i] > indptr[i+1] {
+			panic("mat: invalid sparse index pointer")
+		}
+		for k := indptr[i]; k < indptr[i+1]; k++ {
+			if ind[k] < 0 || cols <= ind[k] {
+				panic(minorErr)
+			}
+			if k > indptr[i] && ind[k] <= ind[k-1] {
+				panic("mat: sparse indices not strictly increasing")
+			}
+		}
+	}
+	return compressed{rows: rows, cols: cols, indptr: indptr, ind: ind, data: data}
+}
+
+// at returns the element at row i and column j. The indices are not
+// checked.
+func (c *compressed) at(i, j int) float64 {
+	lo, hi := c.indptr[i], c.indptr[i+1]
+	k := lo + sort.SearchInts(c.ind[lo:hi], j)
+	if k < hi && c.ind[k] == j {
+		return c.data[k]
+	}
+	return 0
+}
+
+// transpose returns the compressed representation of the transpose of c.
+func (c *compressed) transpose() *compressed {
+	t := &compressed{
+		rows:   c.cols,
+		cols:   c.rows,
+		indptr: make([]int, c.cols+1),
+		ind:    make([]int, len(c.ind)),
+		data:   make([]float64, len(c.data)),
+	}
+	for _, j := range c.ind {
+		t.indptr[j+1]++
+	}
+	for j := 0; j < c.cols; j++ {
+		t.indptr[j+1] += t.indptr[j]
+	}
+	next := make([]int, c.cols)
+	copy(next, t.indptr)
+	for i := 0; i < c.rows; i++ {
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			j := c.ind[k]
+			t.ind[next[j]] = i
+			t.data[next[j]] = c.data[k]
+			next[j]++
+		}
+	}
+	return t
+}
+
+// doNonZero calls fn for each stored element of c in row-major order.
+func (c *compressed) doNonZero(fn func(i, j int, v float64)) {
+	for i := 0; i < c.rows; i++ {
+		for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+			if v := c.data[k]; v != 0 {
+				fn(i, c.ind[k], v)
+			}
+		}
+	}
+}
+
+// doRowNonZero calls fn for each stored element in row i of c.
+func (c *compressed) doRowNonZero(i int, fn func(i, j int, v float64)) {
+	for k := c.indptr[i]; k < c.indptr[i+1]; k++ {
+		if v := c.data[k]; v != 0 {
+			fn(i, c.ind[k], v)
+		}
+	}
+}
+
+// doColNonZero calls fn for each stored element in column j of c.
+func (c *compressed) doColNonZero(j int, fn func(i, j int, v float64)) {
+	for i := 0; i < c.rows; i++ {
+		if v := c.at(i, j); v != 0 {
+			fn(i, j, v)
+		}
+	}
+}
+
+// CSR is a sparse matrix in compressed sparse row format. The zero value
+// of a CSR is an empty matrix that may be used as the receiver of Mul.
+type CSR struct {
+	mat compressed
+}
+
+// NewCSR returns a new r×c sparse matrix in compressed sparse row format.
+// The column indices of the non-zero elements in row i are held in
+// ind[indptr[i]:indptr[i+1]] and must be strictly increasing, and their
+// values are held in the corresponding elements of data. The length of
+// indptr must be r+1 with indptr[0] equal to zero and indptr[r] equal to
+// the lengths of ind and data. The slices are used as the backing storage
+// of the returned matrix. NewCSR panics if the storage is not valid or
+// either of r or c is zero.
+//
+// For example, the matrix
+//
+//	1 0 2
+//	0 0 3
+//	4 5 0
+//
+// has indptr {0, 2, 3, 5}, ind {0, 2, 2, 0, 1} and data {1, 2, 3, 4, 5}.
+func NewCSR(r, c int, indptr, ind []int, data []float64) *CSR {
+	return &CSR{mat: newCompressed(r, c, indptr, ind, data, ErrColAccess)}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSR) Dims() (r, c int) {
+	return m.mat.rows, m.mat.cols
+}
+
+// At returns the element at row i, column j. At takes O(log n) time for a
+// row with n stored elements.
+func (m *CSR) At(i, j int) float64 {
+	if uint(i) >= uint(m.mat.rows) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.cols) {
+		panic(ErrColAccess)
+	}
+	return m.mat.at(i, j)
+}
+
+// T performs an implicit transpose by returning the receiver's storage
+// as a CSC.
+func (m *CSR) T() Matrix {
+	return &CSC{mat: m.mat}
+}
+
+// NNZ returns the number of stored elements in the matrix.
+func (m *CSR) NNZ() int {
+	return len(m.mat.data)
+}
+
+// RawCSR returns the backing storage of the receiver. Changes to the
+// elements of data will be reflected in the receiver. The index slices
+// must not be altered.
+func (m *CSR) RawCSR() (indptr, ind []int, data []float64) {
+	return m.mat.indptr, m.mat.ind, m.mat.data
+}
+
+// IsZero returns whether the receiver is zero-sized. Zero-sized matrices can be the
+// receiver for size-restricted operations. CSR matrices can be zeroed using Reset.
+func (m *CSR) IsZero() bool {
+	return m.mat.rows == 0
+}
+
+// Reset zeros the dimensions of the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+func (m *CSR) Reset() {
+	m.mat = compressed{}
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *CSR) DoNonZero(fn func(i, j int, v float64)) {
+	m.mat.doNonZero(fn)
+}
+
+// DoRowNonZero calls the function fn for each of the non-zero elements of row i of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *CSR) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if uint(i) >= uint(m.mat.rows) {
+		panic(ErrRowAccess)
+	}
+	m.mat.doRowNonZero(i, fn)
+}
+
+// DoColNonZero calls the function fn for each of the non-zero elements of column j of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *CSR) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	if uint(j) >= uint(m.mat.cols) {
+		panic(ErrColAccess)
+	}
+	m.mat.doColNonZero(j, fn)
+}
+
+// ToCSC returns a copy of the receiver in compressed sparse column format.
+func (m *CSR) ToCSC() *CSC {
+	return &CSC{mat: *m.mat.transpose()}
+}
+
+// CSC is a sparse matrix in compressed sparse column format. The zero
+// value of a CSC is an empty matrix that may be used as the receiver of
+// Mul.
+type CSC struct {
+	// mat holds the compressed sparse
+	// row representation of the
+	// transpose of the matrix.
+	mat compressed
+}
+
+// NewCSC returns a new r×c sparse matrix in compressed sparse column
+// format. The row indices of the non-zero elements in column j are held in
+// ind[indptr[j]:indptr[j+1]] and must be strictly increasing, and their
+// values are held in the corresponding elements of data. The length of
+// indptr must be c+1 with indptr[0] equal to zero and indptr[c] equal to
+// the lengths of ind and data. The slices are used as the backing storage
+// of the returned matrix. NewCSC panics if the storage is not valid or
+// either of r or c is zero.
+func NewCSC(r, c int, indptr, ind []int, data []float64) *CSC {
+	return &CSC{mat: newCompressed(c, r, indptr, ind, data, ErrRowAccess)}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSC) Dims() (r, c int) {
+	return m.mat.cols, m.mat.rows
+}
+
+// At returns the element at row i, column j. At takes O(log n) time for a
+// column with n stored elements.
+func (m *CSC) At(i, j int) float64 {
+	if uint(i) >= uint(m.mat.cols) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.rows) {
+		panic(ErrColAccess)
+	}
+	return m.mat.at(j, i)
+}
+
+// T performs an implicit transpose by returning the receiver's storage
+// as a CSR.
+func (m *CSC) T() Matrix {
+	return &CSR{mat: m.mat}
+}
+
+// NNZ returns the number of stored elements in the matrix.
+func (m *CSC) NNZ() int {
+	return len(m.mat.data)
+}
+
+// RawCSC returns the backing storage of the receiver. Changes to the
+// elements of data will be reflected in the receiver. The index slices
+// must not be altered.
+func (m *CSC) RawCSC() (indptr, ind []int, data []float64) {
+	return m.mat.indptr, m.mat.ind, m.mat.data
+}
+
+// IsZero returns whether the receiver is zero-sized. Zero-sized matrices can be the
+// receiver for size-restricted operations. CSC matrices can be zeroed using Reset.
+func (m *CSC) IsZero() bool {
+	return m.mat.rows == 0
+}
+
+// Reset zeros the dimensions of the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+func (m *CSC) Reset() {
+	m.mat = compressed{}
+}
+
+// DoNonZero calls the function fn for each of the non-zero elements of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *CSC) DoNonZero(fn func(i, j int, v float64)) {
+	m.mat.doNonZero(func(j, i int, v float64) { fn(i, j, v) })
+}
+
+// DoRowNonZero calls the function fn for each of the non-zero elements of row i of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *CSC) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if uint(i) >= uint(m.mat.cols) {
+		panic(ErrRowAccess)
+	}
+	m.mat.doColNonZero(i, func(j, i int, v float64) { fn(i, j, v) })
+}
+
+// DoColNonZero calls the function fn for each of the non-zero elements of column j of m. The function fn
+// takes a row/column index and the element value of m at (i, j).
+func (m *CSC) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	if uint(j) >= uint(m.mat.rows) {
+		panic(ErrColAccess)
+	}
+	m.mat.doRowNonZero(j, func(j, i int, v float64) { fn(i, j, v) })
+}
+
+// ToCSR returns a copy of the receiver in compressed sparse row format.
+func (m *CSC) ToCSR() *CSR {
+	return &CSR{mat: *m.mat.transpose()}
+}
+
+// COO is a sparse matrix in coordinate format, holding a list of
+// (row, column, value) triplets. Triplets with the same row and column
+// are summed, so a COO is suited to the incremental construction of
+// sparse matrices that are then converted to CSR or CSC format for
+// computation with ToCSR or ToCSC.
+type COO struct {
+	r, c       int
+	rows, cols []int
+	data       []float64
+}
+
+// NewCOO returns a new r×c sparse matrix in coordinate format holding the
+// triplets (rows[k], cols[k], data[k]). The slices are used as the
+// initial backing storage of the returned matrix and may be nil. NewCOO
+// panics if the slices are not the same length, if any index is out of
+// range or if either of r or c is not positive.
+func NewCOO(r, c int, rows, cols []int, data []float64) *COO {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic("mat: negative dimension")
+	}
+	if len(rows) != len(data) || len(cols) != len(data) {
+		panic(ErrSliceLengthMismatch)
+	}
+	for k := range data {
+		if uint(rows[k]) >= uint(r) {
+			panic(ErrRowAccess)
+		}
+		if uint(cols[k]) >= uint(c) {
+			panic(ErrColAccess)
+		}
+	}
+	return &COO{r: r, c: c, rows: rows, cols: cols, data: data}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *COO) Dims() (r, c int) {
+	return m.r, m.c
+}
+
+// At returns the element at row i, column j. At takes time linear in the
+// number of triplets.
+func (m *COO) At(i, j int) float64 {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	var v float64
+	for k, r := range m.rows {
+		if r == i && m.cols[k] == j {
+			v += m.data[k]
+		}
+	}
+	return v
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *COO) T() Matrix {
+	return Transpose{m}
+}
+
+// NNZ returns the number of triplets in the matrix, including those with
+// duplicate indices.
+func (m *COO) NNZ() int {
+	return len(m.data)
+}
+
+// Append adds the triplet (i, j, v) to the matrix, adding v to the element
+// at row i, column j.
+func (m *COO) Append(i, j int, v float64) {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	m.rows = append(m.rows, i)
+	m.cols = append(m.cols, j)
+	m.data = append(m.data, v)
+}
+
+// ToCSR returns the matrix in compressed sparse row format. Triplets with
+// the same indices are summed and elements that are zero are not stored.
+// ToCSR takes O(n+r+c) time for n triplets in an r×c matrix.
+func (m *COO) ToCSR() *CSR {
+	return &CSR{mat: compress(m.r, m.c, m.rows, m.cols, m.data)}
+}
+
+// ToCSC returns the matrix in compressed sparse column format. Triplets
+// with the same indices are summed and elements that are zero are not
+// stored. ToCSC takes O(n+r+c) time for n triplets in an r×c matrix.
+func (m *COO) ToCSC() *CSC {
+	return &CSC{mat: compress(m.c, m.r, m.cols, m.rows, m.data)}
+}
+
+// compress returns the compressed representation of the list of triplets
+// (major[k], minor[k], data[k]) for a matrix with dimensions n×m.
+func compress(n, m int, major, minor []int, data []float64) compressed {
+	// Sort the triplets by minor and then stably
+	// by major index with two counting sorts.
+	count := make([]int, max(n, m)+1)
+	order := make([]int, len(data))
+	for _, j := range minor {
+		count[j+1]++
+	}
+	for j := 0; j < m; j++ {
+		count[j+1] += count[j]
+	}
+	for k, j := range minor {
+		order[count[j]] = k
+		count[j]++
+	}
+	for i := range count {
+		count[i] = 0
+	}
+	for _, i := range major {
+		count[i+1]++
+	}
+	for i := 0; i < n; i++ {
+		count[i+1] += count[i]
+	}
+	indptr := make([]int, n+1)
+	copy(indptr, count[:n+1])
+	sorted := make([]int, len(data))
+	for _, k := range order {
+		i := major[k]
+		sorted[count[i]] = k
+		count[i]++
+	}
+
+	// Sum duplicates and drop zeros.
+	c := compressed{rows: n, cols: m, indptr: indptr}
+	var k int
+	for i := 0; i < n; i++ {
+		end := indptr[i+1]
+		indptr[i] = len(c.ind)
+		for k < end {
+			j := minor[sorted[k]]
+			var v float64
+			for ; k < end && minor[sorted[k]] == j; k++ {
+				v += data[sorted[k]]
+			}
+			if v != 0 {
+				c.ind = append(c.ind, j)
+				c.data = append(c.data, v)
+			}
+		}
+	}
+	indptr[n] = len(c.ind)
+	return c
+}
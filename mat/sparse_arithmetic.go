@@ -0,0 +1,305 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/internal/asm/f64"
+)
+
+// Mul takes the matrix product of a and b, placing the result in the
+// receiver. If the number of columns in a does not equal the number of
+// rows in b, Mul will panic. Elements of the product that are zero are
+// not stored.
+//
+// Mul is efficient when a and b are sparse matrices, or transposes of
+// sparse matrices, provided by this package.
+func (m *CSR) Mul(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	if !m.IsZero() && (m.mat.rows != ar || m.mat.cols != bc) {
+		panic(ErrShape)
+	}
+	m.mat = mulCompressed(csrOf(a), csrOf(b))
+}
+
+// Mul takes the matrix product of a and b, placing the result in the
+// receiver. If the number of columns in a does not equal the number of
+// rows in b, Mul will panic. Elements of the product that are zero are
+// not stored.
+//
+// Mul is efficient when a and b are sparse matrices, or transposes of
+// sparse matrices, provided by this package.
+func (m *CSC) Mul(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	if !m.IsZero() && (m.mat.cols != ar || m.mat.rows != bc) {
+		panic(ErrShape)
+	}
+	// The receiver holds the compressed
+	// rows of (AB)ᵀ = BᵀAᵀ.
+	m.mat = mulCompressed(csrOf(b.T()), csrOf(a.T()))
+}
+
+// mulCompressed returns the product of x and y using Gustavson's
+// algorithm.
+func mulCompressed(x, y *compressed) compressed {
+	c := compressed{rows: x.rows, cols: y.cols, indptr: make([]int, x.rows+1)}
+	acc := make([]float64, y.cols)
+	mark := make([]int, y.cols)
+	for j := range mark {
+		mark[j] = -1
+	}
+	var cols []int
+	for i := 0; i < x.rows; i++ {
+		cols = cols[:0]
+		for p := x.indptr[i]; p < x.indptr[i+1]; p++ {
+			k, v := x.ind[p], x.data[p]
+			for q := y.indptr[k]; q < y.indptr[k+1]; q++ {
+				j := y.ind[q]
+				if mark[j] != i {
+					mark[j] = i
+					acc[j] = 0
+					cols = append(cols, j)
+				}
+				acc[j] += v * y.data[q]
+			}
+		}
+		sort.Ints(cols)
+		for _, j := range cols {
+			if acc[j] != 0 {
+				c.ind = append(c.ind, j)
+				c.data = append(c.data, acc[j])
+			}
+		}
+		c.indptr[i+1] = len(c.ind)
+	}
+	return c
+}
+
+// compressedOf returns the compressed storage of a if it is a sparse
+// matrix or the transpose of a sparse matrix. The returned trans is true
+// if the storage holds the transpose of a. If a is not sparse, ok is
+// false.
+func compressedOf(a Matrix) (c *compressed, trans, ok bool) {
+	aU, trans := untranspose(a)
+	switch aU := aU.(type) {
+	case *CSR:
+		return &aU.mat, trans, true
+	case *CSC:
+		return &aU.mat, !trans, true
+	case *COO:
+		c := compress(aU.r, aU.c, aU.rows, aU.cols, aU.data)
+		return &c, trans, true
+	case *DIA:
+		return &aU.ToCSR().mat, trans, true
+	}
+	return nil, false, false
+}
+
+// csrOf returns the compressed sparse row storage of a, which is shared
+// with a if a is a *CSR.
+func csrOf(a Matrix) *compressed {
+	if c, trans, ok := compressedOf(a); ok {
+		if trans {
+			return c.transpose()
+		}
+		return c
+	}
+	r, c := a.Dims()
+	var rows, cols []int
+	var data []float64
+	if nz, ok := a.(NonZeroDoer); ok {
+		nz.DoNonZero(func(i, j int, v float64) {
+			rows = append(rows, i)
+			cols = append(cols, j)
+			data = append(data, v)
+		})
+	} else {
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				if v := a.At(i, j); v != 0 {
+					rows = append(rows, i)
+					cols = append(cols, j)
+					data = append(data, v)
+				}
+			}
+		}
+	}
+	s := compress(r, c, rows, cols, data)
+	return &s
+}
+
+// mulSparse places the product of a and b in the receiver if either is a
+// sparse matrix, and returns whether it did so. The receiver must have the
+// dimensions of the product and must not be a or b.
+func (m *Dense) mulSparse(a, b Matrix) bool {
+	if x, trans, ok := compressedOf(a); ok {
+		bU, _ := untranspose(b)
+		m.checkOverlapMatrix(bU)
+		bd, restore := denseOf(b)
+		defer restore()
+		m.Zero()
+		// Each stored element of a multiplies
+		// a row of b into a row of m.
+		for k := 0; k < x.rows; k++ {
+			for p := x.indptr[k]; p < x.indptr[k+1]; p++ {
+				row, inner := k, x.ind[p]
+				if trans {
+					row, inner = inner, k
+				}
+				f64.AxpyUnitary(x.data[p], bd.rawRowView(inner), m.rawRowView(row))
+			}
+		}
+		return true
+	}
+	if y, trans, ok := compressedOf(b); ok {
+		aU, _ := untranspose(a)
+		m.checkOverlapMatrix(aU)
+		ad, restore := denseOf(a)
+		defer restore()
+		r, _ := a.Dims()
+		for i := 0; i < r; i++ {
+			arow := ad.rawRowView(i)
+			mrow := m.rawRowView(i)
+			if trans {
+				// Row k of y is column k of b.
+				for k := 0; k < y.rows; k++ {
+					var v float64
+					for p := y.indptr[k]; p < y.indptr[k+1]; p++ {
+						v += y.data[p] * arow[y.ind[p]]
+					}
+					mrow[k] = v
+				}
+				continue
+			}
+			for j := range mrow {
+				mrow[j] = 0
+			}
+			for k, aik := range arow {
+				if aik == 0 {
+					continue
+				}
+				for p := y.indptr[k]; p < y.indptr[k+1]; p++ {
+					mrow[y.ind[p]] += aik * y.data[p]
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// denseOf returns a with row-major dense storage, and a function to release
+// any workspace that was used.
+func denseOf(a Matrix) (d *Dense, restore func()) {
+	if d, ok := a.(*Dense); ok {
+		return d, func() {}
+	}
+	r, c := a.Dims()
+	w := getWorkspace(r, c, false)
+	if x, trans, ok := compressedOf(a); ok {
+		w.Zero()
+		x.doNonZero(func(i, j int, v float64) {
+			if trans {
+				i, j = j, i
+			}
+			w.set(i, j, v)
+		})
+	} else {
+		w.Copy(a)
+	}
+	return w, func() { putWorkspace(w) }
+}
+
+// mulVecSparse places the product of a and the vector x in the receiver
+// if a is a sparse matrix, and returns whether it did so. The receiver must
+// have the length of the product and must not share storage with x.
+func (v *VecDense) mulVecSparse(a Matrix, x Vector) bool {
+	aU, trans := untranspose(a)
+	d, isDIA := aU.(*DIA)
+	var c *compressed
+	if !isDIA {
+		var ok bool
+		c, trans, ok = compressedOf(a)
+		if !ok {
+			return false
+		}
+	}
+
+	n := x.Len()
+	var xs []float64
+	if rv, ok := x.(*VecDense); ok && rv.mat.Inc == 1 {
+		xs = rv.mat.Data[:n]
+	} else {
+		xs = getFloats(n, false)
+		defer putFloats(xs)
+		for i := range xs {
+			xs[i] = x.AtVec(i)
+		}
+	}
+	r := v.Len()
+	var dst []float64
+	if v.mat.Inc == 1 {
+		dst = v.mat.Data[:r]
+	} else {
+		dst = getFloats(r, false)
+		defer func() {
+			for i, e := range dst {
+				v.setVec(i, e)
+			}
+			putFloats(dst)
+		}()
+	}
+
+	if isDIA {
+		for i := range dst {
+			dst[i] = 0
+		}
+		for p, k := range d.offsets {
+			lo, hi := d.span(k)
+			data := d.data[p*d.c : (p+1)*d.c]
+			for j := lo; j < hi; j++ {
+				if trans {
+					dst[j] += data[j] * xs[j-k]
+				} else {
+					dst[j-k] += data[j] * xs[j]
+				}
+			}
+		}
+		return true
+	}
+
+	if trans {
+		for i := range dst {
+			dst[i] = 0
+		}
+		for k := 0; k < c.rows; k++ {
+			f := xs[k]
+			if f == 0 {
+				continue
+			}
+			for p := c.indptr[k]; p < c.indptr[k+1]; p++ {
+				dst[c.ind[p]] += c.data[p] * f
+			}
+		}
+		return true
+	}
+	for i := range dst {
+		var f float64
+		for p := c.indptr[i]; p < c.indptr[i+1]; p++ {
+			f += c.data[p] * xs[c.ind[p]]
+		}
+		dst[i] = f
+	}
+	return true
+}
@@ -0,0 +1,448 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+const (
+	badSparseCholesky = "mat: invalid sparse Cholesky factorization"
+	badSparsePattern  = "mat: sparsity pattern not in factorization"
+)
+
+// SparseCholesky is a sparse symmetric positive definite matrix represented
+// by its Cholesky decomposition
+//
+//	P * A * Pᵀ = L * Lᵀ
+//
+// where P is a fill-reducing permutation and L is a sparse lower triangular
+// matrix.
+//
+// The decomposition is constructed using the Factorize method, which
+// computes an approximate minimum degree ordering of A, the structure of L
+// and its values. Matrices with the same sparsity pattern may then be
+// factorized using Refactorize, which reuses the ordering and structure.
+//
+// SparseCholesky methods other than Factorize and Reset may only be called
+// on a value that has been successfully initialized by a call to Factorize
+// or Refactorize that has returned true. Calls to methods of an
+// unsuccessful factorization will panic.
+type SparseCholesky struct {
+	n int
+
+	// perm holds the fill-reducing ordering
+	// and pinv its inverse.
+	perm, pinv []int
+
+	// parent is the elimination tree of
+	// the permuted matrix.
+	parent []int
+
+	// colptr, rowind and values hold L in
+	// compressed sparse column order, with
+	// the diagonal first in each column.
+	colptr []int
+	rowind []int
+	values []float64
+
+	cond float64
+	ok   bool
+}
+
+// Factorize calculates the sparse Cholesky decomposition of the matrix a
+// and returns whether the matrix is positive definite. Only the lower
+// triangle of a is used. If Factorize returns false, the factorization must
+// not be used. Factorize will panic if a is not square.
+//
+// Factorize is efficient when a is a sparse matrix provided by this package.
+func (c *SparseCholesky) Factorize(a Matrix) (ok bool) {
+	r, cols := a.Dims()
+	if r != cols {
+		panic(ErrSquare)
+	}
+	c.n = r
+	c.perm = AMD(a)
+	c.pinv = make([]int, r)
+	for k, i := range c.perm {
+		c.pinv[i] = k
+	}
+	c.analyze(c.permutedUpper(a))
+	return c.Refactorize(a)
+}
+
+// Refactorize calculates the sparse Cholesky decomposition of the matrix a
+// using the ordering and structure of a previous factorization, and returns
+// whether the matrix is positive definite. Refactorize is cheaper than
+// Factorize for sequences of matrices with the same sparsity pattern. The
+// receiver must hold a factorization from a call to Factorize, though that
+// factorization need not have succeeded. Refactorize will panic if the
+// dimensions of a do not match those of the factorized matrix, or if a has
+// a non-zero element in the lower triangle outside the pattern of the
+// originally factorized matrix.
+func (c *SparseCholesky) Refactorize(a Matrix) (ok bool) {
+	if c.parent == nil {
+		panic(badSparseCholesky)
+	}
+	if r, cols := a.Dims(); r != c.n || cols != c.n {
+		panic(ErrShape)
+	}
+	c.ok = false
+	n := c.n
+	up := c.permutedUpper(a)
+
+	x := getFloats(n, true)
+	defer putFloats(x)
+	stack := getInts(n, false)
+	defer putInts(stack)
+	pattern := getInts(n, false)
+	defer putInts(pattern)
+	mark := getInts(n, false)
+	defer putInts(mark)
+	for i := range mark {
+		mark[i] = -1
+	}
+	next := getInts(n, false)
+	defer putInts(next)
+	copy(next, c.colptr[:n])
+
+	// Compute row k of L in turn by a sparse
+	// triangular solve with the rows of L
+	// above it.
+	for k := 0; k < n; k++ {
+		top := ereach(up, k, c.parent, mark, stack, pattern)
+		for p := up.indptr[k]; p < up.indptr[k+1]; p++ {
+			x[up.ind[p]] = up.data[p]
+		}
+		d := x[k]
+		x[k] = 0
+		for _, i := range pattern[top:] {
+			lki := x[i] / c.values[c.colptr[i]]
+			x[i] = 0
+			for p := c.colptr[i] + 1; p < next[i]; p++ {
+				x[c.rowind[p]] -= c.values[p] * lki
+			}
+			d -= lki * lki
+			p := next[i]
+			if p == c.colptr[i+1] {
+				panic(badSparsePattern)
+			}
+			next[i]++
+			c.rowind[p] = k
+			c.values[p] = lki
+		}
+		if !(d > 0) {
+			return false
+		}
+		p := next[k]
+		next[k]++
+		c.rowind[p] = k
+		c.values[p] = math.Sqrt(d)
+	}
+
+	// A pattern that is a subset of the analyzed
+	// pattern leaves slots in L unfilled. Make them
+	// explicit zeros on the diagonal so that they
+	// do not contribute to solves.
+	for i := 0; i < n; i++ {
+		for p := next[i]; p < c.colptr[i+1]; p++ {
+			c.rowind[p] = i
+			c.values[p] = 0
+		}
+	}
+
+	// The condition number is at least the square
+	// of the ratio of the extreme diagonal values
+	// of L.
+	lo, hi := math.Inf(1), 0.0
+	for i := 0; i < n; i++ {
+		v := c.values[c.colptr[i]]
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	c.cond = (hi / lo) * (hi / lo)
+	c.ok = true
+	return true
+}
+
+// permutedUpper returns the upper triangle of P * A * Pᵀ with the elements of
+// each column held in the compressed rows of the returned value.
+func (c *SparseCholesky) permutedUpper(a Matrix) *compressed {
+	var rows, cols []int
+	var data []float64
+	csrOf(a).doNonZero(func(i, j int, v float64) {
+		if i < j {
+			// Only the lower triangle is used.
+			return
+		}
+		i, j = c.pinv[i], c.pinv[j]
+		if i > j {
+			i, j = j, i
+		}
+		rows = append(rows, i)
+		cols = append(cols, j)
+		data = append(data, v)
+	})
+	up := compress(c.n, c.n, cols, rows, data)
+	return &up
+}
+
+// analyze computes the elimination tree and the structure of L for the
+// permuted upper triangle up.
+func (c *SparseCholesky) analyze(up *compressed) {
+	n := c.n
+	c.parent = make([]int, n)
+	ancestor := make([]int, n)
+	for k := 0; k < n; k++ {
+		c.parent[k] = -1
+		ancestor[k] = -1
+		for p := up.indptr[k]; p < up.indptr[k+1]; p++ {
+			for i := up.ind[p]; i != -1 && i < k; {
+				next := ancestor[i]
+				ancestor[i] = k
+				if next == -1 {
+					c.parent[i] = k
+				}
+				i = next
+			}
+		}
+	}
+
+	// Count the elements in each column of L from
+	// the row patterns given by the elimination tree.
+	counts := make([]int, n)
+	mark := ancestor
+	for i := range mark {
+		mark[i] = -1
+	}
+	stack := make([]int, n)
+	pattern := make([]int, n)
+	for k := 0; k < n; k++ {
+		top := ereach(up, k, c.parent, mark, stack, pattern)
+		for _, i := range pattern[top:] {
+			counts[i]++
+		}
+		counts[k]++
+	}
+	c.colptr = make([]int, n+1)
+	for i, v := range counts {
+		c.colptr[i+1] = c.colptr[i] + v
+	}
+	c.rowind = make([]int, c.colptr[n])
+	c.values = make([]float64, c.colptr[n])
+}
+
+// ereach returns the pattern of the off-diagonal elements of row k of L in
+// pattern[top:], in topological order, given the elimination tree parent and
+// the upper triangle up. Elements of mark equal to k mark visited rows.
+func ereach(up *compressed, k int, parent, mark, stack, pattern []int) (top int) {
+	top = len(pattern)
+	mark[k] = k
+	for p := up.indptr[k]; p < up.indptr[k+1]; p++ {
+		var n int
+		for i := up.ind[p]; mark[i] != k; i = parent[i] {
+			if i == -1 {
+				panic(badSparsePattern)
+			}
+			stack[n] = i
+			n++
+			mark[i] = k
+		}
+		for n > 0 {
+			n--
+			top--
+			pattern[top] = stack[n]
+		}
+	}
+	return top
+}
+
+// Cond returns a lower bound on the condition number of the factorized
+// matrix, estimated from the diagonal of L.
+func (c *SparseCholesky) Cond() float64 {
+	if !c.ok {
+		panic(badSparseCholesky)
+	}
+	return c.cond
+}
+
+// LogDet returns the log of the determinant of the factorized matrix.
+func (c *SparseCholesky) LogDet() float64 {
+	if !c.ok {
+		panic(badSparseCholesky)
+	}
+	var det float64
+	for i := 0; i < c.n; i++ {
+		det += 2 * math.Log(c.values[c.colptr[i]])
+	}
+	return det
+}
+
+// Det returns the determinant of the factorized matrix.
+func (c *SparseCholesky) Det() float64 {
+	return math.Exp(c.LogDet())
+}
+
+// NNZ returns the number of stored elements of L.
+func (c *SparseCholesky) NNZ() int {
+	if !c.ok {
+		panic(badSparseCholesky)
+	}
+	return c.colptr[c.n]
+}
+
+// Perm returns the fill-reducing permutation P of the factorization, such
+// that row k of P * A * Pᵀ is row perm[k] of A. If dst is not nil, the
+// permutation is stored into dst, which must have length equal to the size
+// of the factorized matrix, and dst is returned.
+func (c *SparseCholesky) Perm(dst []int) []int {
+	if !c.ok {
+		panic(badSparseCholesky)
+	}
+	if dst == nil {
+		dst = make([]int, c.n)
+	}
+	if len(dst) != c.n {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, c.perm)
+	return dst
+}
+
+// LTo stores the lower triangular factor L of P * A * Pᵀ into dst and returns
+// it. If dst is nil a new CSC is allocated.
+func (c *SparseCholesky) LTo(dst *CSC) *CSC {
+	if !c.ok {
+		panic(badSparseCholesky)
+	}
+	if dst == nil {
+		dst = &CSC{}
+	}
+	dst.mat = compressed{rows: c.n, cols: c.n, indptr: make([]int, c.n+1)}
+	for j := 0; j < c.n; j++ {
+		for p := c.colptr[j]; p < c.colptr[j+1]; p++ {
+			if p == c.colptr[j] || c.rowind[p] != j {
+				dst.mat.ind = append(dst.mat.ind, c.rowind[p])
+				dst.mat.data = append(dst.mat.data, c.values[p])
+			}
+		}
+		dst.mat.indptr[j+1] = len(dst.mat.ind)
+	}
+	return dst
+}
+
+// Reset resets the factorization so that it can be reused.
+func (c *SparseCholesky) Reset() {
+	*c = SparseCholesky{}
+}
+
+// SolveTo finds the matrix X that solves A * X = B where A is represented
+// by the sparse Cholesky decomposition. The result is stored in-place into
+// dst.
+//
+// If A is near-singular a Condition error is returned. See the documentation
+// for Condition for more information.
+func (c *SparseCholesky) SolveTo(dst *Dense, b Matrix) error {
+	if !c.ok {
+		panic(badSparseCholesky)
+	}
+	solveSparseTo(dst, c.n, b, c.solve)
+	if c.cond > ConditionTolerance {
+		return Condition(c.cond)
+	}
+	return nil
+}
+
+// SolveVecTo finds the vector x that solves A * x = b where A is represented
+// by the sparse Cholesky decomposition. The result is stored in-place into
+// dst.
+//
+// If A is near-singular a Condition error is returned. See the documentation
+// for Condition for more information.
+func (c *SparseCholesky) SolveVecTo(dst *VecDense, b Vector) error {
+	if !c.ok {
+		panic(badSparseCholesky)
+	}
+	solveSparseVecTo(dst, c.n, b, c.solve)
+	if c.cond > ConditionTolerance {
+		return Condition(c.cond)
+	}
+	return nil
+}
+
+// solve overwrites x with the solution of A * x = b where b is held in x
+// and w is workspace.
+func (c *SparseCholesky) solve(x, w []float64) {
+	for k, i := range c.perm {
+		w[k] = x[i]
+	}
+	for j := 0; j < c.n; j++ {
+		w[j] /= c.values[c.colptr[j]]
+		f := w[j]
+		for p := c.colptr[j] + 1; p < c.colptr[j+1]; p++ {
+			w[c.rowind[p]] -= c.values[p] * f
+		}
+	}
+	for j := c.n - 1; j >= 0; j-- {
+		f := w[j]
+		for p := c.colptr[j] + 1; p < c.colptr[j+1]; p++ {
+			f -= c.values[p] * w[c.rowind[p]]
+		}
+		w[j] = f / c.values[c.colptr[j]]
+	}
+	for k, i := range c.perm {
+		x[i] = w[k]
+	}
+}
+
+// solveSparseTo stores the solution of a system of n equations with the
+// right-hand sides in the columns of b into dst, solving each column in turn
+// with solve.
+func solveSparseTo(dst *Dense, n int, b Matrix, solve func(x, w []float64)) {
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	dst.reuseAs(n, bc)
+	bU, _ := untranspose(b)
+	if dst == bU {
+		var restore func()
+		dst, restore = dst.isolatedWorkspace(bU)
+		defer restore()
+	} else if rm, ok := bU.(RawMatrixer); ok {
+		dst.checkOverlap(rm.RawMatrix())
+	}
+	x := getFloats(n, false)
+	defer putFloats(x)
+	w := getFloats(n, false)
+	defer putFloats(w)
+	for j := 0; j < bc; j++ {
+		for i := range x {
+			x[i] = b.At(i, j)
+		}
+		solve(x, w)
+		for i, v := range x {
+			dst.set(i, j, v)
+		}
+	}
+}
+
+// solveSparseVecTo stores the solution of a system of n equations with the
+// right-hand side b into dst using solve.
+func solveSparseVecTo(dst *VecDense, n int, b Vector, solve func(x, w []float64)) {
+	if b.Len() != n {
+		panic(ErrShape)
+	}
+	x := getFloats(n, false)
+	defer putFloats(x)
+	w := getFloats(n, false)
+	defer putFloats(w)
+	for i := range x {
+		x[i] = b.AtVec(i)
+	}
+	solve(x, w)
+	dst.reuseAs(n)
+	for i, v := range x {
+		dst.setVec(i, v)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randSparseSPD returns a random n×n sparse symmetric positive definite
+// matrix with approximately density*n*n off-diagonal elements, and its
+// dense equivalent.
+func randSparseSPD(n int, density float64, rnd *rand.Rand) (*CSR, *SymDense) {
+	a := NewCOO(n, n, nil, nil, nil)
+	sum := make([]float64, n)
+	for k := 0; k < int(density*float64(n*n)/2); k++ {
+		i, j := rnd.Intn(n), rnd.Intn(n)
+		if i == j {
+			continue
+		}
+		v := rnd.NormFloat64()
+		a.Append(i, j, v)
+		a.Append(j, i, v)
+		sum[i] += math.Abs(v)
+		sum[j] += math.Abs(v)
+	}
+	for i, v := range sum {
+		a.Append(i, i, v+rnd.Float64()+0.1)
+	}
+	s := a.ToCSR()
+	d := NewSymDense(n, nil)
+	s.DoNonZero(func(i, j int, v float64) {
+		if i <= j {
+			d.SetSym(i, j, v)
+		}
+	})
+	return s, d
+}
+
+// lowerOf returns the lower triangle of the symmetric matrix a.
+func lowerOf(a *SymDense) *CSR {
+	n := a.Symmetric()
+	l := NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			if v := a.At(i, j); v != 0 {
+				l.Append(i, j, v)
+			}
+		}
+	}
+	return l.ToCSR()
+}
+
+func TestSparseCholesky(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	type test struct {
+		name string
+		a    *CSR
+		want *SymDense
+	}
+	var tests []test
+	for _, n := range []int{1, 2, 10, 50, 200} {
+		for _, density := range []float64{0, 0.02, 0.1, 0.5} {
+			a, d := randSparseSPD(n, density, rnd)
+			tests = append(tests, test{name: fmt.Sprintf("random n=%d density=%v", n, density), a: a, want: d})
+		}
+	}
+	for _, m := range []int{3, 12} {
+		a := gridLaplacian(m, 0.5)
+		tests = append(tests, test{name: fmt.Sprintf("grid %d×%d", m, m), a: a, want: NewSymDense(m*m, denseCopyOf(a).RawMatrix().Data)})
+	}
+
+	for _, test := range tests {
+		n := test.want.Symmetric()
+		var c SparseCholesky
+		if !c.Factorize(test.a) {
+			t.Errorf("%s: unexpected factorization failure", test.name)
+			continue
+		}
+		checkSparseCholesky(t, test.name, &c, test.want)
+
+		// A factorization using only the lower
+		// triangle must be identical.
+		var lc SparseCholesky
+		lc.Factorize(lowerOf(test.want))
+		if !EqualApprox(lc.LTo(nil), c.LTo(nil), 1e-14) {
+			t.Errorf("%s: unexpected factor from lower triangle", test.name)
+		}
+
+		// Refactorize with new values on a
+		// subset of the pattern.
+		scaled := NewCOO(n, n, nil, nil, nil)
+		test.a.DoNonZero(func(i, j int, v float64) {
+			if i == j {
+				v = 2*v + 1
+			} else if (i+j)%3 == 0 {
+				return
+			}
+			scaled.Append(i, j, v)
+		})
+		want := NewSymDense(n, nil)
+		scaled.ToCSR().DoNonZero(func(i, j int, v float64) {
+			if i <= j {
+				want.SetSym(i, j, v)
+			}
+		})
+		if !c.Refactorize(scaled) {
+			t.Errorf("%s: unexpected refactorization failure", test.name)
+			continue
+		}
+		checkSparseCholesky(t, test.name+" refactorized", &c, want)
+	}
+}
+
+func checkSparseCholesky(t *testing.T, name string, c *SparseCholesky, want *SymDense) {
+	t.Helper()
+	n := want.Symmetric()
+
+	perm := c.Perm(nil)
+	if !isPerm(perm, n) {
+		t.Errorf("%s: invalid permutation", name)
+		return
+	}
+	l := c.LTo(nil)
+	var llt, pap Dense
+	llt.Mul(l, l.T())
+	pap.Apply(func(i, j int, _ float64) float64 { return want.At(perm[i], perm[j]) }, want)
+	if !EqualApprox(&llt, &pap, 1e-12) {
+		t.Errorf("%s: L * Lᵀ does not match P * A * Pᵀ", name)
+	}
+
+	var dc Cholesky
+	if !dc.Factorize(want) {
+		t.Errorf("%s: dense factorization failed", name)
+		return
+	}
+	if got, want := c.LogDet(), dc.LogDet(); math.Abs(got-want) > 1e-10*math.Max(1, math.Abs(want)) {
+		t.Errorf("%s: unexpected log determinant: got %v want %v", name, got, want)
+	}
+
+	b := NewVecDense(n, randFloats(n, rand.New(rand.NewSource(2))))
+	var got, wantx VecDense
+	if err := c.SolveVecTo(&got, b); err != nil {
+		t.Errorf("%s: unexpected error from SolveVecTo: %v", name, err)
+	}
+	dc.SolveVecTo(&wantx, b)
+	if !EqualApprox(&got, &wantx, 1e-10) {
+		t.Errorf("%s: unexpected SolveVecTo result", name)
+	}
+
+	bm := NewDense(n, 3, randFloats(3*n, rand.New(rand.NewSource(3))))
+	var gotm, wantm Dense
+	if err := c.SolveTo(&gotm, bm); err != nil {
+		t.Errorf("%s: unexpected error from SolveTo: %v", name, err)
+	}
+	dc.SolveTo(&wantm, bm)
+	if !EqualApprox(&gotm, &wantm, 1e-10) {
+		t.Errorf("%s: unexpected SolveTo result", name)
+	}
+
+	// Solve in place.
+	bm = NewDense(n, 3, randFloats(3*n, rand.New(rand.NewSource(3))))
+	c.SolveTo(bm, bm)
+	if !EqualApprox(bm, &wantm, 1e-10) {
+		t.Errorf("%s: unexpected in-place SolveTo result", name)
+	}
+}
+
+// denseCopyOf returns a dense copy of a.
+func denseCopyOf(a Matrix) *Dense {
+	r, c := a.Dims()
+	d := NewDense(r, c, nil)
+	d.Copy(a)
+	return d
+}
+
+func TestSparseCholeskyFailure(t *testing.T) {
+	t.Parallel()
+	var c SparseCholesky
+	if c.Factorize(gridLaplacian(4, -1)) {
+		t.Error("unexpected success factorizing an indefinite matrix")
+	}
+	if panicked, _ := panics(func() { c.LogDet() }); !panicked {
+		t.Error("expected panic using a failed factorization")
+	}
+	if !c.Refactorize(gridLaplacian(4, 1)) {
+		t.Error("unexpected failure refactorizing after failure")
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "non-square", fn: func() { new(SparseCholesky).Factorize(NewCOO(2, 3, nil, nil, nil)) }},
+		{name: "Refactorize uninitialized", fn: func() { new(SparseCholesky).Refactorize(gridLaplacian(2, 0)) }},
+		{name: "Refactorize shape", fn: func() {
+			var c SparseCholesky
+			c.Factorize(gridLaplacian(2, 0))
+			c.Refactorize(gridLaplacian(3, 0))
+		}},
+		{name: "Refactorize pattern", fn: func() {
+			var c SparseCholesky
+			c.Factorize(NewDiagDense(3, []float64{1, 2, 3}))
+			c.Refactorize(NewDense(3, 3, []float64{4, 1, 1, 1, 4, 1, 1, 1, 4}))
+		}},
+		{name: "SolveVecTo shape", fn: func() {
+			var c SparseCholesky
+			c.Factorize(gridLaplacian(2, 0))
+			c.SolveVecTo(&VecDense{}, NewVecDense(3, nil))
+		}},
+	} {
+		if panicked, _ := panics(test.fn); !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func BenchmarkSparseCholeskyGrid(b *testing.B) {
+	a := gridLaplacian(50, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var c SparseCholesky
+		c.Factorize(a)
+	}
+}
@@ -0,0 +1,491 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+const badSparseLU = "mat: invalid sparse LU factorization"
+
+// sparseLUPivotTol is the threshold for partial pivoting in SparseLU. The
+// diagonal element of a column is chosen as the pivot if its magnitude is at
+// least sparseLUPivotTol times that of the largest candidate, which helps
+// preserve the sparsity given by the column ordering.
+const sparseLUPivotTol = 0.1
+
+// SparseLU is a sparse square matrix represented by its LU decomposition
+//
+//	P * A * Q = L * U
+//
+// where Q is a fill-reducing column permutation, P is a row permutation
+// chosen by threshold partial pivoting, L is a sparse unit lower triangular
+// matrix and U is a sparse upper triangular matrix.
+//
+// The decomposition is constructed using the Factorize method, which
+// computes an approximate minimum degree ordering of the columns of A and
+// then performs a left-looking factorization. Matrices with a similar
+// sparsity pattern and values may then be factorized using Refactorize,
+// which reuses both permutations.
+//
+// SparseLU methods other than Factorize and Reset may only be called on a
+// value that has been successfully initialized by a call to Factorize or
+// Refactorize that has returned true. Calls to methods of an unsuccessful
+// factorization will panic.
+type SparseLU struct {
+	n int
+
+	// q holds the column ordering, prow the
+	// row ordering and pinv its inverse.
+	q, prow, pinv []int
+
+	// L and U are held in compressed sparse
+	// column order. The unit diagonal of L
+	// is first in each column and the
+	// diagonal of U is last.
+	lptr, lind []int
+	lval       []float64
+	uptr, uind []int
+	uval       []float64
+
+	cond float64
+	ok   bool
+}
+
+// Factorize calculates the sparse LU decomposition of the matrix a and
+// returns whether the matrix is non-singular, so that every pivot is
+// non-zero. If Factorize returns false, the factorization must not be used.
+// Factorize will panic if a is not square.
+//
+// Factorize is efficient when a is a sparse matrix provided by this package.
+func (lu *SparseLU) Factorize(a Matrix) (ok bool) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	lu.n = r
+	lu.q = AMD(a)
+	lu.prow = nil
+	return lu.factorize(a)
+}
+
+// Refactorize calculates the sparse LU decomposition of the matrix a using
+// the row and column permutations of a previous successful factorization,
+// and returns whether every pivot is non-zero. Refactorize avoids the cost
+// of ordering and pivot selection, but since the pivots are not chosen
+// afresh it is only stable when a is close to the previously factorized
+// matrix. Refactorize will panic if the receiver does not hold a successful
+// factorization or if the dimensions of a do not match those of the
+// factorized matrix.
+func (lu *SparseLU) Refactorize(a Matrix) (ok bool) {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	if r, c := a.Dims(); r != lu.n || c != lu.n {
+		panic(ErrShape)
+	}
+	return lu.factorize(a)
+}
+
+// factorize performs the left-looking factorization of a using the column
+// ordering lu.q, and the row ordering lu.prow if it is not nil.
+func (lu *SparseLU) factorize(a Matrix) (ok bool) {
+	lu.ok = false
+	n := lu.n
+	// The compressed rows of aᵀ are
+	// the columns of a.
+	cols := csrOf(a.T())
+
+	static := lu.prow
+	pinv := make([]int, n)
+	for i := range pinv {
+		pinv[i] = -1
+	}
+	lu.lptr = make([]int, n+1)
+	lu.uptr = make([]int, n+1)
+	lu.lind, lu.lval = lu.lind[:0], lu.lval[:0]
+	lu.uind, lu.uval = lu.uind[:0], lu.uval[:0]
+
+	x := getFloats(n, true)
+	defer putFloats(x)
+	mark := getInts(n, false)
+	defer putInts(mark)
+	for i := range mark {
+		mark[i] = -1
+	}
+	stack := getInts(n, false)
+	defer putInts(stack)
+	pstack := getInts(n, false)
+	defer putInts(pstack)
+	pattern := getInts(n, false)
+	defer putInts(pattern)
+
+	for k, j := range lu.q {
+		lu.lptr[k] = len(lu.lind)
+		lu.uptr[k] = len(lu.uind)
+
+		// Solve L * x = A[:, j] with the columns
+		// of L computed so far.
+		top := n
+		for p := cols.indptr[j]; p < cols.indptr[j+1]; p++ {
+			if i := cols.ind[p]; mark[i] != k {
+				top = lu.reach(i, k, pinv, mark, stack, pstack, pattern, top)
+			}
+		}
+		for p := cols.indptr[j]; p < cols.indptr[j+1]; p++ {
+			x[cols.ind[p]] = cols.data[p]
+		}
+		for _, i := range pattern[top:] {
+			c := pinv[i]
+			if c < 0 {
+				continue
+			}
+			f := x[i]
+			for p := lu.lptr[c] + 1; p < lu.lptr[c+1]; p++ {
+				x[lu.lind[p]] -= lu.lval[p] * f
+			}
+		}
+
+		// Choose the pivot and store column k
+		// of U.
+		piv := -1
+		var amax float64
+		for _, i := range pattern[top:] {
+			if pinv[i] < 0 {
+				if v := math.Abs(x[i]); v > amax {
+					piv, amax = i, v
+				}
+				continue
+			}
+			lu.uind = append(lu.uind, pinv[i])
+			lu.uval = append(lu.uval, x[i])
+		}
+		switch {
+		case static != nil:
+			piv = static[k]
+		case pinv[j] < 0 && math.Abs(x[j]) >= sparseLUPivotTol*amax:
+			piv = j
+		}
+		pivot := 0.0
+		if piv >= 0 {
+			pivot = x[piv]
+		}
+		if pivot == 0 || math.IsNaN(pivot) {
+			for _, i := range pattern[top:] {
+				x[i] = 0
+			}
+			return false
+		}
+		lu.uind = append(lu.uind, k)
+		lu.uval = append(lu.uval, pivot)
+		pinv[piv] = k
+
+		// Store column k of L.
+		lu.lind = append(lu.lind, piv)
+		lu.lval = append(lu.lval, 1)
+		for _, i := range pattern[top:] {
+			if pinv[i] < 0 {
+				lu.lind = append(lu.lind, i)
+				lu.lval = append(lu.lval, x[i]/pivot)
+			}
+			x[i] = 0
+		}
+	}
+	lu.lptr[n] = len(lu.lind)
+	lu.uptr[n] = len(lu.uind)
+
+	// Relabel the rows of L with
+	// their pivot order.
+	for p, i := range lu.lind {
+		lu.lind[p] = pinv[i]
+	}
+	if lu.prow == nil {
+		lu.prow = make([]int, n)
+	}
+	for i, k := range pinv {
+		lu.prow[k] = i
+	}
+	lu.pinv = pinv
+
+	lo, hi := math.Inf(1), 0.0
+	for k := 0; k < n; k++ {
+		v := math.Abs(lu.uval[lu.uptr[k+1]-1])
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	lu.cond = hi / lo
+	lu.ok = true
+	return true
+}
+
+// reach performs a depth-first search from row i of the matrix being
+// factorized through the graph of the columns of L computed before step k,
+// placing the rows reached into pattern below top in topological order. It
+// returns the new top.
+func (lu *SparseLU) reach(i, k int, pinv, mark, stack, pstack, pattern []int, top int) int {
+	head := 0
+	stack[0] = i
+	for head >= 0 {
+		j := stack[head]
+		c := pinv[j]
+		if mark[j] != k {
+			mark[j] = k
+			if c >= 0 {
+				pstack[head] = lu.lptr[c] + 1
+			}
+		}
+		done := true
+		if c >= 0 {
+			for p := pstack[head]; p < lu.lptr[c+1]; p++ {
+				r := lu.lind[p]
+				if mark[r] == k {
+					continue
+				}
+				pstack[head] = p + 1
+				head++
+				stack[head] = r
+				done = false
+				break
+			}
+		}
+		if done {
+			head--
+			top--
+			pattern[top] = j
+		}
+	}
+	return top
+}
+
+// Cond returns a lower bound on the condition number of the factorized
+// matrix, estimated from the diagonal of U.
+func (lu *SparseLU) Cond() float64 {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	return lu.cond
+}
+
+// Det returns the determinant of the factorized matrix. In many expressions,
+// using LogDet will be more numerically stable.
+func (lu *SparseLU) Det() float64 {
+	det, sign := lu.LogDet()
+	return math.Exp(det) * sign
+}
+
+// LogDet returns the log of the determinant and the sign of the determinant
+// of the factorized matrix.
+func (lu *SparseLU) LogDet() (det float64, sign float64) {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	sign = permSign(lu.prow) * permSign(lu.q)
+	for k := 0; k < lu.n; k++ {
+		v := lu.uval[lu.uptr[k+1]-1]
+		if v < 0 {
+			sign = -sign
+		}
+		det += math.Log(math.Abs(v))
+	}
+	return det, sign
+}
+
+// permSign returns the sign of the permutation p.
+func permSign(p []int) float64 {
+	seen := make([]bool, len(p))
+	sign := 1.0
+	for i := range p {
+		if seen[i] {
+			continue
+		}
+		for j := p[i]; j != i; j = p[j] {
+			seen[j] = true
+			sign = -sign
+		}
+		seen[i] = true
+	}
+	return sign
+}
+
+// RowPerm returns the row permutation P of the factorization, such that row
+// k of P * A * Q is row p[k] of A. If dst is not nil, the permutation is
+// stored into dst, which must have length equal to the size of the
+// factorized matrix, and dst is returned.
+func (lu *SparseLU) RowPerm(dst []int) []int {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	return copyPerm(dst, lu.prow)
+}
+
+// ColPerm returns the column permutation Q of the factorization, such that
+// column k of P * A * Q is column q[k] of A. If dst is not nil, the
+// permutation is stored into dst, which must have length equal to the size
+// of the factorized matrix, and dst is returned.
+func (lu *SparseLU) ColPerm(dst []int) []int {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	return copyPerm(dst, lu.q)
+}
+
+func copyPerm(dst, p []int) []int {
+	if dst == nil {
+		dst = make([]int, len(p))
+	}
+	if len(dst) != len(p) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, p)
+	return dst
+}
+
+// NNZ returns the number of stored elements of L and U, including the unit
+// diagonal of L.
+func (lu *SparseLU) NNZ() int {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	return len(lu.lind) + len(lu.uind)
+}
+
+// LTo stores the unit lower triangular factor L of P * A * Q into dst and
+// returns it. If dst is nil a new CSC is allocated.
+func (lu *SparseLU) LTo(dst *CSC) *CSC {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	return factorTo(dst, lu.n, lu.lptr, lu.lind, lu.lval)
+}
+
+// UTo stores the upper triangular factor U of P * A * Q into dst and returns
+// it. If dst is nil a new CSC is allocated.
+func (lu *SparseLU) UTo(dst *CSC) *CSC {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	return factorTo(dst, lu.n, lu.uptr, lu.uind, lu.uval)
+}
+
+// factorTo stores the n×n matrix held in compressed sparse column order by
+// ptr, ind and val into dst, sorting the elements of each column.
+func factorTo(dst *CSC, n int, ptr, ind []int, val []float64) *CSC {
+	if dst == nil {
+		dst = &CSC{}
+	}
+	cols := make([]int, len(ind))
+	for j := 0; j < n; j++ {
+		for p := ptr[j]; p < ptr[j+1]; p++ {
+			cols[p] = j
+		}
+	}
+	dst.mat = compress(n, n, cols, ind, val)
+	return dst
+}
+
+// Reset resets the factorization so that it can be reused.
+func (lu *SparseLU) Reset() {
+	*lu = SparseLU{}
+}
+
+// SolveTo solves a system of linear equations using the sparse LU
+// decomposition of a matrix. It computes
+//
+//	A * X = B if trans == false
+//	Aᵀ * X = B if trans == true
+//
+// In both cases, A is represented in LU factorized form, and the matrix X is
+// stored into dst.
+//
+// If A is near-singular a Condition error is returned. See the documentation
+// for Condition for more information.
+func (lu *SparseLU) SolveTo(dst *Dense, trans bool, b Matrix) error {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	solveSparseTo(dst, lu.n, b, lu.solver(trans))
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+// SolveVecTo solves a system of linear equations using the sparse LU
+// decomposition of a matrix. It computes
+//
+//	A * x = b if trans == false
+//	Aᵀ * x = b if trans == true
+//
+// In both cases, A is represented in LU factorized form, and the vector x is
+// stored into dst.
+//
+// If A is near-singular a Condition error is returned. See the documentation
+// for Condition for more information.
+func (lu *SparseLU) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	if !lu.ok {
+		panic(badSparseLU)
+	}
+	solveSparseVecTo(dst, lu.n, b, lu.solver(trans))
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+func (lu *SparseLU) solver(trans bool) func(x, w []float64) {
+	if trans {
+		return lu.solveTrans
+	}
+	return lu.solve
+}
+
+// solve overwrites x with the solution of A * x = b where b is held in x
+// and w is workspace.
+func (lu *SparseLU) solve(x, w []float64) {
+	for i, k := range lu.pinv {
+		w[k] = x[i]
+	}
+	for j := 0; j < lu.n; j++ {
+		f := w[j]
+		for p := lu.lptr[j] + 1; p < lu.lptr[j+1]; p++ {
+			w[lu.lind[p]] -= lu.lval[p] * f
+		}
+	}
+	for j := lu.n - 1; j >= 0; j-- {
+		d := lu.uptr[j+1] - 1
+		w[j] /= lu.uval[d]
+		f := w[j]
+		for p := lu.uptr[j]; p < d; p++ {
+			w[lu.uind[p]] -= lu.uval[p] * f
+		}
+	}
+	for k, j := range lu.q {
+		x[j] = w[k]
+	}
+}
+
+// solveTrans overwrites x with the solution of Aᵀ * x = b where b is held
+// in x and w is workspace.
+func (lu *SparseLU) solveTrans(x, w []float64) {
+	for k, j := range lu.q {
+		w[k] = x[j]
+	}
+	for j := 0; j < lu.n; j++ {
+		d := lu.uptr[j+1] - 1
+		f := w[j]
+		for p := lu.uptr[j]; p < d; p++ {
+			f -= lu.uval[p] * w[lu.uind[p]]
+		}
+		w[j] = f / lu.uval[d]
+	}
+	for j := lu.n - 1; j >= 0; j-- {
+		f := w[j]
+		for p := lu.lptr[j] + 1; p < lu.lptr[j+1]; p++ {
+			f -= lu.lval[p] * w[lu.lind[p]]
+		}
+		w[j] = f
+	}
+	for i, k := range lu.pinv {
+		x[i] = w[k]
+	}
+}
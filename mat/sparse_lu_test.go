@@ -0,0 +1,187 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randSparseNonSingular returns a random n×n sparse matrix with
+// approximately density*n*n elements that is non-singular with high
+// probability, with its rows shuffled so that pivoting is needed, and its
+// dense equivalent.
+func randSparseNonSingular(n int, density float64, rnd *rand.Rand) (*CSR, *Dense) {
+	a := NewCOO(n, n, nil, nil, nil)
+	perm := rnd.Perm(n)
+	for i := 0; i < n; i++ {
+		a.Append(perm[i], i, 1+rnd.Float64())
+	}
+	for k := 0; k < int(density*float64(n*n)); k++ {
+		a.Append(rnd.Intn(n), rnd.Intn(n), rnd.NormFloat64())
+	}
+	s := a.ToCSR()
+	return s, denseCopyOf(s)
+}
+
+func TestSparseLU(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 10, 50, 200} {
+		for _, density := range []float64{0, 0.02, 0.1, 0.5} {
+			name := fmt.Sprintf("n=%d density=%v", n, density)
+			a, want := randSparseNonSingular(n, density, rnd)
+			var lu SparseLU
+			if !lu.Factorize(a) {
+				t.Errorf("%s: unexpected factorization failure", name)
+				continue
+			}
+			checkSparseLU(t, name, &lu, want)
+
+			// Refactorize with values perturbed
+			// on the same pattern.
+			perturbed := NewCOO(n, n, nil, nil, nil)
+			a.DoNonZero(func(i, j int, v float64) {
+				perturbed.Append(i, j, v*(1+0.01*rnd.NormFloat64()))
+			})
+			pa := perturbed.ToCSR()
+			if !lu.Refactorize(pa) {
+				t.Errorf("%s: unexpected refactorization failure", name)
+				continue
+			}
+			checkSparseLU(t, name+" refactorized", &lu, denseCopyOf(pa))
+		}
+	}
+}
+
+func checkSparseLU(t *testing.T, name string, lu *SparseLU, want *Dense) {
+	t.Helper()
+	n, _ := want.Dims()
+
+	p := lu.RowPerm(nil)
+	q := lu.ColPerm(nil)
+	if !isPerm(p, n) || !isPerm(q, n) {
+		t.Errorf("%s: invalid permutation", name)
+		return
+	}
+	l := lu.LTo(nil)
+	u := lu.UTo(nil)
+	var prod, paq Dense
+	prod.Mul(l, u)
+	paq.Apply(func(i, j int, _ float64) float64 { return want.At(p[i], q[j]) }, want)
+	if !EqualApprox(&prod, &paq, 1e-12) {
+		t.Errorf("%s: L * U does not match P * A * Q", name)
+	}
+	for i := 0; i < n; i++ {
+		if l.At(i, i) != 1 {
+			t.Errorf("%s: L is not unit diagonal", name)
+			break
+		}
+	}
+	if !isLowerCSC(l, true) || !isLowerCSC(u, false) {
+		t.Errorf("%s: factors are not triangular", name)
+	}
+
+	var dlu LU
+	dlu.Factorize(want)
+	gotDet, gotSign := lu.LogDet()
+	wantDet, wantSign := dlu.LogDet()
+	if gotSign != wantSign || math.Abs(gotDet-wantDet) > 1e-10*math.Max(1, math.Abs(wantDet)) {
+		t.Errorf("%s: unexpected log determinant: got %v,%v want %v,%v", name, gotDet, gotSign, wantDet, wantSign)
+	}
+
+	for _, trans := range []bool{false, true} {
+		b := NewVecDense(n, randFloats(n, rand.New(rand.NewSource(2))))
+		var got, wantx VecDense
+		if err := lu.SolveVecTo(&got, trans, b); err != nil {
+			t.Errorf("%s trans=%t: unexpected error from SolveVecTo: %v", name, trans, err)
+		}
+		dlu.SolveVecTo(&wantx, trans, b)
+		if !EqualApprox(&got, &wantx, 1e-8) {
+			t.Errorf("%s trans=%t: unexpected SolveVecTo result", name, trans)
+		}
+
+		bm := NewDense(n, 3, randFloats(3*n, rand.New(rand.NewSource(3))))
+		var gotm, wantm Dense
+		if err := lu.SolveTo(&gotm, trans, bm); err != nil {
+			t.Errorf("%s trans=%t: unexpected error from SolveTo: %v", name, trans, err)
+		}
+		dlu.SolveTo(&wantm, trans, bm)
+		if !EqualApprox(&gotm, &wantm, 1e-8) {
+			t.Errorf("%s trans=%t: unexpected SolveTo result", name, trans)
+		}
+	}
+}
+
+// isLowerCSC returns whether m is lower triangular if lower is true, or
+// upper triangular otherwise.
+func isLowerCSC(m *CSC, lower bool) bool {
+	ok := true
+	m.DoNonZero(func(i, j int, _ float64) {
+		if (lower && i < j) || (!lower && i > j) {
+			ok = false
+		}
+	})
+	return ok
+}
+
+func TestSparseLUFailure(t *testing.T) {
+	t.Parallel()
+	var lu SparseLU
+	singular := NewDense(3, 3, []float64{
+		1, 2, 0,
+		2, 4, 0,
+		0, 0, 1,
+	})
+	if lu.Factorize(singular) {
+		t.Error("unexpected success factorizing a singular matrix")
+	}
+	if panicked, _ := panics(func() { lu.Det() }); !panicked {
+		t.Error("expected panic using a failed factorization")
+	}
+
+	// A zero at a pivot chosen by a previous
+	// factorization fails refactorization.
+	if !lu.Factorize(NewDiagDense(3, []float64{1, 2, 3})) {
+		t.Fatal("unexpected failure factorizing a diagonal matrix")
+	}
+	if lu.Refactorize(NewDense(3, 3, []float64{0, 1, 0, 1, 0, 0, 0, 0, 1})) {
+		t.Error("unexpected success refactorizing with a zero pivot")
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "non-square", fn: func() { new(SparseLU).Factorize(NewCOO(2, 3, nil, nil, nil)) }},
+		{name: "Refactorize uninitialized", fn: func() { new(SparseLU).Refactorize(gridLaplacian(2, 0)) }},
+		{name: "Refactorize shape", fn: func() {
+			var lu SparseLU
+			lu.Factorize(gridLaplacian(2, 0))
+			lu.Refactorize(gridLaplacian(3, 0))
+		}},
+		{name: "SolveTo shape", fn: func() {
+			var lu SparseLU
+			lu.Factorize(gridLaplacian(2, 0))
+			lu.SolveTo(&Dense{}, false, NewDense(3, 1, nil))
+		}},
+	} {
+		if panicked, _ := panics(test.fn); !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func BenchmarkSparseLUGrid(b *testing.B) {
+	a := gridLaplacian(50, 0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var lu SparseLU
+		lu.Factorize(a)
+	}
+}
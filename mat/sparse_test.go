@@ -0,0 +1,355 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randCOO returns a random r×c COO matrix with approximately density*r*c
+// triplets, including duplicates and explicit zeros, and its dense
+// equivalent.
+func randCOO(r, c int, density float64, rnd *rand.Rand) (*COO, *Dense) {
+	m := NewCOO(r, c, nil, nil, nil)
+	d := NewDense(r, c, nil)
+	n := int(density * float64(r*c))
+	for k := 0; k < n; k++ {
+		i, j := rnd.Intn(r), rnd.Intn(c)
+		v := float64(rnd.Intn(9) - 4)
+		m.Append(i, j, v)
+		d.Set(i, j, d.At(i, j)+v)
+	}
+	return m, d
+}
+
+// randDIA returns a random r×c DIA matrix and its dense equivalent.
+func randDIA(r, c int, rnd *rand.Rand) (*DIA, *Dense) {
+	var offsets []int
+	for k := -r + 1; k < c; k++ {
+		if rnd.Intn(3) == 0 {
+			offsets = append(offsets, k)
+		}
+	}
+	m := NewDIA(r, c, offsets, nil)
+	_, data := m.RawDIA()
+	for i := range data {
+		data[i] = float64(rnd.Intn(9) - 4)
+	}
+	d := NewDense(r, c, nil)
+	for p, k := range offsets {
+		for j := 0; j < c; j++ {
+			if i := j - k; 0 <= i && i < r {
+				d.Set(i, j, data[p*c+j])
+			}
+		}
+	}
+	return m, d
+}
+
+func TestSparseStorage(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, dims := range [][2]int{{1, 1}, {1, 5}, {5, 1}, {4, 7}, {10, 10}} {
+		r, c := dims[0], dims[1]
+		for _, density := range []float64{0, 0.1, 0.5, 2} {
+			coo, want := randCOO(r, c, density, rnd)
+			csr := coo.ToCSR()
+			csc := coo.ToCSC()
+			for _, test := range []struct {
+				name string
+				m    Matrix
+				want Matrix
+			}{
+				{name: "COO", m: coo, want: want},
+				{name: "CSR", m: csr, want: want},
+				{name: "CSC", m: csc, want: want},
+				{name: "CSR.ToCSC", m: csr.ToCSC(), want: want},
+				{name: "CSC.ToCSR", m: csc.ToCSR(), want: want},
+				{name: "COO.T", m: coo.T(), want: want.T()},
+				{name: "CSR.T", m: csr.T(), want: want.T()},
+				{name: "CSC.T", m: csc.T(), want: want.T()},
+			} {
+				if !Equal(test.m, test.want) {
+					t.Errorf("unexpected %s for %d×%d density %v:\ngot:\n%v\nwant:\n%v",
+						test.name, r, c, density, Formatted(test.m), Formatted(test.want))
+				}
+				if nz, ok := test.m.(NonZeroDoer); ok {
+					checkNonZero(t, test.name, nz, test.want)
+				}
+			}
+
+			var nnz int
+			doDenseNonZero(want, func(_, _ int, _ float64) { nnz++ })
+			if csr.NNZ() != nnz || csc.NNZ() != nnz {
+				t.Errorf("unexpected number of stored elements: got CSR:%d CSC:%d want:%d", csr.NNZ(), csc.NNZ(), nnz)
+			}
+			indptr, ind, data := csr.RawCSR()
+			if !Equal(NewCSR(r, c, indptr, ind, data), want) {
+				t.Errorf("unexpected CSR from raw storage")
+			}
+			indptr, ind, data = csc.RawCSC()
+			if !Equal(NewCSC(r, c, indptr, ind, data), want) {
+				t.Errorf("unexpected CSC from raw storage")
+			}
+		}
+
+		dia, want := randDIA(r, c, rnd)
+		if !Equal(dia, want) {
+			t.Errorf("unexpected DIA:\ngot:\n%v\nwant:\n%v", Formatted(dia), Formatted(want))
+		}
+		if !Equal(dia.ToCSR(), want) {
+			t.Errorf("unexpected DIA.ToCSR:\ngot:\n%v\nwant:\n%v", Formatted(dia.ToCSR()), Formatted(want))
+		}
+		checkNonZero(t, "DIA", dia, want)
+		kl, ku := dia.Bandwidth()
+		doDenseNonZero(want, func(i, j int, _ float64) {
+			if i-j > kl || j-i > ku {
+				t.Errorf("element (%d,%d) outside bandwidth (%d,%d)", i, j, kl, ku)
+			}
+		})
+	}
+}
+
+// checkNonZero checks the non-zero doer methods of m against want.
+func checkNonZero(t *testing.T, name string, m NonZeroDoer, want Matrix) {
+	t.Helper()
+	r, c := want.Dims()
+	got := NewDense(r, c, nil)
+	m.DoNonZero(func(i, j int, v float64) {
+		if got.At(i, j) != 0 {
+			t.Errorf("%s: element (%d,%d) visited twice", name, i, j)
+		}
+		got.Set(i, j, v)
+	})
+	if !Equal(got, want) {
+		t.Errorf("%s: unexpected DoNonZero result", name)
+	}
+	got.Zero()
+	for i := 0; i < r; i++ {
+		m.(RowNonZeroDoer).DoRowNonZero(i, func(ii, j int, v float64) {
+			if ii != i {
+				t.Errorf("%s: unexpected row: got:%d want:%d", name, ii, i)
+			}
+			got.Set(ii, j, v)
+		})
+	}
+	if !Equal(got, want) {
+		t.Errorf("%s: unexpected DoRowNonZero result", name)
+	}
+	got.Zero()
+	for j := 0; j < c; j++ {
+		m.(ColNonZeroDoer).DoColNonZero(j, func(i, jj int, v float64) {
+			if jj != j {
+				t.Errorf("%s: unexpected column: got:%d want:%d", name, jj, j)
+			}
+			got.Set(i, jj, v)
+		})
+	}
+	if !Equal(got, want) {
+		t.Errorf("%s: unexpected DoColNonZero result", name)
+	}
+}
+
+// sparseVariants returns r×c matrices with equal values in each of the
+// sparse formats and their transposes, and in dense format.
+func sparseVariants(r, c int, rnd *rand.Rand) (variants map[string]Matrix, want *Dense) {
+	dia, want := randDIA(r, c, rnd)
+
+	// Split each element over duplicate triplets.
+	// The elements are integers, so the sums are
+	// exact.
+	coo := NewCOO(r, c, nil, nil, nil)
+	doDenseNonZero(want, func(i, j int, v float64) {
+		coo.Append(i, j, v+3)
+		coo.Append(i, j, -3)
+	})
+	cooT := NewCOO(c, r, nil, nil, nil)
+	doDenseNonZero(want, func(i, j int, v float64) { cooT.Append(j, i, v) })
+
+	return map[string]Matrix{
+		"COO":   coo,
+		"CSR":   coo.ToCSR(),
+		"CSC":   coo.ToCSC(),
+		"CSRᵀ":  cooT.ToCSR().T(),
+		"CSCᵀ":  cooT.ToCSC().T(),
+		"COOᵀ":  cooT.T(),
+		"CSRᵀᵀ": Transpose{Transpose{coo.ToCSR()}},
+		"DIA":   dia,
+		"DIAᵀᵀ": Transpose{dia.T()},
+		"Dense": DenseCopyOf(want),
+		"Dᵀᵀ":   DenseCopyOf(want.T()).T(),
+	}, want
+}
+
+func TestSparseMul(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, dims := range [][3]int{{1, 1, 1}, {3, 4, 5}, {6, 6, 6}, {7, 1, 3}} {
+		ar, ac, bc := dims[0], dims[1], dims[2]
+		as, aWant := sparseVariants(ar, ac, rnd)
+		bs, bWant := sparseVariants(ac, bc, rnd)
+		var want Dense
+		want.Mul(aWant, bWant)
+		for aName, a := range as {
+			for bName, b := range bs {
+				name := fmt.Sprintf("%s×%s %v", aName, bName, dims)
+				var got Dense
+				got.Mul(a, b)
+				if !EqualApprox(&got, &want, 1e-12) {
+					t.Errorf("unexpected Dense.Mul for %s:\ngot:\n%v\nwant:\n%v", name, Formatted(&got), Formatted(&want))
+				}
+				var csr CSR
+				csr.Mul(a, b)
+				if !EqualApprox(&csr, &want, 1e-12) {
+					t.Errorf("unexpected CSR.Mul for %s:\ngot:\n%v\nwant:\n%v", name, Formatted(&csr), Formatted(&want))
+				}
+				checkNonZero(t, "CSR.Mul "+name, &csr, &csr)
+				var csc CSC
+				csc.Mul(a, b)
+				if !EqualApprox(&csc, &want, 1e-12) {
+					t.Errorf("unexpected CSC.Mul for %s:\ngot:\n%v\nwant:\n%v", name, Formatted(&csc), Formatted(&want))
+				}
+			}
+
+			for _, x := range []Vector{
+				NewVecDense(ac, nil),
+				NewVecDense(ac, randFloats(ac, rnd)),
+				NewDense(ac, 2, randFloats(2*ac, rnd)).ColView(1),
+			} {
+				var want, got VecDense
+				want.MulVec(aWant, x)
+				got.MulVec(a, x)
+				if !EqualApprox(&got, &want, 1e-12) {
+					t.Errorf("unexpected MulVec for %s: got:%v want:%v", aName, got.RawVector().Data, want.RawVector().Data)
+				}
+				y := NewDense(ar, 3, nil).ColView(2).(*VecDense)
+				y.MulVec(a, x)
+				if !EqualApprox(y, &want, 1e-12) {
+					t.Errorf("unexpected strided MulVec for %s: got:%v want:%v", aName, y, want.RawVector().Data)
+				}
+			}
+		}
+	}
+}
+
+func randFloats(n int, rnd *rand.Rand) []float64 {
+	f := make([]float64, n)
+	for i := range f {
+		f[i] = rnd.NormFloat64()
+	}
+	return f
+}
+
+func TestSparseMulAliased(t *testing.T) {
+	a := NewCSR(2, 2, []int{0, 1, 2}, []int{1, 0}, []float64{2, 3})
+	b := NewDense(2, 2, []float64{1, 2, 3, 4})
+	b.Mul(a, b)
+	want := NewDense(2, 2, []float64{6, 8, 3, 6})
+	if !Equal(b, want) {
+		t.Errorf("unexpected aliased product:\ngot:\n%v\nwant:\n%v", Formatted(b), Formatted(want))
+	}
+	var m CSR
+	m.Mul(a, a)
+	m.Mul(&m, a)
+	want = NewDense(2, 2, []float64{0, 12, 18, 0})
+	if !Equal(&m, want) {
+		t.Errorf("unexpected aliased sparse product:\ngot:\n%v\nwant:\n%v", Formatted(&m), Formatted(want))
+	}
+	if panicked, _ := panics(func() { m.Mul(NewCSR(3, 2, []int{0, 0, 0, 0}, nil, nil), a) }); !panicked {
+		t.Error("expected panic for receiver shape mismatch")
+	}
+	m.Reset()
+	m.Mul(NewCSR(3, 2, []int{0, 0, 0, 0}, nil, nil), a)
+	if r, c := m.Dims(); r != 3 || c != 2 || m.NNZ() != 0 {
+		t.Errorf("unexpected product of zero matrix: %d×%d with %d stored", r, c, m.NNZ())
+	}
+}
+
+func TestSparsePanics(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "CSR zero rows", fn: func() { NewCSR(0, 1, []int{0}, nil, nil) }},
+		{name: "CSR indptr length", fn: func() { NewCSR(2, 2, []int{0, 0}, nil, nil) }},
+		{name: "CSR data length", fn: func() { NewCSR(1, 2, []int{0, 1}, []int{0}, nil) }},
+		{name: "CSR indptr start", fn: func() { NewCSR(1, 2, []int{1, 1}, []int{0}, []float64{1}) }},
+		{name: "CSR indptr decreasing", fn: func() { NewCSR(2, 2, []int{0, 2, 1}, []int{0, 1}, []float64{1, 2}) }},
+		{name: "CSR column range", fn: func() { NewCSR(1, 2, []int{0, 1}, []int{2}, []float64{1}) }},
+		{name: "CSR unsorted", fn: func() { NewCSR(1, 2, []int{0, 2}, []int{1, 0}, []float64{1, 2}) }},
+		{name: "CSR repeated", fn: func() { NewCSR(1, 2, []int{0, 2}, []int{1, 1}, []float64{1, 2}) }},
+		{name: "CSC row range", fn: func() { NewCSC(1, 2, []int{0, 1, 1}, []int{1}, []float64{1}) }},
+		{name: "CSR At", fn: func() { NewCSR(1, 1, []int{0, 0}, nil, nil).At(0, 1) }},
+		{name: "CSC At", fn: func() { NewCSC(1, 1, []int{0, 0}, nil, nil).At(1, 0) }},
+		{name: "COO negative", fn: func() { NewCOO(-1, 1, nil, nil, nil) }},
+		{name: "COO length", fn: func() { NewCOO(1, 1, []int{0}, nil, nil) }},
+		{name: "COO range", fn: func() { NewCOO(1, 1, []int{0}, []int{1}, []float64{1}) }},
+		{name: "COO Append", fn: func() { NewCOO(1, 1, nil, nil, nil).Append(1, 0, 1) }},
+		{name: "DIA offset", fn: func() { NewDIA(2, 3, []int{3}, nil) }},
+		{name: "DIA repeated offset", fn: func() { NewDIA(2, 3, []int{1, 1}, nil) }},
+		{name: "DIA data length", fn: func() { NewDIA(2, 3, []int{1}, make([]float64, 2)) }},
+		{name: "Mul shape", fn: func() {
+			var m Dense
+			m.Mul(NewCSR(1, 2, []int{0, 0}, nil, nil), NewCSR(1, 2, []int{0, 0}, nil, nil))
+		}},
+	} {
+		if panicked, _ := panics(test.fn); !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func TestDIAExample(t *testing.T) {
+	const x = 100 // Never accessed.
+	m := NewDIA(3, 4, []int{0, 2, -2}, []float64{1, 3, 6, x, x, x, 2, 4, 5, x, x, x})
+	want := NewDense(3, 4, []float64{
+		1, 0, 2, 0,
+		0, 3, 0, 4,
+		5, 0, 6, 0,
+	})
+	if !Equal(m, want) {
+		t.Errorf("unexpected DIA:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+	csr := NewCSR(3, 3, []int{0, 2, 3, 5}, []int{0, 2, 2, 0, 1}, []float64{1, 2, 3, 4, 5})
+	want = NewDense(3, 3, []float64{
+		1, 0, 2,
+		0, 0, 3,
+		4, 5, 0,
+	})
+	if !Equal(csr, want) {
+		t.Errorf("unexpected CSR:\ngot:\n%v\nwant:\n%v", Formatted(csr), Formatted(want))
+	}
+}
+
+func BenchmarkSparseMulVec(b *testing.B) {
+	const n = 100000
+	rnd := rand.New(rand.NewSource(1))
+	coo := NewCOO(n, n, nil, nil, nil)
+	for i := 0; i < n; i++ {
+		for k := 0; k < 10; k++ {
+			coo.Append(i, rnd.Intn(n), rnd.NormFloat64())
+		}
+	}
+	a := coo.ToCSR()
+	x := NewVecDense(n, randFloats(n, rnd))
+	y := NewVecDense(n, nil)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		y.MulVec(a, x)
+	}
+}
+
+// doDenseNonZero calls fn for each non-zero element of d.
+func doDenseNonZero(d *Dense, fn func(i, j int, v float64)) {
+	r, c := d.Dims()
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if v := d.At(i, j); v != 0 {
+				fn(i, j, v)
+			}
+		}
+	}
+}
@@ -71,6 +71,18 @@ func (svd *SVD) succFact() bool {
 // Factorize returns whether the decomposition succeeded. If the decomposition
 // failed, routines that require a successful factorization will panic.
 func (svd *SVD) Factorize(a Matrix, kind SVDKind) (ok bool) {
+	return svd.factorize(a, kind, nil)
+}
+
+// FactorizeWork computes the singular value decomposition of a as for
+// Factorize, using ws for scratch memory. Repeated factorizations of matrices
+// of the same size and kind into the same receiver using the same ws do not
+// allocate. If ws is nil, FactorizeWork is equivalent to Factorize.
+func (svd *SVD) FactorizeWork(a Matrix, kind SVDKind, ws *Workspace) (ok bool) {
+	return svd.factorize(a, kind, ws)
+}
+
+func (svd *SVD) factorize(a Matrix, kind SVDKind, ws *Workspace) (ok bool) {
 	// kill previous factorization
 	svd.s = svd.s[:0]
 	svd.kind = kind
@@ -123,15 +135,15 @@ func (svd *SVD) Factorize(a Matrix, kind SVDKind) (ok bool) {
 	}
 
 	// A is destroyed on call, so copy the matrix.
-	aCopy := DenseCopyOf(a)
+	aCopy := ws.copyOf(a)
 	svd.kind = kind
 	svd.s = use(svd.s, min(m, n))
 
-	work := []float64{0}
+	work := ws.getFloats(wsWork, 1, false)
 	lapack64.Gesvd(jobU, jobVT, aCopy.mat, svd.u, svd.vt, svd.s, work, -1)
-	work = getFloats(int(work[0]), false)
+	work = ws.getFloats(wsWork, ws.lwork(work), false)
 	ok = lapack64.Gesvd(jobU, jobVT, aCopy.mat, svd.u, svd.vt, svd.s, work, len(work))
-	putFloats(work)
+	ws.putFloats(work)
 	if !ok {
 		svd.kind = 0
 	}
@@ -0,0 +1,254 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+// defaultTile is the tile size used by tiled operations
+// when a non-positive tile size is requested.
+const defaultTile = 256
+
+const badTiledCholesky = "mat: invalid tiled Cholesky factorization"
+
+// MulTiled takes the matrix product of a and b, placing the result in the
+// receiver. MulTiled computes the same result as Mul, but works through the
+// matrices in square tiles of size tile so that only a few tiles need to be
+// resident in memory at a time. This makes it suitable for matrices backed by
+// a MappedDense that are larger than the available memory. If tile is not
+// positive, a default tile size is used.
+//
+// If the number of columns in a does not equal the number of rows in b,
+// MulTiled will panic. Unlike Mul, the receiver must not be a or b.
+func (m *Dense) MulTiled(a, b *Dense, tile int) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	if m == a || m == b {
+		panic(regionIdentity)
+	}
+	m.reuseAs(ar, bc)
+	if m.checkOverlap(a.mat) || m.checkOverlap(b.mat) {
+		panic(regionIdentity)
+	}
+	if tile <= 0 {
+		tile = defaultTile
+	}
+
+	for i := 0; i < ar; i += tile {
+		ib := min(tile, ar-i)
+		for j := 0; j < bc; j += tile {
+			jb := min(tile, bc-j)
+			c := m.tile(i, j, ib, jb)
+			beta := 0.0
+			for k := 0; k < ac; k += tile {
+				kb := min(tile, ac-k)
+				blas64.Gemm(blas.NoTrans, blas.NoTrans, 1, a.tile(i, k, ib, kb), b.tile(k, j, kb, jb), beta, c)
+				beta = 1
+			}
+		}
+	}
+}
+
+// tile returns the r×c submatrix of the receiver starting at row i and
+// column j.
+func (m *Dense) tile(i, j, r, c int) blas64.General {
+	return blas64.General{
+		Rows:   r,
+		Cols:   c,
+		Stride: m.mat.Stride,
+		Data:   m.mat.Data[i*m.mat.Stride+j : (i+r-1)*m.mat.Stride+j+c],
+	}
+}
+
+// upperTile returns the upper triangle of the n×n diagonal tile of the
+// receiver starting at row and column i as a triangular matrix.
+func (m *Dense) upperTile(i, n int) blas64.Triangular {
+	t := m.tile(i, i, n, n)
+	return blas64.Triangular{
+		Uplo:   blas.Upper,
+		Diag:   blas.NonUnit,
+		N:      n,
+		Stride: t.Stride,
+		Data:   t.Data,
+	}
+}
+
+// TiledCholesky is a type for creating and using the Cholesky factorization
+// of a symmetric positive definite matrix held in a Dense, computed in place
+// tile by tile. TiledCholesky is intended for matrices backed by a MappedDense
+// that are too large to be copied, such as large kernel matrices.
+type TiledCholesky struct {
+	u    *Dense
+	tile int
+	cond float64
+}
+
+// valid returns whether the receiver contains a successful factorization.
+func (c *TiledCholesky) valid() bool {
+	return c.u != nil
+}
+
+// Factorize calculates the Cholesky decomposition
+//
+//	A = Uᵀ * U
+//
+// of the symmetric matrix held in the upper triangle of the square matrix a,
+// working on square tiles of size tile. If tile is not positive, a default
+// tile size is used. The upper triangle of a is overwritten by U and the
+// strictly lower triangle of a is not referenced. The receiver retains a
+// reference to a, which must not be modified while the factorization is in use.
+//
+// Factorize returns whether the matrix is positive definite. If Factorize
+// returns false, the factorization must not be used and the contents of the
+// upper triangle of a are undefined.
+func (c *TiledCholesky) Factorize(a *Dense, tile int) (ok bool) {
+	n, nc := a.Dims()
+	if n != nc {
+		panic(ErrSquare)
+	}
+	if tile <= 0 {
+		tile = defaultTile
+	}
+	c.u = nil
+	c.tile = tile
+	c.cond = math.Inf(1)
+
+	sym := blas64.Symmetric{
+		Uplo:   blas.Upper,
+		N:      n,
+		Stride: a.mat.Stride,
+		Data:   a.mat.Data,
+	}
+	work := getFloats(3*n, false)
+	defer putFloats(work)
+	norm := lapack64.Lansy(CondNorm, sym, work)
+
+	// Right-looking tiled factorization: factorize the diagonal tile,
+	// solve for the tiles to its right and update the trailing
+	// submatrix with their outer products.
+	for k := 0; k < n; k += tile {
+		kb := min(tile, n-k)
+		ukk := a.upperTile(k, kb)
+		_, ok = lapack64.Potrf(blas64.Symmetric{Uplo: blas.Upper, N: kb, Stride: ukk.Stride, Data: ukk.Data})
+		if !ok {
+			return false
+		}
+		for j := k + kb; j < n; j += tile {
+			jb := min(tile, n-j)
+			blas64.Trsm(blas.Left, blas.Trans, 1, ukk, a.tile(k, j, kb, jb))
+		}
+		for i := k + kb; i < n; i += tile {
+			ib := min(tile, n-i)
+			uki := a.tile(k, i, kb, ib)
+			aii := a.upperTile(i, ib)
+			blas64.Syrk(blas.Trans, -1, uki, 1, blas64.Symmetric{Uplo: blas.Upper, N: ib, Stride: aii.Stride, Data: aii.Data})
+			for j := i + ib; j < n; j += tile {
+				jb := min(tile, n-j)
+				blas64.Gemm(blas.Trans, blas.NoTrans, -1, uki, a.tile(k, j, kb, jb), 1, a.tile(i, j, ib, jb))
+			}
+		}
+	}
+	c.u = a
+
+	iwork := getInts(n, false)
+	v := lapack64.Pocon(sym, norm, work, iwork)
+	putInts(iwork)
+	c.cond = 1 / v
+	return true
+}
+
+// Cond returns the condition number of the factorized matrix.
+func (c *TiledCholesky) Cond() float64 {
+	if !c.valid() {
+		panic(badTiledCholesky)
+	}
+	return c.cond
+}
+
+// LogDet returns the log of the determinant of the matrix that has been factorized.
+func (c *TiledCholesky) LogDet() float64 {
+	if !c.valid() {
+		panic(badTiledCholesky)
+	}
+	var det float64
+	for i := 0; i < c.u.mat.Rows; i++ {
+		det += 2 * math.Log(c.u.mat.Data[i*c.u.mat.Stride+i])
+	}
+	return det
+}
+
+// SolveTo finds the matrix X that solves A * X = B where A is represented
+// by the Cholesky decomposition. The result is stored in-place into dst.
+// The triangular solves work tile by tile through the factor.
+func (c *TiledCholesky) SolveTo(dst *Dense, b Matrix) error {
+	if !c.valid() {
+		panic(badTiledCholesky)
+	}
+	n := c.u.mat.Rows
+	bm, bn := b.Dims()
+	if n != bm {
+		panic(ErrShape)
+	}
+	dst.reuseAs(bm, bn)
+	if b != dst {
+		dst.Copy(b)
+	}
+
+	u := c.u
+	tile := c.tile
+	// Solve Uᵀ * Y = B by forward substitution.
+	for k := 0; k < n; k += tile {
+		kb := min(tile, n-k)
+		yk := dst.tile(k, 0, kb, bn)
+		blas64.Trsm(blas.Left, blas.Trans, 1, u.upperTile(k, kb), yk)
+		for i := k + kb; i < n; i += tile {
+			ib := min(tile, n-i)
+			blas64.Gemm(blas.Trans, blas.NoTrans, -1, u.tile(k, i, kb, ib), yk, 1, dst.tile(i, 0, ib, bn))
+		}
+	}
+	// Solve U * X = Y by back substitution.
+	for k := ((n - 1) / tile) * tile; k >= 0; k -= tile {
+		kb := min(tile, n-k)
+		xk := dst.tile(k, 0, kb, bn)
+		blas64.Trsm(blas.Left, blas.NoTrans, 1, u.upperTile(k, kb), xk)
+		for i := 0; i < k; i += tile {
+			ib := min(tile, k-i)
+			blas64.Gemm(blas.NoTrans, blas.NoTrans, -1, u.tile(i, k, ib, kb), xk, 1, dst.tile(i, 0, ib, bn))
+		}
+	}
+	if c.cond > ConditionTolerance {
+		return Condition(c.cond)
+	}
+	return nil
+}
+
+// UTo extracts the n×n upper triangular matrix U from the tiled Cholesky
+// decomposition into dst and returns the result. If dst is nil a new
+// TriDense is allocated.
+//
+//	A = Uᵀ * U.
+func (c *TiledCholesky) UTo(dst *TriDense) *TriDense {
+	if !c.valid() {
+		panic(badTiledCholesky)
+	}
+	n := c.u.mat.Rows
+	if dst == nil {
+		dst = NewTriDense(n, Upper, nil)
+	} else {
+		dst.reuseAs(n, Upper)
+	}
+	for i := 0; i < n; i++ {
+		copy(dst.mat.Data[i*dst.mat.Stride+i:i*dst.mat.Stride+n], c.u.mat.Data[i*c.u.mat.Stride+i:i*c.u.mat.Stride+n])
+	}
+	return dst
+}
@@ -0,0 +1,197 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestMappedDense(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mat")
+	if err != nil {
+		t.Fatalf("unexpected error creating temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "a.mat")
+
+	rnd := rand.New(rand.NewSource(1))
+	want := randNormDense(7, 5, rnd)
+
+	m, err := CreateMapped(path, 7, 5)
+	if err != nil {
+		t.Fatalf("unexpected error creating mapped matrix: %v", err)
+	}
+	if !Equal(m.Dense(), NewDense(7, 5, nil)) {
+		t.Errorf("created matrix is not zero")
+	}
+	m.Dense().Copy(want)
+	err = m.Sync()
+	if err != nil {
+		t.Errorf("unexpected error syncing mapped matrix: %v", err)
+	}
+	err = m.Close()
+	if err != nil {
+		t.Errorf("unexpected error closing mapped matrix: %v", err)
+	}
+	if !m.Dense().IsZero() {
+		t.Errorf("matrix not empty after close")
+	}
+	if m.Close() == nil {
+		t.Errorf("expected error closing closed matrix")
+	}
+
+	// The file must be readable by UnmarshalBinary.
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error reading file: %v", err)
+	}
+	var got Dense
+	err = got.UnmarshalBinary(buf)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshaling file: %v", err)
+	}
+	if !Equal(&got, want) {
+		t.Errorf("unexpected file contents:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(want))
+	}
+
+	// Files written by MarshalBinaryTo must be mappable
+	// and changes must be written back.
+	var b bytes.Buffer
+	_, err = want.MarshalBinaryTo(&b)
+	if err != nil {
+		t.Fatalf("unexpected error marshaling matrix: %v", err)
+	}
+	err = ioutil.WriteFile(path, b.Bytes(), 0666)
+	if err != nil {
+		t.Fatalf("unexpected error writing file: %v", err)
+	}
+	m, err = OpenMapped(path)
+	if err != nil {
+		t.Fatalf("unexpected error opening mapped matrix: %v", err)
+	}
+	if !Equal(m.Dense(), want) {
+		t.Errorf("unexpected mapped matrix:\ngot:\n%v\nwant:\n%v", Formatted(m.Dense()), Formatted(want))
+	}
+	m.Dense().Set(2, 3, 42)
+	want.Set(2, 3, 42)
+	err = m.Close()
+	if err != nil {
+		t.Errorf("unexpected error closing mapped matrix: %v", err)
+	}
+	m, err = OpenMapped(path)
+	if err != nil {
+		t.Fatalf("unexpected error reopening mapped matrix: %v", err)
+	}
+	if !Equal(m.Dense(), want) {
+		t.Errorf("change not written back:\ngot:\n%v\nwant:\n%v", Formatted(m.Dense()), Formatted(want))
+	}
+	m.Close()
+
+	err = ioutil.WriteFile(path, b.Bytes()[:b.Len()-1], 0666)
+	if err != nil {
+		t.Fatalf("unexpected error writing file: %v", err)
+	}
+	_, err = OpenMapped(path)
+	if err == nil {
+		t.Errorf("expected error opening truncated file")
+	}
+}
+
+func TestMulTiled(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, k, c, tile int
+	}{
+		{r: 1, k: 1, c: 1, tile: 1},
+		{r: 5, k: 3, c: 4, tile: 2},
+		{r: 10, k: 10, c: 10, tile: 3},
+		{r: 17, k: 9, c: 13, tile: 4},
+		{r: 20, k: 30, c: 25, tile: 0},
+		{r: 8, k: 8, c: 8, tile: 100},
+	} {
+		a := randNormDense(test.r, test.k, rnd)
+		b := randNormDense(test.k, test.c, rnd)
+		var want, got Dense
+		want.Mul(a, b)
+		got.MulTiled(a, b, test.tile)
+		if !EqualApprox(&got, &want, 1e-12) {
+			t.Errorf("unexpected result for r=%d k=%d c=%d tile=%d:\ngot:\n%v\nwant:\n%v",
+				test.r, test.k, test.c, test.tile, Formatted(&got), Formatted(&want))
+		}
+	}
+}
+
+func TestTiledCholesky(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, tile int
+	}{
+		{n: 1, tile: 1},
+		{n: 5, tile: 2},
+		{n: 10, tile: 3},
+		{n: 17, tile: 4},
+		{n: 20, tile: 0},
+		{n: 12, tile: 12},
+	} {
+		x := randNormDense(test.n, test.n, rnd)
+		var sym SymDense
+		sym.SymOuterK(1, x)
+		for i := 0; i < test.n; i++ {
+			sym.SetSym(i, i, sym.At(i, i)+1)
+		}
+
+		var chol Cholesky
+		if !chol.Factorize(&sym) {
+			t.Fatalf("unexpected Cholesky failure for n=%d", test.n)
+		}
+		a := NewDense(test.n, test.n, nil)
+		a.Copy(&sym)
+
+		var tc TiledCholesky
+		if !tc.Factorize(a, test.tile) {
+			t.Errorf("unexpected tiled Cholesky failure for n=%d tile=%d", test.n, test.tile)
+			continue
+		}
+		got := tc.UTo(nil)
+		want := chol.UTo(nil)
+		if !EqualApprox(got, want, 1e-12) {
+			t.Errorf("unexpected U for n=%d tile=%d:\ngot:\n%v\nwant:\n%v", test.n, test.tile, Formatted(got), Formatted(want))
+		}
+		if math.Abs(tc.LogDet()-chol.LogDet()) > 1e-10 {
+			t.Errorf("unexpected log determinant for n=%d tile=%d: got %v want %v", test.n, test.tile, tc.LogDet(), chol.LogDet())
+		}
+		if math.Abs(tc.Cond()-chol.Cond()) > 1e-8*chol.Cond() {
+			t.Errorf("unexpected condition number for n=%d tile=%d: got %v want %v", test.n, test.tile, tc.Cond(), chol.Cond())
+		}
+
+		b := randNormDense(test.n, 3, rnd)
+		var x1, x2 Dense
+		err := tc.SolveTo(&x1, b)
+		if err != nil {
+			t.Errorf("unexpected error from SolveTo for n=%d: %v", test.n, err)
+		}
+		chol.SolveTo(&x2, b)
+		if !EqualApprox(&x1, &x2, 1e-10) {
+			t.Errorf("unexpected solution for n=%d tile=%d:\ngot:\n%v\nwant:\n%v", test.n, test.tile, Formatted(&x1), Formatted(&x2))
+		}
+	}
+
+	a := NewDense(3, 3, []float64{
+		1, 2, 0,
+		0, 1, 0,
+		0, 0, 1,
+	})
+	var tc TiledCholesky
+	if tc.Factorize(a, 1) {
+		t.Errorf("expected failure for indefinite matrix")
+	}
+}
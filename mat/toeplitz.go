@@ -0,0 +1,398 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/fourier"
+)
+
+var (
+	toeplitz *Toeplitz
+	_        Matrix   = toeplitz
+	_        Operator = toeplitz
+
+	circulant *Circulant
+	_         Matrix   = circulant
+	_         Operator = circulant
+)
+
+// Toeplitz represents an n×n Toeplitz matrix, a matrix that is constant along
+// each of its diagonals,
+//
+//	T[i, j] = t[i-j].
+//
+// Products of a Toeplitz matrix with vectors are computed in O(n log n) time
+// by embedding the matrix in a circulant matrix and using the fast Fourier
+// transform, and linear systems are solved in O(n²) time by the Levinson
+// recursion, without storing the n² elements of the matrix.
+type Toeplitz struct {
+	col, row []float64
+
+	// m is the order of the circulant
+	// embedding and lambda holds the
+	// Fourier coefficients of its first
+	// column.
+	m      int
+	lambda []complex128
+}
+
+// NewToeplitz returns a new n×n Toeplitz matrix with the first column col and
+// the first row row, so that T[i, j] is col[i-j] for i >= j and row[j-i] for
+// i < j. If row is nil, the matrix is symmetric with row equal to col. The
+// slices are copied.
+//
+// NewToeplitz will panic if col is empty, if row is not nil and has a length
+// different to col, or if col[0] and row[0] differ.
+func NewToeplitz(col, row []float64) *Toeplitz {
+	n := len(col)
+	if n == 0 {
+		panic(ErrZeroLength)
+	}
+	if row == nil {
+		row = col
+	}
+	if len(row) != n {
+		panic(ErrShape)
+	}
+	if col[0] != row[0] {
+		panic("mat: Toeplitz diagonal mismatch")
+	}
+	t := &Toeplitz{
+		col: make([]float64, n),
+		row: make([]float64, n),
+	}
+	copy(t.col, col)
+	copy(t.row, row)
+
+	// Embed T in the leading n×n block of an m×m circulant
+	// matrix with m a power of two of at least 2n-1.
+	t.m = 1
+	for t.m < 2*n-1 {
+		t.m <<= 1
+	}
+	c := make([]float64, t.m)
+	copy(c, t.col)
+	for k := 1; k < n; k++ {
+		c[t.m-k] = t.row[k]
+	}
+	t.lambda = fourier.NewFFT(t.m).Coefficients(nil, c)
+	return t
+}
+
+// Dims returns the dimensions of the matrix.
+func (t *Toeplitz) Dims() (r, c int) {
+	return len(t.col), len(t.col)
+}
+
+// At returns the element at row i, column j.
+func (t *Toeplitz) At(i, j int) float64 {
+	n := len(t.col)
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	if i >= j {
+		return t.col[i-j]
+	}
+	return t.row[j-i]
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (t *Toeplitz) T() Matrix {
+	return Transpose{t}
+}
+
+// MulVecTo stores T*x into dst if trans is false, and Tᵀ*x into dst
+// otherwise, using the fast Fourier transform. If dst is empty, it is
+// resized to the length of x.
+//
+// MulVecTo will panic if the length of x is not n or if dst is not empty and
+// its length is not n.
+func (t *Toeplitz) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	n := len(t.col)
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	dst.reuseAs(n)
+	circulantMulVec(dst, t.lambda, t.m, trans, x)
+}
+
+// SolveVecTo solves the linear system
+//
+//	T * x = b if trans is false
+//	Tᵀ * x = b if trans is true
+//
+// using the Levinson recursion, storing the result into dst. If dst is
+// empty, it is resized to the length of b.
+//
+// The Levinson recursion requires that all the leading principal submatrices
+// of T be non-singular, which holds for symmetric positive definite Toeplitz
+// matrices such as autocovariance matrices. If a leading principal submatrix
+// is singular, SolveVecTo returns ErrSingular and the contents of dst are
+// undefined. The recursion is not pivoted and so may be unstable for
+// matrices that are not positive definite.
+//
+// SolveVecTo will panic if the length of b is not n or if dst is not empty
+// and its length is not n.
+func (t *Toeplitz) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	n := len(t.col)
+	if b.Len() != n {
+		panic(ErrShape)
+	}
+	col, row := t.col, t.row
+	if trans {
+		col, row = row, col
+	}
+	// r returns the element on the diagonal d = i-j.
+	r := func(d int) float64 {
+		if d >= 0 {
+			return col[d]
+		}
+		return row[-d]
+	}
+
+	// The recursion follows Press et al., Numerical Recipes,
+	// section 2.8, solving for the forward and backward
+	// vectors g and h of the growing leading submatrices.
+	// The slices are indexed from one.
+	if r(0) == 0 {
+		return ErrSingular
+	}
+	x := make([]float64, n+1)
+	g := make([]float64, n+1)
+	h := make([]float64, n+1)
+	x[1] = b.AtVec(0) / r(0)
+	if n > 1 {
+		g[1] = r(-1) / r(0)
+		h[1] = r(1) / r(0)
+	}
+	for m := 1; m < n; m++ {
+		m1 := m + 1
+		sxn := -b.AtVec(m1 - 1)
+		sd := -r(0)
+		for j := 1; j <= m; j++ {
+			sxn += r(m1-j) * x[j]
+			sd += r(m1-j) * g[m-j+1]
+		}
+		if sd == 0 {
+			return ErrSingular
+		}
+		x[m1] = sxn / sd
+		for j := 1; j <= m; j++ {
+			x[j] -= x[m1] * g[m-j+1]
+		}
+		if m1 == n {
+			break
+		}
+
+		sgn := -r(-m1)
+		shn := -r(m1)
+		sgd := -r(0)
+		for j := 1; j <= m; j++ {
+			sgn += r(j-m1) * g[j]
+			shn += r(m1-j) * h[j]
+			sgd += r(j-m1) * h[m-j+1]
+		}
+		if sgd == 0 {
+			return ErrSingular
+		}
+		g[m1] = sgn / sgd
+		h[m1] = shn / sd
+		k := m
+		pp, qq := g[m1], h[m1]
+		for j := 1; j <= (m+1)/2; j++ {
+			pt1, pt2 := g[j], g[k]
+			qt1, qt2 := h[j], h[k]
+			g[j] = pt1 - pp*qt2
+			g[k] = pt2 - pp*qt1
+			h[j] = qt1 - qq*pt2
+			h[k] = qt2 - qq*pt1
+			k--
+		}
+	}
+
+	dst.reuseAs(n)
+	for i := 0; i < n; i++ {
+		dst.setVec(i, x[i+1])
+	}
+	return nil
+}
+
+// Circulant represents an n×n circulant matrix, a Toeplitz matrix in which
+// each column is the cyclic shift of the previous one,
+//
+//	C[i, j] = c[(i-j) mod n].
+//
+// A circulant matrix is diagonalized by the discrete Fourier transform, so
+// products with vectors and linear systems are computed in O(n log n) time.
+type Circulant struct {
+	c      []float64
+	lambda []complex128
+}
+
+// NewCirculant returns a new n×n circulant matrix with the first column c.
+// The slice is copied. NewCirculant will panic if c is empty.
+func NewCirculant(c []float64) *Circulant {
+	n := len(c)
+	if n == 0 {
+		panic(ErrZeroLength)
+	}
+	cc := make([]float64, n)
+	copy(cc, c)
+	return &Circulant{
+		c:      cc,
+		lambda: fourier.NewFFT(n).Coefficients(nil, cc),
+	}
+}
+
+// Dims returns the dimensions of the matrix.
+func (c *Circulant) Dims() (r, cols int) {
+	return len(c.c), len(c.c)
+}
+
+// At returns the element at row i, column j.
+func (c *Circulant) At(i, j int) float64 {
+	n := len(c.c)
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	return c.c[(i-j+n)%n]
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (c *Circulant) T() Matrix {
+	return Transpose{c}
+}
+
+// Values returns the eigenvalues of the circulant matrix, the discrete
+// Fourier transform of its first column, ordered by frequency,
+//
+//	λ_k = Σ_j c[j] * exp(-2πi*j*k/n).
+//
+// If dst is not nil, the values are stored in-place into dst and dst must
+// have length n, otherwise Values will panic with ErrSliceLengthMismatch. If
+// dst is nil, a new slice is allocated and returned.
+func (c *Circulant) Values(dst []complex128) []complex128 {
+	n := len(c.c)
+	if dst == nil {
+		dst = make([]complex128, n)
+	}
+	if len(dst) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	// The coefficients of the real first column
+	// hold the non-negative frequencies, and the
+	// remaining values are their conjugates.
+	copy(dst, c.lambda)
+	for k := len(c.lambda); k < n; k++ {
+		dst[k] = cmplx.Conj(c.lambda[n-k])
+	}
+	return dst
+}
+
+// MulVecTo stores C*x into dst if trans is false, and Cᵀ*x into dst
+// otherwise, using the fast Fourier transform. If dst is empty, it is
+// resized to the length of x.
+//
+// MulVecTo will panic if the length of x is not n or if dst is not empty and
+// its length is not n.
+func (c *Circulant) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	n := len(c.c)
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	dst.reuseAs(n)
+	circulantMulVec(dst, c.lambda, n, trans, x)
+}
+
+// SolveVecTo solves the linear system
+//
+//	C * x = b if trans is false
+//	Cᵀ * x = b if trans is true
+//
+// using the fast Fourier transform, storing the result into dst. If dst is
+// empty, it is resized to the length of b.
+//
+// If C is singular or near-singular a Condition error is returned. See the
+// documentation for Condition for more information. SolveVecTo will panic
+// if the length of b is not n or if dst is not empty and its length is not
+// n.
+func (c *Circulant) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	n := len(c.c)
+	if b.Len() != n {
+		panic(ErrShape)
+	}
+	lmin, lmax := math.Inf(1), 0.0
+	for _, l := range c.lambda {
+		a := cmplx.Abs(l)
+		lmin = math.Min(lmin, a)
+		lmax = math.Max(lmax, a)
+	}
+	if lmin == 0 {
+		return Condition(math.Inf(1))
+	}
+
+	fft := fourier.NewFFT(n)
+	seq := make([]float64, n)
+	for i := range seq {
+		seq[i] = b.AtVec(i)
+	}
+	coeff := fft.Coefficients(nil, seq)
+	for k, l := range c.lambda {
+		if trans {
+			l = cmplx.Conj(l)
+		}
+		coeff[k] /= l * complex(float64(n), 0)
+	}
+	fft.Sequence(seq, coeff)
+	dst.reuseAs(n)
+	for i, v := range seq {
+		dst.setVec(i, v)
+	}
+
+	// C is normal, so its condition number in the
+	// 2-norm is the ratio of the extreme magnitudes
+	// of its eigenvalues.
+	if cond := lmax / lmin; cond > ConditionTolerance {
+		return Condition(cond)
+	}
+	return nil
+}
+
+// circulantMulVec stores into dst the leading len(dst) elements of the
+// product of the m×m circulant matrix with the Fourier coefficients lambda,
+// or its transpose if trans is true, and the vector x padded with zeros to
+// length m.
+func circulantMulVec(dst *VecDense, lambda []complex128, m int, trans bool, x Vector) {
+	fft := fourier.NewFFT(m)
+	seq := make([]float64, m)
+	for i := 0; i < x.Len(); i++ {
+		seq[i] = x.AtVec(i)
+	}
+	coeff := fft.Coefficients(nil, seq)
+	for k, l := range lambda {
+		if trans {
+			// The transpose of a real circulant matrix has
+			// the conjugate Fourier coefficients.
+			l = cmplx.Conj(l)
+		}
+		coeff[k] *= l
+	}
+	fft.Sequence(seq, coeff)
+	scale := 1 / float64(m)
+	for i := 0; i < dst.Len(); i++ {
+		dst.setVec(i, seq[i]*scale)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestToeplitz(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 4, 7, 16, 33, 100} {
+		for _, sym := range []bool{false, true} {
+			// Make T diagonally dominant so that all its leading
+			// principal submatrices are well conditioned.
+			col := make([]float64, n)
+			row := make([]float64, n)
+			for k := range col {
+				col[k] = rnd.NormFloat64() / float64(k+1)
+				row[k] = rnd.NormFloat64() / float64(k+1)
+			}
+			col[0] = 2 * math.Log(float64(n)+1) * (1 + rnd.Float64())
+			row[0] = col[0]
+			if sym {
+				row = nil
+			}
+			a := NewToeplitz(col, row)
+
+			var want Dense
+			want.CloneFrom(a)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					var v float64
+					if i >= j {
+						v = col[i-j]
+					} else if sym {
+						v = col[j-i]
+					} else {
+						v = row[j-i]
+					}
+					if want.At(i, j) != v {
+						t.Fatalf("unexpected element for n=%d sym=%t at (%d,%d): got %v want %v", n, sym, i, j, want.At(i, j), v)
+					}
+				}
+			}
+
+			x := NewVecDense(n, nil)
+			for i := 0; i < n; i++ {
+				x.SetVec(i, rnd.NormFloat64())
+			}
+			for _, trans := range []bool{false, true} {
+				var m Matrix = &want
+				if trans {
+					m = want.T()
+				}
+				var got, ref VecDense
+				a.MulVecTo(&got, trans, x)
+				ref.MulVec(m, x)
+				if !EqualApprox(&got, &ref, 1e-12) {
+					t.Errorf("unexpected product for n=%d sym=%t trans=%t", n, sym, trans)
+				}
+
+				var sol, b VecDense
+				err := a.SolveVecTo(&sol, trans, x)
+				if err != nil {
+					t.Errorf("unexpected error for n=%d sym=%t trans=%t: %v", n, sym, trans, err)
+					continue
+				}
+				b.MulVec(m, &sol)
+				if !EqualApprox(&b, x, 1e-10) {
+					t.Errorf("unexpected solution for n=%d sym=%t trans=%t", n, sym, trans)
+				}
+			}
+		}
+	}
+
+	// The leading 2×2 submatrix is singular.
+	a := NewToeplitz([]float64{1, 1, 0}, []float64{1, 1, 1})
+	var x VecDense
+	if err := a.SolveVecTo(&x, false, NewVecDense(3, []float64{1, 2, 3})); err != ErrSingular {
+		t.Errorf("unexpected error for singular leading submatrix: got %v want %v", err, ErrSingular)
+	}
+
+	if p, _ := panics(func() { NewToeplitz([]float64{1, 2}, []float64{2, 1}) }); !p {
+		t.Error("expected panic for diagonal mismatch")
+	}
+	if p, _ := panics(func() { NewToeplitz([]float64{1, 2}, []float64{1}) }); !p {
+		t.Error("expected panic for length mismatch")
+	}
+}
+
+func TestCirculant(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 8, 17, 64} {
+		c := make([]float64, n)
+		for i := range c {
+			c[i] = rnd.NormFloat64()
+		}
+		c[0] += 2 * float64(n)
+		a := NewCirculant(c)
+
+		var want Dense
+		want.CloneFrom(a)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if want.At(i, j) != c[((i-j)%n+n)%n] {
+					t.Fatalf("unexpected element for n=%d at (%d,%d)", n, i, j)
+				}
+			}
+		}
+
+		// Check that the values are the eigenvalues of C with
+		// the Fourier vectors as eigenvectors.
+		values := a.Values(nil)
+		for k, l := range values {
+			v := NewCDense(n, 1, nil)
+			for j := 0; j < n; j++ {
+				v.Set(j, 0, cmplx.Exp(complex(0, 2*math.Pi*float64(j*k)/float64(n))))
+			}
+			var av CDense
+			av.Mul(NewCDense(n, n, realToComplex(want.RawMatrix().Data)), v)
+			for j := 0; j < n; j++ {
+				if cmplx.Abs(av.At(j, 0)-l*v.At(j, 0)) > 1e-10*float64(n) {
+					t.Errorf("unexpected eigenvalue %d for n=%d", k, n)
+					break
+				}
+			}
+		}
+
+		x := NewVecDense(n, nil)
+		for i := 0; i < n; i++ {
+			x.SetVec(i, rnd.NormFloat64())
+		}
+		for _, trans := range []bool{false, true} {
+			var m Matrix = &want
+			if trans {
+				m = want.T()
+			}
+			var got, ref VecDense
+			a.MulVecTo(&got, trans, x)
+			ref.MulVec(m, x)
+			if !EqualApprox(&got, &ref, 1e-12) {
+				t.Errorf("unexpected product for n=%d trans=%t", n, trans)
+			}
+
+			var sol, b VecDense
+			err := a.SolveVecTo(&sol, trans, x)
+			if err != nil {
+				t.Errorf("unexpected error for n=%d trans=%t: %v", n, trans, err)
+				continue
+			}
+			b.MulVec(m, &sol)
+			if !EqualApprox(&b, x, 1e-12) {
+				t.Errorf("unexpected solution for n=%d trans=%t", n, trans)
+			}
+		}
+	}
+
+	// The all-ones circulant matrix is singular.
+	a := NewCirculant([]float64{1, 1, 1, 1})
+	var x VecDense
+	err := a.SolveVecTo(&x, false, NewVecDense(4, []float64{1, 2, 3, 4}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular matrix, got %v", err)
+	}
+}
+
+func realToComplex(s []float64) []complex128 {
+	c := make([]complex128, len(s))
+	for i, v := range s {
+		c[i] = complex(v, 0)
+	}
+	return c
+}
@@ -0,0 +1,257 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+// TruncatedSVD is a type for creating and using a truncated singular value
+// decomposition of a matrix, holding only its k largest singular values and
+// the corresponding singular vectors.
+type TruncatedSVD struct {
+	kind SVDKind
+
+	s    []float64
+	u, v *Dense
+}
+
+// RandomizedSVDOptions holds the parameters of the randomized range finder
+// used by TruncatedSVD.
+type RandomizedSVDOptions struct {
+	// Oversample is the number of sample vectors in
+	// addition to k used to find the range of the
+	// matrix. Increasing Oversample improves the
+	// accuracy of the decomposition.
+	Oversample int
+
+	// PowerIterations is the number of subspace
+	// iterations with the matrix and its transpose
+	// used to refine the range. Power iterations
+	// improve the accuracy of the decomposition of
+	// matrices with slowly decaying singular values.
+	PowerIterations int
+
+	// Src is the source of random numbers used for
+	// sampling the range. If Src is nil, the global
+	// source is used.
+	Src rand.Source
+}
+
+// defaultRandomizedSVD holds the RandomizedSVDOptions used when none are
+// provided.
+var defaultRandomizedSVD = RandomizedSVDOptions{Oversample: 10, PowerIterations: 2}
+
+// Factorize computes an approximation to the truncated singular value
+// decomposition of the m×n matrix A
+//
+//	A ≈ U * Σ * Vᵀ
+//
+// where Σ is a k×k diagonal matrix holding the k largest singular values of
+// A, and U and V are, respectively, m×k and n×k matrices with orthonormal
+// columns holding the corresponding left and right singular vectors. The
+// thin vectors U and V are computed when kind includes SVDThinU and
+// SVDThinV respectively. Full vectors are not supported.
+//
+// Factorize uses the randomized range finder with subspace iteration of
+// Halko, Martinsson and Tropp, "Finding structure with randomness:
+// Probabilistic algorithms for constructing approximate matrix
+// decompositions", SIAM Review 53(2), 2011. The matrix A is only used to
+// form products of A and Aᵀ with dense matrices, so a may be a sparse
+// matrix provided by this package. If opts is nil, default options with an
+// oversampling of 10 and 2 power iterations are used. The decomposition is
+// exact up to rounding errors when k plus the oversampling is at least
+// min(m,n), or when the rank of A is at most k.
+//
+// Factorize returns whether the decomposition succeeded. If the
+// decomposition failed, routines that require a successful factorization
+// will panic. Factorize will panic if k is not in [1, min(m,n)], if kind
+// includes full vectors or if opts holds negative values.
+func (svd *TruncatedSVD) Factorize(a Matrix, k int, kind SVDKind, opts *RandomizedSVDOptions) (ok bool) {
+	return svd.FactorizeOperator(MatrixOperator{a}, k, kind, opts)
+}
+
+// FactorizeOperator computes an approximation to the truncated singular value
+// decomposition of the m×n operator a as for Factorize. Only the products of
+// a and its transpose with dense matrices are used, which are formed one
+// column at a time unless a is a MulToer.
+func (svd *TruncatedSVD) FactorizeOperator(a Operator, k int, kind SVDKind, opts *RandomizedSVDOptions) (ok bool) {
+	svd.s = svd.s[:0]
+	m, n := a.Dims()
+	if k <= 0 || min(m, n) < k {
+		panic("mat: truncated SVD rank out of range")
+	}
+	if kind&(SVDFullU|SVDFullV) != 0 {
+		panic("mat: full vectors not supported by truncated SVD")
+	}
+	if opts == nil {
+		opts = &defaultRandomizedSVD
+	}
+	if opts.Oversample < 0 || opts.PowerIterations < 0 {
+		panic("mat: negative randomized SVD option")
+	}
+	l := min(k+opts.Oversample, min(m, n))
+
+	normal := rand.NormFloat64
+	if opts.Src != nil {
+		normal = rand.New(opts.Src).NormFloat64
+	}
+	omega := NewDense(n, l, nil)
+	for i := range omega.mat.Data {
+		omega.mat.Data[i] = normal()
+	}
+
+	// Find an orthonormal basis Q of the range
+	// of A by subspace iteration from A * Ω.
+	var y, z Dense
+	mulOperator(&y, a, false, omega)
+	q := orthonormalBasis(&y)
+	for i := 0; i < opts.PowerIterations; i++ {
+		z.Reset()
+		mulOperator(&z, a, true, q)
+		y.Reset()
+		mulOperator(&y, a, false, orthonormalBasis(&z))
+		q = orthonormalBasis(&y)
+	}
+
+	// Decompose Bᵀ = Aᵀ * Q = Ṽ * Σ * Wᵀ, so that
+	// A ≈ Q * B = (Q * W) * Σ * Ṽᵀ.
+	var bt Dense
+	mulOperator(&bt, a, true, q)
+	var small SVD
+	if !small.Factorize(&bt, SVDThin) {
+		svd.kind = 0
+		return false
+	}
+	svd.kind = kind
+	svd.s = use(svd.s, k)
+	copy(svd.s, small.s[:k])
+	if kind&SVDThinU != 0 {
+		var w Dense
+		small.VTo(&w)
+		if svd.u == nil {
+			svd.u = &Dense{}
+		}
+		svd.u.Reset()
+		svd.u.Mul(q, w.Slice(0, l, 0, k))
+	}
+	if kind&SVDThinV != 0 {
+		var v Dense
+		small.UTo(&v)
+		if svd.v == nil {
+			svd.v = &Dense{}
+		}
+		svd.v.Reset()
+		svd.v.CloneFrom(v.Slice(0, n, 0, k))
+	}
+	return true
+}
+
+// orthonormalBasis returns a matrix with orthonormal columns spanning the
+// columns of the r×c matrix y, where r ≥ c. The contents of y are
+// overwritten.
+func orthonormalBasis(y *Dense) *Dense {
+	r, c := y.Dims()
+	tau := getFloats(c, false)
+	defer putFloats(tau)
+	work := []float64{0}
+	lapack64.Geqrf(y.mat, tau, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Geqrf(y.mat, tau, work, len(work))
+	putFloats(work)
+
+	q := NewDense(r, c, nil)
+	for i := 0; i < c; i++ {
+		q.set(i, i, 1)
+	}
+	work = []float64{0}
+	lapack64.Ormqr(blas.Left, blas.NoTrans, y.mat, tau, q.mat, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Ormqr(blas.Left, blas.NoTrans, y.mat, tau, q.mat, work, len(work))
+	putFloats(work)
+	return q
+}
+
+// Kind returns the SVDKind of the decomposition. If no decomposition has
+// been computed, Kind returns -1.
+func (svd *TruncatedSVD) Kind() SVDKind {
+	if len(svd.s) == 0 {
+		return -1
+	}
+	return svd.kind
+}
+
+// Values returns the k singular values of the factorized matrix in
+// descending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length k, and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a
+// new slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful
+// factorization.
+func (svd *TruncatedSVD) Values(s []float64) []float64 {
+	if len(svd.s) == 0 {
+		panic(badFact)
+	}
+	if s == nil {
+		s = make([]float64, len(svd.s))
+	}
+	if len(s) != len(svd.s) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(s, svd.s)
+	return s
+}
+
+// UTo extracts the m×k matrix U of left singular vectors from the truncated
+// singular value decomposition. The columns correspond to the singular
+// values as returned from Values.
+//
+// If dst is not nil, U is stored in-place into dst, and dst must have size
+// m×k, and UTo panics otherwise. If dst is nil, a new matrix of the
+// appropriate size is allocated and returned.
+func (svd *TruncatedSVD) UTo(dst *Dense) *Dense {
+	if len(svd.s) == 0 {
+		panic(badFact)
+	}
+	if svd.kind&SVDThinU == 0 {
+		panic("svd: u not computed during factorization")
+	}
+	return copyFactor(dst, svd.u)
+}
+
+// VTo extracts the n×k matrix V of right singular vectors from the
+// truncated singular value decomposition. The columns correspond to the
+// singular values as returned from Values.
+//
+// If dst is not nil, V is stored in-place into dst, and dst must have size
+// n×k, and VTo panics otherwise. If dst is nil, a new matrix of the
+// appropriate size is allocated and returned.
+func (svd *TruncatedSVD) VTo(dst *Dense) *Dense {
+	if len(svd.s) == 0 {
+		panic(badFact)
+	}
+	if svd.kind&SVDThinV == 0 {
+		panic("svd: v not computed during factorization")
+	}
+	return copyFactor(dst, svd.v)
+}
+
+// copyFactor copies f into dst, allocating dst if it is nil.
+func copyFactor(dst *Dense, f Matrix) *Dense {
+	r, c := f.Dims()
+	if dst == nil {
+		dst = NewDense(r, c, nil)
+	} else {
+		dst.reuseAs(r, c)
+	}
+	dst.Copy(f)
+	return dst
+}
@@ -0,0 +1,181 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// randSpectrum returns a random m×n matrix with the given singular values.
+func randSpectrum(m, n int, s []float64, rnd *rand.Rand) *Dense {
+	k := len(s)
+	u := randOrthonormal(m, k, rnd)
+	v := randOrthonormal(n, k, rnd)
+	for j, sj := range s {
+		for i := 0; i < m; i++ {
+			u.set(i, j, u.at(i, j)*sj)
+		}
+	}
+	var a Dense
+	a.Mul(u, v.T())
+	return &a
+}
+
+// randOrthonormal returns a random r×c matrix with orthonormal columns.
+func randOrthonormal(r, c int, rnd *rand.Rand) *Dense {
+	y := NewDense(r, c, nil)
+	for i := range y.mat.Data {
+		y.mat.Data[i] = rnd.NormFloat64()
+	}
+	return orthonormalBasis(y)
+}
+
+func TestTruncatedSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+		s       func(i int) float64
+		opts    *RandomizedSVDOptions
+		tol     float64
+	}{
+		// Exactly low rank.
+		{m: 50, n: 30, k: 5, s: func(i int) float64 {
+			if i < 5 {
+				return float64(10 - i)
+			}
+			return 0
+		}, tol: 1e-12},
+		{m: 30, n: 50, k: 3, s: func(i int) float64 {
+			if i < 2 {
+				return float64(3 - i)
+			}
+			return 0
+		}, tol: 1e-12},
+
+		// Oversampling covers the whole range.
+		{m: 20, n: 12, k: 4, s: func(i int) float64 { return 1 / float64(i+1) }, tol: 1e-12},
+
+		// Rapidly and slowly decaying spectra.
+		{m: 200, n: 100, k: 10, s: func(i int) float64 { return math.Pow(0.5, float64(i)) }, tol: 1e-8},
+		{m: 100, n: 200, k: 10, s: func(i int) float64 { return 1 / float64(i+1) }, tol: 1e-2},
+		{m: 100, n: 200, k: 10, s: func(i int) float64 { return 1 / float64(i+1) },
+			opts: &RandomizedSVDOptions{Oversample: 20, PowerIterations: 6}, tol: 1e-5},
+	} {
+		r := min(test.m, test.n)
+		s := make([]float64, r)
+		for i := range s {
+			s[i] = test.s(i)
+		}
+		a := randSpectrum(test.m, test.n, s, rnd)
+		name := fmt.Sprintf("m=%d n=%d k=%d", test.m, test.n, test.k)
+
+		opts := test.opts
+		if opts == nil {
+			opts = &RandomizedSVDOptions{Oversample: 10, PowerIterations: 2}
+		}
+		opts.Src = rand.NewSource(2)
+		var svd TruncatedSVD
+		if !svd.Factorize(a, test.k, SVDThin, opts) {
+			t.Errorf("%s: unexpected factorization failure", name)
+			continue
+		}
+		if svd.Kind() != SVDThin {
+			t.Errorf("%s: unexpected kind: %v", name, svd.Kind())
+		}
+		got := svd.Values(nil)
+		for i, v := range got {
+			if math.Abs(v-s[i]) > test.tol*s[0] {
+				t.Errorf("%s: unexpected singular value %d: got %v want %v", name, i, v, s[i])
+			}
+		}
+
+		u := svd.UTo(nil)
+		v := svd.VTo(nil)
+		if ur, uc := u.Dims(); ur != test.m || uc != test.k {
+			t.Errorf("%s: unexpected U shape %d×%d", name, ur, uc)
+		}
+		if vr, vc := v.Dims(); vr != test.n || vc != test.k {
+			t.Errorf("%s: unexpected V shape %d×%d", name, vr, vc)
+		}
+		var utu, vtv Dense
+		utu.Mul(u.T(), u)
+		vtv.Mul(v.T(), v)
+		id := eye(test.k)
+		if !EqualApprox(&utu, id, 1e-12) || !EqualApprox(&vtv, id, 1e-12) {
+			t.Errorf("%s: singular vectors not orthonormal", name)
+		}
+
+		// The Frobenius norm of the approximation error
+		// is bounded below by the omitted values.
+		var us, usv Dense
+		us.Mul(u, NewDiagDense(test.k, got))
+		usv.Mul(&us, v.T())
+		usv.Sub(a, &usv)
+		var tail float64
+		for _, v := range s[test.k:] {
+			tail += v * v
+		}
+		tail = math.Sqrt(tail)
+		if err := Norm(&usv, 2); err < tail*(1-1e-8) || err > tail+test.tol*s[0]*10 {
+			t.Errorf("%s: unexpected approximation error: got %v want %v", name, err, tail)
+		}
+	}
+}
+
+func TestTruncatedSVDSparse(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a, d := randCOO(60, 40, 0.1, rnd)
+	for _, m := range []Matrix{a.ToCSR(), a.ToCSC(), a.ToCSR().T()} {
+		var want SVD
+		if _, ok := m.(Transpose); ok {
+			want.Factorize(d.T(), SVDNone)
+		} else {
+			want.Factorize(d, SVDNone)
+		}
+		var svd TruncatedSVD
+		if !svd.Factorize(m, 40, SVDNone, &RandomizedSVDOptions{Src: rand.NewSource(1)}) {
+			t.Fatalf("%T: unexpected factorization failure", m)
+		}
+		if !floats.EqualApprox(svd.Values(nil), want.Values(nil), 1e-10) {
+			t.Errorf("%T: unexpected singular values", m)
+		}
+	}
+}
+
+func TestTruncatedSVDPanics(t *testing.T) {
+	t.Parallel()
+	a := NewDense(4, 3, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+	var svd TruncatedSVD
+	if svd.Kind() != -1 {
+		t.Errorf("unexpected kind before factorization: %v", svd.Kind())
+	}
+	svd.Factorize(a, 2, SVDThinV, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero rank", fn: func() { new(TruncatedSVD).Factorize(a, 0, SVDThin, nil) }},
+		{name: "rank too large", fn: func() { new(TruncatedSVD).Factorize(a, 4, SVDThin, nil) }},
+		{name: "full vectors", fn: func() { new(TruncatedSVD).Factorize(a, 2, SVDFullU, nil) }},
+		{name: "negative option", fn: func() {
+			new(TruncatedSVD).Factorize(a, 2, SVDThin, &RandomizedSVDOptions{Oversample: -1})
+		}},
+		{name: "uncomputed U", fn: func() { svd.UTo(nil) }},
+		{name: "Values length", fn: func() { svd.Values(make([]float64, 3)) }},
+		{name: "unfactorized", fn: func() { new(TruncatedSVD).Values(nil) }},
+	} {
+		if panicked, _ := panics(test.fn); !panicked {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
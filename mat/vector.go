@@ -560,6 +560,13 @@ func (v *VecDense) MulVec(a Matrix, b Vector) {
 		defer restore()
 	}
 
+	if v.mulVecBlock(a, b) {
+		return
+	}
+	if v.mulVecSparse(a, b) {
+		return
+	}
+
 	// TODO(kortschak): Improve the non-fast paths.
 	switch aU := aU.(type) {
 	case Vector:
@@ -0,0 +1,111 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+// Indices of the float64 scratch slices held by a Workspace.
+const (
+	wsWork = iota // LAPACK work array.
+	wsNorm        // Work for norm and condition number estimation.
+	wsRe          // Real parts of eigenvalues.
+	wsIm          // Imaginary parts of eigenvalues.
+	numWorkFloats
+)
+
+// Workspace holds scratch memory for the FactorizeWork methods of the
+// decomposition types. The memory is retained between calls, so repeatedly
+// factorizing matrices of the same size using the same receiver and the same
+// Workspace makes no allocations after the first call, with the exception
+// of the nonsymmetric eigenvalue decomposition, Eigen, for which the LAPACK
+// QR algorithm makes a small number of allocations for its internal vectors.
+// The zero value of a Workspace is ready to use.
+//
+// A Workspace may be shared between decompositions of different types and
+// sizes, in which case it grows to hold the largest required memory. A
+// Workspace must not be used by more than one factorization concurrently.
+type Workspace struct {
+	floats [numWorkFloats][]float64
+	ints   []int
+
+	// a holds a copy of the matrix being
+	// factorized for decompositions that
+	// overwrite their input.
+	a Dense
+	// vl and vr hold real eigenvectors.
+	vl, vr Dense
+}
+
+// getFloats returns a []float64 of length l from the slot i of the
+// receiver. If clear is true, the slice visible is zeroed. If the receiver
+// is nil, the slice is taken from the package workspace pool and must be
+// returned with putFloats.
+func (w *Workspace) getFloats(i, l int, clear bool) []float64 {
+	if w == nil {
+		return getFloats(l, clear)
+	}
+	if clear {
+		w.floats[i] = useZeroed(w.floats[i], l)
+	} else {
+		w.floats[i] = use(w.floats[i], l)
+	}
+	return w.floats[i]
+}
+
+// putFloats returns f to the package workspace pool if the receiver
+// is nil. Otherwise putFloats is a no-op.
+func (w *Workspace) putFloats(f []float64) {
+	if w == nil {
+		putFloats(f)
+	}
+}
+
+// getInts returns a []int of length l. If clear is true, the slice
+// visible is zeroed. If the receiver is nil, the slice is taken from
+// the package workspace pool and must be returned with putInts.
+func (w *Workspace) getInts(l int, clear bool) []int {
+	if w == nil {
+		return getInts(l, clear)
+	}
+	if l <= cap(w.ints) {
+		w.ints = w.ints[:l]
+		if clear {
+			for i := range w.ints {
+				w.ints[i] = 0
+			}
+		}
+	} else {
+		w.ints = make([]int, l)
+	}
+	return w.ints
+}
+
+// putInts returns f to the package workspace pool if the receiver
+// is nil. Otherwise putInts is a no-op.
+func (w *Workspace) putInts(f []int) {
+	if w == nil {
+		putInts(f)
+	}
+}
+
+// lwork returns the optimal work length reported by a LAPACK workspace
+// query in work and releases work.
+func (w *Workspace) lwork(work []float64) int {
+	l := int(work[0])
+	w.putFloats(work)
+	return l
+}
+
+// copyOf returns a copy of a. If the receiver is nil, the copy is newly
+// allocated, otherwise it is held by the receiver and is only valid until
+// the next use of the receiver.
+func (w *Workspace) copyOf(a Matrix) *Dense {
+	if w == nil {
+		return DenseCopyOf(a)
+	}
+	r, c := a.Dims()
+	w.a.Reset()
+	w.a.reuseAs(r, c)
+	w.a.Copy(a)
+	return &w.a
+}
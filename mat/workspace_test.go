@@ -0,0 +1,106 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestFactorizeWork(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 3, 10, 25} {
+		a := randNormDense(n, n, rnd)
+		b := randNormDense(n+2, n, rnd)
+		var sym SymDense
+		sym.SymOuterK(1, a)
+		var ws Workspace
+
+		var lu, luWant LU
+		luWant.Factorize(a)
+		lu.FactorizeWork(a, &ws)
+		if !EqualApprox(lu.LTo(nil), luWant.LTo(nil), 1e-14) || !EqualApprox(lu.UTo(nil), luWant.UTo(nil), 1e-14) {
+			t.Errorf("unexpected LU factorization for n=%d", n)
+		}
+		if lu.Cond() != luWant.Cond() {
+			t.Errorf("unexpected LU condition number for n=%d: got %v want %v", n, lu.Cond(), luWant.Cond())
+		}
+		allocs := testing.AllocsPerRun(10, func() { lu.FactorizeWork(a, &ws) })
+		if allocs != 0 {
+			t.Errorf("unexpected allocations by LU.FactorizeWork for n=%d: %v", n, allocs)
+		}
+
+		var qr, qrWant QR
+		qrWant.Factorize(b)
+		qr.FactorizeWork(b, &ws)
+		if !EqualApprox(qr.RTo(nil), qrWant.RTo(nil), 1e-14) || !EqualApprox(qr.QTo(nil), qrWant.QTo(nil), 1e-14) {
+			t.Errorf("unexpected QR factorization for n=%d", n)
+		}
+		allocs = testing.AllocsPerRun(10, func() { qr.FactorizeWork(b, &ws) })
+		if allocs != 0 {
+			t.Errorf("unexpected allocations by QR.FactorizeWork for n=%d: %v", n, allocs)
+		}
+
+		for _, kind := range []SVDKind{SVDNone, SVDThin, SVDFull} {
+			var svd, svdWant SVD
+			if !svdWant.Factorize(b, kind) || !svd.FactorizeWork(b, kind, &ws) {
+				t.Fatalf("unexpected SVD failure for n=%d kind=%v", n, kind)
+			}
+			if !EqualApprox(NewVecDense(n, svd.Values(nil)), NewVecDense(n, svdWant.Values(nil)), 1e-14) {
+				t.Errorf("unexpected singular values for n=%d kind=%v", n, kind)
+			}
+			if kind != SVDNone && !EqualApprox(svd.UTo(nil), svdWant.UTo(nil), 1e-14) {
+				t.Errorf("unexpected left singular vectors for n=%d kind=%v", n, kind)
+			}
+			allocs = testing.AllocsPerRun(10, func() { svd.FactorizeWork(b, kind, &ws) })
+			if allocs != 0 {
+				t.Errorf("unexpected allocations by SVD.FactorizeWork for n=%d kind=%v: %v", n, kind, allocs)
+			}
+		}
+
+		for _, kind := range []EigenKind{EigenNone, EigenLeft, EigenRight, EigenBoth} {
+			var eig, eigWant Eigen
+			if !eigWant.Factorize(a, kind) || !eig.FactorizeWork(a, kind, &ws) {
+				t.Fatalf("unexpected Eigen failure for n=%d kind=%v", n, kind)
+			}
+			got, want := eig.Values(nil), eigWant.Values(nil)
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("unexpected eigenvalue %d for n=%d kind=%v: got %v want %v", i, n, kind, got[i], want[i])
+				}
+			}
+			if kind&EigenRight != 0 && !CEqual(eig.VectorsTo(nil), eigWant.VectorsTo(nil)) {
+				t.Errorf("unexpected right eigenvectors for n=%d kind=%v", n, kind)
+			}
+			if kind&EigenLeft != 0 && !CEqual(eig.LeftVectorsTo(nil), eigWant.LeftVectorsTo(nil)) {
+				t.Errorf("unexpected left eigenvectors for n=%d kind=%v", n, kind)
+			}
+			// The Hessenberg QR algorithm allocates
+			// its reflection vector once per call.
+			allocs = testing.AllocsPerRun(10, func() { eig.FactorizeWork(a, kind, &ws) })
+			if allocs > 1 {
+				t.Errorf("unexpected allocations by Eigen.FactorizeWork for n=%d kind=%v: %v", n, kind, allocs)
+			}
+		}
+
+		for _, vectors := range []bool{false, true} {
+			var es, esWant EigenSym
+			if !esWant.Factorize(&sym, vectors) || !es.FactorizeWork(&sym, vectors, &ws) {
+				t.Fatalf("unexpected EigenSym failure for n=%d", n)
+			}
+			if !Equal(NewVecDense(n, es.Values(nil)), NewVecDense(n, esWant.Values(nil))) {
+				t.Errorf("unexpected symmetric eigenvalues for n=%d", n)
+			}
+			if vectors && !Equal(es.VectorsTo(nil), esWant.VectorsTo(nil)) {
+				t.Errorf("unexpected symmetric eigenvectors for n=%d", n)
+			}
+			allocs = testing.AllocsPerRun(10, func() { es.FactorizeWork(&sym, vectors, &ws) })
+			if allocs != 0 {
+				t.Errorf("unexpected allocations by EigenSym.FactorizeWork for n=%d: %v", n, allocs)
+			}
+		}
+	}
+}
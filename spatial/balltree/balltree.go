@@ -0,0 +1,235 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package balltree
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/vptree"
+)
+
+// Node holds a ball of values in a ball tree. Every value in the subtree
+// rooted at the node is within Radius of Pivot.
+type Node struct {
+	Pivot  vptree.Comparable
+	Radius float64
+
+	// Points holds the values of a leaf
+	// and is nil for internal nodes.
+	Points []vptree.Comparable
+
+	// Left and Right are the children
+	// of an internal node. Both are nil
+	// for leaves.
+	Left  *Node
+	Right *Node
+}
+
+// IsLeaf returns whether n is a leaf.
+func (n *Node) IsLeaf() bool { return n.Left == nil && n.Right == nil }
+
+// Tree implements a ball tree creation and nearest neighbor search.
+type Tree struct {
+	Root  *Node
+	Count int
+}
+
+// New returns a ball tree constructed from the values in p. The leafSize
+// parameter specifies the maximum number of values held in each leaf; values
+// less than one are treated as one. Leaves may hold more values only when
+// all their values are coincident. The order of elements in p will be
+// altered after New returns. Points in p must not be infinitely distant.
+func New(p []vptree.Comparable, leafSize int) (t *Tree, err error) {
+	if leafSize < 1 {
+		leafSize = 1
+	}
+	b := builder{
+		leafSize: leafSize,
+		distA:    make([]float64, len(p)),
+		distB:    make([]float64, len(p)),
+	}
+
+	defer func() {
+		switch r := recover(); r {
+		case nil:
+		case pointAtInfinity:
+			t = nil
+			err = pointAtInfinity
+		default:
+			panic(r)
+		}
+	}()
+
+	t = &Tree{
+		Root:  b.build(p),
+		Count: len(p),
+	}
+	return t, nil
+}
+
+var pointAtInfinity = errors.New("balltree: point at infinity")
+
+// builder performs ball tree construction by recursively splitting sets of
+// values about a pair of distant values.
+type builder struct {
+	leafSize     int
+	distA, distB []float64
+}
+
+func (b *builder) build(s []vptree.Comparable) *Node {
+	if len(s) == 0 {
+		return nil
+	}
+
+	// Find an approximately most distant pair of
+	// values, a and b, and their distances from
+	// each value in s.
+	a := s[b.farthest(s[0], s, b.distA)]
+	bi := b.farthest(a, s, b.distA)
+	pb := s[bi]
+	b.farthest(pb, s, b.distB)
+	distA := b.distA[:len(s)]
+	distB := b.distB[:len(s)]
+
+	// Choose as pivot the value which is closest
+	// to being equidistant between a and b.
+	pivot := s[0]
+	min := math.Inf(1)
+	for i := range s {
+		d := math.Max(distA[i], distB[i])
+		if d < min {
+			pivot, min = s[i], d
+		}
+	}
+	n := &Node{Pivot: pivot}
+	for _, p := range s {
+		n.Radius = math.Max(n.Radius, pivot.Distance(p))
+	}
+
+	if len(s) <= b.leafSize || distA[bi] == 0 {
+		n.Points = s
+		return n
+	}
+
+	// Partition s into the values closer to a and
+	// those closer to b. Neither part is empty since
+	// a and b are distinct.
+	var left int
+	for i := range s {
+		if distA[i] <= distB[i] {
+			s[i], s[left] = s[left], s[i]
+			left++
+		}
+	}
+	n.Left = b.build(s[:left])
+	n.Right = b.build(s[left:])
+	return n
+}
+
+// farthest fills the first len(s) elements of dist with the distances
+// from v to the values in s and returns the index of the most distant
+// value.
+func (b *builder) farthest(v vptree.Comparable, s []vptree.Comparable, dist []float64) int {
+	var idx int
+	for i, p := range s {
+		d := v.Distance(p)
+		if math.IsInf(d, 0) {
+			panic(pointAtInfinity)
+		}
+		dist[i] = d
+		if d > dist[idx] {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// Len returns the number of elements in the tree.
+func (t *Tree) Len() int { return t.Count }
+
+// Nearest returns the nearest value to the query and the distance between them.
+func (t *Tree) Nearest(q vptree.Comparable) (vptree.Comparable, float64) {
+	k := vptree.NewNKeeper(1)
+	t.NearestSet(k, q)
+	if k.Len() == 0 {
+		return nil, math.Inf(1)
+	}
+	return k.Heap[0].Comparable, k.Heap[0].Dist
+}
+
+// NearestSet finds the nearest values to the query accepted by the provided Keeper, k.
+// k must be able to return a ComparableDist specifying the maximum acceptable distance
+// when Max() is called, and retains the results of the search in min sorted order after
+// the call to NearestSet returns.
+// If a sentinel ComparableDist with a nil Comparable is used by the Keeper to mark the
+// maximum distance, NearestSet will remove it before returning.
+func (t *Tree) NearestSet(k vptree.Keeper, q vptree.Comparable) {
+	if t.Root == nil {
+		return
+	}
+	t.Root.searchSet(q, q.Distance(t.Root.Pivot), k)
+
+	// Check whether we have retained a sentinel
+	// and flag removal if we have.
+	removeSentinel := k.Len() != 0 && k.Max().Comparable == nil
+
+	sort.Sort(sort.Reverse(k))
+
+	// This abuses the interface to drop the max.
+	// It is reasonable to do this because we know
+	// that the maximum value will now be at element
+	// zero, which is removed by the Pop method.
+	if removeSentinel {
+		k.Pop()
+	}
+}
+
+// searchSet searches the subtree rooted at n, where d is the distance
+// between q and the pivot of n.
+func (n *Node) searchSet(q vptree.Comparable, d float64, k vptree.Keeper) {
+	if d-n.Radius > k.Max().Dist {
+		return
+	}
+	if n.IsLeaf() {
+		for _, p := range n.Points {
+			k.Keep(vptree.ComparableDist{Comparable: p, Dist: q.Distance(p)})
+		}
+		return
+	}
+
+	near, far := n.Left, n.Right
+	dNear, dFar := q.Distance(near.Pivot), q.Distance(far.Pivot)
+	if dFar-far.Radius < dNear-near.Radius {
+		near, far = far, near
+		dNear, dFar = dFar, dNear
+	}
+	near.searchSet(q, dNear, k)
+	far.searchSet(q, dFar, k)
+}
+
+// Do performs fn on all values stored in the tree. A boolean is returned indicating whether the
+// Do traversal was interrupted by an Operation returning true. The depth passed to fn is the
+// depth of the leaf holding the value. If fn alters stored values' distance relationships, future
+// tree operation behaviors are undefined.
+func (t *Tree) Do(fn vptree.Operation) bool {
+	if t.Root == nil {
+		return false
+	}
+	return t.Root.do(fn, 0)
+}
+
+func (n *Node) do(fn vptree.Operation, depth int) (done bool) {
+	for _, p := range n.Points {
+		if fn(p, depth) {
+			return true
+		}
+	}
+	if n.IsLeaf() {
+		return false
+	}
+	return n.Left.do(fn, depth+1) || n.Right.do(fn, depth+1)
+}
@@ -0,0 +1,47 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package balltree_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/spatial/balltree"
+	"gonum.org/v1/gonum/spatial/vptree"
+)
+
+func ExampleTree_NearestSet() {
+	p := []vptree.Comparable{
+		vptree.Point{2, 3},
+		vptree.Point{5, 4},
+		vptree.Point{9, 6},
+		vptree.Point{4, 7},
+		vptree.Point{8, 1},
+		vptree.Point{7, 2},
+	}
+	t, err := balltree.New(p, 2)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	q := vptree.Point{8, 7}
+
+	// Find the two nearest values.
+	k := vptree.NewNKeeper(2)
+	t.NearestSet(k, q)
+	for _, c := range k.Heap {
+		fmt.Printf("%v is %.2f from %v\n", c.Comparable, c.Dist, q)
+	}
+
+	// Find all values within a radius of 5.
+	d := vptree.NewDistKeeper(5)
+	t.NearestSet(d, q)
+	fmt.Println(len(d.Heap), "values within 5")
+
+	// Output:
+	// [9 6] is 1.41 from [8 7]
+	// [4 7] is 4.00 from [8 7]
+	// 3 values within 5
+}
@@ -0,0 +1,215 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package balltree
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/vptree"
+)
+
+// clustered returns n points in the given number of dimensions
+// distributed in tight clusters about randomly placed centers.
+func clustered(rnd *rand.Rand, n, dims, clusters int) []vptree.Comparable {
+	centers := make([]vptree.Point, clusters)
+	for i := range centers {
+		centers[i] = make(vptree.Point, dims)
+		for j := range centers[i] {
+			centers[i][j] = 100 * rnd.Float64()
+		}
+	}
+	p := make([]vptree.Comparable, n)
+	for i := range p {
+		c := centers[rnd.Intn(clusters)]
+		v := make(vptree.Point, dims)
+		for j := range v {
+			v[j] = c[j] + rnd.NormFloat64()
+		}
+		p[i] = v
+	}
+	return p
+}
+
+func TestNew(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, leafSize := range []int{0, 1, 4, 16} {
+		p := clustered(rnd, 1000, 3, 10)
+		for i := 0; i < 10; i++ {
+			// Coincident points.
+			p = append(p, vptree.Point{1, 2, 3})
+		}
+		tree, err := New(p, leafSize)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tree.Len() != len(p) {
+			t.Errorf("unexpected length: got:%d want:%d", tree.Len(), len(p))
+		}
+		if n := tree.Root.check(t, leafSize); n != len(p) {
+			t.Errorf("unexpected number of values in leaves: got:%d want:%d", n, len(p))
+		}
+	}
+
+	tree, err := New(nil, 1)
+	if err != nil || tree.Root != nil {
+		t.Errorf("unexpected result for empty tree: %v %v", tree.Root, err)
+	}
+	_, err = New([]vptree.Comparable{vptree.Point{0, 0}, vptree.Point{math.Inf(1), 0}}, 1)
+	if err != pointAtInfinity {
+		t.Errorf("unexpected error for point at infinity: got:%v want:%v", err, pointAtInfinity)
+	}
+}
+
+// check checks the ball invariants of the subtree rooted at n and
+// returns the number of values held by the subtree.
+func (n *Node) check(t *testing.T, leafSize int) int {
+	var count int
+	n.do(func(c vptree.Comparable, _ int) bool {
+		if d := n.Pivot.Distance(c); d > n.Radius {
+			t.Errorf("value %v outside ball: distance %v > radius %v", c, d, n.Radius)
+		}
+		count++
+		return false
+	}, 0)
+	if n.IsLeaf() {
+		if len(n.Points) > leafSize && len(n.Points) > 1 {
+			for _, p := range n.Points {
+				if n.Points[0].Distance(p) != 0 {
+					t.Errorf("leaf over capacity: %d values", len(n.Points))
+					break
+				}
+			}
+		}
+		return count
+	}
+	if n.Points != nil {
+		t.Error("internal node holds values")
+	}
+	if n.Left == nil || n.Right == nil {
+		t.Fatal("internal node missing child")
+	}
+	if got := n.Left.check(t, leafSize) + n.Right.check(t, leafSize); got != count {
+		t.Errorf("unexpected number of values in children: got:%d want:%d", got, count)
+	}
+	return count
+}
+
+func sortedDists(q vptree.Comparable, p []vptree.Comparable) []vptree.ComparableDist {
+	d := make([]vptree.ComparableDist, len(p))
+	for i, c := range p {
+		d[i] = vptree.ComparableDist{Comparable: c, Dist: q.Distance(c)}
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i].Dist < d[j].Dist })
+	return d
+}
+
+func TestNearest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, dims := range []int{1, 2, 5, 20} {
+		p := clustered(rnd, 500, dims, 8)
+		tree, err := New(append([]vptree.Comparable(nil), p...), 5)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i < 50; i++ {
+			q := clustered(rnd, 1, dims, 1)[0]
+			want := sortedDists(q, p)
+
+			got, d := tree.Nearest(q)
+			if d != want[0].Dist || q.Distance(got) != d {
+				t.Errorf("unexpected nearest distance for dims=%d: got:%v want:%v", dims, d, want[0].Dist)
+			}
+
+			n := rnd.Intn(20) + 1
+			k := vptree.NewNKeeper(n)
+			tree.NearestSet(k, q)
+			if k.Len() != n {
+				t.Fatalf("unexpected number of nearest values: got:%d want:%d", k.Len(), n)
+			}
+			for j, c := range k.Heap {
+				if c.Dist != want[j].Dist {
+					t.Errorf("unexpected %d-th nearest distance for dims=%d: got:%v want:%v", j, dims, c.Dist, want[j].Dist)
+				}
+			}
+
+			// Avoid radii at which rounding determines inclusion.
+			j := rnd.Intn(len(want) - 1)
+			r := (want[j].Dist + want[j+1].Dist) / 2
+			dk := vptree.NewDistKeeper(r)
+			tree.NearestSet(dk, q)
+			var wantN int
+			for _, c := range want {
+				if c.Dist <= r {
+					wantN++
+				}
+			}
+			if dk.Len() != wantN {
+				t.Errorf("unexpected number of values within %v: got:%d want:%d", r, dk.Len(), wantN)
+			}
+			for j, c := range dk.Heap {
+				if c.Dist != want[j].Dist {
+					t.Errorf("unexpected %d-th distance within %v: got:%v want:%v", j, r, c.Dist, want[j].Dist)
+				}
+			}
+		}
+	}
+
+	if c, d := (&Tree{}).Nearest(vptree.Point{0}); c != nil || !math.IsInf(d, 1) {
+		t.Errorf("unexpected nearest value for empty tree: %v %v", c, d)
+	}
+}
+
+func TestDo(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	p := clustered(rnd, 100, 2, 3)
+	tree, err := New(p, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var n int
+	if tree.Do(func(vptree.Comparable, int) bool { n++; return false }) || n != len(p) {
+		t.Errorf("unexpected number of values visited: got:%d want:%d", n, len(p))
+	}
+	n = 0
+	if !tree.Do(func(vptree.Comparable, int) bool { n++; return n == 10 }) || n != 10 {
+		t.Errorf("Do did not stop: n=%d", n)
+	}
+	if (&Tree{}).Do(func(vptree.Comparable, int) bool { return true }) {
+		t.Error("unexpected done for empty tree")
+	}
+}
+
+func BenchmarkClustered(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	p := clustered(rnd, 100000, 10, 50)
+	queries := clustered(rnd, 100, 10, 50)
+	bt, err := New(append([]vptree.Comparable(nil), p...), 20)
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	vt, err := vptree.New(append([]vptree.Comparable(nil), p...), 0, rand.NewSource(1))
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	for _, bench := range []struct {
+		name string
+		t    interface {
+			NearestSet(vptree.Keeper, vptree.Comparable)
+		}
+	}{
+		{name: "balltree", t: bt},
+		{name: "vptree", t: vt},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bench.t.NearestSet(vptree.NewNKeeper(10), queries[i%len(queries)])
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package balltree implements a ball tree. Ball trees provide an
+// efficient search for nearest neighbors in a metric space, and
+// adapt well to clustered data.
+//
+// The package uses the value, heap and keeper types of the vptree
+// package, so values and keepers may be shared between the two.
+//
+// See https://arxiv.org/abs/1511.00628 for details of ball trees.
+package balltree // import "gonum.org/v1/gonum/spatial/balltree"
@@ -0,0 +1,104 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import "gonum.org/v1/gonum/spatial/r2"
+
+// Curve is a parametric curve in the plane.
+type Curve interface {
+	// Point returns the point on the curve at the
+	// parameter t, which must be within [0, 1].
+	Point(t float64) r2.Vec
+}
+
+// Bezier is a Bézier curve defined by its control points. The degree of
+// the curve is one less than the number of control points. The curve
+// starts at the first control point and ends at the last.
+type Bezier []r2.Vec
+
+// Point returns the point on the curve at the parameter t, evaluated
+// with de Casteljau's algorithm. Point will panic if b has no control
+// points.
+func (b Bezier) Point(t float64) r2.Vec {
+	if len(b) == 0 {
+		panic("curve: no control points")
+	}
+	if len(b) <= 4 {
+		var work [4]r2.Vec
+		return casteljau(work[:len(b)], b, t)
+	}
+	return casteljau(make([]r2.Vec, len(b)), b, t)
+}
+
+// casteljau returns the point of the Bézier curve with control points b
+// at t, using work, which must have the length of b, as scratch space.
+func casteljau(work, b []r2.Vec, t float64) r2.Vec {
+	copy(work, b)
+	for n := len(work) - 1; n > 0; n-- {
+		for i := 0; i < n; i++ {
+			work[i] = lerp(work[i], work[i+1], t)
+		}
+	}
+	return work[0]
+}
+
+// lerp returns the point a fraction t of the way from p to q.
+func lerp(p, q r2.Vec, t float64) r2.Vec {
+	return p.Add(q.Sub(p).Scale(t))
+}
+
+// Derivative returns the derivative of the curve with respect to its
+// parameter, which is a Bézier curve of one lower degree. The derivative
+// of a curve with a single control point has the single control point
+// at the origin.
+func (b Bezier) Derivative() Bezier {
+	if len(b) < 2 {
+		return Bezier{{}}
+	}
+	n := float64(len(b) - 1)
+	d := make(Bezier, len(b)-1)
+	for i := range d {
+		d[i] = b[i+1].Sub(b[i]).Scale(n)
+	}
+	return d
+}
+
+// Split returns the two Bézier curves that trace b over the parameter
+// intervals [0, t] and [t, 1]. Split will panic if b has no control
+// points.
+func (b Bezier) Split(t float64) (left, right Bezier) {
+	if len(b) == 0 {
+		panic("curve: no control points")
+	}
+	n := len(b)
+	left = make(Bezier, n)
+	right = make(Bezier, n)
+	work := append(Bezier(nil), b...)
+	for k := 0; k < n; k++ {
+		left[k] = work[0]
+		right[n-1-k] = work[n-1-k]
+		for i := 0; i < n-1-k; i++ {
+			work[i] = lerp(work[i], work[i+1], t)
+		}
+	}
+	return left, right
+}
+
+// flat returns whether all the control points of b are within tol of
+// the chord from its first to its last control point. Since a Bézier
+// curve is within the convex hull of its control points, the curve is
+// then within tol of the chord.
+func (b Bezier) flat(tol float64) bool {
+	if len(b) <= 2 {
+		return true
+	}
+	a, c := b[0], b[len(b)-1]
+	for _, p := range b[1 : len(b)-1] {
+		if r2.SegmentDistance(p, a, c) > tol {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,148 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func vecEqualApprox(a, b r2.Vec, tol float64) bool {
+	return math.Abs(a.X-b.X) <= tol && math.Abs(a.Y-b.Y) <= tol
+}
+
+// bernstein returns the point of the Bézier curve with control points b
+// at t, evaluated from the Bernstein polynomial form.
+func bernstein(b Bezier, t float64) r2.Vec {
+	n := len(b) - 1
+	var p r2.Vec
+	binom := 1.0
+	for i, c := range b {
+		w := binom * math.Pow(t, float64(i)) * math.Pow(1-t, float64(n-i))
+		p = p.Add(c.Scale(w))
+		binom = binom * float64(n-i) / float64(i+1)
+	}
+	return p
+}
+
+func randomBezier(rnd *rand.Rand, n int) Bezier {
+	b := make(Bezier, n)
+	for i := range b {
+		b[i] = r2.Vec{X: rnd.NormFloat64(), Y: rnd.NormFloat64()}
+	}
+	return b
+}
+
+func TestBezierPoint(t *testing.T) {
+	const tol = 1e-14
+	quad := Bezier{{X: 0, Y: 0}, {X: 1, Y: 2}, {X: 2, Y: 0}}
+	for _, test := range []struct {
+		t    float64
+		want r2.Vec
+	}{
+		{t: 0, want: r2.Vec{X: 0, Y: 0}},
+		{t: 0.5, want: r2.Vec{X: 1, Y: 1}},
+		{t: 1, want: r2.Vec{X: 2, Y: 0}},
+	} {
+		got := quad.Point(test.t)
+		if !vecEqualApprox(got, test.want, tol) {
+			t.Errorf("unexpected point at %v: got:%v want:%v", test.t, got, test.want)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for n := 1; n <= 8; n++ {
+		b := randomBezier(rnd, n)
+		for i := 0; i <= 10; i++ {
+			u := float64(i) / 10
+			got := b.Point(u)
+			want := bernstein(b, u)
+			if !vecEqualApprox(got, want, 1e-12) {
+				t.Errorf("unexpected point of degree %d curve at %v: got:%v want:%v", n-1, u, got, want)
+			}
+		}
+	}
+}
+
+func TestBezierDerivative(t *testing.T) {
+	const h = 1e-6
+	rnd := rand.New(rand.NewSource(1))
+	for n := 1; n <= 6; n++ {
+		b := randomBezier(rnd, n)
+		d := b.Derivative()
+		if want := max(1, n-1); len(d) != want {
+			t.Errorf("unexpected number of control points of derivative: got:%d want:%d", len(d), want)
+		}
+		for i := 1; i < 10; i++ {
+			u := float64(i) / 10
+			want := b.Point(u + h).Sub(b.Point(u - h)).Scale(1 / (2 * h))
+			got := d.Point(u)
+			if !vecEqualApprox(got, want, 1e-6) {
+				t.Errorf("unexpected derivative of degree %d curve at %v: got:%v want:%v", n-1, u, got, want)
+			}
+		}
+	}
+}
+
+func TestBezierSplit(t *testing.T) {
+	const tol = 1e-12
+	rnd := rand.New(rand.NewSource(1))
+	for n := 1; n <= 6; n++ {
+		b := randomBezier(rnd, n)
+		orig := append(Bezier(nil), b...)
+		for _, at := range []float64{0, 0.3, 0.5, 1} {
+			left, right := b.Split(at)
+			for i := 0; i <= 10; i++ {
+				u := float64(i) / 10
+				if got, want := left.Point(u), b.Point(at*u); !vecEqualApprox(got, want, tol) {
+					t.Errorf("unexpected left point at %v of split at %v: got:%v want:%v", u, at, got, want)
+				}
+				if got, want := right.Point(u), b.Point(at+(1-at)*u); !vecEqualApprox(got, want, tol) {
+					t.Errorf("unexpected right point at %v of split at %v: got:%v want:%v", u, at, got, want)
+				}
+			}
+		}
+		for i := range b {
+			if b[i] != orig[i] {
+				t.Errorf("control points modified by split")
+				break
+			}
+		}
+	}
+}
+
+func TestBezierPanics(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "point", fn: func() { Bezier(nil).Point(0) }},
+		{name: "split", fn: func() { Bezier(nil).Split(0) }},
+		{name: "flatten", fn: func() { Flatten(nil, Bezier(nil), 1) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s of empty curve", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,124 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// BSpline is a B-spline curve.
+type BSpline struct {
+	degree  int
+	control []r2.Vec
+	knots   []float64
+}
+
+// NewBSpline returns a B-spline curve of the given degree with the
+// provided control points and knot vector. The knots must be
+// non-decreasing and there must be degree+1 more knots than control
+// points. The curve is defined over the knot interval from the knot at
+// index degree to the knot at index len(control), which is mapped to the
+// parameter interval [0, 1].
+//
+// If knots is nil, a clamped uniform knot vector is used, so that the
+// curve starts at the first control point and ends at the last. The
+// control points and knots are retained by the returned curve and must
+// not be modified.
+//
+// NewBSpline will panic if degree is less than one, there are not more
+// than degree control points, the number of knots is wrong, the knots
+// are decreasing or the knot interval of the curve is empty.
+func NewBSpline(degree int, control []r2.Vec, knots []float64) *BSpline {
+	if degree < 1 {
+		panic("curve: degree less than one")
+	}
+	if len(control) <= degree {
+		panic("curve: too few control points")
+	}
+	if knots == nil {
+		knots = clampedUniform(degree, len(control))
+	}
+	if len(knots) != len(control)+degree+1 {
+		panic("curve: knot length mismatch")
+	}
+	if !sort.Float64sAreSorted(knots) {
+		panic("curve: knots not sorted")
+	}
+	if knots[degree] == knots[len(control)] {
+		panic("curve: empty knot interval")
+	}
+	return &BSpline{degree: degree, control: control, knots: knots}
+}
+
+// clampedUniform returns the clamped uniform knot vector over [0, 1] for
+// a B-spline of the given degree with n control points.
+func clampedUniform(degree, n int) []float64 {
+	knots := make([]float64, n+degree+1)
+	spans := n - degree
+	for i := range knots {
+		switch {
+		case i <= degree:
+			knots[i] = 0
+		case i >= n:
+			knots[i] = 1
+		default:
+			knots[i] = float64(i-degree) / float64(spans)
+		}
+	}
+	return knots
+}
+
+// Degree returns the degree of the curve.
+func (b *BSpline) Degree() int { return b.degree }
+
+// Control returns the control points of the curve.
+func (b *BSpline) Control() []r2.Vec { return b.control }
+
+// Knots returns the knot vector of the curve.
+func (b *BSpline) Knots() []float64 { return b.knots }
+
+// Point returns the point on the curve at the parameter t, evaluated with
+// de Boor's algorithm.
+func (b *BSpline) Point(t float64) r2.Vec {
+	p := b.degree
+	lo, hi := b.knots[p], b.knots[len(b.control)]
+	x := lo + t*(hi-lo)
+
+	// Find the knot span k such that knots[k] <= x < knots[k+1],
+	// restricted to the spans of the curve's knot interval.
+	k := sort.Search(len(b.knots), func(i int) bool { return b.knots[i] > x }) - 1
+	if k < p {
+		k = p
+	}
+	if k >= len(b.control) {
+		k = len(b.control) - 1
+	}
+	for k > p && b.knots[k] == b.knots[k+1] {
+		k--
+	}
+
+	var buf [4]r2.Vec
+	var d []r2.Vec
+	if p < len(buf) {
+		d = buf[:p+1]
+	} else {
+		d = make([]r2.Vec, p+1)
+	}
+	copy(d, b.control[k-p:k+1])
+	for r := 1; r <= p; r++ {
+		for j := p; j >= r; j-- {
+			i := j + k - p
+			den := b.knots[i+p-r+1] - b.knots[i]
+			var alpha float64
+			if den != 0 {
+				alpha = (x - b.knots[i]) / den
+			}
+			d[j] = lerp(d[j-1], d[j], alpha)
+		}
+	}
+	return d[p]
+}
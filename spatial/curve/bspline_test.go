@@ -0,0 +1,99 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func TestBSplinePoint(t *testing.T) {
+	const tol = 1e-14
+
+	// A linear B-spline is the polyline
+	// through its control points.
+	lin := NewBSpline(1, []r2.Vec{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}, nil)
+	for _, test := range []struct {
+		t    float64
+		want r2.Vec
+	}{
+		{t: 0, want: r2.Vec{X: 0, Y: 0}},
+		{t: 0.25, want: r2.Vec{X: 0.5, Y: 0}},
+		{t: 0.5, want: r2.Vec{X: 1, Y: 0}},
+		{t: 0.75, want: r2.Vec{X: 1, Y: 0.5}},
+		{t: 1, want: r2.Vec{X: 1, Y: 1}},
+	} {
+		got := lin.Point(test.t)
+		if !vecEqualApprox(got, test.want, tol) {
+			t.Errorf("unexpected point of linear spline at %v: got:%v want:%v", test.t, got, test.want)
+		}
+	}
+
+	// A clamped B-spline with one more control point
+	// than its degree is a Bézier curve.
+	rnd := rand.New(rand.NewSource(1))
+	for degree := 1; degree <= 6; degree++ {
+		b := randomBezier(rnd, degree+1)
+		s := NewBSpline(degree, b, nil)
+		for i := 0; i <= 10; i++ {
+			u := float64(i) / 10
+			got := s.Point(u)
+			want := b.Point(u)
+			if !vecEqualApprox(got, want, 1e-12) {
+				t.Errorf("unexpected point of degree %d spline at %v: got:%v want:%v", degree, u, got, want)
+			}
+		}
+	}
+
+	// The ends of a uniform cubic B-spline are
+	// weighted averages of its control points.
+	p := []r2.Vec{{X: 0, Y: 0}, {X: 6, Y: 0}, {X: 6, Y: 6}, {X: 0, Y: 6}, {X: 0, Y: 12}}
+	u := NewBSpline(3, p, []float64{0, 1, 2, 3, 4, 5, 6, 7, 8})
+	for _, test := range []struct {
+		t    float64
+		want r2.Vec
+	}{
+		{t: 0, want: p[0].Add(p[1].Scale(4)).Add(p[2]).Scale(1.0 / 6)},
+		{t: 0.5, want: p[1].Add(p[2].Scale(4)).Add(p[3]).Scale(1.0 / 6)},
+		{t: 1, want: p[2].Add(p[3].Scale(4)).Add(p[4]).Scale(1.0 / 6)},
+	} {
+		got := u.Point(test.t)
+		if !vecEqualApprox(got, test.want, tol) {
+			t.Errorf("unexpected point of uniform spline at %v: got:%v want:%v", test.t, got, test.want)
+		}
+	}
+
+	// Repeated interior knots reduce the continuity
+	// of the curve, and a knot of multiplicity equal
+	// to the degree makes it pass through a control
+	// point.
+	q := NewBSpline(2, p, []float64{0, 0, 0, 1, 1, 2, 2, 2})
+	if got := q.Point(0.5); !vecEqualApprox(got, p[2], tol) {
+		t.Errorf("unexpected point at double knot: got:%v want:%v", got, p[2])
+	}
+}
+
+func TestNewBSplinePanics(t *testing.T) {
+	p := []r2.Vec{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}}
+	for _, test := range []struct {
+		name    string
+		degree  int
+		control []r2.Vec
+		knots   []float64
+	}{
+		{name: "zero degree", degree: 0, control: p},
+		{name: "too few control points", degree: 3, control: p},
+		{name: "knot length", degree: 2, control: p, knots: []float64{0, 0, 1, 1, 1}},
+		{name: "unsorted knots", degree: 2, control: p, knots: []float64{0, 0, 1, 0.5, 1, 1}},
+		{name: "empty interval", degree: 1, control: p, knots: []float64{0, 1, 1, 1, 2}},
+	} {
+		if !panics(func() { NewBSpline(test.degree, test.control, test.knots) }) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package curve provides parametric curves in the plane, including Bézier
+// curves and B-splines, with arc-length parameterization and adaptive
+// flattening of curves to polylines, and space-filling curves over integer
+// grids.
+package curve // import "gonum.org/v1/gonum/spatial/curve"
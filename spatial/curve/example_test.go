@@ -0,0 +1,40 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/spatial/curve"
+)
+
+func ExampleFlatten() {
+	// A cubic Bézier curve for a smooth graph edge.
+	edge := curve.Bezier{{X: 0, Y: 0}, {X: 1, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 0}}
+
+	// Flatten the curve to a polyline for rendering.
+	for _, tol := range []float64{0.1, 0.01} {
+		poly := curve.Flatten(nil, edge, tol)
+		fmt.Printf("tolerance %v: %d vertices\n", tol, len(poly))
+	}
+
+	// Place markers at equal distances along the edge.
+	a := curve.NewArcLength(edge, 1e-6)
+	fmt.Printf("length: %.4f\n", a.Length())
+	for i := 0; i <= 4; i++ {
+		p := a.Point(float64(i) / 4)
+		fmt.Printf("marker %d: {%.3f, %.3f}\n", i, p.X, p.Y)
+	}
+
+	// Output:
+	// tolerance 0.1: 9 vertices
+	// tolerance 0.01: 17 vertices
+	// length: 5.2684
+	// marker 0: {0.000, 0.000}
+	// marker 1: {0.795, 1.038}
+	// marker 2: {2.000, 1.500}
+	// marker 3: {3.205, 1.038}
+	// marker 4: {4.000, 0.000}
+}
@@ -0,0 +1,197 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// maxDepth is the maximum depth of subdivision when flattening a curve,
+// limiting the polyline for a curve to 2^maxDepth segments.
+const maxDepth = 20
+
+// Flatten appends to dst the vertices of a polyline approximating the
+// curve c and returns the result. Every point of the polyline is within
+// approximately tol of the curve, and it starts at c.Point(0) and ends at
+// c.Point(1).
+//
+// Bézier curves are flattened by subdividing them until their control
+// points are within tol of their chords, which bounds the distance between
+// the curve and the polyline. Other curves are subdivided until points
+// sampled within each parameter interval are within tol of the chord,
+// so features of the curve that are small compared to the spacing of the
+// samples may be missed.
+//
+// Flatten will panic if tol is not positive.
+func Flatten(dst []r2.Vec, c Curve, tol float64) []r2.Vec {
+	flatten(c, tol, false, func(_ float64, p r2.Vec) {
+		dst = append(dst, p)
+	})
+	return dst
+}
+
+// flatten calls fn with the parameter and location of each vertex of a
+// polyline approximating c in order of increasing parameter. If uniform
+// is true, intervals are also subdivided until the point of the curve at
+// the middle of each parameter interval is within tol of the middle of
+// its chord, so that the parameter is approximately proportional to the
+// distance along each segment of the polyline.
+func flatten(c Curve, tol float64, uniform bool, fn func(t float64, p r2.Vec)) {
+	if !(tol > 0) {
+		panic("curve: tolerance not positive")
+	}
+	switch c := c.(type) {
+	case Bezier:
+		if len(c) == 0 {
+			panic("curve: no control points")
+		}
+		fn(0, c[0])
+		flattenBezier(c, 0, 1, tol, uniform, 0, fn)
+	default:
+		// Begin with a small number of intervals so
+		// that curves with endpoints close together,
+		// such as closed curves, are not considered
+		// flat from their endpoints alone.
+		const initial = 4
+		start := c.Point(0)
+		fn(0, start)
+		for i := 0; i < initial; i++ {
+			t0 := float64(i) / initial
+			t1 := float64(i+1) / initial
+			end := c.Point(t1)
+			flattenCurve(c, t0, t1, start, end, tol, uniform, 2, fn)
+			start = end
+		}
+	}
+}
+
+// flattenBezier calls fn with the parameter and location of each but the
+// first vertex of a polyline approximating b, which traces the original
+// curve over the parameter interval [t0, t1].
+func flattenBezier(b Bezier, t0, t1, tol float64, uniform bool, depth int, fn func(t float64, p r2.Vec)) {
+	end := b[len(b)-1]
+	flat := b.flat(tol)
+	if depth == maxDepth || (flat && !uniform) {
+		fn(t1, end)
+		return
+	}
+	left, right := b.Split(0.5)
+	if flat && dist(right[0], lerp(b[0], end, 0.5)) <= tol {
+		fn(t1, end)
+		return
+	}
+	tm := (t0 + t1) / 2
+	flattenBezier(left, t0, tm, tol, uniform, depth+1, fn)
+	flattenBezier(right, tm, t1, tol, uniform, depth+1, fn)
+}
+
+// flattenCurve calls fn with the parameter and location of each but the
+// first vertex of a polyline approximating c over the parameter interval
+// [t0, t1], where p0 and p1 are the points of c at t0 and t1.
+func flattenCurve(c Curve, t0, t1 float64, p0, p1 r2.Vec, tol float64, uniform bool, depth int, fn func(t float64, p r2.Vec)) {
+	tm := (t0 + t1) / 2
+	pm := c.Point(tm)
+	if depth >= maxDepth || (r2.SegmentDistance(pm, p0, p1) <= tol &&
+		r2.SegmentDistance(c.Point((t0+tm)/2), p0, p1) <= tol &&
+		r2.SegmentDistance(c.Point((tm+t1)/2), p0, p1) <= tol &&
+		(!uniform || dist(pm, lerp(p0, p1, 0.5)) <= tol)) {
+		fn(t1, p1)
+		return
+	}
+	flattenCurve(c, t0, tm, p0, pm, tol, uniform, depth+1, fn)
+	flattenCurve(c, tm, t1, pm, p1, tol, uniform, depth+1, fn)
+}
+
+// Length returns the approximate arc length of the curve c, computed as
+// the length of a polyline within tol of the curve.
+//
+// Length will panic if tol is not positive.
+func Length(c Curve, tol float64) float64 {
+	var (
+		length float64
+		prev   r2.Vec
+		first  = true
+	)
+	flatten(c, tol, false, func(_ float64, p r2.Vec) {
+		if !first {
+			length += dist(prev, p)
+		}
+		prev, first = p, false
+	})
+	return length
+}
+
+// ArcLength is an arc-length parameterization of a curve. The point at
+// parameter u of an ArcLength is the point of the underlying curve that
+// is a fraction u of the length along it, so equal steps in u move equal
+// distances along the curve.
+type ArcLength struct {
+	curve Curve
+
+	// t and s hold the parameters and the
+	// cumulative arc lengths of the vertices
+	// of a polyline that approximates curve.
+	t, s []float64
+}
+
+// NewArcLength returns an arc-length parameterization of the curve c. The
+// arc length is approximated by the length of a polyline within tol of the
+// curve, and the underlying curve parameter for an arc length is
+// interpolated between the vertices of the polyline, which are placed so
+// that the parameter is approximately proportional to the distance along
+// each segment.
+//
+// NewArcLength will panic if tol is not positive.
+func NewArcLength(c Curve, tol float64) *ArcLength {
+	a := &ArcLength{curve: c}
+	var prev r2.Vec
+	flatten(c, tol, true, func(t float64, p r2.Vec) {
+		var s float64
+		if len(a.s) != 0 {
+			s = a.s[len(a.s)-1] + dist(prev, p)
+		}
+		a.t = append(a.t, t)
+		a.s = append(a.s, s)
+		prev = p
+	})
+	return a
+}
+
+// Length returns the arc length of the curve.
+func (a *ArcLength) Length() float64 { return a.s[len(a.s)-1] }
+
+// Param returns the parameter of the underlying curve at the arc length s
+// from its start. Values of s outside [0, a.Length()] are clamped to the
+// interval.
+func (a *ArcLength) Param(s float64) float64 {
+	n := len(a.s)
+	switch {
+	case s <= 0:
+		return a.t[0]
+	case s >= a.s[n-1]:
+		return a.t[n-1]
+	}
+	i := sort.SearchFloat64s(a.s, s)
+	if a.s[i] == s {
+		return a.t[i]
+	}
+	f := (s - a.s[i-1]) / (a.s[i] - a.s[i-1])
+	return a.t[i-1] + f*(a.t[i]-a.t[i-1])
+}
+
+// Point returns the point of the underlying curve that is the fraction u
+// of the arc length along it.
+func (a *ArcLength) Point(u float64) r2.Vec {
+	return a.curve.Point(a.Param(u * a.Length()))
+}
+
+// dist returns the Euclidean distance between p and q.
+func dist(p, q r2.Vec) float64 {
+	d := p.Sub(q)
+	return math.Hypot(d.X, d.Y)
+}
@@ -0,0 +1,158 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// circle is a unit circle about the origin.
+type circle struct{}
+
+func (circle) Point(t float64) r2.Vec {
+	sin, cos := math.Sincos(2 * math.Pi * t)
+	return r2.Vec{X: cos, Y: sin}
+}
+
+func TestFlatten(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	curves := []Curve{
+		circle{},
+		Bezier{{X: 0, Y: 0}, {X: 1, Y: 2}, {X: 2, Y: 0}},
+		Bezier{{X: 0, Y: 0}, {X: 3, Y: 3}, {X: -2, Y: 3}, {X: 1, Y: 0}},
+		Bezier{{X: 0, Y: 0}, {X: 1, Y: 0}},
+		Bezier{{X: 1, Y: 1}},
+		NewBSpline(3, []r2.Vec{{X: 0, Y: 0}, {X: 1, Y: 2}, {X: 3, Y: 3}, {X: 4, Y: 0}, {X: 6, Y: 1}, {X: 5, Y: 4}}, nil),
+	}
+	for i := 0; i < 5; i++ {
+		curves = append(curves, randomBezier(rnd, 3+i))
+	}
+	for _, c := range curves {
+		for _, tol := range []float64{1e-1, 1e-3, 1e-6} {
+			var params []float64
+			var poly []r2.Vec
+			flatten(c, tol, false, func(t float64, p r2.Vec) {
+				params = append(params, t)
+				poly = append(poly, p)
+			})
+			got := Flatten(nil, c, tol)
+			if len(got) != len(poly) {
+				t.Fatalf("unexpected number of vertices for %v: got:%d want:%d", c, len(got), len(poly))
+			}
+			if len(got) < 2 {
+				t.Fatalf("too few vertices for %v: %d", c, len(got))
+			}
+			if got[0] != c.Point(0) || !vecEqualApprox(got[len(got)-1], c.Point(1), 1e-14) {
+				t.Errorf("unexpected end points for %v: got:%v and %v want:%v and %v",
+					c, got[0], got[len(got)-1], c.Point(0), c.Point(1))
+			}
+			for j := 1; j < len(got); j++ {
+				if params[j] <= params[j-1] {
+					t.Fatalf("parameters not increasing for %v", c)
+				}
+				if got[j] != poly[j] || !vecEqualApprox(got[j], c.Point(params[j]), 1e-12) {
+					t.Errorf("unexpected vertex for %v at %v: got:%v want:%v", c, params[j], got[j], c.Point(params[j]))
+				}
+				for k := 1; k < 8; k++ {
+					u := params[j-1] + float64(k)/8*(params[j]-params[j-1])
+					if d := r2.SegmentDistance(c.Point(u), got[j-1], got[j]); d > tol*(1+1e-9) {
+						t.Errorf("curve %v too far from polyline at %v: distance:%v tol:%v", c, u, d, tol)
+					}
+				}
+			}
+		}
+	}
+
+	if !panics(func() { Flatten(nil, circle{}, 0) }) {
+		t.Error("expected panic for zero tolerance")
+	}
+}
+
+func TestLength(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		c    Curve
+		want float64
+	}{
+		{name: "circle", c: circle{}, want: 2 * math.Pi},
+		{name: "line", c: Bezier{{X: 0, Y: 0}, {X: 3, Y: 4}}, want: 5},
+		{name: "point", c: Bezier{{X: 1, Y: 2}}, want: 0},
+		// The length of the parabola y = x^2 for x in [0, 1]
+		// given by the quadratic Bézier curve.
+		{name: "parabola", c: Bezier{{X: 0, Y: 0}, {X: 0.5, Y: 0}, {X: 1, Y: 1}}, want: math.Sqrt(5)/2 + math.Asinh(2)/4},
+	} {
+		for _, tol := range []float64{1e-3, 1e-6} {
+			got := Length(test.c, tol)
+			if math.Abs(got-test.want) > 10*tol {
+				t.Errorf("unexpected length of %s with tolerance %v: got:%v want:%v", test.name, tol, got, test.want)
+			}
+			a := NewArcLength(test.c, tol)
+			if math.Abs(a.Length()-test.want) > 10*tol {
+				t.Errorf("unexpected arc length of %s with tolerance %v: got:%v want:%v", test.name, tol, a.Length(), test.want)
+			}
+		}
+	}
+}
+
+func TestArcLength(t *testing.T) {
+	const tol = 1e-6
+
+	// A straight Bézier curve with coincident control
+	// points that moves along its chord non-uniformly.
+	line := Bezier{{X: 0, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 0}, {X: 3, Y: 0}}
+	a := NewArcLength(line, tol)
+	for i := 0; i <= 20; i++ {
+		u := float64(i) / 20
+		want := r2.Vec{X: 3 * u}
+		if got := a.Point(u); !vecEqualApprox(got, want, 10*tol) {
+			t.Errorf("unexpected point of line at %v: got:%v want:%v", u, got, want)
+		}
+		if got, want := a.Param(3*u), math.Cbrt(u); math.Abs(got-want) > 1e-3 {
+			t.Errorf("unexpected parameter of line at %v: got:%v want:%v", 3*u, got, want)
+		}
+	}
+	if got := a.Param(-1); got != 0 {
+		t.Errorf("unexpected parameter before start: got:%v want:0", got)
+	}
+	if got := a.Param(4); got != 1 {
+		t.Errorf("unexpected parameter after end: got:%v want:1", got)
+	}
+
+	// Equal steps of an arc-length parameterized
+	// curve are equal distances along the curve.
+	curves := []Curve{
+		Bezier{{X: 0, Y: 0}, {X: 3, Y: 3}, {X: -2, Y: 3}, {X: 1, Y: 0}},
+		NewBSpline(3, []r2.Vec{{X: 0, Y: 0}, {X: 1, Y: 2}, {X: 3, Y: 3}, {X: 4, Y: 0}, {X: 6, Y: 1}, {X: 5, Y: 4}}, nil),
+		circle{},
+	}
+	for _, c := range curves {
+		a := NewArcLength(c, tol)
+		const n = 16
+		step := a.Length() / n
+		for i := 0; i < n; i++ {
+			u0 := float64(i) / n
+			u1 := float64(i+1) / n
+			got := Length(section{a, u0, u1}, tol)
+			if math.Abs(got-step) > 1e-4 {
+				t.Errorf("unexpected length of section %d of %v: got:%v want:%v", i, c, got, step)
+			}
+		}
+	}
+}
+
+// section is the part of a curve between two parameters.
+type section struct {
+	c      Curve
+	t0, t1 float64
+}
+
+func (s section) Point(t float64) r2.Vec {
+	return s.c.Point(s.t0 + t*(s.t1-s.t0))
+}
@@ -0,0 +1,211 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import "math/bits"
+
+// SpaceFilling is a space-filling curve that visits each cell of an
+// integer grid once, mapping between the position of a cell along the
+// curve and the coordinates of the cell.
+type SpaceFilling interface {
+	// Dims returns the size of the grid in each dimension.
+	Dims() []int
+
+	// Len returns the number of cells of the grid.
+	Len() int
+
+	// Pos returns the position along the curve of the
+	// cell with the coordinates held in v.
+	Pos(v []int) int
+
+	// Coord stores the coordinates of the cell at the
+	// position pos along the curve into dst and returns
+	// the result. If dst is nil or too short, a new slice
+	// is allocated.
+	Coord(dst []int, pos int) []int
+}
+
+// maxOrder2 and maxOrder3 are the largest orders of the Morton curves for
+// which every position along the curve and the length of the curve can be
+// held in an int.
+const (
+	maxOrder2 = (bits.UintSize - 2) / 2
+	maxOrder3 = (bits.UintSize - 2) / 3
+)
+
+var (
+	_ SpaceFilling = Morton2D{}
+	_ SpaceFilling = Morton3D{}
+)
+
+// Morton2D is a 2D Morton curve, or Z-order curve, over a square grid with
+// sides of 2^Order cells. The position of a cell along the curve is given
+// by interleaving the bits of its coordinates, so cells that are close
+// together along the curve tend to be close together in the grid. Order
+// must be within [0, 31] on 64-bit platforms and [0, 15] on 32-bit
+// platforms.
+type Morton2D struct {
+	Order int
+}
+
+// Dims returns the size of the grid in each dimension.
+func (m Morton2D) Dims() []int {
+	n := side(m.Order, maxOrder2)
+	return []int{n, n}
+}
+
+// Len returns the number of cells of the grid.
+func (m Morton2D) Len() int {
+	n := side(m.Order, maxOrder2)
+	return n * n
+}
+
+// Pos returns the position along the curve of the cell with the
+// coordinates {v[0], v[1]}. Pos will panic if v does not have length 2 or
+// the coordinates are outside the grid.
+func (m Morton2D) Pos(v []int) int {
+	n := side(m.Order, maxOrder2)
+	if len(v) != 2 {
+		panic("curve: dimension mismatch")
+	}
+	for _, c := range v {
+		if c < 0 || c >= n {
+			panic("curve: coordinate out of range")
+		}
+	}
+	return int(spread2(uint64(v[0])) | spread2(uint64(v[1]))<<1)
+}
+
+// Coord stores the coordinates of the cell at the position pos along the
+// curve into dst and returns the result. If dst is nil or shorter than 2,
+// a new slice is allocated. Coord will panic if pos is outside [0, m.Len()).
+func (m Morton2D) Coord(dst []int, pos int) []int {
+	if pos < 0 || pos >= m.Len() {
+		panic("curve: position out of range")
+	}
+	dst = useInts(dst, 2)
+	dst[0] = int(compact2(uint64(pos)))
+	dst[1] = int(compact2(uint64(pos) >> 1))
+	return dst
+}
+
+// Morton3D is a 3D Morton curve, or Z-order curve, over a cubic grid with
+// sides of 2^Order cells. The position of a cell along the curve is given
+// by interleaving the bits of its coordinates, so cells that are close
+// together along the curve tend to be close together in the grid. Order
+// must be within [0, 20] on 64-bit platforms and [0, 10] on 32-bit
+// platforms.
+type Morton3D struct {
+	Order int
+}
+
+// Dims returns the size of the grid in each dimension.
+func (m Morton3D) Dims() []int {
+	n := side(m.Order, maxOrder3)
+	return []int{n, n, n}
+}
+
+// Len returns the number of cells of the grid.
+func (m Morton3D) Len() int {
+	n := side(m.Order, maxOrder3)
+	return n * n * n
+}
+
+// Pos returns the position along the curve of the cell with the
+// coordinates {v[0], v[1], v[2]}. Pos will panic if v does not have length
+// 3 or the coordinates are outside the grid.
+func (m Morton3D) Pos(v []int) int {
+	n := side(m.Order, maxOrder3)
+	if len(v) != 3 {
+		panic("curve: dimension mismatch")
+	}
+	for _, c := range v {
+		if c < 0 || c >= n {
+			panic("curve: coordinate out of range")
+		}
+	}
+	return int(spread3(uint64(v[0])) | spread3(uint64(v[1]))<<1 | spread3(uint64(v[2]))<<2)
+}
+
+// Coord stores the coordinates of the cell at the position pos along the
+// curve into dst and returns the result. If dst is nil or shorter than 3,
+// a new slice is allocated. Coord will panic if pos is outside [0, m.Len()).
+func (m Morton3D) Coord(dst []int, pos int) []int {
+	if pos < 0 || pos >= m.Len() {
+		panic("curve: position out of range")
+	}
+	dst = useInts(dst, 3)
+	dst[0] = int(compact3(uint64(pos)))
+	dst[1] = int(compact3(uint64(pos) >> 1))
+	dst[2] = int(compact3(uint64(pos) >> 2))
+	return dst
+}
+
+// side returns the number of cells along a side of a grid of the given
+// order, panicking if the order is outside [0, max].
+func side(order, max int) int {
+	if order < 0 || order > max {
+		panic("curve: order out of range")
+	}
+	return 1 << uint(order)
+}
+
+// useInts returns dst resliced to length n if it has sufficient
+// capacity, and a new slice of length n otherwise.
+func useInts(dst []int, n int) []int {
+	if cap(dst) < n {
+		return make([]int, n)
+	}
+	return dst[:n]
+}
+
+// spread2 returns the low 32 bits of x spread to the even bits of the
+// result.
+func spread2(x uint64) uint64 {
+	x &= 0x00000000ffffffff
+	x = (x | x<<16) & 0x0000ffff0000ffff
+	x = (x | x<<8) & 0x00ff00ff00ff00ff
+	x = (x | x<<4) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
+
+// compact2 returns the even bits of x gathered into the low 32 bits of
+// the result. It is the inverse of spread2.
+func compact2(x uint64) uint64 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x>>4) & 0x00ff00ff00ff00ff
+	x = (x | x>>8) & 0x0000ffff0000ffff
+	x = (x | x>>16) & 0x00000000ffffffff
+	return x
+}
+
+// spread3 returns the low 21 bits of x spread to every third bit of the
+// result, starting from the lowest.
+func spread3(x uint64) uint64 {
+	x &= 0x00000000001fffff
+	x = (x | x<<32) & 0x001f00000000ffff
+	x = (x | x<<16) & 0x001f0000ff0000ff
+	x = (x | x<<8) & 0x100f00f00f00f00f
+	x = (x | x<<4) & 0x10c30c30c30c30c3
+	x = (x | x<<2) & 0x1249249249249249
+	return x
+}
+
+// compact3 returns every third bit of x, starting from the lowest,
+// gathered into the low 21 bits of the result. It is the inverse of
+// spread3.
+func compact3(x uint64) uint64 {
+	x &= 0x1249249249249249
+	x = (x | x>>2) & 0x10c30c30c30c30c3
+	x = (x | x>>4) & 0x100f00f00f00f00f
+	x = (x | x>>8) & 0x001f0000ff0000ff
+	x = (x | x>>16) & 0x001f00000000ffff
+	x = (x | x>>32) & 0x00000000001fffff
+	return x
+}
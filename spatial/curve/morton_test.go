@@ -0,0 +1,133 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package curve
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestMorton2D(t *testing.T) {
+	// The Morton curve of order 2 visits the cells of
+	// each quadrant in a Z pattern, and the quadrants
+	// in the same pattern.
+	want := [][]int{
+		{0, 0}, {1, 0}, {0, 1}, {1, 1},
+		{2, 0}, {3, 0}, {2, 1}, {3, 1},
+		{0, 2}, {1, 2}, {0, 3}, {1, 3},
+		{2, 2}, {3, 2}, {2, 3}, {3, 3},
+	}
+	m := Morton2D{Order: 2}
+	if got := m.Dims(); !reflect.DeepEqual(got, []int{4, 4}) {
+		t.Errorf("unexpected dimensions: got:%v want:[4 4]", got)
+	}
+	if got := m.Len(); got != len(want) {
+		t.Errorf("unexpected length: got:%d want:%d", got, len(want))
+	}
+	for pos, v := range want {
+		if got := m.Coord(nil, pos); !reflect.DeepEqual(got, v) {
+			t.Errorf("unexpected coordinates at %d: got:%v want:%v", pos, got, v)
+		}
+		if got := m.Pos(v); got != pos {
+			t.Errorf("unexpected position of %v: got:%d want:%d", v, got, pos)
+		}
+	}
+}
+
+func TestMorton3D(t *testing.T) {
+	m := Morton3D{Order: 1}
+	want := [][]int{
+		{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0},
+		{0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1},
+	}
+	if got := m.Dims(); !reflect.DeepEqual(got, []int{2, 2, 2}) {
+		t.Errorf("unexpected dimensions: got:%v want:[2 2 2]", got)
+	}
+	if got := m.Len(); got != len(want) {
+		t.Errorf("unexpected length: got:%d want:%d", got, len(want))
+	}
+	for pos, v := range want {
+		if got := m.Coord(nil, pos); !reflect.DeepEqual(got, v) {
+			t.Errorf("unexpected coordinates at %d: got:%v want:%v", pos, got, v)
+		}
+		if got := m.Pos(v); got != pos {
+			t.Errorf("unexpected position of %v: got:%d want:%d", v, got, pos)
+		}
+	}
+}
+
+// mortonPos returns the Morton position of v by interleaving
+// the bits of its coordinates one at a time.
+func mortonPos(v []int, order int) int {
+	var pos int
+	for b := 0; b < order; b++ {
+		for i, c := range v {
+			pos |= (c >> uint(b) & 1) << uint(b*len(v)+i)
+		}
+	}
+	return pos
+}
+
+func TestMortonRoundTrip(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, m := range []struct {
+		curve SpaceFilling
+		order int
+	}{
+		{curve: Morton2D{Order: 0}, order: 0},
+		{curve: Morton2D{Order: 5}, order: 5},
+		{curve: Morton2D{Order: maxOrder2}, order: maxOrder2},
+		{curve: Morton3D{Order: 0}, order: 0},
+		{curve: Morton3D{Order: 7}, order: 7},
+		{curve: Morton3D{Order: maxOrder3}, order: maxOrder3},
+	} {
+		dims := m.curve.Dims()
+		v := make([]int, len(dims))
+		dst := make([]int, 0, len(dims))
+		for i := 0; i < 1000; i++ {
+			for j, n := range dims {
+				v[j] = rnd.Intn(n)
+			}
+			pos := m.curve.Pos(v)
+			if want := mortonPos(v, m.order); pos != want {
+				t.Errorf("unexpected position of %v for %T order %d: got:%d want:%d", v, m.curve, m.order, pos, want)
+			}
+			got := m.curve.Coord(dst, pos)
+			if !reflect.DeepEqual(got, v) {
+				t.Errorf("unexpected coordinates at %d for %T order %d: got:%v want:%v", pos, m.curve, m.order, got, v)
+			}
+			if &got[0] != &dst[:1][0] {
+				t.Errorf("coordinates not stored in dst")
+			}
+		}
+		if pos := m.curve.Pos(v); pos < 0 || pos >= m.curve.Len() {
+			t.Errorf("position out of range for %T order %d: %d", m.curve, m.order, pos)
+		}
+	}
+}
+
+func TestMortonPanics(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "2D negative order", fn: func() { Morton2D{Order: -1}.Len() }},
+		{name: "2D large order", fn: func() { Morton2D{Order: maxOrder2 + 1}.Dims() }},
+		{name: "2D dimension mismatch", fn: func() { Morton2D{Order: 2}.Pos([]int{1, 1, 1}) }},
+		{name: "2D negative coordinate", fn: func() { Morton2D{Order: 2}.Pos([]int{-1, 1}) }},
+		{name: "2D large coordinate", fn: func() { Morton2D{Order: 2}.Pos([]int{1, 4}) }},
+		{name: "2D position", fn: func() { Morton2D{Order: 2}.Coord(nil, 16) }},
+		{name: "3D large order", fn: func() { Morton3D{Order: maxOrder3 + 1}.Len() }},
+		{name: "3D dimension mismatch", fn: func() { Morton3D{Order: 2}.Pos([]int{1, 1}) }},
+		{name: "3D large coordinate", fn: func() { Morton3D{Order: 2}.Pos([]int{4, 1, 1}) }},
+		{name: "3D position", fn: func() { Morton3D{Order: 2}.Coord(nil, -1) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
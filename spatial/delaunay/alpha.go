@@ -0,0 +1,123 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package delaunay
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// AlphaShape returns the boundaries of the alpha shape of the triangulated
+// points as polygons of indices into t.Points. The alpha shape is the union
+// of the Delaunay triangles with a circumradius no greater than alpha, so
+// small values of alpha give tight concave outlines that may be split into
+// several regions with holes, and as alpha increases the alpha shape grows
+// to the convex hull of the points.
+//
+// The boundary of each region is returned in counter-clockwise order and the
+// boundary of each hole in clockwise order, so the signed areas given by
+// r2.Area are positive for regions and negative for holes. A vertex where
+// regions touch at a point is held in the boundary of each of them. Each
+// polygon starts from its vertex with the lowest X and then Y coordinate,
+// and the polygons are ordered by their first vertices. Points that are not
+// vertices of a triangle of the alpha shape are not included. If no
+// triangle is within alpha, AlphaShape returns nil.
+func (t *Triangulation) AlphaShape(alpha float64) [][]int {
+	keep := make([]bool, len(t.Triangles))
+	for i, tri := range t.Triangles {
+		keep[i] = circumradius(t.Points[tri[0]], t.Points[tri[1]], t.Points[tri[2]]) <= alpha
+	}
+
+	// Collect the boundary edges, oriented with the
+	// alpha shape on their left.
+	var edges [][2]int
+	for i, tri := range t.Triangles {
+		if !keep[i] {
+			continue
+		}
+		for k, adj := range t.Adjacent[i] {
+			if adj < 0 || !keep[adj] {
+				edges = append(edges, [2]int{tri[k], tri[(k+1)%3]})
+			}
+		}
+	}
+	if len(edges) == 0 {
+		return nil
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		ei, ej := edges[i], edges[j]
+		if ei[0] != ej[0] {
+			return less(t.Points[ei[0]], t.Points[ej[0]])
+		}
+		return less(t.Points[ei[1]], t.Points[ej[1]])
+	})
+	out := make(map[int][]int)
+	for i, e := range edges {
+		out[e[0]] = append(out[e[0]], i)
+	}
+
+	// Trace the boundary edges into polygons. Where
+	// more than one unused edge leaves a vertex, the
+	// regions meet at the vertex, and the edge that
+	// continues the current region is the first edge
+	// clockwise from the incoming edge.
+	used := make([]bool, len(edges))
+	var polygons [][]int
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		used[start] = true
+		poly := []int{edges[start][0]}
+		for e := start; ; {
+			a, v := edges[e][0], edges[e][1]
+			next := -1
+			min := math.Inf(1)
+			for _, c := range out[v] {
+				if used[c] && c != start {
+					continue
+				}
+				cw := clockwise(t.Points[v], t.Points[a], t.Points[edges[c][1]])
+				if cw < min {
+					next, min = c, cw
+				}
+			}
+			if next == start || next < 0 {
+				break
+			}
+			used[next] = true
+			poly = append(poly, v)
+			e = next
+		}
+		polygons = append(polygons, poly)
+	}
+	return polygons
+}
+
+// circumradius returns the radius of the circle through a, b and c.
+func circumradius(a, b, c r2.Vec) float64 {
+	ab := b.Sub(a)
+	bc := c.Sub(b)
+	ca := a.Sub(c)
+	area2 := math.Abs(ab.X*ca.Y - ab.Y*ca.X)
+	if area2 == 0 {
+		return math.Inf(1)
+	}
+	return math.Hypot(ab.X, ab.Y) * math.Hypot(bc.X, bc.Y) * math.Hypot(ca.X, ca.Y) / (2 * area2)
+}
+
+// clockwise returns the clockwise angle at v from the direction of a to
+// the direction of w, in the interval (0, 2π].
+func clockwise(v, a, w r2.Vec) float64 {
+	d0 := a.Sub(v)
+	d := w.Sub(v)
+	cw := math.Atan2(d.X*d0.Y-d.Y*d0.X, d0.X*d.X+d0.Y*d.Y)
+	if cw <= 0 {
+		cw += 2 * math.Pi
+	}
+	return cw
+}
@@ -0,0 +1,98 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package delaunay
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func TestAlphaShape(t *testing.T) {
+	var grid, holed []r2.Vec
+	for x := 0; x < 7; x++ {
+		for y := 0; y < 7; y++ {
+			p := r2.Vec{X: float64(x), Y: float64(y)}
+			grid = append(grid, p)
+			if x < 2 || 4 < x || y < 2 || 4 < y {
+				holed = append(holed, p)
+			}
+		}
+	}
+	bowtie := []r2.Vec{{X: 0, Y: 0}, {X: -2, Y: -1}, {X: -2, Y: 1}, {X: 2, Y: -1}, {X: 2, Y: 1}}
+	clusters := []r2.Vec{
+		{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1},
+		{X: 10, Y: 10}, {X: 11, Y: 10}, {X: 10.5, Y: 11},
+	}
+
+	for _, test := range []struct {
+		name   string
+		points []r2.Vec
+		alpha  float64
+
+		wantLens  []int
+		wantAreas []float64
+	}{
+		{name: "grid", points: grid, alpha: 1, wantLens: []int{24}, wantAreas: []float64{36}},
+		{name: "grid small", points: grid, alpha: 0.5},
+		{name: "holed", points: holed, alpha: 1, wantLens: []int{24, 12}, wantAreas: []float64{36, -14}},
+		{name: "holed large", points: holed, alpha: 10, wantLens: []int{24}, wantAreas: []float64{36}},
+		{name: "bowtie", points: bowtie, alpha: 1.5, wantLens: []int{3, 3}, wantAreas: []float64{2, 2}},
+		{name: "bowtie large", points: bowtie, alpha: 3, wantLens: []int{4}, wantAreas: []float64{8}},
+		{name: "clusters", points: clusters, alpha: 1, wantLens: []int{4, 3}, wantAreas: []float64{1, 0.5}},
+	} {
+		tri := Triangulate(test.points)
+		got := tri.AlphaShape(test.alpha)
+		if len(got) != len(test.wantLens) {
+			t.Errorf("%s: unexpected number of polygons: got:%d want:%d", test.name, len(got), len(test.wantLens))
+			continue
+		}
+		for i, poly := range got {
+			if len(poly) != test.wantLens[i] {
+				t.Errorf("%s: unexpected number of vertices in polygon %d: got:%d want:%d", test.name, i, len(poly), test.wantLens[i])
+			}
+			if a := area(tri.Points, poly); math.Abs(a-test.wantAreas[i]) > 1e-12 {
+				t.Errorf("%s: unexpected area of polygon %d: got:%v want:%v", test.name, i, a, test.wantAreas[i])
+			}
+			for _, v := range poly[1:] {
+				if less(tri.Points[v], tri.Points[poly[0]]) {
+					t.Errorf("%s: polygon %d does not start at its lowest vertex", test.name, i)
+				}
+			}
+			if i > 0 && less(tri.Points[poly[0]], tri.Points[got[i-1][0]]) {
+				t.Errorf("%s: polygons not ordered by first vertex", test.name)
+			}
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	random := make([]r2.Vec, 200)
+	for i := range random {
+		random[i] = r2.Vec{X: rnd.Float64(), Y: rnd.Float64()}
+	}
+	tri := Triangulate(random)
+	if got, want := tri.AlphaShape(math.Inf(1)), [][]int{tri.Hull()}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected alpha shape for infinite alpha: got:%v want:%v", got, want)
+	}
+	var total float64
+	for _, poly := range tri.AlphaShape(0.05) {
+		total += area(tri.Points, poly)
+	}
+	if hull := area(tri.Points, tri.Hull()); total <= 0 || hull <= total {
+		t.Errorf("unexpected area of concave alpha shape: got:%v want in (0, %v)", total, hull)
+	}
+}
+
+func area(points []r2.Vec, poly []int) float64 {
+	p := make([]r2.Vec, len(poly))
+	for i, v := range poly {
+		p[i] = points[v]
+	}
+	return r2.Area(p)
+}
@@ -0,0 +1,348 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package delaunay
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// Triangulation is a Delaunay triangulation of a set of points.
+type Triangulation struct {
+	// Points holds the triangulated points.
+	Points []r2.Vec
+
+	// Triangles holds the vertices of each
+	// triangle as indices into Points, in
+	// counter-clockwise order.
+	Triangles [][3]int
+
+	// Adjacent holds the index of the
+	// triangle sharing the edge from
+	// Triangles[i][k] to Triangles[i][(k+1)%3]
+	// for each triangle i, or -1 if the
+	// edge is on the convex hull.
+	Adjacent [][3]int
+}
+
+// Triangulate returns the Delaunay triangulation of points. The returned
+// Triangulation holds points, which must not be modified while the
+// Triangulation is in use. Duplicate points are triangulated once, using
+// the first occurrence, and later occurrences are not vertices of any
+// triangle. When four or more points are cocircular, the triangulation of
+// their convex hull is chosen in an unspecified way.
+//
+// If points holds fewer than three distinct points, or all of the points
+// are collinear, the triangulation has no triangles.
+//
+// Triangulate uses the Bowyer-Watson algorithm with exact orientation and
+// in-circle predicates, inserting points in order of their coordinates.
+func Triangulate(points []r2.Vec) *Triangulation {
+	t := &Triangulation{Points: points}
+
+	order := make([]int, len(points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(points[order[i]], points[order[j]])
+	})
+	n := 0
+	for _, i := range order {
+		if n == 0 || points[i] != points[order[n-1]] {
+			order[n] = i
+			n++
+		}
+	}
+	order = order[:n]
+	if n < 3 {
+		return t
+	}
+
+	// Find the first point that is not collinear
+	// with the first two to form the initial
+	// triangle.
+	k := 2
+	for k < n && r2.Orient(points[order[0]], points[order[1]], points[order[k]]) == 0 {
+		k++
+	}
+	if k == n {
+		return t
+	}
+	b := builder{points: points}
+	b.init(order[0], order[1], order[k])
+	for j, i := range order[2:] {
+		if j+2 != k {
+			b.insert(i)
+		}
+	}
+	b.result(t)
+	return t
+}
+
+// ghost is the vertex at infinity shared by the ghost triangles that
+// cover the exterior of the convex hull.
+const ghost = -1
+
+// triangle is a triangle under construction. The vertices are in
+// counter-clockwise order and adj[k] is the triangle across the edge
+// from v[k] to v[(k+1)%3]. Ghost triangles have a ghost vertex in v[2]
+// and the hull edge from v[0] to v[1], with the hull on the right.
+type triangle struct {
+	v    [3]int
+	adj  [3]int
+	dead bool
+}
+
+// builder constructs a Delaunay triangulation by incremental insertion.
+type builder struct {
+	points []r2.Vec
+	tris   []triangle
+
+	// last is a real triangle from which
+	// point location walks start.
+	last int
+}
+
+// init initializes the triangulation with the non-degenerate triangle
+// of the points with indices i, j and k, and its ghost triangles.
+func (b *builder) init(i, j, k int) {
+	if r2.Orient(b.points[i], b.points[j], b.points[k]) < 0 {
+		j, k = k, j
+	}
+	b.tris = []triangle{
+		{v: [3]int{i, j, k}, adj: [3]int{1, 2, 3}},
+		{v: [3]int{j, i, ghost}, adj: [3]int{0, 3, 2}},
+		{v: [3]int{k, j, ghost}, adj: [3]int{0, 1, 3}},
+		{v: [3]int{i, k, ghost}, adj: [3]int{0, 2, 1}},
+	}
+	b.last = 0
+}
+
+// conflicts returns whether the open circumcircle of triangle t contains
+// p. The circumcircle of a ghost triangle is the open half-plane beyond
+// its hull edge, together with the open hull edge.
+func (b *builder) conflicts(t int, p r2.Vec) bool {
+	v := b.tris[t].v
+	pu, pv := b.points[v[0]], b.points[v[1]]
+	if v[2] != ghost {
+		return r2.InCircle(pu, pv, b.points[v[2]], p) > 0
+	}
+	o := r2.Orient(pu, pv, p)
+	if o != 0 {
+		return o > 0
+	}
+	if pu.X != pv.X {
+		return (pu.X < p.X) == (p.X < pv.X) && p.X != pu.X && p.X != pv.X
+	}
+	return (pu.Y < p.Y) == (p.Y < pv.Y) && p.Y != pu.Y && p.Y != pv.Y
+}
+
+// locate returns a triangle whose circumcircle contains p, found by
+// walking from the last created triangle.
+func (b *builder) locate(p r2.Vec) int {
+	t := b.last
+	for {
+		tri := &b.tris[t]
+		if tri.v[2] == ghost {
+			return t
+		}
+		next := -1
+		for k := range tri.v {
+			if r2.Orient(b.points[tri.v[k]], b.points[tri.v[(k+1)%3]], p) < 0 {
+				next = tri.adj[k]
+				break
+			}
+		}
+		if next < 0 {
+			return t
+		}
+		t = next
+	}
+}
+
+// insert inserts the point with index i into the triangulation.
+func (b *builder) insert(i int) {
+	p := b.points[i]
+
+	// Find the cavity of triangles whose
+	// circumcircles contain p and the edges
+	// at its boundary.
+	cavity := []int{b.locate(p)}
+	b.tris[cavity[0]].dead = true
+	type edge struct{ u, v, adj int }
+	var boundary []edge
+	for n := 0; n < len(cavity); n++ {
+		tri := &b.tris[cavity[n]]
+		for k, adj := range tri.adj {
+			if b.tris[adj].dead {
+				continue
+			}
+			if b.conflicts(adj, p) {
+				b.tris[adj].dead = true
+				cavity = append(cavity, adj)
+				continue
+			}
+			boundary = append(boundary, edge{u: tri.v[k], v: tri.v[(k+1)%3], adj: adj})
+		}
+	}
+
+	// Connect p to each boundary edge. The
+	// triangles of the cavity are reused
+	// before new triangles are allocated.
+	start := make(map[int]int, len(boundary))
+	for j, e := range boundary {
+		var t int
+		if j < len(cavity) {
+			t = cavity[j]
+		} else {
+			t = len(b.tris)
+			b.tris = append(b.tris, triangle{})
+		}
+		// Rotate ghost vertices into the
+		// last position.
+		var tri triangle
+		switch ghost {
+		case e.u:
+			tri.v = [3]int{e.v, i, ghost}
+			tri.adj[2] = e.adj
+		case e.v:
+			tri.v = [3]int{i, e.u, ghost}
+			tri.adj[1] = e.adj
+		default:
+			tri.v = [3]int{e.u, e.v, i}
+			tri.adj[0] = e.adj
+			b.last = t
+		}
+		b.tris[t] = tri
+		b.setAdj(e.adj, e.v, e.u, t)
+		start[e.u] = t
+	}
+	for _, e := range boundary {
+		t := start[e.u]
+		s := start[e.v]
+		b.setAdj(t, e.v, i, s)
+		b.setAdj(s, i, e.v, t)
+	}
+}
+
+// setAdj sets the triangle across the edge from u to v of triangle t
+// to adj.
+func (b *builder) setAdj(t, u, v, adj int) {
+	tri := &b.tris[t]
+	for k := range tri.v {
+		if tri.v[k] == u && tri.v[(k+1)%3] == v {
+			tri.adj[k] = adj
+			return
+		}
+	}
+	panic("delaunay: missing edge")
+}
+
+// result stores the real triangles of the triangulation in t.
+func (b *builder) result(t *Triangulation) {
+	index := make([]int, len(b.tris))
+	for j, tri := range b.tris {
+		if tri.v[2] == ghost {
+			index[j] = -1
+			continue
+		}
+		index[j] = len(t.Triangles)
+		t.Triangles = append(t.Triangles, tri.v)
+	}
+	t.Adjacent = make([][3]int, len(t.Triangles))
+	for j, tri := range b.tris {
+		if index[j] < 0 {
+			continue
+		}
+		for k, adj := range tri.adj {
+			t.Adjacent[index[j]][k] = index[adj]
+		}
+	}
+}
+
+// Locate returns the index of a triangle of t that contains p, or -1 if p
+// is outside the convex hull of the triangulated points. A point on an edge
+// or a vertex is contained by each of the triangles that share it, and any
+// one of them may be returned.
+func (t *Triangulation) Locate(p r2.Vec) int {
+	if len(t.Triangles) == 0 {
+		return -1
+	}
+	i := 0
+	for {
+		tri := t.Triangles[i]
+		next := i
+		for k := range tri {
+			if r2.Orient(t.Points[tri[k]], t.Points[tri[(k+1)%3]], p) < 0 {
+				next = t.Adjacent[i][k]
+				break
+			}
+		}
+		if next == i || next < 0 {
+			return next
+		}
+		i = next
+	}
+}
+
+// Hull returns the indices of the vertices of the convex hull of the
+// triangulated points in counter-clockwise order, starting from the
+// vertex with the lowest X and then Y coordinate. Points that lie on an
+// edge of the hull are included. If t has no triangles, Hull returns nil.
+func (t *Triangulation) Hull() []int {
+	next := make(map[int]int)
+	first := -1
+	for i, tri := range t.Triangles {
+		for k, adj := range t.Adjacent[i] {
+			if adj >= 0 {
+				continue
+			}
+			u := tri[k]
+			next[u] = tri[(k+1)%3]
+			if first < 0 || less(t.Points[u], t.Points[first]) {
+				first = u
+			}
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	hull := []int{first}
+	for v := next[first]; v != first; v = next[v] {
+		hull = append(hull, v)
+	}
+	return hull
+}
+
+// less returns whether p is before q in order of X and then Y coordinate.
+func less(p, q r2.Vec) bool {
+	if p.X != q.X {
+		return p.X < q.X
+	}
+	return p.Y < q.Y
+}
+
+// Edges returns the edges of the triangulation, with each edge held once
+// as a pair of point indices, lower index first. The Delaunay edges are a
+// superset of the edges of the Euclidean minimum spanning tree, relative
+// neighborhood graph and Gabriel graph of the points.
+func (t *Triangulation) Edges() [][2]int {
+	var edges [][2]int
+	for i, tri := range t.Triangles {
+		for k, adj := range t.Adjacent[i] {
+			if adj >= 0 && adj < i {
+				continue
+			}
+			u, v := tri[k], tri[(k+1)%3]
+			if u > v {
+				u, v = v, u
+			}
+			edges = append(edges, [2]int{u, v})
+		}
+	}
+	return edges
+}
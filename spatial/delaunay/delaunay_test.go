@@ -0,0 +1,170 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package delaunay
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func TestTriangulate(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	random := make([]r2.Vec, 300)
+	for i := range random {
+		random[i] = r2.Vec{X: rnd.Float64(), Y: rnd.Float64()}
+	}
+
+	var grid []r2.Vec
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			grid = append(grid, r2.Vec{X: float64(x), Y: float64(y)})
+		}
+	}
+	rnd.Shuffle(len(grid), func(i, j int) { grid[i], grid[j] = grid[j], grid[i] })
+
+	circle := make([]r2.Vec, 64)
+	for i := range circle {
+		s, c := math.Sincos(2 * math.Pi * float64(i) / float64(len(circle)))
+		circle[i] = r2.Vec{X: c, Y: s}
+	}
+	circle = append(circle, r2.Vec{})
+
+	// Points on a line with one point off it.
+	fan := []r2.Vec{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 1.5, Y: 1}, {X: 1, Y: 0}}
+
+	for _, test := range []struct {
+		name   string
+		points []r2.Vec
+	}{
+		{name: "triangle", points: []r2.Vec{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}}},
+		{name: "random", points: random},
+		{name: "grid", points: grid},
+		{name: "circle", points: circle},
+		{name: "fan", points: fan},
+	} {
+		tri := Triangulate(test.points)
+		checkTriangulation(t, test.name, tri)
+	}
+}
+
+// checkTriangulation checks that tri is a valid Delaunay triangulation
+// of its points with consistent adjacency.
+func checkTriangulation(t *testing.T, name string, tri *Triangulation) {
+	t.Helper()
+	p := tri.Points
+	if len(tri.Adjacent) != len(tri.Triangles) {
+		t.Fatalf("mismatched adjacency length for %s", name)
+	}
+	distinct := make(map[r2.Vec]bool)
+	for _, v := range p {
+		distinct[v] = true
+	}
+	hull := tri.Hull()
+	if want := 2*len(distinct) - 2 - len(hull); len(tri.Triangles) != want {
+		t.Errorf("unexpected number of triangles for %s: got:%d want:%d", name, len(tri.Triangles), want)
+	}
+	for i, v := range tri.Triangles {
+		if r2.Orient(p[v[0]], p[v[1]], p[v[2]]) <= 0 {
+			t.Errorf("triangle %d not counter-clockwise for %s: %v", i, name, v)
+		}
+		for k, adj := range tri.Adjacent[i] {
+			if adj < 0 {
+				continue
+			}
+			u, w := v[k], v[(k+1)%3]
+			a := tri.Triangles[adj]
+			ok := false
+			for j := range a {
+				if a[j] == w && a[(j+1)%3] == u && tri.Adjacent[adj][j] == i {
+					ok = true
+				}
+			}
+			if !ok {
+				t.Errorf("inconsistent adjacency between triangles %d and %d for %s", i, adj, name)
+			}
+		}
+		for j, q := range p {
+			if r2.InCircle(p[v[0]], p[v[1]], p[v[2]], q) > 0 {
+				t.Errorf("point %d inside circumcircle of triangle %d for %s", j, i, name)
+			}
+		}
+	}
+	for i := range hull {
+		a, b, c := p[hull[i]], p[hull[(i+1)%len(hull)]], p[hull[(i+2)%len(hull)]]
+		if r2.Orient(a, b, c) < 0 {
+			t.Errorf("hull not convex for %s", name)
+		}
+	}
+	if got, want := len(tri.Edges()), len(distinct)+len(tri.Triangles)-1; got != want {
+		t.Errorf("unexpected number of edges for %s: got:%d want:%d", name, got, want)
+	}
+}
+
+func TestTriangulateDegenerate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		points []r2.Vec
+	}{
+		{name: "empty"},
+		{name: "two", points: []r2.Vec{{X: 0, Y: 0}, {X: 1, Y: 1}}},
+		{name: "duplicates", points: []r2.Vec{{X: 1, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 1}}},
+		{name: "collinear", points: []r2.Vec{{X: 0, Y: 0}, {X: 2, Y: 2}, {X: 1, Y: 1}, {X: 3, Y: 3}}},
+	} {
+		tri := Triangulate(test.points)
+		if len(tri.Triangles) != 0 || tri.Hull() != nil || tri.Locate(r2.Vec{}) != -1 {
+			t.Errorf("unexpected triangulation for %s: %v", test.name, tri.Triangles)
+		}
+	}
+}
+
+func TestLocate(t *testing.T) {
+	points := []r2.Vec{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}, {X: 1, Y: 2}, {X: 3, Y: 1}}
+	tri := Triangulate(points)
+	if want := []int{0, 1, 2, 3}; !reflect.DeepEqual(tri.Hull(), want) {
+		t.Errorf("unexpected hull: got:%v want:%v", tri.Hull(), want)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for n := 0; n < 100; n++ {
+		q := r2.Vec{X: 6*rnd.Float64() - 1, Y: 6*rnd.Float64() - 1}
+		i := tri.Locate(q)
+		inside := 0 <= q.X && q.X <= 4 && 0 <= q.Y && q.Y <= 4
+		if (i >= 0) != inside {
+			t.Errorf("unexpected location of %v: got triangle %d", q, i)
+			continue
+		}
+		if i < 0 {
+			continue
+		}
+		v := tri.Triangles[i]
+		for k := range v {
+			if r2.Orient(points[v[k]], points[v[(k+1)%3]], q) < 0 {
+				t.Errorf("%v not in located triangle %v", q, v)
+			}
+		}
+	}
+}
+
+func BenchmarkTriangulate(b *testing.B) {
+	for _, n := range []int{1e3, 1e4, 1e5} {
+		rnd := rand.New(rand.NewSource(1))
+		points := make([]r2.Vec, n)
+		for i := range points {
+			points[i] = r2.Vec{X: rnd.Float64(), Y: rnd.Float64()}
+		}
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Triangulate(points)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package delaunay implements Delaunay triangulation of points in the plane
+// and the construction of their Voronoi diagrams and alpha shapes.
+//
+// See https://en.wikipedia.org/wiki/Delaunay_triangulation,
+// https://en.wikipedia.org/wiki/Voronoi_diagram and
+// https://en.wikipedia.org/wiki/Alpha_shape for more details.
+package delaunay // import "gonum.org/v1/gonum/spatial/delaunay"
@@ -0,0 +1,112 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package delaunay
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// Voronoi returns the Voronoi cells of the triangulated points clipped to
+// bounds. The cell of t.Points[i] is held in the i-th element of the
+// returned slice as a convex polygon with vertices in counter-clockwise
+// order. Cells that do not intersect bounds, and the cells of later
+// occurrences of duplicate points, are nil.
+//
+// The cells are constructed from the Delaunay triangulation, as the
+// intersection of bounds with the half-planes that are closer to the point
+// than to each of its Delaunay neighbors.
+func (t *Triangulation) Voronoi(bounds r2.Box) [][]r2.Vec {
+	cells := make([][]r2.Vec, len(t.Points))
+	neighbors := t.neighbors()
+	box := []r2.Vec{
+		bounds.Min,
+		{X: bounds.Max.X, Y: bounds.Min.Y},
+		bounds.Max,
+		{X: bounds.Min.X, Y: bounds.Max.Y},
+	}
+	var buf []r2.Vec
+	seen := make(map[r2.Vec]bool)
+	for i, adj := range neighbors {
+		p := t.Points[i]
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		cell := append([]r2.Vec(nil), box...)
+		for _, j := range adj {
+			cell, buf = clip(buf[:0], cell, p, t.Points[j]), cell
+			if len(cell) == 0 {
+				break
+			}
+		}
+		if len(cell) != 0 {
+			cells[i] = append([]r2.Vec(nil), cell...)
+		}
+	}
+	return cells
+}
+
+// neighbors returns the Delaunay neighbors of each point. The neighbors of
+// later occurrences of duplicate points are nil.
+func (t *Triangulation) neighbors() [][]int {
+	neighbors := make([][]int, len(t.Points))
+	if len(t.Triangles) != 0 {
+		for _, e := range t.Edges() {
+			neighbors[e[0]] = append(neighbors[e[0]], e[1])
+			neighbors[e[1]] = append(neighbors[e[1]], e[0])
+		}
+		return neighbors
+	}
+
+	// The distinct points are collinear, so the
+	// neighbors of each point are the points
+	// before and after it along the line.
+	order := make([]int, len(t.Points))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(t.Points[order[i]], t.Points[order[j]])
+	})
+	prev := -1
+	for _, i := range order {
+		if prev >= 0 && t.Points[i] == t.Points[prev] {
+			continue
+		}
+		if prev >= 0 {
+			neighbors[prev] = append(neighbors[prev], i)
+			neighbors[i] = append(neighbors[i], prev)
+		}
+		prev = i
+	}
+	return neighbors
+}
+
+// clip appends the part of the convex polygon that is at least as close to
+// p as to q to dst and returns it.
+func clip(dst, polygon []r2.Vec, p, q r2.Vec) []r2.Vec {
+	// The kept half-plane is the set of points
+	// x with (x-m)·n <= 0 where m is the mid
+	// point of p and q, and n is q-p.
+	m := p.Add(q).Scale(0.5)
+	n := q.Sub(p)
+	side := func(x r2.Vec) float64 {
+		d := x.Sub(m)
+		return d.X*n.X + d.Y*n.Y
+	}
+	for i, a := range polygon {
+		b := polygon[(i+1)%len(polygon)]
+		sa, sb := side(a), side(b)
+		if sa <= 0 {
+			dst = append(dst, a)
+		}
+		if (sa < 0 && sb > 0) || (sa > 0 && sb < 0) {
+			dst = append(dst, a.Add(b.Sub(a).Scale(sa/(sa-sb))))
+		}
+	}
+	return dst
+}
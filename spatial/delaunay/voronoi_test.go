@@ -0,0 +1,124 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package delaunay
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func TestVoronoi(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := make([]r2.Vec, 200)
+	for i := range points {
+		points[i] = r2.Vec{X: rnd.Float64(), Y: rnd.Float64()}
+	}
+	bounds := r2.Box{Max: r2.Vec{X: 1, Y: 1}}
+	cells := Triangulate(points).Voronoi(bounds)
+
+	var area float64
+	for i, c := range cells {
+		if c == nil {
+			t.Fatalf("missing cell for point %d", i)
+		}
+		a := r2.Area(c)
+		if a <= 0 {
+			t.Errorf("cell %d not counter-clockwise: area %v", i, a)
+		}
+		area += a
+		if !inConvex(c, points[i], 0) {
+			t.Errorf("point %d not in its cell", i)
+		}
+	}
+	if math.Abs(area-1) > 1e-12 {
+		t.Errorf("cells do not partition bounds: total area %v", area)
+	}
+
+	for n := 0; n < 1000; n++ {
+		q := r2.Vec{X: rnd.Float64(), Y: rnd.Float64()}
+		nearest := 0
+		for i, p := range points {
+			if dist2(p, q) < dist2(points[nearest], q) {
+				nearest = i
+			}
+		}
+		if !inConvex(cells[nearest], q, 1e-12) {
+			t.Errorf("%v not in the cell of its nearest point %d", q, nearest)
+		}
+	}
+}
+
+func TestVoronoiDegenerate(t *testing.T) {
+	bounds := r2.Box{Min: r2.Vec{X: -1, Y: -1}, Max: r2.Vec{X: 3, Y: 1}}
+	for _, test := range []struct {
+		name   string
+		points []r2.Vec
+		want   []float64
+	}{
+		{
+			name:   "single",
+			points: []r2.Vec{{X: 0, Y: 0}},
+			want:   []float64{8},
+		},
+		{
+			name:   "collinear",
+			points: []r2.Vec{{X: 2, Y: 0}, {X: 0, Y: 0}, {X: 1, Y: 0}},
+			want:   []float64{3, 3, 2},
+		},
+		{
+			name:   "duplicates",
+			points: []r2.Vec{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 0}},
+			want:   []float64{4, 4, 0},
+		},
+		{
+			name:   "outside",
+			points: []r2.Vec{{X: -0.5, Y: -0.5}, {X: 2, Y: 0.5}, {X: 0, Y: 0.5}, {X: 0, Y: -10}},
+			want:   []float64{-1, -1, -1, 0},
+		},
+	} {
+		cells := Triangulate(test.points).Voronoi(bounds)
+		if len(cells) != len(test.points) {
+			t.Fatalf("unexpected number of cells for %s: got:%d want:%d", test.name, len(cells), len(test.points))
+		}
+		for i, c := range cells {
+			switch want := test.want[i]; {
+			case want == 0:
+				if c != nil {
+					t.Errorf("unexpected cell %d for %s: %v", i, test.name, c)
+				}
+			case want < 0:
+				if c == nil {
+					t.Errorf("missing cell %d for %s", i, test.name)
+				}
+			default:
+				if got := r2.Area(c); math.Abs(got-want) > 1e-12 {
+					t.Errorf("unexpected area of cell %d for %s: got:%v want:%v", i, test.name, got, want)
+				}
+			}
+		}
+	}
+}
+
+// inConvex returns whether p is within tol of the counter-clockwise convex
+// polygon.
+func inConvex(polygon []r2.Vec, p r2.Vec, tol float64) bool {
+	for i, a := range polygon {
+		e := polygon[(i+1)%len(polygon)].Sub(a)
+		d := p.Sub(a)
+		if e.X*d.Y-e.Y*d.X < -tol*math.Hypot(e.X, e.Y) {
+			return false
+		}
+	}
+	return true
+}
+
+func dist2(p, q r2.Vec) float64 {
+	d := p.Sub(q)
+	return d.X*d.X + d.Y*d.Y
+}
@@ -0,0 +1,812 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// Intersection returns the region of the plane within both a and b.
+func Intersection(a, b Polygon) Polygon { return clip(a, b, intersection) }
+
+// Union returns the region of the plane within either a or b.
+func Union(a, b Polygon) Polygon { return clip(a, b, union) }
+
+// Difference returns the region of the plane within a and not within b.
+func Difference(a, b Polygon) Polygon { return clip(a, b, difference) }
+
+// Xor returns the region of the plane within exactly one of a and b.
+func Xor(a, b Polygon) Polygon { return clip(a, b, xor) }
+
+// operation is a boolean operation on polygons.
+type operation int
+
+const (
+	intersection operation = iota
+	union
+	difference
+	xor
+)
+
+// edgeType is the classification of an edge with respect to the
+// boolean operation.
+type edgeType int
+
+const (
+	normal edgeType = iota
+
+	// nonContributing edges overlap an edge of the
+	// other polygon and do not contribute to the
+	// result.
+	nonContributing
+
+	// sameTransition and differentTransition edges
+	// overlap an edge of the other polygon with the
+	// same or different in-out transitions.
+	sameTransition
+	differentTransition
+)
+
+// event is an endpoint of an edge. Each edge is represented by a pair of
+// events, the left event for the endpoint that is encountered first by the
+// sweep line and the right event for the other endpoint.
+type event struct {
+	p     r2.Vec
+	left  bool
+	other *event
+
+	// subject is whether the edge is from the subject
+	// polygon, rather than the clipping polygon.
+	subject bool
+	typ     edgeType
+
+	// inOut is whether the edge is an in-out transition
+	// of its polygon for a vertical ray from below, and
+	// otherInOut is the same for the closest edge of the
+	// other polygon below the edge.
+	inOut      bool
+	otherInOut bool
+
+	// transition is +1 if the edge is an out-in
+	// transition of the result polygon, -1 for an
+	// in-out transition and 0 if the edge is not in
+	// the result. prevInResult is the closest edge
+	// below the edge in the result polygon.
+	transition   int
+	prevInResult *event
+
+	// id distinguishes events at the same position
+	// for a deterministic sweep order.
+	id int
+}
+
+// isBelow returns whether the edge of e is below p.
+func (e *event) isBelow(p r2.Vec) bool {
+	if e.left {
+		return r2.Orient(e.p, e.other.p, p) > 0
+	}
+	return r2.Orient(e.other.p, e.p, p) > 0
+}
+
+// isAbove returns whether the edge of e is above p.
+func (e *event) isAbove(p r2.Vec) bool { return !e.isBelow(p) }
+
+// isVertical returns whether the edge of e is vertical.
+func (e *event) isVertical() bool { return e.p.X == e.other.p.X }
+
+// inResult returns whether the edge of e is in the result of op.
+func (e *event) inResult(op operation) bool {
+	switch e.typ {
+	case normal:
+		switch op {
+		case intersection:
+			return !e.otherInOut
+		case union:
+			return e.otherInOut
+		case difference:
+			return e.subject == e.otherInOut
+		case xor:
+			return true
+		}
+	case sameTransition:
+		return op == intersection || op == union
+	case differentTransition:
+		return op == difference
+	}
+	return false
+}
+
+// resultTransition returns the transition of the result of op at the
+// edge of e, which must be in the result. The transition is +1 if the
+// result is above the edge and -1 if it is below.
+func (e *event) resultTransition(op operation) int {
+	this := !e.inOut
+	var that bool
+	switch e.typ {
+	case sameTransition:
+		// The overlapping edge of the other polygon
+		// makes the same transition.
+		that = this
+	case differentTransition:
+		that = !this
+	default:
+		that = !e.otherInOut
+	}
+	var in bool
+	switch op {
+	case intersection:
+		in = this && that
+	case union:
+		in = this || that
+	case difference:
+		if e.subject {
+			in = this && !that
+		} else {
+			in = that && !this
+		}
+	case xor:
+		in = this != that
+	}
+	if in {
+		return 1
+	}
+	return -1
+}
+
+// before returns whether e1 is processed before e2 by the sweep.
+func before(e1, e2 *event) bool {
+	if e1.p.X != e2.p.X {
+		return e1.p.X < e2.p.X
+	}
+	if e1.p.Y != e2.p.Y {
+		return e1.p.Y < e2.p.Y
+	}
+	if e1.left != e2.left {
+		// Right events are processed first.
+		return !e1.left
+	}
+	if r2.Orient(e1.p, e1.other.p, e2.other.p) != 0 {
+		// The edge below is processed first.
+		return e1.isBelow(e2.other.p)
+	}
+	if e1.subject != e2.subject {
+		return e1.subject
+	}
+	return e1.id < e2.id
+}
+
+// below returns whether the edge of the left event le1 is below the edge
+// of the left event le2 on the sweep line.
+func below(le1, le2 *event) bool {
+	if le1 == le2 {
+		return false
+	}
+	if r2.Orient(le1.p, le1.other.p, le2.p) != 0 || r2.Orient(le1.p, le1.other.p, le2.other.p) != 0 {
+		// The edges are not collinear.
+		if le1.p == le2.p {
+			return le1.isBelow(le2.other.p)
+		}
+		if le1.p.X == le2.p.X {
+			return le1.p.Y < le2.p.Y
+		}
+		if before(le2, le1) {
+			// le2 was inserted first.
+			if r2.Orient(le2.p, le2.other.p, le1.p) == 0 {
+				// le1 starts on le2, so the order is
+				// given by the other endpoint of le1.
+				return le2.isAbove(le1.other.p)
+			}
+			return le2.isAbove(le1.p)
+		}
+		if r2.Orient(le1.p, le1.other.p, le2.p) == 0 {
+			// le2 starts on le1.
+			return le1.isBelow(le2.other.p)
+		}
+		return le1.isBelow(le2.p)
+	}
+
+	// The edges are collinear.
+	if le1.subject != le2.subject {
+		return le1.subject
+	}
+	if le1.p == le2.p {
+		return le1.id < le2.id
+	}
+	return before(le1, le2)
+}
+
+// queue is a priority queue of events in sweep order.
+type queue []*event
+
+func (q queue) Len() int            { return len(q) }
+func (q queue) Less(i, j int) bool  { return before(q[i], q[j]) }
+func (q queue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *queue) Push(x interface{}) { *q = append(*q, x.(*event)) }
+func (q *queue) Pop() interface{} {
+	x := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return x
+}
+
+// sweep holds the state of a boolean operation.
+type sweep struct {
+	op     operation
+	queue  queue
+	status []*event
+	nextID int
+}
+
+// newEvent returns a new event.
+func (s *sweep) newEvent(p r2.Vec, left bool, other *event, subject bool) *event {
+	s.nextID++
+	return &event{p: p, left: left, other: other, subject: subject, id: s.nextID}
+}
+
+// add adds the edges of the rings of p to the event queue.
+func (s *sweep) add(p Polygon, subject bool) {
+	for _, ring := range p {
+		for i, u := range ring {
+			v := ring[(i+1)%len(ring)]
+			if u == v {
+				continue
+			}
+			e1 := s.newEvent(u, false, nil, subject)
+			e2 := s.newEvent(v, false, e1, subject)
+			e1.other = e2
+			if before(e1, e2) {
+				e1.left = true
+			} else {
+				e2.left = true
+			}
+			heap.Push(&s.queue, e1)
+			heap.Push(&s.queue, e2)
+		}
+	}
+}
+
+// clip returns the result of op applied to the subject and clipping
+// polygons.
+func clip(subject, clipping Polygon, op operation) Polygon {
+	sb, sok := bounds(subject)
+	cb, cok := bounds(clipping)
+	if !sok || !cok || sb.Max.X < cb.Min.X || cb.Max.X < sb.Min.X || sb.Max.Y < cb.Min.Y || cb.Max.Y < sb.Min.Y {
+		// The polygons are empty or their bounds do not
+		// intersect, so the result is trivial. It is still
+		// constructed by the sweep to normalize the rings.
+		switch op {
+		case intersection:
+			return nil
+		case difference:
+			clipping = nil
+		}
+	}
+
+	// Vertices in the interior of edges of the other
+	// polygon are added to those edges, so that edges
+	// in the sweep only meet at shared endpoints or
+	// where they cross.
+	subject, clipping = splitAt(subject, clipping), splitAt(clipping, subject)
+
+	s := sweep{op: op}
+	s.add(subject, true)
+	s.add(clipping, false)
+
+	// Beyond rightBound no edge can contribute to the result.
+	rightBound := math.Inf(1)
+	switch op {
+	case intersection:
+		rightBound = math.Min(sb.Max.X, cb.Max.X)
+	case difference:
+		rightBound = sb.Max.X
+	}
+
+	var sorted []*event
+	for len(s.queue) != 0 {
+		e := heap.Pop(&s.queue).(*event)
+		if e.p.X > rightBound {
+			break
+		}
+		sorted = append(sorted, e)
+		if e.left {
+			i := s.insert(e)
+			var prev, next *event
+			if i > 0 {
+				prev = s.status[i-1]
+			}
+			if i < len(s.status)-1 {
+				next = s.status[i+1]
+			}
+			s.computeFields(e, prev)
+			if next != nil && s.possibleIntersection(e, next) == 2 {
+				s.computeFields(e, prev)
+				s.computeFields(next, e)
+			}
+			if prev != nil && s.possibleIntersection(prev, e) == 2 {
+				var prevprev *event
+				if j := s.index(prev); j > 0 {
+					prevprev = s.status[j-1]
+				}
+				s.computeFields(prev, prevprev)
+				s.computeFields(e, prev)
+			}
+		} else {
+			e = e.other
+			i := s.index(e)
+			if i < 0 {
+				continue
+			}
+			var prev, next *event
+			if i > 0 {
+				prev = s.status[i-1]
+			}
+			if i < len(s.status)-1 {
+				next = s.status[i+1]
+			}
+			s.status = append(s.status[:i], s.status[i+1:]...)
+			if prev != nil && next != nil {
+				s.possibleIntersection(prev, next)
+			}
+		}
+	}
+	return connect(sorted)
+}
+
+// bounds returns the bounding box of the vertices of p, and whether p
+// has any vertices.
+func bounds(p Polygon) (b r2.Box, ok bool) {
+	b = r2.Box{
+		Min: r2.Vec{X: math.Inf(1), Y: math.Inf(1)},
+		Max: r2.Vec{X: math.Inf(-1), Y: math.Inf(-1)},
+	}
+	for _, ring := range p {
+		for _, v := range ring {
+			b.Min.X = math.Min(b.Min.X, v.X)
+			b.Min.Y = math.Min(b.Min.Y, v.Y)
+			b.Max.X = math.Max(b.Max.X, v.X)
+			b.Max.Y = math.Max(b.Max.Y, v.Y)
+			ok = true
+		}
+	}
+	return b, ok
+}
+
+// splitAt returns p with the vertices of q that are in the interior of
+// edges of p added to the rings of p.
+func splitAt(p, q Polygon) Polygon {
+	var vs []r2.Vec
+	for _, ring := range q {
+		vs = append(vs, ring...)
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i].X < vs[j].X })
+
+	split := make(Polygon, len(p))
+	var changed bool
+	for k, ring := range p {
+		r := make([]r2.Vec, 0, len(ring))
+		for i, u := range ring {
+			r = append(r, u)
+			v := ring[(i+1)%len(ring)]
+			lo, hi := math.Min(u.X, v.X), math.Max(u.X, v.X)
+			var on []r2.Vec
+			for j := sort.Search(len(vs), func(j int) bool { return vs[j].X >= lo }); j < len(vs) && vs[j].X <= hi; j++ {
+				if w := vs[j]; w != u && w != v && onSegment(w, u, v) {
+					on = append(on, w)
+				}
+			}
+			if on == nil {
+				continue
+			}
+			// The vertices are collinear with u, so they are
+			// ordered from u by their L1 distance.
+			dist := func(w r2.Vec) float64 { return math.Abs(w.X-u.X) + math.Abs(w.Y-u.Y) }
+			sort.Slice(on, func(i, j int) bool { return dist(on[i]) < dist(on[j]) })
+			for j, w := range on {
+				if j == 0 || w != on[j-1] {
+					r = append(r, w)
+				}
+			}
+			changed = true
+		}
+		split[k] = r
+	}
+	if !changed {
+		return p
+	}
+	return split
+}
+
+// insert inserts the left event e into the sweep line status and returns
+// its index.
+func (s *sweep) insert(e *event) int {
+	i := sort.Search(len(s.status), func(i int) bool { return below(e, s.status[i]) })
+	s.status = append(s.status, nil)
+	copy(s.status[i+1:], s.status[i:])
+	s.status[i] = e
+	return i
+}
+
+// index returns the index of the left event e in the sweep line status,
+// or -1 if e is not in the status.
+func (s *sweep) index(e *event) int {
+	for i, c := range s.status {
+		if c == e {
+			return i
+		}
+	}
+	return -1
+}
+
+// computeFields sets the transition fields of the left event e, given the
+// closest edge below it on the sweep line, prev, which may be nil.
+func (s *sweep) computeFields(e, prev *event) {
+	switch {
+	case prev == nil:
+		e.inOut = false
+		e.otherInOut = true
+	case e.subject == prev.subject:
+		e.inOut = !prev.inOut
+		e.otherInOut = prev.otherInOut
+	default:
+		e.inOut = !prev.otherInOut
+		if prev.isVertical() {
+			e.otherInOut = !prev.inOut
+		} else {
+			e.otherInOut = prev.inOut
+		}
+	}
+	if prev != nil {
+		if prev.transition == 0 || prev.isVertical() {
+			e.prevInResult = prev.prevInResult
+		} else {
+			e.prevInResult = prev
+		}
+	}
+	if e.inResult(s.op) {
+		e.transition = e.resultTransition(s.op)
+	} else {
+		e.transition = 0
+	}
+}
+
+// possibleIntersection subdivides the edges of the left events e1 and e2
+// at their intersection, if any. It returns 0 if the edges do not
+// intersect or only meet at an endpoint, 1 if they cross at a point, 2 if
+// they overlap and share their left endpoint and 3 if they overlap
+// otherwise.
+func (s *sweep) possibleIntersection(e1, e2 *event) int {
+	x, n := intersect(e1.p, e1.other.p, e2.p, e2.other.p)
+	switch {
+	case n == 0:
+		return 0
+	case n == 1 && (e1.p == e2.p || e1.other.p == e2.other.p):
+		// The edges meet at an endpoint.
+		return 0
+	case n == 2 && e1.subject == e2.subject:
+		// Overlapping edges of the same polygon
+		// are not supported.
+		return 0
+	case n == 1:
+		if e1.p != x[0] && e1.other.p != x[0] {
+			s.divide(e1, x[0])
+		}
+		if e2.p != x[0] && e2.other.p != x[0] {
+			s.divide(e2, x[0])
+		}
+		return 1
+	}
+
+	// The edges overlap.
+	var events []*event
+	leftCoincide := e1.p == e2.p
+	if !leftCoincide {
+		if before(e2, e1) {
+			events = append(events, e2, e1)
+		} else {
+			events = append(events, e1, e2)
+		}
+	}
+	rightCoincide := e1.other.p == e2.other.p
+	if !rightCoincide {
+		if before(e2.other, e1.other) {
+			events = append(events, e2.other, e1.other)
+		} else {
+			events = append(events, e1.other, e2.other)
+		}
+	}
+
+	if leftCoincide {
+		// The edges are equal or share their left endpoint.
+		e2.typ = nonContributing
+		if e2.inOut == e1.inOut {
+			e1.typ = sameTransition
+		} else {
+			e1.typ = differentTransition
+		}
+		if !rightCoincide {
+			s.divide(events[1].other, events[0].p)
+		}
+		return 2
+	}
+	if rightCoincide {
+		// The edges share their right endpoint.
+		s.divide(events[0], events[1].p)
+		return 3
+	}
+	if events[0] != events[3].other {
+		// The edges overlap partially.
+		s.divide(events[0], events[1].p)
+		s.divide(events[1], events[2].p)
+		return 3
+	}
+	// One edge includes the other.
+	s.divide(events[0], events[1].p)
+	s.divide(events[3].other, events[2].p)
+	return 3
+}
+
+// divide divides the edge of the left event e at p, adding the events
+// of the new endpoints to the queue.
+func (s *sweep) divide(e *event, p r2.Vec) {
+	// r is the right event of the edge from e.p to p,
+	// and l is the left event of the edge from p to
+	// e.other.p.
+	r := s.newEvent(p, false, e, e.subject)
+	l := s.newEvent(p, true, e.other, e.subject)
+	if before(e.other, l) {
+		// Rounding of p has moved it past e.other.
+		e.other.left = true
+		l.left = false
+	}
+	e.other.other = l
+	e.other = r
+	heap.Push(&s.queue, l)
+	heap.Push(&s.queue, r)
+}
+
+// intersect returns the intersection of the segments a0-a1 and b0-b1 and
+// the number of points describing it: 0 if the segments do not intersect,
+// 1 if they intersect at a point and 2 if they overlap on a segment.
+func intersect(a0, a1, b0, b1 r2.Vec) (x [2]r2.Vec, n int) {
+	va := a1.Sub(a0)
+	vb := b1.Sub(b0)
+	e := b0.Sub(a0)
+	cross := func(u, v r2.Vec) float64 { return u.X*v.Y - u.Y*v.X }
+	dot := func(u, v r2.Vec) float64 { return u.X*v.X + u.Y*v.Y }
+
+	kross := cross(va, vb)
+	if kross != 0 {
+		// The segments are not parallel. An endpoint on the
+		// other segment is found with the exact orientation
+		// predicate, since the parameters below are rounded.
+		for _, c := range [4][3]r2.Vec{{a0, b0, b1}, {a1, b0, b1}, {b0, a0, a1}, {b1, a0, a1}} {
+			if onSegment(c[0], c[1], c[2]) {
+				x[0] = c[0]
+				return x, 1
+			}
+		}
+		s := cross(e, vb) / kross
+		if s < 0 || 1 < s {
+			return x, 0
+		}
+		t := cross(e, va) / kross
+		if t < 0 || 1 < t {
+			return x, 0
+		}
+		switch {
+		case s == 0:
+			x[0] = a0
+		case s == 1:
+			x[0] = a1
+		case t == 0:
+			x[0] = b0
+		case t == 1:
+			x[0] = b1
+		default:
+			x[0] = a0.Add(va.Scale(s))
+		}
+		return x, 1
+	}
+
+	if cross(e, va) != 0 {
+		// The segments are parallel and not collinear.
+		return x, 0
+	}
+	// The segments are collinear. Find the overlap of
+	// b0-b1 with a0-a1 in the parameterization of a0-a1.
+	lenA := dot(va, va)
+	sb0 := dot(va, e) / lenA
+	sb1 := sb0 + dot(va, vb)/lenA
+	lo, hi := b0, b1
+	slo, shi := sb0, sb1
+	if shi < slo {
+		lo, hi = hi, lo
+		slo, shi = shi, slo
+	}
+	if shi < 0 || 1 < slo {
+		return x, 0
+	}
+	if slo <= 0 {
+		lo, slo = a0, 0
+	}
+	if shi >= 1 {
+		hi, shi = a1, 1
+	}
+	if slo == shi || lo == hi {
+		x[0] = lo
+		return x, 1
+	}
+	x[0], x[1] = lo, hi
+	return x, 2
+}
+
+// onSegment returns whether p is on the segment a-b.
+func onSegment(p, a, b r2.Vec) bool {
+	return r2.Orient(a, b, p) == 0 &&
+		math.Min(a.X, b.X) <= p.X && p.X <= math.Max(a.X, b.X) &&
+		math.Min(a.Y, b.Y) <= p.Y && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// connect returns the polygon formed by joining the result edges of the
+// processed events into rings.
+func connect(sorted []*event) Polygon {
+	// Direct each result edge with the result on its
+	// left, and index the edges by their start points.
+	type edge struct {
+		from, to r2.Vec
+		e        *event
+	}
+	var edges []edge
+	out := make(map[r2.Vec][]int)
+	for _, e := range sorted {
+		if !e.left || e.transition == 0 {
+			continue
+		}
+		ed := edge{from: e.p, to: e.other.p, e: e}
+		if e.transition < 0 {
+			ed.from, ed.to = ed.to, ed.from
+		}
+		out[ed.from] = append(out[ed.from], len(edges))
+		edges = append(edges, ed)
+	}
+
+	// Trace the edges into rings. Where more than one
+	// unused edge leaves a vertex, parts of the result
+	// meet at the vertex, and the edge that continues
+	// the current part is the first edge clockwise from
+	// the incoming edge.
+	used := make([]bool, len(edges))
+	ringOf := make(map[*event]int)
+	var rings [][]r2.Vec
+	var first []*event
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+		id := len(rings)
+		used[start] = true
+		ringOf[edges[start].e] = id
+		ring := []r2.Vec{edges[start].from}
+		for e := start; ; {
+			a, v := edges[e].from, edges[e].to
+			next := -1
+			min := math.Inf(1)
+			for _, c := range out[v] {
+				if used[c] && c != start {
+					continue
+				}
+				if cw := clockwise(v, a, edges[c].to); cw < min {
+					next, min = c, cw
+				}
+			}
+			if next == start || next < 0 {
+				break
+			}
+			used[next] = true
+			ringOf[edges[next].e] = id
+			ring = append(ring, v)
+			e = next
+		}
+		rings = append(rings, simplify(ring))
+		first = append(first, edges[start].e)
+	}
+
+	// Assign each hole to the part containing it. The
+	// first edge of each ring is its first edge in sweep
+	// order, so the closest result edge below it is on
+	// the ring of the containing part, or on another hole
+	// of that part.
+	parent := make([]int, len(rings))
+	holes := make([][]int, len(rings))
+	for i, ring := range rings {
+		parent[i] = -1
+		if len(ring) < 3 || r2.Area(ring) >= 0 {
+			continue
+		}
+		p := -1
+		if prev := first[i].prevInResult; prev != nil && prev.transition != 0 {
+			if j, ok := ringOf[prev]; ok {
+				p = j
+				if parent[j] >= 0 {
+					p = parent[j]
+				}
+			}
+		}
+		if p < 0 || r2.Area(rings[p]) <= 0 {
+			p = containing(rings, ring)
+		}
+		if p >= 0 {
+			parent[i] = p
+			holes[p] = append(holes[p], i)
+		}
+	}
+
+	var poly Polygon
+	for i, ring := range rings {
+		if len(ring) < 3 || r2.Area(ring) <= 0 {
+			continue
+		}
+		poly = append(poly, ring)
+		for _, h := range holes[i] {
+			poly = append(poly, rings[h])
+		}
+	}
+	return poly
+}
+
+// clockwise returns the clockwise angle at v from the direction of a to
+// the direction of w, in the interval (0, 2π].
+func clockwise(v, a, w r2.Vec) float64 {
+	d0 := a.Sub(v)
+	d := w.Sub(v)
+	cw := math.Atan2(d.X*d0.Y-d.Y*d0.X, d0.X*d.X+d0.Y*d.Y)
+	if cw <= 0 {
+		cw += 2 * math.Pi
+	}
+	return cw
+}
+
+// simplify removes the vertices of ring that are collinear with their
+// neighbors, returning the modified ring.
+func simplify(ring []r2.Vec) []r2.Vec {
+	for {
+		n := len(ring)
+		k := 0
+		for i, v := range ring {
+			if r2.Orient(ring[(i+n-1)%n], v, ring[(i+1)%n]) != 0 {
+				ring[k] = v
+				k++
+			}
+		}
+		if k == n || k < 3 {
+			return ring[:k]
+		}
+		ring = ring[:k]
+	}
+}
+
+// containing returns the index of the smallest counter-clockwise ring in
+// rings that contains the clockwise ring hole, or -1 if there is none.
+func containing(rings [][]r2.Vec, hole []r2.Vec) int {
+	// The midpoint of an edge of the hole is within the
+	// containing ring, since rings do not cross and may
+	// only meet at vertices.
+	p := hole[0].Add(hole[1]).Scale(0.5)
+	best := -1
+	var min float64
+	for i, ring := range rings {
+		a := r2.Area(ring)
+		if len(ring) < 3 || a <= 0 || (best >= 0 && a >= min) {
+			continue
+		}
+		if r2.PointInPolygon(p, ring) == r2.Inside {
+			best, min = i, a
+		}
+	}
+	return best
+}
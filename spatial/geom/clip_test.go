@@ -0,0 +1,367 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func rect(x0, y0, x1, y1 float64) []r2.Vec {
+	return []r2.Vec{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}}
+}
+
+var ops = []struct {
+	name string
+	fn   func(a, b Polygon) Polygon
+}{
+	{name: "intersection", fn: Intersection},
+	{name: "union", fn: Union},
+	{name: "difference", fn: Difference},
+	{name: "xor", fn: Xor},
+}
+
+var clipTests = []struct {
+	name string
+	a, b Polygon
+
+	// areas and parts are the areas and number
+	// of outer rings of the intersection, union,
+	// difference and xor of a and b.
+	areas [4]float64
+	parts [4]int
+}{
+	{
+		name:  "overlapping squares",
+		a:     Polygon{rect(0, 0, 2, 2)},
+		b:     Polygon{rect(1, 1, 3, 3)},
+		areas: [4]float64{1, 7, 3, 6},
+		parts: [4]int{1, 1, 1, 2},
+	},
+	{
+		name:  "clockwise input",
+		a:     Polygon{reversed(rect(0, 0, 2, 2))},
+		b:     Polygon{rect(1, 1, 3, 3)},
+		areas: [4]float64{1, 7, 3, 6},
+		parts: [4]int{1, 1, 1, 2},
+	},
+	{
+		name:  "disjoint",
+		a:     Polygon{rect(0, 0, 1, 1)},
+		b:     Polygon{rect(2, 2, 3, 4)},
+		areas: [4]float64{0, 3, 1, 3},
+		parts: [4]int{0, 2, 1, 2},
+	},
+	{
+		name:  "disjoint overlapping bounds",
+		a:     Polygon{{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 4}}},
+		b:     Polygon{rect(3, 3, 5, 5)},
+		areas: [4]float64{0, 12, 8, 12},
+		parts: [4]int{0, 2, 1, 2},
+	},
+	{
+		name:  "shared edge",
+		a:     Polygon{rect(0, 0, 1, 1)},
+		b:     Polygon{rect(1, 0, 2, 1)},
+		areas: [4]float64{0, 2, 1, 2},
+		parts: [4]int{0, 1, 1, 1},
+	},
+	{
+		name:  "identical",
+		a:     Polygon{rect(0, 0, 1, 1)},
+		b:     Polygon{rect(0, 0, 1, 1)},
+		areas: [4]float64{1, 1, 0, 0},
+		parts: [4]int{1, 1, 0, 0},
+	},
+	{
+		name:  "contained",
+		a:     Polygon{rect(0, 0, 4, 4)},
+		b:     Polygon{rect(1, 1, 2, 2)},
+		areas: [4]float64{1, 16, 15, 15},
+		parts: [4]int{1, 1, 1, 1},
+	},
+	{
+		name:  "holed",
+		a:     Polygon{rect(0, 0, 4, 4), rect(1, 1, 3, 3)},
+		b:     Polygon{rect(2, 0, 6, 4)},
+		areas: [4]float64{6, 22, 6, 16},
+		parts: [4]int{1, 1, 1, 3},
+	},
+	{
+		name:  "island in hole",
+		a:     Polygon{rect(0, 0, 10, 10), rect(2, 2, 8, 8)},
+		b:     Polygon{rect(4, 4, 6, 6)},
+		areas: [4]float64{0, 68, 64, 68},
+		parts: [4]int{0, 2, 1, 2},
+	},
+	{
+		name:  "multipolygon",
+		a:     Polygon{rect(0, 0, 1, 1), rect(2, 0, 3, 1)},
+		b:     Polygon{rect(0.5, 0.25, 2.5, 0.75)},
+		areas: [4]float64{0.5, 2.5, 1.5, 2},
+		parts: [4]int{2, 1, 2, 3},
+	},
+	{
+		name:  "vertex on edge",
+		a:     Polygon{{{X: 2, Y: 3}, {X: 1, Y: 2}, {X: 4, Y: 3}}},
+		b:     Polygon{{{X: 2, Y: 2}, {X: 0, Y: 2}, {X: 4, Y: 0}}},
+		areas: [4]float64{0, 3, 1, 3},
+		parts: [4]int{0, 2, 1, 2},
+	},
+	{
+		name:  "vertex on crossed edge",
+		a:     Polygon{{{X: 2, Y: 0}, {X: 0, Y: 4}, {X: 1, Y: 4}}},
+		b:     Polygon{{{X: 3, Y: 0}, {X: 1, Y: 2}, {X: 1, Y: 1}}},
+		areas: [4]float64{2.0 / 7, 19.0 / 7, 12.0 / 7, 17.0 / 7},
+		parts: [4]int{1, 1, 2, 4},
+	},
+	{
+		name:  "vertex on horizontal edge",
+		a:     Polygon{{{X: 3, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 0}}},
+		b:     Polygon{{{X: 2, Y: 0}, {X: 1, Y: 4}, {X: 3, Y: 3}}},
+		areas: [4]float64{1.4, 8.1, 4.6, 6.7},
+		parts: [4]int{1, 1, 2, 3},
+	},
+	{
+		name:  "vertex on edge inside",
+		a:     Polygon{{{X: 1, Y: 0}, {X: 2, Y: 1}, {X: 3, Y: 3}, {X: 1, Y: 2}}},
+		b:     Polygon{{{X: 4, Y: 0}, {X: 4, Y: 2}, {X: 0, Y: 0}}},
+		areas: [4]float64{0.25, 6.25, 2.25, 6},
+		parts: [4]int{1, 1, 1, 3},
+	},
+	{
+		name:  "vertices on edges",
+		a:     Polygon{{{X: 4, Y: 4}, {X: 1, Y: 4}, {X: 0, Y: 3}, {X: 2, Y: 1}}},
+		b:     Polygon{{{X: 4, Y: 3}, {X: 1, Y: 2}, {X: 0, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}}},
+		areas: [4]float64{181.0 / 84, 575.0 / 84, 365.0 / 84, 394.0 / 84},
+		parts: [4]int{1, 1, 3, 5},
+	},
+}
+
+func reversed(p []r2.Vec) []r2.Vec {
+	r := make([]r2.Vec, len(p))
+	for i, v := range p {
+		r[len(p)-1-i] = v
+	}
+	return r
+}
+
+func TestClip(t *testing.T) {
+	for _, test := range clipTests {
+		for i, op := range ops {
+			got := op.fn(test.a, test.b)
+			checkPolygon(t, test.name+" "+op.name, got)
+			if a := Area(got); math.Abs(a-test.areas[i]) > 1e-12 {
+				t.Errorf("unexpected area of %s for %s: got:%v want:%v", op.name, test.name, a, test.areas[i])
+			}
+			var parts int
+			for _, ring := range got {
+				if r2.Area(ring) > 0 {
+					parts++
+				}
+			}
+			if parts != test.parts[i] {
+				t.Errorf("unexpected number of parts of %s for %s: got:%d want:%d", op.name, test.name, parts, test.parts[i])
+			}
+		}
+	}
+}
+
+// checkPolygon checks that each outer ring of p is counter-clockwise and
+// is followed by its clockwise holes, and that the vertices of the holes
+// are within the preceding outer ring.
+func checkPolygon(t *testing.T, name string, p Polygon) {
+	t.Helper()
+	var outer []r2.Vec
+	for i, ring := range p {
+		if len(ring) < 3 {
+			t.Errorf("%s: ring %d has %d vertices", name, i, len(ring))
+			continue
+		}
+		a := r2.Area(ring)
+		switch {
+		case a > 0:
+			outer = ring
+		case a < 0:
+			if outer == nil {
+				t.Errorf("%s: hole %d precedes any outer ring", name, i)
+				continue
+			}
+			for _, v := range ring {
+				if !within(outer, v) {
+					t.Errorf("%s: vertex %v of hole %d is outside its outer ring", name, v, i)
+					break
+				}
+			}
+		default:
+			t.Errorf("%s: ring %d has zero area", name, i)
+		}
+	}
+}
+
+// within returns whether p is within or on the boundary of the ring.
+func within(ring []r2.Vec, p r2.Vec) bool {
+	return r2.PointInPolygon(p, ring) != r2.Outside
+}
+
+// star returns a random simple polygon star-shaped about c.
+func star(rnd *rand.Rand, c r2.Vec, n int) []r2.Vec {
+	p := make([]r2.Vec, n)
+	for i := range p {
+		s, co := math.Sincos(2 * math.Pi * (float64(i) + 0.8*rnd.Float64()) / float64(n))
+		r := 0.2 + rnd.Float64()
+		p[i] = c.Add(r2.Vec{X: co, Y: s}.Scale(r))
+	}
+	return p
+}
+
+func TestClipRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		a := Polygon{star(rnd, r2.Vec{}, 3+rnd.Intn(30))}
+		b := Polygon{star(rnd, r2.Vec{X: rnd.NormFloat64(), Y: rnd.NormFloat64()}, 3+rnd.Intn(30))}
+		if rnd.Intn(2) == 0 {
+			// Add a disjoint part to b.
+			b = append(b, star(rnd, r2.Vec{X: 5, Y: 5}, 3+rnd.Intn(10)))
+		}
+
+		var areas [4]float64
+		for j, op := range ops {
+			got := op.fn(a, b)
+			checkPolygon(t, op.name, got)
+			areas[j] = Area(got)
+		}
+		inter, union, diff, xor := areas[0], areas[1], areas[2], areas[3]
+		// The rings of a and b are disjoint, but
+		// may be in either orientation.
+		var areaA, areaB float64
+		for _, ring := range a {
+			areaA += math.Abs(r2.Area(ring))
+		}
+		for _, ring := range b {
+			areaB += math.Abs(r2.Area(ring))
+		}
+		const tol = 1e-10
+		if math.Abs(union-(areaA+areaB-inter)) > tol {
+			t.Errorf("test %d: union area mismatch: got:%v want:%v", i, union, areaA+areaB-inter)
+		}
+		if math.Abs(diff-(areaA-inter)) > tol {
+			t.Errorf("test %d: difference area mismatch: got:%v want:%v", i, diff, areaA-inter)
+		}
+		if math.Abs(xor-(union-inter)) > tol {
+			t.Errorf("test %d: xor area mismatch: got:%v want:%v", i, xor, union-inter)
+		}
+		if inter < -tol || inter > math.Min(areaA, areaB)+tol {
+			t.Errorf("test %d: intersection area out of range: %v", i, inter)
+		}
+	}
+}
+
+func TestClipGrid(t *testing.T) {
+	// Rectangles with integer vertices have many
+	// overlapping edges and shared vertices. The
+	// areas of the results are checked by counting
+	// the unit cells within the operands.
+	const size = 6
+	rnd := rand.New(rand.NewSource(1))
+	randRect := func() Polygon {
+		x0, x1 := rnd.Intn(size), rnd.Intn(size)
+		y0, y1 := rnd.Intn(size), rnd.Intn(size)
+		if x0 > x1 {
+			x0, x1 = x1, x0
+		}
+		if y0 > y1 {
+			y0, y1 = y1, y0
+		}
+		x1++
+		y1++
+		p := Polygon{rect(float64(x0), float64(y0), float64(x1), float64(y1))}
+		if x1-x0 > 2 && y1-y0 > 2 && rnd.Intn(2) == 0 {
+			// Add a hole.
+			p = append(p, rect(float64(x0+1), float64(y0+1), float64(x1-1), float64(y1-1)))
+		}
+		return p
+	}
+	for i := 0; i < 200; i++ {
+		a, b := randRect(), randRect()
+		var want [4]float64
+		for x := 0; x < size+1; x++ {
+			for y := 0; y < size+1; y++ {
+				c := r2.Vec{X: float64(x) + 0.5, Y: float64(y) + 0.5}
+				inA, inB := inPolygon(a, c), inPolygon(b, c)
+				for j, in := range [4]bool{inA && inB, inA || inB, inA && !inB, inA != inB} {
+					if in {
+						want[j]++
+					}
+				}
+			}
+		}
+		for j, op := range ops {
+			got := op.fn(a, b)
+			checkPolygon(t, op.name, got)
+			if area := Area(got); area != want[j] {
+				t.Errorf("test %d: unexpected area of %s of %v and %v: got:%v want:%v", i, op.name, a, b, area, want[j])
+			}
+		}
+	}
+}
+
+func TestClipTriangles(t *testing.T) {
+	// Triangles with integer vertices on a small
+	// grid often have vertices on the edges of the
+	// other triangle.
+	const size = 5
+	rnd := rand.New(rand.NewSource(1))
+	randTri := func() Polygon {
+		for {
+			p := make([]r2.Vec, 3)
+			for i := range p {
+				p[i] = r2.Vec{X: float64(rnd.Intn(size)), Y: float64(rnd.Intn(size))}
+			}
+			if r2.Area(p) != 0 {
+				return Polygon{p}
+			}
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		a, b := randTri(), randTri()
+		var areas [4]float64
+		for j, op := range ops {
+			got := op.fn(a, b)
+			checkPolygon(t, op.name, got)
+			areas[j] = Area(got)
+		}
+		inter, union, diff, xor := areas[0], areas[1], areas[2], areas[3]
+		areaA := math.Abs(r2.Area(a[0]))
+		areaB := math.Abs(r2.Area(b[0]))
+		const tol = 1e-10
+		if math.Abs(union-(areaA+areaB-inter)) > tol {
+			t.Errorf("test %d: union area mismatch for %v and %v: got:%v want:%v", i, a, b, union, areaA+areaB-inter)
+		}
+		if math.Abs(diff-(areaA-inter)) > tol {
+			t.Errorf("test %d: difference area mismatch for %v and %v: got:%v want:%v", i, a, b, diff, areaA-inter)
+		}
+		if math.Abs(xor-(union-inter)) > tol {
+			t.Errorf("test %d: xor area mismatch for %v and %v: got:%v want:%v", i, a, b, xor, union-inter)
+		}
+		if inter < -tol || inter > math.Min(areaA, areaB)+tol {
+			t.Errorf("test %d: intersection area out of range for %v and %v: %v", i, a, b, inter)
+		}
+	}
+}
+
+func inPolygon(p Polygon, c r2.Vec) bool {
+	var in bool
+	for _, ring := range p {
+		if r2.PointInPolygon(c, ring) == r2.Inside {
+			in = !in
+		}
+	}
+	return in
+}
@@ -0,0 +1,15 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package geom provides geometric operations on polygons and line segments
+// in the plane, including boolean operations for polygon clipping and the
+// intersection of sets of segments.
+//
+// The boolean operations use the algorithm of Martínez, Rueda and Feito,
+// described in https://doi.org/10.1016/j.advengsoft.2013.04.004. The
+// operands may have holes, disjoint parts and edges that overlap edges of
+// the other operand, but the edges of a single operand must not overlap
+// each other. Vertices created at the intersections of edges are rounded
+// to the nearest representable points.
+package geom // import "gonum.org/v1/gonum/spatial/geom"
@@ -0,0 +1,35 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geom_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/spatial/geom"
+)
+
+func Example() {
+	square := geom.Polygon{{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}, {X: 0, Y: 2}}}
+	diamond := geom.Polygon{{{X: 2, Y: 0}, {X: 3, Y: 1}, {X: 2, Y: 2}, {X: 1, Y: 1}}}
+
+	for _, op := range []struct {
+		name string
+		fn   func(a, b geom.Polygon) geom.Polygon
+	}{
+		{name: "intersection", fn: geom.Intersection},
+		{name: "union", fn: geom.Union},
+		{name: "difference", fn: geom.Difference},
+		{name: "xor", fn: geom.Xor},
+	} {
+		p := op.fn(square, diamond)
+		fmt.Printf("%s: area=%.1f rings=%d centroid=%.3f\n", op.name, geom.Area(p), len(p), geom.Centroid(p))
+	}
+
+	// Output:
+	// intersection: area=1.0 rings=1 centroid={1.667 1.000}
+	// union: area=5.0 rings=1 centroid={1.267 1.000}
+	// difference: area=3.0 rings=1 centroid={0.778 1.000}
+	// xor: area=4.0 rings=2 centroid={1.167 1.000}
+}
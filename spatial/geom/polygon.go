@@ -0,0 +1,65 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// Polygon is a region of the plane bounded by a set of rings. Each ring is
+// a closed chain of vertices, with the last vertex joined to the first. A
+// point is within the polygon if a ray from the point crosses the rings an
+// odd number of times, so a polygon may hold holes and disjoint parts.
+//
+// The rings of polygons returned by the boolean operations of this package
+// do not cross each other. The outer ring of each part is in
+// counter-clockwise order and is followed by the rings of its holes in
+// clockwise order.
+type Polygon [][]r2.Vec
+
+// Area returns the sum of the signed areas of the rings of p. For polygons
+// with outer rings in counter-clockwise order and holes in clockwise order,
+// such as those returned by the boolean operations, and whose rings do not
+// cross, Area returns the area of the polygon.
+func Area(p Polygon) float64 {
+	var a float64
+	for _, ring := range p {
+		a += r2.Area(ring)
+	}
+	return a
+}
+
+// Centroid returns the centroid of p, treating the rings of p as described
+// for Area. If the area of p is zero, Centroid returns NaN coordinates.
+func Centroid(p Polygon) r2.Vec {
+	var (
+		o r2.Vec
+		c r2.Vec
+		a float64
+	)
+	for _, ring := range p {
+		if len(ring) != 0 {
+			// Work relative to a vertex to
+			// reduce cancellation.
+			o = ring[0]
+			break
+		}
+	}
+	for _, ring := range p {
+		for i := range ring {
+			u := ring[i].Sub(o)
+			v := ring[(i+1)%len(ring)].Sub(o)
+			w := u.X*v.Y - v.X*u.Y
+			a += w
+			c = c.Add(u.Add(v).Scale(w))
+		}
+	}
+	if a == 0 {
+		return r2.Vec{X: math.NaN(), Y: math.NaN()}
+	}
+	return o.Add(c.Scale(1 / (3 * a)))
+}
@@ -0,0 +1,64 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func TestAreaCentroid(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		p        Polygon
+		area     float64
+		centroid r2.Vec
+	}{
+		{
+			name:     "square",
+			p:        Polygon{rect(0, 0, 2, 2)},
+			area:     4,
+			centroid: r2.Vec{X: 1, Y: 1},
+		},
+		{
+			name:     "triangle",
+			p:        Polygon{{{X: 0, Y: 0}, {X: 3, Y: 0}, {X: 0, Y: 3}}},
+			area:     4.5,
+			centroid: r2.Vec{X: 1, Y: 1},
+		},
+		{
+			name:     "holed",
+			p:        Polygon{rect(0, 0, 4, 4), reversed(rect(0, 0, 2, 2))},
+			area:     12,
+			centroid: r2.Vec{X: 14.0 / 6, Y: 14.0 / 6},
+		},
+		{
+			name:     "parts",
+			p:        Polygon{rect(0, 0, 1, 1), rect(3, 0, 4, 1)},
+			area:     2,
+			centroid: r2.Vec{X: 2, Y: 0.5},
+		},
+		{
+			name:     "distant",
+			p:        Polygon{rect(1e8, 1e8, 1e8+1, 1e8+1)},
+			area:     1,
+			centroid: r2.Vec{X: 1e8 + 0.5, Y: 1e8 + 0.5},
+		},
+	} {
+		if got := Area(test.p); math.Abs(got-test.area) > 1e-12 {
+			t.Errorf("unexpected area for %s: got:%v want:%v", test.name, got, test.area)
+		}
+		got := Centroid(test.p)
+		if math.Abs(got.X-test.centroid.X) > 1e-12 || math.Abs(got.Y-test.centroid.Y) > 1e-12 {
+			t.Errorf("unexpected centroid for %s: got:%v want:%v", test.name, got, test.centroid)
+		}
+	}
+
+	if c := Centroid(nil); !math.IsNaN(c.X) || !math.IsNaN(c.Y) {
+		t.Errorf("unexpected centroid for empty polygon: %v", c)
+	}
+}
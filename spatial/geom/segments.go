@@ -0,0 +1,326 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"container/heap"
+	"math"
+	"math/big"
+	"sort"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// Segment is a line segment in the plane between the end points A and B.
+type Segment struct {
+	A, B r2.Vec
+}
+
+// Crossing is a point where two or more segments intersect.
+type Crossing struct {
+	// Point is the intersection point, rounded
+	// to the nearest representable point.
+	Point r2.Vec
+
+	// Segments holds the indices of the
+	// segments that intersect at Point in
+	// ascending order.
+	Segments []int
+}
+
+// Crossings returns the points where the segments in s intersect, in order
+// of increasing X and then Y coordinate of the exact intersection points.
+// Segments intersect where they cross, where an end point of one segment
+// is on another, and where they share end points. Segments that overlap
+// along a line are reported as intersecting at the two end points of the
+// overlap. A segment with coincident end points is a single point. The
+// number of intersecting pairs of segments at a crossing c is
+// len(c.Segments)*(len(c.Segments)-1)/2.
+//
+// Crossings uses the Bentley–Ottmann sweep line algorithm, and event
+// points and the order of segments on the sweep line are determined
+// exactly, so the reported crossings are exact up to the rounding of the
+// intersection points. Crossings will panic if any end point is not
+// finite.
+func Crossings(s []Segment) []Crossing {
+	segs := make([]sweepSegment, len(s))
+	q := crossingQueue{events: make(map[string]*crossingEvent)}
+	for i, seg := range s {
+		a, b := seg.A, seg.B
+		if !isFinite(a) || !isFinite(b) {
+			panic("geom: segment end point not finite")
+		}
+		if b.X < a.X || (b.X == a.X && b.Y < a.Y) {
+			a, b = b, a
+		}
+		segs[i] = sweepSegment{
+			idx:   i,
+			left:  a,
+			right: b,
+			rl:    ratOf(a),
+			rr:    ratOf(b),
+		}
+		e := q.add(segs[i].rl)
+		e.starts = append(e.starts, i)
+		q.add(segs[i].rr)
+	}
+
+	var (
+		status []*sweepSegment
+		out    []Crossing
+	)
+	for q.Len() != 0 {
+		e := heap.Pop(&q).(*crossingEvent)
+
+		// The segments on the sweep line that contain
+		// the event point are held contiguously, after
+		// the segments below the point.
+		lo := sort.Search(len(status), func(i int) bool { return e.orient(status[i]) <= 0 })
+		hi := lo + sort.Search(len(status)-lo, func(i int) bool { return e.orient(status[lo+i]) < 0 })
+
+		var (
+			at    []int
+			cross []*sweepSegment
+		)
+		for _, ss := range status[lo:hi] {
+			at = append(at, ss.idx)
+			if !e.exact || ss.right != e.p {
+				// The segment continues beyond the event.
+				cross = append(cross, ss)
+			}
+		}
+		for _, i := range e.starts {
+			at = append(at, i)
+			if segs[i].left != segs[i].right {
+				cross = append(cross, &segs[i])
+			}
+		}
+		if len(at) > 1 {
+			sort.Ints(at)
+			out = append(out, Crossing{Point: e.p, Segments: at})
+		}
+
+		// Replace the segments containing the event point
+		// with those that continue beyond it, in their order
+		// immediately to the right of the sweep line.
+		sort.Slice(cross, func(i, j int) bool {
+			c := slopeCmp(cross[i], cross[j])
+			if c != 0 {
+				return c < 0
+			}
+			return cross[i].idx < cross[j].idx
+		})
+		status = replace(status, lo, hi, cross)
+
+		// Check for intersections between the new
+		// neighbors on the sweep line.
+		if len(cross) == 0 {
+			if lo > 0 && lo < len(status) {
+				q.check(status[lo-1], status[lo], e)
+			}
+			continue
+		}
+		if lo > 0 {
+			q.check(status[lo-1], status[lo], e)
+		}
+		if j := lo + len(cross); j < len(status) {
+			q.check(status[j-1], status[j], e)
+		}
+	}
+	return out
+}
+
+func isFinite(v r2.Vec) bool {
+	return !math.IsInf(v.X, 0) && !math.IsNaN(v.X) && !math.IsInf(v.Y, 0) && !math.IsNaN(v.Y)
+}
+
+// replace returns s with the elements in s[lo:hi] replaced by the elements
+// of r.
+func replace(s []*sweepSegment, lo, hi int, r []*sweepSegment) []*sweepSegment {
+	n := len(s) - (hi - lo) + len(r)
+	if n > cap(s) {
+		t := make([]*sweepSegment, n, 2*n)
+		copy(t, s[:lo])
+		copy(t[lo+len(r):], s[hi:])
+		copy(t[lo:], r)
+		return t
+	}
+	tail := len(s) - hi
+	s = s[:n]
+	copy(s[lo+len(r):], s[hi:hi+tail])
+	copy(s[lo:], r)
+	return s
+}
+
+// sweepSegment is a segment directed from its lexicographically lower end
+// point to its upper end point, in both floating point and exact rational
+// representations.
+type sweepSegment struct {
+	idx         int
+	left, right r2.Vec
+	rl, rr      ratVec
+}
+
+// ratVec is an exact representation of a point in the plane.
+type ratVec struct {
+	x, y big.Rat
+}
+
+func ratOf(v r2.Vec) ratVec {
+	var r ratVec
+	r.x.SetFloat64(v.X)
+	r.y.SetFloat64(v.Y)
+	return r
+}
+
+// cmp returns the lexicographic order of p and q by X and then Y.
+func (p *ratVec) cmp(q *ratVec) int {
+	if c := p.x.Cmp(&q.x); c != 0 {
+		return c
+	}
+	return p.y.Cmp(&q.y)
+}
+
+// key returns a string that uniquely identifies the point p.
+func (p *ratVec) key() string {
+	return p.x.RatString() + "," + p.y.RatString()
+}
+
+// orientRat returns the sign of the orientation of a, b and c.
+func orientRat(a, b, c *ratVec) int {
+	var bx, by, cx, cy big.Rat
+	bx.Sub(&b.x, &a.x)
+	by.Sub(&b.y, &a.y)
+	cx.Sub(&c.x, &a.x)
+	cy.Sub(&c.y, &a.y)
+	return bx.Mul(&bx, &cy).Cmp(by.Mul(&by, &cx))
+}
+
+// direction returns the exact direction vector of s.
+func (s *sweepSegment) direction() (dx, dy big.Rat) {
+	dx.Sub(&s.rr.x, &s.rl.x)
+	dy.Sub(&s.rr.y, &s.rl.y)
+	return dx, dy
+}
+
+// slopeCmp returns the order of the slopes of a and b. Since segments are
+// directed to the right or vertically upward, a vertical segment has the
+// greatest slope.
+func slopeCmp(a, b *sweepSegment) int {
+	ax, ay := a.direction()
+	bx, by := b.direction()
+	// The cross product of the directions is positive
+	// if b has the greater slope.
+	return ay.Mul(&ay, &bx).Cmp(by.Mul(&by, &ax))
+}
+
+// crossingEvent is an event point of the segment intersection sweep.
+type crossingEvent struct {
+	r ratVec
+
+	// p is the event point rounded to the nearest
+	// representable point, and exact is whether the
+	// rounding was exact.
+	p     r2.Vec
+	exact bool
+
+	// starts holds the indices of the segments
+	// with their left end point at the event.
+	starts []int
+}
+
+// orient returns the sign of the orientation of the event point relative
+// to the segment s. It is positive if the point is above the line through
+// s, negative if it is below and zero if it is on the line.
+func (e *crossingEvent) orient(s *sweepSegment) int {
+	if e.exact {
+		o := r2.Orient(s.left, s.right, e.p)
+		switch {
+		case o > 0:
+			return 1
+		case o < 0:
+			return -1
+		}
+		return 0
+	}
+	return orientRat(&s.rl, &s.rr, &e.r)
+}
+
+// crossingQueue is a priority queue of event points ordered by X and then
+// Y coordinate, with a single event for each point.
+type crossingQueue struct {
+	heap   []*crossingEvent
+	events map[string]*crossingEvent
+}
+
+func (q crossingQueue) Len() int { return len(q.heap) }
+func (q crossingQueue) Less(i, j int) bool {
+	a, b := q.heap[i], q.heap[j]
+	if a.exact && b.exact {
+		if a.p.X != b.p.X {
+			return a.p.X < b.p.X
+		}
+		return a.p.Y < b.p.Y
+	}
+	return a.r.cmp(&b.r) < 0
+}
+func (q crossingQueue) Swap(i, j int)       { q.heap[i], q.heap[j] = q.heap[j], q.heap[i] }
+func (q *crossingQueue) Push(x interface{}) { q.heap = append(q.heap, x.(*crossingEvent)) }
+func (q *crossingQueue) Pop() interface{} {
+	e := q.heap[len(q.heap)-1]
+	q.heap = q.heap[:len(q.heap)-1]
+	delete(q.events, e.r.key())
+	return e
+}
+
+// add returns the event for the point p, adding it to the queue if it is
+// not already present.
+func (q *crossingQueue) add(p ratVec) *crossingEvent {
+	k := p.key()
+	if e, ok := q.events[k]; ok {
+		return e
+	}
+	e := &crossingEvent{r: p}
+	x, xExact := p.x.Float64()
+	y, yExact := p.y.Float64()
+	e.p = r2.Vec{X: x, Y: y}
+	e.exact = xExact && yExact
+	q.events[k] = e
+	heap.Push(q, e)
+	return e
+}
+
+// check adds the intersection point of a and b to the queue if they cross
+// at a single point to the right of the sweep line at the event e.
+func (q *crossingQueue) check(a, b *sweepSegment, e *crossingEvent) {
+	if !r2.SegmentsIntersect(a.left, a.right, b.left, b.right) {
+		return
+	}
+	ax, ay := a.direction()
+	bx, by := b.direction()
+	var den, t big.Rat
+	den.Mul(&ax, &by)
+	den.Sub(&den, t.Mul(&ay, &bx))
+	if den.Sign() == 0 {
+		// The segments are collinear, and their
+		// intersections are at end points.
+		return
+	}
+
+	// The intersection is at a.rl + s*(ax, ay) with
+	// s = ((b.rl - a.rl) × (bx, by)) / ((ax, ay) × (bx, by)).
+	var dx, dy, num, s big.Rat
+	dx.Sub(&b.rl.x, &a.rl.x)
+	dy.Sub(&b.rl.y, &a.rl.y)
+	num.Sub(dx.Mul(&dx, &by), dy.Mul(&dy, &bx))
+	s.Quo(&num, &den)
+	var p ratVec
+	p.x.Add(&a.rl.x, ax.Mul(&ax, &s))
+	p.y.Add(&a.rl.y, ay.Mul(&ay, &s))
+	if p.cmp(&e.r) <= 0 {
+		return
+	}
+	q.add(p)
+}
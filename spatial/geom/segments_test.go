@@ -0,0 +1,247 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geom
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func seg(ax, ay, bx, by float64) Segment {
+	return Segment{A: r2.Vec{X: ax, Y: ay}, B: r2.Vec{X: bx, Y: by}}
+}
+
+var crossingsTests = []struct {
+	name string
+	s    []Segment
+	want []Crossing
+}{
+	{
+		name: "empty",
+	},
+	{
+		name: "disjoint",
+		s:    []Segment{seg(0, 0, 1, 1), seg(0, 1, 1, 2), seg(2, 0, 3, 0)},
+	},
+	{
+		name: "cross",
+		s:    []Segment{seg(0, 0, 2, 2), seg(0, 2, 2, 0)},
+		want: []Crossing{{Point: r2.Vec{X: 1, Y: 1}, Segments: []int{0, 1}}},
+	},
+	{
+		name: "vertical and horizontal",
+		s:    []Segment{seg(1, 0, 1, 2), seg(0, 1, 2, 1), seg(3, 0, 3, 2)},
+		want: []Crossing{{Point: r2.Vec{X: 1, Y: 1}, Segments: []int{0, 1}}},
+	},
+	{
+		name: "shared end point",
+		s:    []Segment{seg(0, 0, 1, 1), seg(1, 1, 2, 0), seg(1, 1, 1, 3)},
+		want: []Crossing{{Point: r2.Vec{X: 1, Y: 1}, Segments: []int{0, 1, 2}}},
+	},
+	{
+		name: "touching",
+		s:    []Segment{seg(0, 0, 2, 0), seg(1, 0, 1, 1)},
+		want: []Crossing{{Point: r2.Vec{X: 1, Y: 0}, Segments: []int{0, 1}}},
+	},
+	{
+		name: "three through a point",
+		s:    []Segment{seg(0, 0, 2, 2), seg(0, 2, 2, 0), seg(0, 1, 2, 1), seg(1, 0, 1, 2)},
+		want: []Crossing{{Point: r2.Vec{X: 1, Y: 1}, Segments: []int{0, 1, 2, 3}}},
+	},
+	{
+		name: "overlapping",
+		s:    []Segment{seg(0, 0, 2, 0), seg(3, 0, 1, 0), seg(1.5, -1, 1.5, 1)},
+		want: []Crossing{
+			{Point: r2.Vec{X: 1, Y: 0}, Segments: []int{0, 1}},
+			{Point: r2.Vec{X: 1.5, Y: 0}, Segments: []int{0, 1, 2}},
+			{Point: r2.Vec{X: 2, Y: 0}, Segments: []int{0, 1}},
+		},
+	},
+	{
+		name: "overlapping vertical",
+		s:    []Segment{seg(0, 0, 0, 2), seg(0, 1, 0, 3)},
+		want: []Crossing{
+			{Point: r2.Vec{X: 0, Y: 1}, Segments: []int{0, 1}},
+			{Point: r2.Vec{X: 0, Y: 2}, Segments: []int{0, 1}},
+		},
+	},
+	{
+		name: "point segments",
+		s:    []Segment{seg(1, 1, 1, 1), seg(0, 0, 2, 2), seg(1, 1, 1, 1), seg(3, 3, 3, 3)},
+		want: []Crossing{{Point: r2.Vec{X: 1, Y: 1}, Segments: []int{0, 1, 2}}},
+	},
+	{
+		name: "inexact intersection",
+		s:    []Segment{seg(0, 0, 3, 1), seg(0, 1, 3, 0)},
+		want: []Crossing{{Point: r2.Vec{X: 1.5, Y: 0.5}, Segments: []int{0, 1}}},
+	},
+	{
+		name: "crossing order",
+		s:    []Segment{seg(0, 0, 10, 10), seg(0, 10, 10, 0), seg(0, 3, 10, 3), seg(0, 7, 10, 7)},
+		want: []Crossing{
+			{Point: r2.Vec{X: 3, Y: 3}, Segments: []int{0, 2}},
+			{Point: r2.Vec{X: 3, Y: 7}, Segments: []int{1, 3}},
+			{Point: r2.Vec{X: 5, Y: 5}, Segments: []int{0, 1}},
+			{Point: r2.Vec{X: 7, Y: 3}, Segments: []int{1, 2}},
+			{Point: r2.Vec{X: 7, Y: 7}, Segments: []int{0, 3}},
+		},
+	},
+}
+
+func TestCrossings(t *testing.T) {
+	for _, test := range crossingsTests {
+		got := Crossings(test.s)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected crossings for %s:\ngot: %v\nwant:%v", test.name, got, test.want)
+		}
+	}
+
+	if !panics(func() { Crossings([]Segment{seg(0, 0, 1, math.Inf(1))}) }) {
+		t.Error("expected panic for infinite end point")
+	}
+}
+
+func TestCrossingsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := 2 + rnd.Intn(30)
+		s := make([]Segment, n)
+		grid := i%2 == 0
+		for j := range s {
+			if grid {
+				// Segments on a small integer grid
+				// have many degenerate intersections.
+				s[j] = seg(float64(rnd.Intn(6)), float64(rnd.Intn(6)), float64(rnd.Intn(6)), float64(rnd.Intn(6)))
+			} else {
+				s[j] = seg(rnd.Float64(), rnd.Float64(), rnd.Float64(), rnd.Float64())
+			}
+		}
+		got := Crossings(s)
+		want := naiveCrossings(s)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected crossings for test %d:\ngot: %v\nwant:%v\nsegments:%v", i, got, want, s)
+		}
+	}
+}
+
+// naiveCrossings returns the crossings of the segments in s found by
+// testing each pair of segments.
+func naiveCrossings(s []Segment) []Crossing {
+	type point struct {
+		r ratVec
+		p r2.Vec
+		s map[int]bool
+	}
+	points := make(map[string]*point)
+	add := func(r ratVec, i, j int) {
+		k := r.key()
+		p, ok := points[k]
+		if !ok {
+			x, _ := r.x.Float64()
+			y, _ := r.y.Float64()
+			p = &point{r: r, p: r2.Vec{X: x, Y: y}, s: make(map[int]bool)}
+			points[k] = p
+		}
+		p.s[i] = true
+		p.s[j] = true
+	}
+	lower := func(a Segment) (l, u ratVec) {
+		l, u = ratOf(a.A), ratOf(a.B)
+		if u.cmp(&l) < 0 {
+			l, u = u, l
+		}
+		return l, u
+	}
+	for i, a := range s {
+		for j := i + 1; j < len(s); j++ {
+			b := s[j]
+			if !r2.SegmentsIntersect(a.A, a.B, b.A, b.B) {
+				continue
+			}
+			al, au := lower(a)
+			bl, bu := lower(b)
+			var ax, ay, bx, by, den, t big.Rat
+			ax.Sub(&au.x, &al.x)
+			ay.Sub(&au.y, &al.y)
+			bx.Sub(&bu.x, &bl.x)
+			by.Sub(&bu.y, &bl.y)
+			den.Mul(&ax, &by)
+			den.Sub(&den, t.Mul(&ay, &bx))
+			if den.Sign() == 0 {
+				// The segments overlap, so they intersect
+				// at the greater of their lower end points
+				// and the lesser of their upper end points.
+				lo, hi := al, au
+				if bl.cmp(&lo) > 0 {
+					lo = bl
+				}
+				if bu.cmp(&hi) < 0 {
+					hi = bu
+				}
+				add(lo, i, j)
+				add(hi, i, j)
+				continue
+			}
+			var dx, dy, num, f big.Rat
+			dx.Sub(&bl.x, &al.x)
+			dy.Sub(&bl.y, &al.y)
+			num.Sub(dx.Mul(&dx, &by), dy.Mul(&dy, &bx))
+			f.Quo(&num, &den)
+			var p ratVec
+			p.x.Add(&al.x, ax.Mul(&ax, &f))
+			p.y.Add(&al.y, ay.Mul(&ay, &f))
+			add(p, i, j)
+		}
+	}
+	var sorted []*point
+	for _, p := range points {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].r.cmp(&sorted[j].r) < 0 })
+	var c []Crossing
+	for _, p := range sorted {
+		idx := make([]int, 0, len(p.s))
+		for i := range p.s {
+			idx = append(idx, i)
+		}
+		sort.Ints(idx)
+		c = append(c, Crossing{Point: p.p, Segments: idx})
+	}
+	return c
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
+
+func BenchmarkCrossings(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		rnd := rand.New(rand.NewSource(1))
+		s := make([]Segment, n)
+		for i := range s {
+			// Short segments with a number of
+			// crossings proportional to n.
+			x, y := rnd.Float64(), rnd.Float64()
+			s[i] = seg(x, y, x+rnd.NormFloat64()/float64(n)*10, y+rnd.NormFloat64()/float64(n)*10)
+		}
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Crossings(s)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hashgrid implements uniform spatial hash grids in 2D and 3D.
+//
+// A hash grid divides space into cells of a fixed size and holds the
+// points in each occupied cell in a hash table keyed by the cell. Points
+// can be inserted, moved and removed in constant time, so a grid can
+// follow a large number of moving points without being rebuilt. Queries
+// for the points within a radius are efficient when the radius is
+// comparable to the cell size, making hash grids a lighter-weight
+// alternative to trees for short-range interactions such as the local
+// forces in force-directed graph layouts.
+//
+// See https://en.wikipedia.org/wiki/Spatial_hashing for more details.
+package hashgrid // import "gonum.org/v1/gonum/spatial/hashgrid"
@@ -0,0 +1,42 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hashgrid_test
+
+import (
+	"fmt"
+
+	"gonum.org/v1/gonum/spatial/hashgrid"
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func ExampleGrid2() {
+	// Place points on a line and let them drift apart,
+	// counting the pairs closer than the cell size.
+	const r = 1
+	g := hashgrid.NewGrid2(r)
+	pos := make([]r2.Vec, 10)
+	for i := range pos {
+		pos[i] = r2.Vec{X: 0.5 * float64(i)}
+		g.Insert(pos[i])
+	}
+	for step := 0; step < 3; step++ {
+		var n int
+		g.DoPairs(r, func(i, j int) bool {
+			n++
+			return false
+		})
+		fmt.Printf("step %d: %d close pairs\n", step, n)
+
+		for id, p := range pos {
+			pos[id] = p.Scale(1.5)
+			g.Move(id, pos[id])
+		}
+	}
+
+	// Output:
+	// step 0: 17 close pairs
+	// step 1: 9 close pairs
+	// step 2: 0 close pairs
+}
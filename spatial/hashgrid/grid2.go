@@ -0,0 +1,277 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hashgrid
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// Grid2 is a uniform spatial hash grid of points in a plane. Points are
+// identified by the integer IDs returned by Insert.
+type Grid2 struct {
+	size, inv float64
+
+	ids
+	pos []r2.Vec
+
+	// index maps occupied cells to their
+	// buckets. Empty buckets retain their
+	// storage and are listed in spare.
+	index   map[cell2]int
+	buckets []bucket2
+	spare   []int
+}
+
+// cell2 is the coordinate of a cell of a Grid2.
+type cell2 struct {
+	x, y int64
+}
+
+// bucket2 holds the IDs of the points in a cell of a Grid2.
+type bucket2 struct {
+	cell cell2
+	ids  []int
+}
+
+// NewGrid2 returns an empty grid with square cells of the given side
+// length. NewGrid2 panics if size is not positive and finite.
+func NewGrid2(size float64) *Grid2 {
+	if !(0 < size && size <= math.MaxFloat64) {
+		panic("hashgrid: cell size must be positive and finite")
+	}
+	return &Grid2{size: size, inv: 1 / size, index: make(map[cell2]int)}
+}
+
+// CellSize returns the side length of the cells of the grid.
+func (g *Grid2) CellSize() float64 { return g.size }
+
+// Len returns the number of points in the grid.
+func (g *Grid2) Len() int { return len(g.entries) - len(g.free) }
+
+// Insert adds the point p to the grid and returns its ID. IDs of removed
+// points are reused. Insert panics if p is not finite or is too far from
+// the origin for its cell to be represented.
+func (g *Grid2) Insert(p r2.Vec) int {
+	c := g.cellOf(p)
+	id := g.alloc()
+	if id == len(g.pos) {
+		g.pos = append(g.pos, p)
+	} else {
+		g.pos[id] = p
+	}
+	g.attach(id, c)
+	return id
+}
+
+// Move moves the point with the given ID to p. Moving a point within its
+// cell only updates its position. Move panics if id is not the ID of a
+// point in the grid or if p is not a valid location for Insert.
+func (g *Grid2) Move(id int, p r2.Vec) {
+	g.check(id)
+	c := g.cellOf(p)
+	g.pos[id] = p
+	if g.buckets[g.entries[id].bucket].cell == c {
+		return
+	}
+	g.detach(id)
+	g.attach(id, c)
+}
+
+// Remove removes the point with the given ID from the grid. Remove panics
+// if id is not the ID of a point in the grid.
+func (g *Grid2) Remove(id int) {
+	g.check(id)
+	g.detach(id)
+	g.release(id)
+}
+
+// Pos returns the position of the point with the given ID. Pos panics if
+// id is not the ID of a point in the grid.
+func (g *Grid2) Pos(id int) r2.Vec {
+	g.check(id)
+	return g.pos[id]
+}
+
+// Reset removes all the points from the grid, retaining allocated storage
+// for reuse.
+func (g *Grid2) Reset() {
+	g.reset()
+	g.pos = g.pos[:0]
+	for c := range g.index {
+		delete(g.index, c)
+	}
+	g.spare = g.spare[:0]
+	for i := range g.buckets {
+		g.buckets[i].ids = g.buckets[i].ids[:0]
+		g.spare = append(g.spare, i)
+	}
+}
+
+func (g *Grid2) cellOf(p r2.Vec) cell2 {
+	return cell2{x: cellOf(p.X, g.inv), y: cellOf(p.Y, g.inv)}
+}
+
+// attach adds id to the bucket for the cell c.
+func (g *Grid2) attach(id int, c cell2) {
+	b, ok := g.index[c]
+	if !ok {
+		if n := len(g.spare); n != 0 {
+			b = g.spare[n-1]
+			g.spare = g.spare[:n-1]
+			g.buckets[b].cell = c
+		} else {
+			b = len(g.buckets)
+			g.buckets = append(g.buckets, bucket2{cell: c})
+		}
+		g.index[c] = b
+	}
+	g.entries[id] = entry{bucket: b, slot: len(g.buckets[b].ids)}
+	g.buckets[b].ids = append(g.buckets[b].ids, id)
+}
+
+// detach removes id from its bucket.
+func (g *Grid2) detach(id int) {
+	e := g.entries[id]
+	bucket := &g.buckets[e.bucket]
+	last := len(bucket.ids) - 1
+	moved := bucket.ids[last]
+	bucket.ids[e.slot] = moved
+	g.entries[moved].slot = e.slot
+	bucket.ids = bucket.ids[:last]
+	if last == 0 {
+		delete(g.index, bucket.cell)
+		g.spare = append(g.spare, e.bucket)
+	}
+}
+
+// DoWithin calls fn on the ID of each point in the grid within distance r
+// of q, including those at distance r, until fn returns true. DoWithin
+// returns whether fn returned true. The points are visited in no
+// particular order. The grid must not be altered by fn.
+//
+// DoWithin examines the cells overlapping the square of side 2r centered
+// on q, or all the occupied cells if there are fewer.
+func (g *Grid2) DoWithin(q r2.Vec, r float64, fn func(id int) (done bool)) bool {
+	if !(r >= 0) {
+		return false
+	}
+	lo := cell2{x: clampedCellOf(q.X-r, g.inv), y: clampedCellOf(q.Y-r, g.inv)}
+	hi := cell2{x: clampedCellOf(q.X+r, g.inv), y: clampedCellOf(q.Y+r, g.inv)}
+	rr := r * r
+	visit := func(ids []int) bool {
+		for _, id := range ids {
+			d := g.pos[id].Sub(q)
+			if d.X*d.X+d.Y*d.Y <= rr && fn(id) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if float64(hi.x-lo.x+1)*float64(hi.y-lo.y+1) > float64(len(g.index)) {
+		for _, b := range g.buckets {
+			c := b.cell
+			if len(b.ids) == 0 || c.x < lo.x || hi.x < c.x || c.y < lo.y || hi.y < c.y {
+				continue
+			}
+			if visit(b.ids) {
+				return true
+			}
+		}
+		return false
+	}
+	for x := lo.x; x <= hi.x; x++ {
+		for y := lo.y; y <= hi.y; y++ {
+			b, ok := g.index[cell2{x: x, y: y}]
+			if ok && visit(g.buckets[b].ids) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DoPairs calls fn on the IDs of each pair of distinct points in the grid
+// that are within distance r of each other, including those at distance r,
+// until fn returns true. DoPairs returns whether fn returned true. Each
+// pair is visited once in no particular order. The grid must not be
+// altered by fn.
+//
+// DoPairs is most efficient when r is not greater than the cell size, when
+// each cell is compared with its eight neighbors.
+func (g *Grid2) DoPairs(r float64, fn func(i, j int) (done bool)) bool {
+	if !(r >= 0) {
+		return false
+	}
+	rr := r * r
+	within := func(i, j int) bool {
+		d := g.pos[i].Sub(g.pos[j])
+		return d.X*d.X+d.Y*d.Y <= rr
+	}
+	cross := func(a, b []int) bool {
+		for _, i := range a {
+			for _, j := range b {
+				if within(i, j) && fn(i, j) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// Compare points within each cell.
+	for _, b := range g.buckets {
+		for k, i := range b.ids {
+			for _, j := range b.ids[k+1:] {
+				if within(i, j) && fn(i, j) {
+					return true
+				}
+			}
+		}
+	}
+
+	// Compare points in distinct cells that are
+	// within reach of each other.
+	k := reach(r, g.inv)
+	if n := float64(2*k + 1); n*n > float64(len(g.index)) {
+		for i, a := range g.buckets {
+			if len(a.ids) == 0 {
+				continue
+			}
+			for _, b := range g.buckets[i+1:] {
+				if len(b.ids) == 0 || abs(a.cell.x-b.cell.x) > k || abs(a.cell.y-b.cell.y) > k {
+					continue
+				}
+				if cross(a.ids, b.ids) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	for _, a := range g.buckets {
+		if len(a.ids) == 0 {
+			continue
+		}
+		// Only the half of the neighboring cells
+		// that follow a are considered so that
+		// each pair of cells is visited once.
+		for dx := int64(0); dx <= k; dx++ {
+			dy := -k
+			if dx == 0 {
+				dy = 1
+			}
+			for ; dy <= k; dy++ {
+				b, ok := g.index[cell2{x: a.cell.x + dx, y: a.cell.y + dy}]
+				if ok && cross(a.ids, g.buckets[b].ids) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
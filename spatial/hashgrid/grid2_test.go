@@ -0,0 +1,228 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hashgrid
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+func TestGrid2(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGrid2(0.5)
+	want := make(map[int]r2.Vec)
+	randVec := func() r2.Vec {
+		return r2.Vec{X: rnd.NormFloat64() * 3, Y: rnd.NormFloat64() * 3}
+	}
+	for round := 0; round < 20; round++ {
+		for i := 0; i < 50; i++ {
+			p := randVec()
+			id := g.Insert(p)
+			if _, ok := want[id]; ok {
+				t.Fatalf("reused ID of live point: %d", id)
+			}
+			want[id] = p
+		}
+		for id, p := range want {
+			switch rnd.Intn(4) {
+			case 0:
+				g.Remove(id)
+				delete(want, id)
+			case 1:
+				// Move a small distance, mostly
+				// within the same cell.
+				p = p.Add(r2.Vec{X: rnd.NormFloat64() * 0.05, Y: rnd.NormFloat64() * 0.05})
+				g.Move(id, p)
+				want[id] = p
+			case 2:
+				p = randVec()
+				g.Move(id, p)
+				want[id] = p
+			}
+		}
+
+		if g.Len() != len(want) {
+			t.Fatalf("unexpected length: got:%d want:%d", g.Len(), len(want))
+		}
+		for id, p := range want {
+			if got := g.Pos(id); got != p {
+				t.Fatalf("unexpected position of %d: got:%v want:%v", id, got, p)
+			}
+		}
+		for _, r := range []float64{0, 0.1, 0.5, 1.2, 100} {
+			for k := 0; k < 5; k++ {
+				q := randVec()
+				var got []int
+				g.DoWithin(q, r, func(id int) bool {
+					got = append(got, id)
+					return false
+				})
+				var wantIDs []int
+				for id, p := range want {
+					if d := p.Sub(q); math.Hypot(d.X, d.Y) <= r {
+						wantIDs = append(wantIDs, id)
+					}
+				}
+				if !sameInts(got, wantIDs) {
+					t.Errorf("unexpected points within %v of %v: got:%v want:%v", r, q, got, wantIDs)
+				}
+			}
+
+			var got [][2]int
+			g.DoPairs(r, func(i, j int) bool {
+				if i > j {
+					i, j = j, i
+				}
+				got = append(got, [2]int{i, j})
+				return false
+			})
+			var wantPairs [][2]int
+			for i, p := range want {
+				for j, q := range want {
+					if d := p.Sub(q); i < j && math.Hypot(d.X, d.Y) <= r {
+						wantPairs = append(wantPairs, [2]int{i, j})
+					}
+				}
+			}
+			if !samePairs(got, wantPairs) {
+				t.Errorf("unexpected pairs within %v: got %d pairs want %d", r, len(got), len(wantPairs))
+			}
+		}
+	}
+
+	g.Reset()
+	if g.Len() != 0 {
+		t.Errorf("unexpected length after reset: %d", g.Len())
+	}
+	if g.DoPairs(math.Inf(1), func(i, j int) bool { return true }) {
+		t.Error("unexpected pair after reset")
+	}
+	if id := g.Insert(r2.Vec{}); id != 0 {
+		t.Errorf("unexpected ID after reset: got:%d want:0", id)
+	}
+}
+
+func TestGrid2Done(t *testing.T) {
+	g := NewGrid2(1)
+	for i := 0; i < 10; i++ {
+		g.Insert(r2.Vec{X: float64(i) / 10})
+	}
+	var n int
+	if !g.DoWithin(r2.Vec{}, 2, func(int) bool { n++; return n == 3 }) || n != 3 {
+		t.Errorf("unexpected DoWithin termination: n=%d", n)
+	}
+	n = 0
+	if !g.DoPairs(2, func(int, int) bool { n++; return n == 3 }) || n != 3 {
+		t.Errorf("unexpected DoPairs termination: n=%d", n)
+	}
+}
+
+func TestGrid2Panics(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero size", fn: func() { NewGrid2(0) }},
+		{name: "NaN size", fn: func() { NewGrid2(math.NaN()) }},
+		{name: "infinite size", fn: func() { NewGrid2(math.Inf(1)) }},
+		{name: "NaN point", fn: func() { NewGrid2(1).Insert(r2.Vec{X: math.NaN()}) }},
+		{name: "distant point", fn: func() { NewGrid2(1e-300).Insert(r2.Vec{Y: 1}) }},
+		{name: "invalid ID", fn: func() { NewGrid2(1).Move(0, r2.Vec{}) }},
+		{name: "removed ID", fn: func() {
+			g := NewGrid2(1)
+			id := g.Insert(r2.Vec{})
+			g.Remove(id)
+			g.Pos(id)
+		}},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sort.Ints(a)
+	sort.Ints(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func samePairs(a, b [][2]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	less := func(p [][2]int) func(i, j int) bool {
+		return func(i, j int) bool {
+			if p[i][0] != p[j][0] {
+				return p[i][0] < p[j][0]
+			}
+			return p[i][1] < p[j][1]
+		}
+	}
+	sort.Slice(a, less(a))
+	sort.Slice(b, less(b))
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+func BenchmarkGrid2Move(b *testing.B) {
+	const n = 100000
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGrid2(1)
+	pos := make([]r2.Vec, n)
+	for i := range pos {
+		pos[i] = r2.Vec{X: rnd.Float64() * 300, Y: rnd.Float64() * 300}
+		g.Insert(pos[i])
+	}
+	step := make([]r2.Vec, n)
+	for i := range step {
+		step[i] = r2.Vec{X: rnd.NormFloat64() * 0.1, Y: rnd.NormFloat64() * 0.1}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for id := range pos {
+			pos[id] = pos[id].Add(step[id])
+			g.Move(id, pos[id])
+		}
+	}
+}
+
+func BenchmarkGrid2DoPairs(b *testing.B) {
+	const n = 100000
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGrid2(1)
+	for i := 0; i < n; i++ {
+		g.Insert(r2.Vec{X: rnd.Float64() * 300, Y: rnd.Float64() * 300})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.DoPairs(1, func(i, j int) bool { return false })
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hashgrid
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+func TestGrid3(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGrid3(0.5)
+	want := make(map[int]r3.Vec)
+	randVec := func() r3.Vec {
+		return r3.Vec{X: rnd.NormFloat64() * 3, Y: rnd.NormFloat64() * 3, Z: rnd.NormFloat64() * 3}
+	}
+	for round := 0; round < 20; round++ {
+		for i := 0; i < 50; i++ {
+			p := randVec()
+			id := g.Insert(p)
+			if _, ok := want[id]; ok {
+				t.Fatalf("reused ID of live point: %d", id)
+			}
+			want[id] = p
+		}
+		for id, p := range want {
+			switch rnd.Intn(4) {
+			case 0:
+				g.Remove(id)
+				delete(want, id)
+			case 1:
+				// Move a small distance, mostly
+				// within the same cell.
+				p = p.Add(r3.Vec{X: rnd.NormFloat64() * 0.05, Y: rnd.NormFloat64() * 0.05, Z: rnd.NormFloat64() * 0.05})
+				g.Move(id, p)
+				want[id] = p
+			case 2:
+				p = randVec()
+				g.Move(id, p)
+				want[id] = p
+			}
+		}
+
+		if g.Len() != len(want) {
+			t.Fatalf("unexpected length: got:%d want:%d", g.Len(), len(want))
+		}
+		for id, p := range want {
+			if got := g.Pos(id); got != p {
+				t.Fatalf("unexpected position of %d: got:%v want:%v", id, got, p)
+			}
+		}
+		for _, r := range []float64{0, 0.1, 0.5, 1.2, 100} {
+			for k := 0; k < 5; k++ {
+				q := randVec()
+				var got []int
+				g.DoWithin(q, r, func(id int) bool {
+					got = append(got, id)
+					return false
+				})
+				var wantIDs []int
+				for id, p := range want {
+					if d := p.Sub(q); r3.Norm(d) <= r {
+						wantIDs = append(wantIDs, id)
+					}
+				}
+				if !sameInts(got, wantIDs) {
+					t.Errorf("unexpected points within %v of %v: got:%v want:%v", r, q, got, wantIDs)
+				}
+			}
+
+			var got [][2]int
+			g.DoPairs(r, func(i, j int) bool {
+				if i > j {
+					i, j = j, i
+				}
+				got = append(got, [2]int{i, j})
+				return false
+			})
+			var wantPairs [][2]int
+			for i, p := range want {
+				for j, q := range want {
+					if d := p.Sub(q); i < j && r3.Norm(d) <= r {
+						wantPairs = append(wantPairs, [2]int{i, j})
+					}
+				}
+			}
+			if !samePairs(got, wantPairs) {
+				t.Errorf("unexpected pairs within %v: got %d pairs want %d", r, len(got), len(wantPairs))
+			}
+		}
+	}
+
+	g.Reset()
+	if g.Len() != 0 {
+		t.Errorf("unexpected length after reset: %d", g.Len())
+	}
+	if g.DoPairs(math.Inf(1), func(i, j int) bool { return true }) {
+		t.Error("unexpected pair after reset")
+	}
+	if id := g.Insert(r3.Vec{}); id != 0 {
+		t.Errorf("unexpected ID after reset: got:%d want:0", id)
+	}
+}
+
+func TestGrid3Done(t *testing.T) {
+	g := NewGrid3(1)
+	for i := 0; i < 10; i++ {
+		g.Insert(r3.Vec{X: float64(i) / 10})
+	}
+	var n int
+	if !g.DoWithin(r3.Vec{}, 2, func(int) bool { n++; return n == 3 }) || n != 3 {
+		t.Errorf("unexpected DoWithin termination: n=%d", n)
+	}
+	n = 0
+	if !g.DoPairs(2, func(int, int) bool { n++; return n == 3 }) || n != 3 {
+		t.Errorf("unexpected DoPairs termination: n=%d", n)
+	}
+}
+
+func TestGrid3Panics(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero size", fn: func() { NewGrid3(0) }},
+		{name: "NaN size", fn: func() { NewGrid3(math.NaN()) }},
+		{name: "infinite size", fn: func() { NewGrid3(math.Inf(1)) }},
+		{name: "NaN point", fn: func() { NewGrid3(1).Insert(r3.Vec{X: math.NaN()}) }},
+		{name: "distant point", fn: func() { NewGrid3(1e-300).Insert(r3.Vec{Y: 1}) }},
+		{name: "invalid ID", fn: func() { NewGrid3(1).Move(0, r3.Vec{}) }},
+		{name: "removed ID", fn: func() {
+			g := NewGrid3(1)
+			id := g.Insert(r3.Vec{})
+			g.Remove(id)
+			g.Pos(id)
+		}},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func BenchmarkGrid3Move(b *testing.B) {
+	const n = 100000
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGrid3(1)
+	pos := make([]r3.Vec, n)
+	for i := range pos {
+		pos[i] = r3.Vec{X: rnd.Float64() * 300, Y: rnd.Float64() * 300, Z: rnd.Float64() * 30}
+		g.Insert(pos[i])
+	}
+	step := make([]r3.Vec, n)
+	for i := range step {
+		step[i] = r3.Vec{X: rnd.NormFloat64() * 0.1, Y: rnd.NormFloat64() * 0.1, Z: rnd.NormFloat64() * 0.1}
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for id := range pos {
+			pos[id] = pos[id].Add(step[id])
+			g.Move(id, pos[id])
+		}
+	}
+}
+
+func BenchmarkGrid3DoPairs(b *testing.B) {
+	const n = 100000
+	rnd := rand.New(rand.NewSource(1))
+	g := NewGrid3(1)
+	for i := 0; i < n; i++ {
+		g.Insert(r3.Vec{X: rnd.Float64() * 300, Y: rnd.Float64() * 300, Z: rnd.Float64() * 30})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.DoPairs(1, func(i, j int) bool { return false })
+	}
+}
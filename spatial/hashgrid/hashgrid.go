@@ -0,0 +1,99 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hashgrid
+
+import "math"
+
+// maxCell is the largest magnitude of a cell coordinate.
+const maxCell = 1 << 53
+
+// cellOf returns the coordinate of the cell holding x for a grid with
+// the given inverse cell size. It panics if x is not finite or the cell
+// coordinate is out of range.
+func cellOf(x, inv float64) int64 {
+	c := math.Floor(x * inv)
+	if !(-maxCell <= c && c <= maxCell) {
+		panic("hashgrid: coordinate out of range")
+	}
+	return int64(c)
+}
+
+// clampedCellOf returns the coordinate of the cell holding x for a grid
+// with the given inverse cell size, clamped to the range of cell
+// coordinates.
+func clampedCellOf(x, inv float64) int64 {
+	c := math.Floor(x * inv)
+	switch {
+	case c < -maxCell:
+		return -maxCell
+	case c > maxCell:
+		return maxCell
+	}
+	return int64(c)
+}
+
+// reach returns the number of cells of the given inverse size spanned by
+// the distance r.
+func reach(r, inv float64) int64 {
+	k := math.Ceil(r * inv)
+	if k > maxCell {
+		return maxCell
+	}
+	return int64(k)
+}
+
+// entry is the location of a point in a grid.
+type entry struct {
+	// bucket is the index of the bucket holding
+	// the point, or -1 if the ID is not in use.
+	bucket int
+
+	// slot is the index of the point in the IDs
+	// of the bucket.
+	slot int
+}
+
+// ids is an allocator of point IDs.
+type ids struct {
+	entries []entry
+	free    []int
+}
+
+// alloc returns an unused ID, reusing removed IDs first.
+func (a *ids) alloc() int {
+	if n := len(a.free); n != 0 {
+		id := a.free[n-1]
+		a.free = a.free[:n-1]
+		return id
+	}
+	a.entries = append(a.entries, entry{bucket: -1})
+	return len(a.entries) - 1
+}
+
+// release marks id as unused.
+func (a *ids) release(id int) {
+	a.entries[id].bucket = -1
+	a.free = append(a.free, id)
+}
+
+// check panics if id is not in use.
+func (a *ids) check(id int) {
+	if id < 0 || len(a.entries) <= id || a.entries[id].bucket < 0 {
+		panic("hashgrid: invalid point ID")
+	}
+}
+
+// reset marks all IDs as unused, retaining allocated storage.
+func (a *ids) reset() {
+	a.entries = a.entries[:0]
+	a.free = a.free[:0]
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,12 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hnsw implements a hierarchical navigable small world graph.
+// Hierarchical navigable small world graphs provide an approximate
+// search for nearest neighbors that remains efficient in high
+// dimensional spaces where exact methods such as k-d trees degrade
+// to exhaustive search.
+//
+// See https://arxiv.org/abs/1603.09320 for details of HNSW graphs.
+package hnsw // import "gonum.org/v1/gonum/spatial/hnsw"
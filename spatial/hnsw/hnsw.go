@@ -0,0 +1,312 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hnsw
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+// Comparable is the element interface for values stored in a graph.
+type Comparable interface {
+	// Distance returns the distance between the receiver and the
+	// parameter. The returned distance must be non-negative and
+	// symmetric, and should be small for similar values. Search
+	// quality is best when the distance is a metric.
+	Distance(Comparable) float64
+}
+
+// Point represents a point in a Euclidean space that satisfies the Comparable
+// interface.
+type Point []float64
+
+// Distance returns the Euclidean distance between c and the receiver. The concrete
+// type of c must be Point.
+func (p Point) Distance(c Comparable) float64 {
+	q := c.(Point)
+	var sum float64
+	for dim, c := range p {
+		d := c - q[dim]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// ComparableDist holds a Comparable and a distance to a specific query.
+type ComparableDist struct {
+	Comparable Comparable
+	Dist       float64
+}
+
+// Graph is a hierarchical navigable small world graph.
+type Graph struct {
+	m, mMax0       int
+	efConstruction int
+	ml             float64
+	uniform        func() float64
+
+	nodes []node
+	entry int
+	top   int
+}
+
+// node is a value in the graph and its links on each layer
+// of the graph it is present in.
+type node struct {
+	point Comparable
+	links [][]int
+}
+
+// New returns an empty graph. The m parameter specifies the number of
+// links made by each inserted value on each layer of the graph, and the
+// efConstruction parameter specifies the number of candidate neighbors
+// considered during insertion. Larger values of m and efConstruction give
+// better search accuracy at the cost of memory use and insertion time.
+// Values of m between 5 and 48 and efConstruction of at least 100 are
+// typical. The src parameter provides the source of randomness for layer
+// assignment. If src is nil global rand package functions are used.
+//
+// New will panic if m is less than 2 or efConstruction is less than m.
+func New(m, efConstruction int, src rand.Source) *Graph {
+	if m < 2 {
+		panic("hnsw: m less than 2")
+	}
+	if efConstruction < m {
+		panic("hnsw: efConstruction less than m")
+	}
+	uniform := rand.Float64
+	if src != nil {
+		uniform = rand.New(src).Float64
+	}
+	return &Graph{
+		m:              m,
+		mMax0:          2 * m,
+		efConstruction: efConstruction,
+		ml:             1 / math.Log(float64(m)),
+		uniform:        uniform,
+	}
+}
+
+// Len returns the number of elements in the graph.
+func (g *Graph) Len() int { return len(g.nodes) }
+
+// Insert adds c to the graph. Insert must not be called concurrently
+// with any other method of the graph.
+func (g *Graph) Insert(c Comparable) {
+	id := len(g.nodes)
+	level := int(-math.Log(1-g.uniform()) * g.ml)
+	g.nodes = append(g.nodes, node{point: c, links: make([][]int, level+1)})
+	if id == 0 {
+		g.entry = 0
+		g.top = level
+		return
+	}
+
+	ep := []candidate{{id: g.entry, dist: c.Distance(g.nodes[g.entry].point)}}
+	for l := g.top; l > level; l-- {
+		ep = g.searchLayer(c, ep, 1, l)
+	}
+	for l := min(g.top, level); l >= 0; l-- {
+		w := g.searchLayer(c, ep, g.efConstruction, l)
+		neighbors := g.selectNeighbors(w, g.m)
+		links := make([]int, len(neighbors))
+		for i, n := range neighbors {
+			links[i] = n.id
+		}
+		g.nodes[id].links[l] = links
+
+		mMax := g.m
+		if l == 0 {
+			mMax = g.mMax0
+		}
+		for _, n := range neighbors {
+			nl := append(g.nodes[n.id].links[l], id)
+			if len(nl) > mMax {
+				nl = g.shrink(n.id, nl, mMax)
+			}
+			g.nodes[n.id].links[l] = nl
+		}
+		ep = w
+	}
+	if level > g.top {
+		g.entry = id
+		g.top = level
+	}
+}
+
+// shrink returns at most mMax of the links of the node with the given
+// id, selected with the neighbor selection heuristic.
+func (g *Graph) shrink(id int, links []int, mMax int) []int {
+	p := g.nodes[id].point
+	cands := make([]candidate, len(links))
+	for i, l := range links {
+		cands[i] = candidate{id: l, dist: p.Distance(g.nodes[l].point)}
+	}
+	sort.Sort(byDist(cands))
+	cands = g.selectNeighbors(cands, mMax)
+	links = links[:len(cands)]
+	for i, c := range cands {
+		links[i] = c.id
+	}
+	return links
+}
+
+// selectNeighbors returns at most m of the candidates, which must be sorted
+// by increasing distance from a base value. A candidate is selected only
+// if it is closer to the base than to every previously selected candidate,
+// so that links span the directions around the base rather than cluster.
+func (g *Graph) selectNeighbors(cands []candidate, m int) []candidate {
+	sel := make([]candidate, 0, m)
+	for _, c := range cands {
+		if len(sel) == m {
+			break
+		}
+		p := g.nodes[c.id].point
+		ok := true
+		for _, s := range sel {
+			if p.Distance(g.nodes[s.id].point) < c.dist {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			sel = append(sel, c)
+		}
+	}
+	return sel
+}
+
+// searchLayer returns up to ef values of layer l nearest to q found by
+// a greedy search starting from the entry points ep. The returned values
+// are sorted by increasing distance.
+func (g *Graph) searchLayer(q Comparable, ep []candidate, ef, l int) []candidate {
+	visited := make(map[int]bool)
+	cands := make(minQueue, 0, len(ep))
+	var best maxQueue
+	for _, e := range ep {
+		visited[e.id] = true
+		heap.Push(&cands, e)
+		heap.Push(&best, e)
+		if len(best) > ef {
+			heap.Pop(&best)
+		}
+	}
+	for len(cands) != 0 {
+		c := heap.Pop(&cands).(candidate)
+		if len(best) == ef && c.dist > best[0].dist {
+			break
+		}
+		for _, e := range g.nodes[c.id].links[l] {
+			if visited[e] {
+				continue
+			}
+			visited[e] = true
+			d := q.Distance(g.nodes[e].point)
+			if len(best) < ef || d < best[0].dist {
+				heap.Push(&cands, candidate{id: e, dist: d})
+				heap.Push(&best, candidate{id: e, dist: d})
+				if len(best) > ef {
+					heap.Pop(&best)
+				}
+			}
+		}
+	}
+	sorted := make([]candidate, len(best))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		sorted[i] = heap.Pop(&best).(candidate)
+	}
+	return sorted
+}
+
+// Nearest returns the approximate nearest value to the query and the
+// distance between them. The ef parameter specifies the number of
+// candidate neighbors considered during the search; larger values give
+// better search accuracy at the cost of search time. If the graph is
+// empty, Nearest returns nil and +Inf.
+func (g *Graph) Nearest(q Comparable, ef int) (Comparable, float64) {
+	nn := g.NearestN(q, 1, ef)
+	if len(nn) == 0 {
+		return nil, math.Inf(1)
+	}
+	return nn[0].Comparable, nn[0].Dist
+}
+
+// NearestN returns the approximate k nearest values to the query in order
+// of increasing distance. The ef parameter specifies the number of
+// candidate neighbors considered during the search and is increased to k
+// if it is less than k; larger values give better search accuracy at
+// the cost of search time. If the graph holds fewer than k values, at
+// most Len values are returned.
+//
+// NearestN may be called concurrently with other searches.
+func (g *Graph) NearestN(q Comparable, k, ef int) []ComparableDist {
+	if len(g.nodes) == 0 || k < 1 {
+		return nil
+	}
+	if ef < k {
+		ef = k
+	}
+	ep := []candidate{{id: g.entry, dist: q.Distance(g.nodes[g.entry].point)}}
+	for l := g.top; l > 0; l-- {
+		ep = g.searchLayer(q, ep, 1, l)
+	}
+	w := g.searchLayer(q, ep, ef, 0)
+	if len(w) > k {
+		w = w[:k]
+	}
+	nn := make([]ComparableDist, len(w))
+	for i, c := range w {
+		nn[i] = ComparableDist{Comparable: g.nodes[c.id].point, Dist: c.dist}
+	}
+	return nn
+}
+
+// candidate is a node index and its distance from a query.
+type candidate struct {
+	id   int
+	dist float64
+}
+
+type byDist []candidate
+
+func (c byDist) Len() int           { return len(c) }
+func (c byDist) Less(i, j int) bool { return c[i].dist < c[j].dist }
+func (c byDist) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// minQueue is a min-heap of candidates ordered by distance.
+type minQueue []candidate
+
+func (q minQueue) Len() int            { return len(q) }
+func (q minQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q minQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *minQueue) Push(x interface{}) { *q = append(*q, x.(candidate)) }
+func (q *minQueue) Pop() interface{} {
+	x := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return x
+}
+
+// maxQueue is a max-heap of candidates ordered by distance.
+type maxQueue []candidate
+
+func (q maxQueue) Len() int            { return len(q) }
+func (q maxQueue) Less(i, j int) bool  { return q[i].dist > q[j].dist }
+func (q maxQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *maxQueue) Push(x interface{}) { *q = append(*q, x.(candidate)) }
+func (q *maxQueue) Pop() interface{} {
+	x := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return x
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
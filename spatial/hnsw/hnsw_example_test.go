@@ -0,0 +1,42 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hnsw_test
+
+import (
+	"fmt"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/hnsw"
+)
+
+func Example() {
+	// Construct a graph of 1000 random points in 32 dimensions.
+	rnd := rand.New(rand.NewSource(1))
+	g := hnsw.New(16, 100, rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := make(hnsw.Point, 32)
+		for j := range p {
+			p[j] = rnd.Float64()
+		}
+		g.Insert(p)
+	}
+
+	// Find the approximate five nearest neighbors of a point.
+	q := make(hnsw.Point, 32)
+	for j := range q {
+		q[j] = 0.5
+	}
+	for _, c := range g.NearestN(q, 5, 50) {
+		fmt.Printf("%.4f\n", c.Dist)
+	}
+
+	// Output:
+	// 1.0416
+	// 1.1635
+	// 1.1975
+	// 1.2165
+	// 1.2240
+}
@@ -0,0 +1,145 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hnsw
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func randomPoints(rnd *rand.Rand, n, dims int) []Point {
+	p := make([]Point, n)
+	for i := range p {
+		p[i] = make(Point, dims)
+		for j := range p[i] {
+			p[i][j] = rnd.NormFloat64()
+		}
+	}
+	return p
+}
+
+func TestGraphRecall(t *testing.T) {
+	const (
+		n       = 2000
+		dims    = 64
+		k       = 10
+		queries = 50
+	)
+	for _, test := range []struct {
+		m, efConstruction, ef int
+		minRecall             float64
+	}{
+		{m: 8, efConstruction: 100, ef: 50, minRecall: 0.8},
+		{m: 16, efConstruction: 200, ef: 100, minRecall: 0.95},
+		{m: 16, efConstruction: 200, ef: 500, minRecall: 0.99},
+	} {
+		rnd := rand.New(rand.NewSource(1))
+		points := randomPoints(rnd, n, dims)
+		g := New(test.m, test.efConstruction, rand.NewSource(1))
+		for _, p := range points {
+			g.Insert(p)
+		}
+		if g.Len() != n {
+			t.Errorf("unexpected length: got:%d want:%d", g.Len(), n)
+		}
+
+		var found int
+		for _, q := range randomPoints(rnd, queries, dims) {
+			got := g.NearestN(q, k, test.ef)
+			if len(got) != k {
+				t.Fatalf("unexpected number of neighbors: got:%d want:%d", len(got), k)
+			}
+			if !sort.SliceIsSorted(got, func(i, j int) bool { return got[i].Dist < got[j].Dist }) {
+				t.Errorf("neighbors not sorted by distance: %v", got)
+			}
+			for _, c := range got {
+				if d := q.Distance(c.Comparable); d != c.Dist {
+					t.Errorf("unexpected distance: got:%v want:%v", c.Dist, d)
+				}
+			}
+
+			want := make([]float64, n)
+			for i, p := range points {
+				want[i] = q.Distance(p)
+			}
+			sort.Float64s(want)
+			for _, c := range got {
+				if c.Dist <= want[k-1] {
+					found++
+				}
+			}
+		}
+		recall := float64(found) / (queries * k)
+		if recall < test.minRecall {
+			t.Errorf("unexpected recall for m=%d efConstruction=%d ef=%d: got:%.3f want>=%.3f",
+				test.m, test.efConstruction, test.ef, recall, test.minRecall)
+		}
+	}
+}
+
+func TestGraphNearest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := randomPoints(rnd, 500, 20)
+	g := New(12, 100, rand.NewSource(1))
+
+	if p, d := g.Nearest(points[0], 10); p != nil || !math.IsInf(d, 1) {
+		t.Errorf("unexpected result for empty graph: %v %v", p, d)
+	}
+
+	for i, p := range points {
+		g.Insert(p)
+		if got := g.NearestN(p, i+10, 0); len(got) != i+1 {
+			t.Errorf("unexpected number of neighbors with %d values: got:%d", i+1, len(got))
+		}
+	}
+	var missed int
+	for _, p := range points {
+		got, d := g.Nearest(p, 20)
+		if d != 0 || &got.(Point)[0] != &p[0] {
+			missed++
+		}
+	}
+	if missed != 0 {
+		t.Errorf("failed to find %d inserted values", missed)
+	}
+	if got := g.NearestN(points[0], 0, 10); got != nil {
+		t.Errorf("unexpected result for k=0: %v", got)
+	}
+}
+
+func TestNewPanics(t *testing.T) {
+	for _, test := range []struct {
+		m, efConstruction int
+	}{
+		{m: 1, efConstruction: 10},
+		{m: 16, efConstruction: 8},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for m=%d efConstruction=%d", test.m, test.efConstruction)
+				}
+			}()
+			New(test.m, test.efConstruction, nil)
+		}()
+	}
+}
+
+func BenchmarkNearestN(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	points := randomPoints(rnd, 10000, 64)
+	g := New(16, 200, rand.NewSource(1))
+	for _, p := range points {
+		g.Insert(p)
+	}
+	queries := randomPoints(rnd, 100, 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.NearestN(queries[i%len(queries)], 10, 100)
+	}
+}
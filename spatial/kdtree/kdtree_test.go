@@ -10,8 +10,10 @@ import (
 	"math"
 	"os"
 	"reflect"
+	"runtime"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"unsafe"
 
@@ -407,6 +409,90 @@ func TestNearestSetDist(t *testing.T) {
 	}
 }
 
+func TestNearestSets(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	random := func(n int) []Comparable {
+		p := make([]Comparable, n)
+		for i := range p {
+			p[i] = Point{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+		}
+		return p
+	}
+	p := random(1000)
+	points := make(Points, len(p))
+	for i, c := range p {
+		points[i] = c.(Point)
+	}
+	tree := New(points, false)
+	queries := random(500)
+
+	for _, test := range []struct {
+		name   string
+		keeper func() Keeper
+		reused bool
+	}{
+		{name: "NKeeper", keeper: func() Keeper { return NewNKeeper(10) }, reused: true},
+		{name: "DistKeeper", keeper: func() Keeper { return NewDistKeeper(0.01) }, reused: true},
+		{name: "not reusable", keeper: func() Keeper { return wrapped{NewNKeeper(5)} }, reused: false},
+		{name: "Reset method", keeper: func() Keeper { return withReset{NewNKeeper(5)} }, reused: false},
+	} {
+		want := make([]Heap, len(queries))
+		for i, q := range queries {
+			k := test.keeper()
+			tree.NearestSet(k, q)
+			want[i] = append(Heap(nil), heapOf(k)...)
+		}
+		for _, workers := range []int{0, 1, 3, 1000} {
+			got := make([]Heap, len(queries))
+			var calls int64
+			tree.NearestSets(queries, workers, func() Keeper {
+				atomic.AddInt64(&calls, 1)
+				return test.keeper()
+			}, func(i int, k Keeper) {
+				got[i] = append(Heap(nil), heapOf(k)...)
+			})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("unexpected results for %s with %d workers", test.name, workers)
+			}
+			if test.reused {
+				if calls > int64(runtime.GOMAXPROCS(0)) && calls > int64(workers) {
+					t.Errorf("unexpected number of keepers for %s with %d workers: %d", test.name, workers, calls)
+				}
+			} else if calls != int64(len(queries)) {
+				t.Errorf("unexpected number of keepers for %s with %d workers: got:%d want:%d", test.name, workers, calls, len(queries))
+			}
+		}
+	}
+}
+
+// wrapped is a Keeper that is not reused by NearestSets.
+type wrapped struct {
+	Keeper
+}
+
+// withReset is a Keeper with a Reset method that
+// must not be called by NearestSets.
+type withReset struct {
+	Keeper
+}
+
+func (withReset) Reset() { panic("unexpected call to Reset") }
+
+func heapOf(k Keeper) Heap {
+	switch k := k.(type) {
+	case *NKeeper:
+		return k.Heap
+	case *DistKeeper:
+		return k.Heap
+	case wrapped:
+		return heapOf(k.Keeper)
+	case withReset:
+		return heapOf(k.Keeper)
+	default:
+		panic("unexpected keeper type")
+	}
+}
+
 func TestDo(t *testing.T) {
 	tree := New(wpData, false)
 	var got Points
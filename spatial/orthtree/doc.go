@@ -0,0 +1,13 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package orthtree implements region quadtrees and octrees.
+//
+// The trees support range and nearest neighbor queries, and hold the
+// center of mass of the points below each node for aggregate queries
+// such as Barnes-Hut force approximation.
+//
+// See https://en.wikipedia.org/wiki/Quadtree and
+// https://en.wikipedia.org/wiki/Octree for more details.
+package orthtree // import "gonum.org/v1/gonum/spatial/orthtree"
@@ -0,0 +1,58 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orthtree_test
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/orthtree"
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+type body struct {
+	r2.Vec
+	m float64
+}
+
+func (b body) Coord2() r2.Vec { return b.Vec }
+func (b body) Mass() float64  { return b.m }
+
+func ExampleQuadtree_Walk() {
+	rnd := rand.New(rand.NewSource(1))
+	bodies := make([]orthtree.Point2, 1000)
+	for i := range bodies {
+		bodies[i] = body{Vec: r2.Vec{X: rnd.NormFloat64(), Y: rnd.NormFloat64()}, m: 1}
+	}
+	tree := orthtree.NewQuadtree(bodies, 1)
+
+	// Approximate the gravitational potential at a point
+	// using the aggregate mass of nodes that are small
+	// relative to their distance from the point.
+	q := r2.Vec{X: 5, Y: 5}
+	const theta = 0.5
+	var approx float64
+	tree.Walk(func(n *orthtree.Node2) bool {
+		d := n.Center.Sub(q)
+		r := math.Hypot(d.X, d.Y)
+		if n.IsLeaf() || (n.Bounds.Max.X-n.Bounds.Min.X)/r < theta {
+			approx += n.Mass / r
+			return false
+		}
+		return true
+	})
+
+	var exact float64
+	for _, b := range bodies {
+		d := b.Coord2().Sub(q)
+		exact += 1 / math.Hypot(d.X, d.Y)
+	}
+	fmt.Printf("relative error: %.1e\n", math.Abs(approx-exact)/exact)
+
+	// Output:
+	// relative error: 3.8e-03
+}
@@ -0,0 +1,269 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orthtree
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+// Point3 is a point in a volume.
+type Point3 interface {
+	Coord3() r3.Vec
+}
+
+// Octree is a region octree.
+type Octree struct {
+	// Root is the root of the tree.
+	// Root is nil for an empty tree.
+	Root *Node3
+}
+
+// Node3 is a node of an Octree.
+type Node3 struct {
+	// Bounds is the cubic region
+	// of the volume covered by the
+	// node.
+	Bounds r3.Box
+
+	// Children holds the octants
+	// of Bounds. Children of empty
+	// octants are nil, and all the
+	// children of leaves are nil.
+	Children [8]*Node3
+
+	// Points holds the points within
+	// the bounds of a leaf, and is
+	// nil for other nodes.
+	Points []Point3
+
+	// Center and Mass are the center
+	// of mass and total mass of the
+	// points within Bounds, and Count
+	// is the number of points.
+	Center r3.Vec
+	Mass   float64
+	Count  int
+}
+
+// IsLeaf returns whether n is a leaf.
+func (n *Node3) IsLeaf() bool {
+	return n.Children == [8]*Node3{}
+}
+
+// NewOctree returns an octree holding points, with at most capacity
+// points in each leaf. Leaves hold more than capacity points only when
+// the points are too close to be separated by subdivision within the
+// limits of floating point precision. NewOctree panics if capacity is
+// less than one.
+//
+// The tree must be rebuilt if the points are altered.
+func NewOctree(points []Point3, capacity int) *Octree {
+	if capacity < 1 {
+		panic("orthtree: capacity must be positive")
+	}
+	if len(points) == 0 {
+		return &Octree{}
+	}
+
+	min := points[0].Coord3()
+	max := min
+	for _, p := range points[1:] {
+		c := p.Coord3()
+		min.X = math.Min(min.X, c.X)
+		min.Y = math.Min(min.Y, c.Y)
+		min.Z = math.Min(min.Z, c.Z)
+		max.X = math.Max(max.X, c.X)
+		max.Y = math.Max(max.Y, c.Y)
+		max.Z = math.Max(max.Z, c.Z)
+	}
+	side := math.Max(max.X-min.X, math.Max(max.Y-min.Y, max.Z-min.Z))
+	bounds := r3.Box{Min: min, Max: min.Add(r3.Vec{X: side, Y: side, Z: side})}
+
+	p := make([]Point3, len(points))
+	copy(p, points)
+	return &Octree{Root: build3(bounds, p, capacity)}
+}
+
+// build3 returns the subtree covering bounds holding points.
+func build3(bounds r3.Box, points []Point3, capacity int) *Node3 {
+	n := &Node3{Bounds: bounds, Count: len(points)}
+	for _, p := range points {
+		m := massOf(p)
+		n.Center = n.Center.Add(p.Coord3().Scale(m))
+		n.Mass += m
+	}
+	if n.Mass != 0 {
+		n.Center = n.Center.Scale(1 / n.Mass)
+	}
+
+	mid := bounds.Min.Add(bounds.Max).Scale(0.5)
+	splittable := bounds.Min.X < mid.X && mid.X < bounds.Max.X &&
+		bounds.Min.Y < mid.Y && mid.Y < bounds.Max.Y &&
+		bounds.Min.Z < mid.Z && mid.Z < bounds.Max.Z
+	if len(points) <= capacity || !splittable {
+		n.Points = points
+		return n
+	}
+
+	var parts [8][]Point3
+	for _, p := range points {
+		o := octant(p.Coord3(), mid)
+		parts[o] = append(parts[o], p)
+	}
+	for o, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+		b := bounds
+		if o&1 == 0 {
+			b.Max.X = mid.X
+		} else {
+			b.Min.X = mid.X
+		}
+		if o&2 == 0 {
+			b.Max.Y = mid.Y
+		} else {
+			b.Min.Y = mid.Y
+		}
+		if o&4 == 0 {
+			b.Max.Z = mid.Z
+		} else {
+			b.Min.Z = mid.Z
+		}
+		n.Children[o] = build3(b, part, capacity)
+	}
+	return n
+}
+
+// octant returns the index of the octant around mid that holds c.
+// Bit 0 of the index is set for the upper half of X, bit 1 for the
+// upper half of Y and bit 2 for the upper half of Z.
+func octant(c, mid r3.Vec) int {
+	var o int
+	if c.X >= mid.X {
+		o |= 1
+	}
+	if c.Y >= mid.Y {
+		o |= 2
+	}
+	if c.Z >= mid.Z {
+		o |= 4
+	}
+	return o
+}
+
+// Walk calls fn on the nodes of t in depth-first pre-order. The children
+// of a node are not visited if fn returns false for the node.
+func (t *Octree) Walk(fn func(*Node3) bool) {
+	if t.Root != nil {
+		t.Root.walk(fn)
+	}
+}
+
+func (n *Node3) walk(fn func(*Node3) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range &n.Children {
+		if c != nil {
+			c.walk(fn)
+		}
+	}
+}
+
+// DoBounded calls fn on each point of t that is within b, including its
+// boundary, until fn returns true. DoBounded returns whether fn returned
+// true.
+func (t *Octree) DoBounded(b r3.Box, fn func(Point3) (done bool)) bool {
+	if t.Root == nil {
+		return false
+	}
+	return t.Root.doBounded(b, fn)
+}
+
+func (n *Node3) doBounded(b r3.Box, fn func(Point3) bool) bool {
+	if n.Bounds.Max.X < b.Min.X || b.Max.X < n.Bounds.Min.X ||
+		n.Bounds.Max.Y < b.Min.Y || b.Max.Y < n.Bounds.Min.Y ||
+		n.Bounds.Max.Z < b.Min.Z || b.Max.Z < n.Bounds.Min.Z {
+		return false
+	}
+	for _, p := range n.Points {
+		c := p.Coord3()
+		if b.Min.X <= c.X && c.X <= b.Max.X &&
+			b.Min.Y <= c.Y && c.Y <= b.Max.Y &&
+			b.Min.Z <= c.Z && c.Z <= b.Max.Z && fn(p) {
+			return true
+		}
+	}
+	for _, c := range &n.Children {
+		if c != nil && c.doBounded(b, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// Nearest returns the point of t nearest to q and the Euclidean distance
+// between them. If t is empty, Nearest returns nil and +Inf.
+func (t *Octree) Nearest(q r3.Vec) (Point3, float64) {
+	best := t.nearest(q, 1)
+	if len(best) == 0 {
+		return nil, math.Inf(1)
+	}
+	return best[0].val.(Point3), math.Sqrt(best[0].dist)
+}
+
+// NearestN returns the k points of t nearest to q in order of increasing
+// distance. If t has fewer than k points, all the points are returned.
+func (t *Octree) NearestN(q r3.Vec, k int) []Point3 {
+	best := t.nearest(q, k)
+	p := make([]Point3, len(best))
+	for i, b := range best {
+		p[i] = b.val.(Point3)
+	}
+	return p
+}
+
+// nearest returns the k points of t nearest to q and their squared
+// distances in order of increasing distance.
+func (t *Octree) nearest(q r3.Vec, k int) []item {
+	if t.Root == nil || k < 1 {
+		return nil
+	}
+	nodes := minQueue{{val: t.Root, dist: boxDist3(t.Root.Bounds, q)}}
+	var best maxQueue
+	for len(nodes) != 0 {
+		it := heap.Pop(&nodes).(item)
+		if len(best) == k && it.dist > best[0].dist {
+			break
+		}
+		n := it.val.(*Node3)
+		for _, p := range n.Points {
+			d := p.Coord3().Sub(q)
+			keep(&best, item{val: p, dist: r3.Dot(d, d)}, k)
+		}
+		for _, c := range &n.Children {
+			if c != nil {
+				heap.Push(&nodes, item{val: c, dist: boxDist3(c.Bounds, q)})
+			}
+		}
+	}
+	sorted := make([]item, len(best))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		sorted[i] = heap.Pop(&best).(item)
+	}
+	return sorted
+}
+
+// boxDist3 returns the squared distance from q to the nearest point of b.
+func boxDist3(b r3.Box, q r3.Vec) float64 {
+	dx := math.Max(0, math.Max(b.Min.X-q.X, q.X-b.Max.X))
+	dy := math.Max(0, math.Max(b.Min.Y-q.Y, q.Y-b.Max.Y))
+	dz := math.Max(0, math.Max(b.Min.Z-q.Z, q.Z-b.Max.Z))
+	return dx*dx + dy*dy + dz*dz
+}
@@ -0,0 +1,131 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orthtree
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r3"
+)
+
+type particle3 struct {
+	r3.Vec
+	m float64
+}
+
+func (p particle3) Coord3() r3.Vec { return p.Vec }
+func (p particle3) Mass() float64  { return p.m }
+
+func randomPoints3(rnd *rand.Rand, n int) []Point3 {
+	p := make([]Point3, n)
+	for i := range p {
+		p[i] = particle3{Vec: r3.Vec{X: rnd.NormFloat64(), Y: 10 * rnd.Float64(), Z: rnd.ExpFloat64()}, m: rnd.Float64()}
+	}
+	return p
+}
+
+func TestOctreeStructure(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := randomPoints3(rnd, 1000)
+	for i := 0; i < 20; i++ {
+		points = append(points, particle3{Vec: r3.Vec{X: 1, Y: 1, Z: 1}, m: 1})
+	}
+	const capacity = 4
+	tree := NewOctree(points, capacity)
+
+	var count int
+	tree.Walk(func(n *Node3) bool {
+		var (
+			got  int
+			mass float64
+		)
+		if n.IsLeaf() {
+			if len(n.Points) > capacity {
+				for _, p := range n.Points {
+					if p.Coord3() != n.Points[0].Coord3() {
+						t.Errorf("leaf over capacity: %d points", len(n.Points))
+						break
+					}
+				}
+			}
+			b := n.Bounds
+			for _, p := range n.Points {
+				c := p.Coord3()
+				if c.X < b.Min.X || b.Max.X < c.X || c.Y < b.Min.Y || b.Max.Y < c.Y || c.Z < b.Min.Z || b.Max.Z < c.Z {
+					t.Errorf("point %v outside leaf bounds %+v", c, b)
+				}
+				got++
+				mass += massOf(p)
+			}
+			count += got
+		} else {
+			for _, c := range n.Children {
+				if c != nil {
+					got += c.Count
+					mass += c.Mass
+				}
+			}
+		}
+		if got != n.Count || math.Abs(mass-n.Mass) > 1e-10 {
+			t.Errorf("unexpected aggregate: got:%d,%v want:%d,%v", got, mass, n.Count, n.Mass)
+		}
+		return true
+	})
+	if count != len(points) {
+		t.Errorf("unexpected number of points in leaves: got:%d want:%d", count, len(points))
+	}
+}
+
+func TestOctreeQueries(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := randomPoints3(rnd, 500)
+	tree := NewOctree(points, 3)
+
+	for i := 0; i < 100; i++ {
+		q := r3.Vec{X: 3 * rnd.NormFloat64(), Y: 12*rnd.Float64() - 1, Z: rnd.NormFloat64()}
+		want := append([]Point3(nil), points...)
+		sort.Slice(want, func(i, j int) bool {
+			di, dj := want[i].Coord3().Sub(q), want[j].Coord3().Sub(q)
+			return r3.Dot(di, di) < r3.Dot(dj, dj)
+		})
+
+		if p, _ := tree.Nearest(q); p != want[0] {
+			t.Errorf("unexpected nearest point to %v: got:%v want:%v", q, p, want[0])
+		}
+		k := rnd.Intn(20)
+		got := tree.NearestN(q, k)
+		for j := range got {
+			if got[j] != want[j] {
+				t.Errorf("unexpected %d-th nearest point to %v: got:%v want:%v", j, q, got[j], want[j])
+			}
+		}
+
+		b := r3.Box{Min: q, Max: q.Add(r3.Vec{X: rnd.Float64(), Y: 3 * rnd.Float64(), Z: rnd.Float64()})}
+		in := func(c r3.Vec) bool {
+			return b.Min.X <= c.X && c.X <= b.Max.X && b.Min.Y <= c.Y && c.Y <= b.Max.Y && b.Min.Z <= c.Z && c.Z <= b.Max.Z
+		}
+		var n int
+		tree.DoBounded(b, func(p Point3) bool {
+			if !in(p.Coord3()) {
+				t.Errorf("point %v outside query bounds %+v", p.Coord3(), b)
+			}
+			n++
+			return false
+		})
+		var wantN int
+		for _, p := range points {
+			if in(p.Coord3()) {
+				wantN++
+			}
+		}
+		if n != wantN {
+			t.Errorf("unexpected number of points in %+v: got:%d want:%d", b, n, wantN)
+		}
+	}
+}
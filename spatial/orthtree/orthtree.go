@@ -0,0 +1,66 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orthtree
+
+import "container/heap"
+
+// Massive is a point with a mass. Points that do not implement
+// Massive have a mass of 1.
+type Massive interface {
+	Mass() float64
+}
+
+// massOf returns the mass of p.
+func massOf(p interface{}) float64 {
+	if m, ok := p.(Massive); ok {
+		return m.Mass()
+	}
+	return 1
+}
+
+// keep adds it to best if best holds fewer than k items or it is nearer
+// than the farthest item in best, which is then removed.
+func keep(best *maxQueue, it item, k int) {
+	if len(*best) < k {
+		heap.Push(best, it)
+		return
+	}
+	if it.dist < (*best)[0].dist {
+		(*best)[0] = it
+		heap.Fix(best, 0)
+	}
+}
+
+// item is a value with a squared distance from a query point.
+type item struct {
+	val  interface{}
+	dist float64
+}
+
+// minQueue is a min-heap of items ordered by distance.
+type minQueue []item
+
+func (q minQueue) Len() int            { return len(q) }
+func (q minQueue) Less(i, j int) bool  { return q[i].dist < q[j].dist }
+func (q minQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *minQueue) Push(x interface{}) { *q = append(*q, x.(item)) }
+func (q *minQueue) Pop() interface{} {
+	x := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return x
+}
+
+// maxQueue is a max-heap of items ordered by distance.
+type maxQueue []item
+
+func (q maxQueue) Len() int            { return len(q) }
+func (q maxQueue) Less(i, j int) bool  { return q[i].dist > q[j].dist }
+func (q maxQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *maxQueue) Push(x interface{}) { *q = append(*q, x.(item)) }
+func (q *maxQueue) Pop() interface{} {
+	x := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return x
+}
@@ -0,0 +1,258 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orthtree
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+// Point2 is a point in a plane.
+type Point2 interface {
+	Coord2() r2.Vec
+}
+
+// Quadtree is a region quadtree.
+type Quadtree struct {
+	// Root is the root of the tree.
+	// Root is nil for an empty tree.
+	Root *Node2
+}
+
+// Node2 is a node of a Quadtree.
+type Node2 struct {
+	// Bounds is the square region
+	// of the plane covered by the
+	// node.
+	Bounds r2.Box
+
+	// Children holds the quadrants
+	// of Bounds. Children of empty
+	// quadrants are nil, and all
+	// the children of leaves are nil.
+	Children [4]*Node2
+
+	// Points holds the points within
+	// the bounds of a leaf, and is
+	// nil for other nodes.
+	Points []Point2
+
+	// Center and Mass are the center
+	// of mass and total mass of the
+	// points within Bounds, and Count
+	// is the number of points.
+	Center r2.Vec
+	Mass   float64
+	Count  int
+}
+
+// IsLeaf returns whether n is a leaf.
+func (n *Node2) IsLeaf() bool {
+	return n.Children == [4]*Node2{}
+}
+
+// NewQuadtree returns a quadtree holding points, with at most capacity
+// points in each leaf. Leaves hold more than capacity points only when
+// the points are too close to be separated by subdivision within the
+// limits of floating point precision. NewQuadtree panics if capacity is
+// less than one.
+//
+// The tree must be rebuilt if the points are altered.
+func NewQuadtree(points []Point2, capacity int) *Quadtree {
+	if capacity < 1 {
+		panic("orthtree: capacity must be positive")
+	}
+	if len(points) == 0 {
+		return &Quadtree{}
+	}
+
+	min := points[0].Coord2()
+	max := min
+	for _, p := range points[1:] {
+		c := p.Coord2()
+		min.X = math.Min(min.X, c.X)
+		min.Y = math.Min(min.Y, c.Y)
+		max.X = math.Max(max.X, c.X)
+		max.Y = math.Max(max.Y, c.Y)
+	}
+	side := math.Max(max.X-min.X, max.Y-min.Y)
+	bounds := r2.Box{Min: min, Max: min.Add(r2.Vec{X: side, Y: side})}
+
+	p := make([]Point2, len(points))
+	copy(p, points)
+	return &Quadtree{Root: build2(bounds, p, capacity)}
+}
+
+// build2 returns the subtree covering bounds holding points.
+func build2(bounds r2.Box, points []Point2, capacity int) *Node2 {
+	n := &Node2{Bounds: bounds, Count: len(points)}
+	for _, p := range points {
+		m := massOf(p)
+		n.Center = n.Center.Add(p.Coord2().Scale(m))
+		n.Mass += m
+	}
+	if n.Mass != 0 {
+		n.Center = n.Center.Scale(1 / n.Mass)
+	}
+
+	mid := bounds.Min.Add(bounds.Max).Scale(0.5)
+	splittable := bounds.Min.X < mid.X && mid.X < bounds.Max.X &&
+		bounds.Min.Y < mid.Y && mid.Y < bounds.Max.Y
+	if len(points) <= capacity || !splittable {
+		n.Points = points
+		return n
+	}
+
+	var parts [4][]Point2
+	for _, p := range points {
+		q := quadrant(p.Coord2(), mid)
+		parts[q] = append(parts[q], p)
+	}
+	for q, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+		b := bounds
+		if q&1 == 0 {
+			b.Max.X = mid.X
+		} else {
+			b.Min.X = mid.X
+		}
+		if q&2 == 0 {
+			b.Max.Y = mid.Y
+		} else {
+			b.Min.Y = mid.Y
+		}
+		n.Children[q] = build2(b, part, capacity)
+	}
+	return n
+}
+
+// quadrant returns the index of the quadrant around mid that holds c.
+// Bit 0 of the index is set for the upper half of X and bit 1 for the
+// upper half of Y.
+func quadrant(c, mid r2.Vec) int {
+	var q int
+	if c.X >= mid.X {
+		q |= 1
+	}
+	if c.Y >= mid.Y {
+		q |= 2
+	}
+	return q
+}
+
+// Walk calls fn on the nodes of t in depth-first pre-order. The children
+// of a node are not visited if fn returns false for the node.
+//
+// Barnes-Hut approximation can be performed by walking the tree and
+// using the aggregate Center and Mass of nodes that are sufficiently
+// distant from the query point, without descending into them.
+func (t *Quadtree) Walk(fn func(*Node2) bool) {
+	if t.Root != nil {
+		t.Root.walk(fn)
+	}
+}
+
+func (n *Node2) walk(fn func(*Node2) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range &n.Children {
+		if c != nil {
+			c.walk(fn)
+		}
+	}
+}
+
+// DoBounded calls fn on each point of t that is within b, including its
+// boundary, until fn returns true. DoBounded returns whether fn returned
+// true.
+func (t *Quadtree) DoBounded(b r2.Box, fn func(Point2) (done bool)) bool {
+	if t.Root == nil {
+		return false
+	}
+	return t.Root.doBounded(b, fn)
+}
+
+func (n *Node2) doBounded(b r2.Box, fn func(Point2) bool) bool {
+	if n.Bounds.Max.X < b.Min.X || b.Max.X < n.Bounds.Min.X ||
+		n.Bounds.Max.Y < b.Min.Y || b.Max.Y < n.Bounds.Min.Y {
+		return false
+	}
+	for _, p := range n.Points {
+		c := p.Coord2()
+		if b.Min.X <= c.X && c.X <= b.Max.X && b.Min.Y <= c.Y && c.Y <= b.Max.Y && fn(p) {
+			return true
+		}
+	}
+	for _, c := range &n.Children {
+		if c != nil && c.doBounded(b, fn) {
+			return true
+		}
+	}
+	return false
+}
+
+// Nearest returns the point of t nearest to q and the Euclidean distance
+// between them. If t is empty, Nearest returns nil and +Inf.
+func (t *Quadtree) Nearest(q r2.Vec) (Point2, float64) {
+	best := t.nearest(q, 1)
+	if len(best) == 0 {
+		return nil, math.Inf(1)
+	}
+	return best[0].val.(Point2), math.Sqrt(best[0].dist)
+}
+
+// NearestN returns the k points of t nearest to q in order of increasing
+// distance. If t has fewer than k points, all the points are returned.
+func (t *Quadtree) NearestN(q r2.Vec, k int) []Point2 {
+	best := t.nearest(q, k)
+	p := make([]Point2, len(best))
+	for i, b := range best {
+		p[i] = b.val.(Point2)
+	}
+	return p
+}
+
+// nearest returns the k points of t nearest to q and their squared
+// distances in order of increasing distance.
+func (t *Quadtree) nearest(q r2.Vec, k int) []item {
+	if t.Root == nil || k < 1 {
+		return nil
+	}
+	nodes := minQueue{{val: t.Root, dist: boxDist2(t.Root.Bounds, q)}}
+	var best maxQueue
+	for len(nodes) != 0 {
+		it := heap.Pop(&nodes).(item)
+		if len(best) == k && it.dist > best[0].dist {
+			break
+		}
+		n := it.val.(*Node2)
+		for _, p := range n.Points {
+			d := p.Coord2().Sub(q)
+			keep(&best, item{val: p, dist: d.X*d.X + d.Y*d.Y}, k)
+		}
+		for _, c := range &n.Children {
+			if c != nil {
+				heap.Push(&nodes, item{val: c, dist: boxDist2(c.Bounds, q)})
+			}
+		}
+	}
+	sorted := make([]item, len(best))
+	for i := len(sorted) - 1; i >= 0; i-- {
+		sorted[i] = heap.Pop(&best).(item)
+	}
+	return sorted
+}
+
+// boxDist2 returns the squared distance from q to the nearest point of b.
+func boxDist2(b r2.Box, q r2.Vec) float64 {
+	dx := math.Max(0, math.Max(b.Min.X-q.X, q.X-b.Max.X))
+	dy := math.Max(0, math.Max(b.Min.Y-q.Y, q.Y-b.Max.Y))
+	return dx*dx + dy*dy
+}
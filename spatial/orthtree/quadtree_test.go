@@ -0,0 +1,186 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package orthtree
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/spatial/r2"
+)
+
+type particle2 struct {
+	r2.Vec
+	m float64
+}
+
+func (p particle2) Coord2() r2.Vec { return p.Vec }
+func (p particle2) Mass() float64  { return p.m }
+
+type point2 r2.Vec
+
+func (p point2) Coord2() r2.Vec { return r2.Vec(p) }
+
+func randomPoints2(rnd *rand.Rand, n int) []Point2 {
+	p := make([]Point2, n)
+	for i := range p {
+		p[i] = particle2{Vec: r2.Vec{X: rnd.NormFloat64(), Y: 10 * rnd.Float64()}, m: rnd.Float64()}
+	}
+	return p
+}
+
+func TestQuadtreeStructure(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := randomPoints2(rnd, 1000)
+	for i := 0; i < 20; i++ {
+		// Coincident points.
+		points = append(points, particle2{Vec: r2.Vec{X: 1, Y: 1}, m: 1})
+	}
+	const capacity = 4
+	tree := NewQuadtree(points, capacity)
+
+	var count int
+	root := tree.Root.Bounds.Max.X - tree.Root.Bounds.Min.X
+	tree.Walk(func(n *Node2) bool {
+		b := n.Bounds
+		if side := b.Max.X - b.Min.X; math.Abs(side-(b.Max.Y-b.Min.Y)) > 1e-14*root {
+			t.Errorf("node bounds not square: %+v", b)
+		}
+		var (
+			got    int
+			mass   float64
+			center r2.Vec
+		)
+		if n.IsLeaf() {
+			if len(n.Points) != n.Count || len(n.Points) == 0 {
+				t.Errorf("unexpected leaf point count: got:%d want:%d", len(n.Points), n.Count)
+			}
+			if len(n.Points) > capacity && !coincident2(n.Points) {
+				t.Errorf("leaf over capacity: %d points", len(n.Points))
+			}
+			for _, p := range n.Points {
+				c := p.Coord2()
+				if c.X < b.Min.X || b.Max.X < c.X || c.Y < b.Min.Y || b.Max.Y < c.Y {
+					t.Errorf("point %v outside leaf bounds %+v", c, b)
+				}
+				got++
+				m := massOf(p)
+				mass += m
+				center = center.Add(c.Scale(m))
+			}
+			count += got
+		} else {
+			if n.Points != nil {
+				t.Error("internal node holds points")
+			}
+			for _, c := range n.Children {
+				if c == nil {
+					continue
+				}
+				got += c.Count
+				mass += c.Mass
+				center = center.Add(c.Center.Scale(c.Mass))
+			}
+		}
+		if got != n.Count {
+			t.Errorf("unexpected node count: got:%d want:%d", got, n.Count)
+		}
+		center = center.Scale(1 / mass)
+		if math.Abs(mass-n.Mass) > 1e-10 || math.Abs(center.X-n.Center.X) > 1e-10 || math.Abs(center.Y-n.Center.Y) > 1e-10 {
+			t.Errorf("unexpected aggregate: got:%v,%v want:%v,%v", n.Center, n.Mass, center, mass)
+		}
+		return true
+	})
+	if count != len(points) {
+		t.Errorf("unexpected number of points in leaves: got:%d want:%d", count, len(points))
+	}
+
+	if tree := NewQuadtree(nil, 1); tree.Root != nil {
+		t.Error("unexpected root for empty tree")
+	}
+	if p, d := NewQuadtree(nil, 1).Nearest(r2.Vec{}); p != nil || !math.IsInf(d, 1) {
+		t.Errorf("unexpected nearest point for empty tree: %v %v", p, d)
+	}
+	tree = NewQuadtree([]Point2{point2{X: 1, Y: 2}, point2{X: 3, Y: 4}}, 1)
+	if tree.Root.Mass != 2 || tree.Root.Center != (r2.Vec{X: 2, Y: 3}) {
+		t.Errorf("unexpected unit mass aggregate: %v %v", tree.Root.Center, tree.Root.Mass)
+	}
+}
+
+func coincident2(p []Point2) bool {
+	for _, q := range p[1:] {
+		if q.Coord2() != p[0].Coord2() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuadtreeQueries(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := randomPoints2(rnd, 500)
+	tree := NewQuadtree(points, 3)
+
+	for i := 0; i < 100; i++ {
+		q := r2.Vec{X: 3 * rnd.NormFloat64(), Y: 12*rnd.Float64() - 1}
+		want := append([]Point2(nil), points...)
+		sort.Slice(want, func(i, j int) bool {
+			return dist2(want[i].Coord2(), q) < dist2(want[j].Coord2(), q)
+		})
+
+		p, d := tree.Nearest(q)
+		if p != want[0] || d != math.Sqrt(dist2(want[0].Coord2(), q)) {
+			t.Errorf("unexpected nearest point to %v: got:%v want:%v", q, p, want[0])
+		}
+		k := rnd.Intn(20)
+		got := tree.NearestN(q, k)
+		if len(got) != k {
+			t.Fatalf("unexpected number of nearest points: got:%d want:%d", len(got), k)
+		}
+		for j := range got {
+			if got[j] != want[j] {
+				t.Errorf("unexpected %d-th nearest point to %v: got:%v want:%v", j, q, got[j], want[j])
+			}
+		}
+
+		b := r2.Box{Min: q, Max: q.Add(r2.Vec{X: rnd.Float64(), Y: 3 * rnd.Float64()})}
+		var n int
+		tree.DoBounded(b, func(p Point2) bool {
+			c := p.Coord2()
+			if c.X < b.Min.X || b.Max.X < c.X || c.Y < b.Min.Y || b.Max.Y < c.Y {
+				t.Errorf("point %v outside query bounds %+v", c, b)
+			}
+			n++
+			return false
+		})
+		var wantN int
+		for _, p := range points {
+			c := p.Coord2()
+			if b.Min.X <= c.X && c.X <= b.Max.X && b.Min.Y <= c.Y && c.Y <= b.Max.Y {
+				wantN++
+			}
+		}
+		if n != wantN {
+			t.Errorf("unexpected number of points in %+v: got:%d want:%d", b, n, wantN)
+		}
+	}
+
+	if got := tree.NearestN(r2.Vec{}, len(points)+10); len(got) != len(points) {
+		t.Errorf("unexpected number of points for large k: got:%d want:%d", len(got), len(points))
+	}
+	var n int
+	done := tree.DoBounded(tree.Root.Bounds, func(Point2) bool { n++; return n == 5 })
+	if !done || n != 5 {
+		t.Errorf("DoBounded did not stop: done=%t n=%d", done, n)
+	}
+}
+
+func dist2(p, q r2.Vec) float64 {
+	d := p.Sub(q)
+	return d.X*d.X + d.Y*d.Y
+}
@@ -0,0 +1,110 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import "math"
+
+// Affine is a 2D affine transform that maps a vector p to A·p + T, where
+// the linear part A is held in row-major order. The zero value maps every
+// vector to the origin; Identity returns the transform that leaves vectors
+// unchanged.
+type Affine struct {
+	A [2][2]float64
+	T Vec
+}
+
+// Identity returns the identity transform.
+func Identity() Affine {
+	return Affine{A: [2][2]float64{{1, 0}, {0, 1}}}
+}
+
+// NewTranslation returns the transform that translates vectors by v.
+func NewTranslation(v Vec) Affine {
+	a := Identity()
+	a.T = v
+	return a
+}
+
+// NewRotation returns the transform that rotates vectors counter-clockwise
+// by theta radians about the point c.
+func NewRotation(theta float64, c Vec) Affine {
+	sin, cos := math.Sincos(theta)
+	a := Affine{A: [2][2]float64{{cos, -sin}, {sin, cos}}}
+	a.T = c.Sub(a.Apply(c))
+	return a
+}
+
+// NewScaling returns the transform that scales vectors by the factors
+// s.X and s.Y along the X and Y axes about the origin.
+func NewScaling(s Vec) Affine {
+	return Affine{A: [2][2]float64{{s.X, 0}, {0, s.Y}}}
+}
+
+// NewShear returns the transform that shears vectors about the origin,
+// mapping {X, Y} to {X + x·Y, Y + y·X}.
+func NewShear(x, y float64) Affine {
+	return Affine{A: [2][2]float64{{1, x}, {y, 1}}}
+}
+
+// Apply returns the transform of p.
+func (a Affine) Apply(p Vec) Vec {
+	return Vec{
+		X: a.A[0][0]*p.X + a.A[0][1]*p.Y + a.T.X,
+		Y: a.A[1][0]*p.X + a.A[1][1]*p.Y + a.T.Y,
+	}
+}
+
+// ApplyAll stores the transforms of the vectors in p into dst and returns
+// the result. If dst is nil or its length is less than the length of p, a
+// new slice is allocated. dst and p may be the same slice to transform the
+// vectors in place.
+func (a Affine) ApplyAll(dst, p []Vec) []Vec {
+	if len(dst) < len(p) {
+		dst = make([]Vec, len(p))
+	}
+	dst = dst[:len(p)]
+	for i, v := range p {
+		dst[i] = a.Apply(v)
+	}
+	return dst
+}
+
+// Compose returns the transform that applies a and then b.
+func (a Affine) Compose(b Affine) Affine {
+	var c Affine
+	for i := range c.A {
+		for j := range c.A[i] {
+			c.A[i][j] = b.A[i][0]*a.A[0][j] + b.A[i][1]*a.A[1][j]
+		}
+	}
+	c.T = b.Apply(a.T)
+	return c
+}
+
+// Det returns the determinant of the linear part of a. The magnitude of
+// the determinant is the factor by which a scales areas, and it is negative
+// if a reverses orientation.
+func (a Affine) Det() float64 {
+	return a.A[0][0]*a.A[1][1] - a.A[0][1]*a.A[1][0]
+}
+
+// Invert returns the inverse of a and whether a is invertible. If a is not
+// invertible, because its linear part is singular, the returned transform
+// is the zero value.
+func (a Affine) Invert() (inv Affine, ok bool) {
+	det := a.Det()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, false
+	}
+	inv.A = [2][2]float64{
+		{a.A[1][1] / det, -a.A[0][1] / det},
+		{-a.A[1][0] / det, a.A[0][0] / det},
+	}
+	inv.T = Vec{
+		X: -(inv.A[0][0]*a.T.X + inv.A[0][1]*a.T.Y),
+		Y: -(inv.A[1][0]*a.T.X + inv.A[1][1]*a.T.Y),
+	}
+	return inv, true
+}
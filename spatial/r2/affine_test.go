@@ -0,0 +1,130 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func vecEqualApprox(a, b Vec, tol float64) bool {
+	return math.Abs(a.X-b.X) <= tol && math.Abs(a.Y-b.Y) <= tol
+}
+
+var affineTests = []struct {
+	name string
+	a    Affine
+	p    Vec
+	want Vec
+}{
+	{name: "identity", a: Identity(), p: Vec{1, 2}, want: Vec{1, 2}},
+	{name: "translation", a: NewTranslation(Vec{-1, 3}), p: Vec{1, 2}, want: Vec{0, 5}},
+	{name: "rotation", a: NewRotation(math.Pi/2, Vec{}), p: Vec{1, 2}, want: Vec{-2, 1}},
+	{name: "rotation about point", a: NewRotation(math.Pi, Vec{1, 1}), p: Vec{2, 3}, want: Vec{0, -1}},
+	{name: "scaling", a: NewScaling(Vec{2, -3}), p: Vec{1, 2}, want: Vec{2, -6}},
+	{name: "shear", a: NewShear(2, 0.25), p: Vec{1, 2}, want: Vec{5, 2.25}},
+	{
+		name: "composed",
+		a:    NewScaling(Vec{2, 2}).Compose(NewRotation(-math.Pi/2, Vec{})).Compose(NewTranslation(Vec{1, 0})),
+		p:    Vec{1, 2},
+		want: Vec{5, -2},
+	},
+}
+
+func TestAffine(t *testing.T) {
+	const tol = 1e-14
+	for _, test := range affineTests {
+		got := test.a.Apply(test.p)
+		if !vecEqualApprox(got, test.want, tol) {
+			t.Errorf("unexpected result for %s: got:%v want:%v", test.name, got, test.want)
+		}
+		inv, ok := test.a.Invert()
+		if !ok {
+			t.Errorf("unexpected singular transform for %s", test.name)
+			continue
+		}
+		got = inv.Apply(test.want)
+		if !vecEqualApprox(got, test.p, tol) {
+			t.Errorf("unexpected inverse result for %s: got:%v want:%v", test.name, got, test.p)
+		}
+	}
+}
+
+func TestAffineCompose(t *testing.T) {
+	const tol = 1e-12
+	rnd := rand.New(rand.NewSource(1))
+	random := func() Affine {
+		var a Affine
+		for i := range a.A {
+			for j := range a.A[i] {
+				a.A[i][j] = rnd.NormFloat64()
+			}
+		}
+		a.T = Vec{rnd.NormFloat64(), rnd.NormFloat64()}
+		return a
+	}
+	for i := 0; i < 100; i++ {
+		a, b := random(), random()
+		c := a.Compose(b)
+		p := Vec{rnd.NormFloat64(), rnd.NormFloat64()}
+		want := b.Apply(a.Apply(p))
+		if got := c.Apply(p); !vecEqualApprox(got, want, tol) {
+			t.Errorf("unexpected composed result: got:%v want:%v", got, want)
+		}
+		if got, want := c.Det(), a.Det()*b.Det(); math.Abs(got-want) > tol*math.Max(1, math.Abs(want)) {
+			t.Errorf("unexpected determinant of composition: got:%v want:%v", got, want)
+		}
+		inv, ok := a.Invert()
+		if !ok {
+			t.Fatalf("unexpected singular transform: %v", a)
+		}
+		id := a.Compose(inv)
+		if got := id.Apply(p); !vecEqualApprox(got, p, tol) {
+			t.Errorf("unexpected result of composition with inverse: got:%v want:%v", got, p)
+		}
+	}
+}
+
+func TestAffineSingular(t *testing.T) {
+	for _, a := range []Affine{
+		{},
+		NewScaling(Vec{1, 0}),
+		NewShear(1, 1),
+		NewScaling(Vec{math.Inf(1), 1}),
+	} {
+		inv, ok := a.Invert()
+		if ok || inv != (Affine{}) {
+			t.Errorf("unexpected inverse of singular transform %v: got:%v", a, inv)
+		}
+	}
+}
+
+func TestAffineApplyAll(t *testing.T) {
+	a := NewRotation(math.Pi/3, Vec{1, 0}).Compose(NewTranslation(Vec{2, 1}))
+	p := []Vec{{0, 0}, {1, 2}, {-3, 4}}
+	want := make([]Vec, len(p))
+	for i, v := range p {
+		want[i] = a.Apply(v)
+	}
+
+	got := a.ApplyAll(nil, p)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unexpected result for vector %d: got:%v want:%v", i, got[i], want[i])
+		}
+	}
+
+	got = a.ApplyAll(p, p)
+	if &got[0] != &p[0] {
+		t.Error("in place transform allocated a new slice")
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unexpected in place result for vector %d: got:%v want:%v", i, got[i], want[i])
+		}
+	}
+}
@@ -2,5 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// Package r2 provides 2D vectors and boxes and operations on them.
+// Package r2 provides 2D vectors and boxes and operations on them, and
+// affine transforms, robust geometric predicates, polygon utilities and
+// enclosing shapes.
 package r2 // import "gonum.org/v1/gonum/spatial/r2"
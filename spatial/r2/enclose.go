@@ -0,0 +1,260 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// Circle is a circle in the plane.
+type Circle struct {
+	Center Vec
+	Radius float64
+}
+
+// Contains returns whether p is within the circle or on its boundary,
+// allowing for a small relative error in the radius.
+func (c Circle) Contains(p Vec) bool {
+	return dist(c.Center, p) <= c.Radius*(1+enclosingTol)
+}
+
+// enclosingTol is the relative tolerance used to determine whether a point
+// is within an enclosing circle.
+const enclosingTol = 1e-10
+
+// MinEnclosingCircle returns the smallest circle that contains all the
+// points. If points is empty, the zero Circle is returned.
+//
+// MinEnclosingCircle uses Welzl's algorithm, which takes O(n) expected time
+// for n points when the points are considered in random order. The src
+// parameter provides the source of randomness for the order. If src is nil
+// global rand package functions are used. The points slice is not
+// modified.
+func MinEnclosingCircle(points []Vec, src rand.Source) Circle {
+	if len(points) == 0 {
+		return Circle{}
+	}
+	p := make([]Vec, len(points))
+	copy(p, points)
+	shuffle := rand.Shuffle
+	if src != nil {
+		shuffle = rand.New(src).Shuffle
+	}
+	shuffle(len(p), func(i, j int) { p[i], p[j] = p[j], p[i] })
+
+	// The iterative form of Welzl's algorithm. When a
+	// point is outside the circle of the points before
+	// it, it is on the boundary of their smallest
+	// enclosing circle.
+	c := Circle{Center: p[0]}
+	for i := 1; i < len(p); i++ {
+		if c.Contains(p[i]) {
+			continue
+		}
+		c = Circle{Center: p[i]}
+		for j := 0; j < i; j++ {
+			if c.Contains(p[j]) {
+				continue
+			}
+			c = circle2(p[i], p[j])
+			for k := 0; k < j; k++ {
+				if !c.Contains(p[k]) {
+					c = circle3(p[i], p[j], p[k])
+				}
+			}
+		}
+	}
+	return c
+}
+
+// circle2 returns the smallest circle through a and b.
+func circle2(a, b Vec) Circle {
+	center := a.Add(b).Scale(0.5)
+	return Circle{Center: center, Radius: math.Max(dist(center, a), dist(center, b))}
+}
+
+// circle3 returns the circle through a, b and c. If the points are
+// collinear, the circle through the most distant pair is returned.
+func circle3(a, b, c Vec) Circle {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	d := 2 * (ab.X*ac.Y - ab.Y*ac.X)
+	if d == 0 {
+		best := circle2(a, b)
+		if s := circle2(a, c); s.Radius > best.Radius {
+			best = s
+		}
+		if s := circle2(b, c); s.Radius > best.Radius {
+			best = s
+		}
+		return best
+	}
+	ab2 := ab.X*ab.X + ab.Y*ab.Y
+	ac2 := ac.X*ac.X + ac.Y*ac.Y
+	u := Vec{
+		X: (ac.Y*ab2 - ab.Y*ac2) / d,
+		Y: (ab.X*ac2 - ac.X*ab2) / d,
+	}
+	center := a.Add(u)
+	return Circle{
+		Center: center,
+		Radius: math.Max(dist(center, a), math.Max(dist(center, b), dist(center, c))),
+	}
+}
+
+// OrientedBox is a rectangle in the plane with sides that need not be
+// parallel to the coordinate axes.
+type OrientedBox struct {
+	Center Vec
+
+	// Axis is the unit direction of the first pair
+	// of sides of the box. The second pair of sides
+	// is perpendicular to Axis.
+	Axis Vec
+
+	// Half holds the half lengths of the sides of
+	// the box along Axis and perpendicular to it.
+	Half Vec
+}
+
+// Vertices returns the vertices of the box in counter-clockwise order.
+func (b OrientedBox) Vertices() [4]Vec {
+	u := b.Axis.Scale(b.Half.X)
+	v := Vec{X: -b.Axis.Y, Y: b.Axis.X}.Scale(b.Half.Y)
+	return [4]Vec{
+		b.Center.Sub(u).Sub(v),
+		b.Center.Add(u).Sub(v),
+		b.Center.Add(u).Add(v),
+		b.Center.Sub(u).Add(v),
+	}
+}
+
+// Area returns the area of the box.
+func (b OrientedBox) Area() float64 {
+	return 4 * b.Half.X * b.Half.Y
+}
+
+// MinAreaBox returns the rectangle of smallest area that contains all the
+// points. The rectangle has a side collinear with an edge of the convex
+// hull of the points, and Axis is the direction of that edge. If the
+// points are collinear the box has zero width, and if points is empty the
+// zero OrientedBox is returned.
+//
+// MinAreaBox uses the rotating calipers method on the convex hull of the
+// points and takes O(n log n) time for n points.
+func MinAreaBox(points []Vec) OrientedBox {
+	hull := ConvexHull(nil, points)
+	switch len(hull) {
+	case 0:
+		return OrientedBox{}
+	case 1:
+		return OrientedBox{Center: hull[0], Axis: Vec{X: 1}}
+	case 2:
+		d := hull[1].Sub(hull[0])
+		l := math.Hypot(d.X, d.Y)
+		return OrientedBox{
+			Center: hull[0].Add(hull[1]).Scale(0.5),
+			Axis:   d.Scale(1 / l),
+			Half:   Vec{X: l / 2},
+		}
+	}
+
+	best := OrientedBox{Half: Vec{X: math.Inf(1), Y: math.Inf(1)}}
+	calipers(hull, func(i, left, right, top int, u, v Vec) {
+		o := hull[i]
+		lo := dot(u, hull[left].Sub(o))
+		hi := dot(u, hull[right].Sub(o))
+		h := dot(v, hull[top].Sub(o))
+		b := OrientedBox{
+			Center: o.Add(u.Scale((lo + hi) / 2)).Add(v.Scale(h / 2)),
+			Axis:   u,
+			Half:   Vec{X: (hi - lo) / 2, Y: h / 2},
+		}
+		if b.Area() < best.Area() {
+			best = b
+		}
+	})
+	return best
+}
+
+// Width returns the width of the points, the smallest distance between a
+// pair of parallel lines that enclose all the points, and the unit normal
+// of the lines. If the points are collinear the width is zero. If points
+// is empty, Width returns zero and the zero vector.
+//
+// Width uses the rotating calipers method on the convex hull of the points
+// and takes O(n log n) time for n points.
+func Width(points []Vec) (width float64, normal Vec) {
+	hull := ConvexHull(nil, points)
+	switch len(hull) {
+	case 0:
+		return 0, Vec{}
+	case 1:
+		return 0, Vec{Y: 1}
+	case 2:
+		d := hull[1].Sub(hull[0])
+		l := math.Hypot(d.X, d.Y)
+		return 0, Vec{X: -d.Y / l, Y: d.X / l}
+	}
+
+	width = math.Inf(1)
+	calipers(hull, func(i, _, _, top int, _, v Vec) {
+		if h := dot(v, hull[top].Sub(hull[i])); h < width {
+			width, normal = h, v
+		}
+	})
+	return width, normal
+}
+
+// calipers calls fn for each edge i of the convex polygon hull, given in
+// counter-clockwise order, with the unit direction of the edge u and the
+// unit normal v pointing into the polygon, and the indices of the vertices
+// of hull with the least and greatest projections onto u and the greatest
+// projection onto v.
+func calipers(hull []Vec, fn func(i, left, right, top int, u, v Vec)) {
+	n := len(hull)
+	next := func(i int) int { return (i + 1) % n }
+	right, top := 1, 1
+	var left int
+	for i := 0; i < n; i++ {
+		d := hull[next(i)].Sub(hull[i])
+		l := math.Hypot(d.X, d.Y)
+		u := d.Scale(1 / l)
+		v := Vec{X: -u.Y, Y: u.X}
+
+		// Advance each caliper while the next vertex
+		// is further in its direction. Each caliper
+		// moves around the hull at most twice over
+		// all edges.
+		for k := 0; k < n && dot(u, hull[next(right)].Sub(hull[right])) > 0; k++ {
+			right = next(right)
+		}
+		for k := 0; k < n && dot(v, hull[next(top)].Sub(hull[top])) > 0; k++ {
+			top = next(top)
+		}
+		if i == 0 {
+			// The least projection onto u
+			// follows the greatest onto v.
+			left = top
+		}
+		for k := 0; k < n && dot(u, hull[next(left)].Sub(hull[left])) < 0; k++ {
+			left = next(left)
+		}
+		fn(i, left, right, top, u, v)
+	}
+}
+
+// dot returns the dot product of p and q.
+func dot(p, q Vec) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// dist returns the Euclidean distance between p and q.
+func dist(p, q Vec) float64 {
+	d := p.Sub(q)
+	return math.Hypot(d.X, d.Y)
+}
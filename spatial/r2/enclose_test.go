@@ -0,0 +1,239 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+var minEnclosingCircleTests = []struct {
+	name   string
+	points []Vec
+	want   Circle
+}{
+	{name: "empty", want: Circle{}},
+	{name: "single", points: []Vec{{1, 2}}, want: Circle{Center: Vec{1, 2}}},
+	{name: "duplicate", points: []Vec{{1, 2}, {1, 2}, {1, 2}}, want: Circle{Center: Vec{1, 2}}},
+	{name: "pair", points: []Vec{{0, 0}, {2, 0}}, want: Circle{Center: Vec{1, 0}, Radius: 1}},
+	{name: "collinear", points: []Vec{{0, 0}, {1, 1}, {3, 3}, {2, 2}}, want: Circle{Center: Vec{1.5, 1.5}, Radius: math.Sqrt(4.5)}},
+	{name: "obtuse", points: []Vec{{-2, 0}, {2, 0}, {0, 1}}, want: Circle{Center: Vec{0, 0}, Radius: 2}},
+	{name: "equilateral", points: []Vec{{0, 0}, {2, 0}, {1, math.Sqrt(3)}}, want: Circle{Center: Vec{1, 1 / math.Sqrt(3)}, Radius: 2 / math.Sqrt(3)}},
+	{
+		name:   "square",
+		points: []Vec{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {0.5, 0.5}, {0.2, 0.9}},
+		want:   Circle{Center: Vec{0.5, 0.5}, Radius: math.Sqrt(0.5)},
+	},
+}
+
+func TestMinEnclosingCircle(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range minEnclosingCircleTests {
+		for seed := uint64(1); seed <= 5; seed++ {
+			got := MinEnclosingCircle(test.points, rand.NewSource(seed))
+			if !vecEqualApprox(got.Center, test.want.Center, tol) || math.Abs(got.Radius-test.want.Radius) > tol {
+				t.Errorf("unexpected circle for %s: got:%v want:%v", test.name, got, test.want)
+			}
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := 1 + rnd.Intn(20)
+		p := make([]Vec, n)
+		for j := range p {
+			if i%2 == 0 {
+				p[j] = Vec{float64(rnd.Intn(5)), float64(rnd.Intn(5))}
+			} else {
+				p[j] = Vec{rnd.NormFloat64(), rnd.NormFloat64()}
+			}
+		}
+		got := MinEnclosingCircle(p, rnd)
+		want := naiveEnclosingCircle(p)
+		if math.Abs(got.Radius-want.Radius) > tol*math.Max(1, want.Radius) || !vecEqualApprox(got.Center, want.Center, 1e-9) {
+			t.Errorf("unexpected circle for %v: got:%v want:%v", p, got, want)
+		}
+		for _, v := range p {
+			if !got.Contains(v) {
+				t.Errorf("circle %v does not contain %v", got, v)
+			}
+		}
+	}
+}
+
+// naiveEnclosingCircle returns the smallest enclosing circle of p by
+// testing all circles through pairs and triples of points.
+func naiveEnclosingCircle(p []Vec) Circle {
+	best := Circle{Center: p[0]}
+	if len(p) == 1 {
+		return best
+	}
+	best.Radius = math.Inf(1)
+	containsAll := func(c Circle) bool {
+		for _, v := range p {
+			if !c.Contains(v) {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range p {
+		for j := i + 1; j < len(p); j++ {
+			if c := circle2(p[i], p[j]); c.Radius < best.Radius && containsAll(c) {
+				best = c
+			}
+			for k := j + 1; k < len(p); k++ {
+				if c := circle3(p[i], p[j], p[k]); c.Radius < best.Radius && containsAll(c) {
+					best = c
+				}
+			}
+		}
+	}
+	return best
+}
+
+func TestMinAreaBox(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range []struct {
+		name     string
+		points   []Vec
+		wantArea float64
+		wantHalf Vec
+	}{
+		{name: "empty"},
+		{name: "single", points: []Vec{{1, 2}}},
+		{name: "segment", points: []Vec{{0, 0}, {3, 4}, {1.5, 2}}, wantHalf: Vec{2.5, 0}},
+		{name: "square", points: []Vec{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0.5, 0.5}}, wantArea: 1, wantHalf: Vec{0.5, 0.5}},
+		{name: "diamond", points: []Vec{{1, 0}, {0, 2}, {-1, 0}, {0, -2}}, wantArea: 6.4},
+		{name: "rotated rectangle", points: []Vec{{0, 0}, {3, 3}, {1, 5}, {-2, 2}}, wantArea: 3 * math.Sqrt2 * 2 * math.Sqrt2},
+	} {
+		got := MinAreaBox(test.points)
+		if math.Abs(got.Area()-test.wantArea) > tol {
+			t.Errorf("unexpected area for %s: got:%v want:%v", test.name, got.Area(), test.wantArea)
+		}
+		if test.wantHalf != (Vec{}) && !vecEqualApprox(got.Half, test.wantHalf, tol) {
+			t.Errorf("unexpected half lengths for %s: got:%v want:%v", test.name, got.Half, test.wantHalf)
+		}
+		checkBox(t, test.name, got, test.points)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := 3 + rnd.Intn(30)
+		p := make([]Vec, n)
+		for j := range p {
+			p[j] = Vec{rnd.NormFloat64() * 3, rnd.NormFloat64()}
+		}
+		got := MinAreaBox(p)
+		checkBox(t, "random", got, p)
+
+		// Test each side of the hull as the side of the box.
+		hull := ConvexHull(nil, p)
+		want := math.Inf(1)
+		for k, a := range hull {
+			d := hull[(k+1)%len(hull)].Sub(a)
+			u := d.Scale(1 / math.Hypot(d.X, d.Y))
+			v := Vec{-u.Y, u.X}
+			lo, hi, top := math.Inf(1), math.Inf(-1), math.Inf(-1)
+			for _, q := range hull {
+				lo = math.Min(lo, dot(u, q))
+				hi = math.Max(hi, dot(u, q))
+				top = math.Max(top, dot(v, q.Sub(a)))
+			}
+			want = math.Min(want, (hi-lo)*top)
+		}
+		if math.Abs(got.Area()-want) > 1e-10*want {
+			t.Errorf("unexpected area for %v: got:%v want:%v", p, got.Area(), want)
+		}
+	}
+}
+
+func checkBox(t *testing.T, name string, b OrientedBox, points []Vec) {
+	t.Helper()
+	if len(points) == 0 {
+		return
+	}
+	if math.Abs(math.Hypot(b.Axis.X, b.Axis.Y)-1) > 1e-14 {
+		t.Errorf("axis for %s not a unit vector: %v", name, b.Axis)
+	}
+	v := b.Vertices()
+	if Area(v[:]) < 0 {
+		t.Errorf("box vertices for %s not counter-clockwise: %v", name, v)
+	}
+	n := Vec{-b.Axis.Y, b.Axis.X}
+	for _, p := range points {
+		d := p.Sub(b.Center)
+		if math.Abs(dot(d, b.Axis)) > b.Half.X+1e-12 || math.Abs(dot(d, n)) > b.Half.Y+1e-12 {
+			t.Errorf("box for %s does not contain %v: %+v", name, p, b)
+		}
+	}
+}
+
+func TestWidth(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range []struct {
+		name   string
+		points []Vec
+		want   float64
+	}{
+		{name: "empty"},
+		{name: "single", points: []Vec{{1, 2}}},
+		{name: "segment", points: []Vec{{0, 0}, {3, 4}}},
+		{name: "square", points: []Vec{{0, 0}, {1, 0}, {1, 1}, {0, 1}}, want: 1},
+		{name: "triangle", points: []Vec{{0, 0}, {4, 0}, {1, 1}}, want: 1},
+		{name: "diamond", points: []Vec{{1, 0}, {0, 2}, {-1, 0}, {0, -2}}, want: 4 / math.Sqrt(5)},
+	} {
+		got, n := Width(test.points)
+		if math.Abs(got-test.want) > tol {
+			t.Errorf("unexpected width for %s: got:%v want:%v", test.name, got, test.want)
+		}
+		if len(test.points) != 0 {
+			if math.Abs(math.Hypot(n.X, n.Y)-1) > tol {
+				t.Errorf("normal for %s not a unit vector: %v", test.name, n)
+			}
+			lo, hi := math.Inf(1), math.Inf(-1)
+			for _, p := range test.points {
+				lo = math.Min(lo, dot(n, p))
+				hi = math.Max(hi, dot(n, p))
+			}
+			if math.Abs(hi-lo-got) > tol {
+				t.Errorf("unexpected extent along normal for %s: got:%v want:%v", test.name, hi-lo, got)
+			}
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		p := make([]Vec, 3+rnd.Intn(30))
+		for j := range p {
+			p[j] = Vec{rnd.NormFloat64(), rnd.NormFloat64() * 2}
+		}
+		got, _ := Width(p)
+		b := MinAreaBox(p)
+		// The width is no more than the smaller side of
+		// the minimum area box.
+		if got > 2*math.Min(b.Half.X, b.Half.Y)+tol {
+			t.Errorf("width exceeds minimum area box side: width:%v box:%+v", got, b)
+		}
+		// Check against all directions perpendicular
+		// to hull edges.
+		hull := ConvexHull(nil, p)
+		want := math.Inf(1)
+		for k, a := range hull {
+			d := hull[(k+1)%len(hull)].Sub(a)
+			v := Vec{-d.Y, d.X}.Scale(1 / math.Hypot(d.X, d.Y))
+			var top float64
+			for _, q := range hull {
+				top = math.Max(top, dot(v, q.Sub(a)))
+			}
+			want = math.Min(want, top)
+		}
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("unexpected width: got:%v want:%v", got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"sort"
+)
+
+// ConvexHull returns the vertices of the convex hull of points in
+// counter-clockwise order, starting from the vertex with the lowest X
+// and then Y coordinate. Points that lie on an edge of the hull are not
+// included. The hull is appended to dst, which may be nil. The points
+// slice is not modified.
+//
+// ConvexHull uses Andrew's monotone chain algorithm and runs in
+// O(n log n) time for n points. If points holds fewer than three
+// distinct points, or all points are collinear, the returned hull
+// holds the distinct extreme points.
+func ConvexHull(dst, points []Vec) []Vec {
+	p := make([]Vec, len(points))
+	copy(p, points)
+	sort.Slice(p, func(i, j int) bool {
+		if p[i].X != p[j].X {
+			return p[i].X < p[j].X
+		}
+		return p[i].Y < p[j].Y
+	})
+	// Remove duplicate points.
+	n := 0
+	for i, v := range p {
+		if i == 0 || v != p[n-1] {
+			p[n] = v
+			n++
+		}
+	}
+	p = p[:n]
+	if n < 3 {
+		return append(dst, p...)
+	}
+
+	// Build the lower hull from left to right
+	// and then the upper hull from right to
+	// left, dropping vertices that do not make
+	// a counter-clockwise turn.
+	start := len(dst)
+	for _, v := range p {
+		for len(dst) >= start+2 && Orient(dst[len(dst)-2], dst[len(dst)-1], v) <= 0 {
+			dst = dst[:len(dst)-1]
+		}
+		dst = append(dst, v)
+	}
+	lower := len(dst) + 1
+	for i := n - 2; i >= 0; i-- {
+		v := p[i]
+		for len(dst) >= lower && Orient(dst[len(dst)-2], dst[len(dst)-1], v) <= 0 {
+			dst = dst[:len(dst)-1]
+		}
+		dst = append(dst, v)
+	}
+	// The last vertex is the first.
+	return dst[:len(dst)-1]
+}
+
+// cross returns the z component of the cross product of a-o and b-o.
+// It is positive if o, a and b make a counter-clockwise turn, negative
+// if they make a clockwise turn and zero if they are collinear.
+func cross(o, a, b Vec) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// Area returns the signed area of the simple polygon with the given
+// vertices. The area is positive if the vertices are in counter-clockwise
+// order and negative if they are in clockwise order.
+func Area(polygon []Vec) float64 {
+	if len(polygon) < 3 {
+		return 0
+	}
+	// Sum the areas of the fan of triangles from the
+	// first vertex to avoid cancellation for polygons
+	// far from the origin.
+	var a float64
+	for i := 1; i < len(polygon)-1; i++ {
+		a += cross(polygon[0], polygon[i], polygon[i+1])
+	}
+	return a / 2
+}
+
+// Perimeter returns the perimeter of the closed polygon with the given
+// vertices.
+func Perimeter(polygon []Vec) float64 {
+	var l float64
+	for i, p := range polygon {
+		d := polygon[(i+1)%len(polygon)].Sub(p)
+		l += math.Hypot(d.X, d.Y)
+	}
+	return l
+}
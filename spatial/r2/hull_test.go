@@ -0,0 +1,118 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+var hullTests = []struct {
+	name   string
+	points []Vec
+	want   []Vec
+}{
+	{
+		name:   "empty",
+		points: nil,
+		want:   nil,
+	},
+	{
+		name:   "single",
+		points: []Vec{{1, 2}, {1, 2}},
+		want:   []Vec{{1, 2}},
+	},
+	{
+		name:   "collinear",
+		points: []Vec{{2, 2}, {0, 0}, {1, 1}, {3, 3}},
+		want:   []Vec{{0, 0}, {3, 3}},
+	},
+	{
+		name: "square",
+		points: []Vec{
+			{0, 0}, {1, 0}, {2, 0},
+			{0, 1}, {1, 1}, {2, 1},
+			{0, 2}, {1, 2}, {2, 2},
+			{0.5, 1.5}, {2, 0},
+		},
+		want: []Vec{{0, 0}, {2, 0}, {2, 2}, {0, 2}},
+	},
+	{
+		name:   "triangle",
+		points: []Vec{{0, 3}, {1, 1}, {-3, -1}, {3, -1}, {0, 0}},
+		want:   []Vec{{-3, -1}, {3, -1}, {0, 3}},
+	},
+}
+
+func TestConvexHull(t *testing.T) {
+	for _, test := range hullTests {
+		orig := append([]Vec(nil), test.points...)
+		got := ConvexHull(nil, test.points)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected hull for %s: got:%v want:%v", test.name, got, test.want)
+		}
+		if !reflect.DeepEqual(test.points, orig) {
+			t.Errorf("points modified for %s", test.name)
+		}
+
+		prefix := Vec{-1, -1}
+		got = ConvexHull([]Vec{prefix}, test.points)
+		if got[0] != prefix || len(got[1:]) != len(test.want) || (len(test.want) != 0 && !reflect.DeepEqual(got[1:], test.want)) {
+			t.Errorf("unexpected appended hull for %s: got:%v", test.name, got)
+		}
+	}
+}
+
+func TestConvexHullRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for n := 3; n < 200; n += 7 {
+		points := make([]Vec, n)
+		for i := range points {
+			points[i] = Vec{X: rnd.NormFloat64(), Y: rnd.NormFloat64()}
+		}
+		hull := ConvexHull(nil, points)
+		for i, a := range hull {
+			b := hull[(i+1)%len(hull)]
+			for _, p := range points {
+				if cross(a, b, p) < 0 {
+					t.Fatalf("point %v outside hull edge %v-%v for n=%d", p, a, b, n)
+				}
+			}
+		}
+		if Area(hull) <= 0 {
+			t.Errorf("hull not counter-clockwise for n=%d", n)
+		}
+	}
+}
+
+func TestAreaPerimeter(t *testing.T) {
+	square := []Vec{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+	if got := Area(square); got != 4 {
+		t.Errorf("unexpected area: got:%v want:4", got)
+	}
+	if got := Area([]Vec{{0, 0}, {0, 2}, {2, 2}, {2, 0}}); got != -4 {
+		t.Errorf("unexpected clockwise area: got:%v want:-4", got)
+	}
+	if got := Perimeter(square); got != 8 {
+		t.Errorf("unexpected perimeter: got:%v want:8", got)
+	}
+	triangle := []Vec{{0, 0}, {3, 0}, {0, 4}}
+	if got := Area(triangle); got != 6 {
+		t.Errorf("unexpected area: got:%v want:6", got)
+	}
+	if got := Perimeter(triangle); math.Abs(got-12) > 1e-14 {
+		t.Errorf("unexpected perimeter: got:%v want:12", got)
+	}
+	distant := []Vec{{1e8, 1e8}, {1e8 + 1, 1e8}, {1e8 + 1, 1e8 + 1}, {1e8, 1e8 + 1}}
+	if got := Area(distant); got != 1 {
+		t.Errorf("unexpected area far from origin: got:%v want:1", got)
+	}
+	if Area(nil) != 0 || Perimeter(nil) != 0 {
+		t.Error("unexpected non-zero measure for empty polygon")
+	}
+}
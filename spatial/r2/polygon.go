@@ -0,0 +1,120 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import "math"
+
+// Location is the location of a point relative to a polygon.
+type Location int
+
+const (
+	// Outside is a point outside the polygon.
+	Outside Location = iota
+	// OnBoundary is a point on an edge or vertex of the polygon.
+	OnBoundary
+	// Inside is a point strictly inside the polygon.
+	Inside
+)
+
+func (l Location) String() string {
+	switch l {
+	case Outside:
+		return "outside"
+	case OnBoundary:
+		return "on boundary"
+	case Inside:
+		return "inside"
+	}
+	return "invalid location"
+}
+
+// PointInPolygon returns the location of p relative to the closed polygon
+// with the given vertices. A point is inside the polygon if the boundary
+// winds around it a non-zero number of times, so the location does not
+// depend on the orientation of the polygon and self-overlapping regions
+// are inside. The location is determined exactly.
+func PointInPolygon(p Vec, polygon []Vec) Location {
+	var winding int
+	for i, u := range polygon {
+		v := polygon[(i+1)%len(polygon)]
+		o := Orient(u, v, p)
+		if o == 0 && onSegment(p, u, v) {
+			return OnBoundary
+		}
+		if u.Y <= p.Y {
+			if v.Y > p.Y && o > 0 {
+				// The edge crosses upward with p on its left.
+				winding++
+			}
+		} else if v.Y <= p.Y && o < 0 {
+			// The edge crosses downward with p on its right.
+			winding--
+		}
+	}
+	if winding != 0 {
+		return Inside
+	}
+	return Outside
+}
+
+// onSegment returns whether p, which must be collinear with a and b, is on
+// the segment from a to b.
+func onSegment(p, a, b Vec) bool {
+	return math.Min(a.X, b.X) <= p.X && p.X <= math.Max(a.X, b.X) &&
+		math.Min(a.Y, b.Y) <= p.Y && p.Y <= math.Max(a.Y, b.Y)
+}
+
+// SegmentsIntersect returns whether the closed segments from a0 to a1 and
+// from b0 to b1 share a point. The result is determined exactly.
+func SegmentsIntersect(a0, a1, b0, b1 Vec) bool {
+	o1 := sign(Orient(a0, a1, b0))
+	o2 := sign(Orient(a0, a1, b1))
+	o3 := sign(Orient(b0, b1, a0))
+	o4 := sign(Orient(b0, b1, a1))
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	// Otherwise the segments only intersect if an
+	// endpoint of one lies on the other.
+	return (o1 == 0 && onSegment(b0, a0, a1)) ||
+		(o2 == 0 && onSegment(b1, a0, a1)) ||
+		(o3 == 0 && onSegment(a0, b0, b1)) ||
+		(o4 == 0 && onSegment(a1, b0, b1))
+}
+
+func sign(v float64) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// NearestOnSegment returns the point on the segment from a to b that is
+// nearest to p.
+func NearestOnSegment(p, a, b Vec) Vec {
+	d := b.Sub(a)
+	l2 := d.X*d.X + d.Y*d.Y
+	if l2 == 0 {
+		return a
+	}
+	t := ((p.X-a.X)*d.X + (p.Y-a.Y)*d.Y) / l2
+	switch {
+	case t <= 0:
+		return a
+	case t >= 1:
+		return b
+	}
+	return a.Add(d.Scale(t))
+}
+
+// SegmentDistance returns the Euclidean distance from p to the nearest
+// point on the segment from a to b.
+func SegmentDistance(p, a, b Vec) float64 {
+	d := p.Sub(NearestOnSegment(p, a, b))
+	return math.Hypot(d.X, d.Y)
+}
@@ -0,0 +1,153 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"testing"
+)
+
+// notch is a counter-clockwise polygon with a reflex vertex at {2, 2}.
+var notch = []Vec{{0, 0}, {4, 0}, {4, 4}, {2, 2}, {0, 4}}
+
+var pointInPolygonTests = []struct {
+	p    Vec
+	want Location
+}{
+	{p: Vec{1, 1}, want: Inside},
+	{p: Vec{3, 1}, want: Inside},
+	{p: Vec{2, 1.9}, want: Inside},
+	{p: Vec{2, 3}, want: Outside},
+	{p: Vec{5, 1}, want: Outside},
+	{p: Vec{-1, 0}, want: Outside},
+	{p: Vec{5, 4}, want: Outside},
+	{p: Vec{0, 0}, want: OnBoundary},
+	{p: Vec{2, 0}, want: OnBoundary},
+	{p: Vec{4, 2}, want: OnBoundary},
+	{p: Vec{3, 3}, want: OnBoundary},
+	{p: Vec{2, 2}, want: OnBoundary},
+	{p: Vec{0, 4}, want: OnBoundary},
+	{p: Vec{1, 3}, want: OnBoundary},
+
+	// Points in line with vertices.
+	{p: Vec{-1, 4}, want: Outside},
+	{p: Vec{1, 2}, want: Inside},
+	{p: Vec{3, 4}, want: Outside},
+}
+
+func TestPointInPolygon(t *testing.T) {
+	rev := make([]Vec, len(notch))
+	for i, v := range notch {
+		rev[len(rev)-1-i] = v
+	}
+	for _, test := range pointInPolygonTests {
+		for _, poly := range [][]Vec{notch, rev} {
+			got := PointInPolygon(test.p, poly)
+			if got != test.want {
+				t.Errorf("unexpected location of %v in %v: got:%v want:%v", test.p, poly, got, test.want)
+			}
+		}
+	}
+
+	// A point a tiny distance from an edge that is
+	// misclassified by naive evaluation.
+	tri := []Vec{{0.5, 0.5}, {24, 24}, {24, 0}}
+	p := Vec{X: 12 + 0x1p-49, Y: 12}
+	if got := PointInPolygon(p, tri); got != Inside {
+		t.Errorf("unexpected location of %v: got:%v want:%v", p, got, Inside)
+	}
+	p = Vec{X: 12, Y: 12}
+	if got := PointInPolygon(p, tri); got != OnBoundary {
+		t.Errorf("unexpected location of %v: got:%v want:%v", p, got, OnBoundary)
+	}
+
+	// The regions of a self-overlapping
+	// polygon are inside.
+	twice := append(append([]Vec(nil), notch...), notch...)
+	if got := PointInPolygon(Vec{1, 1}, twice); got != Inside {
+		t.Errorf("unexpected location in doubly wound polygon: got:%v want:%v", got, Inside)
+	}
+
+	if got := PointInPolygon(Vec{}, nil); got != Outside {
+		t.Errorf("unexpected location in empty polygon: got:%v want:%v", got, Outside)
+	}
+}
+
+var segmentsIntersectTests = []struct {
+	a0, a1, b0, b1 Vec
+	want           bool
+}{
+	{a0: Vec{0, 0}, a1: Vec{2, 2}, b0: Vec{0, 2}, b1: Vec{2, 0}, want: true},
+	{a0: Vec{0, 0}, a1: Vec{1, 1}, b0: Vec{0, 2}, b1: Vec{2, 2}, want: false},
+	{a0: Vec{0, 0}, a1: Vec{2, 0}, b0: Vec{1, 0}, b1: Vec{1, 1}, want: true},
+	{a0: Vec{0, 0}, a1: Vec{2, 0}, b0: Vec{2, 0}, b1: Vec{3, 1}, want: true},
+	{a0: Vec{0, 0}, a1: Vec{2, 0}, b0: Vec{1, 0}, b1: Vec{3, 0}, want: true},
+	{a0: Vec{0, 0}, a1: Vec{2, 0}, b0: Vec{0.5, 0}, b1: Vec{1.5, 0}, want: true},
+	{a0: Vec{0, 0}, a1: Vec{1, 0}, b0: Vec{2, 0}, b1: Vec{3, 0}, want: false},
+	{a0: Vec{0, 0}, a1: Vec{1, 1}, b0: Vec{2, 2}, b1: Vec{3, 3}, want: false},
+	{a0: Vec{0, 0}, a1: Vec{2, 0}, b0: Vec{3, 0}, b1: Vec{3, 1}, want: false},
+	{a0: Vec{1, 1}, a1: Vec{1, 1}, b0: Vec{0, 0}, b1: Vec{2, 2}, want: true},
+	{a0: Vec{1, 1}, a1: Vec{1, 1}, b0: Vec{0, 1}, b1: Vec{2, 2}, want: false},
+	{a0: Vec{0.5, 0.5}, a1: Vec{24, 24}, b0: Vec{12, 0}, b1: Vec{12 + 0x1p-49, 12}, want: false},
+	{a0: Vec{0.5, 0.5}, a1: Vec{24, 24}, b0: Vec{12, 0}, b1: Vec{12, 12}, want: true},
+}
+
+func TestSegmentsIntersect(t *testing.T) {
+	for _, test := range segmentsIntersectTests {
+		for _, s := range [][4]Vec{
+			{test.a0, test.a1, test.b0, test.b1},
+			{test.a1, test.a0, test.b0, test.b1},
+			{test.b0, test.b1, test.a0, test.a1},
+			{test.b1, test.b0, test.a1, test.a0},
+		} {
+			got := SegmentsIntersect(s[0], s[1], s[2], s[3])
+			if got != test.want {
+				t.Errorf("unexpected intersection of %v-%v and %v-%v: got:%t want:%t",
+					s[0], s[1], s[2], s[3], got, test.want)
+			}
+		}
+	}
+}
+
+var segmentDistanceTests = []struct {
+	p, a, b Vec
+	want    Vec
+}{
+	{p: Vec{1, 1}, a: Vec{0, 0}, b: Vec{2, 0}, want: Vec{1, 0}},
+	{p: Vec{-1, 1}, a: Vec{0, 0}, b: Vec{2, 0}, want: Vec{0, 0}},
+	{p: Vec{3, -1}, a: Vec{0, 0}, b: Vec{2, 0}, want: Vec{2, 0}},
+	{p: Vec{0, 2}, a: Vec{0, 0}, b: Vec{2, 2}, want: Vec{1, 1}},
+	{p: Vec{1, 1}, a: Vec{1, 1}, b: Vec{3, 3}, want: Vec{1, 1}},
+	{p: Vec{3, 4}, a: Vec{0, 0}, b: Vec{0, 0}, want: Vec{0, 0}},
+}
+
+func TestSegmentDistance(t *testing.T) {
+	const tol = 1e-14
+	for _, test := range segmentDistanceTests {
+		got := NearestOnSegment(test.p, test.a, test.b)
+		if math.Abs(got.X-test.want.X) > tol || math.Abs(got.Y-test.want.Y) > tol {
+			t.Errorf("unexpected nearest point to %v on %v-%v: got:%v want:%v", test.p, test.a, test.b, got, test.want)
+		}
+		d := test.p.Sub(test.want)
+		want := math.Hypot(d.X, d.Y)
+		dist := SegmentDistance(test.p, test.a, test.b)
+		if math.Abs(dist-want) > tol {
+			t.Errorf("unexpected distance from %v to %v-%v: got:%v want:%v", test.p, test.a, test.b, dist, want)
+		}
+	}
+}
+
+func TestLocationString(t *testing.T) {
+	for l, want := range map[Location]string{
+		Outside:     "outside",
+		OnBoundary:  "on boundary",
+		Inside:      "inside",
+		Location(3): "invalid location",
+	} {
+		if got := l.String(); got != want {
+			t.Errorf("unexpected string for location %d: got:%q want:%q", int(l), got, want)
+		}
+	}
+}
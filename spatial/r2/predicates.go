@@ -0,0 +1,99 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import (
+	"math"
+	"math/big"
+)
+
+// The orientation and in-circle predicates evaluate their determinants in
+// floating point and fall back to exact rational arithmetic when the
+// result is within the forward error bound of zero. The error bounds are
+// from J. R. Shewchuk, "Adaptive Precision Floating-Point Arithmetic and
+// Fast Robust Geometric Predicates", Discrete & Computational Geometry
+// 18(3):305-363, 1997.
+const (
+	epsilon = 0x1p-53
+
+	ccwErrBound = (3 + 16*epsilon) * epsilon
+	iccErrBound = (10 + 96*epsilon) * epsilon
+)
+
+// Orient returns a positive value if a, b and c are in counter-clockwise
+// order, a negative value if they are in clockwise order and zero if they
+// are collinear. The sign of the result is exact. When the sign can be
+// determined in floating point the result approximates twice the area of
+// the triangle abc, otherwise only the sign is meaningful.
+func Orient(a, b, c Vec) float64 {
+	left := (a.X - c.X) * (b.Y - c.Y)
+	right := (a.Y - c.Y) * (b.X - c.X)
+	det := left - right
+	if math.Abs(det) > ccwErrBound*(math.Abs(left)+math.Abs(right)) {
+		return det
+	}
+	return float64(orientExact(a, b, c))
+}
+
+func orientExact(a, b, c Vec) int {
+	acx, acy := sub(a.X, c.X), sub(a.Y, c.Y)
+	bcx, bcy := sub(b.X, c.X), sub(b.Y, c.Y)
+	return mul(acx, bcy).Cmp(mul(acy, bcx))
+}
+
+// InCircle returns a positive value if d lies inside the circle through
+// a, b and c, a negative value if it lies outside, and zero if the four
+// points are cocircular. The points a, b and c must be in
+// counter-clockwise order for the sign to have this meaning. The sign of
+// the result is exact, but its magnitude is not meaningful.
+func InCircle(a, b, c, d Vec) float64 {
+	adx, ady := a.X-d.X, a.Y-d.Y
+	bdx, bdy := b.X-d.X, b.Y-d.Y
+	cdx, cdy := c.X-d.X, c.Y-d.Y
+
+	bdxcdy, cdxbdy := bdx*cdy, cdx*bdy
+	alift := adx*adx + ady*ady
+	cdxady, adxcdy := cdx*ady, adx*cdy
+	blift := bdx*bdx + bdy*bdy
+	adxbdy, bdxady := adx*bdy, bdx*ady
+	clift := cdx*cdx + cdy*cdy
+
+	det := alift*(bdxcdy-cdxbdy) + blift*(cdxady-adxcdy) + clift*(adxbdy-bdxady)
+	permanent := (math.Abs(bdxcdy)+math.Abs(cdxbdy))*alift +
+		(math.Abs(cdxady)+math.Abs(adxcdy))*blift +
+		(math.Abs(adxbdy)+math.Abs(bdxady))*clift
+	if math.Abs(det) > iccErrBound*permanent {
+		return det
+	}
+	return float64(incircleExact(a, b, c, d))
+}
+
+func incircleExact(a, b, c, d Vec) int {
+	adx, ady := sub(a.X, d.X), sub(a.Y, d.Y)
+	bdx, bdy := sub(b.X, d.X), sub(b.Y, d.Y)
+	cdx, cdy := sub(c.X, d.X), sub(c.Y, d.Y)
+
+	alift := new(big.Rat).Add(mul(adx, adx), mul(ady, ady))
+	blift := new(big.Rat).Add(mul(bdx, bdx), mul(bdy, bdy))
+	clift := new(big.Rat).Add(mul(cdx, cdx), mul(cdy, cdy))
+
+	det := mul(alift, new(big.Rat).Sub(mul(bdx, cdy), mul(cdx, bdy)))
+	det.Add(det, mul(blift, new(big.Rat).Sub(mul(cdx, ady), mul(adx, cdy))))
+	det.Add(det, mul(clift, new(big.Rat).Sub(mul(adx, bdy), mul(bdx, ady))))
+	return det.Sign()
+}
+
+// sub returns the exact difference x-y.
+func sub(x, y float64) *big.Rat {
+	var r, s big.Rat
+	r.SetFloat64(x)
+	s.SetFloat64(y)
+	return r.Sub(&r, &s)
+}
+
+// mul returns the exact product x*y.
+func mul(x, y *big.Rat) *big.Rat {
+	return new(big.Rat).Mul(x, y)
+}
@@ -0,0 +1,40 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r2
+
+import "testing"
+
+func TestPredicates(t *testing.T) {
+	// Points near the line y = x that are
+	// misclassified by naive evaluation.
+	a := Vec{X: 0.5, Y: 0.5}
+	b := Vec{X: 12, Y: 12}
+	c := Vec{X: 24, Y: 24}
+	for i := 0; i < 64; i++ {
+		for j := 0; j < 64; j++ {
+			p := Vec{X: a.X + float64(i)*0x1p-53, Y: a.Y + float64(j)*0x1p-53}
+			got := sign(Orient(p, b, c))
+			want := orientExact(p, b, c)
+			if got != want {
+				t.Fatalf("unexpected orientation of %v: got:%d want:%d", p, got, want)
+			}
+		}
+	}
+
+	o := Vec{}
+	x, y := Vec{X: 1}, Vec{Y: 1}
+	if Orient(o, x, y) <= 0 || Orient(o, y, x) >= 0 || Orient(o, x, Vec{X: 2}) != 0 {
+		t.Error("unexpected orientation")
+	}
+	if InCircle(x, y, Vec{X: -1}, o) <= 0 {
+		t.Error("centre not in circle")
+	}
+	if InCircle(x, y, Vec{X: -1}, Vec{Y: -1}) != 0 {
+		t.Error("cocircular point not on circle")
+	}
+	if InCircle(x, y, Vec{X: -1}, Vec{Y: -1 - 0x1p-52}) >= 0 {
+		t.Error("point outside circle not detected")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r3
+
+import "math"
+
+// Affine is a 3D affine transform that maps a vector p to A·p + T, where
+// the linear part A is held in row-major order. The zero value maps every
+// vector to the origin; Identity returns the transform that leaves vectors
+// unchanged.
+type Affine struct {
+	A [3][3]float64
+	T Vec
+}
+
+// Identity returns the identity transform.
+func Identity() Affine {
+	return Affine{A: [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}}
+}
+
+// NewTranslation returns the transform that translates vectors by v.
+func NewTranslation(v Vec) Affine {
+	a := Identity()
+	a.T = v
+	return a
+}
+
+// NewRotation returns the transform that rotates vectors by theta radians
+// about the line through c in the direction of axis. The rotation follows
+// the right-hand rule, so it is counter-clockwise when viewed with the axis
+// pointing toward the viewer.
+//
+// NewRotation will panic if axis is the zero vector.
+func NewRotation(theta float64, c, axis Vec) Affine {
+	n := Norm(axis)
+	if n == 0 {
+		panic("r3: zero rotation axis")
+	}
+	u := axis.Scale(1 / n)
+	sin, cos := math.Sincos(theta)
+	k := 1 - cos
+	a := Affine{A: [3][3]float64{
+		{cos + u.X*u.X*k, u.X*u.Y*k - u.Z*sin, u.X*u.Z*k + u.Y*sin},
+		{u.Y*u.X*k + u.Z*sin, cos + u.Y*u.Y*k, u.Y*u.Z*k - u.X*sin},
+		{u.Z*u.X*k - u.Y*sin, u.Z*u.Y*k + u.X*sin, cos + u.Z*u.Z*k},
+	}}
+	a.T = c.Sub(a.Apply(c))
+	return a
+}
+
+// NewScaling returns the transform that scales vectors by the factors
+// s.X, s.Y and s.Z along the X, Y and Z axes about the origin.
+func NewScaling(s Vec) Affine {
+	return Affine{A: [3][3]float64{{s.X, 0, 0}, {0, s.Y, 0}, {0, 0, s.Z}}}
+}
+
+// NewShear returns the transform that shears vectors about the origin,
+// mapping {X, Y, Z} to {X + xy·Y + xz·Z, Y + yx·X + yz·Z, Z + zx·X + zy·Y}.
+func NewShear(xy, xz, yx, yz, zx, zy float64) Affine {
+	return Affine{A: [3][3]float64{{1, xy, xz}, {yx, 1, yz}, {zx, zy, 1}}}
+}
+
+// Apply returns the transform of p.
+func (a Affine) Apply(p Vec) Vec {
+	return Vec{
+		X: a.A[0][0]*p.X + a.A[0][1]*p.Y + a.A[0][2]*p.Z + a.T.X,
+		Y: a.A[1][0]*p.X + a.A[1][1]*p.Y + a.A[1][2]*p.Z + a.T.Y,
+		Z: a.A[2][0]*p.X + a.A[2][1]*p.Y + a.A[2][2]*p.Z + a.T.Z,
+	}
+}
+
+// ApplyAll stores the transforms of the vectors in p into dst and returns
+// the result. If dst is nil or its length is less than the length of p, a
+// new slice is allocated. dst and p may be the same slice to transform the
+// vectors in place.
+func (a Affine) ApplyAll(dst, p []Vec) []Vec {
+	if len(dst) < len(p) {
+		dst = make([]Vec, len(p))
+	}
+	dst = dst[:len(p)]
+	for i, v := range p {
+		dst[i] = a.Apply(v)
+	}
+	return dst
+}
+
+// Compose returns the transform that applies a and then b.
+func (a Affine) Compose(b Affine) Affine {
+	var c Affine
+	for i := range c.A {
+		for j := range c.A[i] {
+			c.A[i][j] = b.A[i][0]*a.A[0][j] + b.A[i][1]*a.A[1][j] + b.A[i][2]*a.A[2][j]
+		}
+	}
+	c.T = b.Apply(a.T)
+	return c
+}
+
+// Det returns the determinant of the linear part of a. The magnitude of
+// the determinant is the factor by which a scales volumes, and it is
+// negative if a reverses orientation.
+func (a Affine) Det() float64 {
+	m := &a.A
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// Invert returns the inverse of a and whether a is invertible. If a is not
+// invertible, because its linear part is singular, the returned transform
+// is the zero value.
+func (a Affine) Invert() (inv Affine, ok bool) {
+	det := a.Det()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Affine{}, false
+	}
+	m := &a.A
+	inv.A = [3][3]float64{
+		{
+			(m[1][1]*m[2][2] - m[1][2]*m[2][1]) / det,
+			(m[0][2]*m[2][1] - m[0][1]*m[2][2]) / det,
+			(m[0][1]*m[1][2] - m[0][2]*m[1][1]) / det,
+		},
+		{
+			(m[1][2]*m[2][0] - m[1][0]*m[2][2]) / det,
+			(m[0][0]*m[2][2] - m[0][2]*m[2][0]) / det,
+			(m[0][2]*m[1][0] - m[0][0]*m[1][2]) / det,
+		},
+		{
+			(m[1][0]*m[2][1] - m[1][1]*m[2][0]) / det,
+			(m[0][1]*m[2][0] - m[0][0]*m[2][1]) / det,
+			(m[0][0]*m[1][1] - m[0][1]*m[1][0]) / det,
+		},
+	}
+	inv.T = Vec{}
+	inv.T = inv.Apply(a.T).Scale(-1)
+	return inv, true
+}
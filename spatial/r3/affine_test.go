@@ -0,0 +1,153 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r3
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func vecEqualApprox(a, b Vec, tol float64) bool {
+	return math.Abs(a.X-b.X) <= tol && math.Abs(a.Y-b.Y) <= tol && math.Abs(a.Z-b.Z) <= tol
+}
+
+var affineTests = []struct {
+	name string
+	a    Affine
+	p    Vec
+	want Vec
+}{
+	{name: "identity", a: Identity(), p: Vec{1, 2, 3}, want: Vec{1, 2, 3}},
+	{name: "translation", a: NewTranslation(Vec{-1, 3, 0.5}), p: Vec{1, 2, 3}, want: Vec{0, 5, 3.5}},
+	{name: "rotation z", a: NewRotation(math.Pi/2, Vec{}, Vec{0, 0, 1}), p: Vec{1, 2, 3}, want: Vec{-2, 1, 3}},
+	{name: "rotation x", a: NewRotation(math.Pi/2, Vec{}, Vec{2, 0, 0}), p: Vec{1, 2, 3}, want: Vec{1, -3, 2}},
+	{name: "rotation diagonal", a: NewRotation(2*math.Pi/3, Vec{}, Vec{1, 1, 1}), p: Vec{1, 2, 3}, want: Vec{3, 1, 2}},
+	{name: "rotation about line", a: NewRotation(math.Pi, Vec{1, 1, 0}, Vec{0, 0, -1}), p: Vec{2, 3, 4}, want: Vec{0, -1, 4}},
+	{name: "scaling", a: NewScaling(Vec{2, -3, 0.5}), p: Vec{1, 2, 3}, want: Vec{2, -6, 1.5}},
+	{name: "shear", a: NewShear(1, 0, 0, 2, 0.5, 0), p: Vec{1, 2, 3}, want: Vec{3, 8, 3.5}},
+	{
+		name: "composed",
+		a:    NewScaling(Vec{2, 2, 2}).Compose(NewRotation(-math.Pi/2, Vec{}, Vec{0, 0, 1})).Compose(NewTranslation(Vec{1, 0, -1})),
+		p:    Vec{1, 2, 3},
+		want: Vec{5, -2, 5},
+	},
+}
+
+func TestAffine(t *testing.T) {
+	const tol = 1e-14
+	for _, test := range affineTests {
+		got := test.a.Apply(test.p)
+		if !vecEqualApprox(got, test.want, tol) {
+			t.Errorf("unexpected result for %s: got:%v want:%v", test.name, got, test.want)
+		}
+		inv, ok := test.a.Invert()
+		if !ok {
+			t.Errorf("unexpected singular transform for %s", test.name)
+			continue
+		}
+		got = inv.Apply(test.want)
+		if !vecEqualApprox(got, test.p, tol) {
+			t.Errorf("unexpected inverse result for %s: got:%v want:%v", test.name, got, test.p)
+		}
+	}
+}
+
+func TestAffineCompose(t *testing.T) {
+	const tol = 1e-12
+	rnd := rand.New(rand.NewSource(1))
+	randVec := func() Vec {
+		return Vec{rnd.NormFloat64(), rnd.NormFloat64(), rnd.NormFloat64()}
+	}
+	random := func() Affine {
+		var a Affine
+		for i := range a.A {
+			for j := range a.A[i] {
+				a.A[i][j] = rnd.NormFloat64()
+			}
+		}
+		a.T = randVec()
+		return a
+	}
+	for i := 0; i < 100; i++ {
+		a, b := random(), random()
+		c := a.Compose(b)
+		p := randVec()
+		want := b.Apply(a.Apply(p))
+		if got := c.Apply(p); !vecEqualApprox(got, want, tol) {
+			t.Errorf("unexpected composed result: got:%v want:%v", got, want)
+		}
+		if got, want := c.Det(), a.Det()*b.Det(); math.Abs(got-want) > tol*math.Max(1, math.Abs(want)) {
+			t.Errorf("unexpected determinant of composition: got:%v want:%v", got, want)
+		}
+		inv, ok := a.Invert()
+		if !ok {
+			t.Fatalf("unexpected singular transform: %v", a)
+		}
+		id := a.Compose(inv)
+		if got := id.Apply(p); !vecEqualApprox(got, p, 1e-10) {
+			t.Errorf("unexpected result of composition with inverse: got:%v want:%v", got, p)
+		}
+
+		r := NewRotation(rnd.Float64()*2*math.Pi, randVec(), randVec())
+		if got := r.Det(); math.Abs(got-1) > tol {
+			t.Errorf("unexpected determinant of rotation: got:%v want:1", got)
+		}
+		q := randVec()
+		if got, want := Norm(r.Apply(p).Sub(r.Apply(q))), Norm(p.Sub(q)); math.Abs(got-want) > tol {
+			t.Errorf("rotation did not preserve distance: got:%v want:%v", got, want)
+		}
+	}
+}
+
+func TestAffineSingular(t *testing.T) {
+	for _, a := range []Affine{
+		{},
+		NewScaling(Vec{1, 0, 1}),
+		NewShear(1, 0, 1, 0, 0, 0),
+		NewScaling(Vec{math.NaN(), 1, 1}),
+	} {
+		inv, ok := a.Invert()
+		if ok || inv != (Affine{}) {
+			t.Errorf("unexpected inverse of singular transform %v: got:%v", a, inv)
+		}
+	}
+}
+
+func TestAffineApplyAll(t *testing.T) {
+	a := NewRotation(math.Pi/3, Vec{1, 0, 0}, Vec{0, 1, 1}).Compose(NewTranslation(Vec{2, 1, 0}))
+	p := []Vec{{0, 0, 0}, {1, 2, 3}, {-3, 4, -5}}
+	want := make([]Vec, len(p))
+	for i, v := range p {
+		want[i] = a.Apply(v)
+	}
+
+	got := a.ApplyAll(nil, p)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unexpected result for vector %d: got:%v want:%v", i, got[i], want[i])
+		}
+	}
+
+	got = a.ApplyAll(p, p)
+	if &got[0] != &p[0] {
+		t.Error("in place transform allocated a new slice")
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unexpected in place result for vector %d: got:%v want:%v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewRotationPanics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for zero rotation axis")
+		}
+	}()
+	NewRotation(1, Vec{}, Vec{})
+}
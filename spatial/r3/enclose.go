@@ -0,0 +1,150 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r3
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// Sphere is a sphere in 3D space.
+type Sphere struct {
+	Center Vec
+	Radius float64
+}
+
+// Contains returns whether p is within the sphere or on its boundary,
+// allowing for a small relative error in the radius.
+func (s Sphere) Contains(p Vec) bool {
+	return Norm(p.Sub(s.Center)) <= s.Radius*(1+enclosingTol)
+}
+
+// enclosingTol is the relative tolerance used to determine whether a point
+// is within an enclosing sphere.
+const enclosingTol = 1e-10
+
+// MinEnclosingSphere returns the smallest sphere that contains all the
+// points. If points is empty, the zero Sphere is returned.
+//
+// MinEnclosingSphere uses Welzl's algorithm, which takes O(n) expected
+// time for n points when the points are considered in random order. The
+// src parameter provides the source of randomness for the order. If src is
+// nil global rand package functions are used. The points slice is not
+// modified.
+func MinEnclosingSphere(points []Vec, src rand.Source) Sphere {
+	if len(points) == 0 {
+		return Sphere{}
+	}
+	p := make([]Vec, len(points))
+	copy(p, points)
+	shuffle := rand.Shuffle
+	if src != nil {
+		shuffle = rand.New(src).Shuffle
+	}
+	shuffle(len(p), func(i, j int) { p[i], p[j] = p[j], p[i] })
+
+	// The iterative form of Welzl's algorithm. When a
+	// point is outside the sphere of the points before
+	// it, it is on the boundary of their smallest
+	// enclosing sphere.
+	s := Sphere{Center: p[0]}
+	for i := 1; i < len(p); i++ {
+		if s.Contains(p[i]) {
+			continue
+		}
+		s = Sphere{Center: p[i]}
+		for j := 0; j < i; j++ {
+			if s.Contains(p[j]) {
+				continue
+			}
+			s = sphere2(p[i], p[j])
+			for k := 0; k < j; k++ {
+				if s.Contains(p[k]) {
+					continue
+				}
+				s = sphere3(p[i], p[j], p[k])
+				for l := 0; l < k; l++ {
+					if !s.Contains(p[l]) {
+						s = sphere4(p[i], p[j], p[k], p[l])
+					}
+				}
+			}
+		}
+	}
+	return s
+}
+
+// sphere2 returns the smallest sphere through a and b.
+func sphere2(a, b Vec) Sphere {
+	center := a.Add(b).Scale(0.5)
+	return Sphere{Center: center, Radius: math.Max(Norm(a.Sub(center)), Norm(b.Sub(center)))}
+}
+
+// sphere3 returns the smallest sphere through a, b and c, which has the
+// circumcircle of the triangle as a great circle. If the points are
+// collinear, the sphere through the most distant pair is returned.
+func sphere3(a, b, c Vec) Sphere {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	n := Cross(ab, ac)
+	d := 2 * Dot(n, n)
+	if d == 0 {
+		return farthestPair(a, b, c)
+	}
+	u := Cross(n, ab).Scale(Dot(ac, ac)).Add(Cross(ac, n).Scale(Dot(ab, ab))).Scale(1 / d)
+	return sphereAbout(a.Add(u), a, b, c)
+}
+
+// sphere4 returns the sphere through a, b, c and d. If the points are
+// coplanar, the sphere through the three points that contains the fourth
+// is returned.
+func sphere4(a, b, c, d Vec) Sphere {
+	ab := b.Sub(a)
+	ac := c.Sub(a)
+	ad := d.Sub(a)
+	det := 2 * Dot(ab, Cross(ac, ad))
+	if det == 0 {
+		best := Sphere{Radius: math.Inf(1)}
+		for _, s := range []Sphere{sphere3(a, b, c), sphere3(a, b, d), sphere3(a, c, d), sphere3(b, c, d)} {
+			if s.Radius < best.Radius && s.Contains(a) && s.Contains(b) && s.Contains(c) && s.Contains(d) {
+				best = s
+			}
+		}
+		if math.IsInf(best.Radius, 1) {
+			return farthestPair(a, b, c, d)
+		}
+		return best
+	}
+	u := Cross(ac, ad).Scale(Dot(ab, ab)).
+		Add(Cross(ad, ab).Scale(Dot(ac, ac))).
+		Add(Cross(ab, ac).Scale(Dot(ad, ad))).
+		Scale(1 / det)
+	return sphereAbout(a.Add(u), a, b, c, d)
+}
+
+// sphereAbout returns the smallest sphere about center that contains the
+// points p.
+func sphereAbout(center Vec, p ...Vec) Sphere {
+	s := Sphere{Center: center}
+	for _, v := range p {
+		s.Radius = math.Max(s.Radius, Norm(v.Sub(center)))
+	}
+	return s
+}
+
+// farthestPair returns the sphere through the most distant pair of the
+// points p.
+func farthestPair(p ...Vec) Sphere {
+	var best Sphere
+	for i, a := range p {
+		for _, b := range p[i+1:] {
+			if s := sphere2(a, b); s.Radius > best.Radius {
+				best = s
+			}
+		}
+	}
+	return best
+}
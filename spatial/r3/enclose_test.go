@@ -0,0 +1,113 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r3
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+var minEnclosingSphereTests = []struct {
+	name   string
+	points []Vec
+	want   Sphere
+}{
+	{name: "empty", want: Sphere{}},
+	{name: "single", points: []Vec{{1, 2, 3}}, want: Sphere{Center: Vec{1, 2, 3}}},
+	{name: "pair", points: []Vec{{0, 0, 0}, {2, 0, 0}}, want: Sphere{Center: Vec{1, 0, 0}, Radius: 1}},
+	{name: "collinear", points: []Vec{{0, 0, 0}, {1, 1, 1}, {2, 2, 2}}, want: Sphere{Center: Vec{1, 1, 1}, Radius: math.Sqrt(3)}},
+	{name: "equilateral", points: []Vec{{0, 0, 0}, {2, 0, 0}, {1, math.Sqrt(3), 0}}, want: Sphere{Center: Vec{1, 1 / math.Sqrt(3), 0}, Radius: 2 / math.Sqrt(3)}},
+	{
+		name:   "square",
+		points: []Vec{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0}, {0.5, 0.5, 0}},
+		want:   Sphere{Center: Vec{0.5, 0.5, 0}, Radius: math.Sqrt(0.5)},
+	},
+	{
+		name: "cube",
+		points: []Vec{
+			{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0},
+			{0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1},
+			{0.5, 0.5, 0.5},
+		},
+		want: Sphere{Center: Vec{0.5, 0.5, 0.5}, Radius: math.Sqrt(0.75)},
+	},
+	{
+		name:   "regular tetrahedron",
+		points: []Vec{{1, 1, 1}, {1, -1, -1}, {-1, 1, -1}, {-1, -1, 1}},
+		want:   Sphere{Center: Vec{0, 0, 0}, Radius: math.Sqrt(3)},
+	},
+}
+
+func TestMinEnclosingSphere(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range minEnclosingSphereTests {
+		for seed := uint64(1); seed <= 5; seed++ {
+			got := MinEnclosingSphere(test.points, rand.NewSource(seed))
+			if !vecEqualApprox(got.Center, test.want.Center, tol) || math.Abs(got.Radius-test.want.Radius) > tol {
+				t.Errorf("unexpected sphere for %s: got:%v want:%v", test.name, got, test.want)
+			}
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		n := 1 + rnd.Intn(12)
+		p := make([]Vec, n)
+		for j := range p {
+			if i%2 == 0 {
+				p[j] = Vec{float64(rnd.Intn(3)), float64(rnd.Intn(3)), float64(rnd.Intn(3))}
+			} else {
+				p[j] = Vec{rnd.NormFloat64(), rnd.NormFloat64(), rnd.NormFloat64()}
+			}
+		}
+		got := MinEnclosingSphere(p, rnd)
+		want := naiveEnclosingSphere(p)
+		if math.Abs(got.Radius-want.Radius) > 1e-10*math.Max(1, want.Radius) || !vecEqualApprox(got.Center, want.Center, 1e-8) {
+			t.Errorf("unexpected sphere for %v: got:%v want:%v", p, got, want)
+		}
+		for _, v := range p {
+			if !got.Contains(v) {
+				t.Errorf("sphere %v does not contain %v", got, v)
+			}
+		}
+	}
+}
+
+// naiveEnclosingSphere returns the smallest enclosing sphere of p by
+// testing all spheres through pairs, triples and quadruples of points.
+func naiveEnclosingSphere(p []Vec) Sphere {
+	best := Sphere{Center: p[0]}
+	if len(p) == 1 {
+		return best
+	}
+	best.Radius = math.Inf(1)
+	containsAll := func(s Sphere) bool {
+		for _, v := range p {
+			if !s.Contains(v) {
+				return false
+			}
+		}
+		return true
+	}
+	try := func(s Sphere) {
+		if s.Radius < best.Radius && containsAll(s) {
+			best = s
+		}
+	}
+	for i := range p {
+		for j := i + 1; j < len(p); j++ {
+			try(sphere2(p[i], p[j]))
+			for k := j + 1; k < len(p); k++ {
+				try(sphere3(p[i], p[j], p[k]))
+				for l := k + 1; l < len(p); l++ {
+					try(sphere4(p[i], p[j], p[k], p[l]))
+				}
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,277 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r3
+
+import "math"
+
+// Triangle is a triangular face of a mesh, holding the indices of its
+// three vertices.
+type Triangle [3]int
+
+// ConvexHull returns the triangular faces of the convex hull of points,
+// with vertices given as indices into points. The vertices of each face
+// are in counter-clockwise order when viewed from outside the hull, so
+// the face normals given by the right-hand rule point outward. Duplicate
+// points and points that lie within the hull or on one of its faces are
+// not hull vertices, so the faces of the hull that are not triangles are
+// split into triangles in an unspecified way.
+//
+// If the points do not span three dimensions, because there are fewer than
+// four distinct points or all the points are coplanar, ConvexHull returns
+// nil.
+//
+// ConvexHull uses the quickhull algorithm, starting from a tetrahedron of
+// extreme points. It takes O(n log n) expected time for n points and O(n^2)
+// time in the worst case.
+func ConvexHull(points []Vec) []Triangle {
+	if len(points) < 4 {
+		return nil
+	}
+	eps := tolerance(points)
+
+	simplex, ok := initialSimplex(points, eps)
+	if !ok {
+		return nil
+	}
+	h := hull{points: points, edges: make(map[[2]int]int), eps: eps}
+	a, b, c, d := simplex[0], simplex[1], simplex[2], simplex[3]
+	if h.plane(a, b, c).dist(points[d]) > 0 {
+		b, c = c, b
+	}
+	initial := []int{h.add(a, b, c), h.add(a, d, b), h.add(b, d, c), h.add(c, d, a)}
+	outside := make([]int, 0, len(points))
+	for i := range points {
+		if i != a && i != b && i != c && i != d {
+			outside = append(outside, i)
+		}
+	}
+	h.assign(outside, initial)
+
+	// Repeatedly add the farthest outside point
+	// of the first face that has outside points.
+	for j := 0; j < len(h.faces); j++ {
+		for !h.faces[j].dead && len(h.faces[j].outside) != 0 {
+			h.insert(j)
+		}
+	}
+
+	var t []Triangle
+	for _, f := range h.faces {
+		if !f.dead {
+			t = append(t, f.v)
+		}
+	}
+	return t
+}
+
+// tolerance returns the distance below which points are considered to
+// lie on a plane, scaled by the magnitude of the coordinates of points.
+func tolerance(points []Vec) float64 {
+	var m Vec
+	for _, p := range points {
+		m.X = math.Max(m.X, math.Abs(p.X))
+		m.Y = math.Max(m.Y, math.Abs(p.Y))
+		m.Z = math.Max(m.Z, math.Abs(p.Z))
+	}
+	const epsilon = 0x1p-52
+	return 3 * epsilon * (m.X + m.Y + m.Z)
+}
+
+// initialSimplex returns the indices of four points of points that form
+// a tetrahedron with a volume that is not small relative to eps, and
+// whether such points exist.
+func initialSimplex(points []Vec, eps float64) (simplex [4]int, ok bool) {
+	// Find the pair of extreme points along
+	// the axis with the largest extent.
+	var lo, hi [3]int
+	for i, p := range points {
+		for k, v := range [3]float64{p.X, p.Y, p.Z} {
+			if v < coord(points[lo[k]], k) {
+				lo[k] = i
+			}
+			if v > coord(points[hi[k]], k) {
+				hi[k] = i
+			}
+		}
+	}
+	axis := 0
+	for k := 1; k < 3; k++ {
+		if coord(points[hi[k]], k)-coord(points[lo[k]], k) > coord(points[hi[axis]], axis)-coord(points[lo[axis]], axis) {
+			axis = k
+		}
+	}
+	i0, i1 := lo[axis], hi[axis]
+	p0 := points[i0]
+	if coord(points[i1], axis)-coord(p0, axis) <= eps {
+		return simplex, false
+	}
+
+	// Find the point farthest from the line
+	// through the extreme points.
+	dir := points[i1].Sub(p0)
+	dir = dir.Scale(1 / Norm(dir))
+	i2 := -1
+	max := eps
+	for i, p := range points {
+		if d := Norm(Cross(p.Sub(p0), dir)); d > max {
+			i2, max = i, d
+		}
+	}
+	if i2 < 0 {
+		return simplex, false
+	}
+
+	// Find the point farthest from the plane
+	// through the three points.
+	n := Cross(points[i1].Sub(p0), points[i2].Sub(p0))
+	n = n.Scale(1 / Norm(n))
+	i3 := -1
+	max = eps
+	for i, p := range points {
+		if d := math.Abs(Dot(n, p.Sub(p0))); d > max {
+			i3, max = i, d
+		}
+	}
+	if i3 < 0 {
+		return simplex, false
+	}
+	return [4]int{i0, i1, i2, i3}, true
+}
+
+// coord returns the k-th coordinate of p.
+func coord(p Vec, k int) float64 {
+	switch k {
+	case 0:
+		return p.X
+	case 1:
+		return p.Y
+	default:
+		return p.Z
+	}
+}
+
+// hull is a convex hull under construction.
+type hull struct {
+	points []Vec
+	faces  []face
+
+	// edges holds the index of the face
+	// with each directed edge.
+	edges map[[2]int]int
+
+	eps float64
+}
+
+// face is a face of a hull with its outward
+// unit normal and its offset from the origin.
+type face struct {
+	v      Triangle
+	normal Vec
+	offset float64
+
+	// outside holds the indices of the
+	// points assigned to the face that
+	// are in front of it.
+	outside []int
+
+	dead bool
+}
+
+// dist returns the signed distance of p above the plane of f.
+func (f face) dist(p Vec) float64 {
+	return Dot(f.normal, p) - f.offset
+}
+
+// plane returns the face with the vertices a, b and c.
+func (h *hull) plane(a, b, c int) face {
+	pa := h.points[a]
+	n := Cross(h.points[b].Sub(pa), h.points[c].Sub(pa))
+	n = n.Scale(1 / Norm(n))
+	return face{v: Triangle{a, b, c}, normal: n, offset: Dot(n, pa)}
+}
+
+// add adds the face with vertices a, b and c to the hull and returns
+// its index.
+func (h *hull) add(a, b, c int) int {
+	i := len(h.faces)
+	h.faces = append(h.faces, h.plane(a, b, c))
+	h.edges[[2]int{a, b}] = i
+	h.edges[[2]int{b, c}] = i
+	h.edges[[2]int{c, a}] = i
+	return i
+}
+
+// assign assigns each of the points to the first of the faces that it is
+// more than eps in front of. Points that are not in front of any of the
+// faces are discarded.
+func (h *hull) assign(points, faces []int) {
+	for _, i := range points {
+		p := h.points[i]
+		for _, j := range faces {
+			f := &h.faces[j]
+			if f.dist(p) > h.eps {
+				f.outside = append(f.outside, i)
+				break
+			}
+		}
+	}
+}
+
+// insert adds the outside point of face j that is farthest from it to
+// the hull, replacing the faces visible from the point with faces joining
+// it to the horizon of the visible faces.
+func (h *hull) insert(j int) {
+	f := &h.faces[j]
+	i := f.outside[0]
+	max := f.dist(h.points[i])
+	for _, k := range f.outside[1:] {
+		if d := f.dist(h.points[k]); d > max {
+			i, max = k, d
+		}
+	}
+	p := h.points[i]
+
+	// Find the connected region of faces
+	// visible from p, and its horizon.
+	lit := []int{j}
+	visible := map[int]bool{j: true}
+	var horizon [][2]int
+	for n := 0; n < len(lit); n++ {
+		v := h.faces[lit[n]].v
+		for k := range v {
+			e := [2]int{v[k], v[(k+1)%3]}
+			adj := h.edges[[2]int{e[1], e[0]}]
+			if visible[adj] {
+				continue
+			}
+			if h.faces[adj].dist(p) > h.eps {
+				visible[adj] = true
+				lit = append(lit, adj)
+				continue
+			}
+			horizon = append(horizon, e)
+		}
+	}
+
+	var outside []int
+	for _, k := range lit {
+		f := &h.faces[k]
+		f.dead = true
+		for n := range f.v {
+			delete(h.edges, [2]int{f.v[n], f.v[(n+1)%3]})
+		}
+		for _, o := range f.outside {
+			if o != i {
+				outside = append(outside, o)
+			}
+		}
+		f.outside = nil
+	}
+	added := make([]int, 0, len(horizon))
+	for _, e := range horizon {
+		added = append(added, h.add(e[0], e[1], i))
+	}
+	h.assign(outside, added)
+}
@@ -0,0 +1,125 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r3
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestConvexHull(t *testing.T) {
+	var cube []Vec
+	for _, x := range []float64{0, 0.5, 1} {
+		for _, y := range []float64{0, 0.5, 1} {
+			for _, z := range []float64{0, 0.5, 1} {
+				cube = append(cube, Vec{x, y, z})
+			}
+		}
+	}
+	cube = append(cube, cube[:5]...)
+
+	rnd := rand.New(rand.NewSource(1))
+	ball := make([]Vec, 500)
+	for i := range ball {
+		ball[i] = Vec{rnd.NormFloat64(), rnd.NormFloat64(), rnd.NormFloat64()}
+	}
+
+	sphere := make([]Vec, 1000)
+	for i := range sphere {
+		v := Vec{rnd.NormFloat64(), rnd.NormFloat64(), rnd.NormFloat64()}
+		sphere[i] = v.Scale(1 / Norm(v))
+	}
+
+	var grid []Vec
+	for x := 0; x < 10; x++ {
+		for y := 0; y < 10; y++ {
+			for z := 0; z < 10; z++ {
+				grid = append(grid, Vec{float64(x), float64(y), float64(z)})
+			}
+		}
+	}
+
+	for _, test := range []struct {
+		name      string
+		points    []Vec
+		wantVerts int
+	}{
+		{name: "tetrahedron", points: []Vec{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}, wantVerts: 4},
+		{name: "cube", points: cube, wantVerts: 8},
+		{name: "ball", points: ball, wantVerts: -1},
+		{name: "sphere", points: sphere, wantVerts: len(sphere)},
+		{name: "grid", points: grid, wantVerts: 8},
+	} {
+		got := ConvexHull(test.points)
+		checkHull(t, test.name, test.points, got)
+		verts := make(map[int]bool)
+		for _, f := range got {
+			for _, v := range f {
+				verts[v] = true
+			}
+		}
+		if test.wantVerts >= 0 && len(verts) != test.wantVerts {
+			t.Errorf("unexpected number of hull vertices for %s: got:%d want:%d", test.name, len(verts), test.wantVerts)
+		}
+	}
+}
+
+// checkHull checks that faces is a closed consistently oriented triangle
+// mesh with all points on or behind each of its faces.
+func checkHull(t *testing.T, name string, points []Vec, faces []Triangle) {
+	t.Helper()
+	edges := make(map[[2]int]bool)
+	verts := make(map[int]bool)
+	for _, f := range faces {
+		for k := range f {
+			e := [2]int{f[k], f[(k+1)%3]}
+			if edges[e] {
+				t.Errorf("repeated directed edge %v for %s", e, name)
+			}
+			edges[e] = true
+			verts[f[k]] = true
+		}
+	}
+	for e := range edges {
+		if !edges[[2]int{e[1], e[0]}] {
+			t.Errorf("unmatched edge %v for %s", e, name)
+		}
+	}
+	if len(faces) != 2*len(verts)-4 {
+		t.Errorf("unexpected Euler characteristic for %s: %d vertices and %d faces", name, len(verts), len(faces))
+	}
+
+	eps := tolerance(points)
+	var h hull
+	h.points = points
+	for _, f := range faces {
+		plane := h.plane(f[0], f[1], f[2])
+		for i, p := range points {
+			if d := plane.dist(p); d > eps {
+				t.Errorf("point %d is %v in front of face %v for %s", i, d, f, name)
+				return
+			}
+		}
+	}
+}
+
+func TestConvexHullDegenerate(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		points []Vec
+	}{
+		{name: "empty"},
+		{name: "too few", points: []Vec{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}},
+		{name: "duplicates", points: []Vec{{1, 2, 3}, {1, 2, 3}, {1, 2, 3}, {1, 2, 3}}},
+		{name: "collinear", points: []Vec{{0, 0, 0}, {1, 1, 1}, {2, 2, 2}, {3, 3, 3}, {-1, -1, -1}}},
+		{name: "coplanar", points: []Vec{{0, 0, 1}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1}, {0.5, 0.5, 1}}},
+		{name: "repeated tetrahedron base", points: []Vec{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 0}}},
+	} {
+		if got := ConvexHull(test.points); got != nil {
+			t.Errorf("expected nil hull for %s: got:%v", test.name, got)
+		}
+	}
+}
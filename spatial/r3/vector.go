@@ -4,6 +4,8 @@
 
 package r3
 
+import "math"
+
 // Vec is a 3D vector.
 type Vec struct {
 	X, Y, Z float64
@@ -33,6 +35,25 @@ func (p Vec) Scale(f float64) Vec {
 	return p
 }
 
+// Dot returns the dot product p·q.
+func Dot(p, q Vec) float64 {
+	return p.X*q.X + p.Y*q.Y + p.Z*q.Z
+}
+
+// Cross returns the cross product p×q.
+func Cross(p, q Vec) Vec {
+	return Vec{
+		p.Y*q.Z - p.Z*q.Y,
+		p.Z*q.X - p.X*q.Z,
+		p.X*q.Y - p.Y*q.X,
+	}
+}
+
+// Norm returns the Euclidean norm of p, |p| = sqrt(p_x^2 + p_y^2 + p_z^2).
+func Norm(p Vec) float64 {
+	return math.Hypot(p.X, math.Hypot(p.Y, p.Z))
+}
+
 // Box is a 3D bounding box.
 type Box struct {
 	Min, Max Vec
@@ -0,0 +1,30 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package r3
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVecProducts(t *testing.T) {
+	x, y, z := Vec{1, 0, 0}, Vec{0, 1, 0}, Vec{0, 0, 1}
+	if got := Cross(x, y); got != z {
+		t.Errorf("unexpected x×y: got:%v want:%v", got, z)
+	}
+	if got := Cross(z, y); got != x.Scale(-1) {
+		t.Errorf("unexpected z×y: got:%v want:%v", got, x.Scale(-1))
+	}
+	p, q := Vec{1, 2, 3}, Vec{-4, 5, 0.5}
+	if got := Dot(p, q); got != 7.5 {
+		t.Errorf("unexpected dot product: got:%v want:7.5", got)
+	}
+	if c := Cross(p, q); Dot(c, p) != 0 || Dot(c, q) != 0 {
+		t.Errorf("cross product not orthogonal: %v", c)
+	}
+	if got := Norm(Vec{2, 3, 6}); math.Abs(got-7) > 1e-14 {
+		t.Errorf("unexpected norm: got:%v want:7", got)
+	}
+}
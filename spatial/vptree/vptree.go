@@ -8,7 +8,10 @@ import (
 	"container/heap"
 	"errors"
 	"math"
+	"runtime"
 	"sort"
+	"sync"
+	"sync/atomic"
 
 	"golang.org/x/exp/rand"
 
@@ -349,6 +352,77 @@ func (t *Tree) NearestSet(k Keeper, q Comparable) {
 	}
 }
 
+// NearestSets finds the nearest values to each of the queries in q, performing
+// the searches concurrently on the given number of workers. If workers is less
+// than one, runtime.GOMAXPROCS(0) workers are used.
+//
+// Each search is performed as described for NearestSet with a Keeper obtained by
+// calling keeper, and fn is called with the index of the query in q and the Keeper
+// holding the results of the search. If keeper returns an *NKeeper or *DistKeeper
+// holding only its initial maximum, as those returned by NewNKeeper and
+// NewDistKeeper do, the Keeper is returned to that state after fn returns and
+// reused for later searches by the same worker, so fn must not retain it. Other
+// Keepers are not modified by NearestSets after fn returns, and keeper is called
+// for each search. Calls to keeper and fn are made concurrently.
+func (t *Tree) NearestSets(q []Comparable, workers int, keeper func() Keeper, fn func(i int, k Keeper)) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(q) {
+		workers = len(q)
+	}
+	next := int64(-1)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			var (
+				k        Keeper
+				h        *Heap
+				sentinel ComparableDist
+			)
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(q) {
+					return
+				}
+				if k == nil {
+					k = keeper()
+					h, sentinel = reusableHeap(k)
+				}
+				t.NearestSet(k, q[i])
+				fn(i, k)
+				if h != nil {
+					*h = append((*h)[:0], sentinel)
+				} else {
+					k = nil
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// reusableHeap returns the heap of k and its initial maximum if k is an
+// *NKeeper or *DistKeeper holding only its initial maximum, and nil
+// otherwise.
+func reusableHeap(k Keeper) (*Heap, ComparableDist) {
+	var h *Heap
+	switch k := k.(type) {
+	case *NKeeper:
+		h = &k.Heap
+	case *DistKeeper:
+		h = &k.Heap
+	default:
+		return nil, ComparableDist{}
+	}
+	if len(*h) != 1 || (*h)[0].Comparable != nil {
+		return nil, ComparableDist{}
+	}
+	return h, (*h)[0]
+}
+
 func (n *Node) searchSet(q Comparable, k Keeper) {
 	if n == nil {
 		return
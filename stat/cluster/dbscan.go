@@ -0,0 +1,172 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// Noise is the label given to observations that do not belong
+// to any cluster by the density-based clustering algorithms.
+const Noise = -1
+
+// DBSCANResult holds the result of a DBSCAN clustering.
+type DBSCANResult struct {
+	// Labels holds the index of the cluster
+	// of each observation, or Noise.
+	Labels []int
+
+	// Core holds whether each observation
+	// is a core point of its cluster.
+	Core []bool
+
+	// NumClusters is the number of clusters.
+	NumClusters int
+}
+
+// DBSCAN partitions the observations in the rows of x into clusters of
+// densely connected observations using the DBSCAN algorithm, see Ester et
+// al., "A density-based algorithm for discovering clusters in large spatial
+// databases with noise", KDD 1996.
+//
+// An observation is a core point if at least minPts observations, including
+// itself, lie within the Euclidean distance eps of it. Clusters are the
+// connected components of core points within eps of each other, together
+// with the non-core points within eps of one of their core points. The
+// remaining observations are labeled as Noise. A non-core point within eps
+// of core points of several clusters is assigned to the cluster found first.
+// Unlike k-means, DBSCAN does not need the number of clusters and finds
+// clusters of arbitrary shape. The region queries are performed
+// concurrently using a k-d tree.
+//
+// DBSCAN panics if x has no rows, if eps is negative or if minPts is not
+// positive.
+func DBSCAN(x mat.Matrix, eps float64, minPts int) *DBSCANResult {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic("cluster: no observations")
+	}
+	if eps < 0 {
+		panic("cluster: negative eps")
+	}
+	if minPts <= 0 {
+		panic("cluster: invalid minimum number of points")
+	}
+
+	pts := newIndexedPoints(x)
+	tree := kdtree.New(append(indexedPoints(nil), pts...), false)
+	neighbors := make([][]int, n)
+	tree.NearestSets(pts.comparables(), 0,
+		func() kdtree.Keeper { return kdtree.NewDistKeeper(eps * eps) },
+		func(i int, k kdtree.Keeper) {
+			h := k.(*kdtree.DistKeeper).Heap
+			nb := make([]int, len(h))
+			for j, c := range h {
+				nb[j] = c.Comparable.(indexedPoint).idx
+			}
+			neighbors[i] = nb
+		},
+	)
+
+	res := &DBSCANResult{
+		Labels: make([]int, n),
+		Core:   make([]bool, n),
+	}
+	for i, nb := range neighbors {
+		res.Labels[i] = Noise
+		res.Core[i] = len(nb) >= minPts
+	}
+	var queue []int
+	for i := range neighbors {
+		if !res.Core[i] || res.Labels[i] != Noise {
+			continue
+		}
+		// Expand a new cluster from the core point i
+		// through the neighborhoods of its core points.
+		c := res.NumClusters
+		res.NumClusters++
+		res.Labels[i] = c
+		queue = append(queue[:0], i)
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			for _, q := range neighbors[p] {
+				if res.Labels[q] != Noise {
+					continue
+				}
+				res.Labels[q] = c
+				if res.Core[q] {
+					queue = append(queue, q)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// indexedPoint is a k-d tree point that retains the index of its
+// observation.
+type indexedPoint struct {
+	x   []float64
+	idx int
+}
+
+func (p indexedPoint) Compare(c kdtree.Comparable, d kdtree.Dim) float64 {
+	return p.x[d] - c.(indexedPoint).x[d]
+}
+func (p indexedPoint) Dims() int { return len(p.x) }
+func (p indexedPoint) Distance(c kdtree.Comparable) float64 {
+	return sqDist(p.x, c.(indexedPoint).x)
+}
+
+// indexedPoints is a collection of indexedPoint values that satisfies
+// the kdtree.Interface.
+type indexedPoints []indexedPoint
+
+// newIndexedPoints returns the rows of x as indexedPoints.
+func newIndexedPoints(x mat.Matrix) indexedPoints {
+	xd := mat.DenseCopyOf(x)
+	n, _ := xd.Dims()
+	p := make(indexedPoints, n)
+	for i := range p {
+		p[i] = indexedPoint{x: xd.RawRowView(i), idx: i}
+	}
+	return p
+}
+
+// comparables returns the points as a slice of kdtree.Comparable.
+func (p indexedPoints) comparables() []kdtree.Comparable {
+	c := make([]kdtree.Comparable, len(p))
+	for i, v := range p {
+		c[i] = v
+	}
+	return c
+}
+
+func (p indexedPoints) Index(i int) kdtree.Comparable { return p[i] }
+func (p indexedPoints) Len() int                      { return len(p) }
+func (p indexedPoints) Pivot(d kdtree.Dim) int {
+	pl := indexedPlane{indexedPoints: p, Dim: d}
+	return kdtree.Partition(pl, kdtree.MedianOfRandoms(pl, 100))
+}
+func (p indexedPoints) Slice(start, end int) kdtree.Interface { return p[start:end] }
+
+// indexedPlane allows an indexedPoints to be pivoted on a dimension.
+type indexedPlane struct {
+	kdtree.Dim
+	indexedPoints
+}
+
+func (p indexedPlane) Less(i, j int) bool {
+	return p.indexedPoints[i].x[p.Dim] < p.indexedPoints[j].x[p.Dim]
+}
+func (p indexedPlane) Slice(start, end int) kdtree.SortSlicer {
+	p.indexedPoints = p.indexedPoints[start:end]
+	return p
+}
+func (p indexedPlane) Swap(i, j int) {
+	p.indexedPoints[i], p.indexedPoints[j] = p.indexedPoints[j], p.indexedPoints[i]
+}
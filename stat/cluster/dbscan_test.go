@@ -0,0 +1,118 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// moons returns n observations on two interleaved half circles with
+// normal noise of the given standard deviation, followed by outliers
+// far from both, and the generating labels with the outliers labeled
+// as Noise.
+func moons(n int, std float64, outliers [][]float64, rnd *rand.Rand) (*mat.Dense, []int) {
+	x := mat.NewDense(n+len(outliers), 2, nil)
+	labels := make([]int, n+len(outliers))
+	for i := 0; i < n; i++ {
+		t := math.Pi * rnd.Float64()
+		u, v := math.Cos(t), math.Sin(t)
+		if i%2 == 1 {
+			u, v = 1-u, 0.5-v
+		}
+		labels[i] = i % 2
+		x.Set(i, 0, u+std*rnd.NormFloat64())
+		x.Set(i, 1, v+std*rnd.NormFloat64())
+	}
+	for i, o := range outliers {
+		x.SetRow(n+i, o)
+		labels[n+i] = Noise
+	}
+	return x, labels
+}
+
+var moonOutliers = [][]float64{{-3, -3}, {4, 3}, {-2, 2.5}, {3.5, -2}, {0.5, 4}}
+
+func TestDBSCAN(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n      int
+		std    float64
+		eps    float64
+		minPts int
+	}{
+		{n: 400, std: 0.05, eps: 0.2, minPts: 5},
+		{n: 1000, std: 0.08, eps: 0.15, minPts: 10},
+		{n: 200, std: 0.05, eps: 0.05, minPts: 4},
+	} {
+		x, _ := moons(test.n, test.std, moonOutliers, rnd)
+		r, _ := x.Dims()
+		res := DBSCAN(x, test.eps, test.minPts)
+
+		// Check the result against the definition using
+		// brute force neighborhoods.
+		neighbors := make([][]int, r)
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				if sqDist(x.RawRowView(i), x.RawRowView(j)) <= test.eps*test.eps {
+					neighbors[i] = append(neighbors[i], j)
+				}
+			}
+		}
+		for i, nb := range neighbors {
+			if core := len(nb) >= test.minPts; core != res.Core[i] {
+				t.Fatalf("unexpected core status for observation %d of test n=%d eps=%v: got %t want %t", i, test.n, test.eps, res.Core[i], core)
+			}
+			l := res.Labels[i]
+			if l < Noise || l >= res.NumClusters {
+				t.Fatalf("invalid label %d for observation %d", l, i)
+			}
+			var nearCore bool
+			for _, j := range nb {
+				if !res.Core[j] {
+					continue
+				}
+				nearCore = true
+				if res.Core[i] && res.Labels[j] != l {
+					t.Errorf("core observations %d and %d within eps in different clusters", i, j)
+				}
+			}
+			if !res.Core[i] && nearCore == (l == Noise) {
+				t.Errorf("unexpected label for non-core observation %d: got %d with core neighbor %t", i, l, nearCore)
+			}
+			if !res.Core[i] && l != Noise {
+				var found bool
+				for _, j := range nb {
+					found = found || (res.Core[j] && res.Labels[j] == l)
+				}
+				if !found {
+					t.Errorf("border observation %d not within eps of a core point of its cluster", i)
+				}
+			}
+		}
+	}
+
+	// Well chosen parameters recover the non-convex
+	// clusters and the outliers.
+	x, want := moons(600, 0.05, moonOutliers, rnd)
+	res := DBSCAN(x, 0.2, 5)
+	if res.NumClusters != 2 {
+		t.Fatalf("unexpected number of clusters: got %d want 2", res.NumClusters)
+	}
+	if !samePartition(res.Labels, want) {
+		t.Errorf("clusters do not match the moons")
+	}
+
+	// All observations are noise if no point is core.
+	res = DBSCAN(x, 0, 2)
+	if res.NumClusters != 0 {
+		t.Errorf("unexpected number of clusters for zero eps: got %d want 0", res.NumClusters)
+	}
+}
@@ -0,0 +1,10 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cluster provides algorithms for partitioning observations into
+// clusters.
+//
+// Observations are held in the rows of a mat.Matrix, and may be weighted
+// where the algorithm supports it.
+package cluster // import "gonum.org/v1/gonum/stat/cluster"
@@ -0,0 +1,344 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+// HDBSCANResult holds the result of an HDBSCAN clustering.
+type HDBSCANResult struct {
+	// Labels holds the index of the cluster
+	// of each observation, or Noise.
+	Labels []int
+
+	// Probabilities holds the strength of the
+	// membership of each observation in its
+	// cluster in [0, 1]. Noise observations
+	// have zero probability.
+	Probabilities []float64
+
+	// Stability holds the stability of each
+	// cluster, the excess of mass of the
+	// cluster over the density levels at
+	// which it exists.
+	Stability []float64
+
+	// NumClusters is the number of clusters.
+	NumClusters int
+}
+
+// HDBSCAN partitions the observations in the rows of x into clusters of
+// varying density using the HDBSCAN algorithm, see Campello et al.,
+// "Density-based clustering based on hierarchical density estimates",
+// PAKDD 2013.
+//
+// The core distance of an observation is the Euclidean distance to its
+// minPts-th nearest observation, counting itself, and the mutual reachability
+// distance between two observations is the maximum of their distance and
+// their core distances. HDBSCAN builds the single linkage hierarchy of the
+// observations under the mutual reachability distance, which corresponds to
+// running DBSCAN for every value of eps, and condenses it by treating splits
+// that separate fewer than minClusterSize observations as observations
+// leaving their cluster. The flat clustering is the set of non-overlapping
+// clusters of the condensed hierarchy with the greatest total stability,
+// excluding the root. Observations not in a selected cluster are labeled as
+// Noise.
+//
+// The minimum spanning tree of the mutual reachability distances is found in
+// O(n²) time and O(n) memory, where n is the number of observations.
+//
+// HDBSCAN panics if x has no rows, if minPts is not positive or if
+// minClusterSize is less than two.
+func HDBSCAN(x mat.Matrix, minPts, minClusterSize int) *HDBSCANResult {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic("cluster: no observations")
+	}
+	if minPts <= 0 {
+		panic("cluster: invalid minimum number of points")
+	}
+	if minClusterSize < 2 {
+		panic("cluster: invalid minimum cluster size")
+	}
+
+	pts := newIndexedPoints(x)
+	tree := kdtree.New(append(indexedPoints(nil), pts...), false)
+	core := make([]float64, n)
+	tree.NearestSets(pts.comparables(), 0,
+		func() kdtree.Keeper { return kdtree.NewNKeeper(minPts) },
+		func(i int, k kdtree.Keeper) {
+			h := k.(*kdtree.NKeeper).Heap
+			core[i] = math.Sqrt(h[len(h)-1].Dist)
+		},
+	)
+
+	links := singleLinkage(mutualReachabilityMST(pts, core))
+	clusters, pointCluster, pointLambda := condense(links, n, minClusterSize)
+	selected := selectClusters(clusters)
+
+	res := &HDBSCANResult{
+		Labels:        make([]int, n),
+		Probabilities: make([]float64, n),
+	}
+	label := make([]int, len(clusters))
+	for c, ok := range selected {
+		label[c] = Noise
+		if ok {
+			label[c] = res.NumClusters
+			res.NumClusters++
+			res.Stability = append(res.Stability, clusters[c].stability)
+		}
+	}
+
+	// Assign each observation to the selected ancestor of
+	// the cluster it left, and find the greatest density
+	// level of the members of each selected cluster.
+	lambda := make([]float64, n)
+	maxLambda := make([]float64, res.NumClusters)
+	for i := range res.Labels {
+		c := pointCluster[i]
+		for c >= 0 && !selected[c] {
+			c = clusters[c].parent
+		}
+		if c < 0 {
+			res.Labels[i] = Noise
+			continue
+		}
+		res.Labels[i] = label[c]
+		lambda[i] = pointLambda[i]
+		if pointCluster[i] != c {
+			lambda[i] = clusters[c].death
+		}
+		maxLambda[label[c]] = math.Max(maxLambda[label[c]], lambda[i])
+	}
+	for i, l := range res.Labels {
+		switch {
+		case l == Noise:
+		case lambda[i] == maxLambda[l]:
+			res.Probabilities[i] = 1
+		default:
+			res.Probabilities[i] = lambda[i] / maxLambda[l]
+		}
+	}
+	return res
+}
+
+// edge is a weighted edge between two observations.
+type edge struct {
+	u, v int
+	w    float64
+}
+
+// mutualReachabilityMST returns the edges of a minimum spanning tree of the
+// complete graph of the points weighted by their mutual reachability
+// distances, computed by Prim's algorithm.
+func mutualReachabilityMST(pts indexedPoints, core []float64) []edge {
+	n := len(pts)
+	in := make([]bool, n)
+	dist := make([]float64, n)
+	from := make([]int, n)
+	for i := range dist {
+		dist[i] = math.Inf(1)
+	}
+	mst := make([]edge, 0, n-1)
+	u := 0
+	in[u] = true
+	for len(mst) < n-1 {
+		next := -1
+		for v := range pts {
+			if in[v] {
+				continue
+			}
+			w := math.Max(math.Sqrt(sqDist(pts[u].x, pts[v].x)), math.Max(core[u], core[v]))
+			if w < dist[v] {
+				dist[v] = w
+				from[v] = u
+			}
+			if next < 0 || dist[v] < dist[next] {
+				next = v
+			}
+		}
+		mst = append(mst, edge{u: from[next], v: next, w: dist[next]})
+		in[next] = true
+		u = next
+	}
+	return mst
+}
+
+// link is a merge in a single linkage hierarchy. Nodes less than the
+// number of observations n are observations, and node n+i is the merge
+// held in the ith link.
+type link struct {
+	left, right int
+	dist        float64
+	size        int
+}
+
+// singleLinkage returns the single linkage hierarchy of the observations
+// joined by the minimum spanning tree mst.
+func singleLinkage(mst []edge) []link {
+	n := len(mst) + 1
+	sort.SliceStable(mst, func(i, j int) bool { return mst[i].w < mst[j].w })
+
+	// parent is a disjoint set forest over the observations
+	// and node holds the hierarchy node of each set.
+	parent := make([]int, n)
+	node := make([]int, n)
+	size := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+		node[i] = i
+		size[i] = 1
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	links := make([]link, len(mst))
+	for i, e := range mst {
+		u, v := find(e.u), find(e.v)
+		links[i] = link{left: node[u], right: node[v], dist: e.w, size: size[u] + size[v]}
+		if size[u] < size[v] {
+			u, v = v, u
+		}
+		parent[v] = u
+		size[u] += size[v]
+		node[u] = n + i
+	}
+	return links
+}
+
+// condensedCluster is a cluster of a condensed hierarchy. The density
+// levels, lambda, are the reciprocals of the mutual reachability
+// distances.
+type condensedCluster struct {
+	// parent is the index of the parent
+	// cluster, or -1 for the root.
+	parent   int
+	children []int
+
+	// birth and death are the density levels
+	// at which the cluster appears and splits.
+	birth, death float64
+
+	// stability is the sum over the observations
+	// of the cluster of the density level at
+	// which they leave it, less its birth.
+	stability float64
+}
+
+// condense returns the clusters of the condensed hierarchy of the single
+// linkage hierarchy links over n observations, with the root cluster first
+// and each cluster after its parent. It also returns the last cluster each
+// observation belongs to and the density level at which it leaves it.
+func condense(links []link, n, minSize int) (clusters []condensedCluster, pointCluster []int, pointLambda []float64) {
+	pointCluster = make([]int, n)
+	pointLambda = make([]float64, n)
+	size := func(node int) int {
+		if node < n {
+			return 1
+		}
+		return links[node-n].size
+	}
+
+	// leave records that the observations under node
+	// leave cluster c at the density level lambda.
+	var stack []int
+	leave := func(node, c int, lambda float64) {
+		stack = append(stack[:0], node)
+		for len(stack) > 0 {
+			node := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if node >= n {
+				stack = append(stack, links[node-n].left, links[node-n].right)
+				continue
+			}
+			pointCluster[node] = c
+			pointLambda[node] = lambda
+			if lambda > clusters[c].birth {
+				clusters[c].stability += lambda - clusters[c].birth
+			}
+		}
+	}
+
+	type job struct{ node, cluster int }
+	clusters = []condensedCluster{{parent: -1}}
+	jobs := []job{{node: 2*n - 2, cluster: 0}}
+	for len(jobs) > 0 {
+		j := jobs[len(jobs)-1]
+		jobs = jobs[:len(jobs)-1]
+		if j.node < n {
+			leave(j.node, j.cluster, clusters[j.cluster].birth)
+			continue
+		}
+		l := links[j.node-n]
+		lambda := 1 / l.dist
+		bigLeft := size(l.left) >= minSize
+		bigRight := size(l.right) >= minSize
+		switch {
+		case bigLeft && bigRight:
+			// The cluster splits into two new clusters.
+			c := &clusters[j.cluster]
+			c.death = lambda
+			if lambda > c.birth {
+				c.stability += float64(l.size) * (lambda - c.birth)
+			}
+			for _, child := range []int{l.left, l.right} {
+				id := len(clusters)
+				clusters[j.cluster].children = append(clusters[j.cluster].children, id)
+				clusters = append(clusters, condensedCluster{parent: j.cluster, birth: lambda})
+				jobs = append(jobs, job{node: child, cluster: id})
+			}
+		case bigLeft:
+			leave(l.right, j.cluster, lambda)
+			jobs = append(jobs, job{node: l.left, cluster: j.cluster})
+		case bigRight:
+			leave(l.left, j.cluster, lambda)
+			jobs = append(jobs, job{node: l.right, cluster: j.cluster})
+		default:
+			leave(j.node, j.cluster, lambda)
+			clusters[j.cluster].death = lambda
+		}
+	}
+	return clusters, pointCluster, pointLambda
+}
+
+// selectClusters returns the non-root clusters that maximize the total
+// stability such that no selected cluster is the descendant of another,
+// selecting a cluster in preference to its descendants when their total
+// stability is equal.
+func selectClusters(clusters []condensedCluster) []bool {
+	selected := make([]bool, len(clusters))
+	best := make([]float64, len(clusters))
+	var deselect func(c int)
+	deselect = func(c int) {
+		for _, child := range clusters[c].children {
+			selected[child] = false
+			deselect(child)
+		}
+	}
+	for c := len(clusters) - 1; c > 0; c-- {
+		var sum float64
+		for _, child := range clusters[c].children {
+			sum += best[child]
+		}
+		if clusters[c].stability >= sum {
+			selected[c] = true
+			best[c] = clusters[c].stability
+			deselect(c)
+		} else {
+			best[c] = sum
+		}
+	}
+	return selected
+}
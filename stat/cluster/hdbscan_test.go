@@ -0,0 +1,96 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestHDBSCAN(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	x, want := moons(600, 0.05, moonOutliers, rnd)
+	res := HDBSCAN(x, 5, 20)
+	if res.NumClusters != 2 {
+		t.Fatalf("unexpected number of clusters for moons: got %d want 2", res.NumClusters)
+	}
+	checkHDBSCAN(t, res, want, 0.02)
+
+	// Clusters of different densities cannot be found
+	// by DBSCAN with a single eps.
+	n := 600
+	x = mat.NewDense(n+len(moonOutliers), 2, nil)
+	want = make([]int, n+len(moonOutliers))
+	for i := 0; i < n; i++ {
+		j := i % 3
+		std := []float64{0.05, 0.2, 0.5}[j]
+		x.Set(i, 0, 4*float64(j)+std*rnd.NormFloat64())
+		x.Set(i, 1, std*rnd.NormFloat64())
+		want[i] = j
+	}
+	for i, o := range moonOutliers {
+		x.SetRow(n+i, []float64{10 * o[0], 10 * o[1]})
+		want[n+i] = Noise
+	}
+	res = HDBSCAN(x, 10, 30)
+	if res.NumClusters != 3 {
+		t.Fatalf("unexpected number of clusters for varying densities: got %d want 3", res.NumClusters)
+	}
+	checkHDBSCAN(t, res, want, 0.05)
+
+	// Too few observations for a cluster.
+	res = HDBSCAN(mat.NewDense(3, 1, []float64{0, 1, 2}), 1, 4)
+	for i, l := range res.Labels {
+		if l != Noise {
+			t.Errorf("unexpected label for observation %d of small data: got %d want noise", i, l)
+		}
+	}
+	res = HDBSCAN(mat.NewDense(1, 1, nil), 1, 2)
+	if res.Labels[0] != Noise {
+		t.Errorf("unexpected label for single observation: got %d want noise", res.Labels[0])
+	}
+}
+
+// checkHDBSCAN checks the consistency of res and that its labels match
+// want apart from at most the fraction frac of the clustered observations
+// labeled as noise.
+func checkHDBSCAN(t *testing.T, res *HDBSCANResult, want []int, frac float64) {
+	t.Helper()
+	if len(res.Stability) != res.NumClusters {
+		t.Errorf("unexpected number of stabilities: got %d want %d", len(res.Stability), res.NumClusters)
+	}
+	var a, b []int
+	var lost, clustered int
+	for i, l := range res.Labels {
+		p := res.Probabilities[i]
+		if p < 0 || p > 1 || (l == Noise) != (p == 0) {
+			t.Errorf("unexpected probability for observation %d with label %d: %v", i, l, p)
+		}
+		if want[i] == Noise {
+			if l != Noise {
+				t.Errorf("outlier %d not labeled as noise", i)
+			}
+			continue
+		}
+		clustered++
+		if l == Noise {
+			lost++
+			continue
+		}
+		a = append(a, l)
+		b = append(b, want[i])
+	}
+	if !samePartition(a, b) {
+		t.Errorf("clusters do not match the generating clusters")
+	}
+	if float64(lost) > frac*float64(clustered) {
+		t.Errorf("too many clustered observations labeled as noise: %d of %d", lost, clustered)
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Linkage specifies the distance between clusters used by Agglomerative.
+type Linkage int
+
+const (
+	// Single is the distance between the closest
+	// observations of the two clusters.
+	Single Linkage = iota
+
+	// Complete is the distance between the farthest
+	// observations of the two clusters.
+	Complete
+
+	// Average is the mean distance between the
+	// observations of the two clusters.
+	Average
+
+	// Ward is Ward's minimum variance criterion,
+	//  sqrt(2*|A|*|B|/(|A|+|B|)) * ||c_A - c_B||,
+	// where c_A and c_B are the centroids of the
+	// clusters A and B. Merging the clusters with
+	// the least Ward distance gives the least
+	// increase in the within-cluster sum of squares.
+	Ward
+)
+
+// Merge is a merge of two clusters in a Dendrogram.
+type Merge struct {
+	// A and B are the nodes merged, with A < B.
+	// Nodes less than the number of observations
+	// n are observations, and node n+i is the
+	// cluster formed by the ith merge.
+	A, B int
+
+	// Height is the linkage distance between
+	// the merged clusters.
+	Height float64
+
+	// Size is the number of observations
+	// in the merged cluster.
+	Size int
+}
+
+// Dendrogram is a hierarchy of clusters formed by successive merges.
+type Dendrogram struct {
+	// Merges holds the n-1 merges of a
+	// hierarchy of n observations in order
+	// of non-decreasing height.
+	Merges []Merge
+}
+
+// Len returns the number of observations in the hierarchy.
+func (d *Dendrogram) Len() int {
+	return len(d.Merges) + 1
+}
+
+// CutHeight returns the labels of the observations in the clusters formed
+// by the merges with heights at most h. The clusters are labeled in
+// the order of their first observation.
+func (d *Dendrogram) CutHeight(h float64) []int {
+	m := sort.Search(len(d.Merges), func(i int) bool { return d.Merges[i].Height > h })
+	return d.cut(m)
+}
+
+// CutCount returns the labels of the observations in the k clusters formed
+// by the first n-k merges, where n is the number of observations. The
+// clusters are labeled in the order of their first observation. CutCount
+// panics if k is not positive or is greater than n.
+func (d *Dendrogram) CutCount(k int) []int {
+	n := d.Len()
+	if k <= 0 || k > n {
+		panic("cluster: invalid number of clusters")
+	}
+	return d.cut(n - k)
+}
+
+// cut returns the labels of the observations in the clusters formed by
+// the first m merges.
+func (d *Dendrogram) cut(m int) []int {
+	n := d.Len()
+	// root holds the node of the root cluster of
+	// each node formed by the first m merges.
+	root := make([]int, n+m)
+	for i := range root {
+		root[i] = i
+	}
+	for i := m - 1; i >= 0; i-- {
+		r := root[n+i]
+		root[d.Merges[i].A] = r
+		root[d.Merges[i].B] = r
+	}
+	labels := make([]int, n)
+	seen := make(map[int]int)
+	for i := range labels {
+		l, ok := seen[root[i]]
+		if !ok {
+			l = len(seen)
+			seen[root[i]] = l
+		}
+		labels[i] = l
+	}
+	return labels
+}
+
+// Agglomerative returns the hierarchy of clusters of the observations in the
+// rows of x formed by repeatedly merging the two clusters with the least
+// linkage distance between them, starting from singleton clusters and using
+// the Euclidean distance between observations.
+//
+// The hierarchy is found using the nearest-neighbor chain algorithm, which
+// takes O(n²) time and memory for n observations, see Müllner, "Modern
+// hierarchical, agglomerative clustering algorithms", arXiv:1109.2378, 2011.
+//
+// Agglomerative panics if x has no rows or if the linkage is not valid.
+func Agglomerative(x mat.Matrix, linkage Linkage) *Dendrogram {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic("cluster: no observations")
+	}
+	var update func(dai, dbi, dab float64, na, nb, ni int) float64
+	switch linkage {
+	case Single:
+		update = func(dai, dbi, _ float64, _, _, _ int) float64 {
+			return math.Min(dai, dbi)
+		}
+	case Complete:
+		update = func(dai, dbi, _ float64, _, _, _ int) float64 {
+			return math.Max(dai, dbi)
+		}
+	case Average:
+		update = func(dai, dbi, _ float64, na, nb, _ int) float64 {
+			return (float64(na)*dai + float64(nb)*dbi) / float64(na+nb)
+		}
+	case Ward:
+		update = func(dai, dbi, dab float64, na, nb, ni int) float64 {
+			fa, fb, fi := float64(na+ni), float64(nb+ni), float64(ni)
+			v := (fa*dai*dai + fb*dbi*dbi - fi*dab*dab) / float64(na+nb+ni)
+			return math.Sqrt(math.Max(v, 0))
+		}
+	default:
+		panic("cluster: invalid linkage")
+	}
+
+	// dist holds the distances between the active
+	// clusters in the strict upper triangle of a
+	// packed n×n matrix.
+	xd := mat.DenseCopyOf(x)
+	dist := make([]float64, n*(n-1)/2)
+	idx := func(i, j int) int {
+		if i > j {
+			i, j = j, i
+		}
+		return i*(2*n-i-1)/2 + j - i - 1
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			dist[idx(i, j)] = math.Sqrt(sqDist(xd.RawRowView(i), xd.RawRowView(j)))
+		}
+	}
+
+	// Each active cluster is represented by one of its
+	// observations, and merges are first recorded with
+	// the representatives of the merged clusters.
+	active := make([]bool, n)
+	size := make([]int, n)
+	for i := range active {
+		active[i] = true
+		size[i] = 1
+	}
+	merges := make([]Merge, 0, n-1)
+	var chain []int
+	for len(merges) < n-1 {
+		if len(chain) == 0 {
+			for i, ok := range active {
+				if ok {
+					chain = append(chain, i)
+					break
+				}
+			}
+		}
+
+		// Grow the chain of nearest neighbors until its last
+		// two clusters are reciprocal nearest neighbors,
+		// preferring the previous cluster in case of ties.
+		var a, b int
+		var h float64
+		for {
+			a = chain[len(chain)-1]
+			b = -1
+			h = math.Inf(1)
+			if len(chain) > 1 {
+				b = chain[len(chain)-2]
+				h = dist[idx(a, b)]
+			}
+			for i, ok := range active {
+				if !ok || i == a {
+					continue
+				}
+				if v := dist[idx(a, i)]; v < h {
+					b, h = i, v
+				}
+			}
+			if len(chain) > 1 && b == chain[len(chain)-2] {
+				break
+			}
+			chain = append(chain, b)
+		}
+		chain = chain[:len(chain)-2]
+
+		// Merge a into b and update the distances to b.
+		merges = append(merges, Merge{A: a, B: b, Height: h, Size: size[a] + size[b]})
+		active[a] = false
+		for i, ok := range active {
+			if !ok || i == b {
+				continue
+			}
+			dist[idx(b, i)] = update(dist[idx(a, i)], dist[idx(b, i)], h, size[a], size[b], size[i])
+		}
+		size[b] += size[a]
+	}
+
+	// Order the merges by height and relabel them with the
+	// nodes of the merged clusters.
+	sort.SliceStable(merges, func(i, j int) bool { return merges[i].Height < merges[j].Height })
+	parent := make([]int, n)
+	node := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+		node[i] = i
+	}
+	find := func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	for i, m := range merges {
+		ra, rb := find(m.A), find(m.B)
+		a, b := node[ra], node[rb]
+		if a > b {
+			a, b = b, a
+		}
+		merges[i].A, merges[i].B = a, b
+		parent[ra] = rb
+		node[rb] = n + i
+	}
+	return &Dendrogram{Merges: merges}
+}
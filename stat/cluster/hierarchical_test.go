@@ -0,0 +1,180 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// naiveAgglomerative returns the merge heights of the agglomerative
+// clustering of x computed from the definitions of the linkages, and the
+// partitions into each number of clusters.
+func naiveAgglomerative(x *mat.Dense, linkage Linkage) (heights []float64, parts [][]int) {
+	n, _ := x.Dims()
+	clusters := make([][]int, n)
+	for i := range clusters {
+		clusters[i] = []int{i}
+	}
+	dist := func(a, b []int) float64 {
+		switch linkage {
+		case Ward:
+			ca := make([]float64, x.RawMatrix().Cols)
+			cb := make([]float64, len(ca))
+			for _, i := range a {
+				for l, v := range x.RawRowView(i) {
+					ca[l] += v / float64(len(a))
+				}
+			}
+			for _, i := range b {
+				for l, v := range x.RawRowView(i) {
+					cb[l] += v / float64(len(b))
+				}
+			}
+			na, nb := float64(len(a)), float64(len(b))
+			return math.Sqrt(2*na*nb/(na+nb)) * math.Sqrt(sqDist(ca, cb))
+		}
+		var min, max, sum float64
+		min = math.Inf(1)
+		for _, i := range a {
+			for _, j := range b {
+				d := math.Sqrt(sqDist(x.RawRowView(i), x.RawRowView(j)))
+				min = math.Min(min, d)
+				max = math.Max(max, d)
+				sum += d
+			}
+		}
+		switch linkage {
+		case Single:
+			return min
+		case Complete:
+			return max
+		default:
+			return sum / float64(len(a)*len(b))
+		}
+	}
+	labels := func() []int {
+		l := make([]int, n)
+		for c, cl := range clusters {
+			for _, i := range cl {
+				l[i] = c
+			}
+		}
+		return l
+	}
+	parts = make([][]int, n+1)
+	parts[n] = labels()
+	for len(clusters) > 1 {
+		h := math.Inf(1)
+		var a, b int
+		for i := range clusters {
+			for j := i + 1; j < len(clusters); j++ {
+				if d := dist(clusters[i], clusters[j]); d < h {
+					h, a, b = d, i, j
+				}
+			}
+		}
+		heights = append(heights, h)
+		clusters[a] = append(clusters[a], clusters[b]...)
+		clusters = append(clusters[:b], clusters[b+1:]...)
+		parts[len(clusters)] = labels()
+	}
+	return heights, parts
+}
+
+func TestAgglomerative(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 10, 40} {
+		x := mat.NewDense(n, 3, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < 3; j++ {
+				x.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		for _, linkage := range []Linkage{Single, Complete, Average, Ward} {
+			d := Agglomerative(x, linkage)
+			if d.Len() != n {
+				t.Fatalf("unexpected number of observations for n=%d linkage=%d: got %d", n, linkage, d.Len())
+			}
+			heights, parts := naiveAgglomerative(x, linkage)
+			got := make([]float64, len(d.Merges))
+			for i, m := range d.Merges {
+				got[i] = m.Height
+				if m.A >= m.B || m.B >= n+i {
+					t.Errorf("invalid nodes in merge %d for n=%d linkage=%d: %d, %d", i, n, linkage, m.A, m.B)
+				}
+			}
+			if !sort.Float64sAreSorted(got) {
+				t.Errorf("merge heights not sorted for n=%d linkage=%d", n, linkage)
+			}
+			sort.Float64s(heights)
+			for i, h := range heights {
+				if !floats.EqualWithinAbsOrRel(got[i], h, 1e-12, 1e-12) {
+					t.Errorf("unexpected height of merge %d for n=%d linkage=%d: got %v want %v", i, n, linkage, got[i], h)
+				}
+			}
+			if n > 1 && d.Merges[n-2].Size != n {
+				t.Errorf("unexpected size of root for n=%d linkage=%d: got %d want %d", n, linkage, d.Merges[n-2].Size, n)
+			}
+			for k := 1; k <= n; k++ {
+				if !samePartition(d.CutCount(k), parts[k]) {
+					t.Errorf("unexpected partition into %d clusters for n=%d linkage=%d", k, n, linkage)
+				}
+			}
+		}
+	}
+}
+
+func TestDendrogramCut(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(6, 1, []float64{0, 20, 1, 5, 6, 8})
+	d := Agglomerative(x, Single)
+	for _, test := range []struct {
+		h    float64
+		want []int
+	}{
+		{h: 0.5, want: []int{0, 1, 2, 3, 4, 5}},
+		{h: 1, want: []int{0, 1, 0, 2, 2, 3}},
+		{h: 2, want: []int{0, 1, 0, 2, 2, 2}},
+		{h: 4, want: []int{0, 1, 0, 0, 0, 0}},
+		{h: 100, want: []int{0, 0, 0, 0, 0, 0}},
+	} {
+		got := d.CutHeight(test.h)
+		for i, l := range got {
+			if l != test.want[i] {
+				t.Errorf("unexpected labels for height %v: got %v want %v", test.h, got, test.want)
+				break
+			}
+		}
+	}
+	got := d.CutCount(3)
+	want := []int{0, 1, 0, 2, 2, 2}
+	for i, l := range got {
+		if l != want[i] {
+			t.Errorf("unexpected labels for 3 clusters: got %v want %v", got, want)
+			break
+		}
+	}
+	for _, k := range []int{0, 7} {
+		if !panics(func() { d.CutCount(k) }) {
+			t.Errorf("expected panic for %d clusters", k)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,493 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// KMeansAlgorithm specifies the algorithm used by KMeans.
+type KMeansAlgorithm int
+
+const (
+	// Lloyd is Lloyd's algorithm, alternating the assignment
+	// of each observation to its nearest center with the
+	// update of each center to the mean of its observations.
+	Lloyd KMeansAlgorithm = iota
+
+	// Elkan is Elkan's accelerated version of Lloyd's
+	// algorithm, which uses the triangle inequality to
+	// avoid most distance computations while giving the
+	// same result. It uses O(n*k) additional memory.
+	Elkan
+
+	// MiniBatch is the mini-batch k-means algorithm, which
+	// updates the centers from random batches of the
+	// observations. It is much faster than Lloyd's
+	// algorithm for large data sets but gives higher
+	// inertia.
+	MiniBatch
+)
+
+// KMeansSettings holds the settings for KMeans. See the field comments for
+// default values.
+type KMeansSettings struct {
+	// Algorithm is the algorithm used to find
+	// the clusters. The default is Lloyd.
+	Algorithm KMeansAlgorithm
+
+	// Init, if not nil, holds the initial centers
+	// in its k rows. If Init is nil, the centers
+	// are initialized by KMeansPlusPlus.
+	Init mat.Matrix
+
+	// MaxIterations is the maximum number of
+	// iterations, or of batches for MiniBatch. If
+	// MaxIterations is zero, a default of 300 is
+	// used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance on the
+	// sum of the squared movements of the centers in
+	// an iteration relative to the mean variance of
+	// the columns of the observations. If Tolerance
+	// is zero, a default of 1e-4 is used.
+	Tolerance float64
+
+	// BatchSize is the number of observations in each
+	// batch for MiniBatch. If BatchSize is zero, a
+	// default of min(n, 1024) is used.
+	BatchSize int
+
+	// Src is the source of random numbers used for the
+	// initialization and the batches. If Src is nil,
+	// the global source is used.
+	Src rand.Source
+}
+
+// KMeansResult holds the result of a k-means clustering.
+type KMeansResult struct {
+	// Centers holds the cluster centers
+	// in its k rows.
+	Centers *mat.Dense
+
+	// Labels holds the index of the cluster
+	// of each observation.
+	Labels []int
+
+	// Inertia is the weighted sum of squared
+	// distances of the observations to the
+	// centers of their clusters.
+	Inertia float64
+
+	// Iterations is the number of iterations,
+	// or of batches for MiniBatch.
+	Iterations int
+
+	// Converged is whether the movement of the
+	// centers fell below the tolerance before
+	// the iteration limit.
+	Converged bool
+}
+
+// KMeans partitions the observations in the rows of x into k clusters
+// minimizing the weighted sum of squared Euclidean distances of the
+// observations to the centers of their clusters. If weights is nil, all the
+// observations are weighted equally, otherwise weights holds the
+// non-negative weights of the observations. If settings is nil, the default
+// settings are used.
+//
+// The k-means objective has many local minima, so KMeans may be run from
+// several initializations and the result with the lowest Inertia chosen.
+// A cluster that becomes empty during Lloyd or Elkan iterations is moved
+// to the observation farthest from its center.
+//
+// KMeans panics if x has no rows, if k is not positive or greater than the
+// number of rows of x, if weights is not nil and its length is not the
+// number of rows of x, or if the settings are invalid.
+func KMeans(x mat.Matrix, weights []float64, k int, settings *KMeansSettings) *KMeansResult {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic("cluster: no observations")
+	}
+	if k <= 0 || k > n {
+		panic("cluster: invalid number of clusters")
+	}
+	if weights != nil && len(weights) != n {
+		panic("cluster: mismatched weights length")
+	}
+	var s KMeansSettings
+	if settings != nil {
+		s = *settings
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 300
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = 1e-4
+	}
+	if s.BatchSize == 0 {
+		s.BatchSize = 1024
+	}
+	if s.BatchSize > n {
+		s.BatchSize = n
+	}
+	if s.MaxIterations < 0 || s.Tolerance < 0 || s.BatchSize < 0 {
+		panic("cluster: invalid k-means settings")
+	}
+
+	xd := mat.DenseCopyOf(x)
+	w := weights
+	if w == nil {
+		w = make([]float64, n)
+		for i := range w {
+			w[i] = 1
+		}
+	}
+
+	var centers *mat.Dense
+	if s.Init != nil {
+		if r, c := s.Init.Dims(); r != k || c != d {
+			panic("cluster: mismatched initial centers")
+		}
+		centers = mat.DenseCopyOf(s.Init)
+	} else {
+		centers = KMeansPlusPlus(xd, w, k, s.Src)
+	}
+	tol := s.Tolerance * meanVariance(xd, w)
+
+	res := &KMeansResult{
+		Centers: centers,
+		Labels:  make([]int, n),
+	}
+	switch s.Algorithm {
+	case Lloyd:
+		lloyd(res, xd, w, s.MaxIterations, tol)
+	case Elkan:
+		elkan(res, xd, w, s.MaxIterations, tol)
+	case MiniBatch:
+		miniBatch(res, xd, w, s, tol)
+	default:
+		panic("cluster: invalid k-means algorithm")
+	}
+	res.Inertia = 0
+	for i := range res.Labels {
+		res.Labels[i], _ = nearest(xd.RawRowView(i), res.Centers)
+		res.Inertia += w[i] * sqDist(xd.RawRowView(i), res.Centers.RawRowView(res.Labels[i]))
+	}
+	return res
+}
+
+// KMeansPlusPlus returns k initial centers chosen from the rows of x by
+// k-means++ seeding, choosing each center with probability proportional to
+// the weighted squared distance of the observation to the nearest center
+// already chosen, see Arthur and Vassilvitskii, "k-means++: the advantages of
+// careful seeding", SODA 2007. If weights is nil, all the observations are
+// weighted equally. The source of random numbers is src. If src is nil, the
+// global source is used.
+//
+// KMeansPlusPlus panics if k is not positive or greater than the number of
+// rows of x, or if weights is not nil and its length is not the number of
+// rows of x.
+func KMeansPlusPlus(x mat.Matrix, weights []float64, k int, src rand.Source) *mat.Dense {
+	n, d := x.Dims()
+	if k <= 0 || k > n {
+		panic("cluster: invalid number of clusters")
+	}
+	if weights != nil && len(weights) != n {
+		panic("cluster: mismatched weights length")
+	}
+	xd, ok := x.(*mat.Dense)
+	if !ok {
+		xd = mat.DenseCopyOf(x)
+	}
+	uniform := rand.Float64
+	if src != nil {
+		uniform = rand.New(src).Float64
+	}
+	weight := func(i int) float64 {
+		if weights == nil {
+			return 1
+		}
+		return weights[i]
+	}
+	choose := func(p []float64) int {
+		sum := floats.Sum(p)
+		if sum == 0 {
+			return int(uniform() * float64(n))
+		}
+		u := uniform() * sum
+		for i, v := range p {
+			if u -= v; u < 0 {
+				return i
+			}
+		}
+		return n - 1
+	}
+
+	centers := mat.NewDense(k, d, nil)
+	p := make([]float64, n)
+	for i := range p {
+		p[i] = weight(i)
+	}
+	centers.SetRow(0, xd.RawRowView(choose(p)))
+	dist := make([]float64, n)
+	for i := range dist {
+		dist[i] = sqDist(xd.RawRowView(i), centers.RawRowView(0))
+	}
+	for j := 1; j < k; j++ {
+		for i, v := range dist {
+			p[i] = weight(i) * v
+		}
+		centers.SetRow(j, xd.RawRowView(choose(p)))
+		c := centers.RawRowView(j)
+		for i, v := range dist {
+			dist[i] = math.Min(v, sqDist(xd.RawRowView(i), c))
+		}
+	}
+	return centers
+}
+
+// lloyd performs Lloyd's algorithm from the centers in res.
+func lloyd(res *KMeansResult, x *mat.Dense, w []float64, maxIter int, tol float64) {
+	n, _ := x.Dims()
+	for res.Iterations < maxIter {
+		res.Iterations++
+		for i := 0; i < n; i++ {
+			res.Labels[i], _ = nearest(x.RawRowView(i), res.Centers)
+		}
+		shift := updateCenters(res.Centers, x, w, res.Labels)
+		if floats.Sum(shift) <= tol {
+			res.Converged = true
+			return
+		}
+	}
+}
+
+// elkan performs Elkan's algorithm from the centers in res, see Elkan,
+// "Using the triangle inequality to accelerate k-means", ICML 2003.
+func elkan(res *KMeansResult, x *mat.Dense, w []float64, maxIter int, tol float64) {
+	n, _ := x.Dims()
+	k, _ := res.Centers.Dims()
+	labels := res.Labels
+
+	// upper holds an upper bound on the distance of each
+	// observation to its center, and lower holds lower
+	// bounds on its distances to every center.
+	upper := make([]float64, n)
+	lower := mat.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		xi := x.RawRowView(i)
+		li := lower.RawRowView(i)
+		for j := range li {
+			li[j] = math.Sqrt(sqDist(xi, res.Centers.RawRowView(j)))
+			if li[j] < li[labels[i]] {
+				labels[i] = j
+			}
+		}
+		upper[i] = li[labels[i]]
+	}
+
+	cc := mat.NewDense(k, k, nil)
+	half := make([]float64, k)
+	shift := make([]float64, k)
+	for res.Iterations < maxIter {
+		res.Iterations++
+
+		// Compute the half distances between centers and
+		// to the nearest other center.
+		for j := 0; j < k; j++ {
+			half[j] = math.Inf(1)
+		}
+		for j := 0; j < k; j++ {
+			for l := j + 1; l < k; l++ {
+				v := 0.5 * math.Sqrt(sqDist(res.Centers.RawRowView(j), res.Centers.RawRowView(l)))
+				cc.Set(j, l, v)
+				cc.Set(l, j, v)
+				half[j] = math.Min(half[j], v)
+				half[l] = math.Min(half[l], v)
+			}
+		}
+
+		for i := 0; i < n; i++ {
+			c := labels[i]
+			if upper[i] <= half[c] {
+				continue
+			}
+			xi := x.RawRowView(i)
+			li := lower.RawRowView(i)
+			tight := false
+			for j := 0; j < k; j++ {
+				if j == c || upper[i] <= li[j] || upper[i] <= cc.At(c, j) {
+					continue
+				}
+				if !tight {
+					upper[i] = math.Sqrt(sqDist(xi, res.Centers.RawRowView(c)))
+					li[c] = upper[i]
+					tight = true
+					if upper[i] <= li[j] || upper[i] <= cc.At(c, j) {
+						continue
+					}
+				}
+				dj := math.Sqrt(sqDist(xi, res.Centers.RawRowView(j)))
+				li[j] = dj
+				if dj < upper[i] {
+					c = j
+					upper[i] = dj
+				}
+			}
+			labels[i] = c
+		}
+
+		sq := updateCenters(res.Centers, x, w, labels)
+		for j, v := range sq {
+			shift[j] = math.Sqrt(v)
+		}
+		for i := 0; i < n; i++ {
+			li := lower.RawRowView(i)
+			for j, v := range shift {
+				li[j] = math.Max(li[j]-v, 0)
+			}
+			upper[i] += shift[labels[i]]
+		}
+		if floats.Sum(sq) <= tol {
+			res.Converged = true
+			return
+		}
+	}
+}
+
+// miniBatch performs mini-batch k-means from the centers in res, see
+// Sculley, "Web-scale k-means clustering", WWW 2010.
+func miniBatch(res *KMeansResult, x *mat.Dense, w []float64, s KMeansSettings, tol float64) {
+	n, _ := x.Dims()
+	k, _ := res.Centers.Dims()
+	intn := rand.Intn
+	if s.Src != nil {
+		intn = rand.New(s.Src).Intn
+	}
+	counts := make([]float64, k)
+	old := mat.NewDense(k, x.RawMatrix().Cols, nil)
+	batch := make([]int, s.BatchSize)
+	labels := make([]int, s.BatchSize)
+	for res.Iterations < s.MaxIterations {
+		res.Iterations++
+		old.Copy(res.Centers)
+		for b := range batch {
+			batch[b] = intn(n)
+			labels[b], _ = nearest(x.RawRowView(batch[b]), res.Centers)
+		}
+		for b, i := range batch {
+			if w[i] == 0 {
+				continue
+			}
+			// Move the center towards the observation with
+			// a per-center learning rate.
+			j := labels[b]
+			counts[j] += w[i]
+			eta := w[i] / counts[j]
+			c := res.Centers.RawRowView(j)
+			for l, v := range x.RawRowView(i) {
+				c[l] += eta * (v - c[l])
+			}
+		}
+		var shift float64
+		for j := 0; j < k; j++ {
+			shift += sqDist(old.RawRowView(j), res.Centers.RawRowView(j))
+		}
+		if shift <= tol {
+			res.Converged = true
+			return
+		}
+	}
+}
+
+// updateCenters sets each center to the weighted mean of the observations
+// with its label, and returns the squared movement of each center. Empty
+// clusters are moved to the observations farthest from their centers.
+func updateCenters(centers *mat.Dense, x *mat.Dense, w []float64, labels []int) []float64 {
+	k, _ := centers.Dims()
+	old := mat.DenseCopyOf(centers)
+	centers.Zero()
+	sum := make([]float64, k)
+	for i, j := range labels {
+		floats.AddScaled(centers.RawRowView(j), w[i], x.RawRowView(i))
+		sum[j] += w[i]
+	}
+	var empty []int
+	for j := 0; j < k; j++ {
+		if sum[j] == 0 {
+			empty = append(empty, j)
+			continue
+		}
+		floats.Scale(1/sum[j], centers.RawRowView(j))
+	}
+	if len(empty) > 0 {
+		// Move each empty cluster to the observation
+		// farthest from its center.
+		n := len(labels)
+		dist := make([]float64, n)
+		for i, j := range labels {
+			dist[i] = w[i] * sqDist(x.RawRowView(i), centers.RawRowView(j))
+		}
+		for _, j := range empty {
+			far := floats.MaxIdx(dist)
+			if dist[far] == 0 {
+				copy(centers.RawRowView(j), old.RawRowView(j))
+				continue
+			}
+			copy(centers.RawRowView(j), x.RawRowView(far))
+			dist[far] = 0
+		}
+	}
+	shift := make([]float64, k)
+	for j := range shift {
+		shift[j] = sqDist(old.RawRowView(j), centers.RawRowView(j))
+	}
+	return shift
+}
+
+// nearest returns the index of the row of centers nearest to x and its
+// squared distance.
+func nearest(x []float64, centers *mat.Dense) (idx int, dist float64) {
+	k, _ := centers.Dims()
+	dist = math.Inf(1)
+	for j := 0; j < k; j++ {
+		if v := sqDist(x, centers.RawRowView(j)); v < dist {
+			idx, dist = j, v
+		}
+	}
+	return idx, dist
+}
+
+// meanVariance returns the mean of the weighted variances of the columns
+// of x.
+func meanVariance(x *mat.Dense, w []float64) float64 {
+	n, d := x.Dims()
+	sumW := floats.Sum(w)
+	mean := make([]float64, d)
+	for i := 0; i < n; i++ {
+		floats.AddScaled(mean, w[i]/sumW, x.RawRowView(i))
+	}
+	var v float64
+	for i := 0; i < n; i++ {
+		v += w[i] * sqDist(x.RawRowView(i), mean)
+	}
+	return v / (sumW * float64(d))
+}
+
+// sqDist returns the squared Euclidean distance between x and y.
+func sqDist(x, y []float64) float64 {
+	var d float64
+	for i, v := range x {
+		d += (v - y[i]) * (v - y[i])
+	}
+	return d
+}
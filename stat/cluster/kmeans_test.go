@@ -0,0 +1,224 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// blobs returns n observations drawn from k spherical normal clusters
+// with the given standard deviation centered at random points separated
+// by at least sep, and the generating labels.
+func blobs(n, k, d int, sep, std float64, rnd *rand.Rand) (*mat.Dense, []int) {
+	centers := mat.NewDense(k, d, nil)
+	for j := 0; j < k; j++ {
+		centers.Set(j, 0, sep*float64(j))
+		for l := 1; l < d; l++ {
+			centers.Set(j, l, sep*rnd.Float64())
+		}
+	}
+	x := mat.NewDense(n, d, nil)
+	labels := make([]int, n)
+	for i := 0; i < n; i++ {
+		j := i % k
+		labels[i] = j
+		for l := 0; l < d; l++ {
+			x.Set(i, l, centers.At(j, l)+std*rnd.NormFloat64())
+		}
+	}
+	return x, labels
+}
+
+// samePartition returns whether the labels a and b describe the same
+// partition up to a relabeling.
+func samePartition(a, b []int) bool {
+	ab := make(map[int]int)
+	ba := make(map[int]int)
+	for i := range a {
+		if v, ok := ab[a[i]]; ok && v != b[i] {
+			return false
+		}
+		if v, ok := ba[b[i]]; ok && v != a[i] {
+			return false
+		}
+		ab[a[i]] = b[i]
+		ba[b[i]] = a[i]
+	}
+	return true
+}
+
+func TestKMeans(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, k, d int
+	}{
+		{n: 300, k: 3, d: 2},
+		{n: 1000, k: 5, d: 3},
+		{n: 2000, k: 8, d: 4},
+	} {
+		x, want := blobs(test.n, test.k, test.d, 10, 0.5, rnd)
+		w := make([]float64, test.n)
+		for i := range w {
+			w[i] = 0.5 + rnd.Float64()
+		}
+		for _, weights := range [][]float64{nil, w} {
+			// Take the best of several seedings for Lloyd's
+			// algorithm.
+			var lloyd *KMeansResult
+			var init *mat.Dense
+			for seed := uint64(1); seed <= 5; seed++ {
+				c := KMeansPlusPlus(x, weights, test.k, rand.NewSource(seed))
+				r := KMeans(x, weights, test.k, &KMeansSettings{Init: c})
+				if lloyd == nil || r.Inertia < lloyd.Inertia {
+					lloyd, init = r, c
+				}
+			}
+			if !lloyd.Converged {
+				t.Errorf("n=%d k=%d: Lloyd did not converge", test.n, test.k)
+			}
+			if !samePartition(lloyd.Labels, want) {
+				t.Errorf("n=%d k=%d: Lloyd did not recover the clusters", test.n, test.k)
+			}
+
+			// Elkan from the same centers gives the same result.
+			elkan := KMeans(x, weights, test.k, &KMeansSettings{Init: init, Algorithm: Elkan})
+			if !samePartition(elkan.Labels, lloyd.Labels) || elkan.Iterations != lloyd.Iterations {
+				t.Errorf("n=%d k=%d: mismatched Elkan result: iterations %d and %d", test.n, test.k, elkan.Iterations, lloyd.Iterations)
+			}
+			if !mat.EqualApprox(elkan.Centers, lloyd.Centers, 1e-12) || math.Abs(elkan.Inertia-lloyd.Inertia) > 1e-9*lloyd.Inertia {
+				t.Errorf("n=%d k=%d: mismatched Elkan centers", test.n, test.k)
+			}
+
+			// Check the centers and inertia against the labels.
+			_, d := x.Dims()
+			sums := mat.NewDense(test.k, d, nil)
+			counts := make([]float64, test.k)
+			var inertia float64
+			for i, j := range lloyd.Labels {
+				wi := 1.0
+				if weights != nil {
+					wi = weights[i]
+				}
+				for l := 0; l < d; l++ {
+					sums.Set(j, l, sums.At(j, l)+wi*x.At(i, l))
+				}
+				counts[j] += wi
+			}
+			for j := range counts {
+				for l := 0; l < d; l++ {
+					sums.Set(j, l, sums.At(j, l)/counts[j])
+				}
+			}
+			if !mat.EqualApprox(sums, lloyd.Centers, 1e-6) {
+				t.Errorf("n=%d k=%d: centers are not the means of their clusters", test.n, test.k)
+			}
+			for i, j := range lloyd.Labels {
+				wi := 1.0
+				if weights != nil {
+					wi = weights[i]
+				}
+				var v float64
+				for l := 0; l < d; l++ {
+					v += math.Pow(x.At(i, l)-lloyd.Centers.At(j, l), 2)
+				}
+				inertia += wi * v
+			}
+			if math.Abs(inertia-lloyd.Inertia) > 1e-9*inertia {
+				t.Errorf("n=%d k=%d: unexpected inertia: got %v want %v", test.n, test.k, lloyd.Inertia, inertia)
+			}
+
+			// Mini-batch k-means is close to the full result.
+			mb := KMeans(x, weights, test.k, &KMeansSettings{
+				Init:          init,
+				Algorithm:     MiniBatch,
+				BatchSize:     100,
+				MaxIterations: 200,
+				Src:           rand.NewSource(1),
+			})
+			if !samePartition(mb.Labels, lloyd.Labels) {
+				t.Errorf("n=%d k=%d: mini-batch did not recover the clusters", test.n, test.k)
+			}
+			if mb.Inertia > 1.05*lloyd.Inertia {
+				t.Errorf("n=%d k=%d: unexpected mini-batch inertia: got %v want about %v", test.n, test.k, mb.Inertia, lloyd.Inertia)
+			}
+		}
+	}
+}
+
+func TestKMeansPlusPlus(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, _ := blobs(400, 4, 2, 20, 0.1, rnd)
+	var ok int
+	for seed := uint64(1); seed <= 20; seed++ {
+		c := KMeansPlusPlus(x, nil, 4, rand.NewSource(seed))
+		labels := make([]int, 4)
+		for j := range labels {
+			labels[j], _ = nearest(c.RawRowView(j), c)
+		}
+		// The seeds are observations, and for well separated
+		// clusters they are almost always in distinct clusters.
+		seen := make(map[int]bool)
+		for j := 0; j < 4; j++ {
+			found := false
+			for i := 0; i < 400; i++ {
+				if mat.Equal(x.RowView(i), c.RowView(j)) {
+					found = true
+					seen[i%4] = true
+					break
+				}
+			}
+			if !found {
+				t.Fatalf("seed %d is not an observation", j)
+			}
+		}
+		if len(seen) == 4 {
+			ok++
+		}
+	}
+	if ok < 18 {
+		t.Errorf("unexpected number of seedings covering all the clusters: got %d of 20", ok)
+	}
+
+	// Zero weighted observations are never chosen.
+	w := make([]float64, 400)
+	for i := range w {
+		if i%4 != 0 {
+			w[i] = 1
+		}
+	}
+	c := KMeansPlusPlus(x, w, 3, rand.NewSource(1))
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 400; i += 4 {
+			if mat.Equal(x.RowView(i), c.RowView(j)) {
+				t.Errorf("zero weighted observation %d chosen as seed", i)
+			}
+		}
+	}
+}
+
+func TestKMeansEmptyCluster(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(6, 1, []float64{0, 0.1, 0.2, 10, 10.1, 10.2})
+	// The third initial center is far from all the
+	// observations, so its cluster is empty.
+	init := mat.NewDense(3, 1, []float64{0, 10, 100})
+	for _, alg := range []KMeansAlgorithm{Lloyd, Elkan} {
+		r := KMeans(x, nil, 3, &KMeansSettings{Init: init, Algorithm: alg})
+		counts := make(map[int]int)
+		for _, l := range r.Labels {
+			counts[l]++
+		}
+		if len(counts) != 3 {
+			t.Errorf("algorithm %d: unexpected number of non-empty clusters: got %d want 3", alg, len(counts))
+		}
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distfit
+
+import "gonum.org/v1/gonum/stat/distuv"
+
+// Beta is a Fitter for the beta distribution with parameters Alpha and Beta.
+type Beta struct{ *distuv.Beta }
+
+// Parameters stores Alpha and Beta into dst.
+func (b Beta) Parameters(dst []float64) { set2(dst, b.Alpha, b.Beta.Beta) }
+
+// SetParameters sets Alpha and Beta to the elements of p.
+func (b Beta) SetParameters(p []float64) { get2(p, &b.Alpha, &b.Beta.Beta) }
+
+// Positive returns true for all parameters.
+func (Beta) Positive(int) bool { return true }
+
+// ChiSquared is a Fitter for the χ² distribution with parameter K.
+type ChiSquared struct{ *distuv.ChiSquared }
+
+// Parameters stores K into dst.
+func (c ChiSquared) Parameters(dst []float64) { set1(dst, c.K) }
+
+// SetParameters sets K to the element of p.
+func (c ChiSquared) SetParameters(p []float64) { get1(p, &c.K) }
+
+// Positive returns true for all parameters.
+func (ChiSquared) Positive(int) bool { return true }
+
+// F is a Fitter for the F distribution with parameters D1 and D2.
+type F struct{ *distuv.F }
+
+// Parameters stores D1 and D2 into dst.
+func (f F) Parameters(dst []float64) { set2(dst, f.D1, f.D2) }
+
+// SetParameters sets D1 and D2 to the elements of p.
+func (f F) SetParameters(p []float64) { get2(p, &f.D1, &f.D2) }
+
+// Positive returns true for all parameters.
+func (F) Positive(int) bool { return true }
+
+// Gamma is a Fitter for the gamma distribution with parameters Alpha and
+// Beta.
+type Gamma struct{ *distuv.Gamma }
+
+// Parameters stores Alpha and Beta into dst.
+func (g Gamma) Parameters(dst []float64) { set2(dst, g.Alpha, g.Beta) }
+
+// SetParameters sets Alpha and Beta to the elements of p.
+func (g Gamma) SetParameters(p []float64) { get2(p, &g.Alpha, &g.Beta) }
+
+// Positive returns true for all parameters.
+func (Gamma) Positive(int) bool { return true }
+
+// GumbelRight is a Fitter for the right-skewed Gumbel distribution with
+// parameters Mu and Beta.
+type GumbelRight struct{ *distuv.GumbelRight }
+
+// Parameters stores Mu and Beta into dst.
+func (g GumbelRight) Parameters(dst []float64) { set2(dst, g.Mu, g.Beta) }
+
+// SetParameters sets Mu and Beta to the elements of p.
+func (g GumbelRight) SetParameters(p []float64) { get2(p, &g.Mu, &g.Beta) }
+
+// Positive returns whether parameter i is Beta.
+func (GumbelRight) Positive(i int) bool { return i == 1 }
+
+// InverseGamma is a Fitter for the inverse gamma distribution with
+// parameters Alpha and Beta.
+type InverseGamma struct{ *distuv.InverseGamma }
+
+// Parameters stores Alpha and Beta into dst.
+func (g InverseGamma) Parameters(dst []float64) { set2(dst, g.Alpha, g.Beta) }
+
+// SetParameters sets Alpha and Beta to the elements of p.
+func (g InverseGamma) SetParameters(p []float64) { get2(p, &g.Alpha, &g.Beta) }
+
+// Positive returns true for all parameters.
+func (InverseGamma) Positive(int) bool { return true }
+
+// LogNormal is a Fitter for the log-normal distribution with parameters Mu
+// and Sigma.
+type LogNormal struct{ *distuv.LogNormal }
+
+// Parameters stores Mu and Sigma into dst.
+func (l LogNormal) Parameters(dst []float64) { set2(dst, l.Mu, l.Sigma) }
+
+// SetParameters sets Mu and Sigma to the elements of p.
+func (l LogNormal) SetParameters(p []float64) { get2(p, &l.Mu, &l.Sigma) }
+
+// Positive returns whether parameter i is Sigma.
+func (LogNormal) Positive(i int) bool { return i == 1 }
+
+// StudentsT is a Fitter for Student's t distribution with parameters Mu,
+// Sigma and Nu.
+type StudentsT struct{ *distuv.StudentsT }
+
+// Parameters stores Mu, Sigma and Nu into dst.
+func (s StudentsT) Parameters(dst []float64) {
+	if len(dst) != 3 {
+		panic(badLength)
+	}
+	dst[0], dst[1], dst[2] = s.Mu, s.Sigma, s.Nu
+}
+
+// SetParameters sets Mu, Sigma and Nu to the elements of p.
+func (s StudentsT) SetParameters(p []float64) {
+	if len(p) != 3 {
+		panic(badLength)
+	}
+	s.Mu, s.Sigma, s.Nu = p[0], p[1], p[2]
+}
+
+// Positive returns whether parameter i is Sigma or Nu.
+func (StudentsT) Positive(i int) bool { return i != 0 }
+
+// Weibull is a Fitter for the Weibull distribution with parameters K and
+// Lambda.
+type Weibull struct{ *distuv.Weibull }
+
+// Parameters stores K and Lambda into dst.
+func (w Weibull) Parameters(dst []float64) { set2(dst, w.K, w.Lambda) }
+
+// SetParameters sets K and Lambda to the elements of p.
+func (w Weibull) SetParameters(p []float64) { get2(p, &w.K, &w.Lambda) }
+
+// Positive returns true for all parameters.
+func (Weibull) Positive(int) bool { return true }
+
+const badLength = "distfit: incorrect number of parameters"
+
+func set1(dst []float64, a float64) {
+	if len(dst) != 1 {
+		panic(badLength)
+	}
+	dst[0] = a
+}
+
+func get1(p []float64, a *float64) {
+	if len(p) != 1 {
+		panic(badLength)
+	}
+	*a = p[0]
+}
+
+func set2(dst []float64, a, b float64) {
+	if len(dst) != 2 {
+		panic(badLength)
+	}
+	dst[0], dst[1] = a, b
+}
+
+func get2(p []float64, a, b *float64) {
+	if len(p) != 2 {
+		panic(badLength)
+	}
+	*a, *b = p[0], p[1]
+}
@@ -0,0 +1,120 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distfit
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestFitDistributions(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	for _, test := range []struct {
+		name  string
+		truth distuv.Rander
+		fit   Fitter
+		want  []float64
+	}{
+		{
+			name:  "Beta",
+			truth: distuv.Beta{Alpha: 2, Beta: 5, Src: src},
+			fit:   Beta{&distuv.Beta{Alpha: 1, Beta: 1}},
+			want:  []float64{2, 5},
+		},
+		{
+			name:  "ChiSquared",
+			truth: distuv.ChiSquared{K: 4, Src: src},
+			fit:   ChiSquared{&distuv.ChiSquared{K: 1}},
+			want:  []float64{4},
+		},
+		{
+			name:  "F",
+			truth: distuv.F{D1: 5, D2: 10, Src: src},
+			fit:   F{&distuv.F{D1: 2, D2: 5}},
+			want:  []float64{5, 10},
+		},
+		{
+			name:  "Gamma",
+			truth: distuv.Gamma{Alpha: 2, Beta: 3, Src: src},
+			fit:   Gamma{&distuv.Gamma{Alpha: 1, Beta: 1}},
+			want:  []float64{2, 3},
+		},
+		{
+			name:  "GumbelRight",
+			truth: distuv.GumbelRight{Mu: 1, Beta: 2, Src: src},
+			fit:   GumbelRight{&distuv.GumbelRight{Mu: 0, Beta: 1}},
+			want:  []float64{1, 2},
+		},
+		{
+			name:  "InverseGamma",
+			truth: distuv.InverseGamma{Alpha: 3, Beta: 2, Src: src},
+			fit:   InverseGamma{&distuv.InverseGamma{Alpha: 1, Beta: 1}},
+			want:  []float64{3, 2},
+		},
+		{
+			name:  "LogNormal",
+			truth: distuv.LogNormal{Mu: 0.5, Sigma: 0.8, Src: src},
+			fit:   LogNormal{&distuv.LogNormal{Mu: 0, Sigma: 1}},
+			want:  []float64{0.5, 0.8},
+		},
+		{
+			name:  "StudentsT",
+			truth: distuv.StudentsT{Mu: 1, Sigma: 2, Nu: 5, Src: src},
+			fit:   StudentsT{&distuv.StudentsT{Mu: 0, Sigma: 1, Nu: 1}},
+			want:  []float64{1, 2, 5},
+		},
+		{
+			name:  "Weibull",
+			truth: distuv.Weibull{K: 1.5, Lambda: 2, Src: src},
+			fit:   Weibull{&distuv.Weibull{K: 1, Lambda: 1}},
+			want:  []float64{1.5, 2},
+		},
+	} {
+		if n := test.fit.NumParameters(); n != len(test.want) {
+			t.Errorf("%s: unexpected number of parameters: got %d want %d", test.name, n, len(test.want))
+			continue
+		}
+		// Parameters and SetParameters are inverses.
+		p := make([]float64, len(test.want))
+		test.fit.SetParameters(test.want)
+		test.fit.Parameters(p)
+		if !floats.Equal(p, test.want) {
+			t.Errorf("%s: parameters do not round trip: got %v want %v", test.name, p, test.want)
+		}
+		start := make([]float64, len(p))
+		for i := range start {
+			start[i] = 1
+			if !test.fit.Positive(i) {
+				start[i] = 0
+			}
+		}
+		test.fit.SetParameters(start)
+
+		x := make([]float64, 2000)
+		for i := range x {
+			x[i] = test.truth.Rand()
+		}
+		res, err := Fit(test.fit, Data{X: x}, nil, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		test.fit.Parameters(p)
+		if !floats.Equal(p, res.Parameters) {
+			t.Errorf("%s: fitted distribution parameters differ from result: got %v want %v", test.name, p, res.Parameters)
+		}
+		for i, v := range res.Parameters {
+			if math.Abs(v-test.want[i]) > 4*res.StdErr[i] {
+				t.Errorf("%s: parameter %d not recovered: got %v±%v want %v", test.name, i, v, res.StdErr[i], test.want[i])
+			}
+		}
+	}
+}
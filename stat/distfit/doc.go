@@ -0,0 +1,12 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package distfit provides maximum likelihood estimation of the parameters of
+// univariate distributions from possibly censored data.
+//
+// The distributions in package distuv that do not have closed form maximum
+// likelihood estimates are made available for fitting by the wrapper types in
+// this package, and other distributions may be fitted by implementing the
+// Fitter interface.
+package distfit // import "gonum.org/v1/gonum/stat/distfit"
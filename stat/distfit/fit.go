@@ -0,0 +1,271 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distfit
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// defaultGradientThreshold is the gradient threshold of the optimization
+// if it is not set.
+const defaultGradientThreshold = 1e-6
+
+var (
+	// ErrNotPositiveDefinite is returned by Fit when the observed
+	// information matrix at the estimate is not positive definite.
+	ErrNotPositiveDefinite = errors.New("distfit: observed information not positive definite")
+
+	// ErrInitialLikelihood is returned by Fit when the log likelihood of
+	// the initial parameters is not finite.
+	ErrInitialLikelihood = errors.New("distfit: initial log likelihood not finite")
+)
+
+// Fitter is a parametric univariate distribution whose parameters can be
+// estimated by maximum likelihood.
+type Fitter interface {
+	// NumParameters returns the number of parameters
+	// of the distribution.
+	NumParameters() int
+
+	// Parameters stores the parameters of the
+	// distribution into dst, which must have length
+	// NumParameters.
+	Parameters(dst []float64)
+
+	// SetParameters sets the parameters of the
+	// distribution to p.
+	SetParameters(p []float64)
+
+	// Positive returns whether parameter i must be
+	// positive. Other parameters are unconstrained.
+	Positive(i int) bool
+
+	// LogProb returns the log of the probability
+	// density function at x.
+	LogProb(x float64) float64
+
+	// CDF returns the cumulative distribution
+	// function at x.
+	CDF(x float64) float64
+
+	// Survival returns the survival function at x.
+	Survival(x float64) float64
+}
+
+// Data holds possibly censored observations of a univariate distribution.
+type Data struct {
+	// X holds the exactly observed values.
+	X []float64
+
+	// Right holds right censored observations,
+	// which are only known to be greater than
+	// the values.
+	Right []float64
+
+	// Left holds left censored observations,
+	// which are only known to be less than the
+	// values.
+	Left []float64
+
+	// Interval holds interval censored
+	// observations, which are only known to be
+	// between the lower and upper bounds.
+	Interval [][2]float64
+}
+
+func (d Data) len() int {
+	return len(d.X) + len(d.Right) + len(d.Left) + len(d.Interval)
+}
+
+// LogLikelihood returns the log likelihood of the data for the distribution
+// f,
+//
+//	Σ log f(x_i) + Σ log S(r_i) + Σ log F(l_i) + Σ log(F(b_i) - F(a_i)),
+//
+// where f is the density, F is the cumulative distribution function and S is
+// the survival function of the distribution, x_i are the exact observations,
+// r_i and l_i are the right and left censored observations and [a_i, b_i]
+// are the censoring intervals.
+//
+// LogLikelihood panics if the lower bound of a censoring interval is greater
+// than its upper bound.
+func LogLikelihood(f Fitter, data Data) float64 {
+	var l float64
+	for _, x := range data.X {
+		l += f.LogProb(x)
+	}
+	for _, x := range data.Right {
+		l += math.Log(f.Survival(x))
+	}
+	for _, x := range data.Left {
+		l += math.Log(f.CDF(x))
+	}
+	for _, b := range data.Interval {
+		if b[0] > b[1] {
+			panic("distfit: invalid censoring interval")
+		}
+		l += math.Log(f.CDF(b[1]) - f.CDF(b[0]))
+	}
+	return l
+}
+
+// Result holds the maximum likelihood estimates of the parameters of a
+// distribution.
+type Result struct {
+	// Parameters holds the estimates of the
+	// parameters.
+	Parameters []float64
+
+	// LogLikelihood is the log likelihood of the
+	// data at the estimates.
+	LogLikelihood float64
+
+	// Cov is the estimated covariance of the
+	// estimates, the inverse of the observed
+	// information matrix. Cov is nil if the
+	// observed information is not positive
+	// definite.
+	Cov *mat.SymDense
+
+	// StdErr holds the standard errors of the
+	// estimates, the square roots of the diagonal
+	// of Cov. StdErr is NaN if the observed
+	// information is not positive definite.
+	StdErr []float64
+
+	// Status is the status of the optimization.
+	Status optimize.Status
+}
+
+// Fit sets the parameters of f to the maximum likelihood estimates for the
+// data and returns the estimates. The current parameters of f are the initial
+// parameters of the optimization, and must have a finite log likelihood.
+//
+// The mean log likelihood of the observations is maximized by
+// optimize.Minimize with the given settings and method, using the logarithms
+// of the positive parameters and finite difference gradients. If method is
+// nil, a default method is used. If settings is nil or its GradientThreshold
+// is zero, a gradient threshold of 1e-6 suited to the accuracy of the finite
+// differences is used. The observed information matrix is estimated by
+// finite differences at the estimates.
+//
+// If the log likelihood of the initial parameters is not finite, Fit returns
+// a nil result and ErrInitialLikelihood. If the optimization fails without
+// an estimate, the parameters of f are unchanged and Fit returns a nil
+// result and the optimization error. If the observed information is not
+// positive definite, Fit returns the estimates without standard errors and
+// ErrNotPositiveDefinite. Otherwise Fit returns the estimates and any error
+// from the optimization.
+//
+// Fit panics if data has no observations or if a positive initial parameter
+// is not positive.
+func Fit(f Fitter, data Data, settings *optimize.Settings, method optimize.Method) (*Result, error) {
+	if data.len() == 0 {
+		panic("distfit: no observations")
+	}
+	n := f.NumParameters()
+	p0 := make([]float64, n)
+	f.Parameters(p0)
+	u0 := make([]float64, n)
+	for i, v := range p0 {
+		if f.Positive(i) {
+			if v <= 0 {
+				panic("distfit: non-positive initial parameter")
+			}
+			v = math.Log(v)
+		}
+		u0[i] = v
+	}
+
+	// Minimize the mean negative log likelihood so
+	// that the convergence criteria do not depend on
+	// the number of observations.
+	scale := float64(data.len())
+	p := make([]float64, n)
+	nll := func(u []float64) float64 {
+		for i, v := range u {
+			if f.Positive(i) {
+				v = math.Exp(v)
+			}
+			p[i] = v
+		}
+		f.SetParameters(p)
+		l := LogLikelihood(f, data)
+		if math.IsNaN(l) {
+			return math.Inf(1)
+		}
+		return -l / scale
+	}
+	if math.IsInf(nll(u0), 0) {
+		f.SetParameters(p0)
+		return nil, ErrInitialLikelihood
+	}
+	problem := optimize.Problem{
+		Func: nll,
+		Grad: func(grad, u []float64) {
+			fd.Gradient(grad, nll, u, &fd.Settings{Formula: fd.Central})
+		},
+	}
+	var s optimize.Settings
+	if settings != nil {
+		s = *settings
+	}
+	if s.GradientThreshold == 0 {
+		s.GradientThreshold = defaultGradientThreshold
+	}
+	res, err := optimize.Minimize(problem, u0, &s, method)
+	if res == nil {
+		f.SetParameters(p0)
+		return nil, err
+	}
+
+	// Estimate the covariance of the parameters on the
+	// optimization scale from the inverse Hessian of the
+	// negative log likelihood and convert it to the
+	// natural scale by the delta method. The Jacobian of
+	// the transformation is diagonal with the positive
+	// parameters on the diagonal.
+	var hess mat.SymDense
+	fd.Hessian(&hess, nll, res.X, &fd.Settings{Formula: fd.Central, Step: 1e-3})
+	hess.ScaleSym(scale, &hess)
+	l := -scale * nll(res.X)
+	r := &Result{
+		Parameters:    make([]float64, n),
+		LogLikelihood: l,
+		StdErr:        make([]float64, n),
+		Status:        res.Status,
+	}
+	f.Parameters(r.Parameters)
+
+	var chol mat.Cholesky
+	if ok := chol.Factorize(&hess); !ok {
+		for i := range r.StdErr {
+			r.StdErr[i] = math.NaN()
+		}
+		return r, ErrNotPositiveDefinite
+	}
+	var cov mat.SymDense
+	_ = chol.InverseTo(&cov)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			v := cov.At(i, j)
+			if f.Positive(i) {
+				v *= r.Parameters[i]
+			}
+			if f.Positive(j) {
+				v *= r.Parameters[j]
+			}
+			cov.SetSym(i, j, v)
+		}
+		r.StdErr[i] = math.Sqrt(cov.At(i, i))
+	}
+	r.Cov = &cov
+	return r, err
+}
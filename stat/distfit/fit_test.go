@@ -0,0 +1,208 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distfit
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mathext"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestLogLikelihood(t *testing.T) {
+	t.Parallel()
+	// The Weibull distribution with K = 1 and λ = 1 is
+	// the unit exponential distribution with log density
+	// -x, survival function exp(-x) and CDF 1-exp(-x).
+	f := Weibull{&distuv.Weibull{K: 1, Lambda: 1}}
+	data := Data{
+		X:        []float64{0.5, 1},
+		Right:    []float64{2},
+		Left:     []float64{1},
+		Interval: [][2]float64{{1, 2}},
+	}
+	want := -1.5 - 2 + math.Log(1-math.Exp(-1)) + math.Log(math.Exp(-1)-math.Exp(-2))
+	if got := LogLikelihood(f, data); !floats.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+		t.Errorf("unexpected log likelihood: got %v want %v", got, want)
+	}
+}
+
+func TestFitLogNormal(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 500
+	x := make([]float64, n)
+	logX := make([]float64, n)
+	for i := range x {
+		logX[i] = 0.3 + 1.5*rnd.NormFloat64()
+		x[i] = math.Exp(logX[i])
+	}
+	f := LogNormal{&distuv.LogNormal{Mu: 0, Sigma: 1}}
+	res, err := Fit(f, Data{X: x}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The estimates are the mean and the plug-in standard
+	// deviation of log x, with standard errors σ/√n and
+	// σ/√(2n) and zero covariance.
+	mu, v := stat.MeanVariance(logX, nil)
+	sigma := math.Sqrt(v * (n - 1) / n)
+	want := []float64{mu, sigma}
+	if !floats.EqualApprox(res.Parameters, want, 1e-6) {
+		t.Errorf("unexpected estimates: got %v want %v", res.Parameters, want)
+	}
+	wantSE := []float64{sigma / math.Sqrt(n), sigma / math.Sqrt(2*n)}
+	if !floats.EqualApprox(res.StdErr, wantSE, 1e-5) {
+		t.Errorf("unexpected standard errors: got %v want %v", res.StdErr, wantSE)
+	}
+	if c := res.Cov.At(0, 1); math.Abs(c) > 1e-6 {
+		t.Errorf("unexpected covariance of estimates: got %v want 0", c)
+	}
+	if want := LogLikelihood(f, Data{X: x}); res.LogLikelihood != want {
+		t.Errorf("unexpected log likelihood: got %v want %v", res.LogLikelihood, want)
+	}
+}
+
+func TestFitGamma(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	truth := distuv.Gamma{Alpha: 0.7, Beta: 2, Src: rnd}
+	x := make([]float64, 300)
+	var meanLog float64
+	for i := range x {
+		x[i] = truth.Rand()
+		meanLog += math.Log(x[i]) / float64(len(x))
+	}
+	mean := stat.Mean(x, nil)
+	f := Gamma{&distuv.Gamma{Alpha: 1, Beta: 1}}
+	res, err := Fit(f, Data{X: x}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The estimates solve the likelihood equations
+	//  log α - ψ(α) = log x̄ - mean(log x),
+	//  β = α/x̄.
+	alpha, beta := f.Alpha, f.Beta
+	if got, want := math.Log(alpha)-mathext.Digamma(alpha), math.Log(mean)-meanLog; math.Abs(got-want) > 1e-6 {
+		t.Errorf("shape estimate does not solve likelihood equation: %v != %v", got, want)
+	}
+	if math.Abs(beta-alpha/mean) > 1e-5 {
+		t.Errorf("rate estimate does not solve likelihood equation: %v != %v", beta, alpha/mean)
+	}
+	if !floats.Equal(res.Parameters, []float64{alpha, beta}) {
+		t.Errorf("result parameters differ from fitted distribution: got %v want %v", res.Parameters, []float64{alpha, beta})
+	}
+}
+
+func TestFitCensored(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	truth := distuv.Weibull{K: 2, Lambda: 3, Src: rnd}
+
+	// Observations above c are right censored at c,
+	// below l are left censored at l, and those in
+	// [a, b] are interval censored.
+	const (
+		l = 0.5
+		a = 1.5
+		b = 2
+		c = 3.5
+	)
+	var data, exact Data
+	for i := 0; i < 2000; i++ {
+		x := truth.Rand()
+		switch {
+		case x > c:
+			data.Right = append(data.Right, c)
+		case x < l:
+			data.Left = append(data.Left, l)
+		case a <= x && x <= b:
+			data.Interval = append(data.Interval, [2]float64{a, b})
+		default:
+			data.X = append(data.X, x)
+			exact.X = append(exact.X, x)
+		}
+	}
+	if len(data.Right) == 0 || len(data.Left) == 0 || len(data.Interval) == 0 {
+		t.Fatal("no censored observations")
+	}
+
+	f := Weibull{&distuv.Weibull{K: 1, Lambda: 1}}
+	res, err := Fit(f, data, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{2, 3}
+	for i, v := range res.Parameters {
+		if math.Abs(v-want[i]) > 4*res.StdErr[i] {
+			t.Errorf("parameter %d not recovered: got %v±%v want %v", i, v, res.StdErr[i], want[i])
+		}
+	}
+
+	// The estimate is a stationary point of the
+	// censored log likelihood.
+	grad := fd.Gradient(nil, func(p []float64) float64 {
+		f.SetParameters(p)
+		return LogLikelihood(f, data)
+	}, res.Parameters, &fd.Settings{Formula: fd.Central})
+	if floats.Norm(grad, math.Inf(1)) > 1e-3 {
+		t.Errorf("estimate is not a stationary point of the log likelihood: gradient %v", grad)
+	}
+
+	// Ignoring the censored observations biases the
+	// estimates.
+	naive := Weibull{&distuv.Weibull{K: 1, Lambda: 1}}
+	nres, err := Fit(naive, exact, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(nres.Parameters[0]-want[0]) < 4*nres.StdErr[0] {
+		t.Errorf("unexpected unbiased shape estimate ignoring censoring: got %v±%v", nres.Parameters[0], nres.StdErr[0])
+	}
+}
+
+func TestFitErrors(t *testing.T) {
+	t.Parallel()
+	// The beta distribution has zero density at 2.
+	f := Beta{&distuv.Beta{Alpha: 2, Beta: 3}}
+	res, err := Fit(f, Data{X: []float64{0.5, 2}}, nil, nil)
+	if err != ErrInitialLikelihood || res != nil {
+		t.Errorf("unexpected result for infinite initial likelihood: got %v, %v want nil, %v", res, err, ErrInitialLikelihood)
+	}
+	if f.Alpha != 2 || f.Beta.Beta != 3 {
+		t.Errorf("parameters modified after failure: got %v, %v want 2, 3", f.Alpha, f.Beta.Beta)
+	}
+
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "no observations", fn: func() { Fit(f, Data{}, nil, nil) }},
+		{name: "initial parameter", fn: func() {
+			Fit(Gamma{&distuv.Gamma{Alpha: 0, Beta: 1}}, Data{X: []float64{1}}, nil, nil)
+		}},
+		{name: "interval", fn: func() { LogLikelihood(f, Data{Interval: [][2]float64{{0.5, 0.2}}}) }},
+		{name: "parameter length", fn: func() { f.SetParameters([]float64{1}) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
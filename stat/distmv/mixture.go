@@ -0,0 +1,552 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+var badComponents = "distmv: invalid number of mixture components"
+
+// CovarianceType specifies the form of the covariance matrices of the
+// components of a GaussianMixture.
+type CovarianceType int
+
+const (
+	// FullCovariance components have general covariance
+	// matrices.
+	FullCovariance CovarianceType = iota
+
+	// DiagonalCovariance components have diagonal
+	// covariance matrices.
+	DiagonalCovariance
+
+	// SphericalCovariance components have covariance
+	// matrices that are multiples of the identity.
+	SphericalCovariance
+)
+
+// GaussianMixture is a mixture of multivariate normal distributions with
+// the density
+//
+//	p(x) = Σ_j π_j * N(x; μ_j, Σ_j),
+//
+// where the mixing weights π_j are non-negative and sum to one.
+type GaussianMixture struct {
+	dim        int
+	weights    []float64
+	logWeights []float64
+	components []*Normal
+	covType    CovarianceType
+
+	cat distuv.Categorical
+}
+
+// NewGaussianMixture returns a new GaussianMixture with the given mixing
+// weights and normal components. The weights are normalized to sum to one
+// and the components are used directly, not copied. The source of random
+// numbers for choosing a component in Rand is src, and the components use
+// their own sources. If src is nil, the global source is used. The
+// components are treated as having full covariance matrices when counting
+// the parameters of the mixture.
+//
+// NewGaussianMixture panics if there are no components, if the lengths of
+// weights and components differ, if the components have different
+// dimensions, or if the weights are negative or have a zero sum.
+func NewGaussianMixture(weights []float64, components []*Normal, src rand.Source) *GaussianMixture {
+	if len(components) == 0 {
+		panic(badComponents)
+	}
+	if len(weights) != len(components) {
+		panic(badSizeMismatch)
+	}
+	dim := components[0].Dim()
+	for _, c := range components {
+		if c.Dim() != dim {
+			panic(badSizeMismatch)
+		}
+	}
+	w := make([]float64, len(weights))
+	copy(w, weights)
+	return newGaussianMixture(w, append([]*Normal(nil), components...), FullCovariance, src)
+}
+
+// newGaussianMixture returns a GaussianMixture holding w, normalized in
+// place, and components.
+func newGaussianMixture(w []float64, components []*Normal, covType CovarianceType, src rand.Source) *GaussianMixture {
+	for _, v := range w {
+		if v < 0 {
+			panic("distmv: negative mixture weight")
+		}
+	}
+	sum := floats.Sum(w)
+	if sum == 0 {
+		panic("distmv: zero mixture weight sum")
+	}
+	floats.Scale(1/sum, w)
+	logWeights := make([]float64, len(w))
+	for i, v := range w {
+		logWeights[i] = math.Log(v)
+	}
+	return &GaussianMixture{
+		dim:        components[0].Dim(),
+		weights:    w,
+		logWeights: logWeights,
+		components: components,
+		covType:    covType,
+		cat:        distuv.NewCategorical(w, src),
+	}
+}
+
+// Dim returns the dimension of the distribution.
+func (g *GaussianMixture) Dim() int {
+	return g.dim
+}
+
+// NumComponents returns the number of components of the mixture.
+func (g *GaussianMixture) NumComponents() int {
+	return len(g.components)
+}
+
+// Component returns the ith normal component of the mixture. The returned
+// Normal is shared with the receiver and must not be modified.
+func (g *GaussianMixture) Component(i int) *Normal {
+	return g.components[i]
+}
+
+// Weights returns the mixing weights of the mixture. If dst is not nil, the
+// weights are stored in-place into dst and returned, otherwise a new slice is
+// allocated. Weights panics if dst is not nil and its length is not the
+// number of components.
+func (g *GaussianMixture) Weights(dst []float64) []float64 {
+	dst = reuseAs(dst, len(g.weights))
+	copy(dst, g.weights)
+	return dst
+}
+
+// LogProb computes the log of the pdf of the point x.
+func (g *GaussianMixture) LogProb(x []float64) float64 {
+	if len(x) != g.dim {
+		panic(badSizeMismatch)
+	}
+	lp := make([]float64, len(g.components))
+	return g.logProb(lp, x)
+}
+
+// logProb returns the log of the pdf of x, storing the log of the weighted
+// component densities into lp.
+func (g *GaussianMixture) logProb(lp, x []float64) float64 {
+	for j, c := range g.components {
+		lp[j] = g.logWeights[j] + c.LogProb(x)
+	}
+	return floats.LogSumExp(lp)
+}
+
+// Prob computes the value of the probability density function at x.
+func (g *GaussianMixture) Prob(x []float64) float64 {
+	return math.Exp(g.LogProb(x))
+}
+
+// Rand generates a random sample according to the distribution by choosing
+// a component with probability equal to its weight and sampling from it.
+// If x is nil, a new slice is allocated and returned, otherwise the sample
+// is stored in-place into x.
+func (g *GaussianMixture) Rand(x []float64) []float64 {
+	return g.components[int(g.cat.Rand())].Rand(x)
+}
+
+// Responsibilities stores the posterior probabilities of the components for
+// the observations in the rows of x into dst, so that dst[i, j] is the
+// probability that the ith observation was generated by the jth component.
+// The dst matrix must either be zero-sized or n×k.
+//
+// Responsibilities panics if the columns of x do not match the dimension of
+// the distribution, or if dst is not zero-sized and is not n×k.
+func (g *GaussianMixture) Responsibilities(dst *mat.Dense, x mat.Matrix) {
+	n, d := x.Dims()
+	if d != g.dim {
+		panic(badSizeMismatch)
+	}
+	k := len(g.components)
+	if dst.IsZero() {
+		*dst = *(dst.Grow(n, k).(*mat.Dense))
+	} else if r, c := dst.Dims(); r != n || c != k {
+		panic(badSizeMismatch)
+	}
+	row := make([]float64, d)
+	lp := make([]float64, k)
+	for i := 0; i < n; i++ {
+		mat.Row(row, i, x)
+		lse := g.logProb(lp, row)
+		for j, v := range lp {
+			dst.Set(i, j, math.Exp(v-lse))
+		}
+	}
+}
+
+// LogLikelihood returns the log-likelihood of the observations in the rows
+// of x with the optional weights,
+//
+//	Σ_i w_i * log p(x_i).
+//
+// If weights is nil, all the weights are one. LogLikelihood panics if the
+// columns of x do not match the dimension of the distribution, or if weights
+// is not nil and its length is not the number of rows of x.
+func (g *GaussianMixture) LogLikelihood(x mat.Matrix, weights []float64) float64 {
+	n, d := x.Dims()
+	if d != g.dim {
+		panic(badSizeMismatch)
+	}
+	if weights != nil && len(weights) != n {
+		panic(badSizeMismatch)
+	}
+	row := make([]float64, d)
+	lp := make([]float64, len(g.components))
+	var ll float64
+	for i := 0; i < n; i++ {
+		mat.Row(row, i, x)
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		ll += w * g.logProb(lp, row)
+	}
+	return ll
+}
+
+// NumParameters returns the number of free parameters of the mixture, the
+// k-1 independent weights, the k means and the parameters of the k
+// covariance matrices which depend on their CovarianceType.
+func (g *GaussianMixture) NumParameters() int {
+	k, d := len(g.components), g.dim
+	var cov int
+	switch g.covType {
+	case FullCovariance:
+		cov = d * (d + 1) / 2
+	case DiagonalCovariance:
+		cov = d
+	case SphericalCovariance:
+		cov = 1
+	}
+	return k - 1 + k*d + k*cov
+}
+
+// AIC returns the Akaike information criterion of the mixture for the
+// observations in the rows of x with the optional weights,
+//
+//	AIC = 2*p - 2*LogLikelihood(x, weights),
+//
+// where p is the number of parameters. Lower values indicate a better
+// trade-off between goodness of fit and complexity.
+func (g *GaussianMixture) AIC(x mat.Matrix, weights []float64) float64 {
+	return 2*float64(g.NumParameters()) - 2*g.LogLikelihood(x, weights)
+}
+
+// BIC returns the Bayesian information criterion of the mixture for the
+// observations in the rows of x with the optional weights,
+//
+//	BIC = p*log(n) - 2*LogLikelihood(x, weights),
+//
+// where p is the number of parameters and n is the number of observations,
+// the sum of the weights if weights is not nil. Lower values indicate a
+// better trade-off between goodness of fit and complexity, and BIC
+// penalizes complex models more strongly than AIC.
+func (g *GaussianMixture) BIC(x mat.Matrix, weights []float64) float64 {
+	r, _ := x.Dims()
+	n := float64(r)
+	if weights != nil {
+		n = floats.Sum(weights)
+	}
+	return float64(g.NumParameters())*math.Log(n) - 2*g.LogLikelihood(x, weights)
+}
+
+// GaussianMixtureSettings holds the settings for fitting a GaussianMixture
+// with FitGaussianMixture. See the field comments for default values.
+type GaussianMixtureSettings struct {
+	// Covariance is the form of the covariance
+	// matrices of the components. The default is
+	// FullCovariance.
+	Covariance CovarianceType
+
+	// MaxIterations is the maximum number of EM
+	// iterations. If MaxIterations is zero, a
+	// default of 100 is used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance on the
+	// change of the mean log-likelihood of the
+	// observations between iterations. If Tolerance
+	// is zero, a default of 1e-6 is used.
+	Tolerance float64
+
+	// Regularization is added to the diagonal of
+	// the covariance matrices to keep them positive
+	// definite. If Regularization is zero, a default
+	// of 1e-6 is used.
+	Regularization float64
+
+	// Src is the source of random numbers used for
+	// the initialization and by the fitted mixture.
+	// If Src is nil, the global source is used.
+	Src rand.Source
+}
+
+// FitGaussianMixture fits a GaussianMixture with k components to the
+// observations in the rows of x with the optional weights by the
+// expectation-maximization (EM) algorithm, and returns the mixture and
+// whether the EM iterations converged. If settings is nil, the default
+// settings are used.
+//
+// The means are initialized by k-means++ seeding and the responsibilities
+// by assigning each observation to its nearest mean, see Arthur and
+// Vassilvitskii, "k-means++: the advantages of careful seeding", SODA 2007.
+// EM converges to a local maximum of the likelihood, so fits from several
+// sources of random numbers may be compared with LogLikelihood. The number
+// of components may be chosen by minimizing the BIC or AIC of the fits.
+//
+// FitGaussianMixture panics if x has no rows, if k is not positive or
+// greater than the number of rows of x, if weights is not nil and its length
+// is not the number of rows of x, or if the settings are invalid.
+func FitGaussianMixture(x mat.Matrix, weights []float64, k int, settings *GaussianMixtureSettings) (g *GaussianMixture, converged bool) {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic(badZeroDimension)
+	}
+	if k <= 0 || k > n {
+		panic(badComponents)
+	}
+	if weights != nil && len(weights) != n {
+		panic(badSizeMismatch)
+	}
+	var s GaussianMixtureSettings
+	if settings != nil {
+		s = *settings
+	}
+	switch s.Covariance {
+	case FullCovariance, DiagonalCovariance, SphericalCovariance:
+	default:
+		panic("distmv: invalid covariance type")
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 100
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = 1e-6
+	}
+	if s.Regularization == 0 {
+		s.Regularization = 1e-6
+	}
+	if s.MaxIterations < 0 || s.Tolerance < 0 || s.Regularization < 0 {
+		panic("distmv: invalid mixture settings")
+	}
+
+	xd := mat.DenseCopyOf(x)
+	w := weights
+	if w == nil {
+		w = make([]float64, n)
+		for i := range w {
+			w[i] = 1
+		}
+	}
+	sumW := floats.Sum(w)
+
+	// Initialize the responsibilities by hard assignment
+	// to the k-means++ seeds.
+	resp := mat.NewDense(n, k, nil)
+	centers := kMeansPlusPlus(xd, w, k, s.Src)
+	for i := 0; i < n; i++ {
+		row := xd.RawRowView(i)
+		best, bestDist := 0, math.Inf(1)
+		for j, c := range centers {
+			if dist := sqDist(row, c); dist < bestDist {
+				best, bestDist = j, dist
+			}
+		}
+		resp.Set(i, best, 1)
+	}
+
+	components := make([]*Normal, k)
+	mix := make([]float64, k)
+	lp := make([]float64, k)
+	prev := math.Inf(-1)
+	for iter := 0; iter < s.MaxIterations; iter++ {
+		gaussianMixtureMStep(components, mix, centers, xd, w, resp, s)
+		g = newGaussianMixture(append([]float64(nil), mix...), append([]*Normal(nil), components...), s.Covariance, s.Src)
+
+		// Compute the responsibilities of the updated
+		// mixture and the mean log-likelihood.
+		var ll float64
+		for i := 0; i < n; i++ {
+			lse := g.logProb(lp, xd.RawRowView(i))
+			for j, v := range lp {
+				resp.Set(i, j, math.Exp(v-lse))
+			}
+			ll += w[i] * lse
+		}
+		ll /= sumW
+		if math.Abs(ll-prev) <= s.Tolerance {
+			return g, true
+		}
+		prev = ll
+	}
+	return g, false
+}
+
+// gaussianMixtureMStep updates the mixing weights mix and the components
+// from the responsibilities resp of the weighted observations x. Components
+// without responsibility keep their previous parameters, or are centered on
+// their seed with the covariance of the observations if they have none.
+func gaussianMixtureMStep(components []*Normal, mix []float64, seeds [][]float64, x *mat.Dense, w []float64, resp *mat.Dense, s GaussianMixtureSettings) {
+	n, d := x.Dims()
+	mu := make([]float64, d)
+	diff := make([]float64, d)
+	for j := range components {
+		var nj float64
+		for i := range mu {
+			mu[i] = 0
+		}
+		for i := 0; i < n; i++ {
+			r := w[i] * resp.At(i, j)
+			nj += r
+			floats.AddScaled(mu, r, x.RawRowView(i))
+		}
+		mix[j] = nj
+		if nj == 0 {
+			if components[j] == nil {
+				components[j] = fallbackComponent(seeds[j], x, w, s)
+			}
+			continue
+		}
+		floats.Scale(1/nj, mu)
+
+		sigma := mat.NewSymDense(d, nil)
+		for i := 0; i < n; i++ {
+			r := w[i] * resp.At(i, j)
+			if r == 0 {
+				continue
+			}
+			floats.SubTo(diff, x.RawRowView(i), mu)
+			switch s.Covariance {
+			case FullCovariance:
+				sigma.SymRankOne(sigma, r/nj, mat.NewVecDense(d, diff))
+			default:
+				for l, v := range diff {
+					sigma.SetSym(l, l, sigma.At(l, l)+r/nj*v*v)
+				}
+			}
+		}
+		regularizeCovariance(sigma, s)
+		c, ok := NewNormal(mu, sigma, s.Src)
+		if !ok {
+			if components[j] == nil {
+				components[j] = fallbackComponent(seeds[j], x, w, s)
+			}
+			continue
+		}
+		components[j] = c
+	}
+}
+
+// regularizeCovariance adds the regularization of s to the diagonal of sigma
+// after averaging its diagonal for spherical covariances.
+func regularizeCovariance(sigma *mat.SymDense, s GaussianMixtureSettings) {
+	d := sigma.Symmetric()
+	if s.Covariance == SphericalCovariance {
+		var v float64
+		for l := 0; l < d; l++ {
+			v += sigma.At(l, l)
+		}
+		v /= float64(d)
+		for l := 0; l < d; l++ {
+			sigma.SetSym(l, l, v)
+		}
+	}
+	for l := 0; l < d; l++ {
+		sigma.SetSym(l, l, sigma.At(l, l)+s.Regularization)
+	}
+}
+
+// fallbackComponent returns a normal distribution centered on mu with the
+// regularized diagonal covariance of all the observations.
+func fallbackComponent(mu []float64, x *mat.Dense, w []float64, s GaussianMixtureSettings) *Normal {
+	n, d := x.Dims()
+	sumW := floats.Sum(w)
+	mean := make([]float64, d)
+	for i := 0; i < n; i++ {
+		floats.AddScaled(mean, w[i]/sumW, x.RawRowView(i))
+	}
+	sigma := mat.NewSymDense(d, nil)
+	for i := 0; i < n; i++ {
+		for l, v := range x.RawRowView(i) {
+			sigma.SetSym(l, l, sigma.At(l, l)+w[i]/sumW*(v-mean[l])*(v-mean[l]))
+		}
+	}
+	s.Covariance = DiagonalCovariance
+	regularizeCovariance(sigma, s)
+	c, ok := NewNormal(mu, sigma, s.Src)
+	if !ok {
+		panic("distmv: invalid fallback covariance")
+	}
+	return c
+}
+
+// kMeansPlusPlus returns k seeds chosen from the rows of x by k-means++
+// seeding, where each seed is chosen with probability proportional to the
+// weighted squared distance of the observation to the nearest seed already
+// chosen.
+func kMeansPlusPlus(x *mat.Dense, w []float64, k int, src rand.Source) [][]float64 {
+	uniform := rand.Float64
+	if src != nil {
+		uniform = rand.New(src).Float64
+	}
+	n, _ := x.Dims()
+	choose := func(p []float64) int {
+		sum := floats.Sum(p)
+		if sum == 0 {
+			return int(uniform() * float64(n))
+		}
+		u := uniform() * sum
+		for i, v := range p {
+			if u -= v; u < 0 {
+				return i
+			}
+		}
+		return n - 1
+	}
+
+	seeds := make([][]float64, 0, k)
+	seeds = append(seeds, x.RawRowView(choose(w)))
+	dist := make([]float64, n)
+	p := make([]float64, n)
+	for i := range dist {
+		dist[i] = sqDist(x.RawRowView(i), seeds[0])
+	}
+	for len(seeds) < k {
+		for i, v := range dist {
+			p[i] = w[i] * v
+		}
+		c := x.RawRowView(choose(p))
+		seeds = append(seeds, c)
+		for i, v := range dist {
+			dist[i] = math.Min(v, sqDist(x.RawRowView(i), c))
+		}
+	}
+	return seeds
+}
+
+// sqDist returns the squared Euclidean distance between x and y.
+func sqDist(x, y []float64) float64 {
+	var d float64
+	for i, v := range x {
+		d += (v - y[i]) * (v - y[i])
+	}
+	return d
+}
@@ -0,0 +1,160 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package distmv
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGaussianMixture(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	n1, _ := NewNormal([]float64{0, 0}, mat.NewSymDense(2, []float64{1, 0.5, 0.5, 1}), src)
+	n2, _ := NewNormal([]float64{5, 5}, mat.NewSymDense(2, []float64{0.5, 0, 0, 2}), src)
+	g := NewGaussianMixture([]float64{1, 3}, []*Normal{n1, n2}, src)
+	if g.Dim() != 2 || g.NumComponents() != 2 {
+		t.Errorf("unexpected dimensions: got dim=%d k=%d", g.Dim(), g.NumComponents())
+	}
+	if w := g.Weights(nil); !floats.EqualApprox(w, []float64{0.25, 0.75}, 1e-15) {
+		t.Errorf("unexpected weights: got %v", w)
+	}
+	for _, x := range [][]float64{{0, 0}, {1, 2}, {5, 4}, {-3, 10}} {
+		want := 0.25*n1.Prob(x) + 0.75*n2.Prob(x)
+		if got := g.Prob(x); math.Abs(got-want) > 1e-14*math.Max(want, 1e-300) {
+			t.Errorf("unexpected density at %v: got %v want %v", x, got, want)
+		}
+	}
+
+	// Samples are drawn from the components in proportion
+	// to their weights.
+	const n = 5000
+	x := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		g.Rand(x.RawRowView(i))
+	}
+	var resp mat.Dense
+	g.Responsibilities(&resp, x)
+	var frac float64
+	for i := 0; i < n; i++ {
+		if math.Abs(resp.At(i, 0)+resp.At(i, 1)-1) > 1e-14 {
+			t.Fatalf("responsibilities of row %d do not sum to one", i)
+		}
+		frac += resp.At(i, 1) / n
+	}
+	if math.Abs(frac-0.75) > 0.01 {
+		t.Errorf("unexpected fraction of samples from second component: got %v want 0.75", frac)
+	}
+	var ll float64
+	for i := 0; i < n; i++ {
+		ll += g.LogProb(x.RawRowView(i))
+	}
+	if got := g.LogLikelihood(x, nil); math.Abs(got-ll) > 1e-10*math.Abs(ll) {
+		t.Errorf("unexpected log-likelihood: got %v want %v", got, ll)
+	}
+	if p := g.NumParameters(); p != 1+4+6 {
+		t.Errorf("unexpected number of parameters: got %d want 11", p)
+	}
+	if got, want := g.BIC(x, nil), 11*math.Log(n)-2*ll; math.Abs(got-want) > 1e-10*math.Abs(want) {
+		t.Errorf("unexpected BIC: got %v want %v", got, want)
+	}
+	if got, want := g.AIC(x, nil), 22-2*ll; math.Abs(got-want) > 1e-10*math.Abs(want) {
+		t.Errorf("unexpected AIC: got %v want %v", got, want)
+	}
+
+	// Fitting recovers the mixture.
+	for _, cov := range []CovarianceType{FullCovariance, DiagonalCovariance, SphericalCovariance} {
+		fit, ok := FitGaussianMixture(x, nil, 2, &GaussianMixtureSettings{Covariance: cov, Src: rand.NewSource(2)})
+		if !ok {
+			t.Errorf("cov=%d: unexpected convergence failure", cov)
+		}
+		// Order the components by their means.
+		first, second := 0, 1
+		if fit.Component(0).Mean(nil)[0] > fit.Component(1).Mean(nil)[0] {
+			first, second = 1, 0
+		}
+		w := fit.Weights(nil)
+		if math.Abs(w[second]-0.75) > 0.02 {
+			t.Errorf("cov=%d: unexpected weights: got %v", cov, w)
+		}
+		if m := fit.Component(first).Mean(nil); !floats.EqualApprox(m, []float64{0, 0}, 0.1) {
+			t.Errorf("cov=%d: unexpected mean of first component: got %v", cov, m)
+		}
+		if m := fit.Component(second).Mean(nil); !floats.EqualApprox(m, []float64{5, 5}, 0.1) {
+			t.Errorf("cov=%d: unexpected mean of second component: got %v", cov, m)
+		}
+		var sigma mat.SymDense
+		fit.Component(first).CovarianceMatrix(&sigma)
+		switch cov {
+		case FullCovariance:
+			want := mat.NewSymDense(2, []float64{1, 0.5, 0.5, 1})
+			if !mat.EqualApprox(&sigma, want, 0.1) {
+				t.Errorf("unexpected full covariance: got %v", mat.Formatted(&sigma))
+			}
+		case DiagonalCovariance:
+			if sigma.At(0, 1) != 0 {
+				t.Errorf("unexpected off-diagonal covariance: got %v", sigma.At(0, 1))
+			}
+			fit.Component(second).CovarianceMatrix(&sigma)
+			want := mat.NewSymDense(2, []float64{0.5, 0, 0, 2})
+			if !mat.EqualApprox(&sigma, want, 0.1) {
+				t.Errorf("unexpected diagonal covariance: got %v", mat.Formatted(&sigma))
+			}
+		case SphericalCovariance:
+			if sigma.At(0, 1) != 0 || sigma.At(0, 0) != sigma.At(1, 1) {
+				t.Errorf("unexpected spherical covariance: got %v", mat.Formatted(&sigma))
+			}
+		}
+		// The full fit has at least the likelihood of the
+		// generating mixture up to sampling error.
+		if cov == FullCovariance {
+			if got := fit.LogLikelihood(x, nil); got < ll-1e-6*math.Abs(ll) {
+				t.Errorf("unexpected log-likelihood of fit: got %v want at least %v", got, ll)
+			}
+		}
+	}
+
+	// BIC selects the generating number of components.
+	best, bestBIC := 0, math.Inf(1)
+	for k := 1; k <= 4; k++ {
+		fit, _ := FitGaussianMixture(x, nil, k, &GaussianMixtureSettings{Src: rand.NewSource(3)})
+		if bic := fit.BIC(x, nil); bic < bestBIC {
+			best, bestBIC = k, bic
+		}
+	}
+	if best != 2 {
+		t.Errorf("unexpected number of components selected by BIC: got %d want 2", best)
+	}
+}
+
+func TestFitGaussianMixtureWeights(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 500
+	x := mat.NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		x.Set(i, 0, rnd.NormFloat64()+float64(4*(i%2)))
+	}
+	// Integer weights are equivalent to repeated observations.
+	w := make([]float64, n)
+	var data []float64
+	for i := range w {
+		w[i] = float64(1 + i%3)
+		for j := 0; j < int(w[i]); j++ {
+			data = append(data, x.At(i, 0))
+		}
+	}
+	rep := mat.NewDense(len(data), 1, data)
+	a, _ := FitGaussianMixture(x, w, 2, &GaussianMixtureSettings{Tolerance: 1e-12, MaxIterations: 1000, Src: rand.NewSource(1)})
+	b, _ := FitGaussianMixture(rep, nil, 2, &GaussianMixtureSettings{Tolerance: 1e-12, MaxIterations: 1000, Src: rand.NewSource(1)})
+	if got, want := a.LogLikelihood(x, w), b.LogLikelihood(rep, nil); math.Abs(got-want) > 1e-6*math.Abs(want) {
+		t.Errorf("unexpected weighted log-likelihood: got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gp provides Gaussian process regression.
+//
+// A Gaussian process is a prior distribution over functions f in which the
+// values of f at any finite set of inputs are jointly normal, with zero mean
+// and covariances given by a kernel k,
+//
+//	cov(f(x), f(x')) = k(x, x').
+//
+// Observing noisy values y = f(x) + ε with independent normal noise ε gives
+// a Gaussian posterior distribution over f, whose mean and variance at new
+// inputs are the predictions of the model. The kernel hyperparameters and
+// the noise variance may be chosen by maximizing the marginal likelihood of
+// the observations.
+//
+// See Rasmussen and Williams, "Gaussian Processes for Machine Learning", MIT
+// Press, 2006 for more details.
+package gp // import "gonum.org/v1/gonum/stat/gp"
@@ -0,0 +1,289 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// ErrNotPositiveDefinite is returned when the covariance matrix of the
+// observations is not positive definite.
+var ErrNotPositiveDefinite = errors.New("gp: covariance matrix not positive definite")
+
+// GP is a Gaussian process regression model conditioned on a set of
+// observations.
+type GP struct {
+	kernel Kernel
+	noise  float64
+
+	x *mat.Dense
+	y *mat.VecDense
+
+	// chol is the Cholesky factorization of
+	// the covariance matrix of the observations,
+	// K + σ_n²*I, and alpha is its solve with y.
+	chol  mat.Cholesky
+	alpha mat.VecDense
+}
+
+// New returns a Gaussian process with the kernel k conditioned on the
+// responses y observed at the inputs in the rows of x with independent
+// normal noise of the given variance. The posterior is computed exactly
+// using the Cholesky factorization of the n×n covariance matrix of the
+// observations in O(n³) time. The kernel must not be modified while the
+// returned Gaussian process is in use.
+//
+// If the covariance matrix is not positive definite, New returns a nil
+// model and ErrNotPositiveDefinite. A small noise variance may be used to
+// improve the conditioning of noise-free observations.
+//
+// New panics if x has no rows, if the length of y is not the number of
+// rows of x, or if noise is negative.
+func New(x mat.Matrix, y []float64, k Kernel, noise float64) (*GP, error) {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic("gp: no observations")
+	}
+	if len(y) != n {
+		panic("gp: mismatched response length")
+	}
+	if noise < 0 {
+		panic("gp: negative noise variance")
+	}
+	yc := make([]float64, n)
+	copy(yc, y)
+	g, ok := condition(mat.DenseCopyOf(x), mat.NewVecDense(n, yc), k, noise)
+	if !ok {
+		return nil, ErrNotPositiveDefinite
+	}
+	return g, nil
+}
+
+// condition returns the Gaussian process with kernel k and the noise
+// variance conditioned on the observations, and whether the covariance
+// matrix of the observations is positive definite.
+func condition(x *mat.Dense, y *mat.VecDense, k Kernel, noise float64) (*GP, bool) {
+	n, _ := x.Dims()
+	cov := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		xi := x.RawRowView(i)
+		for j := i; j < n; j++ {
+			cov.SetSym(i, j, k.Cov(xi, x.RawRowView(j)))
+		}
+		cov.SetSym(i, i, cov.At(i, i)+noise)
+	}
+	g := &GP{kernel: k, noise: noise, x: x, y: y}
+	if !g.chol.Factorize(cov) {
+		return nil, false
+	}
+	// The solution is computed even when the covariance
+	// is ill-conditioned, so the Condition error is not
+	// fatal.
+	_ = g.chol.SolveVecTo(&g.alpha, y)
+	return g, true
+}
+
+// Kernel returns the kernel of the Gaussian process.
+func (g *GP) Kernel() Kernel {
+	return g.kernel
+}
+
+// Noise returns the noise variance of the observations.
+func (g *GP) Noise() float64 {
+	return g.noise
+}
+
+// LogMarginalLikelihood returns the log of the marginal likelihood of the
+// observations,
+//
+//	log p(y) = -1/2 * yᵀ*(K + σ_n²*I)⁻¹*y - 1/2 * log|K + σ_n²*I| - n/2 * log(2π).
+func (g *GP) LogMarginalLikelihood() float64 {
+	n := g.y.Len()
+	return -0.5*mat.Dot(g.y, &g.alpha) - 0.5*g.chol.LogDet() - 0.5*float64(n)*math.Log(2*math.Pi)
+}
+
+// logMarginalLikelihoodGrad stores into dst the gradient of the log of the
+// marginal likelihood with respect to the hyperparameters of the kernel
+// followed by the log of the noise variance,
+//
+//	∂log p(y)/∂θ = 1/2 * tr((α*αᵀ - (K + σ_n²*I)⁻¹) * ∂K/∂θ).
+func (g *GP) logMarginalLikelihoodGrad(dst []float64) {
+	n := g.y.Len()
+	p := g.kernel.NumHyper()
+	var inv mat.SymDense
+	_ = g.chol.InverseTo(&inv)
+	for i := range dst {
+		dst[i] = 0
+	}
+	grad := make([]float64, p)
+	var trace float64
+	for i := 0; i < n; i++ {
+		xi := g.x.RawRowView(i)
+		ai := g.alpha.AtVec(i)
+		for j := i; j < n; j++ {
+			g.kernel.CovGrad(grad, xi, g.x.RawRowView(j))
+			f := ai*g.alpha.AtVec(j) - inv.At(i, j)
+			if i != j {
+				// Count the symmetric element.
+				f *= 2
+			} else {
+				trace += f
+			}
+			floats.AddScaled(dst[:p], 0.5*f, grad)
+		}
+	}
+	dst[p] = 0.5 * g.noise * trace
+}
+
+// Predict returns the mean and variance of the posterior distribution of
+// the function value at the input x. The variance is that of the function
+// value, and the noise variance must be added to obtain the variance of a
+// new observation. Predict panics if the length of x is not the number of
+// columns of the inputs.
+func (g *GP) Predict(x []float64) (mean, variance float64) {
+	n, d := g.x.Dims()
+	if len(x) != d {
+		panic("gp: input length mismatch")
+	}
+	ks := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		ks.SetVec(i, g.kernel.Cov(x, g.x.RawRowView(i)))
+	}
+	mean = mat.Dot(ks, &g.alpha)
+	var v mat.VecDense
+	_ = g.chol.SolveVecTo(&v, ks)
+	variance = math.Max(g.kernel.Cov(x, x)-mat.Dot(ks, &v), 0)
+	return mean, variance
+}
+
+// PredictCov stores the mean of the posterior distribution of the function
+// values at the inputs in the rows of x into mean and their joint covariance
+// matrix into cov. If mean is nil, a new slice is allocated, and the mean is
+// returned. If cov is nil, the covariance is not computed. If the cov matrix
+// is zero-sized it will be resized to the number of rows of x.
+//
+// PredictCov panics if the number of columns of x is not the number of
+// columns of the inputs, if mean is not nil and its length is not the number
+// of rows of x, or if cov is not zero-sized and its size is not the number
+// of rows of x.
+func (g *GP) PredictCov(mean []float64, cov *mat.SymDense, x mat.Matrix) []float64 {
+	n, d := g.x.Dims()
+	m, c := x.Dims()
+	if c != d {
+		panic("gp: input length mismatch")
+	}
+	if mean == nil {
+		mean = make([]float64, m)
+	}
+	if len(mean) != m {
+		panic("gp: slice length mismatch")
+	}
+	xd := mat.DenseCopyOf(x)
+	ks := mat.NewDense(n, m, nil)
+	for i := 0; i < n; i++ {
+		xi := g.x.RawRowView(i)
+		for j := 0; j < m; j++ {
+			ks.Set(i, j, g.kernel.Cov(xd.RawRowView(j), xi))
+		}
+	}
+	mv := mat.NewVecDense(m, mean)
+	mv.MulVec(ks.T(), &g.alpha)
+	if cov == nil {
+		return mean
+	}
+	if cov.IsZero() {
+		*cov = *(cov.GrowSym(m).(*mat.SymDense))
+	} else if cov.Symmetric() != m {
+		panic("gp: input matrix size mismatch")
+	}
+	var v, kv mat.Dense
+	_ = g.chol.SolveTo(&v, ks)
+	kv.Mul(ks.T(), &v)
+	for i := 0; i < m; i++ {
+		xi := xd.RawRowView(i)
+		for j := i; j < m; j++ {
+			cov.SetSym(i, j, g.kernel.Cov(xi, xd.RawRowView(j))-kv.At(i, j))
+		}
+	}
+	return mean
+}
+
+// Optimize returns a Gaussian process conditioned on the observations as for
+// New, with the hyperparameters of the kernel k and the noise variance chosen
+// to maximize the log of the marginal likelihood of the observations using
+// the analytic gradient. The optimization starts from the current
+// hyperparameters of k and the given noise variance, and k is left holding
+// the optimal hyperparameters. The settings and method are passed to
+// optimize.Minimize, and if method is nil the default method for problems
+// with gradients is used.
+//
+// The marginal likelihood may have several local maxima, which correspond to
+// different explanations of the observations, so the starting point should
+// be chosen with care. If the optimization returns an error and a location,
+// Optimize returns the Gaussian process at that location and the error.
+//
+// Optimize panics if x has no rows, if the length of y is not the number of
+// rows of x, or if noise is not positive.
+func Optimize(x mat.Matrix, y []float64, k Kernel, noise float64, settings *optimize.Settings, method optimize.Method) (*GP, error) {
+	n, d := x.Dims()
+	if n == 0 || d == 0 {
+		panic("gp: no observations")
+	}
+	if len(y) != n {
+		panic("gp: mismatched response length")
+	}
+	if noise <= 0 {
+		panic("gp: non-positive noise variance")
+	}
+	xd := mat.DenseCopyOf(x)
+	yc := make([]float64, n)
+	copy(yc, y)
+	yv := mat.NewVecDense(n, yc)
+
+	p := k.NumHyper()
+	at := func(h []float64) (*GP, bool) {
+		k.SetHyper(h[:p])
+		return condition(xd, yv, k, math.Exp(h[p]))
+	}
+	problem := optimize.Problem{
+		Func: func(h []float64) float64 {
+			g, ok := at(h)
+			if !ok {
+				return math.Inf(1)
+			}
+			return -g.LogMarginalLikelihood()
+		},
+		Grad: func(grad, h []float64) {
+			g, ok := at(h)
+			if !ok {
+				for i := range grad {
+					grad[i] = math.NaN()
+				}
+				return
+			}
+			g.logMarginalLikelihoodGrad(grad)
+			floats.Scale(-1, grad)
+		},
+	}
+	x0 := make([]float64, p+1)
+	k.Hyper(x0[:p])
+	x0[p] = math.Log(noise)
+
+	res, err := optimize.Minimize(problem, x0, settings, method)
+	if res == nil {
+		k.SetHyper(x0[:p])
+		return nil, err
+	}
+	g, ok := at(res.X)
+	if !ok {
+		return nil, ErrNotPositiveDefinite
+	}
+	return g, err
+}
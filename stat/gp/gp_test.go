@@ -0,0 +1,252 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// noisySin returns n observations of sin(x) on [0, 2π] with
+// normal noise of the given standard deviation.
+func noisySin(n int, std float64, rnd *rand.Rand) (*mat.Dense, []float64) {
+	x := mat.NewDense(n, 1, nil)
+	y := make([]float64, n)
+	for i := range y {
+		v := 2 * math.Pi * rnd.Float64()
+		x.Set(i, 0, v)
+		y[i] = math.Sin(v) + std*rnd.NormFloat64()
+	}
+	return x, y
+}
+
+func TestGPInterpolation(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, y := noisySin(20, 0, rnd)
+	for i, k := range testKernels() {
+		if _, ok := k.(*Periodic); ok {
+			// The periodic kernel cannot interpolate
+			// non-periodic observations.
+			continue
+		}
+		g, err := New(x, y, k, 1e-10)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		for j, v := range y {
+			mean, variance := g.Predict(x.RawRowView(j))
+			if math.Abs(mean-v) > 1e-5 {
+				t.Errorf("test %d: mean does not interpolate observation %d: got %v want %v", i, j, mean, v)
+			}
+			if variance > 1e-5 {
+				t.Errorf("test %d: non-zero variance at observation %d: %v", i, j, variance)
+			}
+		}
+	}
+
+	// Far from the observations the posterior
+	// returns to the prior.
+	k := &RBF{Variance: 2, LengthScale: 0.5}
+	g, err := New(x, y, k, 0.01)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mean, variance := g.Predict([]float64{100})
+	if math.Abs(mean) > 1e-12 || math.Abs(variance-k.Variance) > 1e-12 {
+		t.Errorf("unexpected prediction far from observations: got (%v, %v) want (0, %v)", mean, variance, k.Variance)
+	}
+}
+
+func TestGPDuplicate(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(3, 1, []float64{0, 1, 1})
+	y := []float64{0, 1, 2}
+	_, err := New(x, y, &RBF{Variance: 1, LengthScale: 1}, 0)
+	if err != ErrNotPositiveDefinite {
+		t.Errorf("unexpected error for duplicate inputs without noise: got %v want %v", err, ErrNotPositiveDefinite)
+	}
+	g, err := New(x, y, &RBF{Variance: 1, LengthScale: 1}, 0.1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The noisy observations at the duplicated
+	// input are averaged.
+	if mean, _ := g.Predict([]float64{1}); mean < 1 || mean > 2 {
+		t.Errorf("unexpected mean at duplicated input: %v", mean)
+	}
+}
+
+func TestGPPredictCov(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, y := noisySin(15, 0.1, rnd)
+	for i, k := range testKernels() {
+		g, err := New(x, y, k, 0.01)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		xs := mat.NewDense(8, 1, nil)
+		for j := 0; j < 8; j++ {
+			xs.Set(j, 0, -1+float64(j))
+		}
+		var cov mat.SymDense
+		mean := g.PredictCov(nil, &cov, xs)
+		for j := range mean {
+			m, v := g.Predict(xs.RawRowView(j))
+			if math.Abs(mean[j]-m) > 1e-10 {
+				t.Errorf("test %d: mean mismatch at %d: got %v want %v", i, j, mean[j], m)
+			}
+			if math.Abs(cov.At(j, j)-v) > 1e-10 {
+				t.Errorf("test %d: variance mismatch at %d: got %v want %v", i, j, cov.At(j, j), v)
+			}
+		}
+		got := g.PredictCov(make([]float64, 8), nil, xs)
+		if !floats.Equal(got, mean) {
+			t.Errorf("test %d: mean mismatch without covariance", i)
+		}
+	}
+}
+
+func TestGPLogMarginalLikelihood(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x, y := noisySin(12, 0.2, rnd)
+	n := len(y)
+	for i, k := range testKernels() {
+		const noise = 0.05
+		g, err := New(x, y, k, noise)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+
+		// Compare with the density of the multivariate normal
+		// distribution of the observations.
+		cov := mat.NewSymDense(n, nil)
+		for r := 0; r < n; r++ {
+			for c := r; c < n; c++ {
+				cov.SetSym(r, c, k.Cov(x.RawRowView(r), x.RawRowView(c)))
+			}
+			cov.SetSym(r, r, cov.At(r, r)+noise)
+		}
+		dist, ok := distmv.NewNormal(make([]float64, n), cov, nil)
+		if !ok {
+			t.Fatalf("test %d: covariance not positive definite", i)
+		}
+		got := g.LogMarginalLikelihood()
+		want := dist.LogProb(y)
+		if math.Abs(got-want) > 1e-8*math.Abs(want) {
+			t.Errorf("test %d: log marginal likelihood mismatch: got %v want %v", i, got, want)
+		}
+
+		// Compare the gradient with finite differences.
+		p := k.NumHyper()
+		h := make([]float64, p+1)
+		k.Hyper(h[:p])
+		h[p] = math.Log(noise)
+		grad := make([]float64, p+1)
+		g.logMarginalLikelihoodGrad(grad)
+		lml := func(h []float64) float64 {
+			k.SetHyper(h[:p])
+			g, err := New(x, y, k, math.Exp(h[p]))
+			if err != nil {
+				t.Fatalf("test %d: unexpected error: %v", i, err)
+			}
+			return g.LogMarginalLikelihood()
+		}
+		want2 := make([]float64, p+1)
+		const step = 1e-6
+		for j := range h {
+			hj := h[j]
+			h[j] = hj + step
+			f1 := lml(h)
+			h[j] = hj - step
+			f0 := lml(h)
+			h[j] = hj
+			want2[j] = (f1 - f0) / (2 * step)
+		}
+		k.SetHyper(h[:p])
+		if !floats.EqualApprox(grad, want2, 1e-5) {
+			t.Errorf("test %d: gradient mismatch: got %v want %v", i, grad, want2)
+		}
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const std = 0.1
+	x, y := noisySin(60, std, rnd)
+
+	k := &RBF{Variance: 0.2, LengthScale: 3}
+	const noise = 0.5
+	start, err := New(x, y, &RBF{Variance: k.Variance, LengthScale: k.LengthScale}, noise)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The default gradient threshold is too small
+	// for the precision of the log marginal likelihood.
+	settings := &optimize.Settings{GradientThreshold: 1e-5}
+	g, err := Optimize(x, y, k, noise, settings, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g.Kernel() != Kernel(k) {
+		t.Errorf("kernel not retained")
+	}
+	if g.LogMarginalLikelihood() <= start.LogMarginalLikelihood() {
+		t.Errorf("log marginal likelihood not increased: got %v start %v", g.LogMarginalLikelihood(), start.LogMarginalLikelihood())
+	}
+	if math.Abs(math.Sqrt(g.Noise())-std) > 0.3*std {
+		t.Errorf("noise not recovered: got %v want %v", math.Sqrt(g.Noise()), std)
+	}
+
+	// The gradient vanishes at the optimum.
+	grad := make([]float64, k.NumHyper()+1)
+	g.logMarginalLikelihoodGrad(grad)
+	if floats.Norm(grad, math.Inf(1)) > 1e-4 {
+		t.Errorf("non-zero gradient at optimum: %v", grad)
+	}
+
+	for _, v := range []float64{0.5, 2, 4} {
+		mean, variance := g.Predict([]float64{v})
+		if math.Abs(mean-math.Sin(v)) > 3*math.Sqrt(variance)+0.05 {
+			t.Errorf("unexpected prediction at %v: got %v±%v want %v", v, mean, math.Sqrt(variance), math.Sin(v))
+		}
+	}
+}
+
+func TestGPPanics(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(3, 1, []float64{0, 1, 2})
+	y := []float64{0, 1, 2}
+	k := &RBF{Variance: 1, LengthScale: 1}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "response length", fn: func() { New(x, y[:2], k, 0.1) }},
+		{name: "negative noise", fn: func() { New(x, y, k, -1) }},
+		{name: "zero noise", fn: func() { Optimize(x, y, k, 0, nil, nil) }},
+		{name: "input length", fn: func() {
+			g, _ := New(x, y, k, 0.1)
+			g.Predict([]float64{1, 2})
+		}},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import "math"
+
+// Kernel is a covariance function of a Gaussian process with adjustable
+// hyperparameters. The hyperparameters are represented by the logs of the
+// positive parameters of the kernel, so that they may be optimized without
+// constraints.
+type Kernel interface {
+	// Cov returns the covariance k(x, y) of the
+	// function values at the inputs x and y.
+	Cov(x, y []float64) float64
+
+	// CovGrad stores the gradient of k(x, y) with
+	// respect to the hyperparameters into dst and
+	// returns k(x, y). The length of dst must be
+	// the number of hyperparameters.
+	CovGrad(dst, x, y []float64) float64
+
+	// NumHyper returns the number of
+	// hyperparameters of the kernel.
+	NumHyper() int
+
+	// Hyper stores the hyperparameters into dst,
+	// which must have length NumHyper.
+	Hyper(dst []float64)
+
+	// SetHyper sets the hyperparameters from src,
+	// which must have length NumHyper.
+	SetHyper(src []float64)
+}
+
+var (
+	_ Kernel = (*RBF)(nil)
+	_ Kernel = (*Matern)(nil)
+	_ Kernel = (*Periodic)(nil)
+	_ Kernel = (*Sum)(nil)
+	_ Kernel = (*Product)(nil)
+)
+
+// RBF is the squared exponential, or radial basis function, kernel
+//
+//	k(x, y) = σ² * exp(-|x-y|²/(2ℓ²)),
+//
+// with variance σ² and length scale ℓ, whose functions are infinitely
+// differentiable. Its hyperparameters are log(σ²) and log(ℓ).
+type RBF struct {
+	Variance    float64
+	LengthScale float64
+}
+
+// Cov returns k(x, y).
+func (k *RBF) Cov(x, y []float64) float64 {
+	return k.Variance * math.Exp(-sqDist(x, y)/(2*k.LengthScale*k.LengthScale))
+}
+
+// CovGrad stores the gradient of k(x, y) with respect to the
+// hyperparameters into dst and returns k(x, y).
+func (k *RBF) CovGrad(dst, x, y []float64) float64 {
+	checkHyper(dst, 2)
+	r2 := sqDist(x, y) / (k.LengthScale * k.LengthScale)
+	c := k.Variance * math.Exp(-r2/2)
+	dst[0] = c
+	dst[1] = c * r2
+	return c
+}
+
+// NumHyper returns 2.
+func (*RBF) NumHyper() int { return 2 }
+
+// Hyper stores log(σ²) and log(ℓ) into dst.
+func (k *RBF) Hyper(dst []float64) {
+	checkHyper(dst, 2)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+}
+
+// SetHyper sets σ² and ℓ from their logs in src.
+func (k *RBF) SetHyper(src []float64) {
+	checkHyper(src, 2)
+	k.Variance = math.Exp(src[0])
+	k.LengthScale = math.Exp(src[1])
+}
+
+// Matern is the Matérn kernel with smoothness ν of 1/2, 3/2 or 5/2,
+//
+//	ν = 1/2: k(x, y) = σ² * exp(-r),
+//	ν = 3/2: k(x, y) = σ² * (1 + √3 r) * exp(-√3 r),
+//	ν = 5/2: k(x, y) = σ² * (1 + √5 r + 5r²/3) * exp(-√5 r),
+//
+// where r = |x-y|/ℓ, with variance σ² and length scale ℓ. Its functions
+// are ⌈ν⌉-1 times differentiable, and ν = 1/2 gives the exponential
+// kernel. The hyperparameters are log(σ²) and log(ℓ). The smoothness is
+// not a hyperparameter, and the methods of Matern panic if Nu is not one
+// of the supported values.
+type Matern struct {
+	Nu          float64
+	Variance    float64
+	LengthScale float64
+}
+
+// Cov returns k(x, y).
+func (k *Matern) Cov(x, y []float64) float64 {
+	c, _ := k.cov(x, y)
+	return c
+}
+
+// CovGrad stores the gradient of k(x, y) with respect to the
+// hyperparameters into dst and returns k(x, y).
+func (k *Matern) CovGrad(dst, x, y []float64) float64 {
+	checkHyper(dst, 2)
+	c, dl := k.cov(x, y)
+	dst[0] = c
+	dst[1] = dl
+	return c
+}
+
+// cov returns k(x, y) and its derivative with respect to log(ℓ).
+func (k *Matern) cov(x, y []float64) (c, dl float64) {
+	r := math.Sqrt(sqDist(x, y)) / k.LengthScale
+	switch k.Nu {
+	case 0.5:
+		e := k.Variance * math.Exp(-r)
+		return e, e * r
+	case 1.5:
+		a := math.Sqrt(3) * r
+		e := k.Variance * math.Exp(-a)
+		return e * (1 + a), e * a * a
+	case 2.5:
+		a := math.Sqrt(5) * r
+		e := k.Variance * math.Exp(-a)
+		return e * (1 + a + a*a/3), e * a * a * (1 + a) / 3
+	default:
+		panic("gp: unsupported Matérn smoothness")
+	}
+}
+
+// NumHyper returns 2.
+func (*Matern) NumHyper() int { return 2 }
+
+// Hyper stores log(σ²) and log(ℓ) into dst.
+func (k *Matern) Hyper(dst []float64) {
+	checkHyper(dst, 2)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+}
+
+// SetHyper sets σ² and ℓ from their logs in src.
+func (k *Matern) SetHyper(src []float64) {
+	checkHyper(src, 2)
+	k.Variance = math.Exp(src[0])
+	k.LengthScale = math.Exp(src[1])
+}
+
+// Periodic is the periodic kernel
+//
+//	k(x, y) = σ² * exp(-2 sin²(π|x-y|/p)/ℓ²),
+//
+// with variance σ², length scale ℓ and period p, whose functions repeat
+// with period p. Its hyperparameters are log(σ²), log(ℓ) and log(p).
+type Periodic struct {
+	Variance    float64
+	LengthScale float64
+	Period      float64
+}
+
+// Cov returns k(x, y).
+func (k *Periodic) Cov(x, y []float64) float64 {
+	s := math.Sin(math.Pi * math.Sqrt(sqDist(x, y)) / k.Period)
+	return k.Variance * math.Exp(-2*s*s/(k.LengthScale*k.LengthScale))
+}
+
+// CovGrad stores the gradient of k(x, y) with respect to the
+// hyperparameters into dst and returns k(x, y).
+func (k *Periodic) CovGrad(dst, x, y []float64) float64 {
+	checkHyper(dst, 3)
+	l2 := k.LengthScale * k.LengthScale
+	u := math.Pi * math.Sqrt(sqDist(x, y)) / k.Period
+	s := math.Sin(u)
+	c := k.Variance * math.Exp(-2*s*s/l2)
+	dst[0] = c
+	dst[1] = c * 4 * s * s / l2
+	dst[2] = c * 2 * u * math.Sin(2*u) / l2
+	return c
+}
+
+// NumHyper returns 3.
+func (*Periodic) NumHyper() int { return 3 }
+
+// Hyper stores log(σ²), log(ℓ) and log(p) into dst.
+func (k *Periodic) Hyper(dst []float64) {
+	checkHyper(dst, 3)
+	dst[0] = math.Log(k.Variance)
+	dst[1] = math.Log(k.LengthScale)
+	dst[2] = math.Log(k.Period)
+}
+
+// SetHyper sets σ², ℓ and p from their logs in src.
+func (k *Periodic) SetHyper(src []float64) {
+	checkHyper(src, 3)
+	k.Variance = math.Exp(src[0])
+	k.LengthScale = math.Exp(src[1])
+	k.Period = math.Exp(src[2])
+}
+
+// Sum is the sum of two kernels,
+//
+//	k(x, y) = A(x, y) + B(x, y),
+//
+// modeling the sum of independent functions. Its hyperparameters are
+// those of A followed by those of B.
+type Sum struct {
+	A, B Kernel
+}
+
+// Cov returns k(x, y).
+func (k *Sum) Cov(x, y []float64) float64 {
+	return k.A.Cov(x, y) + k.B.Cov(x, y)
+}
+
+// CovGrad stores the gradient of k(x, y) with respect to the
+// hyperparameters into dst and returns k(x, y).
+func (k *Sum) CovGrad(dst, x, y []float64) float64 {
+	na := k.A.NumHyper()
+	checkHyper(dst, na+k.B.NumHyper())
+	return k.A.CovGrad(dst[:na], x, y) + k.B.CovGrad(dst[na:], x, y)
+}
+
+// NumHyper returns the total number of hyperparameters of A and B.
+func (k *Sum) NumHyper() int { return k.A.NumHyper() + k.B.NumHyper() }
+
+// Hyper stores the hyperparameters of A and B into dst.
+func (k *Sum) Hyper(dst []float64) {
+	na := k.A.NumHyper()
+	checkHyper(dst, na+k.B.NumHyper())
+	k.A.Hyper(dst[:na])
+	k.B.Hyper(dst[na:])
+}
+
+// SetHyper sets the hyperparameters of A and B from src.
+func (k *Sum) SetHyper(src []float64) {
+	na := k.A.NumHyper()
+	checkHyper(src, na+k.B.NumHyper())
+	k.A.SetHyper(src[:na])
+	k.B.SetHyper(src[na:])
+}
+
+// Product is the product of two kernels,
+//
+//	k(x, y) = A(x, y) * B(x, y),
+//
+// modeling, for example, a periodic function whose shape changes slowly.
+// Its hyperparameters are those of A followed by those of B.
+type Product struct {
+	A, B Kernel
+}
+
+// Cov returns k(x, y).
+func (k *Product) Cov(x, y []float64) float64 {
+	return k.A.Cov(x, y) * k.B.Cov(x, y)
+}
+
+// CovGrad stores the gradient of k(x, y) with respect to the
+// hyperparameters into dst and returns k(x, y).
+func (k *Product) CovGrad(dst, x, y []float64) float64 {
+	na := k.A.NumHyper()
+	checkHyper(dst, na+k.B.NumHyper())
+	a := k.A.CovGrad(dst[:na], x, y)
+	b := k.B.CovGrad(dst[na:], x, y)
+	for i := range dst[:na] {
+		dst[i] *= b
+	}
+	for i := range dst[na:] {
+		dst[na+i] *= a
+	}
+	return a * b
+}
+
+// NumHyper returns the total number of hyperparameters of A and B.
+func (k *Product) NumHyper() int { return k.A.NumHyper() + k.B.NumHyper() }
+
+// Hyper stores the hyperparameters of A and B into dst.
+func (k *Product) Hyper(dst []float64) {
+	na := k.A.NumHyper()
+	checkHyper(dst, na+k.B.NumHyper())
+	k.A.Hyper(dst[:na])
+	k.B.Hyper(dst[na:])
+}
+
+// SetHyper sets the hyperparameters of A and B from src.
+func (k *Product) SetHyper(src []float64) {
+	na := k.A.NumHyper()
+	checkHyper(src, na+k.B.NumHyper())
+	k.A.SetHyper(src[:na])
+	k.B.SetHyper(src[na:])
+}
+
+// checkHyper panics if the length of h is not n.
+func checkHyper(h []float64, n int) {
+	if len(h) != n {
+		panic("gp: hyperparameter length mismatch")
+	}
+}
+
+// sqDist returns the squared Euclidean distance between x and y.
+func sqDist(x, y []float64) float64 {
+	if len(x) != len(y) {
+		panic("gp: input length mismatch")
+	}
+	var d float64
+	for i, v := range x {
+		d += (v - y[i]) * (v - y[i])
+	}
+	return d
+}
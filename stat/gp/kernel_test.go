@@ -0,0 +1,120 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func testKernels() []Kernel {
+	return []Kernel{
+		&RBF{Variance: 1.3, LengthScale: 0.7},
+		&Matern{Nu: 0.5, Variance: 0.8, LengthScale: 1.2},
+		&Matern{Nu: 1.5, Variance: 2, LengthScale: 0.5},
+		&Matern{Nu: 2.5, Variance: 1.1, LengthScale: 0.9},
+		&Periodic{Variance: 1.5, LengthScale: 0.8, Period: 1.7},
+		&Sum{
+			A: &RBF{Variance: 1, LengthScale: 2},
+			B: &Periodic{Variance: 0.5, LengthScale: 1, Period: 0.9},
+		},
+		&Product{
+			A: &Matern{Nu: 2.5, Variance: 1.4, LengthScale: 3},
+			B: &Periodic{Variance: 0.7, LengthScale: 1.3, Period: 1.1},
+		},
+	}
+}
+
+func TestKernel(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i, k := range testKernels() {
+		p := k.NumHyper()
+		h := make([]float64, p)
+		k.Hyper(h)
+		got := make([]float64, p)
+		k.SetHyper(h)
+		k.Hyper(got)
+		if !floats.EqualApprox(got, h, 1e-14) {
+			t.Errorf("test %d: hyperparameters not preserved: got %v want %v", i, got, h)
+		}
+
+		grad := make([]float64, p)
+		want := make([]float64, p)
+		for trial := 0; trial < 10; trial++ {
+			x := []float64{rnd.NormFloat64(), rnd.NormFloat64()}
+			y := []float64{rnd.NormFloat64(), rnd.NormFloat64()}
+			if k.Cov(x, y) != k.Cov(y, x) {
+				t.Errorf("test %d: covariance not symmetric", i)
+			}
+			c := k.CovGrad(grad, x, y)
+			if c != k.Cov(x, y) {
+				t.Errorf("test %d: mismatched covariance from CovGrad: got %v want %v", i, c, k.Cov(x, y))
+			}
+			const step = 1e-6
+			for j := range h {
+				hj := h[j]
+				h[j] = hj + step
+				k.SetHyper(h)
+				f1 := k.Cov(x, y)
+				h[j] = hj - step
+				k.SetHyper(h)
+				f0 := k.Cov(x, y)
+				h[j] = hj
+				k.SetHyper(h)
+				want[j] = (f1 - f0) / (2 * step)
+			}
+			if !floats.EqualApprox(grad, want, 1e-7) {
+				t.Errorf("test %d: gradient mismatch: got %v want %v", i, grad, want)
+			}
+		}
+	}
+}
+
+func TestKernelPeriodic(t *testing.T) {
+	t.Parallel()
+	k := &Periodic{Variance: 1.2, LengthScale: 0.6, Period: 2.5}
+	for _, d := range []float64{0.3, 1, 2} {
+		got := k.Cov([]float64{0}, []float64{d + 2*k.Period})
+		want := k.Cov([]float64{0}, []float64{d})
+		if math.Abs(got-want) > 1e-12 {
+			t.Errorf("covariance not periodic at %v: got %v want %v", d, got, want)
+		}
+	}
+	if got := k.Cov([]float64{1}, []float64{1}); got != k.Variance {
+		t.Errorf("unexpected variance: got %v want %v", got, k.Variance)
+	}
+}
+
+func TestKernelMaternLimit(t *testing.T) {
+	t.Parallel()
+	// The Matérn kernel with ν = 1/2 is the exponential kernel.
+	k := &Matern{Nu: 0.5, Variance: 2, LengthScale: 1.5}
+	for _, d := range []float64{0, 0.5, 3} {
+		got := k.Cov([]float64{0}, []float64{d})
+		want := 2 * math.Exp(-d/1.5)
+		if math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected covariance at %v: got %v want %v", d, got, want)
+		}
+	}
+	for _, nu := range []float64{0, 1, 3.5} {
+		k := &Matern{Nu: nu, Variance: 1, LengthScale: 1}
+		if !panics(func() { k.Cov([]float64{0}, []float64{1}) }) {
+			t.Errorf("expected panic for unsupported smoothness %v", nu)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
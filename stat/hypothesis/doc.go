@@ -0,0 +1,10 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hypothesis provides statistical hypothesis tests and the
+// adjustment of p-values for multiple testing.
+//
+// See Hollander, Wolfe and Chicken, "Nonparametric Statistical Methods",
+// Wiley, 2013 for more details of the rank tests.
+package hypothesis // import "gonum.org/v1/gonum/stat/hypothesis"
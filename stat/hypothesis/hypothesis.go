@@ -0,0 +1,125 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/resample"
+)
+
+// Method specifies how the p-value of a test is computed.
+type Method int
+
+const (
+	// Auto uses the exact distribution of the
+	// statistic when it is available and cheap to
+	// compute, and the asymptotic distribution
+	// otherwise. See the documentation of each test
+	// for the conditions.
+	Auto Method = iota
+	// Exact uses the exact distribution of the
+	// statistic under the null hypothesis.
+	Exact
+	// Asymptotic uses the large sample approximation
+	// of the distribution of the statistic under the
+	// null hypothesis.
+	Asymptotic
+)
+
+// Result holds the result of a hypothesis test.
+type Result struct {
+	// Statistic is the value of the test statistic.
+	Statistic float64
+
+	// P is the p-value of the test.
+	P float64
+
+	// Exact is whether P was computed from the exact
+	// distribution of the statistic.
+	Exact bool
+}
+
+// rank stores the ranks of the elements of x into dst, assigning the mean
+// rank to tied values, and returns the sum of t³-t over the groups of t tied
+// values.
+func rank(dst, x []float64) (ties float64) {
+	if len(dst) != len(x) {
+		panic("hypothesis: slice length mismatch")
+	}
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return x[idx[i]] < x[idx[j]] })
+	for i := 0; i < len(idx); {
+		j := i + 1
+		for j < len(idx) && x[idx[j]] == x[idx[i]] {
+			j++
+		}
+		// Elements i to j-1 in sorted order have
+		// ranks i+1 to j.
+		r := float64(i+j+1) / 2
+		for _, k := range idx[i:j] {
+			dst[k] = r
+		}
+		if t := float64(j - i); t > 1 {
+			ties += t*t*t - t
+		}
+		i = j
+	}
+	return ties
+}
+
+// normalP returns the p-value for the statistic s with the given mean and
+// standard deviation under the null hypothesis using the normal
+// approximation with a continuity correction of 1/2.
+func normalP(s, mean, sd float64, alt resample.Alternative) float64 {
+	if sd == 0 {
+		return math.NaN()
+	}
+	d := s - mean
+	switch alt {
+	case resample.TwoSided:
+		var c float64
+		switch {
+		case d > 0:
+			c = 0.5
+		case d < 0:
+			c = -0.5
+		}
+		z := (d - c) / sd
+		return math.Min(1, 2*distuv.UnitNormal.CDF(-math.Abs(z)))
+	case resample.Less:
+		return distuv.UnitNormal.CDF((d + 0.5) / sd)
+	case resample.Greater:
+		return distuv.UnitNormal.Survival((d - 0.5) / sd)
+	default:
+		panic(badAlternative)
+	}
+}
+
+// exactP returns the p-value for the statistic s given the lower and upper
+// tail probabilities P(S ≤ s) and P(S ≥ s) under the null hypothesis.
+func exactP(lower, upper float64, alt resample.Alternative) float64 {
+	switch alt {
+	case resample.TwoSided:
+		return math.Min(1, 2*math.Min(lower, upper))
+	case resample.Less:
+		return lower
+	case resample.Greater:
+		return upper
+	default:
+		panic(badAlternative)
+	}
+}
+
+const (
+	badAlternative = "hypothesis: bad alternative"
+	badMethod      = "hypothesis: bad method"
+	exactTies      = "hypothesis: exact p-value with ties"
+)
@@ -0,0 +1,34 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		x     []float64
+		ranks []float64
+		ties  float64
+	}{
+		{x: []float64{}, ranks: []float64{}, ties: 0},
+		{x: []float64{3, 1, 2}, ranks: []float64{3, 1, 2}, ties: 0},
+		{x: []float64{2, 1, 2, 3, 1, 2}, ranks: []float64{4, 1.5, 4, 6, 1.5, 4}, ties: 24 + 6},
+		{x: []float64{5, 5, 5, 5}, ranks: []float64{2.5, 2.5, 2.5, 2.5}, ties: 60},
+	} {
+		got := make([]float64, len(test.x))
+		ties := rank(got, test.x)
+		if !floats.Equal(got, test.ranks) {
+			t.Errorf("unexpected ranks of %v: got %v want %v", test.x, got, test.ranks)
+		}
+		if ties != test.ties {
+			t.Errorf("unexpected tie sum for %v: got %v want %v", test.x, ties, test.ties)
+		}
+	}
+}
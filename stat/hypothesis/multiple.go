@@ -0,0 +1,178 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+)
+
+// Adjustment specifies a method of adjusting p-values for multiple testing.
+type Adjustment int
+
+const (
+	// Bonferroni controls the family-wise error rate
+	// by multiplying the p-values by the number of
+	// tests.
+	Bonferroni Adjustment = iota
+	// Holm controls the family-wise error rate with
+	// the step-down method of Holm, which is uniformly
+	// more powerful than Bonferroni.
+	Holm
+	// BenjaminiHochberg controls the false discovery
+	// rate for independent or positively dependent
+	// tests with the step-up method of Benjamini and
+	// Hochberg.
+	BenjaminiHochberg
+	// BenjaminiYekutieli controls the false discovery
+	// rate under arbitrary dependence with the method
+	// of Benjamini and Yekutieli.
+	BenjaminiYekutieli
+)
+
+// Adjust stores the p-values p adjusted for multiple testing with the given
+// method into dst and returns it. If dst is nil, a new slice is allocated.
+// dst may be p. Adjusted p-values can be compared directly with the
+// significance level.
+//
+// NaN p-values are ignored and are NaN in dst. The number of tests is the
+// number of p-values that are not NaN.
+//
+// Adjust panics if dst is not nil and its length differs from p, if any
+// p-value is outside [0, 1] or if method is unknown.
+func Adjust(dst, p []float64, method Adjustment) []float64 {
+	idx, dst := sortedPValues(dst, p)
+	m := float64(len(idx))
+	adj := make([]float64, len(idx))
+	switch method {
+	case Bonferroni:
+		for i, j := range idx {
+			adj[i] = math.Min(1, m*p[j])
+		}
+	case Holm:
+		var cur float64
+		for i, j := range idx {
+			cur = math.Max(cur, math.Min(1, (m-float64(i))*p[j]))
+			adj[i] = cur
+		}
+	case BenjaminiHochberg:
+		stepUp(adj, p, idx, 1)
+	case BenjaminiYekutieli:
+		var c float64
+		for k := len(idx); k >= 1; k-- {
+			c += 1 / float64(k)
+		}
+		stepUp(adj, p, idx, c)
+	default:
+		panic("hypothesis: bad adjustment")
+	}
+	for i, j := range idx {
+		dst[j] = adj[i]
+	}
+	return dst
+}
+
+// Pi0 returns Storey's estimate of the proportion of true null hypotheses
+// among the tests with p-values p,
+//
+//	π̂₀(λ) = min(1, #{p_i > λ} / (m(1-λ))),
+//
+// where m is the number of tests. NaN p-values are ignored. If all p-values
+// are NaN, Pi0 returns NaN.
+//
+// Pi0 panics if lambda is not in [0, 1) or if any p-value is outside [0, 1].
+func Pi0(p []float64, lambda float64) float64 {
+	if !(0 <= lambda && lambda < 1) {
+		panic("hypothesis: invalid lambda")
+	}
+	var m, above float64
+	for _, v := range p {
+		if math.IsNaN(v) {
+			continue
+		}
+		if v < 0 || 1 < v {
+			panic(badPValue)
+		}
+		m++
+		if v > lambda {
+			above++
+		}
+	}
+	if m == 0 {
+		return math.NaN()
+	}
+	return math.Min(1, above/(m*(1-lambda)))
+}
+
+// QValues stores the q-values of the tests with p-values p into dst and
+// returns it, where pi0 is the proportion of true null hypotheses, for
+// example as estimated by Pi0. If dst is nil, a new slice is allocated. dst
+// may be p. The q-value of a test is the smallest false discovery rate at
+// which the test is significant, see Storey and Tibshirani, "Statistical
+// significance for genomewide studies", Proceedings of the National Academy
+// of Sciences 100(16), 2003. With pi0 equal to one, the q-values are the
+// BenjaminiHochberg adjusted p-values.
+//
+// NaN p-values are ignored and are NaN in dst.
+//
+// QValues panics if dst is not nil and its length differs from p, if any
+// p-value is outside [0, 1] or if pi0 is not in (0, 1].
+func QValues(dst, p []float64, pi0 float64) []float64 {
+	if !(0 < pi0 && pi0 <= 1) {
+		panic("hypothesis: invalid pi0")
+	}
+	idx, dst := sortedPValues(dst, p)
+	q := make([]float64, len(idx))
+	stepUp(q, p, idx, pi0)
+	for i, j := range idx {
+		dst[j] = q[i]
+	}
+	return dst
+}
+
+// stepUp stores the step-up adjusted p-values
+//
+//	min_{j ≥ i} min(1, c m p_(j) / j)
+//
+// of the p-values p in the order given by idx into dst.
+func stepUp(dst, p []float64, idx []int, c float64) {
+	m := float64(len(idx))
+	cur := 1.0
+	for i := len(idx) - 1; i >= 0; i-- {
+		cur = math.Min(cur, c*m*p[idx[i]]/float64(i+1))
+		dst[i] = cur
+	}
+}
+
+// sortedPValues returns the indices of the p-values in p that are not NaN in
+// increasing order of p-value, and dst with NaN stored at the indices of NaN
+// p-values, allocating dst if it is nil.
+func sortedPValues(dst, p []float64) ([]int, []float64) {
+	if dst == nil {
+		dst = make([]float64, len(p))
+	}
+	if len(dst) != len(p) {
+		panic("hypothesis: slice length mismatch")
+	}
+	idx := make([]int, 0, len(p))
+	for i, v := range p {
+		if math.IsNaN(v) {
+			continue
+		}
+		if v < 0 || 1 < v {
+			panic(badPValue)
+		}
+		idx = append(idx, i)
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return p[idx[i]] < p[idx[j]] })
+	for i, v := range p {
+		if math.IsNaN(v) {
+			dst[i] = math.NaN()
+		}
+	}
+	return idx, dst
+}
+
+const badPValue = "hypothesis: p-value out of range"
@@ -0,0 +1,172 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestAdjust(t *testing.T) {
+	t.Parallel()
+	nan := math.NaN()
+	// In increasing order the p-values are 0.005, 0.01,
+	// 0.03 and 0.04, and there are four tests.
+	p := []float64{0.01, 0.04, nan, 0.03, 0.005}
+	const by = 25.0 / 12 // Σ_{k=1}^4 1/k.
+	for _, test := range []struct {
+		method Adjustment
+		want   []float64
+	}{
+		{method: Bonferroni, want: []float64{0.04, 0.16, nan, 0.12, 0.02}},
+		// 4·0.005, 3·0.01, 2·0.03, max(1·0.04, 0.06).
+		{method: Holm, want: []float64{0.03, 0.06, nan, 0.06, 0.02}},
+		// 4/1·0.005, 4/2·0.01, 4/3·0.03, 4/4·0.04.
+		{method: BenjaminiHochberg, want: []float64{0.02, 0.04, nan, 0.04, 0.02}},
+		{method: BenjaminiYekutieli, want: []float64{0.02 * by, 0.04 * by, nan, 0.04 * by, 0.02 * by}},
+	} {
+		got := Adjust(nil, p, test.method)
+		if !equalNaN(got, test.want) {
+			t.Errorf("unexpected adjusted p-values for method %d: got %v want %v", test.method, got, test.want)
+		}
+
+		// Adjustment in place.
+		dst := append([]float64(nil), p...)
+		Adjust(dst, dst, test.method)
+		if !equalNaN(dst, test.want) {
+			t.Errorf("unexpected in-place adjusted p-values for method %d: got %v want %v", test.method, dst, test.want)
+		}
+	}
+
+	// Adjusted p-values are capped at one.
+	got := Adjust(nil, []float64{0.5, 0.9, 0.6}, Holm)
+	if want := []float64{1, 1, 1}; !floats.Equal(got, want) {
+		t.Errorf("unexpected capped p-values: got %v want %v", got, want)
+	}
+
+	// Empty and all NaN inputs.
+	if got := Adjust(nil, nil, BenjaminiHochberg); len(got) != 0 {
+		t.Errorf("unexpected adjustment of no p-values: %v", got)
+	}
+	if got := Adjust(nil, []float64{nan, nan}, Holm); !math.IsNaN(got[0]) || !math.IsNaN(got[1]) {
+		t.Errorf("unexpected adjustment of NaN p-values: %v", got)
+	}
+}
+
+func TestAdjustOrder(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	p := make([]float64, 1000)
+	for i := range p {
+		p[i] = math.Pow(rnd.Float64(), 3)
+		if i%100 == 0 {
+			p[i] = math.NaN()
+		}
+	}
+	// Duplicate p-values.
+	p[1] = p[2]
+	bonf := Adjust(nil, p, Bonferroni)
+	holm := Adjust(nil, p, Holm)
+	bh := Adjust(nil, p, BenjaminiHochberg)
+	by := Adjust(nil, p, BenjaminiYekutieli)
+	for i, v := range p {
+		if math.IsNaN(v) {
+			if !math.IsNaN(holm[i]) || !math.IsNaN(bh[i]) {
+				t.Errorf("NaN p-value not NaN when adjusted at %d", i)
+			}
+			continue
+		}
+		if !(v <= bh[i] && bh[i] <= by[i] && by[i] <= 1) {
+			t.Errorf("false discovery rate adjustments out of order at %d: p=%v BH=%v BY=%v", i, v, bh[i], by[i])
+		}
+		if !(v <= holm[i] && holm[i] <= bonf[i]) {
+			t.Errorf("family-wise adjustments out of order at %d: p=%v Holm=%v Bonferroni=%v", i, v, holm[i], bonf[i])
+		}
+		if bh[i] > holm[i] {
+			t.Errorf("BH adjustment greater than Holm at %d: %v > %v", i, bh[i], holm[i])
+		}
+		// Adjustment preserves the order of p-values.
+		for j, w := range p {
+			if !math.IsNaN(w) && v < w && (holm[i] > holm[j] || bh[i] > bh[j]) {
+				t.Errorf("adjustment does not preserve order at %d and %d", i, j)
+			}
+		}
+	}
+	if holm[1] != holm[2] || bh[1] != bh[2] {
+		t.Errorf("tied p-values have different adjustments")
+	}
+}
+
+func TestQValues(t *testing.T) {
+	t.Parallel()
+	nan := math.NaN()
+	p := []float64{0.01, 0.9, 0.02, nan, 0.6, 0.03}
+	// Two of the five p-values exceed 0.5, so the
+	// estimate is 2/(5·0.5).
+	pi0 := Pi0(p, 0.5)
+	if pi0 != 0.8 {
+		t.Errorf("unexpected pi0: got %v want 0.8", pi0)
+	}
+	if got := Pi0(p, 0); got != 1 {
+		t.Errorf("unexpected pi0 for lambda 0: got %v want 1", got)
+	}
+	if got := Pi0([]float64{nan}, 0.5); !math.IsNaN(got) {
+		t.Errorf("unexpected pi0 for NaN p-values: got %v want NaN", got)
+	}
+
+	// The BH adjusted p-values are 5/1·0.01, 5/2·0.02,
+	// 5/3·0.03, 5/4·0.6 and 0.9 with step-up minima
+	// 0.05, 0.05, 0.05, 0.75 and 0.9.
+	got := QValues(nil, p, pi0)
+	want := []float64{0.8 * 0.05, 0.8 * 0.9, 0.8 * 0.05, nan, 0.8 * 0.75, 0.8 * 0.05}
+	if !equalNaN(got, want) {
+		t.Errorf("unexpected q-values: got %v want %v", got, want)
+	}
+	if got, want := QValues(nil, p, 1), Adjust(nil, p, BenjaminiHochberg); !equalNaN(got, want) {
+		t.Errorf("q-values with pi0=1 not equal to BH adjustment: got %v want %v", got, want)
+	}
+}
+
+func TestMultiplePanics(t *testing.T) {
+	t.Parallel()
+	p := []float64{0.1, 0.2}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "length", fn: func() { Adjust(make([]float64, 1), p, Holm) }},
+		{name: "method", fn: func() { Adjust(nil, p, Adjustment(-1)) }},
+		{name: "negative p", fn: func() { Adjust(nil, []float64{-0.1}, Holm) }},
+		{name: "large p", fn: func() { QValues(nil, []float64{1.1}, 1) }},
+		{name: "pi0", fn: func() { QValues(nil, p, 0) }},
+		{name: "lambda", fn: func() { Pi0(p, 1) }},
+		{name: "pi0 p", fn: func() { Pi0([]float64{2}, 0.5) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+// equalNaN returns whether a and b are equal to within tol, treating NaNs as
+// equal.
+func equalNaN(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if math.IsNaN(v) != math.IsNaN(b[i]) {
+			return false
+		}
+		if !math.IsNaN(v) && !floats.EqualWithinAbsOrRel(v, b[i], tol, tol) {
+			return false
+		}
+	}
+	return true
+}
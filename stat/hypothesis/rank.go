@@ -0,0 +1,477 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"encoding/binary"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/resample"
+)
+
+const (
+	// exactSize is the largest sample size for which
+	// Auto uses the exact distributions of the
+	// Mann–Whitney and Wilcoxon statistics.
+	exactSize = 50
+
+	// exactLimit is the largest number of rearrangements
+	// for which Auto uses the exact distributions of the
+	// Kruskal–Wallis and Friedman statistics.
+	exactLimit = 100000
+)
+
+// MannWhitney performs the Mann–Whitney U test, also known as the Wilcoxon
+// rank-sum test, of the null hypothesis that the samples x and y are from the
+// same continuous distribution, against the alternative that values in x tend
+// to be less than or greater than values in y. The statistic is
+//
+//	U = R_x - n_x(n_x+1)/2,
+//
+// where R_x is the sum of the ranks of x in the pooled sample, which is the
+// number of pairs with the element of x greater than the element of y,
+// counting ties as half.
+//
+// The asymptotic p-value uses the normal approximation with a continuity
+// correction and a variance corrected for ties. The exact p-value is only
+// available without ties. Auto uses the exact p-value if there are no ties
+// and both samples have fewer than 50 elements.
+//
+// MannWhitney panics if x or y is empty, or if method is Exact and there are
+// ties. The alternative hypothesis is given by alt, where resample.Less is
+// the alternative that values in x tend to be less than values in y.
+func MannWhitney(x, y []float64, alt resample.Alternative, method Method) Result {
+	nx, ny := len(x), len(y)
+	if nx == 0 || ny == 0 {
+		panic("hypothesis: too few observations")
+	}
+	n := nx + ny
+	pool := make([]float64, 0, n)
+	pool = append(pool, x...)
+	pool = append(pool, y...)
+	r := make([]float64, n)
+	ties := rank(r, pool)
+	var rx float64
+	for _, v := range r[:nx] {
+		rx += v
+	}
+	u := rx - float64(nx*(nx+1))/2
+
+	if useExact(method, ties == 0 && nx < exactSize && ny < exactSize, ties == 0) {
+		lower, upper := tails(mannWhitneyDist(nx, ny), int(u))
+		return Result{Statistic: u, P: exactP(lower, upper, alt), Exact: true}
+	}
+	fx, fy, fn := float64(nx), float64(ny), float64(n)
+	mean := fx * fy / 2
+	v := fx * fy / 12 * (fn + 1 - ties/(fn*(fn-1)))
+	return Result{Statistic: u, P: normalP(u, mean, math.Sqrt(v), alt)}
+}
+
+// mannWhitneyDist returns the probabilities of the values of the Mann–Whitney
+// U statistic for samples of sizes m and n without ties.
+func mannWhitneyDist(m, n int) []float64 {
+	// The largest of the i+j observations is in x with
+	// probability i/(i+j), when it is greater than all
+	// j elements of y, so
+	//  p_{i,j}(u) = i/(i+j) p_{i-1,j}(u-j) + j/(i+j) p_{i,j-1}(u).
+	prev := make([][]float64, n+1)
+	for j := range prev {
+		prev[j] = []float64{1}
+	}
+	for i := 1; i <= m; i++ {
+		cur := make([][]float64, n+1)
+		cur[0] = []float64{1}
+		for j := 1; j <= n; j++ {
+			p := make([]float64, i*j+1)
+			wx := float64(i) / float64(i+j)
+			wy := float64(j) / float64(i+j)
+			for u, v := range prev[j] {
+				p[u+j] += wx * v
+			}
+			for u, v := range cur[j-1] {
+				p[u] += wy * v
+			}
+			cur[j] = p
+		}
+		prev = cur
+	}
+	return prev[n]
+}
+
+// WilcoxonSignedRank performs the Wilcoxon signed-rank test of the null
+// hypothesis that the differences x-y are from a continuous distribution
+// symmetric about zero, against the alternative that the differences tend to
+// be less than or greater than zero. If y is nil, the differences are x. The
+// statistic is the sum V of the ranks of the absolute values of the positive
+// differences. Zero differences are discarded.
+//
+// The asymptotic p-value uses the normal approximation with a continuity
+// correction and a variance corrected for ties. The exact p-value is only
+// available without ties or zero differences. Auto uses the exact p-value if
+// there are no ties or zero differences and there are fewer than 50
+// differences.
+//
+// If all differences are zero, the statistic is zero and the p-value is NaN.
+//
+// WilcoxonSignedRank panics if x is empty, if y is not nil and x and y have
+// different lengths, or if method is Exact and there are ties or zero
+// differences. The alternative hypothesis is given by alt, where
+// resample.Greater is the alternative that the differences tend to be
+// greater than zero.
+func WilcoxonSignedRank(x, y []float64, alt resample.Alternative, method Method) Result {
+	if len(x) == 0 {
+		panic("hypothesis: too few observations")
+	}
+	if y != nil && len(y) != len(x) {
+		panic("hypothesis: slice length mismatch")
+	}
+	d := make([]float64, 0, len(x))
+	for i, v := range x {
+		if y != nil {
+			v -= y[i]
+		}
+		if v != 0 {
+			d = append(d, v)
+		}
+	}
+	zeros := len(x) - len(d)
+	n := len(d)
+	abs := make([]float64, n)
+	for i, v := range d {
+		abs[i] = math.Abs(v)
+	}
+	r := make([]float64, n)
+	ties := rank(r, abs)
+	var s float64
+	for i, v := range d {
+		if v > 0 {
+			s += r[i]
+		}
+	}
+	if n == 0 {
+		if method == Exact {
+			panic(exactTies)
+		}
+		return Result{Statistic: 0, P: math.NaN()}
+	}
+
+	possible := ties == 0 && zeros == 0
+	if useExact(method, possible && n < exactSize, possible) {
+		lower, upper := tails(signedRankDist(n), int(s))
+		return Result{Statistic: s, P: exactP(lower, upper, alt), Exact: true}
+	}
+	fn := float64(n)
+	mean := fn * (fn + 1) / 4
+	v := fn*(fn+1)*(2*fn+1)/24 - ties/48
+	return Result{Statistic: s, P: normalP(s, mean, math.Sqrt(v), alt)}
+}
+
+// signedRankDist returns the probabilities of the values of the Wilcoxon
+// signed-rank statistic for n differences without ties.
+func signedRankDist(n int) []float64 {
+	// Each rank i is included in the sum independently
+	// with probability 1/2.
+	p := make([]float64, n*(n+1)/2+1)
+	p[0] = 1
+	max := 0
+	for i := 1; i <= n; i++ {
+		max += i
+		for s := max; s >= 0; s-- {
+			v := p[s] / 2
+			if s >= i {
+				v += p[s-i] / 2
+			}
+			p[s] = v
+		}
+	}
+	return p
+}
+
+// tails returns the lower and upper tail probabilities P(S ≤ s) and
+// P(S ≥ s) of the distribution with probabilities p of the values 0, 1, ….
+func tails(p []float64, s int) (lower, upper float64) {
+	for i, v := range p {
+		if i <= s {
+			lower += v
+		}
+		if i >= s {
+			upper += v
+		}
+	}
+	return math.Min(lower, 1), math.Min(upper, 1)
+}
+
+// KruskalWallis performs the Kruskal–Wallis test of the null hypothesis that
+// the samples in groups are from the same continuous distribution. The
+// statistic is
+//
+//	H = (12/(N(N+1)) Σ_i R_i²/n_i - 3(N+1)) / (1 - Σ(t³-t)/(N³-N)),
+//
+// where N is the total number of observations, R_i is the sum of the ranks
+// of the n_i observations in group i in the pooled sample and the sum in the
+// denominator is over the groups of t tied values.
+//
+// The asymptotic p-value uses the χ² distribution with one less degrees of
+// freedom than the number of groups. The exact p-value is the fraction of
+// the assignments of the ranks to the groups with a statistic at least as
+// large as the observed statistic, and is also exact with ties. Auto uses
+// the exact p-value if there are at most 100000 distinct assignments.
+//
+// If all observations are tied, the statistic and the p-value are NaN.
+//
+// KruskalWallis panics if there are fewer than two groups or if any group is
+// empty.
+func KruskalWallis(groups [][]float64, method Method) Result {
+	k := len(groups)
+	if k < 2 {
+		panic("hypothesis: too few groups")
+	}
+	var n int
+	for _, g := range groups {
+		if len(g) == 0 {
+			panic("hypothesis: empty group")
+		}
+		n += len(g)
+	}
+	pool := make([]float64, 0, n)
+	for _, g := range groups {
+		pool = append(pool, g...)
+	}
+	r := make([]float64, n)
+	ties := rank(r, pool)
+	fn := float64(n)
+	if ties == fn*fn*fn-fn {
+		if method != Auto && method != Exact && method != Asymptotic {
+			panic(badMethod)
+		}
+		return Result{Statistic: math.NaN(), P: math.NaN()}
+	}
+
+	sizes := make([]int, k)
+	sums := make([]float64, k)
+	var off int
+	for i, g := range groups {
+		sizes[i] = len(g)
+		for _, v := range r[off : off+len(g)] {
+			sums[i] += v
+		}
+		off += len(g)
+	}
+	s := kruskalWallisSum(sums, sizes)
+	h := (12/(fn*(fn+1))*s - 3*(fn+1)) / (1 - ties/(fn*fn*fn-fn))
+
+	logCount, _ := math.Lgamma(fn + 1)
+	for _, m := range sizes {
+		lg, _ := math.Lgamma(float64(m + 1))
+		logCount -= lg
+	}
+	if useExact(method, logCount <= math.Log(exactLimit)+1e-9, true) {
+		return Result{Statistic: h, P: kruskalWallisExact(r, sizes, s), Exact: true}
+	}
+	return Result{Statistic: h, P: distuv.ChiSquared{K: float64(k - 1)}.Survival(h)}
+}
+
+// kruskalWallisSum returns Σ_i sums_i²/sizes_i.
+func kruskalWallisSum(sums []float64, sizes []int) float64 {
+	var s float64
+	for i, v := range sums {
+		s += v * v / float64(sizes[i])
+	}
+	return s
+}
+
+// kruskalWallisExact returns the fraction of the assignments of the ranks r
+// to groups of the given sizes for which Σ_i R_i²/n_i is at least obs.
+func kruskalWallisExact(r []float64, sizes []int, obs float64) float64 {
+	// The statistics of rearrangements equal to the
+	// observed data may differ by rounding error.
+	tol := 1e-12 * obs
+	remain := append([]int(nil), sizes...)
+	sums := make([]float64, len(sizes))
+	var extreme, total float64
+	var assign func(i int)
+	assign = func(i int) {
+		if i == len(r) {
+			total++
+			if kruskalWallisSum(sums, sizes) >= obs-tol {
+				extreme++
+			}
+			return
+		}
+		for g := range remain {
+			if remain[g] == 0 {
+				continue
+			}
+			remain[g]--
+			sums[g] += r[i]
+			assign(i + 1)
+			sums[g] -= r[i]
+			remain[g]++
+		}
+	}
+	assign(0)
+	return extreme / total
+}
+
+// Friedman performs the Friedman test of the null hypothesis that the
+// treatments in the columns of data have the same effect, where the rows of
+// data are blocks. The statistic is
+//
+//	Q = 12 Σ_j (R_j - n(k+1)/2)² / (nk(k+1) - Σ(t³-t)/(k-1)),
+//
+// where n is the number of blocks, k is the number of treatments, R_j is the
+// sum of the within-block ranks of treatment j and the sum in the denominator
+// is over the groups of t tied values within each block.
+//
+// The asymptotic p-value uses the χ² distribution with k-1 degrees of
+// freedom. The exact p-value is the fraction of the (k!)^n permutations of
+// the ranks within blocks with a statistic at least as large as the observed
+// statistic, and is also exact with ties. Auto uses the exact p-value if
+// there are at most 100000 permutations.
+//
+// If all blocks are entirely tied, the statistic and the p-value are NaN.
+//
+// Friedman panics if data has fewer than two columns.
+func Friedman(data mat.Matrix, method Method) Result {
+	n, k := data.Dims()
+	if k < 2 {
+		panic("hypothesis: too few treatments")
+	}
+	ranks := mat.NewDense(n, k, nil)
+	row := make([]float64, k)
+	var ties float64
+	for i := 0; i < n; i++ {
+		mat.Row(row, i, data)
+		ties += rank(ranks.RawRowView(i), row)
+	}
+	fn, fk := float64(n), float64(k)
+	den := fn*fk*(fk+1) - ties/(fk-1)
+	if den == 0 {
+		if method != Auto && method != Exact && method != Asymptotic {
+			panic(badMethod)
+		}
+		return Result{Statistic: math.NaN(), P: math.NaN()}
+	}
+	sums := make([]float64, k)
+	for j := range sums {
+		sums[j] = mat.Sum(ranks.ColView(j))
+	}
+	s := friedmanSum(sums, fn*(fk+1)/2)
+	q := 12 * s / den
+
+	lg, _ := math.Lgamma(fk + 1)
+	if useExact(method, fn*lg <= math.Log(exactLimit)+1e-9, true) {
+		return Result{Statistic: q, P: friedmanExact(ranks, s), Exact: true}
+	}
+	return Result{Statistic: q, P: distuv.ChiSquared{K: fk - 1}.Survival(q)}
+}
+
+// friedmanSum returns Σ_j (sums_j - mean)².
+func friedmanSum(sums []float64, mean float64) float64 {
+	var s float64
+	for _, v := range sums {
+		d := v - mean
+		s += d * d
+	}
+	return s
+}
+
+// friedmanExact returns the fraction of the permutations of the ranks within
+// the rows of ranks for which Σ_j (R_j - n(k+1)/2)² is at least obs.
+func friedmanExact(ranks *mat.Dense, obs float64) float64 {
+	n, k := ranks.Dims()
+	perms := permutations(k)
+
+	// Compute the distribution of the vector of column
+	// rank sums block by block. Ranks are multiples of
+	// one half, so the doubled sums are integers.
+	type state struct {
+		sums []int
+		p    float64
+	}
+	dist := map[string]*state{"": {sums: make([]int, k), p: 1}}
+	key := make([]byte, 4*k)
+	w := 1 / float64(len(perms))
+	for i := 0; i < n; i++ {
+		row := ranks.RawRowView(i)
+		next := make(map[string]*state)
+		for _, st := range dist {
+			for _, perm := range perms {
+				sums := make([]int, k)
+				for j, p := range perm {
+					sums[j] = st.sums[j] + int(2*row[p])
+					binary.LittleEndian.PutUint32(key[4*j:], uint32(sums[j]))
+				}
+				if ns, ok := next[string(key)]; ok {
+					ns.p += w * st.p
+					continue
+				}
+				next[string(key)] = &state{sums: sums, p: w * st.p}
+			}
+		}
+		dist = next
+	}
+
+	mean := float64(n*(k+1)) / 2
+	tol := 1e-12 * math.Max(obs, 1)
+	sums := make([]float64, k)
+	var p float64
+	for _, st := range dist {
+		for j, v := range st.sums {
+			sums[j] = float64(v) / 2
+		}
+		if friedmanSum(sums, mean) >= obs-tol {
+			p += st.p
+		}
+	}
+	return math.Min(p, 1)
+}
+
+// permutations returns all permutations of 0, …, n-1.
+func permutations(n int) [][]int {
+	var perms [][]int
+	perm := make([]int, n)
+	used := make([]bool, n)
+	var build func(i int)
+	build = func(i int) {
+		if i == n {
+			perms = append(perms, append([]int(nil), perm...))
+			return
+		}
+		for v := 0; v < n; v++ {
+			if used[v] {
+				continue
+			}
+			used[v] = true
+			perm[i] = v
+			build(i + 1)
+			used[v] = false
+		}
+	}
+	build(0)
+	return perms
+}
+
+// useExact returns whether the exact p-value is used for the given method,
+// where auto is whether Auto uses the exact p-value and possible is whether
+// the exact p-value is available. useExact panics if method is Exact and the
+// exact p-value is not available.
+func useExact(method Method, auto, possible bool) bool {
+	switch method {
+	case Auto:
+		return auto
+	case Exact:
+		if !possible {
+			panic(exactTies)
+		}
+		return true
+	case Asymptotic:
+		return false
+	default:
+		panic(badMethod)
+	}
+}
@@ -0,0 +1,349 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/combin"
+	"gonum.org/v1/gonum/stat/distuv"
+	"gonum.org/v1/gonum/stat/resample"
+)
+
+const tol = 1e-12
+
+type resultTest struct {
+	name   string
+	got    Result
+	stat   float64
+	p      float64
+	exact  bool
+	pTol   float64
+	isNaNs bool
+}
+
+func checkResults(t *testing.T, tests []resultTest) {
+	t.Helper()
+	for _, test := range tests {
+		if test.isNaNs {
+			if !math.IsNaN(test.got.P) {
+				t.Errorf("%s: unexpected p-value: got %v want NaN", test.name, test.got.P)
+			}
+			continue
+		}
+		if !floats.EqualWithinAbsOrRel(test.got.Statistic, test.stat, tol, tol) {
+			t.Errorf("%s: unexpected statistic: got %v want %v", test.name, test.got.Statistic, test.stat)
+		}
+		pTol := test.pTol
+		if pTol == 0 {
+			pTol = tol
+		}
+		if !floats.EqualWithinAbsOrRel(test.got.P, test.p, pTol, pTol) {
+			t.Errorf("%s: unexpected p-value: got %v want %v", test.name, test.got.P, test.p)
+		}
+		if test.got.Exact != test.exact {
+			t.Errorf("%s: unexpected exactness: got %t want %t", test.name, test.got.Exact, test.exact)
+		}
+	}
+}
+
+func TestMannWhitney(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3}
+	y := []float64{4, 5, 6}
+
+	// With ties, the pooled ranks of x={1, 2, 2, 3} are
+	// {1, 3, 3, 5.5}, so U = 12.5 - 10 = 2.5. The tie sum
+	// is 24 + 6 = 30, so the variance is
+	//  16/12 (9 - 30/56) = 79/7.
+	tx := []float64{1, 2, 2, 3}
+	ty := []float64{2, 3, 4, 5}
+	tieSD := math.Sqrt(79.0 / 7)
+
+	// Large samples use the normal approximation with
+	// mean 50·50/2 and variance 50·50·101/12.
+	lx := make([]float64, 50)
+	ly := make([]float64, 50)
+	for i := range lx {
+		lx[i] = float64(2 * i)
+		ly[i] = float64(2*i + 1)
+	}
+	// x has 50·49/2 pairs greater than y.
+	lu := float64(50 * 49 / 2)
+	lsd := math.Sqrt(50 * 50 * 101 / 12.0)
+
+	checkResults(t, []resultTest{
+		// Only one of the 20 assignments has U = 0.
+		{name: "less", got: MannWhitney(x, y, resample.Less, Auto), stat: 0, p: 1.0 / 20, exact: true},
+		{name: "two sided", got: MannWhitney(x, y, resample.TwoSided, Auto), stat: 0, p: 2.0 / 20, exact: true},
+		{name: "greater", got: MannWhitney(x, y, resample.Greater, Exact), stat: 0, p: 1, exact: true},
+		{name: "swapped", got: MannWhitney(y, x, resample.Greater, Auto), stat: 9, p: 1.0 / 20, exact: true},
+		{
+			name: "asymptotic", got: MannWhitney(x, y, resample.Less, Asymptotic),
+			stat: 0, p: distuv.UnitNormal.CDF((0 - 4.5 + 0.5) / math.Sqrt(9*7/12.0)),
+		},
+		{
+			name: "ties", got: MannWhitney(tx, ty, resample.TwoSided, Auto),
+			stat: 2.5, p: 2 * distuv.UnitNormal.CDF(-5/tieSD),
+		},
+		{
+			name: "ties greater", got: MannWhitney(tx, ty, resample.Greater, Auto),
+			stat: 2.5, p: distuv.UnitNormal.Survival((2.5 - 8 - 0.5) / tieSD),
+		},
+		{
+			name: "large", got: MannWhitney(lx, ly, resample.TwoSided, Auto),
+			stat: lu, p: 2 * distuv.UnitNormal.CDF((lu-1250+0.5)/lsd),
+		},
+	})
+}
+
+func TestMannWhitneyDist(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct{ m, n int }{{1, 1}, {1, 4}, {3, 2}, {4, 5}, {6, 6}} {
+		// Count U over the subsets of ranks of x.
+		total := combin.Binomial(test.m+test.n, test.m)
+		want := make([]float64, test.m*test.n+1)
+		for _, c := range combin.Combinations(test.m+test.n, test.m) {
+			u := -test.m * (test.m - 1) / 2
+			for _, r := range c {
+				u += r
+			}
+			want[u] += 1 / float64(total)
+		}
+		got := mannWhitneyDist(test.m, test.n)
+		if !floats.EqualApprox(got, want, tol) {
+			t.Errorf("unexpected distribution for m=%d n=%d:\ngot: %v\nwant:%v", test.m, test.n, got, want)
+		}
+	}
+}
+
+func TestWilcoxonSignedRank(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3, 4, 5}
+
+	// The differences {0, 1, 2, -2, 4, 5} have a zero,
+	// which is dropped, and a tie. The ranks of the
+	// absolute differences are {1, 2.5, 2.5, 4, 5}, so
+	// V = 12.5 with mean 7.5 and variance
+	//  5·6·11/24 - 6/48 = 13.625.
+	px := []float64{1, 2, 3, 4, 5, 6}
+	py := []float64{1, 1, 1, 6, 1, 1}
+	sd := math.Sqrt(13.625)
+
+	checkResults(t, []resultTest{
+		// Only one of the 32 sign assignments has V = 15.
+		{name: "greater", got: WilcoxonSignedRank(x, nil, resample.Greater, Auto), stat: 15, p: 1.0 / 32, exact: true},
+		{name: "two sided", got: WilcoxonSignedRank(x, nil, resample.TwoSided, Exact), stat: 15, p: 2.0 / 32, exact: true},
+		{name: "less", got: WilcoxonSignedRank(x, nil, resample.Less, Auto), stat: 15, p: 1, exact: true},
+		// The signs of ranks 1 and 2 give V ≤ 3 for
+		// 5 of the 32 assignments, and {1, 2} and {3}
+		// both give V = 3.
+		{name: "paired", got: WilcoxonSignedRank([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 0, 8, 10}, resample.Less, Auto), stat: 3, p: 5.0 / 32, exact: true},
+		{
+			name: "asymptotic", got: WilcoxonSignedRank(x, nil, resample.Greater, Asymptotic),
+			stat: 15, p: distuv.UnitNormal.Survival((15 - 7.5 - 0.5) / math.Sqrt(13.75)),
+		},
+		{
+			name: "ties and zeros", got: WilcoxonSignedRank(px, py, resample.Greater, Auto),
+			stat: 12.5, p: distuv.UnitNormal.Survival((12.5 - 7.5 - 0.5) / sd),
+		},
+		{
+			name: "ties and zeros two sided", got: WilcoxonSignedRank(px, py, resample.TwoSided, Auto),
+			stat: 12.5, p: 2 * distuv.UnitNormal.Survival((12.5-7.5-0.5)/sd),
+		},
+		{name: "all zero", got: WilcoxonSignedRank(x, x, resample.TwoSided, Auto), isNaNs: true},
+	})
+}
+
+func TestSignedRankDist(t *testing.T) {
+	t.Parallel()
+	for n := 1; n <= 10; n++ {
+		// Count V over the subsets of ranks with
+		// positive differences.
+		want := make([]float64, n*(n+1)/2+1)
+		for mask := 0; mask < 1<<uint(n); mask++ {
+			var v int
+			for i := 0; i < n; i++ {
+				if mask&(1<<uint(i)) != 0 {
+					v += i + 1
+				}
+			}
+			want[v] += 1 / float64(int(1)<<uint(n))
+		}
+		got := signedRankDist(n)
+		if !floats.EqualApprox(got, want, tol) {
+			t.Errorf("unexpected distribution for n=%d:\ngot: %v\nwant:%v", n, got, want)
+		}
+	}
+}
+
+func TestKruskalWallis(t *testing.T) {
+	t.Parallel()
+	// The pooled ranks of {1, 1, 2} and {2, 3, 3} are
+	// {1.5, 1.5, 3.5} and {3.5, 5.5, 5.5} with rank sums
+	// 6.5 and 14.5, so the uncorrected statistic is
+	//  12/42 · (6.5² + 14.5²)/3 - 21 = 64/21,
+	// and the tie correction is 1 - 18/210, giving 10/3.
+	// Rank sums as extreme as 6.5 and 14.5 need both
+	// of the smallest or the largest values in the
+	// first group, which occurs in 4 of the 20
+	// assignments.
+	tied := [][]float64{{1, 1, 2}, {2, 3, 3}}
+
+	checkResults(t, []resultTest{
+		// Of the 6 assignments of {1, 2, 3, 4} to two
+		// groups of two, {1, 2} and {3, 4} give the
+		// observed rank sums.
+		{name: "exact", got: KruskalWallis([][]float64{{1, 2}, {3, 4}}, Auto), stat: 2.4, p: 2.0 / 6, exact: true},
+		{
+			name: "asymptotic", got: KruskalWallis([][]float64{{1, 2}, {3, 4}}, Asymptotic),
+			stat: 2.4, p: 2 * distuv.UnitNormal.CDF(-math.Sqrt(2.4)), pTol: 1e-10,
+		},
+		{name: "ties exact", got: KruskalWallis(tied, Exact), stat: 10.0 / 3, p: 4.0 / 20, exact: true},
+		{
+			name: "ties asymptotic", got: KruskalWallis(tied, Asymptotic),
+			stat: 10.0 / 3, p: 2 * distuv.UnitNormal.CDF(-math.Sqrt(10.0/3)), pTol: 1e-10,
+		},
+		{name: "all tied", got: KruskalWallis([][]float64{{1, 1}, {1}}, Auto), isNaNs: true},
+	})
+
+	// Three singleton groups. Every assignment has
+	// H = 12/12 · 14 - 12 = 2.
+	got := KruskalWallis([][]float64{{3}, {1}, {2}}, Auto)
+	if !got.Exact || got.Statistic != 2 || got.P != 1 {
+		t.Errorf("unexpected result for singleton groups: got %+v", got)
+	}
+
+	// Large samples use the asymptotic p-value, which
+	// for 3 groups is the χ²₂ survival function exp(-H/2).
+	rnd := rand.New(rand.NewSource(1))
+	groups := make([][]float64, 3)
+	for i := range groups {
+		groups[i] = make([]float64, 20)
+		for j := range groups[i] {
+			groups[i][j] = rnd.NormFloat64() + 0.3*float64(i)
+		}
+	}
+	got = KruskalWallis(groups, Auto)
+	if got.Exact {
+		t.Error("unexpected exact p-value for large sample")
+	}
+	if want := math.Exp(-got.Statistic / 2); !floats.EqualWithinAbsOrRel(got.P, want, 1e-10, 1e-10) {
+		t.Errorf("unexpected asymptotic p-value: got %v want %v", got.P, want)
+	}
+}
+
+func TestFriedman(t *testing.T) {
+	t.Parallel()
+	identical := mat.NewDense(2, 3, []float64{
+		1, 2, 3,
+		10, 20, 30,
+	})
+	// The ranks within the rows are {1.5, 1.5, 3},
+	// {1, 2, 3} and {3, 2, 1}, with rank sums 5.5, 5.5
+	// and 7 about the mean 6. The statistic is
+	//  12 · 1.5 / (36 - 6/2) = 6/11.
+	tied := mat.NewDense(3, 3, []float64{
+		1, 1, 2,
+		1, 2, 3,
+		3, 2, 1,
+	})
+	checkResults(t, []resultTest{
+		// The rank sums {2, 4, 6} give Q = 4, which is
+		// attained when both blocks have the same
+		// ordering, in 6 of the 36 permutations.
+		{name: "exact", got: Friedman(identical, Auto), stat: 4, p: 6.0 / 36, exact: true},
+		{name: "asymptotic", got: Friedman(identical, Asymptotic), stat: 4, p: math.Exp(-2), pTol: 1e-10},
+		{name: "ties", got: Friedman(tied, Asymptotic), stat: 6.0 / 11, p: math.Exp(-3.0 / 11), pTol: 1e-10},
+		{name: "all tied", got: Friedman(mat.NewDense(2, 2, []float64{1, 1, 2, 2}), Auto), isNaNs: true},
+	})
+
+	// The exact p-value with ties matches enumeration
+	// of all permutations within blocks.
+	got := Friedman(tied, Exact)
+	if want := friedmanBrute(tied); !got.Exact || !floats.EqualWithinAbsOrRel(got.P, want, tol, tol) {
+		t.Errorf("unexpected exact p-value with ties: got %v want %v", got.P, want)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	data := mat.NewDense(3, 4, nil)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 4; j++ {
+			data.Set(i, j, float64(rnd.Intn(3*(j+1))))
+		}
+	}
+	got = Friedman(data, Auto)
+	if want := friedmanBrute(data); !got.Exact || !floats.EqualWithinAbsOrRel(got.P, want, tol, tol) {
+		t.Errorf("unexpected exact p-value: got %v want %v", got.P, want)
+	}
+}
+
+// friedmanBrute returns the exact p-value of the Friedman test by enumerating
+// the permutations of the data within blocks.
+func friedmanBrute(data *mat.Dense) float64 {
+	n, k := data.Dims()
+	obs := Friedman(data, Asymptotic).Statistic
+	perms := permutations(k)
+	dims := make([]int, n)
+	for i := range dims {
+		dims[i] = len(perms)
+	}
+	total := 1
+	for range dims {
+		total *= len(perms)
+	}
+	var extreme int
+	perm := mat.NewDense(n, k, nil)
+	sub := make([]int, n)
+	for idx := 0; idx < total; idx++ {
+		combin.SubFor(sub, idx, dims)
+		for i, s := range sub {
+			for j, p := range perms[s] {
+				perm.Set(i, j, data.At(i, p))
+			}
+		}
+		if Friedman(perm, Asymptotic).Statistic >= obs-1e-10 {
+			extreme++
+		}
+	}
+	return float64(extreme) / float64(total)
+}
+
+func TestRankPanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3}
+	tied := []float64{1, 1, 2}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "Mann–Whitney empty", fn: func() { MannWhitney(x, nil, resample.TwoSided, Auto) }},
+		{name: "Mann–Whitney exact ties", fn: func() { MannWhitney(tied, x, resample.TwoSided, Exact) }},
+		{name: "Mann–Whitney alternative", fn: func() { MannWhitney(x, x, resample.Alternative(-1), Auto) }},
+		{name: "Mann–Whitney method", fn: func() { MannWhitney(x, x, resample.TwoSided, Method(-1)) }},
+		{name: "Wilcoxon length", fn: func() { WilcoxonSignedRank(x, x[:2], resample.TwoSided, Auto) }},
+		{name: "Wilcoxon exact ties", fn: func() { WilcoxonSignedRank(tied, nil, resample.TwoSided, Exact) }},
+		{name: "Wilcoxon exact zeros", fn: func() { WilcoxonSignedRank([]float64{0, 1}, nil, resample.TwoSided, Exact) }},
+		{name: "Kruskal–Wallis groups", fn: func() { KruskalWallis([][]float64{x}, Auto) }},
+		{name: "Kruskal–Wallis empty", fn: func() { KruskalWallis([][]float64{x, nil}, Auto) }},
+		{name: "Friedman treatments", fn: func() { Friedman(mat.NewDense(3, 1, x), Auto) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
@@ -0,0 +1,182 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// sampleSize returns the number of the n observations with the given
+// weights, treating the weights as frequencies as in package stat.
+func sampleSize(n int, weights []float64) float64 {
+	if weights == nil {
+		return float64(n)
+	}
+	return floats.Sum(weights)
+}
+
+// ScottBandwidth returns Scott's normal reference rule bandwidth for the
+// observations x with the optional weights,
+//
+//	h = 1.06 * σ * n^(-1/5),
+//
+// where σ is the standard deviation of x and n is the number of observations,
+// the sum of the weights if weights is not nil. The bandwidth is close to
+// optimal when the distribution of x is near to normal.
+func ScottBandwidth(x, weights []float64) float64 {
+	n := sampleSize(len(x), weights)
+	return 1.06 * stat.StdDev(x, weights) * math.Pow(n, -0.2)
+}
+
+// SilvermanBandwidth returns Silverman's rule of thumb bandwidth for the
+// observations x with the optional weights,
+//
+//	h = 0.9 * min(σ, IQR/1.349) * n^(-1/5),
+//
+// where σ is the standard deviation and IQR the interquartile range of x
+// and n is the number of observations, the sum of the weights if weights is
+// not nil. The rule is more robust than ScottBandwidth to skewed and
+// multimodal distributions. If the interquartile range is zero, σ is used.
+func SilvermanBandwidth(x, weights []float64) float64 {
+	n := sampleSize(len(x), weights)
+	std := stat.StdDev(x, weights)
+	xs := make([]float64, len(x))
+	copy(xs, x)
+	var ws []float64
+	if weights != nil {
+		ws = make([]float64, len(weights))
+		copy(ws, weights)
+	}
+	stat.SortWeighted(xs, ws)
+	iqr := stat.Quantile(0.75, stat.Empirical, xs, ws) - stat.Quantile(0.25, stat.Empirical, xs, ws)
+	s := std
+	if iqr > 0 {
+		s = math.Min(std, iqr/1.349)
+	}
+	return 0.9 * s * math.Pow(n, -0.2)
+}
+
+// CrossValidatedBandwidth returns the bandwidth for the kernel k that
+// maximizes the leave-one-out log-likelihood of the observations x with the
+// optional weights,
+//
+//	Σ_i w_i * log f_{-i}(x_i),
+//
+// where f_{-i} is the estimate formed without the ith observation. The
+// bandwidth is searched for between 1/20 and 5 times SilvermanBandwidth,
+// and each evaluation of the likelihood takes O(n²) time for kernels with
+// unbounded support. CrossValidatedBandwidth returns zero if x has no
+// spread.
+func CrossValidatedBandwidth(x, weights []float64, k Kernel) float64 {
+	const (
+		gridSize = 41
+		minScale = 1.0 / 20
+		maxScale = 5.0
+		iters    = 40
+	)
+	s := SilvermanBandwidth(x, weights)
+	if !(s > 0) {
+		return 0
+	}
+	xs, ws := sortedCopy(x, weights)
+	score := func(logh float64) float64 {
+		return looLikelihood(xs, ws, k, math.Exp(logh))
+	}
+
+	// Find the best bandwidth on a coarse grid and refine it
+	// by golden section search between its neighbors.
+	grid := floats.Span(make([]float64, gridSize), math.Log(minScale*s), math.Log(maxScale*s))
+	best := 0
+	bestScore := math.Inf(-1)
+	for i, logh := range grid {
+		if v := score(logh); v > bestScore {
+			best, bestScore = i, v
+		}
+	}
+	lo, hi := grid[best], grid[best]
+	if best > 0 {
+		lo = grid[best-1]
+	}
+	if best < gridSize-1 {
+		hi = grid[best+1]
+	}
+	phi := (math.Sqrt(5) - 1) / 2
+	a := hi - phi*(hi-lo)
+	b := lo + phi*(hi-lo)
+	fa, fb := score(a), score(b)
+	for i := 0; i < iters; i++ {
+		if fa > fb {
+			hi, b, fb = b, a, fa
+			a = hi - phi*(hi-lo)
+			fa = score(a)
+		} else {
+			lo, a, fa = a, b, fb
+			b = lo + phi*(hi-lo)
+			fb = score(b)
+		}
+	}
+	h := math.Exp((lo + hi) / 2)
+	if bestScore > math.Max(fa, fb) {
+		h = math.Exp(grid[best])
+	}
+	return h
+}
+
+// looLikelihood returns the weighted leave-one-out log-likelihood of the
+// sorted observations x with normalized weights w for the kernel k and
+// bandwidth h.
+func looLikelihood(x, w []float64, k Kernel, h float64) float64 {
+	u := &Univariate{x: x, w: w, h: h, k: k}
+	var ll float64
+	for i, xi := range x {
+		if w[i] == 1 {
+			return math.Inf(-1)
+		}
+		lo, hi := u.window(xi)
+		var p float64
+		for j := lo; j < hi; j++ {
+			if j != i {
+				p += w[j] * k.Prob((xi-x[j])/h)
+			}
+		}
+		ll += w[i] * math.Log(p/(h*(1-w[i])))
+	}
+	return ll
+}
+
+// ScottBandwidthMatrix stores Scott's normal reference rule bandwidth matrix
+// for the observations in the rows of x with the optional weights into dst,
+//
+//	H = n^(-2/(d+4)) * Σ,
+//
+// where Σ is the covariance matrix of x, d is the number of columns of x and
+// n is the number of observations, the sum of the weights if weights is
+// not nil. The dst matrix must either be zero-sized or d×d.
+func ScottBandwidthMatrix(dst *mat.SymDense, x mat.Matrix, weights []float64) {
+	r, d := x.Dims()
+	n := sampleSize(r, weights)
+	stat.CovarianceMatrix(dst, x, weights)
+	dst.ScaleSym(math.Pow(n, -2/float64(d+4)), dst)
+}
+
+// SilvermanBandwidthMatrix stores Silverman's rule of thumb bandwidth matrix
+// for the observations in the rows of x with the optional weights into dst,
+//
+//	H = (4/(d+2))^(2/(d+4)) * n^(-2/(d+4)) * Σ,
+//
+// where Σ is the covariance matrix of x, d is the number of columns of x and
+// n is the number of observations, the sum of the weights if weights is
+// not nil. The dst matrix must either be zero-sized or d×d.
+func SilvermanBandwidthMatrix(dst *mat.SymDense, x mat.Matrix, weights []float64) {
+	r, d := x.Dims()
+	n := sampleSize(r, weights)
+	stat.CovarianceMatrix(dst, x, weights)
+	f := 4 / (float64(d+2) * n)
+	dst.ScaleSym(math.Pow(f, 2/float64(d+4)), dst)
+}
@@ -0,0 +1,18 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kde provides univariate and multivariate kernel density estimation.
+//
+// A kernel density estimate smooths a set of weighted observations x_i by
+// placing a scaled kernel K at each observation,
+//
+//	f(x) = Σ_i w_i/h * K((x - x_i)/h),
+//
+// where the bandwidth h controls the amount of smoothing. The bandwidth may
+// be chosen with the normal reference rules of Scott and Silverman, or by
+// likelihood cross-validation.
+//
+// See Silverman, "Density Estimation for Statistics and Data Analysis",
+// Chapman and Hall, 1986 for more details.
+package kde // import "gonum.org/v1/gonum/stat/kde"
@@ -0,0 +1,140 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// Kernel is a symmetric probability density with zero mean and unit variance
+// used to smooth observations. Since all kernels have unit variance, the
+// bandwidth of an estimate is the standard deviation of the kernel placed at
+// each observation, and bandwidths are comparable between kernels.
+type Kernel interface {
+	// Prob returns the density of the kernel at u.
+	Prob(u float64) float64
+
+	// LogProb returns the log of the density of the
+	// kernel at u.
+	LogProb(u float64) float64
+
+	// CDF returns the cumulative distribution function
+	// of the kernel at u.
+	CDF(u float64) float64
+
+	// Rand returns a random sample drawn from the kernel
+	// using src as the source of random numbers. If src
+	// is nil, the global source is used.
+	Rand(src rand.Source) float64
+
+	// Support returns the radius r of the support of the
+	// kernel, so that the density is zero for |u| > r.
+	// Support returns +Inf for kernels with unbounded
+	// support.
+	Support() float64
+}
+
+var (
+	_ Kernel = Gaussian{}
+	_ Kernel = Epanechnikov{}
+)
+
+// Gaussian is the standard normal kernel
+//
+//	K(u) = exp(-u²/2) / √(2π).
+type Gaussian struct{}
+
+// Prob returns the density of the kernel at u.
+func (Gaussian) Prob(u float64) float64 {
+	return math.Exp(-0.5*u*u) / math.Sqrt(2*math.Pi)
+}
+
+// LogProb returns the log of the density of the kernel at u.
+func (Gaussian) LogProb(u float64) float64 {
+	return -0.5*u*u - 0.5*math.Log(2*math.Pi)
+}
+
+// CDF returns the cumulative distribution function of the kernel at u.
+func (Gaussian) CDF(u float64) float64 {
+	return 0.5 * math.Erfc(-u/math.Sqrt2)
+}
+
+// Rand returns a random sample drawn from the kernel.
+func (Gaussian) Rand(src rand.Source) float64 {
+	if src == nil {
+		return rand.NormFloat64()
+	}
+	return rand.New(src).NormFloat64()
+}
+
+// Support returns +Inf.
+func (Gaussian) Support() float64 {
+	return math.Inf(1)
+}
+
+// epanechnikovRadius is the radius of the support of
+// the Epanechnikov kernel with unit variance.
+var epanechnikovRadius = math.Sqrt(5)
+
+// Epanechnikov is the Epanechnikov kernel scaled to unit variance
+//
+//	K(u) = 3/(4√5) * (1 - u²/5) for |u| ≤ √5,
+//
+// which minimizes the asymptotic mean integrated squared error of the
+// estimate among kernels with unit variance.
+type Epanechnikov struct{}
+
+// Prob returns the density of the kernel at u.
+func (Epanechnikov) Prob(u float64) float64 {
+	t := u / epanechnikovRadius
+	if math.Abs(t) > 1 {
+		return 0
+	}
+	return 0.75 * (1 - t*t) / epanechnikovRadius
+}
+
+// LogProb returns the log of the density of the kernel at u.
+func (e Epanechnikov) LogProb(u float64) float64 {
+	return math.Log(e.Prob(u))
+}
+
+// CDF returns the cumulative distribution function of the kernel at u.
+func (Epanechnikov) CDF(u float64) float64 {
+	t := u / epanechnikovRadius
+	switch {
+	case t <= -1:
+		return 0
+	case t >= 1:
+		return 1
+	}
+	return 0.5 + 0.75*t - 0.25*t*t*t
+}
+
+// Rand returns a random sample drawn from the kernel.
+func (Epanechnikov) Rand(src rand.Source) float64 {
+	rnd := rand.Float64
+	if src != nil {
+		rnd = rand.New(src).Float64
+	}
+	// Take u2 if |u3| is the largest of three uniform
+	// samples on [-1, 1] and u3 otherwise, see Devroye and
+	// Györfi, "Nonparametric Density Estimation: The L1
+	// View", Wiley, 1985.
+	u1 := 2*rnd() - 1
+	u2 := 2*rnd() - 1
+	u3 := 2*rnd() - 1
+	t := u3
+	if math.Abs(u3) >= math.Abs(u2) && math.Abs(u3) >= math.Abs(u1) {
+		t = u2
+	}
+	return t * epanechnikovRadius
+}
+
+// Support returns √5.
+func (Epanechnikov) Support() float64 {
+	return epanechnikovRadius
+}
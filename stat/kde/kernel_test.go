@@ -0,0 +1,87 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestKernel(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		k    Kernel
+	}{
+		{name: "Gaussian", k: Gaussian{}},
+		{name: "Epanechnikov", k: Epanechnikov{}},
+	} {
+		k := test.k
+
+		// Integrate the moments of the density by the
+		// trapezoidal rule.
+		const n = 20001
+		u := floats.Span(make([]float64, n), -8, 8)
+		du := u[1] - u[0]
+		var mass, mean, variance float64
+		for i, v := range u {
+			p := k.Prob(v)
+			if i == 0 || i == n-1 {
+				p /= 2
+			}
+			mass += p * du
+			mean += v * p * du
+			variance += v * v * p * du
+			if math.Abs(v) > k.Support() && p != 0 {
+				t.Errorf("%s: non-zero density outside support at %v", test.name, v)
+			}
+			if lp := math.Log(k.Prob(v)); math.Abs(lp-k.LogProb(v)) > 1e-12*math.Max(1, math.Abs(lp)) {
+				t.Errorf("%s: mismatched log density at %v: got %v want %v", test.name, v, k.LogProb(v), lp)
+			}
+		}
+		if math.Abs(mass-1) > 1e-6 {
+			t.Errorf("%s: unexpected mass: got %v want 1", test.name, mass)
+		}
+		if math.Abs(mean) > 1e-12 {
+			t.Errorf("%s: unexpected mean: got %v want 0", test.name, mean)
+		}
+		if math.Abs(variance-1) > 1e-6 {
+			t.Errorf("%s: unexpected variance: got %v want 1", test.name, variance)
+		}
+
+		var cdf float64
+		for i := 1; i < n; i++ {
+			cdf += (k.Prob(u[i-1]) + k.Prob(u[i])) / 2 * du
+			if i%1000 == 0 && math.Abs(cdf-k.CDF(u[i])) > 1e-6 {
+				t.Errorf("%s: unexpected CDF at %v: got %v want %v", test.name, u[i], k.CDF(u[i]), cdf)
+			}
+		}
+
+		src := rand.NewSource(1)
+		x := make([]float64, 100000)
+		for i := range x {
+			x[i] = k.Rand(src)
+		}
+		m, s := stat.MeanStdDev(x, nil)
+		if math.Abs(m) > 0.01 || math.Abs(s-1) > 0.01 {
+			t.Errorf("%s: unexpected sample moments: mean=%v std=%v", test.name, m, s)
+		}
+		if q := stat.Quantile(0.9, stat.Empirical, sortCopy(x), nil); math.Abs(k.CDF(q)-0.9) > 0.01 {
+			t.Errorf("%s: unexpected sample quantile: CDF(%v)=%v want 0.9", test.name, q, k.CDF(q))
+		}
+	}
+}
+
+func sortCopy(x []float64) []float64 {
+	s := make([]float64, len(x))
+	copy(s, x)
+	stat.SortWeighted(s, nil)
+	return s
+}
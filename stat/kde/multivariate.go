@@ -0,0 +1,184 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Multivariate is a kernel density estimate of a multivariate distribution
+// with the bandwidth matrix H = L*Lᵀ,
+//
+//	f(x) = Σ_i w_i/|L| * Π_j K([L⁻¹(x - x_i)]_j),
+//
+// where the weights w_i sum to one. The product of the univariate kernels is
+// a kernel with identity covariance, so H is the covariance of the kernel
+// placed at each observation. With the Gaussian kernel, the estimate is a
+// mixture of normal distributions with covariance H.
+type Multivariate struct {
+	dim int
+
+	// x holds the observations and z the
+	// observations transformed by L⁻¹.
+	x, z *mat.Dense
+
+	// w holds the normalized weights and
+	// cum their cumulative sums.
+	w, cum []float64
+
+	// chol holds the Cholesky factorization
+	// of H, l its lower triangular factor and
+	// uInv the inverse of its transpose.
+	chol   mat.Cholesky
+	l      mat.TriDense
+	uInv   mat.TriDense
+	logDet float64
+
+	k   Kernel
+	src rand.Source
+}
+
+// NewMultivariate returns a kernel density estimate of the observations in
+// the rows of x with the kernel k and the bandwidth matrix h, and whether h
+// is positive definite. If weights is nil, all the observations are weighted
+// equally, otherwise weights holds the relative weights of the observations.
+// The inputs are copied. The bandwidth matrix may be chosen with
+// ScottBandwidthMatrix or SilvermanBandwidthMatrix. The source of random
+// numbers for Rand is src. If src is nil, the global source is used.
+//
+// NewMultivariate will panic if x has no rows, if the dimensions of h do not
+// match the columns of x, or if weights is not nil and has a length different
+// to the number of rows of x or a non-positive sum.
+func NewMultivariate(x mat.Matrix, weights []float64, k Kernel, h mat.Symmetric, src rand.Source) (*Multivariate, bool) {
+	r, d := x.Dims()
+	if r == 0 {
+		panic("kde: no observations")
+	}
+	if h.Symmetric() != d {
+		panic("kde: mismatched bandwidth dimension")
+	}
+	if weights != nil && len(weights) != r {
+		panic("kde: mismatched observation and weight lengths")
+	}
+	m := &Multivariate{
+		dim: d,
+		x:   mat.DenseCopyOf(x),
+		w:   make([]float64, r),
+		cum: make([]float64, r),
+		k:   k,
+		src: src,
+	}
+	if weights == nil {
+		for i := range m.w {
+			m.w[i] = 1
+		}
+	} else {
+		copy(m.w, weights)
+	}
+	sum := floats.Sum(m.w)
+	if !(sum > 0) {
+		panic("kde: non-positive weight sum")
+	}
+	floats.Scale(1/sum, m.w)
+	floats.CumSum(m.cum, m.w)
+
+	if !m.chol.Factorize(h) {
+		return nil, false
+	}
+	// With H = Uᵀ*U, L is Uᵀ and the rows of X*U⁻¹
+	// are the transformed observations L⁻¹*x_i.
+	var u mat.TriDense
+	m.chol.UTo(&u)
+	m.chol.LTo(&m.l)
+	if err := m.uInv.InverseTri(&u); err != nil {
+		return nil, false
+	}
+	m.z = &mat.Dense{}
+	m.z.Mul(m.x, &m.uInv)
+	m.logDet = 0.5 * m.chol.LogDet()
+	return m, true
+}
+
+// Dim returns the dimension of the distribution.
+func (m *Multivariate) Dim() int {
+	return m.dim
+}
+
+// Bandwidth stores the bandwidth matrix of the estimate into dst. The dst
+// matrix must either be zero-sized or d×d.
+func (m *Multivariate) Bandwidth(dst *mat.SymDense) {
+	m.chol.ToSym(dst)
+}
+
+// transform returns L⁻¹*x.
+func (m *Multivariate) transform(x []float64) *mat.VecDense {
+	if len(x) != m.dim {
+		panic("kde: mismatched dimension")
+	}
+	var z mat.VecDense
+	z.MulVec(m.uInv.T(), mat.NewVecDense(m.dim, x))
+	return &z
+}
+
+// Prob returns the estimated density at x.
+func (m *Multivariate) Prob(x []float64) float64 {
+	return math.Exp(m.LogProb(x))
+}
+
+// LogProb returns the log of the estimated density at x.
+func (m *Multivariate) LogProb(x []float64) float64 {
+	z := m.transform(x)
+	r, _ := m.z.Dims()
+	terms := make([]float64, r)
+	for i := range terms {
+		v := math.Log(m.w[i])
+		zi := m.z.RawRowView(i)
+		for j, zij := range zi {
+			v += m.k.LogProb(z.AtVec(j) - zij)
+		}
+		terms[i] = v
+	}
+	return floats.LogSumExp(terms) - m.logDet
+}
+
+// Rand generates a random sample from the estimate. If dst is not nil, the
+// sample is stored in-place into dst and returned, otherwise a new slice is
+// allocated. Rand will panic if dst is not nil and its length is not the
+// dimension of the distribution.
+func (m *Multivariate) Rand(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, m.dim)
+	}
+	if len(dst) != m.dim {
+		panic("kde: mismatched dimension")
+	}
+	var v float64
+	if m.src == nil {
+		v = rand.Float64()
+	} else {
+		v = rand.New(m.src).Float64()
+	}
+	i := sort.SearchFloat64s(m.cum, v)
+	if i == len(m.cum) {
+		i--
+	}
+	e := make([]float64, m.dim)
+	for j := range e {
+		e[j] = m.k.Rand(m.src)
+	}
+	// Add L*e to the chosen observation.
+	var le mat.VecDense
+	le.MulVec(&m.l, mat.NewVecDense(m.dim, e))
+	for j := range dst {
+		dst[j] = m.x.At(i, j) + le.AtVec(j)
+	}
+	return dst
+}
@@ -0,0 +1,138 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+func TestMultivariate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, d = 100, 3
+	x := mat.NewDense(n, d, nil)
+	w := make([]float64, n)
+	for i := 0; i < n; i++ {
+		x.Set(i, 0, rnd.NormFloat64())
+		x.Set(i, 1, 0.5*x.At(i, 0)+rnd.NormFloat64())
+		x.Set(i, 2, 3*rnd.Float64())
+		w[i] = 0.5 + rnd.Float64()
+	}
+	sumW := floats.Sum(w)
+
+	var h mat.SymDense
+	SilvermanBandwidthMatrix(&h, x, w)
+	m, ok := NewMultivariate(x, w, Gaussian{}, &h, rand.NewSource(1))
+	if !ok {
+		t.Fatal("unexpected failure for positive definite bandwidth")
+	}
+	if m.Dim() != d {
+		t.Errorf("unexpected dimension: got %d want %d", m.Dim(), d)
+	}
+	var got mat.SymDense
+	m.Bandwidth(&got)
+	if !mat.EqualApprox(&got, &h, 1e-14) {
+		t.Error("unexpected bandwidth matrix")
+	}
+
+	// The Gaussian estimate is a mixture of normal
+	// distributions centered on the observations.
+	for _, p := range [][]float64{{0, 0, 0}, {1, -1, 2}, {-2, 0.5, 4}, {10, 10, 10}} {
+		var terms []float64
+		for i := 0; i < n; i++ {
+			nd, _ := distmv.NewNormal(x.RawRowView(i), &h, nil)
+			terms = append(terms, math.Log(w[i]/sumW)+nd.LogProb(p))
+		}
+		want := floats.LogSumExp(terms)
+		if got := m.LogProb(p); math.Abs(got-want) > 1e-10*math.Max(1, math.Abs(want)) {
+			t.Errorf("unexpected log density at %v: got %v want %v", p, got, want)
+		}
+		if got := m.Prob(p); math.Abs(got-math.Exp(want)) > 1e-10*math.Exp(want) {
+			t.Errorf("unexpected density at %v: got %v want %v", p, got, math.Exp(want))
+		}
+	}
+
+	// Samples have the covariance of the observations
+	// increased by H.
+	var cov, want mat.SymDense
+	stat.CovarianceMatrix(&cov, x, w)
+	want.AddSym(&cov, &h)
+	s := mat.NewDense(50000, d, nil)
+	for i := 0; i < 50000; i++ {
+		m.Rand(s.RawRowView(i))
+	}
+	var sc mat.SymDense
+	stat.CovarianceMatrix(&sc, s, nil)
+	if !mat.EqualApprox(&sc, &want, 0.05) {
+		t.Errorf("unexpected sample covariance:\ngot  %v\nwant %v", mat.Formatted(&sc), mat.Formatted(&want))
+	}
+
+	// A one-dimensional estimate matches the univariate
+	// estimate.
+	col := mat.Col(nil, 0, x)
+	for _, k := range []Kernel{Gaussian{}, Epanechnikov{}} {
+		bw := SilvermanBandwidth(col, nil)
+		u := NewUnivariate(col, nil, k, bw, nil)
+		mv, ok := NewMultivariate(mat.NewDense(n, 1, col), nil, k, mat.NewSymDense(1, []float64{bw * bw}), nil)
+		if !ok {
+			t.Fatalf("%T: unexpected failure for one-dimensional estimate", k)
+		}
+		for _, v := range []float64{-2, 0, 0.7, 3} {
+			if got, want := mv.Prob([]float64{v}), u.Prob(v); math.Abs(got-want) > 1e-12 {
+				t.Errorf("%T: mismatched one-dimensional density at %v: got %v want %v", k, v, got, want)
+			}
+		}
+	}
+
+	if _, ok := NewMultivariate(x, nil, Gaussian{}, mat.NewSymDense(d, nil), nil); ok {
+		t.Error("expected failure for singular bandwidth")
+	}
+}
+
+func TestBandwidthMatrix(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n, d = 500, 2
+	x := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			x.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	var cov mat.SymDense
+	stat.CovarianceMatrix(&cov, x, nil)
+	for _, test := range []struct {
+		name string
+		fn   func(*mat.SymDense, mat.Matrix, []float64)
+		f    float64
+	}{
+		{name: "Scott", fn: ScottBandwidthMatrix, f: math.Pow(n, -2.0/(d+4))},
+		{name: "Silverman", fn: SilvermanBandwidthMatrix, f: math.Pow(4/(float64(d+2)*n), 2.0/(d+4))},
+	} {
+		var h, want mat.SymDense
+		test.fn(&h, x, nil)
+		want.ScaleSym(test.f, &cov)
+		if !mat.EqualApprox(&h, &want, 1e-14) {
+			t.Errorf("%s: unexpected bandwidth matrix", test.name)
+		}
+	}
+
+	// The univariate rule is the square root of the one
+	// dimensional Scott matrix up to its constant.
+	col := mat.Col(nil, 0, x)
+	var h mat.SymDense
+	ScottBandwidthMatrix(&h, mat.NewDense(n, 1, col), nil)
+	if got, want := 1.06*math.Sqrt(h.At(0, 0)), ScottBandwidth(col, nil); math.Abs(got-want) > 1e-12 {
+		t.Errorf("mismatched univariate Scott bandwidth: got %v want %v", got, want)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+// Univariate is a kernel density estimate of a univariate distribution,
+//
+//	f(x) = Σ_i w_i/h * K((x - x_i)/h),
+//
+// where the weights w_i sum to one.
+type Univariate struct {
+	// x holds the sorted observations, w their
+	// normalized weights and cum the cumulative
+	// sums of w.
+	x, w, cum []float64
+
+	h   float64
+	k   Kernel
+	src rand.Source
+}
+
+// NewUnivariate returns a kernel density estimate of the observations x with
+// the kernel k and bandwidth h. If weights is nil, all the observations are
+// weighted equally, otherwise weights holds the relative weights of the
+// observations. The slices are copied. The source of random numbers for Rand
+// is src. If src is nil, the global source is used.
+//
+// NewUnivariate will panic if x is empty, if weights is not nil and has a
+// length different to x or a non-positive sum, or if h is not positive.
+func NewUnivariate(x, weights []float64, k Kernel, h float64, src rand.Source) *Univariate {
+	x, w := sortedCopy(x, weights)
+	if !(h > 0) {
+		panic("kde: non-positive bandwidth")
+	}
+	u := &Univariate{
+		x:   x,
+		w:   w,
+		cum: make([]float64, len(x)),
+		h:   h,
+		k:   k,
+		src: src,
+	}
+	floats.CumSum(u.cum, u.w)
+	return u
+}
+
+// sortedCopy returns copies of x and weights sorted by x, with the weights
+// normalized to sum to one.
+func sortedCopy(x, weights []float64) (xs, ws []float64) {
+	if len(x) == 0 {
+		panic("kde: no observations")
+	}
+	if weights != nil && len(weights) != len(x) {
+		panic("kde: mismatched observation and weight lengths")
+	}
+	xs = make([]float64, len(x))
+	copy(xs, x)
+	ws = make([]float64, len(x))
+	if weights == nil {
+		for i := range ws {
+			ws[i] = 1
+		}
+	} else {
+		copy(ws, weights)
+	}
+	stat.SortWeighted(xs, ws)
+	sum := floats.Sum(ws)
+	if !(sum > 0) {
+		panic("kde: non-positive weight sum")
+	}
+	floats.Scale(1/sum, ws)
+	return xs, ws
+}
+
+// Bandwidth returns the bandwidth of the estimate.
+func (u *Univariate) Bandwidth() float64 {
+	return u.h
+}
+
+// window returns the range of observations within the support of the kernel
+// placed at x.
+func (u *Univariate) window(x float64) (lo, hi int) {
+	r := u.k.Support() * u.h
+	if math.IsInf(r, 1) {
+		return 0, len(u.x)
+	}
+	lo = sort.SearchFloat64s(u.x, x-r)
+	hi = sort.Search(len(u.x), func(i int) bool { return u.x[i] > x+r })
+	return lo, hi
+}
+
+// Prob returns the estimated density at x.
+func (u *Univariate) Prob(x float64) float64 {
+	lo, hi := u.window(x)
+	var p float64
+	for i := lo; i < hi; i++ {
+		p += u.w[i] * u.k.Prob((x-u.x[i])/u.h)
+	}
+	return p / u.h
+}
+
+// LogProb returns the log of the estimated density at x. LogProb is
+// accurate in the tails of the estimate where Prob underflows.
+func (u *Univariate) LogProb(x float64) float64 {
+	lo, hi := u.window(x)
+	if lo == hi {
+		return math.Inf(-1)
+	}
+	terms := make([]float64, hi-lo)
+	for i := range terms {
+		terms[i] = math.Log(u.w[lo+i]) + u.k.LogProb((x-u.x[lo+i])/u.h)
+	}
+	return floats.LogSumExp(terms) - math.Log(u.h)
+}
+
+// CDF returns the estimated cumulative distribution function at x.
+func (u *Univariate) CDF(x float64) float64 {
+	lo, hi := u.window(x)
+	var p float64
+	if lo > 0 {
+		// The kernels of the observations below the
+		// window lie entirely to the left of x.
+		p = u.cum[lo-1]
+	}
+	for i := lo; i < hi; i++ {
+		p += u.w[i] * u.k.CDF((x-u.x[i])/u.h)
+	}
+	return math.Min(p, 1)
+}
+
+// ProbGrid stores the estimated density at len(dst) evenly spaced points
+// between min and max inclusive into dst, and returns dst. The points are
+// those returned by floats.Span(make([]float64, len(dst)), min, max).
+// ProbGrid will panic if len(dst) is less than two.
+func (u *Univariate) ProbGrid(dst []float64, min, max float64) []float64 {
+	if len(dst) < 2 {
+		panic("kde: grid too short")
+	}
+	floats.Span(dst, min, max)
+	for i, x := range dst {
+		dst[i] = u.Prob(x)
+	}
+	return dst
+}
+
+// Rand returns a random sample drawn from the estimate, by choosing an
+// observation with probability equal to its weight and adding a sample of
+// the kernel scaled by the bandwidth.
+func (u *Univariate) Rand() float64 {
+	var v float64
+	if u.src == nil {
+		v = rand.Float64()
+	} else {
+		v = rand.New(u.src).Float64()
+	}
+	i := sort.SearchFloat64s(u.cum, v)
+	if i == len(u.x) {
+		i--
+	}
+	return u.x[i] + u.h*u.k.Rand(u.src)
+}
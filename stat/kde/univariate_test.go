@@ -0,0 +1,145 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kde
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestUnivariate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 200)
+	w := make([]float64, len(x))
+	for i := range x {
+		// A bimodal mixture.
+		x[i] = rnd.NormFloat64()
+		if i%3 == 0 {
+			x[i] = 4 + 0.5*rnd.NormFloat64()
+		}
+		w[i] = 0.5 + rnd.Float64()
+	}
+	for _, k := range []Kernel{Gaussian{}, Epanechnikov{}} {
+		for _, weights := range [][]float64{nil, w} {
+			h := SilvermanBandwidth(x, weights)
+			u := NewUnivariate(x, weights, k, h, rand.NewSource(1))
+			if u.Bandwidth() != h {
+				t.Errorf("%T: unexpected bandwidth: got %v want %v", k, u.Bandwidth(), h)
+			}
+
+			sum := floats.Sum(w)
+			for _, v := range []float64{-3, -1, 0, 0.3, 2, 4, 5.5, 9} {
+				var want, wantCDF float64
+				for i, xi := range x {
+					wi := 1 / float64(len(x))
+					if weights != nil {
+						wi = w[i] / sum
+					}
+					want += wi * k.Prob((v-xi)/h) / h
+					wantCDF += wi * k.CDF((v-xi)/h)
+				}
+				if got := u.Prob(v); math.Abs(got-want) > 1e-14 {
+					t.Errorf("%T: unexpected density at %v: got %v want %v", k, v, got, want)
+				}
+				if got := u.CDF(v); math.Abs(got-wantCDF) > 1e-14 {
+					t.Errorf("%T: unexpected CDF at %v: got %v want %v", k, v, got, wantCDF)
+				}
+				if want > 0 {
+					if got := u.LogProb(v); math.Abs(got-math.Log(want)) > 1e-12 {
+						t.Errorf("%T: unexpected log density at %v: got %v want %v", k, v, got, math.Log(want))
+					}
+				}
+			}
+
+			// The density on a grid integrates to one.
+			grid := u.ProbGrid(make([]float64, 4001), -10, 15)
+			dx := 25.0 / 4000
+			if mass := floats.Sum(grid) * dx; math.Abs(mass-1) > 1e-6 {
+				t.Errorf("%T: unexpected mass on grid: got %v want 1", k, mass)
+			}
+			if grid[1000] != u.Prob(-10+1000*dx) {
+				t.Errorf("%T: mismatched grid density", k)
+			}
+
+			// Samples have the mean of the observations and
+			// their variance increased by h².
+			mean, variance := stat.MeanVariance(x, weights)
+			s := make([]float64, 100000)
+			for i := range s {
+				s[i] = u.Rand()
+			}
+			sm, sv := stat.MeanVariance(s, nil)
+			if math.Abs(sm-mean) > 0.05 || math.Abs(sv-(variance+h*h))/sv > 0.02 {
+				t.Errorf("%T: unexpected sample moments: got mean=%v variance=%v want mean=%v variance=%v",
+					k, sm, sv, mean, variance+h*h)
+			}
+		}
+	}
+}
+
+func TestBandwidth(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 1000)
+	for i := range x {
+		x[i] = 2 * rnd.NormFloat64()
+	}
+	std := stat.StdDev(x, nil)
+	if got, want := ScottBandwidth(x, nil), 1.06*std*math.Pow(1000, -0.2); math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected Scott bandwidth: got %v want %v", got, want)
+	}
+	// For normal data the interquartile range is about
+	// 1.349 standard deviations.
+	silverman := SilvermanBandwidth(x, nil)
+	if want := 0.9 * std * math.Pow(1000, -0.2); math.Abs(silverman-want) > 0.05*want {
+		t.Errorf("unexpected Silverman bandwidth: got %v want about %v", silverman, want)
+	}
+
+	// Weights are frequencies, so doubling the weights
+	// is the same as repeating the observations.
+	w := make([]float64, len(x))
+	for i := range w {
+		w[i] = 2
+	}
+	xx := append(append([]float64(nil), x...), x...)
+	if got, want := SilvermanBandwidth(x, w), SilvermanBandwidth(xx, nil); math.Abs(got-want) > 1e-12 {
+		t.Errorf("unexpected weighted Silverman bandwidth: got %v want %v", got, want)
+	}
+	if got, want := ScottBandwidth(x, w), ScottBandwidth(xx, nil); math.Abs(got-want) > 1e-12 {
+		t.Errorf("unexpected weighted Scott bandwidth: got %v want %v", got, want)
+	}
+
+	// Cross-validation approximates the optimal bandwidth
+	// for normal data.
+	for _, k := range []Kernel{Gaussian{}, Epanechnikov{}} {
+		h := CrossValidatedBandwidth(x, nil, k)
+		if want := 1.06 * 2 * math.Pow(1000, -0.2); !(0.5*want < h && h < 2*want) {
+			t.Errorf("%T: unexpected cross-validated bandwidth: got %v want about %v", k, h, want)
+		}
+		cv := looLikelihood(sortCopy(x), uniformWeights(len(x)), k, h)
+		for _, f := range []float64{0.9, 1.1} {
+			if v := looLikelihood(sortCopy(x), uniformWeights(len(x)), k, f*h); v > cv {
+				t.Errorf("%T: bandwidth %v is not a local optimum: score %v < %v at %v", k, h, cv, v, f*h)
+			}
+		}
+	}
+	if h := CrossValidatedBandwidth([]float64{1, 1, 1}, nil, Gaussian{}); h != 0 {
+		t.Errorf("unexpected bandwidth for data without spread: got %v want 0", h)
+	}
+}
+
+func uniformWeights(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1 / float64(n)
+	}
+	return w
+}
@@ -0,0 +1,13 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package regression provides regression models beyond the simple linear
+// regression of package stat.
+//
+// Models are fitted to observations held in the rows of a design matrix x
+// with responses in a slice y, and may be weighted. The design matrix is
+// used as given, so a model with an intercept must include a column of ones
+// in x, except for the penalized regressions, which fit an unpenalized
+// intercept when requested by their settings.
+package regression // import "gonum.org/v1/gonum/stat/regression"
@@ -0,0 +1,154 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import "math"
+
+// Family is the distribution of the response of a generalized linear model
+// together with its link function g, which relates the mean μ of the
+// response to the linear predictor η = g(μ).
+type Family interface {
+	// Link returns the linear predictor
+	// η = g(μ) of the mean mu.
+	Link(mu float64) float64
+
+	// LinkDeriv returns the derivative of
+	// the link function, dη/dμ, at mu.
+	LinkDeriv(mu float64) float64
+
+	// Mean returns the mean μ = g⁻¹(η) of
+	// the linear predictor eta.
+	Mean(eta float64) float64
+
+	// Variance returns the variance function
+	// V(μ) of the family at mu, so that the
+	// variance of the response is φ*V(μ) for
+	// the dispersion φ.
+	Variance(mu float64) float64
+
+	// Deviance returns the unit deviance of
+	// the response y at the mean mu, twice
+	// the log likelihood ratio of the
+	// saturated model. Deviance returns NaN
+	// or ±Inf if y or mu is not valid for
+	// the family.
+	Deviance(y, mu float64) float64
+
+	// FixedDispersion returns whether the
+	// dispersion of the family is fixed at
+	// one rather than estimated.
+	FixedDispersion() bool
+}
+
+var (
+	_ Family = Binomial{}
+	_ Family = Poisson{}
+	_ Family = Gamma{}
+)
+
+// Binomial is the binomial family with the canonical logit link,
+//
+//	η = log(μ / (1-μ)),
+//
+// for responses in [0, 1], which gives logistic regression. A response
+// that is the proportion of successes in m trials has weight m.
+type Binomial struct{}
+
+// Link returns the logit of mu.
+func (Binomial) Link(mu float64) float64 { return math.Log(mu / (1 - mu)) }
+
+// LinkDeriv returns the derivative of the logit at mu.
+func (Binomial) LinkDeriv(mu float64) float64 { return 1 / (mu * (1 - mu)) }
+
+// Mean returns the logistic function of eta.
+func (Binomial) Mean(eta float64) float64 {
+	if eta < 0 {
+		e := math.Exp(eta)
+		return e / (1 + e)
+	}
+	return 1 / (1 + math.Exp(-eta))
+}
+
+// Variance returns μ(1-μ).
+func (Binomial) Variance(mu float64) float64 { return mu * (1 - mu) }
+
+// Deviance returns the unit deviance of the binomial family.
+func (Binomial) Deviance(y, mu float64) float64 {
+	if y < 0 || y > 1 || mu < 0 || mu > 1 {
+		return math.NaN()
+	}
+	return 2 * (xlogy(y, y/mu) + xlogy(1-y, (1-y)/(1-mu)))
+}
+
+// FixedDispersion returns true.
+func (Binomial) FixedDispersion() bool { return true }
+
+// Poisson is the Poisson family with the canonical log link,
+//
+//	η = log(μ),
+//
+// for non-negative count responses.
+type Poisson struct{}
+
+// Link returns the log of mu.
+func (Poisson) Link(mu float64) float64 { return math.Log(mu) }
+
+// LinkDeriv returns 1/mu.
+func (Poisson) LinkDeriv(mu float64) float64 { return 1 / mu }
+
+// Mean returns the exponential of eta.
+func (Poisson) Mean(eta float64) float64 { return math.Exp(eta) }
+
+// Variance returns μ.
+func (Poisson) Variance(mu float64) float64 { return mu }
+
+// Deviance returns the unit deviance of the Poisson family.
+func (Poisson) Deviance(y, mu float64) float64 {
+	if y < 0 || mu < 0 {
+		return math.NaN()
+	}
+	return 2 * (xlogy(y, y/mu) - (y - mu))
+}
+
+// FixedDispersion returns true.
+func (Poisson) FixedDispersion() bool { return true }
+
+// Gamma is the gamma family with the canonical inverse link,
+//
+//	η = 1/μ,
+//
+// for positive responses with a constant coefficient of variation.
+type Gamma struct{}
+
+// Link returns 1/mu.
+func (Gamma) Link(mu float64) float64 { return 1 / mu }
+
+// LinkDeriv returns -1/mu².
+func (Gamma) LinkDeriv(mu float64) float64 { return -1 / (mu * mu) }
+
+// Mean returns 1/eta.
+func (Gamma) Mean(eta float64) float64 { return 1 / eta }
+
+// Variance returns μ².
+func (Gamma) Variance(mu float64) float64 { return mu * mu }
+
+// Deviance returns the unit deviance of the gamma family.
+func (Gamma) Deviance(y, mu float64) float64 {
+	if y <= 0 || mu <= 0 {
+		return math.NaN()
+	}
+	return 2 * (-math.Log(y/mu) + (y-mu)/mu)
+}
+
+// FixedDispersion returns false.
+func (Gamma) FixedDispersion() bool { return false }
+
+// xlogy returns x*log(y), with a value of zero when x is zero.
+func xlogy(x, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+	return x * math.Log(y)
+}
@@ -0,0 +1,347 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	// ErrIterationLimit is returned when a model is not fitted
+	// to the required tolerance within the iteration limit.
+	ErrIterationLimit = errors.New("regression: iteration limit reached")
+
+	// ErrNoValidFit is returned when no valid model can be
+	// fitted, such as when the fit of a generalized linear
+	// model leaves the domain of its family or when RANSAC
+	// cannot fit any subset of the observations.
+	ErrNoValidFit = errors.New("regression: no valid coefficients found")
+)
+
+// maxHalvings is the maximum number of times an iteratively reweighted
+// least squares step is halved to return to the domain of a family.
+const maxHalvings = 30
+
+// GLMSettings holds the settings for FitGLM. See the field comments for
+// default values.
+type GLMSettings struct {
+	// MaxIterations is the maximum number of
+	// iteratively reweighted least squares
+	// iterations. If MaxIterations is zero, a
+	// default of 100 is used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance on
+	// the change in deviance in an iteration,
+	// relative to the deviance plus 0.1. If
+	// Tolerance is zero, a default of 1e-8 is
+	// used.
+	Tolerance float64
+}
+
+// GLM is a generalized linear model fitted by FitGLM,
+//
+//	g(E[y]) = xᵀ * β,
+//
+// where g is the link function of the family of the response y.
+type GLM struct {
+	family Family
+	coef   []float64
+
+	// cov is the covariance matrix of the
+	// coefficients for unit dispersion.
+	cov mat.SymDense
+
+	dispersion float64
+	deviance   float64
+	dof        int
+	iterations int
+}
+
+// FitGLM fits the generalized linear model of the family f to the responses
+// y and the observations in the rows of the design matrix x by the
+// iteratively reweighted least squares algorithm, giving the maximum
+// likelihood estimate of the coefficients, see McCullagh and Nelder,
+// "Generalized Linear Models", Chapman and Hall, 1989. If weights is nil,
+// all the observations are weighted equally, otherwise weights holds the
+// non-negative prior weights of the observations. If settings is nil, the
+// default settings are used.
+//
+// Each iteration solves a weighted least squares problem using a QR
+// factorization. If the design matrix is rank deficient, FitGLM returns a
+// nil model and a mat.Condition error. If an iteration leaves the domain of
+// the family, the step is halved until it returns, and if the first
+// iteration leaves the domain FitGLM returns a nil model and ErrNoValidFit.
+// If the deviance has not converged within the iteration limit, FitGLM
+// returns the last model and ErrIterationLimit.
+//
+// FitGLM panics if x has fewer rows than columns, if the length of y or of
+// a non-nil weights is not the number of rows of x, if a weight is negative,
+// if a response is not valid for the family or if the settings are invalid.
+func FitGLM(x mat.Matrix, y, weights []float64, f Family, settings *GLMSettings) (*GLM, error) {
+	n, p := x.Dims()
+	if n < p || p == 0 {
+		panic("regression: fewer observations than coefficients")
+	}
+	if len(y) != n {
+		panic("regression: mismatched response length")
+	}
+	if weights != nil && len(weights) != n {
+		panic("regression: mismatched weights length")
+	}
+	var s GLMSettings
+	if settings != nil {
+		s = *settings
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 100
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = 1e-8
+	}
+	if s.MaxIterations < 0 || s.Tolerance < 0 {
+		panic("regression: invalid GLM settings")
+	}
+
+	pw := weights
+	if pw == nil {
+		pw = make([]float64, n)
+		for i := range pw {
+			pw[i] = 1
+		}
+	}
+	var sumW, mean float64
+	nonZero := 0
+	for i, w := range pw {
+		if w < 0 {
+			panic("regression: negative weight")
+		}
+		if w > 0 {
+			nonZero++
+		}
+		sumW += w
+		mean += w * y[i]
+	}
+	mean /= sumW
+
+	// Start from the responses moved towards their mean,
+	// which lies in the domain of each family.
+	mu := make([]float64, n)
+	eta := mat.NewVecDense(n, nil)
+	for i, v := range y {
+		mu[i] = (v + mean) / 2
+		eta.SetVec(i, f.Link(mu[i]))
+	}
+	dev := deviance(f, y, mu, pw)
+	if math.IsNaN(dev) || math.IsInf(dev, 0) {
+		panic("regression: invalid response for family")
+	}
+
+	xd := mat.DenseCopyOf(x)
+	a := mat.NewDense(n, p, nil)
+	z := mat.NewVecDense(n, nil)
+	var (
+		qr         mat.QR
+		beta, next mat.VecDense
+		err        error
+	)
+	g := &GLM{family: f}
+	for g.iterations < s.MaxIterations {
+		g.iterations++
+
+		// Solve the weighted least squares problem for the
+		// working response linearized about the current mean.
+		for i := 0; i < n; i++ {
+			d := f.LinkDeriv(mu[i])
+			sw := math.Sqrt(pw[i] / (f.Variance(mu[i]) * d * d))
+			z.SetVec(i, sw*(eta.AtVec(i)+(y[i]-mu[i])*d))
+			row := a.RawRowView(i)
+			copy(row, xd.RawRowView(i))
+			floats.Scale(sw, row)
+		}
+		qr.Factorize(a)
+		if err := qr.SolveVecTo(&next, false, z); err != nil {
+			return nil, err
+		}
+
+		var devNext float64
+		for h := 0; ; h++ {
+			eta.MulVec(xd, &next)
+			for i := range mu {
+				mu[i] = f.Mean(eta.AtVec(i))
+			}
+			devNext = deviance(f, y, mu, pw)
+			if !math.IsNaN(devNext) && !math.IsInf(devNext, 0) {
+				break
+			}
+			if g.iterations == 1 || h == maxHalvings {
+				return nil, ErrNoValidFit
+			}
+			next.AddVec(&next, &beta)
+			next.ScaleVec(0.5, &next)
+		}
+		beta.CloneVec(&next)
+		converged := math.Abs(devNext-dev) < s.Tolerance*(math.Abs(devNext)+0.1)
+		dev = devNext
+		if converged {
+			break
+		}
+		if g.iterations == s.MaxIterations {
+			err = ErrIterationLimit
+		}
+	}
+
+	// Compute the covariance of the coefficients from the
+	// Fisher information at the final mean.
+	var info mat.SymDense
+	for i := 0; i < n; i++ {
+		d := f.LinkDeriv(mu[i])
+		row := a.RawRowView(i)
+		copy(row, xd.RawRowView(i))
+		floats.Scale(math.Sqrt(pw[i]/(f.Variance(mu[i])*d*d)), row)
+	}
+	info.SymOuterK(1, a.T())
+	var chol mat.Cholesky
+	if !chol.Factorize(&info) {
+		return nil, mat.Condition(math.Inf(1))
+	}
+	if err := chol.InverseTo(&g.cov); err != nil {
+		return nil, err
+	}
+
+	g.coef = make([]float64, p)
+	for j := range g.coef {
+		g.coef[j] = beta.AtVec(j)
+	}
+	g.deviance = dev
+	g.dof = nonZero - p
+	g.dispersion = 1
+	if !f.FixedDispersion() {
+		// Estimate the dispersion by the Pearson
+		// statistic over the residual degrees of
+		// freedom.
+		var chi2 float64
+		for i, v := range y {
+			if pw[i] == 0 {
+				continue
+			}
+			r := v - mu[i]
+			chi2 += pw[i] * r * r / f.Variance(mu[i])
+		}
+		g.dispersion = chi2 / float64(g.dof)
+	}
+	return g, err
+}
+
+// deviance returns the weighted sum of the unit deviances of f for the
+// responses y at the means mu.
+func deviance(f Family, y, mu, w []float64) float64 {
+	var dev float64
+	for i, v := range y {
+		if w[i] != 0 {
+			dev += w[i] * f.Deviance(v, mu[i])
+		}
+	}
+	return dev
+}
+
+// Family returns the family of the model.
+func (g *GLM) Family() Family {
+	return g.family
+}
+
+// Coefficients returns the coefficients β of the model. If dst is not nil,
+// the coefficients are stored in-place into dst and returned, otherwise a new
+// slice is allocated first. Coefficients panics if dst is not nil and its
+// length is not the number of coefficients.
+func (g *GLM) Coefficients(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(g.coef))
+	}
+	if len(dst) != len(g.coef) {
+		panic("regression: slice length mismatch")
+	}
+	copy(dst, g.coef)
+	return dst
+}
+
+// CovarianceMatrix stores the estimated covariance matrix of the coefficients
+// in dst, the inverse of the Fisher information scaled by the dispersion. If
+// the dst matrix is zero-sized it will be resized to the correct dimensions,
+// otherwise dst must match the number of coefficients or CovarianceMatrix will
+// panic.
+func (g *GLM) CovarianceMatrix(dst *mat.SymDense) {
+	p := len(g.coef)
+	if dst.IsZero() {
+		*dst = *(dst.GrowSym(p).(*mat.SymDense))
+	} else if dst.Symmetric() != p {
+		panic("regression: input matrix size mismatch")
+	}
+	dst.ScaleSym(g.dispersion, &g.cov)
+}
+
+// StdErrors returns the standard errors of the coefficients. If dst is not
+// nil, the standard errors are stored in-place into dst and returned,
+// otherwise a new slice is allocated first. StdErrors panics if dst is not
+// nil and its length is not the number of coefficients.
+func (g *GLM) StdErrors(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(g.coef))
+	}
+	if len(dst) != len(g.coef) {
+		panic("regression: slice length mismatch")
+	}
+	for j := range dst {
+		dst[j] = math.Sqrt(g.dispersion * g.cov.At(j, j))
+	}
+	return dst
+}
+
+// Deviance returns the residual deviance of the model, the weighted sum of
+// the unit deviances of the observations.
+func (g *GLM) Deviance() float64 {
+	return g.deviance
+}
+
+// Dispersion returns the dispersion φ of the model. The dispersion is one
+// for families with fixed dispersion, and otherwise is estimated by the
+// Pearson χ² statistic divided by the residual degrees of freedom.
+func (g *GLM) Dispersion() float64 {
+	return g.dispersion
+}
+
+// DegreesOfFreedom returns the residual degrees of freedom of the model,
+// the number of observations with non-zero weight less the number of
+// coefficients.
+func (g *GLM) DegreesOfFreedom() int {
+	return g.dof
+}
+
+// Iterations returns the number of iteratively reweighted least squares
+// iterations used to fit the model.
+func (g *GLM) Iterations() int {
+	return g.iterations
+}
+
+// LinearPredictor returns the linear predictor η = xᵀ * β for the
+// observation x. LinearPredictor panics if the length of x is not the
+// number of coefficients.
+func (g *GLM) LinearPredictor(x []float64) float64 {
+	if len(x) != len(g.coef) {
+		panic("regression: slice length mismatch")
+	}
+	return floats.Dot(x, g.coef)
+}
+
+// Predict returns the predicted mean response μ = g⁻¹(xᵀ * β) for the
+// observation x. Predict panics if the length of x is not the number of
+// coefficients.
+func (g *GLM) Predict(x []float64) float64 {
+	return g.family.Mean(g.LinearPredictor(x))
+}
@@ -0,0 +1,201 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestGLMDobson(t *testing.T) {
+	t.Parallel()
+	// Poisson regression example of Dobson, "An Introduction
+	// to Generalized Linear Models", 1990, with reference values
+	// from R's glm.
+	counts := []float64{18, 17, 15, 20, 10, 20, 25, 13, 12}
+	x := mat.NewDense(9, 5, nil)
+	for i := 0; i < 9; i++ {
+		x.Set(i, 0, 1)
+		if outcome := i % 3; outcome > 0 {
+			x.Set(i, outcome, 1)
+		}
+		if treatment := i / 3; treatment > 0 {
+			x.Set(i, 2+treatment, 1)
+		}
+	}
+	g, err := FitGLM(x, counts, nil, Poisson{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantCoef := []float64{3.045, -0.4543, -0.2930, 0, 0}
+	wantSE := []float64{0.1709, 0.2022, 0.1927, 0.2000, 0.2000}
+	if !floats.EqualApprox(g.Coefficients(nil), wantCoef, 1e-3) {
+		t.Errorf("unexpected coefficients: got %v want %v", g.Coefficients(nil), wantCoef)
+	}
+	if !floats.EqualApprox(g.StdErrors(nil), wantSE, 1e-4) {
+		t.Errorf("unexpected standard errors: got %v want %v", g.StdErrors(nil), wantSE)
+	}
+	if math.Abs(g.Deviance()-5.1291) > 1e-4 {
+		t.Errorf("unexpected deviance: got %v want 5.1291", g.Deviance())
+	}
+	if g.DegreesOfFreedom() != 4 {
+		t.Errorf("unexpected degrees of freedom: got %d want 4", g.DegreesOfFreedom())
+	}
+	if g.Dispersion() != 1 {
+		t.Errorf("unexpected dispersion: got %v want 1", g.Dispersion())
+	}
+}
+
+func TestGLM(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name   string
+		family Family
+		coef   []float64
+		sample func(mu, w float64) float64
+	}{
+		{
+			name:   "binomial",
+			family: Binomial{},
+			coef:   []float64{-0.5, 1, -2},
+			sample: func(mu, _ float64) float64 {
+				if rnd.Float64() < mu {
+					return 1
+				}
+				return 0
+			},
+		},
+		{
+			name:   "poisson",
+			family: Poisson{},
+			coef:   []float64{1, 0.5, -0.3},
+			sample: func(mu, _ float64) float64 {
+				return distuv.Poisson{Lambda: mu, Src: rnd}.Rand()
+			},
+		},
+		{
+			name:   "gamma",
+			family: Gamma{},
+			coef:   []float64{2, 0.3, 0.2},
+			sample: func(mu, w float64) float64 {
+				// Shape 4 gives a dispersion of 1/4 with the
+				// variance scaled by the prior weight.
+				return distuv.Gamma{Alpha: 4 * w, Beta: 4 * w / mu, Src: rnd}.Rand()
+			},
+		},
+	} {
+		const n = 2000
+		p := len(test.coef)
+		x := mat.NewDense(n, p, nil)
+		y := make([]float64, n)
+		w := make([]float64, n)
+		for i := 0; i < n; i++ {
+			x.Set(i, 0, 1)
+			for j := 1; j < p; j++ {
+				x.Set(i, j, rnd.Float64()*2-1)
+			}
+			w[i] = float64(1 + rnd.Intn(3))
+			y[i] = test.sample(test.family.Mean(floats.Dot(x.RawRowView(i), test.coef)), w[i])
+		}
+		g, err := FitGLM(x, y, w, test.family, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		// The coefficients solve the score equations.
+		score := make([]float64, p)
+		for i := 0; i < n; i++ {
+			xi := x.RawRowView(i)
+			mu := g.Predict(xi)
+			d := test.family.LinkDeriv(mu)
+			floats.AddScaled(score, w[i]*(y[i]-mu)/(test.family.Variance(mu)*d), xi)
+		}
+		if floats.Norm(score, math.Inf(1)) > 1e-6*n {
+			t.Errorf("%s: score not zero at the estimate: %v", test.name, score)
+		}
+
+		// The estimates are close to the generating coefficients.
+		coef := g.Coefficients(nil)
+		se := g.StdErrors(nil)
+		for j, c := range test.coef {
+			if math.Abs(coef[j]-c) > 4*se[j] {
+				t.Errorf("%s: coefficient %d far from generating value: got %v±%v want %v", test.name, j, coef[j], se[j], c)
+			}
+		}
+		if test.name == "gamma" && math.Abs(g.Dispersion()-0.25) > 0.03 {
+			t.Errorf("%s: unexpected dispersion: got %v want 0.25", test.name, g.Dispersion())
+		}
+		var cov mat.SymDense
+		g.CovarianceMatrix(&cov)
+		for j := range se {
+			if math.Abs(cov.At(j, j)-se[j]*se[j]) > 1e-14 {
+				t.Errorf("%s: standard error %d does not match covariance", test.name, j)
+			}
+		}
+
+		// Integer weights are equivalent to repeated observations.
+		var rep []float64
+		var ry []float64
+		for i, v := range w {
+			for k := 0; k < int(v); k++ {
+				rep = append(rep, x.RawRowView(i)...)
+				ry = append(ry, y[i])
+			}
+		}
+		gr, err := FitGLM(mat.NewDense(len(ry), p, rep), ry, nil, test.family, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error for repeated observations: %v", test.name, err)
+		}
+		if !floats.EqualApprox(gr.Coefficients(nil), coef, 1e-8) {
+			t.Errorf("%s: weighted fit does not match repeated observations", test.name)
+		}
+		if math.Abs(gr.Deviance()-g.Deviance()) > 1e-8*g.Deviance() {
+			t.Errorf("%s: weighted deviance does not match repeated observations", test.name)
+		}
+	}
+}
+
+func TestGLMErrors(t *testing.T) {
+	t.Parallel()
+	// Collinear columns.
+	x := mat.NewDense(4, 2, []float64{1, 2, 1, 2, 1, 2, 1, 2})
+	if _, err := FitGLM(x, []float64{1, 2, 3, 4}, nil, Poisson{}, nil); err == nil {
+		t.Error("expected error for rank deficient design")
+	}
+
+	x = mat.NewDense(4, 2, []float64{1, 0, 1, 1, 1, 2, 1, 3})
+	g, err := FitGLM(x, []float64{0, 1, 0, 1}, nil, Binomial{}, &GLMSettings{MaxIterations: 1})
+	if err != ErrIterationLimit {
+		t.Errorf("unexpected error for iteration limit: got %v want %v", err, ErrIterationLimit)
+	}
+	if g == nil || g.Iterations() != 1 {
+		t.Error("expected model at iteration limit")
+	}
+
+	for _, test := range []struct {
+		family Family
+		y      []float64
+	}{
+		{family: Binomial{}, y: []float64{0, 1, 2, 1}},
+		{family: Poisson{}, y: []float64{1, -1, 2, 3}},
+		{family: Gamma{}, y: []float64{1, 0, 2, 3}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected panic for invalid response %v for %T", test.y, test.family)
+				}
+			}()
+			FitGLM(x, test.y, nil, test.family, nil)
+		}()
+	}
+}
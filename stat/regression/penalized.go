@@ -0,0 +1,418 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// LinearModel is a fitted linear regression model
+//
+//	y = b₀ + xᵀ * β.
+type LinearModel struct {
+	// Intercept is the intercept b₀, which
+	// is zero if no intercept was fitted.
+	Intercept float64
+
+	// Coefficients holds the coefficients β.
+	Coefficients []float64
+
+	// Lambda is the regularization parameter
+	// of the fit.
+	Lambda float64
+
+	// Iterations is the number of coordinate
+	// descent sweeps used in the fit, or zero
+	// for closed form fits.
+	Iterations int
+}
+
+// Predict returns the predicted response for the observation x. Predict
+// panics if the length of x is not the number of coefficients.
+func (m *LinearModel) Predict(x []float64) float64 {
+	if len(x) != len(m.Coefficients) {
+		panic("regression: slice length mismatch")
+	}
+	return m.Intercept + floats.Dot(x, m.Coefficients)
+}
+
+// PenalizedSettings holds the settings for the penalized linear regressions.
+// See the field comments for default values.
+type PenalizedSettings struct {
+	// Intercept specifies whether to fit an
+	// unpenalized intercept. If Intercept is
+	// true, the design matrix should not hold
+	// a column of ones.
+	Intercept bool
+
+	// Standardize specifies whether to scale
+	// the columns of the design matrix to unit
+	// weighted variance before fitting, so that
+	// the penalty does not depend on the units
+	// of the columns. The coefficients are
+	// returned on the original scale.
+	Standardize bool
+
+	// MaxIterations is the maximum number of
+	// coordinate descent sweeps. If MaxIterations
+	// is zero, a default of 1000 is used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance of
+	// coordinate descent on the greatest change
+	// in the objective from updating a single
+	// coefficient in a sweep, relative to the
+	// weighted variance of the responses. If
+	// Tolerance is zero, a default of 1e-7 is
+	// used.
+	Tolerance float64
+}
+
+// Ridge returns the ridge regression of the responses y on the observations
+// in the rows of the design matrix x, the coefficients minimizing
+//
+//	1/2 * Σ_i w_i*(y_i - b₀ - x_iᵀ*β)² + λ/2 * ||β||²,
+//
+// where the weights w, given by weights or equal if weights is nil, are
+// normalized to sum to one. The solution is computed in closed form from the
+// singular value decomposition of the weighted design matrix, so only the
+// Intercept and Standardize settings are used. If settings is nil, the
+// default settings are used.
+//
+// Ridge panics if the length of y or of a non-nil weights is not the number
+// of rows of x, if a weight is negative, or if lambda is negative.
+func Ridge(x mat.Matrix, y, weights []float64, lambda float64, settings *PenalizedSettings) *LinearModel {
+	return RidgePath(x, y, weights, []float64{lambda}, settings)[0]
+}
+
+// RidgePath returns the ridge regressions of the responses y on the
+// observations in the rows of the design matrix x for each of the
+// regularization parameters in lambdas, as described for Ridge. The singular
+// value decomposition of the design matrix is computed once for the path.
+//
+// RidgePath panics if the length of y or of a non-nil weights is not the
+// number of rows of x, if a weight is negative, if lambdas is empty or if
+// a lambda is negative.
+func RidgePath(x mat.Matrix, y, weights, lambdas []float64, settings *PenalizedSettings) []*LinearModel {
+	if len(lambdas) == 0 {
+		panic("regression: no regularization parameters")
+	}
+	for _, l := range lambdas {
+		if l < 0 {
+			panic("regression: negative regularization parameter")
+		}
+	}
+	d := newDesign(x, y, weights, settings)
+
+	// Factorize the weighted design matrix W^{1/2} * X.
+	a := mat.NewDense(d.n, d.p, nil)
+	wy := mat.NewVecDense(d.n, nil)
+	for i := 0; i < d.n; i++ {
+		sw := math.Sqrt(d.w[i])
+		for j := 0; j < d.p; j++ {
+			a.Set(i, j, sw*d.xt.At(j, i))
+		}
+		wy.SetVec(i, sw*d.y[i])
+	}
+	var svd mat.SVD
+	if !svd.Factorize(a, mat.SVDThin) {
+		panic("regression: singular value decomposition failed")
+	}
+	values := svd.Values(nil)
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	var uty mat.VecDense
+	uty.MulVec(u.T(), wy)
+
+	models := make([]*LinearModel, len(lambdas))
+	beta := mat.NewVecDense(d.p, nil)
+	scaled := mat.NewVecDense(len(values), nil)
+	for k, l := range lambdas {
+		for i, s := range values {
+			var f float64
+			if s*s+l != 0 {
+				f = s / (s*s + l)
+			}
+			scaled.SetVec(i, f*uty.AtVec(i))
+		}
+		beta.MulVec(&v, scaled)
+		models[k] = d.model(beta.RawVector().Data, l, 0)
+	}
+	return models
+}
+
+// Lasso returns the lasso regression of the responses y on the observations
+// in the rows of the design matrix x, the elastic net regression with alpha
+// equal to one. See ElasticNet for details.
+func Lasso(x mat.Matrix, y, weights []float64, lambda float64, settings *PenalizedSettings) (*LinearModel, error) {
+	return ElasticNet(x, y, weights, lambda, 1, settings)
+}
+
+// ElasticNet returns the elastic net regression of the responses y on the
+// observations in the rows of the design matrix x, the coefficients
+// minimizing
+//
+//	1/2 * Σ_i w_i*(y_i - b₀ - x_iᵀ*β)² + λ*((1-α)/2 * ||β||² + α*||β||₁),
+//
+// where the weights w, given by weights or equal if weights is nil, are
+// normalized to sum to one. An alpha of one gives the lasso, and an alpha of
+// zero gives ridge regression. The coefficients are found by cyclic
+// coordinate descent, see Friedman et al., "Regularization paths for
+// generalized linear models via coordinate descent", J. Stat. Softw. 33(1),
+// 2010. If settings is nil, the default settings are used.
+//
+// If coordinate descent does not converge within the iteration limit,
+// ElasticNet returns the last model and ErrIterationLimit.
+//
+// ElasticNet panics if the length of y or of a non-nil weights is not the
+// number of rows of x, if a weight is negative, if lambda is negative or if
+// alpha is not in [0, 1].
+func ElasticNet(x mat.Matrix, y, weights []float64, lambda, alpha float64, settings *PenalizedSettings) (*LinearModel, error) {
+	models, err := ElasticNetPath(x, y, weights, []float64{lambda}, alpha, settings)
+	return models[0], err
+}
+
+// ElasticNetPath returns the elastic net regressions of the responses y on
+// the observations in the rows of the design matrix x for each of the
+// regularization parameters in lambdas, as described for ElasticNet. Each fit
+// is started from the coefficients of the previous, so lambdas should be in
+// decreasing order.
+//
+// If lambdas is nil, a path of 100 values decreasing on a log scale from
+// the smallest lambda for which all the coefficients are zero to 1e-3 times
+// that value is used. The regularization parameter of each model is held in
+// its Lambda field.
+//
+// If coordinate descent does not converge within the iteration limit for a
+// value of lambda, ElasticNetPath continues along the path and returns the
+// models and ErrIterationLimit.
+//
+// ElasticNetPath panics if the length of y or of a non-nil weights is not the
+// number of rows of x, if a weight is negative, if lambdas is not nil and is
+// empty or holds a negative value, if alpha is not in [0, 1], or if lambdas
+// is nil and alpha is zero.
+func ElasticNetPath(x mat.Matrix, y, weights, lambdas []float64, alpha float64, settings *PenalizedSettings) ([]*LinearModel, error) {
+	if alpha < 0 || alpha > 1 {
+		panic("regression: alpha out of range")
+	}
+	if lambdas != nil && len(lambdas) == 0 {
+		panic("regression: no regularization parameters")
+	}
+	for _, l := range lambdas {
+		if l < 0 {
+			panic("regression: negative regularization parameter")
+		}
+	}
+	var s PenalizedSettings
+	if settings != nil {
+		s = *settings
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 1000
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = 1e-7
+	}
+	if s.MaxIterations < 0 || s.Tolerance < 0 {
+		panic("regression: invalid penalized regression settings")
+	}
+	d := newDesign(x, y, weights, &s)
+
+	// v holds the weighted sums of squares of the columns
+	// and r the residuals of the current coefficients.
+	v := make([]float64, d.p)
+	for j := range v {
+		for i, xij := range d.xt.RawRowView(j) {
+			v[j] += d.w[i] * xij * xij
+		}
+	}
+	r := append([]float64(nil), d.y...)
+	var variance float64
+	for i, ri := range r {
+		variance += d.w[i] * ri * ri
+	}
+
+	if lambdas == nil {
+		if alpha == 0 {
+			panic("regression: no default path for zero alpha")
+		}
+		var max float64
+		for j := 0; j < d.p; j++ {
+			max = math.Max(max, math.Abs(weightedDot(d.w, d.xt.RawRowView(j), r)))
+		}
+		max /= alpha
+		lambdas = make([]float64, 100)
+		if max == 0 {
+			max = 1
+		}
+		floats.LogSpan(lambdas, max, 1e-3*max)
+	}
+
+	var err error
+	models := make([]*LinearModel, len(lambdas))
+	beta := make([]float64, d.p)
+	tol := s.Tolerance * variance
+	for k, l := range lambdas {
+		l1 := l * alpha
+		l2 := l * (1 - alpha)
+		var iter int
+		converged := false
+		for iter < s.MaxIterations {
+			iter++
+			var maxChange float64
+			for j, bj := range beta {
+				if v[j] == 0 {
+					continue
+				}
+				xj := d.xt.RawRowView(j)
+				g := weightedDot(d.w, xj, r) + v[j]*bj
+				b := softThreshold(g, l1) / (v[j] + l2)
+				if b == bj {
+					continue
+				}
+				delta := b - bj
+				floats.AddScaled(r, -delta, xj)
+				beta[j] = b
+				maxChange = math.Max(maxChange, v[j]*delta*delta)
+			}
+			if maxChange <= tol {
+				converged = true
+				break
+			}
+		}
+		if !converged {
+			err = ErrIterationLimit
+		}
+		models[k] = d.model(beta, l, iter)
+	}
+	return models, err
+}
+
+// design is a design matrix and response prepared for penalized
+// regression, centered if an intercept is fitted and standardized if
+// requested.
+type design struct {
+	n, p int
+
+	// xt holds the prepared design matrix
+	// transposed, so that its columns are
+	// contiguous.
+	xt *mat.Dense
+
+	// w holds the normalized weights and y
+	// the prepared responses.
+	w []float64
+	y []float64
+
+	// xMean, xScale and yMean are the shifts
+	// and scales of the preparation.
+	xMean, xScale []float64
+	yMean         float64
+}
+
+// newDesign returns the design matrix x and responses y with the given
+// weights prepared according to the settings s.
+func newDesign(x mat.Matrix, y, weights []float64, s *PenalizedSettings) *design {
+	n, p := x.Dims()
+	if n == 0 || p == 0 {
+		panic("regression: no observations")
+	}
+	if len(y) != n {
+		panic("regression: mismatched response length")
+	}
+	if weights != nil && len(weights) != n {
+		panic("regression: mismatched weights length")
+	}
+	var intercept, standardize bool
+	if s != nil {
+		intercept, standardize = s.Intercept, s.Standardize
+	}
+	d := &design{
+		n:      n,
+		p:      p,
+		xt:     mat.DenseCopyOf(x.T()),
+		w:      make([]float64, n),
+		y:      make([]float64, n),
+		xMean:  make([]float64, p),
+		xScale: make([]float64, p),
+	}
+	for i := range d.w {
+		d.w[i] = 1
+		if weights != nil {
+			if weights[i] < 0 {
+				panic("regression: negative weight")
+			}
+			d.w[i] = weights[i]
+		}
+	}
+	sum := floats.Sum(d.w)
+	if sum == 0 {
+		panic("regression: zero total weight")
+	}
+	floats.Scale(1/sum, d.w)
+
+	copy(d.y, y)
+	if intercept {
+		d.yMean = floats.Dot(d.w, d.y)
+		floats.AddConst(-d.yMean, d.y)
+	}
+	for j := 0; j < p; j++ {
+		xj := d.xt.RawRowView(j)
+		if intercept {
+			d.xMean[j] = floats.Dot(d.w, xj)
+			floats.AddConst(-d.xMean[j], xj)
+		}
+		d.xScale[j] = 1
+		if standardize {
+			if sd := math.Sqrt(weightedDot(d.w, xj, xj)); sd > 0 {
+				d.xScale[j] = sd
+				floats.Scale(1/sd, xj)
+			}
+		}
+	}
+	return d
+}
+
+// model returns the linear model on the original scale for the
+// coefficients beta of the prepared design.
+func (d *design) model(beta []float64, lambda float64, iter int) *LinearModel {
+	m := &LinearModel{
+		Intercept:    d.yMean,
+		Coefficients: make([]float64, d.p),
+		Lambda:       lambda,
+		Iterations:   iter,
+	}
+	for j, b := range beta {
+		m.Coefficients[j] = b / d.xScale[j]
+		m.Intercept -= d.xMean[j] * m.Coefficients[j]
+	}
+	return m
+}
+
+// weightedDot returns Σ_i w_i*x_i*y_i.
+func weightedDot(w, x, y []float64) float64 {
+	var sum float64
+	for i, v := range w {
+		sum += v * x[i] * y[i]
+	}
+	return sum
+}
+
+// softThreshold returns the soft thresholding of x at t,
+// sign(x) * max(|x| - t, 0).
+func softThreshold(x, t float64) float64 {
+	switch {
+	case x > t:
+		return x - t
+	case x < -t:
+		return x + t
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// linearData returns n observations of p normal variables with responses
+// from the linear model with intercept b0 and coefficients coef and noise
+// of the given standard deviation, and random weights.
+func linearData(n int, b0 float64, coef []float64, std float64, rnd *rand.Rand) (x *mat.Dense, y, w []float64) {
+	p := len(coef)
+	x = mat.NewDense(n, p, nil)
+	y = make([]float64, n)
+	w = make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < p; j++ {
+			x.Set(i, j, float64(j+1)*rnd.NormFloat64()+float64(j))
+		}
+		y[i] = b0 + floats.Dot(x.RawRowView(i), coef) + std*rnd.NormFloat64()
+		w[i] = 0.5 + rnd.Float64()
+	}
+	return x, y, w
+}
+
+// centeredGradient returns the gradients Xᵀ*W*r of the weighted least
+// squares loss of m with respect to the coefficients, with the weights
+// normalized to sum to one.
+func centeredGradient(m *LinearModel, x *mat.Dense, y, w []float64) []float64 {
+	n, p := x.Dims()
+	sum := floats.Sum(w)
+	g := make([]float64, p)
+	for i := 0; i < n; i++ {
+		r := y[i] - m.Predict(x.RawRowView(i))
+		floats.AddScaled(g, w[i]/sum*r, x.RawRowView(i))
+	}
+	return g
+}
+
+func TestRidge(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, p      int
+		intercept bool
+	}{
+		{n: 50, p: 5, intercept: true},
+		{n: 50, p: 5, intercept: false},
+		{n: 20, p: 40, intercept: true},
+	} {
+		coef := make([]float64, test.p)
+		for j := range coef {
+			coef[j] = rnd.NormFloat64()
+		}
+		x, y, w := linearData(test.n, 2, coef, 0.5, rnd)
+		lambdas := []float64{0.01, 0.1, 1, 10}
+		s := &PenalizedSettings{Intercept: test.intercept}
+		path := RidgePath(x, y, w, lambdas, s)
+		for k, m := range path {
+			if m.Lambda != lambdas[k] {
+				t.Errorf("unexpected lambda: got %v want %v", m.Lambda, lambdas[k])
+			}
+			if !test.intercept && m.Intercept != 0 {
+				t.Errorf("unexpected intercept without intercept: %v", m.Intercept)
+			}
+			// The gradient of the objective is zero.
+			g := centeredGradient(m, x, y, w)
+			floats.AddScaled(g, -m.Lambda, m.Coefficients)
+			if floats.Norm(g, math.Inf(1)) > 1e-10 {
+				t.Errorf("n=%d p=%d intercept=%t lambda=%v: gradient not zero: %v", test.n, test.p, test.intercept, m.Lambda, g)
+			}
+			if test.intercept {
+				var sum, rsum float64
+				for i := 0; i < test.n; i++ {
+					sum += w[i]
+					rsum += w[i] * (y[i] - m.Predict(x.RawRowView(i)))
+				}
+				if math.Abs(rsum/sum) > 1e-10 {
+					t.Errorf("n=%d p=%d lambda=%v: weighted residuals do not sum to zero", test.n, test.p, m.Lambda)
+				}
+			}
+
+			// Coordinate descent with zero alpha solves the
+			// same problem, converging slowly for small lambda
+			// and for more coefficients than observations.
+			if m.Lambda < 0.1 || test.p > test.n {
+				continue
+			}
+			cd, err := ElasticNet(x, y, w, m.Lambda, 0, &PenalizedSettings{Intercept: test.intercept, Tolerance: 1e-14, MaxIterations: 100000})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !floats.EqualApprox(cd.Coefficients, m.Coefficients, 1e-4) || math.Abs(cd.Intercept-m.Intercept) > 1e-4 {
+				t.Errorf("n=%d p=%d intercept=%t lambda=%v: coordinate descent does not match closed form", test.n, test.p, test.intercept, m.Lambda)
+			}
+		}
+	}
+}
+
+func TestElasticNet(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	coef := []float64{3, 0, 0, -1.5, 0, 0, 0, 2, 0, 0}
+	x, y, w := linearData(200, 1, coef, 0.5, rnd)
+	for _, alpha := range []float64{1, 0.5, 0.1} {
+		for _, standardize := range []bool{false, true} {
+			s := &PenalizedSettings{Intercept: true, Standardize: standardize, Tolerance: 1e-14}
+			path, err := ElasticNetPath(x, y, w, nil, alpha, s)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(path) != 100 {
+				t.Fatalf("unexpected path length: got %d want 100", len(path))
+			}
+			for _, b := range path[0].Coefficients {
+				if math.Abs(b) > 1e-12 {
+					t.Errorf("alpha=%v standardize=%t: non-zero coefficient at the start of the path", alpha, standardize)
+					break
+				}
+			}
+
+			for k, m := range path {
+				if k > 0 && m.Lambda >= path[k-1].Lambda {
+					t.Fatalf("path not decreasing")
+				}
+				// Check the optimality conditions in the
+				// scale of the fit.
+				g := centeredGradient(m, x, y, w)
+				for j, b := range m.Coefficients {
+					scale := 1.0
+					if standardize {
+						scale = math.Sqrt(weightedVariance(x, w, j))
+					}
+					gj := g[j]/scale - m.Lambda*(1-alpha)*b*scale
+					l1 := m.Lambda * alpha
+					if b == 0 && math.Abs(gj) > l1*(1+1e-6)+1e-9 {
+						t.Errorf("alpha=%v standardize=%t lambda=%v: zero coefficient %d violates optimality: %v > %v", alpha, standardize, m.Lambda, j, math.Abs(gj), l1)
+					}
+					if b != 0 && math.Abs(gj-math.Copysign(l1, b)) > 1e-5*l1+1e-9 {
+						t.Errorf("alpha=%v standardize=%t lambda=%v: non-zero coefficient %d violates optimality: %v != %v", alpha, standardize, m.Lambda, j, gj, math.Copysign(l1, b))
+					}
+				}
+			}
+		}
+	}
+
+	// The lasso recovers the support of sparse coefficients.
+	m, err := Lasso(x, y, w, 2, &PenalizedSettings{Intercept: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for j, c := range coef {
+		if (c == 0) != (m.Coefficients[j] == 0) {
+			t.Errorf("unexpected lasso support for coefficient %d: got %v want %v", j, m.Coefficients[j], c)
+		}
+	}
+
+	_, err = ElasticNet(x, y, w, 0.001, 1, &PenalizedSettings{Intercept: true, MaxIterations: 1})
+	if err != ErrIterationLimit {
+		t.Errorf("unexpected error for iteration limit: got %v want %v", err, ErrIterationLimit)
+	}
+}
+
+// weightedVariance returns the weighted variance of column j of x with
+// the weights normalized to sum to one.
+func weightedVariance(x *mat.Dense, w []float64, j int) float64 {
+	n, _ := x.Dims()
+	sum := floats.Sum(w)
+	var mean float64
+	for i := 0; i < n; i++ {
+		mean += w[i] / sum * x.At(i, j)
+	}
+	var v float64
+	for i := 0; i < n; i++ {
+		d := x.At(i, j) - mean
+		v += w[i] / sum * d * d
+	}
+	return v
+}
@@ -0,0 +1,543 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// madScale is the factor converting the median absolute deviation of
+// normally distributed values into an estimate of their standard
+// deviation.
+const madScale = 0.6744897501960817
+
+// Predictor is a fitted regression model.
+type Predictor interface {
+	// Predict returns the predicted
+	// response for the observation x.
+	Predict(x []float64) float64
+}
+
+var (
+	_ Predictor = (*GLM)(nil)
+	_ Predictor = (*LinearModel)(nil)
+	_ Predictor = (*RobustModel)(nil)
+)
+
+// Loss is the loss function ρ of the standardized residuals minimized by
+// M-estimation.
+type Loss interface {
+	// Loss returns ρ(u) for the
+	// standardized residual u.
+	Loss(u float64) float64
+
+	// Weight returns the iteratively
+	// reweighted least squares weight
+	// ψ(u)/u, where ψ is the derivative
+	// of ρ.
+	Weight(u float64) float64
+}
+
+var (
+	_ Loss = Huber{}
+	_ Loss = Tukey{}
+)
+
+// Huber is Huber's loss function, quadratic for residuals up to the
+// threshold K and linear beyond,
+//
+//	ρ(u) = u²/2        if |u| <= K,
+//	ρ(u) = K*|u| - K²/2 otherwise.
+//
+// If K is zero, a default of 1.345 is used, which gives 95% efficiency
+// for normally distributed errors.
+type Huber struct {
+	K float64
+}
+
+func (h Huber) k() float64 {
+	if h.K == 0 {
+		return 1.345
+	}
+	return h.K
+}
+
+// Loss returns ρ(u).
+func (h Huber) Loss(u float64) float64 {
+	k := h.k()
+	if a := math.Abs(u); a > k {
+		return k*a - k*k/2
+	}
+	return u * u / 2
+}
+
+// Weight returns ψ(u)/u.
+func (h Huber) Weight(u float64) float64 {
+	k := h.k()
+	if a := math.Abs(u); a > k {
+		return k / a
+	}
+	return 1
+}
+
+// Tukey is Tukey's bisquare loss function, which gives zero weight to
+// residuals beyond the threshold C,
+//
+//	ρ(u) = C²/6 * (1 - (1 - (u/C)²)³) if |u| <= C,
+//	ρ(u) = C²/6                      otherwise.
+//
+// If C is zero, a default of 4.685 is used, which gives 95% efficiency
+// for normally distributed errors.
+type Tukey struct {
+	C float64
+}
+
+func (t Tukey) c() float64 {
+	if t.C == 0 {
+		return 4.685
+	}
+	return t.C
+}
+
+// Loss returns ρ(u).
+func (t Tukey) Loss(u float64) float64 {
+	c := t.c()
+	if math.Abs(u) > c {
+		return c * c / 6
+	}
+	v := 1 - (u/c)*(u/c)
+	return c * c / 6 * (1 - v*v*v)
+}
+
+// Weight returns ψ(u)/u.
+func (t Tukey) Weight(u float64) float64 {
+	c := t.c()
+	if math.Abs(u) > c {
+		return 0
+	}
+	v := 1 - (u/c)*(u/c)
+	return v * v
+}
+
+// RobustSettings holds the settings for MEstimate. See the field comments
+// for default values.
+type RobustSettings struct {
+	// Scale is the scale of the residuals used to
+	// standardize them. If Scale is zero, the scale
+	// is estimated in each iteration by the median
+	// absolute residual divided by 0.6745.
+	Scale float64
+
+	// MaxIterations is the maximum number of
+	// iteratively reweighted least squares
+	// iterations. If MaxIterations is zero, a
+	// default of 100 is used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance on
+	// the greatest change in a coefficient in an
+	// iteration, relative to one plus the greatest
+	// magnitude of the coefficients. If Tolerance
+	// is zero, a default of 1e-8 is used.
+	Tolerance float64
+}
+
+// RobustModel is a linear regression model fitted by MEstimate.
+type RobustModel struct {
+	// Coefficients holds the coefficients β.
+	Coefficients []float64
+
+	// Scale is the scale of the residuals.
+	Scale float64
+
+	// Weights holds the final weight of
+	// each observation in the fit, near
+	// zero for outliers.
+	Weights []float64
+
+	// Iterations is the number of iteratively
+	// reweighted least squares iterations.
+	Iterations int
+}
+
+// Predict returns the predicted response xᵀ * β for the observation x.
+// Predict panics if the length of x is not the number of coefficients.
+func (m *RobustModel) Predict(x []float64) float64 {
+	if len(x) != len(m.Coefficients) {
+		panic("regression: slice length mismatch")
+	}
+	return floats.Dot(x, m.Coefficients)
+}
+
+// MEstimate returns the M-estimate of the coefficients β of the linear
+// model of the responses y on the observations in the rows of the design
+// matrix x, minimizing
+//
+//	Σ_i ρ((y_i - x_iᵀ*β) / s),
+//
+// for the loss function ρ and the residual scale s, by iteratively
+// reweighted least squares starting from the ordinary least squares fit, see
+// Huber and Ronchetti, "Robust Statistics", Wiley, 2009. Unlike least
+// squares, the fit is not dominated by a few outlying responses. If settings
+// is nil, the default settings are used.
+//
+// For a redescending loss such as Tukey the objective is not convex and the
+// fit depends on the starting point, so it may be improved by first fitting
+// with Huber. If the design matrix is rank deficient, MEstimate returns a nil
+// model and a mat.Condition error. If the coefficients have not converged
+// within the iteration limit, MEstimate returns the last model and
+// ErrIterationLimit.
+//
+// MEstimate panics if x has fewer rows than columns, if the length of y is
+// not the number of rows of x or if the settings are invalid.
+func MEstimate(x mat.Matrix, y []float64, loss Loss, settings *RobustSettings) (*RobustModel, error) {
+	n, p := x.Dims()
+	if n < p || p == 0 {
+		panic("regression: fewer observations than coefficients")
+	}
+	if len(y) != n {
+		panic("regression: mismatched response length")
+	}
+	var s RobustSettings
+	if settings != nil {
+		s = *settings
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 100
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = 1e-8
+	}
+	if s.Scale < 0 || s.MaxIterations < 0 || s.Tolerance < 0 {
+		panic("regression: invalid robust settings")
+	}
+
+	xd := mat.DenseCopyOf(x)
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1
+	}
+	beta, err := weightedLeastSquares(xd, y, w)
+	if err != nil {
+		return nil, err
+	}
+	m := &RobustModel{Weights: w}
+	r := make([]float64, n)
+	for m.Iterations < s.MaxIterations {
+		m.Iterations++
+		for i := range r {
+			r[i] = y[i] - floats.Dot(xd.RawRowView(i), beta)
+		}
+		m.Scale = s.Scale
+		if m.Scale == 0 {
+			m.Scale = medianAbs(r) / madScale
+		}
+		if m.Scale == 0 {
+			// At least half the observations are fitted
+			// exactly, so the others are outliers.
+			for i, v := range r {
+				w[i] = 0
+				if v == 0 {
+					w[i] = 1
+				}
+			}
+			m.Coefficients = beta
+			return m, nil
+		}
+		for i, v := range r {
+			w[i] = loss.Weight(v / m.Scale)
+		}
+		next, err := weightedLeastSquares(xd, y, w)
+		if err != nil {
+			return nil, err
+		}
+		var change, size float64
+		for j, v := range next {
+			change = math.Max(change, math.Abs(v-beta[j]))
+			size = math.Max(size, math.Abs(v))
+		}
+		beta = next
+		if change <= s.Tolerance*(1+size) {
+			m.Coefficients = beta
+			return m, nil
+		}
+	}
+	m.Coefficients = beta
+	return m, ErrIterationLimit
+}
+
+// weightedLeastSquares returns the coefficients minimizing the weighted
+// sum of squared residuals of the responses y on the rows of x.
+func weightedLeastSquares(x *mat.Dense, y, w []float64) ([]float64, error) {
+	n, p := x.Dims()
+	a := mat.NewDense(n, p, nil)
+	b := mat.NewVecDense(n, nil)
+	for i := 0; i < n; i++ {
+		sw := math.Sqrt(w[i])
+		row := a.RawRowView(i)
+		copy(row, x.RawRowView(i))
+		floats.Scale(sw, row)
+		b.SetVec(i, sw*y[i])
+	}
+	var qr mat.QR
+	qr.Factorize(a)
+	var beta mat.VecDense
+	if err := qr.SolveVecTo(&beta, false, b); err != nil {
+		return nil, err
+	}
+	return beta.RawVector().Data, nil
+}
+
+// medianAbs returns the median of the absolute values of x.
+func medianAbs(x []float64) float64 {
+	a := make([]float64, len(x))
+	for i, v := range x {
+		a[i] = math.Abs(v)
+	}
+	return median(a)
+}
+
+// Fitter fits a regression model to the responses y and the observations in
+// the rows of x.
+type Fitter func(x mat.Matrix, y []float64) (Predictor, error)
+
+// RANSACSettings holds the settings for RANSAC. See the field comments for
+// default values.
+type RANSACSettings struct {
+	// Fit fits the model to each random
+	// subset and to the consensus set. If
+	// Fit is nil, ordinary least squares
+	// regression on the design matrix is used.
+	Fit Fitter
+
+	// MinSamples is the number of observations
+	// in each random subset. If MinSamples is
+	// zero, the number of columns of the design
+	// matrix is used.
+	MinSamples int
+
+	// Threshold is the greatest absolute
+	// residual of an inlier. If Threshold is
+	// zero, the median absolute deviation of
+	// the responses is used.
+	Threshold float64
+
+	// MaxTrials is the maximum number of random
+	// subsets. If MaxTrials is zero, a default
+	// of 100 is used.
+	MaxTrials int
+
+	// StopProbability is the probability with
+	// which the trials stop after finding a
+	// subset free of outliers, estimated from
+	// the largest consensus set found. If
+	// StopProbability is zero, a default of 0.99
+	// is used.
+	StopProbability float64
+
+	// Src is the source of random numbers used
+	// to choose the subsets. If Src is nil, the
+	// global source is used.
+	Src rand.Source
+}
+
+// RANSACModel is a regression model fitted by RANSAC.
+type RANSACModel struct {
+	// Model is the model fitted to
+	// the inliers.
+	Model Predictor
+
+	// Inliers holds whether each
+	// observation is an inlier.
+	Inliers []bool
+
+	// NumInliers is the number
+	// of inliers.
+	NumInliers int
+
+	// Trials is the number of random
+	// subsets fitted.
+	Trials int
+}
+
+// Predict returns the prediction of the model fitted to the inliers for
+// the observation x.
+func (m *RANSACModel) Predict(x []float64) float64 {
+	return m.Model.Predict(x)
+}
+
+// RANSAC fits a regression model to the responses y and the observations in
+// the rows of the design matrix x that is robust to a large proportion of
+// outliers, using the random sample consensus algorithm, see Fischler and
+// Bolles, "Random sample consensus: a paradigm for model fitting with
+// applications to image analysis and automated cartography", Commun. ACM
+// 24(6), 1981. If settings is nil, the default settings are used.
+//
+// Each trial fits the model to a random subset of the observations and
+// finds the consensus set of observations with absolute residuals within
+// the threshold. The model is then refitted to the largest consensus set,
+// with ties broken by the least sum of squared residuals, whose members are
+// reported as the inliers. Subsets for which the fit returns an error are
+// skipped. If no subset could be fitted, RANSAC returns a nil model and
+// ErrNoValidFit.
+//
+// RANSAC panics if the length of y is not the number of rows of x or if the
+// settings are invalid.
+func RANSAC(x mat.Matrix, y []float64, settings *RANSACSettings) (*RANSACModel, error) {
+	n, p := x.Dims()
+	if len(y) != n {
+		panic("regression: mismatched response length")
+	}
+	var s RANSACSettings
+	if settings != nil {
+		s = *settings
+	}
+	if s.Fit == nil {
+		s.Fit = leastSquares
+	}
+	if s.MinSamples == 0 {
+		s.MinSamples = p
+	}
+	if s.Threshold == 0 {
+		med := median(y)
+		d := make([]float64, n)
+		for i, v := range y {
+			d[i] = v - med
+		}
+		s.Threshold = medianAbs(d)
+	}
+	if s.MaxTrials == 0 {
+		s.MaxTrials = 100
+	}
+	if s.StopProbability == 0 {
+		s.StopProbability = 0.99
+	}
+	if s.MinSamples <= 0 || s.MinSamples > n || s.Threshold < 0 || s.MaxTrials < 0 || s.StopProbability < 0 || s.StopProbability > 1 {
+		panic("regression: invalid RANSAC settings")
+	}
+	intn := rand.Intn
+	if s.Src != nil {
+		intn = rand.New(s.Src).Intn
+	}
+
+	xd := mat.DenseCopyOf(x)
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	res := &RANSACModel{}
+	var (
+		inliers = make([]bool, n)
+		best    = -1
+		bestSSE = math.Inf(1)
+		trials  = s.MaxTrials
+	)
+	for res.Trials < trials {
+		res.Trials++
+
+		// Choose a random subset by a partial shuffle.
+		for i := 0; i < s.MinSamples; i++ {
+			j := i + intn(n-i)
+			perm[i], perm[j] = perm[j], perm[i]
+		}
+		model, err := s.Fit(subset(xd, y, perm[:s.MinSamples]))
+		if err != nil {
+			continue
+		}
+		var count int
+		var sse float64
+		for i := range inliers {
+			r := y[i] - model.Predict(xd.RawRowView(i))
+			inliers[i] = math.Abs(r) <= s.Threshold
+			if inliers[i] {
+				count++
+				sse += r * r
+			}
+		}
+		if count < best || (count == best && sse >= bestSSE) {
+			continue
+		}
+		best, bestSSE = count, sse
+		res.Inliers = append(res.Inliers[:0], inliers...)
+		if count == n {
+			break
+		}
+
+		// Update the number of trials needed to choose a
+		// subset of inliers with the stopping probability.
+		frac := float64(count) / float64(n)
+		pGood := math.Pow(frac, float64(s.MinSamples))
+		if pGood > 0 && pGood < 1 {
+			need := math.Ceil(math.Log(1-s.StopProbability) / math.Log(1-pGood))
+			if need < float64(trials) {
+				trials = int(need)
+			}
+		}
+	}
+	if best < 0 {
+		return nil, ErrNoValidFit
+	}
+
+	idx := make([]int, 0, best)
+	for i, ok := range res.Inliers {
+		if ok {
+			idx = append(idx, i)
+		}
+	}
+	model, err := s.Fit(subset(xd, y, idx))
+	if err != nil {
+		return nil, err
+	}
+	res.Model = model
+	res.NumInliers = best
+	return res, nil
+}
+
+// leastSquares is a Fitter returning the ordinary least squares fit of y
+// on the rows of x.
+func leastSquares(x mat.Matrix, y []float64) (Predictor, error) {
+	n, p := x.Dims()
+	if n < p {
+		return nil, mat.ErrShape
+	}
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 1
+	}
+	beta, err := weightedLeastSquares(mat.DenseCopyOf(x), y, w)
+	if err != nil {
+		return nil, err
+	}
+	return &LinearModel{Coefficients: beta}, nil
+}
+
+// subset returns the rows of x and elements of y indexed by idx.
+func subset(x *mat.Dense, y []float64, idx []int) (*mat.Dense, []float64) {
+	_, p := x.Dims()
+	xs := mat.NewDense(len(idx), p, nil)
+	ys := make([]float64, len(idx))
+	for k, i := range idx {
+		xs.SetRow(k, x.RawRowView(i))
+		ys[k] = y[i]
+	}
+	return xs, ys
+}
+
+// median returns the median of x.
+func median(x []float64) float64 {
+	a := append([]float64(nil), x...)
+	sort.Float64s(a)
+	n := len(a)
+	if n%2 == 1 {
+		return a[n/2]
+	}
+	return (a[n/2-1] + a[n/2]) / 2
+}
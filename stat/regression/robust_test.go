@@ -0,0 +1,164 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package regression
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestLoss(t *testing.T) {
+	t.Parallel()
+	for _, loss := range []Loss{Huber{}, Huber{K: 2}, Tukey{}, Tukey{C: 3}} {
+		if loss.Loss(0) != 0 {
+			t.Errorf("%#v: non-zero loss at zero", loss)
+		}
+		for _, u := range []float64{-7, -3.5, -1.2, -0.3, 0.3, 1, 2.5, 4, 8} {
+			if loss.Loss(u) != loss.Loss(-u) {
+				t.Errorf("%#v: loss not symmetric at %v", loss, u)
+			}
+			// The weight is the derivative of the loss
+			// divided by the residual.
+			const h = 1e-6
+			psi := (loss.Loss(u+h) - loss.Loss(u-h)) / (2 * h)
+			if math.Abs(loss.Weight(u)*u-psi) > 1e-6 {
+				t.Errorf("%#v: weight at %v does not match derivative: got %v want %v", loss, u, loss.Weight(u)*u, psi)
+			}
+		}
+	}
+}
+
+// contaminated returns n observations of the line y = 1 + 2*x with a
+// column of ones, with the fraction frac of the responses replaced by
+// large outliers, and whether each observation is an outlier.
+func contaminated(n int, frac float64, rnd *rand.Rand) (*mat.Dense, []float64, []bool) {
+	x := mat.NewDense(n, 2, nil)
+	y := make([]float64, n)
+	outlier := make([]bool, n)
+	for i := 0; i < n; i++ {
+		xi := 10 * rnd.Float64()
+		x.Set(i, 0, 1)
+		x.Set(i, 1, xi)
+		y[i] = 1 + 2*xi + 0.1*rnd.NormFloat64()
+		if float64(i) < frac*float64(n) {
+			y[i] += 20 + 30*rnd.Float64()
+			outlier[i] = true
+		}
+	}
+	return x, y, outlier
+}
+
+func TestMEstimate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	want := []float64{1, 2}
+	x, y, outlier := contaminated(200, 0.2, rnd)
+
+	ols, err := leastSquares(x, y)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if floats.EqualApprox(ols.(*LinearModel).Coefficients, want, 0.5) {
+		t.Fatalf("least squares unexpectedly robust")
+	}
+
+	for _, test := range []struct {
+		loss Loss
+		tol  float64
+	}{
+		{loss: Huber{}, tol: 0.6},
+		{loss: Tukey{}, tol: 0.05},
+	} {
+		m, err := MEstimate(x, y, test.loss, nil)
+		if err != nil {
+			t.Fatalf("%#v: unexpected error: %v", test.loss, err)
+		}
+		if !floats.EqualApprox(m.Coefficients, want, test.tol) {
+			t.Errorf("%#v: unexpected coefficients: got %v want %v", test.loss, m.Coefficients, want)
+		}
+		for i, w := range m.Weights {
+			if outlier[i] && w > 0.1 {
+				t.Errorf("%#v: unexpected weight for outlier %d: %v", test.loss, i, w)
+			}
+		}
+		if math.Abs(m.Predict([]float64{1, 3})-floats.Dot([]float64{1, 3}, m.Coefficients)) > 1e-14 {
+			t.Errorf("%#v: unexpected prediction", test.loss)
+		}
+	}
+
+	// Tukey's loss ignores the outliers entirely, giving
+	// the least squares fit of the inliers.
+	var idx []int
+	for i, o := range outlier {
+		if !o {
+			idx = append(idx, i)
+		}
+	}
+	clean, err := leastSquares(subset(x, y, idx))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m, _ := MEstimate(x, y, Tukey{}, nil)
+	if !floats.EqualApprox(m.Coefficients, clean.(*LinearModel).Coefficients, 1e-2) {
+		t.Errorf("unexpected Tukey coefficients: got %v want %v", m.Coefficients, clean.(*LinearModel).Coefficients)
+	}
+
+	if _, err := MEstimate(x, y, Huber{}, &RobustSettings{MaxIterations: 1}); err != ErrIterationLimit {
+		t.Errorf("unexpected error for iteration limit: got %v want %v", err, ErrIterationLimit)
+	}
+}
+
+func TestRANSAC(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	want := []float64{1, 2}
+	x, y, outlier := contaminated(200, 0.4, rnd)
+
+	m, err := RANSAC(x, y, &RANSACSettings{Threshold: 0.5, Src: rand.NewSource(1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(m.Model.(*LinearModel).Coefficients, want, 0.05) {
+		t.Errorf("unexpected coefficients: got %v want %v", m.Model.(*LinearModel).Coefficients, want)
+	}
+	var count int
+	for i, in := range m.Inliers {
+		if in == outlier[i] {
+			t.Errorf("unexpected inlier status for observation %d: got %t", i, in)
+		}
+		if in {
+			count++
+		}
+	}
+	if count != m.NumInliers {
+		t.Errorf("unexpected number of inliers: got %d want %d", m.NumInliers, count)
+	}
+	if m.Trials >= 100 {
+		t.Errorf("trials not stopped early: %d", m.Trials)
+	}
+
+	// Any model may be used for the fits.
+	robust := func(x mat.Matrix, y []float64) (Predictor, error) {
+		return MEstimate(x, y, Huber{}, nil)
+	}
+	m, err = RANSAC(x, y, &RANSACSettings{Fit: robust, MinSamples: 5, Threshold: 0.5, Src: rand.NewSource(1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(m.Model.(*RobustModel).Coefficients, want, 0.05) {
+		t.Errorf("unexpected coefficients for robust fit: got %v want %v", m.Model.(*RobustModel).Coefficients, want)
+	}
+
+	// Subsets of identical observations cannot be fitted.
+	x = mat.NewDense(4, 2, []float64{1, 1, 1, 1, 1, 1, 1, 1})
+	if _, err := RANSAC(x, []float64{1, 2, 3, 4}, nil); err != ErrNoValidFit {
+		t.Errorf("unexpected error for degenerate data: got %v want %v", err, ErrNoValidFit)
+	}
+}
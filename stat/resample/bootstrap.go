@@ -0,0 +1,377 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Settings holds the settings for the resampling functions. See the field
+// comments for default values.
+type Settings struct {
+	// Replicates is the number of bootstrap
+	// replicates. If Replicates is zero, a
+	// default of 2000 is used. Replicates is
+	// not used by the jackknife.
+	Replicates int
+
+	// Workers is the number of goroutines
+	// evaluating the statistic concurrently.
+	// The statistic must be safe for concurrent
+	// use if Workers is not one. If Workers is
+	// zero, runtime.GOMAXPROCS(0) is used.
+	Workers int
+
+	// Src is the source of randomness for the
+	// resamples. If Src is nil, the global
+	// source is used. The resamples depend
+	// only on Src and not on the number of
+	// workers. Src is not used by the
+	// jackknife.
+	Src rand.Source
+}
+
+// defaults returns the settings with default values filled in.
+func (s *Settings) defaults() Settings {
+	var d Settings
+	if s != nil {
+		d = *s
+	}
+	if d.Replicates == 0 {
+		d.Replicates = 2000
+	}
+	if d.Workers == 0 {
+		d.Workers = runtime.GOMAXPROCS(0)
+	}
+	if d.Replicates < 0 || d.Workers < 0 {
+		panic("resample: invalid settings")
+	}
+	return d
+}
+
+// BootstrapResult holds the bootstrap replicates of a statistic.
+type BootstrapResult struct {
+	// Estimate holds the value of the statistic
+	// for the original sample.
+	Estimate []float64
+
+	// Replicates holds the values of the
+	// statistic for the bootstrap resamples
+	// in its rows.
+	Replicates *mat.Dense
+
+	// jackknife returns the jackknife values
+	// of the statistic, which are computed
+	// once when needed by BCa.
+	jackknife func() *mat.Dense
+	once      sync.Once
+	jack      *mat.Dense
+}
+
+// Bootstrap returns the bootstrap replicates of the statistic fn of the
+// sample x, the values of fn for resamples of x drawn with replacement. The
+// slice passed to fn must not be retained or modified. If settings is nil,
+// the default settings are used.
+//
+// Bootstrap panics if x has fewer than two elements or if the settings are
+// invalid.
+func Bootstrap(x []float64, fn func([]float64) float64, settings *Settings) *BootstrapResult {
+	n := len(x)
+	if n < 2 {
+		panic("resample: too few observations")
+	}
+	s := settings.defaults()
+	newEval := univariate(x, fn)
+	b := &BootstrapResult{
+		Estimate: []float64{fn(x)},
+		jackknife: func() *mat.Dense {
+			return jackknife(n, 1, s.Workers, newEval)
+		},
+	}
+	b.Replicates = bootstrap(n, 1, s, newEval)
+	return b
+}
+
+// BootstrapMatrix returns the bootstrap replicates of the statistic fn of the
+// observations in the rows of x, the values of fn for resamples of the rows
+// of x drawn with replacement. The matrix passed to fn must not be retained
+// or modified, and the length of the slice returned by fn must be the same
+// for all resamples. If settings is nil, the default settings are used.
+//
+// BootstrapMatrix panics if x has fewer than two rows, if the settings are
+// invalid or if the lengths of the statistics differ.
+func BootstrapMatrix(x mat.Matrix, fn func(mat.Matrix) []float64, settings *Settings) *BootstrapResult {
+	n, _ := x.Dims()
+	if n < 2 {
+		panic("resample: too few observations")
+	}
+	s := settings.defaults()
+	xd := mat.DenseCopyOf(x)
+	est := fn(xd)
+	k := len(est)
+	newEval := multivariate(xd, fn, k)
+	b := &BootstrapResult{
+		Estimate: append([]float64(nil), est...),
+		jackknife: func() *mat.Dense {
+			return jackknife(n, k, s.Workers, newEval)
+		},
+	}
+	b.Replicates = bootstrap(n, k, s, newEval)
+	return b
+}
+
+// bootstrap returns the replicates of the statistic evaluated by the
+// evaluators returned by newEval for resamples of n observations.
+func bootstrap(n, k int, s Settings, newEval func() evaluator) *mat.Dense {
+	seeds := replicateSeeds(s)
+	dst := mat.NewDense(s.Replicates, k, nil)
+	evaluate(dst, s.Workers, newEval, func(idx []int, r int) []int {
+		rnd := rand.New(rand.NewSource(seeds[r]))
+		for i := 0; i < n; i++ {
+			idx = append(idx, rnd.Intn(n))
+		}
+		return idx
+	})
+	return dst
+}
+
+// replicateSeeds returns the seeds of the sources of randomness for each
+// replicate. Seeding each replicate independently makes the replicates
+// independent of the number of workers.
+func replicateSeeds(s Settings) []uint64 {
+	seed := rand.Uint64
+	if s.Src != nil {
+		seed = rand.New(s.Src).Uint64
+	}
+	seeds := make([]uint64, s.Replicates)
+	for i := range seeds {
+		seeds[i] = seed()
+	}
+	return seeds
+}
+
+// evaluator stores the value of a statistic of the observations with the
+// indices idx into dst.
+type evaluator func(dst []float64, idx []int)
+
+// univariate returns a function returning evaluators of fn for the
+// elements of x.
+func univariate(x []float64, fn func([]float64) float64) func() evaluator {
+	return func() evaluator {
+		buf := make([]float64, len(x))
+		return func(dst []float64, idx []int) {
+			for i, j := range idx {
+				buf[i] = x[j]
+			}
+			dst[0] = fn(buf[:len(idx)])
+		}
+	}
+}
+
+// multivariate returns a function returning evaluators of fn for the rows
+// of x, where fn returns k values.
+func multivariate(x *mat.Dense, fn func(mat.Matrix) []float64, k int) func() evaluator {
+	n, p := x.Dims()
+	return func() evaluator {
+		buf := mat.NewDense(n, p, nil)
+		return func(dst []float64, idx []int) {
+			for i, j := range idx {
+				copy(buf.RawRowView(i), x.RawRowView(j))
+			}
+			v := fn(buf.Slice(0, len(idx), 0, p))
+			if len(v) != k {
+				panic("resample: statistic length mismatch")
+			}
+			copy(dst, v)
+		}
+	}
+}
+
+// evaluate stores the values of the statistic for the resamples returned by
+// sample into the rows of dst, using the given number of workers. A panic in
+// a worker is repanicked in the calling goroutine.
+func evaluate(dst *mat.Dense, workers int, newEval func() evaluator, sample func(idx []int, r int) []int) {
+	rows, _ := dst.Dims()
+	if workers > rows {
+		workers = rows
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		panicked bool
+		reason   interface{}
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func(w int) {
+			defer func() {
+				if r := recover(); r != nil {
+					mu.Lock()
+					if !panicked {
+						panicked = true
+						reason = r
+					}
+					mu.Unlock()
+				}
+				wg.Done()
+			}()
+			eval := newEval()
+			var idx []int
+			for r := w; r < rows; r += workers {
+				idx = sample(idx[:0], r)
+				eval(dst.RawRowView(r), idx)
+			}
+		}(w)
+	}
+	wg.Wait()
+	if panicked {
+		panic(reason)
+	}
+}
+
+// StdErr returns the bootstrap estimates of the standard errors of the
+// statistics, the standard deviations of the replicates. If dst is not nil,
+// the standard errors are stored in-place into dst and returned, otherwise a
+// new slice is allocated first. StdErr panics if dst is not nil and its
+// length is not the number of statistics.
+func (b *BootstrapResult) StdErr(dst []float64) []float64 {
+	dst = b.checkDst(dst)
+	col := make([]float64, b.numReplicates())
+	for j := range dst {
+		mat.Col(col, j, b.Replicates)
+		dst[j] = stat.StdDev(col, nil)
+	}
+	return dst
+}
+
+// Bias returns the bootstrap estimates of the bias of the statistics, the
+// differences between the means of the replicates and the estimates. If dst
+// is not nil, the biases are stored in-place into dst and returned,
+// otherwise a new slice is allocated first. Bias panics if dst is not nil
+// and its length is not the number of statistics.
+func (b *BootstrapResult) Bias(dst []float64) []float64 {
+	dst = b.checkDst(dst)
+	col := make([]float64, b.numReplicates())
+	for j := range dst {
+		mat.Col(col, j, b.Replicates)
+		dst[j] = stat.Mean(col, nil) - b.Estimate[j]
+	}
+	return dst
+}
+
+// Percentile stores the bounds of the bootstrap percentile confidence
+// intervals of the statistics with the given coverage level into lower and
+// upper. The bounds are the (1-level)/2 and (1+level)/2 quantiles of the
+// replicates.
+//
+// Percentile panics if the lengths of lower and upper are not the number of
+// statistics or if level is not in (0, 1).
+func (b *BootstrapResult) Percentile(lower, upper []float64, level float64) {
+	b.checkInterval(lower, upper, level)
+	alpha := (1 - level) / 2
+	col := make([]float64, b.numReplicates())
+	for j := range lower {
+		mat.Col(col, j, b.Replicates)
+		sort.Float64s(col)
+		lower[j] = stat.Quantile(alpha, stat.Empirical, col, nil)
+		upper[j] = stat.Quantile(1-alpha, stat.Empirical, col, nil)
+	}
+}
+
+// BCa stores the bounds of the bias-corrected and accelerated bootstrap
+// confidence intervals of the statistics with the given coverage level into
+// lower and upper. The bounds are quantiles of the replicates adjusted for
+// the median bias of the replicates and for the rate of change of the
+// standard error of the statistic, which is estimated from the jackknife
+// values of the statistic. The jackknife values are computed by the first
+// call to BCa. The intervals are second order accurate and transformation
+// respecting, see Efron, "Better bootstrap confidence intervals", Journal of
+// the American Statistical Association 82(397), 1987.
+//
+// If a replicate is on only one side of an estimate, the interval of the
+// statistic is not defined and its bounds are NaN.
+//
+// BCa panics if the lengths of lower and upper are not the number of
+// statistics or if level is not in (0, 1).
+func (b *BootstrapResult) BCa(lower, upper []float64, level float64) {
+	b.checkInterval(lower, upper, level)
+	b.once.Do(func() { b.jack = b.jackknife() })
+	n, _ := b.jack.Dims()
+	zLo := distuv.UnitNormal.Quantile((1 - level) / 2)
+	zHi := -zLo
+	col := make([]float64, b.numReplicates())
+	jcol := make([]float64, n)
+	for j := range lower {
+		mat.Col(col, j, b.Replicates)
+		sort.Float64s(col)
+
+		// Estimate the bias correction z0 from the
+		// fraction of replicates below the estimate,
+		// counting ties as half.
+		est := b.Estimate[j]
+		less := sort.SearchFloat64s(col, est)
+		equal := sort.Search(len(col), func(i int) bool { return col[i] > est }) - less
+		z0 := distuv.UnitNormal.Quantile((float64(less) + float64(equal)/2) / float64(len(col)))
+
+		// Estimate the acceleration from the skewness
+		// of the jackknife values.
+		mat.Col(jcol, j, b.jack)
+		mean := stat.Mean(jcol, nil)
+		var num, den float64
+		for _, v := range jcol {
+			d := mean - v
+			num += d * d * d
+			den += d * d
+		}
+		var a float64
+		if den > 0 {
+			a = num / (6 * math.Pow(den, 1.5))
+		}
+
+		adjust := func(z float64) float64 {
+			return distuv.UnitNormal.CDF(z0 + (z0+z)/(1-a*(z0+z)))
+		}
+		if math.IsInf(z0, 0) {
+			lower[j] = math.NaN()
+			upper[j] = math.NaN()
+			continue
+		}
+		lower[j] = stat.Quantile(adjust(zLo), stat.Empirical, col, nil)
+		upper[j] = stat.Quantile(adjust(zHi), stat.Empirical, col, nil)
+	}
+}
+
+func (b *BootstrapResult) numReplicates() int {
+	r, _ := b.Replicates.Dims()
+	return r
+}
+
+func (b *BootstrapResult) checkDst(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(b.Estimate))
+	}
+	if len(dst) != len(b.Estimate) {
+		panic("resample: slice length mismatch")
+	}
+	return dst
+}
+
+func (b *BootstrapResult) checkInterval(lower, upper []float64, level float64) {
+	if len(lower) != len(b.Estimate) || len(upper) != len(b.Estimate) {
+		panic("resample: slice length mismatch")
+	}
+	if !(0 < level && level < 1) {
+		panic("resample: invalid level")
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func mean(x []float64) float64     { return stat.Mean(x, nil) }
+func variance(x []float64) float64 { return stat.Variance(x, nil) }
+
+func normalSample(n int, rnd *rand.Rand) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	return x
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+func TestBootstrapStdErr(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := normalSample(100, rnd)
+	b := Bootstrap(x, mean, &Settings{Replicates: 5000, Src: rand.NewSource(2)})
+	if r, c := b.Replicates.Dims(); r != 5000 || c != 1 {
+		t.Fatalf("unexpected replicates dimensions: got %d×%d want 5000×1", r, c)
+	}
+	if b.Estimate[0] != mean(x) {
+		t.Errorf("unexpected estimate: got %v want %v", b.Estimate[0], mean(x))
+	}
+
+	// The bootstrap standard error of the mean estimates
+	// the plug-in standard deviation over √n.
+	n := float64(len(x))
+	want := stat.StdDev(x, nil) * math.Sqrt((n-1)/n) / math.Sqrt(n)
+	got := b.StdErr(nil)[0]
+	if math.Abs(got-want) > 0.05*want {
+		t.Errorf("unexpected standard error: got %v want %v", got, want)
+	}
+	// The mean is unbiased.
+	if bias := b.Bias(nil)[0]; math.Abs(bias) > 3*want/math.Sqrt(5000) {
+		t.Errorf("unexpected bias: got %v want 0", bias)
+	}
+}
+
+func TestBootstrapWorkers(t *testing.T) {
+	t.Parallel()
+	x := normalSample(20, rand.New(rand.NewSource(1)))
+	var want *BootstrapResult
+	for _, workers := range []int{1, 2, 3, 7, 0} {
+		b := Bootstrap(x, variance, &Settings{Replicates: 101, Workers: workers, Src: rand.NewSource(1)})
+		if want == nil {
+			want = b
+			continue
+		}
+		if !mat.Equal(b.Replicates, want.Replicates) {
+			t.Errorf("replicates depend on number of workers: %d", workers)
+		}
+		lo, hi := make([]float64, 1), make([]float64, 1)
+		wantLo, wantHi := make([]float64, 1), make([]float64, 1)
+		b.BCa(lo, hi, 0.9)
+		want.BCa(wantLo, wantHi, 0.9)
+		if lo[0] != wantLo[0] || hi[0] != wantHi[0] {
+			t.Errorf("BCa interval depends on number of workers: %d", workers)
+		}
+	}
+}
+
+func TestBootstrapCoverage(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const (
+		trials = 200
+		n      = 30
+		level  = 0.9
+	)
+	lo, hi := make([]float64, 1), make([]float64, 1)
+	var percentile, bca int
+	for i := 0; i < trials; i++ {
+		// Exponential data with mean one.
+		x := make([]float64, n)
+		for j := range x {
+			x[j] = rnd.ExpFloat64()
+		}
+		b := Bootstrap(x, mean, &Settings{Replicates: 1000, Src: rnd})
+		b.Percentile(lo, hi, level)
+		if lo[0] > hi[0] {
+			t.Fatalf("invalid percentile interval: [%v, %v]", lo[0], hi[0])
+		}
+		if lo[0] <= 1 && 1 <= hi[0] {
+			percentile++
+		}
+		b.BCa(lo, hi, level)
+		if lo[0] > hi[0] {
+			t.Fatalf("invalid BCa interval: [%v, %v]", lo[0], hi[0])
+		}
+		if lo[0] <= 1 && 1 <= hi[0] {
+			bca++
+		}
+	}
+	for _, c := range []struct {
+		name  string
+		cover int
+	}{
+		{name: "percentile", cover: percentile},
+		{name: "BCa", cover: bca},
+	} {
+		got := float64(c.cover) / trials
+		if math.Abs(got-level) > 0.07 {
+			t.Errorf("unexpected %s coverage: got %v want %v", c.name, got, level)
+		}
+	}
+}
+
+func TestBootstrapDegenerate(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3, 4}
+	lo, hi := make([]float64, 1), make([]float64, 1)
+
+	// A statistic that is constant on all resamples
+	// has a degenerate interval.
+	b := Bootstrap(x, func([]float64) float64 { return 1 }, &Settings{Replicates: 10, Src: rand.NewSource(1)})
+	b.Percentile(lo, hi, 0.95)
+	if lo[0] != 1 || hi[0] != 1 {
+		t.Errorf("unexpected percentile interval: got [%v, %v] want [1, 1]", lo[0], hi[0])
+	}
+	b.BCa(lo, hi, 0.95)
+	if lo[0] != 1 || hi[0] != 1 {
+		t.Errorf("unexpected BCa interval: got [%v, %v] want [1, 1]", lo[0], hi[0])
+	}
+
+	// A statistic with all replicates above the
+	// estimate has an undefined BCa interval.
+	fn := func(s []float64) float64 {
+		if floats.Equal(s, x) {
+			return 0
+		}
+		return 1
+	}
+	b = Bootstrap(x, fn, &Settings{Replicates: 50, Src: rand.NewSource(1)})
+	if floats.Min(b.Replicates.RawMatrix().Data) != 1 {
+		t.Fatal("resample equal to sample")
+	}
+	b.BCa(lo, hi, 0.95)
+	if !math.IsNaN(lo[0]) || !math.IsNaN(hi[0]) {
+		t.Errorf("unexpected BCa interval for one-sided replicates: got [%v, %v] want [NaN, NaN]", lo[0], hi[0])
+	}
+}
+
+func TestBootstrapMatrix(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 200
+	// Bivariate normal with correlation ρ.
+	const rho = 0.6
+	x := mat.NewDense(n, 2, nil)
+	for i := 0; i < n; i++ {
+		z0, z1 := rnd.NormFloat64(), rnd.NormFloat64()
+		x.Set(i, 0, z0)
+		x.Set(i, 1, rho*z0+math.Sqrt(1-rho*rho)*z1)
+	}
+	fn := func(m mat.Matrix) []float64 {
+		a := mat.Col(nil, 0, m)
+		b := mat.Col(nil, 1, m)
+		return []float64{stat.Mean(a, nil), stat.Correlation(a, b, nil)}
+	}
+	b := BootstrapMatrix(x, fn, &Settings{Replicates: 2000, Src: rand.NewSource(1)})
+	if r, c := b.Replicates.Dims(); r != 2000 || c != 2 {
+		t.Fatalf("unexpected replicates dimensions: got %d×%d want 2000×2", r, c)
+	}
+	se := b.StdErr(nil)
+	// The asymptotic standard error of the sample
+	// correlation is (1-ρ²)/√n.
+	if want := (1 - rho*rho) / math.Sqrt(n); math.Abs(se[1]-want) > 0.2*want {
+		t.Errorf("unexpected correlation standard error: got %v want %v", se[1], want)
+	}
+	if want := 1 / math.Sqrt(n); math.Abs(se[0]-want) > 0.2*want {
+		t.Errorf("unexpected mean standard error: got %v want %v", se[0], want)
+	}
+	lo, hi := make([]float64, 2), make([]float64, 2)
+	b.BCa(lo, hi, 0.95)
+	for j, est := range b.Estimate {
+		if !(lo[j] < est && est < hi[j]) {
+			t.Errorf("BCa interval for statistic %d does not contain estimate: %v not in [%v, %v]", j, est, lo[j], hi[j])
+		}
+	}
+	if !(lo[1] < rho && rho < hi[1]) {
+		t.Errorf("BCa interval does not contain correlation: %v not in [%v, %v]", rho, lo[1], hi[1])
+	}
+}
+
+func TestBootstrapPanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "too few", fn: func() { Bootstrap(x[:1], mean, nil) }},
+		{name: "negative replicates", fn: func() { Bootstrap(x, mean, &Settings{Replicates: -1}) }},
+		{name: "negative workers", fn: func() { Bootstrap(x, mean, &Settings{Workers: -1}) }},
+		{name: "stat length", fn: func() {
+			i := 0
+			BootstrapMatrix(mat.NewDense(3, 1, x), func(mat.Matrix) []float64 {
+				i++
+				return make([]float64, i)
+			}, &Settings{Replicates: 10, Workers: 1})
+		}},
+		{name: "level", fn: func() {
+			b := Bootstrap(x, mean, &Settings{Replicates: 10})
+			b.Percentile(make([]float64, 1), make([]float64, 1), 1)
+		}},
+		{name: "dst length", fn: func() {
+			b := Bootstrap(x, mean, &Settings{Replicates: 10})
+			b.StdErr(make([]float64, 2))
+		}},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
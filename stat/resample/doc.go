@@ -0,0 +1,12 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resample provides resampling methods for estimating the sampling
+// distribution of statistics, such as the bootstrap and the jackknife, and
+// permutation tests.
+//
+// See Efron and Tibshirani, "An Introduction to the Bootstrap", Chapman and
+// Hall, 1993 and Davison and Hinkley, "Bootstrap Methods and their
+// Application", Cambridge University Press, 1997 for more details.
+package resample // import "gonum.org/v1/gonum/stat/resample"
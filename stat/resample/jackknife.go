@@ -0,0 +1,137 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+// JackknifeResult holds the jackknife values of a statistic.
+type JackknifeResult struct {
+	// Estimate holds the value of the statistic
+	// for the original sample.
+	Estimate []float64
+
+	// Values holds the values of the statistic
+	// for the sample with the ith observation
+	// left out in its ith row.
+	Values *mat.Dense
+}
+
+// Jackknife returns the jackknife values of the statistic fn of the sample
+// x, the values of fn for x with each element left out in turn. The slice
+// passed to fn must not be retained or modified. If settings is nil, the
+// default settings are used. Only the Workers field of settings is used.
+//
+// Jackknife panics if x has fewer than two elements or if the settings are
+// invalid.
+func Jackknife(x []float64, fn func([]float64) float64, settings *Settings) *JackknifeResult {
+	n := len(x)
+	if n < 2 {
+		panic("resample: too few observations")
+	}
+	s := settings.defaults()
+	return &JackknifeResult{
+		Estimate: []float64{fn(x)},
+		Values:   jackknife(n, 1, s.Workers, univariate(x, fn)),
+	}
+}
+
+// JackknifeMatrix returns the jackknife values of the statistic fn of the
+// observations in the rows of x, the values of fn for x with each row left
+// out in turn. The matrix passed to fn must not be retained or modified, and
+// the length of the slice returned by fn must be the same for all subsamples.
+// If settings is nil, the default settings are used. Only the Workers field of
+// settings is used.
+//
+// JackknifeMatrix panics if x has fewer than two rows, if the settings are
+// invalid or if the lengths of the statistics differ.
+func JackknifeMatrix(x mat.Matrix, fn func(mat.Matrix) []float64, settings *Settings) *JackknifeResult {
+	n, _ := x.Dims()
+	if n < 2 {
+		panic("resample: too few observations")
+	}
+	s := settings.defaults()
+	xd := mat.DenseCopyOf(x)
+	est := fn(xd)
+	k := len(est)
+	return &JackknifeResult{
+		Estimate: append([]float64(nil), est...),
+		Values:   jackknife(n, k, s.Workers, multivariate(xd, fn, k)),
+	}
+}
+
+// jackknife returns the values of the statistic evaluated by the evaluators
+// returned by newEval for the leave-one-out subsamples of n observations.
+func jackknife(n, k, workers int, newEval func() evaluator) *mat.Dense {
+	dst := mat.NewDense(n, k, nil)
+	evaluate(dst, workers, newEval, func(idx []int, r int) []int {
+		for i := 0; i < n; i++ {
+			if i != r {
+				idx = append(idx, i)
+			}
+		}
+		return idx
+	})
+	return dst
+}
+
+// StdErr returns the jackknife estimates of the standard errors of the
+// statistics,
+//
+//	sqrt((n-1)/n Σ_i (θ_i - θ̄)²),
+//
+// where θ_i are the jackknife values and θ̄ is their mean. If dst is not nil,
+// the standard errors are stored in-place into dst and returned, otherwise a
+// new slice is allocated first. StdErr panics if dst is not nil and its
+// length is not the number of statistics.
+func (j *JackknifeResult) StdErr(dst []float64) []float64 {
+	dst = j.checkDst(dst)
+	n, _ := j.Values.Dims()
+	col := make([]float64, n)
+	for i := range dst {
+		mat.Col(col, i, j.Values)
+		mean := stat.Mean(col, nil)
+		var ss float64
+		for _, v := range col {
+			d := v - mean
+			ss += d * d
+		}
+		dst[i] = math.Sqrt(float64(n-1) / float64(n) * ss)
+	}
+	return dst
+}
+
+// Bias returns the jackknife estimates of the bias of the statistics,
+//
+//	(n-1) (θ̄ - θ̂),
+//
+// where θ̄ is the mean of the jackknife values and θ̂ is the estimate. If dst
+// is not nil, the biases are stored in-place into dst and returned,
+// otherwise a new slice is allocated first. Bias panics if dst is not nil
+// and its length is not the number of statistics.
+func (j *JackknifeResult) Bias(dst []float64) []float64 {
+	dst = j.checkDst(dst)
+	n, _ := j.Values.Dims()
+	col := make([]float64, n)
+	for i := range dst {
+		mat.Col(col, i, j.Values)
+		dst[i] = float64(n-1) * (stat.Mean(col, nil) - j.Estimate[i])
+	}
+	return dst
+}
+
+func (j *JackknifeResult) checkDst(dst []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(j.Estimate))
+	}
+	if len(dst) != len(j.Estimate) {
+		panic("resample: slice length mismatch")
+	}
+	return dst
+}
@@ -0,0 +1,92 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestJackknife(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{2, 5, 37} {
+		x := normalSample(n, rnd)
+		nf := float64(n)
+		s2 := stat.Variance(x, nil)
+
+		// The jackknife standard error of the mean is
+		// the usual standard error.
+		j := Jackknife(x, mean, nil)
+		if r, c := j.Values.Dims(); r != n || c != 1 {
+			t.Fatalf("unexpected values dimensions: got %d×%d want %d×1", r, c, n)
+		}
+		if got, want := j.StdErr(nil)[0], math.Sqrt(s2/nf); !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+			t.Errorf("n=%d: unexpected standard error: got %v want %v", n, got, want)
+		}
+		if got := j.Bias(nil)[0]; math.Abs(got) > 1e-12 {
+			t.Errorf("n=%d: unexpected bias of mean: got %v want 0", n, got)
+		}
+
+		// The jackknife bias of the plug-in variance is
+		// -s²/n, so the bias corrected estimate is the
+		// unbiased variance.
+		plugin := func(x []float64) float64 {
+			m := mean(x)
+			var v float64
+			for _, e := range x {
+				v += (e - m) * (e - m)
+			}
+			return v / float64(len(x))
+		}
+		j = Jackknife(x, plugin, &Settings{Workers: 2})
+		if got, want := j.Bias(nil)[0], -s2/nf; !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+			t.Errorf("n=%d: unexpected bias of plug-in variance: got %v want %v", n, got, want)
+		}
+	}
+}
+
+func TestJackknifeMatrix(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(4, 2, []float64{
+		1, 2,
+		3, 5,
+		4, 4,
+		8, 1,
+	})
+	fn := func(m mat.Matrix) []float64 {
+		r, c := m.Dims()
+		sum := make([]float64, c)
+		for i := 0; i < r; i++ {
+			for k := range sum {
+				sum[k] += m.At(i, k)
+			}
+		}
+		return sum
+	}
+	j := JackknifeMatrix(x, fn, &Settings{Workers: 3})
+	want := mat.NewDense(4, 2, []float64{
+		15, 10,
+		13, 7,
+		12, 8,
+		8, 11,
+	})
+	if !mat.Equal(j.Values, want) {
+		t.Errorf("unexpected jackknife values:\ngot:\n%v\nwant:\n%v", mat.Formatted(j.Values), mat.Formatted(want))
+	}
+	if !floats.Equal(j.Estimate, []float64{16, 12}) {
+		t.Errorf("unexpected estimate: got %v want [16 12]", j.Estimate)
+	}
+	// The jackknife bias of a sum is -(n-1)/n θ̂.
+	if got := j.Bias(nil); !floats.EqualApprox(got, []float64{-12, -9}, 1e-12) {
+		t.Errorf("unexpected bias: got %v want [-12 -9]", got)
+	}
+}
@@ -0,0 +1,427 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/combin"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Alternative specifies the alternative hypothesis of a test.
+type Alternative int
+
+const (
+	// TwoSided is the alternative that the statistic
+	// differs from its value under the null hypothesis
+	// in either direction. Permutation tests compare
+	// the absolute values of the statistics, so their
+	// null distribution is assumed to be symmetric
+	// about zero.
+	TwoSided Alternative = iota
+	// Less is the alternative that the statistic is
+	// less than under the null hypothesis.
+	Less
+	// Greater is the alternative that the statistic
+	// is greater than under the null hypothesis.
+	Greater
+)
+
+// PermutationResult holds the result of a permutation test.
+type PermutationResult struct {
+	// Statistic is the value of the test
+	// statistic for the observed data.
+	Statistic float64
+
+	// P is the p-value of the test. If the test
+	// is exact, P is the fraction of rearrangements
+	// with a statistic at least as extreme as the
+	// observed statistic. Otherwise P is
+	// (Extreme+1)/(Rearrangements+1), which is a
+	// valid p-value for random rearrangements.
+	P float64
+
+	// Extreme is the number of evaluated
+	// rearrangements with a statistic at least
+	// as extreme as the observed statistic.
+	Extreme int
+
+	// Rearrangements is the number of evaluated
+	// rearrangements of the data.
+	Rearrangements int
+
+	// Exact is whether all rearrangements were
+	// evaluated.
+	Exact bool
+}
+
+// PBounds returns the Clopper–Pearson confidence interval with the given
+// coverage level for the p-value estimated by a Monte Carlo permutation
+// test, the fraction of all rearrangements with a statistic at least as
+// extreme as the observed statistic. If the test is exact, lower and upper
+// are both P. PBounds panics if level is not in (0, 1).
+func (r PermutationResult) PBounds(level float64) (lower, upper float64) {
+	if !(0 < level && level < 1) {
+		panic("resample: invalid level")
+	}
+	if r.Exact {
+		return r.P, r.P
+	}
+	alpha := (1 - level) / 2
+	k := float64(r.Extreme)
+	n := float64(r.Rearrangements)
+	lower = 0
+	if r.Extreme > 0 {
+		lower = distuv.Beta{Alpha: k, Beta: n - k + 1}.Quantile(alpha)
+	}
+	upper = 1
+	if r.Extreme < r.Rearrangements {
+		upper = distuv.Beta{Alpha: k + 1, Beta: n - k}.Quantile(1 - alpha)
+	}
+	return lower, upper
+}
+
+// MeanDifference returns the difference between the means of x and y.
+func MeanDifference(x, y []float64) float64 {
+	return stat.Mean(x, nil) - stat.Mean(y, nil)
+}
+
+// MedianDifference returns the difference between the medians of x and y.
+// The median of an even number of values is the mean of the two middle
+// values.
+func MedianDifference(x, y []float64) float64 {
+	return median(x) - median(y)
+}
+
+func median(x []float64) float64 {
+	s := append([]float64(nil), x...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+// Correlation returns the Pearson correlation of x and y.
+func Correlation(x, y []float64) float64 {
+	return stat.Correlation(x, y, nil)
+}
+
+// TwoSample performs a permutation test of the null hypothesis that the
+// samples x and y are from the same distribution, using the test statistic
+// fn, for example MeanDifference or MedianDifference. The rearrangements of
+// the data reassign the pooled observations to samples of the sizes of x
+// and y. The slices passed to fn must not be retained or modified.
+//
+// If strataX and strataY are not nil, they hold the strata of the
+// observations in x and y, and observations are only reassigned within their
+// stratum, so each rearranged sample has the same number of observations in
+// each stratum as the original sample.
+//
+// If the number of distinct rearrangements is at most settings.Replicates,
+// all are evaluated and the test is exact. Otherwise settings.Replicates
+// random rearrangements are evaluated. If settings is nil, the default
+// settings are used.
+//
+// TwoSample panics if x or y is empty, if only one of strataX and strataY is
+// nil, if the lengths of the strata and the samples differ or if the
+// settings are invalid.
+func TwoSample(x, y []float64, fn func(x, y []float64) float64, strataX, strataY []int, alt Alternative, settings *Settings) PermutationResult {
+	nx, ny := len(x), len(y)
+	if nx == 0 || ny == 0 {
+		panic("resample: too few observations")
+	}
+	if (strataX == nil) != (strataY == nil) {
+		panic("resample: missing strata")
+	}
+	if strataX != nil && (len(strataX) != nx || len(strataY) != ny) {
+		panic("resample: strata length mismatch")
+	}
+	s := settings.defaults()
+	n := nx + ny
+	pool := make([]float64, 0, n)
+	pool = append(pool, x...)
+	pool = append(pool, y...)
+	var labels []int
+	if strataX != nil {
+		labels = make([]int, 0, n)
+		labels = append(labels, strataX...)
+		labels = append(labels, strataY...)
+	}
+	groups := strataOf(labels, n)
+
+	// Each stratum has Binomial(size, inX) distinct
+	// reassignments to the samples.
+	dims := make([]int, len(groups))
+	inX := make([]int, len(groups))
+	var logCount float64
+	for i, g := range groups {
+		for _, j := range g {
+			if j < nx {
+				inX[i]++
+			}
+		}
+		logCount += combin.LogGeneralizedBinomial(float64(len(g)), float64(inX[i]))
+	}
+
+	newEval := func() evaluator {
+		bx := make([]float64, nx)
+		by := make([]float64, ny)
+		return func(dst []float64, idx []int) {
+			for i, j := range idx[:nx] {
+				bx[i] = pool[j]
+			}
+			for i, j := range idx[nx:] {
+				by[i] = pool[j]
+			}
+			dst[0] = fn(bx, by)
+		}
+	}
+
+	// assign appends the pool indices in x followed
+	// by those in y for the reassignments given by
+	// choose for each stratum.
+	assign := func(idx []int, choose func(i int, g []int) []int) []int {
+		idx = idx[:0]
+		rest := make([]int, 0, ny)
+		for i, g := range groups {
+			c := choose(i, g)
+			in := make([]bool, len(g))
+			for _, k := range c {
+				in[k] = true
+				idx = append(idx, g[k])
+			}
+			for k, j := range g {
+				if !in[k] {
+					rest = append(rest, j)
+				}
+			}
+		}
+		return append(idx, rest...)
+	}
+
+	if exactCount(logCount, s.Replicates) {
+		total := 1
+		for i, g := range groups {
+			dims[i] = combin.Binomial(len(g), inX[i])
+			total *= dims[i]
+		}
+		return permutationTest(fn(x, y), total, true, alt, s.Workers, newEval, func(idx []int, r int) []int {
+			sub := combin.SubFor(nil, r, dims)
+			return assign(idx, func(i int, g []int) []int {
+				return combin.IndexToCombination(nil, sub[i], len(g), inX[i])
+			})
+		})
+	}
+	seeds := replicateSeeds(s)
+	return permutationTest(fn(x, y), s.Replicates, false, alt, s.Workers, newEval, func(idx []int, r int) []int {
+		rnd := rand.New(rand.NewSource(seeds[r]))
+		return assign(idx, func(i int, g []int) []int {
+			return rnd.Perm(len(g))[:inX[i]]
+		})
+	})
+}
+
+// Association performs a permutation test of the null hypothesis that the
+// paired observations x and y are independent, using the test statistic fn,
+// for example Correlation. The rearrangements of the data permute y while
+// holding x fixed. The slices passed to fn must not be retained or modified.
+//
+// If strata is not nil, it holds the strata of the pairs, and y is only
+// permuted within strata.
+//
+// If the number of permutations is at most settings.Replicates, all are
+// evaluated and the test is exact. Otherwise settings.Replicates random
+// permutations are evaluated. If settings is nil, the default settings are
+// used.
+//
+// Association panics if x and y have different lengths, if x has fewer than
+// two elements, if strata is not nil and its length differs from x or if the
+// settings are invalid.
+func Association(x, y []float64, fn func(x, y []float64) float64, strata []int, alt Alternative, settings *Settings) PermutationResult {
+	n := len(x)
+	if len(y) != n {
+		panic("resample: slice length mismatch")
+	}
+	if n < 2 {
+		panic("resample: too few observations")
+	}
+	if strata != nil && len(strata) != n {
+		panic("resample: strata length mismatch")
+	}
+	s := settings.defaults()
+	groups := strataOf(strata, n)
+
+	var logCount float64
+	for _, g := range groups {
+		lg, _ := math.Lgamma(float64(len(g) + 1))
+		logCount += lg
+	}
+
+	newEval := func() evaluator {
+		by := make([]float64, n)
+		return func(dst []float64, idx []int) {
+			for i, j := range idx {
+				by[i] = y[j]
+			}
+			dst[0] = fn(x, by)
+		}
+	}
+
+	// permute stores into idx the indices of y
+	// for the permutations given by perm for each
+	// stratum.
+	permute := func(idx []int, perm func(i int, g []int) []int) []int {
+		if cap(idx) < n {
+			idx = make([]int, n)
+		}
+		idx = idx[:n]
+		for i, g := range groups {
+			for k, p := range perm(i, g) {
+				idx[g[k]] = g[p]
+			}
+		}
+		return idx
+	}
+
+	if exactCount(logCount, s.Replicates) {
+		dims := make([]int, len(groups))
+		total := 1
+		for i, g := range groups {
+			dims[i] = factorial(len(g))
+			total *= dims[i]
+		}
+		return permutationTest(fn(x, y), total, true, alt, s.Workers, newEval, func(idx []int, r int) []int {
+			sub := combin.SubFor(nil, r, dims)
+			return permute(idx, func(i int, g []int) []int {
+				return indexToPermutation(sub[i], len(g))
+			})
+		})
+	}
+	seeds := replicateSeeds(s)
+	return permutationTest(fn(x, y), s.Replicates, false, alt, s.Workers, newEval, func(idx []int, r int) []int {
+		rnd := rand.New(rand.NewSource(seeds[r]))
+		return permute(idx, func(_ int, g []int) []int {
+			return rnd.Perm(len(g))
+		})
+	})
+}
+
+// permutationTest returns the result of a permutation test with the observed
+// statistic obs and the given number of rearrangements returned by sample.
+func permutationTest(obs float64, rearrangements int, exact bool, alt Alternative, workers int, newEval func() evaluator, sample func(idx []int, r int) []int) PermutationResult {
+	if alt != TwoSided && alt != Less && alt != Greater {
+		panic("resample: bad alternative")
+	}
+	dst := mat.NewDense(rearrangements, 1, nil)
+	evaluate(dst, workers, newEval, sample)
+	values := dst.RawMatrix().Data
+
+	// Rearrangements that are equal to the observed
+	// data may give a statistic that differs from
+	// the observed statistic by rounding error, so
+	// compare with a tolerance.
+	scale := math.Abs(obs)
+	for _, v := range values {
+		scale = math.Max(scale, math.Abs(v))
+	}
+	tol := 1e-12 * scale
+
+	var extreme int
+	for _, v := range values {
+		var ok bool
+		switch alt {
+		case TwoSided:
+			ok = math.Abs(v) >= math.Abs(obs)-tol
+		case Less:
+			ok = v <= obs+tol
+		case Greater:
+			ok = v >= obs-tol
+		}
+		if ok {
+			extreme++
+		}
+	}
+	r := PermutationResult{
+		Statistic:      obs,
+		Extreme:        extreme,
+		Rearrangements: rearrangements,
+		Exact:          exact,
+	}
+	if exact {
+		r.P = float64(extreme) / float64(rearrangements)
+	} else {
+		r.P = float64(extreme+1) / float64(rearrangements+1)
+	}
+	return r
+}
+
+// exactCount returns whether the number of rearrangements with logarithm
+// logCount is at most limit.
+func exactCount(logCount float64, limit int) bool {
+	// Allow for rounding error in the logarithm
+	// of integer counts.
+	return logCount <= math.Log(float64(limit))+1e-9
+}
+
+// strataOf returns the indices of the n observations with each stratum label
+// in order of the first appearance of the label. If labels is nil, all
+// observations are in one stratum.
+func strataOf(labels []int, n int) [][]int {
+	if labels == nil {
+		g := make([]int, n)
+		for i := range g {
+			g[i] = i
+		}
+		return [][]int{g}
+	}
+	var groups [][]int
+	index := make(map[int]int)
+	for i, l := range labels {
+		k, ok := index[l]
+		if !ok {
+			k = len(groups)
+			index[l] = k
+			groups = append(groups, nil)
+		}
+		groups[k] = append(groups[k], i)
+	}
+	return groups
+}
+
+// factorial returns n!.
+func factorial(n int) int {
+	f := 1
+	for i := 2; i <= n; i++ {
+		f *= i
+	}
+	return f
+}
+
+// indexToPermutation returns the permutation of n elements with the given
+// index in lexicographic order.
+func indexToPermutation(idx, n int) []int {
+	elems := make([]int, n)
+	for i := range elems {
+		elems[i] = i
+	}
+	perm := make([]int, n)
+	f := factorial(n)
+	for i := range perm {
+		f /= n - i
+		d := idx / f
+		idx %= f
+		perm[i] = elems[d]
+		elems = append(elems[:d], elems[d+1:]...)
+	}
+	return perm
+}
@@ -0,0 +1,244 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestTwoSampleExact(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name             string
+		x, y             []float64
+		fn               func(x, y []float64) float64
+		strataX, strataY []int
+		alt              Alternative
+
+		stat           float64
+		extreme, count int
+	}{
+		// Only the observed assignment of the 20 has a
+		// difference of means of -3, and only its mirror
+		// has a difference of 3.
+		{name: "less", x: []float64{1, 2, 3}, y: []float64{4, 5, 6}, fn: MeanDifference, alt: Less, stat: -3, extreme: 1, count: 20},
+		{name: "two sided", x: []float64{1, 2, 3}, y: []float64{4, 5, 6}, fn: MeanDifference, alt: TwoSided, stat: -3, extreme: 2, count: 20},
+		{name: "greater", x: []float64{1, 2, 3}, y: []float64{4, 5, 6}, fn: MeanDifference, alt: Greater, stat: -3, extreme: 20, count: 20},
+		// The assignments of x with the two smallest
+		// values have a difference of medians of at
+		// most -3.
+		{name: "median", x: []float64{1, 2, 100}, y: []float64{4, 5, 6}, fn: MedianDifference, alt: Less, stat: -3, extreme: 4, count: 20},
+
+		// Within strata the assignments are
+		//  x={1, 10} y={2, 11}: -1
+		//  x={2, 10} y={1, 11}:  0
+		//  x={1, 11} y={2, 10}:  0
+		//  x={2, 11} y={1, 10}:  1
+		{
+			name: "stratified less", x: []float64{1, 10}, y: []float64{2, 11}, fn: MeanDifference,
+			strataX: []int{0, 1}, strataY: []int{0, 1}, alt: Less,
+			stat: -1, extreme: 1, count: 4,
+		},
+		{
+			name: "stratified two sided", x: []float64{1, 10}, y: []float64{2, 11}, fn: MeanDifference,
+			strataX: []int{0, 1}, strataY: []int{0, 1}, alt: TwoSided,
+			stat: -1, extreme: 2, count: 4,
+		},
+		// Without strata, the assignments x={1, 2} and
+		// x={10, 11} with differences -9 and 9 are also
+		// possible.
+		{
+			name: "unstratified", x: []float64{1, 10}, y: []float64{2, 11}, fn: MeanDifference, alt: TwoSided,
+			stat: -1, extreme: 4, count: 6,
+		},
+		// A stratum with no observations in x. The
+		// assignments x={1, 3} and x={2, 3} have
+		// differences -98⅔ and -97⅚.
+		{
+			name: "stratum only in y", x: []float64{1, 2}, y: []float64{3, 100, 200}, fn: MeanDifference,
+			strataX: []int{5, 5}, strataY: []int{5, 7, 7}, alt: Less,
+			stat: -99.5, extreme: 1, count: 3,
+		},
+	} {
+		for _, workers := range []int{1, 3} {
+			got := TwoSample(test.x, test.y, test.fn, test.strataX, test.strataY, test.alt, &Settings{Workers: workers})
+			if !got.Exact {
+				t.Errorf("%s: test not exact", test.name)
+			}
+			if got.Statistic != test.stat {
+				t.Errorf("%s: unexpected statistic: got %v want %v", test.name, got.Statistic, test.stat)
+			}
+			if got.Extreme != test.extreme || got.Rearrangements != test.count {
+				t.Errorf("%s: unexpected count: got %d/%d want %d/%d", test.name, got.Extreme, got.Rearrangements, test.extreme, test.count)
+			}
+			if want := float64(test.extreme) / float64(test.count); got.P != want {
+				t.Errorf("%s: unexpected p-value: got %v want %v", test.name, got.P, want)
+			}
+			if lo, hi := got.PBounds(0.95); lo != got.P || hi != got.P {
+				t.Errorf("%s: unexpected p-value bounds for exact test: got [%v, %v] want [%v, %v]", test.name, lo, hi, got.P, got.P)
+			}
+		}
+	}
+}
+
+func TestAssociationExact(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3, 4}
+	y := []float64{1, 2, 3, 4}
+	// Of the 24 permutations only the identity has
+	// correlation 1 and only the reversal has -1.
+	for _, test := range []struct {
+		alt     Alternative
+		extreme int
+	}{
+		{alt: Greater, extreme: 1},
+		{alt: TwoSided, extreme: 2},
+		{alt: Less, extreme: 24},
+	} {
+		got := Association(x, y, Correlation, nil, test.alt, nil)
+		if !got.Exact || got.Rearrangements != 24 {
+			t.Errorf("alternative %d: unexpected rearrangements: got %d exact=%t want 24 exact=true", test.alt, got.Rearrangements, got.Exact)
+		}
+		if got.Extreme != test.extreme {
+			t.Errorf("alternative %d: unexpected extreme count: got %d want %d", test.alt, got.Extreme, test.extreme)
+		}
+	}
+
+	// Permuting within the strata {0, 1} and {2, 3}
+	// gives 4 permutations, of which only the identity
+	// has correlation 1.
+	got := Association(x, y, Correlation, []int{0, 0, 1, 1}, Greater, nil)
+	if !got.Exact || got.Rearrangements != 4 || got.Extreme != 1 || got.P != 0.25 {
+		t.Errorf("unexpected stratified result: got %+v", got)
+	}
+}
+
+func TestPermutationMonteCarlo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := normalSample(7, rnd)
+	y := normalSample(7, rnd)
+	floats.AddConst(0.8, y)
+
+	// There are Binomial(14, 7) = 3432 assignments.
+	exact := TwoSample(x, y, MeanDifference, nil, nil, TwoSided, &Settings{Replicates: 5000})
+	if !exact.Exact || exact.Rearrangements != 3432 {
+		t.Fatalf("unexpected exact test: got %d rearrangements exact=%t", exact.Rearrangements, exact.Exact)
+	}
+	var want *PermutationResult
+	for _, workers := range []int{1, 4} {
+		mc := TwoSample(x, y, MeanDifference, nil, nil, TwoSided, &Settings{Replicates: 2000, Workers: workers, Src: rand.NewSource(1)})
+		if mc.Exact || mc.Rearrangements != 2000 {
+			t.Fatalf("unexpected Monte Carlo test: got %d rearrangements exact=%t", mc.Rearrangements, mc.Exact)
+		}
+		if want == nil {
+			want = &mc
+		} else if mc != *want {
+			t.Errorf("result depends on number of workers: got %+v want %+v", mc, *want)
+		}
+		if p := float64(mc.Extreme+1) / 2001; mc.P != p {
+			t.Errorf("unexpected Monte Carlo p-value: got %v want %v", mc.P, p)
+		}
+		lo, hi := mc.PBounds(0.999)
+		if !(lo <= exact.P && exact.P <= hi) {
+			t.Errorf("exact p-value outside Monte Carlo bounds: %v not in [%v, %v]", exact.P, lo, hi)
+		}
+	}
+
+	// Independent pairs with 10! permutations.
+	a := normalSample(10, rnd)
+	b := normalSample(10, rnd)
+	r := Association(a, b, Correlation, nil, TwoSided, &Settings{Replicates: 1000, Src: rand.NewSource(1)})
+	if r.Exact {
+		t.Error("unexpected exact association test")
+	}
+	if r.Statistic != Correlation(a, b) {
+		t.Errorf("unexpected statistic: got %v want %v", r.Statistic, Correlation(a, b))
+	}
+}
+
+func TestPBounds(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		extreme, n int
+		level      float64
+		lo, hi     float64
+	}{
+		// For k = 0 the upper bound solves (1-p)^n = α/2.
+		{extreme: 0, n: 100, level: 0.95, lo: 0, hi: 1 - math.Pow(0.025, 1.0/100)},
+		// For k = n the lower bound solves p^n = α/2.
+		{extreme: 50, n: 50, level: 0.9, lo: math.Pow(0.05, 1.0/50), hi: 1},
+		// For n = 1 and k = 1 the lower bound is α/2.
+		{extreme: 1, n: 1, level: 0.8, lo: 0.1, hi: 1},
+	} {
+		r := PermutationResult{Extreme: test.extreme, Rearrangements: test.n}
+		lo, hi := r.PBounds(test.level)
+		if !floats.EqualWithinAbsOrRel(lo, test.lo, 1e-10, 1e-10) || !floats.EqualWithinAbsOrRel(hi, test.hi, 1e-10, 1e-10) {
+			t.Errorf("unexpected bounds for %d/%d: got [%v, %v] want [%v, %v]", test.extreme, test.n, lo, hi, test.lo, test.hi)
+		}
+	}
+	// The bounds are nested in the level.
+	r := PermutationResult{Extreme: 30, Rearrangements: 999}
+	lo90, hi90 := r.PBounds(0.9)
+	lo99, hi99 := r.PBounds(0.99)
+	if !(lo99 < lo90 && lo90 < 0.03 && 0.03 < hi90 && hi90 < hi99) {
+		t.Errorf("bounds not nested: [%v, %v] and [%v, %v]", lo90, hi90, lo99, hi99)
+	}
+}
+
+func TestIndexToPermutation(t *testing.T) {
+	t.Parallel()
+	const n = 4
+	seen := make(map[[n]int]bool)
+	var prev []int
+	for i := 0; i < factorial(n); i++ {
+		p := indexToPermutation(i, n)
+		var key [n]int
+		copy(key[:], p)
+		if seen[key] {
+			t.Errorf("duplicate permutation %v at index %d", p, i)
+		}
+		seen[key] = true
+		if prev != nil && !lexLess(prev, p) {
+			t.Errorf("permutations not in lexicographic order: %v then %v", prev, p)
+		}
+		prev = p
+	}
+}
+
+func lexLess(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func TestPermutationPanics(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 2, 3}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "empty", fn: func() { TwoSample(x, nil, MeanDifference, nil, nil, TwoSided, nil) }},
+		{name: "missing strata", fn: func() { TwoSample(x, x, MeanDifference, []int{0, 0, 0}, nil, TwoSided, nil) }},
+		{name: "strata length", fn: func() { TwoSample(x, x, MeanDifference, []int{0, 0}, []int{0, 0, 0}, TwoSided, nil) }},
+		{name: "alternative", fn: func() { TwoSample(x, x, MeanDifference, nil, nil, Alternative(-1), nil) }},
+		{name: "pair length", fn: func() { Association(x, x[:2], Correlation, nil, TwoSided, nil) }},
+		{name: "association strata", fn: func() { Association(x, x, Correlation, []int{0}, TwoSided, nil) }},
+		{name: "level", fn: func() { PermutationResult{}.PBounds(0) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
@@ -0,0 +1,708 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Order is the order of the non-seasonal part of an ARIMA model.
+type Order struct {
+	// P is the autoregressive order, D is
+	// the order of differencing and Q is the
+	// moving average order.
+	P, D, Q int
+}
+
+// Seasonal is the order of the seasonal part of an ARIMA model.
+type Seasonal struct {
+	// P is the seasonal autoregressive order,
+	// D is the order of seasonal differencing
+	// and Q is the seasonal moving average
+	// order, in units of the period.
+	P, D, Q int
+
+	// Period is the number of observations
+	// in a season. Period must be at least
+	// two if any of the seasonal orders are
+	// non-zero.
+	Period int
+}
+
+// Method is the method used to estimate the parameters of an ARIMA model.
+type Method int
+
+const (
+	// MaximumLikelihood maximizes the exact Gaussian
+	// likelihood of the differenced series, computed
+	// by the Kalman filter of its state space form,
+	// starting from the conditional sum of squares
+	// estimates.
+	MaximumLikelihood Method = iota
+
+	// ConditionalSumOfSquares minimizes the sum of
+	// squares of the innovations conditional on the
+	// first p+s*P observations of the differenced
+	// series and on zero earlier innovations.
+	ConditionalSumOfSquares
+)
+
+// ARIMASettings holds the settings for FitARIMA.
+type ARIMASettings struct {
+	// Seasonal is the seasonal order of
+	// the model. The zero value specifies
+	// a non-seasonal model.
+	Seasonal Seasonal
+
+	// Mean specifies whether the mean of the
+	// series is estimated. Otherwise the mean
+	// is zero. The mean may only be estimated
+	// for series that are not differenced.
+	Mean bool
+
+	// Method is the estimation method.
+	Method Method
+
+	// Optimize holds the settings passed to
+	// optimize.Minimize, which minimizes the
+	// objective function with the Nelder-Mead
+	// method. If Optimize is nil, the
+	// default settings are used.
+	Optimize *optimize.Settings
+}
+
+// ARIMA is a seasonal autoregressive integrated moving average model fitted
+// by FitARIMA. See the package documentation for the form of the model.
+type ARIMA struct {
+	// Order and Seasonal are the order
+	// of the model.
+	Order    Order
+	Seasonal Seasonal
+
+	// AR and MA are the non-seasonal
+	// coefficients φ and θ, and SeasonalAR
+	// and SeasonalMA are the seasonal
+	// coefficients Φ and Θ.
+	AR, MA                 []float64
+	SeasonalAR, SeasonalMA []float64
+
+	// Mean is the mean μ of the series.
+	Mean float64
+
+	// Variance is the estimated variance σ²
+	// of the innovations.
+	Variance float64
+
+	// LogLikelihood is the maximized log
+	// likelihood of the differenced series.
+	// For models estimated by conditional sum
+	// of squares it is the conditional log
+	// likelihood.
+	LogLikelihood float64
+
+	// Residuals holds the estimated innovations
+	// of the differenced series, which has
+	// d+s*D fewer observations than the series.
+	// For models estimated by maximum likelihood
+	// these are the standardized innovations of
+	// the Kalman filter, and for models estimated
+	// by conditional sum of squares the first
+	// p+s*P residuals are zero.
+	Residuals []float64
+
+	// NumObservations is the number of
+	// observations in the likelihood.
+	NumObservations int
+
+	hasMean bool
+	y       []float64
+}
+
+// FitARIMA fits the ARIMA model of the given order to the series y. The
+// seasonal order, the estimation of the mean and the estimation method are
+// specified by settings, and if settings is nil a non-seasonal model with
+// zero mean is fitted by maximum likelihood.
+//
+// The autoregressive polynomials are parameterized by their partial
+// autocorrelations, so the fitted model is always stationary. The moving
+// average polynomials are not constrained to be invertible.
+//
+// If the optimization of the parameters returns an error and a location,
+// FitARIMA returns the model at that location and the error.
+//
+// FitARIMA panics if an order is negative, if the seasonal period or the
+// method is invalid, if the mean is estimated for a differenced series or if
+// the differenced series has too few observations for the number of
+// parameters.
+func FitARIMA(y []float64, order Order, settings *ARIMASettings) (*ARIMA, error) {
+	var s ARIMASettings
+	if settings != nil {
+		s = *settings
+	}
+	season := s.Seasonal
+	if order.P < 0 || order.D < 0 || order.Q < 0 || season.P < 0 || season.D < 0 || season.Q < 0 {
+		panic("timeseries: negative order")
+	}
+	if season.P+season.D+season.Q == 0 {
+		season.Period = 0
+	} else if season.Period < 2 {
+		panic("timeseries: invalid seasonal period")
+	}
+	if s.Mean && order.D+season.D > 0 {
+		panic("timeseries: mean of differenced series")
+	}
+	if s.Method != MaximumLikelihood && s.Method != ConditionalSumOfSquares {
+		panic("timeseries: invalid method")
+	}
+
+	w := difference(y, order.D, season.D, season.Period)
+	p := armaParams{
+		order:    order,
+		seasonal: season,
+		mean:     s.Mean,
+	}
+	if s.Mean {
+		p.mean0 = stat.Mean(w, nil)
+		p.scale = math.Sqrt(stat.MomentAbout(2, w, p.mean0, nil))
+		if p.scale == 0 {
+			p.scale = 1
+		}
+	}
+	np := p.numParams()
+	ncond := order.P + season.Period*season.P
+	if len(w)-ncond <= np+1 {
+		panic("timeseries: too few observations")
+	}
+
+	x := make([]float64, np)
+	res, err := p.minimize(x, func(x []float64) float64 {
+		ssq, n := p.css(nil, w, x)
+		return 0.5 * math.Log(ssq/float64(n))
+	}, s.Optimize)
+	if res == nil {
+		return nil, err
+	}
+	copy(x, res.X)
+	if s.Method == MaximumLikelihood {
+		res, err = p.minimize(x, func(x []float64) float64 {
+			ssq, sumLog, ok := p.exact(nil, w, x)
+			if !ok {
+				return math.Inf(1)
+			}
+			n := float64(len(w))
+			return 0.5 * (math.Log(ssq/n) + sumLog/n)
+		}, s.Optimize)
+		if res == nil {
+			return nil, err
+		}
+		copy(x, res.X)
+	}
+
+	m := &ARIMA{
+		Order:     order,
+		Seasonal:  season,
+		Residuals: make([]float64, len(w)),
+		hasMean:   s.Mean,
+		y:         append([]float64(nil), y...),
+	}
+	m.AR, m.MA, m.SeasonalAR, m.SeasonalMA, m.Mean = p.unpack(x)
+	switch s.Method {
+	case MaximumLikelihood:
+		ssq, sumLog, _ := p.exact(m.Residuals, w, x)
+		n := float64(len(w))
+		m.NumObservations = len(w)
+		m.Variance = ssq / n
+		m.LogLikelihood = -0.5 * (n*math.Log(2*math.Pi*m.Variance) + sumLog + n)
+	case ConditionalSumOfSquares:
+		ssq, n := p.css(m.Residuals, w, x)
+		m.NumObservations = n
+		m.Variance = ssq / float64(n)
+		m.LogLikelihood = -0.5 * float64(n) * (math.Log(2*math.Pi*m.Variance) + 1)
+	}
+	return m, err
+}
+
+// SelectARIMA returns the model with the least AICc among the models fitted
+// by FitARIMA to the series y with autoregressive orders from zero to maxP,
+// moving average orders from zero to maxQ and the order of differencing d.
+// The remaining order of the models and the estimation are specified by
+// settings as for FitARIMA. Models whose fit returns an error are not
+// considered, and if no model can be fitted SelectARIMA returns a nil model
+// and the last error.
+//
+// Models with different orders of differencing are fitted to different
+// series, so their information criteria cannot be compared and d is not
+// selected. SelectARIMA panics if the orders are invalid or if the series
+// has too few observations for the largest model as for FitARIMA.
+func SelectARIMA(y []float64, maxP, d, maxQ int, settings *ARIMASettings) (*ARIMA, error) {
+	if maxP < 0 || maxQ < 0 {
+		panic("timeseries: negative order")
+	}
+	var (
+		best    *ARIMA
+		bestIC  = math.Inf(1)
+		lastErr error
+	)
+	for p := 0; p <= maxP; p++ {
+		for q := 0; q <= maxQ; q++ {
+			m, err := FitARIMA(y, Order{P: p, D: d, Q: q}, settings)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+			if ic := m.AICc(); best == nil || ic < bestIC {
+				best, bestIC = m, ic
+			}
+		}
+	}
+	if best == nil {
+		return nil, lastErr
+	}
+	return best, nil
+}
+
+// NumParameters returns the number of estimated parameters of the model,
+// including the variance of the innovations.
+func (m *ARIMA) NumParameters() int {
+	k := len(m.AR) + len(m.MA) + len(m.SeasonalAR) + len(m.SeasonalMA) + 1
+	if m.hasMean {
+		k++
+	}
+	return k
+}
+
+// AIC returns the Akaike information criterion of the model,
+//
+//	AIC = 2k - 2 log L,
+//
+// where k is the number of parameters and L is the likelihood.
+func (m *ARIMA) AIC() float64 {
+	return 2*float64(m.NumParameters()) - 2*m.LogLikelihood
+}
+
+// AICc returns the Akaike information criterion of the model corrected for
+// small samples,
+//
+//	AICc = AIC + 2k(k+1)/(n-k-1),
+//
+// where k is the number of parameters and n is the number of observations.
+// AICc returns +Inf if n is at most k+1.
+func (m *ARIMA) AICc() float64 {
+	k := float64(m.NumParameters())
+	n := float64(m.NumObservations)
+	if n <= k+1 {
+		return math.Inf(1)
+	}
+	return m.AIC() + 2*k*(k+1)/(n-k-1)
+}
+
+// BIC returns the Bayesian information criterion of the model,
+//
+//	BIC = k log(n) - 2 log L,
+//
+// where k is the number of parameters, n is the number of observations and
+// L is the likelihood.
+func (m *ARIMA) BIC() float64 {
+	return float64(m.NumParameters())*math.Log(float64(m.NumObservations)) - 2*m.LogLikelihood
+}
+
+// Forecast stores the forecasts of the next len(mean) values of the series
+// into mean. If lower and upper are not nil, the bounds of the prediction
+// intervals of the forecasts with the given coverage level are stored into
+// them. The forecasts are computed conditional on the observed series and
+// the residuals of the model, and the standard errors of the forecasts are
+// computed from the ψ-weights of the model, treating the parameters as
+// known.
+//
+// Forecast panics if lower or upper are not nil and their lengths are not
+// the length of mean, or if they are not nil and level is not in (0, 1).
+func (m *ARIMA) Forecast(mean, lower, upper []float64, level float64) {
+	h := len(mean)
+	if (lower != nil && len(lower) != h) || (upper != nil && len(upper) != h) {
+		panic("timeseries: slice length mismatch")
+	}
+	if (lower != nil || upper != nil) && !(0 < level && level < 1) {
+		panic("timeseries: invalid level")
+	}
+
+	// Expand the model to the polynomials of the
+	// series, including the differencing.
+	s := m.Seasonal.Period
+	ar := polyMul(arPoly(m.AR, 1), arPoly(m.SeasonalAR, s))
+	ar = polyMul(ar, differencePoly(m.Order.D, m.Seasonal.D, s))
+	ma := polyMul(maPoly(m.MA, 1), maPoly(m.SeasonalMA, s))
+
+	n := len(m.y)
+	nd := n - len(m.Residuals)
+	z := make([]float64, n+h)
+	e := make([]float64, n+h)
+	for i, v := range m.y {
+		z[i] = v - m.Mean
+	}
+	copy(e[nd:], m.Residuals)
+	for t := n; t < n+h; t++ {
+		var v float64
+		for i := 1; i < len(ar) && i <= t; i++ {
+			v -= ar[i] * z[t-i]
+		}
+		for j := 1; j < len(ma) && j <= t; j++ {
+			v += ma[j] * e[t-j]
+		}
+		z[t] = v
+		mean[t-n] = v + m.Mean
+	}
+	if lower == nil && upper == nil {
+		return
+	}
+
+	// Compute the ψ-weights of the model and the
+	// standard errors of the forecasts.
+	psi := make([]float64, h)
+	psi[0] = 1
+	for j := 1; j < h; j++ {
+		var v float64
+		if j < len(ma) {
+			v = ma[j]
+		}
+		for i := 1; i < len(ar) && i <= j; i++ {
+			v -= ar[i] * psi[j-i]
+		}
+		psi[j] = v
+	}
+	q := distuv.UnitNormal.Quantile(0.5 + level/2)
+	var sum float64
+	for i, v := range psi {
+		sum += v * v
+		d := q * math.Sqrt(m.Variance*sum)
+		if lower != nil {
+			lower[i] = mean[i] - d
+		}
+		if upper != nil {
+			upper[i] = mean[i] + d
+		}
+	}
+}
+
+// LjungBox returns the Ljung–Box statistic and its p-value for the first lags
+// autocorrelations of the residuals of the model, with the degrees of freedom
+// of the test reduced by the number of autoregressive and moving average
+// coefficients. See LjungBox for details.
+func (m *ARIMA) LjungBox(lags int) (q, p float64) {
+	dof := len(m.AR) + len(m.MA) + len(m.SeasonalAR) + len(m.SeasonalMA)
+	return LjungBox(m.Residuals[len(m.Residuals)-m.NumObservations:], lags, dof)
+}
+
+// armaParams describes the mapping between the vector of parameters optimized
+// by FitARIMA and the coefficients of an ARIMA model.
+type armaParams struct {
+	order    Order
+	seasonal Seasonal
+
+	// mean specifies whether the mean is
+	// estimated. The mean is represented
+	// by its deviation from mean0 in units
+	// of scale.
+	mean         bool
+	mean0, scale float64
+}
+
+// numParams returns the number of parameters.
+func (p armaParams) numParams() int {
+	n := p.order.P + p.order.Q + p.seasonal.P + p.seasonal.Q
+	if p.mean {
+		n++
+	}
+	return n
+}
+
+// unpack returns the coefficients of the model at the parameters x.
+func (p armaParams) unpack(x []float64) (ar, ma, sar, sma []float64, mean float64) {
+	ar = pacfToAR(x[:p.order.P])
+	x = x[p.order.P:]
+	ma = append([]float64(nil), x[:p.order.Q]...)
+	x = x[p.order.Q:]
+	sar = pacfToAR(x[:p.seasonal.P])
+	x = x[p.seasonal.P:]
+	sma = append([]float64(nil), x[:p.seasonal.Q]...)
+	x = x[p.seasonal.Q:]
+	if p.mean {
+		mean = p.mean0 + p.scale*x[0]
+	}
+	return ar, ma, sar, sma, mean
+}
+
+// polys returns the expanded autoregressive and moving average polynomials
+// and the mean of the model at the parameters x.
+func (p armaParams) polys(x []float64) (ar, ma []float64, mean float64) {
+	a, b, sa, sb, mean := p.unpack(x)
+	s := p.seasonal.Period
+	return polyMul(arPoly(a, 1), arPoly(sa, s)), polyMul(maPoly(b, 1), maPoly(sb, s)), mean
+}
+
+// minimize minimizes f starting from x using the Nelder-Mead method.
+func (p armaParams) minimize(x []float64, f func([]float64) float64, settings *optimize.Settings) (*optimize.Result, error) {
+	if len(x) == 0 {
+		// There is nothing to optimize.
+		return &optimize.Result{Location: optimize.Location{X: x, F: f(x)}}, nil
+	}
+	return optimize.Minimize(optimize.Problem{Func: f}, x, settings, &optimize.NelderMead{})
+}
+
+// css returns the conditional sum of squares of the innovations of the
+// differenced series w at the parameters x and the number of innovations in
+// the sum. If resid is not nil, the innovations are stored into it.
+func (p armaParams) css(resid, w, x []float64) (ssq float64, n int) {
+	ar, ma, mean := p.polys(x)
+	ncond := len(ar) - 1
+	e := resid
+	if e == nil {
+		e = make([]float64, len(w))
+	}
+	for t := ncond; t < len(w); t++ {
+		var v float64
+		for i, c := range ar {
+			v += c * (w[t-i] - mean)
+		}
+		for j := 1; j < len(ma) && j <= t-ncond; j++ {
+			v -= ma[j] * e[t-j]
+		}
+		e[t] = v
+		ssq += v * v
+	}
+	return ssq, len(w) - ncond
+}
+
+// exact returns the sum of squared standardized innovations and the sum of
+// the logs of the innovation variances from the Kalman filter of the
+// differenced series w at the parameters x, in units of the innovation
+// variance σ², and whether the filter succeeded. If resid is not nil, the
+// standardized innovations are stored into it.
+func (p armaParams) exact(resid, w, x []float64) (ssq, sumLog float64, ok bool) {
+	ar, ma, mean := p.polys(x)
+	r := len(ar)
+	if len(ma) > r {
+		r = len(ma)
+	}
+	phi := make([]float64, r)
+	for i := 1; i < len(ar); i++ {
+		phi[i-1] = -ar[i]
+	}
+	theta := make([]float64, r)
+	copy(theta, ma)
+
+	// The state of the model in Harvey's form is
+	//  α_{t+1} = T α_t + R e_t, z_t = α_t[0],
+	// where T has phi in its first column and ones
+	// on its superdiagonal and R is theta. The
+	// state is initialized with its stationary
+	// distribution.
+	pm, ok := stationaryCov(phi, theta)
+	if !ok {
+		return 0, 0, false
+	}
+	a := make([]float64, r)
+	au := make([]float64, r)
+	col := make([]float64, r)
+	tp := make([]float64, r*r)
+	for t, v := range w {
+		f := pm[0]
+		if !(f > 0) || math.IsInf(f, 0) {
+			return 0, 0, false
+		}
+		inn := v - mean - a[0]
+		ssq += inn * inn / f
+		sumLog += math.Log(f)
+		if resid != nil {
+			resid[t] = inn / math.Sqrt(f)
+		}
+
+		// Update the state with the observation.
+		for i := range col {
+			col[i] = pm[i*r]
+			au[i] = a[i] + col[i]*inn/f
+		}
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				pm[i*r+j] -= col[i] * col[j] / f
+			}
+		}
+
+		// Predict the next state.
+		for i := range a {
+			a[i] = phi[i] * au[0]
+			if i+1 < r {
+				a[i] += au[i+1]
+			}
+		}
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				v := phi[i] * pm[j]
+				if i+1 < r {
+					v += pm[(i+1)*r+j]
+				}
+				tp[i*r+j] = v
+			}
+		}
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				v := phi[j]*tp[i*r] + theta[i]*theta[j]
+				if j+1 < r {
+					v += tp[i*r+j+1]
+				}
+				pm[i*r+j] = v
+			}
+		}
+	}
+	return ssq, sumLog, true
+}
+
+// stationaryCov returns the covariance matrix P of the stationary distribution
+// of the state in row-major order, the solution of
+//
+//	P = T P Tᵀ + R Rᵀ,
+//
+// where T has phi in its first column and ones on its superdiagonal and R is
+// theta, and whether the solution converged.
+func stationaryCov(phi, theta []float64) ([]float64, bool) {
+	// Use the doubling algorithm,
+	//  P_{k+1} = P_k + A_k P_k A_kᵀ, A_{k+1} = A_k²,
+	// with P_0 = R Rᵀ and A_0 = T, where P_k sums
+	// the first 2^k terms of Σ T^i R Rᵀ (T^i)ᵀ.
+	r := len(phi)
+	pm := make([]float64, r*r)
+	am := make([]float64, r*r)
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			pm[i*r+j] = theta[i] * theta[j]
+		}
+		am[i*r] = phi[i]
+		if i+1 < r {
+			am[i*r+i+1] = 1
+		}
+	}
+	const maxIter = 100
+	tmp := make([]float64, r*r)
+	ap := make([]float64, r*r)
+	for k := 0; k < maxIter; k++ {
+		matMul(ap, am, pm, r, false)
+		matMul(tmp, ap, am, r, true)
+		floats.Add(pm, tmp)
+		if floats.Norm(tmp, math.Inf(1)) <= 1e-15*floats.Norm(pm, math.Inf(1)) {
+			return pm, true
+		}
+		matMul(tmp, am, am, r, false)
+		copy(am, tmp)
+	}
+	return nil, false
+}
+
+// matMul stores a*b, or a*bᵀ if trans is true, into dst, where a, b and dst
+// are r×r matrices in row-major order.
+func matMul(dst, a, b []float64, r int, trans bool) {
+	for i := 0; i < r; i++ {
+		for j := 0; j < r; j++ {
+			var v float64
+			for k := 0; k < r; k++ {
+				if trans {
+					v += a[i*r+k] * b[j*r+k]
+				} else {
+					v += a[i*r+k] * b[k*r+j]
+				}
+			}
+			dst[i*r+j] = v
+		}
+	}
+}
+
+// pacfToAR returns the coefficients of the stationary autoregressive
+// polynomial whose partial autocorrelations are tanh(u) using the
+// Durbin–Levinson recursion.
+func pacfToAR(u []float64) []float64 {
+	phi := make([]float64, len(u))
+	prev := make([]float64, len(u))
+	for k, v := range u {
+		r := math.Tanh(v)
+		copy(prev, phi[:k])
+		for j := 0; j < k; j++ {
+			phi[j] = prev[j] - r*prev[k-1-j]
+		}
+		phi[k] = r
+	}
+	return phi
+}
+
+// difference returns the series y differenced d times at lag one and sd
+// times at lag s.
+func difference(y []float64, d, sd, s int) []float64 {
+	w := append([]float64(nil), y...)
+	for i := 0; i < d; i++ {
+		w = diffLag(w, 1)
+	}
+	for i := 0; i < sd; i++ {
+		w = diffLag(w, s)
+	}
+	return w
+}
+
+// diffLag returns the series y differenced at the given lag.
+func diffLag(y []float64, lag int) []float64 {
+	if len(y) <= lag {
+		return nil
+	}
+	w := make([]float64, len(y)-lag)
+	for i := range w {
+		w[i] = y[i+lag] - y[i]
+	}
+	return w
+}
+
+// arPoly returns the coefficients of the polynomial 1 - Σ phi_i B^(s*i).
+func arPoly(phi []float64, s int) []float64 {
+	c := make([]float64, len(phi)*s+1)
+	c[0] = 1
+	for i, v := range phi {
+		c[(i+1)*s] = -v
+	}
+	return c
+}
+
+// maPoly returns the coefficients of the polynomial 1 + Σ theta_i B^(s*i).
+func maPoly(theta []float64, s int) []float64 {
+	c := make([]float64, len(theta)*s+1)
+	c[0] = 1
+	for i, v := range theta {
+		c[(i+1)*s] = v
+	}
+	return c
+}
+
+// differencePoly returns the coefficients of the polynomial (1-B)^d (1-B^s)^sd.
+func differencePoly(d, sd, s int) []float64 {
+	c := []float64{1}
+	for i := 0; i < d; i++ {
+		c = polyMul(c, []float64{1, -1})
+	}
+	for i := 0; i < sd; i++ {
+		c = polyMul(c, arPoly([]float64{1}, s))
+	}
+	return c
+}
+
+// polyMul returns the coefficients of the product of the polynomials with
+// coefficients a and b.
+func polyMul(a, b []float64) []float64 {
+	c := make([]float64, len(a)+len(b)-1)
+	for i, u := range a {
+		for j, v := range b {
+			c[i+j] += u * v
+		}
+	}
+	return c
+}
@@ -0,0 +1,374 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// simulate returns n observations of the ARIMA model m with unit variance
+// innovations after discarding burn observations.
+func simulate(n, burn int, m *ARIMA, rnd *rand.Rand) []float64 {
+	s := m.Seasonal.Period
+	ar := polyMul(arPoly(m.AR, 1), arPoly(m.SeasonalAR, s))
+	ar = polyMul(ar, differencePoly(m.Order.D, m.Seasonal.D, s))
+	ma := polyMul(maPoly(m.MA, 1), maPoly(m.SeasonalMA, s))
+	z := make([]float64, n+burn)
+	e := make([]float64, n+burn)
+	for t := range z {
+		e[t] = rnd.NormFloat64()
+		v := e[t]
+		for i := 1; i < len(ar) && i <= t; i++ {
+			v -= ar[i] * z[t-i]
+		}
+		for j := 1; j < len(ma) && j <= t; j++ {
+			v += ma[j] * e[t-j]
+		}
+		z[t] = v
+	}
+	y := z[burn:]
+	for i := range y {
+		y[i] += m.Mean
+	}
+	return y
+}
+
+func TestFitARIMA(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		model    ARIMA
+		mean     bool
+		n        int
+		tol      float64
+		settings *ARIMASettings
+	}{
+		{
+			model: ARIMA{Order: Order{P: 1}, AR: []float64{0.6}, Mean: 5},
+			mean:  true,
+			n:     500,
+			tol:   0.1,
+		},
+		{
+			model: ARIMA{Order: Order{P: 2}, AR: []float64{0.5, -0.3}},
+			n:     500,
+			tol:   0.1,
+		},
+		{
+			model: ARIMA{Order: Order{P: 1, Q: 1}, AR: []float64{0.7}, MA: []float64{0.4}, Mean: -2},
+			mean:  true,
+			n:     800,
+			tol:   0.1,
+		},
+		{
+			model: ARIMA{Order: Order{D: 1, Q: 2}, MA: []float64{-0.4, 0.3}},
+			n:     600,
+			tol:   0.1,
+		},
+		{
+			// The airline model.
+			model: ARIMA{
+				Order:      Order{D: 1, Q: 1},
+				Seasonal:   Seasonal{D: 1, Q: 1, Period: 12},
+				MA:         []float64{-0.4},
+				SeasonalMA: []float64{-0.6},
+			},
+			n:   480,
+			tol: 0.12,
+		},
+		{
+			model: ARIMA{
+				Order:      Order{P: 1},
+				Seasonal:   Seasonal{P: 1, Period: 4},
+				AR:         []float64{0.5},
+				SeasonalAR: []float64{0.4},
+			},
+			n:   600,
+			tol: 0.1,
+		},
+	} {
+		rnd := rand.New(rand.NewSource(uint64(i + 1)))
+		y := simulate(test.n, 200, &test.model, rnd)
+		for _, method := range []Method{MaximumLikelihood, ConditionalSumOfSquares} {
+			m, err := FitARIMA(y, test.model.Order, &ARIMASettings{
+				Seasonal: test.model.Seasonal,
+				Mean:     test.mean,
+				Method:   method,
+			})
+			if err != nil {
+				t.Errorf("test %d method %d: unexpected error: %v", i, method, err)
+				continue
+			}
+			for _, c := range []struct {
+				name      string
+				got, want []float64
+			}{
+				{name: "AR", got: m.AR, want: test.model.AR},
+				{name: "MA", got: m.MA, want: test.model.MA},
+				{name: "seasonal AR", got: m.SeasonalAR, want: test.model.SeasonalAR},
+				{name: "seasonal MA", got: m.SeasonalMA, want: test.model.SeasonalMA},
+			} {
+				if len(c.got) != len(c.want) {
+					t.Errorf("test %d method %d: unexpected number of %s coefficients: got %d want %d", i, method, c.name, len(c.got), len(c.want))
+					continue
+				}
+				if !floats.EqualApprox(c.got, c.want, test.tol) {
+					t.Errorf("test %d method %d: unexpected %s coefficients: got %v want %v", i, method, c.name, c.got, c.want)
+				}
+			}
+			if math.Abs(m.Mean-test.model.Mean) > 0.3 {
+				t.Errorf("test %d method %d: unexpected mean: got %v want %v", i, method, m.Mean, test.model.Mean)
+			}
+			if math.Abs(m.Variance-1) > 0.15 {
+				t.Errorf("test %d method %d: unexpected variance: got %v want 1", i, method, m.Variance)
+			}
+			if _, p := m.LjungBox(20); p < 0.01 {
+				t.Errorf("test %d method %d: residuals not independent: p = %v", i, method, p)
+			}
+		}
+	}
+}
+
+func TestARIMALogLikelihood(t *testing.T) {
+	t.Parallel()
+	// Compare the exact likelihood of an ARMA(1, 1) model
+	// with the density of the multivariate normal distribution
+	// of its observations.
+	const (
+		n     = 30
+		phi   = 0.6
+		theta = -0.3
+		mean  = 1.5
+	)
+	rnd := rand.New(rand.NewSource(1))
+	y := simulate(n, 100, &ARIMA{Order: Order{P: 1, Q: 1}, AR: []float64{phi}, MA: []float64{theta}, Mean: mean}, rnd)
+
+	p := armaParams{order: Order{P: 1, Q: 1}, mean: true, mean0: mean, scale: 1}
+	x := []float64{math.Atanh(phi), theta, 0}
+	ssq, sumLog, ok := p.exact(nil, y, x)
+	if !ok {
+		t.Fatal("unexpected failure of Kalman filter")
+	}
+	got := -0.5 * (n*math.Log(2*math.Pi) + sumLog + ssq)
+
+	gamma := make([]float64, n)
+	gamma[0] = (1 + 2*phi*theta + theta*theta) / (1 - phi*phi)
+	gamma[1] = (1 + phi*theta) * (phi + theta) / (1 - phi*phi)
+	for k := 2; k < n; k++ {
+		gamma[k] = phi * gamma[k-1]
+	}
+	cov := mat.NewSymDense(n, nil)
+	mu := make([]float64, n)
+	for i := 0; i < n; i++ {
+		mu[i] = mean
+		for j := i; j < n; j++ {
+			cov.SetSym(i, j, gamma[j-i])
+		}
+	}
+	dist, ok := distmv.NewNormal(mu, cov, nil)
+	if !ok {
+		t.Fatal("covariance not positive definite")
+	}
+	want := dist.LogProb(y)
+	if math.Abs(got-want) > 1e-10*math.Abs(want) {
+		t.Errorf("log likelihood mismatch: got %v want %v", got, want)
+	}
+}
+
+func TestARIMAForecast(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// The forecasts of an AR(1) model decay geometrically
+	// to the mean.
+	y := simulate(300, 100, &ARIMA{Order: Order{P: 1}, AR: []float64{0.8}, Mean: 3}, rnd)
+	m, err := FitARIMA(y, Order{P: 1}, &ARIMASettings{Mean: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const h = 10
+	mean := make([]float64, h)
+	lower := make([]float64, h)
+	upper := make([]float64, h)
+	m.Forecast(mean, lower, upper, 0.95)
+	phi := m.AR[0]
+	last := y[len(y)-1] - m.Mean
+	var sum float64
+	for i := range mean {
+		want := m.Mean + math.Pow(phi, float64(i+1))*last
+		if math.Abs(mean[i]-want) > 1e-12 {
+			t.Errorf("unexpected AR(1) forecast at %d: got %v want %v", i, mean[i], want)
+		}
+		sum += math.Pow(phi, float64(2*i))
+		se := math.Sqrt(m.Variance * sum)
+		if math.Abs(upper[i]-mean[i]-1.959963984540054*se) > 1e-10 || math.Abs(mean[i]-lower[i]-1.959963984540054*se) > 1e-10 {
+			t.Errorf("unexpected AR(1) interval at %d: got [%v, %v] want %v±%v", i, lower[i], upper[i], mean[i], 1.959963984540054*se)
+		}
+	}
+
+	// The forecasts of a random walk are the last
+	// observation with linearly growing variance.
+	y = simulate(200, 0, &ARIMA{Order: Order{D: 1}}, rnd)
+	m, err = FitARIMA(y, Order{D: 1}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.Forecast(mean, nil, upper, 0.9)
+	for i := range mean {
+		if mean[i] != y[len(y)-1] {
+			t.Errorf("unexpected random walk forecast at %d: got %v want %v", i, mean[i], y[len(y)-1])
+		}
+		want := mean[i] + 1.6448536269514722*math.Sqrt(m.Variance*float64(i+1))
+		if math.Abs(upper[i]-want) > 1e-10 {
+			t.Errorf("unexpected random walk upper bound at %d: got %v want %v", i, upper[i], want)
+		}
+	}
+
+	// The forecasts of a seasonal random walk repeat
+	// the last season.
+	y = simulate(100, 0, &ARIMA{Seasonal: Seasonal{D: 1, Period: 4}}, rnd)
+	m, err = FitARIMA(y, Order{}, &ARIMASettings{Seasonal: Seasonal{D: 1, Period: 4}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m.Forecast(mean, nil, nil, 0)
+	for i := range mean {
+		want := y[len(y)-4+i%4]
+		if mean[i] != want {
+			t.Errorf("unexpected seasonal random walk forecast at %d: got %v want %v", i, mean[i], want)
+		}
+	}
+
+	// The coverage of the prediction intervals of a fitted
+	// ARMA model is close to the nominal level.
+	var covered, total int
+	for trial := 0; trial < 100; trial++ {
+		y := simulate(201, 100, &ARIMA{Order: Order{P: 1, Q: 1}, AR: []float64{0.5}, MA: []float64{0.3}}, rnd)
+		m, err := FitARIMA(y[:200], Order{P: 1, Q: 1}, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		mean := make([]float64, 1)
+		lower := make([]float64, 1)
+		upper := make([]float64, 1)
+		m.Forecast(mean, lower, upper, 0.8)
+		if lower[0] <= y[200] && y[200] <= upper[0] {
+			covered++
+		}
+		total++
+	}
+	if frac := float64(covered) / float64(total); math.Abs(frac-0.8) > 0.12 {
+		t.Errorf("unexpected coverage of prediction intervals: got %v want 0.8", frac)
+	}
+}
+
+func TestSelectARIMA(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	y := simulate(400, 100, &ARIMA{Order: Order{P: 2}, AR: []float64{0.6, -0.4}}, rnd)
+	m, err := SelectARIMA(y, 3, 0, 2, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Order != (Order{P: 2}) {
+		t.Errorf("unexpected selected order: got %+v want %+v", m.Order, Order{P: 2})
+	}
+	for p := 0; p <= 3; p++ {
+		for q := 0; q <= 2; q++ {
+			other, err := FitARIMA(y, Order{P: p, Q: q}, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if other.AICc() < m.AICc() {
+				t.Errorf("order %+v has smaller AICc than selected order %+v", other.Order, m.Order)
+			}
+		}
+	}
+}
+
+func TestPACFToAR(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		u := make([]float64, 1+trial%5)
+		for i := range u {
+			u[i] = 2 * rnd.NormFloat64()
+		}
+		phi := pacfToAR(u)
+
+		// The partial autocorrelations recovered from the
+		// autocovariances of the stationary model match.
+		p := len(phi)
+		gamma := arAutocovariance(phi, p+1)
+		for k := 1; k <= p; k++ {
+			// Solve the Yule-Walker equations of order k.
+			a := mat.NewDense(k, k, nil)
+			b := mat.NewVecDense(k, nil)
+			for i := 0; i < k; i++ {
+				for j := 0; j < k; j++ {
+					a.Set(i, j, gamma[abs(i-j)])
+				}
+				b.SetVec(i, gamma[i+1])
+			}
+			var sol mat.VecDense
+			if err := sol.SolveVec(a, b); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := sol.AtVec(k-1), math.Tanh(u[k-1]); math.Abs(got-want) > 1e-8 {
+				t.Errorf("trial %d: unexpected partial autocorrelation at lag %d: got %v want %v", trial, k, got, want)
+			}
+		}
+	}
+}
+
+// arAutocovariance returns the autocovariances of the stationary AR model
+// with coefficients phi at lags zero to n-1 computed from the stationary
+// state covariance.
+func arAutocovariance(phi []float64, n int) []float64 {
+	r := len(phi)
+	if n > r {
+		r = n
+	}
+	padded := make([]float64, r)
+	copy(padded, phi)
+	theta := make([]float64, r)
+	theta[0] = 1
+	pm, _ := stationaryCov(padded, theta)
+
+	// In Harvey's form the first element of the state is the
+	// series, and the autocovariances follow from the state
+	// covariance propagated by the transition matrix.
+	gamma := make([]float64, n)
+	cur := make([]float64, r)
+	for i := range cur {
+		cur[i] = pm[i*r]
+	}
+	gamma[0] = cur[0]
+	next := make([]float64, r)
+	for k := 1; k < n; k++ {
+		for i := range next {
+			next[i] = padded[i] * cur[0]
+			if i+1 < r {
+				next[i] += cur[i+1]
+			}
+		}
+		cur, next = next, cur
+		gamma[k] = cur[0]
+	}
+	return gamma
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
@@ -0,0 +1,76 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Autocorrelation stores the sample autocorrelations of x at lags zero to
+// len(dst)-1 into dst and returns it. If dst is nil, a new slice holding the
+// autocorrelations at lags zero to len(x)-1 is allocated. The autocorrelation
+// at lag k is
+//
+//	r_k = Σ_{t=k}^{n-1} (x_t - x̄)(x_{t-k} - x̄) / Σ_{t=0}^{n-1} (x_t - x̄)².
+//
+// Autocorrelation panics if x has fewer than two elements or the length of
+// dst is greater than the length of x.
+func Autocorrelation(dst, x []float64) []float64 {
+	n := len(x)
+	if n < 2 {
+		panic("timeseries: too few observations")
+	}
+	if dst == nil {
+		dst = make([]float64, n)
+	}
+	if len(dst) > n {
+		panic("timeseries: too many lags")
+	}
+	mean := stat.Mean(x, nil)
+	var c0 float64
+	for _, v := range x {
+		c0 += (v - mean) * (v - mean)
+	}
+	for k := range dst {
+		var c float64
+		for t := k; t < n; t++ {
+			c += (x[t] - mean) * (x[t-k] - mean)
+		}
+		dst[k] = c / c0
+	}
+	return dst
+}
+
+// LjungBox returns the Ljung–Box statistic for the first lags sample
+// autocorrelations r_k of x,
+//
+//	Q = n(n+2) Σ_{k=1}^{lags} r_k²/(n-k),
+//
+// and its p-value under the null hypothesis that x is independent, for which
+// Q is approximately χ² distributed with lags-dof degrees of freedom. When x
+// holds the residuals of a fitted ARMA model, dof should be the number of
+// autoregressive and moving average coefficients of the model.
+//
+// See Ljung and Box, "On a measure of lack of fit in time series models",
+// Biometrika 65(2), 1978.
+//
+// LjungBox panics if lags is not positive, if lags is not less than the
+// length of x or if dof is negative or not less than lags.
+func LjungBox(x []float64, lags, dof int) (q, p float64) {
+	n := len(x)
+	if lags <= 0 || lags >= n {
+		panic("timeseries: invalid number of lags")
+	}
+	if dof < 0 || dof >= lags {
+		panic("timeseries: invalid degrees of freedom")
+	}
+	r := Autocorrelation(make([]float64, lags+1), x)
+	for k := 1; k <= lags; k++ {
+		q += r[k] * r[k] / float64(n-k)
+	}
+	q *= float64(n * (n + 2))
+	return q, distuv.ChiSquared{K: float64(lags - dof)}.Survival(q)
+}
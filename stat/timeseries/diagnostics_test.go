@@ -0,0 +1,66 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestAutocorrelation(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 3, 2, 5, 4, 6}
+	want := []float64{1, 0.1, 12.0 / 35, -31.0 / 70, -1.0 / 7, -5.0 / 14}
+	got := Autocorrelation(nil, x)
+	if !floats.EqualApprox(got, want, 1e-14) {
+		t.Errorf("unexpected autocorrelations: got %v want %v", got, want)
+	}
+	short := Autocorrelation(make([]float64, 3), x)
+	if !floats.Equal(short, got[:3]) {
+		t.Errorf("unexpected truncated autocorrelations: got %v want %v", short, got[:3])
+	}
+}
+
+func TestLjungBox(t *testing.T) {
+	t.Parallel()
+	x := []float64{1, 3, 2, 5, 4, 6}
+	q, p := LjungBox(x, 2, 0)
+	// The χ² distribution with two degrees of
+	// freedom has survival function exp(-q/2).
+	wantQ := 48 * (0.1*0.1/5 + (12.0/35)*(12.0/35)/4)
+	wantP := math.Exp(-wantQ / 2)
+	if math.Abs(q-wantQ) > 1e-12 || math.Abs(p-wantP) > 1e-12 {
+		t.Errorf("unexpected Ljung-Box test: got (%v, %v) want (%v, %v)", q, p, wantQ, wantP)
+	}
+
+	// The test rejects independence of an AR(1) series but
+	// rarely rejects independence of white noise.
+	rnd := rand.New(rand.NewSource(1))
+	var rejected int
+	const trials = 200
+	for i := 0; i < trials; i++ {
+		x := make([]float64, 200)
+		for j := range x {
+			x[j] = rnd.NormFloat64()
+		}
+		if _, p := LjungBox(x, 10, 0); p < 0.05 {
+			rejected++
+		}
+		ar := make([]float64, len(x))
+		for j := 1; j < len(x); j++ {
+			ar[j] = 0.5*ar[j-1] + x[j]
+		}
+		if _, p := LjungBox(ar, 10, 0); p > 0.05 {
+			t.Errorf("trial %d: unexpected p-value for AR(1) series: %v", i, p)
+		}
+	}
+	if frac := float64(rejected) / trials; frac > 0.1 {
+		t.Errorf("unexpected rejection rate for white noise: got %v want 0.05", frac)
+	}
+}
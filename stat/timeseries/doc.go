@@ -0,0 +1,31 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package timeseries provides models for the analysis and forecasting of
+// time series.
+//
+// The seasonal autoregressive integrated moving average model,
+// SARIMA(p, d, q)×(P, D, Q)_s, of a series y is
+//
+//	φ(B) Φ(B^s) (1-B)^d (1-B^s)^D (y_t - μ) = θ(B) Θ(B^s) e_t,
+//
+// where B is the backshift operator, B y_t = y_{t-1}, e_t are independent
+// normal innovations with variance σ², and
+//
+//	φ(B) = 1 - φ_1 B - … - φ_p B^p,
+//	Φ(B) = 1 - Φ_1 B - … - Φ_P B^P,
+//	θ(B) = 1 + θ_1 B + … + θ_q B^q,
+//	Θ(B) = 1 + Θ_1 B + … + Θ_Q B^Q.
+//
+// The mean μ is only estimated for series that are not differenced.
+//
+// The package also provides Kalman filtering and smoothing of linear Gaussian
+// state space models with time-varying matrices, estimation of their matrices
+// by the expectation–maximization algorithm, and the extended and unscented
+// Kalman filters of nonlinear state space models.
+//
+// See Box, Jenkins, Reinsel and Ljung, "Time Series Analysis: Forecasting
+// and Control", Wiley, 2015 and Durbin and Koopman, "Time Series Analysis by
+// State Space Methods", Oxford University Press, 2012 for more details.
+package timeseries // import "gonum.org/v1/gonum/stat/timeseries"
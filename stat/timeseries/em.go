@@ -0,0 +1,221 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrIterationLimit is returned when an estimate does not converge within
+// the iteration limit.
+var ErrIterationLimit = errors.New("timeseries: iteration limit reached")
+
+// EMSettings holds the settings for FitEM. See the field comments for
+// default values.
+type EMSettings struct {
+	// MaxIterations is the maximum number of
+	// iterations. If MaxIterations is zero, a
+	// default of 500 is used.
+	MaxIterations int
+
+	// Tolerance is the convergence tolerance
+	// on the change in log likelihood in an
+	// iteration, relative to the log likelihood
+	// plus 0.1. If Tolerance is zero, a default
+	// of 1e-8 is used.
+	Tolerance float64
+
+	// FixTransition, FixProcessCov,
+	// FixObservation and FixObservationCov
+	// specify that the corresponding matrix of
+	// the model is not estimated.
+	FixTransition     bool
+	FixProcessCov     bool
+	FixObservation    bool
+	FixObservationCov bool
+
+	// FixInitialMean specifies that the mean of
+	// the initial state is not estimated. The
+	// covariance of the initial state is never
+	// estimated.
+	FixInitialMean bool
+}
+
+// EMResult holds the result of FitEM.
+type EMResult struct {
+	// LogLikelihood is the log likelihood of
+	// the observations for the final model.
+	LogLikelihood float64
+
+	// Iterations is the number of iterations.
+	Iterations int
+}
+
+// FitEM estimates the matrices of the time-invariant linear Gaussian state
+// space model m and the mean of the initial state init from the observations
+// in the rows of y by maximum likelihood using the expectation–maximization
+// algorithm. Each iteration computes the expected sufficient statistics of
+// the states with the Kalman smoother, and then maximizes the expected log
+// likelihood, which does not decrease the likelihood. The estimates are
+// stored into m and init, starting from their values. If settings is nil,
+// the default settings are used, and all the matrices are estimated.
+//
+// The model is not identifiable when both the transition and observation
+// matrices are estimated, since any invertible transformation of the state
+// gives the same likelihood, so at least one of them is usually fixed.
+//
+// If a filter or smoother fails, FitEM returns the last model and the error,
+// and if the log likelihood has not converged within the iteration limit,
+// FitEM returns ErrIterationLimit.
+//
+// See Shumway and Stoffer, "An approach to time series smoothing and
+// forecasting using the EM algorithm", Journal of Time Series Analysis 3(4),
+// 1982.
+//
+// FitEM panics if y has fewer than two rows, if y has missing observations,
+// if the dimensions of the model do not match or if the settings are
+// invalid.
+func FitEM(m *LinearGaussian, y mat.Matrix, init State, settings *EMSettings) (*EMResult, error) {
+	var s EMSettings
+	if settings != nil {
+		s = *settings
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = 500
+	}
+	if s.Tolerance == 0 {
+		s.Tolerance = 1e-8
+	}
+	if s.MaxIterations < 0 || s.Tolerance < 0 {
+		panic("timeseries: invalid EM settings")
+	}
+	nt, p := y.Dims()
+	if nt < 2 {
+		panic("timeseries: too few observations")
+	}
+	for t := 0; t < nt; t++ {
+		for j := 0; j < p; j++ {
+			if math.IsNaN(y.At(t, j)) {
+				panic("timeseries: missing observation")
+			}
+		}
+	}
+	n := init.Mean.Len()
+
+	res := &EMResult{LogLikelihood: math.Inf(-1)}
+	for res.Iterations < s.MaxIterations {
+		f, err := Filter(m, y, init)
+		if err != nil {
+			return res, err
+		}
+		sm, err := Smooth(m, f)
+		if err != nil {
+			return res, err
+		}
+		prev := res.LogLikelihood
+		res.LogLikelihood = f.LogLikelihood
+		if res.Iterations > 0 && math.Abs(res.LogLikelihood-prev) < s.Tolerance*(math.Abs(res.LogLikelihood)+0.1) {
+			return res, nil
+		}
+		res.Iterations++
+
+		// Accumulate the expected sufficient statistics,
+		//  s00 = Σ_{t<T-1} E[x_t x_tᵀ],
+		//  s11 = Σ_{t>0} E[x_t x_tᵀ],
+		//  s10 = Σ_{t>0} E[x_t x_{t-1}ᵀ],
+		//  syx = Σ_t y_t E[x_t]ᵀ,
+		//  syy = Σ_t y_t y_tᵀ.
+		s00 := mat.NewDense(n, n, nil)
+		s11 := mat.NewDense(n, n, nil)
+		s10 := mat.NewDense(n, n, nil)
+		syx := mat.NewDense(p, n, nil)
+		syy := mat.NewDense(p, p, nil)
+		yt := mat.NewVecDense(p, nil)
+		var xx mat.Dense
+		for t, st := range sm.States {
+			xx.Outer(1, st.Mean, st.Mean)
+			xx.Add(&xx, st.Cov)
+			if t < nt-1 {
+				s00.Add(s00, &xx)
+			}
+			if t > 0 {
+				s11.Add(s11, &xx)
+				s10.Add(s10, sm.LagCov[t])
+				s10.RankOne(s10, 1, st.Mean, sm.States[t-1].Mean)
+			}
+			for j := 0; j < p; j++ {
+				yt.SetVec(j, y.At(t, j))
+			}
+			syx.RankOne(syx, 1, yt, st.Mean)
+			syy.RankOne(syy, 1, yt, yt)
+		}
+		sxx := mat.NewDense(n, n, nil)
+		sxx.Add(s00, &xx)
+
+		// Maximize the expected log likelihood.
+		if !s.FixTransition {
+			if err := solveRight(m.F, s10, s00); err != nil {
+				return res, err
+			}
+		}
+		if !s.FixProcessCov {
+			residualCov(m.Q, m.F, s11, s10, s00, nt-1)
+		}
+		if !s.FixObservation {
+			if err := solveRight(m.H, syx, sxx); err != nil {
+				return res, err
+			}
+		}
+		if !s.FixObservationCov {
+			residualCov(m.R, m.H, syy, syx, sxx, nt)
+		}
+		if !s.FixInitialMean {
+			init.Mean.CopyVec(sm.States[0].Mean)
+		}
+	}
+	f, err := Filter(m, y, init)
+	if err != nil {
+		return res, err
+	}
+	res.LogLikelihood = f.LogLikelihood
+	return res, ErrIterationLimit
+}
+
+// solveRight stores b*a⁻¹ into dst for the symmetric positive definite
+// matrix a.
+func solveRight(dst *mat.Dense, b, a mat.Matrix) error {
+	var sa mat.SymDense
+	symmetrize(&sa, a)
+	var chol mat.Cholesky
+	if !chol.Factorize(&sa) {
+		return ErrNotPositiveDefinite
+	}
+	var x mat.Dense
+	_ = chol.SolveTo(&x, b.T())
+	dst.Copy(x.T())
+	return nil
+}
+
+// residualCov stores the covariance of the residuals of the regression of u on
+// v with coefficients c into dst,
+//
+//	(suu - c svuᵀ - svu cᵀ + c svv cᵀ) / n,
+//
+// where suu, suv and svv are the sums of the products of u and v.
+func residualCov(dst *mat.SymDense, c *mat.Dense, suu, suv, svv mat.Matrix, n int) {
+	var a, b, cv mat.Dense
+	a.Mul(c, suv.T())
+	b.CloneFrom(suu)
+	b.Sub(&b, &a)
+	b.Sub(&b, a.T())
+	cv.Mul(c, svv)
+	a.Mul(&cv, c.T())
+	b.Add(&b, &a)
+	b.Scale(1/float64(n), &b)
+	symmetrize(dst, &b)
+}
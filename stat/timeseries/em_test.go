@@ -0,0 +1,105 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestFitEM(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+
+	// An AR(1) process observed with noise.
+	truth := &LinearGaussian{
+		F: mat.NewDense(1, 1, []float64{0.8}),
+		Q: mat.NewSymDense(1, []float64{1}),
+		H: mat.NewDense(1, 1, []float64{1}),
+		R: mat.NewSymDense(1, []float64{0.5}),
+	}
+	init := State{Mean: mat.NewVecDense(1, []float64{0}), Cov: mat.NewSymDense(1, []float64{1 / (1 - 0.64)})}
+	_, y := simulateStateSpace(truth, 2000, init, rnd)
+
+	newModel := func() (*LinearGaussian, State) {
+		return &LinearGaussian{
+			F: mat.NewDense(1, 1, []float64{0.3}),
+			Q: mat.NewSymDense(1, []float64{0.3}),
+			H: mat.NewDense(1, 1, []float64{1}),
+			R: mat.NewSymDense(1, []float64{2}),
+		}, State{
+			Mean: mat.NewVecDense(1, []float64{1}),
+			Cov:  mat.NewSymDense(1, []float64{3}),
+		}
+	}
+	settings := &EMSettings{FixObservation: true}
+
+	// The log likelihood does not decrease.
+	m, start := newModel()
+	prev := math.Inf(-1)
+	for i := 0; i < 20; i++ {
+		s := *settings
+		s.MaxIterations = 1
+		res, err := FitEM(m, y, start, &s)
+		if err != ErrIterationLimit {
+			t.Fatalf("unexpected error: got %v want %v", err, ErrIterationLimit)
+		}
+		if res.LogLikelihood < prev-1e-8*math.Abs(prev) {
+			t.Errorf("log likelihood decreased at iteration %d: %v < %v", i, res.LogLikelihood, prev)
+		}
+		prev = res.LogLikelihood
+	}
+
+	m, start = newModel()
+	s := *settings
+	s.Tolerance = 1e-12
+	res, err := FitEM(m, y, start, &s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.LogLikelihood < prev {
+		t.Errorf("converged log likelihood less than early iterate: %v < %v", res.LogLikelihood, prev)
+	}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+		tol       float64
+	}{
+		{name: "F", got: m.F.At(0, 0), want: 0.8, tol: 0.05},
+		{name: "Q", got: m.Q.At(0, 0), want: 1, tol: 0.3},
+		{name: "H", got: m.H.At(0, 0), want: 1, tol: 0},
+		{name: "R", got: m.R.At(0, 0), want: 0.5, tol: 0.15},
+	} {
+		if math.Abs(c.got-c.want) > c.tol {
+			t.Errorf("unexpected estimate of %s: got %v want %v", c.name, c.got, c.want)
+		}
+	}
+
+	// The converged model is a stationary point of the
+	// likelihood.
+	f, err := Filter(m, y, start)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, a := range []*float64{&m.F.RawMatrix().Data[0], &m.Q.RawSymmetric().Data[0], &m.R.RawSymmetric().Data[0]} {
+		v := *a
+		const step = 1e-4
+		*a = v + step
+		f1, _ := Filter(m, y, start)
+		*a = v - step
+		f0, _ := Filter(m, y, start)
+		*a = v
+		if d := (f1.LogLikelihood - f0.LogLikelihood) / (2 * step); math.Abs(d) > 0.5 {
+			t.Errorf("non-zero derivative of log likelihood at converged model: %v", d)
+		}
+		if f0.LogLikelihood > f.LogLikelihood+1e-6 || f1.LogLikelihood > f.LogLikelihood+1e-6 {
+			t.Errorf("converged model is not a local maximum")
+		}
+	}
+}
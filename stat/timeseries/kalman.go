@@ -0,0 +1,307 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrNotPositiveDefinite is returned when a covariance matrix required by a
+// Kalman filter or smoother is not positive definite.
+var ErrNotPositiveDefinite = errors.New("timeseries: covariance matrix not positive definite")
+
+// StateSpace is a linear Gaussian state space model with time-varying
+// matrices,
+//
+//	x_{t+1} = F_t x_t + w_t, w_t ~ N(0, Q_t),
+//	y_t = H_t x_t + v_t,     v_t ~ N(0, R_t),
+//
+// where x_t is the state and y_t is the observation at time t. The matrices
+// returned by the methods must not be modified by the caller.
+type StateSpace interface {
+	// Transition returns the transition
+	// matrix F_t from time t to t+1.
+	Transition(t int) mat.Matrix
+
+	// ProcessCov returns the covariance
+	// Q_t of the process noise w_t.
+	ProcessCov(t int) mat.Symmetric
+
+	// Observation returns the observation
+	// matrix H_t at time t.
+	Observation(t int) mat.Matrix
+
+	// ObservationCov returns the covariance
+	// R_t of the observation noise v_t.
+	ObservationCov(t int) mat.Symmetric
+}
+
+// LinearGaussian is a time-invariant linear Gaussian state space model with
+// transition matrix F, process noise covariance Q, observation matrix H and
+// observation noise covariance R. See StateSpace for the form of the model.
+type LinearGaussian struct {
+	F *mat.Dense
+	Q *mat.SymDense
+	H *mat.Dense
+	R *mat.SymDense
+}
+
+var _ StateSpace = (*LinearGaussian)(nil)
+
+// Transition returns F.
+func (m *LinearGaussian) Transition(int) mat.Matrix { return m.F }
+
+// ProcessCov returns Q.
+func (m *LinearGaussian) ProcessCov(int) mat.Symmetric { return m.Q }
+
+// Observation returns H.
+func (m *LinearGaussian) Observation(int) mat.Matrix { return m.H }
+
+// ObservationCov returns R.
+func (m *LinearGaussian) ObservationCov(int) mat.Symmetric { return m.R }
+
+// State is a Gaussian distribution of the state of a state space model.
+type State struct {
+	Mean *mat.VecDense
+	Cov  *mat.SymDense
+}
+
+// clone returns a copy of s.
+func (s State) clone() State {
+	c := State{
+		Mean: mat.VecDenseCopyOf(s.Mean),
+		Cov:  mat.NewSymDense(s.Cov.Symmetric(), nil),
+	}
+	c.Cov.CopySym(s.Cov)
+	return c
+}
+
+// Filtered holds the result of a Kalman filter.
+type Filtered struct {
+	// Predicted holds the distributions of
+	// the states given the observations
+	// before each time, and Filtered holds
+	// the distributions of the states given
+	// the observations up to and including
+	// each time.
+	Predicted, Filtered []State
+
+	// LogLikelihood is the log likelihood of
+	// the observations.
+	LogLikelihood float64
+}
+
+// Filter returns the Kalman filter of the observations in the rows of y for
+// the state space model m, starting from the distribution init of the state
+// at time zero. Elements of y that are NaN are treated as missing, and times
+// at which all the elements are missing are predicted without an update. If
+// the covariance of the observations at a time is not positive definite,
+// Filter returns the filter up to that time and ErrNotPositiveDefinite.
+//
+// See Durbin and Koopman, "Time Series Analysis by State Space Methods",
+// Oxford University Press, 2012, chapter 4 for details.
+//
+// Filter panics if the dimensions of the matrices of the model do not match
+// the dimensions of the state and the observations.
+func Filter(m StateSpace, y mat.Matrix, init State) (*Filtered, error) {
+	return filter(linear{m}, y, init)
+}
+
+// transform is the propagation of the distribution of the state through the
+// transition and observation functions of a state space model, which
+// distinguishes the variants of the Kalman filter.
+type transform interface {
+	// predict stores the distribution of the
+	// state at time t+1 given the distribution
+	// s of the state at time t into dst.
+	predict(dst State, t int, s State) error
+
+	// observe stores the mean and covariance
+	// of the observation at time t and the
+	// cross-covariance of the state and the
+	// observation, given the distribution s
+	// of the state, into mean, cov and cross.
+	observe(mean *mat.VecDense, cov *mat.SymDense, cross *mat.Dense, t int, s State) error
+}
+
+// filter returns the Kalman filter of the observations y using the transform
+// tr, starting from init.
+func filter(tr transform, y mat.Matrix, init State) (*Filtered, error) {
+	nt, p := y.Dims()
+	n := init.Mean.Len()
+	if init.Cov.Symmetric() != n {
+		panic("timeseries: mismatched initial state dimensions")
+	}
+	f := &Filtered{
+		Predicted: make([]State, 0, nt),
+		Filtered:  make([]State, 0, nt),
+	}
+	pred := init.clone()
+	yhat := mat.NewVecDense(p, nil)
+	cov := mat.NewSymDense(p, nil)
+	cross := mat.NewDense(n, p, nil)
+	obs := make([]int, 0, p)
+	for t := 0; t < nt; t++ {
+		f.Predicted = append(f.Predicted, pred)
+		obs = obs[:0]
+		for j := 0; j < p; j++ {
+			if !math.IsNaN(y.At(t, j)) {
+				obs = append(obs, j)
+			}
+		}
+		upd := pred.clone()
+		if len(obs) != 0 {
+			if err := tr.observe(yhat, cov, cross, t, pred); err != nil {
+				return f, err
+			}
+			k := len(obs)
+			v := mat.NewVecDense(k, nil)
+			c := mat.NewDense(n, k, nil)
+			for i, j := range obs {
+				v.SetVec(i, y.At(t, j)-yhat.AtVec(j))
+				for r := 0; r < n; r++ {
+					c.Set(r, i, cross.At(r, j))
+				}
+			}
+			var s mat.SymDense
+			s.SubsetSym(cov, obs)
+			var chol mat.Cholesky
+			if !chol.Factorize(&s) {
+				return f, ErrNotPositiveDefinite
+			}
+
+			// Update the state with the gain
+			// K = C S⁻¹ and the innovation v.
+			var sv mat.VecDense
+			_ = chol.SolveVecTo(&sv, v)
+			upd.Mean.MulVec(c, &sv)
+			upd.Mean.AddVec(upd.Mean, pred.Mean)
+			var sc, kc mat.Dense
+			_ = chol.SolveTo(&sc, c.T())
+			kc.Mul(c, &sc)
+			kc.Scale(-1, &kc)
+			kc.Add(&kc, pred.Cov)
+			symmetrize(upd.Cov, &kc)
+
+			f.LogLikelihood -= 0.5 * (float64(k)*math.Log(2*math.Pi) + chol.LogDet() + mat.Dot(v, &sv))
+		}
+		f.Filtered = append(f.Filtered, upd)
+		if t < nt-1 {
+			pred = State{Mean: mat.NewVecDense(n, nil), Cov: mat.NewSymDense(n, nil)}
+			if err := tr.predict(pred, t, upd); err != nil {
+				return f, err
+			}
+		}
+	}
+	return f, nil
+}
+
+// linear is the transform of a linear state space model.
+type linear struct {
+	m StateSpace
+}
+
+func (l linear) predict(dst State, t int, s State) error {
+	f := l.m.Transition(t)
+	dst.Mean.MulVec(f, s.Mean)
+	quadForm(dst.Cov, f, s.Cov, l.m.ProcessCov(t))
+	return nil
+}
+
+func (l linear) observe(mean *mat.VecDense, cov *mat.SymDense, cross *mat.Dense, t int, s State) error {
+	h := l.m.Observation(t)
+	mean.MulVec(h, s.Mean)
+	quadForm(cov, h, s.Cov, l.m.ObservationCov(t))
+	cross.Mul(s.Cov, h.T())
+	return nil
+}
+
+// Smoothed holds the result of a Rauch–Tung–Striebel smoother.
+type Smoothed struct {
+	// States holds the distributions of
+	// the states given all the observations.
+	States []State
+
+	// LagCov holds the covariances of the
+	// states at times t and t-1 given all
+	// the observations, Cov(x_t, x_{t-1}).
+	// The first element is nil.
+	LagCov []*mat.Dense
+}
+
+// Smooth returns the Rauch–Tung–Striebel smoother of the Kalman filter f of
+// the state space model m. If a predicted covariance matrix of the filter is
+// not positive definite, Smooth returns a nil smoother and
+// ErrNotPositiveDefinite.
+//
+// Smooth panics if f has no filtered states.
+func Smooth(m StateSpace, f *Filtered) (*Smoothed, error) {
+	nt := len(f.Filtered)
+	if nt == 0 {
+		panic("timeseries: no filtered states")
+	}
+	s := &Smoothed{
+		States: make([]State, nt),
+		LagCov: make([]*mat.Dense, nt),
+	}
+	s.States[nt-1] = f.Filtered[nt-1].clone()
+	for t := nt - 2; t >= 0; t-- {
+		filt := f.Filtered[t]
+		pred := f.Predicted[t+1]
+		next := s.States[t+1]
+
+		// Compute the smoother gain
+		//  J = C_t Fᵀ P_{t+1}⁻¹.
+		var chol mat.Cholesky
+		if !chol.Factorize(pred.Cov) {
+			return nil, ErrNotPositiveDefinite
+		}
+		var fc, jt, j mat.Dense
+		fc.Mul(m.Transition(t), filt.Cov)
+		_ = chol.SolveTo(&jt, &fc)
+		j.CloneFrom(jt.T())
+
+		var d mat.VecDense
+		d.SubVec(next.Mean, pred.Mean)
+		cur := State{Mean: mat.NewVecDense(filt.Mean.Len(), nil), Cov: mat.NewSymDense(filt.Mean.Len(), nil)}
+		cur.Mean.MulVec(&j, &d)
+		cur.Mean.AddVec(cur.Mean, filt.Mean)
+
+		var dc mat.Dense
+		dc.Sub(next.Cov, pred.Cov)
+		quadForm(cur.Cov, &j, &dc, filt.Cov)
+		s.States[t] = cur
+
+		lag := mat.NewDense(filt.Mean.Len(), filt.Mean.Len(), nil)
+		lag.Mul(next.Cov, j.T())
+		s.LagCov[t+1] = lag
+	}
+	return s, nil
+}
+
+// quadForm stores a*s*aᵀ + c into dst and ensures that dst is symmetric.
+func quadForm(dst *mat.SymDense, a, s mat.Matrix, c mat.Symmetric) {
+	var as, asa mat.Dense
+	as.Mul(a, s)
+	asa.Mul(&as, a.T())
+	asa.Add(&asa, c)
+	symmetrize(dst, &asa)
+}
+
+// symmetrize stores the symmetric part of the square matrix a into dst.
+func symmetrize(dst *mat.SymDense, a mat.Matrix) {
+	n, _ := a.Dims()
+	if dst.IsZero() {
+		*dst = *(dst.GrowSym(n).(*mat.SymDense))
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			dst.SetSym(i, j, 0.5*(a.At(i, j)+a.At(j, i)))
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distmv"
+)
+
+// timeVarying is a state space model with a two-dimensional state and
+// observation whose matrices change with time.
+type timeVarying struct{}
+
+func (timeVarying) Transition(t int) mat.Matrix {
+	return mat.NewDense(2, 2, []float64{1, 1 + 0.1*float64(t), 0, 0.9})
+}
+
+func (timeVarying) ProcessCov(t int) mat.Symmetric {
+	return mat.NewSymDense(2, []float64{0.2 + 0.05*float64(t), 0.05, 0.05, 0.3})
+}
+
+func (timeVarying) Observation(t int) mat.Matrix {
+	return mat.NewDense(2, 2, []float64{1, 0, 0.5 * float64(t%3), 1})
+}
+
+func (timeVarying) ObservationCov(t int) mat.Symmetric {
+	return mat.NewSymDense(2, []float64{0.5, 0.1 * float64(t%2), 0.1 * float64(t%2), 0.4})
+}
+
+// simulateStateSpace returns nt states and observations of the linear state
+// space model m starting from a draw from init.
+func simulateStateSpace(m StateSpace, nt int, init State, rnd *rand.Rand) (x, y *mat.Dense) {
+	n := init.Mean.Len()
+	p, _ := m.Observation(0).Dims()
+	x = mat.NewDense(nt, n, nil)
+	y = mat.NewDense(nt, p, nil)
+	src := rand.NewSource(rnd.Uint64())
+	draw := func(mu mat.Vector, cov mat.Symmetric) *mat.VecDense {
+		dim := mu.Len()
+		mean := make([]float64, dim)
+		for i := range mean {
+			mean[i] = mu.AtVec(i)
+		}
+		d, ok := distmv.NewNormal(mean, cov, src)
+		if !ok {
+			panic("covariance not positive definite")
+		}
+		return mat.NewVecDense(dim, d.Rand(nil))
+	}
+	cur := draw(init.Mean, init.Cov)
+	for t := 0; t < nt; t++ {
+		x.SetRow(t, cur.RawVector().Data)
+		var hx mat.VecDense
+		hx.MulVec(m.Observation(t), cur)
+		y.SetRow(t, draw(&hx, m.ObservationCov(t)).RawVector().Data)
+		var fx mat.VecDense
+		fx.MulVec(m.Transition(t), cur)
+		cur = draw(&fx, m.ProcessCov(t))
+	}
+	return x, y
+}
+
+// joint returns the mean and covariance of the stacked states and the
+// observed elements of y of the first nt times, and the observed values.
+func joint(m StateSpace, y mat.Matrix, nt int, init State) (mx []float64, sxx *mat.SymDense, my []float64, syy *mat.SymDense, sxy *mat.Dense, obs []float64) {
+	n := init.Mean.Len()
+	_, p := y.Dims()
+
+	// Compute the joint distribution of the states.
+	means := make([]*mat.VecDense, nt)
+	cov := make([][]*mat.Dense, nt)
+	for t := range cov {
+		cov[t] = make([]*mat.Dense, nt)
+	}
+	means[0] = mat.VecDenseCopyOf(init.Mean)
+	cov[0][0] = mat.DenseCopyOf(init.Cov)
+	for t := 1; t < nt; t++ {
+		f := m.Transition(t - 1)
+		means[t] = mat.NewVecDense(n, nil)
+		means[t].MulVec(f, means[t-1])
+		for s := 0; s < t; s++ {
+			cov[t][s] = mat.NewDense(n, n, nil)
+			cov[t][s].Mul(f, cov[t-1][s])
+		}
+		var v mat.Dense
+		v.Mul(cov[t][t-1], f.T())
+		v.Add(&v, m.ProcessCov(t-1))
+		cov[t][t] = &v
+	}
+	sx := mat.NewDense(n*nt, n*nt, nil)
+	for t := 0; t < nt; t++ {
+		for s := 0; s <= t; s++ {
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					sx.Set(t*n+i, s*n+j, cov[t][s].At(i, j))
+					sx.Set(s*n+j, t*n+i, cov[t][s].At(i, j))
+				}
+			}
+		}
+		for i := 0; i < n; i++ {
+			mx = append(mx, means[t].AtVec(i))
+		}
+	}
+	sxx = mat.NewSymDense(n*nt, nil)
+	symmetrize(sxx, sx)
+
+	// Compute the joint distribution of the observed
+	// elements and their covariance with the states.
+	type index struct{ t, j int }
+	var idx []index
+	for t := 0; t < nt; t++ {
+		for j := 0; j < p; j++ {
+			if !math.IsNaN(y.At(t, j)) {
+				idx = append(idx, index{t, j})
+				obs = append(obs, y.At(t, j))
+			}
+		}
+	}
+	k := len(idx)
+	hblk := mat.NewDense(k, n*nt, nil)
+	rblk := mat.NewDense(k, k, nil)
+	for a, ia := range idx {
+		h := m.Observation(ia.t)
+		for i := 0; i < n; i++ {
+			hblk.Set(a, ia.t*n+i, h.At(ia.j, i))
+		}
+		for b, ib := range idx {
+			if ia.t == ib.t {
+				rblk.Set(a, b, m.ObservationCov(ia.t).At(ia.j, ib.j))
+			}
+		}
+	}
+	var yv mat.VecDense
+	yv.MulVec(hblk, mat.NewVecDense(n*nt, mx))
+	my = yv.RawVector().Data
+	sxy = mat.NewDense(n*nt, k, nil)
+	sxy.Mul(sxx, hblk.T())
+	var sy mat.Dense
+	sy.Mul(hblk, sxy)
+	sy.Add(&sy, rblk)
+	syy = mat.NewSymDense(k, nil)
+	symmetrize(syy, &sy)
+	return mx, sxx, my, syy, sxy, obs
+}
+
+// condition returns the mean and covariance of the states given the
+// observations from the joint distribution.
+func condition(mx []float64, sxx *mat.SymDense, my []float64, syy *mat.SymDense, sxy *mat.Dense, obs []float64) (*mat.VecDense, *mat.SymDense) {
+	var chol mat.Cholesky
+	if !chol.Factorize(syy) {
+		panic("covariance not positive definite")
+	}
+	d := mat.NewVecDense(len(obs), nil)
+	for i, v := range obs {
+		d.SetVec(i, v-my[i])
+	}
+	var sd mat.VecDense
+	_ = chol.SolveVecTo(&sd, d)
+	mean := mat.NewVecDense(len(mx), nil)
+	mean.MulVec(sxy, &sd)
+	mean.AddVec(mean, mat.NewVecDense(len(mx), mx))
+	var s, c mat.Dense
+	_ = chol.SolveTo(&s, sxy.T())
+	c.Mul(sxy, &s)
+	c.Sub(sxx, &c)
+	cov := mat.NewSymDense(len(mx), nil)
+	symmetrize(cov, &c)
+	return mean, cov
+}
+
+func testInit() State {
+	return State{
+		Mean: mat.NewVecDense(2, []float64{1, -0.5}),
+		Cov:  mat.NewSymDense(2, []float64{1, 0.2, 0.2, 0.5}),
+	}
+}
+
+func TestFilterSmooth(t *testing.T) {
+	t.Parallel()
+	const nt = 6
+	rnd := rand.New(rand.NewSource(1))
+	for _, missing := range []bool{false, true} {
+		var m StateSpace = timeVarying{}
+		init := testInit()
+		_, y := simulateStateSpace(m, nt, init, rnd)
+		if missing {
+			y.Set(1, 0, math.NaN())
+			y.Set(3, 0, math.NaN())
+			y.Set(3, 1, math.NaN())
+		}
+		f, err := Filter(m, y, init)
+		if err != nil {
+			t.Fatalf("missing=%t: unexpected error: %v", missing, err)
+		}
+		s, err := Smooth(m, f)
+		if err != nil {
+			t.Fatalf("missing=%t: unexpected error: %v", missing, err)
+		}
+
+		// Compare the filtered states with the conditional
+		// distributions given the observations up to each time.
+		for tt := 0; tt < nt; tt++ {
+			mx, sxx, my, syy, sxy, obs := joint(m, y, tt+1, init)
+			if len(obs) == 0 {
+				continue
+			}
+			mean, cov := condition(mx, sxx, my, syy, sxy, obs)
+			checkState(t, "filtered", missing, tt, f.Filtered[tt], mean, cov, tt)
+		}
+
+		// Compare the log likelihood with the density of the
+		// observations.
+		mx, sxx, my, syy, sxy, obs := joint(m, y, nt, init)
+		dist, ok := distmv.NewNormal(my, syy, nil)
+		if !ok {
+			t.Fatal("covariance not positive definite")
+		}
+		if got, want := f.LogLikelihood, dist.LogProb(obs); math.Abs(got-want) > 1e-10*math.Abs(want) {
+			t.Errorf("missing=%t: log likelihood mismatch: got %v want %v", missing, got, want)
+		}
+
+		// Compare the smoothed states with the conditional
+		// distribution given all the observations.
+		mean, cov := condition(mx, sxx, my, syy, sxy, obs)
+		for tt := 0; tt < nt; tt++ {
+			checkState(t, "smoothed", missing, tt, s.States[tt], mean, cov, tt)
+			if tt == 0 {
+				if s.LagCov[0] != nil {
+					t.Errorf("missing=%t: unexpected lag covariance at time zero", missing)
+				}
+				continue
+			}
+			for i := 0; i < 2; i++ {
+				for j := 0; j < 2; j++ {
+					got := s.LagCov[tt].At(i, j)
+					want := cov.At(tt*2+i, (tt-1)*2+j)
+					if math.Abs(got-want) > 1e-10 {
+						t.Errorf("missing=%t: lag covariance mismatch at time %d: got %v want %v", missing, tt, mat.Formatted(s.LagCov[tt]), want)
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkState checks that the state s matches the block of the stacked mean
+// and covariance at time k.
+func checkState(t *testing.T, name string, missing bool, tt int, s State, mean *mat.VecDense, cov *mat.SymDense, k int) {
+	t.Helper()
+	n := s.Mean.Len()
+	for i := 0; i < n; i++ {
+		if got, want := s.Mean.AtVec(i), mean.AtVec(k*n+i); math.Abs(got-want) > 1e-10 {
+			t.Errorf("missing=%t: %s mean mismatch at time %d: got %v want %v", missing, name, tt, got, want)
+		}
+		for j := 0; j < n; j++ {
+			if got, want := s.Cov.At(i, j), cov.At(k*n+i, k*n+j); math.Abs(got-want) > 1e-10 {
+				t.Errorf("missing=%t: %s covariance mismatch at time %d: got %v want %v", missing, name, tt, got, want)
+			}
+		}
+	}
+}
+
+func TestFilterNotPositiveDefinite(t *testing.T) {
+	t.Parallel()
+	m := &LinearGaussian{
+		F: mat.NewDense(1, 1, []float64{1}),
+		Q: mat.NewSymDense(1, []float64{0}),
+		H: mat.NewDense(1, 1, []float64{1}),
+		R: mat.NewSymDense(1, []float64{0}),
+	}
+	init := State{Mean: mat.NewVecDense(1, nil), Cov: mat.NewSymDense(1, []float64{1})}
+	y := mat.NewDense(3, 1, []float64{1, 2, 3})
+	f, err := Filter(m, y, init)
+	if err != ErrNotPositiveDefinite {
+		t.Errorf("unexpected error: got %v want %v", err, ErrNotPositiveDefinite)
+	}
+	// The first observation determines the state exactly,
+	// so the second observation has zero variance.
+	if len(f.Filtered) != 1 || f.Filtered[0].Mean.AtVec(0) != 1 {
+		t.Errorf("unexpected filter before failure: %d states", len(f.Filtered))
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Nonlinear is a state space model with nonlinear transition and observation
+// functions and additive Gaussian noise,
+//
+//	x_{t+1} = f_t(x_t) + w_t, w_t ~ N(0, Q_t),
+//	y_t = h_t(x_t) + v_t,     v_t ~ N(0, R_t),
+//
+// where x_t is the state and y_t is the observation at time t. The matrices
+// returned by the methods must not be modified by the caller.
+type Nonlinear interface {
+	// Transition stores f_t(x) into dst,
+	// which has the length of the state.
+	Transition(dst *mat.VecDense, t int, x mat.Vector)
+
+	// ProcessCov returns the covariance
+	// Q_t of the process noise w_t.
+	ProcessCov(t int) mat.Symmetric
+
+	// Observe stores h_t(x) into dst, which
+	// has the length of the observations.
+	Observe(dst *mat.VecDense, t int, x mat.Vector)
+
+	// ObservationCov returns the covariance
+	// R_t of the observation noise v_t.
+	ObservationCov(t int) mat.Symmetric
+}
+
+// Differentiable is a nonlinear state space model with differentiable
+// transition and observation functions.
+type Differentiable interface {
+	Nonlinear
+
+	// TransitionJacobian stores the Jacobian
+	// of f_t at x into dst, which is a square
+	// matrix of the size of the state.
+	TransitionJacobian(dst *mat.Dense, t int, x mat.Vector)
+
+	// ObservationJacobian stores the Jacobian
+	// of h_t at x into dst, which has a row
+	// for each element of the observations
+	// and a column for each element of the
+	// state.
+	ObservationJacobian(dst *mat.Dense, t int, x mat.Vector)
+}
+
+// ExtendedFilter returns the extended Kalman filter of the observations in
+// the rows of y for the nonlinear state space model m, starting from the
+// distribution init of the state at time zero. The extended Kalman filter
+// linearizes the transition and observation functions about the current
+// estimate of the state. Missing observations and errors are handled as for
+// Filter, and the log likelihood is that of the linearized model.
+func ExtendedFilter(m Differentiable, y mat.Matrix, init State) (*Filtered, error) {
+	_, p := y.Dims()
+	n := init.Mean.Len()
+	return filter(extended{
+		m:  m,
+		jf: mat.NewDense(n, n, nil),
+		jh: mat.NewDense(p, n, nil),
+	}, y, init)
+}
+
+// extended is the transform of the extended Kalman filter.
+type extended struct {
+	m      Differentiable
+	jf, jh *mat.Dense
+}
+
+func (e extended) predict(dst State, t int, s State) error {
+	e.m.Transition(dst.Mean, t, s.Mean)
+	e.m.TransitionJacobian(e.jf, t, s.Mean)
+	quadForm(dst.Cov, e.jf, s.Cov, e.m.ProcessCov(t))
+	return nil
+}
+
+func (e extended) observe(mean *mat.VecDense, cov *mat.SymDense, cross *mat.Dense, t int, s State) error {
+	e.m.Observe(mean, t, s.Mean)
+	e.m.ObservationJacobian(e.jh, t, s.Mean)
+	quadForm(cov, e.jh, s.Cov, e.m.ObservationCov(t))
+	cross.Mul(s.Cov, e.jh.T())
+	return nil
+}
+
+// Unscented holds the parameters of the unscented transform used by the
+// unscented Kalman filter. The transform propagates 2n+1 sigma points,
+//
+//	χ_0 = μ, χ_i = μ ± sqrt(n+λ) L_i,
+//
+// where n is the length of the state, L_i are the columns of the Cholesky
+// factor of the covariance and λ = α²(n+κ) - n.
+type Unscented struct {
+	// Alpha controls the spread of the sigma
+	// points around the mean. If Alpha is
+	// zero, a default of 1 is used.
+	Alpha float64
+
+	// Beta incorporates prior knowledge of
+	// the distribution of the state, and two
+	// is optimal for Gaussian distributions.
+	Beta float64
+
+	// Kappa is a secondary scaling parameter.
+	Kappa float64
+}
+
+// UnscentedFilter returns the unscented Kalman filter of the observations in
+// the rows of y for the nonlinear state space model m, starting from the
+// distribution init of the state at time zero. The unscented Kalman filter
+// propagates a set of sigma points through the transition and observation
+// functions, and does not need their derivatives. If u is nil, the parameters
+// Alpha = 1, Beta = 2 and Kappa = 0 are used. Missing observations and errors
+// are handled as for Filter, and UnscentedFilter also returns
+// ErrNotPositiveDefinite when a covariance matrix of the state is not
+// positive definite.
+//
+// See Julier and Uhlmann, "Unscented filtering and nonlinear estimation",
+// Proceedings of the IEEE 92(3), 2004 and Wan and van der Merwe, "The
+// unscented Kalman filter for nonlinear estimation", IEEE AS-SPCC, 2000.
+//
+// UnscentedFilter panics if n+λ is not positive.
+func UnscentedFilter(m Nonlinear, y mat.Matrix, init State, u *Unscented) (*Filtered, error) {
+	params := Unscented{Alpha: 1, Beta: 2}
+	if u != nil {
+		params = *u
+		if params.Alpha == 0 {
+			params.Alpha = 1
+		}
+	}
+	_, p := y.Dims()
+	n := init.Mean.Len()
+	lambda := params.Alpha*params.Alpha*(float64(n)+params.Kappa) - float64(n)
+	if float64(n)+lambda <= 0 {
+		panic("timeseries: invalid unscented transform parameters")
+	}
+	ut := &unscented{
+		m:     m,
+		scale: math.Sqrt(float64(n) + lambda),
+		wm0:   lambda / (float64(n) + lambda),
+		wi:    1 / (2 * (float64(n) + lambda)),
+		sigma: make([]*mat.VecDense, 2*n+1),
+		fx:    make([]*mat.VecDense, 2*n+1),
+		hx:    make([]*mat.VecDense, 2*n+1),
+	}
+	ut.wc0 = ut.wm0 + 1 - params.Alpha*params.Alpha + params.Beta
+	for i := range ut.sigma {
+		ut.sigma[i] = mat.NewVecDense(n, nil)
+		ut.fx[i] = mat.NewVecDense(n, nil)
+		ut.hx[i] = mat.NewVecDense(p, nil)
+	}
+	return filter(ut, y, init)
+}
+
+// unscented is the transform of the unscented Kalman filter.
+type unscented struct {
+	m Nonlinear
+
+	// scale is sqrt(n+λ), and wm0, wc0 and wi
+	// are the weights of the mean and the
+	// covariance at the central sigma point
+	// and the weight of the other points.
+	scale         float64
+	wm0, wc0, wi  float64
+	sigma, fx, hx []*mat.VecDense
+}
+
+// sigmaPoints stores the sigma points of s into u.sigma.
+func (u *unscented) sigmaPoints(s State) error {
+	var chol mat.Cholesky
+	if !chol.Factorize(s.Cov) {
+		return ErrNotPositiveDefinite
+	}
+	var l mat.TriDense
+	chol.LTo(&l)
+	n := s.Mean.Len()
+	u.sigma[0].CopyVec(s.Mean)
+	for i := 0; i < n; i++ {
+		col := mat.NewVecDense(n, nil)
+		for j := i; j < n; j++ {
+			col.SetVec(j, l.At(j, i))
+		}
+		u.sigma[1+i].AddScaledVec(s.Mean, u.scale, col)
+		u.sigma[1+n+i].AddScaledVec(s.Mean, -u.scale, col)
+	}
+	return nil
+}
+
+// weights returns the weights of the mean and covariance of the ith sigma
+// point.
+func (u *unscented) weights(i int) (wm, wc float64) {
+	if i == 0 {
+		return u.wm0, u.wc0
+	}
+	return u.wi, u.wi
+}
+
+func (u *unscented) predict(dst State, t int, s State) error {
+	if err := u.sigmaPoints(s); err != nil {
+		return err
+	}
+	dst.Mean.Zero()
+	for i, x := range u.sigma {
+		u.m.Transition(u.fx[i], t, x)
+		wm, _ := u.weights(i)
+		dst.Mean.AddScaledVec(dst.Mean, wm, u.fx[i])
+	}
+	n := dst.Mean.Len()
+	cov := mat.NewDense(n, n, nil)
+	cov.Copy(u.m.ProcessCov(t))
+	var d mat.VecDense
+	for i, fx := range u.fx {
+		_, wc := u.weights(i)
+		d.SubVec(fx, dst.Mean)
+		cov.RankOne(cov, wc, &d, &d)
+	}
+	symmetrize(dst.Cov, cov)
+	return nil
+}
+
+func (u *unscented) observe(mean *mat.VecDense, cov *mat.SymDense, cross *mat.Dense, t int, s State) error {
+	if err := u.sigmaPoints(s); err != nil {
+		return err
+	}
+	mean.Zero()
+	for i, x := range u.sigma {
+		u.m.Observe(u.hx[i], t, x)
+		wm, _ := u.weights(i)
+		mean.AddScaledVec(mean, wm, u.hx[i])
+	}
+	p := mean.Len()
+	c := mat.NewDense(p, p, nil)
+	c.Copy(u.m.ObservationCov(t))
+	cross.Zero()
+	var dx, dy mat.VecDense
+	for i, hx := range u.hx {
+		_, wc := u.weights(i)
+		dy.SubVec(hx, mean)
+		dx.SubVec(u.sigma[i], s.Mean)
+		c.RankOne(c, wc, &dy, &dy)
+		cross.RankOne(cross, wc, &dx, &dy)
+	}
+	symmetrize(cov, c)
+	return nil
+}
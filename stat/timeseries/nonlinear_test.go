@@ -0,0 +1,173 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timeseries
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// linearized is a linear state space model expressed as a differentiable
+// nonlinear model.
+type linearized struct {
+	StateSpace
+}
+
+func (l linearized) Transition(dst *mat.VecDense, t int, x mat.Vector) {
+	dst.MulVec(l.StateSpace.Transition(t), x)
+}
+
+func (l linearized) Observe(dst *mat.VecDense, t int, x mat.Vector) {
+	dst.MulVec(l.StateSpace.Observation(t), x)
+}
+
+func (l linearized) TransitionJacobian(dst *mat.Dense, t int, _ mat.Vector) {
+	dst.Copy(l.StateSpace.Transition(t))
+}
+
+func (l linearized) ObservationJacobian(dst *mat.Dense, t int, _ mat.Vector) {
+	dst.Copy(l.StateSpace.Observation(t))
+}
+
+func TestNonlinearFilterLinear(t *testing.T) {
+	t.Parallel()
+	// The extended and unscented filters are exact for
+	// linear models.
+	const nt = 20
+	rnd := rand.New(rand.NewSource(1))
+	m := timeVarying{}
+	init := testInit()
+	_, y := simulateStateSpace(m, nt, init, rnd)
+	y.Set(5, 1, math.NaN())
+	want, err := Filter(m, y, init)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		name string
+		fn   func() (*Filtered, error)
+	}{
+		{name: "extended", fn: func() (*Filtered, error) { return ExtendedFilter(linearized{m}, y, init) }},
+		{name: "unscented", fn: func() (*Filtered, error) { return UnscentedFilter(linearized{m}, y, init, nil) }},
+		{name: "unscented small alpha", fn: func() (*Filtered, error) {
+			return UnscentedFilter(linearized{m}, y, init, &Unscented{Alpha: 1e-2, Beta: 2})
+		}},
+	} {
+		got, err := test.fn()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Abs(got.LogLikelihood-want.LogLikelihood) > 1e-8*math.Abs(want.LogLikelihood) {
+			t.Errorf("%s: log likelihood mismatch: got %v want %v", test.name, got.LogLikelihood, want.LogLikelihood)
+		}
+		for tt := range want.Filtered {
+			for _, c := range []struct {
+				kind      string
+				got, want State
+			}{
+				{kind: "predicted", got: got.Predicted[tt], want: want.Predicted[tt]},
+				{kind: "filtered", got: got.Filtered[tt], want: want.Filtered[tt]},
+			} {
+				if !mat.EqualApprox(c.got.Mean, c.want.Mean, 1e-8) || !mat.EqualApprox(c.got.Cov, c.want.Cov, 1e-8) {
+					t.Errorf("%s: %s state mismatch at time %d", test.name, c.kind, tt)
+				}
+			}
+		}
+	}
+}
+
+// pendulum is a pendulum whose angle and angular velocity are the state,
+// observed through the horizontal position of its bob.
+type pendulum struct {
+	dt, q, r float64
+}
+
+const gravity = 9.81
+
+func (p pendulum) Transition(dst *mat.VecDense, _ int, x mat.Vector) {
+	theta, omega := x.AtVec(0), x.AtVec(1)
+	dst.SetVec(0, theta+omega*p.dt)
+	dst.SetVec(1, omega-gravity*math.Sin(theta)*p.dt)
+}
+
+func (p pendulum) TransitionJacobian(dst *mat.Dense, _ int, x mat.Vector) {
+	dst.Set(0, 0, 1)
+	dst.Set(0, 1, p.dt)
+	dst.Set(1, 0, -gravity*math.Cos(x.AtVec(0))*p.dt)
+	dst.Set(1, 1, 1)
+}
+
+func (p pendulum) ProcessCov(int) mat.Symmetric {
+	return mat.NewSymDense(2, []float64{p.q * p.dt * p.dt * p.dt / 3, p.q * p.dt * p.dt / 2, p.q * p.dt * p.dt / 2, p.q * p.dt})
+}
+
+func (p pendulum) Observe(dst *mat.VecDense, _ int, x mat.Vector) {
+	dst.SetVec(0, math.Sin(x.AtVec(0)))
+}
+
+func (p pendulum) ObservationJacobian(dst *mat.Dense, _ int, x mat.Vector) {
+	dst.Set(0, 0, math.Cos(x.AtVec(0)))
+	dst.Set(0, 1, 0)
+}
+
+func (p pendulum) ObservationCov(int) mat.Symmetric {
+	return mat.NewSymDense(1, []float64{p.r})
+}
+
+func TestNonlinearFilterPendulum(t *testing.T) {
+	t.Parallel()
+	const nt = 500
+	rnd := rand.New(rand.NewSource(1))
+	m := pendulum{dt: 0.01, q: 0.01, r: 0.01}
+	x := mat.NewDense(nt, 2, nil)
+	y := mat.NewDense(nt, 1, nil)
+	cur := mat.NewVecDense(2, []float64{1.5, 0})
+	next := mat.NewVecDense(2, nil)
+	for tt := 0; tt < nt; tt++ {
+		x.SetRow(tt, cur.RawVector().Data)
+		y.Set(tt, 0, math.Sin(cur.AtVec(0))+math.Sqrt(m.r)*rnd.NormFloat64())
+		m.Transition(next, tt, cur)
+		cur.CopyVec(next)
+		cur.SetVec(1, cur.AtVec(1)+math.Sqrt(m.q*m.dt)*rnd.NormFloat64())
+	}
+	init := State{
+		Mean: mat.NewVecDense(2, []float64{1.2, 0.5}),
+		Cov:  mat.NewSymDense(2, []float64{0.5, 0, 0, 1}),
+	}
+	for _, test := range []struct {
+		name string
+		fn   func() (*Filtered, error)
+	}{
+		{name: "extended", fn: func() (*Filtered, error) { return ExtendedFilter(m, y, init) }},
+		{name: "unscented", fn: func() (*Filtered, error) { return UnscentedFilter(m, y, init, nil) }},
+	} {
+		f, err := test.fn()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		// The filtered angle is more accurate than the
+		// angle inverted from the observations.
+		var filt, raw float64
+		const burn = 50
+		for tt := burn; tt < nt; tt++ {
+			theta := x.At(tt, 0)
+			d := f.Filtered[tt].Mean.AtVec(0) - theta
+			filt += d * d
+			d = math.Asin(math.Max(-1, math.Min(1, y.At(tt, 0)))) - theta
+			raw += d * d
+		}
+		filt = math.Sqrt(filt / (nt - burn))
+		raw = math.Sqrt(raw / (nt - burn))
+		if filt > 0.5*raw {
+			t.Errorf("%s: filtered angle not accurate: got RMSE %v, observation RMSE %v", test.name, filt, raw)
+		}
+	}
+}